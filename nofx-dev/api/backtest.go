@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"nofx/backtest"
+	"nofx/mcp"
 	"nofx/store"
 
 	"github.com/gin-gonic/gin"
@@ -545,7 +546,7 @@ func (s *Server) hydrateBacktestAIConfig(cfg *backtest.BacktestConfig) error {
 	}
 
 	apiKey := strings.TrimSpace(model.APIKey)
-	if apiKey == "" {
+	if apiKey == "" && mcp.RequiresAPIKey(model.Provider, model.CustomAPIURL) {
 		return fmt.Errorf("AI model %s is missing API Key, please configure it in the system first", model.Name)
 	}
 
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetTraderConfidenceGate Get the trader's confidence gate
+func (s *Server) handleGetTraderConfidenceGate(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	gate, err := s.store.Trader().GetConfidenceGate(userID, traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+	c.JSON(http.StatusOK, gate)
+}
+
+// handleUpdateTraderConfidenceGate Set the trader's confidence gate
+// Entries below min_confidence are ignored, scale_size sizes entries by confidence
+func (s *Server) handleUpdateTraderConfidenceGate(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req store.ConfidenceGate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := trader.ValidateConfidenceGate(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := s.store.Trader().GetConfidenceGate(userID, traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+	if err := s.store.Trader().UpdateConfidenceGate(userID, traderID, req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update confidence gate: %v", err)})
+		return
+	}
+
+	// If trader is in memory, apply immediately
+	if at, err := s.traderManager.GetTrader(traderID); err == nil {
+		if err := at.SetConfidenceGate(req); err != nil {
+			logger.Warnf("⚠️ Failed to apply confidence gate to trader %s: %v", at.GetName(), err)
+		} else {
+			logger.Infof("✓ Updated trader %s confidence gate (min=%d, scale=%v)", at.GetName(), req.MinConfidence, req.ScaleSize)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Confidence gate updated",
+		"gate":    req,
+	})
+}
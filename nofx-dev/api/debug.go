@@ -0,0 +1,140 @@
+package api
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"nofx/diagnostics"
+	"nofx/endpoint"
+	"nofx/market"
+	"nofx/ratelimit"
+	"runtime"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requireDeploymentAdmin debug endpoints expose the whole process (command line, heap, CPU profiles, traces):
+// only the admin account, or the single user of a single-user deployment, may use them
+func requireDeploymentAdmin(c *gin.Context) {
+	if !canChangeDeploymentFlags(c.GetString("user_id")) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Only the administrator can access debug endpoints"})
+		return
+	}
+	c.Next()
+}
+
+func (s *Server) registerDebugRoutes(router *gin.RouterGroup) {
+	router.Use(requireDeploymentAdmin)
+	router.GET("/diagnostics", s.handleDebugDiagnostics)
+
+	// pprof endpoints
+	router.GET("/pprof/", gin.WrapF(pprof.Index))
+	router.GET("/pprof/cmdline", gin.WrapF(pprof.Cmdline))
+	router.GET("/pprof/profile", gin.WrapF(pprof.Profile))
+	router.GET("/pprof/symbol", gin.WrapF(pprof.Symbol))
+	router.GET("/pprof/trace", gin.WrapF(pprof.Trace))
+	for _, name := range []string{"goroutine", "heap", "allocs", "block", "mutex", "threadcreate"} {
+		router.GET("/pprof/"+name, gin.WrapH(pprof.Handler(name)))
+	}
+}
+
+// handleDebugDiagnostics returns goroutine breakdown, memory usage, market cache sizes and leak detector state
+func (s *Server) handleDebugDiagnostics(c *gin.Context) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	result := gin.H{
+		"time":                    time.Now().UTC().Format(time.RFC3339),
+		"goroutines":              runtime.NumGoroutine(),
+		"goroutines_by_subsystem": diagnostics.GoroutinesBySubsystem(),
+		"memory": gin.H{
+			"heap_alloc":     mem.HeapAlloc,
+			"heap_inuse":     mem.HeapInuse,
+			"heap_sys":       mem.HeapSys,
+			"sys":            mem.Sys,
+			"num_gc":         mem.NumGC,
+			"heap_objects":   mem.HeapObjects,
+			"pause_total_ns": mem.PauseTotalNs,
+		},
+		"traders": len(s.traderManager.GetTraderIDs()),
+		"pprof": gin.H{
+			"index":     "/api/debug/pprof/",
+			"goroutine": "/api/debug/pprof/goroutine?debug=1",
+			"heap":      "/api/debug/pprof/heap",
+			"allocs":    "/api/debug/pprof/allocs",
+			"profile":   "/api/debug/pprof/profile?seconds=30",
+			"trace":     "/api/debug/pprof/trace?seconds=5",
+		},
+	}
+
+	result["rate_limits"] = ratelimit.AllStats()
+	result["endpoints"] = endpoint.AllStats()
+
+	if market.WSMonitorCli != nil {
+		result["market"] = market.WSMonitorCli.Stats()
+	}
+
+	if detector := diagnostics.Default(); detector != nil {
+		report := detector.Report()
+		result["leak_detector"] = report
+		if report.PeakHeapAlloc > 0 {
+			result["memory"].(gin.H)["peak_heap_alloc"] = report.PeakHeapAlloc
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// handleRateLimits returns shared exchange rate-limit budgets (used / remaining weight)
+func (s *Server) handleRateLimits(c *gin.Context) {
+	c.JSON(http.StatusOK, ratelimit.AllStats())
+}
+
+// handleEndpoints returns endpoint pools (preferred endpoint, probed latency, health)
+func (s *Server) handleEndpoints(c *gin.Context) {
+	c.JSON(http.StatusOK, endpoint.AllStats())
+}
+
+// handleMarketHealth returns market data freshness: kline series not updated recently and the degraded-mode flag
+func (s *Server) handleMarketHealth(c *gin.Context) {
+	if market.WSMonitorCli == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Market monitor is not running"})
+		return
+	}
+	c.JSON(http.StatusOK, market.WSMonitorCli.Health())
+}
+
+// handleMarketStreams returns combined stream connections and per-stream message rates, lag and reconnect counts
+func (s *Server) handleMarketStreams(c *gin.Context) {
+	if market.WSMonitorCli == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Market monitor is not running"})
+		return
+	}
+	c.JSON(http.StatusOK, market.WSMonitorCli.StreamMetrics())
+}
+
+// handleMarketBasis returns the mark / index basis and last vs mark spread of a symbol (?symbol=), or of every
+// symbol widest first
+func (s *Server) handleMarketBasis(c *gin.Context) {
+	if market.WSMonitorCli == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Market monitor is not running"})
+		return
+	}
+	if symbol := c.Query("symbol"); symbol != "" {
+		p := market.WSMonitorCli.MarkPrice(symbol)
+		if p == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No recent mark price for " + symbol})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"mark_price": p, "blowout": p.Blowout()})
+		return
+	}
+	prices := market.WSMonitorCli.MarkPrices()
+	var blowouts []string
+	for _, p := range prices {
+		if p.Blowout() {
+			blowouts = append(blowouts, p.Symbol)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"mark_prices": prices, "blowouts": blowouts, "blowout_threshold_pct": market.BasisBlowoutPct})
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"nofx/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestDebugRoutesAdminOnly tests that pprof is only served to the deployment administrator
+func TestDebugRoutesAdminOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	maxUsers := config.Get().MaxUsers
+	config.Get().MaxUsers = 0 // Multi-user deployment
+	defer func() { config.Get().MaxUsers = maxUsers }()
+
+	for userID, want := range map[string]int{"user-1": http.StatusForbidden, "admin": http.StatusOK} {
+		router := gin.New()
+		group := router.Group("/debug", func(c *gin.Context) { c.Set("user_id", userID) })
+		(&Server{}).registerDebugRoutes(group)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pprof/cmdline", nil))
+		if w.Code != want {
+			t.Errorf("%s: expected HTTP %d, got %d", userID, want, w.Code)
+		}
+	}
+}
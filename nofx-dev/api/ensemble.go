@@ -0,0 +1,93 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetTraderEnsemble Get the trader's ensemble models and vote rules
+func (s *Server) handleGetTraderEnsemble(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	ensemble, err := s.store.Trader().GetEnsemble(userID, traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+	if ensemble.ModelIDs == nil {
+		ensemble.ModelIDs = []string{}
+	}
+	c.JSON(http.StatusOK, ensemble)
+}
+
+// handleUpdateTraderEnsemble Set the trader's ensemble models and vote rules
+// While the ensemble_mode flag is on, the listed models decide alongside the trader's own model and only actions
+// the ensemble agrees on (majority or win-rate weighted) are executed (empty model_ids disables the ensemble)
+func (s *Server) handleUpdateTraderEnsemble(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req store.EnsembleConfig
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := trader.ValidateEnsemble(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := s.store.Trader().GetEnsemble(userID, traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+	traderCfg, err := s.store.Trader().GetByID(traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+
+	// Ensemble models must be enabled models of the same user, other than the trader's own model
+	models := make([]*store.AIModel, 0, len(req.ModelIDs))
+	for _, modelID := range req.ModelIDs {
+		if modelID == traderCfg.AIModelID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The trader's own AI model already votes"})
+			return
+		}
+		model, err := s.store.AIModel().Get(userID, modelID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("AI model %s does not exist or no access permission", modelID)})
+			return
+		}
+		if !model.Enabled {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("AI model %s is not enabled", modelID)})
+			return
+		}
+		models = append(models, model)
+	}
+
+	if err := s.store.Trader().UpdateEnsemble(userID, traderID, req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update ensemble: %v", err)})
+		return
+	}
+
+	// If trader is in memory, apply immediately
+	if at, err := s.traderManager.GetTrader(traderID); err == nil {
+		if err := at.SetEnsemble(req, models); err != nil {
+			logger.Warnf("⚠️ Failed to apply ensemble to trader %s: %v", at.GetName(), err)
+		} else {
+			logger.Infof("✓ Updated trader %s ensemble (%d models, method=%s)", at.GetName(), len(models), req.Method)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Ensemble updated",
+		"ensemble": req,
+	})
+}
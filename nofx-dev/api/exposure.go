@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleAccountExposure Net/gross notional, leverage and margin utilization of an exchange account across its traders
+// Figures come from the traders' last cycle; "traders" is empty until a trader on the account has run a cycle
+func (s *Server) handleAccountExposure(c *gin.Context) {
+	userID := c.GetString("user_id")
+	exchangeID := c.Param("id")
+
+	// Verify exchange account belongs to current user
+	if _, err := s.store.Exchange().GetByID(userID, exchangeID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exchange account does not exist or no access permission"})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.traderManager.GetAccountExposure(exchangeID))
+}
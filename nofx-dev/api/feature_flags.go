@@ -0,0 +1,153 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/config"
+	"nofx/logger"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// featureFlagStatus one row of the degradation matrix: what a flag does, what happens while it is off, and where its
+// value comes from
+type featureFlagStatus struct {
+	config.FeatureFlag
+	Default  bool  `json:"default"`            // Environment default (FEATURE_FLAGS)
+	Override *bool `json:"override,omitempty"` // Runtime override at this level, nil = inherited
+	Enabled  bool  `json:"enabled"`            // Effective value
+}
+
+// featureFlagsRequest overrides to set, flags left out are inherited
+type featureFlagsRequest struct {
+	Flags map[string]bool `json:"flags"`
+}
+
+// featureFlagMatrix builds the status of every known flag from the overrides at one level
+func featureFlagMatrix(overrides map[string]bool, enabled func(name string) bool) []featureFlagStatus {
+	flags := config.FeatureFlags()
+	matrix := make([]featureFlagStatus, 0, len(flags))
+	for _, f := range flags {
+		status := featureFlagStatus{
+			FeatureFlag: f,
+			Default:     config.FeatureFlagDefault(f.Name),
+			Enabled:     enabled(f.Name),
+		}
+		if v, ok := overrides[f.Name]; ok {
+			status.Override = &v
+		}
+		matrix = append(matrix, status)
+	}
+	return matrix
+}
+
+// canChangeDeploymentFlags deployment-wide flags affect every user's traders: only the admin account,
+// or the single user of a single-user deployment, may change them
+func canChangeDeploymentFlags(userID string) bool {
+	return userID == "admin" || config.Get().MaxUsers == 1
+}
+
+// handleGetFeatureFlags Get the deployment's feature flags
+func (s *Server) handleGetFeatureFlags(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"flags":    featureFlagMatrix(config.FeatureFlagOverrides(), config.FeatureEnabled),
+		"editable": canChangeDeploymentFlags(c.GetString("user_id")),
+	})
+}
+
+// handleUpdateFeatureFlags Replace the deployment's runtime flag overrides
+// Running traders pick the change up on their next cycle (unless they override the flag themselves)
+func (s *Server) handleUpdateFeatureFlags(c *gin.Context) {
+	if !canChangeDeploymentFlags(c.GetString("user_id")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the administrator can change deployment feature flags"})
+		return
+	}
+
+	var req featureFlagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := config.ValidateFeatureFlags(req.Flags); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.store.FeatureFlag().Replace(req.Flags); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update feature flags: %v", err)})
+		return
+	}
+	if err := config.SetFeatureFlagOverrides(req.Flags); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	logger.Infof("✓ Updated deployment feature flags: %v", req.Flags)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Feature flags updated",
+		"flags":   featureFlagMatrix(config.FeatureFlagOverrides(), config.FeatureEnabled),
+	})
+}
+
+// handleGetTraderFeatureFlags Get the trader's feature flags
+func (s *Server) handleGetTraderFeatureFlags(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	overrides, err := s.store.Trader().GetFeatureFlags(userID, traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"flags": featureFlagMatrix(overrides, traderFeatureEnabled(overrides))})
+}
+
+// handleUpdateTraderFeatureFlags Replace the trader's flag overrides (flags left out follow the deployment)
+func (s *Server) handleUpdateTraderFeatureFlags(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req featureFlagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := trader.ValidateFeatureFlags(req.Flags); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := s.store.Trader().GetFeatureFlags(userID, traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+	if err := s.store.Trader().UpdateFeatureFlags(userID, traderID, req.Flags); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update feature flags: %v", err)})
+		return
+	}
+
+	// If trader is in memory, apply immediately
+	if at, err := s.traderManager.GetTrader(traderID); err == nil {
+		if err := at.SetFeatureFlags(req.Flags); err != nil {
+			logger.Warnf("⚠️ Failed to apply feature flags to trader %s: %v", at.GetName(), err)
+		} else {
+			logger.Infof("✓ Updated trader %s feature flags: %v", at.GetName(), req.Flags)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Feature flags updated",
+		"flags":   featureFlagMatrix(req.Flags, traderFeatureEnabled(req.Flags)),
+	})
+}
+
+// traderFeatureEnabled resolves a flag for a trader with the given overrides (trader → deployment → default)
+func traderFeatureEnabled(overrides map[string]bool) func(name string) bool {
+	return func(name string) bool {
+		if enabled, ok := overrides[name]; ok {
+			return enabled
+		}
+		return config.FeatureEnabled(name)
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetTraderFlatSchedule Get the trader's end-of-day flat schedule
+func (s *Server) handleGetTraderFlatSchedule(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	schedule, err := s.store.Trader().GetFlatSchedule(userID, traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// handleUpdateTraderFlatSchedule Set the trader's end-of-day flat schedule
+// While the flat period is active all positions are closed and new entries are blocked
+func (s *Server) handleUpdateTraderFlatSchedule(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req store.FlatSchedule
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = trader.FlatModeDaily
+	}
+	if err := trader.ValidateFlatSchedule(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := s.store.Trader().GetFlatSchedule(userID, traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+	if err := s.store.Trader().UpdateFlatSchedule(userID, traderID, req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update flat schedule: %v", err)})
+		return
+	}
+
+	// If trader is in memory, apply immediately
+	if at, err := s.traderManager.GetTrader(traderID); err == nil {
+		if err := at.SetFlatSchedule(req); err != nil {
+			logger.Warnf("⚠️ Failed to apply flat schedule to trader %s: %v", at.GetName(), err)
+		} else {
+			logger.Infof("✓ Updated trader %s flat schedule (enabled=%v, mode=%s, flat=%s)", at.GetName(), req.Enabled, req.Mode, req.FlatTime)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Flat schedule updated",
+		"schedule": req,
+	})
+}
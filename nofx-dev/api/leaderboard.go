@@ -0,0 +1,33 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/config"
+	"nofx/manager"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleLeaderboard Risk-adjusted leaderboard of traders shown in the competition (no auth required)
+// Query: window=24h|7d|30d|90d (default 7d), sort=sharpe|return|drawdown (default sharpe)
+func (s *Server) handleLeaderboard(c *gin.Context) {
+	if !config.Get().LeaderboardEnabled {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Leaderboard is not enabled on this deployment"})
+		return
+	}
+
+	window := c.DefaultQuery("window", "7d")
+	sortBy := c.DefaultQuery("sort", manager.LeaderboardSortSharpe)
+	if err := manager.ValidateLeaderboardParams(window, sortBy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	board, err := s.traderManager.GetLeaderboard(s.store, window, sortBy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get leaderboard: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, board)
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetTraderLiquidityFilter Get the trader's liquidity filter
+func (s *Server) handleGetTraderLiquidityFilter(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	filter, err := s.store.Trader().GetLiquidityFilter(userID, traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+	c.JSON(http.StatusOK, filter)
+}
+
+// handleUpdateTraderLiquidityFilter Set the trader's liquidity filter
+// Candidate coins below min_oi_value_usd / min_volume_24h_usd are skipped, zero_oi_pass lets OI reported as 0 through
+func (s *Server) handleUpdateTraderLiquidityFilter(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req store.LiquidityFilter
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := trader.ValidateLiquidityFilter(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := s.store.Trader().GetLiquidityFilter(userID, traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+	if err := s.store.Trader().UpdateLiquidityFilter(userID, traderID, req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update liquidity filter: %v", err)})
+		return
+	}
+
+	// If trader is in memory, apply immediately
+	if at, err := s.traderManager.GetTrader(traderID); err == nil {
+		if err := at.SetLiquidityFilter(req); err != nil {
+			logger.Warnf("⚠️ Failed to apply liquidity filter to trader %s: %v", at.GetName(), err)
+		} else {
+			logger.Infof("✓ Updated trader %s liquidity filter (min_oi=%.0f, zero_oi_pass=%v, min_volume=%.0f)",
+				at.GetName(), req.MinOIValueUSD, req.ZeroOIPass, req.MinVolume24hUSD)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Liquidity filter updated",
+		"filter":  req,
+	})
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+	"nofx/benchmark"
+	"nofx/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetModelBenchmarks Get benchmark stats (latency, cost, output validity) of the user's AI models
+func (s *Server) handleGetModelBenchmarks(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	b := benchmark.Default()
+	if b == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Model benchmarker is not running"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"running": b.IsRunning(userID),
+		"models":  b.Summaries(userID),
+	})
+}
+
+// handleRunModelBenchmarks Trigger a benchmark run of the user's enabled AI models (runs in background)
+func (s *Server) handleRunModelBenchmarks(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	b := benchmark.Default()
+	if b == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Model benchmarker is not running"})
+		return
+	}
+	if b.IsRunning(userID) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Benchmark is already running"})
+		return
+	}
+
+	go func() {
+		if err := b.RunUser(userID); err != nil {
+			logger.Warnf("⚠️ Model benchmark run failed for user %s: %v", userID, err)
+		}
+	}()
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Benchmark started, results will appear in /api/models/benchmarks"})
+}
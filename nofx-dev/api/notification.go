@@ -0,0 +1,57 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetNotificationSettings Get the user's notification quiet hours and digest batching
+func (s *Server) handleGetNotificationSettings(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	settings, err := s.store.Notification().Get(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get notification settings: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// handleUpdateNotificationSettings Set the user's notification quiet hours and digest batching
+// Applies to the webhooks of all the user's traders, critical alerts are always sent immediately
+func (s *Server) handleUpdateNotificationSettings(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req store.NotificationSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := trader.ValidateNotificationSettings(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.store.Notification().Save(userID, req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update notification settings: %v", err)})
+		return
+	}
+
+	// Apply immediately to traders in memory
+	for _, at := range s.traderManager.GetUserTraders(userID) {
+		if err := at.SetNotificationSettings(req); err != nil {
+			logger.Warnf("⚠️ Failed to apply notification settings to trader %s: %v", at.GetName(), err)
+		}
+	}
+	logger.Infof("✓ Updated notification settings of user %s (quiet hours=%v, digest=%v)", userID, req.QuietHoursEnabled, req.DigestEnabled)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Notification settings updated",
+		"settings": req,
+	})
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetTraderOutcomeExport Get the trader's decision outcome export sink (the secret is never returned)
+func (s *Server) handleGetTraderOutcomeExport(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	sink, secret, err := s.store.Trader().GetOutcomeExport(userID, traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"url":        sink,
+		"has_secret": secret != "",
+	})
+}
+
+// handleUpdateTraderOutcomeExport Set or clear the sink receiving (prompt, decision, outcome) JSONL examples
+// of the trader's closed trades. An empty URL disables the export, an omitted secret keeps the current one
+func (s *Server) handleUpdateTraderOutcomeExport(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req struct {
+		URL    string `json:"url"`    // http(s) URL (batches POSTed as application/x-ndjson) or file:// URL inside OUTCOME_EXPORT_DIR
+		Secret string `json:"secret"` // HMAC-SHA256 signing secret for http(s) sinks (optional)
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	_, currentSecret, err := s.store.Trader().GetOutcomeExport(userID, traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+
+	secret := req.Secret
+	if req.URL == "" {
+		secret = ""
+	} else {
+		if err := trader.ValidateOutcomeSink(req.URL); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if secret == "" {
+			secret = currentSecret
+		}
+	}
+
+	if err := s.store.Trader().UpdateOutcomeExport(userID, traderID, req.URL, secret); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update outcome export: %v", err)})
+		return
+	}
+
+	// If trader is in memory, apply immediately
+	if at, err := s.traderManager.GetTrader(traderID); err == nil {
+		at.SetOutcomeExport(req.URL, secret)
+		logger.Infof("✓ Updated trader %s outcome export (enabled=%v)", at.GetName(), req.URL != "")
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Outcome export updated",
+		"url":        req.URL,
+		"has_secret": secret != "",
+	})
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetTraderPerformanceSeed Get the trader's cold-start performance seed
+func (s *Server) handleGetTraderPerformanceSeed(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	seed, err := s.store.Trader().GetPerformanceSeed(userID, traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+	c.JSON(http.StatusOK, seed)
+}
+
+// handleUpdateTraderPerformanceSeed Set the trader's cold-start performance seed
+// Until the trader has enough live closed trades, the AI's recent trades are topped up from a predecessor
+// trader or a backtest run of the same user (an empty source disables seeding)
+func (s *Server) handleUpdateTraderPerformanceSeed(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req store.PerformanceSeed
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := trader.ValidatePerformanceSeed(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := s.store.Trader().GetPerformanceSeed(userID, traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+
+	// The seed source must belong to the same user
+	switch req.Source {
+	case store.PerformanceSeedTrader:
+		if req.ID == traderID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A trader cannot seed from itself"})
+			return
+		}
+		if _, err := s.store.Trader().GetPerformanceSeed(userID, req.ID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Predecessor trader does not exist or no access permission"})
+			return
+		}
+	case store.PerformanceSeedBacktest:
+		if _, err := s.ensureBacktestRunOwnership(req.ID, userID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Backtest run does not exist or no access permission"})
+			return
+		}
+	}
+
+	if err := s.store.Trader().UpdatePerformanceSeed(userID, traderID, req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update performance seed: %v", err)})
+		return
+	}
+
+	// If trader is in memory, apply immediately
+	if at, err := s.traderManager.GetTrader(traderID); err == nil {
+		if err := at.SetPerformanceSeed(req); err != nil {
+			logger.Warnf("⚠️ Failed to apply performance seed to trader %s: %v", at.GetName(), err)
+		} else {
+			logger.Infof("✓ Updated trader %s performance seed (source=%s, id=%s)", at.GetName(), req.Source, req.ID)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Performance seed updated",
+		"seed":    req,
+	})
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetTraderRiskGuardrail Get the trader's risk guardrail
+func (s *Server) handleGetTraderRiskGuardrail(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	guardrail, err := s.store.Trader().GetRiskGuardrail(userID, traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+	c.JSON(http.StatusOK, guardrail)
+}
+
+// handleUpdateTraderRiskGuardrail Set the trader's risk guardrail
+// Leverage, position size and stop distance of AI entries are clamped to these limits before execution
+func (s *Server) handleUpdateTraderRiskGuardrail(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req store.RiskGuardrail
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := trader.ValidateRiskGuardrail(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := s.store.Trader().GetRiskGuardrail(userID, traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+	if err := s.store.Trader().UpdateRiskGuardrail(userID, traderID, req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update risk guardrail: %v", err)})
+		return
+	}
+
+	// If trader is in memory, apply immediately
+	if at, err := s.traderManager.GetTrader(traderID); err == nil {
+		if err := at.SetRiskGuardrail(req); err != nil {
+			logger.Warnf("⚠️ Failed to apply risk guardrail to trader %s: %v", at.GetName(), err)
+		} else {
+			logger.Infof("✓ Updated trader %s risk guardrail (leverage≤%d, size≤%.0f USD / %.1f%%, stop %.2f-%.2f%%)",
+				at.GetName(), req.MaxLeverage, req.MaxPositionSizeUSD, req.MaxPositionEquityPct, req.MinStopDistancePct, req.MaxStopDistancePct)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Risk guardrail updated",
+		"guardrail": req,
+	})
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"nofx/decision"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleRiskPositionSize computes the quantity that loses risk_pct of balance if price moves from entry to stop
+func (s *Server) handleRiskPositionSize(c *gin.Context) {
+	var req struct {
+		EntryPrice float64 `json:"entry_price" binding:"required"`
+		StopLoss   float64 `json:"stop_loss" binding:"required"`
+		Balance    float64 `json:"balance" binding:"required"`
+		RiskPct    float64 `json:"risk_pct" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter error: entry_price, stop_loss, balance and risk_pct are required"})
+		return
+	}
+
+	qty, err := decision.RiskQuantity(req.EntryPrice, req.StopLoss, req.Balance, req.RiskPct)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	side := "long"
+	if req.StopLoss > req.EntryPrice {
+		side = "short"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"side":              side,
+		"quantity":          qty,
+		"position_size_usd": qty * req.EntryPrice,
+		"risk_usd":          req.Balance * req.RiskPct / 100,
+		"stop_distance_pct": math.Abs(req.EntryPrice-req.StopLoss) / req.EntryPrice * 100,
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"nofx/crypto"
 	"nofx/logger"
 	"nofx/manager"
+	"nofx/market"
 	"nofx/store"
 	"nofx/trader"
 	"strings"
@@ -102,9 +103,11 @@ func (s *Server) setupRoutes() {
 		api.GET("/traders", s.handlePublicTraderList)
 		api.GET("/competition", s.handlePublicCompetition)
 		api.GET("/top-traders", s.handleTopTraders)
+		api.GET("/leaderboard", s.handleLeaderboard)
 		api.GET("/equity-history", s.handleEquityHistory)
 		api.POST("/equity-history-batch", s.handleEquityHistoryBatch)
 		api.GET("/traders/:id/public-config", s.handleGetPublicTraderConfig)
+		api.GET("/share/:token", s.handleGetSharedPerformance)
 
 		// Authentication related routes (no authentication required)
 		api.POST("/register", s.handleRegister)
@@ -121,6 +124,14 @@ func (s *Server) setupRoutes() {
 			// Server IP query (requires authentication, for whitelist configuration)
 			protected.GET("/server-ip", s.handleGetServerIP)
 
+			// Notification quiet hours / digest batching (all of the user's trader webhooks)
+			protected.GET("/notification-settings", s.handleGetNotificationSettings)
+			protected.PUT("/notification-settings", s.handleUpdateNotificationSettings)
+
+			// Feature flags of experimental features (deployment-wide, per-trader overrides are under /traders/:id)
+			protected.GET("/feature-flags", s.handleGetFeatureFlags)
+			protected.PUT("/feature-flags", s.handleUpdateFeatureFlags)
+
 			// AI trader management
 			protected.GET("/my-traders", s.handleTraderList)
 			protected.GET("/traders/:id/config", s.handleGetTraderConfig)
@@ -130,19 +141,50 @@ func (s *Server) setupRoutes() {
 			protected.POST("/traders/:id/start", s.handleStartTrader)
 			protected.POST("/traders/:id/stop", s.handleStopTrader)
 			protected.PUT("/traders/:id/prompt", s.handleUpdateTraderPrompt)
+			protected.GET("/traders/:id/webhook", s.handleGetTraderWebhook)
+			protected.PUT("/traders/:id/webhook", s.handleUpdateTraderWebhook)
+			protected.GET("/traders/:id/outcome-export", s.handleGetTraderOutcomeExport)
+			protected.PUT("/traders/:id/outcome-export", s.handleUpdateTraderOutcomeExport)
+			protected.GET("/traders/:id/flat-schedule", s.handleGetTraderFlatSchedule)
+			protected.PUT("/traders/:id/flat-schedule", s.handleUpdateTraderFlatSchedule)
+			protected.GET("/traders/:id/performance-seed", s.handleGetTraderPerformanceSeed)
+			protected.PUT("/traders/:id/performance-seed", s.handleUpdateTraderPerformanceSeed)
+			protected.GET("/traders/:id/feature-flags", s.handleGetTraderFeatureFlags)
+			protected.PUT("/traders/:id/feature-flags", s.handleUpdateTraderFeatureFlags)
+			protected.GET("/traders/:id/ensemble", s.handleGetTraderEnsemble)
+			protected.PUT("/traders/:id/ensemble", s.handleUpdateTraderEnsemble)
+			protected.GET("/traders/:id/confidence-gate", s.handleGetTraderConfidenceGate)
+			protected.PUT("/traders/:id/confidence-gate", s.handleUpdateTraderConfidenceGate)
+			protected.GET("/traders/:id/liquidity-filter", s.handleGetTraderLiquidityFilter)
+			protected.PUT("/traders/:id/liquidity-filter", s.handleUpdateTraderLiquidityFilter)
+			protected.GET("/traders/:id/risk-guardrail", s.handleGetTraderRiskGuardrail)
+			protected.PUT("/traders/:id/risk-guardrail", s.handleUpdateTraderRiskGuardrail)
+			protected.GET("/traders/:id/share-links", s.handleListShareLinks)
+			protected.POST("/traders/:id/share-links", s.handleCreateShareLink)
+			protected.DELETE("/traders/:id/share-links/:token", s.handleRevokeShareLink)
 			protected.POST("/traders/:id/sync-balance", s.handleSyncBalance)
 			protected.POST("/traders/:id/close-position", s.handleClosePosition)
+			protected.POST("/traders/:id/import-positions", s.handleImportPositions)
+			protected.GET("/traders/:id/performance-comparison", s.handlePerformanceComparison)
+			protected.GET("/traders/:id/ai-cost", s.handleAICost)
+			protected.GET("/traders/:id/events", s.handleTraderEvents)
+			protected.GET("/traders/:id/trades/:position_id/replay", s.handleTradeReplay)
 			protected.PUT("/traders/:id/competition", s.handleToggleCompetition)
+			protected.POST("/traders/:id/transfer", s.handleTransferFunds)
+			protected.POST("/traders/:id/stress-test", s.handleStressTest)
 
 			// AI model configuration
 			protected.GET("/models", s.handleGetModelConfigs)
 			protected.PUT("/models", s.handleUpdateModelConfigs)
+			protected.GET("/models/benchmarks", s.handleGetModelBenchmarks)
+			protected.POST("/models/benchmarks/run", s.handleRunModelBenchmarks)
 
 			// Exchange configuration
 			protected.GET("/exchanges", s.handleGetExchangeConfigs)
 			protected.POST("/exchanges", s.handleCreateExchange)
 			protected.PUT("/exchanges", s.handleUpdateExchangeConfigs)
 			protected.DELETE("/exchanges/:id", s.handleDeleteExchange)
+			protected.GET("/exchanges/:id/exposure", s.handleAccountExposure)
 
 			// Strategy management
 			protected.GET("/strategies", s.handleGetStrategies)
@@ -164,6 +206,19 @@ func (s *Server) setupRoutes() {
 			protected.GET("/decisions", s.handleDecisions)
 			protected.GET("/decisions/latest", s.handleLatestDecisions)
 			protected.GET("/statistics", s.handleStatistics)
+			protected.GET("/rate-limits", s.handleRateLimits)
+			protected.GET("/endpoints", s.handleEndpoints)
+			protected.GET("/market/health", s.handleMarketHealth)
+			protected.GET("/market/streams", s.handleMarketStreams)
+			protected.GET("/market/basis", s.handleMarketBasis)
+			protected.GET("/symbols/metadata", s.handleSymbolMetadata)
+			protected.GET("/symbols/map", s.handleSymbolMap)
+			protected.POST("/risk/position-size", s.handleRiskPositionSize)
+
+			// Runtime diagnostics (only when DEBUG_ENDPOINTS=true, administrator only)
+			if config.Get().DebugEndpoints {
+				s.registerDebugRoutes(protected.Group("/debug"))
+			}
 		}
 	}
 }
@@ -182,6 +237,7 @@ func (s *Server) handleGetSystemConfig(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"registration_enabled": cfg.RegistrationEnabled,
+		"leaderboard_enabled":  cfg.LeaderboardEnabled,
 		"btc_eth_leverage":     10, // Default value
 		"altcoin_leverage":     5,  // Default value
 	})
@@ -349,9 +405,12 @@ type CreateTraderRequest struct {
 	Name                string  `json:"name" binding:"required"`
 	AIModelID           string  `json:"ai_model_id" binding:"required"`
 	ExchangeID          string  `json:"exchange_id" binding:"required"`
+	FallbackExchangeID  string  `json:"fallback_exchange_id"` // Takes new entries while the primary exchange is failing (optional)
 	StrategyID          string  `json:"strategy_id"` // Strategy ID (new version)
 	InitialBalance      float64 `json:"initial_balance"`
 	ScanIntervalMinutes int     `json:"scan_interval_minutes"`
+	MaxPositionAgeMinutes int   `json:"max_position_age_minutes"` // Max holding time, 0 = no limit
+	MaxPositionAgeAction  string `json:"max_position_age_action"` // "close" (default) or "review"
 	IsCrossMargin       *bool   `json:"is_cross_margin"`     // Pointer type, nil means use default value true
 	ShowInCompetition   *bool   `json:"show_in_competition"` // Pointer type, nil means use default value true
 	// The following fields are kept for backward compatibility, new version uses strategy config
@@ -397,7 +456,7 @@ type ExchangeConfig struct {
 // SafeExchangeConfig Safe exchange configuration structure (does not contain sensitive information)
 type SafeExchangeConfig struct {
 	ID                    string `json:"id"`            // UUID
-	ExchangeType          string `json:"exchange_type"` // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter"
+	ExchangeType          string `json:"exchange_type"` // "binance", "bybit", "okx", "mexc", "coinbase", "hyperliquid", "aster", "lighter"
 	AccountName           string `json:"account_name"`  // User-defined account name
 	Name                  string `json:"name"`          // Display name
 	Type                  string `json:"type"`          // "cex" or "dex"
@@ -407,6 +466,7 @@ type SafeExchangeConfig struct {
 	AsterUser             string `json:"asterUser"`             // Aster username (not sensitive)
 	AsterSigner           string `json:"asterSigner"`           // Aster signer (not sensitive)
 	LighterWalletAddr     string `json:"lighterWalletAddr"`     // LIGHTER wallet address (not sensitive)
+	QuoteAsset            string `json:"quote_asset"`           // Quote/margin asset: USDT, USDC, BUSD, FDUSD
 }
 
 type UpdateModelConfigRequest struct {
@@ -423,7 +483,7 @@ type UpdateExchangeConfigRequest struct {
 		Enabled                 bool   `json:"enabled"`
 		APIKey                  string `json:"api_key"`
 		SecretKey               string `json:"secret_key"`
-		Passphrase              string `json:"passphrase"` // OKX specific
+		Passphrase              string `json:"passphrase"` // OKX/Coinbase specific
 		Testnet                 bool   `json:"testnet"`
 		HyperliquidWalletAddr   string `json:"hyperliquid_wallet_addr"`
 		AsterUser               string `json:"aster_user"`
@@ -432,9 +492,24 @@ type UpdateExchangeConfigRequest struct {
 		LighterWalletAddr       string `json:"lighter_wallet_addr"`
 		LighterPrivateKey       string `json:"lighter_private_key"`
 		LighterAPIKeyPrivateKey string `json:"lighter_api_key_private_key"`
+		QuoteAsset              string `json:"quote_asset"` // Optional, unchanged if empty
 	} `json:"exchanges"`
 }
 
+// validateFallbackExchange checks the fallback exchange account belongs to the user and differs from the primary
+func (s *Server) validateFallbackExchange(userID, exchangeID, fallbackExchangeID string) error {
+	if fallbackExchangeID == "" {
+		return nil
+	}
+	if fallbackExchangeID == exchangeID {
+		return fmt.Errorf("fallback exchange must be different from the primary exchange")
+	}
+	if _, err := s.store.Exchange().GetByID(userID, fallbackExchangeID); err != nil {
+		return fmt.Errorf("fallback exchange %s not found", fallbackExchangeID)
+	}
+	return nil
+}
+
 // handleCreateTrader Create new AI trader
 func (s *Server) handleCreateTrader(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -443,6 +518,18 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := s.validateFallbackExchange(userID, req.ExchangeID, req.FallbackExchangeID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := trader.ValidatePositionAge(req.MaxPositionAgeMinutes, req.MaxPositionAgeAction); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	maxPositionAgeAction := req.MaxPositionAgeAction
+	if maxPositionAgeAction == "" {
+		maxPositionAgeAction = trader.PositionAgeActionClose
+	}
 
 	// Validate leverage values
 	if req.BTCETHLeverage < 0 || req.BTCETHLeverage > 50 {
@@ -459,8 +546,8 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 		symbols := strings.Split(req.TradingSymbols, ",")
 		for _, symbol := range symbols {
 			symbol = strings.TrimSpace(symbol)
-			if symbol != "" && !strings.HasSuffix(strings.ToUpper(symbol), "USDT") {
-				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid symbol format: %s, must end with USDT", symbol)})
+			if symbol != "" && market.QuoteAsset(symbol) == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid symbol format: %s, must end with USDT or USDC", symbol)})
 				return
 			}
 		}
@@ -558,6 +645,17 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 				exchangeCfg.SecretKey,
 				exchangeCfg.Passphrase,
 			)
+		case "mexc":
+			tempTrader = trader.NewMEXCTrader(
+				exchangeCfg.APIKey,
+				exchangeCfg.SecretKey,
+			)
+		case "coinbase":
+			tempTrader = trader.NewCoinbaseTrader(
+				exchangeCfg.APIKey,
+				exchangeCfg.SecretKey,
+				exchangeCfg.Passphrase,
+			)
 		case "lighter":
 			if exchangeCfg.LighterAPIKeyPrivateKey != "" {
 				tempTrader, createErr = trader.NewLighterTraderV2(
@@ -611,6 +709,7 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 		Name:                 req.Name,
 		AIModelID:            req.AIModelID,
 		ExchangeID:           req.ExchangeID,
+		FallbackExchangeID:   req.FallbackExchangeID,
 		StrategyID:           req.StrategyID, // Associated strategy ID (new version)
 		InitialBalance:       actualBalance,  // Use actual queried balance
 		BTCETHLeverage:       btcEthLeverage,
@@ -624,6 +723,8 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 		IsCrossMargin:        isCrossMargin,
 		ShowInCompetition:    showInCompetition,
 		ScanIntervalMinutes:  scanIntervalMinutes,
+		MaxPositionAgeMinutes: req.MaxPositionAgeMinutes,
+		MaxPositionAgeAction:  maxPositionAgeAction,
 		IsRunning:            false,
 	}
 
@@ -661,9 +762,12 @@ type UpdateTraderRequest struct {
 	Name                string  `json:"name" binding:"required"`
 	AIModelID           string  `json:"ai_model_id" binding:"required"`
 	ExchangeID          string  `json:"exchange_id" binding:"required"`
+	FallbackExchangeID  string  `json:"fallback_exchange_id"` // Takes new entries while the primary exchange is failing (optional)
 	StrategyID          string  `json:"strategy_id"` // Strategy ID (new version)
 	InitialBalance      float64 `json:"initial_balance"`
 	ScanIntervalMinutes int     `json:"scan_interval_minutes"`
+	MaxPositionAgeMinutes *int  `json:"max_position_age_minutes"` // Pointer type, nil keeps the current limit
+	MaxPositionAgeAction  string `json:"max_position_age_action"` // Empty keeps the current action
 	IsCrossMargin       *bool   `json:"is_cross_margin"`
 	ShowInCompetition   *bool   `json:"show_in_competition"`
 	// The following fields are kept for backward compatibility, new version uses strategy config
@@ -685,6 +789,10 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := s.validateFallbackExchange(userID, req.ExchangeID, req.FallbackExchangeID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Check if trader exists and belongs to current user
 	traders, err := s.store.Trader().List(userID)
@@ -735,6 +843,20 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 		scanIntervalMinutes = 3
 	}
 
+	// Set max position age, allow updates
+	maxPositionAgeMinutes := existingTrader.MaxPositionAgeMinutes // Keep original value
+	if req.MaxPositionAgeMinutes != nil {
+		maxPositionAgeMinutes = *req.MaxPositionAgeMinutes
+	}
+	maxPositionAgeAction := req.MaxPositionAgeAction
+	if maxPositionAgeAction == "" {
+		maxPositionAgeAction = existingTrader.MaxPositionAgeAction // Keep original value
+	}
+	if err := trader.ValidatePositionAge(maxPositionAgeMinutes, maxPositionAgeAction); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Set system prompt template
 	systemPromptTemplate := req.SystemPromptTemplate
 	if systemPromptTemplate == "" {
@@ -754,6 +876,7 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 		Name:                 req.Name,
 		AIModelID:            req.AIModelID,
 		ExchangeID:           req.ExchangeID,
+		FallbackExchangeID:   req.FallbackExchangeID,
 		StrategyID:           strategyID, // Associated strategy ID
 		InitialBalance:       req.InitialBalance,
 		BTCETHLeverage:       btcEthLeverage,
@@ -765,6 +888,8 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 		IsCrossMargin:        isCrossMargin,
 		ShowInCompetition:    showInCompetition,
 		ScanIntervalMinutes:  scanIntervalMinutes,
+		MaxPositionAgeMinutes: maxPositionAgeMinutes,
+		MaxPositionAgeAction:  maxPositionAgeAction,
 		IsRunning:            existingTrader.IsRunning, // Keep original value
 	}
 
@@ -781,6 +906,13 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 		logger.Infof("⚠️ Failed to reload user traders into memory: %v", err)
 	}
 
+	// Traders already in memory are not reloaded, apply the max position age immediately
+	if at, err := s.traderManager.GetTrader(traderID); err == nil {
+		if err := at.SetMaxPositionAge(maxPositionAgeMinutes, maxPositionAgeAction); err != nil {
+			logger.Warnf("⚠️ Failed to apply max position age to trader %s: %v", at.GetName(), err)
+		}
+	}
+
 	logger.Infof("✓ Trader updated successfully: %s (model: %s, exchange: %s)", req.Name, req.AIModelID, req.ExchangeID)
 
 	c.JSON(http.StatusOK, gin.H{
@@ -802,6 +934,9 @@ func (s *Server) handleDeleteTrader(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to delete trader: %v", err)})
 		return
 	}
+	if err := s.store.ShareLink().DeleteByTrader(userID, traderID); err != nil {
+		logger.Infof("⚠️ Failed to delete share links of trader %s: %v", traderID, err)
+	}
 
 	// If trader is running, stop it first
 	if trader, err := s.traderManager.GetTrader(traderID); err == nil {
@@ -1126,6 +1261,97 @@ func (s *Server) handleSyncBalance(c *gin.Context) {
 	})
 }
 
+// handleImportPositions Import positions that already exist on the exchange account into the trader's records
+func (s *Server) handleImportPositions(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	// Verify trader belongs to current user
+	if _, err := s.store.Trader().GetFullConfig(userID, traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+
+	trader, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader not loaded"})
+		return
+	}
+
+	imported, err := trader.ImportPositions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to import positions: %v", err)})
+		return
+	}
+
+	logger.Infof("📥 User %s imported %d existing position(s) into trader %s", userID, imported, traderID)
+	c.JSON(http.StatusOK, gin.H{"message": "Positions imported", "imported": imported})
+}
+
+// handlePerformanceComparison Compare backfilled pre-nofx trade history with AI-driven performance
+func (s *Server) handlePerformanceComparison(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	// Verify trader belongs to current user
+	if _, err := s.store.Trader().GetFullConfig(userID, traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+
+	comparison, err := s.store.Position().GetPerformanceComparison(traderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get performance comparison: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+// handleAICost AI tokens and estimated cost of the trader's decisions (in total and per model) next to its trading PnL
+func (s *Server) handleAICost(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	// Verify trader belongs to current user
+	if _, err := s.store.Trader().GetFullConfig(userID, traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+
+	usage, err := s.store.Decision().GetAIUsage(traderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get AI usage: %v", err)})
+		return
+	}
+	stats, err := s.store.Position().GetFullStats(traderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get trading statistics: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"usage":            usage,
+		"trading_pnl":      stats.TotalPnL,
+		"trading_fee":      stats.TotalFee,
+		"net_pnl_after_ai": stats.TotalPnL - usage.CostUSD,
+	})
+}
+
+// handleTraderEvents Recent trader events (exchange failover etc.)
+func (s *Server) handleTraderEvents(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	// Verify trader belongs to current user
+	if _, err := s.store.Trader().GetFullConfig(userID, traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": s.traderManager.GetTraderEvents(traderID)})
+}
+
 // handleClosePosition One-click close position
 func (s *Server) handleClosePosition(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -1188,6 +1414,17 @@ func (s *Server) handleClosePosition(c *gin.Context) {
 			exchangeCfg.SecretKey,
 			exchangeCfg.Passphrase,
 		)
+	case "mexc":
+		tempTrader = trader.NewMEXCTrader(
+			exchangeCfg.APIKey,
+			exchangeCfg.SecretKey,
+		)
+	case "coinbase":
+		tempTrader = trader.NewCoinbaseTrader(
+			exchangeCfg.APIKey,
+			exchangeCfg.SecretKey,
+			exchangeCfg.Passphrase,
+		)
 	case "lighter":
 		if exchangeCfg.LighterAPIKeyPrivateKey != "" {
 			tempTrader, createErr = trader.NewLighterTraderV2(
@@ -1264,6 +1501,7 @@ func (s *Server) handleGetModelConfigs(c *gin.Context) {
 			{ID: "gemini", Name: "Gemini AI", Provider: "gemini", Enabled: false},
 			{ID: "grok", Name: "Grok AI", Provider: "grok", Enabled: false},
 			{ID: "kimi", Name: "Kimi AI", Provider: "kimi", Enabled: false},
+			{ID: "ollama", Name: "Ollama (Local)", Provider: "ollama", Enabled: false},
 		}
 		c.JSON(http.StatusOK, defaultModels)
 		return
@@ -1402,6 +1640,7 @@ func (s *Server) handleGetExchangeConfigs(c *gin.Context) {
 			AsterUser:             exchange.AsterUser,
 			AsterSigner:           exchange.AsterSigner,
 			LighterWalletAddr:     exchange.LighterWalletAddr,
+			QuoteAsset:            exchange.QuoteAsset,
 		}
 	}
 
@@ -1468,6 +1707,14 @@ func (s *Server) handleUpdateExchangeConfigs(c *gin.Context) {
 		logger.Infof("🔓 Decrypted exchange config data (UserID: %s)", userID)
 	}
 
+	// Validate quote assets before touching any exchange
+	for exchangeID, exchangeData := range req.Exchanges {
+		if exchangeData.QuoteAsset != "" && !market.IsSupportedQuote(exchangeData.QuoteAsset) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unsupported quote asset for exchange %s: %s", exchangeID, exchangeData.QuoteAsset)})
+			return
+		}
+	}
+
 	// Update each exchange's configuration
 	for exchangeID, exchangeData := range req.Exchanges {
 		err := s.store.Exchange().Update(userID, exchangeID, exchangeData.Enabled, exchangeData.APIKey, exchangeData.SecretKey, exchangeData.Passphrase, exchangeData.Testnet, exchangeData.HyperliquidWalletAddr, exchangeData.AsterUser, exchangeData.AsterSigner, exchangeData.AsterPrivateKey, exchangeData.LighterWalletAddr, exchangeData.LighterPrivateKey, exchangeData.LighterAPIKeyPrivateKey)
@@ -1475,6 +1722,12 @@ func (s *Server) handleUpdateExchangeConfigs(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update exchange %s: %v", exchangeID, err)})
 			return
 		}
+		if exchangeData.QuoteAsset != "" {
+			if err := s.store.Exchange().UpdateQuoteAsset(userID, exchangeID, exchangeData.QuoteAsset); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update exchange %s: %v", exchangeID, err)})
+				return
+			}
+		}
 	}
 
 	// Reload all traders for this user to make new config take effect immediately
@@ -1490,7 +1743,7 @@ func (s *Server) handleUpdateExchangeConfigs(c *gin.Context) {
 
 // CreateExchangeRequest request structure for creating a new exchange account
 type CreateExchangeRequest struct {
-	ExchangeType            string `json:"exchange_type" binding:"required"` // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter"
+	ExchangeType            string `json:"exchange_type" binding:"required"` // "binance", "bybit", "okx", "mexc", "coinbase", "hyperliquid", "aster", "lighter"
 	AccountName             string `json:"account_name"`                     // User-defined account name
 	Enabled                 bool   `json:"enabled"`
 	APIKey                  string `json:"api_key"`
@@ -1504,6 +1757,7 @@ type CreateExchangeRequest struct {
 	LighterWalletAddr       string `json:"lighter_wallet_addr"`
 	LighterPrivateKey       string `json:"lighter_private_key"`
 	LighterAPIKeyPrivateKey string `json:"lighter_api_key_private_key"`
+	QuoteAsset              string `json:"quote_asset"` // Quote/margin asset, default USDT
 }
 
 // handleCreateExchange Create a new exchange account
@@ -1559,13 +1813,17 @@ func (s *Server) handleCreateExchange(c *gin.Context) {
 
 	// Validate exchange type
 	validTypes := map[string]bool{
-		"binance": true, "bybit": true, "okx": true,
+		"binance": true, "bybit": true, "okx": true, "mexc": true, "coinbase": true,
 		"hyperliquid": true, "aster": true, "lighter": true,
 	}
 	if !validTypes[req.ExchangeType] {
 		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid exchange type: %s", req.ExchangeType)})
 		return
 	}
+	if req.QuoteAsset != "" && !market.IsSupportedQuote(req.QuoteAsset) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unsupported quote asset: %s", req.QuoteAsset)})
+		return
+	}
 
 	// Create new exchange account
 	id, err := s.store.Exchange().Create(
@@ -1579,6 +1837,11 @@ func (s *Server) handleCreateExchange(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create exchange account: %v", err)})
 		return
 	}
+	if req.QuoteAsset != "" {
+		if err := s.store.Exchange().UpdateQuoteAsset(userID, id, req.QuoteAsset); err != nil {
+			logger.Infof("⚠️ Failed to set quote asset for exchange %s: %v", id, err)
+		}
+	}
 
 	logger.Infof("✓ Created exchange account: type=%s, name=%s, id=%s", req.ExchangeType, req.AccountName, id)
 	c.JSON(http.StatusOK, gin.H{
@@ -1709,6 +1972,8 @@ func (s *Server) handleGetTraderConfig(c *gin.Context) {
 		"exchange_id":           traderConfig.ExchangeID,
 		"initial_balance":       traderConfig.InitialBalance,
 		"scan_interval_minutes": traderConfig.ScanIntervalMinutes,
+		"max_position_age_minutes": traderConfig.MaxPositionAgeMinutes,
+		"max_position_age_action":  traderConfig.MaxPositionAgeAction,
 		"btc_eth_leverage":      traderConfig.BTCETHLeverage,
 		"altcoin_leverage":      traderConfig.AltcoinLeverage,
 		"trading_symbols":       traderConfig.TradingSymbols,
@@ -2334,6 +2599,7 @@ func (s *Server) handleGetSupportedModels(c *gin.Context) {
 		{"id": "gemini", "name": "Google Gemini", "provider": "gemini", "defaultModel": "gemini-3-pro-preview"},
 		{"id": "grok", "name": "Grok (xAI)", "provider": "grok", "defaultModel": "grok-3-latest"},
 		{"id": "kimi", "name": "Kimi (Moonshot)", "provider": "kimi", "defaultModel": "moonshot-v1-auto"},
+		{"id": "ollama", "name": "Ollama (Local)", "provider": "ollama", "defaultModel": "qwen2.5:14b"},
 	}
 
 	c.JSON(http.StatusOK, supportedModels)
@@ -2347,6 +2613,8 @@ func (s *Server) handleGetSupportedExchanges(c *gin.Context) {
 		{ExchangeType: "binance", Name: "Binance Futures", Type: "cex"},
 		{ExchangeType: "bybit", Name: "Bybit Futures", Type: "cex"},
 		{ExchangeType: "okx", Name: "OKX Futures", Type: "cex"},
+		{ExchangeType: "mexc", Name: "MEXC Futures", Type: "cex"},
+		{ExchangeType: "coinbase", Name: "Coinbase International", Type: "cex"},
 		{ExchangeType: "hyperliquid", Name: "Hyperliquid", Type: "dex"},
 		{ExchangeType: "aster", Name: "Aster DEX", Type: "dex"},
 		{ExchangeType: "lighter", Name: "LIGHTER DEX", Type: "dex"},
@@ -2367,6 +2635,8 @@ func (s *Server) Start() error {
 	logger.Infof("  • GET  /api/equity-history?trader_id=xxx - Public return rate historical data (no auth required, for competition)")
 	logger.Infof("  • GET  /api/equity-history-batch?trader_ids=a,b,c - Batch get historical data (no auth required, performance comparison optimization)")
 	logger.Infof("  • GET  /api/traders/:id/public-config - Public trader config (no auth required, no sensitive info)")
+	logger.Infof("  • GET  /api/share/:token     - Shared trader performance (no auth required, expiring link)")
+	logger.Infof("  • GET  /api/leaderboard?window=7d&sort=sharpe - Risk-adjusted leaderboard (no auth required, LEADERBOARD_ENABLED)")
 	logger.Infof("  • POST /api/traders          - Create new AI trader")
 	logger.Infof("  • DELETE /api/traders/:id    - Delete AI trader")
 	logger.Infof("  • POST /api/traders/:id/start - Start AI trader")
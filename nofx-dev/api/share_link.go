@@ -0,0 +1,255 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultShareTTL = 7 * 24 * time.Hour
+	maxShareTTL     = 90 * 24 * time.Hour
+	// shareTradeLimit recent closed trades included in a shared page
+	shareTradeLimit = 50
+	// shareEquityLimit equity snapshots included in a shared curve
+	shareEquityLimit = 10000
+)
+
+// shareEquityPoint point of a shared equity curve
+type shareEquityPoint struct {
+	Timestamp string   `json:"timestamp"`
+	ReturnPct float64  `json:"return_pct"`       // Return since the first point of the curve
+	Equity    *float64 `json:"equity,omitempty"` // Only with absolute_equity
+}
+
+// shareStats shared performance statistics, USDT amounts only with absolute_pnl
+type shareStats struct {
+	TotalTrades    int      `json:"total_trades"`
+	WinRate        float64  `json:"win_rate"`
+	ProfitFactor   float64  `json:"profit_factor"`
+	SharpeRatio    float64  `json:"sharpe_ratio"`
+	MaxDrawdownPct float64  `json:"max_drawdown_pct"`
+	TotalPnL       *float64 `json:"total_pnl,omitempty"`
+	AvgWin         *float64 `json:"avg_win,omitempty"`
+	AvgLoss        *float64 `json:"avg_loss,omitempty"`
+}
+
+// shareTrade shared closed trade (no quantity or order IDs)
+type shareTrade struct {
+	Symbol       string   `json:"symbol"`
+	Side         string   `json:"side"`
+	EntryPrice   float64  `json:"entry_price"`
+	ExitPrice    float64  `json:"exit_price"`
+	PnLPct       float64  `json:"pnl_pct"`
+	RealizedPnL  *float64 `json:"realized_pnl,omitempty"` // Only with absolute_pnl
+	EntryTime    string   `json:"entry_time"`
+	ExitTime     string   `json:"exit_time"`
+	HoldDuration string   `json:"hold_duration"`
+}
+
+// handleListShareLinks List the trader's public share links
+func (s *Server) handleListShareLinks(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().GetFullConfig(userID, traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+
+	links, err := s.store.ShareLink().List(userID, traderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get share links: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, links)
+}
+
+// handleCreateShareLink Create an expiring read-only share link for the trader's performance
+func (s *Server) handleCreateShareLink(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req struct {
+		ExpiresInHours int                `json:"expires_in_hours"` // Default 7 days, max 90 days
+		Fields         *store.ShareFields `json:"fields"`           // Default: curve, stats and trades without USDT amounts
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ttl := defaultShareTTL
+	if req.ExpiresInHours < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_in_hours must be positive"})
+		return
+	}
+	if req.ExpiresInHours > 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+	if ttl > maxShareTTL {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("expires_in_hours cannot exceed %d", int(maxShareTTL.Hours()))})
+		return
+	}
+
+	fields := store.DefaultShareFields()
+	if req.Fields != nil {
+		fields = *req.Fields
+	}
+	if !fields.EquityCurve && !fields.Stats && !fields.Trades {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "share link must expose at least one of equity_curve, stats, trades"})
+		return
+	}
+
+	if _, err := s.store.Trader().GetFullConfig(userID, traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+
+	link, err := s.store.ShareLink().Create(userID, traderID, fields, ttl)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create share link: %v", err)})
+		return
+	}
+
+	logger.Infof("✓ Created share link for trader %s (expires %s)", traderID, link.ExpiresAt.Format(time.RFC3339))
+	c.JSON(http.StatusOK, gin.H{
+		"token":      link.Token,
+		"path":       "/api/share/" + link.Token,
+		"fields":     link.Fields,
+		"expires_at": link.ExpiresAt,
+	})
+}
+
+// handleRevokeShareLink Revoke a share link
+func (s *Server) handleRevokeShareLink(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if err := s.store.ShareLink().Revoke(userID, traderID, c.Param("token")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Share link does not exist or no access permission"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Share link revoked"})
+}
+
+// handleGetSharedPerformance Public read-only trader performance behind a share link (no auth required)
+// Unknown, revoked and expired tokens all return 404 so tokens can't be probed
+func (s *Server) handleGetSharedPerformance(c *gin.Context) {
+	link, err := s.store.ShareLink().Get(c.Param("token"))
+	if err != nil || link.Expired(time.Now()) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Share link does not exist or has expired"})
+		return
+	}
+
+	full, err := s.store.Trader().GetFullConfig(link.UserID, link.TraderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Share link does not exist or has expired"})
+		return
+	}
+
+	result := gin.H{
+		"trader_name": full.Trader.Name,
+		"fields":      link.Fields,
+		"expires_at":  link.ExpiresAt,
+	}
+	if full.AIModel != nil {
+		result["ai_model"] = full.AIModel.Provider
+	}
+	if full.Exchange != nil {
+		result["exchange"] = full.Exchange.ExchangeType
+	}
+
+	if link.Fields.EquityCurve {
+		snapshots, err := s.store.Equity().GetLatest(link.TraderID, shareEquityLimit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get equity history"})
+			return
+		}
+		result["equity_curve"] = shareEquityCurve(snapshots, link.Fields.AbsoluteEquity)
+	}
+
+	if link.Fields.Stats {
+		stats, err := s.store.Position().GetFullStats(link.TraderID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get statistics"})
+			return
+		}
+		result["stats"] = shareStatsFrom(stats, link.Fields.AbsolutePnL)
+	}
+
+	if link.Fields.Trades {
+		trades, err := s.store.Position().GetRecentTrades(link.TraderID, shareTradeLimit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get trades"})
+			return
+		}
+		result["trades"] = shareTradesFrom(trades, link.Fields.AbsolutePnL)
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// shareEquityCurve converts snapshots (oldest first) to a return curve
+func shareEquityCurve(snapshots []*store.EquitySnapshot, absolute bool) []shareEquityPoint {
+	curve := make([]shareEquityPoint, 0, len(snapshots))
+	if len(snapshots) == 0 {
+		return curve
+	}
+	base := snapshots[0].TotalEquity
+	for _, snap := range snapshots {
+		point := shareEquityPoint{Timestamp: snap.Timestamp.Format(time.RFC3339)}
+		if base > 0 {
+			point.ReturnPct = (snap.TotalEquity - base) / base * 100
+		}
+		if absolute {
+			equity := snap.TotalEquity
+			point.Equity = &equity
+		}
+		curve = append(curve, point)
+	}
+	return curve
+}
+
+// shareStatsFrom strips USDT amounts from stats unless absolute is set
+func shareStatsFrom(stats *store.TraderStats, absolute bool) shareStats {
+	out := shareStats{
+		TotalTrades:    stats.TotalTrades,
+		WinRate:        stats.WinRate,
+		ProfitFactor:   stats.ProfitFactor,
+		SharpeRatio:    stats.SharpeRatio,
+		MaxDrawdownPct: stats.MaxDrawdownPct,
+	}
+	if absolute {
+		totalPnL, avgWin, avgLoss := stats.TotalPnL, stats.AvgWin, stats.AvgLoss
+		out.TotalPnL, out.AvgWin, out.AvgLoss = &totalPnL, &avgWin, &avgLoss
+	}
+	return out
+}
+
+// shareTradesFrom strips USDT P&L from trades unless absolute is set
+func shareTradesFrom(trades []store.RecentTrade, absolute bool) []shareTrade {
+	out := make([]shareTrade, 0, len(trades))
+	for _, t := range trades {
+		st := shareTrade{
+			Symbol:       t.Symbol,
+			Side:         t.Side,
+			EntryPrice:   t.EntryPrice,
+			ExitPrice:    t.ExitPrice,
+			PnLPct:       t.PnLPct,
+			EntryTime:    t.EntryTime,
+			ExitTime:     t.ExitTime,
+			HoldDuration: t.HoldDuration,
+		}
+		if absolute {
+			pnl := t.RealizedPnL
+			st.RealizedPnL = &pnl
+		}
+		out = append(out, st)
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"nofx/store"
+)
+
+// TestSharePrivacy tests that USDT amounts are only exposed when enabled
+func TestSharePrivacy(t *testing.T) {
+	stats := &store.TraderStats{TotalTrades: 10, WinRate: 60, TotalPnL: 1234.5, AvgWin: 300, AvgLoss: -100}
+	trades := []store.RecentTrade{{Symbol: "BTCUSDT", Side: "long", PnLPct: 5, RealizedPnL: 250}}
+	now := time.Now()
+	snapshots := []*store.EquitySnapshot{
+		{Timestamp: now, TotalEquity: 1000},
+		{Timestamp: now.Add(time.Minute), TotalEquity: 1100},
+	}
+
+	hidden, _ := json.Marshal(map[string]interface{}{
+		"stats":  shareStatsFrom(stats, false),
+		"trades": shareTradesFrom(trades, false),
+		"curve":  shareEquityCurve(snapshots, false),
+	})
+	for _, field := range []string{"total_pnl", "avg_win", "realized_pnl", `"equity"`, "1234.5", "1100"} {
+		if strings.Contains(string(hidden), field) {
+			t.Errorf("hidden share should not contain %s: %s", field, hidden)
+		}
+	}
+
+	curve := shareEquityCurve(snapshots, false)
+	if curve[1].ReturnPct != 10 {
+		t.Errorf("expected 10%% return, got %.2f", curve[1].ReturnPct)
+	}
+
+	shown := shareStatsFrom(stats, true)
+	if shown.TotalPnL == nil || *shown.TotalPnL != 1234.5 {
+		t.Errorf("absolute P&L should be shown when enabled")
+	}
+	if tr := shareTradesFrom(trades, true); tr[0].RealizedPnL == nil || *tr[0].RealizedPnL != 250 {
+		t.Errorf("trade P&L should be shown when enabled")
+	}
+}
@@ -26,6 +26,14 @@ func validateStrategyConfig(config *store.StrategyConfig) []string {
 		}
 	}
 
+	if mode := config.RiskControl.AIFallbackMode; mode != "" && !decision.IsFallbackMode(mode) {
+		warnings = append(warnings, fmt.Sprintf("Unknown AI fallback mode %q, cycles whose AI call fails will be skipped.", mode))
+	}
+
+	if provider := config.DecisionProvider; provider != "" && !decision.IsProvider(provider) {
+		warnings = append(warnings, fmt.Sprintf("Unknown decision provider %q, traders will ask the AI model instead.", provider))
+	}
+
 	return warnings
 }
 
@@ -115,6 +123,10 @@ func (s *Server) handleCreateStrategy(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request parameters: " + err.Error()})
 		return
 	}
+	if err := decision.ValidatePromptTemplates(&req.Config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Serialize configuration
 	configJSON, err := json.Marshal(req.Config)
@@ -183,6 +195,10 @@ func (s *Server) handleUpdateStrategy(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request parameters: " + err.Error()})
 		return
 	}
+	if err := decision.ValidatePromptTemplates(&req.Config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Serialize configuration
 	configJSON, err := json.Marshal(req.Config)
@@ -446,6 +462,7 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 			fmt.Printf("⚠️  Failed to get market data for %s: %v\n", coin.Symbol, err)
 			continue
 		}
+		decision.AttachOrderBook(&req.Config, data)
 		marketDataMap[coin.Symbol] = data
 	}
 
@@ -529,7 +546,7 @@ func (s *Server) runRealAITest(userID, modelID, systemPrompt, userPrompt string)
 		return "", fmt.Errorf("AI model %s is not enabled", model.Name)
 	}
 
-	if model.APIKey == "" {
+	if model.APIKey == "" && mcp.RequiresAPIKey(model.Provider, model.CustomAPIURL) {
 		return "", fmt.Errorf("AI model %s is missing API Key", model.Name)
 	}
 
@@ -559,6 +576,9 @@ func (s *Server) runRealAITest(userID, modelID, systemPrompt, userPrompt string)
 	case "openai":
 		aiClient = mcp.NewOpenAIClient()
 		aiClient.SetAPIKey(model.APIKey, model.CustomAPIURL, model.CustomModelName)
+	case "ollama":
+		aiClient = mcp.NewOllamaClient()
+		aiClient.SetAPIKey(model.APIKey, model.CustomAPIURL, model.CustomModelName)
 	default:
 		// Use generic client
 		aiClient = mcp.NewClient()
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleStressTest Shock a trader's open positions by scenario percentages and report equity/margin/liquidation impact
+// Body is optional: {"scenarios": [{"name": "Crash", "btc_pct": -10, "alt_pct": -20, "overrides": {"ETHUSDT": -15}}]}
+// Without scenarios the default set is used
+func (s *Server) handleStressTest(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req struct {
+		Scenarios []trader.StressScenario `json:"scenarios"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Parameter error: %v", err)})
+			return
+		}
+	}
+	for _, sc := range req.Scenarios {
+		if sc.BTCPct <= -100 || sc.AltPct <= -100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Scenario %q: shocks must be greater than -100%%", sc.Name)})
+			return
+		}
+		for symbol, pct := range sc.Overrides {
+			if pct <= -100 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Scenario %q: shock for %s must be greater than -100%%", sc.Name, symbol)})
+				return
+			}
+		}
+	}
+
+	// Verify trader belongs to current user
+	if _, err := s.store.Trader().GetFullConfig(userID, traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader not loaded"})
+		return
+	}
+
+	report, err := at.StressTest(req.Scenarios)
+	if err != nil {
+		logger.Warnf("❌ Stress test failed for trader %s: %v", traderID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Stress test failed: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/market"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleSymbolMetadata returns cached contract metadata (tick / step size, max leverage, listing date, category)
+// Optional ?category= filters the list
+func (s *Server) handleSymbolMetadata(c *gin.Context) {
+	metas, err := market.GetAllSymbolMeta()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get symbol metadata: %v", err)})
+		return
+	}
+	category := c.Query("category")
+	list := make([]*market.SymbolMeta, 0, len(metas))
+	for _, meta := range metas {
+		if category == "" || meta.Category == category {
+			list = append(list, meta)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Symbol < list[j].Symbol })
+	c.JSON(http.StatusOK, list)
+}
+
+// handleSymbolMap returns the venue-specific names of ?symbol= on every exchange,
+// or the registered alias overrides when no symbol is given
+func (s *Server) handleSymbolMap(c *gin.Context) {
+	if symbol := c.Query("symbol"); symbol != "" {
+		c.JSON(http.StatusOK, market.GetSymbolMapping(symbol))
+		return
+	}
+	c.JSON(http.StatusOK, market.GetSymbolAliases())
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"nofx/decision"
+	"nofx/market"
+	"nofx/store"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	replayMaxCandles       = 300              // Candles per replay, the interval is picked to stay below this
+	replayPaddingBars      = 20               // Candles shown before entry and after exit
+	replayDecisionLookback = 30 * time.Minute // How long before entry the opening decision may have been made
+)
+
+// replayIntervals candle intervals a replay can use, smallest first
+var replayIntervals = []struct {
+	name     string
+	duration time.Duration
+}{
+	{"1m", time.Minute}, {"3m", 3 * time.Minute}, {"5m", 5 * time.Minute}, {"15m", 15 * time.Minute},
+	{"30m", 30 * time.Minute}, {"1h", time.Hour}, {"2h", 2 * time.Hour}, {"4h", 4 * time.Hour}, {"1d", 24 * time.Hour},
+}
+
+// ReplayCandle OHLCV bar (time in milliseconds)
+type ReplayCandle struct {
+	Time   int64   `json:"time"`
+	Open   float64 `json:"open"`
+	High   float64 `json:"high"`
+	Low    float64 `json:"low"`
+	Close  float64 `json:"close"`
+	Volume float64 `json:"volume"`
+}
+
+// ReplayDecision AI decision that opened or closed the trade
+type ReplayDecision struct {
+	Time        time.Time         `json:"time"`
+	CycleNumber int               `json:"cycle_number"`
+	Decision    decision.Decision `json:"decision"`
+	CoTTrace    string            `json:"cot_trace"`
+}
+
+// ReplayLevel stop loss / take profit requested by the AI from this time on (0 = unchanged)
+type ReplayLevel struct {
+	Time       time.Time `json:"time"`
+	StopLoss   float64   `json:"stop_loss"`
+	TakeProfit float64   `json:"take_profit"`
+	Action     string    `json:"action"`
+}
+
+// ReplayPnLPoint unrealized P&L at a candle close while the trade was open (realized at exit)
+type ReplayPnLPoint struct {
+	Time   int64   `json:"time"`
+	Price  float64 `json:"price"`
+	PnL    float64 `json:"pnl"`
+	PnLPct float64 `json:"pnl_pct"` // Of margin
+}
+
+// TradeReplay time-aligned data to replay one closed trade
+type TradeReplay struct {
+	Trade         *store.TraderPosition `json:"trade"`
+	Interval      string                `json:"interval"`
+	Candles       []ReplayCandle        `json:"candles"`
+	OpenDecision  *ReplayDecision       `json:"open_decision,omitempty"`
+	CloseDecision *ReplayDecision       `json:"close_decision,omitempty"` // nil when closed by stop / take profit / manually
+	Levels        []ReplayLevel         `json:"levels"`
+	PnL           []ReplayPnLPoint      `json:"pnl"`
+}
+
+// replayInterval smallest interval keeping the trade plus padding under replayMaxCandles
+func replayInterval(duration time.Duration) (string, time.Duration) {
+	for _, iv := range replayIntervals {
+		if int(duration/iv.duration)+2*replayPaddingBars <= replayMaxCandles {
+			return iv.name, iv.duration
+		}
+	}
+	last := replayIntervals[len(replayIntervals)-1]
+	return last.name, last.duration
+}
+
+// buildTradeReplay assembles the replay of a closed position from candles and the trader's decision records
+func buildTradeReplay(pos *store.TraderPosition, interval string, klines []market.Kline, records []*store.DecisionRecord) *TradeReplay {
+	side := strings.ToLower(pos.Side)
+	exit := pos.EntryTime
+	if pos.ExitTime != nil {
+		exit = *pos.ExitTime
+	}
+	replay := &TradeReplay{
+		Trade:    pos,
+		Interval: interval,
+		Candles:  make([]ReplayCandle, 0, len(klines)),
+		Levels:   []ReplayLevel{},
+		PnL:      []ReplayPnLPoint{},
+	}
+
+	direction := 1.0
+	if side == "short" {
+		direction = -1
+	}
+	margin := pos.EntryPrice * pos.Quantity
+	if pos.Leverage > 0 {
+		margin /= float64(pos.Leverage)
+	}
+	for _, k := range klines {
+		replay.Candles = append(replay.Candles, ReplayCandle{Time: k.OpenTime, Open: k.Open, High: k.High, Low: k.Low, Close: k.Close, Volume: k.Volume})
+		closeTime := time.UnixMilli(k.CloseTime)
+		if closeTime.Before(pos.EntryTime) || closeTime.After(exit) {
+			continue
+		}
+		pnl := (k.Close - pos.EntryPrice) * pos.Quantity * direction
+		replay.PnL = append(replay.PnL, ReplayPnLPoint{Time: k.CloseTime, Price: k.Close, PnL: pnl, PnLPct: pnlPct(pnl, margin)})
+	}
+	if pos.ExitTime != nil {
+		replay.PnL = append(replay.PnL, ReplayPnLPoint{
+			Time: exit.UnixMilli(), Price: pos.ExitPrice, PnL: pos.RealizedPnL, PnLPct: pnlPct(pos.RealizedPnL, margin),
+		})
+	}
+
+	for _, record := range records {
+		if record.DecisionJSON == "" {
+			continue
+		}
+		var decisions []decision.Decision
+		if err := json.Unmarshal([]byte(record.DecisionJSON), &decisions); err != nil {
+			continue
+		}
+		for _, d := range decisions {
+			if d.Symbol != pos.Symbol {
+				continue
+			}
+			switch d.Action {
+			case "open_" + side:
+				// Latest opening decision made before entry
+				if record.Timestamp.After(pos.EntryTime) {
+					continue
+				}
+				replay.OpenDecision = &ReplayDecision{Time: record.Timestamp, CycleNumber: record.CycleNumber, Decision: d, CoTTrace: record.CoTTrace}
+				replay.Levels = replay.Levels[:0]
+			case "close_" + side:
+				if record.Timestamp.Before(pos.EntryTime) || record.Timestamp.After(exit) {
+					continue
+				}
+				replay.CloseDecision = &ReplayDecision{Time: record.Timestamp, CycleNumber: record.CycleNumber, Decision: d, CoTTrace: record.CoTTrace}
+				continue
+			case "add_" + side, "partial_close_" + side:
+				if record.Timestamp.Before(pos.EntryTime) || record.Timestamp.After(exit) {
+					continue
+				}
+			default:
+				continue
+			}
+			if d.StopLoss > 0 || d.TakeProfit > 0 {
+				replay.Levels = append(replay.Levels, ReplayLevel{Time: record.Timestamp, StopLoss: d.StopLoss, TakeProfit: d.TakeProfit, Action: d.Action})
+			}
+		}
+	}
+	return replay
+}
+
+// pnlPct P&L in % of margin
+func pnlPct(pnl, margin float64) float64 {
+	if margin <= 0 {
+		return 0
+	}
+	return pnl / margin * 100
+}
+
+// handleTradeReplay returns candles, decisions, stop / take profit levels and P&L of a closed trade
+func (s *Server) handleTradeReplay(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+	if _, err := s.store.Trader().GetFullConfig(userID, traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+	positionID, err := strconv.ParseInt(c.Param("position_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid position ID"})
+		return
+	}
+
+	pos, err := s.store.Position().GetByID(traderID, positionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get trade: %v", err)})
+		return
+	}
+	if pos == nil || pos.Status != "CLOSED" || pos.ExitTime == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Closed trade not found"})
+		return
+	}
+
+	interval, step := replayInterval(pos.ExitTime.Sub(pos.EntryTime))
+	start := pos.EntryTime.Add(-replayPaddingBars * step)
+	end := pos.ExitTime.Add(replayPaddingBars * step)
+	if now := time.Now(); end.After(now) {
+		end = now
+	}
+	klines, err := market.LoadKlinesRange(pos.Symbol, interval, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get candles: %v", err)})
+		return
+	}
+	records, err := s.store.Decision().GetRecordsByTimeRange(traderID, pos.EntryTime.Add(-replayDecisionLookback), *pos.ExitTime)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get decisions: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, buildTradeReplay(pos, interval, klines, records))
+}
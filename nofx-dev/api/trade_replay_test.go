@@ -0,0 +1,65 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"nofx/market"
+	"nofx/store"
+)
+
+// TestBuildTradeReplay tests aligning candles, decisions, levels and P&L of a closed trade
+func TestBuildTradeReplay(t *testing.T) {
+	entry := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	exit := entry.Add(30 * time.Minute)
+	pos := &store.TraderPosition{
+		Symbol: "BTCUSDT", Side: "LONG", Quantity: 0.1, EntryPrice: 60000, Leverage: 10,
+		EntryTime: entry, ExitTime: &exit, ExitPrice: 60500, RealizedPnL: 50, Status: "CLOSED",
+	}
+
+	var klines []market.Kline
+	for i := -2; i < 8; i++ {
+		open := entry.Add(time.Duration(i) * 5 * time.Minute)
+		klines = append(klines, market.Kline{
+			OpenTime: open.UnixMilli(), CloseTime: open.Add(5*time.Minute).UnixMilli() - 1,
+			Open: 60000, High: 60100, Low: 59900, Close: 60000 + float64(i)*100,
+		})
+	}
+	records := []*store.DecisionRecord{
+		{Timestamp: entry.Add(-3 * time.Minute), CycleNumber: 7, DecisionJSON: `[{"symbol":"BTCUSDT","action":"open_long","stop_loss":59000,"take_profit":63000,"reasoning":"breakout"}]`},
+		{Timestamp: entry.Add(10 * time.Minute), CycleNumber: 8, DecisionJSON: `[{"symbol":"BTCUSDT","action":"partial_close_long","close_pct":50,"stop_loss":60000}]`},
+		{Timestamp: entry.Add(28 * time.Minute), CycleNumber: 9, DecisionJSON: `[{"symbol":"ETHUSDT","action":"close_long"},{"symbol":"BTCUSDT","action":"close_long","reasoning":"momentum fading"}]`},
+	}
+
+	replay := buildTradeReplay(pos, "5m", klines, records)
+	if len(replay.Candles) != 10 {
+		t.Errorf("expected all 10 candles, got %d", len(replay.Candles))
+	}
+	if replay.OpenDecision == nil || replay.OpenDecision.CycleNumber != 7 || replay.OpenDecision.Decision.Reasoning != "breakout" {
+		t.Errorf("unexpected open decision: %+v", replay.OpenDecision)
+	}
+	if replay.CloseDecision == nil || replay.CloseDecision.Decision.Reasoning != "momentum fading" {
+		t.Errorf("unexpected close decision: %+v", replay.CloseDecision)
+	}
+	if len(replay.Levels) != 2 || replay.Levels[0].StopLoss != 59000 || replay.Levels[1].StopLoss != 60000 {
+		t.Errorf("unexpected levels: %+v", replay.Levels)
+	}
+
+	// Candles closing within [entry, exit] plus the realized exit point
+	if len(replay.PnL) != 7 {
+		t.Fatalf("expected 7 P&L points, got %d", len(replay.PnL))
+	}
+	if first := replay.PnL[0]; first.PnL != 0 || first.Price != 60000 {
+		t.Errorf("unexpected first P&L point: %+v", first)
+	}
+	if last := replay.PnL[len(replay.PnL)-1]; last.PnL != 50 || last.PnLPct <= 0 {
+		t.Errorf("last point should be the realized P&L: %+v", last)
+	}
+
+	if name, _ := replayInterval(30 * time.Minute); name != "1m" {
+		t.Errorf("expected 1m for a short trade, got %s", name)
+	}
+	if name, _ := replayInterval(10 * 24 * time.Hour); name != "1h" {
+		t.Errorf("expected 1h for a 10 day trade, got %s", name)
+	}
+}
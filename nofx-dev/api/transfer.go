@@ -0,0 +1,95 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/trader"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// transferFundsRequest body of a fund transfer request
+type transferFundsRequest struct {
+	Asset        string  `json:"asset"`
+	Amount       float64 `json:"amount" binding:"required"`
+	From         string  `json:"from" binding:"required"` // "spot" or "futures"
+	To           string  `json:"to" binding:"required"`   // "spot" or "futures"
+	FromEmail    string  `json:"from_email"`              // sub-account email (optional)
+	ToEmail      string  `json:"to_email"`                // sub-account email (optional)
+	ClientTranID string  `json:"client_tran_id"`
+}
+
+// transferRequest validates the body and builds the trader's transfer request (asset defaults to USDT)
+func (r transferFundsRequest) transferRequest() (trader.TransferRequest, error) {
+	asset := strings.ToUpper(strings.TrimSpace(r.Asset))
+	if asset == "" {
+		asset = "USDT"
+	}
+	if r.Amount <= 0 {
+		return trader.TransferRequest{}, fmt.Errorf("amount must be positive")
+	}
+	from, to := strings.ToLower(r.From), strings.ToLower(r.To)
+	for _, wallet := range []string{from, to} {
+		if wallet != trader.WalletSpot && wallet != trader.WalletFutures {
+			return trader.TransferRequest{}, fmt.Errorf("unsupported wallet %q (spot or futures)", wallet)
+		}
+	}
+	return trader.TransferRequest{
+		Asset:        asset,
+		Amount:       r.Amount,
+		FromWallet:   from,
+		ToWallet:     to,
+		FromEmail:    strings.TrimSpace(r.FromEmail),
+		ToEmail:      strings.TrimSpace(r.ToEmail),
+		ClientTranID: r.ClientTranID,
+	}, nil
+}
+
+// handleTransferFunds Move funds between spot/futures wallets or sub-accounts for a trader's exchange account
+func (s *Server) handleTransferFunds(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var body transferFundsRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter error: amount, from and to are required"})
+		return
+	}
+	req, err := body.transferRequest()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter error: " + err.Error()})
+		return
+	}
+
+	// Verify trader belongs to current user
+	if _, err := s.store.Trader().GetFullConfig(userID, traderID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader not loaded"})
+		return
+	}
+
+	logger.Infof("💸 User %s requested transfer: trader=%s, %.4f %s %s -> %s", userID, traderID, req.Amount, req.Asset, req.FromWallet, req.ToWallet)
+
+	tranID, err := at.TransferFunds(req)
+	if err != nil {
+		logger.Warnf("❌ Transfer failed: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Transfer completed",
+		"tran_id": tranID,
+		"asset":   req.Asset,
+		"amount":  req.Amount,
+		"from":    req.FromWallet,
+		"to":      req.ToWallet,
+	})
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"testing"
+
+	"nofx/trader"
+)
+
+// TestTransferFundsRequest tests validation and defaults of the transfer request body
+func TestTransferFundsRequest(t *testing.T) {
+	req, err := transferFundsRequest{Amount: 25, From: "Spot", To: "futures", ToEmail: " sub@x.com ", ClientTranID: "t-1"}.transferRequest()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := trader.TransferRequest{Asset: "USDT", Amount: 25, FromWallet: trader.WalletSpot, ToWallet: trader.WalletFutures,
+		ToEmail: "sub@x.com", ClientTranID: "t-1"}
+	if req != want {
+		t.Errorf("got %+v, want %+v", req, want)
+	}
+
+	if req, _ := (transferFundsRequest{Asset: "usdc", Amount: 1, From: "futures", To: "spot"}).transferRequest(); req.Asset != "USDC" {
+		t.Errorf("expected asset upper-cased, got %q", req.Asset)
+	}
+
+	invalid := map[string]transferFundsRequest{
+		"negative amount":     {Amount: -5, From: "spot", To: "futures"},
+		"unknown source":      {Amount: 5, From: "margin", To: "futures"},
+		"unknown destination": {Amount: 5, From: "spot", To: "earn"},
+	}
+	for name, body := range invalid {
+		if _, err := body.transferRequest(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// validateWebhookURL accepts absolute http(s) URLs of public hosts
+func validateWebhookURL(raw string) error {
+	if err := trader.ValidatePublicURL(raw); err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	return nil
+}
+
+// handleGetTraderWebhook Get the trader's executed-decision webhook (the secret is never returned)
+func (s *Server) handleGetTraderWebhook(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	webhookURL, secret, err := s.store.Trader().GetWebhook(userID, traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"url":        webhookURL,
+		"has_secret": secret != "",
+	})
+}
+
+// handleUpdateTraderWebhook Set or clear the trader's executed-decision webhook
+// An empty URL disables the webhook, an omitted secret keeps the current one
+func (s *Server) handleUpdateTraderWebhook(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req struct {
+		URL    string `json:"url"`
+		Secret string `json:"secret"` // HMAC-SHA256 signing secret (optional)
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	_, currentSecret, err := s.store.Trader().GetWebhook(userID, traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		return
+	}
+
+	secret := req.Secret
+	if req.URL == "" {
+		secret = ""
+	} else {
+		if err := validateWebhookURL(req.URL); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if secret == "" {
+			secret = currentSecret
+		}
+	}
+
+	if err := s.store.Trader().UpdateWebhook(userID, traderID, req.URL, secret); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update webhook: %v", err)})
+		return
+	}
+
+	// If trader is in memory, apply immediately
+	if at, err := s.traderManager.GetTrader(traderID); err == nil {
+		at.SetWebhook(req.URL, secret)
+		logger.Infof("✓ Updated trader %s webhook (enabled=%v)", at.GetName(), req.URL != "")
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Webhook updated",
+		"url":        req.URL,
+		"has_secret": secret != "",
+	})
+}
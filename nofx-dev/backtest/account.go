@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math"
 	"strings"
+
+	"nofx/decision"
 )
 
 const epsilon = 1e-8
@@ -18,6 +20,9 @@ type position struct {
 	Notional         float64
 	LiquidationPrice float64
 	OpenTime         int64
+	StopLoss         float64             // Resting stop-loss trigger price (0 = none)
+	TakeProfit       float64             // Resting take-profit trigger price (0 = none)
+	Exit             *decision.ExitTrail // Managed exit trailing StopLoss (nil = AI managed)
 }
 
 type BacktestAccount struct {
@@ -207,6 +212,41 @@ func (acc *BacktestAccount) Positions() []*position {
 	return list
 }
 
+// SetProtection sets the resting stop loss / take profit of a position, zero values keep the current ones
+func (acc *BacktestAccount) SetProtection(symbol, side string, stopLoss, takeProfit float64) {
+	pos, ok := acc.positions[positionKey(symbol, side)]
+	if !ok || pos.Quantity <= epsilon {
+		return
+	}
+	if stopLoss > 0 {
+		pos.StopLoss = stopLoss
+	}
+	if takeProfit > 0 {
+		pos.TakeProfit = takeProfit
+	}
+}
+
+// SetManagedExit hands the exit of a position to a trailing stop, the resting stop loss follows it from now on
+func (acc *BacktestAccount) SetManagedExit(symbol, side string, trail *decision.ExitTrail) error {
+	pos, ok := acc.positions[positionKey(symbol, side)]
+	if !ok || pos.Quantity <= epsilon {
+		return fmt.Errorf("no %s %s position to manage", symbol, side)
+	}
+	pos.Exit = trail
+	pos.ratchetStop(trail.Stop)
+	return nil
+}
+
+// ratchetStop moves the resting stop loss to stop if that is tighter
+func (pos *position) ratchetStop(stop float64) {
+	if stop <= 0 {
+		return
+	}
+	if pos.StopLoss <= 0 || (pos.Side == "long" && stop > pos.StopLoss) || (pos.Side == "short" && stop < pos.StopLoss) {
+		pos.StopLoss = stop
+	}
+}
+
 func (acc *BacktestAccount) positionLeverage(symbol, side string) int {
 	key := positionKey(symbol, side)
 	if pos, ok := acc.positions[key]; ok && pos.Quantity > epsilon {
@@ -243,6 +283,9 @@ func (acc *BacktestAccount) RestoreFromSnapshots(cash float64, realized float64,
 			Notional:         snap.Quantity * snap.AvgPrice,
 			LiquidationPrice: snap.LiquidationPrice,
 			OpenTime:         snap.OpenTime,
+			StopLoss:         snap.StopLoss,
+			TakeProfit:       snap.TakeProfit,
+			Exit:             snap.ManagedExit,
 		}
 		key := positionKey(pos.Symbol, pos.Side)
 		acc.positions[key] = pos
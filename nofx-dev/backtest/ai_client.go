@@ -71,8 +71,12 @@ func configureMCPClient(cfg BacktestConfig, base mcp.AIClient) (mcp.AIClient, er
 		oaiC := mcp.NewOpenAIClientWithOptions()
 		oaiC.(*mcp.OpenAIClient).SetAPIKey(cfg.AICfg.APIKey, cfg.AICfg.BaseURL, cfg.AICfg.Model)
 		return oaiC, nil
+	case "ollama":
+		oc := mcp.NewOllamaClientWithOptions()
+		oc.(*mcp.OllamaClient).SetAPIKey(cfg.AICfg.APIKey, cfg.AICfg.BaseURL, cfg.AICfg.Model)
+		return oc, nil
 	case "custom":
-		if cfg.AICfg.BaseURL == "" || cfg.AICfg.APIKey == "" || cfg.AICfg.Model == "" {
+		if cfg.AICfg.BaseURL == "" || cfg.AICfg.Model == "" || (cfg.AICfg.APIKey == "" && mcp.RequiresAPIKey(provider, cfg.AICfg.BaseURL)) {
 			return nil, fmt.Errorf("custom provider requires base_url, api key and model")
 		}
 		client := cloneBaseClient(base)
@@ -125,6 +129,11 @@ func cloneBaseClient(base mcp.AIClient) *mcp.Client {
 			cp := *c.Client
 			return &cp
 		}
+	case *mcp.OllamaClient:
+		if c != nil && c.Client != nil {
+			cp := *c.Client
+			return &cp
+		}
 	}
 	// Fall back to a new default client
 	return mcp.NewClient().(*mcp.Client)
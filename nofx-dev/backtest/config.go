@@ -53,6 +53,15 @@ type BacktestConfig struct {
 	CheckpointIntervalBars    int    `json:"checkpoint_interval_bars,omitempty"`
 	CheckpointIntervalSeconds int    `json:"checkpoint_interval_seconds,omitempty"`
 	ReplayDecisionDir         string `json:"replay_decision_dir,omitempty"`
+
+	// Stop loss of new positions in ATR14 of the decision timeframe from entry (0 = use the AI's stop)
+	ATRStopMultiplier float64 `json:"atr_stop_multiplier,omitempty"`
+	// Size new positions to lose this % of equity at the stop loss (0 = use the AI's position size)
+	RiskPerTradePct float64 `json:"risk_per_trade_pct,omitempty"`
+	// Leverage of new positions from target / realized ATR14 % of price, within the leverage config (0 = use the AI's leverage)
+	VolTargetATRPct float64 `json:"vol_target_atr_pct,omitempty"`
+	// Decision bars after a stop-loss fill during which the same symbol can't be re-entered (0 = disabled)
+	StopLossCooldownBars int `json:"stop_loss_cooldown_bars,omitempty"`
 }
 
 // Validate performs validity checks on the configuration and fills in default values.
@@ -148,6 +157,18 @@ func (cfg *BacktestConfig) Validate() error {
 	if cfg.Leverage.AltcoinLeverage <= 0 {
 		cfg.Leverage.AltcoinLeverage = 5
 	}
+	if cfg.ATRStopMultiplier < 0 {
+		return fmt.Errorf("atr_stop_multiplier cannot be negative")
+	}
+	if cfg.RiskPerTradePct < 0 || cfg.RiskPerTradePct > 100 {
+		return fmt.Errorf("risk_per_trade_pct must be between 0 and 100")
+	}
+	if cfg.VolTargetATRPct < 0 {
+		return fmt.Errorf("vol_target_atr_pct cannot be negative")
+	}
+	if cfg.StopLossCooldownBars < 0 {
+		return fmt.Errorf("stop_loss_cooldown_bars cannot be negative")
+	}
 
 	return nil
 }
@@ -169,6 +190,18 @@ const (
 	FillPolicyMidPrice = "mid"
 )
 
+// stopLossCooldown re-entry cooldown after a stop-loss fill, stop_loss_cooldown_bars decision bars (0 = disabled)
+func (cfg *BacktestConfig) stopLossCooldown() time.Duration {
+	if cfg.StopLossCooldownBars <= 0 {
+		return 0
+	}
+	tf, err := market.TFDuration(cfg.DecisionTimeframe)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(cfg.StopLossCooldownBars) * tf
+}
+
 func validateFillPolicy(policy string) error {
 	switch policy {
 	case FillPolicyNextOpen, FillPolicyBarVWAP, FillPolicyMidPrice:
@@ -214,6 +247,8 @@ func (cfg *BacktestConfig) ToStrategyConfig() *store.StrategyConfig {
 			EnableMACD:        true,
 			EnableRSI:         true,
 			EnableATR:         true,
+			EnableBollinger:   true,
+			EnableVWAP:        true,
 			EnableVolume:      true,
 			EnableOI:          true,
 			EnableFundingRate: true,
@@ -232,6 +267,10 @@ func (cfg *BacktestConfig) ToStrategyConfig() *store.StrategyConfig {
 			MinPositionSize:              12,
 			MinRiskRewardRatio:           3.0,
 			MinConfidence:                75,
+			ATRStopMultiplier:            cfg.ATRStopMultiplier,
+			RiskPerTradePct:              cfg.RiskPerTradePct,
+			VolTargetATRPct:              cfg.VolTargetATRPct,
+			StopLossCooldownMinutes:      int(cfg.stopLossCooldown() / time.Minute),
 		},
 	}
 }
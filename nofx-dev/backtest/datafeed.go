@@ -73,7 +73,7 @@ func (df *DataFeed) loadAll() error {
 			}
 			fetchEnd := end.Add(dur)
 
-			klines, err := market.GetKlinesRange(symbol, tf, fetchStart, fetchEnd)
+			klines, err := market.LoadKlinesRange(symbol, tf, fetchStart, fetchEnd)
 			if err != nil {
 				return fmt.Errorf("fetch klines for %s %s: %w", symbol, tf, err)
 			}
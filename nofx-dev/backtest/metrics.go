@@ -150,7 +150,8 @@ func fillTradeMetrics(metrics *Metrics, events []TradeEvent) {
 	totalLossAmount := 0.0
 
 	for _, evt := range events {
-		include := evt.LiquidationFlag || strings.HasPrefix(evt.Action, "close")
+		include := evt.LiquidationFlag || strings.HasPrefix(evt.Action, "close") ||
+			evt.Action == actionStopLoss || evt.Action == actionTakeProfit || evt.Action == actionManagedExit
 		if evt.RealizedPnL != 0 {
 			include = true
 		}
@@ -0,0 +1,154 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"nofx/decision"
+	"nofx/market"
+)
+
+// Trade event actions of simulated protective order fills
+const (
+	actionStopLoss    = "stop_loss"
+	actionTakeProfit  = "take_profit"
+	actionManagedExit = "managed_exit"
+)
+
+// protectiveFill returns the resting order a bar triggers ("" if none) and its fill price before slippage.
+// The bar's high/low are used when available, otherwise the mark price. A bar that gaps through the trigger
+// fills at its open; when both legs are inside one bar the stop loss is assumed to fill first (conservative)
+func protectiveFill(pos *position, bar *market.Kline, price float64) (string, float64) {
+	high, low, open := price, price, price
+	if bar != nil && bar.High > 0 && bar.Low > 0 {
+		high, low = bar.High, bar.Low
+		if bar.Open > 0 {
+			open = bar.Open
+		}
+	}
+
+	if pos.Side == "long" {
+		if pos.StopLoss > 0 && low <= pos.StopLoss {
+			return actionStopLoss, math.Min(pos.StopLoss, open)
+		}
+		if pos.TakeProfit > 0 && high >= pos.TakeProfit {
+			return actionTakeProfit, math.Max(pos.TakeProfit, open)
+		}
+		return "", 0
+	}
+
+	if pos.StopLoss > 0 && high >= pos.StopLoss {
+		return actionStopLoss, math.Max(pos.StopLoss, open)
+	}
+	if pos.TakeProfit > 0 && low <= pos.TakeProfit {
+		return actionTakeProfit, math.Min(pos.TakeProfit, open)
+	}
+	return "", 0
+}
+
+// checkProtectiveOrders fills resting stop loss / take profit orders the current bar crossed
+// Runs before the cycle's decisions so positions opened in this cycle are not checked against the bar they opened on
+func (r *Runner) checkProtectiveOrders(ts int64, priceMap map[string]float64, cycle int) ([]TradeEvent, []string, error) {
+	positions := append([]*position(nil), r.account.Positions()...)
+	events := make([]TradeEvent, 0)
+	var notes []string
+
+	for _, pos := range positions {
+		if pos.StopLoss <= 0 && pos.TakeProfit <= 0 {
+			continue
+		}
+		price := priceMap[pos.Symbol]
+		if price <= 0 {
+			continue
+		}
+		bar, _ := r.feed.decisionBarSnapshot(pos.Symbol, ts)
+		kind, fillPrice := protectiveFill(pos, bar, price)
+		if kind == "" {
+			// Managed exit trails with the bar after the fill check, the new stop applies from the next bar
+			if pos.Exit != nil {
+				high, low := price, price
+				if bar != nil && bar.High > 0 && bar.Low > 0 {
+					high, low = bar.High, bar.Low
+				}
+				pos.ratchetStop(pos.Exit.Advance(high, low, price))
+			}
+			continue
+		}
+		if kind == actionStopLoss && pos.Exit != nil && pos.StopLoss == pos.Exit.Stop {
+			kind = actionManagedExit
+		}
+		if kind == actionStopLoss {
+			r.stopCooldown.Record(pos.Symbol, time.UnixMilli(ts), r.cfg.stopLossCooldown())
+		}
+
+		qty := pos.Quantity
+		realized, fee, execPrice, err := r.account.Close(pos.Symbol, pos.Side, qty, fillPrice)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		slippage := fillPrice - execPrice
+		if pos.Side == "short" {
+			slippage = execPrice - fillPrice
+		}
+		note := fmt.Sprintf("%s triggered at %.4f, filled %.4f", kind, fillPrice, execPrice)
+		events = append(events, TradeEvent{
+			Timestamp:     ts,
+			Symbol:        pos.Symbol,
+			Action:        kind,
+			Side:          pos.Side,
+			Quantity:      qty,
+			Price:         execPrice,
+			Fee:           fee,
+			Slippage:      slippage,
+			OrderValue:    execPrice * qty,
+			RealizedPnL:   realized - fee,
+			Leverage:      pos.Leverage,
+			Cycle:         cycle,
+			PositionAfter: 0,
+			Note:          note,
+		})
+		notes = append(notes, fmt.Sprintf("🛑 %s %s %s", pos.Symbol, pos.Side, note))
+	}
+
+	return events, notes, nil
+}
+
+// handOffExit starts the managed exit of an open_* / manage_exit_* decision that carries one, using the
+// cycle's ATR of the requested timeframe. Returns an execution log note ("" if the decision has no managed exit)
+func (r *Runner) handOffExit(dec decision.Decision, marketData map[string]*market.Data, priceMap map[string]float64) string {
+	if dec.ManagedExit == nil {
+		return ""
+	}
+	var side string
+	switch dec.Action {
+	case "open_long", "manage_exit_long":
+		side = "long"
+	case "open_short", "manage_exit_short":
+		side = "short"
+	default:
+		return ""
+	}
+
+	timeframe := dec.ManagedExit.Timeframe
+	if timeframe == "" {
+		timeframe = r.cfg.DecisionTimeframe
+	}
+	trail, err := decision.NewExitTrail(side, *dec.ManagedExit, marketData[dec.Symbol].ATR14For(timeframe), priceMap[dec.Symbol])
+	if err == nil {
+		err = r.account.SetManagedExit(dec.Symbol, side, trail)
+	}
+	if err != nil {
+		return fmt.Sprintf("⚠️ %s managed exit not set: %v", dec.Symbol, err)
+	}
+	return fmt.Sprintf("🪢 %s %s managed exit: %s", dec.Symbol, side, trail)
+}
+
+// managedExitSummary describes a position's managed exit for the prompt ("" if none)
+func managedExitSummary(pos *position) string {
+	if pos.Exit == nil {
+		return ""
+	}
+	return pos.Exit.String()
+}
@@ -0,0 +1,77 @@
+package backtest
+
+import (
+	"testing"
+
+	"nofx/decision"
+	"nofx/market"
+)
+
+func TestProtectiveFill(t *testing.T) {
+	long := &position{Side: "long", StopLoss: 95, TakeProfit: 110}
+	short := &position{Side: "short", StopLoss: 105, TakeProfit: 90}
+
+	tests := []struct {
+		name     string
+		pos      *position
+		bar      *market.Kline
+		price    float64
+		wantKind string
+		wantFill float64
+	}{
+		{"long untouched", long, &market.Kline{Open: 100, High: 105, Low: 96, Close: 101}, 101, "", 0},
+		{"long stop", long, &market.Kline{Open: 100, High: 101, Low: 94, Close: 96}, 96, actionStopLoss, 95},
+		{"long stop gap", long, &market.Kline{Open: 90, High: 92, Low: 88, Close: 91}, 91, actionStopLoss, 90},
+		{"long take profit", long, &market.Kline{Open: 105, High: 112, Low: 104, Close: 108}, 108, actionTakeProfit, 110},
+		{"long both legs, stop first", long, &market.Kline{Open: 100, High: 111, Low: 94, Close: 100}, 100, actionStopLoss, 95},
+		{"short stop", short, &market.Kline{Open: 100, High: 106, Low: 99, Close: 104}, 104, actionStopLoss, 105},
+		{"short take profit gap", short, &market.Kline{Open: 88, High: 89, Low: 85, Close: 86}, 86, actionTakeProfit, 88},
+		{"no bar uses mark price", long, nil, 94, actionStopLoss, 94},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, fill := protectiveFill(tt.pos, tt.bar, tt.price)
+			if kind != tt.wantKind || fill != tt.wantFill {
+				t.Errorf("got %q @ %.2f, want %q @ %.2f", kind, fill, tt.wantKind, tt.wantFill)
+			}
+		})
+	}
+}
+
+func TestSetProtectionKeepsMissingLegs(t *testing.T) {
+	acc := NewBacktestAccount(1000, 0, 0)
+	if _, _, _, err := acc.Open("BTCUSDT", "long", 0.01, 5, 100, 0); err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	acc.SetProtection("BTCUSDT", "long", 95, 110)
+	acc.SetProtection("BTCUSDT", "long", 98, 0)
+
+	pos := acc.positions[positionKey("BTCUSDT", "long")]
+	if pos.StopLoss != 98 || pos.TakeProfit != 110 {
+		t.Errorf("expected stop 98 / take profit 110, got %.2f / %.2f", pos.StopLoss, pos.TakeProfit)
+	}
+}
+
+func TestManagedExitRatchetsStop(t *testing.T) {
+	acc := NewBacktestAccount(1000, 0, 0)
+	if _, _, _, err := acc.Open("BTCUSDT", "long", 0.01, 5, 100, 0); err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	acc.SetProtection("BTCUSDT", "long", 90, 130)
+	trail, _ := decision.NewExitTrail("long", decision.ManagedExit{Mode: decision.ExitModeChandelier, ATRMultiplier: 2}, 2, 100)
+	if err := acc.SetManagedExit("BTCUSDT", "long", trail); err != nil {
+		t.Fatalf("SetManagedExit: %v", err)
+	}
+
+	pos := acc.positions[positionKey("BTCUSDT", "long")]
+	if pos.StopLoss != 96 {
+		t.Fatalf("expected stop tightened to 96, got %.2f", pos.StopLoss)
+	}
+	pos.ratchetStop(trail.Advance(108, 101, 106))
+	if pos.StopLoss != 104 || pos.TakeProfit != 130 {
+		t.Errorf("expected stop 104 / take profit 130, got %.2f / %.2f", pos.StopLoss, pos.TakeProfit)
+	}
+	if err := acc.SetManagedExit("ETHUSDT", "long", trail); err == nil {
+		t.Error("expected error without a position")
+	}
+}
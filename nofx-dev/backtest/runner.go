@@ -62,6 +62,8 @@ type Runner struct {
 
 	lockInfo *RunLockInfo
 	lockStop chan struct{}
+
+	stopCooldown decision.StopCooldown // Symbols recently stopped out, blocked from re-entry
 }
 
 // NewRunner constructs a backtest runner.
@@ -294,6 +296,14 @@ func (r *Runner) stepOnce() error {
 
 	decisionAttempted := shouldDecide
 
+	// Resting stop loss / take profit orders fill on the bar that crossed them, before this cycle's decisions
+	protectiveEvents, protectiveNotes, err := r.checkProtectiveOrders(ts, priceMap, state.DecisionCycle)
+	if err != nil {
+		return err
+	}
+	tradeEvents = append(tradeEvents, protectiveEvents...)
+	execLog = append(execLog, protectiveNotes...)
+
 	if shouldDecide {
 		ctx, rec, err := r.buildDecisionContext(ts, marketData, multiTF, priceMap, callCount)
 		if err != nil {
@@ -357,6 +367,13 @@ func (r *Runner) stepOnce() error {
 			if len(prevLogs) > 0 {
 				execLog = append(execLog, prevLogs...)
 			}
+			if note := fullDecision.SchemaRepairNote(); note != "" {
+				execLog = append(execLog, note)
+			}
+			execLog = append(execLog, decision.ApplyATRStops(sorted, ctx.MarketDataMap, r.cfg.DecisionTimeframe, r.cfg.ATRStopMultiplier)...)
+			execLog = append(execLog, decision.RoundPricesToTick(sorted, ctx.MarketDataMap)...)
+			execLog = append(execLog, decision.ApplyVolatilityLeverage(sorted, ctx.MarketDataMap, r.cfg.DecisionTimeframe,
+				r.cfg.VolTargetATRPct, r.cfg.Leverage.BTCETHLeverage, r.cfg.Leverage.AltcoinLeverage)...)
 
 			for _, dec := range sorted {
 				actionRecord, trades, logEntry, execErr := r.executeDecision(dec, priceMap, ts, callCount)
@@ -368,6 +385,9 @@ func (r *Runner) stepOnce() error {
 				} else {
 					actionRecord.Success = true
 					execLog = append(execLog, fmt.Sprintf("✓ %s %s", dec.Symbol, dec.Action))
+					if note := r.handOffExit(dec, ctx.MarketDataMap, priceMap); note != "" {
+						execLog = append(execLog, note)
+					}
 				}
 				if len(trades) > 0 {
 					tradeEvents = append(tradeEvents, trades...)
@@ -534,6 +554,10 @@ func (r *Runner) buildDecisionContext(ts int64, marketData map[string]*market.Da
 func (r *Runner) fillDecisionRecord(record *store.DecisionRecord, full *decision.FullDecision) {
 	record.InputPrompt = full.UserPrompt
 	record.CoTTrace = full.CoTTrace
+	record.PromptHash = full.PromptHash
+	record.PromptTokens = full.Usage.PromptTokens
+	record.CompletionTokens = full.Usage.CompletionTokens
+	record.AICostUSD = full.Usage.CostUSD
 	if len(full.Decisions) > 0 {
 		if data, err := json.MarshalIndent(full.Decisions, "", "  "); err == nil {
 			record.DecisionJSON = string(data)
@@ -578,8 +602,21 @@ func (r *Runner) executeDecision(dec decision.Decision, priceMap map[string]floa
 	}
 	fillPrice := r.executionPrice(symbol, basePrice, ts)
 
+	if dec.Action == "open_long" || dec.Action == "open_short" {
+		if err := r.stopCooldown.Check(symbol, time.UnixMilli(ts)); err != nil {
+			return actionRecord, nil, "", err
+		}
+	}
+
 	switch dec.Action {
-	case "open_long":
+	case "open_long", "add_long":
+		if dec.Action == "add_long" {
+			// Scale-in keeps the position's leverage, the account averages the entry price
+			if r.remainingPosition(symbol, "long") <= 0 {
+				return actionRecord, nil, "", fmt.Errorf("no long position to add to")
+			}
+			usedLeverage = r.account.positionLeverage(symbol, "long")
+		}
 		qty := r.determineQuantity(dec, basePrice)
 		if qty <= 0 {
 			return actionRecord, nil, "", fmt.Errorf("invalid qty")
@@ -588,6 +625,7 @@ func (r *Runner) executeDecision(dec decision.Decision, priceMap map[string]floa
 		if err != nil {
 			return actionRecord, nil, "", err
 		}
+		r.account.SetProtection(symbol, "long", dec.StopLoss, dec.TakeProfit)
 		actionRecord.Quantity = qty
 		actionRecord.Price = execPrice
 		actionRecord.Leverage = pos.Leverage
@@ -608,7 +646,14 @@ func (r *Runner) executeDecision(dec decision.Decision, priceMap map[string]floa
 		}
 		return actionRecord, []TradeEvent{trade}, "", nil
 
-	case "open_short":
+	case "open_short", "add_short":
+		if dec.Action == "add_short" {
+			// Scale-in keeps the position's leverage, the account averages the entry price
+			if r.remainingPosition(symbol, "short") <= 0 {
+				return actionRecord, nil, "", fmt.Errorf("no short position to add to")
+			}
+			usedLeverage = r.account.positionLeverage(symbol, "short")
+		}
 		qty := r.determineQuantity(dec, basePrice)
 		if qty <= 0 {
 			return actionRecord, nil, "", fmt.Errorf("invalid qty")
@@ -617,6 +662,7 @@ func (r *Runner) executeDecision(dec decision.Decision, priceMap map[string]floa
 		if err != nil {
 			return actionRecord, nil, "", err
 		}
+		r.account.SetProtection(symbol, "short", dec.StopLoss, dec.TakeProfit)
 		actionRecord.Quantity = qty
 		actionRecord.Price = execPrice
 		actionRecord.Leverage = pos.Leverage
@@ -637,7 +683,7 @@ func (r *Runner) executeDecision(dec decision.Decision, priceMap map[string]floa
 		}
 		return actionRecord, []TradeEvent{trade}, "", nil
 
-	case "close_long":
+	case "close_long", "partial_close_long":
 		qty := r.determineCloseQuantity(symbol, "long", dec)
 		if qty <= 0 {
 			return actionRecord, nil, "", fmt.Errorf("invalid close qty")
@@ -647,6 +693,8 @@ func (r *Runner) executeDecision(dec decision.Decision, priceMap map[string]floa
 		if err != nil {
 			return actionRecord, nil, "", err
 		}
+		// Protection of the remainder after a partial close
+		r.account.SetProtection(symbol, "long", dec.StopLoss, dec.TakeProfit)
 		actionRecord.Quantity = qty
 		actionRecord.Price = execPrice
 		actionRecord.Leverage = posLev
@@ -667,7 +715,7 @@ func (r *Runner) executeDecision(dec decision.Decision, priceMap map[string]floa
 		}
 		return actionRecord, []TradeEvent{trade}, "", nil
 
-	case "close_short":
+	case "close_short", "partial_close_short":
 		qty := r.determineCloseQuantity(symbol, "short", dec)
 		if qty <= 0 {
 			return actionRecord, nil, "", fmt.Errorf("invalid close qty")
@@ -677,6 +725,8 @@ func (r *Runner) executeDecision(dec decision.Decision, priceMap map[string]floa
 		if err != nil {
 			return actionRecord, nil, "", err
 		}
+		// Protection of the remainder after a partial close
+		r.account.SetProtection(symbol, "short", dec.StopLoss, dec.TakeProfit)
 		actionRecord.Quantity = qty
 		actionRecord.Price = execPrice
 		actionRecord.Leverage = posLev
@@ -697,8 +747,19 @@ func (r *Runner) executeDecision(dec decision.Decision, priceMap map[string]floa
 		}
 		return actionRecord, []TradeEvent{trade}, "", nil
 
+	case "manage_exit_long", "manage_exit_short":
+		// The trail itself is started by the cycle, which has the market data for its ATR
+		side := strings.TrimPrefix(dec.Action, "manage_exit_")
+		if r.remainingPosition(symbol, side) <= 0 {
+			return actionRecord, nil, "", fmt.Errorf("no %s position to manage", side)
+		}
+		actionRecord.Price = basePrice
+		return actionRecord, nil, "", nil
+
 	case "hold", "wait":
 		return actionRecord, nil, fmt.Sprintf("hold position: %s", dec.Action), nil
+	case decision.ActionAlert:
+		return actionRecord, nil, fmt.Sprintf("alert (%s): %s", dec.AlertType, dec.Reasoning), nil
 	default:
 		return actionRecord, nil, "", fmt.Errorf("unsupported action %s", dec.Action)
 	}
@@ -710,6 +771,11 @@ func (r *Runner) determineQuantity(dec decision.Decision, price float64) float64
 	if equity <= 0 {
 		equity = r.account.InitialBalance()
 	}
+	if r.cfg.RiskPerTradePct > 0 && strings.HasPrefix(dec.Action, "open_") {
+		if qty, err := decision.RiskQuantity(price, dec.StopLoss, equity, r.cfg.RiskPerTradePct); err == nil {
+			return qty
+		}
+	}
 	sizeUSD := dec.PositionSizeUSD
 	if sizeUSD <= 0 {
 		sizeUSD = 0.05 * equity
@@ -724,6 +790,9 @@ func (r *Runner) determineQuantity(dec decision.Decision, price float64) float64
 func (r *Runner) determineCloseQuantity(symbol, side string, dec decision.Decision) float64 {
 	for _, pos := range r.account.Positions() {
 		if pos.Symbol == strings.ToUpper(symbol) && pos.Side == side {
+			if dec.ClosePct > 0 && dec.ClosePct < 100 {
+				return pos.Quantity * dec.ClosePct / 100
+			}
 			return pos.Quantity
 		}
 	}
@@ -792,6 +861,7 @@ func (r *Runner) convertPositions(priceMap map[string]float64) []decision.Positi
 			LiquidationPrice: pos.LiquidationPrice,
 			MarginUsed:       pos.Margin,
 			UpdateTime:       time.Now().UnixMilli(),
+			ManagedExit:      managedExitSummary(pos),
 		})
 	}
 	return list
@@ -855,6 +925,9 @@ func (r *Runner) updateState(ts int64, equity, unrealized, marginUsed float64, p
 			LiquidationPrice: pos.LiquidationPrice,
 			MarginUsed:       pos.Margin,
 			OpenTime:         pos.OpenTime,
+			StopLoss:         pos.StopLoss,
+			TakeProfit:       pos.TakeProfit,
+			ManagedExit:      pos.Exit,
 		}
 	}
 
@@ -1322,9 +1395,9 @@ func sortDecisionsByPriority(decisions []decision.Decision) []decision.Decision
 
 	priority := func(action string) int {
 		switch action {
-		case "close_long", "close_short":
+		case "close_long", "close_short", "partial_close_long", "partial_close_short":
 			return 1
-		case "open_long", "open_short":
+		case "open_long", "open_short", "add_long", "add_short":
 			return 2
 		case "hold", "wait":
 			return 3
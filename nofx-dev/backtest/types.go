@@ -1,6 +1,10 @@
 package backtest
 
-import "time"
+import (
+	"time"
+
+	"nofx/decision"
+)
 
 // RunState represents the current state of a backtest run.
 type RunState string
@@ -17,14 +21,17 @@ const (
 
 // PositionSnapshot represents core position data for backtest state and persistence.
 type PositionSnapshot struct {
-	Symbol           string  `json:"symbol"`
-	Side             string  `json:"side"`
-	Quantity         float64 `json:"quantity"`
-	AvgPrice         float64 `json:"avg_price"`
-	Leverage         int     `json:"leverage"`
-	LiquidationPrice float64 `json:"liquidation_price"`
-	MarginUsed       float64 `json:"margin_used"`
-	OpenTime         int64   `json:"open_time"`
+	Symbol           string              `json:"symbol"`
+	Side             string              `json:"side"`
+	Quantity         float64             `json:"quantity"`
+	AvgPrice         float64             `json:"avg_price"`
+	Leverage         int                 `json:"leverage"`
+	LiquidationPrice float64             `json:"liquidation_price"`
+	MarginUsed       float64             `json:"margin_used"`
+	OpenTime         int64               `json:"open_time"`
+	StopLoss         float64             `json:"stop_loss,omitempty"`
+	TakeProfit       float64             `json:"take_profit,omitempty"`
+	ManagedExit      *decision.ExitTrail `json:"managed_exit,omitempty"`
 }
 
 // BacktestState represents the real-time state during execution (in-memory state).
@@ -0,0 +1,357 @@
+package benchmark
+
+import (
+	"fmt"
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/mcp"
+	"nofx/store"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Benchmarker periodically sends a canned evaluation prompt to every configured AI model
+// and records latency, estimated cost and output validity, so providers can be compared
+// before switching a live trader
+type Benchmarker struct {
+	store       *store.Store
+	interval    time.Duration
+	callTimeout time.Duration
+	newClient   func(provider string) mcp.AIClient
+
+	mu      sync.RWMutex
+	results map[string][]Result // userID/modelID -> recent results (oldest first)
+	active  map[string]bool     // userID -> run in progress
+	stopCh  chan struct{}
+	running bool
+}
+
+// Result single benchmark call of one AI model
+type Result struct {
+	ModelID      string    `json:"model_id"`
+	ModelName    string    `json:"model_name"`
+	Provider     string    `json:"provider"`
+	Time         time.Time `json:"time"`
+	LatencyMs    int64     `json:"latency_ms"`
+	InputTokens  int       `json:"input_tokens"`  // Estimated
+	OutputTokens int       `json:"output_tokens"` // Estimated
+	CostUSD      float64   `json:"cost_usd"`      // Estimated from list prices
+	Responded    bool      `json:"responded"`     // AI API call returned a response
+	Valid        bool      `json:"valid"`         // Response parsed and passed decision validation
+	Decisions    int       `json:"decisions"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// ModelSummary aggregated benchmark stats of one AI model
+type ModelSummary struct {
+	ModelID      string    `json:"model_id"`
+	ModelName    string    `json:"model_name"`
+	Provider     string    `json:"provider"`
+	Runs         int       `json:"runs"`
+	SuccessRate  float64   `json:"success_rate"` // Calls that returned a response
+	ValidRate    float64   `json:"valid_rate"`   // Responses that passed decision validation
+	AvgLatencyMs int64     `json:"avg_latency_ms"`
+	P95LatencyMs int64     `json:"p95_latency_ms"`
+	AvgCostUSD   float64   `json:"avg_cost_usd"`
+	LastRun      time.Time `json:"last_run"`
+	LastError    string    `json:"last_error,omitempty"`
+	Recent       []Result  `json:"recent"`
+}
+
+const (
+	maxResultsPerModel = 50
+	maxRecentResults   = 10
+	defaultCallTimeout = 120 * time.Second
+)
+
+var (
+	defaultBenchmarker   *Benchmarker
+	defaultBenchmarkerMu sync.Mutex
+)
+
+// NewBenchmarker creates a benchmarker
+// interval: periodic run interval (<= 0 disables periodic runs, manual runs still work)
+func NewBenchmarker(st *store.Store, interval time.Duration) *Benchmarker {
+	return &Benchmarker{
+		store:       st,
+		interval:    interval,
+		callTimeout: defaultCallTimeout,
+		newClient:   mcp.NewProviderClient,
+		results:     make(map[string][]Result),
+		active:      make(map[string]bool),
+	}
+}
+
+// StartDefault creates and starts the process-wide benchmarker (idempotent)
+func StartDefault(st *store.Store, interval time.Duration) *Benchmarker {
+	defaultBenchmarkerMu.Lock()
+	defer defaultBenchmarkerMu.Unlock()
+	if defaultBenchmarker == nil {
+		defaultBenchmarker = NewBenchmarker(st, interval)
+		defaultBenchmarker.Start()
+	}
+	return defaultBenchmarker
+}
+
+// Default returns the process-wide benchmarker (nil if not started)
+func Default() *Benchmarker {
+	defaultBenchmarkerMu.Lock()
+	defer defaultBenchmarkerMu.Unlock()
+	return defaultBenchmarker
+}
+
+// Start starts periodic benchmarking (no-op if interval is not set)
+func (b *Benchmarker) Start() {
+	if b.interval <= 0 {
+		logger.Info("📏 Model benchmarker ready (periodic runs disabled, manual runs only)")
+		return
+	}
+
+	b.mu.Lock()
+	if b.running {
+		b.mu.Unlock()
+		return
+	}
+	b.running = true
+	b.stopCh = make(chan struct{})
+	stopCh := b.stopCh
+	b.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(b.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				b.runAllUsers()
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+	logger.Infof("📏 Model benchmarker started (interval: %v)", b.interval)
+}
+
+// Stop stops periodic benchmarking
+func (b *Benchmarker) Stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.running {
+		return
+	}
+	b.running = false
+	close(b.stopCh)
+}
+
+// runAllUsers benchmarks the enabled models of every user
+func (b *Benchmarker) runAllUsers() {
+	userIDs, err := b.store.User().GetAllIDs()
+	if err != nil {
+		logger.Warnf("⚠️ Model benchmark: failed to list users: %v", err)
+		return
+	}
+	// Include the default user (models created before authentication was enabled)
+	seen := map[string]bool{}
+	for _, userID := range append([]string{"default"}, userIDs...) {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		if err := b.RunUser(userID); err != nil {
+			logger.Warnf("⚠️ Model benchmark failed for user %s: %v", userID, err)
+		}
+	}
+}
+
+// RunUser benchmarks all enabled models of a user (blocks until all calls finish)
+func (b *Benchmarker) RunUser(userID string) error {
+	if !b.tryAcquire(userID) {
+		return fmt.Errorf("benchmark already running")
+	}
+	defer b.release(userID)
+
+	models, err := b.store.AIModel().List(userID)
+	if err != nil {
+		return fmt.Errorf("failed to list AI models: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, model := range models {
+		if !model.Enabled || (model.APIKey == "" && mcp.RequiresAPIKey(model.Provider, model.CustomAPIURL)) {
+			continue
+		}
+		wg.Add(1)
+		go func(m *store.AIModel) {
+			defer wg.Done()
+			result := b.runModel(m)
+			b.record(userID, result)
+		}(model)
+	}
+	wg.Wait()
+	return nil
+}
+
+// IsRunning whether a benchmark run is in progress for the user
+func (b *Benchmarker) IsRunning(userID string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.active[userID]
+}
+
+func (b *Benchmarker) tryAcquire(userID string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.active[userID] {
+		return false
+	}
+	b.active[userID] = true
+	return true
+}
+
+func (b *Benchmarker) release(userID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.active, userID)
+}
+
+// runModel sends the canned prompt to one model and evaluates the response
+func (b *Benchmarker) runModel(model *store.AIModel) Result {
+	systemPrompt, userPrompt, riskControl := cannedPrompts()
+
+	result := Result{
+		ModelID:     model.ID,
+		ModelName:   model.Name,
+		Provider:    model.Provider,
+		Time:        time.Now(),
+		InputTokens: mcp.EstimateTokens(systemPrompt) + mcp.EstimateTokens(userPrompt),
+	}
+
+	client := b.newClient(model.Provider)
+	client.SetAPIKey(model.APIKey, model.CustomAPIURL, model.CustomModelName)
+	client.SetTimeout(b.callTimeout)
+
+	start := time.Now()
+	response, err := client.CallWithMessages(systemPrompt, userPrompt)
+	result.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		result.CostUSD = mcp.EstimateCost(model.Provider, result.InputTokens, 0)
+		return result
+	}
+
+	result.Responded = true
+	result.OutputTokens = mcp.EstimateTokens(response)
+	result.CostUSD = mcp.EstimateCost(model.Provider, result.InputTokens, result.OutputTokens)
+
+	full, err := decision.ParseFullDecisionResponse(response, benchmarkEquity,
+		riskControl.BTCETHMaxLeverage, riskControl.AltcoinMaxLeverage)
+	if full != nil {
+		for _, d := range full.Decisions {
+			// Symbol "ALL" is a market-wide wait / hold, not a decision on a symbol
+			if d.Symbol != "ALL" {
+				result.Decisions++
+			}
+		}
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Valid = result.Decisions > 0
+	if !result.Valid {
+		result.Error = "no structured decisions in response"
+	}
+	return result
+}
+
+// record stores a result, keeping the most recent maxResultsPerModel per model
+func (b *Benchmarker) record(userID string, result Result) {
+	status := "✓"
+	if !result.Valid {
+		status = "✗"
+	}
+	logger.Infof("📏 Model benchmark %s %s (%s): %dms, ~$%.5f, decisions=%d %s",
+		status, result.ModelName, result.Provider, result.LatencyMs, result.CostUSD, result.Decisions, result.Error)
+
+	key := userID + "/" + result.ModelID
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	results := append(b.results[key], result)
+	if len(results) > maxResultsPerModel {
+		results = results[len(results)-maxResultsPerModel:]
+	}
+	b.results[key] = results
+}
+
+// Summaries returns aggregated benchmark stats for a user's models
+func (b *Benchmarker) Summaries(userID string) []ModelSummary {
+	prefix := userID + "/"
+
+	b.mu.RLock()
+	summaries := make([]ModelSummary, 0)
+	for key, results := range b.results {
+		if !strings.HasPrefix(key, prefix) || len(results) == 0 {
+			continue
+		}
+		summaries = append(summaries, summarize(results))
+	}
+	b.mu.RUnlock()
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].ModelID < summaries[j].ModelID
+	})
+	return summaries
+}
+
+// summarize aggregates results of one model (results are ordered oldest first)
+func summarize(results []Result) ModelSummary {
+	last := results[len(results)-1]
+	summary := ModelSummary{
+		ModelID:   last.ModelID,
+		ModelName: last.ModelName,
+		Provider:  last.Provider,
+		Runs:      len(results),
+		LastRun:   last.Time,
+		LastError: last.Error,
+	}
+
+	var succeeded, valid int
+	var totalCost float64
+	latencies := make([]int64, 0, len(results))
+	for _, r := range results {
+		if r.Responded {
+			succeeded++
+			latencies = append(latencies, r.LatencyMs)
+		}
+		if r.Valid {
+			valid++
+		}
+		totalCost += r.CostUSD
+	}
+
+	n := float64(len(results))
+	summary.SuccessRate = float64(succeeded) / n
+	summary.ValidRate = float64(valid) / n
+	summary.AvgCostUSD = totalCost / n
+
+	// Latency stats only over calls that returned a response (timeouts would skew them)
+	if len(latencies) > 0 {
+		var total int64
+		for _, l := range latencies {
+			total += l
+		}
+		summary.AvgLatencyMs = total / int64(len(latencies))
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		idx := (len(latencies)*95+99)/100 - 1
+		summary.P95LatencyMs = latencies[idx]
+	}
+
+	recentStart := len(results) - maxRecentResults
+	if recentStart < 0 {
+		recentStart = 0
+	}
+	summary.Recent = append([]Result(nil), results[recentStart:]...)
+	return summary
+}
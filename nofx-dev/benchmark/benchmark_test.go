@@ -0,0 +1,101 @@
+package benchmark
+
+import (
+	"errors"
+	"nofx/mcp"
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+// fakeClient returns a fixed response
+type fakeClient struct {
+	response string
+	err      error
+}
+
+func (f *fakeClient) SetAPIKey(apiKey, customURL, customModel string) {}
+func (f *fakeClient) SetTimeout(timeout time.Duration)                {}
+func (f *fakeClient) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	return f.response, f.err
+}
+func (f *fakeClient) CallWithRequest(req *mcp.Request) (string, error) { return f.response, f.err }
+
+func newTestBenchmarker(client mcp.AIClient) *Benchmarker {
+	b := NewBenchmarker(nil, 0)
+	b.newClient = func(string) mcp.AIClient { return client }
+	return b
+}
+
+// TestRunModel tests validity classification of model responses
+func TestRunModel(t *testing.T) {
+	model := &store.AIModel{ID: "m1", Name: "DeepSeek", Provider: "deepseek", Enabled: true, APIKey: "k"}
+
+	tests := []struct {
+		name          string
+		client        *fakeClient
+		wantResponded bool
+		wantValid     bool
+	}{
+		{"valid decisions", &fakeClient{response: `Trend is up.
+[{"symbol":"BTCUSDT","action":"open_long","leverage":5,"position_size_usd":200,"stop_loss":95000,"take_profit":100000,"confidence":80,"reasoning":"breakout"},
+ {"symbol":"SOLUSDT","action":"wait","reasoning":"oversold"}]`}, true, true},
+		{"unparseable output", &fakeClient{response: "I cannot decide right now."}, true, false},
+		{"api error", &fakeClient{err: errors.New("request timeout")}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newTestBenchmarker(tt.client).runModel(model)
+			if result.Responded != tt.wantResponded {
+				t.Errorf("responded: expected %v, got %v", tt.wantResponded, result.Responded)
+			}
+			if result.Valid != tt.wantValid {
+				t.Errorf("valid: expected %v, got %v (error: %s)", tt.wantValid, result.Valid, result.Error)
+			}
+			if result.InputTokens == 0 {
+				t.Error("expected input tokens to be estimated")
+			}
+			if tt.wantResponded && result.CostUSD <= 0 {
+				t.Error("expected a positive cost estimate for deepseek")
+			}
+		})
+	}
+}
+
+// TestSummarize tests aggregation of benchmark results
+func TestSummarize(t *testing.T) {
+	now := time.Now()
+	var results []Result
+	for i := 1; i <= 20; i++ {
+		results = append(results, Result{
+			ModelID: "m1", Provider: "qwen", Time: now.Add(time.Duration(i) * time.Minute),
+			LatencyMs: int64(i * 100), Responded: true, Valid: i%2 == 0, CostUSD: 0.01,
+		})
+	}
+	results = append(results, Result{ModelID: "m1", Provider: "qwen", Time: now.Add(time.Hour), LatencyMs: 120000, Error: "timeout"})
+
+	s := summarize(results)
+	if s.Runs != 21 {
+		t.Errorf("runs: expected 21, got %d", s.Runs)
+	}
+	if got, want := s.SuccessRate, 20.0/21; got != want {
+		t.Errorf("success rate: expected %.4f, got %.4f", want, got)
+	}
+	if got, want := s.ValidRate, 10.0/21; got != want {
+		t.Errorf("valid rate: expected %.4f, got %.4f", want, got)
+	}
+	// Timed-out call is excluded from latency stats
+	if s.AvgLatencyMs != 1050 {
+		t.Errorf("avg latency: expected 1050, got %d", s.AvgLatencyMs)
+	}
+	if s.P95LatencyMs != 1900 {
+		t.Errorf("p95 latency: expected 1900, got %d", s.P95LatencyMs)
+	}
+	if s.LastError != "timeout" {
+		t.Errorf("last error: expected timeout, got %q", s.LastError)
+	}
+	if len(s.Recent) != maxRecentResults {
+		t.Errorf("recent: expected %d, got %d", maxRecentResults, len(s.Recent))
+	}
+}
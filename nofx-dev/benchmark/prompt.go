@@ -0,0 +1,49 @@
+package benchmark
+
+import (
+	"nofx/decision"
+	"nofx/store"
+)
+
+// benchmarkEquity account equity used by the canned prompt (USDT)
+const benchmarkEquity = 1000.0
+
+// cannedUserPrompt fixed market snapshot, identical for every model so results are comparable
+const cannedUserPrompt = `# Benchmark snapshot (fixed data, not live)
+
+Time: 2025-01-15 14:00 UTC | Cycle #42 | Runtime: 126 min
+
+## Account
+Total equity: 1000.00 USDT | Available: 820.00 USDT | Unrealized PnL: +12.40 USDT | Margin usage: 18.0% | Positions: 1
+
+## Current Positions
+1. ETHUSDT LONG | Entry 3120.50 | Mark 3146.80 | Qty 0.20 | Leverage 5x | uPnL +5.26 (+0.84%) | Liq 2540.00 | Held 95 min
+
+## Candidate Coins
+
+### BTCUSDT
+Price 96850.0 | 1h +0.62% | 4h +1.85% | Funding 0.0100% | OI +2.1% (4h)
+3m close (oldest→latest): 96420, 96510, 96480, 96600, 96720, 96690, 96780, 96850
+3m EMA20 96610 | MACD 42.5 | RSI7 64.2 | RSI14 58.9
+4h EMA20 95120 | EMA50 93880 | ATR14 1420 | Volume 18240 BTC
+
+### ETHUSDT
+Price 3146.80 | 1h +0.35% | 4h +0.92% | Funding 0.0085% | OI +0.8% (4h)
+3m close (oldest→latest): 3131.2, 3135.0, 3138.4, 3136.9, 3141.5, 3144.0, 3143.2, 3146.8
+3m EMA20 3139.1 | MACD 2.1 | RSI7 61.0 | RSI14 57.3
+4h EMA20 3098.4 | EMA50 3052.7 | ATR14 58.2
+
+### SOLUSDT
+Price 187.42 | 1h -1.24% | 4h -3.10% | Funding -0.0120% | OI +6.4% (4h)
+3m close (oldest→latest): 190.10, 189.55, 189.02, 188.70, 188.15, 187.90, 187.61, 187.42
+3m EMA20 188.90 | MACD -0.42 | RSI7 28.4 | RSI14 34.6
+4h EMA20 192.30 | EMA50 194.85 | ATR14 4.85
+
+Decide for each coin: open_long, open_short, close_long, close_short, hold or wait.`
+
+// cannedPrompts builds the benchmark system/user prompts using the default strategy
+func cannedPrompts() (systemPrompt, userPrompt string, riskControl store.RiskControlConfig) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	engine := decision.NewStrategyEngine(&cfg)
+	return engine.BuildSystemPrompt(benchmarkEquity, ""), cannedUserPrompt, engine.GetRiskControlConfig()
+}
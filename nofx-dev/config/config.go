@@ -4,6 +4,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Global configuration instance
@@ -17,19 +18,126 @@ type Config struct {
 	JWTSecret           string
 	RegistrationEnabled bool
 	MaxUsers            int // Maximum number of users allowed (0 = unlimited, default = 1)
+	// LeaderboardEnabled exposes the public risk-adjusted leaderboard of opted-in traders (multi-user competitions)
+	LeaderboardEnabled bool
 
 	// Security configuration
 	// TransportEncryption enables browser-side encryption for API keys
 	// Requires HTTPS or localhost. Set to false for HTTP access via IP.
 	TransportEncryption bool
+
+	// Diagnostics configuration
+	// DebugEndpoints exposes /api/debug/* (goroutine breakdown, cache sizes, pprof)
+	DebugEndpoints bool
+
+	// ModelBenchmarkInterval periodic AI model benchmarking interval (0 = disabled, manual runs only)
+	ModelBenchmarkInterval time.Duration
+
+	// HistoryBackfillDays days of exchange trade history imported on a trader's first run (0 = disabled)
+	HistoryBackfillDays int
+
+	// ReduceOnlyAutoCorrect corrects close quantities above the live position to its size (false = reject the close)
+	ReduceOnlyAutoCorrect bool
+
+	// MaxConditionalOrders account-wide budget of open Binance stop / take-profit orders (0 = only the exchange's
+	// per-symbol MAX_NUM_ALGO_ORDERS cap applies)
+	MaxConditionalOrders int
+
+	// ShutdownTimeout how long traders get to settle and flush on SIGTERM before the database is closed anyway
+	ShutdownTimeout time.Duration
+
+	// KlineRetentionDays days of closed klines persisted for backtests and analytics (0 = persistence disabled)
+	KlineRetentionDays int
+
+	// EventsFile JSON file of extra calendar events (token unlocks, custom events) merged with the economic calendar
+	EventsFile string
+
+	// OutcomeExportDir directory file:// decision outcome export sinks must be inside (empty = only http(s) sinks)
+	OutcomeExportDir string
+
+	// LimitEntry opens positions with post-only limit orders chased toward mark price instead of market orders
+	LimitEntry LimitEntryConfig
+
+	// ExposureAlert account exposure thresholds that raise trader events
+	ExposureAlert ExposureAlertConfig
+
+	// DriftCheck periodic comparison of exchange-side leverage / margin mode with the trader's settings
+	DriftCheck DriftCheckConfig
+
+	// FaultInjection simulates exchange failures (testnet only) to verify risk settings and recovery
+	FaultInjection FaultInjectionConfig
+
+	// EndpointSelection latency-based selection among equivalent exchange / LLM endpoints
+	EndpointSelection EndpointSelectionConfig
+}
+
+// LimitEntryConfig limit order entry chase policy (Binance futures)
+type LimitEntryConfig struct {
+	Enabled          bool
+	MaxReprices      int           // Number of times the limit price is moved toward mark price
+	RepriceInterval  time.Duration // Time each limit order rests before being repriced
+	OffsetBps        float64       // Initial distance from mark price in basis points (shrinks to 0 over the reprices)
+	FallbackToMarket bool          // Fill the remaining quantity with a market order after the last reprice
+}
+
+// ExposureAlertConfig account-level exposure alert thresholds (0 disables a check)
+type ExposureAlertConfig struct {
+	MaxLeverage  float64 // Gross notional / equity above which an alert is raised
+	MaxMarginPct float64 // Margin utilization (% of equity) above which an alert is raised
+}
+
+// DriftCheckConfig leverage / margin mode drift detection
+type DriftCheckConfig struct {
+	Interval    time.Duration // Time between checks (0 = disabled)
+	AutoCorrect bool          // Set drifted leverage back to the trader's value (margin mode can only be alerted on)
+}
+
+// FaultInjectionConfig chaos testing configuration for exchange calls
+// Rates are probabilities in [0, 1] applied per call
+type FaultInjectionConfig struct {
+	Enabled          bool
+	TimeoutRate      float64       // Probability of a simulated request timeout
+	RejectRate       float64       // Probability of a simulated order rejection
+	PartialFillRate  float64       // Probability of a simulated partial fill
+	PartialFillRatio float64       // Fraction of requested quantity filled on partial fill (default 0.5)
+	WSDropRate       float64       // Probability (per position query) of dropping the market WebSocket
+	Latency          time.Duration // Extra latency added to every exchange call
+}
+
+// EndpointSelectionConfig endpoint latency probing and failover
+type EndpointSelectionConfig struct {
+	Enabled       bool
+	ProbeInterval time.Duration       // Time between latency probes of all endpoints
+	Pools         map[string][]string // Extra pools (service name -> equivalent base URLs, first is the default)
 }
 
 // Init initializes global configuration (from .env)
 func Init() {
 	cfg := &Config{
-		APIServerPort:       8080,
-		RegistrationEnabled: true,
-		MaxUsers:            1, // Default: only 1 user allowed
+		APIServerPort:         8080,
+		RegistrationEnabled:   true,
+		MaxUsers:              1, // Default: only 1 user allowed
+		HistoryBackfillDays:   30,
+		KlineRetentionDays:    30,
+		ShutdownTimeout:       25 * time.Second, // Inside docker-compose's 30s stop_grace_period
+		ReduceOnlyAutoCorrect: true,
+		LimitEntry: LimitEntryConfig{
+			MaxReprices:      3,
+			RepriceInterval:  5 * time.Second,
+			OffsetBps:        2,
+			FallbackToMarket: true,
+		},
+		ExposureAlert: ExposureAlertConfig{
+			MaxLeverage:  5,
+			MaxMarginPct: 80,
+		},
+		DriftCheck: DriftCheckConfig{
+			Interval:    5 * time.Minute,
+			AutoCorrect: true,
+		},
+		EndpointSelection: EndpointSelectionConfig{
+			ProbeInterval: 30 * time.Second,
+		},
 	}
 
 	// Load from environment variables
@@ -44,6 +152,10 @@ func Init() {
 		cfg.RegistrationEnabled = strings.ToLower(v) == "true"
 	}
 
+	if v := os.Getenv("LEADERBOARD_ENABLED"); v != "" {
+		cfg.LeaderboardEnabled = strings.ToLower(v) == "true"
+	}
+
 	if v := os.Getenv("MAX_USERS"); v != "" {
 		if maxUsers, err := strconv.Atoi(v); err == nil && maxUsers >= 0 {
 			cfg.MaxUsers = maxUsers
@@ -62,9 +174,179 @@ func Init() {
 		cfg.TransportEncryption = strings.ToLower(v) == "true"
 	}
 
+	// Debug endpoints: default false, set DEBUG_ENDPOINTS=true to expose diagnostics and pprof
+	if v := os.Getenv("DEBUG_ENDPOINTS"); v != "" {
+		cfg.DebugEndpoints = strings.ToLower(v) == "true"
+	}
+
+	// Model benchmark: MODEL_BENCHMARK_INTERVAL_MINUTES=60 sends the canned prompt to all enabled models hourly
+	if v := os.Getenv("MODEL_BENCHMARK_INTERVAL_MINUTES"); v != "" {
+		if minutes, err := strconv.Atoi(v); err == nil && minutes > 0 {
+			cfg.ModelBenchmarkInterval = time.Duration(minutes) * time.Minute
+		}
+	}
+
+	// History backfill: HISTORY_BACKFILL_DAYS=0 disables importing pre-existing trade history
+	if v := os.Getenv("HISTORY_BACKFILL_DAYS"); v != "" {
+		if days, err := strconv.Atoi(v); err == nil && days >= 0 {
+			cfg.HistoryBackfillDays = days
+		}
+	}
+
+	// Kline history: KLINE_RETENTION_DAYS=0 disables persisting streamed klines
+	if v := os.Getenv("KLINE_RETENTION_DAYS"); v != "" {
+		if days, err := strconv.Atoi(v); err == nil && days >= 0 {
+			cfg.KlineRetentionDays = days
+		}
+	}
+
+	// Reduce-only: REDUCE_ONLY_AUTO_CORRECT=false rejects closes larger than the live position instead of shrinking them
+	if v := os.Getenv("REDUCE_ONLY_AUTO_CORRECT"); v != "" {
+		cfg.ReduceOnlyAutoCorrect = strings.ToLower(v) == "true"
+	}
+
+	// Conditional orders: MAX_CONDITIONAL_ORDERS=100 throttles new stops once that many are open across all symbols
+	if v := os.Getenv("MAX_CONDITIONAL_ORDERS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.MaxConditionalOrders = n
+		}
+	}
+
+	// Shutdown deadline: SHUTDOWN_TIMEOUT_SECONDS=25, keep it below the container's stop grace period
+	if v := os.Getenv("SHUTDOWN_TIMEOUT_SECONDS"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+			cfg.ShutdownTimeout = time.Duration(seconds) * time.Second
+		}
+	}
+
+	// Event calendar: EVENTS_FILE=/data/events.json adds token unlocks and custom events to the macro calendar
+	if v := os.Getenv("EVENTS_FILE"); v != "" {
+		cfg.EventsFile = strings.TrimSpace(v)
+	}
+
+	// Outcome export: OUTCOME_EXPORT_DIR=/data/exports allows traders to append their JSONL training examples to files there
+	if v := os.Getenv("OUTCOME_EXPORT_DIR"); v != "" {
+		cfg.OutcomeExportDir = strings.TrimSpace(v)
+	}
+
+	// Limit entry: LIMIT_ENTRY_ENABLED=true opens positions as maker orders to reduce taker fees
+	if v := os.Getenv("LIMIT_ENTRY_ENABLED"); v != "" {
+		cfg.LimitEntry.Enabled = strings.ToLower(v) == "true"
+	}
+	if v := os.Getenv("LIMIT_ENTRY_MAX_REPRICES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.LimitEntry.MaxReprices = n
+		}
+	}
+	if v := os.Getenv("LIMIT_ENTRY_REPRICE_SECONDS"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+			cfg.LimitEntry.RepriceInterval = time.Duration(seconds) * time.Second
+		}
+	}
+	if v := os.Getenv("LIMIT_ENTRY_OFFSET_BPS"); v != "" {
+		if bps, err := strconv.ParseFloat(v, 64); err == nil && bps >= 0 {
+			cfg.LimitEntry.OffsetBps = bps
+		}
+	}
+	if v := os.Getenv("LIMIT_ENTRY_MARKET_FALLBACK"); v != "" {
+		cfg.LimitEntry.FallbackToMarket = strings.ToLower(v) == "true"
+	}
+
+	// Exposure alerts: EXPOSURE_ALERT_MAX_LEVERAGE=0 / EXPOSURE_ALERT_MAX_MARGIN_PCT=0 disable a check
+	if v := os.Getenv("EXPOSURE_ALERT_MAX_LEVERAGE"); v != "" {
+		if leverage, err := strconv.ParseFloat(v, 64); err == nil && leverage >= 0 {
+			cfg.ExposureAlert.MaxLeverage = leverage
+		}
+	}
+	if v := os.Getenv("EXPOSURE_ALERT_MAX_MARGIN_PCT"); v != "" {
+		if pct, err := strconv.ParseFloat(v, 64); err == nil && pct >= 0 {
+			cfg.ExposureAlert.MaxMarginPct = pct
+		}
+	}
+
+	// Drift check: DRIFT_CHECK_INTERVAL_MINUTES=0 disables, DRIFT_AUTO_CORRECT=false only alerts
+	if v := os.Getenv("DRIFT_CHECK_INTERVAL_MINUTES"); v != "" {
+		if minutes, err := strconv.Atoi(v); err == nil && minutes >= 0 {
+			cfg.DriftCheck.Interval = time.Duration(minutes) * time.Minute
+		}
+	}
+	if v := os.Getenv("DRIFT_AUTO_CORRECT"); v != "" {
+		cfg.DriftCheck.AutoCorrect = strings.ToLower(v) == "true"
+	}
+
+	// Fault injection (chaos testing): only applies to testnet traders
+	if v := os.Getenv("FAULT_INJECTION_ENABLED"); v != "" {
+		cfg.FaultInjection.Enabled = strings.ToLower(v) == "true"
+	}
+	cfg.FaultInjection.TimeoutRate = parseRate("FAULT_TIMEOUT_RATE")
+	cfg.FaultInjection.RejectRate = parseRate("FAULT_REJECT_RATE")
+	cfg.FaultInjection.PartialFillRate = parseRate("FAULT_PARTIAL_FILL_RATE")
+	cfg.FaultInjection.PartialFillRatio = parseRate("FAULT_PARTIAL_FILL_RATIO")
+	if cfg.FaultInjection.PartialFillRatio <= 0 {
+		cfg.FaultInjection.PartialFillRatio = 0.5
+	}
+	cfg.FaultInjection.WSDropRate = parseRate("FAULT_WS_DROP_RATE")
+	if v := os.Getenv("FAULT_LATENCY_MS"); v != "" {
+		if ms, err := strconv.Atoi(v); err == nil && ms > 0 {
+			cfg.FaultInjection.Latency = time.Duration(ms) * time.Millisecond
+		}
+	}
+
+	// Endpoint selection: ENDPOINT_SELECTION_ENABLED=true probes exchange mirrors and prefers the fastest healthy one
+	if v := os.Getenv("ENDPOINT_SELECTION_ENABLED"); v != "" {
+		cfg.EndpointSelection.Enabled = strings.ToLower(v) == "true"
+	}
+	if v := os.Getenv("ENDPOINT_PROBE_INTERVAL_SECONDS"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+			cfg.EndpointSelection.ProbeInterval = time.Duration(seconds) * time.Second
+		}
+	}
+	if v := os.Getenv("ENDPOINT_POOLS"); v != "" {
+		cfg.EndpointSelection.Pools = parseEndpointPools(v)
+	}
+
+	// Feature flags: FEATURE_FLAGS="ensemble_mode=true,event_triggers" enables experimental features for all traders
+	// (runtime / per-trader overrides are set through the API)
+	initFeatureFlags(cfg, os.Getenv("FEATURE_FLAGS"))
+
 	global = cfg
 }
 
+// parseEndpointPools parses "name=url1,url2;name2=url3,url4" (pools with fewer than two URLs are ignored)
+func parseEndpointPools(v string) map[string][]string {
+	result := make(map[string][]string)
+	for _, entry := range strings.Split(v, ";") {
+		name, list, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			continue
+		}
+		var urls []string
+		for _, u := range strings.Split(list, ",") {
+			if u = strings.TrimSpace(u); u != "" {
+				urls = append(urls, u)
+			}
+		}
+		if len(urls) >= 2 {
+			result[name] = urls
+		}
+	}
+	return result
+}
+
+// parseRate parses a probability in [0, 1] from environment variable (invalid values are ignored)
+func parseRate(key string) float64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0
+	}
+	rate, err := strconv.ParseFloat(v, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return 0
+	}
+	return rate
+}
+
 // Get returns the global configuration
 func Get() *Config {
 	if global == nil {
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Feature flags of risky / experimental features, resolved per trader override → deployment override → default
+const (
+	FlagEnsembleMode        = "ensemble_mode"
+	FlagEventTriggers       = "event_triggers"
+	FlagMakerFirstExecution = "maker_first_execution"
+	FlagReflectionPass      = "reflection_pass"
+)
+
+// FeatureFlag a feature that can be rolled out gradually, and what the system does while it is off
+type FeatureFlag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Fallback    string `json:"fallback"` // Behavior while the flag is off (graceful degradation)
+}
+
+// featureFlagRegistry known flags, unknown names are rejected
+var featureFlagRegistry = []FeatureFlag{
+	{
+		Name:        FlagEnsembleMode,
+		Description: "Ask several AI models per cycle and trade on their combined decision",
+		Fallback:    "The trader's single AI model decides",
+	},
+	{
+		Name:        FlagEventTriggers,
+		Description: "Run extra decision cycles on market events (price spikes, liquidation bursts) between scheduled cycles",
+		Fallback:    "Decision cycles only run on the scan interval",
+	},
+	{
+		Name:        FlagMakerFirstExecution,
+		Description: "Open positions with post-only limit orders chased toward mark price (LIMIT_ENTRY_* policy)",
+		Fallback:    "Positions are opened with market orders",
+	},
+	{
+		Name:        FlagReflectionPass,
+		Description: "Have the AI model review the proposed entries against the risk rules and recent losing trades, vetoing or downsizing them before execution",
+		Fallback:    "The proposed decisions are executed without a second review",
+	},
+}
+
+// featureFlagState deployment defaults (environment) and runtime overrides (persisted by the caller)
+var featureFlagState struct {
+	mu        sync.RWMutex
+	defaults  map[string]bool
+	overrides map[string]bool
+}
+
+// FeatureFlags returns the known feature flags, sorted by name
+func FeatureFlags() []FeatureFlag {
+	flags := append([]FeatureFlag(nil), featureFlagRegistry...)
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
+
+// IsFeatureFlag reports whether name is a known feature flag
+func IsFeatureFlag(name string) bool {
+	for _, f := range featureFlagRegistry {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateFeatureFlags rejects unknown flag names
+func ValidateFeatureFlags(flags map[string]bool) error {
+	for name := range flags {
+		if !IsFeatureFlag(name) {
+			return fmt.Errorf("unknown feature flag %q", name)
+		}
+	}
+	return nil
+}
+
+// FeatureEnabled reports whether the flag is on for the whole deployment (runtime override, else environment default)
+func FeatureEnabled(name string) bool {
+	Get() // Make sure the environment defaults are loaded
+	featureFlagState.mu.RLock()
+	defer featureFlagState.mu.RUnlock()
+	if enabled, ok := featureFlagState.overrides[name]; ok {
+		return enabled
+	}
+	return featureFlagState.defaults[name]
+}
+
+// FeatureFlagDefault reports the environment default of the flag
+func FeatureFlagDefault(name string) bool {
+	Get()
+	featureFlagState.mu.RLock()
+	defer featureFlagState.mu.RUnlock()
+	return featureFlagState.defaults[name]
+}
+
+// FeatureFlagOverrides returns a copy of the deployment's runtime overrides
+func FeatureFlagOverrides() map[string]bool {
+	featureFlagState.mu.RLock()
+	defer featureFlagState.mu.RUnlock()
+	overrides := make(map[string]bool, len(featureFlagState.overrides))
+	for name, enabled := range featureFlagState.overrides {
+		overrides[name] = enabled
+	}
+	return overrides
+}
+
+// SetFeatureFlagOverrides replaces the deployment's runtime overrides (flags left out follow the environment default)
+func SetFeatureFlagOverrides(overrides map[string]bool) error {
+	if err := ValidateFeatureFlags(overrides); err != nil {
+		return err
+	}
+	copied := make(map[string]bool, len(overrides))
+	for name, enabled := range overrides {
+		copied[name] = enabled
+	}
+	featureFlagState.mu.Lock()
+	featureFlagState.overrides = copied
+	featureFlagState.mu.Unlock()
+	return nil
+}
+
+// initFeatureFlags loads the flag defaults: maker-first execution follows LIMIT_ENTRY_ENABLED,
+// FEATURE_FLAGS="ensemble_mode=true,event_triggers" then sets any flag (a bare name means true)
+func initFeatureFlags(cfg *Config, v string) {
+	defaults := map[string]bool{
+		FlagMakerFirstExecution: cfg.LimitEntry.Enabled,
+	}
+	for name, enabled := range parseFeatureFlags(v) {
+		defaults[name] = enabled
+	}
+	featureFlagState.mu.Lock()
+	featureFlagState.defaults = defaults
+	featureFlagState.mu.Unlock()
+}
+
+// parseFeatureFlags parses "name=true,name2=false,name3" (unknown names and invalid values are ignored)
+func parseFeatureFlags(v string) map[string]bool {
+	flags := make(map[string]bool)
+	for _, entry := range strings.Split(v, ",") {
+		name, value, hasValue := strings.Cut(entry, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !IsFeatureFlag(name) {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "true", "1", "on":
+			flags[name] = true
+		case "false", "0", "off":
+			flags[name] = false
+		case "":
+			if !hasValue {
+				flags[name] = true
+			}
+		}
+	}
+	return flags
+}
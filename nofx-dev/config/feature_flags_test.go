@@ -0,0 +1,37 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseFeatureFlags tests FEATURE_FLAGS parsing
+func TestParseFeatureFlags(t *testing.T) {
+	got := parseFeatureFlags(" ensemble_mode , event_triggers=false,maker_first_execution=on,unknown=true,ensemble_mode=maybe")
+	want := map[string]bool{FlagEnsembleMode: true, FlagEventTriggers: false, FlagMakerFirstExecution: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseFeatureFlags = %v, want %v", got, want)
+	}
+}
+
+// TestFeatureFlagOverrides tests that runtime overrides win over environment defaults
+func TestFeatureFlagOverrides(t *testing.T) {
+	t.Setenv("LIMIT_ENTRY_ENABLED", "true")
+	t.Setenv("FEATURE_FLAGS", "event_triggers")
+	Init()
+	defer SetFeatureFlagOverrides(nil)
+
+	if !FeatureEnabled(FlagMakerFirstExecution) || !FeatureEnabled(FlagEventTriggers) || FeatureEnabled(FlagEnsembleMode) {
+		t.Fatal("expected maker-first from LIMIT_ENTRY_ENABLED and event triggers from FEATURE_FLAGS")
+	}
+
+	if err := SetFeatureFlagOverrides(map[string]bool{FlagMakerFirstExecution: false}); err != nil {
+		t.Fatal(err)
+	}
+	if FeatureEnabled(FlagMakerFirstExecution) || !FeatureFlagDefault(FlagMakerFirstExecution) {
+		t.Error("override should switch maker-first off without changing its default")
+	}
+	if err := SetFeatureFlagOverrides(map[string]bool{"turbo_mode": true}); err == nil {
+		t.Error("unknown flags should be rejected")
+	}
+}
@@ -0,0 +1,41 @@
+package decision
+
+import (
+	"fmt"
+	"nofx/market"
+)
+
+// ApplyATRStops replaces the stop loss of open decisions with entry ∓ multiplier × ATR14 of the timeframe
+// (atr_stop_multiplier), returns an execution log note per changed stop
+// Symbols without market data or ATR keep the AI's stop
+func ApplyATRStops(decisions []Decision, marketData map[string]*market.Data, timeframe string, multiplier float64) []string {
+	if multiplier <= 0 {
+		return nil
+	}
+	var notes []string
+	for i := range decisions {
+		d := &decisions[i]
+		var side string
+		switch d.Action {
+		case "open_long":
+			side = "long"
+		case "open_short":
+			side = "short"
+		default:
+			continue
+		}
+		data, ok := marketData[d.Symbol]
+		if !ok || data == nil {
+			continue
+		}
+		atr := data.ATR14For(timeframe)
+		stop := market.RoundToTick(market.ATRStopLoss(side, data.CurrentPrice, atr, multiplier), data.TickSize)
+		if stop <= 0 || stop == d.StopLoss {
+			continue
+		}
+		notes = append(notes, fmt.Sprintf("📏 %s stop loss %.6g → %.6g (%.2f × ATR14 %.6g on %s)",
+			d.Symbol, d.StopLoss, stop, multiplier, atr, timeframe))
+		d.StopLoss = stop
+	}
+	return notes
+}
@@ -0,0 +1,42 @@
+package decision
+
+import (
+	"nofx/market"
+	"testing"
+)
+
+// TestApplyATRStops tests replacing open stops with ATR-based distances
+func TestApplyATRStops(t *testing.T) {
+	data := map[string]*market.Data{
+		"BTCUSDT": {
+			CurrentPrice:   60000,
+			IntradaySeries: &market.IntradayData{ATR14: 999},
+			TimeframeData:  map[string]*market.TimeframeSeriesData{"15m": {ATR14: 400}},
+		},
+		"ETHUSDT": {CurrentPrice: 3000, IntradaySeries: &market.IntradayData{ATR14: 20}},
+	}
+	decisions := []Decision{
+		{Symbol: "BTCUSDT", Action: "open_long", StopLoss: 59000},
+		{Symbol: "ETHUSDT", Action: "open_short", StopLoss: 3100},
+		{Symbol: "BTCUSDT", Action: "close_long"},
+		{Symbol: "SOLUSDT", Action: "open_long", StopLoss: 140}, // No market data
+	}
+
+	notes := ApplyATRStops(decisions, data, "15m", 1.5)
+	if len(notes) != 2 {
+		t.Fatalf("expected 2 notes, got %v", notes)
+	}
+	if decisions[0].StopLoss != 59400 {
+		t.Errorf("long stop should use the 15m ATR, got %v", decisions[0].StopLoss)
+	}
+	if decisions[1].StopLoss != 3030 {
+		t.Errorf("short stop should fall back to the intraday ATR, got %v", decisions[1].StopLoss)
+	}
+	if decisions[3].StopLoss != 140 {
+		t.Errorf("stop without market data should be kept, got %v", decisions[3].StopLoss)
+	}
+
+	if notes := ApplyATRStops(decisions, data, "15m", 0); notes != nil {
+		t.Errorf("disabled multiplier should not change stops: %v", notes)
+	}
+}
@@ -0,0 +1,32 @@
+package decision
+
+import (
+	"nofx/market"
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+// TestFilterCandidatesByMeta tests category and listing age filters of the candidate scanner
+func TestFilterCandidatesByMeta(t *testing.T) {
+	now := time.Now()
+	metas := map[string]*market.SymbolMeta{
+		"DOGEUSDT": {Symbol: "DOGEUSDT", Category: market.CategoryMeme, LaunchDate: now.AddDate(-3, 0, 0)},
+		"SOLUSDT":  {Symbol: "SOLUSDT", Category: market.CategoryL1, LaunchDate: now.AddDate(-2, 0, 0)},
+		"NEWUSDT":  {Symbol: "NEWUSDT", Category: market.CategoryL1, LaunchDate: now.AddDate(0, 0, -3)},
+	}
+	candidates := []CandidateCoin{{Symbol: "DOGEUSDT"}, {Symbol: "SOLUSDT"}, {Symbol: "NEWUSDT"}, {Symbol: "UNKNOWNUSDT"}}
+
+	kept, dropped := filterCandidatesByMeta(candidates, metas, store.CoinSourceConfig{ExcludeCategories: []string{"meme"}, MinListingDays: 30}, now)
+	if len(kept) != 2 || kept[0].Symbol != "SOLUSDT" || kept[1].Symbol != "UNKNOWNUSDT" {
+		t.Errorf("unexpected kept candidates: %v", kept)
+	}
+	if len(dropped) != 2 {
+		t.Errorf("expected DOGE and NEW dropped, got %v", dropped)
+	}
+
+	kept, _ = filterCandidatesByMeta(candidates, metas, store.CoinSourceConfig{IncludeCategories: []string{"Meme"}}, now)
+	if len(kept) != 2 || kept[0].Symbol != "DOGEUSDT" {
+		t.Errorf("include filter should keep memes and unknown symbols, got %v", kept)
+	}
+}
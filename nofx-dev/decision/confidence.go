@@ -0,0 +1,47 @@
+package decision
+
+import (
+	"fmt"
+	"nofx/store"
+)
+
+// ApplyConfidenceGate applies a trader's confidence gate: entries (open / add) below MinConfidence are removed and,
+// with ScaleSize, entry sizes are scaled by confidence / FullSizeConfidence (capped at the AI's size). Closes and
+// other risk-reducing actions are never ignored. Returns the decisions to execute, the confidence of every action
+// (hold / wait / alert left out) for calibration and an execution log note per ignored or scaled entry
+func ApplyConfidenceGate(decisions []Decision, gate store.ConfidenceGate) ([]Decision, []store.ActionConfidence, []string) {
+	fullSize := gate.FullSizeConfidence
+	if fullSize <= 0 {
+		fullSize = 100
+	}
+
+	kept := decisions[:0:0]
+	var confidences []store.ActionConfidence
+	var notes []string
+	for _, d := range decisions {
+		switch d.Action {
+		case "hold", "wait", "alert":
+			kept = append(kept, d)
+			continue
+		}
+		c := store.ActionConfidence{Symbol: d.Symbol, Action: d.Action, Confidence: d.Confidence}
+		if isEntryAction(d.Action) {
+			if gate.MinConfidence > 0 && d.Confidence < gate.MinConfidence {
+				c.Ignored = true
+				confidences = append(confidences, c)
+				notes = append(notes, fmt.Sprintf("🎯 %s %s ignored: confidence %d < %d", d.Symbol, d.Action, d.Confidence, gate.MinConfidence))
+				continue
+			}
+			if gate.ScaleSize && d.Confidence < fullSize && d.PositionSizeUSD > 0 {
+				c.SizeFactor = float64(d.Confidence) / float64(fullSize)
+				notes = append(notes, fmt.Sprintf("🎯 %s %s sized by confidence %d/%d: %.2f → %.2f USDT",
+					d.Symbol, d.Action, d.Confidence, fullSize, d.PositionSizeUSD, d.PositionSizeUSD*c.SizeFactor))
+				d.PositionSizeUSD *= c.SizeFactor
+				d.RiskUSD *= c.SizeFactor
+			}
+		}
+		confidences = append(confidences, c)
+		kept = append(kept, d)
+	}
+	return kept, confidences, notes
+}
@@ -0,0 +1,44 @@
+package decision
+
+import (
+	"nofx/store"
+	"strings"
+	"testing"
+)
+
+// TestApplyConfidenceGate tests ignoring low-confidence entries, confidence sizing and the recorded confidences
+func TestApplyConfidenceGate(t *testing.T) {
+	decisions := []Decision{
+		{Symbol: "BTCUSDT", Action: "open_long", PositionSizeUSD: 1000, RiskUSD: 50, Confidence: 80},
+		{Symbol: "ETHUSDT", Action: "open_short", PositionSizeUSD: 500, Confidence: 55},
+		{Symbol: "SOLUSDT", Action: "close_long", Confidence: 40},
+		{Symbol: "DOGEUSDT", Action: "add_long", PositionSizeUSD: 200, Confidence: 95},
+		{Symbol: "ALL", Action: "wait"},
+	}
+	kept, confidences, notes := ApplyConfidenceGate(decisions, store.ConfidenceGate{MinConfidence: 60, ScaleSize: true, FullSizeConfidence: 90})
+
+	if len(kept) != 4 || kept[1].Symbol != "SOLUSDT" {
+		t.Fatalf("expected the ETH entry ignored and the low-confidence close kept, got %+v", kept)
+	}
+	if kept[0].PositionSizeUSD < 888 || kept[0].PositionSizeUSD > 889 || kept[0].RiskUSD < 44 || kept[0].RiskUSD > 45 {
+		t.Errorf("expected BTC sized by 80/90, got size %.2f risk %.2f", kept[0].PositionSizeUSD, kept[0].RiskUSD)
+	}
+	if kept[2].PositionSizeUSD != 200 {
+		t.Errorf("expected confidence above the full size one to keep the AI's size, got %.2f", kept[2].PositionSizeUSD)
+	}
+	if decisions[0].PositionSizeUSD != 1000 {
+		t.Error("expected the input decisions left unchanged")
+	}
+
+	if len(confidences) != 4 || !confidences[1].Ignored || confidences[1].Confidence != 55 || confidences[0].SizeFactor == 0 || confidences[3].SizeFactor != 0 {
+		t.Errorf("unexpected confidences %+v", confidences)
+	}
+	if len(notes) != 2 || !strings.Contains(notes[0], "BTCUSDT open_long sized by confidence 80/90") || !strings.Contains(notes[1], "confidence 55 < 60") {
+		t.Errorf("unexpected notes %v", notes)
+	}
+
+	kept, confidences, notes = ApplyConfidenceGate(decisions, store.ConfidenceGate{})
+	if len(kept) != len(decisions) || len(confidences) != 4 || len(notes) != 0 {
+		t.Error("expected a disabled gate to only record confidences")
+	}
+}
@@ -0,0 +1,64 @@
+package decision
+
+import (
+	"nofx/logger"
+	"nofx/mcp"
+	"unicode/utf8"
+)
+
+// promptBudget prompt token budget of clients with a configured context window (small local models), 0 = unlimited
+func promptBudget(client mcp.AIClient) int {
+	if b, ok := client.(interface{ PromptTokenBudget() int }); ok {
+		return b.PromptTokenBudget()
+	}
+	return 0
+}
+
+// estimateTokens rough token count (~4 characters per token)
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// compactEngine copy of the engine sending compact market data (the strategy itself is unchanged)
+func (e *StrategyEngine) compactEngine() *StrategyEngine {
+	if e.config.Indicators.EnableCompactMode {
+		return e
+	}
+	cfg := *e.config
+	cfg.Indicators.EnableCompactMode = true
+	return &StrategyEngine{config: &cfg, quoteAsset: e.quoteAsset}
+}
+
+// fitUserPrompt shrinks the user prompt into the token budget left by the system prompt: compact market data
+// first, then fewer candidate coins (positions are always kept). Returns the prompt unchanged when it fits
+func (e *StrategyEngine) fitUserPrompt(ctx *Context, systemPrompt, userPrompt string, budget int) string {
+	available := budget - estimateTokens(systemPrompt)
+	original := estimateTokens(userPrompt)
+	if original <= available {
+		return userPrompt
+	}
+
+	compact := e
+	if !e.config.Indicators.EnableCompactMode {
+		compact = e.compactEngine()
+		userPrompt = compact.BuildUserPrompt(ctx)
+	}
+
+	kept := len(ctx.CandidateCoins)
+	for estimateTokens(userPrompt) > available && kept > 0 {
+		kept /= 2
+		trimmed := *ctx
+		trimmed.CandidateCoins = ctx.CandidateCoins[:kept]
+		userPrompt = compact.BuildUserPrompt(&trimmed)
+	}
+
+	tokens := estimateTokens(userPrompt)
+	if tokens > available {
+		logger.Warnf("⚠️ User prompt ~%d tokens still exceeds the context budget (%d left after the system prompt), the model may truncate it",
+			tokens, available)
+	} else {
+		logger.Infof("📉 User prompt shrunk from ~%d to ~%d tokens to fit the context window (compact market data, %d/%d candidates)",
+			original, tokens, kept, len(ctx.CandidateCoins))
+	}
+	return userPrompt
+}
@@ -0,0 +1,61 @@
+package decision
+
+import (
+	"fmt"
+	"nofx/market"
+	"nofx/mcp"
+	"nofx/store"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeAIClient mcp.AIClient that never calls a model
+type fakeAIClient struct{}
+
+func (fakeAIClient) SetAPIKey(apiKey, customURL, customModel string)                  {}
+func (fakeAIClient) SetTimeout(timeout time.Duration)                                 {}
+func (fakeAIClient) CallWithMessages(systemPrompt, userPrompt string) (string, error) { return "", nil }
+func (fakeAIClient) CallWithRequest(req *mcp.Request) (string, error)                 { return "", nil }
+
+// budgetClient AI client reporting a prompt token budget
+type budgetClient struct {
+	fakeAIClient
+	budget int
+}
+
+func (c budgetClient) PromptTokenBudget() int { return c.budget }
+
+// TestFitUserPrompt tests shrinking the prompt into a small context window
+func TestFitUserPrompt(t *testing.T) {
+	ctx := &Context{MarketDataMap: make(map[string]*market.Data)}
+	for i := 0; i < 16; i++ {
+		symbol := fmt.Sprintf("COIN%dUSDT", i)
+		prices := make([]float64, 40)
+		for j := range prices {
+			prices[j] = 100 + float64(j)
+		}
+		ctx.CandidateCoins = append(ctx.CandidateCoins, CandidateCoin{Symbol: symbol})
+		ctx.MarketDataMap[symbol] = &market.Data{Symbol: symbol, CurrentPrice: 100, IntradaySeries: &market.IntradayData{MidPrices: prices}}
+	}
+	engine := NewStrategyEngine(&store.StrategyConfig{})
+	full := engine.BuildUserPrompt(ctx)
+
+	if got := engine.fitUserPrompt(ctx, "system", full, estimateTokens(full)+10); got != full {
+		t.Error("a prompt within budget should be unchanged")
+	}
+
+	// Compact market data alone isn't enough, candidates are dropped from the end
+	budget := 100
+	fitted := engine.fitUserPrompt(ctx, "system", full, budget)
+	if estimateTokens(fitted) > budget || !strings.Contains(fitted, "COIN0USDT") || strings.Contains(fitted, "COIN15USDT") {
+		t.Errorf("expected the top candidates within ~%d tokens, got ~%d tokens", budget, estimateTokens(fitted))
+	}
+	if len(ctx.CandidateCoins) != 16 || engine.config.Indicators.EnableCompactMode {
+		t.Error("the context and strategy shouldn't be modified")
+	}
+
+	if promptBudget(budgetClient{budget: 4000}) != 4000 || promptBudget(fakeAIClient{}) != 0 {
+		t.Error("unexpected prompt budgets")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"nofx/events"
 	"nofx/logger"
 	"nofx/market"
 	"nofx/mcp"
@@ -48,8 +49,10 @@ type PositionInfo struct {
 	UnrealizedPnLPct float64 `json:"unrealized_pnl_pct"`
 	PeakPnLPct       float64 `json:"peak_pnl_pct"` // Historical peak profit percentage
 	LiquidationPrice float64 `json:"liquidation_price"`
+	LiquidationDist  float64 `json:"liquidation_dist_pct,omitempty"` // Mark to liquidation price in % of mark (0 if unknown)
 	MarginUsed       float64 `json:"margin_used"`
-	UpdateTime       int64   `json:"update_time"` // Position update timestamp (milliseconds)
+	UpdateTime       int64   `json:"update_time"`            // Position update timestamp (milliseconds)
+	ManagedExit      string  `json:"managed_exit,omitempty"` // Active managed exit (mode, multiplier, current stop), empty when the AI manages the exit
 }
 
 // AccountInfo account information
@@ -94,15 +97,16 @@ type TradingStats struct {
 
 // RecentOrder recently completed order (for AI input)
 type RecentOrder struct {
-	Symbol       string  `json:"symbol"`        // Trading pair
-	Side         string  `json:"side"`          // long/short
-	EntryPrice   float64 `json:"entry_price"`   // Entry price
-	ExitPrice    float64 `json:"exit_price"`    // Exit price
-	RealizedPnL  float64 `json:"realized_pnl"`  // Realized profit/loss
-	PnLPct       float64 `json:"pnl_pct"`       // Profit/loss percentage
-	EntryTime    string  `json:"entry_time"`    // Entry time
-	ExitTime     string  `json:"exit_time"`     // Exit time
-	HoldDuration string  `json:"hold_duration"` // Hold duration, e.g. "2h30m"
+	Symbol       string  `json:"symbol"`           // Trading pair
+	Side         string  `json:"side"`             // long/short
+	EntryPrice   float64 `json:"entry_price"`      // Entry price
+	ExitPrice    float64 `json:"exit_price"`       // Exit price
+	RealizedPnL  float64 `json:"realized_pnl"`     // Realized profit/loss
+	PnLPct       float64 `json:"pnl_pct"`          // Profit/loss percentage
+	EntryTime    string  `json:"entry_time"`       // Entry time
+	ExitTime     string  `json:"exit_time"`        // Exit time
+	HoldDuration string  `json:"hold_duration"`    // Hold duration, e.g. "2h30m"
+	Seeded       bool    `json:"seeded,omitempty"` // Cold-start seed (predecessor trader / backtest), not a live trade of this trader
 }
 
 // Context trading context (complete information passed to AI)
@@ -116,25 +120,52 @@ type Context struct {
 	PromptVariant   string                             `json:"prompt_variant,omitempty"`
 	TradingStats    *TradingStats                      `json:"trading_stats,omitempty"`
 	RecentOrders    []RecentOrder                      `json:"recent_orders,omitempty"`
+	SimilarTrades   map[string][]SimilarTrade          `json:"similar_trades,omitempty"` // Symbol -> past trades most similar to its current situation
 	MarketDataMap   map[string]*market.Data            `json:"-"`
 	MultiTFMarket   map[string]map[string]*market.Data `json:"-"`
 	OITopDataMap    map[string]*OITopData              `json:"-"`
 	QuantDataMap    map[string]*QuantData              `json:"-"`
+	SymbolMetaMap   map[string]*market.SymbolMeta      `json:"-"`
+	MarketOverview  *market.MarketOverview             `json:"market_overview,omitempty"` // Total market cap, dominance, BTC/ETH 24h (nil if unavailable)
+	FearGreed       *market.FearGreedIndex             `json:"fear_greed,omitempty"`      // Crypto Fear & Greed index and its recent trend (nil if unavailable)
+	StaleSymbols    []string                           `json:"-"`                         // Position symbols whose market data is stale (degraded mode, stale candidates are excluded)
+	UpcomingEvents  []events.Event                     `json:"upcoming_events,omitempty"` // High-impact events within EventWindow affecting the market or a listed symbol
+	MarketEventSoon bool                               `json:"market_event_soon,omitempty"`
+	SymbolEvents    map[string][]events.Event          `json:"-"` // Symbol -> its own events (unlocks) within EventWindow
+	EventWindow     time.Duration                      `json:"-"`
+	EventPause      time.Duration                      `json:"-"` // New entries are blocked this long before / after a high-impact event (0 = disabled)
 	BTCETHLeverage  int                                `json:"-"`
 	AltcoinLeverage int                                `json:"-"`
 	Timeframes      []string                           `json:"-"`
+	ReviewAge       time.Duration                      `json:"-"` // Positions held longer must be reviewed (0 = no limit)
+	PerformanceSeed string                             `json:"-"` // Where seeded recent trades come from ("" = none seeded)
+	LiqGuardPct     float64                            `json:"-"` // Positions closer to liquidation can't be added to (0 = disabled)
+	ToolSource      ToolSource                         `json:"-"` // Market data of the decision tools (nil = no tools, e.g. backtests)
+	TradeMemory     []TradeOutcome                     `json:"-"` // Closed trades with their entry situation (nil = no trade memory)
+	LiquidityFilter store.LiquidityFilter              `json:"-"` // Trader's liquidity filter of candidate coins
+	LiquidityChecks []LiquidityCheck                   `json:"-"` // Filter outcome of each candidate coin fetched this cycle
 }
 
 // Decision AI trading decision
 type Decision struct {
 	Symbol string `json:"symbol"`
-	Action string `json:"action"` // "open_long", "open_short", "close_long", "close_short", "hold", "wait"
+	Action string `json:"action"` // "open_long", "open_short", "add_long", "add_short", "close_long", "close_short", "partial_close_long", "partial_close_short", "manage_exit_long", "manage_exit_short", "alert", "hold", "wait"
 
 	// Opening position parameters
 	Leverage        int     `json:"leverage,omitempty"`
 	PositionSizeUSD float64 `json:"position_size_usd,omitempty"`
 	StopLoss        float64 `json:"stop_loss,omitempty"`
 	TakeProfit      float64 `json:"take_profit,omitempty"`
+	TrailingStopPct float64 `json:"trailing_stop_pct,omitempty"` // Optional trailing stop distance from best price (%)
+
+	// Partial close parameters
+	ClosePct float64 `json:"close_pct,omitempty"` // Share of the position to close (%), 0-100
+
+	// Managed exit parameters (manage_exit_*, optional on open_*)
+	ManagedExit *ManagedExit `json:"managed_exit,omitempty"` // Hands exit management to a deterministic trailing stop
+
+	// Alert parameters (reasoning is the alert text)
+	AlertType string `json:"alert_type,omitempty"` // breakout, breakdown, oi_surge, volume_spike, funding_shift, commentary
 
 	// Common parameters
 	Confidence int     `json:"confidence,omitempty"` // Confidence level (0-100)
@@ -151,6 +182,17 @@ type FullDecision struct {
 	RawResponse         string     `json:"raw_response"`
 	Timestamp           time.Time  `json:"timestamp"`
 	AIRequestDurationMs int64      `json:"ai_request_duration_ms,omitempty"`
+
+	// Schema repair (set when the first response didn't match DecisionSchema)
+	SchemaViolations []string `json:"schema_violations,omitempty"` // Violations of the first response
+	SchemaRepair     string   `json:"schema_repair,omitempty"`     // SchemaRepaired / SchemaFailed
+	RepairResponse   string   `json:"repair_response,omitempty"`   // Raw answer to the repair request
+
+	ToolCalls []string `json:"tool_calls,omitempty"` // Decision tools the model called, one line each
+
+	PromptHash string `json:"prompt_hash,omitempty"` // Hash of the strategy's prompt configuration (StrategyEngine.PromptHash)
+
+	Usage mcp.Usage `json:"usage"` // Tokens and estimated cost of the AI calls (tool rounds and schema repair included)
 }
 
 // QuantData quantitative data structure (fund flow, position changes, price changes)
@@ -191,7 +233,8 @@ type OIDeltaData struct {
 
 // StrategyEngine strategy execution engine
 type StrategyEngine struct {
-	config *store.StrategyConfig
+	config     *store.StrategyConfig
+	quoteAsset string // Quote asset of the trading exchange (empty = USDT)
 }
 
 // NewStrategyEngine creates strategy execution engine
@@ -199,6 +242,12 @@ func NewStrategyEngine(config *store.StrategyConfig) *StrategyEngine {
 	return &StrategyEngine{config: config}
 }
 
+// SetQuoteAsset sets the quote asset candidate coins are traded against (e.g. "USDC")
+// Bare static coins get this quote, coin pool / OI ranking symbols are rebased onto it
+func (e *StrategyEngine) SetQuoteAsset(quote string) {
+	e.quoteAsset = quote
+}
+
 // GetRiskControlConfig gets risk control configuration
 func (e *StrategyEngine) GetRiskControlConfig() store.RiskControlConfig {
 	return e.config.RiskControl
@@ -232,47 +281,47 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 	}
 
 	// 1. Fetch market data using strategy config
-	if len(ctx.MarketDataMap) == 0 {
-		if err := fetchMarketDataWithStrategy(ctx, engine); err != nil {
-			return nil, fmt.Errorf("failed to fetch market data: %w", err)
-		}
+	if err := PrepareContext(ctx, engine); err != nil {
+		return nil, err
 	}
 
-	// Ensure OITopDataMap is initialized
-	if ctx.OITopDataMap == nil {
-		ctx.OITopDataMap = make(map[string]*OITopData)
-		oiPositions, err := pool.GetOITopPositions()
-		if err == nil {
-			for _, pos := range oiPositions {
-				ctx.OITopDataMap[pos.Symbol] = &OITopData{
-					Rank:              pos.Rank,
-					OIDeltaPercent:    pos.OIDeltaPercent,
-					OIDeltaValue:      pos.OIDeltaValue,
-					PriceDeltaPercent: pos.PriceDeltaPercent,
-					NetLong:           pos.NetLong,
-					NetShort:          pos.NetShort,
-				}
-			}
-		}
-	}
+	// Past trades entered in situations like the current one
+	engine.attachSimilarTrades(ctx)
 
 	// 2. Build System Prompt using strategy engine
 	riskConfig := engine.GetRiskControlConfig()
-	systemPrompt := engine.BuildSystemPrompt(ctx.Account.TotalEquity, variant)
+	systemPrompt := engine.buildSystemPrompt(ctx.Account.TotalEquity, variant, contextSymbols(ctx))
 
-	// 3. Build User Prompt using strategy engine
-	userPrompt := engine.BuildUserPrompt(ctx)
+	// 3. Build User Prompt using strategy engine (compact market data when the model can fetch detail with tools)
+	caller, useTools := toolCaller(mcpClient, engine, ctx)
+	promptEngine := engine
+	if useTools {
+		systemPrompt += toolPrompt()
+		promptEngine = engine.compactEngine()
+	}
+	userPrompt := promptEngine.BuildUserPrompt(ctx)
+	if budget := promptBudget(mcpClient); budget > 0 {
+		userPrompt = promptEngine.fitUserPrompt(ctx, systemPrompt, userPrompt, budget)
+	}
 
 	// 4. Call AI API
 	aiCallStart := time.Now()
-	aiResponse, err := mcpClient.CallWithMessages(systemPrompt, userPrompt)
+	var aiResponse string
+	var toolCalls []string
+	var err error
+	if useTools {
+		aiResponse, toolCalls, err = runToolLoop(ctx, caller, systemPrompt, userPrompt)
+	} else {
+		aiResponse, err = mcpClient.CallWithMessages(systemPrompt, userPrompt)
+	}
 	aiCallDuration := time.Since(aiCallStart)
 	if err != nil {
 		return nil, fmt.Errorf("AI API call failed: %w", err)
 	}
 
-	// 5. Parse AI response
-	decision, err := parseFullDecisionResponse(
+	// 5. Parse AI response (one repair request when it doesn't match the decision schema)
+	decision, err := parseDecisionWithRepair(
+		mcpClient,
 		aiResponse,
 		ctx.Account.TotalEquity,
 		riskConfig.BTCETHMaxLeverage,
@@ -285,6 +334,9 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 		decision.UserPrompt = userPrompt
 		decision.AIRequestDurationMs = aiCallDuration.Milliseconds()
 		decision.RawResponse = aiResponse
+		decision.ToolCalls = toolCalls
+		decision.PromptHash = engine.PromptHash()
+		decision.Usage = callUsage(mcpClient, systemPrompt+userPrompt, aiResponse+decision.RepairResponse)
 	}
 
 	if err != nil {
@@ -294,6 +346,44 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 	return decision, nil
 }
 
+// PrepareContext fetches the cycle's market data (candidate coins through the liquidity filter, open positions)
+// and OI ranking into the context unless already present; every decision provider decides on this data
+func PrepareContext(ctx *Context, engine *StrategyEngine) error {
+	if len(ctx.MarketDataMap) == 0 {
+		if err := fetchMarketDataWithStrategy(ctx, engine); err != nil {
+			return fmt.Errorf("failed to fetch market data: %w", err)
+		}
+	}
+
+	// Ensure OITopDataMap is initialized
+	if ctx.OITopDataMap == nil {
+		ctx.OITopDataMap = make(map[string]*OITopData)
+		oiPositions, err := pool.GetOITopPositions()
+		if err == nil {
+			for _, pos := range oiPositions {
+				ctx.OITopDataMap[pos.Symbol] = &OITopData{
+					Rank:              pos.Rank,
+					OIDeltaPercent:    pos.OIDeltaPercent,
+					OIDeltaValue:      pos.OIDeltaValue,
+					PriceDeltaPercent: pos.PriceDeltaPercent,
+					NetLong:           pos.NetLong,
+					NetShort:          pos.NetShort,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// callUsage tokens the client counted since its last decision, estimated from the prompt and answers when the
+// client doesn't count them
+func callUsage(client mcp.AIClient, prompt, response string) mcp.Usage {
+	if tracker, ok := client.(mcp.UsageTracker); ok {
+		return tracker.TakeUsage()
+	}
+	return mcp.Usage{PromptTokens: mcp.EstimateTokens(prompt), CompletionTokens: mcp.EstimateTokens(response), Estimated: true}
+}
+
 // ============================================================================
 // Market Data Fetching
 // ============================================================================
@@ -334,6 +424,16 @@ func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
 			logger.Infof("⚠️  Failed to fetch market data for position %s: %v", pos.Symbol, err)
 			continue
 		}
+		if data.Stale {
+			// Positions keep their (annotated) data, the AI still has to manage them
+			logger.Infof("⚠️  %s market data is stale (last update %v ago)", pos.Symbol, data.DataAge.Round(time.Second))
+			ctx.StaleSymbols = append(ctx.StaleSymbols, pos.Symbol)
+		}
+		if data.MarkPrice != nil && data.MarkPrice.Blowout() {
+			logger.Infof("⚠️  %s basis blown out: %s", pos.Symbol, data.MarkPrice.Summary())
+		}
+		AttachOrderBook(config, data)
+		AttachWeeklyContext(config, data)
 		ctx.MarketDataMap[pos.Symbol] = data
 	}
 
@@ -343,8 +443,6 @@ func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
 		positionSymbols[pos.Symbol] = true
 	}
 
-	const minOIThresholdMillions = 15.0 // 15M USD minimum open interest value
-
 	for _, coin := range ctx.CandidateCoins {
 		if _, exists := ctx.MarketDataMap[coin.Symbol]; exists {
 			continue
@@ -358,29 +456,195 @@ func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
 
 		// Liquidity filter
 		isExistingPosition := positionSymbols[coin.Symbol]
-		if !isExistingPosition && data.OpenInterest != nil && data.CurrentPrice > 0 {
-			oiValue := data.OpenInterest.Latest * data.CurrentPrice
-			oiValueInMillions := oiValue / 1_000_000
-			if oiValueInMillions < minOIThresholdMillions {
-				logger.Infof("⚠️  %s OI value too low (%.2fM USD < %.1fM), skipping coin",
-					coin.Symbol, oiValueInMillions, minOIThresholdMillions)
+		if !isExistingPosition && data.Stale {
+			logger.Infof("⚠️  %s market data is stale (last update %v ago), skipping coin", coin.Symbol, data.DataAge.Round(time.Second))
+			continue
+		}
+		if !isExistingPosition && data.MarkPrice != nil && data.MarkPrice.Blowout() {
+			// Market entries would fill far from mark (and from the price stops and PnL are computed on)
+			logger.Infof("⚠️  %s basis blown out (%s), skipping coin", coin.Symbol, data.MarkPrice.Summary())
+			continue
+		}
+		if !isExistingPosition {
+			check := CheckLiquidity(coin.Symbol, data, ctx.LiquidityFilter)
+			ctx.LiquidityChecks = append(ctx.LiquidityChecks, check)
+			logger.Infof("  %s", check)
+			if !check.Passed {
 				continue
 			}
 		}
 
+		AttachOrderBook(config, data)
+		AttachWeeklyContext(config, data)
 		ctx.MarketDataMap[coin.Symbol] = data
 	}
 
 	logger.Infof("📊 Successfully fetched multi-timeframe market data for %d coins", len(ctx.MarketDataMap))
+
+	// 3. Contract metadata (category, listing date, max leverage) for the prompt
+	if metas, err := market.GetAllSymbolMeta(); err != nil {
+		logger.Infof("⚠️  Failed to fetch symbol metadata: %v", err)
+	} else {
+		ctx.SymbolMetaMap = metas
+		// Tick sizes put prompt prices on the grid the exchange accepts
+		for symbol, data := range ctx.MarketDataMap {
+			if meta, ok := metas[symbol]; ok && data.TickSize == 0 {
+				data.TickSize = meta.TickSize
+			}
+		}
+	}
+
+	// 4. Global market context (total market cap, BTC dominance, BTC/ETH 24h change)
+	if overview, err := market.GetMarketOverview(); err != nil {
+		logger.Infof("⚠️  Failed to fetch market overview: %v", err)
+	} else {
+		ctx.MarketOverview = overview
+	}
+
+	// 5. Crypto Fear & Greed index (refreshed in the background, see market.StartFearGreedFetcher)
+	if index, err := market.GetFearGreedIndex(); err != nil {
+		logger.Infof("⚠️  Failed to fetch fear & greed index: %v", err)
+	} else {
+		ctx.FearGreed = index
+	}
+
+	// 6. High-impact events (macro releases, token unlocks) within the event window
+	attachEvents(ctx, config, events.Default(), time.Now())
 	return nil
 }
 
+// attachEvents flags high-impact events within the strategy's event window, market-wide and per listed symbol
+func attachEvents(ctx *Context, config *store.StrategyConfig, calendar *events.Calendar, now time.Time) {
+	if !config.Indicators.EnableEventCalendar || calendar == nil {
+		return
+	}
+	hours := config.Indicators.EventWindowHours
+	if hours <= 0 {
+		hours = 24
+	}
+	ctx.EventWindow = time.Duration(hours) * time.Hour
+	ctx.EventPause = time.Duration(config.RiskControl.EventPauseMinutes) * time.Minute
+
+	for _, e := range calendar.Upcoming(now, ctx.EventWindow) {
+		if e.Impact != "high" {
+			continue
+		}
+		if e.MarketWide() {
+			ctx.MarketEventSoon = true
+			ctx.UpcomingEvents = append(ctx.UpcomingEvents, e)
+			continue
+		}
+		relevant := false
+		for symbol := range ctx.MarketDataMap {
+			if e.Applies(symbol) {
+				if ctx.SymbolEvents == nil {
+					ctx.SymbolEvents = make(map[string][]events.Event)
+				}
+				ctx.SymbolEvents[symbol] = append(ctx.SymbolEvents[symbol], e)
+				relevant = true
+			}
+		}
+		if relevant {
+			ctx.UpcomingEvents = append(ctx.UpcomingEvents, e)
+		}
+	}
+}
+
+// EventSoon whether a high-impact event affecting the symbol (market-wide or its own) is within the event window
+func (ctx *Context) EventSoon(symbol string) bool {
+	return ctx.MarketEventSoon || len(ctx.SymbolEvents[symbol]) > 0
+}
+
+// AttachOrderBook adds order book metrics to data when the strategy enables them, a failed fetch leaves them out
+func AttachOrderBook(config *store.StrategyConfig, data *market.Data) {
+	if !config.Indicators.EnableOrderBook {
+		return
+	}
+	metrics, err := market.GetOrderBookMetrics(data.Symbol)
+	if err != nil {
+		logger.Infof("⚠️  Failed to fetch order book for %s: %v", data.Symbol, err)
+		return
+	}
+	data.OrderBook = metrics
+}
+
+// AttachWeeklyContext adds the weekly trend structure to data when the strategy enables it, a failed fetch
+// (e.g. a listing younger than two weeks) leaves it out
+func AttachWeeklyContext(config *store.StrategyConfig, data *market.Data) {
+	if !config.Indicators.EnableWeeklyContext {
+		return
+	}
+	weekly, err := market.GetWeeklyContext(data.Symbol)
+	if err != nil {
+		logger.Infof("⚠️  Failed to fetch weekly context for %s: %v", data.Symbol, err)
+		return
+	}
+	data.WeeklyContext = weekly
+}
+
 // ============================================================================
 // Candidate Coins
 // ============================================================================
 
-// GetCandidateCoins gets candidate coins based on strategy configuration
+// GetCandidateCoins gets candidate coins based on strategy configuration,
+// dropping those excluded by the category / listing age filters
 func (e *StrategyEngine) GetCandidateCoins() ([]CandidateCoin, error) {
+	candidates, err := e.collectCandidateCoins()
+	if err != nil {
+		return nil, err
+	}
+	coinSource := e.config.CoinSource
+	if len(coinSource.IncludeCategories) > 0 || len(coinSource.ExcludeCategories) > 0 || coinSource.MinListingDays > 0 {
+		metas, err := market.GetAllSymbolMeta()
+		if err != nil {
+			logger.Infof("⚠️  Failed to fetch symbol metadata, category filters skipped: %v", err)
+		} else {
+			var dropped []string
+			candidates, dropped = filterCandidatesByMeta(candidates, metas, coinSource, time.Now())
+			if len(dropped) > 0 {
+				logger.Infof("📋 Category / listing filters dropped %d candidate(s): %v", len(dropped), dropped)
+			}
+		}
+	}
+	// Watch-only coins are picked explicitly, the filters don't apply to them
+	return addWatchOnlyCandidates(candidates, e.WatchOnlySymbols()), nil
+}
+
+// filterCandidatesByMeta applies include / exclude categories and the minimum listing age
+// Symbols without metadata are kept, returns the kept candidates and the dropped symbols
+func filterCandidatesByMeta(candidates []CandidateCoin, metas map[string]*market.SymbolMeta, coinSource store.CoinSourceConfig, now time.Time) ([]CandidateCoin, []string) {
+	include := make(map[string]bool, len(coinSource.IncludeCategories))
+	for _, c := range coinSource.IncludeCategories {
+		include[strings.ToLower(c)] = true
+	}
+	exclude := make(map[string]bool, len(coinSource.ExcludeCategories))
+	for _, c := range coinSource.ExcludeCategories {
+		exclude[strings.ToLower(c)] = true
+	}
+
+	kept := make([]CandidateCoin, 0, len(candidates))
+	var dropped []string
+	for _, coin := range candidates {
+		meta, ok := metas[coin.Symbol]
+		if !ok {
+			kept = append(kept, coin)
+			continue
+		}
+		if (len(include) > 0 && !include[meta.Category]) || exclude[meta.Category] {
+			dropped = append(dropped, coin.Symbol)
+			continue
+		}
+		if age := meta.ListingAgeDays(now); coinSource.MinListingDays > 0 && age >= 0 && age < coinSource.MinListingDays {
+			dropped = append(dropped, coin.Symbol)
+			continue
+		}
+		kept = append(kept, coin)
+	}
+	return kept, dropped
+}
+
+// collectCandidateCoins gathers candidates from the configured coin sources
+func (e *StrategyEngine) collectCandidateCoins() ([]CandidateCoin, error) {
 	var candidates []CandidateCoin
 	symbolSources := make(map[string][]string)
 
@@ -396,7 +660,7 @@ func (e *StrategyEngine) GetCandidateCoins() ([]CandidateCoin, error) {
 	switch coinSource.SourceType {
 	case "static":
 		for _, symbol := range coinSource.StaticCoins {
-			symbol = market.Normalize(symbol)
+			symbol = market.NormalizeQuote(symbol, e.quoteAsset)
 			candidates = append(candidates, CandidateCoin{
 				Symbol:  symbol,
 				Sources: []string{"static"},
@@ -410,6 +674,9 @@ func (e *StrategyEngine) GetCandidateCoins() ([]CandidateCoin, error) {
 	case "oi_top":
 		return e.getOITopCoins(coinSource.OITopLimit)
 
+	case "top_movers":
+		return e.getTopMoversCoins(coinSource.TopMoversLimit)
+
 	case "mixed":
 		if coinSource.UseCoinPool {
 			poolCoins, err := e.getCoinPoolCoins(coinSource.CoinPoolLimit)
@@ -433,8 +700,19 @@ func (e *StrategyEngine) GetCandidateCoins() ([]CandidateCoin, error) {
 			}
 		}
 
+		if coinSource.UseTopMovers {
+			moverCoins, err := e.getTopMoversCoins(coinSource.TopMoversLimit)
+			if err != nil {
+				logger.Infof("⚠️  Failed to scan top movers: %v", err)
+			} else {
+				for _, coin := range moverCoins {
+					symbolSources[coin.Symbol] = append(symbolSources[coin.Symbol], "top_movers")
+				}
+			}
+		}
+
 		for _, symbol := range coinSource.StaticCoins {
-			symbol = market.Normalize(symbol)
+			symbol = market.NormalizeQuote(symbol, e.quoteAsset)
 			if _, exists := symbolSources[symbol]; !exists {
 				symbolSources[symbol] = []string{"static"}
 			} else {
@@ -468,13 +746,21 @@ func (e *StrategyEngine) getCoinPoolCoins(limit int) ([]CandidateCoin, error) {
 	var candidates []CandidateCoin
 	for _, symbol := range symbols {
 		candidates = append(candidates, CandidateCoin{
-			Symbol:  symbol,
+			Symbol:  e.rebaseQuote(symbol),
 			Sources: []string{"ai500"},
 		})
 	}
 	return candidates, nil
 }
 
+// rebaseQuote moves a USDT-listed symbol from an external ranking onto the configured quote asset
+func (e *StrategyEngine) rebaseQuote(symbol string) string {
+	if e.quoteAsset == "" {
+		return market.Normalize(symbol)
+	}
+	return market.WithQuote(symbol, e.quoteAsset)
+}
+
 func (e *StrategyEngine) getOITopCoins(limit int) ([]CandidateCoin, error) {
 	if limit <= 0 {
 		limit = 20
@@ -490,7 +776,7 @@ func (e *StrategyEngine) getOITopCoins(limit int) ([]CandidateCoin, error) {
 		if i >= limit {
 			break
 		}
-		symbol := market.Normalize(pos.Symbol)
+		symbol := e.rebaseQuote(pos.Symbol)
 		candidates = append(candidates, CandidateCoin{
 			Symbol:  symbol,
 			Sources: []string{"oi_top"},
@@ -499,6 +785,27 @@ func (e *StrategyEngine) getOITopCoins(limit int) ([]CandidateCoin, error) {
 	return candidates, nil
 }
 
+// getTopMoversCoins ranks all futures symbols by 1h/4h change and volume spike (see market.GetTopMovers)
+func (e *StrategyEngine) getTopMoversCoins(limit int) ([]CandidateCoin, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	movers, err := market.GetTopMovers(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]CandidateCoin, 0, len(movers))
+	for _, m := range movers {
+		candidates = append(candidates, CandidateCoin{
+			Symbol:  e.rebaseQuote(m.Symbol),
+			Sources: []string{"top_movers"},
+		})
+	}
+	return candidates, nil
+}
+
 // ============================================================================
 // External & Quant Data
 // ============================================================================
@@ -648,9 +955,20 @@ func (e *StrategyEngine) FetchQuantDataBatch(symbols []string) map[string]*Quant
 
 // BuildSystemPrompt builds System Prompt according to strategy configuration
 func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string) string {
+	return e.buildSystemPrompt(accountEquity, variant, e.config.CoinSource.StaticCoins)
+}
+
+// buildSystemPrompt builds System Prompt, symbols fill the {{.Symbols}} template variable of the editable sections
+func (e *StrategyEngine) buildSystemPrompt(accountEquity float64, variant string, symbols []string) string {
 	var sb strings.Builder
 	riskControl := e.config.RiskControl
-	promptSections := e.config.PromptSections
+	vars := e.promptVars(accountEquity, variant, symbols)
+	promptSections := store.PromptSectionsConfig{
+		RoleDefinition:   e.renderPrompt("role_definition", e.config.PromptSections.RoleDefinition, vars),
+		TradingFrequency: e.renderPrompt("trading_frequency", e.config.PromptSections.TradingFrequency, vars),
+		EntryStandards:   e.renderPrompt("entry_standards", e.config.PromptSections.EntryStandards, vars),
+		DecisionProcess:  e.renderPrompt("decision_process", e.config.PromptSections.DecisionProcess, vars),
+	}
 
 	// 1. Role definition (editable)
 	if promptSections.RoleDefinition != "" {
@@ -746,19 +1064,67 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 	sb.WriteString("```json\n[\n")
 	sb.WriteString(fmt.Sprintf("  {\"symbol\": \"BTCUSDT\", \"action\": \"open_short\", \"leverage\": %d, \"position_size_usd\": %.0f, \"stop_loss\": 97000, \"take_profit\": 91000, \"confidence\": 85, \"risk_usd\": 300},\n",
 		riskControl.BTCETHMaxLeverage, accountEquity*5))
-	sb.WriteString("  {\"symbol\": \"ETHUSDT\", \"action\": \"close_long\"}\n")
+	sb.WriteString("  {\"symbol\": \"ETHUSDT\", \"action\": \"close_long\", \"confidence\": 75}\n")
 	sb.WriteString("]\n```\n")
 	sb.WriteString("</decision>\n\n")
 	sb.WriteString("## Field Description\n\n")
-	sb.WriteString("- `action`: open_long | open_short | close_long | close_short | hold | wait\n")
-	sb.WriteString(fmt.Sprintf("- `confidence`: 0-100 (opening recommended ≥ %d)\n", riskControl.MinConfidence))
+	sb.WriteString("- `action`: open_long | open_short | add_long | add_short | close_long | close_short | partial_close_long | partial_close_short | manage_exit_long | manage_exit_short | alert | hold | wait\n")
+	sb.WriteString(fmt.Sprintf("- `confidence`: 0-100, required when opening or adding, optional on other actions (opening recommended ≥ %d); state it honestly, it is compared with your realized win rate\n", riskControl.MinConfidence))
 	sb.WriteString("- Required when opening: leverage, position_size_usd, stop_loss, take_profit, confidence, risk_usd\n")
+	sb.WriteString("- Optional when opening: trailing_stop_pct (0.1-10, trailing stop distance from the best price in %, lets winners run)\n")
+	sb.WriteString("- add_long / add_short scale into an existing position of the same direction (entry price becomes the weighted average)\n")
+	sb.WriteString("- Required when adding: position_size_usd (size of the added order); optional: stop_loss, take_profit (replace the protection of the whole position)\n")
+	sb.WriteString("- partial_close_long / partial_close_short take profit or cut risk on part of a position: close_pct (1-100, share of the position to close); optional: stop_loss, take_profit for the remainder\n")
+	sb.WriteString("- manage_exit_long / manage_exit_short hand the exit of an open position to a trailing stop checked every few seconds until it closes the position: managed_exit {\"mode\": \"chandelier\" (multiplier × ATR14 from the highest high / lowest low) | \"atr_trail\" (multiplier × ATR14 from the best close), \"atr_multiplier\": 0.5-10, \"timeframe\": optional ATR timeframe}; open_* also accept managed_exit. The stop only tightens, stop_loss / take_profit orders stay in place\n")
+	sb.WriteString("- alert sends a notification about a symbol without trading it: alert_type (breakout | breakdown | oi_surge | volume_spike | funding_shift | commentary), the alert text in reasoning. Use it sparingly, for notable setups only\n")
+	if len(e.config.CoinSource.WatchOnlyCoins) > 0 {
+		sb.WriteString("- Coins marked WATCH-ONLY are never traded: only alert / hold / wait are accepted for them, any other action is discarded\n")
+	}
+	if riskControl.TrailingStopPct > 0 {
+		sb.WriteString(fmt.Sprintf("- Default trailing stop: %.1f%% (applied when trailing_stop_pct is omitted)\n", riskControl.TrailingStopPct))
+	}
+	if riskControl.LiquidationGuardPct > 0 {
+		sb.WriteString(fmt.Sprintf("- Liquidation guard: positions within %.1f%% of their liquidation price can't be added to (no new entries at all under cross margin)",
+			riskControl.LiquidationGuardPct))
+		if riskControl.LiquidationDeleveragePct > 0 {
+			sb.WriteString(fmt.Sprintf(" and are reduced by %.0f%% automatically", riskControl.LiquidationDeleveragePct))
+		}
+		sb.WriteString("\n")
+	}
+	if riskControl.EventPauseMinutes > 0 {
+		sb.WriteString(fmt.Sprintf("- Event pause: no new entries from %d minutes before until %d minutes after a high-impact event affecting the symbol; don't propose entries inside that window\n",
+			riskControl.EventPauseMinutes, riskControl.EventPauseMinutes))
+	}
+	if riskControl.StopLossCooldownMinutes > 0 {
+		sb.WriteString(fmt.Sprintf("- Stop-loss cooldown: a symbol whose stop loss was hit can't be opened again for %d minutes; don't propose re-entries inside that window\n",
+			riskControl.StopLossCooldownMinutes))
+	}
+	if riskControl.MaxMarginRatioPct > 0 {
+		sb.WriteString(fmt.Sprintf("- Margin ratio limit: above %.0f%% maintenance margin / margin balance the largest losing position is reduced automatically; keep leverage and size well inside it\n",
+			riskControl.MaxMarginRatioPct))
+	}
+	if riskControl.RiskPerTradePct > 0 {
+		sb.WriteString(fmt.Sprintf("- position_size_usd of new positions is computed from the stop distance so hitting stop_loss loses %.2f%% of equity; place stop_loss where the trade idea is invalidated\n",
+			riskControl.RiskPerTradePct))
+	}
+	if riskControl.ATRStopMultiplier > 0 {
+		sb.WriteString(fmt.Sprintf("- stop_loss of new positions is replaced by entry ∓ %.2f × ATR14 (%s); keep take_profit consistent with that distance\n",
+			riskControl.ATRStopMultiplier, e.config.Indicators.Klines.PrimaryTimeframe))
+	}
+	if riskControl.VolTargetATRPct > 0 {
+		sb.WriteString(fmt.Sprintf("- leverage of new positions is set from volatility: max leverage × %.2f%% / ATR14%% of price (%s), at least 1x; size positions for that leverage\n",
+			riskControl.VolTargetATRPct, e.config.Indicators.Klines.PrimaryTimeframe))
+	}
+	if riskControl.ExpectedValueGate {
+		sb.WriteString(fmt.Sprintf("- New positions are skipped when expected value is below %.2f%% of notional: your past win rate at the stated confidence × take_profit distance - loss rate × stop_loss distance - fees and funding; state confidence honestly and set realistic targets\n",
+			riskControl.MinExpectedValuePct))
+	}
 	sb.WriteString("- **IMPORTANT**: All numeric values must be calculated numbers, NOT formulas/expressions (e.g., use `27.76` not `3000 * 0.01`)\n\n")
 
 	// 8. Custom Prompt
 	if e.config.CustomPrompt != "" {
 		sb.WriteString("# 📌 Personalized Trading Strategy\n\n")
-		sb.WriteString(e.config.CustomPrompt)
+		sb.WriteString(e.renderPrompt("custom_prompt", e.config.CustomPrompt, vars))
 		sb.WriteString("\n\n")
 		sb.WriteString("Note: The above personalized strategy is a supplement to the basic rules and cannot violate the basic risk control principles.\n")
 	}
@@ -789,12 +1155,20 @@ func (e *StrategyEngine) writeAvailableIndicators(sb *strings.Builder) {
 		sb.WriteString("- MACD indicators\n")
 	}
 
+	if indicators.EnableBollinger {
+		sb.WriteString("- Bollinger Bands (20, 2σ) with %B (0 = lower band, 1 = upper band) and band width (low = squeeze)\n")
+	}
+
+	if indicators.EnableVWAP {
+		sb.WriteString("- VWAP (rolling 24h session and anchored at the daily open 00:00 UTC) with the price's deviation from each\n")
+	}
+
 	if indicators.EnableRSI {
 		sb.WriteString("- RSI indicators")
 		if len(indicators.RSIPeriods) > 0 {
 			sb.WriteString(fmt.Sprintf(" (periods: %v)", indicators.RSIPeriods))
 		}
-		sb.WriteString("\n")
+		sb.WriteString(", Stochastic RSI (14, 14, 3, 3) and RSI divergence flags (swing lows / highs of the last 30 candles)\n")
 	}
 
 	if indicators.EnableATR {
@@ -806,20 +1180,41 @@ func (e *StrategyEngine) writeAvailableIndicators(sb *strings.Builder) {
 	}
 
 	if indicators.EnableVolume {
-		sb.WriteString("- Volume data\n")
+		sb.WriteString("- Volume data and volume profile (POC, 70% value area, high-volume nodes as support / resistance)\n")
 	}
 
 	if indicators.EnableOI {
-		sb.WriteString("- Open Interest (OI) data\n")
+		sb.WriteString("- Open Interest (OI) data (1h/4h/24h change, OI-vs-price divergence)\n")
 	}
 
 	if indicators.EnableFundingRate {
-		sb.WriteString("- Funding rate\n")
+		sb.WriteString("- Funding rate (predicted next, last 8 settlements, average and trend)\n")
+	}
+
+	if indicators.EnableOrderBook {
+		sb.WriteString("- Order book (bid/ask spread, top-10 depth, bid/ask imbalance)\n")
+	}
+
+	if indicators.EnableWeeklyContext {
+		sb.WriteString("- Weekly (1w) context (trend, EMA20/50, ATR14, recent weekly closes) for the macro trend\n")
+	}
+
+	if indicators.EnableEventCalendar {
+		sb.WriteString("- Upcoming high-impact events (FOMC, CPI and other US macro releases, token unlocks): volatility spikes and gaps around them, size down or wait\n")
 	}
 
+	sb.WriteString("- Recent liquidations (longs/shorts liquidated and bursts, only when there were any)\n")
+	sb.WriteString("- 24h statistics (change, high/low and where price sits in that range, volume, quote volume, trades)\n")
+	sb.WriteString(fmt.Sprintf("- Mark / index basis (premium index) and last vs mark spread; above %.1f%% it is flagged BASIS BLOWOUT, don't open market entries there since they fill far from mark\n", market.BasisBlowoutPct))
+	sb.WriteString("- Market overview (total crypto market cap, BTC/ETH dominance, BTC/ETH 24h change): weigh altcoin setups against the macro picture\n")
+	sb.WriteString("- Crypto Fear & Greed index (0 extreme fear - 100 extreme greed) with its last days: extremes often precede reversals\n")
+
 	if len(e.config.CoinSource.StaticCoins) > 0 || e.config.CoinSource.UseCoinPool || e.config.CoinSource.UseOITop {
 		sb.WriteString("- AI500 / OI_Top filter tags (if available)\n")
 	}
+	if e.config.CoinSource.SourceType == "top_movers" || e.config.CoinSource.UseTopMovers {
+		sb.WriteString("- Top_Movers tag: ranked among all futures by 1h/4h change and volume spike, momentum may already be extended\n")
+	}
 
 	if indicators.EnableQuantData {
 		sb.WriteString("- Quantitative data (institutional/retail fund flow, position changes, multi-period price changes)\n")
@@ -845,6 +1240,37 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 			btcData.CurrentMACD, btcData.CurrentRSI7))
 	}
 
+	// Degraded mode: stale feeds must not be mistaken for current prices
+	if len(ctx.StaleSymbols) > 0 {
+		sb.WriteString(fmt.Sprintf("⚠️ Degraded market data: %s prices are stale (marked STALE below), treat them as outdated and don't add to these positions on them\n\n",
+			strings.Join(ctx.StaleSymbols, ", ")))
+	}
+
+	// Global market context
+	if ctx.MarketOverview != nil || ctx.FearGreed != nil {
+		sb.WriteString("## Market Overview\n")
+		if ctx.MarketOverview != nil {
+			sb.WriteString(ctx.MarketOverview.Summary() + "\n")
+		}
+		if ctx.FearGreed != nil {
+			sb.WriteString("Fear & Greed: " + ctx.FearGreed.Summary() + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	// Event calendar
+	if len(ctx.UpcomingEvents) > 0 {
+		now := time.Now()
+		sb.WriteString(fmt.Sprintf("## Upcoming Events (next %s)\n", ctx.EventWindow))
+		for _, event := range ctx.UpcomingEvents {
+			sb.WriteString("- " + event.Describe(now) + "\n")
+		}
+		if ctx.EventPause > 0 {
+			sb.WriteString(fmt.Sprintf("New entries on affected symbols are blocked from %s before until %s after each event\n", ctx.EventPause, ctx.EventPause))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Account information
 	sb.WriteString(fmt.Sprintf("Account: Equity %.2f | Balance %.2f (%.1f%%) | PnL %+.2f%% | Margin %.1f%% | Positions %d\n\n",
 		ctx.Account.TotalEquity,
@@ -862,15 +1288,26 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 			if order.RealizedPnL < 0 {
 				resultStr = "Loss"
 			}
-			sb.WriteString(fmt.Sprintf("%d. %s %s | Entry %.4f Exit %.4f | %s: %+.2f USDT (%+.2f%%) | %s→%s (%s)\n",
+			seededTag := ""
+			if order.Seeded {
+				seededTag = " [seeded]"
+			}
+			sb.WriteString(fmt.Sprintf("%d. %s %s | Entry %.4f Exit %.4f | %s: %+.2f USDT (%+.2f%%) | %s→%s (%s)%s\n",
 				i+1, order.Symbol, order.Side,
 				order.EntryPrice, order.ExitPrice,
 				resultStr, order.RealizedPnL, order.PnLPct,
-				order.EntryTime, order.ExitTime, order.HoldDuration))
+				order.EntryTime, order.ExitTime, order.HoldDuration, seededTag))
+		}
+		if ctx.PerformanceSeed != "" {
+			sb.WriteString(fmt.Sprintf("Trades marked [seeded] come from %s, not from this trader's live account; they are replaced by live trades as those accumulate\n",
+				ctx.PerformanceSeed))
 		}
 		sb.WriteString("\n")
 	}
 
+	// Past trades entered in similar situations (trade memory)
+	writeSimilarTrades(&sb, ctx)
+
 	// Position information
 	if len(ctx.Positions) > 0 {
 		sb.WriteString("## Current Positions\n")
@@ -893,6 +1330,11 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 
 		sourceTags := e.formatCoinSourceTag(coin.Sources)
 		sb.WriteString(fmt.Sprintf("### %d. %s%s\n\n", displayedCount, coin.Symbol, sourceTags))
+		if meta, ok := ctx.SymbolMetaMap[coin.Symbol]; ok {
+			sb.WriteString(formatSymbolMeta(meta, time.Now()))
+		}
+		sb.WriteString(formatSymbolEvents(ctx.SymbolEvents[coin.Symbol], time.Now()))
+		sb.WriteString(e.formatSymbolPrompt(coin.Symbol))
 		sb.WriteString(e.formatMarketData(marketData))
 
 		if ctx.QuantDataMap != nil {
@@ -910,6 +1352,27 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 	return sb.String()
 }
 
+// formatSymbolEvents the symbol's own upcoming events (market-wide ones are listed once under Upcoming Events)
+func formatSymbolEvents(symbolEvents []events.Event, now time.Time) string {
+	var sb strings.Builder
+	for _, event := range symbolEvents {
+		sb.WriteString("📅 Event: " + event.Describe(now) + "\n\n")
+	}
+	return sb.String()
+}
+
+// formatSymbolMeta one line of contract metadata for the prompt
+func formatSymbolMeta(meta *market.SymbolMeta, now time.Time) string {
+	parts := []string{"Category: " + meta.Category}
+	if age := meta.ListingAgeDays(now); age >= 0 {
+		parts = append(parts, fmt.Sprintf("Listed %s (%d days ago)", meta.LaunchDate.Format("2006-01-02"), age))
+	}
+	if meta.MaxLeverage > 0 {
+		parts = append(parts, fmt.Sprintf("Max leverage %dx", meta.MaxLeverage))
+	}
+	return strings.Join(parts, " | ") + "\n\n"
+}
+
 func (e *StrategyEngine) formatPositionInfo(index int, pos PositionInfo, ctx *Context) string {
 	var sb strings.Builder
 
@@ -931,10 +1394,27 @@ func (e *StrategyEngine) formatPositionInfo(index int, pos PositionInfo, ctx *Co
 		positionValue = -positionValue
 	}
 
-	sb.WriteString(fmt.Sprintf("%d. %s %s | Entry %.4f Current %.4f | Qty %.4f | Position Value %.2f USDT | PnL%+.2f%% | PnL Amount%+.2f USDT | Peak PnL%.2f%% | Leverage %dx | Margin %.0f | Liq Price %.4f%s\n\n",
+	var tick float64
+	if marketData, ok := ctx.MarketDataMap[pos.Symbol]; ok {
+		tick = marketData.TickSize
+	}
+	sb.WriteString(fmt.Sprintf("%d. %s %s | Entry %s Current %s | Qty %.4f | Position Value %.2f USDT | PnL%+.2f%% | PnL Amount%+.2f USDT | Peak PnL%.2f%% | Leverage %dx | Margin %.0f | Liq Price %s%s\n\n",
 		index, pos.Symbol, strings.ToUpper(pos.Side),
-		pos.EntryPrice, pos.MarkPrice, pos.Quantity, positionValue, pos.UnrealizedPnLPct, pos.UnrealizedPnL, pos.PeakPnLPct,
-		pos.Leverage, pos.MarginUsed, pos.LiquidationPrice, holdingDuration))
+		market.FormatPriceTick(pos.EntryPrice, tick), market.FormatPriceTick(pos.MarkPrice, tick), pos.Quantity, positionValue, pos.UnrealizedPnLPct, pos.UnrealizedPnL, pos.PeakPnLPct,
+		pos.Leverage, pos.MarginUsed, market.FormatPriceTick(pos.LiquidationPrice, tick), holdingDuration))
+	if ctx.ReviewAge > 0 && pos.UpdateTime > 0 && time.Since(time.UnixMilli(pos.UpdateTime)) > ctx.ReviewAge {
+		sb.WriteString(fmt.Sprintf("⏰ Held beyond the max holding time (%s): review this position now, close it unless the reasoning gives a fresh case for holding\n\n",
+			ctx.ReviewAge))
+	}
+	if ctx.LiqGuardPct > 0 && pos.LiquidationPrice > 0 && pos.LiquidationDist < ctx.LiqGuardPct {
+		sb.WriteString(fmt.Sprintf("🚨 Mark price only %.2f%% from liquidation (guard %.2f%%): adding is blocked, reduce or close this position\n\n",
+			pos.LiquidationDist, ctx.LiqGuardPct))
+	}
+	sb.WriteString(formatSymbolEvents(ctx.SymbolEvents[pos.Symbol], time.Now()))
+	sb.WriteString(e.formatSymbolPrompt(pos.Symbol))
+	if pos.ManagedExit != "" {
+		sb.WriteString(fmt.Sprintf("Managed exit active: %s (manage_exit_* again to change it, close_* to exit now)\n\n", pos.ManagedExit))
+	}
 
 	if marketData, ok := ctx.MarketDataMap[pos.Symbol]; ok {
 		sb.WriteString(e.formatMarketData(marketData))
@@ -951,14 +1431,34 @@ func (e *StrategyEngine) formatPositionInfo(index int, pos PositionInfo, ctx *Co
 }
 
 func (e *StrategyEngine) formatCoinSourceTag(sources []string) string {
+	if (CandidateCoin{Sources: sources}).WatchOnly() {
+		return " (WATCH-ONLY: alert / hold / wait only, no orders)"
+	}
 	if len(sources) > 1 {
-		return " (AI500+OI_Top dual signal)"
+		names := map[string]string{"ai500": "AI500", "oi_top": "OI_Top", "top_movers": "Top_Movers", "static": "Manual"}
+		tags := make([]string, 0, len(sources))
+		for _, source := range sources {
+			if name, ok := names[source]; ok {
+				tags = append(tags, name)
+			}
+		}
+		switch {
+		case len(tags) == 2:
+			return fmt.Sprintf(" (%s dual signal)", strings.Join(tags, "+"))
+		case len(tags) > 2:
+			return fmt.Sprintf(" (%s multi-signal)", strings.Join(tags, "+"))
+		}
+		if len(tags) == 1 {
+			return " (" + tags[0] + ")"
+		}
 	} else if len(sources) == 1 {
 		switch sources[0] {
 		case "ai500":
 			return " (AI500)"
 		case "oi_top":
 			return " (OI_Top position growth)"
+		case "top_movers":
+			return " (Top_Movers momentum / volume spike)"
 		case "static":
 			return " (Manual selection)"
 		}
@@ -976,7 +1476,10 @@ func (e *StrategyEngine) formatMarketDataCompact(data *market.Data) string {
 	var parts []string
 	indicators := e.config.Indicators
 
-	parts = append(parts, fmt.Sprintf("price=%.4f", data.CurrentPrice))
+	parts = append(parts, "price="+data.FormatPrice(data.CurrentPrice))
+	if data.Stale {
+		parts = append(parts, fmt.Sprintf("STALE(%v)", data.DataAge.Round(time.Second)))
+	}
 
 	if indicators.EnableEMA {
 		// Calculate trend based on price vs EMA
@@ -999,6 +1502,18 @@ func (e *StrategyEngine) formatMarketDataCompact(data *market.Data) string {
 		parts = append(parts, fmt.Sprintf("macd=%s", macdSignal))
 	}
 
+	if indicators.EnableBollinger && data.Bollinger != nil {
+		parts = append(parts, fmt.Sprintf("bb_%%b=%.2f, bb_width=%.2f%%", data.Bollinger.PercentB, data.Bollinger.BandWidth))
+	}
+
+	if indicators.EnableVWAP && data.VWAP != nil {
+		parts = append(parts, fmt.Sprintf("vwap_dev=%+.2f%%, avwap_dev=%+.2f%%", data.VWAP.SessionDevPct, data.VWAP.AnchoredDevPct))
+	}
+
+	if indicators.EnableVolume && data.VolumeProfile != nil {
+		parts = append(parts, fmt.Sprintf("poc=%.4f, va=%.4f-%.4f", data.VolumeProfile.POC, data.VolumeProfile.ValueAreaLow, data.VolumeProfile.ValueAreaHigh))
+	}
+
 	if indicators.EnableRSI {
 		rsiSignal := "neutral"
 		if data.CurrentRSI7 > 70 {
@@ -1007,6 +1522,15 @@ func (e *StrategyEngine) formatMarketDataCompact(data *market.Data) string {
 			rsiSignal = "oversold"
 		}
 		parts = append(parts, fmt.Sprintf("rsi=%.1f(%s)", data.CurrentRSI7, rsiSignal))
+		if data.StochRSI != nil {
+			parts = append(parts, fmt.Sprintf("stoch_rsi=%.0f/%.0f", data.StochRSI.K, data.StochRSI.D))
+		}
+		if data.RSIBullishDivergence {
+			parts = append(parts, "rsi_div=bullish")
+		}
+		if data.RSIBearishDivergence {
+			parts = append(parts, "rsi_div=bearish")
+		}
 	}
 
 	if indicators.EnableOI && data.OpenInterest != nil {
@@ -1016,7 +1540,20 @@ func (e *StrategyEngine) formatMarketDataCompact(data *market.Data) string {
 		} else if data.OpenInterest.Latest < data.OpenInterest.Average*0.95 {
 			oiChange = "-falling"
 		}
-		parts = append(parts, fmt.Sprintf("oi=%s", oiChange))
+		oi := fmt.Sprintf("oi=%s", oiChange)
+		if data.OpenInterest.HasChange1h {
+			oi += fmt.Sprintf(", oi_1h=%+.2f%%", data.OpenInterest.Change1h)
+		}
+		if data.OpenInterest.HasChange4h {
+			oi += fmt.Sprintf(", oi_4h=%+.2f%%", data.OpenInterest.Change4h)
+		}
+		if data.OpenInterest.HasChange24h {
+			oi += fmt.Sprintf(", oi_24h=%+.2f%%", data.OpenInterest.Change24h)
+		}
+		if data.OpenInterest.Divergence != "" {
+			oi += fmt.Sprintf(", oi_div=%s", data.OpenInterest.Divergence)
+		}
+		parts = append(parts, oi)
 	}
 
 	if indicators.EnableFundingRate {
@@ -1026,7 +1563,23 @@ func (e *StrategyEngine) formatMarketDataCompact(data *market.Data) string {
 		} else if data.FundingRate < -0.0005 {
 			frSignal = "high_short"
 		}
-		parts = append(parts, fmt.Sprintf("fr=%.4f%%(%s)", data.FundingRate*100, frSignal))
+		fr := fmt.Sprintf("fr=%.4f%%(%s)", data.FundingRate*100, frSignal)
+		if data.FundingHistory != nil && len(data.FundingHistory.Settled) > 0 {
+			fr += fmt.Sprintf(", fr_avg%d=%.4f%%(%s)", len(data.FundingHistory.Settled), data.FundingHistory.Average()*100, data.FundingHistory.Trend())
+		}
+		parts = append(parts, fr)
+	}
+
+	if indicators.EnableOrderBook && data.OrderBook != nil {
+		parts = append(parts, fmt.Sprintf("spread=%.1fbps, imb=%+.2f", data.OrderBook.SpreadBps, data.OrderBook.Imbalance))
+	}
+
+	if indicators.EnableWeeklyContext && data.WeeklyContext != nil {
+		parts = append(parts, fmt.Sprintf("1w=%s", data.WeeklyContext.Trend))
+	}
+
+	if data.Liquidations != nil && data.Liquidations.Burst {
+		parts = append(parts, fmt.Sprintf("liq_burst(long=%.0f, short=%.0f)", data.Liquidations.LongNotional, data.Liquidations.ShortNotional))
 	}
 
 	// Add price changes if available
@@ -1036,6 +1589,17 @@ func (e *StrategyEngine) formatMarketDataCompact(data *market.Data) string {
 	if data.PriceChange4h != 0 {
 		parts = append(parts, fmt.Sprintf("4h=%+.2f%%", data.PriceChange4h))
 	}
+	if data.Ticker24h != nil {
+		parts = append(parts, fmt.Sprintf("24h=%+.2f%%", data.PriceChange24h),
+			fmt.Sprintf("24h_range_pos=%.0f%%", data.Ticker24h.RangePosition()*100),
+			fmt.Sprintf("24h_qvol=%.1fM", data.Ticker24h.QuoteVolume/1e6))
+	}
+	if data.MarkPrice != nil {
+		parts = append(parts, fmt.Sprintf("basis=%+.3f%%", data.MarkPrice.BasisPct))
+		if data.MarkPrice.Blowout() {
+			parts = append(parts, fmt.Sprintf("BASIS_BLOWOUT(last_vs_mark=%+.3f%%)", data.MarkPrice.LastMarkPct))
+		}
+	}
 
 	return strings.Join(parts, ", ")
 }
@@ -1049,7 +1613,10 @@ func (e *StrategyEngine) formatMarketData(data *market.Data) string {
 		return e.formatMarketDataCompact(data)
 	}
 
-	sb.WriteString(fmt.Sprintf("current_price = %.4f", data.CurrentPrice))
+	if data.Stale {
+		sb.WriteString(fmt.Sprintf("⚠️ STALE: no market data update for %v, prices below may be outdated\n", data.DataAge.Round(time.Second)))
+	}
+	sb.WriteString("current_price = " + data.FormatPrice(data.CurrentPrice))
 
 	if indicators.EnableEMA {
 		sb.WriteString(fmt.Sprintf(", current_ema20 = %.3f", data.CurrentEMA20))
@@ -1065,25 +1632,71 @@ func (e *StrategyEngine) formatMarketData(data *market.Data) string {
 
 	sb.WriteString("\n\n")
 
+	if indicators.EnableRSI {
+		if data.StochRSI != nil {
+			sb.WriteString(fmt.Sprintf("Stochastic RSI (14, 14, 3, 3): %s\n\n", data.StochRSI.Summary()))
+		}
+		if data.RSIBullishDivergence || data.RSIBearishDivergence {
+			sb.WriteString(fmt.Sprintf("RSI divergence (last 30 candles): bullish %v, bearish %v\n\n",
+				data.RSIBullishDivergence, data.RSIBearishDivergence))
+		}
+	}
+
+	if indicators.EnableBollinger && data.Bollinger != nil {
+		sb.WriteString(fmt.Sprintf("Bollinger Bands (20, 2σ): %s\n\n", data.Bollinger.Summary()))
+	}
+
+	if indicators.EnableVWAP && data.VWAP != nil {
+		sb.WriteString(fmt.Sprintf("VWAP: %s\n\n", data.VWAP.Summary()))
+	}
+
+	if indicators.EnableVolume && data.VolumeProfile != nil {
+		sb.WriteString(fmt.Sprintf("Volume profile: %s\n\n", data.VolumeProfile.Summary()))
+	}
+
 	if indicators.EnableOI || indicators.EnableFundingRate {
 		sb.WriteString(fmt.Sprintf("Additional data for %s:\n\n", data.Symbol))
 
 		if indicators.EnableOI && data.OpenInterest != nil {
 			sb.WriteString(fmt.Sprintf("Open Interest: Latest: %.2f Average: %.2f\n\n",
 				data.OpenInterest.Latest, data.OpenInterest.Average))
+			sb.WriteString(fmt.Sprintf("Open Interest change: %s\n\n", data.OpenInterest.Summary()))
 		}
 
 		if indicators.EnableFundingRate {
 			sb.WriteString(fmt.Sprintf("Funding Rate: %.2e\n\n", data.FundingRate))
+			if data.FundingHistory != nil && len(data.FundingHistory.Settled) > 0 {
+				sb.WriteString(fmt.Sprintf("Funding Rate history: %s\n\n", data.FundingHistory.Summary()))
+			}
 		}
 	}
 
+	if indicators.EnableOrderBook && data.OrderBook != nil {
+		sb.WriteString(fmt.Sprintf("Order Book: %s\n\n", data.OrderBook.Summary()))
+	}
+
+	if data.Liquidations != nil {
+		sb.WriteString(fmt.Sprintf("Liquidations (last %dm): %s\n\n", data.Liquidations.WindowMinutes, data.Liquidations.Summary()))
+	}
+
+	if data.Ticker24h != nil {
+		sb.WriteString(fmt.Sprintf("24h stats: %s\n\n", data.Ticker24h.Summary()))
+	}
+
+	if data.MarkPrice != nil {
+		sb.WriteString(fmt.Sprintf("Mark / index: %s\n\n", data.MarkPrice.Summary()))
+	}
+
+	if indicators.EnableWeeklyContext && data.WeeklyContext != nil {
+		sb.WriteString(fmt.Sprintf("Weekly context (1w): %s\n\n", data.WeeklyContext.Summary()))
+	}
+
 	if len(data.TimeframeData) > 0 {
 		timeframeOrder := []string{"1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h", "1d", "3d", "1w"}
 		for _, tf := range timeframeOrder {
 			if tfData, ok := data.TimeframeData[tf]; ok {
 				sb.WriteString(fmt.Sprintf("=== %s Timeframe (oldest → latest) ===\n\n", strings.ToUpper(tf)))
-				e.formatTimeframeSeriesData(&sb, tfData, indicators)
+				e.formatTimeframeSeriesData(&sb, tfData, data.TickSize, indicators)
 			}
 		}
 	} else {
@@ -1093,7 +1706,7 @@ func (e *StrategyEngine) formatMarketData(data *market.Data) string {
 			sb.WriteString(fmt.Sprintf("Intraday series (%s intervals, oldest → latest):\n\n", klineConfig.PrimaryTimeframe))
 
 			if len(data.IntradaySeries.MidPrices) > 0 {
-				sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", formatFloatSlice(data.IntradaySeries.MidPrices)))
+				sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", formatPriceSlice(data.IntradaySeries.MidPrices, data.TickSize)))
 			}
 
 			if indicators.EnableEMA && len(data.IntradaySeries.EMA20Values) > 0 {
@@ -1153,7 +1766,7 @@ func (e *StrategyEngine) formatMarketData(data *market.Data) string {
 	return sb.String()
 }
 
-func (e *StrategyEngine) formatTimeframeSeriesData(sb *strings.Builder, data *market.TimeframeSeriesData, indicators store.IndicatorConfig) {
+func (e *StrategyEngine) formatTimeframeSeriesData(sb *strings.Builder, data *market.TimeframeSeriesData, tick float64, indicators store.IndicatorConfig) {
 	if len(data.Klines) > 0 {
 		sb.WriteString("Time(UTC)      Open      High      Low       Close     Volume\n")
 		for i, k := range data.Klines {
@@ -1163,12 +1776,13 @@ func (e *StrategyEngine) formatTimeframeSeriesData(sb *strings.Builder, data *ma
 			if i == len(data.Klines)-1 {
 				marker = "  <- current"
 			}
-			sb.WriteString(fmt.Sprintf("%-14s %-9.4f %-9.4f %-9.4f %-9.4f %-12.2f%s\n",
-				timeStr, k.Open, k.High, k.Low, k.Close, k.Volume, marker))
+			sb.WriteString(fmt.Sprintf("%-14s %-9s %-9s %-9s %-9s %-12.2f%s\n",
+				timeStr, market.FormatPriceTick(k.Open, tick), market.FormatPriceTick(k.High, tick), market.FormatPriceTick(k.Low, tick),
+				market.FormatPriceTick(k.Close, tick), k.Volume, marker))
 		}
 		sb.WriteString("\n")
 	} else if len(data.MidPrices) > 0 {
-		sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", formatFloatSlice(data.MidPrices)))
+		sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", formatPriceSlice(data.MidPrices, tick)))
 		if indicators.EnableVolume && len(data.Volume) > 0 {
 			sb.WriteString(fmt.Sprintf("Volume: %s\n\n", formatFloatSlice(data.Volume)))
 		}
@@ -1315,10 +1929,25 @@ func formatFloatSlice(values []float64) string {
 	return "[" + strings.Join(strValues, ", ") + "]"
 }
 
+// formatPriceSlice formats prices on the symbol's tick grid
+func formatPriceSlice(values []float64, tick float64) string {
+	strValues := make([]string, len(values))
+	for i, v := range values {
+		strValues[i] = market.FormatPriceTick(v, tick)
+	}
+	return "[" + strings.Join(strValues, ", ") + "]"
+}
+
 // ============================================================================
 // AI Response Parsing
 // ============================================================================
 
+// ParseFullDecisionResponse parses and validates a raw AI response against the given risk limits
+// Used outside the trading loop (e.g. model benchmarking) to check output validity
+func ParseFullDecisionResponse(aiResponse string, accountEquity float64, btcEthLeverage, altcoinLeverage int) (*FullDecision, error) {
+	return parseFullDecisionResponse(aiResponse, accountEquity, btcEthLeverage, altcoinLeverage)
+}
+
 func parseFullDecisionResponse(aiResponse string, accountEquity float64, btcEthLeverage, altcoinLeverage int) (*FullDecision, error) {
 	cotTrace := extractCoTTrace(aiResponse)
 
@@ -1379,48 +2008,33 @@ func extractDecisions(response string) ([]Decision, error) {
 
 	jsonPart = fixMissingQuotes(jsonPart)
 
+	var jsonContent string
 	if m := reJSONFence.FindStringSubmatch(jsonPart); m != nil && len(m) > 1 {
-		jsonContent := strings.TrimSpace(m[1])
-		jsonContent = compactArrayOpen(jsonContent)
-		jsonContent = fixMissingQuotes(jsonContent)
-		if err := validateJSONFormat(jsonContent); err != nil {
-			return nil, fmt.Errorf("JSON format validation failed: %w\nJSON content: %s\nFull response:\n%s", err, jsonContent, response)
-		}
-		var decisions []Decision
-		if err := json.Unmarshal([]byte(jsonContent), &decisions); err != nil {
-			return nil, fmt.Errorf("JSON parsing failed: %w\nJSON content: %s", err, jsonContent)
-		}
-		return decisions, nil
+		jsonContent = strings.TrimSpace(m[1])
+	} else {
+		jsonContent = strings.TrimSpace(reJSONArray.FindString(jsonPart))
 	}
-
-	jsonContent := strings.TrimSpace(reJSONArray.FindString(jsonPart))
 	if jsonContent == "" {
-		logger.Infof("⚠️  [SafeFallback] AI didn't output JSON decision, entering safe wait mode")
-
-		cotSummary := jsonPart
-		if len(cotSummary) > 240 {
-			cotSummary = cotSummary[:240] + "..."
+		if strings.TrimSpace(jsonPart) == "[]" {
+			return []Decision{}, nil
 		}
-
-		fallbackDecision := Decision{
-			Symbol:    "ALL",
-			Action:    "wait",
-			Reasoning: fmt.Sprintf("Model didn't output structured JSON decision, entering safe wait; summary: %s", cotSummary),
-		}
-
-		return []Decision{fallbackDecision}, nil
+		logger.Infof("⚠️  AI didn't output a JSON decision array")
+		return nil, &SchemaError{Violations: []string{"no JSON decision array found, expected <decision>[{...}]</decision>"}}
 	}
 
 	jsonContent = compactArrayOpen(jsonContent)
 	jsonContent = fixMissingQuotes(jsonContent)
 
 	if err := validateJSONFormat(jsonContent); err != nil {
-		return nil, fmt.Errorf("JSON format validation failed: %w\nJSON content: %s\nFull response:\n%s", err, jsonContent, response)
+		return nil, &SchemaError{Violations: []string{err.Error()}}
+	}
+	if violations := ValidateDecisionJSON(jsonContent); len(violations) > 0 {
+		return nil, &SchemaError{Violations: violations}
 	}
 
 	var decisions []Decision
 	if err := json.Unmarshal([]byte(jsonContent), &decisions); err != nil {
-		return nil, fmt.Errorf("JSON parsing failed: %w\nJSON content: %s", err, jsonContent)
+		return nil, &SchemaError{Violations: []string{fmt.Sprintf("JSON parsing failed: %v", err)}}
 	}
 
 	return decisions, nil
@@ -1505,24 +2119,56 @@ func validateDecisions(decisions []Decision, accountEquity float64, btcEthLevera
 	return nil
 }
 
+// Minimum opening amounts (USDT), also applied to scale-in orders
+const (
+	minPositionSizeGeneral = 12.0
+	minPositionSizeBTCETH  = 60.0
+)
+
 func validateDecision(d *Decision, accountEquity float64, btcEthLeverage, altcoinLeverage int) error {
 	validActions := map[string]bool{
-		"open_long":   true,
-		"open_short":  true,
-		"close_long":  true,
-		"close_short": true,
-		"hold":        true,
-		"wait":        true,
+		"open_long":           true,
+		"open_short":          true,
+		"add_long":            true,
+		"add_short":           true,
+		"close_long":          true,
+		"close_short":         true,
+		"partial_close_long":  true,
+		"partial_close_short": true,
+		"manage_exit_long":    true,
+		"manage_exit_short":   true,
+		ActionAlert:           true,
+		"hold":                true,
+		"wait":                true,
 	}
 
 	if !validActions[d.Action] {
 		return fmt.Errorf("invalid action: %s", d.Action)
 	}
 
+	if d.Action == ActionAlert {
+		return validateAlertDecision(d)
+	}
+
+	if d.Action == "add_long" || d.Action == "add_short" {
+		return validateAddDecision(d, accountEquity, btcEthLeverage, altcoinLeverage)
+	}
+
+	if d.Action == "partial_close_long" || d.Action == "partial_close_short" {
+		return validatePartialCloseDecision(d)
+	}
+
+	if d.Action == "manage_exit_long" || d.Action == "manage_exit_short" {
+		if d.ManagedExit == nil {
+			return fmt.Errorf("managed_exit is required for %s", d.Action)
+		}
+		return d.ManagedExit.Validate()
+	}
+
 	if d.Action == "open_long" || d.Action == "open_short" {
 		maxLeverage := altcoinLeverage
 		maxPositionValue := accountEquity * 1.5
-		if d.Symbol == "BTCUSDT" || d.Symbol == "ETHUSDT" {
+		if market.IsBTCETH(d.Symbol) {
 			maxLeverage = btcEthLeverage
 			maxPositionValue = accountEquity * 10
 		}
@@ -1539,10 +2185,7 @@ func validateDecision(d *Decision, accountEquity float64, btcEthLeverage, altcoi
 			return fmt.Errorf("position size must be greater than 0: %.2f", d.PositionSizeUSD)
 		}
 
-		const minPositionSizeGeneral = 12.0
-		const minPositionSizeBTCETH = 60.0
-
-		if d.Symbol == "BTCUSDT" || d.Symbol == "ETHUSDT" {
+		if market.IsBTCETH(d.Symbol) {
 			if d.PositionSizeUSD < minPositionSizeBTCETH {
 				return fmt.Errorf("%s opening amount too small (%.2f USDT), must be ≥%.2f USDT", d.Symbol, d.PositionSizeUSD, minPositionSizeBTCETH)
 			}
@@ -1554,7 +2197,7 @@ func validateDecision(d *Decision, accountEquity float64, btcEthLeverage, altcoi
 
 		tolerance := maxPositionValue * 0.01
 		if d.PositionSizeUSD > maxPositionValue+tolerance {
-			if d.Symbol == "BTCUSDT" || d.Symbol == "ETHUSDT" {
+			if market.IsBTCETH(d.Symbol) {
 				return fmt.Errorf("BTC/ETH single coin position value cannot exceed %.0f USDT (10x account equity), actual: %.0f", maxPositionValue, d.PositionSizeUSD)
 			} else {
 				return fmt.Errorf("altcoin single coin position value cannot exceed %.0f USDT (1.5x account equity), actual: %.0f", maxPositionValue, d.PositionSizeUSD)
@@ -1563,6 +2206,19 @@ func validateDecision(d *Decision, accountEquity float64, btcEthLeverage, altcoi
 		if d.StopLoss <= 0 || d.TakeProfit <= 0 {
 			return fmt.Errorf("stop loss and take profit must be greater than 0")
 		}
+		if d.TrailingStopPct < 0 {
+			return fmt.Errorf("trailing stop percentage must not be negative: %.2f", d.TrailingStopPct)
+		}
+		if d.TrailingStopPct > 10 {
+			// Exchange callback rate range is 0.1%-10%
+			logger.Infof("⚠️  [Trailing Stop Fallback] %s trailing stop %.2f%% too wide, auto-adjusting to 10%%", d.Symbol, d.TrailingStopPct)
+			d.TrailingStopPct = 10
+		}
+		if d.ManagedExit != nil {
+			if err := d.ManagedExit.Validate(); err != nil {
+				return err
+			}
+		}
 
 		if d.Action == "open_long" {
 			if d.StopLoss >= d.TakeProfit {
@@ -1604,3 +2260,72 @@ func validateDecision(d *Decision, accountEquity float64, btcEthLeverage, altcoi
 
 	return nil
 }
+
+// validateAddDecision validates a scale-in (add_long/add_short) into an existing position
+// Leverage is optional, the existing position's leverage is kept. Stop loss / take profit are optional,
+// when given they replace the protection of the whole position
+func validateAddDecision(d *Decision, accountEquity float64, btcEthLeverage, altcoinLeverage int) error {
+	maxLeverage := altcoinLeverage
+	maxPositionValue := accountEquity * 1.5
+	minPositionSize := minPositionSizeGeneral
+	if market.IsBTCETH(d.Symbol) {
+		maxLeverage = btcEthLeverage
+		maxPositionValue = accountEquity * 10
+		minPositionSize = minPositionSizeBTCETH
+	}
+
+	if d.Leverage < 0 {
+		return fmt.Errorf("leverage must not be negative: %d", d.Leverage)
+	}
+	if d.Leverage > maxLeverage {
+		logger.Infof("⚠️  [Leverage Fallback] %s leverage exceeded (%dx > %dx), auto-adjusting to limit %dx",
+			d.Symbol, d.Leverage, maxLeverage, maxLeverage)
+		d.Leverage = maxLeverage
+	}
+	if d.PositionSizeUSD < minPositionSize {
+		return fmt.Errorf("%s add amount too small (%.2f USDT), must be ≥%.2f USDT", d.Symbol, d.PositionSizeUSD, minPositionSize)
+	}
+	if d.PositionSizeUSD > maxPositionValue*1.01 {
+		return fmt.Errorf("%s add amount cannot exceed %.0f USDT, actual: %.0f", d.Symbol, maxPositionValue, d.PositionSizeUSD)
+	}
+
+	if d.StopLoss < 0 || d.TakeProfit < 0 {
+		return fmt.Errorf("stop loss and take profit must not be negative")
+	}
+	if d.StopLoss > 0 && d.TakeProfit > 0 {
+		if d.Action == "add_long" && d.StopLoss >= d.TakeProfit {
+			return fmt.Errorf("for long positions, stop loss price must be less than take profit price")
+		}
+		if d.Action == "add_short" && d.StopLoss <= d.TakeProfit {
+			return fmt.Errorf("for short positions, stop loss price must be greater than take profit price")
+		}
+	}
+	if d.TrailingStopPct < 0 {
+		return fmt.Errorf("trailing stop percentage must not be negative: %.2f", d.TrailingStopPct)
+	}
+	if d.TrailingStopPct > 10 {
+		logger.Infof("⚠️  [Trailing Stop Fallback] %s trailing stop %.2f%% too wide, auto-adjusting to 10%%", d.Symbol, d.TrailingStopPct)
+		d.TrailingStopPct = 10
+	}
+	return nil
+}
+
+// validatePartialCloseDecision validates closing a share of a position (partial_close_long/partial_close_short)
+// Stop loss / take profit are optional, when given they replace the protection of the remainder
+func validatePartialCloseDecision(d *Decision) error {
+	if d.ClosePct <= 0 || d.ClosePct > 100 {
+		return fmt.Errorf("close percentage must be within (0, 100]: %.2f", d.ClosePct)
+	}
+	if d.StopLoss < 0 || d.TakeProfit < 0 {
+		return fmt.Errorf("stop loss and take profit must not be negative")
+	}
+	if d.StopLoss > 0 && d.TakeProfit > 0 {
+		if d.Action == "partial_close_long" && d.StopLoss >= d.TakeProfit {
+			return fmt.Errorf("for long positions, stop loss price must be less than take profit price")
+		}
+		if d.Action == "partial_close_short" && d.StopLoss <= d.TakeProfit {
+			return fmt.Errorf("for short positions, stop loss price must be greater than take profit price")
+		}
+	}
+	return nil
+}
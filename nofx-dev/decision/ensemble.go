@@ -0,0 +1,133 @@
+package decision
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// EnsembleVote one model's decisions in an ensemble vote
+type EnsembleVote struct {
+	Model     string // Model name shown in notes and reasoning
+	Weight    float64
+	Decisions []Decision
+}
+
+// ensembleActions actions that need the ensemble's agreement, hold / wait / alert are taken from the first vote
+var ensembleActions = map[string]bool{
+	"open_long": true, "open_short": true, "add_long": true, "add_short": true,
+	"close_long": true, "close_short": true, "partial_close_long": true, "partial_close_short": true,
+	"manage_exit_long": true, "manage_exit_short": true,
+}
+
+// entryDirection "long" / "short" for entries, "" otherwise
+func entryDirection(action string) string {
+	switch action {
+	case "open_long", "add_long":
+		return "long"
+	case "open_short", "add_short":
+		return "short"
+	}
+	return ""
+}
+
+// ensembleTally the vote on one symbol + action
+type ensembleTally struct {
+	symbol, action string
+	support        float64 // Weight of the models proposing it
+	confidence     float64 // Weighted sum of their confidence
+	voters         []string
+	best           Decision // Parameters of the heaviest proposing model (first vote on ties)
+	bestWeight     float64
+}
+
+// CombineEnsemble combines the decisions of several models (first vote = the trader's own model): an action on a
+// symbol is kept when the models proposing it hold more than half of the vote weight (or at least minAgreement when
+// set) and, for entries, their average confidence reaches minConfidence. Parameters come from the heaviest agreeing
+// model. Entries in opposite directions on one symbol cancel out. Returns the decisions and a note per vote
+func CombineEnsemble(votes []EnsembleVote, minAgreement float64, minConfidence int) ([]Decision, []string) {
+	if len(votes) == 0 {
+		return nil, nil
+	}
+	var total float64
+	for _, v := range votes {
+		total += v.Weight
+	}
+
+	tallies := make(map[string]*ensembleTally)
+	var order []string
+	for _, v := range votes {
+		seen := make(map[string]bool)
+		for _, d := range v.Decisions {
+			key := d.Symbol + "|" + d.Action
+			if !ensembleActions[d.Action] || seen[key] {
+				continue
+			}
+			seen[key] = true
+			t, ok := tallies[key]
+			if !ok {
+				t = &ensembleTally{symbol: d.Symbol, action: d.Action}
+				tallies[key] = t
+				order = append(order, key)
+			}
+			t.support += v.Weight
+			t.confidence += float64(d.Confidence) * v.Weight
+			t.voters = append(t.voters, v.Model)
+			if v.Weight > t.bestWeight {
+				t.best, t.bestWeight = d, v.Weight
+			}
+		}
+	}
+
+	var notes []string
+	var passed []*ensembleTally
+	directions := make(map[string]map[string]bool) // Symbol -> entry directions that passed
+	for _, key := range order {
+		t := tallies[key]
+		share := 0.0
+		if total > 0 {
+			share = t.support / total
+		}
+		agreed := share > 0.5
+		if minAgreement > 0 {
+			agreed = share >= minAgreement-1e-9
+		}
+		if !agreed {
+			notes = append(notes, fmt.Sprintf("🗳 %s %s rejected: only %s agree (%.0f%% of the vote)",
+				t.symbol, t.action, strings.Join(t.voters, ", "), share*100))
+			continue
+		}
+		avgConfidence := t.confidence / t.support
+		if dir := entryDirection(t.action); dir != "" {
+			if minConfidence > 0 && avgConfidence < float64(minConfidence) {
+				notes = append(notes, fmt.Sprintf("🗳 %s %s rejected: average confidence %.0f < %d",
+					t.symbol, t.action, avgConfidence, minConfidence))
+				continue
+			}
+			if directions[t.symbol] == nil {
+				directions[t.symbol] = make(map[string]bool)
+			}
+			directions[t.symbol][dir] = true
+		}
+		t.best.Confidence = int(math.Round(avgConfidence))
+		passed = append(passed, t)
+	}
+
+	var result []Decision
+	for _, d := range votes[0].Decisions {
+		if !ensembleActions[d.Action] {
+			result = append(result, d)
+		}
+	}
+	for _, t := range passed {
+		if entryDirection(t.action) != "" && len(directions[t.symbol]) > 1 {
+			notes = append(notes, fmt.Sprintf("🗳 %s %s rejected: the ensemble is split between long and short", t.symbol, t.action))
+			continue
+		}
+		d := t.best
+		d.Reasoning = fmt.Sprintf("[ensemble: %s] %s", strings.Join(t.voters, ", "), d.Reasoning)
+		result = append(result, d)
+		notes = append(notes, fmt.Sprintf("🗳 %s %s agreed by %s", t.symbol, t.action, strings.Join(t.voters, ", ")))
+	}
+	return result, notes
+}
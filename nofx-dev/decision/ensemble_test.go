@@ -0,0 +1,67 @@
+package decision
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestCombineEnsemble_Majority tests that only actions backed by more than half of the vote are kept
+func TestCombineEnsemble_Majority(t *testing.T) {
+	votes := []EnsembleVote{
+		{Model: "primary", Weight: 1, Decisions: []Decision{
+			{Symbol: "BTCUSDT", Action: "open_long", Leverage: 5, StopLoss: 60000, Confidence: 80, Reasoning: "breakout"},
+			{Symbol: "ETHUSDT", Action: "close_short"},
+			{Symbol: "SOLUSDT", Action: "wait"},
+		}},
+		{Model: "b", Weight: 1, Decisions: []Decision{
+			{Symbol: "BTCUSDT", Action: "open_long", Leverage: 3, Confidence: 70},
+		}},
+		{Model: "c", Weight: 1, Decisions: []Decision{
+			{Symbol: "BTCUSDT", Action: "wait"},
+			{Symbol: "ETHUSDT", Action: "hold"},
+		}},
+	}
+
+	got, notes := CombineEnsemble(votes, 0, 0)
+	if len(got) != 2 || got[0].Action != "wait" || got[1].Symbol != "BTCUSDT" {
+		t.Fatalf("expected the primary's wait and the agreed BTC entry, got %+v", got)
+	}
+	if got[1].Leverage != 5 || got[1].Confidence != 75 || !strings.HasPrefix(got[1].Reasoning, "[ensemble: primary, b]") {
+		t.Errorf("expected the first vote's parameters with averaged confidence, got %+v", got[1])
+	}
+	if len(notes) != 2 || !strings.Contains(notes[0], "ETHUSDT close_short rejected") || !strings.Contains(notes[1], "agreed") {
+		t.Errorf("expected a note per vote, got %v", notes)
+	}
+}
+
+// TestCombineEnsemble_Thresholds tests the agreement share, confidence floor and vote weights
+func TestCombineEnsemble_Thresholds(t *testing.T) {
+	votes := []EnsembleVote{
+		{Model: "primary", Weight: 0.3, Decisions: []Decision{{Symbol: "BTCUSDT", Action: "open_short", Confidence: 90}}},
+		{Model: "b", Weight: 0.7, Decisions: []Decision{{Symbol: "BTCUSDT", Action: "open_short", Leverage: 2, Confidence: 60}}},
+		{Model: "c", Weight: 0.5, Decisions: nil},
+	}
+
+	if got, _ := CombineEnsemble(votes, 1, 0); len(got) != 0 {
+		t.Errorf("a unanimous threshold should reject a 2 of 3 vote, got %+v", got)
+	}
+	got, _ := CombineEnsemble(votes, 0.6, 0)
+	if len(got) != 1 || got[0].Leverage != 2 || got[0].Confidence != 69 {
+		t.Errorf("expected the heaviest model's parameters and weighted confidence, got %+v", got)
+	}
+	if got, notes := CombineEnsemble(votes, 0.6, 75); len(got) != 0 || !strings.Contains(notes[0], "confidence") {
+		t.Errorf("entries below the confidence floor should be rejected, got %+v %v", got, notes)
+	}
+}
+
+// TestCombineEnsemble_SplitDirection tests that agreed entries in opposite directions cancel out
+func TestCombineEnsemble_SplitDirection(t *testing.T) {
+	votes := []EnsembleVote{
+		{Model: "primary", Weight: 1, Decisions: []Decision{{Symbol: "BTCUSDT", Action: "open_long"}, {Symbol: "BTCUSDT", Action: "open_short"}}},
+		{Model: "b", Weight: 1, Decisions: []Decision{{Symbol: "BTCUSDT", Action: "open_long"}}},
+		{Model: "c", Weight: 1, Decisions: []Decision{{Symbol: "BTCUSDT", Action: "open_short"}}},
+	}
+	if got, _ := CombineEnsemble(votes, 0, 0); len(got) != 0 {
+		t.Errorf("a split ensemble shouldn't open either side, got %+v", got)
+	}
+}
@@ -0,0 +1,52 @@
+package decision
+
+import (
+	"nofx/events"
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixedEvents events.Source returning fixed events
+type fixedEvents []events.Event
+
+func (f fixedEvents) Name() string                   { return "fixed" }
+func (f fixedEvents) Fetch() ([]events.Event, error) { return f, nil }
+
+// TestAttachEvents tests market-wide and per-symbol event flags within the strategy's window
+func TestAttachEvents(t *testing.T) {
+	now := time.Now()
+	calendar := events.NewCalendar(0, fixedEvents{
+		{Title: "USD CPI m/m", Category: events.CategoryMacro, Impact: "high", Time: now.Add(5 * time.Hour)},
+		{Title: "USD Retail Sales", Category: events.CategoryMacro, Impact: "medium", Time: now.Add(time.Hour)},
+		{Title: "ARB unlock", Category: events.CategoryUnlock, Impact: "high", Time: now.Add(2 * time.Hour), Symbols: []string{"ARB"}},
+		{Title: "SUI unlock", Category: events.CategoryUnlock, Impact: "high", Time: now.Add(3 * time.Hour), Symbols: []string{"SUI"}},
+		{Title: "USD FOMC Statement", Category: events.CategoryMacro, Impact: "high", Time: now.Add(30 * time.Hour)},
+	})
+	calendar.Refresh()
+
+	config := &store.StrategyConfig{}
+	ctx := &Context{MarketDataMap: map[string]*market.Data{"BTCUSDT": {}, "ARBUSDT": {}}}
+	attachEvents(ctx, config, calendar, now)
+	if ctx.UpcomingEvents != nil {
+		t.Fatal("events should only be attached when the calendar is enabled")
+	}
+
+	config.Indicators.EnableEventCalendar = true
+	config.Indicators.EventWindowHours = 6
+	attachEvents(ctx, config, calendar, now)
+	if len(ctx.UpcomingEvents) != 2 || ctx.UpcomingEvents[0].Title != "ARB unlock" || ctx.UpcomingEvents[1].Title != "USD CPI m/m" {
+		t.Fatalf("expected the ARB unlock and CPI, got %+v", ctx.UpcomingEvents)
+	}
+	if !ctx.MarketEventSoon || len(ctx.SymbolEvents["ARBUSDT"]) != 1 || len(ctx.SymbolEvents["BTCUSDT"]) != 0 {
+		t.Errorf("unexpected flags: market %v, symbols %+v", ctx.MarketEventSoon, ctx.SymbolEvents)
+	}
+	if !ctx.EventSoon("BTCUSDT") {
+		t.Error("CPI should flag every symbol")
+	}
+	if s := formatSymbolEvents(ctx.SymbolEvents["ARBUSDT"], now); !strings.Contains(s, "ARB unlock (unlock, ARB) in 2h0m") {
+		t.Errorf("unexpected symbol events: %s", s)
+	}
+}
@@ -0,0 +1,157 @@
+package decision
+
+import (
+	"fmt"
+	"math"
+	"nofx/market"
+	"strings"
+)
+
+const (
+	// hitRatePriorTrades weight of the AI's stated confidence in a bucket's hit rate, in trades: with fewer closed
+	// trades than this the confidence dominates, with many more the recorded hit rate does
+	hitRatePriorTrades = 10
+	// defaultHoldHours expected holding time for funding when no trades are on record
+	defaultHoldHours = 4.0
+	// fundingIntervalHours funding settlement interval the funding rate applies to
+	fundingIntervalHours = 8.0
+)
+
+// ConfidenceBucket 10-point confidence bucket of a decision (e.g. 70 for 70-79), 100 joins the 90 bucket
+func ConfidenceBucket(confidence int) int {
+	if confidence >= 100 {
+		return 90
+	}
+	if confidence < 0 {
+		return 0
+	}
+	return confidence / 10 * 10
+}
+
+// bucketRecord closed trades opened at one confidence bucket
+type bucketRecord struct {
+	trades      int
+	wins        int
+	holdMinutes float64
+}
+
+// HitRates historical win rate of trades by the confidence bucket of their opening decision. The zero value has no history
+type HitRates struct {
+	buckets map[int]*bucketRecord
+}
+
+// Add records a closed trade opened at confidence
+func (h *HitRates) Add(confidence int, win bool, holdMinutes float64) {
+	if h.buckets == nil {
+		h.buckets = make(map[int]*bucketRecord)
+	}
+	b := h.buckets[ConfidenceBucket(confidence)]
+	if b == nil {
+		b = &bucketRecord{}
+		h.buckets[ConfidenceBucket(confidence)] = b
+	}
+	b.trades++
+	if win {
+		b.wins++
+	}
+	b.holdMinutes += holdMinutes
+}
+
+// Trades closed trades on record for confidence's bucket
+func (h *HitRates) Trades(confidence int) int {
+	if b := h.bucket(confidence); b != nil {
+		return b.trades
+	}
+	return 0
+}
+
+// HitRate probability that a trade opened at confidence reaches its target: the bucket's win rate with
+// the stated confidence as a prior worth hitRatePriorTrades trades, so thin history can't swing it to 0 or 1
+func (h *HitRates) HitRate(confidence int) float64 {
+	prior := math.Max(0, math.Min(100, float64(confidence))) / 100
+	b := h.bucket(confidence)
+	if b == nil {
+		return prior
+	}
+	return (float64(b.wins) + prior*hitRatePriorTrades) / float64(b.trades+hitRatePriorTrades)
+}
+
+// HoldHours average holding time of the bucket's trades (defaultHoldHours without history)
+func (h *HitRates) HoldHours(confidence int) float64 {
+	if b := h.bucket(confidence); b != nil && b.trades > 0 && b.holdMinutes > 0 {
+		return b.holdMinutes / float64(b.trades) / 60
+	}
+	return defaultHoldHours
+}
+
+func (h *HitRates) bucket(confidence int) *bucketRecord {
+	if h == nil || h.buckets == nil {
+		return nil
+	}
+	return h.buckets[ConfidenceBucket(confidence)]
+}
+
+// ExpectedValue expected outcome of an open decision, in % of position notional
+type ExpectedValue struct {
+	HitRate    float64 // Probability of reaching take profit before stop loss
+	RewardPct  float64 // Move to take profit, % of entry
+	RiskPct    float64 // Move to stop loss, % of entry
+	FeePct     float64 // Round-trip taker fees
+	FundingPct float64 // Expected funding paid (negative when received) over the holding time
+	EVPct      float64 // HitRate × reward - (1 - HitRate) × risk - fees - funding
+}
+
+// ComputeExpectedValue expected value of opening side ("long"/"short") at entry with the decision's stop loss and
+// take profit. feeRate is the taker fee rate per side, fundingRate the current rate per settlement (positive: longs pay)
+// ok is false when the stop loss or take profit is missing or on the wrong side of entry
+func ComputeExpectedValue(side string, entry, stopLoss, takeProfit, hitRate, feeRate, fundingRate, holdHours float64) (ExpectedValue, bool) {
+	if entry <= 0 || stopLoss <= 0 || takeProfit <= 0 {
+		return ExpectedValue{}, false
+	}
+	ev := ExpectedValue{HitRate: hitRate}
+	if side == "short" {
+		ev.RewardPct = (entry - takeProfit) / entry * 100
+		ev.RiskPct = (stopLoss - entry) / entry * 100
+		ev.FundingPct = -fundingRate * 100 * holdHours / fundingIntervalHours
+	} else {
+		ev.RewardPct = (takeProfit - entry) / entry * 100
+		ev.RiskPct = (entry - stopLoss) / entry * 100
+		ev.FundingPct = fundingRate * 100 * holdHours / fundingIntervalHours
+	}
+	if ev.RewardPct <= 0 || ev.RiskPct <= 0 {
+		return ExpectedValue{}, false
+	}
+	ev.FeePct = 2 * feeRate * 100
+	ev.EVPct = hitRate*ev.RewardPct - (1-hitRate)*ev.RiskPct - ev.FeePct - ev.FundingPct
+	return ev, true
+}
+
+// ApplyExpectedValueGate drops open decisions whose expected value after fees and funding is below minEVPct
+// (% of notional), using the historical hit rate of their confidence bucket (expected_value_gate).
+// Returns the decisions to execute and an execution log note per skipped trade; symbols without market data,
+// stop loss or take profit are kept and left to the other entry checks
+func ApplyExpectedValueGate(decisions []Decision, marketData map[string]*market.Data, rates *HitRates, feeRate, minEVPct float64) ([]Decision, []string) {
+	kept := decisions[:0:0]
+	var notes []string
+	for _, d := range decisions {
+		if d.Action != "open_long" && d.Action != "open_short" {
+			kept = append(kept, d)
+			continue
+		}
+		data, ok := marketData[d.Symbol]
+		if !ok || data == nil {
+			kept = append(kept, d)
+			continue
+		}
+		side := strings.TrimPrefix(d.Action, "open_")
+		ev, ok := ComputeExpectedValue(side, data.CurrentPrice, d.StopLoss, d.TakeProfit,
+			rates.HitRate(d.Confidence), feeRate, data.FundingRate, rates.HoldHours(d.Confidence))
+		if !ok || ev.EVPct >= minEVPct {
+			kept = append(kept, d)
+			continue
+		}
+		notes = append(notes, fmt.Sprintf("🎲 %s %s skipped: expected value %+.2f%% < %.2f%% (hit rate %.0f%% at confidence %d over %d trades, reward %.2f%%, risk %.2f%%, fees %.2f%%, funding %+.3f%%)",
+			d.Symbol, d.Action, ev.EVPct, minEVPct, ev.HitRate*100, d.Confidence, rates.Trades(d.Confidence), ev.RewardPct, ev.RiskPct, ev.FeePct, ev.FundingPct))
+	}
+	return kept, notes
+}
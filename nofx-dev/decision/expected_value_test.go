@@ -0,0 +1,92 @@
+package decision
+
+import (
+	"math"
+	"nofx/market"
+	"testing"
+)
+
+// TestHitRates tests bucketing and shrinking recorded win rates toward the stated confidence
+func TestHitRates(t *testing.T) {
+	var rates HitRates
+	if got := rates.HitRate(80); got != 0.8 {
+		t.Errorf("without history the hit rate should be the confidence, got %v", got)
+	}
+	if got := rates.HoldHours(80); got != defaultHoldHours {
+		t.Errorf("without history the holding time should be the default, got %v", got)
+	}
+
+	// 10 trades at 80-89 confidence, 3 wins, held 2h on average
+	for i := 0; i < 10; i++ {
+		rates.Add(80+i%10, i < 3, 120)
+	}
+	if got, want := rates.HitRate(85), (3+0.85*hitRatePriorTrades)/20.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("hit rate = %v, want %v", got, want)
+	}
+	if rates.Trades(89) != 10 || rates.Trades(79) != 0 || rates.HoldHours(80) != 2 {
+		t.Errorf("unexpected bucket: %d trades, %v hours", rates.Trades(89), rates.HoldHours(80))
+	}
+	if ConfidenceBucket(100) != 90 || ConfidenceBucket(79) != 70 {
+		t.Errorf("unexpected buckets %d %d", ConfidenceBucket(100), ConfidenceBucket(79))
+	}
+}
+
+// TestComputeExpectedValue tests expected value after fees and funding for both sides
+func TestComputeExpectedValue(t *testing.T) {
+	// Long: +4% target, -2% stop, 50% hit rate, 0.05% taker, 0.01% funding over 8h
+	ev, ok := ComputeExpectedValue("long", 100, 98, 104, 0.5, 0.0005, 0.0001, 8)
+	if !ok {
+		t.Fatal("expected a valid long")
+	}
+	if want := 0.5*4 - 0.5*2 - 0.1 - 0.01; math.Abs(ev.EVPct-want) > 1e-9 {
+		t.Errorf("long EV = %v, want %v", ev.EVPct, want)
+	}
+
+	// Short receives positive funding
+	ev, ok = ComputeExpectedValue("short", 100, 102, 96, 0.5, 0.0005, 0.0001, 8)
+	if want := 0.5*4 - 0.5*2 - 0.1 + 0.01; !ok || math.Abs(ev.EVPct-want) > 1e-9 {
+		t.Errorf("short EV = %v (%v), want %v", ev.EVPct, ok, want)
+	}
+
+	if _, ok := ComputeExpectedValue("long", 100, 0, 104, 0.5, 0, 0, 8); ok {
+		t.Error("missing stop loss should not produce an expected value")
+	}
+	if _, ok := ComputeExpectedValue("long", 100, 98, 99, 0.5, 0, 0, 8); ok {
+		t.Error("take profit below a long entry should not produce an expected value")
+	}
+}
+
+// TestApplyExpectedValueGate tests skipping low-EV entries and keeping everything else
+func TestApplyExpectedValueGate(t *testing.T) {
+	data := map[string]*market.Data{
+		"BTCUSDT": {CurrentPrice: 100},
+		"ETHUSDT": {CurrentPrice: 100},
+	}
+	var rates HitRates
+	for i := 0; i < 40; i++ {
+		rates.Add(90, i < 10, 60) // Confident calls have won 25% of the time
+	}
+	decisions := []Decision{
+		{Symbol: "BTCUSDT", Action: "close_long"},
+		{Symbol: "BTCUSDT", Action: "open_long", StopLoss: 98, TakeProfit: 103, Confidence: 90},  // Hit rate 0.38: EV -0.2%
+		{Symbol: "ETHUSDT", Action: "open_short", StopLoss: 101, TakeProfit: 94, Confidence: 60}, // No history: 60% of +6 vs 40% of -1
+		{Symbol: "SOLUSDT", Action: "open_long", StopLoss: 140, TakeProfit: 160, Confidence: 90}, // No market data
+	}
+
+	kept, notes := ApplyExpectedValueGate(decisions, data, &rates, 0.0005, 0)
+	if len(kept) != 3 || len(notes) != 1 {
+		t.Fatalf("expected the BTC long to be skipped, kept %+v, notes %v", kept, notes)
+	}
+	for _, d := range kept {
+		if d.Symbol == "BTCUSDT" && d.Action == "open_long" {
+			t.Errorf("low EV entry was kept: %+v", d)
+		}
+	}
+	if decisions[1].Action != "open_long" {
+		t.Error("the input decisions should not be modified")
+	}
+
+	if kept, _ := ApplyExpectedValueGate(decisions, data, &rates, 0.0005, -5); len(kept) != 4 {
+		t.Errorf("a permissive threshold should keep everything, kept %d", len(kept))
+	}
+}
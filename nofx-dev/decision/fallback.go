@@ -0,0 +1,142 @@
+package decision
+
+import (
+	"fmt"
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"time"
+)
+
+// Fallback modes of RiskControl.AIFallbackMode, run instead of skipping the cycle when the AI fails
+const (
+	FallbackManageStops = "manage_stops" // Hold open positions under their stops, no entries
+	FallbackEMACross    = "ema_cross"    // EMA20 / EMA50 trend of the primary timeframe
+)
+
+const (
+	// fallbackEntryEquityPct notional of an ema_cross entry in % of equity
+	fallbackEntryEquityPct = 10
+	// fallbackMaxLeverage leverage of ema_cross entries (lower when the strategy allows less)
+	fallbackMaxLeverage = 3
+	// fallbackStopATR stop loss distance of ema_cross entries in ATR14 of the primary timeframe
+	fallbackStopATR = 2.0
+	// fallbackRewardRisk take profit distance of ema_cross entries in stop distances (at least the strategy's min)
+	fallbackRewardRisk = 2.0
+	// fallbackConfidence confidence stated on fallback actions
+	fallbackConfidence = 50
+)
+
+// IsFallbackMode reports whether mode is a known AI fallback mode
+func IsFallbackMode(mode string) bool {
+	return mode == FallbackManageStops || mode == FallbackEMACross
+}
+
+// FallbackDecision deterministic decisions of the fallback mode for a cycle whose AI call failed (reason):
+//   - manage_stops holds every position, its stop loss / take profit orders and exit monitors stay in charge
+//   - ema_cross also closes positions whose EMA20 crossed against them and opens on a fresh EMA20 / EMA50 cross of
+//     the primary timeframe (fixed size, ATR stop), up to the strategy's max positions
+func FallbackDecision(ctx *Context, config *store.StrategyConfig, mode, reason string) *FullDecision {
+	return &FullDecision{
+		CoTTrace:  fmt.Sprintf("Rule-based fallback (%s), AI unavailable: %s", mode, reason),
+		Decisions: ruleDecisions(ctx, config, mode),
+		Timestamp: time.Now(),
+	}
+}
+
+// ruleDecisions deterministic decisions of a rule-based mode, shared by the AI fallback and the ema_cross provider
+func ruleDecisions(ctx *Context, config *store.StrategyConfig, mode string) []Decision {
+	rc := config.RiskControl
+	timeframe := config.Indicators.Klines.PrimaryTimeframe
+	var decisions []Decision
+	held := make(map[string]bool)
+	for _, p := range ctx.Positions {
+		held[p.Symbol] = true
+		d := Decision{Symbol: p.Symbol, Action: "hold", Reasoning: "Holding under the existing stop loss / take profit"}
+		if mode == FallbackEMACross {
+			if trend, _ := emaTrend(ctx.MarketDataMap[p.Symbol], timeframe); trend != "" && trend != strings.ToLower(p.Side) {
+				d.Action = "close_" + strings.ToLower(p.Side)
+				d.Confidence = fallbackConfidence
+				d.Reasoning = fmt.Sprintf("EMA20 crossed %s EMA50 on %s against the %s position", crossWord(trend), timeframe, strings.ToLower(p.Side))
+			}
+		}
+		decisions = append(decisions, d)
+	}
+
+	if mode == FallbackEMACross {
+		slots := len(ctx.Positions)
+		for _, coin := range ctx.CandidateCoins {
+			if rc.MaxPositions > 0 && slots >= rc.MaxPositions {
+				break
+			}
+			data := ctx.MarketDataMap[coin.Symbol]
+			trend, fresh := emaTrend(data, timeframe)
+			if held[coin.Symbol] || !fresh {
+				continue
+			}
+			if d, ok := fallbackEntry(coin.Symbol, trend, data, timeframe, ctx.Account.TotalEquity, rc); ok {
+				decisions = append(decisions, d)
+				slots++
+			}
+		}
+	}
+
+	if len(decisions) == 0 {
+		decisions = append(decisions, Decision{Symbol: "ALL", Action: "wait", Reasoning: "No position to manage and no fresh signal"})
+	}
+	return decisions
+}
+
+// fallbackEntry ema_cross entry in the trend's direction with an ATR stop and a fixed reward / risk target
+func fallbackEntry(symbol, side string, data *market.Data, timeframe string, equity float64, rc store.RiskControlConfig) (Decision, bool) {
+	atr := data.ATR14For(timeframe)
+	stop := market.ATRStopLoss(side, data.CurrentPrice, atr, fallbackStopATR)
+	if stop <= 0 || equity <= 0 {
+		return Decision{}, false
+	}
+	rewardRisk := max(fallbackRewardRisk, rc.MinRiskRewardRatio)
+	target := data.CurrentPrice + (data.CurrentPrice-stop)*rewardRisk
+	leverage := rc.AltcoinMaxLeverage
+	if market.IsBTCETH(symbol) {
+		leverage = rc.BTCETHMaxLeverage
+	}
+	leverage = max(1, min(leverage, fallbackMaxLeverage))
+	return Decision{
+		Symbol:          symbol,
+		Action:          "open_" + side,
+		Leverage:        leverage,
+		PositionSizeUSD: max(equity*fallbackEntryEquityPct/100, rc.MinPositionSize),
+		StopLoss:        market.RoundToTick(stop, data.TickSize),
+		TakeProfit:      market.RoundToTick(target, data.TickSize),
+		Confidence:      fallbackConfidence,
+		Reasoning:       fmt.Sprintf("EMA20 crossed %s EMA50 on %s", crossWord(side), timeframe),
+	}, true
+}
+
+// emaTrend direction of EMA20 vs EMA50 on the timeframe ("long" / "short", "" without data) and whether they
+// crossed on the last candle
+func emaTrend(data *market.Data, timeframe string) (string, bool) {
+	if data == nil {
+		return "", false
+	}
+	tf := data.TimeframeData[timeframe]
+	if tf == nil || len(tf.EMA20Values) < 2 || len(tf.EMA50Values) < 2 {
+		return "", false
+	}
+	ema20, ema50 := tf.EMA20Values[len(tf.EMA20Values)-1], tf.EMA50Values[len(tf.EMA50Values)-1]
+	prev20, prev50 := tf.EMA20Values[len(tf.EMA20Values)-2], tf.EMA50Values[len(tf.EMA50Values)-2]
+	if ema20 <= 0 || ema50 <= 0 || ema20 == ema50 {
+		return "", false
+	}
+	if ema20 > ema50 {
+		return "long", prev20 <= prev50
+	}
+	return "short", prev20 >= prev50
+}
+
+func crossWord(trend string) string {
+	if trend == "long" {
+		return "above"
+	}
+	return "below"
+}
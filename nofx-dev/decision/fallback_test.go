@@ -0,0 +1,65 @@
+package decision
+
+import (
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"testing"
+)
+
+func crossData(symbol string, price float64, ema20, ema50 []float64) *market.Data {
+	return &market.Data{
+		Symbol:       symbol,
+		CurrentPrice: price,
+		TimeframeData: map[string]*market.TimeframeSeriesData{
+			"5m": {Timeframe: "5m", EMA20Values: ema20, EMA50Values: ema50, ATR14: price * 0.01},
+		},
+	}
+}
+
+// TestFallbackDecision tests the manage_stops and ema_cross fallback decisions
+func TestFallbackDecision(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.Indicators.Klines.PrimaryTimeframe = "5m"
+	cfg.RiskControl.MaxPositions = 2
+	cfg.RiskControl.AltcoinMaxLeverage = 2
+	cfg.RiskControl.MinRiskRewardRatio = 3
+	ctx := &Context{
+		Account:   AccountInfo{TotalEquity: 1000},
+		Positions: []PositionInfo{{Symbol: "BTCUSDT", Side: "long"}},
+		CandidateCoins: []CandidateCoin{
+			{Symbol: "BTCUSDT"}, {Symbol: "ETHUSDT"}, {Symbol: "SOLUSDT"}, {Symbol: "XRPUSDT"},
+		},
+		MarketDataMap: map[string]*market.Data{
+			"BTCUSDT": crossData("BTCUSDT", 60000, []float64{60100, 59900}, []float64{60000, 60000}), // Crossed below: against the long
+			"ETHUSDT": crossData("ETHUSDT", 3000, []float64{3050, 3060}, []float64{3000, 3000}),      // Trend up, no fresh cross
+			"SOLUSDT": crossData("SOLUSDT", 100, []float64{101, 99}, []float64{100, 100}),            // Fresh cross below
+			"XRPUSDT": crossData("XRPUSDT", 1, []float64{0.9, 1.1}, []float64{1, 1}),                 // Fresh cross above, no slot left
+		},
+	}
+
+	hold := FallbackDecision(ctx, &cfg, FallbackManageStops, "timeout")
+	if len(hold.Decisions) != 1 || hold.Decisions[0].Action != "hold" || !strings.Contains(hold.CoTTrace, "manage_stops") {
+		t.Errorf("expected manage_stops to only hold the position, got %+v", hold.Decisions)
+	}
+
+	cross := FallbackDecision(ctx, &cfg, FallbackEMACross, "timeout")
+	if len(cross.Decisions) != 2 {
+		t.Fatalf("expected a close and one entry, got %+v", cross.Decisions)
+	}
+	if d := cross.Decisions[0]; d.Symbol != "BTCUSDT" || d.Action != "close_long" {
+		t.Errorf("expected the long closed on the cross below, got %+v", d)
+	}
+	d := cross.Decisions[1]
+	if d.Symbol != "SOLUSDT" || d.Action != "open_short" || d.Leverage != 2 || d.PositionSizeUSD != 100 {
+		t.Fatalf("expected a 2x 100 USDT SOL short, got %+v", d)
+	}
+	if d.StopLoss != 102 || d.TakeProfit != 94 {
+		t.Errorf("expected a 2 ATR stop and a 3R target, got SL %.2f TP %.2f", d.StopLoss, d.TakeProfit)
+	}
+
+	empty := FallbackDecision(&Context{}, &cfg, FallbackEMACross, "invalid JSON")
+	if len(empty.Decisions) != 1 || empty.Decisions[0].Action != "wait" {
+		t.Errorf("expected a wait without positions or crosses, got %+v", empty.Decisions)
+	}
+}
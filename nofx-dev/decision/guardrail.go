@@ -0,0 +1,102 @@
+package decision
+
+import (
+	"fmt"
+	"math"
+	"nofx/market"
+	"nofx/store"
+	"strings"
+)
+
+// GuardrailMaxPositionUSD the guardrail's position size limit at the equity (0 = no limit): the lower of
+// MaxPositionSizeUSD and MaxPositionEquityPct of equity
+func GuardrailMaxPositionUSD(g store.RiskGuardrail, equity float64) float64 {
+	limit := g.MaxPositionSizeUSD
+	if g.MaxPositionEquityPct > 0 && equity > 0 {
+		if byEquity := equity * g.MaxPositionEquityPct / 100; limit <= 0 || byEquity < limit {
+			limit = byEquity
+		}
+	}
+	return limit
+}
+
+// ClampToGuardrail clamps the leverage, position size and stop loss distance of entries (open / add) to a trader's
+// risk guardrail in place, returns the original and clamped value of each change and an execution log note per change.
+// Stop distances are measured from the current price; symbols without market data keep their stop
+func ClampToGuardrail(decisions []Decision, marketData map[string]*market.Data, equity float64, g store.RiskGuardrail) ([]store.GuardrailClamp, []string) {
+	var clamps []store.GuardrailClamp
+	maxSize := GuardrailMaxPositionUSD(g, equity)
+	for i := range decisions {
+		d := &decisions[i]
+		if !isEntryAction(d.Action) {
+			continue
+		}
+		clamp := func(field string, original, clamped float64, reason string) {
+			clamps = append(clamps, store.GuardrailClamp{
+				Symbol: d.Symbol, Action: d.Action, Field: field, Original: original, Clamped: clamped, Reason: reason,
+			})
+		}
+
+		if g.MaxLeverage > 0 && d.Leverage > g.MaxLeverage {
+			clamp("leverage", float64(d.Leverage), float64(g.MaxLeverage), fmt.Sprintf("max leverage %dx", g.MaxLeverage))
+			d.Leverage = g.MaxLeverage
+		}
+		if maxSize > 0 && d.PositionSizeUSD > maxSize {
+			clamp("position_size_usd", d.PositionSizeUSD, maxSize, fmt.Sprintf("max position size %.2f USD", maxSize))
+			d.RiskUSD *= maxSize / d.PositionSizeUSD
+			d.PositionSizeUSD = maxSize
+		}
+
+		data := marketData[d.Symbol]
+		if data == nil || data.CurrentPrice <= 0 {
+			continue
+		}
+		if stop, reason := guardrailStop(d, data, g); stop > 0 {
+			clamp("stop_loss", d.StopLoss, stop, reason)
+			d.StopLoss = stop
+		}
+	}
+
+	notes := make([]string, 0, len(clamps))
+	for _, c := range clamps {
+		notes = append(notes, fmt.Sprintf("🛡️ %s %s %s %.6g → %.6g (guardrail %s)", c.Symbol, c.Action, c.Field, c.Original, c.Clamped, c.Reason))
+	}
+	return clamps, notes
+}
+
+// guardrailStop the stop loss moved into the guardrail's distance range (0 = unchanged). Stops on the wrong side of
+// the price are left to decision validation; a missing stop of an open gets the max distance
+func guardrailStop(d *Decision, data *market.Data, g store.RiskGuardrail) (float64, string) {
+	sign := 1.0 // long: stop below the price
+	if strings.HasSuffix(d.Action, "_short") {
+		sign = -1
+	}
+	price := data.CurrentPrice
+	var distancePct float64
+	switch {
+	case d.StopLoss > 0:
+		distancePct = sign * (price - d.StopLoss) / price * 100
+		if distancePct <= 0 {
+			return 0, ""
+		}
+	case strings.HasPrefix(d.Action, "open_") && g.MaxStopDistancePct > 0:
+		distancePct = math.Inf(1)
+	default:
+		return 0, ""
+	}
+
+	target, reason := distancePct, ""
+	if g.MaxStopDistancePct > 0 && distancePct > g.MaxStopDistancePct {
+		target, reason = g.MaxStopDistancePct, fmt.Sprintf("max stop distance %.2f%%", g.MaxStopDistancePct)
+	} else if g.MinStopDistancePct > 0 && distancePct < g.MinStopDistancePct {
+		target, reason = g.MinStopDistancePct, fmt.Sprintf("min stop distance %.2f%%", g.MinStopDistancePct)
+	}
+	if reason == "" {
+		return 0, ""
+	}
+	stop := market.RoundToTick(price*(1-sign*target/100), data.TickSize)
+	if stop <= 0 || stop == d.StopLoss {
+		return 0, ""
+	}
+	return stop, reason
+}
@@ -0,0 +1,63 @@
+package decision
+
+import (
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"testing"
+)
+
+// TestClampToGuardrail tests leverage, size and stop distance clamps and the audit of original values
+func TestClampToGuardrail(t *testing.T) {
+	marketData := map[string]*market.Data{
+		"BTCUSDT": {CurrentPrice: 100, TickSize: 0.01},
+		"ETHUSDT": {CurrentPrice: 100, TickSize: 0.01},
+		"SOLUSDT": {CurrentPrice: 100, TickSize: 0.01},
+	}
+	decisions := []Decision{
+		{Symbol: "BTCUSDT", Action: "open_long", Leverage: 20, PositionSizeUSD: 800, RiskUSD: 40, StopLoss: 80},
+		{Symbol: "ETHUSDT", Action: "open_short", Leverage: 3, PositionSizeUSD: 100, StopLoss: 100.2},
+		{Symbol: "SOLUSDT", Action: "open_long", Leverage: 2, PositionSizeUSD: 100},
+		{Symbol: "XRPUSDT", Action: "close_long", Leverage: 20, PositionSizeUSD: 5000},
+	}
+	g := store.RiskGuardrail{MaxLeverage: 5, MaxPositionSizeUSD: 500, MaxPositionEquityPct: 40, MinStopDistancePct: 1, MaxStopDistancePct: 5}
+
+	clamps, notes := ClampToGuardrail(decisions, marketData, 1000, g)
+
+	btc := decisions[0]
+	if btc.Leverage != 5 || btc.PositionSizeUSD != 400 || btc.RiskUSD != 20 || btc.StopLoss != 95 {
+		t.Errorf("expected BTC clamped to 5x, 400 USD (40%% of equity), risk 20, stop 95, got %+v", btc)
+	}
+	if decisions[1].StopLoss != 101 {
+		t.Errorf("expected the ETH short stop widened to 1%%, got %v", decisions[1].StopLoss)
+	}
+	if decisions[2].StopLoss != 95 {
+		t.Errorf("expected the missing SOL stop set at the max distance, got %v", decisions[2].StopLoss)
+	}
+	if decisions[3].Leverage != 20 || decisions[3].PositionSizeUSD != 5000 {
+		t.Error("expected closes left unchanged")
+	}
+
+	if len(clamps) != 5 || len(notes) != 5 {
+		t.Fatalf("expected 5 clamps, got %+v", clamps)
+	}
+	want := store.GuardrailClamp{Symbol: "BTCUSDT", Action: "open_long", Field: "position_size_usd", Original: 800, Clamped: 400, Reason: "max position size 400.00 USD"}
+	if clamps[1] != want {
+		t.Errorf("expected %+v, got %+v", want, clamps[1])
+	}
+	if clamps[4].Field != "stop_loss" || clamps[4].Original != 0 || clamps[4].Clamped != 95 {
+		t.Errorf("expected the missing stop recorded with original 0, got %+v", clamps[4])
+	}
+	if !strings.Contains(notes[0], "BTCUSDT open_long leverage 20 → 5 (guardrail max leverage 5x)") {
+		t.Errorf("unexpected note %q", notes[0])
+	}
+}
+
+// TestClampToGuardrail_NoLimits tests that an empty guardrail changes nothing
+func TestClampToGuardrail_NoLimits(t *testing.T) {
+	decisions := []Decision{{Symbol: "BTCUSDT", Action: "open_long", Leverage: 50, PositionSizeUSD: 1e6}}
+	clamps, _ := ClampToGuardrail(decisions, map[string]*market.Data{"BTCUSDT": {CurrentPrice: 100}}, 1000, store.RiskGuardrail{})
+	if len(clamps) != 0 || decisions[0].Leverage != 50 {
+		t.Errorf("expected no clamps, got %+v", clamps)
+	}
+}
@@ -0,0 +1,85 @@
+package decision
+
+import (
+	"fmt"
+	"nofx/market"
+	"nofx/store"
+	"strings"
+)
+
+// defaultMinOIValueUSD min open interest value of candidate coins when the trader's liquidity filter sets none
+const defaultMinOIValueUSD = 15_000_000
+
+// LiquidityCheck liquidity filter outcome of a candidate coin
+type LiquidityCheck struct {
+	Symbol       string
+	OIValueUSD   float64 // Open interest × price (0 = unknown)
+	Volume24hUSD float64 // 24h quote volume (0 = unknown or not checked)
+	Passed       bool
+	Reason       string // Checks that were applied and their result
+}
+
+func (c LiquidityCheck) String() string {
+	if c.Passed {
+		return fmt.Sprintf("💧 %s passed liquidity filter: %s", c.Symbol, c.Reason)
+	}
+	return fmt.Sprintf("💧 %s skipped by liquidity filter: %s", c.Symbol, c.Reason)
+}
+
+// CheckLiquidity applies a trader's liquidity filter to a candidate coin: open interest value against
+// MinOIValueUSD (default 15M USD, negative disables; missing OI data passes, OI reported as 0 passes only with
+// ZeroOIPass) and, when MinVolume24hUSD is set, the 24h quote volume (missing ticker data passes)
+func CheckLiquidity(symbol string, data *market.Data, filter store.LiquidityFilter) LiquidityCheck {
+	check := LiquidityCheck{Symbol: symbol, Passed: true}
+	var parts []string
+
+	minOI := filter.MinOIValueUSD
+	if minOI == 0 {
+		minOI = defaultMinOIValueUSD
+	}
+	if minOI > 0 {
+		switch {
+		case data == nil || data.OpenInterest == nil || data.CurrentPrice <= 0:
+			parts = append(parts, "OI unknown")
+		case data.OpenInterest.Latest == 0:
+			if filter.ZeroOIPass {
+				parts = append(parts, "OI reported as 0, passed through")
+			} else {
+				check.Passed = false
+				parts = append(parts, "OI reported as 0")
+			}
+		default:
+			check.OIValueUSD = data.OpenInterest.Latest * data.CurrentPrice
+			parts = append(parts, compareUSD("OI", check.OIValueUSD, minOI))
+			if check.OIValueUSD < minOI {
+				check.Passed = false
+			}
+		}
+	}
+
+	if filter.MinVolume24hUSD > 0 {
+		if data == nil || data.Ticker24h == nil {
+			parts = append(parts, "24h volume unknown")
+		} else {
+			check.Volume24hUSD = data.Ticker24h.QuoteVolume
+			parts = append(parts, compareUSD("24h volume", check.Volume24hUSD, filter.MinVolume24hUSD))
+			if check.Volume24hUSD < filter.MinVolume24hUSD {
+				check.Passed = false
+			}
+		}
+	}
+
+	if len(parts) == 0 {
+		parts = append(parts, "disabled")
+	}
+	check.Reason = strings.Join(parts, ", ")
+	return check
+}
+
+func compareUSD(name string, value, min float64) string {
+	op := "≥"
+	if value < min {
+		op = "<"
+	}
+	return fmt.Sprintf("%s %.2fM USD %s %.2fM", name, value/1_000_000, op, min/1_000_000)
+}
@@ -0,0 +1,44 @@
+package decision
+
+import (
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"testing"
+)
+
+// TestCheckLiquidity tests the OI threshold, zero / missing OI handling and the 24h volume filter
+func TestCheckLiquidity(t *testing.T) {
+	withOI := func(oi, volume float64) *market.Data {
+		data := &market.Data{CurrentPrice: 10, OpenInterest: &market.OIData{Latest: oi}}
+		if volume > 0 {
+			data.Ticker24h = &market.Ticker24h{QuoteVolume: volume}
+		}
+		return data
+	}
+	tests := []struct {
+		name   string
+		data   *market.Data
+		filter store.LiquidityFilter
+		passed bool
+		reason string
+	}{
+		{"default threshold passes", withOI(2_000_000, 0), store.LiquidityFilter{}, true, "OI 20.00M USD ≥ 15.00M"},
+		{"default threshold skips", withOI(1_000_000, 0), store.LiquidityFilter{}, false, "OI 10.00M USD < 15.00M"},
+		{"custom threshold", withOI(1_000_000, 0), store.LiquidityFilter{MinOIValueUSD: 5_000_000}, true, "≥ 5.00M"},
+		{"threshold disabled", withOI(1, 0), store.LiquidityFilter{MinOIValueUSD: -1}, true, "disabled"},
+		{"missing OI passes", &market.Data{CurrentPrice: 10}, store.LiquidityFilter{}, true, "OI unknown"},
+		{"zero OI skipped", withOI(0, 0), store.LiquidityFilter{}, false, "OI reported as 0"},
+		{"zero OI passed through", withOI(0, 0), store.LiquidityFilter{ZeroOIPass: true}, true, "passed through"},
+		{"volume too low", withOI(2_000_000, 30_000_000), store.LiquidityFilter{MinVolume24hUSD: 50_000_000}, false, "24h volume 30.00M USD < 50.00M"},
+		{"missing ticker passes", withOI(2_000_000, 0), store.LiquidityFilter{MinVolume24hUSD: 50_000_000}, true, "24h volume unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := CheckLiquidity("SOLUSDT", tt.data, tt.filter)
+			if check.Passed != tt.passed || !strings.Contains(check.Reason, tt.reason) {
+				t.Errorf("expected passed=%v with %q, got %+v", tt.passed, tt.reason, check)
+			}
+		})
+	}
+}
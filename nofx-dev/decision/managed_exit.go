@@ -0,0 +1,117 @@
+package decision
+
+import (
+	"fmt"
+	"math"
+)
+
+// Managed exit modes
+const (
+	// ExitModeChandelier stop multiplier × ATR14 below the highest high (long) / above the lowest low (short) since hand-off
+	ExitModeChandelier = "chandelier"
+	// ExitModeATRTrail stop multiplier × ATR14 behind the best close since hand-off
+	ExitModeATRTrail = "atr_trail"
+)
+
+const (
+	minManagedExitMultiplier = 0.5
+	maxManagedExitMultiplier = 10.0
+)
+
+// ManagedExit parameters of a deterministic trailing exit the AI hands a position over to
+type ManagedExit struct {
+	Mode          string  `json:"mode"`                // ExitModeChandelier / ExitModeATRTrail
+	ATRMultiplier float64 `json:"atr_multiplier"`      // Stop distance in ATR14
+	Timeframe     string  `json:"timeframe,omitempty"` // ATR timeframe ("" = primary timeframe)
+}
+
+// Validate checks the mode and clamps the multiplier to its range
+func (m *ManagedExit) Validate() error {
+	if m.Mode != ExitModeChandelier && m.Mode != ExitModeATRTrail {
+		return fmt.Errorf("invalid managed exit mode: %q (chandelier | atr_trail)", m.Mode)
+	}
+	if m.ATRMultiplier <= 0 {
+		return fmt.Errorf("managed exit atr_multiplier must be greater than 0: %.2f", m.ATRMultiplier)
+	}
+	m.ATRMultiplier = math.Min(math.Max(m.ATRMultiplier, minManagedExitMultiplier), maxManagedExitMultiplier)
+	return nil
+}
+
+// ExitTrail state of a managed exit: the stop only moves in the position's favor
+type ExitTrail struct {
+	Side       string  `json:"side"` // "long" or "short"
+	Mode       string  `json:"mode"`
+	Multiplier float64 `json:"multiplier"`
+	ATR        float64 `json:"atr"`
+	Extreme    float64 `json:"extreme"` // Best high / low (chandelier) or close (atr_trail) since hand-off
+	Stop       float64 `json:"stop"`
+}
+
+// NewExitTrail starts a managed exit at price with the given ATR14
+func NewExitTrail(side string, exit ManagedExit, atr, price float64) (*ExitTrail, error) {
+	if atr <= 0 || price <= 0 {
+		return nil, fmt.Errorf("ATR and price must be positive (ATR %.6g, price %.6g)", atr, price)
+	}
+	t := &ExitTrail{Side: side, Mode: exit.Mode, Multiplier: exit.ATRMultiplier, ATR: atr, Extreme: price}
+	t.Stop = t.stopFrom(price)
+	return t, nil
+}
+
+func (t *ExitTrail) stopFrom(extreme float64) float64 {
+	if t.Side == "short" {
+		return extreme + t.Multiplier*t.ATR
+	}
+	return math.Max(extreme-t.Multiplier*t.ATR, 0)
+}
+
+// SetATR refreshes the ATR, a wider ATR never loosens the current stop
+func (t *ExitTrail) SetATR(atr float64) {
+	if atr > 0 {
+		t.ATR = atr
+		t.ratchet(t.stopFrom(t.Extreme))
+	}
+}
+
+// Advance records a bar (or a tick with high = low = close) and returns the updated stop
+func (t *ExitTrail) Advance(high, low, close float64) float64 {
+	best := close
+	if t.Mode == ExitModeChandelier {
+		best = high
+		if t.Side == "short" {
+			best = low
+		}
+	}
+	if best > 0 && ((t.Side == "short" && best < t.Extreme) || (t.Side != "short" && best > t.Extreme)) {
+		t.Extreme = best
+	}
+	t.ratchet(t.stopFrom(t.Extreme))
+	return t.Stop
+}
+
+func (t *ExitTrail) ratchet(stop float64) {
+	if t.Side == "short" {
+		if stop < t.Stop {
+			t.Stop = stop
+		}
+		return
+	}
+	if stop > t.Stop {
+		t.Stop = stop
+	}
+}
+
+// Hit reports whether price crossed the stop
+func (t *ExitTrail) Hit(price float64) bool {
+	if price <= 0 {
+		return false
+	}
+	if t.Side == "short" {
+		return price >= t.Stop
+	}
+	return price <= t.Stop
+}
+
+// String short description for prompts and logs
+func (t *ExitTrail) String() string {
+	return fmt.Sprintf("%s %.1f×ATR, stop %.6g", t.Mode, t.Multiplier, t.Stop)
+}
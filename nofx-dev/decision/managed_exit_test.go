@@ -0,0 +1,55 @@
+package decision
+
+import "testing"
+
+// TestExitTrailChandelier tests that the chandelier stop follows the highest high and never loosens
+func TestExitTrailChandelier(t *testing.T) {
+	trail, err := NewExitTrail("long", ManagedExit{Mode: ExitModeChandelier, ATRMultiplier: 3}, 2, 100)
+	if err != nil {
+		t.Fatalf("NewExitTrail: %v", err)
+	}
+	if trail.Stop != 94 {
+		t.Fatalf("expected initial stop 94, got %.2f", trail.Stop)
+	}
+	if stop := trail.Advance(110, 104, 106); stop != 104 {
+		t.Errorf("expected stop 104 after new high, got %.2f", stop)
+	}
+	if stop := trail.Advance(107, 101, 102); stop != 104 {
+		t.Errorf("stop must not loosen on a pullback, got %.2f", stop)
+	}
+	trail.SetATR(4) // Wider ATR would put the stop at 98
+	if trail.Stop != 104 {
+		t.Errorf("wider ATR must not loosen the stop, got %.2f", trail.Stop)
+	}
+	if trail.Hit(104.5) || !trail.Hit(104) {
+		t.Errorf("expected hit at or below 104")
+	}
+}
+
+// TestExitTrailATRShort tests the close-based trail of a short position
+func TestExitTrailATRShort(t *testing.T) {
+	trail, err := NewExitTrail("short", ManagedExit{Mode: ExitModeATRTrail, ATRMultiplier: 2}, 1, 100)
+	if err != nil {
+		t.Fatalf("NewExitTrail: %v", err)
+	}
+	if stop := trail.Advance(99, 90, 95); stop != 97 {
+		t.Errorf("expected stop 97 from the best close (not the low), got %.2f", stop)
+	}
+	if !trail.Hit(97.5) || trail.Hit(96) {
+		t.Errorf("expected hit at or above 97")
+	}
+}
+
+// TestManagedExitValidate tests mode checks and multiplier clamping
+func TestManagedExitValidate(t *testing.T) {
+	if err := (&ManagedExit{Mode: "fixed", ATRMultiplier: 2}).Validate(); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+	if err := (&ManagedExit{Mode: ExitModeATRTrail}).Validate(); err == nil {
+		t.Error("expected error for missing multiplier")
+	}
+	m := &ManagedExit{Mode: ExitModeChandelier, ATRMultiplier: 25}
+	if err := m.Validate(); err != nil || m.ATRMultiplier != maxManagedExitMultiplier {
+		t.Errorf("expected multiplier clamped to %.0f, got %.2f (err %v)", maxManagedExitMultiplier, m.ATRMultiplier, err)
+	}
+}
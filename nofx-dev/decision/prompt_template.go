@@ -0,0 +1,149 @@
+package decision
+
+import (
+	"bytes"
+	"fmt"
+	"nofx/logger"
+	"nofx/store"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// PromptVars variables available to the editable prompt sections, the custom prompt and the partials, which are
+// rendered as Go text/template: {{.Equity}}, {{.Risk.MaxPositions}}, {{join .Symbols ", "}}, {{template "name" .}}
+type PromptVars struct {
+	Equity           float64                 // Account equity of the cycle
+	QuoteAsset       string                  // Quote asset of the exchange (USDT, USDC)
+	Variant          string                  // Prompt variant (balanced / aggressive / conservative / scalping)
+	Symbols          []string                // Candidate coins and open positions of the cycle (static coins in previews)
+	PrimaryTimeframe string                  // Primary kline timeframe
+	Risk             store.RiskControlConfig // Risk limits of the strategy
+
+	MaxBTCETHPositionUSD  float64 // Position value limit of BTC/ETH (equity × ratio)
+	MaxAltcoinPositionUSD float64 // Position value limit of altcoins (equity × ratio)
+}
+
+// promptFuncs helper functions of prompt templates
+var promptFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"pct":   func(v float64) string { return fmt.Sprintf("%.0f%%", v*100) },
+	"usd":   func(v float64) string { return fmt.Sprintf("%.0f", v) },
+}
+
+// promptVars template variables of the cycle
+func (e *StrategyEngine) promptVars(accountEquity float64, variant string, symbols []string) PromptVars {
+	risk := e.config.RiskControl
+	btcEthRatio := risk.BTCETHMaxPositionValueRatio
+	if btcEthRatio <= 0 {
+		btcEthRatio = 5.0
+	}
+	altcoinRatio := risk.AltcoinMaxPositionValueRatio
+	if altcoinRatio <= 0 {
+		altcoinRatio = 1.0
+	}
+	quote := e.quoteAsset
+	if quote == "" {
+		quote = "USDT"
+	}
+	return PromptVars{
+		Equity:                accountEquity,
+		QuoteAsset:            quote,
+		Variant:               variant,
+		Symbols:               symbols,
+		PrimaryTimeframe:      e.config.Indicators.Klines.PrimaryTimeframe,
+		Risk:                  risk,
+		MaxBTCETHPositionUSD:  accountEquity * btcEthRatio,
+		MaxAltcoinPositionUSD: accountEquity * altcoinRatio,
+	}
+}
+
+// renderPrompt renders an editable prompt text as a template with the strategy's partials. Text without template
+// actions is returned as is; when rendering fails the raw text is used so an old prompt containing "{{" still works
+func (e *StrategyEngine) renderPrompt(name, text string, vars PromptVars) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+	out, err := executePromptTemplate(name, text, e.config.PromptPartials, vars)
+	if err != nil {
+		logger.Warnf("⚠️ Prompt template %s failed, using the raw text: %v", name, err)
+		return text
+	}
+	return out
+}
+
+// executePromptTemplate parses the partials and the text into one template set and executes the text
+func executePromptTemplate(name, text string, partials map[string]string, vars PromptVars) (string, error) {
+	tmpl := template.New(name).Funcs(promptFuncs).Option("missingkey=error")
+	names := make([]string, 0, len(partials))
+	for partial := range partials {
+		names = append(names, partial)
+	}
+	sort.Strings(names)
+	for _, partial := range names {
+		if _, err := tmpl.New(partial).Parse(partials[partial]); err != nil {
+			return "", fmt.Errorf("partial %q: %w", partial, err)
+		}
+	}
+	if _, err := tmpl.Parse(text); err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, vars); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ValidatePromptTemplates parses and renders the editable prompt sections, custom prompt and partials with sample
+// variables, so template errors are reported when the strategy is saved instead of at the next cycle
+func ValidatePromptTemplates(config *store.StrategyConfig) error {
+	for name := range config.PromptPartials {
+		if strings.TrimSpace(name) == "" || strings.ContainsAny(name, "\"`{}") {
+			return fmt.Errorf("invalid prompt partial name %q", name)
+		}
+	}
+	for symbol := range config.SymbolPrompts {
+		if strings.TrimSpace(symbol) == "" {
+			return fmt.Errorf("symbol prompt without a symbol")
+		}
+	}
+	vars := NewStrategyEngine(config).promptVars(1000, "balanced", config.CoinSource.StaticCoins)
+	texts := []struct{ name, text string }{
+		{"role_definition", config.PromptSections.RoleDefinition},
+		{"trading_frequency", config.PromptSections.TradingFrequency},
+		{"entry_standards", config.PromptSections.EntryStandards},
+		{"decision_process", config.PromptSections.DecisionProcess},
+		{"custom_prompt", config.CustomPrompt},
+	}
+	for _, t := range texts {
+		if !strings.Contains(t.text, "{{") {
+			continue
+		}
+		if _, err := executePromptTemplate(t.name, t.text, config.PromptPartials, vars); err != nil {
+			return fmt.Errorf("prompt template %s: %w", t.name, err)
+		}
+	}
+	return nil
+}
+
+// contextSymbols candidate coins and position symbols of the cycle
+func contextSymbols(ctx *Context) []string {
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, c := range ctx.CandidateCoins {
+		if !seen[c.Symbol] {
+			seen[c.Symbol] = true
+			symbols = append(symbols, c.Symbol)
+		}
+	}
+	for _, p := range ctx.Positions {
+		if !seen[p.Symbol] {
+			seen[p.Symbol] = true
+			symbols = append(symbols, p.Symbol)
+		}
+	}
+	return symbols
+}
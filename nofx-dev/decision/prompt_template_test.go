@@ -0,0 +1,69 @@
+package decision
+
+import (
+	"nofx/store"
+	"strings"
+	"testing"
+)
+
+// TestBuildSystemPrompt_Templates tests variables and partials in the editable sections and custom prompt
+func TestBuildSystemPrompt_Templates(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.RiskControl.MaxPositions = 3
+	cfg.RiskControl.AltcoinMaxLeverage = 7
+	cfg.PromptPartials = map[string]string{
+		"limits": "Hold at most {{.Risk.MaxPositions}} positions, altcoin leverage ≤ {{.Risk.AltcoinMaxLeverage}}x",
+	}
+	cfg.PromptSections.RoleDefinition = "# Trader for {{usd .Equity}} {{.QuoteAsset}}\n{{template \"limits\" .}}"
+	cfg.CustomPrompt = "Focus on {{join .Symbols \", \"}}"
+	engine := NewStrategyEngine(&cfg)
+
+	prompt := engine.buildSystemPrompt(2500, "balanced", []string{"BTCUSDT", "SOLUSDT"})
+	for _, want := range []string{
+		"# Trader for 2500 USDT",
+		"Hold at most 3 positions, altcoin leverage ≤ 7x",
+		"Focus on BTCUSDT, SOLUSDT",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q", want)
+		}
+	}
+}
+
+// TestBuildSystemPrompt_TemplateFallback tests that text failing to render is used as is
+func TestBuildSystemPrompt_TemplateFallback(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.CustomPrompt = "Use {{.Unknown}} here"
+	engine := NewStrategyEngine(&cfg)
+
+	if prompt := engine.BuildSystemPrompt(1000, ""); !strings.Contains(prompt, "Use {{.Unknown}} here") {
+		t.Error("expected the raw custom prompt when the template fails")
+	}
+}
+
+// TestValidatePromptTemplates tests template errors reported when a strategy is saved
+func TestValidatePromptTemplates(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.CustomPrompt = "Plain text without templates"
+	cfg.PromptSections.EntryStandards = "Confidence ≥ {{.Risk.MinConfidence}}"
+	if err := ValidatePromptTemplates(&cfg); err != nil {
+		t.Fatalf("expected valid templates: %v", err)
+	}
+
+	invalid := []func(c *store.StrategyConfig){
+		func(c *store.StrategyConfig) { c.CustomPrompt = "{{.Risk.MaxPositions" },
+		func(c *store.StrategyConfig) { c.PromptSections.DecisionProcess = "{{.NoSuchField}}" },
+		func(c *store.StrategyConfig) { c.CustomPrompt = `{{template "missing" .}}` },
+		func(c *store.StrategyConfig) {
+			c.PromptPartials = map[string]string{"broken": "{{if}}"}
+			c.CustomPrompt = `{{template "broken" .}}`
+		},
+	}
+	for i, mutate := range invalid {
+		c := store.GetDefaultStrategyConfig("en")
+		mutate(&c)
+		if err := ValidatePromptTemplates(&c); err == nil {
+			t.Errorf("case %d: expected template error", i)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package decision
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DecisionProvider makes the decisions of a trading cycle. Traders ask the AI model by default; a strategy's
+// decision_provider selects a registered provider instead, e.g. a purely algorithmic strategy. Its decisions go
+// through the same logging, risk checks and execution as the AI's
+type DecisionProvider interface {
+	// Name identifies the provider in logs and decision records
+	Name() string
+	// Decide returns the cycle's decisions. The context holds account, positions and candidate coins;
+	// PrepareContext fetches its market data
+	Decide(ctx *Context) (*FullDecision, error)
+}
+
+// ProviderFactory creates a provider deciding with a strategy's configuration
+type ProviderFactory func(engine *StrategyEngine) DecisionProvider
+
+// ProviderEMACross built-in provider trading the EMA20 / EMA50 cross of the primary timeframe
+const ProviderEMACross = "ema_cross"
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]ProviderFactory{
+		ProviderEMACross: func(engine *StrategyEngine) DecisionProvider { return &emaCrossProvider{engine: engine} },
+	}
+)
+
+// RegisterProvider makes a provider available under name, replacing any provider of that name
+func RegisterProvider(name string, factory ProviderFactory) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[name] = factory
+}
+
+// ProviderNames names of the registered providers, sorted
+func ProviderNames() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsProvider reports whether name is a registered provider ("" is the AI model, not a provider)
+func IsProvider(name string) bool {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	_, ok := providers[name]
+	return ok
+}
+
+// NewProvider creates the named provider for the strategy engine
+func NewProvider(name string, engine *StrategyEngine) (DecisionProvider, error) {
+	providersMu.RLock()
+	factory, ok := providers[name]
+	providersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown decision provider %q (available: %v)", name, ProviderNames())
+	}
+	return factory(engine), nil
+}
+
+// emaCrossProvider holds positions in the EMA20 / EMA50 trend, closes them when it turns against them and opens on
+// fresh crosses (same rules as the ema_cross AI fallback)
+type emaCrossProvider struct {
+	engine *StrategyEngine
+}
+
+func (p *emaCrossProvider) Name() string { return ProviderEMACross }
+
+func (p *emaCrossProvider) Decide(ctx *Context) (*FullDecision, error) {
+	if err := PrepareContext(ctx, p.engine); err != nil {
+		return nil, err
+	}
+	config := p.engine.GetConfig()
+	return &FullDecision{
+		CoTTrace: fmt.Sprintf("EMA20 / EMA50 cross strategy on %s: %d candidate coins, %d positions",
+			config.Indicators.Klines.PrimaryTimeframe, len(ctx.CandidateCoins), len(ctx.Positions)),
+		Decisions: ruleDecisions(ctx, config, FallbackEMACross),
+		Timestamp: time.Now(),
+	}, nil
+}
@@ -0,0 +1,72 @@
+package decision
+
+import (
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"testing"
+)
+
+type staticProvider struct{ decisions []Decision }
+
+func (p *staticProvider) Name() string { return "static" }
+
+func (p *staticProvider) Decide(ctx *Context) (*FullDecision, error) {
+	return &FullDecision{Decisions: p.decisions}, nil
+}
+
+// TestNewProvider tests the built-in provider, registering a provider and unknown names
+func TestNewProvider(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	engine := NewStrategyEngine(&cfg)
+
+	if p, err := NewProvider(ProviderEMACross, engine); err != nil || p.Name() != ProviderEMACross {
+		t.Fatalf("expected the ema_cross provider, got %v, %v", p, err)
+	}
+	if _, err := NewProvider("grid", engine); err == nil || !strings.Contains(err.Error(), "ema_cross") {
+		t.Errorf("expected an unknown provider error listing the available providers, got %v", err)
+	}
+	if IsProvider("") {
+		t.Error("expected the AI model not to be a provider")
+	}
+
+	RegisterProvider("static", func(*StrategyEngine) DecisionProvider {
+		return &staticProvider{decisions: []Decision{{Symbol: "BTCUSDT", Action: "wait"}}}
+	})
+	p, err := NewProvider("static", engine)
+	if err != nil || !IsProvider("static") {
+		t.Fatalf("expected the registered provider, got %v", err)
+	}
+	if full, _ := p.Decide(&Context{}); len(full.Decisions) != 1 || full.Decisions[0].Action != "wait" {
+		t.Errorf("unexpected decisions %+v", full)
+	}
+}
+
+// TestEMACrossProvider tests that the provider decides with the ema_cross rules on the prepared context
+func TestEMACrossProvider(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.Indicators.Klines.PrimaryTimeframe = "5m"
+	cfg.RiskControl.MaxPositions = 3
+	p, _ := NewProvider(ProviderEMACross, NewStrategyEngine(&cfg))
+	ctx := &Context{
+		Account:        AccountInfo{TotalEquity: 1000},
+		Positions:      []PositionInfo{{Symbol: "ETHUSDT", Side: "long"}},
+		CandidateCoins: []CandidateCoin{{Symbol: "SOLUSDT"}},
+		MarketDataMap: map[string]*market.Data{
+			"ETHUSDT": crossData("ETHUSDT", 3000, []float64{3050, 3060}, []float64{3000, 3000}),
+			"SOLUSDT": crossData("SOLUSDT", 100, []float64{99, 101}, []float64{100, 100}),
+		},
+		OITopDataMap: map[string]*OITopData{},
+	}
+
+	full, err := p.Decide(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(full.Decisions) != 2 || full.Decisions[0].Action != "hold" || full.Decisions[1].Action != "open_long" {
+		t.Fatalf("expected the ETH long held and a SOL long opened, got %+v", full.Decisions)
+	}
+	if !strings.Contains(full.CoTTrace, "EMA20 / EMA50 cross strategy on 5m") || strings.Contains(full.Decisions[0].Reasoning, "AI") {
+		t.Errorf("unexpected trace %q / reasoning %q", full.CoTTrace, full.Decisions[0].Reasoning)
+	}
+}
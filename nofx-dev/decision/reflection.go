@@ -0,0 +1,260 @@
+package decision
+
+import (
+	"encoding/json"
+	"fmt"
+	"nofx/mcp"
+	"regexp"
+	"strings"
+)
+
+// Verdicts of the reflection pass
+const (
+	ReflectionApprove  = "approve"
+	ReflectionVeto     = "veto"
+	ReflectionDownsize = "downsize"
+)
+
+// maxReflectionMistakes recent losing trades shown to the reflection pass
+const maxReflectionMistakes = 8
+
+var reReflectionTag = regexp.MustCompile(`(?s)<reflection>(.*?)</reflection>`)
+
+// ReflectionVerdict review of one proposed entry by the reflection pass
+type ReflectionVerdict struct {
+	Index      int     `json:"index"` // Position of the entry in the proposed decisions
+	Symbol     string  `json:"symbol"`
+	Action     string  `json:"action"`
+	Verdict    string  `json:"verdict"`               // approve / veto / downsize
+	SizeFactor float64 `json:"size_factor,omitempty"` // Downsize: share of the proposed size kept (0-1)
+	Reason     string  `json:"reason"`
+}
+
+// Reflection result of the second AI pass that reviewed the proposed decisions before execution
+type Reflection struct {
+	Decisions []Decision          // Decisions to execute: vetoed entries removed, downsized ones scaled
+	Verdicts  []ReflectionVerdict // Verdict of every reviewed entry (missing answers count as approve)
+	Prompt    string
+	Response  string // Raw answer of the reflection pass
+	Usage     mcp.Usage
+}
+
+const reflectionSystemPrompt = `You are the risk reviewer of a crypto futures trading desk. Another model proposed the entries below.
+Before they are executed, check each one against the risk rules and the recent mistakes of this account:
+- veto an entry that breaks a rule, repeats a recent losing setup, fights the stated reasoning or is not justified by it
+- downsize an entry that is acceptable but too large for its confidence, the account state or the recent losses
+- approve an entry that is sound
+You can only veto or reduce, never add entries, increase sizes or change prices. Be strict but don't veto without a concrete reason.
+
+Risk rules:
+%s
+Reply with one verdict per entry, as <reflection>[...]</reflection> containing a JSON array:
+[{"index": 0, "verdict": "approve" | "veto" | "downsize", "size_factor": 0.5, "reason": "..."}]
+size_factor (0-1, share of the proposed size kept) is required for downsize. No commentary outside the <reflection> tag.`
+
+// Reflect asks the model to review the proposed entries (open / add) against the strategy's risk rules and the
+// recent losing trades, and applies its verdicts: vetoed entries are removed and downsized ones scaled. Closes,
+// holds and other actions are never blocked. Returns nil without calling the model when there is no entry to review
+func Reflect(client mcp.AIClient, engine *StrategyEngine, ctx *Context, decisions []Decision) (*Reflection, error) {
+	var entries []int
+	for i, d := range decisions {
+		if isEntryAction(d.Action) {
+			entries = append(entries, i)
+		}
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	systemPrompt := fmt.Sprintf(reflectionSystemPrompt, reflectionRules(engine, ctx))
+	userPrompt := reflectionUserPrompt(ctx, decisions, entries)
+	req, err := mcp.NewRequestBuilder().
+		WithSystemPrompt(systemPrompt).
+		WithUserPrompt(userPrompt).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.CallWithRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("reflection call failed: %w", err)
+	}
+
+	reflection := &Reflection{
+		Prompt:   systemPrompt + "\n\n" + userPrompt,
+		Response: response,
+		Usage:    callUsage(client, systemPrompt+userPrompt, response),
+	}
+	answers, err := parseReflection(response)
+	if err != nil {
+		return reflection, err
+	}
+	reflection.Decisions, reflection.Verdicts = applyReflection(decisions, entries, answers)
+	return reflection, nil
+}
+
+// Notes execution log lines of the verdicts that changed a decision
+func (r *Reflection) Notes() []string {
+	var notes []string
+	for _, v := range r.Verdicts {
+		switch v.Verdict {
+		case ReflectionVeto:
+			notes = append(notes, fmt.Sprintf("🪞 Reflection vetoed %s %s: %s", v.Symbol, v.Action, v.Reason))
+		case ReflectionDownsize:
+			notes = append(notes, fmt.Sprintf("🪞 Reflection downsized %s %s to %.0f%%: %s", v.Symbol, v.Action, v.SizeFactor*100, v.Reason))
+		}
+	}
+	return notes
+}
+
+// reflectionRules risk limits of the strategy in the reflection prompt
+func reflectionRules(engine *StrategyEngine, ctx *Context) string {
+	vars := engine.promptVars(ctx.Account.TotalEquity, "", nil)
+	rc := vars.Risk
+	var sb strings.Builder
+	if rc.MaxPositions > 0 {
+		sb.WriteString(fmt.Sprintf("- At most %d open positions (currently %d)\n", rc.MaxPositions, len(ctx.Positions)))
+	}
+	sb.WriteString(fmt.Sprintf("- Leverage ≤ %dx on BTC/ETH, ≤ %dx on altcoins\n", rc.BTCETHMaxLeverage, rc.AltcoinMaxLeverage))
+	sb.WriteString(fmt.Sprintf("- Position value ≤ %.0f %s on BTC/ETH, ≤ %.0f %s on altcoins\n",
+		vars.MaxBTCETHPositionUSD, vars.QuoteAsset, vars.MaxAltcoinPositionUSD, vars.QuoteAsset))
+	if rc.MaxMarginUsage > 0 {
+		sb.WriteString(fmt.Sprintf("- Margin usage ≤ %.0f%%\n", rc.MaxMarginUsage*100))
+	}
+	if rc.MinConfidence > 0 {
+		sb.WriteString(fmt.Sprintf("- Confidence ≥ %d\n", rc.MinConfidence))
+	}
+	if rc.MinRiskRewardRatio > 0 {
+		sb.WriteString(fmt.Sprintf("- Reward / risk (take profit vs stop loss distance) ≥ %.1f\n", rc.MinRiskRewardRatio))
+	}
+	if rc.RiskPerTradePct > 0 {
+		sb.WriteString(fmt.Sprintf("- Loss at the stop loss ≤ %.1f%% of equity\n", rc.RiskPerTradePct))
+	}
+	if rc.MaxDailyLossPct > 0 {
+		sb.WriteString(fmt.Sprintf("- Daily loss ≤ %.1f%% of equity\n", rc.MaxDailyLossPct))
+	}
+	return sb.String()
+}
+
+// reflectionUserPrompt account state, open positions, recent losses and the proposed decisions
+func reflectionUserPrompt(ctx *Context, decisions []Decision, entries []int) string {
+	var sb strings.Builder
+	acc := ctx.Account
+	sb.WriteString(fmt.Sprintf("## Account\nEquity %.2f | Available %.2f | Margin used %.1f%% | Positions %d\n\n",
+		acc.TotalEquity, acc.AvailableBalance, acc.MarginUsedPct, len(ctx.Positions)))
+
+	if len(ctx.Positions) > 0 {
+		sb.WriteString("## Open Positions\n")
+		for _, p := range ctx.Positions {
+			sb.WriteString(fmt.Sprintf("- %s %s %dx | entry %.4f mark %.4f | P&L %+.2f%%\n",
+				p.Symbol, strings.ToUpper(p.Side), p.Leverage, p.EntryPrice, p.MarkPrice, p.UnrealizedPnLPct))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## Recent Mistakes (losing trades, newest first)\n")
+	mistakes := 0
+	for _, o := range ctx.RecentOrders {
+		if o.RealizedPnL >= 0 || mistakes == maxReflectionMistakes {
+			continue
+		}
+		mistakes++
+		sb.WriteString(fmt.Sprintf("- %s %s: entry %.4f → exit %.4f, %+.2f USDT (%+.2f%%), held %s\n",
+			o.Symbol, strings.ToUpper(o.Side), o.EntryPrice, o.ExitPrice, o.RealizedPnL, o.PnLPct, o.HoldDuration))
+	}
+	for _, symbol := range contextSymbols(ctx) {
+		for _, t := range ctx.SimilarTrades[symbol] {
+			if t.RealizedPnL < 0 {
+				sb.WriteString(fmt.Sprintf("- Similar past setup: %s %s in %s lost %+.2f%%\n", t.Symbol, t.Side, t.Regime, t.ReturnPct))
+			}
+		}
+	}
+	if mistakes == 0 {
+		sb.WriteString("None\n")
+	}
+
+	sb.WriteString("\n## Proposed Entries\n")
+	for _, i := range entries {
+		d := decisions[i]
+		sb.WriteString(fmt.Sprintf("[%d] %s %s | %dx | size %.2f | SL %.4f TP %.4f | confidence %d | reasoning: %s\n",
+			i, d.Symbol, d.Action, d.Leverage, d.PositionSizeUSD, d.StopLoss, d.TakeProfit, d.Confidence,
+			truncateRunes(d.Reasoning, 300)))
+	}
+	return sb.String()
+}
+
+// parseReflection extracts the verdict array from the reflection answer
+func parseReflection(response string) ([]ReflectionVerdict, error) {
+	s := fixMissingQuotes(removeInvisibleRunes(response))
+	if match := reReflectionTag.FindStringSubmatch(s); match != nil {
+		s = match[1]
+	}
+	if m := reJSONFence.FindStringSubmatch(s); m != nil {
+		s = m[1]
+	}
+	start, end := strings.Index(s, "["), strings.LastIndex(s, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no reflection verdict array found")
+	}
+	var verdicts []ReflectionVerdict
+	if err := json.Unmarshal([]byte(s[start:end+1]), &verdicts); err != nil {
+		return nil, fmt.Errorf("invalid reflection JSON: %w", err)
+	}
+	return verdicts, nil
+}
+
+// applyReflection applies the answers to the reviewed entries. Unknown verdicts and missing answers count as
+// approve, a downsize that keeps nothing is a veto and one that keeps everything an approve
+func applyReflection(decisions []Decision, entries []int, answers []ReflectionVerdict) ([]Decision, []ReflectionVerdict) {
+	byIndex := make(map[int]ReflectionVerdict, len(answers))
+	for _, a := range answers {
+		byIndex[a.Index] = a
+	}
+
+	verdicts := make([]ReflectionVerdict, 0, len(entries))
+	vetoed := make(map[int]bool)
+	result := make([]Decision, len(decisions))
+	copy(result, decisions)
+	for _, i := range entries {
+		v := byIndex[i]
+		v.Index, v.Symbol, v.Action = i, decisions[i].Symbol, decisions[i].Action
+		verdict := strings.ToLower(strings.TrimSpace(v.Verdict))
+		if verdict == ReflectionDownsize {
+			switch {
+			case v.SizeFactor <= 0:
+				verdict = ReflectionVeto
+			case v.SizeFactor >= 1:
+				verdict = ReflectionApprove
+			}
+		}
+		switch verdict {
+		case ReflectionVeto:
+			vetoed[i] = true
+			v.SizeFactor = 0
+		case ReflectionDownsize:
+			result[i].PositionSizeUSD *= v.SizeFactor
+			result[i].RiskUSD *= v.SizeFactor
+		default:
+			verdict = ReflectionApprove
+			v.SizeFactor = 0
+		}
+		v.Verdict = verdict
+		verdicts = append(verdicts, v)
+	}
+
+	kept := result[:0]
+	for i, d := range result {
+		if !vetoed[i] {
+			kept = append(kept, d)
+		}
+	}
+	return kept, verdicts
+}
+
+func isEntryAction(action string) bool {
+	switch action {
+	case "open_long", "open_short", "add_long", "add_short":
+		return true
+	}
+	return false
+}
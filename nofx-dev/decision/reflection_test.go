@@ -0,0 +1,88 @@
+package decision
+
+import (
+	"nofx/store"
+	"strings"
+	"testing"
+)
+
+// TestReflect tests the review prompt and applying veto / downsize verdicts to the proposed entries
+func TestReflect(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.RiskControl.MinConfidence = 70
+	engine := NewStrategyEngine(&cfg)
+	ctx := &Context{
+		Account: AccountInfo{TotalEquity: 1000, AvailableBalance: 800},
+		RecentOrders: []RecentOrder{
+			{Symbol: "SOLUSDT", Side: "long", EntryPrice: 150, ExitPrice: 140, RealizedPnL: -20, PnLPct: -6.7, HoldDuration: "2h"},
+			{Symbol: "BTCUSDT", Side: "long", EntryPrice: 60000, ExitPrice: 61000, RealizedPnL: 15, PnLPct: 1.7},
+		},
+	}
+	decisions := []Decision{
+		{Symbol: "ETHUSDT", Action: "close_long"},
+		{Symbol: "SOLUSDT", Action: "open_long", Leverage: 5, PositionSizeUSD: 400, StopLoss: 140, TakeProfit: 170, Confidence: 72},
+		{Symbol: "BTCUSDT", Action: "open_short", Leverage: 3, PositionSizeUSD: 1000, RiskUSD: 40, StopLoss: 62000, TakeProfit: 58000, Confidence: 80},
+		{Symbol: "DOGEUSDT", Action: "add_long", PositionSizeUSD: 100},
+	}
+	client := &repairClient{response: `Review done.
+<reflection>[{"index": 1, "verdict": "veto", "reason": "repeats the SOL long that just lost"},
+{"index": 2, "verdict": "downsize", "size_factor": 0.5, "reason": "oversized for the account"}]</reflection>`}
+
+	reflection, err := Reflect(client, engine, ctx, decisions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	prompt := client.requests[0].Messages[0].Content + client.requests[0].Messages[1].Content
+	for _, want := range []string{"Confidence ≥ 70", "SOLUSDT LONG: entry 150.0000 → exit 140.0000", "[2] BTCUSDT open_short"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected reflection prompt to contain %q", want)
+		}
+	}
+	if strings.Contains(prompt, "BTCUSDT LONG: entry") || strings.Contains(prompt, "ETHUSDT close_long") {
+		t.Error("expected only losing trades and entries in the prompt")
+	}
+
+	if len(reflection.Decisions) != 3 || reflection.Decisions[1].Symbol != "BTCUSDT" {
+		t.Fatalf("expected the vetoed SOL entry removed, got %+v", reflection.Decisions)
+	}
+	if d := reflection.Decisions[1]; d.PositionSizeUSD != 500 || d.RiskUSD != 20 {
+		t.Errorf("expected the BTC entry halved, got size %.0f risk %.0f", d.PositionSizeUSD, d.RiskUSD)
+	}
+	if decisions[2].PositionSizeUSD != 1000 {
+		t.Error("expected the proposed decisions left unchanged")
+	}
+	if len(reflection.Verdicts) != 3 || reflection.Verdicts[2].Verdict != ReflectionApprove || reflection.Verdicts[2].Symbol != "DOGEUSDT" {
+		t.Errorf("expected the unanswered entry approved, got %+v", reflection.Verdicts)
+	}
+	if notes := reflection.Notes(); len(notes) != 2 || !strings.Contains(notes[1], "downsized BTCUSDT open_short to 50%") {
+		t.Errorf("unexpected notes %v", notes)
+	}
+}
+
+// TestReflect_NoEntries tests that decisions without entries aren't reviewed
+func TestReflect_NoEntries(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	client := &repairClient{}
+	reflection, err := Reflect(client, NewStrategyEngine(&cfg), &Context{}, []Decision{{Symbol: "BTCUSDT", Action: "close_long"}})
+	if reflection != nil || err != nil || len(client.requests) != 0 {
+		t.Errorf("expected no reflection call, got %+v, %v", reflection, err)
+	}
+}
+
+// TestApplyReflection_SizeFactorBounds tests downsizes that keep nothing or everything
+func TestApplyReflection_SizeFactorBounds(t *testing.T) {
+	decisions := []Decision{
+		{Symbol: "BTCUSDT", Action: "open_long", PositionSizeUSD: 100},
+		{Symbol: "ETHUSDT", Action: "open_long", PositionSizeUSD: 100},
+	}
+	kept, verdicts := applyReflection(decisions, []int{0, 1}, []ReflectionVerdict{
+		{Index: 0, Verdict: "Downsize", SizeFactor: 0},
+		{Index: 1, Verdict: "downsize", SizeFactor: 1.5},
+	})
+	if len(kept) != 1 || kept[0].Symbol != "ETHUSDT" || kept[0].PositionSizeUSD != 100 {
+		t.Errorf("expected the zero downsize vetoed and the upsize ignored, got %+v", kept)
+	}
+	if verdicts[0].Verdict != ReflectionVeto || verdicts[1].Verdict != ReflectionApprove {
+		t.Errorf("unexpected verdicts %+v", verdicts)
+	}
+}
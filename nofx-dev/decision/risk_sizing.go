@@ -0,0 +1,40 @@
+package decision
+
+import (
+	"fmt"
+	"math"
+)
+
+// RiskQuantity quantity that loses riskPct of balance when price moves from entry to stop (before fees)
+// Inverse of RiskStopLoss
+func RiskQuantity(entry, stop, balance, riskPct float64) (float64, error) {
+	if entry <= 0 || stop <= 0 {
+		return 0, fmt.Errorf("entry and stop must be positive")
+	}
+	if balance <= 0 || riskPct <= 0 {
+		return 0, fmt.Errorf("balance and risk percent must be positive")
+	}
+	distance := math.Abs(entry - stop)
+	if distance == 0 {
+		return 0, fmt.Errorf("stop loss equals entry price")
+	}
+	return balance * riskPct / 100 / distance, nil
+}
+
+// RiskStopLoss stop price at which quantity loses riskPct of balance (before fees), side: "long"/"short"
+func RiskStopLoss(side string, entry, quantity, balance, riskPct float64) (float64, error) {
+	if entry <= 0 || quantity <= 0 {
+		return 0, fmt.Errorf("entry and quantity must be positive")
+	}
+	if balance <= 0 || riskPct <= 0 {
+		return 0, fmt.Errorf("balance and risk percent must be positive")
+	}
+	distance := balance * riskPct / 100 / quantity
+	if side == "short" {
+		return entry + distance, nil
+	}
+	if distance >= entry {
+		return 0, fmt.Errorf("risk exceeds the position value, no stop needed")
+	}
+	return entry - distance, nil
+}
@@ -0,0 +1,30 @@
+package decision
+
+import (
+	"math"
+	"testing"
+)
+
+// TestRiskSizing tests that quantity and stop loss sizing are inverses
+func TestRiskSizing(t *testing.T) {
+	// 1% of 10,000 = 100 USDT risk over a 500 stop distance
+	qty, err := RiskQuantity(60000, 59500, 10000, 1)
+	if err != nil || math.Abs(qty-0.2) > 1e-9 {
+		t.Fatalf("expected 0.2, got %v (%v)", qty, err)
+	}
+	stop, err := RiskStopLoss("long", 60000, qty, 10000, 1)
+	if err != nil || math.Abs(stop-59500) > 1e-6 {
+		t.Errorf("expected stop 59500, got %v (%v)", stop, err)
+	}
+	stop, _ = RiskStopLoss("short", 60000, qty, 10000, 1)
+	if math.Abs(stop-60500) > 1e-6 {
+		t.Errorf("expected short stop 60500, got %v", stop)
+	}
+
+	if _, err := RiskQuantity(60000, 60000, 10000, 1); err == nil {
+		t.Error("stop at entry should be rejected")
+	}
+	if _, err := RiskQuantity(60000, 59500, 10000, 0); err == nil {
+		t.Error("zero risk should be rejected")
+	}
+}
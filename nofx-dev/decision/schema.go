@@ -0,0 +1,294 @@
+package decision
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"nofx/logger"
+	"nofx/mcp"
+	"sort"
+	"strings"
+)
+
+// DecisionSchema JSON schema (draft-07 subset) of the decision array the AI outputs inside <decision></decision>.
+// Unknown keys are rejected so a misspelled field (position_size instead of position_size_usd) can't silently
+// turn an order into a no-op
+const DecisionSchema = `{
+  "type": "array",
+  "items": {
+    "type": "object",
+    "additionalProperties": false,
+    "required": ["symbol", "action"],
+    "properties": {
+      "symbol": {"type": "string", "minLength": 1},
+      "action": {"type": "string", "enum": ["open_long", "open_short", "add_long", "add_short", "close_long", "close_short",
+        "partial_close_long", "partial_close_short", "manage_exit_long", "manage_exit_short", "alert", "hold", "wait"]},
+      "leverage": {"type": "integer", "minimum": 0},
+      "position_size_usd": {"type": "number", "minimum": 0},
+      "stop_loss": {"type": "number", "minimum": 0},
+      "take_profit": {"type": "number", "minimum": 0},
+      "trailing_stop_pct": {"type": "number", "minimum": 0},
+      "close_pct": {"type": "number", "minimum": 0, "maximum": 100},
+      "managed_exit": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["mode", "atr_multiplier"],
+        "properties": {
+          "mode": {"type": "string", "enum": ["chandelier", "atr_trail"]},
+          "atr_multiplier": {"type": "number", "minimum": 0},
+          "timeframe": {"type": "string"}
+        }
+      },
+      "alert_type": {"type": "string", "enum": ["breakout", "breakdown", "oi_surge", "volume_spike", "funding_shift", "commentary"]},
+      "confidence": {"type": "integer", "minimum": 0, "maximum": 100},
+      "risk_usd": {"type": "number", "minimum": 0},
+      "reasoning": {"type": "string"}
+    },
+    "allOf": [
+      {"if": {"properties": {"action": {"enum": ["open_long", "open_short", "add_long", "add_short"]}}},
+       "then": {"required": ["confidence"]}},
+      {"if": {"properties": {"action": {"enum": ["open_long", "open_short"]}}},
+       "then": {"required": ["leverage", "position_size_usd", "stop_loss", "take_profit"]}},
+      {"if": {"properties": {"action": {"enum": ["add_long", "add_short"]}}},
+       "then": {"required": ["position_size_usd"]}},
+      {"if": {"properties": {"action": {"enum": ["partial_close_long", "partial_close_short"]}}},
+       "then": {"required": ["close_pct"]}},
+      {"if": {"properties": {"action": {"enum": ["manage_exit_long", "manage_exit_short"]}}},
+       "then": {"required": ["managed_exit"]}},
+      {"if": {"properties": {"action": {"const": "alert"}}},
+       "then": {"required": ["reasoning"]}}
+    ]
+  }
+}`
+
+// maxSchemaViolations violations reported back to the model, the first ones are enough to fix the output
+const maxSchemaViolations = 10
+
+// SchemaError the AI response has no decision array, invalid JSON or JSON that doesn't match DecisionSchema
+type SchemaError struct {
+	Violations []string
+}
+
+func (e *SchemaError) Error() string {
+	return "decision JSON doesn't match the schema: " + strings.Join(e.Violations, "; ")
+}
+
+// jsonSchema the JSON schema keywords DecisionSchema uses
+type jsonSchema struct {
+	Type                 string                 `json:"type"`
+	Enum                 []any                  `json:"enum"`
+	Const                any                    `json:"const"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	MinLength            int                    `json:"minLength"`
+	AllOf                []*jsonSchema          `json:"allOf"`
+	If                   *jsonSchema            `json:"if"`
+	Then                 *jsonSchema            `json:"then"`
+}
+
+var decisionSchema = mustParseSchema(DecisionSchema)
+
+func mustParseSchema(raw string) *jsonSchema {
+	var s jsonSchema
+	if err := json.Unmarshal([]byte(raw), &s); err != nil {
+		panic(fmt.Sprintf("invalid JSON schema: %v", err))
+	}
+	return &s
+}
+
+// ValidateDecisionJSON validates a decision array against DecisionSchema, returns the violations (nil when valid)
+func ValidateDecisionJSON(jsonContent string) []string {
+	var v any
+	if err := json.Unmarshal([]byte(jsonContent), &v); err != nil {
+		return []string{fmt.Sprintf("invalid JSON: %v", err)}
+	}
+	var violations []string
+	decisionSchema.validate(v, "$", &violations)
+	if len(violations) > maxSchemaViolations {
+		violations = append(violations[:maxSchemaViolations], fmt.Sprintf("... and %d more", len(violations)-maxSchemaViolations))
+	}
+	return violations
+}
+
+// validate appends the violations of v (at path) to out
+func (s *jsonSchema) validate(v any, path string, out *[]string) {
+	if s.Type != "" && !matchesType(s.Type, v) {
+		*out = append(*out, fmt.Sprintf("%s: expected %s, got %s", path, s.Type, jsonTypeName(v)))
+		return
+	}
+	if s.Const != nil && !jsonEqual(s.Const, v) {
+		*out = append(*out, fmt.Sprintf("%s: must be %v", path, s.Const))
+	}
+	if len(s.Enum) > 0 && !s.inEnum(v) {
+		*out = append(*out, fmt.Sprintf("%s: %v is not one of %v", path, v, s.Enum))
+	}
+
+	switch val := v.(type) {
+	case string:
+		if len(val) < s.MinLength {
+			*out = append(*out, fmt.Sprintf("%s: must not be empty", path))
+		}
+	case float64:
+		if s.Minimum != nil && val < *s.Minimum {
+			*out = append(*out, fmt.Sprintf("%s: %v is below the minimum %v", path, val, *s.Minimum))
+		}
+		if s.Maximum != nil && val > *s.Maximum {
+			*out = append(*out, fmt.Sprintf("%s: %v is above the maximum %v", path, val, *s.Maximum))
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range val {
+				s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), out)
+			}
+		}
+	case map[string]any:
+		for _, key := range s.Required {
+			if _, ok := val[key]; !ok {
+				*out = append(*out, fmt.Sprintf("%s: missing required field %q", path, key))
+			}
+		}
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if prop, ok := s.Properties[key]; ok {
+				prop.validate(val[key], path+"."+key, out)
+			} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				*out = append(*out, fmt.Sprintf("%s: unknown field %q", path, key))
+			}
+		}
+	}
+
+	for _, sub := range s.AllOf {
+		if sub.If != nil && sub.Then != nil {
+			var ifViolations []string
+			sub.If.validate(v, path, &ifViolations)
+			if len(ifViolations) == 0 {
+				sub.Then.validate(v, path, out)
+			}
+			continue
+		}
+		sub.validate(v, path, out)
+	}
+}
+
+func (s *jsonSchema) inEnum(v any) bool {
+	for _, e := range s.Enum {
+		if jsonEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonEqual(a, b any) bool {
+	return fmt.Sprint(a) == fmt.Sprint(b) && jsonTypeName(a) == jsonTypeName(b)
+}
+
+func matchesType(typ string, v any) bool {
+	switch typ {
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	default:
+		return jsonTypeName(v) == typ
+	}
+}
+
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// Schema repair outcomes recorded on the decision
+const (
+	SchemaRepaired = "repaired" // The model fixed its JSON on the repair request
+	SchemaFailed   = "failed"   // The fix was invalid too, the cycle held
+)
+
+const schemaRepairPrompt = `Your previous trading decision output could not be executed because its JSON is invalid.
+Reply with the corrected decisions only, as <decision>[...]</decision> containing a JSON array that matches this schema:
+%s
+Keep the same trading intent, only fix the format. No commentary outside the <decision> tag.`
+
+// parseDecisionWithRepair parses the AI response. When it doesn't match DecisionSchema the model is asked once to
+// fix its JSON, and when the fix fails too the cycle falls back to hold, so a malformed answer is always recorded
+// instead of silently becoming a no-op
+func parseDecisionWithRepair(client mcp.AIClient, response string, accountEquity float64, btcEthLeverage, altcoinLeverage int) (*FullDecision, error) {
+	full, err := parseFullDecisionResponse(response, accountEquity, btcEthLeverage, altcoinLeverage)
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		return full, err
+	}
+	violations := schemaErr.Violations
+	logger.Warnf("⚠️ AI decision doesn't match the schema, requesting a fix: %v", schemaErr)
+
+	req, err := mcp.NewRequestBuilder().
+		WithSystemPrompt(fmt.Sprintf(schemaRepairPrompt, DecisionSchema)).
+		WithUserPrompt(fmt.Sprintf("Previous output:\n%s\n\nSchema violations:\n- %s", response, strings.Join(violations, "\n- "))).
+		Build()
+	var repairResponse string
+	if err == nil {
+		repairResponse, err = client.CallWithRequest(req)
+	}
+	if err == nil {
+		fixed, fixErr := parseFullDecisionResponse(repairResponse, accountEquity, btcEthLeverage, altcoinLeverage)
+		if !errors.As(fixErr, &schemaErr) {
+			logger.Infof("✓ AI decision JSON repaired")
+			// Keep the original reasoning, the repair answer only carries the JSON
+			fixed.CoTTrace = full.CoTTrace
+			fixed.SchemaViolations = violations
+			fixed.SchemaRepair = SchemaRepaired
+			fixed.RepairResponse = repairResponse
+			return fixed, fixErr
+		}
+		err = fixErr
+	}
+
+	logger.Warnf("⚠️ AI decision JSON repair failed, holding this cycle: %v", err)
+	return &FullDecision{
+		CoTTrace: full.CoTTrace,
+		Decisions: []Decision{{
+			Symbol:    "ALL",
+			Action:    "hold",
+			Reasoning: fmt.Sprintf("Decision JSON invalid and the repair failed, holding: %s", strings.Join(violations, "; ")),
+		}},
+		SchemaViolations: violations,
+		SchemaRepair:     SchemaFailed,
+		RepairResponse:   repairResponse,
+	}, nil
+}
+
+// SchemaRepairNote execution log line about the schema repair ("" when the first response was valid)
+func (d *FullDecision) SchemaRepairNote() string {
+	switch d.SchemaRepair {
+	case SchemaRepaired:
+		return fmt.Sprintf("🔧 AI decision JSON repaired after schema violations: %s", strings.Join(d.SchemaViolations, "; "))
+	case SchemaFailed:
+		return fmt.Sprintf("⚠️ AI decision JSON invalid after repair, holding: %s", strings.Join(d.SchemaViolations, "; "))
+	}
+	return ""
+}
@@ -0,0 +1,114 @@
+package decision
+
+import (
+	"errors"
+	"nofx/mcp"
+	"strings"
+	"testing"
+)
+
+// repairClient AI client answering repair requests with a fixed response
+type repairClient struct {
+	fakeAIClient
+	response string
+	requests []*mcp.Request
+}
+
+func (c *repairClient) CallWithRequest(req *mcp.Request) (string, error) {
+	c.requests = append(c.requests, req)
+	return c.response, nil
+}
+
+// TestValidateDecisionJSON tests types, enums, unknown fields and per-action required fields
+func TestValidateDecisionJSON(t *testing.T) {
+	valid := `[{"symbol":"BTCUSDT","action":"open_long","leverage":5,"position_size_usd":1000,"stop_loss":60000,"take_profit":70000,"confidence":80},
+		{"symbol":"ETHUSDT","action":"manage_exit_long","managed_exit":{"mode":"chandelier","atr_multiplier":3},"confidence":70},
+		{"symbol":"SOLUSDT","action":"close_short"},
+		{"symbol":"BNBUSDT","action":"partial_close_long","close_pct":50},
+		{"symbol":"ALL","action":"wait","reasoning":"no setup"}]`
+	if violations := ValidateDecisionJSON(valid); len(violations) != 0 {
+		t.Errorf("expected valid decisions, got %v", violations)
+	}
+
+	tests := []struct {
+		name, json, want string
+	}{
+		{"misspelled field", `[{"symbol":"BTCUSDT","action":"open_long","leverage":5,"position_size":1000,"stop_loss":1,"take_profit":2}]`, `unknown field "position_size"`},
+		{"missing entry field", `[{"symbol":"BTCUSDT","action":"open_long","leverage":5,"stop_loss":1,"take_profit":2}]`, `missing required field "position_size_usd"`},
+		{"number as string", `[{"symbol":"BTCUSDT","action":"close_long","confidence":"80"}]`, "$[0].confidence: expected integer, got string"},
+		{"fractional leverage", `[{"symbol":"BTCUSDT","action":"add_long","position_size_usd":100,"leverage":2.5}]`, "expected integer"},
+		{"unknown action", `[{"symbol":"BTCUSDT","action":"buy"}]`, "buy is not one of"},
+		{"missing entry confidence", `[{"symbol":"BTCUSDT","action":"add_short","position_size_usd":100}]`, `missing required field "confidence"`},
+		{"close pct range", `[{"symbol":"BTCUSDT","action":"partial_close_long","close_pct":150}]`, "above the maximum 100"},
+		{"nested object", `[{"symbol":"BTCUSDT","action":"manage_exit_short","managed_exit":{"mode":"fixed","atr_multiplier":2}}]`, "$[0].managed_exit.mode"},
+		{"not an array", `{"symbol":"BTCUSDT","action":"wait"}`, "expected array, got object"},
+	}
+	for _, tt := range tests {
+		violations := ValidateDecisionJSON(tt.json)
+		if !strings.Contains(strings.Join(violations, "\n"), tt.want) {
+			t.Errorf("%s: expected violation containing %q, got %v", tt.name, tt.want, violations)
+		}
+	}
+}
+
+// TestDecisionSchemaAlertTypes tests the schema's alert types stay in sync with the validator
+func TestDecisionSchemaAlertTypes(t *testing.T) {
+	enum := decisionSchema.Items.Properties["alert_type"].Enum
+	if len(enum) != len(alertTypes) {
+		t.Fatalf("schema has %d alert types, validator %d", len(enum), len(alertTypes))
+	}
+	for _, e := range enum {
+		if !alertTypes[e.(string)] {
+			t.Errorf("alert type %v isn't accepted by the validator", e)
+		}
+	}
+}
+
+// TestExtractDecisions_SchemaError tests that malformed output is a schema error instead of a silent no-op
+func TestExtractDecisions_SchemaError(t *testing.T) {
+	var schemaErr *SchemaError
+	if _, err := extractDecisions("I'd wait for a pullback before entering."); !errors.As(err, &schemaErr) {
+		t.Errorf("a response without JSON should be a schema error, got %v", err)
+	}
+	if _, err := extractDecisions(`<decision>[{"symbol":"BTCUSDT","action":"close_long","qty":1}]</decision>`); !errors.As(err, &schemaErr) {
+		t.Errorf("unknown fields should be a schema error, got %v", err)
+	}
+	if decisions, err := extractDecisions("<decision>[]</decision>"); err != nil || len(decisions) != 0 {
+		t.Errorf("an empty decision array is valid, got %v %v", decisions, err)
+	}
+}
+
+// TestParseDecisionWithRepair tests the single repair request and the hold fallback
+func TestParseDecisionWithRepair(t *testing.T) {
+	broken := `Reasoning: BTC lost support.<decision>[{"symbol":"BTCUSDT","action":"close_long","size":"all"}]</decision>`
+
+	client := &repairClient{response: `<decision>[{"symbol":"BTCUSDT","action":"close_long","confidence":75}]</decision>`}
+	full, err := parseDecisionWithRepair(client, broken, 1000, 10, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(client.requests) != 1 || !strings.Contains(client.requests[0].Messages[1].Content, `unknown field "size"`) {
+		t.Fatalf("expected one repair request listing the violations, got %+v", client.requests)
+	}
+	if full.SchemaRepair != SchemaRepaired || len(full.Decisions) != 1 || full.Decisions[0].Action != "close_long" ||
+		full.CoTTrace != "Reasoning: BTC lost support." {
+		t.Errorf("expected the repaired decision with the original reasoning, got %+v", full)
+	}
+
+	client = &repairClient{response: "Sorry, I can't help with that."}
+	full, err = parseDecisionWithRepair(client, broken, 1000, 10, 5)
+	if err != nil {
+		t.Fatalf("a failed repair should hold, not error: %v", err)
+	}
+	if len(client.requests) != 1 || full.SchemaRepair != SchemaFailed || len(full.Decisions) != 1 || full.Decisions[0].Action != "hold" {
+		t.Errorf("expected a single repair attempt and a hold, got %+v", full)
+	}
+	if !strings.Contains(full.SchemaRepairNote(), `unknown field "size"`) {
+		t.Errorf("the note should list the violations: %s", full.SchemaRepairNote())
+	}
+
+	client = &repairClient{}
+	if full, _ := parseDecisionWithRepair(client, `<decision>[{"symbol":"BTCUSDT","action":"wait"}]</decision>`, 1000, 10, 5); len(client.requests) != 0 || full.SchemaRepair != "" {
+		t.Error("valid responses shouldn't be repaired")
+	}
+}
@@ -0,0 +1,53 @@
+package decision
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// StopCooldown blocks re-entering a symbol for a while after a stop-loss exit, so a stop hit in a choppy
+// market isn't immediately followed by the same trade (stop_loss_cooldown_minutes). The zero value is ready to use
+type StopCooldown struct {
+	mu    sync.Mutex
+	until map[string]time.Time // Symbol -> end of its cooldown
+}
+
+// Record starts the cooldown of symbol at exitTime (d <= 0 does nothing)
+// A later cooldown end replaces an earlier one, never the other way round
+func (c *StopCooldown) Record(symbol string, exitTime time.Time, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.until == nil {
+		c.until = make(map[string]time.Time)
+	}
+	if end := exitTime.Add(d); end.After(c.until[symbol]) {
+		c.until[symbol] = end
+	}
+}
+
+// Remaining time symbol stays blocked at now (0 if it isn't cooling down)
+func (c *StopCooldown) Remaining(symbol string, now time.Time) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	end, ok := c.until[symbol]
+	if !ok {
+		return 0
+	}
+	if !now.Before(end) {
+		delete(c.until, symbol)
+		return 0
+	}
+	return end.Sub(now)
+}
+
+// Check returns an error when symbol is still cooling down at now (CODE ENFORCED entry rule)
+func (c *StopCooldown) Check(symbol string, now time.Time) error {
+	if left := c.Remaining(symbol, now); left > 0 {
+		return fmt.Errorf("❌ %s hit its stop loss recently, re-entry blocked for another %s", symbol, left.Round(time.Second))
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package decision
+
+import (
+	"testing"
+	"time"
+)
+
+// TestStopCooldown tests blocking a symbol for the cooldown after a stop-loss exit
+func TestStopCooldown(t *testing.T) {
+	var c StopCooldown
+	exit := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := c.Check("BTCUSDT", exit); err != nil {
+		t.Fatalf("unrecorded symbol should not be blocked: %v", err)
+	}
+	c.Record("BTCUSDT", exit, 30*time.Minute)
+	c.Record("BTCUSDT", exit, 10*time.Minute) // Shorter cooldown must not cut the running one
+	c.Record("ETHUSDT", exit, 0)
+
+	if left := c.Remaining("BTCUSDT", exit.Add(20*time.Minute)); left != 10*time.Minute {
+		t.Errorf("expected 10m left, got %s", left)
+	}
+	if err := c.Check("BTCUSDT", exit.Add(29*time.Minute)); err == nil {
+		t.Error("expected re-entry to be blocked inside the cooldown")
+	}
+	if err := c.Check("BTCUSDT", exit.Add(30*time.Minute)); err != nil {
+		t.Errorf("cooldown should end after 30m: %v", err)
+	}
+	if err := c.Check("ETHUSDT", exit); err != nil {
+		t.Errorf("zero cooldown should not block: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package decision
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"nofx/market"
+	"strings"
+)
+
+// symbolPrompt the strategy's instructions for the symbol ("" if none); keys match in any symbol format
+// ("BTC", "BTC/USDT", "BTCUSDT")
+func (e *StrategyEngine) symbolPrompt(symbol string) string {
+	if text, ok := e.config.SymbolPrompts[symbol]; ok {
+		return strings.TrimSpace(text)
+	}
+	normalized := market.Normalize(symbol)
+	for key, text := range e.config.SymbolPrompts {
+		if market.Normalize(key) == normalized {
+			return strings.TrimSpace(text)
+		}
+	}
+	return ""
+}
+
+// formatSymbolPrompt the symbol's instructions for its section of the user prompt
+func (e *StrategyEngine) formatSymbolPrompt(symbol string) string {
+	text := e.symbolPrompt(symbol)
+	if text == "" {
+		return ""
+	}
+	return fmt.Sprintf("📌 Instructions for %s (follow them for this symbol, they override the general rules): %s\n\n", symbol, text)
+}
+
+// PromptHash short hash of the strategy's prompt configuration (editable sections, custom prompt, partials and
+// per-symbol instructions), recorded with each decision so decisions can be grouped by the prompt that made them
+func (e *StrategyEngine) PromptHash() string {
+	// Maps are marshaled with sorted keys, so the hash doesn't depend on map order
+	data, _ := json.Marshal(struct {
+		Sections      any               `json:"sections"`
+		CustomPrompt  string            `json:"custom_prompt"`
+		Partials      map[string]string `json:"partials"`
+		SymbolPrompts map[string]string `json:"symbol_prompts"`
+	}{e.config.PromptSections, e.config.CustomPrompt, e.config.PromptPartials, e.config.SymbolPrompts})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,62 @@
+package decision
+
+import (
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"testing"
+)
+
+// TestBuildUserPrompt_SymbolPrompts tests that each symbol's instructions land in its own section
+func TestBuildUserPrompt_SymbolPrompts(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.SymbolPrompts = map[string]string{
+		"BTC":      "never short BTC",
+		"ETH/USDT": " ETH max leverage 3x ",
+		"SOLUSDT":  "",
+	}
+	engine := NewStrategyEngine(&cfg)
+	ctx := &Context{
+		Account:        AccountInfo{TotalEquity: 1000, AvailableBalance: 1000},
+		Positions:      []PositionInfo{{Symbol: "ETHUSDT", Side: "long", Quantity: 1, MarkPrice: 3000}},
+		CandidateCoins: []CandidateCoin{{Symbol: "BTCUSDT"}, {Symbol: "SOLUSDT"}},
+		MarketDataMap: map[string]*market.Data{
+			"BTCUSDT": {Symbol: "BTCUSDT", CurrentPrice: 60000},
+			"ETHUSDT": {Symbol: "ETHUSDT", CurrentPrice: 3000},
+			"SOLUSDT": {Symbol: "SOLUSDT", CurrentPrice: 150},
+		},
+	}
+
+	prompt := engine.BuildUserPrompt(ctx)
+	btc := strings.Index(prompt, "### 1. BTCUSDT")
+	if i := strings.Index(prompt, "📌 Instructions for BTCUSDT (follow them for this symbol, they override the general rules): never short BTC"); i < btc || btc < 0 {
+		t.Error("expected the BTC instructions in the BTCUSDT candidate section")
+	}
+	if i := strings.Index(prompt, "📌 Instructions for ETHUSDT (follow them for this symbol, they override the general rules): ETH max leverage 3x\n"); i < 0 || i > btc {
+		t.Error("expected the ETH instructions in the ETHUSDT position section")
+	}
+	if strings.Contains(prompt, "Instructions for SOLUSDT") {
+		t.Error("expected no instructions line for an empty override")
+	}
+}
+
+// TestPromptHash tests that the hash follows the prompt configuration, symbol instructions included
+func TestPromptHash(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	base := NewStrategyEngine(&cfg).PromptHash()
+	if len(base) != 12 {
+		t.Fatalf("expected a 12 character hash, got %q", base)
+	}
+
+	withSymbol := cfg
+	withSymbol.SymbolPrompts = map[string]string{"BTCUSDT": "never short BTC", "ETHUSDT": "max leverage 3x"}
+	hash := NewStrategyEngine(&withSymbol).PromptHash()
+	if hash == base {
+		t.Error("expected symbol instructions to change the prompt hash")
+	}
+	reordered := cfg
+	reordered.SymbolPrompts = map[string]string{"ETHUSDT": "max leverage 3x", "BTCUSDT": "never short BTC"}
+	if NewStrategyEngine(&reordered).PromptHash() != hash {
+		t.Error("expected the same hash for the same instructions")
+	}
+}
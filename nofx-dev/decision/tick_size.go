@@ -0,0 +1,36 @@
+package decision
+
+import (
+	"fmt"
+	"nofx/market"
+)
+
+// RoundPricesToTick rounds stop loss / take profit of open and add decisions to the symbol's tick size so
+// the exchange accepts them, returns an execution log note per changed decision
+// Symbols without market data or tick size keep the AI's prices
+func RoundPricesToTick(decisions []Decision, marketData map[string]*market.Data) []string {
+	var notes []string
+	for i := range decisions {
+		d := &decisions[i]
+		switch d.Action {
+		case "open_long", "open_short", "add_long", "add_short":
+		default:
+			continue
+		}
+		data, ok := marketData[d.Symbol]
+		if !ok || data == nil || data.TickSize <= 0 {
+			continue
+		}
+		stop := market.RoundToTick(d.StopLoss, data.TickSize)
+		target := market.RoundToTick(d.TakeProfit, data.TickSize)
+		if stop == d.StopLoss && target == d.TakeProfit {
+			continue
+		}
+		notes = append(notes, fmt.Sprintf("🎯 %s prices rounded to tick %s: stop loss %.10g → %s, take profit %.10g → %s",
+			d.Symbol, market.FormatPriceTick(data.TickSize, data.TickSize), d.StopLoss, data.FormatPrice(stop),
+			d.TakeProfit, data.FormatPrice(target)))
+		d.StopLoss = stop
+		d.TakeProfit = target
+	}
+	return notes
+}
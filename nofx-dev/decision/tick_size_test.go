@@ -0,0 +1,40 @@
+package decision
+
+import (
+	"nofx/market"
+	"strings"
+	"testing"
+)
+
+// TestRoundPricesToTick tests rounding open / add prices to the symbol's tick size
+func TestRoundPricesToTick(t *testing.T) {
+	data := map[string]*market.Data{
+		"BTCUSDT":  {CurrentPrice: 60000, TickSize: 0.1},
+		"PEPEUSDT": {CurrentPrice: 0.00001234, TickSize: 0.0000001},
+		"ETHUSDT":  {CurrentPrice: 3000}, // Tick size unknown
+	}
+	decisions := []Decision{
+		{Symbol: "BTCUSDT", Action: "open_long", StopLoss: 59123.456, TakeProfit: 61000.04},
+		{Symbol: "PEPEUSDT", Action: "add_short", StopLoss: 0.000012912345, TakeProfit: 0.0000115},
+		{Symbol: "ETHUSDT", Action: "open_short", StopLoss: 3100.123},
+		{Symbol: "BTCUSDT", Action: "close_long"},
+	}
+
+	notes := RoundPricesToTick(decisions, data)
+	if len(notes) != 2 || !strings.Contains(notes[0], "59123.5") {
+		t.Fatalf("expected 2 notes, got %v", notes)
+	}
+	if decisions[0].StopLoss != 59123.5 || decisions[0].TakeProfit != 61000 {
+		t.Errorf("BTC prices should be on the 0.1 grid, got %v / %v", decisions[0].StopLoss, decisions[0].TakeProfit)
+	}
+	if decisions[1].StopLoss != 0.0000129 || decisions[1].TakeProfit != 0.0000115 {
+		t.Errorf("PEPE prices should be on the 1e-7 grid, got %v / %v", decisions[1].StopLoss, decisions[1].TakeProfit)
+	}
+	if decisions[2].StopLoss != 3100.123 {
+		t.Errorf("prices without tick size should be kept, got %v", decisions[2].StopLoss)
+	}
+
+	if notes := RoundPricesToTick(decisions, data); len(notes) != 0 {
+		t.Errorf("prices already on the grid shouldn't be noted again: %v", notes)
+	}
+}
@@ -0,0 +1,303 @@
+package decision
+
+import (
+	"encoding/json"
+	"fmt"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/mcp"
+	"strings"
+)
+
+// Decision tools the model can call while deciding (Indicators.EnableToolCalling): the prompt carries compact
+// market data and the model fetches the detail it needs instead of receiving every series up front
+const (
+	ToolGetKlines          = "get_klines"
+	ToolGetOrderBook       = "get_orderbook"
+	ToolGetPositionHistory = "get_position_history"
+
+	// maxToolRounds model turns that may call tools, the next turn must answer with the decision
+	maxToolRounds = 4
+	// maxToolCalls tool calls executed per decision, further calls are answered with an error
+	maxToolCalls = 12
+
+	defaultToolKlines  = 50
+	maxToolKlines      = 200
+	defaultToolDepth   = 10
+	maxToolDepth       = 50
+	defaultToolHistory = 10
+)
+
+// toolIntervals kline intervals get_klines accepts
+var toolIntervals = map[string]bool{
+	"1m": true, "3m": true, "5m": true, "15m": true, "30m": true,
+	"1h": true, "2h": true, "4h": true, "6h": true, "12h": true, "1d": true, "1w": true,
+}
+
+// ToolSource market data behind the decision tools
+type ToolSource interface {
+	Klines(symbol, interval string, limit int) ([]market.Kline, error)
+	OrderBook(symbol string, depth int) (*market.OrderBook, error)
+}
+
+// LiveToolSource live exchange data (order book from the depth stream when the WebSocket monitor runs)
+type LiveToolSource struct{}
+
+func (LiveToolSource) Klines(symbol, interval string, limit int) ([]market.Kline, error) {
+	return market.NewAPIClient().GetKlines(symbol, interval, limit)
+}
+
+func (LiveToolSource) OrderBook(symbol string, depth int) (*market.OrderBook, error) {
+	if market.WSMonitorCli != nil {
+		if book, err := market.WSMonitorCli.GetOrderBook(symbol); err == nil {
+			return book, nil
+		}
+	}
+	return market.NewAPIClient().GetDepth(symbol, depth)
+}
+
+// toolArgs arguments of the decision tools
+type toolArgs struct {
+	Symbol   string `json:"symbol"`
+	Interval string `json:"interval"`
+	Limit    int    `json:"limit"`
+	Depth    int    `json:"depth"`
+}
+
+// decisionTools tool definitions offered to the model
+func decisionTools() []mcp.Tool {
+	symbol := map[string]any{"type": "string", "description": "Trading pair from the candidate coins or positions, e.g. BTCUSDT"}
+	return []mcp.Tool{
+		{Type: "function", Function: mcp.FunctionDef{
+			Name:        ToolGetKlines,
+			Description: "OHLCV candles of a symbol, oldest first: [open_time_ms, open, high, low, close, volume]",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"symbol":   symbol,
+					"interval": map[string]any{"type": "string", "enum": []string{"1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "12h", "1d", "1w"}},
+					"limit":    map[string]any{"type": "integer", "minimum": 1, "maximum": maxToolKlines, "description": fmt.Sprintf("Candles to return (default %d)", defaultToolKlines)},
+				},
+				"required": []string{"symbol", "interval"},
+			},
+		}},
+		{Type: "function", Function: mcp.FunctionDef{
+			Name:        ToolGetOrderBook,
+			Description: "Order book snapshot of a symbol: bids (best first) and asks (best first) as [price, quantity]",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"symbol": symbol,
+					"depth":  map[string]any{"type": "integer", "minimum": 1, "maximum": maxToolDepth, "description": fmt.Sprintf("Levels per side (default %d)", defaultToolDepth)},
+				},
+				"required": []string{"symbol"},
+			},
+		}},
+		{Type: "function", Function: mcp.FunctionDef{
+			Name:        ToolGetPositionHistory,
+			Description: "Recently closed trades of this trader, newest first, optionally of one symbol",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"symbol": symbol,
+					"limit":  map[string]any{"type": "integer", "minimum": 1, "description": fmt.Sprintf("Trades to return (default %d)", defaultToolHistory)},
+				},
+			},
+		}},
+	}
+}
+
+// toolPrompt system prompt section describing the tools
+func toolPrompt() string {
+	var sb strings.Builder
+	sb.WriteString("\n\n# Tools\n\n")
+	sb.WriteString("The market data in the user prompt is a compact summary. Before deciding you can call tools to fetch detail:\n")
+	sb.WriteString(fmt.Sprintf("- %s: candles of any interval (e.g. to check structure on a higher timeframe)\n", ToolGetKlines))
+	sb.WriteString(fmt.Sprintf("- %s: bids / asks around the price (e.g. to place stops beyond liquidity)\n", ToolGetOrderBook))
+	sb.WriteString(fmt.Sprintf("- %s: this trader's recently closed trades\n", ToolGetPositionHistory))
+	sb.WriteString(fmt.Sprintf("At most %d tool calls over %d rounds, only for candidate coins and open positions. ", maxToolCalls, maxToolRounds))
+	sb.WriteString("When you have enough information, answer in the required <reasoning> / <decision> format.")
+	return sb.String()
+}
+
+// toolCaller the client's tool-calling interface when the strategy enables tools and the context has a data source
+func toolCaller(client mcp.AIClient, engine *StrategyEngine, ctx *Context) (mcp.ToolCaller, bool) {
+	if !engine.config.Indicators.EnableToolCalling || ctx.ToolSource == nil {
+		return nil, false
+	}
+	caller, ok := client.(mcp.ToolCaller)
+	if !ok {
+		logger.Infof("⚠️  AI client doesn't support tool calling, sending the full prompt")
+	}
+	return caller, ok
+}
+
+// runToolLoop runs the conversation until the model answers without tool calls, executing its tool calls for at
+// most maxToolRounds turns. Returns the final answer and a log line per tool call
+func runToolLoop(ctx *Context, client mcp.ToolCaller, systemPrompt, userPrompt string) (string, []string, error) {
+	messages := []mcp.Message{mcp.NewSystemMessage(systemPrompt), mcp.NewUserMessage(userPrompt)}
+	tools := decisionTools()
+	var calls []string
+
+	for round := 0; ; round++ {
+		final := round == maxToolRounds || len(calls) >= maxToolCalls
+		req := &mcp.Request{Messages: messages, Tools: tools, ToolChoice: "auto"}
+		if final && round > 0 {
+			req.ToolChoice = "none"
+			req.Messages = append(req.Messages, mcp.NewUserMessage("Tool budget used up, answer now with your <reasoning> and <decision>."))
+		}
+
+		resp, err := client.CallWithTools(req)
+		if err != nil {
+			return "", calls, err
+		}
+		if len(resp.ToolCalls) == 0 || final {
+			if strings.TrimSpace(resp.Content) == "" {
+				return "", calls, fmt.Errorf("model answered without a decision after %d tool calls", len(calls))
+			}
+			return resp.Content, calls, nil
+		}
+
+		messages = append(messages, mcp.NewAssistantToolCallMessage(resp.Content, resp.ToolCalls))
+		for _, call := range resp.ToolCalls {
+			result := `{"error":"tool call limit reached, answer with the decision"}`
+			if len(calls) < maxToolCalls {
+				result = runTool(ctx, call)
+			}
+			calls = append(calls, fmt.Sprintf("🔧 %s %s → %d bytes", call.Function.Name, call.Function.Arguments, len(result)))
+			messages = append(messages, mcp.NewToolResultMessage(call.ID, result))
+		}
+	}
+}
+
+// runTool executes a tool call, errors are returned to the model as {"error": ...}
+func runTool(ctx *Context, call mcp.ToolCall) string {
+	var args toolArgs
+	if call.Function.Arguments != "" {
+		if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+			return toolError(fmt.Errorf("invalid arguments: %w", err))
+		}
+	}
+
+	var result any
+	var err error
+	switch call.Function.Name {
+	case ToolGetKlines:
+		result, err = toolKlines(ctx, args)
+	case ToolGetOrderBook:
+		result, err = toolOrderBook(ctx, args)
+	case ToolGetPositionHistory:
+		result = toolPositionHistory(ctx, args)
+	default:
+		err = fmt.Errorf("unknown tool %q", call.Function.Name)
+	}
+	if err != nil {
+		return toolError(err)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return toolError(err)
+	}
+	return string(data)
+}
+
+func toolError(err error) string {
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return string(data)
+}
+
+// toolSymbol resolves the symbol argument, limited to the cycle's candidates and positions
+func toolSymbol(ctx *Context, symbol string) (string, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return "", fmt.Errorf("symbol is required")
+	}
+	for _, c := range ctx.CandidateCoins {
+		if c.Symbol == symbol {
+			return symbol, nil
+		}
+	}
+	for _, p := range ctx.Positions {
+		if p.Symbol == symbol {
+			return symbol, nil
+		}
+	}
+	return "", fmt.Errorf("%s is neither a candidate coin nor an open position", symbol)
+}
+
+func toolKlines(ctx *Context, args toolArgs) (any, error) {
+	symbol, err := toolSymbol(ctx, args.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	if !toolIntervals[args.Interval] {
+		return nil, fmt.Errorf("unsupported interval %q", args.Interval)
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultToolKlines
+	}
+	limit = min(limit, maxToolKlines)
+
+	klines, err := ctx.ToolSource.Klines(symbol, args.Interval, limit)
+	if err != nil {
+		return nil, err
+	}
+	var tick float64
+	if data := ctx.MarketDataMap[symbol]; data != nil {
+		tick = data.TickSize
+	}
+	rows := make([][]any, 0, len(klines))
+	for _, k := range klines {
+		rows = append(rows, []any{k.OpenTime, toolPrice(k.Open, tick), toolPrice(k.High, tick), toolPrice(k.Low, tick), toolPrice(k.Close, tick), k.Volume})
+	}
+	return map[string]any{"symbol": symbol, "interval": args.Interval, "klines": rows}, nil
+}
+
+func toolOrderBook(ctx *Context, args toolArgs) (any, error) {
+	symbol, err := toolSymbol(ctx, args.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	depth := args.Depth
+	if depth <= 0 {
+		depth = defaultToolDepth
+	}
+	depth = min(depth, maxToolDepth)
+
+	book, err := ctx.ToolSource.OrderBook(symbol, depth)
+	if err != nil {
+		return nil, err
+	}
+	levels := func(side []market.DepthLevel) [][2]float64 {
+		out := make([][2]float64, 0, min(len(side), depth))
+		for _, l := range side[:min(len(side), depth)] {
+			out = append(out, [2]float64{l.Price, l.Quantity})
+		}
+		return out
+	}
+	return map[string]any{"symbol": symbol, "bids": levels(book.Bids), "asks": levels(book.Asks)}, nil
+}
+
+func toolPositionHistory(ctx *Context, args toolArgs) any {
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultToolHistory
+	}
+	symbol := strings.ToUpper(strings.TrimSpace(args.Symbol))
+	trades := make([]RecentOrder, 0, limit)
+	for _, o := range ctx.RecentOrders {
+		if len(trades) == limit {
+			break
+		}
+		if symbol == "" || o.Symbol == symbol {
+			trades = append(trades, o)
+		}
+	}
+	return map[string]any{"trades": trades}
+}
+
+// toolPrice price on the symbol's tick grid as a JSON number
+func toolPrice(price, tick float64) json.Number {
+	return json.Number(market.FormatPriceTick(price, tick))
+}
@@ -0,0 +1,120 @@
+package decision
+
+import (
+	"errors"
+	"nofx/market"
+	"nofx/mcp"
+	"strings"
+	"testing"
+)
+
+// stubToolSource fixed market data for the decision tools
+type stubToolSource struct {
+	intervals []string
+}
+
+func (s *stubToolSource) Klines(symbol, interval string, limit int) ([]market.Kline, error) {
+	s.intervals = append(s.intervals, interval)
+	return []market.Kline{{OpenTime: 1, Open: 100.123, High: 101, Low: 99, Close: 100.5, Volume: 10}}, nil
+}
+
+func (s *stubToolSource) OrderBook(symbol string, depth int) (*market.OrderBook, error) {
+	return nil, errors.New("depth unavailable")
+}
+
+// scriptedToolClient answers tool-calling requests in order
+type scriptedToolClient struct {
+	fakeAIClient
+	responses []*mcp.Response
+	requests  []*mcp.Request
+}
+
+func (c *scriptedToolClient) CallWithTools(req *mcp.Request) (*mcp.Response, error) {
+	c.requests = append(c.requests, req)
+	resp := c.responses[0]
+	if len(c.responses) > 1 {
+		c.responses = c.responses[1:]
+	}
+	return resp, nil
+}
+
+func toolCall(id, name, args string) mcp.ToolCall {
+	return mcp.ToolCall{ID: id, Type: "function", Function: mcp.ToolCallFunction{Name: name, Arguments: args}}
+}
+
+func toolContext(source ToolSource) *Context {
+	return &Context{
+		CandidateCoins: []CandidateCoin{{Symbol: "BTCUSDT"}},
+		MarketDataMap:  map[string]*market.Data{"BTCUSDT": {Symbol: "BTCUSDT", TickSize: 0.1}},
+		RecentOrders:   []RecentOrder{{Symbol: "ETHUSDT", Side: "long"}, {Symbol: "BTCUSDT", Side: "short"}},
+		ToolSource:     source,
+	}
+}
+
+// TestRunToolLoop tests that tool results are fed back until the model answers
+func TestRunToolLoop(t *testing.T) {
+	source := &stubToolSource{}
+	client := &scriptedToolClient{responses: []*mcp.Response{
+		{ToolCalls: []mcp.ToolCall{
+			toolCall("1", ToolGetKlines, `{"symbol":"btcusdt","interval":"4h","limit":500}`),
+			toolCall("2", ToolGetOrderBook, `{"symbol":"BTCUSDT"}`),
+		}},
+		{Content: `<decision>[{"symbol":"BTCUSDT","action":"wait"}]</decision>`},
+	}}
+
+	answer, calls, err := runToolLoop(toolContext(source), client, "system", "user")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(answer, "<decision>") || len(calls) != 2 || len(source.intervals) != 1 {
+		t.Fatalf("expected the final answer after two tool calls, got %q %v", answer, calls)
+	}
+
+	second := client.requests[1].Messages
+	if len(second) != 5 || len(second[2].ToolCalls) != 2 {
+		t.Fatalf("expected the assistant tool calls and both results in the follow-up, got %+v", second)
+	}
+	if second[3].ToolCallID != "1" || !strings.Contains(second[3].Content, `[1,100.1,101.0,99.0,100.5,10]`) {
+		t.Errorf("klines should be returned on the tick grid, got %s", second[3].Content)
+	}
+	if second[4].ToolCallID != "2" || !strings.Contains(second[4].Content, "depth unavailable") {
+		t.Errorf("tool errors should be returned to the model, got %s", second[4].Content)
+	}
+}
+
+// TestRunToolLoop_Bounded tests that a model calling tools forever is made to answer
+func TestRunToolLoop_Bounded(t *testing.T) {
+	client := &scriptedToolClient{responses: []*mcp.Response{
+		{Content: "thinking", ToolCalls: []mcp.ToolCall{toolCall("x", ToolGetPositionHistory, `{}`)}},
+	}}
+
+	answer, calls, err := runToolLoop(toolContext(&stubToolSource{}), client, "system", "user")
+	if err != nil || answer != "thinking" {
+		t.Fatalf("expected the last answer once the tool budget is used, got %q %v", answer, err)
+	}
+	if len(client.requests) != maxToolRounds+1 || len(calls) != maxToolRounds {
+		t.Errorf("expected %d requests and %d calls, got %d and %d", maxToolRounds+1, maxToolRounds, len(client.requests), len(calls))
+	}
+	if last := client.requests[maxToolRounds]; last.ToolChoice != "none" {
+		t.Errorf("the last request should disable tools, got %q", last.ToolChoice)
+	}
+}
+
+// TestRunTool tests argument checks and the position history filter
+func TestRunTool(t *testing.T) {
+	ctx := toolContext(&stubToolSource{})
+
+	if got := runTool(ctx, toolCall("1", ToolGetKlines, `{"symbol":"DOGEUSDT","interval":"1h"}`)); !strings.Contains(got, "neither a candidate") {
+		t.Errorf("symbols outside the cycle should be refused, got %s", got)
+	}
+	if got := runTool(ctx, toolCall("1", ToolGetKlines, `{"symbol":"BTCUSDT","interval":"7m"}`)); !strings.Contains(got, "unsupported interval") {
+		t.Errorf("unknown intervals should be refused, got %s", got)
+	}
+	if got := runTool(ctx, toolCall("1", "place_order", `{}`)); !strings.Contains(got, "unknown tool") {
+		t.Errorf("unknown tools should be refused, got %s", got)
+	}
+	got := runTool(ctx, toolCall("1", ToolGetPositionHistory, `{"symbol":"BTCUSDT"}`))
+	if !strings.Contains(got, `"side":"short"`) || strings.Contains(got, "ETHUSDT") {
+		t.Errorf("expected only BTC trades, got %s", got)
+	}
+}
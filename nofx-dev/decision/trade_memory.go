@@ -0,0 +1,213 @@
+package decision
+
+import (
+	"fmt"
+	"math"
+	"nofx/market"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultTradeMemoryK similar past trades shown per symbol
+	defaultTradeMemoryK = 3
+	// maxTradeMemoryK upper bound of Indicators.TradeMemoryK
+	maxTradeMemoryK = 10
+	// minTradeSimilarity past trades less similar than this aren't shown
+	minTradeSimilarity = 0.5
+	// sameSymbolBonus ranks a past trade of the same symbol above an equally similar one of another symbol
+	sameSymbolBonus = 0.1
+)
+
+// TradeOutcome closed trade of the trader with the market situation at its entry (trade memory)
+type TradeOutcome struct {
+	Symbol      string
+	Side        string    // LONG/SHORT
+	Regime      string    // MarketRegime at entry
+	Vector      []float64 // SituationVector at entry
+	Summary     string    // Entry reasoning
+	EntryTime   time.Time
+	ExitTime    time.Time
+	RealizedPnL float64
+	ReturnPct   float64 // Realized PnL / entry notional (%)
+	CloseReason string
+}
+
+// SimilarTrade a past trade retrieved for the current situation of a symbol
+type SimilarTrade struct {
+	TradeOutcome
+	Similarity float64 // Cosine similarity of the entry situation with the current one
+}
+
+// SituationVector normalized indicator features of a symbol's market situation, used as the embedding of trade
+// memories: trend (price vs EMA20, EMA20 vs EMA50), momentum (RSI7, MACD, 1h / 4h change), volatility (ATR14 %)
+// and positioning (funding rate). Each feature is scaled to about [-1, 1]
+func SituationVector(data *market.Data) []float64 {
+	if data == nil || data.CurrentPrice <= 0 {
+		return nil
+	}
+	price := data.CurrentPrice
+	var emaTrend, atrPct float64
+	if lt := data.LongerTermContext; lt != nil {
+		if lt.EMA50 > 0 {
+			emaTrend = (lt.EMA20/lt.EMA50 - 1) * 100
+		}
+		atrPct = lt.ATR14 / price * 100
+	}
+	var priceVsEMA float64
+	if data.CurrentEMA20 > 0 {
+		priceVsEMA = (price/data.CurrentEMA20 - 1) * 100
+	}
+	return []float64{
+		clampUnit(priceVsEMA / 5),
+		clampUnit(emaTrend / 5),
+		clampUnit((data.CurrentRSI7 - 50) / 50),
+		clampUnit(data.CurrentMACD / price * 100),
+		clampUnit(data.PriceChange1h / 5),
+		clampUnit(data.PriceChange4h / 10),
+		clampUnit(atrPct/5*2 - 1),
+		clampUnit(data.FundingRate * 1000),
+	}
+}
+
+// MarketRegime trend / volatility label of a symbol's situation, e.g. "uptrend/high_vol"
+func MarketRegime(data *market.Data) string {
+	if data == nil || data.CurrentPrice <= 0 {
+		return ""
+	}
+	trend := "range"
+	vol := "normal_vol"
+	if lt := data.LongerTermContext; lt != nil && lt.EMA50 > 0 {
+		price := data.CurrentPrice
+		switch {
+		case price > lt.EMA20 && lt.EMA20 > lt.EMA50:
+			trend = "uptrend"
+		case price < lt.EMA20 && lt.EMA20 < lt.EMA50:
+			trend = "downtrend"
+		}
+		if lt.ATR14/price*100 > 3 {
+			vol = "high_vol"
+		}
+	}
+	return trend + "/" + vol
+}
+
+// FindSimilarTrades the k past trades of the same symbol or regime whose entry situation is most similar to the
+// current one (cosine similarity, same symbol ranked first among equals)
+func FindSimilarTrades(memory []TradeOutcome, symbol string, data *market.Data, k int) []SimilarTrade {
+	vector := SituationVector(data)
+	if len(vector) == 0 || k <= 0 {
+		return nil
+	}
+	regime := MarketRegime(data)
+
+	type scored struct {
+		trade SimilarTrade
+		score float64
+	}
+	var matches []scored
+	for _, m := range memory {
+		if m.Symbol != symbol && m.Regime != regime {
+			continue
+		}
+		sim := cosineSimilarity(vector, m.Vector)
+		if sim < minTradeSimilarity {
+			continue
+		}
+		score := sim
+		if m.Symbol == symbol {
+			score += sameSymbolBonus
+		}
+		matches = append(matches, scored{trade: SimilarTrade{TradeOutcome: m, Similarity: sim}, score: score})
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	trades := make([]SimilarTrade, 0, min(k, len(matches)))
+	for _, m := range matches[:min(k, len(matches))] {
+		trades = append(trades, m.trade)
+	}
+	return trades
+}
+
+// attachSimilarTrades retrieves the similar past trades of every candidate coin and position (Indicators.EnableTradeMemory)
+func (e *StrategyEngine) attachSimilarTrades(ctx *Context) {
+	if !e.config.Indicators.EnableTradeMemory || len(ctx.TradeMemory) == 0 {
+		return
+	}
+	k := e.config.Indicators.TradeMemoryK
+	if k <= 0 {
+		k = defaultTradeMemoryK
+	}
+	k = min(k, maxTradeMemoryK)
+
+	ctx.SimilarTrades = make(map[string][]SimilarTrade)
+	for _, symbol := range contextSymbols(ctx) {
+		if trades := FindSimilarTrades(ctx.TradeMemory, symbol, ctx.MarketDataMap[symbol], k); len(trades) > 0 {
+			ctx.SimilarTrades[symbol] = trades
+		}
+	}
+}
+
+// writeSimilarTrades user prompt section with the retrieved past trades
+func writeSimilarTrades(sb *strings.Builder, ctx *Context) {
+	if len(ctx.SimilarTrades) == 0 {
+		return
+	}
+	sb.WriteString("## Similar Past Situations (your own closed trades with the most similar entry conditions)\n")
+	for _, symbol := range contextSymbols(ctx) {
+		trades := ctx.SimilarTrades[symbol]
+		if len(trades) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%s (now %s):\n", symbol, MarketRegime(ctx.MarketDataMap[symbol])))
+		for _, t := range trades {
+			result := "Profit"
+			if t.RealizedPnL < 0 {
+				result = "Loss"
+			}
+			line := fmt.Sprintf("- %s %s %s in %s, similarity %.2f | %s: %+.2f USDT (%+.2f%%)",
+				t.EntryTime.UTC().Format("01-02 15:04"), t.Symbol, t.Side, t.Regime, t.Similarity,
+				result, t.RealizedPnL, t.ReturnPct)
+			if held := t.ExitTime.Sub(t.EntryTime).Round(time.Minute); !t.ExitTime.IsZero() && held > 0 {
+				line += ", held " + strings.TrimSuffix(held.String(), "0s")
+			}
+			if t.CloseReason != "" {
+				line += ", closed by " + t.CloseReason
+			}
+			if t.Summary != "" {
+				line += " | entry reasoning: " + truncateRunes(t.Summary, 160)
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+	sb.WriteString("Weigh these outcomes when the current setup repeats one that failed or worked before\n\n")
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
+
+func clampUnit(v float64) float64 {
+	return math.Max(-1, math.Min(1, v))
+}
+
+func truncateRunes(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,102 @@
+package decision
+
+import (
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"testing"
+	"time"
+)
+
+func memoryData(price, ema20, ema50, rsi, change1h float64) *market.Data {
+	return &market.Data{
+		Symbol:            "BTCUSDT",
+		CurrentPrice:      price,
+		CurrentEMA20:      ema20,
+		CurrentRSI7:       rsi,
+		PriceChange1h:     change1h,
+		LongerTermContext: &market.LongerTermData{EMA20: ema20, EMA50: ema50, ATR14: price * 0.01},
+	}
+}
+
+// TestMarketRegime tests the trend / volatility labels
+func TestMarketRegime(t *testing.T) {
+	if got := MarketRegime(memoryData(105, 100, 95, 60, 1)); got != "uptrend/normal_vol" {
+		t.Errorf("expected uptrend/normal_vol, got %s", got)
+	}
+	if got := MarketRegime(memoryData(90, 95, 100, 40, -1)); got != "downtrend/normal_vol" {
+		t.Errorf("expected downtrend/normal_vol, got %s", got)
+	}
+	volatile := memoryData(100, 101, 99, 50, 0)
+	volatile.LongerTermContext.ATR14 = 5
+	if got := MarketRegime(volatile); got != "range/high_vol" {
+		t.Errorf("expected range/high_vol, got %s", got)
+	}
+	if MarketRegime(nil) != "" || SituationVector(nil) != nil {
+		t.Error("expected no regime or vector without data")
+	}
+}
+
+// TestFindSimilarTrades tests similarity ranking, the symbol / regime filter and k
+func TestFindSimilarTrades(t *testing.T) {
+	uptrend := memoryData(105, 100, 95, 70, 2)
+	downtrend := memoryData(90, 95, 100, 25, -3)
+	memory := []TradeOutcome{
+		{Symbol: "ETHUSDT", Regime: MarketRegime(uptrend), Vector: SituationVector(uptrend), RealizedPnL: 5},
+		{Symbol: "BTCUSDT", Regime: MarketRegime(uptrend), Vector: SituationVector(memoryData(104, 100, 96, 65, 1.5)), RealizedPnL: -3},
+		{Symbol: "BTCUSDT", Regime: MarketRegime(downtrend), Vector: SituationVector(downtrend), RealizedPnL: 8},
+		{Symbol: "SOLUSDT", Regime: MarketRegime(downtrend), Vector: SituationVector(downtrend), RealizedPnL: 1},
+	}
+
+	trades := FindSimilarTrades(memory, "BTCUSDT", uptrend, 5)
+	if len(trades) != 2 {
+		t.Fatalf("expected the 2 similar trades (opposite situation and other regime excluded), got %d", len(trades))
+	}
+	if trades[0].Symbol != "BTCUSDT" || trades[1].Symbol != "ETHUSDT" {
+		t.Errorf("expected the same symbol first, got %s then %s", trades[0].Symbol, trades[1].Symbol)
+	}
+	if trades[1].Similarity < 0.99 {
+		t.Errorf("expected the identical situation to have similarity 1, got %.3f", trades[1].Similarity)
+	}
+	if got := FindSimilarTrades(memory, "BTCUSDT", uptrend, 1); len(got) != 1 {
+		t.Errorf("expected k to limit the result, got %d", len(got))
+	}
+}
+
+// TestBuildUserPrompt_SimilarTrades tests retrieval from the context and the prompt section
+func TestBuildUserPrompt_SimilarTrades(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.Indicators.EnableTradeMemory = true
+	engine := NewStrategyEngine(&cfg)
+
+	data := memoryData(105, 100, 95, 70, 2)
+	entry := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	ctx := &Context{
+		Account:        AccountInfo{TotalEquity: 1000, AvailableBalance: 1000},
+		CandidateCoins: []CandidateCoin{{Symbol: "BTCUSDT"}},
+		MarketDataMap:  map[string]*market.Data{"BTCUSDT": data},
+		TradeMemory: []TradeOutcome{{
+			Symbol: "BTCUSDT", Side: "LONG", Regime: MarketRegime(data), Vector: SituationVector(data),
+			Summary: "Breakout above range high", EntryTime: entry, ExitTime: entry.Add(90 * time.Minute),
+			RealizedPnL: -12.5, ReturnPct: -1.25, CloseReason: "stop_loss",
+		}},
+	}
+	engine.attachSimilarTrades(ctx)
+	if len(ctx.SimilarTrades["BTCUSDT"]) != 1 {
+		t.Fatalf("expected one similar trade, got %v", ctx.SimilarTrades)
+	}
+
+	prompt := engine.BuildUserPrompt(ctx)
+	for _, want := range []string{"Similar Past Situations", "BTCUSDT LONG in uptrend/normal_vol", "Loss: -12.50 USDT", "held 1h30m,", "closed by stop_loss", "Breakout above range high"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q", want)
+		}
+	}
+
+	cfg.Indicators.EnableTradeMemory = false
+	ctx.SimilarTrades = nil
+	engine.attachSimilarTrades(ctx)
+	if ctx.SimilarTrades != nil {
+		t.Error("expected no retrieval while trade memory is disabled")
+	}
+}
@@ -99,6 +99,47 @@ func TestLeverageFallback(t *testing.T) {
 	}
 }
 
+// TestValidateAddDecision tests scale-in validation (leverage and stop loss / take profit are optional)
+func TestValidateAddDecision(t *testing.T) {
+	tests := []struct {
+		name      string
+		decision  Decision
+		wantError bool
+	}{
+		{
+			name:     "Size only - valid",
+			decision: Decision{Symbol: "SOLUSDT", Action: "add_long", PositionSizeUSD: 50},
+		},
+		{
+			name:     "Short with new bracket - valid",
+			decision: Decision{Symbol: "ETHUSDT", Action: "add_short", PositionSizeUSD: 100, StopLoss: 4000, TakeProfit: 3000},
+		},
+		{
+			name:      "Amount below minimum",
+			decision:  Decision{Symbol: "BTCUSDT", Action: "add_long", PositionSizeUSD: 30},
+			wantError: true,
+		},
+		{
+			name:      "Amount above max position value",
+			decision:  Decision{Symbol: "SOLUSDT", Action: "add_long", PositionSizeUSD: 200},
+			wantError: true,
+		},
+		{
+			name:      "Long with inverted bracket",
+			decision:  Decision{Symbol: "SOLUSDT", Action: "add_long", PositionSizeUSD: 50, StopLoss: 200, TakeProfit: 100},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDecision(&tt.decision, 100, 10, 5)
+			if (err != nil) != tt.wantError {
+				t.Errorf("validateDecision() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
 
 // contains checks if string contains substring (helper function)
 func contains(s, substr string) bool {
@@ -114,3 +155,45 @@ func stringContains(s, substr string) bool {
 	}
 	return false
 }
+
+// TestValidatePartialCloseDecision tests partial_close_long/partial_close_short validation
+func TestValidatePartialCloseDecision(t *testing.T) {
+	tests := []struct {
+		name      string
+		decision  Decision
+		wantError bool
+	}{
+		{
+			name:     "Half close - valid",
+			decision: Decision{Symbol: "SOLUSDT", Action: "partial_close_long", ClosePct: 50},
+		},
+		{
+			name:     "With new stop for remainder - valid",
+			decision: Decision{Symbol: "ETHUSDT", Action: "partial_close_short", ClosePct: 30, StopLoss: 4000, TakeProfit: 3000},
+		},
+		{
+			name:      "Missing percentage",
+			decision:  Decision{Symbol: "SOLUSDT", Action: "partial_close_long"},
+			wantError: true,
+		},
+		{
+			name:      "Percentage above 100",
+			decision:  Decision{Symbol: "SOLUSDT", Action: "partial_close_short", ClosePct: 150},
+			wantError: true,
+		},
+		{
+			name:      "Long with inverted bracket",
+			decision:  Decision{Symbol: "SOLUSDT", Action: "partial_close_long", ClosePct: 50, StopLoss: 200, TakeProfit: 100},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDecision(&tt.decision, 100, 10, 5)
+			if (err != nil) != tt.wantError {
+				t.Errorf("validateDecision() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package decision
+
+import (
+	"fmt"
+	"math"
+	"nofx/market"
+)
+
+// VolatilityLeverage leverage targeting targetPct realized volatility: maxLeverage × targetPct / (ATR / price in %),
+// rounded down and bounded to [1, maxLeverage]. Returns maxLeverage when volatility can't be computed
+func VolatilityLeverage(maxLeverage int, atr, price, targetPct float64) int {
+	if maxLeverage <= 1 || atr <= 0 || price <= 0 || targetPct <= 0 {
+		return maxLeverage
+	}
+	volPct := atr / price * 100
+	leverage := int(math.Floor(float64(maxLeverage) * targetPct / volPct))
+	if leverage < 1 {
+		return 1
+	}
+	if leverage > maxLeverage {
+		return maxLeverage
+	}
+	return leverage
+}
+
+// ApplyVolatilityLeverage sets the leverage of open decisions from the ATR14 volatility of the timeframe
+// (vol_target_atr_pct): lower when volatility spikes, up to the BTC/ETH or altcoin max in calm regimes.
+// Returns an execution log note per changed leverage; symbols without market data or ATR keep the AI's leverage
+func ApplyVolatilityLeverage(decisions []Decision, marketData map[string]*market.Data, timeframe string, targetPct float64, btcEthMax, altcoinMax int) []string {
+	if targetPct <= 0 {
+		return nil
+	}
+	var notes []string
+	for i := range decisions {
+		d := &decisions[i]
+		if d.Action != "open_long" && d.Action != "open_short" {
+			continue
+		}
+		data, ok := marketData[d.Symbol]
+		if !ok || data == nil || data.CurrentPrice <= 0 {
+			continue
+		}
+		atr := data.ATR14For(timeframe)
+		if atr <= 0 {
+			continue
+		}
+		maxLeverage := altcoinMax
+		if market.IsBTCETH(d.Symbol) {
+			maxLeverage = btcEthMax
+		}
+		if maxLeverage <= 0 {
+			continue
+		}
+		leverage := VolatilityLeverage(maxLeverage, atr, data.CurrentPrice, targetPct)
+		if leverage == d.Leverage {
+			continue
+		}
+		notes = append(notes, fmt.Sprintf("🌊 %s leverage %dx → %dx (ATR14 %.2f%% of price on %s, target %.2f%%)",
+			d.Symbol, d.Leverage, leverage, atr/data.CurrentPrice*100, timeframe, targetPct))
+		d.Leverage = leverage
+	}
+	return notes
+}
@@ -0,0 +1,64 @@
+package decision
+
+import (
+	"nofx/market"
+	"testing"
+)
+
+// TestVolatilityLeverage tests scaling leverage by target / realized volatility within the max
+func TestVolatilityLeverage(t *testing.T) {
+	tests := []struct {
+		name      string
+		max       int
+		atr       float64
+		price     float64
+		targetPct float64
+		want      int
+	}{
+		{"volatility at target keeps max", 10, 1, 100, 1, 10},
+		{"double volatility halves", 10, 2, 100, 1, 5},
+		{"calm regime capped at max", 10, 0.2, 100, 1, 10},
+		{"spike floors at 1x", 10, 50, 100, 1, 1},
+		{"rounds down", 10, 3, 100, 1, 3},
+		{"no ATR keeps max", 10, 0, 100, 1, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VolatilityLeverage(tt.max, tt.atr, tt.price, tt.targetPct); got != tt.want {
+				t.Errorf("VolatilityLeverage() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestApplyVolatilityLeverage tests per-class max leverage and skipping non-open decisions
+func TestApplyVolatilityLeverage(t *testing.T) {
+	data := map[string]*market.Data{
+		"BTCUSDT": {CurrentPrice: 60000, IntradaySeries: &market.IntradayData{ATR14: 1200}}, // 2%
+		"SOLUSDT": {CurrentPrice: 100, IntradaySeries: &market.IntradayData{ATR14: 0.5}},    // 0.5%
+	}
+	decisions := []Decision{
+		{Symbol: "BTCUSDT", Action: "open_long", Leverage: 10},
+		{Symbol: "SOLUSDT", Action: "open_short", Leverage: 2},
+		{Symbol: "BTCUSDT", Action: "close_long", Leverage: 10},
+		{Symbol: "DOGEUSDT", Action: "open_long", Leverage: 3}, // No market data
+	}
+
+	notes := ApplyVolatilityLeverage(decisions, data, "15m", 1, 10, 5)
+	if len(notes) != 2 {
+		t.Fatalf("expected 2 notes, got %v", notes)
+	}
+	if decisions[0].Leverage != 5 {
+		t.Errorf("BTC at 2%% volatility should use half the 10x max, got %dx", decisions[0].Leverage)
+	}
+	if decisions[1].Leverage != 5 {
+		t.Errorf("calm altcoin should scale up to the 5x max, got %dx", decisions[1].Leverage)
+	}
+	if decisions[2].Leverage != 10 || decisions[3].Leverage != 3 {
+		t.Errorf("close and no-data decisions should keep leverage, got %dx / %dx", decisions[2].Leverage, decisions[3].Leverage)
+	}
+
+	if notes := ApplyVolatilityLeverage(decisions, data, "15m", 0, 10, 5); notes != nil {
+		t.Errorf("disabled target should not change leverage: %v", notes)
+	}
+}
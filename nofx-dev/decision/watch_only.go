@@ -0,0 +1,91 @@
+package decision
+
+import (
+	"fmt"
+	"nofx/market"
+)
+
+// SourceWatchOnly candidate source of watch-only coins: analyzed and commented on, never traded
+const SourceWatchOnly = "watch_only"
+
+// ActionAlert commentary / alert about a symbol (breakout, OI surge...), delivered as a notification, never an order
+const ActionAlert = "alert"
+
+// Alert types of ActionAlert decisions
+var alertTypes = map[string]bool{
+	"breakout":      true,
+	"breakdown":     true,
+	"oi_surge":      true,
+	"volume_spike":  true,
+	"funding_shift": true,
+	"commentary":    true,
+}
+
+// WatchOnlySymbols the strategy's watch-only coins (normalized to the quote asset)
+func (e *StrategyEngine) WatchOnlySymbols() map[string]bool {
+	symbols := make(map[string]bool, len(e.config.CoinSource.WatchOnlyCoins))
+	for _, symbol := range e.config.CoinSource.WatchOnlyCoins {
+		symbols[market.NormalizeQuote(symbol, e.quoteAsset)] = true
+	}
+	return symbols
+}
+
+// addWatchOnlyCandidates adds watch-only coins to the candidates, tagging candidates that are also watch-only
+func addWatchOnlyCandidates(candidates []CandidateCoin, watch map[string]bool) []CandidateCoin {
+	seen := make(map[string]bool, len(candidates))
+	for i := range candidates {
+		seen[candidates[i].Symbol] = true
+		if watch[candidates[i].Symbol] && !candidates[i].WatchOnly() {
+			candidates[i].Sources = append(candidates[i].Sources, SourceWatchOnly)
+		}
+	}
+	for symbol := range watch {
+		if !seen[symbol] {
+			candidates = append(candidates, CandidateCoin{Symbol: symbol, Sources: []string{SourceWatchOnly}})
+		}
+	}
+	return candidates
+}
+
+// WatchOnly reports whether the candidate may only be commented on
+func (c CandidateCoin) WatchOnly() bool {
+	for _, source := range c.Sources {
+		if source == SourceWatchOnly {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterWatchOnly drops every decision on a watch-only symbol except alert / hold / wait (CODE ENFORCED),
+// returns the decisions to execute and an execution log note per dropped decision
+func FilterWatchOnly(decisions []Decision, watch map[string]bool) ([]Decision, []string) {
+	if len(watch) == 0 {
+		return decisions, nil
+	}
+	kept := decisions[:0:0]
+	var notes []string
+	for _, d := range decisions {
+		switch {
+		case !watch[d.Symbol], d.Action == ActionAlert, d.Action == "hold", d.Action == "wait":
+			kept = append(kept, d)
+		default:
+			notes = append(notes, fmt.Sprintf("👁 %s is watch-only, %s not executed", d.Symbol, d.Action))
+		}
+	}
+	return kept, notes
+}
+
+// validateAlertDecision requires commentary, alert_type defaults to commentary
+func validateAlertDecision(d *Decision) error {
+	if d.Reasoning == "" {
+		return fmt.Errorf("reasoning (the alert text) is required for %s", d.Action)
+	}
+	if d.AlertType == "" {
+		d.AlertType = "commentary"
+	}
+	if !alertTypes[d.AlertType] {
+		return fmt.Errorf("invalid alert_type %q", d.AlertType)
+	}
+	return nil
+}
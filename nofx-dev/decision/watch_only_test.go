@@ -0,0 +1,56 @@
+package decision
+
+import (
+	"testing"
+)
+
+// TestAddWatchOnlyCandidates tests tagging and appending watch-only coins
+func TestAddWatchOnlyCandidates(t *testing.T) {
+	candidates := []CandidateCoin{
+		{Symbol: "BTCUSDT", Sources: []string{"ai500"}},
+		{Symbol: "PEPEUSDT", Sources: []string{"oi_top"}},
+	}
+	got := addWatchOnlyCandidates(candidates, map[string]bool{"PEPEUSDT": true, "WIFUSDT": true})
+	if len(got) != 3 {
+		t.Fatalf("expected 3 candidates, got %+v", got)
+	}
+	if got[0].WatchOnly() || !got[1].WatchOnly() || got[2].Symbol != "WIFUSDT" || !got[2].WatchOnly() {
+		t.Errorf("unexpected watch-only tagging: %+v", got)
+	}
+}
+
+// TestFilterWatchOnly tests that only alert / hold / wait survive on watch-only symbols
+func TestFilterWatchOnly(t *testing.T) {
+	decisions := []Decision{
+		{Symbol: "PEPEUSDT", Action: "open_long"},
+		{Symbol: "PEPEUSDT", Action: ActionAlert, AlertType: "breakout", Reasoning: "broke range high"},
+		{Symbol: "PEPEUSDT", Action: "wait"},
+		{Symbol: "BTCUSDT", Action: "open_long"},
+	}
+	kept, notes := FilterWatchOnly(decisions, map[string]bool{"PEPEUSDT": true})
+	if len(kept) != 3 || len(notes) != 1 {
+		t.Fatalf("expected the PEPE entry to be dropped, kept %+v, notes %v", kept, notes)
+	}
+	for _, d := range kept {
+		if d.Symbol == "PEPEUSDT" && d.Action == "open_long" {
+			t.Error("order on a watch-only symbol was kept")
+		}
+	}
+	if kept, _ := FilterWatchOnly(decisions, nil); len(kept) != 4 {
+		t.Errorf("without watch-only coins every decision should be kept, got %d", len(kept))
+	}
+}
+
+// TestValidateAlertDecision tests alert text and type checks
+func TestValidateAlertDecision(t *testing.T) {
+	d := Decision{Symbol: "PEPEUSDT", Action: ActionAlert, Reasoning: "OI up 40% in an hour"}
+	if err := validateDecision(&d, 100, 10, 5); err != nil || d.AlertType != "commentary" {
+		t.Errorf("expected a valid commentary alert, got %v (%q)", err, d.AlertType)
+	}
+	if err := validateDecision(&Decision{Symbol: "PEPEUSDT", Action: ActionAlert}, 100, 10, 5); err == nil {
+		t.Error("expected an error for an alert without text")
+	}
+	if err := validateDecision(&Decision{Symbol: "PEPEUSDT", Action: ActionAlert, AlertType: "moon", Reasoning: "x"}, 100, 10, 5); err == nil {
+		t.Error("expected an error for an unknown alert type")
+	}
+}
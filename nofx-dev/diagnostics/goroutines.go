@@ -0,0 +1,85 @@
+// Package diagnostics provides runtime introspection helpers (goroutine breakdown,
+// memory peaks and leak detection) used by the debug API endpoints
+package diagnostics
+
+import (
+	"bufio"
+	"bytes"
+	"runtime"
+	"strings"
+)
+
+// GoroutinesBySubsystem groups all live goroutines by the subsystem that created them
+// Subsystem is derived from the "created by" frame of each stack:
+//   - nofx/<pkg>.xxx → "<pkg>" (e.g. "market", "trader")
+//   - main.xxx → "main"
+//   - anything else → "external" (runtime, net/http, third-party SDKs)
+//   - no creator (root goroutine) → "root"
+func GoroutinesBySubsystem() map[string]int {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		// Buffer too small, double it (cap at 64MB to avoid runaway allocation)
+		if len(buf) >= 64<<20 {
+			break
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+	return parseGoroutineDump(buf)
+}
+
+// parseGoroutineDump counts goroutines per subsystem from a runtime.Stack(all=true) dump
+func parseGoroutineDump(dump []byte) map[string]int {
+	result := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(dump))
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+
+	inGoroutine := false
+	creator := ""
+	flush := func() {
+		if inGoroutine {
+			result[subsystemOf(creator)]++
+		}
+		inGoroutine = false
+		creator = ""
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "goroutine "):
+			flush()
+			inGoroutine = true
+		case strings.HasPrefix(line, "created by "):
+			creator = strings.TrimPrefix(line, "created by ")
+			// Go 1.21+ appends " in goroutine N"
+			if idx := strings.Index(creator, " in goroutine "); idx >= 0 {
+				creator = creator[:idx]
+			}
+		}
+	}
+	flush()
+	return result
+}
+
+// subsystemOf maps a creator function name to a subsystem label
+func subsystemOf(creator string) string {
+	if creator == "" {
+		return "root"
+	}
+	if strings.HasPrefix(creator, "main.") {
+		return "main"
+	}
+	if strings.HasPrefix(creator, "nofx/") {
+		rest := strings.TrimPrefix(creator, "nofx/")
+		if idx := strings.IndexAny(rest, "./"); idx > 0 {
+			return rest[:idx]
+		}
+		return rest
+	}
+	return "external"
+}
@@ -0,0 +1,82 @@
+package diagnostics
+
+import (
+	"testing"
+)
+
+// TestParseGoroutineDump tests grouping goroutines by creator subsystem
+func TestParseGoroutineDump(t *testing.T) {
+	dump := []byte(`goroutine 1 [running]:
+main.main()
+	/app/main.go:10 +0x1
+
+goroutine 7 [select]:
+nofx/market.(*WSMonitor).handleKlineData(...)
+	/app/market/monitor.go:171 +0x1
+created by nofx/market.(*WSMonitor).subscribeSymbol in goroutine 1
+	/app/market/monitor.go:147 +0x1
+
+goroutine 8 [select]:
+nofx/market.(*WSMonitor).handleKlineData(...)
+created by nofx/market.(*WSMonitor).subscribeSymbol in goroutine 1
+
+goroutine 9 [sleep]:
+created by nofx/trader.(*AutoTrader).startDrawdownMonitor
+	/app/trader/auto_trader.go:1467 +0x1
+
+goroutine 10 [IO wait]:
+created by net/http.(*Server).Serve in goroutine 1
+
+goroutine 11 [chan receive]:
+created by main.main in goroutine 1
+`)
+
+	got := parseGoroutineDump(dump)
+	want := map[string]int{
+		"root":     1,
+		"market":   2,
+		"trader":   1,
+		"external": 1,
+		"main":     1,
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d subsystems, got %d: %v", len(want), len(got), got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("subsystem %s: expected %d, got %d", k, v, got[k])
+		}
+	}
+}
+
+// TestLeakDetectorRecord tests alert is raised only after sustained growth
+func TestLeakDetectorRecord(t *testing.T) {
+	d := NewLeakDetector(0, 3)
+	d.minGrowth = 10
+
+	for i, n := range []int{100, 110, 120} {
+		d.record(Sample{Goroutines: n, HeapAlloc: uint64(i)})
+	}
+	if len(d.Report().Alerts) != 0 {
+		t.Fatal("should not alert before growth cycles reached")
+	}
+
+	d.record(Sample{Goroutines: 130, HeapAlloc: 1})
+	report := d.Report()
+	if len(report.Alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(report.Alerts))
+	}
+	if report.Alerts[0].Growth != 30 {
+		t.Errorf("expected growth 30, got %d", report.Alerts[0].Growth)
+	}
+	if report.PeakGoroutines != 130 || report.PeakHeapAlloc != 2 {
+		t.Errorf("unexpected peaks: goroutines=%d heap=%d", report.PeakGoroutines, report.PeakHeapAlloc)
+	}
+
+	// Flat samples reset the growth counter
+	d.record(Sample{Goroutines: 130})
+	if d.Report().Consecutive != 0 {
+		t.Error("consecutive growth should reset on flat sample")
+	}
+}
@@ -0,0 +1,224 @@
+package diagnostics
+
+import (
+	"nofx/logger"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// LeakDetector periodically samples goroutine count and heap usage
+// If goroutine count keeps growing for GrowthCycles consecutive samples, an alert is logged
+type LeakDetector struct {
+	interval     time.Duration
+	growthCycles int // Consecutive growth samples required to raise an alert
+	minGrowth    int // Minimum total growth (goroutines) across the window to raise an alert
+
+	mu             sync.RWMutex
+	samples        []Sample
+	peakHeapAlloc  uint64
+	peakGoroutines int
+	consecutive    int
+	alerts         []LeakAlert
+	stopCh         chan struct{}
+	running        bool
+}
+
+// Sample single runtime sample
+type Sample struct {
+	Time       time.Time `json:"time"`
+	Goroutines int       `json:"goroutines"`
+	HeapAlloc  uint64    `json:"heap_alloc"`
+}
+
+// LeakAlert raised when goroutines grow unbounded across cycles
+type LeakAlert struct {
+	Time        time.Time      `json:"time"`
+	Goroutines  int            `json:"goroutines"`
+	Growth      int            `json:"growth"`
+	Cycles      int            `json:"cycles"`
+	BySubsystem map[string]int `json:"by_subsystem"`
+}
+
+// LeakReport snapshot of detector state (for API)
+type LeakReport struct {
+	Running        bool        `json:"running"`
+	Interval       string      `json:"interval"`
+	GrowthCycles   int         `json:"growth_cycles"`
+	Consecutive    int         `json:"consecutive_growth"`
+	PeakHeapAlloc  uint64      `json:"peak_heap_alloc"`
+	PeakGoroutines int         `json:"peak_goroutines"`
+	Samples        []Sample    `json:"samples"`
+	Alerts         []LeakAlert `json:"alerts"`
+}
+
+const (
+	maxLeakSamples = 60
+	maxLeakAlerts  = 20
+)
+
+var (
+	defaultDetector   *LeakDetector
+	defaultDetectorMu sync.Mutex
+)
+
+// NewLeakDetector creates a leak detector
+// interval: sampling interval (default 1 minute), growthCycles: consecutive growth samples to alert (default 10)
+func NewLeakDetector(interval time.Duration, growthCycles int) *LeakDetector {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	if growthCycles <= 0 {
+		growthCycles = 10
+	}
+	return &LeakDetector{
+		interval:     interval,
+		growthCycles: growthCycles,
+		minGrowth:    50,
+	}
+}
+
+// StartDefault starts the process-wide leak detector (idempotent)
+func StartDefault(interval time.Duration, growthCycles int) *LeakDetector {
+	defaultDetectorMu.Lock()
+	defer defaultDetectorMu.Unlock()
+	if defaultDetector == nil {
+		defaultDetector = NewLeakDetector(interval, growthCycles)
+		defaultDetector.Start()
+	}
+	return defaultDetector
+}
+
+// Default returns the process-wide leak detector (nil if not started)
+func Default() *LeakDetector {
+	defaultDetectorMu.Lock()
+	defer defaultDetectorMu.Unlock()
+	return defaultDetector
+}
+
+// Start starts background sampling
+func (d *LeakDetector) Start() {
+	d.mu.Lock()
+	if d.running {
+		d.mu.Unlock()
+		return
+	}
+	d.running = true
+	d.stopCh = make(chan struct{})
+	d.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		d.sample()
+		for {
+			select {
+			case <-ticker.C:
+				d.sample()
+			case <-d.stopCh:
+				return
+			}
+		}
+	}()
+	logger.Infof("🩺 Goroutine leak detector started (interval: %v, alert after %d growth cycles)", d.interval, d.growthCycles)
+}
+
+// Stop stops background sampling
+func (d *LeakDetector) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.running {
+		return
+	}
+	d.running = false
+	close(d.stopCh)
+}
+
+// sample takes one sample and evaluates growth
+func (d *LeakDetector) sample() {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	d.record(Sample{
+		Time:       time.Now(),
+		Goroutines: runtime.NumGoroutine(),
+		HeapAlloc:  mem.HeapAlloc,
+	})
+}
+
+// record appends a sample and raises an alert when growth is sustained
+func (d *LeakDetector) record(s Sample) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if s.HeapAlloc > d.peakHeapAlloc {
+		d.peakHeapAlloc = s.HeapAlloc
+	}
+	if s.Goroutines > d.peakGoroutines {
+		d.peakGoroutines = s.Goroutines
+	}
+
+	if n := len(d.samples); n > 0 && s.Goroutines > d.samples[n-1].Goroutines {
+		d.consecutive++
+	} else {
+		d.consecutive = 0
+	}
+
+	d.samples = append(d.samples, s)
+	if len(d.samples) > maxLeakSamples {
+		d.samples = d.samples[len(d.samples)-maxLeakSamples:]
+	}
+
+	if d.consecutive < d.growthCycles {
+		return
+	}
+
+	// Growth across the detection window
+	windowStart := len(d.samples) - 1 - d.consecutive
+	if windowStart < 0 {
+		windowStart = 0
+	}
+	growth := s.Goroutines - d.samples[windowStart].Goroutines
+	if growth < d.minGrowth {
+		return
+	}
+
+	alert := LeakAlert{
+		Time:        s.Time,
+		Goroutines:  s.Goroutines,
+		Growth:      growth,
+		Cycles:      d.consecutive,
+		BySubsystem: GoroutinesBySubsystem(),
+	}
+	d.alerts = append(d.alerts, alert)
+	if len(d.alerts) > maxLeakAlerts {
+		d.alerts = d.alerts[len(d.alerts)-maxLeakAlerts:]
+	}
+	logger.Warnf("🚨 Possible goroutine leak: %d goroutines (+%d over %d cycles), by subsystem: %v",
+		s.Goroutines, growth, d.consecutive, alert.BySubsystem)
+
+	// Reset so the next alert requires a fresh growth window
+	d.consecutive = 0
+}
+
+// Report returns a snapshot of the detector state
+func (d *LeakDetector) Report() LeakReport {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	samples := make([]Sample, len(d.samples))
+	copy(samples, d.samples)
+	alerts := make([]LeakAlert, len(d.alerts))
+	copy(alerts, d.alerts)
+
+	return LeakReport{
+		Running:        d.running,
+		Interval:       d.interval.String(),
+		GrowthCycles:   d.growthCycles,
+		Consecutive:    d.consecutive,
+		PeakHeapAlloc:  d.peakHeapAlloc,
+		PeakGoroutines: d.peakGoroutines,
+		Samples:        samples,
+		Alerts:         alerts,
+	}
+}
@@ -0,0 +1,369 @@
+// Package endpoint keeps process-wide pools of equivalent base URLs (exchange REST/WS
+// mirrors, LLM regions) and prefers the fastest healthy one. Selection is sticky: the
+// current endpoint is only replaced when another one is clearly faster or the current
+// one keeps failing, so a noisy probe doesn't flip traffic between endpoints
+package endpoint
+
+import (
+	"net/url"
+	"nofx/logger"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// failoverThreshold consecutive failures after which an endpoint is unhealthy
+	failoverThreshold = 3
+	// switchMargin fraction by which a candidate must beat the current endpoint's latency
+	switchMargin = 0.25
+	// minSwitchGain absolute latency gain below which the current endpoint is kept
+	minSwitchGain = 10 * time.Millisecond
+	// latencyAlpha EWMA weight of a new latency sample
+	latencyAlpha = 0.3
+)
+
+// endpointState latency and health of one base URL
+type endpointState struct {
+	base      string // scheme://host
+	latency   time.Duration
+	samples   int64
+	failures  int // Consecutive failures
+	lastError string
+	lastSeen  time.Time
+}
+
+// healthy reports whether the endpoint is usable
+func (e *endpointState) healthy() bool {
+	return e.failures < failoverThreshold
+}
+
+// Pool equivalent endpoints of one service, the first URL is the default
+type Pool struct {
+	name      string
+	probePath string // Path appended to the base URL when probing (empty = base URL)
+
+	mu        sync.Mutex
+	endpoints []*endpointState
+	current   int
+	switches  int64
+	now       func() time.Time
+}
+
+// EndpointStats metrics of one endpoint (for API)
+type EndpointStats struct {
+	URL       string    `json:"url"`
+	LatencyMs float64   `json:"latency_ms"`
+	Samples   int64     `json:"samples"`
+	Healthy   bool      `json:"healthy"`
+	Failures  int       `json:"consecutive_failures"`
+	LastError string    `json:"last_error,omitempty"`
+	LastSeen  time.Time `json:"last_seen,omitempty"`
+}
+
+// Stats pool metrics (for API)
+type Stats struct {
+	Name      string          `json:"name"`
+	Current   string          `json:"current"`
+	Switches  int64           `json:"switches"`
+	Endpoints []EndpointStats `json:"endpoints"`
+}
+
+var (
+	pools   = make(map[string]*Pool)
+	byHost  = make(map[string]*Pool)
+	poolsMu sync.RWMutex
+)
+
+// normalize reduces a URL to scheme://host ("" if it isn't absolute)
+func normalize(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+	return strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host)
+}
+
+// Register registers (or replaces) the pool of a service
+// Only scheme and host of each URL are used, paths must be identical across the endpoints
+func Register(name, probePath string, urls ...string) *Pool {
+	p := &Pool{name: name, probePath: probePath, now: time.Now}
+	seen := make(map[string]bool)
+	for _, raw := range urls {
+		base := normalize(raw)
+		if base == "" || seen[base] {
+			continue
+		}
+		seen[base] = true
+		p.endpoints = append(p.endpoints, &endpointState{base: base})
+	}
+
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	if old, ok := pools[name]; ok {
+		for _, e := range old.endpoints {
+			delete(byHost, hostOf(e.base))
+		}
+	}
+	pools[name] = p
+	for _, e := range p.endpoints {
+		byHost[hostOf(e.base)] = p
+	}
+	return p
+}
+
+// Unregister removes the pool of a service, its hosts are no longer rewritten
+func Unregister(name string) {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	if old, ok := pools[name]; ok {
+		for _, e := range old.endpoints {
+			delete(byHost, hostOf(e.base))
+		}
+		delete(pools, name)
+	}
+}
+
+// RegisterDefaults registers the known exchange mirrors
+func RegisterDefaults() {
+	// OKX: global domain and the AWS-hosted domain
+	Register("okx", "/api/v5/public/time", "https://www.okx.com", "https://aws.okx.com")
+	// Bybit: main domain and the alternative bytick domain
+	Register("bybit", "/v5/market/time", "https://api.bybit.com", "https://api.bytick.com")
+}
+
+// Reset removes all pools (tests, reconfiguration)
+func Reset() {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	pools = make(map[string]*Pool)
+	byHost = make(map[string]*Pool)
+}
+
+// hostOf returns the lowercased host of a normalized base URL
+func hostOf(base string) string {
+	if i := strings.Index(base, "://"); i >= 0 {
+		return base[i+3:]
+	}
+	return base
+}
+
+// Get returns the pool of a service (nil if none registered)
+func Get(name string) *Pool {
+	poolsMu.RLock()
+	defer poolsMu.RUnlock()
+	return pools[name]
+}
+
+// forHost returns the pool containing host (nil if none)
+func forHost(host string) *Pool {
+	poolsMu.RLock()
+	defer poolsMu.RUnlock()
+	return byHost[strings.ToLower(host)]
+}
+
+// Resolve rewrites rawURL to the preferred endpoint of its pool
+// URLs whose host isn't part of a pool are returned unchanged
+func Resolve(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	p := forHost(u.Host)
+	if p == nil {
+		return rawURL
+	}
+	return p.rewrite(u).String()
+}
+
+// Observe feeds the outcome of a request made to rawURL back into its pool's health
+func Observe(rawURL string, err error) {
+	u, parseErr := url.Parse(rawURL)
+	if parseErr != nil || u.Host == "" {
+		return
+	}
+	if p := forHost(u.Host); p != nil {
+		p.mu.Lock()
+		p.observe(strings.ToLower(u.Host), err)
+		p.mu.Unlock()
+	}
+}
+
+// AllStats returns metrics for all registered pools (sorted by name)
+func AllStats() []Stats {
+	poolsMu.RLock()
+	list := make([]*Pool, 0, len(pools))
+	for _, p := range pools {
+		list = append(list, p)
+	}
+	poolsMu.RUnlock()
+
+	result := make([]Stats, 0, len(list))
+	for _, p := range list {
+		result = append(result, p.Stats())
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// URL returns the preferred base URL (scheme://host, "" for an empty pool)
+func (p *Pool) URL() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.endpoints) == 0 {
+		return ""
+	}
+	return p.endpoints[p.current].base
+}
+
+// rewrite returns a copy of u pointing at the preferred endpoint
+func (p *Pool) rewrite(u *url.URL) *url.URL {
+	rewritten := *u
+	base := p.URL()
+	if base == "" {
+		return &rewritten
+	}
+	if i := strings.Index(base, "://"); i >= 0 {
+		rewritten.Scheme = base[:i]
+		rewritten.Host = base[i+3:]
+	}
+	return &rewritten
+}
+
+// find returns the endpoint of host (caller must hold lock)
+func (p *Pool) find(host string) (int, *endpointState) {
+	for i, e := range p.endpoints {
+		if hostOf(e.base) == host {
+			return i, e
+		}
+	}
+	return -1, nil
+}
+
+// observe updates an endpoint's health (caller must hold lock), returns the endpoint (nil if unknown)
+// Failures of the current endpoint fail over once it becomes unhealthy. Request latency isn't
+// recorded here since it includes server-side work (e.g. order matching), probes measure latency
+func (p *Pool) observe(host string, err error) *endpointState {
+	i, e := p.find(host)
+	if e == nil {
+		return nil
+	}
+	e.lastSeen = p.now()
+	if err != nil {
+		e.failures++
+		e.lastError = err.Error()
+		if i == p.current && !e.healthy() {
+			p.failover()
+		}
+		return e
+	}
+	e.failures = 0
+	e.lastError = ""
+	return e
+}
+
+// recordProbe records a probe result (latency of successful probes is smoothed with an EWMA)
+func (p *Pool) recordProbe(host string, latency time.Duration, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e := p.observe(host, err)
+	if e == nil || err != nil {
+		return
+	}
+	if e.samples == 0 {
+		e.latency = latency
+	} else {
+		e.latency = time.Duration(latencyAlpha*float64(latency) + (1-latencyAlpha)*float64(e.latency))
+	}
+	e.samples++
+}
+
+// failover moves to the fastest healthy endpoint other than the current one (caller must hold lock)
+// Stays on the current endpoint when none is healthy
+func (p *Pool) failover() {
+	best := -1
+	for i, e := range p.endpoints {
+		if i == p.current || !e.healthy() {
+			continue
+		}
+		if best < 0 || faster(e, p.endpoints[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		logger.Warnf("⚠️ [endpoint %s] %s failing and no healthy alternative", p.name, p.endpoints[p.current].base)
+		return
+	}
+	logger.Warnf("🔀 [endpoint %s] Failing over %s → %s", p.name, p.endpoints[p.current].base, p.endpoints[best].base)
+	p.current = best
+	p.switches++
+}
+
+// faster reports whether a is faster than b (endpoints without samples are slowest)
+func faster(a, b *endpointState) bool {
+	if a.samples == 0 {
+		return false
+	}
+	if b.samples == 0 {
+		return true
+	}
+	return a.latency < b.latency
+}
+
+// reselect switches to a healthy endpoint that is clearly faster than the current one (caller must hold lock)
+func (p *Pool) reselect() {
+	if len(p.endpoints) < 2 {
+		return
+	}
+	cur := p.endpoints[p.current]
+	if !cur.healthy() {
+		p.failover()
+		return
+	}
+	if cur.samples == 0 {
+		return
+	}
+
+	best := p.current
+	for i, e := range p.endpoints {
+		if e.healthy() && e.samples > 0 && e.latency < p.endpoints[best].latency {
+			best = i
+		}
+	}
+	if best == p.current {
+		return
+	}
+	gain := cur.latency - p.endpoints[best].latency
+	if gain < minSwitchGain || float64(gain) < switchMargin*float64(cur.latency) {
+		return
+	}
+	logger.Infof("⚡ [endpoint %s] Switching %s (%v) → %s (%v)", p.name,
+		cur.base, cur.latency.Round(time.Millisecond), p.endpoints[best].base, p.endpoints[best].latency.Round(time.Millisecond))
+	p.current = best
+	p.switches++
+}
+
+// Stats returns current pool metrics
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s := Stats{Name: p.name, Switches: p.switches, Endpoints: make([]EndpointStats, 0, len(p.endpoints))}
+	if len(p.endpoints) > 0 {
+		s.Current = p.endpoints[p.current].base
+	}
+	for _, e := range p.endpoints {
+		s.Endpoints = append(s.Endpoints, EndpointStats{
+			URL:       e.base,
+			LatencyMs: float64(e.latency) / float64(time.Millisecond),
+			Samples:   e.samples,
+			Healthy:   e.healthy(),
+			Failures:  e.failures,
+			LastError: e.lastError,
+			LastSeen:  e.lastSeen,
+		})
+	}
+	return s
+}
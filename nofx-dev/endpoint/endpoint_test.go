@@ -0,0 +1,146 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// fakeProbe returns fixed latencies per base URL (missing = error)
+func fakeProbe(latencies map[string]time.Duration) ProbeFunc {
+	return func(base, path string) (time.Duration, error) {
+		if l, ok := latencies[base]; ok {
+			return l, nil
+		}
+		return 0, errors.New("unreachable")
+	}
+}
+
+// TestSelectionIsSticky tests that only a clear latency gain moves traffic
+func TestSelectionIsSticky(t *testing.T) {
+	Reset()
+	defer Reset()
+	p := Register("svc", "", "https://a.example.com", "https://b.example.com/v1")
+
+	if got := p.URL(); got != "https://a.example.com" {
+		t.Fatalf("default should be the first URL, got %s", got)
+	}
+
+	latencies := map[string]time.Duration{
+		"https://a.example.com": 100 * time.Millisecond,
+		"https://b.example.com": 90 * time.Millisecond, // 10% faster: not enough
+	}
+	pr := NewProber(time.Minute, fakeProbe(latencies))
+	pr.ProbeAll()
+	if got := p.URL(); got != "https://a.example.com" {
+		t.Errorf("small gain should keep current endpoint, got %s", got)
+	}
+
+	latencies["https://b.example.com"] = 20 * time.Millisecond
+	for i := 0; i < 5; i++ {
+		pr.ProbeAll()
+	}
+	if got := p.URL(); got != "https://b.example.com" {
+		t.Errorf("clearly faster endpoint should be preferred, got %s", got)
+	}
+	if got := p.Stats().Switches; got != 1 {
+		t.Errorf("expected 1 switch, got %d", got)
+	}
+}
+
+// TestFailover tests that repeated failures of the current endpoint move traffic
+func TestFailover(t *testing.T) {
+	Reset()
+	defer Reset()
+	p := Register("svc", "", "https://a.example.com", "https://b.example.com")
+
+	for i := 0; i < failoverThreshold-1; i++ {
+		Observe("https://a.example.com/x", errors.New("timeout"))
+	}
+	if got := p.URL(); got != "https://a.example.com" {
+		t.Fatalf("should not fail over before threshold, got %s", got)
+	}
+
+	// A success resets the consecutive failure count
+	Observe("https://a.example.com/x", nil)
+	Observe("https://a.example.com/x", errors.New("timeout"))
+	if got := p.URL(); got != "https://a.example.com" {
+		t.Fatalf("failures should be consecutive, got %s", got)
+	}
+
+	for i := 0; i < failoverThreshold; i++ {
+		Observe("https://a.example.com/x", errors.New("timeout"))
+	}
+	if got := p.URL(); got != "https://b.example.com" {
+		t.Errorf("expected failover to b, got %s", got)
+	}
+
+	// Both unhealthy: stay where we are
+	for i := 0; i < failoverThreshold; i++ {
+		Observe("https://b.example.com/x", errors.New("timeout"))
+	}
+	if got := p.URL(); got != "https://b.example.com" {
+		t.Errorf("no healthy alternative should keep current, got %s", got)
+	}
+}
+
+// TestResolve tests URL rewriting for pooled and unknown hosts
+func TestResolve(t *testing.T) {
+	Reset()
+	defer Reset()
+	p := Register("ws", "", "wss://a.example.com", "wss://b.example.com")
+
+	if got := Resolve("wss://other.example.com/stream"); got != "wss://other.example.com/stream" {
+		t.Errorf("unknown host should be unchanged, got %s", got)
+	}
+
+	p.mu.Lock()
+	p.current = 1
+	p.mu.Unlock()
+	if got := Resolve("wss://A.example.com/stream?x=1"); got != "wss://b.example.com/stream?x=1" {
+		t.Errorf("expected rewrite to b, got %s", got)
+	}
+}
+
+// TestTransportRewritesAndObserves tests request routing and 5xx health feedback
+func TestTransportRewritesAndObserves(t *testing.T) {
+	Reset()
+	defer Reset()
+
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.URL.Path != "/api/time" {
+			t.Errorf("path should be preserved, got %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	// Preferred endpoint is the test server, requests are addressed to the primary domain
+	p := Register("svc", "", "https://primary.invalid", srv.URL)
+	p.mu.Lock()
+	p.current = 1
+	p.mu.Unlock()
+
+	client := WrapClient(nil)
+	resp, err := client.Get("https://primary.invalid/api/time")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if hits != 1 {
+		t.Fatalf("request should reach the preferred endpoint, hits=%d", hits)
+	}
+
+	u, _ := url.Parse(srv.URL)
+	stats := p.Stats()
+	for _, e := range stats.Endpoints {
+		if e.URL == "http://"+u.Host && e.Failures != 1 {
+			t.Errorf("5xx should count as a failure, got %d", e.Failures)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package endpoint
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const probeTimeout = 5 * time.Second
+
+// ProbeFunc measures the round trip to a base URL
+type ProbeFunc func(base, path string) (time.Duration, error)
+
+// Prober periodically probes all pools and re-selects their preferred endpoints
+type Prober struct {
+	interval time.Duration
+	probe    ProbeFunc
+
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+}
+
+var (
+	defaultProber   *Prober
+	defaultProberMu sync.Mutex
+)
+
+// NewProber creates a prober (probe nil = HTTP/TCP round trip)
+func NewProber(interval time.Duration, probe ProbeFunc) *Prober {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	if probe == nil {
+		probe = roundTrip
+	}
+	return &Prober{interval: interval, probe: probe}
+}
+
+// StartDefault starts the process-wide prober (idempotent)
+func StartDefault(interval time.Duration) *Prober {
+	defaultProberMu.Lock()
+	defer defaultProberMu.Unlock()
+	if defaultProber == nil {
+		defaultProber = NewProber(interval, nil)
+		defaultProber.Start()
+	}
+	return defaultProber
+}
+
+// Start probes immediately, then every interval
+func (pr *Prober) Start() {
+	pr.mu.Lock()
+	if pr.running {
+		pr.mu.Unlock()
+		return
+	}
+	pr.running = true
+	pr.stopCh = make(chan struct{})
+	stopCh := pr.stopCh
+	pr.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(pr.interval)
+		defer ticker.Stop()
+		pr.ProbeAll()
+		for {
+			select {
+			case <-ticker.C:
+				pr.ProbeAll()
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops background probing
+func (pr *Prober) Stop() {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if !pr.running {
+		return
+	}
+	pr.running = false
+	close(pr.stopCh)
+}
+
+// ProbeAll probes every endpoint of every registered pool concurrently
+func (pr *Prober) ProbeAll() {
+	poolsMu.RLock()
+	list := make([]*Pool, 0, len(pools))
+	for _, p := range pools {
+		list = append(list, p)
+	}
+	poolsMu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, p := range list {
+		wg.Add(1)
+		go func(p *Pool) {
+			defer wg.Done()
+			pr.probePool(p)
+		}(p)
+	}
+	wg.Wait()
+}
+
+// probePool probes a pool's endpoints, then re-selects the preferred one
+func (pr *Prober) probePool(p *Pool) {
+	p.mu.Lock()
+	bases := make([]string, 0, len(p.endpoints))
+	for _, e := range p.endpoints {
+		bases = append(bases, e.base)
+	}
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, base := range bases {
+		wg.Add(1)
+		go func(base string) {
+			defer wg.Done()
+			latency, err := pr.probe(base, p.probePath)
+			p.recordProbe(hostOf(base), latency, err)
+		}(base)
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	p.reselect()
+	p.mu.Unlock()
+}
+
+// roundTrip measures an HTTP GET (any non-5xx response is healthy) or, for ws/wss, a TCP connect
+func roundTrip(base, path string) (time.Duration, error) {
+	if strings.HasPrefix(base, "ws") {
+		host := hostOf(base)
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			port := "443"
+			if strings.HasPrefix(base, "ws://") {
+				port = "80"
+			}
+			host = net.JoinHostPort(host, port)
+		}
+		start := time.Now()
+		conn, err := net.DialTimeout("tcp", host, probeTimeout)
+		if err != nil {
+			return 0, err
+		}
+		conn.Close()
+		return time.Since(start), nil
+	}
+
+	client := &http.Client{
+		Timeout: probeTimeout,
+		// Probe the endpoint itself, not where it redirects to
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	start := time.Now()
+	resp, err := client.Get(base + path)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	latency := time.Since(start)
+	if resp.StatusCode >= 500 {
+		return 0, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return latency, nil
+}
@@ -0,0 +1,65 @@
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Transport http.RoundTripper that sends requests for a pooled host to the pool's
+// preferred endpoint and feeds network errors / 5xx responses back into its health
+// Requests are not retried on another endpoint (orders are not idempotent), the next
+// request goes to the new endpoint once the failing one is unhealthy
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport wraps base (nil = http.DefaultTransport)
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	// Avoid double wrapping
+	if t, ok := base.(*Transport); ok {
+		return t
+	}
+	return &Transport{Base: base}
+}
+
+// WrapClient returns a shallow copy of client using the endpoint-selecting transport
+// (the original client is not modified, so shared clients like http.DefaultClient stay untouched)
+func WrapClient(client *http.Client) *http.Client {
+	wrapped := &http.Client{}
+	if client != nil {
+		*wrapped = *client
+	}
+	wrapped.Transport = NewTransport(wrapped.Transport)
+	return wrapped
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	p := forHost(req.URL.Host)
+	if p == nil {
+		return t.Base.RoundTrip(req)
+	}
+
+	out := req.Clone(req.Context())
+	out.URL = p.rewrite(req.URL)
+	out.Host = ""
+	host := strings.ToLower(out.URL.Host)
+
+	resp, err := t.Base.RoundTrip(out)
+
+	p.mu.Lock()
+	switch {
+	case err != nil:
+		p.observe(host, err)
+	case resp.StatusCode >= 500:
+		p.observe(host, fmt.Errorf("HTTP %d", resp.StatusCode))
+	default:
+		p.observe(host, nil)
+	}
+	p.mu.Unlock()
+	return resp, err
+}
@@ -0,0 +1,231 @@
+// Package events keeps a calendar of upcoming high-impact events (FOMC, CPI and other US macro releases from an
+// economic calendar feed, token unlocks and custom events from a local file) so decision cycles can flag them and
+// traders can hold off new entries around them
+package events
+
+import (
+	"fmt"
+	"nofx/logger"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event categories
+const (
+	CategoryMacro  = "macro"  // Economic release or central bank decision, moves the whole market
+	CategoryUnlock = "unlock" // Token unlock, moves the unlocked tokens
+	CategoryCustom = "custom"
+)
+
+// Event one scheduled event
+type Event struct {
+	Title    string    `json:"title"`
+	Category string    `json:"category"`
+	Impact   string    `json:"impact"` // "high" / "medium" / "low"
+	Time     time.Time `json:"time"`
+	// Symbols affected (base assets or USDT pairs), empty = market-wide
+	Symbols []string `json:"symbols,omitempty"`
+	Source  string   `json:"source,omitempty"`
+}
+
+// MarketWide whether the event affects every symbol
+func (e Event) MarketWide() bool {
+	return len(e.Symbols) == 0
+}
+
+// Applies whether the event affects the symbol (market-wide events affect all)
+func (e Event) Applies(symbol string) bool {
+	if e.MarketWide() {
+		return true
+	}
+	base := baseAsset(symbol)
+	for _, s := range e.Symbols {
+		if baseAsset(s) == base {
+			return true
+		}
+	}
+	return false
+}
+
+// Describe one line for prompts, e.g. "FOMC Statement (macro, market-wide) in 3h20m"
+func (e Event) Describe(now time.Time) string {
+	scope := "market-wide"
+	if !e.MarketWide() {
+		scope = strings.Join(e.Symbols, ", ")
+	}
+	until := e.Time.Sub(now)
+	when := "in " + formatDuration(until)
+	if until < 0 {
+		when = formatDuration(-until) + " ago"
+	}
+	return fmt.Sprintf("%s (%s, %s) %s at %s", e.Title, e.Category, scope, when, e.Time.UTC().Format("15:04 UTC"))
+}
+
+// formatDuration rounded to minutes, e.g. "3h20m"
+func formatDuration(d time.Duration) string {
+	s := d.Round(time.Minute).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	return s
+}
+
+// baseAsset "ARBUSDT" / "arb" -> "ARB"
+func baseAsset(symbol string) string {
+	return strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(symbol)), "USDT")
+}
+
+// Source loads events from one feed
+type Source interface {
+	Name() string
+	Fetch() ([]Event, error)
+}
+
+// Calendar merged events of its sources, refreshed periodically
+type Calendar struct {
+	sources  []Source
+	interval time.Duration
+
+	mu        sync.RWMutex
+	events    []Event // Sorted by time
+	updatedAt time.Time
+	running   bool
+	stopCh    chan struct{}
+}
+
+// NewCalendar creates a calendar over the sources (interval <= 0 = 1 hour)
+func NewCalendar(interval time.Duration, sources ...Source) *Calendar {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &Calendar{sources: sources, interval: interval}
+}
+
+// Refresh reloads every source. A failing source keeps its previously loaded events
+func (c *Calendar) Refresh() {
+	c.mu.RLock()
+	previous := make(map[string][]Event)
+	for _, e := range c.events {
+		previous[e.Source] = append(previous[e.Source], e)
+	}
+	c.mu.RUnlock()
+
+	var merged []Event
+	for _, source := range c.sources {
+		loaded, err := source.Fetch()
+		if err != nil {
+			logger.Warnf("⚠️ Failed to load events from %s: %v", source.Name(), err)
+			merged = append(merged, previous[source.Name()]...)
+			continue
+		}
+		for _, e := range loaded {
+			e.Source = source.Name()
+			merged = append(merged, e)
+		}
+	}
+	c.set(merged)
+}
+
+// set replaces the events (sorted by time)
+func (c *Calendar) set(events []Event) {
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	c.mu.Lock()
+	c.events = events
+	c.updatedAt = time.Now()
+	c.mu.Unlock()
+}
+
+// Upcoming events between now and now + within, soonest first
+func (c *Calendar) Upcoming(now time.Time, within time.Duration) []Event {
+	return c.Between(now, now.Add(within))
+}
+
+// Between events in [from, to], soonest first
+func (c *Calendar) Between(from, to time.Time) []Event {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var result []Event
+	for _, e := range c.events {
+		if !e.Time.Before(from) && !e.Time.After(to) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Blocking the first high-impact event affecting symbol from before it until after it, nil if none
+func (c *Calendar) Blocking(symbol string, now time.Time, before, after time.Duration) *Event {
+	for _, e := range c.Between(now.Add(-after), now.Add(before)) {
+		if e.Impact == "high" && e.Applies(symbol) {
+			return &e
+		}
+	}
+	return nil
+}
+
+// Start refreshes immediately, then every interval
+func (c *Calendar) Start() {
+	c.mu.Lock()
+	if c.running {
+		c.mu.Unlock()
+		return
+	}
+	c.running = true
+	c.stopCh = make(chan struct{})
+	stopCh := c.stopCh
+	c.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		c.Refresh()
+		for {
+			select {
+			case <-ticker.C:
+				c.Refresh()
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops background refreshing
+func (c *Calendar) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.running {
+		return
+	}
+	c.running = false
+	close(c.stopCh)
+}
+
+var (
+	defaultCalendar   *Calendar
+	defaultCalendarMu sync.Mutex
+)
+
+// StartDefault starts the process-wide calendar over the economic calendar feed and, if set, a local events file (idempotent)
+func StartDefault(interval time.Duration, file string) *Calendar {
+	defaultCalendarMu.Lock()
+	defer defaultCalendarMu.Unlock()
+	if defaultCalendar == nil {
+		sources := []Source{NewEconomicCalendarSource("")}
+		if file != "" {
+			sources = append(sources, NewFileSource(file))
+		}
+		defaultCalendar = NewCalendar(interval, sources...)
+		defaultCalendar.Start()
+	}
+	return defaultCalendar
+}
+
+// Default returns the process-wide calendar, nil when it wasn't started
+func Default() *Calendar {
+	defaultCalendarMu.Lock()
+	defer defaultCalendarMu.Unlock()
+	return defaultCalendar
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubSource returns fixed events or an error
+type stubSource struct {
+	name   string
+	events []Event
+	err    error
+}
+
+func (s *stubSource) Name() string            { return s.name }
+func (s *stubSource) Fetch() ([]Event, error) { return s.events, s.err }
+
+// TestParseEconomicCalendar tests that only timed high-impact USD entries are kept
+func TestParseEconomicCalendar(t *testing.T) {
+	body := []byte(`[
+		{"title":"CPI m/m","country":"USD","date":"2024-01-11T08:30:00-05:00","impact":"High","forecast":"0.2%"},
+		{"title":"Unemployment Claims","country":"USD","date":"2024-01-11T08:30:00-05:00","impact":"Medium"},
+		{"title":"Main Refinancing Rate","country":"EUR","date":"2024-01-25T08:15:00-05:00","impact":"High"},
+		{"title":"FOMC Member Speaks","country":"USD","date":"Tentative","impact":"High"}
+	]`)
+	events, err := parseEconomicCalendar(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].Title != "USD CPI m/m" || !events[0].MarketWide() {
+		t.Fatalf("expected only the USD CPI release, got %+v", events)
+	}
+	if want := time.Date(2024, 1, 11, 13, 30, 0, 0, time.UTC); !events[0].Time.Equal(want) {
+		t.Errorf("time = %v, want %v", events[0].Time, want)
+	}
+}
+
+// TestParseEventsFile tests defaults and validation of the local events file
+func TestParseEventsFile(t *testing.T) {
+	events, err := parseEventsFile([]byte(`[{"title":"ARB unlock","category":"unlock","time":"2024-03-16T13:00:00Z","symbols":["arb"]}]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].Impact != "high" || events[0].Symbols[0] != "ARB" {
+		t.Errorf("unexpected events: %+v", events)
+	}
+	if _, err := parseEventsFile([]byte(`[{"title":"no time"}]`)); err == nil {
+		t.Error("an event without time should be rejected")
+	}
+}
+
+// TestCalendarWindows tests upcoming events, symbol matching and the entry pause window
+func TestCalendarWindows(t *testing.T) {
+	now := time.Date(2024, 3, 16, 10, 0, 0, 0, time.UTC)
+	macro := &stubSource{name: "macro", events: []Event{
+		{Title: "USD FOMC Statement", Category: CategoryMacro, Impact: "high", Time: now.Add(3 * time.Hour)},
+		{Title: "USD Retail Sales", Category: CategoryMacro, Impact: "medium", Time: now.Add(20 * time.Minute)},
+	}}
+	unlocks := &stubSource{name: "file", events: []Event{
+		{Title: "ARB unlock", Category: CategoryUnlock, Impact: "high", Time: now.Add(10 * time.Minute), Symbols: []string{"ARB"}},
+	}}
+	c := NewCalendar(0, macro, unlocks)
+	c.Refresh()
+
+	upcoming := c.Upcoming(now, 4*time.Hour)
+	if len(upcoming) != 3 || upcoming[0].Title != "ARB unlock" || upcoming[2].Source != "macro" {
+		t.Fatalf("expected 3 events soonest first, got %+v", upcoming)
+	}
+	if !upcoming[0].Applies("ARBUSDT") || upcoming[0].Applies("BTCUSDT") || !upcoming[2].Applies("BTCUSDT") {
+		t.Error("unlock should only apply to ARB, FOMC to every symbol")
+	}
+	if d := upcoming[2].Describe(now); !strings.Contains(d, "in 3h0m") || !strings.Contains(d, "market-wide") {
+		t.Errorf("unexpected description: %s", d)
+	}
+
+	if e := c.Blocking("ARBUSDT", now, 30*time.Minute, 30*time.Minute); e == nil || e.Title != "ARB unlock" {
+		t.Errorf("ARB entries should be paused by the unlock, got %+v", e)
+	}
+	if e := c.Blocking("BTCUSDT", now, 30*time.Minute, 30*time.Minute); e != nil {
+		t.Errorf("medium-impact events shouldn't pause entries, got %+v", e)
+	}
+	if e := c.Blocking("BTCUSDT", now.Add(3*time.Hour+20*time.Minute), 30*time.Minute, 30*time.Minute); e == nil {
+		t.Error("entries should stay paused shortly after FOMC")
+	}
+
+	// A failing source keeps what it loaded before
+	unlocks.err = errors.New("file missing")
+	c.Refresh()
+	if len(c.Upcoming(now, 4*time.Hour)) != 3 {
+		t.Error("events of a failing source should be kept")
+	}
+}
@@ -0,0 +1,119 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"nofx/endpoint"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultEconomicCalendarURL this week's economic calendar (Forex Factory export)
+const DefaultEconomicCalendarURL = "https://nfs.faireconomy.media/ff_calendar_thisweek.json"
+
+// EconomicCalendarSource high-impact US releases (FOMC, CPI, NFP, ...) from an economic calendar feed, market-wide
+type EconomicCalendarSource struct {
+	url    string
+	client *http.Client
+}
+
+// NewEconomicCalendarSource creates the source (url "" = DefaultEconomicCalendarURL)
+func NewEconomicCalendarSource(url string) *EconomicCalendarSource {
+	if url == "" {
+		url = DefaultEconomicCalendarURL
+	}
+	return &EconomicCalendarSource{url: url, client: endpoint.WrapClient(&http.Client{Timeout: 15 * time.Second})}
+}
+
+func (s *EconomicCalendarSource) Name() string { return "economic_calendar" }
+
+func (s *EconomicCalendarSource) Fetch() ([]Event, error) {
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get economic calendar: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read economic calendar: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("economic calendar request failed: %s", resp.Status)
+	}
+	return parseEconomicCalendar(body)
+}
+
+// parseEconomicCalendar keeps the high-impact USD entries of the calendar export
+func parseEconomicCalendar(body []byte) ([]Event, error) {
+	var entries []struct {
+		Title   string `json:"title"`
+		Country string `json:"country"`
+		Date    string `json:"date"` // RFC 3339 with the publisher's offset
+		Impact  string `json:"impact"`
+	}
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse economic calendar: %w", err)
+	}
+
+	var result []Event
+	for _, entry := range entries {
+		if entry.Country != "USD" || !strings.EqualFold(entry.Impact, "high") {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, entry.Date)
+		if err != nil {
+			continue // All-day / tentative entries have no time
+		}
+		result = append(result, Event{Title: "USD " + entry.Title, Category: CategoryMacro, Impact: "high", Time: t})
+	}
+	return result, nil
+}
+
+// FileSource events from a local JSON file (an array of Event, e.g. token unlocks), re-read on every refresh
+type FileSource struct {
+	path string
+}
+
+// NewFileSource creates the source
+func NewFileSource(path string) *FileSource {
+	return &FileSource{path: path}
+}
+
+func (s *FileSource) Name() string { return "file" }
+
+func (s *FileSource) Fetch() ([]Event, error) {
+	body, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	return parseEventsFile(body)
+}
+
+// parseEventsFile parses a JSON array of events, category defaults to custom and impact to high
+func parseEventsFile(body []byte) ([]Event, error) {
+	var loaded []Event
+	if err := json.Unmarshal(body, &loaded); err != nil {
+		return nil, fmt.Errorf("failed to parse events file: %w", err)
+	}
+	result := make([]Event, 0, len(loaded))
+	for i, e := range loaded {
+		if e.Title == "" || e.Time.IsZero() {
+			return nil, fmt.Errorf("event %d: title and time are required", i+1)
+		}
+		if e.Category == "" {
+			e.Category = CategoryCustom
+		}
+		if e.Impact == "" {
+			e.Impact = "high"
+		}
+		e.Impact = strings.ToLower(e.Impact)
+		for j, symbol := range e.Symbols {
+			e.Symbols[j] = strings.ToUpper(symbol)
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
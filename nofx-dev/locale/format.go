@@ -0,0 +1,189 @@
+package locale
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default locale used when a user hasn't chosen one
+const Default = "en"
+
+// style number and date conventions of one locale
+type style struct {
+	group    string // Thousands separator (no-break spaces where the locale uses spaces)
+	decimal  string // Decimal separator
+	date     string // Go time layout of a date
+	clock    string // Go time layout of a time of day
+	pctSpace bool   // No-break space between a number and "%"
+}
+
+// styles supported locales (language tags, case-insensitive; "zh-CN" falls back to "zh")
+var styles = map[string]style{
+	"en":    {group: ",", decimal: ".", date: "Jan 2, 2006", clock: "15:04"},
+	"en-us": {group: ",", decimal: ".", date: "Jan 2, 2006", clock: "3:04 PM"},
+	"en-gb": {group: ",", decimal: ".", date: "2 Jan 2006", clock: "15:04"},
+	"zh":    {group: ",", decimal: ".", date: "2006年1月2日", clock: "15:04"},
+	"ja":    {group: ",", decimal: ".", date: "2006/01/02", clock: "15:04"},
+	"ko":    {group: ",", decimal: ".", date: "2006. 1. 2.", clock: "15:04"},
+	"de":    {group: ".", decimal: ",", date: "02.01.2006", clock: "15:04", pctSpace: true},
+	"es":    {group: ".", decimal: ",", date: "02/01/2006", clock: "15:04", pctSpace: true},
+	"fr":    {group: "\u202f", decimal: ",", date: "02/01/2006", clock: "15:04", pctSpace: true},
+	"ru":    {group: "\u00a0", decimal: ",", date: "02.01.2006", clock: "15:04", pctSpace: true},
+}
+
+// Supported returns the supported locale tags, sorted
+func Supported() []string {
+	tags := make([]string, 0, len(styles))
+	for tag := range styles {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// resolve finds the style of a tag, trying the full tag ("en-GB") then its language ("en")
+func resolve(tag string) (style, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if tag == "" {
+		tag = Default
+	}
+	if s, ok := styles[tag]; ok {
+		return s, true
+	}
+	if i := strings.Index(tag, "-"); i > 0 {
+		s, ok := styles[tag[:i]]
+		return s, ok
+	}
+	return style{}, false
+}
+
+// Formatter formats numbers, amounts and timestamps for one user: locale separators and date order,
+// timestamps converted to the user's time zone. The zero value formats like the default locale in UTC
+type Formatter struct {
+	style style
+	loc   *time.Location
+}
+
+// New creates a formatter for a locale tag ("" = Default) and IANA time zone ("" = UTC)
+func New(tag, timezone string) (Formatter, error) {
+	s, ok := resolve(tag)
+	if !ok {
+		return Formatter{}, fmt.Errorf("unsupported locale %q (supported: %s)", tag, strings.Join(Supported(), ", "))
+	}
+	f := Formatter{style: s, loc: time.UTC}
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return Formatter{}, fmt.Errorf("invalid time zone %q: %w", timezone, err)
+		}
+		f.loc = loc
+	}
+	return f, nil
+}
+
+// styleOrDefault the formatter's style, the default locale's for the zero value
+func (f Formatter) styleOrDefault() style {
+	if f.style.decimal == "" {
+		return styles[Default]
+	}
+	return f.style
+}
+
+// Location time zone timestamps are shown in
+func (f Formatter) Location() *time.Location {
+	if f.loc == nil {
+		return time.UTC
+	}
+	return f.loc
+}
+
+// Number formats v with decimals fraction digits and thousands separators, e.g. 1234.5 -> "1,234.50" (en), "1.234,50" (de)
+func (f Formatter) Number(v float64, decimals int) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	s := f.styleOrDefault()
+	raw := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	intPart, frac, _ := strings.Cut(raw, ".")
+
+	var b strings.Builder
+	if v < 0 && strings.Trim(raw, "0.") != "" {
+		b.WriteByte('-')
+	}
+	for i, digit := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(s.group)
+		}
+		b.WriteRune(digit)
+	}
+	if frac != "" {
+		b.WriteString(s.decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// Decimal formats v with up to maxDecimals fraction digits, trailing zeros dropped (quantities, e.g. "0.015")
+func (f Formatter) Decimal(v float64, maxDecimals int) string {
+	raw := strconv.FormatFloat(v, 'f', maxDecimals, 64)
+	if strings.Contains(raw, ".") {
+		raw = strings.TrimRight(strings.TrimRight(raw, "0"), ".")
+	}
+	decimals := 0
+	if i := strings.Index(raw, "."); i >= 0 {
+		decimals = len(raw) - i - 1
+	}
+	return f.Number(v, decimals)
+}
+
+// Price formats a price with enough significant digits for low-priced assets (at least 2 decimals)
+func (f Formatter) Price(v float64) string {
+	decimals := 2
+	if abs := math.Abs(v); abs > 0 && abs < 1 {
+		decimals = min(8, int(math.Ceil(-math.Log10(abs)))+3)
+	} else if abs < 100 {
+		decimals = 4
+	}
+	return f.Number(v, decimals)
+}
+
+// Amount formats an amount of an asset with 2 decimals, e.g. "1,234.56 USDT"
+func (f Formatter) Amount(v float64, asset string) string {
+	return f.Number(v, 2) + " " + asset
+}
+
+// SignedAmount formats a P&L amount with an explicit sign, e.g. "+12.30 USDT"
+func (f Formatter) SignedAmount(v float64, asset string) string {
+	if v > 0 {
+		return "+" + f.Amount(v, asset)
+	}
+	return f.Amount(v, asset)
+}
+
+// Percent formats a percentage (v in %, e.g. 12.5 -> "12.50%" / "12,50 %")
+func (f Formatter) Percent(v float64, decimals int) string {
+	if f.styleOrDefault().pctSpace {
+		return f.Number(v, decimals) + "\u00a0%"
+	}
+	return f.Number(v, decimals) + "%"
+}
+
+// Date formats the date of t in the user's time zone
+func (f Formatter) Date(t time.Time) string {
+	return t.In(f.Location()).Format(f.styleOrDefault().date)
+}
+
+// Clock formats the time of day of t in the user's time zone
+func (f Formatter) Clock(t time.Time) string {
+	return t.In(f.Location()).Format(f.styleOrDefault().clock)
+}
+
+// DateTime formats t as date, time of day and time zone abbreviation in the user's time zone
+func (f Formatter) DateTime(t time.Time) string {
+	local := t.In(f.Location())
+	return local.Format(f.styleOrDefault().date) + " " + local.Format(f.styleOrDefault().clock) + " " + local.Format("MST")
+}
@@ -0,0 +1,87 @@
+package locale
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNumber tests separators and rounding per locale
+func TestNumber(t *testing.T) {
+	tests := []struct {
+		locale   string
+		v        float64
+		decimals int
+		want     string
+	}{
+		{"en", 1234567.891, 2, "1,234,567.89"},
+		{"de", 1234567.891, 2, "1.234.567,89"},
+		{"fr-FR", 1234.5, 1, "1\u202f234,5"},
+		{"zh-CN", -9876.54321, 3, "-9,876.543"},
+		{"en", 999.999, 2, "1,000.00"},
+		{"en", -0.001, 2, "0.00"},
+		{"en", 12, 0, "12"},
+	}
+	for _, tt := range tests {
+		f, err := New(tt.locale, "")
+		if err != nil {
+			t.Fatalf("New(%s): %v", tt.locale, err)
+		}
+		if got := f.Number(tt.v, tt.decimals); got != tt.want {
+			t.Errorf("%s Number(%v, %d) = %q, want %q", tt.locale, tt.v, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+// TestAmountsAndPercent tests amount, signed P&L and percent formatting
+func TestAmountsAndPercent(t *testing.T) {
+	en, _ := New("en", "")
+	de, _ := New("de", "")
+	if got := en.SignedAmount(12.3, "USDT"); got != "+12.30 USDT" {
+		t.Errorf("unexpected signed amount %q", got)
+	}
+	if got := de.Amount(-1500, "USDT"); got != "-1.500,00 USDT" {
+		t.Errorf("unexpected amount %q", got)
+	}
+	if got := de.Percent(12.5, 1); got != "12,5\u00a0%" {
+		t.Errorf("unexpected percent %q", got)
+	}
+	if got := de.Decimal(1234.0150, 8); got != "1.234,015" {
+		t.Errorf("unexpected decimal %q", got)
+	}
+	if got := en.Price(0.000123456); got != "0.0001235" {
+		t.Errorf("unexpected low price %q", got)
+	}
+}
+
+// TestDateTime tests timestamps are shown in the user's time zone and date order
+func TestDateTime(t *testing.T) {
+	ts := time.Date(2025, 3, 9, 23, 30, 0, 0, time.UTC)
+	zh, err := New("zh", "Asia/Shanghai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := zh.DateTime(ts); got != "2025年3月10日 07:30 CST" {
+		t.Errorf("unexpected zh date time %q", got)
+	}
+	de, _ := New("de", "Europe/Berlin")
+	if got := de.Date(ts); got != "10.03.2025" {
+		t.Errorf("unexpected de date %q", got)
+	}
+	var zero Formatter
+	if got := zero.Clock(ts); got != "23:30" {
+		t.Errorf("zero formatter should use UTC, got %q", got)
+	}
+}
+
+// TestNewInvalid tests unknown locales and time zones are rejected
+func TestNewInvalid(t *testing.T) {
+	if _, err := New("xx", ""); err == nil {
+		t.Error("expected error for unknown locale")
+	}
+	if _, err := New("en", "Mars/Olympus"); err == nil {
+		t.Error("expected error for unknown time zone")
+	}
+	if _, err := New("", ""); err != nil {
+		t.Errorf("empty locale should use the default, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+)
+
+// FeeSchedule maker/taker fee rates of an exchange (fraction of notional, e.g. 0.0002 = 0.02%)
+type FeeSchedule struct {
+	Maker float64 `json:"maker"`
+	Taker float64 `json:"taker"`
+}
+
+var (
+	feeSchedules   = make(map[string]FeeSchedule)
+	feeSchedulesMu sync.RWMutex
+)
+
+// RegisterFeeSchedule registers the default fee schedule of an exchange
+// Used to estimate fees when the exchange does not report commission on fills
+func RegisterFeeSchedule(exchange string, schedule FeeSchedule) {
+	feeSchedulesMu.Lock()
+	defer feeSchedulesMu.Unlock()
+	feeSchedules[strings.ToLower(exchange)] = schedule
+}
+
+// GetFeeSchedule returns the registered fee schedule of an exchange
+func GetFeeSchedule(exchange string) (FeeSchedule, bool) {
+	feeSchedulesMu.RLock()
+	defer feeSchedulesMu.RUnlock()
+	schedule, ok := feeSchedules[strings.ToLower(exchange)]
+	return schedule, ok
+}
+
+// EstimateFee estimates the fee of a fill from the registered schedule (0 if not registered)
+func EstimateFee(exchange string, notional float64, maker bool) float64 {
+	schedule, ok := GetFeeSchedule(exchange)
+	if !ok {
+		return 0
+	}
+	if notional < 0 {
+		notional = -notional
+	}
+	if maker {
+		return notional * schedule.Maker
+	}
+	return notional * schedule.Taker
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+)
+
+// quoteRates USD value of one unit of a quote asset, stablecoins default to 1
+var (
+	quoteRates = map[string]float64{
+		"USDT":  1,
+		"USDC":  1,
+		"BUSD":  1,
+		"FDUSD": 1,
+	}
+	quoteRatesMu sync.RWMutex
+)
+
+// RegisterQuoteRate sets the USD value of one unit of a quote asset
+// Used when a stablecoin trades off peg or a non-USD quote asset is configured
+func RegisterQuoteRate(asset string, usdRate float64) {
+	if usdRate <= 0 {
+		return
+	}
+	quoteRatesMu.Lock()
+	defer quoteRatesMu.Unlock()
+	quoteRates[strings.ToUpper(asset)] = usdRate
+}
+
+// QuoteRate returns the USD value of one unit of a quote asset (1 if unknown)
+func QuoteRate(asset string) float64 {
+	quoteRatesMu.RLock()
+	defer quoteRatesMu.RUnlock()
+	if rate, ok := quoteRates[strings.ToUpper(asset)]; ok {
+		return rate
+	}
+	return 1
+}
+
+// ToUSD converts a PnL or fee amount denominated in a quote asset to USD
+// Keeps P&L of USDT-, USDC- and BUSD-margined positions comparable in records and stats
+func ToUSD(asset string, amount float64) float64 {
+	return amount * QuoteRate(asset)
+}
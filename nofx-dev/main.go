@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"nofx/api"
 	"nofx/auth"
 	"nofx/backtest"
+	"nofx/benchmark"
 	"nofx/config"
 	"nofx/crypto"
+	"nofx/diagnostics"
+	"nofx/endpoint"
+	"nofx/events"
 	"nofx/logger"
 	"nofx/manager"
 	"nofx/market"
@@ -27,6 +32,7 @@ func main() {
 
 	// Initialize logger
 	logger.Init(nil)
+	defer logger.Shutdown() // Deferred first, so the log file is closed after everything else
 
 	logger.Info("╔════════════════════════════════════════════════════════════╗")
 	logger.Info("║    🤖 AI Multi-Model Trading System - DeepSeek & Qwen      ║")
@@ -58,6 +64,13 @@ func main() {
 	defer st.Close()
 	backtest.UseDatabase(st.DB())
 
+	// Runtime feature flag overrides take precedence over FEATURE_FLAGS
+	if flags, err := st.FeatureFlag().List(); err != nil {
+		logger.Warnf("⚠️ Failed to load feature flags: %v", err)
+	} else if err := config.SetFeatureFlagOverrides(flags); err != nil {
+		logger.Warnf("⚠️ Ignoring stored feature flags: %v", err)
+	}
+
 	// Initialize encryption service
 	logger.Info("🔐 Initializing encryption service...")
 	cryptoService, err := crypto.NewCryptoService()
@@ -103,6 +116,44 @@ func main() {
 	// Give WebSocket monitor time to initialize
 	time.Sleep(500 * time.Millisecond)
 
+	// Start goroutine leak detector (samples every minute, alerts after 10 consecutive growth cycles)
+	leakDetector := diagnostics.StartDefault(time.Minute, 10)
+	defer leakDetector.Stop()
+
+	// Start AI model benchmarker (warm-standby comparison of configured providers)
+	modelBenchmarker := benchmark.StartDefault(st, cfg.ModelBenchmarkInterval)
+	defer modelBenchmarker.Stop()
+
+	// Start endpoint latency prober (exchange mirrors / LLM regions, sticky with failover)
+	if cfg.EndpointSelection.Enabled {
+		endpoint.RegisterDefaults()
+		for name, urls := range cfg.EndpointSelection.Pools {
+			endpoint.Register(name, "", urls...)
+		}
+		endpointProber := endpoint.StartDefault(cfg.EndpointSelection.ProbeInterval)
+		defer endpointProber.Stop()
+		logger.Info("⚡ Endpoint latency selection enabled")
+	}
+
+	// Persist closed klines for backtests and analytics
+	if cfg.KlineRetentionDays > 0 {
+		klinePersister := market.StartKlinePersistence(st.Kline(), time.Duration(cfg.KlineRetentionDays)*24*time.Hour)
+		defer klinePersister.Stop()
+		logger.Infof("🗄️ Kline persistence enabled (retention %d days)", cfg.KlineRetentionDays)
+	}
+
+	// Start crypto Fear & Greed index fetcher (cached for the decision prompt)
+	fearGreedFetcher := market.StartFearGreedFetcher(30 * time.Minute)
+	defer fearGreedFetcher.Stop()
+
+	// Start quote asset rate fetcher (USD value of USDC for P&L of USDC-margined positions)
+	quoteRateFetcher := market.StartQuoteRateFetcher(5 * time.Minute)
+	defer quoteRateFetcher.Stop()
+
+	// Start event calendar (macro releases, token unlocks) for event flags and entry pauses
+	eventCalendar := events.StartDefault(time.Hour, cfg.EventsFile)
+	defer eventCalendar.Stop()
+
 	// Create TraderManager and BacktestManager
 	traderManager := manager.NewTraderManager()
 	mcpClient := newSharedMCPClient()
@@ -159,8 +210,14 @@ func main() {
 	<-quit
 	logger.Info("📴 Shutdown signal received, closing system...")
 
-	// Stop all traders
-	traderManager.StopAll()
+	// Shut down in priority order: traders stop taking cycles, abandon AI requests, settle orders and flush
+	// their state (bounded by SHUTDOWN_TIMEOUT_SECONDS), then the deferred background services stop
+	// and the database and log file are closed last
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if pending := traderManager.Shutdown(ctx); len(pending) > 0 {
+		logger.Warnf("⚠️ Shutdown deadline (%v) reached, traders still stopping: %v", cfg.ShutdownTimeout, pending)
+	}
 	logger.Info("✅ System shut down safely")
 }
 
@@ -0,0 +1,219 @@
+package manager
+
+import (
+	"fmt"
+	"math"
+	"nofx/store"
+	"sort"
+	"sync"
+	"time"
+)
+
+// leaderboardCacheTTL how long a computed leaderboard is reused
+const leaderboardCacheTTL = time.Minute
+
+// leaderboardWindow lookback span and the bucket equity is resampled to before computing returns
+type leaderboardWindow struct {
+	span   time.Duration
+	bucket time.Duration
+}
+
+// leaderboardWindows selectable windows, buckets keep enough return samples for a meaningful Sharpe
+var leaderboardWindows = map[string]leaderboardWindow{
+	"24h": {span: 24 * time.Hour, bucket: time.Hour},
+	"7d":  {span: 7 * 24 * time.Hour, bucket: 4 * time.Hour},
+	"30d": {span: 30 * 24 * time.Hour, bucket: 24 * time.Hour},
+	"90d": {span: 90 * 24 * time.Hour, bucket: 24 * time.Hour},
+}
+
+// Leaderboard sort keys
+const (
+	LeaderboardSortSharpe   = "sharpe"
+	LeaderboardSortReturn   = "return"
+	LeaderboardSortDrawdown = "drawdown"
+)
+
+// LeaderboardEntry one trader's risk-adjusted performance over the window
+type LeaderboardEntry struct {
+	Rank           int     `json:"rank"`
+	TraderID       string  `json:"trader_id"`
+	TraderName     string  `json:"trader_name"`
+	AIModel        string  `json:"ai_model"`
+	Exchange       string  `json:"exchange"`
+	ReturnPct      float64 `json:"return_pct"`       // Equity change over the window
+	SharpeRatio    float64 `json:"sharpe_ratio"`     // Annualized, from bucketed equity returns
+	VolatilityPct  float64 `json:"volatility_pct"`   // Annualized standard deviation of returns
+	MaxDrawdownPct float64 `json:"max_drawdown_pct"` // Largest peak-to-trough equity decline
+	Samples        int     `json:"samples"`          // Bucketed equity points used
+}
+
+// Leaderboard ranked traders for one window
+type Leaderboard struct {
+	Window    string             `json:"window"`
+	SortBy    string             `json:"sort_by"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	Entries   []LeaderboardEntry `json:"entries"`
+}
+
+// leaderboardCache computed leaderboards by window|sort
+type leaderboardCache struct {
+	mu     sync.Mutex
+	boards map[string]*Leaderboard
+}
+
+// ValidateLeaderboardParams checks window and sort key
+func ValidateLeaderboardParams(window, sortBy string) error {
+	if _, ok := leaderboardWindows[window]; !ok {
+		return fmt.Errorf("invalid window %q, expected 24h, 7d, 30d or 90d", window)
+	}
+	switch sortBy {
+	case LeaderboardSortSharpe, LeaderboardSortReturn, LeaderboardSortDrawdown:
+		return nil
+	}
+	return fmt.Errorf("invalid sort %q, expected sharpe, return or drawdown", sortBy)
+}
+
+// resampleEquity keeps the last equity of every bucket (snapshots oldest first)
+func resampleEquity(snapshots []*store.EquitySnapshot, bucket time.Duration) []float64 {
+	var equity []float64
+	var current time.Time
+	for _, snap := range snapshots {
+		if snap.TotalEquity <= 0 {
+			continue
+		}
+		b := snap.Timestamp.Truncate(bucket)
+		if len(equity) > 0 && b.Equal(current) {
+			equity[len(equity)-1] = snap.TotalEquity
+			continue
+		}
+		current = b
+		equity = append(equity, snap.TotalEquity)
+	}
+	return equity
+}
+
+// computeLeaderboardMetrics return, annualized Sharpe / volatility and max drawdown of a bucketed equity series
+func computeLeaderboardMetrics(equity []float64, bucket time.Duration) LeaderboardEntry {
+	entry := LeaderboardEntry{Samples: len(equity)}
+	if len(equity) < 2 {
+		return entry
+	}
+	entry.ReturnPct = (equity[len(equity)-1] - equity[0]) / equity[0] * 100
+
+	returns := make([]float64, 0, len(equity)-1)
+	peak := equity[0]
+	for i := 1; i < len(equity); i++ {
+		returns = append(returns, equity[i]/equity[i-1]-1)
+		if equity[i] > peak {
+			peak = equity[i]
+		} else if dd := (peak - equity[i]) / peak * 100; dd > entry.MaxDrawdownPct {
+			entry.MaxDrawdownPct = dd
+		}
+	}
+
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	std := 0.0
+	if len(returns) > 1 {
+		std = math.Sqrt(variance / float64(len(returns)-1))
+	}
+
+	periodsPerYear := float64(365*24*time.Hour) / float64(bucket)
+	entry.VolatilityPct = std * math.Sqrt(periodsPerYear) * 100
+	if std > 0 {
+		entry.SharpeRatio = mean / std * math.Sqrt(periodsPerYear)
+	}
+	return entry
+}
+
+// sortLeaderboard ranks entries (ties broken by return, then trader ID for a stable order)
+func sortLeaderboard(entries []LeaderboardEntry, sortBy string) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch sortBy {
+		case LeaderboardSortReturn:
+			if a.ReturnPct != b.ReturnPct {
+				return a.ReturnPct > b.ReturnPct
+			}
+		case LeaderboardSortDrawdown:
+			if a.MaxDrawdownPct != b.MaxDrawdownPct {
+				return a.MaxDrawdownPct < b.MaxDrawdownPct
+			}
+		default:
+			if a.SharpeRatio != b.SharpeRatio {
+				return a.SharpeRatio > b.SharpeRatio
+			}
+		}
+		if a.ReturnPct != b.ReturnPct {
+			return a.ReturnPct > b.ReturnPct
+		}
+		return a.TraderID < b.TraderID
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+}
+
+// GetLeaderboard ranks traders that opted into the competition (show_in_competition) across all users
+// Computed from persisted equity snapshots, so stopped traders and traders not loaded in memory are included
+func (tm *TraderManager) GetLeaderboard(st *store.Store, window, sortBy string) (*Leaderboard, error) {
+	if err := ValidateLeaderboardParams(window, sortBy); err != nil {
+		return nil, err
+	}
+	key := window + "|" + sortBy
+
+	tm.leaderboards.mu.Lock()
+	defer tm.leaderboards.mu.Unlock()
+	if board, ok := tm.leaderboards.boards[key]; ok && time.Since(board.UpdatedAt) < leaderboardCacheTTL {
+		return board, nil
+	}
+
+	traders, err := st.Trader().ListAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list traders: %w", err)
+	}
+
+	w := leaderboardWindows[window]
+	now := time.Now().UTC()
+	entries := []LeaderboardEntry{}
+	for _, t := range traders {
+		if !t.ShowInCompetition {
+			continue
+		}
+		snapshots, err := st.Equity().GetByTimeRange(t.ID, now.Add(-w.span), now)
+		if err != nil {
+			continue
+		}
+		equity := resampleEquity(snapshots, w.bucket)
+		if len(equity) < 2 {
+			continue // Not enough history in this window
+		}
+
+		entry := computeLeaderboardMetrics(equity, w.bucket)
+		entry.TraderID = t.ID
+		entry.TraderName = t.Name
+		entry.AIModel = t.AIModelID
+		if model, err := st.AIModel().Get(t.UserID, t.AIModelID); err == nil {
+			entry.AIModel = model.Provider
+		}
+		if exchange, err := st.Exchange().GetByID(t.UserID, t.ExchangeID); err == nil {
+			entry.Exchange = exchange.ExchangeType
+		}
+		entries = append(entries, entry)
+	}
+	sortLeaderboard(entries, sortBy)
+
+	board := &Leaderboard{Window: window, SortBy: sortBy, UpdatedAt: time.Now(), Entries: entries}
+	if tm.leaderboards.boards == nil {
+		tm.leaderboards.boards = make(map[string]*Leaderboard)
+	}
+	tm.leaderboards.boards[key] = board
+	return board, nil
+}
@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"math"
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+// TestResampleEquity tests that the last equity of each bucket is kept
+func TestResampleEquity(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	snapshots := []*store.EquitySnapshot{
+		{Timestamp: base, TotalEquity: 100},
+		{Timestamp: base.Add(30 * time.Minute), TotalEquity: 101},
+		{Timestamp: base.Add(time.Hour), TotalEquity: 0}, // Failed snapshot, skipped
+		{Timestamp: base.Add(90 * time.Minute), TotalEquity: 103},
+	}
+	got := resampleEquity(snapshots, time.Hour)
+	if len(got) != 2 || got[0] != 101 || got[1] != 103 {
+		t.Errorf("expected [101 103], got %v", got)
+	}
+}
+
+// TestLeaderboardMetricsAndRanking tests return, drawdown and Sharpe ordering
+func TestLeaderboardMetricsAndRanking(t *testing.T) {
+	steady := computeLeaderboardMetrics([]float64{100, 101, 102, 103, 104}, 24*time.Hour)
+	volatile := computeLeaderboardMetrics([]float64{100, 120, 90, 125, 110}, 24*time.Hour)
+
+	if math.Abs(steady.ReturnPct-4) > 1e-9 || steady.MaxDrawdownPct != 0 {
+		t.Errorf("steady: unexpected return %.2f / drawdown %.2f", steady.ReturnPct, steady.MaxDrawdownPct)
+	}
+	if math.Abs(volatile.MaxDrawdownPct-25) > 1e-9 {
+		t.Errorf("volatile: expected 25%% drawdown, got %.2f", volatile.MaxDrawdownPct)
+	}
+
+	steady.TraderID, volatile.TraderID = "steady", "volatile"
+	entries := []LeaderboardEntry{volatile, steady}
+	sortLeaderboard(entries, LeaderboardSortSharpe)
+	if entries[0].TraderID != "steady" || entries[0].Rank != 1 {
+		t.Errorf("steady trader should rank first by Sharpe, got %+v", entries)
+	}
+	sortLeaderboard(entries, LeaderboardSortReturn)
+	if entries[0].TraderID != "volatile" {
+		t.Errorf("volatile trader should rank first by return, got %+v", entries)
+	}
+
+	if err := ValidateLeaderboardParams("1y", LeaderboardSortSharpe); err == nil {
+		t.Error("unknown window should be rejected")
+	}
+}
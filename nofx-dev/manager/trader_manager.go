@@ -23,8 +23,14 @@ type TraderManager struct {
 	traders          map[string]*trader.AutoTrader // key: trader ID
 	competitionCache *CompetitionCache
 	mu               sync.RWMutex
+	events           map[string][]trader.TraderEvent // key: trader ID, most recent last
+	eventsMu         sync.RWMutex
+	leaderboards     leaderboardCache
 }
 
+// maxTraderEvents number of recent events kept per trader
+const maxTraderEvents = 50
+
 // NewTraderManager creates a trader manager
 func NewTraderManager() *TraderManager {
 	return &TraderManager{
@@ -32,7 +38,69 @@ func NewTraderManager() *TraderManager {
 		competitionCache: &CompetitionCache{
 			data: make(map[string]interface{}),
 		},
+		events: make(map[string][]trader.TraderEvent),
+	}
+}
+
+// handleTraderEvent records an event emitted by a trader (exchange circuit open/closed, etc.)
+func (tm *TraderManager) handleTraderEvent(event trader.TraderEvent) {
+	logger.Warnf("📣 Trader %s event [%s]: %s", event.TraderID, event.Type, event.Message)
+
+	tm.eventsMu.Lock()
+	defer tm.eventsMu.Unlock()
+	events := append(tm.events[event.TraderID], event)
+	if len(events) > maxTraderEvents {
+		events = events[len(events)-maxTraderEvents:]
+	}
+	tm.events[event.TraderID] = events
+}
+
+// GetTraderEvents returns recent events of a trader, newest first
+func (tm *TraderManager) GetTraderEvents(traderID string) []trader.TraderEvent {
+	tm.eventsMu.RLock()
+	defer tm.eventsMu.RUnlock()
+	events := tm.events[traderID]
+	result := make([]trader.TraderEvent, 0, len(events))
+	for i := len(events) - 1; i >= 0; i-- {
+		result = append(result, events[i])
+	}
+	return result
+}
+
+// GetAccountExposure aggregates the latest exposure snapshots of all traders on an exchange account
+func (tm *TraderManager) GetAccountExposure(exchangeID string) *trader.AccountExposure {
+	tm.mu.RLock()
+	var snapshots []*trader.ExposureSnapshot
+	for _, t := range tm.traders {
+		if t.GetExchangeID() == exchangeID {
+			snapshots = append(snapshots, t.GetExposure())
+		}
+	}
+	tm.mu.RUnlock()
+	return trader.AggregateExposure(exchangeID, snapshots)
+}
+
+// GetUserExposure returns the notional by symbol across a user's running traders, skipping excludeExchangeID
+// Traders sharing an exchange account see the same positions, so each account is counted once (newest snapshot)
+func (tm *TraderManager) GetUserExposure(userID, excludeExchangeID string) map[string]float64 {
+	tm.mu.RLock()
+	byAccount := make(map[string][]*trader.ExposureSnapshot)
+	for _, t := range tm.traders {
+		if t.GetUserID() != userID || t.GetExchangeID() == excludeExchangeID {
+			continue
+		}
+		byAccount[t.GetExchangeID()] = append(byAccount[t.GetExchangeID()], t.GetExposure())
+	}
+	tm.mu.RUnlock()
+
+	bySymbol := make(map[string]float64)
+	for exchangeID, snapshots := range byAccount {
+		account := trader.AggregateExposure(exchangeID, snapshots)
+		for symbol, notional := range trader.SnapshotExposureBySymbol(&account.ExposureSnapshot) {
+			bySymbol[symbol] += notional
+		}
 	}
+	return bySymbol
 }
 
 // GetTrader retrieves a trader by ID
@@ -47,6 +115,19 @@ func (tm *TraderManager) GetTrader(id string) (*trader.AutoTrader, error) {
 	return t, nil
 }
 
+// GetUserTraders returns the loaded traders of a user
+func (tm *TraderManager) GetUserTraders(userID string) []*trader.AutoTrader {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	var traders []*trader.AutoTrader
+	for _, t := range tm.traders {
+		if t.GetUserID() == userID {
+			traders = append(traders, t)
+		}
+	}
+	return traders
+}
+
 // GetAllTraders retrieves all traders
 func (tm *TraderManager) GetAllTraders() map[string]*trader.AutoTrader {
 	tm.mu.RLock()
@@ -98,6 +179,39 @@ func (tm *TraderManager) StopAll() {
 	}
 }
 
+// Shutdown stops all traders in parallel (each stops its cycle, settles orders and flushes its state),
+// waiting at most until ctx is done. Returns the names of the traders still stopping by then
+func (tm *TraderManager) Shutdown(ctx context.Context) []string {
+	tm.mu.RLock()
+	traders := make([]*trader.AutoTrader, 0, len(tm.traders))
+	for _, t := range tm.traders {
+		traders = append(traders, t)
+	}
+	tm.mu.RUnlock()
+
+	logger.Infof("⏹  Shutting down %d trader(s)...", len(traders))
+	var (
+		mu      sync.Mutex
+		pending []string
+		wg      sync.WaitGroup
+	)
+	for _, t := range traders {
+		wg.Add(1)
+		go func(at *trader.AutoTrader) {
+			defer wg.Done()
+			if err := at.Shutdown(ctx); err != nil {
+				logger.Warnf("⚠️ %v", err)
+				mu.Lock()
+				pending = append(pending, at.GetName())
+				mu.Unlock()
+			}
+		}(t)
+	}
+	wg.Wait()
+	sort.Strings(pending)
+	return pending
+}
+
 // AutoStartRunningTraders automatically starts traders marked as running in the database
 func (tm *TraderManager) AutoStartRunningTraders(st *store.Store) {
 	// Get all trader configurations (single query)
@@ -609,22 +723,103 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 		ID:                    traderCfg.ID,
 		Name:                  traderCfg.Name,
 		AIModel:               aiModelCfg.Provider,
+		AIModelID:             aiModelCfg.ID,
 		Exchange:              exchangeCfg.ExchangeType, // Exchange type: binance/bybit/okx/etc
 		ExchangeID:            exchangeCfg.ID,           // Exchange account UUID (for multi-account)
+		QuoteAsset:            exchangeCfg.QuoteAsset,
 		BinanceAPIKey:         "",
 		BinanceSecretKey:      "",
 		HyperliquidPrivateKey: "",
-		HyperliquidTestnet:    exchangeCfg.Testnet,
 		UseQwen:               aiModelCfg.Provider == "qwen",
 		DeepSeekKey:           "",
 		QwenKey:               "",
 		CustomAPIURL:          aiModelCfg.CustomAPIURL,
 		CustomModelName:       aiModelCfg.CustomModelName,
 		ScanInterval:         time.Duration(traderCfg.ScanIntervalMinutes) * time.Minute,
+		MaxPositionAgeMinutes: traderCfg.MaxPositionAgeMinutes,
+		MaxPositionAgeAction:  traderCfg.MaxPositionAgeAction,
 		InitialBalance:       traderCfg.InitialBalance,
 		IsCrossMargin:        traderCfg.IsCrossMargin,
 		ShowInCompetition:    traderCfg.ShowInCompetition,
 		StrategyConfig:       strategyConfig,
+		OnEvent:              tm.handleTraderEvent,
+		UserExposure:         tm.GetUserExposure,
+	}
+
+	// Load fallback exchange (optional, used while the primary exchange API is failing)
+	if traderCfg.FallbackExchangeID != "" && traderCfg.FallbackExchangeID != exchangeCfg.ID {
+		fallback, err := st.Exchange().GetByID(traderCfg.UserID, traderCfg.FallbackExchangeID)
+		if err != nil {
+			logger.Infof("⚠️ Fallback exchange %s for trader %s does not exist, failover disabled", traderCfg.FallbackExchangeID, traderCfg.Name)
+		} else if !fallback.Enabled {
+			logger.Infof("⚠️ Fallback exchange %s for trader %s is not enabled, failover disabled", traderCfg.FallbackExchangeID, traderCfg.Name)
+		} else {
+			traderConfig.FallbackExchange = fallback
+		}
+	}
+
+	// Load end-of-day flat schedule (optional)
+	if schedule, err := st.Trader().GetFlatSchedule(traderCfg.UserID, traderCfg.ID); err == nil {
+		traderConfig.FlatSchedule = schedule
+	}
+
+	// Load executed-decision webhook (optional)
+	if url, secret, err := st.Trader().GetWebhook(traderCfg.UserID, traderCfg.ID); err == nil && url != "" {
+		traderConfig.WebhookURL = url
+		traderConfig.WebhookSecret = secret
+	}
+
+	// Load decision outcome export sink (optional)
+	if url, secret, err := st.Trader().GetOutcomeExport(traderCfg.UserID, traderCfg.ID); err == nil && url != "" {
+		traderConfig.OutcomeExportURL = url
+		traderConfig.OutcomeExportSecret = secret
+	}
+
+	// Load cold-start performance seed (optional)
+	if seed, err := st.Trader().GetPerformanceSeed(traderCfg.UserID, traderCfg.ID); err == nil {
+		traderConfig.PerformanceSeed = seed
+	}
+
+	// Load confidence gate (optional)
+	if gate, err := st.Trader().GetConfidenceGate(traderCfg.UserID, traderCfg.ID); err == nil {
+		traderConfig.ConfidenceGate = gate
+	}
+
+	// Load liquidity filter (optional)
+	if filter, err := st.Trader().GetLiquidityFilter(traderCfg.UserID, traderCfg.ID); err == nil {
+		traderConfig.LiquidityFilter = filter
+	}
+
+	// Load risk guardrail (optional)
+	if guardrail, err := st.Trader().GetRiskGuardrail(traderCfg.UserID, traderCfg.ID); err == nil {
+		traderConfig.RiskGuardrail = guardrail
+	}
+
+	// Load feature flag overrides (optional)
+	if flags, err := st.Trader().GetFeatureFlags(traderCfg.UserID, traderCfg.ID); err == nil {
+		traderConfig.FeatureFlags = flags
+	}
+
+	// Load ensemble models (optional), unavailable models are left out of the vote
+	if ensemble, err := st.Trader().GetEnsemble(traderCfg.UserID, traderCfg.ID); err == nil && len(ensemble.ModelIDs) > 0 {
+		traderConfig.Ensemble = ensemble
+		for _, modelID := range ensemble.ModelIDs {
+			model, err := st.AIModel().Get(traderCfg.UserID, modelID)
+			if err != nil {
+				logger.Infof("⚠️ Ensemble model %s for trader %s does not exist, skipping", modelID, traderCfg.Name)
+				continue
+			}
+			if !model.Enabled {
+				logger.Infof("⚠️ Ensemble model %s for trader %s is not enabled, skipping", modelID, traderCfg.Name)
+				continue
+			}
+			traderConfig.EnsembleModels = append(traderConfig.EnsembleModels, model)
+		}
+	}
+
+	// Load the owner's quiet hours / digest batching (optional)
+	if settings, err := st.Notification().Get(traderCfg.UserID); err == nil {
+		traderConfig.Notifications = settings
 	}
 
 	// Set API keys based on exchange type
@@ -639,9 +834,17 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 		traderConfig.OKXAPIKey = exchangeCfg.APIKey
 		traderConfig.OKXSecretKey = exchangeCfg.SecretKey
 		traderConfig.OKXPassphrase = exchangeCfg.Passphrase
+	case "mexc":
+		traderConfig.MEXCAPIKey = exchangeCfg.APIKey
+		traderConfig.MEXCSecretKey = exchangeCfg.SecretKey
+	case "coinbase":
+		traderConfig.CoinbaseAPIKey = exchangeCfg.APIKey
+		traderConfig.CoinbaseSecretKey = exchangeCfg.SecretKey
+		traderConfig.CoinbasePassphrase = exchangeCfg.Passphrase
 	case "hyperliquid":
 		traderConfig.HyperliquidPrivateKey = exchangeCfg.APIKey
 		traderConfig.HyperliquidWalletAddr = exchangeCfg.HyperliquidWalletAddr
+		traderConfig.HyperliquidTestnet = exchangeCfg.Testnet
 	case "aster":
 		traderConfig.AsterUser = exchangeCfg.AsterUser
 		traderConfig.AsterSigner = exchangeCfg.AsterSigner
@@ -657,6 +860,9 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 		traderConfig.QwenKey = aiModelCfg.APIKey
 	} else if aiModelCfg.Provider == "deepseek" {
 		traderConfig.DeepSeekKey = aiModelCfg.APIKey
+	} else {
+		// Claude, Kimi, Gemini, Grok, OpenAI and custom providers read the key from CustomAPIKey
+		traderConfig.CustomAPIKey = aiModelCfg.APIKey
 	}
 
 	// Create trader instance
@@ -6,7 +6,10 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"nofx/endpoint"
 	"nofx/hook"
+	"nofx/ratelimit"
+	"sort"
 	"strconv"
 	"time"
 )
@@ -30,6 +33,10 @@ func NewAPIClient() *APIClient {
 		client = hookRes.GetResult()
 	}
 
+	// Share Binance request weight budget with trader clients
+	client = ratelimit.WrapClient("binance", client)
+	client = endpoint.WrapClient(client)
+
 	return &APIClient{
 		client: client,
 	}
@@ -158,3 +165,95 @@ func (c *APIClient) GetCurrentPrice(symbol string) (float64, error) {
 
 	return price, nil
 }
+
+// GetDepth gets order book snapshot (limit: 5/10/20/50/100/500/1000)
+func (c *APIClient) GetDepth(symbol string, limit int) (*OrderBook, error) {
+	url := fmt.Sprintf("%s/fapi/v1/depth", baseURL)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("symbol", symbol)
+	q.Add("limit", strconv.Itoa(limit))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("binance depth api returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var depth DepthResponse
+	if err := json.Unmarshal(body, &depth); err != nil {
+		return nil, err
+	}
+
+	book := &OrderBook{Symbol: symbol}
+	book.Bids = parseDepthLevels(depth.Bids)
+	book.Asks = parseDepthLevels(depth.Asks)
+	return book, nil
+}
+
+func parseDepthLevels(raw [][]string) []DepthLevel {
+	levels := make([]DepthLevel, 0, len(raw))
+	for _, r := range raw {
+		if len(r) < 2 {
+			continue
+		}
+		price, _ := strconv.ParseFloat(r[0], 64)
+		qty, _ := strconv.ParseFloat(r[1], 64)
+		levels = append(levels, DepthLevel{Price: price, Quantity: qty})
+	}
+	return levels
+}
+
+// GetFundingRateHistory gets the last limit settled funding rates, oldest first
+func (c *APIClient) GetFundingRateHistory(symbol string, limit int) ([]FundingRatePoint, error) {
+	url := fmt.Sprintf("%s/fapi/v1/fundingRate", baseURL)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("symbol", symbol)
+	q.Add("limit", strconv.Itoa(limit))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []struct {
+		FundingRate string `json:"fundingRate"`
+		FundingTime int64  `json:"fundingTime"`
+	}
+	if err := json.Unmarshal(body, &records); err != nil {
+		return nil, err
+	}
+
+	points := make([]FundingRatePoint, 0, len(records))
+	for _, r := range records {
+		rate, _ := strconv.ParseFloat(r.FundingRate, 64)
+		points = append(points, FundingRatePoint{Time: time.UnixMilli(r.FundingTime), Rate: rate})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	return points, nil
+}
@@ -0,0 +1,30 @@
+package market
+
+// ATR14For returns ATR14 of the given timeframe, falling back to the intraday series (0 if unavailable)
+func (d *Data) ATR14For(timeframe string) float64 {
+	if d == nil {
+		return 0
+	}
+	if tf, ok := d.TimeframeData[timeframe]; ok && tf != nil && tf.ATR14 > 0 {
+		return tf.ATR14
+	}
+	if d.IntradaySeries != nil {
+		return d.IntradaySeries.ATR14
+	}
+	return 0
+}
+
+// ATRStopLoss stop loss multiplier × ATR away from entry, side: "long"/"short" (0 if it can't be computed)
+func ATRStopLoss(side string, entry, atr, multiplier float64) float64 {
+	if entry <= 0 || atr <= 0 || multiplier <= 0 {
+		return 0
+	}
+	distance := atr * multiplier
+	if side == "short" {
+		return entry + distance
+	}
+	if distance >= entry {
+		return 0
+	}
+	return entry - distance
+}
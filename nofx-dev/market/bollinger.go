@@ -0,0 +1,60 @@
+package market
+
+import (
+	"fmt"
+	"math"
+)
+
+// Bollinger Bands parameters
+const (
+	bollingerPeriod = 20
+	bollingerStdDev = 2.0
+)
+
+// BollingerBands Bollinger Bands (20, 2σ) of the latest close
+type BollingerBands struct {
+	Upper     float64 `json:"upper"`
+	Middle    float64 `json:"middle"` // 20-period SMA
+	Lower     float64 `json:"lower"`
+	PercentB  float64 `json:"percent_b"`  // (close - lower) / (upper - lower): 0 at the lower band, 1 at the upper band
+	BandWidth float64 `json:"band_width"` // (upper - lower) / middle in %, low values mark a volatility squeeze
+}
+
+// calculateBollinger computes Bollinger Bands over the last period closes (population σ), nil without enough klines
+func calculateBollinger(klines []Kline, period int, k float64) *BollingerBands {
+	if period <= 0 || len(klines) < period {
+		return nil
+	}
+	window := klines[len(klines)-period:]
+	sum := 0.0
+	for _, kl := range window {
+		sum += kl.Close
+	}
+	mean := sum / float64(period)
+	variance := 0.0
+	for _, kl := range window {
+		variance += (kl.Close - mean) * (kl.Close - mean)
+	}
+	sd := math.Sqrt(variance / float64(period))
+
+	bb := &BollingerBands{
+		Upper:    mean + k*sd,
+		Middle:   mean,
+		Lower:    mean - k*sd,
+		PercentB: 0.5, // Flat series: price sits on the middle band
+	}
+	if width := bb.Upper - bb.Lower; width > 0 {
+		bb.PercentB = (window[len(window)-1].Close - bb.Lower) / width
+	}
+	if mean != 0 {
+		bb.BandWidth = (bb.Upper - bb.Lower) / mean * 100
+	}
+	return bb
+}
+
+// Summary one line for prompts, e.g. "upper 101.2, middle 100.0, lower 98.8, %B 0.83, width 2.40%"
+func (b *BollingerBands) Summary() string {
+	return fmt.Sprintf("upper %s, middle %s, lower %s, %%B %.2f, width %.2f%%",
+		formatPriceWithDynamicPrecision(b.Upper), formatPriceWithDynamicPrecision(b.Middle),
+		formatPriceWithDynamicPrecision(b.Lower), b.PercentB, b.BandWidth)
+}
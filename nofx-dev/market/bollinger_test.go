@@ -0,0 +1,47 @@
+package market
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// closes klines with the given closes
+func closes(values ...float64) []Kline {
+	klines := make([]Kline, len(values))
+	for i, v := range values {
+		klines[i] = Kline{Close: v}
+	}
+	return klines
+}
+
+// TestCalculateBollinger tests bands, %B and band width against a hand-computed window
+func TestCalculateBollinger(t *testing.T) {
+	// Alternating 98 / 102: mean 100, population σ 2
+	values := make([]float64, 0, 25)
+	for i := 0; i < 25; i++ {
+		values = append(values, 100+2*math.Pow(-1, float64(i)))
+	}
+	bb := calculateBollinger(closes(values...), 20, 2)
+	if bb == nil {
+		t.Fatal("expected bands with 25 klines")
+	}
+	if math.Abs(bb.Middle-100) > 1e-9 || math.Abs(bb.Upper-104) > 1e-9 || math.Abs(bb.Lower-96) > 1e-9 {
+		t.Errorf("unexpected bands: %+v", bb)
+	}
+	// Last close 102: (102 - 96) / 8
+	if math.Abs(bb.PercentB-0.75) > 1e-9 || math.Abs(bb.BandWidth-8) > 1e-9 {
+		t.Errorf("%%B = %v, width = %v", bb.PercentB, bb.BandWidth)
+	}
+	if s := bb.Summary(); !strings.Contains(s, "%B 0.75") || !strings.Contains(s, "width 8.00%") {
+		t.Errorf("unexpected summary: %s", s)
+	}
+
+	flat := calculateBollinger(closes(make([]float64, 20)...), 20, 2)
+	if flat == nil || flat.PercentB != 0.5 || flat.BandWidth != 0 {
+		t.Errorf("flat series should sit on the middle band: %+v", flat)
+	}
+	if calculateBollinger(closes(1, 2, 3), 20, 2) != nil {
+		t.Error("fewer klines than the period should not produce bands")
+	}
+}
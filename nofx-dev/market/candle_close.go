@@ -0,0 +1,76 @@
+package market
+
+import (
+	"log"
+	"strings"
+	"sync"
+)
+
+// candleCloseBuffer closed candles buffered per subscriber, further candles are dropped while it lags
+const candleCloseBuffer = 8
+
+// candleCloseBus subscribers of closed candles, keyed symbol@timeframe
+type candleCloseBus struct {
+	mu   sync.Mutex
+	subs map[string][]chan Kline
+}
+
+func (b *candleCloseBus) subscribe(key string) chan Kline {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs == nil {
+		b.subs = make(map[string][]chan Kline)
+	}
+	ch := make(chan Kline, candleCloseBuffer)
+	b.subs[key] = append(b.subs[key], ch)
+	return ch
+}
+
+// unsubscribe removes and closes ch, false if it isn't subscribed
+func (b *candleCloseBus) unsubscribe(ch <-chan Kline) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for key, subs := range b.subs {
+		for i, sub := range subs {
+			if sub != ch {
+				continue
+			}
+			close(sub)
+			b.subs[key] = append(subs[:i:i], subs[i+1:]...)
+			if len(b.subs[key]) == 0 {
+				delete(b.subs, key)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// publish delivers a closed candle to the key's subscribers without blocking the stream
+func (b *candleCloseBus) publish(key string, k Kline) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.subs[key] {
+		select {
+		case ch <- k:
+		default:
+			log.Printf("⚠️  Candle close subscriber of %s lagging, dropped candle %d", key, k.OpenTime)
+		}
+	}
+}
+
+// OnCandleClose returns a channel receiving every candle of symbol's timeframe as it closes (subscribing the kline
+// stream if needed), release it with OffCandleClose
+func (m *WSMonitor) OnCandleClose(symbol, timeframe string) <-chan Kline {
+	symbol = strings.ToUpper(symbol)
+	ch := m.candleClose.subscribe(symbol + "@" + timeframe)
+	if _, err := m.GetCurrentKlines(symbol, timeframe); err != nil {
+		log.Printf("⚠️  Failed to subscribe %s %s klines for candle close events: %v", symbol, timeframe, err)
+	}
+	return ch
+}
+
+// OffCandleClose stops delivery to a channel returned by OnCandleClose and closes it
+func (m *WSMonitor) OffCandleClose(ch <-chan Kline) {
+	m.candleClose.unsubscribe(ch)
+}
@@ -0,0 +1,44 @@
+package market
+
+import "testing"
+
+// TestOnCandleClose tests that only final kline updates reach subscribers of their symbol and timeframe
+func TestOnCandleClose(t *testing.T) {
+	m := &WSMonitor{}
+	m.getKlineDataMap("1h").Store("BTCUSDT", []Kline{{OpenTime: 0, CloseTime: 3599999}}) // Cached: no REST fetch
+	ch := m.OnCandleClose("btcusdt", "1h")
+	other := m.candleClose.subscribe("ETHUSDT@1h")
+
+	update := func(open int64, final bool) {
+		var ws KlineWSData
+		ws.Kline.StartTime, ws.Kline.CloseTime = open, open+3599999
+		ws.Kline.ClosePrice = "100.5"
+		ws.Kline.IsFinal = final
+		m.processKlineUpdate("BTCUSDT", ws, "1h")
+	}
+	update(3600000, false)
+	select {
+	case k := <-ch:
+		t.Fatalf("forming candle delivered: %+v", k)
+	default:
+	}
+
+	update(3600000, true)
+	select {
+	case k := <-ch:
+		if k.OpenTime != 3600000 || k.Close != 100.5 {
+			t.Errorf("unexpected closed candle: %+v", k)
+		}
+	default:
+		t.Fatal("closed candle not delivered")
+	}
+	if len(other) != 0 {
+		t.Error("candle delivered to another symbol's subscriber")
+	}
+
+	m.OffCandleClose(ch)
+	if _, ok := <-ch; ok {
+		t.Error("channel should be closed after OffCandleClose")
+	}
+	update(7200000, true) // No subscriber left: must not block or panic
+}
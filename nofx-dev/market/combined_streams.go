@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"nofx/endpoint"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -11,45 +13,95 @@ import (
 	"github.com/gorilla/websocket"
 )
 
+const (
+	// maxStreamsPerConnection Binance futures limit of streams on one combined-stream connection
+	maxStreamsPerConnection = 200
+	// streamRateWindow period over which per-stream message rates are measured
+	streamRateWindow = time.Minute
+	// streamLagSampleInterval minimum time between event time reads of a stream, so lag tracking doesn't decode
+	// every payload a second time
+	streamLagSampleInterval = time.Second
+)
+
+// CombinedStreamsClient combined-stream subscriptions sharded over as many connections as the per-connection
+// stream limit requires
 type CombinedStreamsClient struct {
-	conn        *websocket.Conn
 	mu          sync.RWMutex
+	conns       []*streamConn
 	subscribers map[string]chan []byte
 	reconnect   bool
 	done        chan struct{}
 	batchSize   int // Number of streams per batch subscription
+
+	statsMu sync.Mutex
+	stats   map[string]*streamStats // Stream -> message counters, only for subscribed streams
+}
+
+// streamConn one combined-stream connection and the streams subscribed on it (guarded by the client's mu)
+type streamConn struct {
+	id          int
+	conn        *websocket.Conn // nil while reconnecting
+	streams     map[string]bool
+	reconnects  int
+	connectedAt time.Time
+}
+
+// streamStats message counters of one stream
+type streamStats struct {
+	messages      uint64
+	lastMessageAt time.Time
+	lag           time.Duration // Receive time minus exchange event time of the last sampled message
+	reconnects    int           // Reconnects of its connection while subscribed
+	lagSampledAt  time.Time
+	windowStart   time.Time
+	windowCount   int
+	rate          float64 // Messages per second over the last full streamRateWindow
 }
 
 func NewCombinedStreamsClient(batchSize int) *CombinedStreamsClient {
 	return &CombinedStreamsClient{
 		subscribers: make(map[string]chan []byte),
+		stats:       make(map[string]*streamStats),
 		reconnect:   true,
 		done:        make(chan struct{}),
 		batchSize:   batchSize,
 	}
 }
 
+// Connect opens the first connection, more are opened as subscriptions exceed maxStreamsPerConnection
 func (c *CombinedStreamsClient) Connect() error {
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-	}
-
-	// Combined streams use a different endpoint
-	conn, _, err := dialer.Dial("wss://fstream.binance.com/stream", nil)
+	conn, err := c.dial()
 	if err != nil {
-		return fmt.Errorf("Combined stream WebSocket connection failed: %v", err)
+		return err
 	}
 
 	c.mu.Lock()
-	c.conn = conn
+	sc := &streamConn{id: len(c.conns), conn: conn, streams: make(map[string]bool), connectedAt: time.Now()}
+	c.conns = append(c.conns, sc)
 	c.mu.Unlock()
 
-	log.Println("Combined stream WebSocket connected successfully")
-	go c.readMessages()
+	log.Printf("Combined stream WebSocket #%d connected successfully", sc.id)
+	go c.readMessages(sc, conn)
 
 	return nil
 }
 
+// dial opens a combined-stream connection
+func (c *CombinedStreamsClient) dial() (*websocket.Conn, error) {
+	dialer := websocket.Dialer{
+		HandshakeTimeout: 10 * time.Second,
+	}
+
+	// Combined streams use a different endpoint (rewritten to the preferred mirror if one is configured)
+	streamURL := endpoint.Resolve("wss://fstream.binance.com/stream")
+	conn, _, err := dialer.Dial(streamURL, nil)
+	endpoint.Observe(streamURL, err)
+	if err != nil {
+		return nil, fmt.Errorf("Combined stream WebSocket connection failed: %v", err)
+	}
+	return conn, nil
+}
+
 // BatchSubscribeKlines subscribes to K-lines in batches
 func (c *CombinedStreamsClient) BatchSubscribeKlines(symbols []string, interval string) error {
 	// Split symbols into batches
@@ -91,49 +143,111 @@ func (c *CombinedStreamsClient) splitIntoBatches(symbols []string, batchSize int
 	return batches
 }
 
-// subscribeStreams subscribes to multiple streams
+// subscribeStreams subscribes to multiple streams, opening another connection when all are full
 func (c *CombinedStreamsClient) subscribeStreams(streams []string) error {
-	subscribeMsg := map[string]interface{}{
-		"method": "SUBSCRIBE",
-		"params": streams,
-		"id":     time.Now().UnixNano(),
+	for {
+		c.mu.Lock()
+		if len(c.conns) == 0 {
+			c.mu.Unlock()
+			return fmt.Errorf("WebSocket not connected")
+		}
+		assigned, rest := c.assignStreams(streams)
+		err := c.writeAssigned("SUBSCRIBE", assigned)
+		c.mu.Unlock()
+		if err != nil || len(rest) == 0 {
+			return err
+		}
+
+		log.Printf("All combined stream connections hold %d streams, opening another for %d more", maxStreamsPerConnection, len(rest))
+		if err := c.Connect(); err != nil {
+			return err
+		}
+		streams = rest
 	}
+}
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// assignStreams places streams on connections with room left (already placed ones stay where they are),
+// returns them per connection and the streams no connection had room for. Caller holds mu
+func (c *CombinedStreamsClient) assignStreams(streams []string) (map[*streamConn][]string, []string) {
+	assigned := make(map[*streamConn][]string)
+	var rest []string
+	for _, stream := range streams {
+		sc := c.connOf(stream)
+		if sc == nil {
+			for _, candidate := range c.conns {
+				if len(candidate.streams) < maxStreamsPerConnection {
+					sc = candidate
+					break
+				}
+			}
+		}
+		if sc == nil {
+			rest = append(rest, stream)
+			continue
+		}
+		sc.streams[stream] = true
+		assigned[sc] = append(assigned[sc], stream)
 
-	if c.conn == nil {
-		return fmt.Errorf("WebSocket not connected")
+		c.statsMu.Lock()
+		if c.stats[stream] == nil {
+			c.stats[stream] = &streamStats{}
+		}
+		c.statsMu.Unlock()
+	}
+	return assigned, rest
+}
+
+// connOf the connection a stream is subscribed on, nil if none. Caller holds mu
+func (c *CombinedStreamsClient) connOf(stream string) *streamConn {
+	for _, sc := range c.conns {
+		if sc.streams[stream] {
+			return sc
+		}
 	}
+	return nil
+}
 
-	log.Printf("Subscribing to streams: %v", streams)
-	return c.conn.WriteJSON(subscribeMsg)
+// writeAssigned sends a SUBSCRIBE / UNSUBSCRIBE per connection. Connections that are reconnecting are skipped,
+// they resubscribe their streams once back. Caller holds mu
+func (c *CombinedStreamsClient) writeAssigned(method string, assigned map[*streamConn][]string) error {
+	for sc, streams := range assigned {
+		if sc.conn == nil {
+			continue
+		}
+		log.Printf("%s streams on connection #%d: %v", strings.ToLower(method), sc.id, streams)
+		if err := sc.conn.WriteJSON(map[string]interface{}{
+			"method": method,
+			"params": streams,
+			"id":     time.Now().UnixNano(),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (c *CombinedStreamsClient) readMessages() {
+func (c *CombinedStreamsClient) readMessages(sc *streamConn, conn *websocket.Conn) {
 	for {
 		select {
 		case <-c.done:
 			return
 		default:
-			c.mu.RLock()
-			conn := c.conn
-			c.mu.RUnlock()
-
-			if conn == nil {
-				time.Sleep(1 * time.Second)
-				continue
-			}
+		}
 
-			_, message, err := conn.ReadMessage()
-			if err != nil {
-				log.Printf("Failed to read combined stream message: %v", err)
-				c.handleReconnect()
-				return
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			log.Printf("Failed to read combined stream #%d message: %v", sc.id, err)
+			c.mu.Lock()
+			if sc.conn == conn {
+				sc.conn = nil
 			}
-
-			c.handleCombinedMessage(message)
+			c.mu.Unlock()
+			conn.Close()
+			c.handleReconnect(sc)
+			return
 		}
+
+		c.handleCombinedMessage(message)
 	}
 }
 
@@ -147,12 +261,12 @@ func (c *CombinedStreamsClient) handleCombinedMessage(message []byte) {
 		log.Printf("Failed to parse combined message: %v", err)
 		return
 	}
+	c.recordMessage(combinedMsg.Stream, combinedMsg.Data, time.Now())
 
+	// Send under the read lock so RemoveSubscriber can't close the channel mid-send
 	c.mu.RLock()
-	ch, exists := c.subscribers[combinedMsg.Stream]
-	c.mu.RUnlock()
-
-	if exists {
+	defer c.mu.RUnlock()
+	if ch, exists := c.subscribers[combinedMsg.Stream]; exists {
 		select {
 		case ch <- combinedMsg.Data:
 		default:
@@ -161,6 +275,57 @@ func (c *CombinedStreamsClient) handleCombinedMessage(message []byte) {
 	}
 }
 
+// recordMessage updates the stream's counters, rate and lag behind the exchange event time (sampled)
+func (c *CombinedStreamsClient) recordMessage(stream string, data []byte, now time.Time) {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	s := c.stats[stream]
+	if s == nil {
+		return
+	}
+
+	s.messages++
+	s.lastMessageAt = now
+	if now.Sub(s.lagSampledAt) >= streamLagSampleInterval {
+		s.lagSampledAt = now
+		if eventTime := parseEventTime(data); eventTime > 0 {
+			s.lag = now.Sub(time.UnixMilli(eventTime))
+		}
+	}
+	if s.windowStart.IsZero() {
+		s.windowStart = now
+	}
+	s.windowCount++
+	if elapsed := now.Sub(s.windowStart); elapsed >= streamRateWindow {
+		s.rate = float64(s.windowCount) / elapsed.Seconds()
+		s.windowStart, s.windowCount = now, 0
+	}
+}
+
+// parseEventTime the exchange event time ("E") of a stream payload, the latest one for all-market arrays
+func parseEventTime(data []byte) int64 {
+	type event struct {
+		EventType string `json:"e"` // Keeps "e" from matching E case-insensitively
+		EventTime int64  `json:"E"`
+	}
+	if len(data) > 0 && data[0] == '[' {
+		var events []event
+		if json.Unmarshal(data, &events) != nil {
+			return 0
+		}
+		var latest int64
+		for _, e := range events {
+			latest = max(latest, e.EventTime)
+		}
+		return latest
+	}
+	var e event
+	if json.Unmarshal(data, &e) != nil {
+		return 0
+	}
+	return e.EventTime
+}
+
 func (c *CombinedStreamsClient) AddSubscriber(stream string, bufferSize int) <-chan []byte {
 	ch := make(chan []byte, bufferSize)
 	c.mu.Lock()
@@ -169,18 +334,114 @@ func (c *CombinedStreamsClient) AddSubscriber(stream string, bufferSize int) <-c
 	return ch
 }
 
-func (c *CombinedStreamsClient) handleReconnect() {
+// HasSubscriber whether a listener is registered for the stream
+func (c *CombinedStreamsClient) HasSubscriber(stream string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.subscribers[stream]
+	return ok
+}
+
+// RemoveSubscriber closes and removes the stream's listener
+func (c *CombinedStreamsClient) RemoveSubscriber(stream string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ch, ok := c.subscribers[stream]; ok {
+		close(ch)
+		delete(c.subscribers, stream)
+	}
+}
+
+// unsubscribeStreams unsubscribes from multiple streams on the connections holding them
+func (c *CombinedStreamsClient) unsubscribeStreams(streams []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.conns) == 0 {
+		return fmt.Errorf("WebSocket not connected")
+	}
+
+	assigned := make(map[*streamConn][]string)
+	for _, stream := range streams {
+		if sc := c.connOf(stream); sc != nil {
+			delete(sc.streams, stream)
+			assigned[sc] = append(assigned[sc], stream)
+		}
+		c.statsMu.Lock()
+		delete(c.stats, stream)
+		c.statsMu.Unlock()
+	}
+	return c.writeAssigned("UNSUBSCRIBE", assigned)
+}
+
+// SimulateDisconnect forcibly closes the current connections to exercise reconnect logic (chaos testing)
+func (c *CombinedStreamsClient) SimulateDisconnect() {
+	c.mu.RLock()
+	var conns []*websocket.Conn
+	for _, sc := range c.conns {
+		if sc.conn != nil {
+			conns = append(conns, sc.conn)
+		}
+	}
+	c.mu.RUnlock()
+
+	if len(conns) > 0 {
+		log.Printf("⚡ Simulating combined stream disconnect of %d connection(s) (fault injection)", len(conns))
+	}
+	for _, conn := range conns {
+		conn.Close()
+	}
+}
+
+// SubscriberCount returns the number of registered stream subscribers
+func (c *CombinedStreamsClient) SubscriberCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.subscribers)
+}
+
+// handleReconnect redials a dropped connection and resubscribes the streams it held
+func (c *CombinedStreamsClient) handleReconnect(sc *streamConn) {
 	if !c.reconnect {
 		return
 	}
 
-	log.Println("Combined stream attempting to reconnect...")
+	log.Printf("Combined stream #%d attempting to reconnect...", sc.id)
 	time.Sleep(3 * time.Second)
 
-	if err := c.Connect(); err != nil {
-		log.Printf("Combined stream reconnection failed: %v", err)
-		go c.handleReconnect()
+	conn, err := c.dial()
+	if err != nil {
+		log.Printf("Combined stream #%d reconnection failed: %v", sc.id, err)
+		go c.handleReconnect(sc)
+		return
+	}
+
+	c.mu.Lock()
+	sc.conn = conn
+	sc.reconnects++
+	sc.connectedAt = time.Now()
+	streams := make([]string, 0, len(sc.streams))
+	for stream := range sc.streams {
+		streams = append(streams, stream)
+	}
+	c.statsMu.Lock()
+	for _, stream := range streams {
+		if s := c.stats[stream]; s != nil {
+			s.reconnects++
+		}
+	}
+	c.statsMu.Unlock()
+	var subErr error
+	if len(streams) > 0 {
+		subErr = c.writeAssigned("SUBSCRIBE", map[*streamConn][]string{sc: streams})
+	}
+	c.mu.Unlock()
+
+	if subErr != nil {
+		log.Printf("Combined stream #%d failed to resubscribe %d stream(s): %v", sc.id, len(streams), subErr)
 	}
+	log.Printf("Combined stream #%d reconnected, resubscribed %d stream(s)", sc.id, len(streams))
+	go c.readMessages(sc, conn)
 }
 
 func (c *CombinedStreamsClient) Close() {
@@ -190,9 +451,11 @@ func (c *CombinedStreamsClient) Close() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.conn != nil {
-		c.conn.Close()
-		c.conn = nil
+	for _, sc := range c.conns {
+		if sc.conn != nil {
+			sc.conn.Close()
+			sc.conn = nil
+		}
 	}
 
 	for stream, ch := range c.subscribers {
@@ -200,3 +463,82 @@ func (c *CombinedStreamsClient) Close() {
 		delete(c.subscribers, stream)
 	}
 }
+
+// StreamMetrics message statistics of one subscribed stream
+type StreamMetrics struct {
+	Stream         string    `json:"stream"`
+	Connection     int       `json:"connection"` // ID of the connection it is subscribed on
+	Messages       uint64    `json:"messages"`
+	MessagesPerSec float64   `json:"messages_per_sec"`
+	LastMessageAt  time.Time `json:"last_message_at,omitempty"`
+	LagMs          int64     `json:"lag_ms"` // Receive time minus exchange event time of the last message
+	Reconnects     int       `json:"reconnects"`
+}
+
+// ConnectionMetrics state of one combined-stream connection
+type ConnectionMetrics struct {
+	ID          int       `json:"id"`
+	Connected   bool      `json:"connected"`
+	Streams     int       `json:"streams"`
+	Reconnects  int       `json:"reconnects"`
+	ConnectedAt time.Time `json:"connected_at,omitempty"`
+}
+
+// CombinedStreamsMetrics connection health and per-stream message statistics
+type CombinedStreamsMetrics struct {
+	MaxStreamsPerConnection int                 `json:"max_streams_per_connection"`
+	Connections             []ConnectionMetrics `json:"connections"`
+	Streams                 []StreamMetrics     `json:"streams"`
+	Messages                uint64              `json:"messages"`
+	MessagesPerSec          float64             `json:"messages_per_sec"`
+	MaxLagMs                int64               `json:"max_lag_ms"`
+}
+
+// Metrics returns connection health and per-stream message rates and lag, streams sorted by name
+func (c *CombinedStreamsClient) Metrics() CombinedStreamsMetrics {
+	metrics := CombinedStreamsMetrics{MaxStreamsPerConnection: maxStreamsPerConnection}
+	now := time.Now()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+
+	for _, sc := range c.conns {
+		metrics.Connections = append(metrics.Connections, ConnectionMetrics{
+			ID:          sc.id,
+			Connected:   sc.conn != nil,
+			Streams:     len(sc.streams),
+			Reconnects:  sc.reconnects,
+			ConnectedAt: sc.connectedAt,
+		})
+		for stream := range sc.streams {
+			sm := StreamMetrics{Stream: stream, Connection: sc.id}
+			if s := c.stats[stream]; s != nil {
+				sm.Messages = s.messages
+				sm.MessagesPerSec = s.messageRate(now)
+				sm.LastMessageAt = s.lastMessageAt
+				sm.LagMs = s.lag.Milliseconds()
+				sm.Reconnects = s.reconnects
+			}
+			metrics.Messages += sm.Messages
+			metrics.MessagesPerSec += sm.MessagesPerSec
+			metrics.MaxLagMs = max(metrics.MaxLagMs, sm.LagMs)
+			metrics.Streams = append(metrics.Streams, sm)
+		}
+	}
+	sort.Slice(metrics.Streams, func(i, j int) bool { return metrics.Streams[i].Stream < metrics.Streams[j].Stream })
+	return metrics
+}
+
+// messageRate messages per second: the last full window, the current one (at least a second) before that,
+// 0 once the stream went quiet
+func (s *streamStats) messageRate(now time.Time) float64 {
+	if s.lastMessageAt.IsZero() || now.Sub(s.lastMessageAt) > streamRateWindow {
+		return 0
+	}
+	if s.rate > 0 {
+		return s.rate
+	}
+	return float64(s.windowCount) / max(now.Sub(s.windowStart).Seconds(), 1)
+}
@@ -0,0 +1,84 @@
+package market
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestAssignStreamsShards tests that streams beyond the per-connection limit are left for another connection
+func TestAssignStreamsShards(t *testing.T) {
+	c := NewCombinedStreamsClient(50)
+	c.conns = []*streamConn{{id: 0, streams: make(map[string]bool)}}
+
+	var streams []string
+	for i := 0; i < maxStreamsPerConnection+30; i++ {
+		streams = append(streams, fmt.Sprintf("sym%dusdt@kline_3m", i))
+	}
+	assigned, rest := c.assignStreams(streams)
+	if len(assigned[c.conns[0]]) != maxStreamsPerConnection || len(rest) != 30 {
+		t.Fatalf("expected %d assigned and 30 left, got %d and %d", maxStreamsPerConnection, len(assigned[c.conns[0]]), len(rest))
+	}
+
+	c.conns = append(c.conns, &streamConn{id: 1, streams: make(map[string]bool)})
+	assigned, rest = c.assignStreams(append(rest, streams[0]))
+	if len(rest) != 0 || len(assigned[c.conns[1]]) != 30 || len(assigned[c.conns[0]]) != 1 {
+		t.Fatalf("expected the rest on connection 1 and the known stream kept on 0, got %v / %v", assigned, rest)
+	}
+
+	if err := c.writeAssigned("SUBSCRIBE", assigned); err != nil {
+		t.Fatalf("reconnecting connections should be skipped: %v", err)
+	}
+}
+
+// TestStreamMetrics tests message counts, lag and rates per stream
+func TestStreamMetrics(t *testing.T) {
+	c := NewCombinedStreamsClient(50)
+	c.conns = []*streamConn{{id: 0, streams: make(map[string]bool), reconnects: 2}}
+	c.assignStreams([]string{"btcusdt@kline_3m", "!ticker@arr"})
+
+	now := time.Now()
+	eventTime := strconv.FormatInt(now.Add(-250*time.Millisecond).UnixMilli(), 10)
+	for i := 0; i < 3; i++ {
+		c.handleCombinedMessage([]byte(`{"stream":"btcusdt@kline_3m","data":{"e":"kline","E":` + eventTime + `}}`))
+	}
+	c.handleCombinedMessage([]byte(`{"stream":"!ticker@arr","data":[{"E":1},{"E":` + eventTime + `}]}`))
+	c.handleCombinedMessage([]byte(`{"stream":"ethusdt@kline_3m","data":{"E":1}}`)) // Not subscribed
+
+	metrics := c.Metrics()
+	if len(metrics.Connections) != 1 || metrics.Connections[0].Streams != 2 || metrics.Connections[0].Reconnects != 2 {
+		t.Fatalf("unexpected connections: %+v", metrics.Connections)
+	}
+	if len(metrics.Streams) != 2 || metrics.Streams[0].Stream != "!ticker@arr" || metrics.Messages != 4 {
+		t.Fatalf("unexpected streams: %+v", metrics)
+	}
+	btc := metrics.Streams[1]
+	if btc.Messages != 3 || btc.LagMs < 250 || btc.LagMs > 5000 || btc.MessagesPerSec <= 0 {
+		t.Errorf("unexpected btc metrics: %+v", btc)
+	}
+	if metrics.Streams[0].LagMs < 250 || metrics.Streams[0].LagMs > 5000 {
+		t.Errorf("array payloads should use the latest event time: %+v", metrics.Streams[0])
+	}
+
+	// Lag is sampled: a payload right after the last sample isn't decoded
+	lag := c.stats["btcusdt@kline_3m"].lag
+	c.handleCombinedMessage([]byte(`{"stream":"btcusdt@kline_3m","data":{"e":"kline","E":1}}`))
+	if got := c.stats["btcusdt@kline_3m"].lag; got != lag {
+		t.Errorf("expected lag kept between samples, got %v (was %v)", got, lag)
+	}
+
+	// A full window turns into the reported rate
+	s := &streamStats{}
+	c.stats["x"] = s
+	start := time.Now().Add(-2 * streamRateWindow)
+	for i := 0; i <= 120; i++ {
+		c.recordMessage("x", nil, start.Add(time.Duration(i)*time.Second))
+	}
+	if s.rate < 0.99 || s.rate > 1.01 {
+		t.Errorf("expected ~1 msg/s, got %.3f", s.rate)
+	}
+	if rate := s.messageRate(time.Now().Add(2 * streamRateWindow)); rate != 0 {
+		t.Errorf("a quiet stream should report 0 msg/s, got %.3f", rate)
+	}
+}
@@ -1,29 +1,14 @@
 package market
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
 	"math"
 	"nofx/logger"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 )
 
-// FundingRateCache is the funding rate cache structure
-// Binance Funding Rate only updates every 8 hours, using 1-hour cache can significantly reduce API calls
-type FundingRateCache struct {
-	Rate      float64
-	UpdatedAt time.Time
-}
-
-var (
-	fundingRateMap sync.Map // map[string]*FundingRateCache
-	frCacheTTL     = 1 * time.Hour
-)
-
 // Get retrieves market data for the specified token
 func Get(symbol string) (*Data, error) {
 	var klines3m, klines4h []Kline
@@ -82,14 +67,14 @@ func Get(symbol string) (*Data, error) {
 	}
 
 	// Get OI data
-	oiData, err := getOpenInterestData(symbol)
+	oiData, err := getOpenInterestData(symbol, currentPrice)
 	if err != nil {
 		// OI failure doesn't affect overall result, use default values
 		oiData = &OIData{Latest: 0, Average: 0}
 	}
 
-	// Get Funding Rate
-	fundingRate, _ := getFundingRate(symbol)
+	// Get Funding Rate (predicted next + recent settlements)
+	funding, _ := getFundingHistory(symbol)
 
 	// Calculate intraday series data
 	intradayData := calculateIntradaySeries(klines3m)
@@ -97,7 +82,7 @@ func Get(symbol string) (*Data, error) {
 	// Calculate longer-term data
 	longerTermData := calculateLongerTermData(klines4h)
 
-	return &Data{
+	data := &Data{
 		Symbol:            symbol,
 		CurrentPrice:      currentPrice,
 		PriceChange1h:     priceChange1h,
@@ -106,10 +91,21 @@ func Get(symbol string) (*Data, error) {
 		CurrentMACD:       currentMACD,
 		CurrentRSI7:       currentRSI7,
 		OpenInterest:      oiData,
-		FundingRate:       fundingRate,
+		FundingRate:       funding.PredictedRate(),
+		FundingHistory:    funding,
 		IntradaySeries:    intradayData,
 		LongerTermContext: longerTermData,
-	}, nil
+		Liquidations:      WSMonitorCli.Liquidations(symbol),
+		Bollinger:         calculateBollinger(klines3m, bollingerPeriod, bollingerStdDev),
+		VWAP:              vwapStateFor(symbol, "3m").update(klines3m),
+		VolumeProfile:     calculateVolumeProfile(klines3m),
+	}
+	setMomentumSignals(data, klines3m)
+	setTicker24h(data)
+	setTickSize(data)
+	setMarkPrice(data)
+	WSMonitorCli.setFreshness(data, "3m")
+	return data, nil
 }
 
 // GetWithTimeframes retrieves market data for specified multiple timeframes
@@ -189,26 +185,37 @@ func GetWithTimeframes(symbol string, timeframes []string, primaryTimeframe stri
 	priceChange4h := calculatePriceChangeByBars(primaryKlines, primaryTimeframe, 240) // 4 hours
 
 	// Get OI data
-	oiData, err := getOpenInterestData(symbol)
+	oiData, err := getOpenInterestData(symbol, currentPrice)
 	if err != nil {
 		oiData = &OIData{Latest: 0, Average: 0}
 	}
 
-	// Get Funding Rate
-	fundingRate, _ := getFundingRate(symbol)
-
-	return &Data{
-		Symbol:        symbol,
-		CurrentPrice:  currentPrice,
-		PriceChange1h: priceChange1h,
-		PriceChange4h: priceChange4h,
-		CurrentEMA20:  currentEMA20,
-		CurrentMACD:   currentMACD,
-		CurrentRSI7:   currentRSI7,
-		OpenInterest:  oiData,
-		FundingRate:   fundingRate,
-		TimeframeData: timeframeData,
-	}, nil
+	// Get Funding Rate (predicted next + recent settlements)
+	funding, _ := getFundingHistory(symbol)
+
+	data := &Data{
+		Symbol:         symbol,
+		CurrentPrice:   currentPrice,
+		PriceChange1h:  priceChange1h,
+		PriceChange4h:  priceChange4h,
+		CurrentEMA20:   currentEMA20,
+		CurrentMACD:    currentMACD,
+		CurrentRSI7:    currentRSI7,
+		OpenInterest:   oiData,
+		FundingRate:    funding.PredictedRate(),
+		FundingHistory: funding,
+		TimeframeData:  timeframeData,
+		Liquidations:   WSMonitorCli.Liquidations(symbol),
+		Bollinger:      calculateBollinger(primaryKlines, bollingerPeriod, bollingerStdDev),
+		VWAP:           vwapStateFor(symbol, primaryTimeframe).update(primaryKlines),
+		VolumeProfile:  calculateVolumeProfile(primaryKlines),
+	}
+	setMomentumSignals(data, primaryKlines)
+	setTicker24h(data)
+	setTickSize(data)
+	setMarkPrice(data)
+	WSMonitorCli.setFreshness(data, primaryTimeframe)
+	return data, nil
 }
 
 // calculateTimeframeSeries calculates series data for a single timeframe
@@ -562,101 +569,44 @@ func calculateLongerTermData(klines []Kline) *LongerTermData {
 	return data
 }
 
-// getOpenInterestData retrieves OI data
-func getOpenInterestData(symbol string) (*OIData, error) {
-	url := fmt.Sprintf("https://fapi.binance.com/fapi/v1/openInterest?symbol=%s", symbol)
+// Format formats and outputs market data
+func Format(data *Data) string {
+	var sb strings.Builder
 
-	apiClient := NewAPIClient()
-	resp, err := apiClient.client.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	// Format price on the symbol's tick grid (dynamic precision until its tick size is known)
+	priceStr := data.FormatPrice(data.CurrentPrice)
+	sb.WriteString(fmt.Sprintf("current_price = %s, current_ema20 = %.3f, current_macd = %.3f, current_rsi (7 period) = %.3f\n\n",
+		priceStr, data.CurrentEMA20, data.CurrentMACD, data.CurrentRSI7))
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	if data.Ticker24h != nil {
+		sb.WriteString(fmt.Sprintf("24h stats: %s\n\n", data.Ticker24h.Summary()))
 	}
 
-	var result struct {
-		OpenInterest string `json:"openInterest"`
-		Symbol       string `json:"symbol"`
-		Time         int64  `json:"time"`
+	if data.MarkPrice != nil {
+		sb.WriteString(fmt.Sprintf("Mark / index: %s\n\n", data.MarkPrice.Summary()))
 	}
 
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, err
+	if data.Bollinger != nil {
+		sb.WriteString(fmt.Sprintf("Bollinger Bands (20, 2σ): %s\n\n", data.Bollinger.Summary()))
 	}
 
-	oi, _ := strconv.ParseFloat(result.OpenInterest, 64)
-
-	return &OIData{
-		Latest:  oi,
-		Average: oi * 0.999, // Approximate average
-	}, nil
-}
-
-// getFundingRate retrieves funding rate (optimized: uses 1-hour cache)
-func getFundingRate(symbol string) (float64, error) {
-	// Check cache (1-hour validity)
-	// Funding Rate only updates every 8 hours, 1-hour cache is very reasonable
-	if cached, ok := fundingRateMap.Load(symbol); ok {
-		cache := cached.(*FundingRateCache)
-		if time.Since(cache.UpdatedAt) < frCacheTTL {
-			// Cache hit, return directly
-			return cache.Rate, nil
-		}
+	if data.VWAP != nil {
+		sb.WriteString(fmt.Sprintf("VWAP: %s\n\n", data.VWAP.Summary()))
 	}
 
-	// Cache expired or doesn't exist, call API
-	url := fmt.Sprintf("https://fapi.binance.com/fapi/v1/premiumIndex?symbol=%s", symbol)
-
-	apiClient := NewAPIClient()
-	resp, err := apiClient.client.Get(url)
-	if err != nil {
-		return 0, err
+	if data.VolumeProfile != nil {
+		sb.WriteString(fmt.Sprintf("Volume profile: %s\n\n", data.VolumeProfile.Summary()))
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, err
-	}
-
-	var result struct {
-		Symbol          string `json:"symbol"`
-		MarkPrice       string `json:"markPrice"`
-		IndexPrice      string `json:"indexPrice"`
-		LastFundingRate string `json:"lastFundingRate"`
-		NextFundingTime int64  `json:"nextFundingTime"`
-		InterestRate    string `json:"interestRate"`
-		Time            int64  `json:"time"`
+	if data.StochRSI != nil {
+		sb.WriteString(fmt.Sprintf("Stochastic RSI (14, 14, 3, 3): %s\n\n", data.StochRSI.Summary()))
 	}
 
-	if err := json.Unmarshal(body, &result); err != nil {
-		return 0, err
+	if data.RSIBullishDivergence || data.RSIBearishDivergence {
+		sb.WriteString(fmt.Sprintf("RSI divergence (last %d candles): bullish %v, bearish %v\n\n",
+			divergenceLookback, data.RSIBullishDivergence, data.RSIBearishDivergence))
 	}
 
-	rate, _ := strconv.ParseFloat(result.LastFundingRate, 64)
-
-	// Update cache
-	fundingRateMap.Store(symbol, &FundingRateCache{
-		Rate:      rate,
-		UpdatedAt: time.Now(),
-	})
-
-	return rate, nil
-}
-
-// Format formats and outputs market data
-func Format(data *Data) string {
-	var sb strings.Builder
-
-	// Format price with dynamic precision
-	priceStr := formatPriceWithDynamicPrecision(data.CurrentPrice)
-	sb.WriteString(fmt.Sprintf("current_price = %s, current_ema20 = %.3f, current_macd = %.3f, current_rsi (7 period) = %.3f\n\n",
-		priceStr, data.CurrentEMA20, data.CurrentMACD, data.CurrentRSI7))
-
 	sb.WriteString(fmt.Sprintf("In addition, here is the latest %s open interest and funding rate for perps:\n\n",
 		data.Symbol))
 
@@ -666,15 +616,32 @@ func Format(data *Data) string {
 		oiAverageStr := formatPriceWithDynamicPrecision(data.OpenInterest.Average)
 		sb.WriteString(fmt.Sprintf("Open Interest: Latest: %s Average: %s\n\n",
 			oiLatestStr, oiAverageStr))
+		sb.WriteString(fmt.Sprintf("Open Interest change: %s\n\n", data.OpenInterest.Summary()))
 	}
 
 	sb.WriteString(fmt.Sprintf("Funding Rate: %.2e\n\n", data.FundingRate))
 
+	if data.FundingHistory != nil && len(data.FundingHistory.Settled) > 0 {
+		sb.WriteString(fmt.Sprintf("Funding Rate history: %s\n\n", data.FundingHistory.Summary()))
+	}
+
+	if data.OrderBook != nil {
+		sb.WriteString(fmt.Sprintf("Order Book: %s\n\n", data.OrderBook.Summary()))
+	}
+
+	if data.Liquidations != nil {
+		sb.WriteString(fmt.Sprintf("Liquidations (last %dm): %s\n\n", data.Liquidations.WindowMinutes, data.Liquidations.Summary()))
+	}
+
+	if data.WeeklyContext != nil {
+		sb.WriteString(fmt.Sprintf("Weekly context (1w): %s\n\n", data.WeeklyContext.Summary()))
+	}
+
 	if data.IntradaySeries != nil {
 		sb.WriteString("Intraday series (3‑minute intervals, oldest → latest):\n\n")
 
 		if len(data.IntradaySeries.MidPrices) > 0 {
-			sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", formatFloatSlice(data.IntradaySeries.MidPrices)))
+			sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", formatPriceSlice(data.IntradaySeries.MidPrices, data.TickSize)))
 		}
 
 		if len(data.IntradaySeries.EMA20Values) > 0 {
@@ -728,7 +695,7 @@ func Format(data *Data) string {
 		for _, tf := range timeframeOrder {
 			if tfData, ok := data.TimeframeData[tf]; ok {
 				sb.WriteString(fmt.Sprintf("=== %s Timeframe ===\n\n", strings.ToUpper(tf)))
-				formatTimeframeData(&sb, tfData)
+				formatTimeframeData(&sb, tfData, data.TickSize)
 			}
 		}
 	}
@@ -736,8 +703,8 @@ func Format(data *Data) string {
 	return sb.String()
 }
 
-// formatTimeframeData formats data for a single timeframe
-func formatTimeframeData(sb *strings.Builder, data *TimeframeSeriesData) {
+// formatTimeframeData formats data for a single timeframe, prices on the tick grid
+func formatTimeframeData(sb *strings.Builder, data *TimeframeSeriesData, tick float64) {
 	// Use OHLCV table format if kline data is available
 	if len(data.Klines) > 0 {
 		sb.WriteString("Time(UTC)      Open      High      Low       Close     Volume\n")
@@ -748,13 +715,14 @@ func formatTimeframeData(sb *strings.Builder, data *TimeframeSeriesData) {
 			if i == len(data.Klines)-1 {
 				marker = "  <- current"
 			}
-			sb.WriteString(fmt.Sprintf("%-14s %-9.4f %-9.4f %-9.4f %-9.4f %-12.2f%s\n",
-				timeStr, k.Open, k.High, k.Low, k.Close, k.Volume, marker))
+			sb.WriteString(fmt.Sprintf("%-14s %-9s %-9s %-9s %-9s %-12.2f%s\n",
+				timeStr, FormatPriceTick(k.Open, tick), FormatPriceTick(k.High, tick), FormatPriceTick(k.Low, tick),
+				FormatPriceTick(k.Close, tick), k.Volume, marker))
 		}
 		sb.WriteString("\n")
 	} else if len(data.MidPrices) > 0 {
 		// Fallback to old format for backward compatibility
-		sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", formatFloatSlice(data.MidPrices)))
+		sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", formatPriceSlice(data.MidPrices, tick)))
 		if len(data.Volume) > 0 {
 			sb.WriteString(fmt.Sprintf("Volume: %s\n\n", formatFloatSlice(data.Volume)))
 		}
@@ -828,18 +796,10 @@ func formatFloatSlice(values []float64) string {
 	return "[" + strings.Join(strValues, ", ") + "]"
 }
 
-// Normalize normalizes symbol, ensures it's a USDT trading pair
-// Handles formats like "BTC/USDT", "BTC-USDT", "BTCUSDT", "BTC"
+// Normalize normalizes symbol, ensures it has a quote asset (USDT by default, USDC/BUSD/FDUSD kept as-is)
+// Handles formats like "BTC/USDT", "BTC-USDC", "BTCUSDT", "BTC"
 func Normalize(symbol string) string {
-	symbol = strings.ToUpper(symbol)
-	// Remove common separators (/, -, _)
-	symbol = strings.ReplaceAll(symbol, "/", "")
-	symbol = strings.ReplaceAll(symbol, "-", "")
-	symbol = strings.ReplaceAll(symbol, "_", "")
-	if strings.HasSuffix(symbol, "USDT") {
-		return symbol
-	}
-	return symbol + "USDT"
+	return NormalizeQuote(symbol, DefaultQuoteAsset)
 }
 
 // parseFloat parses float value
@@ -880,7 +840,11 @@ func BuildDataFromKlines(symbol string, primary []Kline, longer []Kline) (*Data,
 		FundingRate:       0,
 		IntradaySeries:    calculateIntradaySeries(primary),
 		LongerTermContext: nil,
+		Bollinger:         calculateBollinger(primary, bollingerPeriod, bollingerStdDev),
+		VWAP:              CalculateVWAP(primary),
+		VolumeProfile:     calculateVolumeProfile(primary),
 	}
+	setMomentumSignals(data, primary)
 
 	if len(longer) > 0 {
 		data.LongerTermContext = calculateLongerTermData(longer)
@@ -0,0 +1,182 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	// depthStreamLevels levels per side of the partial book depth stream (Binance offers 5, 10 and 20)
+	depthStreamLevels = 20
+	// depthStaleAfter a streamed book older than this is refetched (stream stalled, released or reconnecting)
+	depthStaleAfter = 5 * time.Second
+	// depthTopN levels per side summed for top-N liquidity and imbalance
+	depthTopN = 10
+)
+
+// depthStream stream name of a symbol's partial book depth
+func depthStream(symbol string) string {
+	return fmt.Sprintf("%s@depth%d@500ms", strings.ToLower(symbol), depthStreamLevels)
+}
+
+// DepthWSData partial book depth stream payload
+type DepthWSData struct {
+	EventType string     `json:"e"`
+	EventTime int64      `json:"E"`
+	Symbol    string     `json:"s"`
+	Bids      [][]string `json:"b"`
+	Asks      [][]string `json:"a"`
+}
+
+// OrderBookMetrics top-of-book summary for the AI prompt and slippage protection
+type OrderBookMetrics struct {
+	BestBid     float64   `json:"best_bid"`
+	BestAsk     float64   `json:"best_ask"`
+	SpreadBps   float64   `json:"spread_bps"`   // (ask - bid) / mid, in bps
+	Levels      int       `json:"levels"`       // Levels summed per side (up to depthTopN)
+	BidNotional float64   `json:"bid_notional"` // Top-N bid notional (quote currency)
+	AskNotional float64   `json:"ask_notional"` // Top-N ask notional (quote currency)
+	Imbalance   float64   `json:"imbalance"`    // (bid - ask) / (bid + ask) notional, -1..1, > 0 bid-heavy
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Summary one line for prompts, e.g. "spread 1.2 bps, top 10 bid 1.20M / ask 0.90M, imbalance +0.14 (bid-heavy)"
+func (o *OrderBookMetrics) Summary() string {
+	side := "balanced"
+	if o.Imbalance > 0.2 {
+		side = "bid-heavy"
+	} else if o.Imbalance < -0.2 {
+		side = "ask-heavy"
+	}
+	return fmt.Sprintf("spread %.1f bps, top %d bid %s / ask %s, imbalance %+.2f (%s)",
+		o.SpreadBps, o.Levels, formatNotional(o.BidNotional), formatNotional(o.AskNotional), o.Imbalance, side)
+}
+
+// formatNotional shortens a quote amount, e.g. 1234567 -> "1.23M"
+func formatNotional(v float64) string {
+	switch {
+	case v >= 1e9:
+		return fmt.Sprintf("%.2fB", v/1e9)
+	case v >= 1e6:
+		return fmt.Sprintf("%.2fM", v/1e6)
+	case v >= 1e3:
+		return fmt.Sprintf("%.2fK", v/1e3)
+	default:
+		return fmt.Sprintf("%.2f", v)
+	}
+}
+
+// computeOrderBookMetrics summarizes the top topN levels of each side of book
+func computeOrderBookMetrics(book *OrderBook, topN int) (*OrderBookMetrics, error) {
+	if book == nil || len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return nil, fmt.Errorf("order book is empty")
+	}
+	bid, ask := book.Bids[0].Price, book.Asks[0].Price
+	mid := (bid + ask) / 2
+	if mid <= 0 {
+		return nil, fmt.Errorf("invalid mid price")
+	}
+
+	metrics := &OrderBookMetrics{
+		BestBid:   bid,
+		BestAsk:   ask,
+		SpreadBps: (ask - bid) / mid * 10000,
+		Levels:    min(topN, len(book.Bids), len(book.Asks)),
+		UpdatedAt: book.UpdatedAt,
+	}
+	for _, lvl := range book.Bids[:metrics.Levels] {
+		metrics.BidNotional += lvl.Price * lvl.Quantity
+	}
+	for _, lvl := range book.Asks[:metrics.Levels] {
+		metrics.AskNotional += lvl.Price * lvl.Quantity
+	}
+	if total := metrics.BidNotional + metrics.AskNotional; total > 0 {
+		metrics.Imbalance = (metrics.BidNotional - metrics.AskNotional) / total
+	}
+	return metrics, nil
+}
+
+// GetOrderBookMetrics returns spread, top-N liquidity and imbalance of symbol's book,
+// from the depth stream when the WebSocket monitor runs
+func GetOrderBookMetrics(symbol string) (*OrderBookMetrics, error) {
+	symbol = Normalize(symbol)
+	var book *OrderBook
+	var err error
+	if WSMonitorCli != nil {
+		book, err = WSMonitorCli.GetOrderBook(symbol)
+	} else {
+		book, err = NewAPIClient().GetDepth(symbol, depthStreamLevels)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order book for %s: %w", symbol, err)
+	}
+	metrics, err := computeOrderBookMetrics(book, depthTopN)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", symbol, err)
+	}
+	return metrics, nil
+}
+
+// streamedBook returns symbol's cached book if it is fresh, books are replaced whole and must not be modified
+func (m *WSMonitor) streamedBook(symbol string) (*OrderBook, bool) {
+	value, ok := m.depthDataMap.Load(symbol)
+	if !ok {
+		return nil, false
+	}
+	book := value.(*OrderBook)
+	if time.Since(book.UpdatedAt) > depthStaleAfter {
+		return nil, false
+	}
+	return book, true
+}
+
+// GetOrderBook returns symbol's top depthStreamLevels levels per side
+// Served from the depth stream; the first read (or a stale book) fetches via API and subscribes to the stream
+func (m *WSMonitor) GetOrderBook(symbol string) (*OrderBook, error) {
+	if book, ok := m.streamedBook(symbol); ok {
+		return book, nil
+	}
+	book, err := NewAPIClient().GetDepth(symbol, depthStreamLevels)
+	if err != nil {
+		return nil, err
+	}
+	book.UpdatedAt = time.Now()
+	m.depthDataMap.Store(symbol, book)
+	m.subscribeDepth(symbol)
+	return book, nil
+}
+
+// subscribeDepth subscribes to symbol's depth stream on demand (unless a listener is still registered)
+func (m *WSMonitor) subscribeDepth(symbol string) {
+	stream := depthStream(symbol)
+	if m.combinedClient == nil || m.combinedClient.HasSubscriber(stream) {
+		return
+	}
+	ch := m.combinedClient.AddSubscriber(stream, 10)
+	go m.handleDepthData(symbol, ch)
+	m.streams.markDynamic([]string{stream})
+	if err := m.combinedClient.subscribeStreams([]string{stream}); err != nil {
+		log.Printf("Warning: Failed to subscribe to %s depth stream: %v (using API data)", symbol, err)
+	}
+}
+
+func (m *WSMonitor) handleDepthData(symbol string, ch <-chan []byte) {
+	for data := range ch {
+		var depth DepthWSData
+		if err := json.Unmarshal(data, &depth); err != nil {
+			log.Printf("Failed to parse depth data: %v", err)
+			continue
+		}
+		m.depthDataMap.Store(symbol, &OrderBook{
+			Symbol:    symbol,
+			Bids:      parseDepthLevels(depth.Bids),
+			Asks:      parseDepthLevels(depth.Asks),
+			UpdatedAt: time.Now(),
+		})
+	}
+	// Stream released: drop the book so the next read refetches instead of serving a stale one
+	m.depthDataMap.Delete(symbol)
+}
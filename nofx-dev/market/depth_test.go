@@ -0,0 +1,61 @@
+package market
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestComputeOrderBookMetrics(t *testing.T) {
+	book := &OrderBook{
+		Bids: []DepthLevel{{Price: 99.9, Quantity: 10}, {Price: 99.8, Quantity: 20}},
+		Asks: []DepthLevel{{Price: 100.1, Quantity: 5}, {Price: 100.2, Quantity: 5}, {Price: 100.3, Quantity: 50}},
+	}
+	m, err := computeOrderBookMetrics(book, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(m.SpreadBps-20) > 1e-9 {
+		t.Errorf("spread = %.4f bps, want 20", m.SpreadBps)
+	}
+	// Both sides summed to the shallower side's depth
+	if m.Levels != 2 {
+		t.Errorf("levels = %d, want 2", m.Levels)
+	}
+	bid, ask := 99.9*10+99.8*20, 100.1*5+100.2*5
+	if math.Abs(m.BidNotional-bid) > 1e-9 || math.Abs(m.AskNotional-ask) > 1e-9 {
+		t.Errorf("notional = %.2f / %.2f, want %.2f / %.2f", m.BidNotional, m.AskNotional, bid, ask)
+	}
+	if want := (bid - ask) / (bid + ask); math.Abs(m.Imbalance-want) > 1e-9 || m.Imbalance <= 0 {
+		t.Errorf("imbalance = %.4f, want %.4f", m.Imbalance, want)
+	}
+
+	if _, err := computeOrderBookMetrics(&OrderBook{Bids: book.Bids}, 10); err == nil {
+		t.Error("expected error for a one-sided book")
+	}
+}
+
+func TestStreamedBookFreshness(t *testing.T) {
+	m := &WSMonitor{}
+	book := &OrderBook{
+		Symbol:    "BTCUSDT",
+		Bids:      []DepthLevel{{Price: 99, Quantity: 1}},
+		Asks:      []DepthLevel{{Price: 101, Quantity: 1}},
+		UpdatedAt: time.Now(),
+	}
+	m.depthDataMap.Store("BTCUSDT", book)
+	if got, err := m.GetOrderBook("BTCUSDT"); err != nil || got != book {
+		t.Fatalf("expected the streamed book, got %v, %v", got, err)
+	}
+
+	book.UpdatedAt = time.Now().Add(-2 * depthStaleAfter)
+	if _, ok := m.streamedBook("BTCUSDT"); ok {
+		t.Error("expected a stale book to be refetched")
+	}
+}
+
+func TestDepthStream(t *testing.T) {
+	if got := depthStream("BTCUSDT"); got != "btcusdt@depth20@500ms" {
+		t.Fatalf("depthStream = %q", got)
+	}
+}
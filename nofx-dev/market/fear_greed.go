@@ -0,0 +1,223 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"nofx/endpoint"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// fearGreedURL crypto Fear & Greed index (daily values, newest first), the extra days give the recent trend
+	fearGreedURL = "https://api.alternative.me/fng/?limit=8"
+	// fearGreedCacheTTL how long a fetched index is served before a read fetches again (it updates once a day)
+	fearGreedCacheTTL = time.Hour
+	// fearGreedTrendPoints a move of at least this many points over the history is a rising / falling trend
+	fearGreedTrendPoints = 5
+)
+
+// Fear & Greed trend directions
+const (
+	FearGreedRising  = "rising"
+	FearGreedFalling = "falling"
+	FearGreedFlat    = "flat"
+)
+
+// FearGreedPoint one daily index value
+type FearGreedPoint struct {
+	Value          int       `json:"value"` // 0 (extreme fear) - 100 (extreme greed)
+	Classification string    `json:"classification"`
+	Time           time.Time `json:"time"`
+}
+
+// FearGreedIndex current crypto Fear & Greed index and its recent daily values
+type FearGreedIndex struct {
+	Value          int              `json:"value"`
+	Classification string           `json:"classification"` // e.g. "Extreme Fear", "Greed"
+	UpdatedAt      time.Time        `json:"updated_at"`
+	History        []FearGreedPoint `json:"history"` // Daily values, oldest first, ending with the current one
+	Change         int              `json:"change"`  // Current value minus the oldest value of History
+	Trend          string           `json:"trend"`   // rising / falling / flat over History
+}
+
+// Summary one line for prompts, e.g. "32 (Fear), last 8 days 20 → 24 → 25 → 28 → 30 → 29 → 31 → 32, rising (+12)"
+func (f *FearGreedIndex) Summary() string {
+	s := fmt.Sprintf("%d (%s)", f.Value, f.Classification)
+	if len(f.History) > 1 {
+		values := make([]string, len(f.History))
+		for i, p := range f.History {
+			values[i] = strconv.Itoa(p.Value)
+		}
+		s += fmt.Sprintf(", last %d days %s, %s (%+d)", len(f.History), strings.Join(values, " → "), f.Trend, f.Change)
+	}
+	return s
+}
+
+var (
+	fearGreedMu        sync.Mutex
+	fearGreedCache     *FearGreedIndex
+	fearGreedUpdatedAt time.Time
+)
+
+// GetFearGreedIndex returns the cached index, fetching it when the background fetcher isn't running or fell behind
+func GetFearGreedIndex() (*FearGreedIndex, error) {
+	fearGreedMu.Lock()
+	if fearGreedCache != nil && time.Since(fearGreedUpdatedAt) < fearGreedCacheTTL {
+		index := fearGreedCache
+		fearGreedMu.Unlock()
+		return index, nil
+	}
+	fearGreedMu.Unlock()
+	return refreshFearGreedIndex()
+}
+
+// refreshFearGreedIndex fetches the index and replaces the cache
+func refreshFearGreedIndex() (*FearGreedIndex, error) {
+	client := endpoint.WrapClient(&http.Client{Timeout: 15 * time.Second})
+	resp, err := client.Get(fearGreedURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fear & greed index: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fear & greed index: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fear & greed index request failed: %s", resp.Status)
+	}
+	index, err := parseFearGreed(body)
+	if err != nil {
+		return nil, err
+	}
+
+	fearGreedMu.Lock()
+	fearGreedCache = index
+	fearGreedUpdatedAt = time.Now()
+	fearGreedMu.Unlock()
+	return index, nil
+}
+
+// parseFearGreed parses the alternative.me /fng/ response (values and timestamps are strings, newest first)
+func parseFearGreed(body []byte) (*FearGreedIndex, error) {
+	var parsed struct {
+		Data []struct {
+			Value               string `json:"value"`
+			ValueClassification string `json:"value_classification"`
+			Timestamp           string `json:"timestamp"`
+		} `json:"data"`
+		Metadata struct {
+			Error any `json:"error"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse fear & greed index: %w", err)
+	}
+	if len(parsed.Data) == 0 {
+		return nil, fmt.Errorf("fear & greed index has no data (error: %v)", parsed.Metadata.Error)
+	}
+
+	history := make([]FearGreedPoint, 0, len(parsed.Data))
+	for i := len(parsed.Data) - 1; i >= 0; i-- {
+		d := parsed.Data[i]
+		value, err := strconv.Atoi(d.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fear & greed value %q", d.Value)
+		}
+		ts, _ := strconv.ParseInt(d.Timestamp, 10, 64)
+		history = append(history, FearGreedPoint{Value: value, Classification: d.ValueClassification, Time: time.Unix(ts, 0)})
+	}
+
+	latest := history[len(history)-1]
+	index := &FearGreedIndex{
+		Value:          latest.Value,
+		Classification: latest.Classification,
+		UpdatedAt:      latest.Time,
+		History:        history,
+		Change:         latest.Value - history[0].Value,
+		Trend:          FearGreedFlat,
+	}
+	switch {
+	case index.Change >= fearGreedTrendPoints:
+		index.Trend = FearGreedRising
+	case index.Change <= -fearGreedTrendPoints:
+		index.Trend = FearGreedFalling
+	}
+	return index, nil
+}
+
+// FearGreedFetcher periodically refreshes the cached Fear & Greed index so decision cycles read it without waiting
+type FearGreedFetcher struct {
+	interval time.Duration
+
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+}
+
+var (
+	defaultFearGreedFetcher   *FearGreedFetcher
+	defaultFearGreedFetcherMu sync.Mutex
+)
+
+// NewFearGreedFetcher creates a fetcher (interval <= 0 = 30 minutes)
+func NewFearGreedFetcher(interval time.Duration) *FearGreedFetcher {
+	if interval <= 0 {
+		interval = 30 * time.Minute
+	}
+	return &FearGreedFetcher{interval: interval}
+}
+
+// StartFearGreedFetcher starts the process-wide fetcher (idempotent)
+func StartFearGreedFetcher(interval time.Duration) *FearGreedFetcher {
+	defaultFearGreedFetcherMu.Lock()
+	defer defaultFearGreedFetcherMu.Unlock()
+	if defaultFearGreedFetcher == nil {
+		defaultFearGreedFetcher = NewFearGreedFetcher(interval)
+		defaultFearGreedFetcher.Start()
+	}
+	return defaultFearGreedFetcher
+}
+
+// Start fetches immediately, then every interval (failures keep the previous value)
+func (f *FearGreedFetcher) Start() {
+	f.mu.Lock()
+	if f.running {
+		f.mu.Unlock()
+		return
+	}
+	f.running = true
+	f.stopCh = make(chan struct{})
+	stopCh := f.stopCh
+	f.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+		refreshFearGreedIndex()
+		for {
+			select {
+			case <-ticker.C:
+				refreshFearGreedIndex()
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops background fetching
+func (f *FearGreedFetcher) Stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.running {
+		return
+	}
+	f.running = false
+	close(f.stopCh)
+}
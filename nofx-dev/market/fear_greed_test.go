@@ -0,0 +1,36 @@
+package market
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseFearGreed tests history ordering and trend from the alternative.me response
+func TestParseFearGreed(t *testing.T) {
+	body := []byte(`{"name":"Fear and Greed Index","data":[
+		{"value":"32","value_classification":"Fear","timestamp":"1760659200","time_until_update":"3600"},
+		{"value":"29","value_classification":"Fear","timestamp":"1760572800"},
+		{"value":"20","value_classification":"Extreme Fear","timestamp":"1760486400"}
+	],"metadata":{"error":null}}`)
+
+	index, err := parseFearGreed(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index.Value != 32 || index.Classification != "Fear" || !index.UpdatedAt.Equal(time.Unix(1760659200, 0)) {
+		t.Errorf("unexpected current value: %+v", index)
+	}
+	if len(index.History) != 3 || index.History[0].Value != 20 {
+		t.Fatalf("history should be oldest first: %+v", index.History)
+	}
+	if index.Change != 12 || index.Trend != FearGreedRising {
+		t.Errorf("expected rising +12, got %s %+d", index.Trend, index.Change)
+	}
+	if got, want := index.Summary(), "32 (Fear), last 3 days 20 → 29 → 32, rising (+12)"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	if _, err := parseFearGreed([]byte(`{"data":[],"metadata":{"error":"limit exceeded"}}`)); err == nil {
+		t.Error("expected an error without data")
+	}
+}
@@ -0,0 +1,149 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fundingHistoryPeriods settled funding periods kept per symbol
+const fundingHistoryPeriods = 8
+
+// FundingRateCache is the funding rate cache structure
+// Binance Funding Rate only updates every 8 hours, using 1-hour cache can significantly reduce API calls
+type FundingRateCache struct {
+	History   *FundingRateHistory
+	UpdatedAt time.Time
+}
+
+var (
+	fundingRateMap sync.Map // map[string]*FundingRateCache
+	frCacheTTL     = 1 * time.Hour
+)
+
+// FundingRatePoint one settled funding rate
+type FundingRatePoint struct {
+	Time time.Time `json:"time"`
+	Rate float64   `json:"rate"`
+}
+
+// FundingRateHistory recent funding of a symbol
+type FundingRateHistory struct {
+	Settled         []FundingRatePoint `json:"settled"`           // Last fundingHistoryPeriods settlements, oldest first
+	Predicted       float64            `json:"predicted"`         // Rate of the next settlement at current premium
+	NextFundingTime time.Time          `json:"next_funding_time"` // Zero if unknown
+}
+
+// PredictedRate rate of the next settlement, 0 without funding data
+func (h *FundingRateHistory) PredictedRate() float64 {
+	if h == nil {
+		return 0
+	}
+	return h.Predicted
+}
+
+// Average mean of the settled rates (0 without history)
+func (h *FundingRateHistory) Average() float64 {
+	if h == nil || len(h.Settled) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, p := range h.Settled {
+		sum += p.Rate
+	}
+	return sum / float64(len(h.Settled))
+}
+
+// Trend direction of funding: "rising" / "falling" when the predicted rate is above / below the settled average by
+// more than 0.005% per period (or it has been moving the same way across the last three settlements), else "flat"
+func (h *FundingRateHistory) Trend() string {
+	if h == nil || len(h.Settled) == 0 {
+		return "flat"
+	}
+	const threshold = 0.00005
+	diff := h.Predicted - h.Average()
+	n := len(h.Settled)
+	switch {
+	case diff > threshold:
+		return "rising"
+	case diff < -threshold:
+		return "falling"
+	case n >= 3 && h.Settled[n-3].Rate < h.Settled[n-2].Rate && h.Settled[n-2].Rate < h.Settled[n-1].Rate:
+		return "rising"
+	case n >= 3 && h.Settled[n-3].Rate > h.Settled[n-2].Rate && h.Settled[n-2].Rate > h.Settled[n-1].Rate:
+		return "falling"
+	}
+	return "flat"
+}
+
+// Summary one line for prompts, e.g. "0.0100%, 0.0125%, 0.0150% (oldest → latest), avg 0.0125%, predicted next 0.0180%, trend rising"
+func (h *FundingRateHistory) Summary() string {
+	rates := make([]string, len(h.Settled))
+	for i, p := range h.Settled {
+		rates[i] = fmt.Sprintf("%.4f%%", p.Rate*100)
+	}
+	return fmt.Sprintf("%s (oldest → latest), avg %.4f%%, predicted next %.4f%%, trend %s",
+		strings.Join(rates, ", "), h.Average()*100, h.Predicted*100, h.Trend())
+}
+
+// getFundingHistory retrieves the predicted funding rate and recent settlements (optimized: uses 1-hour cache)
+// A failed history fetch still returns the predicted rate
+func getFundingHistory(symbol string) (*FundingRateHistory, error) {
+	// Funding Rate only updates every 8 hours, 1-hour cache is very reasonable
+	if cached, ok := fundingRateMap.Load(symbol); ok {
+		cache := cached.(*FundingRateCache)
+		if time.Since(cache.UpdatedAt) < frCacheTTL {
+			return cache.History, nil
+		}
+	}
+
+	apiClient := NewAPIClient()
+	history, err := apiClient.getPremiumIndex(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if settled, err := apiClient.GetFundingRateHistory(symbol, fundingHistoryPeriods); err == nil {
+		history.Settled = settled
+	}
+
+	fundingRateMap.Store(symbol, &FundingRateCache{
+		History:   history,
+		UpdatedAt: time.Now(),
+	})
+	return history, nil
+}
+
+// getPremiumIndex predicted funding rate and next funding time of symbol
+func (c *APIClient) getPremiumIndex(symbol string) (*FundingRateHistory, error) {
+	url := fmt.Sprintf("%s/fapi/v1/premiumIndex?symbol=%s", baseURL, symbol)
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Symbol          string `json:"symbol"`
+		LastFundingRate string `json:"lastFundingRate"`
+		NextFundingTime int64  `json:"nextFundingTime"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	rate, _ := strconv.ParseFloat(result.LastFundingRate, 64)
+	history := &FundingRateHistory{Predicted: rate}
+	if result.NextFundingTime > 0 {
+		history.NextFundingTime = time.UnixMilli(result.NextFundingTime)
+	}
+	return history, nil
+}
@@ -0,0 +1,51 @@
+package market
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fundingHistory(predicted float64, rates ...float64) *FundingRateHistory {
+	h := &FundingRateHistory{Predicted: predicted}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, r := range rates {
+		h.Settled = append(h.Settled, FundingRatePoint{Time: start.Add(time.Duration(i) * 8 * time.Hour), Rate: r})
+	}
+	return h
+}
+
+// TestFundingRateHistoryTrend tests average and trend of funding settlements
+func TestFundingRateHistoryTrend(t *testing.T) {
+	tests := []struct {
+		name string
+		h    *FundingRateHistory
+		want string
+	}{
+		{"predicted above average", fundingHistory(0.0003, 0.0001, 0.0001, 0.0001), "rising"},
+		{"predicted below average", fundingHistory(-0.0002, 0.0001, 0.0001, 0.0001), "falling"},
+		{"steady", fundingHistory(0.0001, 0.0001, 0.0001, 0.0001), "flat"},
+		{"small steps up", fundingHistory(0.00011, 0.00009, 0.0001, 0.00011), "rising"},
+		{"small steps down", fundingHistory(0.00009, 0.00011, 0.0001, 0.00009), "falling"},
+		{"no history", fundingHistory(0.001), "flat"},
+		{"nil", nil, "flat"},
+	}
+	for _, tt := range tests {
+		if got := tt.h.Trend(); got != tt.want {
+			t.Errorf("%s: trend %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	h := fundingHistory(0.0002, 0.0001, 0.0002, 0.0003)
+	if math.Abs(h.Average()-0.0002) > 1e-12 {
+		t.Errorf("average = %v, want 0.0002", h.Average())
+	}
+	if s := h.Summary(); !strings.Contains(s, "0.0100%, 0.0200%, 0.0300% (oldest → latest)") || !strings.Contains(s, "predicted next 0.0200%") {
+		t.Errorf("unexpected summary %q", s)
+	}
+	var missing *FundingRateHistory
+	if missing.PredictedRate() != 0 {
+		t.Error("nil history should have no predicted rate")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"nofx/endpoint"
+	"nofx/ratelimit"
 	"time"
 )
 
@@ -30,7 +32,7 @@ func GetKlinesRange(symbol string, timeframe string, start, end time.Time) ([]Kl
 	var all []Kline
 	cursor := startMs
 
-	client := &http.Client{Timeout: 15 * time.Second}
+	client := endpoint.WrapClient(ratelimit.WrapClient("binance", &http.Client{Timeout: 15 * time.Second}))
 
 	for cursor < endMs {
 		req, err := http.NewRequest("GET", binanceFuturesKlinesURL, nil)
@@ -0,0 +1,190 @@
+package market
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// klinePersistQueue closed-kline batches buffered for the writer, further batches are dropped while it lags
+	klinePersistQueue = 1024
+	// klinePruneInterval how often klines past the retention window are deleted
+	klinePruneInterval = time.Hour
+)
+
+// KlineStorage persistent kline history (implemented by store.KlineStore)
+type KlineStorage interface {
+	SaveKlines(symbol, timeframe string, klines []Kline) error
+	LoadKlines(symbol, timeframe string, start, end time.Time) ([]Kline, error)
+	DeleteBefore(cutoff time.Time) (int64, error)
+}
+
+// klineBatch closed klines of one symbol's timeframe waiting to be written
+type klineBatch struct {
+	symbol    string
+	timeframe string
+	klines    []Kline
+}
+
+// KlinePersister writes closed klines from the WebSocket monitor and REST fetches to storage in the background
+// and deletes klines older than the retention window
+type KlinePersister struct {
+	storage   KlineStorage
+	retention time.Duration // 0 = keep forever
+	queue     chan klineBatch
+	stopCh    chan struct{}
+	done      chan struct{}
+	stopOnce  sync.Once
+}
+
+var (
+	klinePersister   *KlinePersister
+	klinePersisterMu sync.RWMutex
+)
+
+// StartKlinePersistence persists closed klines to storage from now on, pruning those older than retention
+func StartKlinePersistence(storage KlineStorage, retention time.Duration) *KlinePersister {
+	p := &KlinePersister{
+		storage:   storage,
+		retention: retention,
+		queue:     make(chan klineBatch, klinePersistQueue),
+		stopCh:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	klinePersisterMu.Lock()
+	klinePersister = p
+	klinePersisterMu.Unlock()
+	go p.run()
+	return p
+}
+
+func (p *KlinePersister) run() {
+	defer close(p.done)
+	ticker := time.NewTicker(klinePruneInterval)
+	defer ticker.Stop()
+	p.prune()
+	for {
+		select {
+		case batch := <-p.queue:
+			p.write(batch)
+		case <-ticker.C:
+			p.prune()
+		case <-p.stopCh:
+			// Flush what is already queued
+			for {
+				select {
+				case batch := <-p.queue:
+					p.write(batch)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *KlinePersister) write(batch klineBatch) {
+	if err := p.storage.SaveKlines(batch.symbol, batch.timeframe, batch.klines); err != nil {
+		log.Printf("⚠️  Failed to persist %s %s klines: %v", batch.symbol, batch.timeframe, err)
+	}
+}
+
+func (p *KlinePersister) prune() {
+	if p.retention <= 0 {
+		return
+	}
+	if n, err := p.storage.DeleteBefore(time.Now().Add(-p.retention)); err != nil {
+		log.Printf("⚠️  Failed to prune persisted klines: %v", err)
+	} else if n > 0 {
+		log.Printf("🧹 Pruned %d persisted klines older than %v", n, p.retention)
+	}
+}
+
+// Stop stops persisting after writing the queued klines
+func (p *KlinePersister) Stop() {
+	p.stopOnce.Do(func() {
+		klinePersisterMu.Lock()
+		if klinePersister == p {
+			klinePersister = nil
+		}
+		klinePersisterMu.Unlock()
+		close(p.stopCh)
+		<-p.done
+	})
+}
+
+func currentKlinePersister() *KlinePersister {
+	klinePersisterMu.RLock()
+	defer klinePersisterMu.RUnlock()
+	return klinePersister
+}
+
+// persistClosedKlines queues the klines closed by now for storage (no-op while persistence is off)
+func persistClosedKlines(symbol, timeframe string, klines []Kline) {
+	p := currentKlinePersister()
+	if p == nil {
+		return
+	}
+	now := time.Now().UnixMilli()
+	closed := make([]Kline, 0, len(klines))
+	for _, k := range klines {
+		if k.CloseTime > 0 && k.CloseTime < now {
+			closed = append(closed, k)
+		}
+	}
+	if len(closed) == 0 {
+		return
+	}
+	select {
+	case p.queue <- klineBatch{symbol: symbol, timeframe: timeframe, klines: closed}:
+	default:
+		log.Printf("⚠️  Kline persistence queue full, dropping %d %s %s klines", len(closed), symbol, timeframe)
+	}
+}
+
+// LoadKlinesRange is GetKlinesRange served from persisted history when it fully covers [start, end], otherwise the
+// range is fetched from the exchange and its closed klines are persisted for the next caller
+func LoadKlinesRange(symbol string, timeframe string, start, end time.Time) ([]Kline, error) {
+	p := currentKlinePersister()
+	if p == nil {
+		return GetKlinesRange(symbol, timeframe, start, end)
+	}
+	symbol = Normalize(symbol)
+	normTF, err := NormalizeTimeframe(timeframe)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := p.storage.LoadKlines(symbol, normTF, start, end)
+	if err != nil {
+		log.Printf("⚠️  Failed to load persisted %s %s klines: %v", symbol, normTF, err)
+	} else if klinesCover(stored, supportedTimeframes[normTF], start, end) {
+		return stored, nil
+	}
+
+	klines, err := GetKlinesRange(symbol, normTF, start, end)
+	if err != nil {
+		return nil, err
+	}
+	persistClosedKlines(symbol, normTF, klines)
+	return klines, nil
+}
+
+// klinesCover reports whether klines are gap-free bars of interval spanning every bar opening within [start, end]
+func klinesCover(klines []Kline, interval time.Duration, start, end time.Time) bool {
+	if len(klines) == 0 {
+		return false
+	}
+	step := interval.Milliseconds()
+	first, last := klines[0], klines[len(klines)-1]
+	if first.OpenTime-start.UnixMilli() >= step || end.UnixMilli()-last.OpenTime >= step {
+		return false
+	}
+	for i := 1; i < len(klines); i++ {
+		if klines[i].OpenTime-klines[i-1].OpenTime != step {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package market
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// memKlineStorage in-memory KlineStorage
+type memKlineStorage struct {
+	mu     sync.Mutex
+	klines map[string][]Kline
+}
+
+func (s *memKlineStorage) SaveKlines(symbol, timeframe string, klines []Kline) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.klines[symbol+"@"+timeframe] = append(s.klines[symbol+"@"+timeframe], klines...)
+	return nil
+}
+
+func (s *memKlineStorage) LoadKlines(symbol, timeframe string, start, end time.Time) ([]Kline, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []Kline
+	for _, k := range s.klines[symbol+"@"+timeframe] {
+		if k.OpenTime >= start.UnixMilli() && k.OpenTime <= end.UnixMilli() {
+			result = append(result, k)
+		}
+	}
+	return result, nil
+}
+
+func (s *memKlineStorage) DeleteBefore(cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
+// TestKlinePersistence tests that only closed klines are written and that stored history serves covered ranges
+func TestKlinePersistence(t *testing.T) {
+	storage := &memKlineStorage{klines: make(map[string][]Kline)}
+	p := StartKlinePersistence(storage, 0)
+
+	step := time.Hour.Milliseconds()
+	base := time.Now().Add(-10 * time.Hour).Truncate(time.Hour)
+	var klines []Kline
+	for i := 0; i < 10; i++ {
+		open := base.UnixMilli() + int64(i)*step
+		klines = append(klines, Kline{OpenTime: open, CloseTime: open + step - 1, Close: float64(100 + i)})
+	}
+	forming := Kline{OpenTime: base.UnixMilli() + 10*step, CloseTime: base.UnixMilli() + 11*step - 1}
+	persistClosedKlines("BTCUSDT", "1h", append(klines, forming))
+	p.Stop() // Flushes the queue
+
+	stored, _ := storage.LoadKlines("BTCUSDT", "1h", base, base.Add(24*time.Hour))
+	if len(stored) != 10 {
+		t.Fatalf("expected the 10 closed klines, got %d", len(stored))
+	}
+
+	end := base.Add(9 * time.Hour)
+	if !klinesCover(stored, time.Hour, base, end) {
+		t.Error("stored klines should cover the range")
+	}
+	if klinesCover(stored, time.Hour, base.Add(-time.Hour), end) {
+		t.Error("range starting before the stored history should not be covered")
+	}
+	if klinesCover(stored, time.Hour, base, end.Add(time.Hour)) {
+		t.Error("range ending after the stored history should not be covered")
+	}
+	gapped := append(append([]Kline(nil), stored[:4]...), stored[5:]...)
+	if klinesCover(gapped, time.Hour, base, end) {
+		t.Error("klines with a gap should not cover the range")
+	}
+
+	persistClosedKlines("BTCUSDT", "1h", klines) // Persistence stopped: no-op
+	if currentKlinePersister() != nil {
+		t.Error("stopped persister should be unset")
+	}
+}
@@ -0,0 +1,181 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// liquidationStream all-market liquidation order stream (at most one event per symbol per second)
+	liquidationStream = "!forceOrder@arr"
+	// liquidationWindow how far back liquidations are kept and summarized
+	liquidationWindow = 15 * time.Minute
+	// liquidationBurstFactor a minute is a burst when its notional exceeds this multiple of the window's per-minute average
+	liquidationBurstFactor = 3.0
+	// liquidationBurstMinCount liquidations a minute needs to count as a burst (a single large one isn't a cascade)
+	liquidationBurstMinCount = 3
+)
+
+// ForceOrderWSData liquidation order stream payload
+type ForceOrderWSData struct {
+	EventType string `json:"e"`
+	EventTime int64  `json:"E"`
+	Order     struct {
+		Symbol    string `json:"s"`
+		Side      string `json:"S"` // SELL liquidates a long, BUY liquidates a short
+		Quantity  string `json:"q"`
+		Price     string `json:"p"`
+		AvgPrice  string `json:"ap"`
+		Status    string `json:"X"`
+		FilledQty string `json:"z"`
+		TradeTime int64  `json:"T"`
+	} `json:"o"`
+}
+
+// liquidation one forced order
+type liquidation struct {
+	long     bool // A long position was liquidated
+	notional float64
+	at       time.Time
+}
+
+// LiquidationStats liquidations of a symbol over the last liquidationWindow
+type LiquidationStats struct {
+	WindowMinutes      int       `json:"window_minutes"`
+	LongCount          int       `json:"long_count"`    // Longs liquidated (forced sells)
+	LongNotional       float64   `json:"long_notional"` // Quote currency
+	ShortCount         int       `json:"short_count"`   // Shorts liquidated (forced buys)
+	ShortNotional      float64   `json:"short_notional"`
+	PeakMinuteNotional float64   `json:"peak_minute_notional"` // Largest notional liquidated within one minute
+	PeakMinute         time.Time `json:"peak_minute"`
+	Burst              bool      `json:"burst"` // The peak minute is a cascade well above the window's average
+}
+
+// Summary one line for prompts, e.g. "longs 12 / 1.20M, shorts 1 / 15.00K; burst: 950.00K in the minute of 12:03 UTC"
+func (s *LiquidationStats) Summary() string {
+	line := fmt.Sprintf("longs %d / %s, shorts %d / %s", s.LongCount, formatNotional(s.LongNotional), s.ShortCount, formatNotional(s.ShortNotional))
+	if s.Burst {
+		line += fmt.Sprintf("; burst: %s in the minute of %s UTC", formatNotional(s.PeakMinuteNotional), s.PeakMinute.UTC().Format("15:04"))
+	}
+	return line
+}
+
+// liquidationTracker recent liquidations per symbol
+type liquidationTracker struct {
+	mu     sync.Mutex
+	events map[string][]liquidation
+}
+
+// add records a liquidation, dropping the symbol's events that left the window
+func (l *liquidationTracker) add(symbol string, e liquidation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.events == nil {
+		l.events = make(map[string][]liquidation)
+	}
+	l.events[symbol] = append(pruneLiquidations(l.events[symbol], e.at), e)
+}
+
+// pruneLiquidations drops events older than the window ending at now (events are in arrival order)
+func pruneLiquidations(events []liquidation, now time.Time) []liquidation {
+	cutoff := now.Add(-liquidationWindow)
+	i := 0
+	for i < len(events) && events[i].at.Before(cutoff) {
+		i++
+	}
+	return events[i:]
+}
+
+// stats summarizes symbol's liquidations in the window ending at now, nil when there were none
+func (l *liquidationTracker) stats(symbol string, now time.Time) *LiquidationStats {
+	l.mu.Lock()
+	events := pruneLiquidations(l.events[symbol], now)
+	if len(events) == 0 {
+		delete(l.events, symbol)
+	} else {
+		l.events[symbol] = events
+	}
+	events = append([]liquidation(nil), events...)
+	l.mu.Unlock()
+	if len(events) == 0 {
+		return nil
+	}
+
+	s := &LiquidationStats{WindowMinutes: int(liquidationWindow / time.Minute)}
+	type minute struct {
+		notional float64
+		count    int
+	}
+	minutes := make(map[time.Time]*minute)
+	for _, e := range events {
+		if e.long {
+			s.LongCount++
+			s.LongNotional += e.notional
+		} else {
+			s.ShortCount++
+			s.ShortNotional += e.notional
+		}
+		key := e.at.Truncate(time.Minute)
+		if minutes[key] == nil {
+			minutes[key] = &minute{}
+		}
+		minutes[key].notional += e.notional
+		minutes[key].count++
+	}
+
+	var peak *minute
+	for at, m := range minutes {
+		if peak == nil || m.notional > peak.notional {
+			peak, s.PeakMinute = m, at
+		}
+	}
+	s.PeakMinuteNotional = peak.notional
+	average := (s.LongNotional + s.ShortNotional) / liquidationWindow.Minutes()
+	s.Burst = peak.count >= liquidationBurstMinCount && peak.notional > average*liquidationBurstFactor
+	return s
+}
+
+// Liquidations returns symbol's liquidations over the last liquidationWindow, nil when there were none
+func (m *WSMonitor) Liquidations(symbol string) *LiquidationStats {
+	if m == nil {
+		return nil
+	}
+	return m.liquidations.stats(Normalize(symbol), time.Now())
+}
+
+// subscribeLiquidations subscribes to the all-market liquidation stream
+func (m *WSMonitor) subscribeLiquidations() error {
+	ch := m.combinedClient.AddSubscriber(liquidationStream, 1000)
+	go m.handleLiquidationData(ch)
+	return m.combinedClient.subscribeStreams([]string{liquidationStream})
+}
+
+func (m *WSMonitor) handleLiquidationData(ch <-chan []byte) {
+	for data := range ch {
+		var event ForceOrderWSData
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Printf("Failed to parse liquidation data: %v", err)
+			continue
+		}
+		m.liquidations.add(event.Order.Symbol, event.liquidation())
+	}
+}
+
+// liquidation the forced order's side and notional (at the average fill price once filled)
+func (e *ForceOrderWSData) liquidation() liquidation {
+	qty, _ := strconv.ParseFloat(e.Order.FilledQty, 64)
+	price, _ := strconv.ParseFloat(e.Order.AvgPrice, 64)
+	if qty == 0 || price == 0 {
+		qty, _ = strconv.ParseFloat(e.Order.Quantity, 64)
+		price, _ = strconv.ParseFloat(e.Order.Price, 64)
+	}
+	at := e.Order.TradeTime
+	if at == 0 {
+		at = e.EventTime
+	}
+	return liquidation{long: e.Order.Side == "SELL", notional: qty * price, at: time.UnixMilli(at)}
+}
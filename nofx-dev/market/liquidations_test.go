@@ -0,0 +1,61 @@
+package market
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForceOrderLiquidation(t *testing.T) {
+	payload := `{"e":"forceOrder","E":1700000000100,"o":{"s":"BTCUSDT","S":"SELL","o":"LIMIT","f":"IOC","q":"0.5","p":"35000","ap":"35100","X":"FILLED","l":"0.5","z":"0.5","T":1700000000000}}`
+	var event ForceOrderWSData
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	l := event.liquidation()
+	if !l.long || math.Abs(l.notional-17550) > 1e-9 || l.at.UnixMilli() != 1700000000000 {
+		t.Errorf("expected a 17550 long liquidation at the trade time, got %+v", l)
+	}
+}
+
+func TestLiquidationStats(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 15, 30, 0, time.UTC)
+	var tracker liquidationTracker
+	if s := tracker.stats("BTCUSDT", now); s != nil {
+		t.Fatalf("expected no stats without liquidations, got %+v", s)
+	}
+
+	// Outside the window
+	tracker.add("BTCUSDT", liquidation{long: true, notional: 1e6, at: now.Add(-20 * time.Minute)})
+	// Scattered short liquidations
+	for i := 1; i <= 5; i++ {
+		tracker.add("BTCUSDT", liquidation{notional: 10_000, at: now.Add(-time.Duration(14-i) * time.Minute)})
+	}
+	if s := tracker.stats("BTCUSDT", now); s == nil || s.Burst || s.LongCount != 0 || s.ShortCount != 5 {
+		t.Fatalf("expected 5 short liquidations without a burst, got %+v", s)
+	}
+
+	// Long cascade within one minute
+	for i := 0; i < 4; i++ {
+		tracker.add("BTCUSDT", liquidation{long: true, notional: 100_000, at: now.Add(-2*time.Minute + time.Duration(i)*time.Second)})
+	}
+	s := tracker.stats("BTCUSDT", now)
+	if s == nil || !s.Burst || s.LongCount != 4 || s.LongNotional != 400_000 || s.PeakMinuteNotional != 400_000 {
+		t.Fatalf("expected a 400K long burst, got %+v", s)
+	}
+	if !s.PeakMinute.Equal(time.Date(2024, 1, 1, 12, 13, 0, 0, time.UTC)) {
+		t.Errorf("expected the burst in the minute of 12:13, got %v", s.PeakMinute)
+	}
+	if summary := s.Summary(); !strings.Contains(summary, "longs 4 / 400.00K") || !strings.Contains(summary, "burst: 400.00K in the minute of 12:13 UTC") {
+		t.Errorf("unexpected summary %q", summary)
+	}
+
+	if s := tracker.stats("ETHUSDT", now); s != nil {
+		t.Errorf("expected no stats for another symbol, got %+v", s)
+	}
+	if s := tracker.stats("BTCUSDT", now.Add(time.Hour)); s != nil {
+		t.Errorf("expected liquidations to expire, got %+v", s)
+	}
+}
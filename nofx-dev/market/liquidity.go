@@ -0,0 +1,127 @@
+package market
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// liquidityBandPct order book depth is summed within ±1% of mid price
+	liquidityBandPct = 0.01
+	// liquidityCacheTTL how long a computed limit is reused
+	liquidityCacheTTL = time.Minute
+)
+
+// LiquidityLimit per-symbol max position notional derived from recent volume and order book depth
+type LiquidityLimit struct {
+	Symbol        string    `json:"symbol"`
+	QuoteVolume1h float64   `json:"quote_volume_1h"` // Quote volume traded over the last hour
+	DepthNotional float64   `json:"depth_notional"`  // Min(bid, ask) notional within ±1% of mid
+	VolumeCap     float64   `json:"volume_cap"`      // volumePct × 1h volume
+	DepthCap      float64   `json:"depth_cap"`       // depthPct × depth notional
+	MaxNotional   float64   `json:"max_notional"`    // Min of the non-zero caps
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+var (
+	liquidityCache   = make(map[string]*LiquidityLimit)
+	liquidityCacheMu sync.Mutex
+)
+
+// GetLiquidityLimit returns the max position notional for symbol
+// volumePct: max share of 1h quote volume (e.g. 0.01 = 1%), depthPct: max share of ±1% book depth
+func GetLiquidityLimit(symbol string, volumePct, depthPct float64) (*LiquidityLimit, error) {
+	symbol = Normalize(symbol)
+	liquidityCacheMu.Lock()
+	cached, ok := liquidityCache[symbol]
+	liquidityCacheMu.Unlock()
+
+	if !ok || time.Since(cached.UpdatedAt) > liquidityCacheTTL {
+		apiClient := NewAPIClient()
+
+		// 1h volume from twenty 3m bars (served from WS cache when available)
+		var klines []Kline
+		var err error
+		if WSMonitorCli != nil {
+			klines, err = WSMonitorCli.GetCurrentKlines(symbol, "3m")
+		} else {
+			klines, err = apiClient.GetKlines(symbol, "3m", 20)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get klines for %s: %w", symbol, err)
+		}
+
+		book, err := apiClient.GetDepth(symbol, 100)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get order book for %s: %w", symbol, err)
+		}
+
+		cached = &LiquidityLimit{
+			Symbol:        symbol,
+			QuoteVolume1h: recentQuoteVolume(klines, 20),
+			DepthNotional: depthNotional(book, liquidityBandPct),
+			UpdatedAt:     time.Now(),
+		}
+		liquidityCacheMu.Lock()
+		liquidityCache[symbol] = cached
+		liquidityCacheMu.Unlock()
+	}
+
+	limit := *cached
+	limit.applyCaps(volumePct, depthPct)
+	return &limit, nil
+}
+
+// applyCaps computes MaxNotional from raw liquidity figures (0 means no cap could be derived)
+func (l *LiquidityLimit) applyCaps(volumePct, depthPct float64) {
+	l.VolumeCap = l.QuoteVolume1h * volumePct
+	l.DepthCap = l.DepthNotional * depthPct
+
+	l.MaxNotional = 0
+	for _, c := range []float64{l.VolumeCap, l.DepthCap} {
+		if c > 0 && (l.MaxNotional == 0 || c < l.MaxNotional) {
+			l.MaxNotional = c
+		}
+	}
+}
+
+// recentQuoteVolume sums quote volume of the last n klines
+func recentQuoteVolume(klines []Kline, n int) float64 {
+	if len(klines) > n {
+		klines = klines[len(klines)-n:]
+	}
+	total := 0.0
+	for _, k := range klines {
+		total += k.QuoteVolume
+	}
+	return total
+}
+
+// depthNotional returns the thinner side's notional within ±band of mid price
+func depthNotional(book *OrderBook, band float64) float64 {
+	if book == nil || len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return 0
+	}
+	mid := (book.Bids[0].Price + book.Asks[0].Price) / 2
+	if mid <= 0 {
+		return 0
+	}
+
+	bidNotional := 0.0
+	for _, lvl := range book.Bids {
+		if lvl.Price < mid*(1-band) {
+			break
+		}
+		bidNotional += lvl.Price * lvl.Quantity
+	}
+	askNotional := 0.0
+	for _, lvl := range book.Asks {
+		if lvl.Price > mid*(1+band) {
+			break
+		}
+		askNotional += lvl.Price * lvl.Quantity
+	}
+	return math.Min(bidNotional, askNotional)
+}
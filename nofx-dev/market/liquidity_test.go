@@ -0,0 +1,52 @@
+package market
+
+import (
+	"math"
+	"testing"
+)
+
+// TestDepthNotional tests order book depth summation within the price band
+func TestDepthNotional(t *testing.T) {
+	book := &OrderBook{
+		Bids: []DepthLevel{{Price: 99.9, Quantity: 10}, {Price: 99.5, Quantity: 10}, {Price: 98.0, Quantity: 1000}},
+		Asks: []DepthLevel{{Price: 100.1, Quantity: 5}, {Price: 100.8, Quantity: 5}, {Price: 102.0, Quantity: 1000}},
+	}
+	// mid = 100, band ±1% → bids ≥ 99, asks ≤ 101
+	bids := 99.9*10 + 99.5*10
+	asks := 100.1*5 + 100.8*5
+	got := depthNotional(book, 0.01)
+	if math.Abs(got-math.Min(bids, asks)) > 1e-9 {
+		t.Errorf("expected %.4f, got %.4f", math.Min(bids, asks), got)
+	}
+
+	if depthNotional(&OrderBook{}, 0.01) != 0 {
+		t.Error("empty book should have zero depth")
+	}
+}
+
+// TestLiquidityLimitCaps tests that the tighter of volume and depth caps wins
+func TestLiquidityLimitCaps(t *testing.T) {
+	klines := make([]Kline, 30)
+	for i := range klines {
+		klines[i].QuoteVolume = 1000
+	}
+	l := &LiquidityLimit{QuoteVolume1h: recentQuoteVolume(klines, 20), DepthNotional: 500}
+	if l.QuoteVolume1h != 20000 {
+		t.Fatalf("expected 1h volume 20000, got %.0f", l.QuoteVolume1h)
+	}
+
+	l.applyCaps(0.01, 0.2) // volume cap 200, depth cap 100
+	if l.MaxNotional != 100 {
+		t.Errorf("expected depth cap 100, got %.2f", l.MaxNotional)
+	}
+
+	l.applyCaps(0.01, 0) // depth disabled
+	if l.MaxNotional != 200 {
+		t.Errorf("expected volume cap 200, got %.2f", l.MaxNotional)
+	}
+
+	l.applyCaps(0, 0)
+	if l.MaxNotional != 0 {
+		t.Errorf("expected no cap, got %.2f", l.MaxNotional)
+	}
+}
@@ -0,0 +1,145 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// markPriceStream all-market mark price stream (mark, index and funding of every symbol each second)
+	markPriceStream = "!markPrice@arr@1s"
+	// markPriceStaleAfter mark prices not refreshed for this long are not served
+	markPriceStaleAfter = 2 * time.Minute
+	// BasisBlowoutPct |mark - index| or |last - mark| above this (%) means market orders fill far from mark
+	BasisBlowoutPct = 0.5
+)
+
+// MarkPrice mark / index / last price of a symbol and the spreads between them
+type MarkPrice struct {
+	Symbol      string    `json:"symbol"`
+	MarkPrice   float64   `json:"mark_price"`
+	IndexPrice  float64   `json:"index_price"`
+	LastPrice   float64   `json:"last_price"`    // Latest trade price (0 when unknown)
+	BasisPct    float64   `json:"basis_pct"`     // (mark - index) / index, the premium index in %
+	LastMarkPct float64   `json:"last_mark_pct"` // (last - mark) / mark in %
+	FundingRate float64   `json:"funding_rate"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Blowout whether the basis or the last/mark spread exceeds BasisBlowoutPct
+func (p *MarkPrice) Blowout() bool {
+	return math.Abs(p.BasisPct) > BasisBlowoutPct || math.Abs(p.LastMarkPct) > BasisBlowoutPct
+}
+
+// setLastPrice sets the last trade price and the last/mark spread
+func (p *MarkPrice) setLastPrice(last float64) {
+	p.LastPrice = last
+	p.LastMarkPct = 0
+	if last > 0 && p.MarkPrice > 0 {
+		p.LastMarkPct = (last - p.MarkPrice) / p.MarkPrice * 100
+	}
+}
+
+// Summary one line for prompts, e.g. "mark 100.05, index 100.00 (basis +0.050%), last 100.10 (+0.050% vs mark)"
+func (p *MarkPrice) Summary() string {
+	s := fmt.Sprintf("mark %s, index %s (basis %+.3f%%)",
+		formatPriceWithDynamicPrecision(p.MarkPrice), formatPriceWithDynamicPrecision(p.IndexPrice), p.BasisPct)
+	if p.LastPrice > 0 {
+		s += fmt.Sprintf(", last %s (%+.3f%% vs mark)", formatPriceWithDynamicPrecision(p.LastPrice), p.LastMarkPct)
+	}
+	if p.Blowout() {
+		s += fmt.Sprintf(", BASIS BLOWOUT (>%.1f%%): market orders fill far from mark", BasisBlowoutPct)
+	}
+	return s
+}
+
+// parseMarkPrice converts a mark price stream entry
+func parseMarkPrice(w MarkPriceWSData) *MarkPrice {
+	p := &MarkPrice{Symbol: strings.ToUpper(w.Symbol), UpdatedAt: time.Now()}
+	p.MarkPrice, _ = strconv.ParseFloat(w.MarkPrice, 64)
+	p.IndexPrice, _ = strconv.ParseFloat(w.IndexPrice, 64)
+	p.FundingRate, _ = strconv.ParseFloat(w.FundingRate, 64)
+	if p.IndexPrice > 0 {
+		p.BasisPct = (p.MarkPrice - p.IndexPrice) / p.IndexPrice * 100
+	}
+	if w.EventTime > 0 {
+		p.UpdatedAt = time.UnixMilli(w.EventTime)
+	}
+	return p
+}
+
+// subscribeMarkPrices subscribes to the all-market mark price stream
+func (m *WSMonitor) subscribeMarkPrices() error {
+	ch := m.combinedClient.AddSubscriber(markPriceStream, 100)
+	go m.handleMarkPriceData(ch)
+	return m.combinedClient.subscribeStreams([]string{markPriceStream})
+}
+
+func (m *WSMonitor) handleMarkPriceData(ch <-chan []byte) {
+	for data := range ch {
+		m.processMarkPrices(data)
+	}
+}
+
+// processMarkPrices stores the mark prices of one stream message
+func (m *WSMonitor) processMarkPrices(data []byte) {
+	var prices []MarkPriceWSData
+	if err := json.Unmarshal(data, &prices); err != nil {
+		log.Printf("Failed to parse mark price data: %v", err)
+		return
+	}
+	for _, w := range prices {
+		p := parseMarkPrice(w)
+		m.markPriceMap.Store(p.Symbol, p)
+	}
+}
+
+// MarkPrice returns the symbol's mark / index price with the last price from the ticker stream,
+// nil when the mark price stream hasn't delivered it recently
+func (m *WSMonitor) MarkPrice(symbol string) *MarkPrice {
+	value, ok := m.markPriceMap.Load(strings.ToUpper(symbol))
+	if !ok {
+		return nil
+	}
+	p := value.(*MarkPrice)
+	if time.Since(p.UpdatedAt) > markPriceStaleAfter {
+		return nil
+	}
+	copied := *p
+	if t := m.Ticker24h(symbol); t != nil {
+		copied.setLastPrice(t.LastPrice)
+	}
+	return &copied
+}
+
+// setMarkPrice attaches the symbol's mark / index basis, priced against the latest kline close
+func setMarkPrice(data *Data) {
+	if WSMonitorCli == nil {
+		return
+	}
+	if p := WSMonitorCli.MarkPrice(data.Symbol); p != nil {
+		if data.CurrentPrice > 0 {
+			p.setLastPrice(data.CurrentPrice)
+		}
+		data.MarkPrice = p
+	}
+}
+
+// MarkPrices returns the fresh mark prices of every symbol, widest basis first
+func (m *WSMonitor) MarkPrices() []*MarkPrice {
+	var prices []*MarkPrice
+	m.markPriceMap.Range(func(key, _ any) bool {
+		if p := m.MarkPrice(key.(string)); p != nil {
+			prices = append(prices, p)
+		}
+		return true
+	})
+	sort.Slice(prices, func(i, j int) bool { return math.Abs(prices[i].BasisPct) > math.Abs(prices[j].BasisPct) })
+	return prices
+}
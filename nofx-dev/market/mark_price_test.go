@@ -0,0 +1,48 @@
+package market
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestProcessMarkPrices tests basis from the mark price stream, the last vs mark spread and the blowout flag
+func TestProcessMarkPrices(t *testing.T) {
+	m := &WSMonitor{}
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	payload := `[
+		{"e":"markPriceUpdate","E":` + now + `,"s":"BTCUSDT","p":"100050","i":"100000","P":"100040","r":"0.0001","T":0},
+		{"e":"markPriceUpdate","E":` + now + `,"s":"WILDUSDT","p":"1.012","i":"1.000","r":"0.0030","T":0}
+	]`
+	m.processMarkPrices([]byte(payload))
+	m.tickerDataMap.Store("BTCUSDT", &Ticker24h{Symbol: "BTCUSDT", LastPrice: 100100, UpdatedAt: time.Now()})
+
+	btc := m.MarkPrice("btcusdt")
+	if btc == nil {
+		t.Fatal("BTC mark price not stored")
+	}
+	if math.Abs(btc.BasisPct-0.05) > 1e-9 || btc.FundingRate != 0.0001 {
+		t.Errorf("unexpected mark price: %+v", btc)
+	}
+	if math.Abs(btc.LastMarkPct-0.05/1.0005) > 1e-9 || btc.Blowout() {
+		t.Errorf("last vs mark %.6f%%, blowout %v", btc.LastMarkPct, btc.Blowout())
+	}
+
+	wild := m.MarkPrice("WILDUSDT")
+	if wild == nil || !wild.Blowout() || !strings.Contains(wild.Summary(), "BASIS BLOWOUT") {
+		t.Errorf("1.2%% basis should be a blowout: %+v", wild)
+	}
+
+	prices := m.MarkPrices()
+	if len(prices) != 2 || prices[0].Symbol != "WILDUSDT" {
+		t.Errorf("expected WILDUSDT first, got %+v", prices)
+	}
+
+	// A last price far from mark is a blowout even with a calm basis
+	btc.setLastPrice(101000)
+	if !btc.Blowout() {
+		t.Errorf("last %.3f%% from mark should be a blowout", btc.LastMarkPct)
+	}
+}
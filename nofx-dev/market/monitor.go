@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -17,11 +18,18 @@ type WSMonitor struct {
 	alertsChan     chan Alert
 	klineDataMap3m sync.Map // Store K-line historical data for each trading pair
 	klineDataMap4h sync.Map // Store K-line historical data for each trading pair
-	tickerDataMap  sync.Map // Store ticker data for each trading pair
+	klineDataMaps  sync.Map // Timeframe -> *sync.Map of K-lines for timeframes subscribed on demand
+	tickerDataMap  sync.Map // Symbol -> *Ticker24h from the all-market 24h ticker stream
+	markPriceMap   sync.Map // Symbol -> *MarkPrice from the all-market mark price stream
+	depthDataMap   sync.Map // Symbol -> *OrderBook from the partial depth stream
+	liquidations   liquidationTracker
 	batchSize      int
 	filterSymbols  sync.Map // Use sync.Map to store monitored coins and their status
 	symbolStats    sync.Map // Store symbol statistics
 	FilterSymbol   []string // Filtered symbols
+	streams        streamOwners
+	candleClose    candleCloseBus
+	receivedAt     sync.Map // symbol@timeframe -> time.Time of the last kline update (staleness detection)
 }
 type SymbolStats struct {
 	LastActiveTime   time.Time
@@ -57,7 +65,8 @@ func (m *WSMonitor) Initialize(coins []string) error {
 		// Filter perpetual contract trading pairs -- only use for testing
 		//exchangeInfo.Symbols = exchangeInfo.Symbols[0:2]
 		for _, symbol := range exchangeInfo.Symbols {
-			if symbol.Status == "TRADING" && symbol.ContractType == "PERPETUAL" && strings.ToUpper(symbol.Symbol[len(symbol.Symbol)-4:]) == "USDT" {
+			// USDT- and USDC-margined perpetuals
+			if symbol.Status == "TRADING" && symbol.ContractType == "PERPETUAL" && IsSupportedQuote(symbol.QuoteAsset) {
 				m.symbols = append(m.symbols, symbol.Symbol)
 				m.filterSymbols.Store(symbol.Symbol, true)
 			}
@@ -97,6 +106,7 @@ func (m *WSMonitor) initializeHistoricalData() error {
 			}
 			if len(klines) > 0 {
 				m.klineDataMap3m.Store(s, klines)
+				m.markReceived(s, "3m")
 				log.Printf("Loaded %s historical K-line data-3m: %d entries", s, len(klines))
 			}
 			// Get historical K-line data
@@ -107,6 +117,7 @@ func (m *WSMonitor) initializeHistoricalData() error {
 			}
 			if len(klines4h) > 0 {
 				m.klineDataMap4h.Store(s, klines4h)
+				m.markReceived(s, "4h")
 				log.Printf("Loaded %s historical K-line data-4h: %d entries", s, len(klines4h))
 			}
 		}(symbol)
@@ -141,7 +152,7 @@ func (m *WSMonitor) Start(coins []string) {
 // subscribeSymbol registers listener
 func (m *WSMonitor) subscribeSymbol(symbol, st string) []string {
 	var streams []string
-	stream := fmt.Sprintf("%s@kline_%s", strings.ToLower(symbol), st)
+	stream := klineStream(symbol, st)
 	ch := m.combinedClient.AddSubscriber(stream, 100)
 	streams = append(streams, stream)
 	go m.handleKlineData(symbol, ch, st)
@@ -163,6 +174,17 @@ func (m *WSMonitor) subscribeAll() error {
 			return err
 		}
 	}
+	// Liquidations are context, not required for trading: a failure only leaves them out of the prompt
+	if err := m.subscribeLiquidations(); err != nil {
+		log.Printf("⚠️ Failed to subscribe to liquidation stream: %v", err)
+	}
+	// 24h ticker statistics are context too
+	if err := m.subscribeTickers(); err != nil {
+		log.Printf("⚠️ Failed to subscribe to 24h ticker stream: %v", err)
+	}
+	if err := m.subscribeMarkPrices(); err != nil {
+		log.Printf("⚠️ Failed to subscribe to mark price stream: %v", err)
+	}
 	log.Println("All trading pair subscriptions completed")
 	return nil
 }
@@ -176,6 +198,9 @@ func (m *WSMonitor) handleKlineData(symbol string, ch <-chan []byte, _time strin
 		}
 		m.processKlineUpdate(symbol, klineData, _time)
 	}
+	// Stream released: drop the cache so the next read refetches instead of serving stale bars
+	m.getKlineDataMap(_time).Delete(symbol)
+	m.receivedAt.Delete(strings.ToUpper(symbol) + "@" + _time)
 }
 
 func (m *WSMonitor) getKlineDataMap(_time string) *sync.Map {
@@ -185,7 +210,8 @@ func (m *WSMonitor) getKlineDataMap(_time string) *sync.Map {
 	} else if _time == "4h" {
 		klineDataMap = &m.klineDataMap4h
 	} else {
-		klineDataMap = &sync.Map{}
+		value, _ := m.klineDataMaps.LoadOrStore(_time, &sync.Map{})
+		klineDataMap = value.(*sync.Map)
 	}
 	return klineDataMap
 }
@@ -210,14 +236,18 @@ func (m *WSMonitor) processKlineUpdate(symbol string, wsData KlineWSData, _time
 	value, exists := klineDataMap.Load(symbol)
 	var klines []Kline
 	if exists {
-		klines = value.([]Kline)
+		// Copy on write: readers may still hold the cached slice, it is never modified in place
+		klines = slices.Clone(value.([]Kline))
 
 		// Check if it's a new K-line
 		if len(klines) > 0 && klines[len(klines)-1].OpenTime == kline.OpenTime {
 			// Update current K-line
 			klines[len(klines)-1] = kline
 		} else {
-			// Add new K-line
+			// Add new K-line, the previous one just closed
+			if len(klines) > 0 {
+				persistClosedKlines(symbol, _time, klines[len(klines)-1:])
+			}
 			klines = append(klines, kline)
 
 			// Maintain data length
@@ -230,6 +260,11 @@ func (m *WSMonitor) processKlineUpdate(symbol string, wsData KlineWSData, _time
 	}
 
 	klineDataMap.Store(symbol, klines)
+	m.markReceived(symbol, _time)
+
+	if wsData.Kline.IsFinal {
+		m.candleClose.publish(symbol+"@"+_time, kline)
+	}
 }
 
 func (m *WSMonitor) GetCurrentKlines(symbol string, duration string) ([]Kline, error) {
@@ -245,13 +280,18 @@ func (m *WSMonitor) GetCurrentKlines(symbol string, duration string) ([]Kline, e
 
 		// Dynamically cache into cache
 		m.getKlineDataMap(duration).Store(strings.ToUpper(symbol), klines)
+		m.markReceived(symbol, duration)
+		persistClosedKlines(strings.ToUpper(symbol), duration, klines)
 
-		// Subscribe to WebSocket stream
-		subStr := m.subscribeSymbol(symbol, duration)
-		subErr := m.combinedClient.subscribeStreams(subStr)
-		log.Printf("Dynamic subscription to stream: %v", subStr)
-		if subErr != nil {
-			log.Printf("Warning: Failed to dynamically subscribe to %v-minute K-line: %v (using API data)", duration, subErr)
+		// Subscribe to WebSocket stream (unless a listener is still registered, e.g. cache dropped on release)
+		if !m.combinedClient.HasSubscriber(klineStream(symbol, duration)) {
+			subStr := m.subscribeSymbol(symbol, duration)
+			m.streams.markDynamic(subStr)
+			subErr := m.combinedClient.subscribeStreams(subStr)
+			log.Printf("Dynamic subscription to stream: %v", subStr)
+			if subErr != nil {
+				log.Printf("Warning: Failed to dynamically subscribe to %v-minute K-line: %v (using API data)", duration, subErr)
+			}
 		}
 
 		// ✅ FIX: Return deep copy instead of reference
@@ -267,6 +307,63 @@ func (m *WSMonitor) GetCurrentKlines(symbol string, duration string) ([]Kline, e
 	return result, nil
 }
 
+// MonitorStats cache and subscription statistics (for diagnostics)
+type MonitorStats struct {
+	Symbols          int            `json:"symbols"`
+	KlineCacheSizes  map[string]int `json:"kline_cache_sizes"`  // timeframe -> cached symbols
+	KlineCacheBars   map[string]int `json:"kline_cache_bars"`   // timeframe -> total cached bars
+	TickerCacheSize  int            `json:"ticker_cache_size"`  // cached ticker entries
+	Subscribers      int            `json:"subscribers"`        // combined stream subscribers
+	Connections      int            `json:"connections"`        // combined stream connections
+	AlertsChanLength int            `json:"alerts_chan_length"` // pending alerts
+}
+
+// Stats returns cache sizes and subscriber counts
+func (m *WSMonitor) Stats() MonitorStats {
+	stats := MonitorStats{
+		Symbols:         len(m.symbols),
+		KlineCacheSizes: make(map[string]int),
+		KlineCacheBars:  make(map[string]int),
+	}
+	for _, tf := range subKlineTime {
+		symbols, bars := 0, 0
+		m.getKlineDataMap(tf).Range(func(_, value any) bool {
+			symbols++
+			if klines, ok := value.([]Kline); ok {
+				bars += len(klines)
+			}
+			return true
+		})
+		stats.KlineCacheSizes[tf] = symbols
+		stats.KlineCacheBars[tf] = bars
+	}
+	m.tickerDataMap.Range(func(_, _ any) bool {
+		stats.TickerCacheSize++
+		return true
+	})
+	if m.combinedClient != nil {
+		stats.Subscribers = m.combinedClient.SubscriberCount()
+		stats.Connections = len(m.combinedClient.Metrics().Connections)
+	}
+	stats.AlertsChanLength = len(m.alertsChan)
+	return stats
+}
+
+// StreamMetrics returns combined stream connection health and per-stream message rates, lag and reconnects
+func (m *WSMonitor) StreamMetrics() CombinedStreamsMetrics {
+	if m.combinedClient == nil {
+		return CombinedStreamsMetrics{MaxStreamsPerConnection: maxStreamsPerConnection}
+	}
+	return m.combinedClient.Metrics()
+}
+
+// SimulateDisconnect drops the combined stream connections (chaos testing)
+func (m *WSMonitor) SimulateDisconnect() {
+	if m.combinedClient != nil {
+		m.combinedClient.SimulateDisconnect()
+	}
+}
+
 func (m *WSMonitor) Close() {
 	m.wsClient.Close()
 	close(m.alertsChan)
@@ -0,0 +1,194 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// moversCacheTTL how long a scan is reused (one scan costs a ticker snapshot plus one kline request per symbol)
+	moversCacheTTL = 5 * time.Minute
+	// moversByVolume / moversByChange symbols scanned in depth: the most traded plus the biggest 24h movers
+	moversByVolume = 80
+	moversByChange = 40
+	// moversMinQuoteVolume minimum 24h quote volume (USDT) of a scanned symbol, thinner books are skipped
+	moversMinQuoteVolume = 20_000_000
+	// moversConcurrency parallel kline requests of a scan
+	moversConcurrency = 5
+)
+
+// Mover one symbol of the top-movers scan
+type Mover struct {
+	Symbol         string  `json:"symbol"`
+	Price          float64 `json:"price"`
+	Change1h       float64 `json:"change_1h"`  // %
+	Change4h       float64 `json:"change_4h"`  // %
+	Change24h      float64 `json:"change_24h"` // %
+	QuoteVolume24h float64 `json:"quote_volume_24h"`
+	VolumeSpike    float64 `json:"volume_spike"` // Last hour's volume / mean hourly volume of the 24h before it
+	Score          float64 `json:"score"`        // |1h change| + |4h change| / 2 + volume spike above 1x
+}
+
+var (
+	moversMu        sync.Mutex
+	moversCache     []Mover
+	moversUpdatedAt time.Time
+)
+
+// GetTopMovers ranks the liquid USDT perpetuals by 1h / 4h change and relative volume spike, best first
+func GetTopMovers(limit int) ([]Mover, error) {
+	moversMu.Lock()
+	defer moversMu.Unlock()
+	if moversCache == nil || time.Since(moversUpdatedAt) >= moversCacheTTL {
+		movers, err := scanMovers()
+		if err != nil {
+			return nil, err
+		}
+		moversCache = movers
+		moversUpdatedAt = time.Now()
+	}
+	if limit > 0 && limit < len(moversCache) {
+		return append([]Mover(nil), moversCache[:limit]...), nil
+	}
+	return append([]Mover(nil), moversCache...), nil
+}
+
+// scanMovers picks the symbols to scan from the 24h ticker snapshot and scores them from their hourly klines
+func scanMovers() ([]Mover, error) {
+	apiClient := NewAPIClient()
+	tickers, err := apiClient.GetAllTickers24hr()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get 24h tickers: %w", err)
+	}
+	var trading map[string]*SymbolMeta
+	if metas, err := GetAllSymbolMeta(); err == nil {
+		trading = metas
+	}
+
+	var movers []Mover
+	for _, t := range selectMoverCandidates(tickers, trading) {
+		m := Mover{Symbol: t.Symbol}
+		m.Price, _ = strconv.ParseFloat(t.LastPrice, 64)
+		m.Change24h, _ = strconv.ParseFloat(t.PriceChangePercent, 64)
+		m.QuoteVolume24h, _ = strconv.ParseFloat(t.QuoteVolume, 64)
+		movers = append(movers, m)
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, moversConcurrency)
+	for i := range movers {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(m *Mover) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			klines, err := apiClient.GetKlines(m.Symbol, "1h", 26)
+			if err != nil {
+				return // Scored on its 24h change only
+			}
+			scoreMover(m, klines)
+		}(&movers[i])
+	}
+	wg.Wait()
+
+	sort.Slice(movers, func(i, j int) bool { return movers[i].Score > movers[j].Score })
+	return movers, nil
+}
+
+// selectMoverCandidates liquid USDT perpetuals still trading: the most traded by quote volume plus the biggest
+// absolute 24h movers
+func selectMoverCandidates(tickers []Ticker24hr, trading map[string]*SymbolMeta) []Ticker24hr {
+	var liquid []Ticker24hr
+	for _, t := range tickers {
+		if !strings.HasSuffix(t.Symbol, "USDT") {
+			continue
+		}
+		if trading != nil && trading[t.Symbol] == nil {
+			continue
+		}
+		if qv, _ := strconv.ParseFloat(t.QuoteVolume, 64); qv < moversMinQuoteVolume {
+			continue
+		}
+		liquid = append(liquid, t)
+	}
+
+	selected := make(map[string]bool)
+	var result []Ticker24hr
+	pick := func(less func(a, b Ticker24hr) bool, n int) {
+		sorted := append([]Ticker24hr(nil), liquid...)
+		sort.Slice(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+		for _, t := range sorted[:min(n, len(sorted))] {
+			if !selected[t.Symbol] {
+				selected[t.Symbol] = true
+				result = append(result, t)
+			}
+		}
+	}
+	value := func(s string) float64 {
+		v, _ := strconv.ParseFloat(s, 64)
+		return v
+	}
+	pick(func(a, b Ticker24hr) bool { return value(a.QuoteVolume) > value(b.QuoteVolume) }, moversByVolume)
+	pick(func(a, b Ticker24hr) bool {
+		return math.Abs(value(a.PriceChangePercent)) > math.Abs(value(b.PriceChangePercent))
+	}, moversByChange)
+	return result
+}
+
+// scoreMover sets the 1h / 4h change and volume spike from hourly klines (the last one still forming) and scores them
+func scoreMover(m *Mover, klines []Kline) {
+	n := len(klines)
+	if n >= 2 {
+		last := klines[n-1].Close
+		m.Change1h = pctChange(klines[n-2].Close, last)
+		if n >= 5 {
+			m.Change4h = pctChange(klines[n-5].Close, last)
+		}
+		if m.Price == 0 {
+			m.Price = last
+		}
+	}
+
+	// Compare the last closed hour with the hours before it (the forming hour would understate the spike)
+	if n >= 3 {
+		closed := klines[:n-1]
+		latest := closed[len(closed)-1].Volume
+		prior := closed[max(0, len(closed)-25) : len(closed)-1]
+		sum := 0.0
+		for _, k := range prior {
+			sum += k.Volume
+		}
+		if mean := sum / float64(len(prior)); mean > 0 {
+			m.VolumeSpike = latest / mean
+		}
+	}
+
+	m.Score = math.Abs(m.Change1h) + math.Abs(m.Change4h)/2 + math.Max(0, m.VolumeSpike-1)
+}
+
+// GetAllTickers24hr gets the 24h rolling ticker statistics of every symbol
+func (c *APIClient) GetAllTickers24hr() ([]Ticker24hr, error) {
+	resp, err := c.client.Get(fmt.Sprintf("%s/fapi/v1/ticker/24hr", baseURL))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var tickers []Ticker24hr
+	if err := json.Unmarshal(body, &tickers); err != nil {
+		return nil, fmt.Errorf("failed to parse 24h tickers: %w", err)
+	}
+	return tickers, nil
+}
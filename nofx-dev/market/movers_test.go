@@ -0,0 +1,54 @@
+package market
+
+import (
+	"math"
+	"testing"
+)
+
+// TestScoreMover tests 1h / 4h change and the volume spike of the last closed hour
+func TestScoreMover(t *testing.T) {
+	var klines []Kline
+	for i := 0; i < 26; i++ {
+		klines = append(klines, Kline{Close: 100, Volume: 1000})
+	}
+	klines[21].Close = 95  // 4h reference (4 bars before the forming one)
+	klines[24].Close = 100 // 1h reference, last closed hour
+	klines[24].Volume = 5000
+	klines[25].Close = 104.5 // Forming hour
+
+	m := &Mover{Symbol: "SOLUSDT"}
+	scoreMover(m, klines)
+	if math.Abs(m.Change1h-4.5) > 1e-9 || math.Abs(m.Change4h-10) > 1e-9 {
+		t.Errorf("unexpected changes: 1h %.4f, 4h %.4f", m.Change1h, m.Change4h)
+	}
+	if math.Abs(m.VolumeSpike-5) > 1e-9 {
+		t.Errorf("expected 5x volume spike, got %.4f", m.VolumeSpike)
+	}
+	if want := 4.5 + 10.0/2 + 4; math.Abs(m.Score-want) > 1e-9 {
+		t.Errorf("Score = %.4f, want %.4f", m.Score, want)
+	}
+	if m.Price != 104.5 {
+		t.Errorf("price should default to the last close, got %v", m.Price)
+	}
+}
+
+// TestSelectMoverCandidates tests the liquidity, quote and trading filters of the scan universe
+func TestSelectMoverCandidates(t *testing.T) {
+	tickers := []Ticker24hr{
+		{Symbol: "BTCUSDT", QuoteVolume: "9000000000", PriceChangePercent: "1.0"},
+		{Symbol: "PEPEUSDT", QuoteVolume: "50000000", PriceChangePercent: "-18.5"},
+		{Symbol: "THINUSDT", QuoteVolume: "1000000", PriceChangePercent: "40"},
+		{Symbol: "BTCUSDC", QuoteVolume: "900000000", PriceChangePercent: "1.1"},
+		{Symbol: "OLDUSDT", QuoteVolume: "90000000", PriceChangePercent: "3"},
+	}
+	trading := map[string]*SymbolMeta{"BTCUSDT": {}, "PEPEUSDT": {}, "THINUSDT": {}, "BTCUSDC": {}}
+
+	selected := selectMoverCandidates(tickers, trading)
+	got := map[string]bool{}
+	for _, s := range selected {
+		got[s.Symbol] = true
+	}
+	if len(selected) != 2 || !got["BTCUSDT"] || !got["PEPEUSDT"] {
+		t.Errorf("expected BTCUSDT and PEPEUSDT, got %v", got)
+	}
+}
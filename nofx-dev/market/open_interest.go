@@ -0,0 +1,289 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// oiHistoryRetention how long OI samples are kept per symbol (the longest change window plus slack)
+	oiHistoryRetention = 26 * time.Hour
+	// oiSampleInterval minimum spacing of cached OI samples, fetches in between update the latest sample
+	oiSampleInterval = time.Minute
+	// oiDivergenceOIPct / oiDivergencePricePct minimum opposite moves (%) of OI and price for a divergence
+	oiDivergenceOIPct    = 2.0
+	oiDivergencePricePct = 0.5
+)
+
+// OI vs price divergence flags
+const (
+	OIDivergencePriceUpOIDown = "price_up_oi_down" // Rally on shrinking positioning (short covering, weak follow-through)
+	OIDivergencePriceDownOIUp = "price_down_oi_up" // Sell-off with new positions opening (shorts pressing or longs trapped)
+)
+
+// OIPoint one open interest sample with the price at the time
+type OIPoint struct {
+	Time  time.Time
+	OI    float64
+	Price float64
+}
+
+// oiSeries rolling OI samples of a symbol, oldest first
+type oiSeries struct {
+	mu     sync.Mutex
+	points []OIPoint
+	seeded bool // Exchange OI history was loaded (or the attempt failed)
+}
+
+var oiHistoryMap sync.Map // map[string]*oiSeries
+
+// oiHistoryFor returns the symbol's OI series, creating it on first use
+func oiHistoryFor(symbol string) *oiSeries {
+	series, _ := oiHistoryMap.LoadOrStore(symbol, &oiSeries{})
+	return series.(*oiSeries)
+}
+
+// add records a sample, samples closer than oiSampleInterval replace the latest one
+func (s *oiSeries) add(p OIPoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n := len(s.points); n > 0 && p.Time.Sub(s.points[n-1].Time) < oiSampleInterval {
+		if !p.Time.Before(s.points[n-1].Time) {
+			s.points[n-1] = p
+		}
+		return
+	}
+	s.points = append(s.points, p)
+	s.prune(p.Time)
+}
+
+// seed merges exchange history older than the cached samples
+func (s *oiSeries) seed(history []OIPoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seeded = true
+	var older []OIPoint
+	for _, p := range history {
+		if len(s.points) == 0 || p.Time.Before(s.points[0].Time) {
+			older = append(older, p)
+		}
+	}
+	s.points = append(older, s.points...)
+	if n := len(s.points); n > 0 {
+		s.prune(s.points[n-1].Time)
+	}
+}
+
+// prune drops samples older than oiHistoryRetention (caller holds mu)
+func (s *oiSeries) prune(now time.Time) {
+	cutoff := now.Add(-oiHistoryRetention)
+	i := 0
+	for i < len(s.points) && s.points[i].Time.Before(cutoff) {
+		i++
+	}
+	s.points = s.points[i:]
+}
+
+// snapshot copies the samples
+func (s *oiSeries) snapshot() []OIPoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]OIPoint(nil), s.points...)
+}
+
+// sampleAt latest sample at or before t (false if the history doesn't reach back that far)
+func sampleAt(points []OIPoint, t time.Time) (OIPoint, bool) {
+	i := sort.Search(len(points), func(i int) bool { return points[i].Time.After(t) })
+	if i == 0 {
+		return OIPoint{}, false
+	}
+	return points[i-1], true
+}
+
+// pctChange percentage change from before to after (0 when before is 0)
+func pctChange(before, after float64) float64 {
+	if before == 0 {
+		return 0
+	}
+	return (after - before) / before * 100
+}
+
+// buildOIData computes OI change rates and the divergence flag from the samples, the last sample is the current one
+func buildOIData(points []OIPoint) *OIData {
+	if len(points) == 0 {
+		return &OIData{}
+	}
+	latest := points[len(points)-1]
+	data := &OIData{Latest: latest.OI}
+
+	// Average over the last 24h of samples
+	sum, n := 0.0, 0
+	for _, p := range points {
+		if latest.Time.Sub(p.Time) <= 24*time.Hour {
+			sum += p.OI
+			n++
+		}
+	}
+	data.Average = sum / float64(n)
+
+	windows := []struct {
+		d      time.Duration
+		change *float64
+		has    *bool
+	}{
+		{time.Hour, &data.Change1h, &data.HasChange1h},
+		{4 * time.Hour, &data.Change4h, &data.HasChange4h},
+		{24 * time.Hour, &data.Change24h, &data.HasChange24h},
+	}
+	for _, w := range windows {
+		if past, ok := sampleAt(points, latest.Time.Add(-w.d)); ok {
+			*w.change, *w.has = pctChange(past.OI, latest.OI), true
+		}
+	}
+
+	// Divergence over the longest of 4h / 1h covered by the history
+	for _, d := range []time.Duration{4 * time.Hour, time.Hour} {
+		past, ok := sampleAt(points, latest.Time.Add(-d))
+		if !ok || past.Price <= 0 || latest.Price <= 0 {
+			continue
+		}
+		oiChange, priceChange := pctChange(past.OI, latest.OI), pctChange(past.Price, latest.Price)
+		switch {
+		case priceChange >= oiDivergencePricePct && oiChange <= -oiDivergenceOIPct:
+			data.Divergence = OIDivergencePriceUpOIDown
+		case priceChange <= -oiDivergencePricePct && oiChange >= oiDivergenceOIPct:
+			data.Divergence = OIDivergencePriceDownOIUp
+		}
+		data.DivergenceWindowHours = int(d / time.Hour)
+		break
+	}
+	return data
+}
+
+// Summary one line for prompts, e.g. "1h +1.20%, 4h +3.50%, 24h n/a; divergence (4h): price_down_oi_up"
+func (o *OIData) Summary() string {
+	format := func(change float64, ok bool) string {
+		if !ok {
+			return "n/a"
+		}
+		return fmt.Sprintf("%+.2f%%", change)
+	}
+	s := fmt.Sprintf("1h %s, 4h %s, 24h %s", format(o.Change1h, o.HasChange1h), format(o.Change4h, o.HasChange4h),
+		format(o.Change24h, o.HasChange24h))
+	if o.Divergence != "" {
+		s += fmt.Sprintf("; divergence (%dh): %s", o.DivergenceWindowHours, o.Divergence)
+	}
+	return s
+}
+
+// getOpenInterestData retrieves OI data, records it with the current price and computes change rates from the
+// rolling history (seeded from the exchange's hourly OI history on first use)
+func getOpenInterestData(symbol string, price float64) (*OIData, error) {
+	apiClient := NewAPIClient()
+	oi, at, err := apiClient.GetOpenInterest(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	series := oiHistoryFor(symbol)
+	series.mu.Lock()
+	seeded := series.seeded
+	series.mu.Unlock()
+	if !seeded {
+		history, err := apiClient.GetOpenInterestHistory(symbol, "1h", 25)
+		if err != nil {
+			history = nil // Changes fill in as samples accumulate
+		}
+		series.seed(history)
+	}
+
+	series.add(OIPoint{Time: at, OI: oi, Price: price})
+	return buildOIData(series.snapshot()), nil
+}
+
+// GetOpenInterest gets the current open interest (contracts) and its timestamp
+func (c *APIClient) GetOpenInterest(symbol string) (float64, time.Time, error) {
+	url := fmt.Sprintf("%s/fapi/v1/openInterest?symbol=%s", baseURL, symbol)
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	var result struct {
+		OpenInterest string `json:"openInterest"`
+		Symbol       string `json:"symbol"`
+		Time         int64  `json:"time"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, time.Time{}, err
+	}
+
+	oi, _ := strconv.ParseFloat(result.OpenInterest, 64)
+	at := time.Now()
+	if result.Time > 0 {
+		at = time.UnixMilli(result.Time)
+	}
+	return oi, at, nil
+}
+
+// GetOpenInterestHistory gets the last limit OI samples of period (5m, 15m, 30m, 1h, ...), oldest first
+// The price of each sample is derived from its notional value
+func (c *APIClient) GetOpenInterestHistory(symbol, period string, limit int) ([]OIPoint, error) {
+	url := fmt.Sprintf("%s/futures/data/openInterestHist", baseURL)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("symbol", symbol)
+	q.Add("period", period)
+	q.Add("limit", strconv.Itoa(limit))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []struct {
+		SumOpenInterest      string `json:"sumOpenInterest"`
+		SumOpenInterestValue string `json:"sumOpenInterestValue"`
+		Timestamp            int64  `json:"timestamp"`
+	}
+	if err := json.Unmarshal(body, &records); err != nil {
+		return nil, err
+	}
+
+	points := make([]OIPoint, 0, len(records))
+	for _, r := range records {
+		oi, _ := strconv.ParseFloat(r.SumOpenInterest, 64)
+		value, _ := strconv.ParseFloat(r.SumOpenInterestValue, 64)
+		p := OIPoint{Time: time.UnixMilli(r.Timestamp), OI: oi}
+		if oi > 0 {
+			p.Price = math.Round(value/oi*1e8) / 1e8
+		}
+		points = append(points, p)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	return points, nil
+}
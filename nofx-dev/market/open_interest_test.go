@@ -0,0 +1,71 @@
+package market
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestBuildOIData tests change rates per window, the 24h average and the divergence flag
+func TestBuildOIData(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	points := []OIPoint{
+		{Time: now.Add(-5 * time.Hour), OI: 900, Price: 100},
+		{Time: now.Add(-4 * time.Hour), OI: 1000, Price: 100},
+		{Time: now.Add(-time.Hour), OI: 1040, Price: 98},
+		{Time: now, OI: 1050, Price: 97},
+	}
+	oi := buildOIData(points)
+	if !oi.HasChange1h || math.Abs(oi.Change1h-pctChange(1040, 1050)) > 1e-9 {
+		t.Errorf("1h change = %v (%v)", oi.Change1h, oi.HasChange1h)
+	}
+	if !oi.HasChange4h || math.Abs(oi.Change4h-5) > 1e-9 {
+		t.Errorf("4h change = %v (%v), want 5", oi.Change4h, oi.HasChange4h)
+	}
+	if oi.HasChange24h {
+		t.Error("24h change should be unavailable with 5h of history")
+	}
+	if oi.Average != (900+1000+1040+1050)/4.0 {
+		t.Errorf("average = %v", oi.Average)
+	}
+	// Price -3% while OI +5% over 4h
+	if oi.Divergence != OIDivergencePriceDownOIUp || oi.DivergenceWindowHours != 4 {
+		t.Errorf("divergence = %q over %dh", oi.Divergence, oi.DivergenceWindowHours)
+	}
+	if s := oi.Summary(); !strings.Contains(s, "4h +5.00%") || !strings.Contains(s, "24h n/a") || !strings.Contains(s, OIDivergencePriceDownOIUp) {
+		t.Errorf("unexpected summary: %s", s)
+	}
+
+	// OI following price is no divergence
+	points[len(points)-1].Price = 104
+	if oi := buildOIData(points); oi.Divergence != "" {
+		t.Errorf("expected no divergence, got %q", oi.Divergence)
+	}
+
+	if oi := buildOIData(nil); oi.Latest != 0 || oi.HasChange1h {
+		t.Errorf("expected empty OI data, got %+v", oi)
+	}
+}
+
+// TestOISeries tests sample spacing, seeding with older history and retention
+func TestOISeries(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	var s oiSeries
+	s.add(OIPoint{Time: now, OI: 100})
+	s.add(OIPoint{Time: now.Add(20 * time.Second), OI: 101}) // Replaces the latest sample
+	s.seed([]OIPoint{
+		{Time: now.Add(-30 * time.Hour), OI: 50},
+		{Time: now.Add(-2 * time.Hour), OI: 90},
+		{Time: now.Add(time.Minute), OI: 95}, // Not older than the cached samples
+	})
+	s.add(OIPoint{Time: now.Add(2 * time.Minute), OI: 102})
+
+	points := s.snapshot()
+	if len(points) != 3 || points[0].OI != 90 || points[1].OI != 101 || points[2].OI != 102 {
+		t.Fatalf("unexpected samples: %+v", points)
+	}
+	if !s.seeded {
+		t.Error("series should be marked as seeded")
+	}
+}
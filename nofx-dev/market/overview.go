@@ -0,0 +1,147 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"nofx/endpoint"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// marketOverviewCacheTTL how long the global market snapshot is reused (CoinGecko refreshes it every few minutes)
+	marketOverviewCacheTTL = 10 * time.Minute
+	// coinGeckoGlobalURL public global market data: total market cap and dominance
+	coinGeckoGlobalURL = "https://api.coingecko.com/api/v3/global"
+)
+
+// MarketOverview macro picture of the whole crypto market, the backdrop of altcoin decisions
+type MarketOverview struct {
+	TotalMarketCapUSD     float64   `json:"total_market_cap_usd"`
+	MarketCapChange24hPct float64   `json:"market_cap_change_24h_pct"`
+	BTCDominance          float64   `json:"btc_dominance"` // BTC share of the total market cap in %
+	ETHDominance          float64   `json:"eth_dominance"`
+	BTCChange24hPct       float64   `json:"btc_change_24h_pct"`
+	ETHChange24hPct       float64   `json:"eth_change_24h_pct"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// Summary one line for prompts, e.g. "total cap $2.45T (24h +1.20%), BTC dominance 54.3%, ETH dominance 17.1%, BTC 24h +0.85%, ETH 24h -1.30%"
+func (o *MarketOverview) Summary() string {
+	return fmt.Sprintf("total cap $%.2fT (24h %+.2f%%), BTC dominance %.1f%%, ETH dominance %.1f%%, BTC 24h %+.2f%%, ETH 24h %+.2f%%",
+		o.TotalMarketCapUSD/1e12, o.MarketCapChange24hPct, o.BTCDominance, o.ETHDominance,
+		o.BTCChange24hPct, o.ETHChange24hPct)
+}
+
+var (
+	marketOverviewMu        sync.Mutex
+	marketOverviewCache     *MarketOverview
+	marketOverviewUpdatedAt time.Time
+)
+
+// GetMarketOverview returns total market cap, dominance (CoinGecko) and BTC / ETH 24h change (Binance), cached for
+// marketOverviewCacheTTL
+func GetMarketOverview() (*MarketOverview, error) {
+	marketOverviewMu.Lock()
+	defer marketOverviewMu.Unlock()
+	if marketOverviewCache != nil && time.Since(marketOverviewUpdatedAt) < marketOverviewCacheTTL {
+		return marketOverviewCache, nil
+	}
+
+	overview, err := fetchGlobalMarket()
+	if err != nil {
+		return nil, err
+	}
+
+	apiClient := NewAPIClient()
+	btc, err := apiClient.GetTicker24hr("BTCUSDT")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get BTC 24h ticker: %w", err)
+	}
+	eth, err := apiClient.GetTicker24hr("ETHUSDT")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ETH 24h ticker: %w", err)
+	}
+	overview.BTCChange24hPct, _ = strconv.ParseFloat(btc.PriceChangePercent, 64)
+	overview.ETHChange24hPct, _ = strconv.ParseFloat(eth.PriceChangePercent, 64)
+
+	marketOverviewCache = overview
+	marketOverviewUpdatedAt = time.Now()
+	return overview, nil
+}
+
+// fetchGlobalMarket total market cap and dominance from CoinGecko's global endpoint
+func fetchGlobalMarket() (*MarketOverview, error) {
+	client := endpoint.WrapClient(&http.Client{Timeout: 15 * time.Second})
+	resp, err := client.Get(coinGeckoGlobalURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get global market data: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read global market data: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("global market data request failed: %s", resp.Status)
+	}
+	return parseGlobalMarket(body)
+}
+
+// parseGlobalMarket parses CoinGecko's /global response
+func parseGlobalMarket(body []byte) (*MarketOverview, error) {
+	var parsed struct {
+		Data struct {
+			TotalMarketCap                  map[string]float64 `json:"total_market_cap"`
+			MarketCapPercentage             map[string]float64 `json:"market_cap_percentage"`
+			MarketCapChangePercentage24hUSD float64            `json:"market_cap_change_percentage_24h_usd"`
+			UpdatedAt                       int64              `json:"updated_at"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse global market data: %w", err)
+	}
+	d := parsed.Data
+	if d.TotalMarketCap["usd"] <= 0 {
+		return nil, fmt.Errorf("global market data has no USD market cap")
+	}
+
+	overview := &MarketOverview{
+		TotalMarketCapUSD:     d.TotalMarketCap["usd"],
+		MarketCapChange24hPct: d.MarketCapChangePercentage24hUSD,
+		BTCDominance:          d.MarketCapPercentage["btc"],
+		ETHDominance:          d.MarketCapPercentage["eth"],
+		UpdatedAt:             time.Now(),
+	}
+	if d.UpdatedAt > 0 {
+		overview.UpdatedAt = time.Unix(d.UpdatedAt, 0)
+	}
+	return overview, nil
+}
+
+// GetTicker24hr gets the 24h rolling ticker statistics of a symbol
+func (c *APIClient) GetTicker24hr(symbol string) (*Ticker24hr, error) {
+	url := fmt.Sprintf("%s/fapi/v1/ticker/24hr?symbol=%s", baseURL, symbol)
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var ticker Ticker24hr
+	if err := json.Unmarshal(body, &ticker); err != nil {
+		return nil, err
+	}
+	if ticker.Symbol == "" {
+		return nil, fmt.Errorf("no 24h ticker for %s: %s", symbol, string(body))
+	}
+	return &ticker, nil
+}
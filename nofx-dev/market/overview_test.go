@@ -0,0 +1,37 @@
+package market
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseGlobalMarket tests market cap and dominance extraction from CoinGecko's /global response
+func TestParseGlobalMarket(t *testing.T) {
+	body := []byte(`{"data":{"active_cryptocurrencies":17000,
+		"total_market_cap":{"btc":38500000.5,"usd":2450000000000},
+		"market_cap_percentage":{"btc":54.32,"eth":17.08,"usdt":4.1},
+		"market_cap_change_percentage_24h_usd":1.2,"updated_at":1760000000}}`)
+
+	overview, err := parseGlobalMarket(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if overview.TotalMarketCapUSD != 2.45e12 || overview.BTCDominance != 54.32 || overview.ETHDominance != 17.08 ||
+		overview.MarketCapChange24hPct != 1.2 {
+		t.Errorf("unexpected overview: %+v", overview)
+	}
+	if !overview.UpdatedAt.Equal(time.Unix(1760000000, 0)) {
+		t.Errorf("unexpected update time: %v", overview.UpdatedAt)
+	}
+
+	if _, err := parseGlobalMarket([]byte(`{"status":{"error_code":429,"error_message":"rate limited"}}`)); err == nil {
+		t.Error("expected an error without market cap")
+	}
+
+	overview.BTCChange24hPct, overview.ETHChange24hPct = 0.85, -1.3
+	want := "total cap $2.45T (24h +1.20%), BTC dominance 54.3%, ETH dominance 17.1%, BTC 24h +0.85%, ETH 24h -1.30%"
+	if got := overview.Summary(); !strings.Contains(got, want) {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
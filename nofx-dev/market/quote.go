@@ -0,0 +1,73 @@
+package market
+
+import "strings"
+
+// DefaultQuoteAsset quote asset appended when a symbol has no recognized quote suffix
+const DefaultQuoteAsset = "USDT"
+
+// quoteAssets supported margin/quote assets for perpetual contracts (BUSD kept for legacy pairs)
+// Order matters: longer suffixes must not be shadowed by shorter ones
+var quoteAssets = []string{"FDUSD", "USDT", "USDC", "BUSD"}
+
+// QuoteAsset returns the quote (margin) asset of a symbol, e.g. BTCUSDC -> USDC
+// Returns empty string if the symbol has no recognized quote suffix
+func QuoteAsset(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	for _, q := range quoteAssets {
+		if len(symbol) > len(q) && strings.HasSuffix(symbol, q) {
+			return q
+		}
+	}
+	return ""
+}
+
+// BaseAsset returns the base asset of a symbol, e.g. BTCUSDC -> BTC
+// Case of the base is preserved (Hyperliquid uses names like kPEPE)
+func BaseAsset(symbol string) string {
+	if q := QuoteAsset(symbol); q != "" {
+		return symbol[:len(symbol)-len(q)]
+	}
+	return symbol
+}
+
+// IsBTCETH returns whether a symbol is a BTC or ETH contract, whatever its quote asset (BTCUSDT, ETHUSDC, BTC)
+func IsBTCETH(symbol string) bool {
+	base := strings.ToUpper(BaseAsset(symbol))
+	return base == "BTC" || base == "ETH"
+}
+
+// IsSupportedQuote returns whether the asset can be used as a quote/margin asset
+func IsSupportedQuote(asset string) bool {
+	asset = strings.ToUpper(asset)
+	for _, q := range quoteAssets {
+		if q == asset {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeQuote normalizes symbol like Normalize, appending quote (instead of USDT) when the symbol has none
+// An empty or unsupported quote falls back to DefaultQuoteAsset
+func NormalizeQuote(symbol, quote string) string {
+	symbol = strings.ToUpper(symbol)
+	symbol = strings.ReplaceAll(symbol, "/", "")
+	symbol = strings.ReplaceAll(symbol, "-", "")
+	symbol = strings.ReplaceAll(symbol, "_", "")
+	if QuoteAsset(symbol) != "" {
+		return symbol
+	}
+	if !IsSupportedQuote(quote) {
+		quote = DefaultQuoteAsset
+	}
+	return symbol + strings.ToUpper(quote)
+}
+
+// WithQuote rebases symbol onto quote, e.g. BTCUSDT with USDC -> BTCUSDC
+// Used for symbols from USDT-only sources (coin pool, OI ranking) on exchanges configured for another quote
+func WithQuote(symbol, quote string) string {
+	if !IsSupportedQuote(quote) {
+		return NormalizeQuote(symbol, DefaultQuoteAsset)
+	}
+	return NormalizeQuote(BaseAsset(NormalizeQuote(symbol, quote)), quote)
+}
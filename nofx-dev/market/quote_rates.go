@@ -0,0 +1,99 @@
+package market
+
+import (
+	"nofx/logger"
+	"sync"
+	"time"
+)
+
+// quoteRateSymbols USDⓈ-M perpetual pricing each non-USDT quote asset in USDT
+// FDUSD and BUSD have no such contract and stay at the stablecoin default of 1
+var quoteRateSymbols = map[string]string{
+	"USDC": "USDCUSDT",
+}
+
+// refreshQuoteRates fetches the USDT price of each quote asset and registers it for logger.ToUSD
+// USDT is the USD reference; a failed fetch keeps the previous rate
+func refreshQuoteRates(price func(symbol string) (float64, error)) {
+	for quote, symbol := range quoteRateSymbols {
+		rate, err := price(symbol)
+		if err != nil || rate <= 0 {
+			logger.Warnf("⚠️ Failed to refresh %s quote rate (keeping %.4f): %v", quote, logger.QuoteRate(quote), err)
+			continue
+		}
+		logger.RegisterQuoteRate(quote, rate)
+	}
+}
+
+// QuoteRateFetcher periodically refreshes the USD rates of quote assets so P&L of USDC-margined positions is
+// recorded at the stablecoin's market value
+type QuoteRateFetcher struct {
+	interval time.Duration
+
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+}
+
+var (
+	defaultQuoteRateFetcher   *QuoteRateFetcher
+	defaultQuoteRateFetcherMu sync.Mutex
+)
+
+// NewQuoteRateFetcher creates a fetcher (interval <= 0 = 5 minutes)
+func NewQuoteRateFetcher(interval time.Duration) *QuoteRateFetcher {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	return &QuoteRateFetcher{interval: interval}
+}
+
+// StartQuoteRateFetcher starts the process-wide fetcher (idempotent)
+func StartQuoteRateFetcher(interval time.Duration) *QuoteRateFetcher {
+	defaultQuoteRateFetcherMu.Lock()
+	defer defaultQuoteRateFetcherMu.Unlock()
+	if defaultQuoteRateFetcher == nil {
+		defaultQuoteRateFetcher = NewQuoteRateFetcher(interval)
+		defaultQuoteRateFetcher.Start()
+	}
+	return defaultQuoteRateFetcher
+}
+
+// Start fetches immediately, then every interval
+func (f *QuoteRateFetcher) Start() {
+	f.mu.Lock()
+	if f.running {
+		f.mu.Unlock()
+		return
+	}
+	f.running = true
+	f.stopCh = make(chan struct{})
+	stopCh := f.stopCh
+	f.mu.Unlock()
+
+	go func() {
+		client := NewAPIClient()
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+		refreshQuoteRates(client.GetCurrentPrice)
+		for {
+			select {
+			case <-ticker.C:
+				refreshQuoteRates(client.GetCurrentPrice)
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops background fetching
+func (f *QuoteRateFetcher) Stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.running {
+		return
+	}
+	f.running = false
+	close(f.stopCh)
+}
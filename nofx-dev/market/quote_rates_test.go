@@ -0,0 +1,27 @@
+package market
+
+import (
+	"errors"
+	"nofx/logger"
+	"testing"
+)
+
+// TestRefreshQuoteRates tests fetched rates feed logger.ToUSD and a failed fetch keeps the previous rate
+func TestRefreshQuoteRates(t *testing.T) {
+	defer logger.RegisterQuoteRate("USDC", 1)
+
+	refreshQuoteRates(func(symbol string) (float64, error) {
+		if symbol != "USDCUSDT" {
+			t.Errorf("unexpected symbol %s", symbol)
+		}
+		return 0.998, nil
+	})
+	if got := logger.ToUSD("USDC", 100); got != 99.8 {
+		t.Errorf("ToUSD(USDC, 100) = %v, want 99.8", got)
+	}
+
+	refreshQuoteRates(func(symbol string) (float64, error) { return 0, errors.New("timeout") })
+	if got := logger.QuoteRate("USDC"); got != 0.998 {
+		t.Errorf("expected the previous rate kept after a failed fetch, got %v", got)
+	}
+}
@@ -0,0 +1,89 @@
+package market
+
+import "testing"
+
+// TestQuoteAndBaseAsset tests quote asset detection for USDT/USDC-margined symbols
+func TestQuoteAndBaseAsset(t *testing.T) {
+	tests := []struct {
+		symbol    string
+		wantQuote string
+		wantBase  string
+	}{
+		{"BTCUSDT", "USDT", "BTC"},
+		{"BTCUSDC", "USDC", "BTC"},
+		{"ethusdc", "USDC", "eth"},
+		{"kPEPEUSDT", "USDT", "kPEPE"},
+		{"BTCBUSD", "BUSD", "BTC"},
+		{"ETHFDUSD", "FDUSD", "ETH"},
+		{"BTC", "", "BTC"},
+		{"USDT", "", "USDT"},
+	}
+	for _, tt := range tests {
+		if got := QuoteAsset(tt.symbol); got != tt.wantQuote {
+			t.Errorf("QuoteAsset(%q) = %q, want %q", tt.symbol, got, tt.wantQuote)
+		}
+		if got := BaseAsset(tt.symbol); got != tt.wantBase {
+			t.Errorf("BaseAsset(%q) = %q, want %q", tt.symbol, got, tt.wantBase)
+		}
+	}
+}
+
+// TestNormalizeKeepsUSDC tests that USDC-margined pairs are not rewritten to USDT
+func TestNormalizeKeepsUSDC(t *testing.T) {
+	tests := map[string]string{
+		"BTC":      "BTCUSDT",
+		"btc/usdt": "BTCUSDT",
+		"BTC-USDC": "BTCUSDC",
+		"ethusdc":  "ETHUSDC",
+	}
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestNormalizeQuote tests normalization onto a configured quote asset
+func TestNormalizeQuote(t *testing.T) {
+	tests := []struct {
+		symbol, quote, want string
+	}{
+		{"BTC", "USDC", "BTCUSDC"},
+		{"btc/busd", "USDC", "BTCBUSD"}, // Explicit quote is kept
+		{"ETH", "", "ETHUSDT"},
+		{"ETH", "EUR", "ETHUSDT"}, // Unsupported quote falls back to USDT
+	}
+	for _, tt := range tests {
+		if got := NormalizeQuote(tt.symbol, tt.quote); got != tt.want {
+			t.Errorf("NormalizeQuote(%q, %q) = %q, want %q", tt.symbol, tt.quote, got, tt.want)
+		}
+	}
+
+	rebased := map[string]string{
+		"BTCUSDT": "BTCUSDC",
+		"SOL":     "SOLUSDC",
+		"ETHBUSD": "ETHUSDC",
+	}
+	for in, want := range rebased {
+		if got := WithQuote(in, "usdc"); got != want {
+			t.Errorf("WithQuote(%q, usdc) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestIsBTCETH tests BTC / ETH detection by base asset, not symbol prefix
+func TestIsBTCETH(t *testing.T) {
+	tests := map[string]bool{
+		"BTCUSDT":    true,
+		"ethusdc":    true,
+		"BTC":        true,
+		"ETHFIUSDT":  false,
+		"BTCDOMUSDT": false,
+		"SOLUSDT":    false,
+	}
+	for symbol, want := range tests {
+		if got := IsBTCETH(symbol); got != want {
+			t.Errorf("IsBTCETH(%q) = %v, want %v", symbol, got, want)
+		}
+	}
+}
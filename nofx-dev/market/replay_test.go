@@ -0,0 +1,81 @@
+package market
+
+import (
+	"math"
+	"nofx/vcr"
+	"testing"
+	"time"
+)
+
+// TestReplayKlinesAndStream tests the REST fallback and the kline stream against recorded Binance traffic
+func TestReplayKlinesAndStream(t *testing.T) {
+	session := vcr.Use(t, "testdata/cassettes/binance_klines_3m.json")
+	session.Streams(t, "wss://fstream.binance.com")
+
+	m := &WSMonitor{combinedClient: NewCombinedStreamsClient(10)}
+	if err := m.combinedClient.Connect(); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer m.combinedClient.Close()
+
+	klines, err := m.GetCurrentKlines("BTCUSDT", "3m")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(klines) != 2 || klines[1].Close != 93488.80 || klines[1].Trades != 5371 {
+		t.Fatalf("unexpected klines from the recorded response: %+v", klines)
+	}
+
+	// The recorded stream update replaces the open bar
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if bars, err := m.GetCurrentKlines("BTCUSDT", "3m"); err == nil && len(bars) == 2 && bars[1].Close == 93512.40 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("expected the open bar to be updated from the recorded stream")
+}
+
+// TestReplayFundingHistory tests fetching the predicted funding rate and the last settlements from recorded responses
+func TestReplayFundingHistory(t *testing.T) {
+	vcr.Use(t, "testdata/cassettes/binance_funding.json")
+	fundingRateMap.Delete("ETHUSDT")
+	defer fundingRateMap.Delete("ETHUSDT")
+
+	history, err := getFundingHistory("ETHUSDT")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if history.Predicted != 0.000245 || history.NextFundingTime.UnixMilli() != 1735718400000 {
+		t.Errorf("unexpected predicted funding: %+v", history)
+	}
+	if len(history.Settled) != fundingHistoryPeriods || history.Settled[7].Rate != 0.00021 {
+		t.Fatalf("expected %d settlements oldest first, got %+v", fundingHistoryPeriods, history.Settled)
+	}
+	if trend := history.Trend(); trend != "rising" {
+		t.Errorf("expected a rising trend, got %s", trend)
+	}
+}
+
+// TestReplayOpenInterest tests OI change rates seeded from the recorded hourly OI history
+func TestReplayOpenInterest(t *testing.T) {
+	vcr.Use(t, "testdata/cassettes/binance_open_interest.json")
+	oiHistoryMap.Delete("ETHUSDT")
+	defer oiHistoryMap.Delete("ETHUSDT")
+
+	// Price fell 96 points from 3400 over 24h while OI kept climbing
+	oi, err := getOpenInterestData("ETHUSDT", 3300)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if oi.Latest != 112300 || !oi.HasChange1h || !oi.HasChange4h || !oi.HasChange24h {
+		t.Fatalf("expected all change windows from the recorded history, got %+v", oi)
+	}
+	if want := pctChange(100000, 112300); math.Abs(oi.Change24h-want) > 1e-9 {
+		t.Errorf("24h change = %v, want %v", oi.Change24h, want)
+	}
+	if oi.Divergence != OIDivergencePriceDownOIUp {
+		t.Errorf("expected %s, got %q", OIDivergencePriceDownOIUp, oi.Divergence)
+	}
+}
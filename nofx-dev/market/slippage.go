@@ -0,0 +1,128 @@
+package market
+
+import (
+	"fmt"
+	"math"
+)
+
+// slippageDepthLimit levels fetched for slippage estimation
+const slippageDepthLimit = 100
+
+// SlippageEstimate expected price impact of a market order walking the order book
+type SlippageEstimate struct {
+	Symbol      string  `json:"symbol"`
+	Side        string  `json:"side"`         // "buy" or "sell"
+	Notional    float64 `json:"notional"`     // Requested order notional (USDT)
+	MidPrice    float64 `json:"mid_price"`    // (best bid + best ask) / 2
+	AvgPrice    float64 `json:"avg_price"`    // Volume-weighted fill price
+	SlippageBps float64 `json:"slippage_bps"` // Avg fill price distance from mid, in bps
+	Exhausted   bool    `json:"exhausted"`    // Fetched book is thinner than the order
+	// MaxNotional largest notional filling within the requested bps limit (0 when no limit given)
+	MaxNotional float64 `json:"max_notional"`
+}
+
+// EstimateSlippage estimates the impact of a market order on symbol's current order book
+// side: "buy" walks the asks, "sell" walks the bids; maxBps > 0 also computes MaxNotional
+// The streamed book is used when it is fresh and deep enough for the order, otherwise a deeper book is fetched
+func EstimateSlippage(symbol, side string, notional, maxBps float64) (*SlippageEstimate, error) {
+	symbol = Normalize(symbol)
+	if WSMonitorCli != nil {
+		if book, ok := WSMonitorCli.streamedBook(symbol); ok {
+			if est, err := estimateFromBook(book, side, notional, maxBps); err == nil && !est.Exhausted {
+				est.Symbol = symbol
+				return est, nil
+			}
+		}
+	}
+	book, err := NewAPIClient().GetDepth(symbol, slippageDepthLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order book for %s: %w", symbol, err)
+	}
+	est, err := estimateFromBook(book, side, notional, maxBps)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", symbol, err)
+	}
+	est.Symbol = symbol
+	return est, nil
+}
+
+// estimateFromBook walks one side of book for notional (quote currency)
+func estimateFromBook(book *OrderBook, side string, notional, maxBps float64) (*SlippageEstimate, error) {
+	if book == nil || len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return nil, fmt.Errorf("order book is empty")
+	}
+	mid := (book.Bids[0].Price + book.Asks[0].Price) / 2
+	if mid <= 0 {
+		return nil, fmt.Errorf("invalid mid price")
+	}
+
+	var levels []DepthLevel
+	switch side {
+	case "buy":
+		levels = book.Asks
+	case "sell":
+		levels = book.Bids
+	default:
+		return nil, fmt.Errorf("invalid side %q, expected buy or sell", side)
+	}
+
+	est := &SlippageEstimate{Side: side, Notional: notional, MidPrice: mid}
+
+	remaining := notional
+	filledQty, filledNotional := 0.0, 0.0
+	for _, lvl := range levels {
+		if remaining <= 0 {
+			break
+		}
+		take := math.Min(remaining, lvl.Price*lvl.Quantity)
+		filledNotional += take
+		filledQty += take / lvl.Price
+		remaining -= take
+	}
+	if filledQty > 0 {
+		est.AvgPrice = filledNotional / filledQty
+		est.SlippageBps = math.Abs(est.AvgPrice-mid) / mid * 10000
+	}
+	est.Exhausted = remaining > 1e-9
+	if maxBps > 0 {
+		est.MaxNotional = maxNotionalWithin(levels, mid, maxBps)
+	}
+	return est, nil
+}
+
+// maxNotionalWithin returns the largest notional whose average fill stays within maxBps of mid
+// The average price only worsens level by level, so levels are taken until the one where the
+// average would cross the limit, which is then filled partially
+func maxNotionalWithin(levels []DepthLevel, mid, maxBps float64) float64 {
+	limit := maxBps / 10000
+	qty, notional := 0.0, 0.0
+	for _, lvl := range levels {
+		dev := math.Abs(lvl.Price-mid) / mid
+		if dev <= limit {
+			qty += lvl.Quantity
+			notional += lvl.Price * lvl.Quantity
+			continue
+		}
+		// Partial fill q at this level so that |notional+p·q - mid·(qty+q)| = limit·mid·(qty+q)
+		// Buys (p > mid): q = (mid(1+limit)qty - notional) / (p - mid(1+limit))
+		// Sells (p < mid): q = (notional - mid(1-limit)qty) / (mid(1-limit) - p)
+		var q float64
+		if lvl.Price > mid {
+			target := mid * (1 + limit)
+			q = (target*qty - notional) / (lvl.Price - target)
+		} else {
+			target := mid * (1 - limit)
+			q = (notional - target*qty) / (target - lvl.Price)
+		}
+		if q >= lvl.Quantity {
+			qty += lvl.Quantity
+			notional += lvl.Price * lvl.Quantity
+			continue
+		}
+		if q > 0 {
+			notional += lvl.Price * q
+		}
+		break
+	}
+	return notional
+}
@@ -0,0 +1,75 @@
+package market
+
+import (
+	"math"
+	"testing"
+)
+
+// TestEstimateFromBook tests the volume-weighted fill price of an order walking the book
+func TestEstimateFromBook(t *testing.T) {
+	book := &OrderBook{
+		Bids: []DepthLevel{{Price: 99.9, Quantity: 10}, {Price: 99.0, Quantity: 10}},
+		Asks: []DepthLevel{{Price: 100.1, Quantity: 10}, {Price: 101.0, Quantity: 10}},
+	}
+
+	// Fits in the best ask: 10 bps from mid 100
+	est, err := estimateFromBook(book, "buy", 500, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(est.SlippageBps-10) > 1e-6 || est.Exhausted {
+		t.Errorf("expected 10 bps, got %.4f (exhausted %v)", est.SlippageBps, est.Exhausted)
+	}
+
+	// Takes the whole first level and 1001 USDT of the second
+	est, _ = estimateFromBook(book, "buy", 2002, 0)
+	qty := 10 + 1001/101.0
+	want := (2002/qty - 100) / 100 * 10000
+	if math.Abs(est.SlippageBps-want) > 1e-6 {
+		t.Errorf("expected %.4f bps, got %.4f", want, est.SlippageBps)
+	}
+
+	est, _ = estimateFromBook(book, "sell", 1e6, 0)
+	if !est.Exhausted {
+		t.Error("order larger than the book should be marked exhausted")
+	}
+
+	if _, err := estimateFromBook(&OrderBook{}, "buy", 100, 0); err == nil {
+		t.Error("empty book should fail")
+	}
+}
+
+// TestMaxNotionalWithin tests sizing down to the bps limit
+func TestMaxNotionalWithin(t *testing.T) {
+	book := &OrderBook{
+		Bids: []DepthLevel{{Price: 99.9, Quantity: 10}, {Price: 99.0, Quantity: 10}},
+		Asks: []DepthLevel{{Price: 100.1, Quantity: 10}, {Price: 101.0, Quantity: 10}},
+	}
+
+	for _, side := range []string{"buy", "sell"} {
+		est, err := estimateFromBook(book, side, 1e6, 30)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if est.MaxNotional <= 1001 || est.MaxNotional >= 2011 {
+			t.Fatalf("%s: max notional %.2f should include the first level and part of the second", side, est.MaxNotional)
+		}
+		// Filling exactly MaxNotional lands on the limit
+		atMax, _ := estimateFromBook(book, side, est.MaxNotional, 0)
+		if math.Abs(atMax.SlippageBps-30) > 1e-6 {
+			t.Errorf("%s: slippage at max notional should be 30 bps, got %.4f", side, atMax.SlippageBps)
+		}
+	}
+
+	// Limit inside the spread: nothing fills
+	est, _ := estimateFromBook(book, "buy", 100, 5)
+	if est.MaxNotional != 0 {
+		t.Errorf("expected 0 within 5 bps, got %.2f", est.MaxNotional)
+	}
+
+	// Generous limit: whole fetched side
+	est, _ = estimateFromBook(book, "buy", 100, 1000)
+	if math.Abs(est.MaxNotional-(1001+1010)) > 1e-6 {
+		t.Errorf("expected whole ask side, got %.2f", est.MaxNotional)
+	}
+}
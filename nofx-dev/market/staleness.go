@@ -0,0 +1,87 @@
+package market
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// DataStaleAfter a cached kline series not updated for this long is stale (the kline stream pushes every few
+// seconds while it is connected, REST fills are refreshed by the stream subscribed along with them)
+const DataStaleAfter = 2 * time.Minute
+
+// SeriesHealth receive time of one cached kline series
+type SeriesHealth struct {
+	Symbol     string    `json:"symbol"`
+	Timeframe  string    `json:"timeframe"`
+	ReceivedAt time.Time `json:"received_at"`
+	AgeSeconds float64   `json:"age_seconds"`
+	Stale      bool      `json:"stale"`
+}
+
+// DataHealth freshness of the market data cache, degraded while any series is stale
+type DataHealth struct {
+	CheckedAt         time.Time      `json:"checked_at"`
+	StaleAfterSeconds float64        `json:"stale_after_seconds"`
+	Series            int            `json:"series"` // Cached kline series
+	Degraded          bool           `json:"degraded"`
+	StaleSymbols      []string       `json:"stale_symbols"`
+	Stale             []SeriesHealth `json:"stale"` // Stale series, oldest first
+}
+
+// markReceived records that symbol's timeframe series was just updated
+func (m *WSMonitor) markReceived(symbol, timeframe string) {
+	m.receivedAt.Store(strings.ToUpper(symbol)+"@"+timeframe, time.Now())
+}
+
+// ReceivedAt when symbol's timeframe series was last updated (false if it isn't cached)
+func (m *WSMonitor) ReceivedAt(symbol, timeframe string) (time.Time, bool) {
+	value, ok := m.receivedAt.Load(strings.ToUpper(symbol) + "@" + timeframe)
+	if !ok {
+		return time.Time{}, false
+	}
+	return value.(time.Time), true
+}
+
+// Health reports the cached kline series not updated within DataStaleAfter
+func (m *WSMonitor) Health() DataHealth {
+	now := time.Now()
+	health := DataHealth{CheckedAt: now, StaleAfterSeconds: DataStaleAfter.Seconds(), StaleSymbols: []string{}, Stale: []SeriesHealth{}}
+	staleSymbols := make(map[string]bool)
+	m.receivedAt.Range(func(key, value any) bool {
+		health.Series++
+		receivedAt := value.(time.Time)
+		age := now.Sub(receivedAt)
+		if age < DataStaleAfter {
+			return true
+		}
+		symbol, timeframe, _ := strings.Cut(key.(string), "@")
+		health.Stale = append(health.Stale, SeriesHealth{
+			Symbol:     symbol,
+			Timeframe:  timeframe,
+			ReceivedAt: receivedAt,
+			AgeSeconds: age.Seconds(),
+			Stale:      true,
+		})
+		staleSymbols[symbol] = true
+		return true
+	})
+	sort.Slice(health.Stale, func(i, j int) bool { return health.Stale[i].ReceivedAt.Before(health.Stale[j].ReceivedAt) })
+	for symbol := range staleSymbols {
+		health.StaleSymbols = append(health.StaleSymbols, symbol)
+	}
+	sort.Strings(health.StaleSymbols)
+	health.Degraded = len(health.Stale) > 0
+	return health
+}
+
+// setFreshness sets data's receive time and stale flag from its primary series
+func (m *WSMonitor) setFreshness(data *Data, timeframe string) {
+	receivedAt, ok := m.ReceivedAt(data.Symbol, timeframe)
+	if !ok {
+		return
+	}
+	data.ReceivedAt = receivedAt
+	data.DataAge = time.Since(receivedAt)
+	data.Stale = data.DataAge >= DataStaleAfter
+}
@@ -0,0 +1,44 @@
+package market
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDataHealth tests receive-time tracking, the health report and the stale flag on Data
+func TestDataHealth(t *testing.T) {
+	m := &WSMonitor{}
+	var ws KlineWSData
+	ws.Kline.StartTime, ws.Kline.CloseTime = 0, 179999
+	m.processKlineUpdate("BTCUSDT", ws, "3m")
+	m.receivedAt.Store("DOGEUSDT@3m", time.Now().Add(-5*time.Minute))
+	m.receivedAt.Store("DOGEUSDT@1h", time.Now().Add(-10*time.Minute))
+
+	if at, ok := m.ReceivedAt("btcusdt", "3m"); !ok || time.Since(at) > time.Second {
+		t.Fatalf("BTC update not recorded: %v %v", at, ok)
+	}
+
+	health := m.Health()
+	if !health.Degraded || health.Series != 3 || len(health.Stale) != 2 {
+		t.Fatalf("unexpected health: %+v", health)
+	}
+	if len(health.StaleSymbols) != 1 || health.StaleSymbols[0] != "DOGEUSDT" || health.Stale[0].Timeframe != "1h" {
+		t.Errorf("stale series should be DOGEUSDT, oldest first: %+v", health)
+	}
+
+	fresh, stale := &Data{Symbol: "BTCUSDT"}, &Data{Symbol: "DOGEUSDT"}
+	m.setFreshness(fresh, "3m")
+	m.setFreshness(stale, "3m")
+	if fresh.Stale || fresh.ReceivedAt.IsZero() {
+		t.Errorf("BTC data should be fresh: %+v", fresh)
+	}
+	if !stale.Stale || stale.DataAge < 5*time.Minute {
+		t.Errorf("DOGE data should be stale: %+v", stale)
+	}
+
+	m.receivedAt.Delete("DOGEUSDT@3m")
+	m.receivedAt.Delete("DOGEUSDT@1h")
+	if m.Health().Degraded {
+		t.Error("health should recover once stale series are gone")
+	}
+}
@@ -0,0 +1,162 @@
+package market
+
+import "fmt"
+
+// Stochastic RSI (14, 14, 3, 3) and RSI divergence parameters
+const (
+	stochRSIPeriod     = 14
+	stochPeriod        = 14
+	stochKSmooth       = 3
+	stochDSmooth       = 3
+	divergenceLookback = 30 // Candles searched for the two latest swing highs / lows
+	pivotWidth         = 2  // Candles on each side a swing high / low must exceed
+)
+
+// StochRSI stochastic oscillator of RSI (0-100): above 80 overbought, below 20 oversold
+type StochRSI struct {
+	K float64 `json:"k"` // SMA(3) of the raw stochastic of RSI
+	D float64 `json:"d"` // SMA(3) of K
+}
+
+// Summary one line for prompts, e.g. "K 85.2, D 78.9 (overbought)"
+func (s *StochRSI) Summary() string {
+	zone := ""
+	switch {
+	case s.K >= 80:
+		zone = " (overbought)"
+	case s.K <= 20:
+		zone = " (oversold)"
+	}
+	return fmt.Sprintf("K %.1f, D %.1f%s", s.K, s.D, zone)
+}
+
+// calculateRSISeries Wilder RSI for every kline (same smoothing as calculateRSI), entries before period are 0
+func calculateRSISeries(klines []Kline, period int) []float64 {
+	series := make([]float64, len(klines))
+	if len(klines) <= period {
+		return series
+	}
+	rsi := func(avgGain, avgLoss float64) float64 {
+		if avgLoss == 0 {
+			return 100
+		}
+		return 100 - 100/(1+avgGain/avgLoss)
+	}
+
+	gains, losses := 0.0, 0.0
+	for i := 1; i <= period; i++ {
+		change := klines[i].Close - klines[i-1].Close
+		if change > 0 {
+			gains += change
+		} else {
+			losses -= change
+		}
+	}
+	avgGain, avgLoss := gains/float64(period), losses/float64(period)
+	series[period] = rsi(avgGain, avgLoss)
+
+	for i := period + 1; i < len(klines); i++ {
+		gain, loss := 0.0, 0.0
+		if change := klines[i].Close - klines[i-1].Close; change > 0 {
+			gain = change
+		} else {
+			loss = -change
+		}
+		avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+		avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+		series[i] = rsi(avgGain, avgLoss)
+	}
+	return series
+}
+
+// sma mean of the last n values
+func sma(values []float64, n int) float64 {
+	sum := 0.0
+	for _, v := range values[len(values)-n:] {
+		sum += v
+	}
+	return sum / float64(n)
+}
+
+// calculateStochRSI computes StochRSI (14, 14, 3, 3), nil without enough klines
+func calculateStochRSI(klines []Kline) *StochRSI {
+	rsi := calculateRSISeries(klines, stochRSIPeriod)
+	first := stochRSIPeriod // First valid RSI
+	// Raw stochastic needs stochPeriod RSI values, K needs kSmooth raw values, D needs dSmooth K values
+	if len(klines)-first < stochPeriod+stochKSmooth+stochDSmooth-2 {
+		return nil
+	}
+
+	var raw []float64
+	for i := first + stochPeriod - 1; i < len(rsi); i++ {
+		lo, hi := rsi[i], rsi[i]
+		for _, v := range rsi[i-stochPeriod+1 : i+1] {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+		stoch := 50.0 // Flat RSI: middle of the range
+		if hi > lo {
+			stoch = (rsi[i] - lo) / (hi - lo) * 100
+		}
+		raw = append(raw, stoch)
+	}
+
+	var k []float64
+	for i := stochKSmooth; i <= len(raw); i++ {
+		k = append(k, sma(raw[:i], stochKSmooth))
+	}
+	return &StochRSI{K: k[len(k)-1], D: sma(k, stochDSmooth)}
+}
+
+// isPivot reports whether values[i] is below (low) / above (high) the pivotWidth values on each side
+func isPivot(values []float64, i int, low bool) bool {
+	for j := i - pivotWidth; j <= i+pivotWidth; j++ {
+		if j == i {
+			continue
+		}
+		if low && values[j] <= values[i] || !low && values[j] >= values[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// detectRSIDivergence compares the two latest swing lows / highs of the last divergenceLookback candles:
+// bullish when price made a lower low but RSI(14) a higher low, bearish when price made a higher high but RSI a lower high
+func detectRSIDivergence(klines []Kline) (bullish, bearish bool) {
+	rsi := calculateRSISeries(klines, 14)
+	start := len(klines) - divergenceLookback
+	if start < 14+pivotWidth {
+		start = 14 + pivotWidth
+	}
+	lows := make([]float64, len(klines))
+	highs := make([]float64, len(klines))
+	for i, k := range klines {
+		lows[i], highs[i] = k.Low, k.High
+	}
+
+	var lowPivots, highPivots []int
+	for i := start; i < len(klines)-pivotWidth; i++ {
+		if isPivot(lows, i, true) {
+			lowPivots = append(lowPivots, i)
+		}
+		if isPivot(highs, i, false) {
+			highPivots = append(highPivots, i)
+		}
+	}
+
+	if n := len(lowPivots); n >= 2 {
+		prev, last := lowPivots[n-2], lowPivots[n-1]
+		bullish = lows[last] < lows[prev] && rsi[last] > rsi[prev]
+	}
+	if n := len(highPivots); n >= 2 {
+		prev, last := highPivots[n-2], highPivots[n-1]
+		bearish = highs[last] > highs[prev] && rsi[last] < rsi[prev]
+	}
+	return bullish, bearish
+}
+
+// setMomentumSignals sets StochRSI and the RSI divergence flags of data from its primary series
+func setMomentumSignals(data *Data, klines []Kline) {
+	data.StochRSI = calculateStochRSI(klines)
+	data.RSIBullishDivergence, data.RSIBearishDivergence = detectRSIDivergence(klines)
+}
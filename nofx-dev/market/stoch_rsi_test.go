@@ -0,0 +1,81 @@
+package market
+
+import (
+	"math"
+	"testing"
+)
+
+// priceKlines klines whose high, low and close are the given prices
+func priceKlines(prices ...float64) []Kline {
+	klines := make([]Kline, len(prices))
+	for i, p := range prices {
+		klines[i] = Kline{High: p, Low: p, Close: p}
+	}
+	return klines
+}
+
+// TestCalculateRSISeries tests that the series ends on the same value as calculateRSI
+func TestCalculateRSISeries(t *testing.T) {
+	var prices []float64
+	for i := 0; i < 40; i++ {
+		prices = append(prices, 100+10*math.Sin(float64(i)/3))
+	}
+	klines := priceKlines(prices...)
+	series := calculateRSISeries(klines, 14)
+	if got, want := series[len(series)-1], calculateRSI(klines, 14); math.Abs(got-want) > 1e-9 {
+		t.Errorf("last RSI = %v, want %v", got, want)
+	}
+	if series[13] != 0 || series[14] == 0 {
+		t.Errorf("series should start at the period: %v", series[:16])
+	}
+}
+
+// TestCalculateStochRSI tests the oscillator range and the minimum history
+func TestCalculateStochRSI(t *testing.T) {
+	var prices []float64
+	for i := 0; i < 60; i++ {
+		prices = append(prices, 100+10*math.Sin(float64(i)/4))
+	}
+	s := calculateStochRSI(priceKlines(prices...))
+	if s == nil || s.K < 0 || s.K > 100 || s.D < 0 || s.D > 100 {
+		t.Fatalf("unexpected StochRSI: %+v", s)
+	}
+
+	// Turning up from the bottom of the cycle: RSI rises within its recent range
+	up := calculateStochRSI(priceKlines(prices[:52]...))
+	if up.K <= up.D {
+		t.Errorf("K should lead D when RSI turns up: %+v", up)
+	}
+
+	if calculateStochRSI(priceKlines(prices[:30]...)) != nil {
+		t.Error("30 klines are not enough for StochRSI (14, 14, 3, 3)")
+	}
+}
+
+// TestDetectRSIDivergence tests a lower price low on weaker selling (bullish) and a higher high on weaker buying (bearish)
+func TestDetectRSIDivergence(t *testing.T) {
+	base := make([]float64, 20)
+	for i := range base {
+		base[i] = 100 + float64(i%2)
+	}
+
+	// Sharp drop to 90, rebound, then a slow grind to a marginally lower low at 89.5
+	bull := append(append([]float64{}, base...), 96, 92, 90, 94, 97, 99, 98, 97, 96, 95, 94, 93, 92, 91, 90.5, 90, 89.5, 91, 93)
+	if bullish, bearish := detectRSIDivergence(priceKlines(bull...)); !bullish || bearish {
+		t.Errorf("expected a bullish divergence only, got bullish=%v bearish=%v", bullish, bearish)
+	}
+
+	// Mirror image: sharp spike to 110, pullback, slow grind to a marginally higher high at 110.5
+	bear := make([]float64, len(bull))
+	for i, p := range bull {
+		bear[i] = 200 - p
+	}
+	if bullish, bearish := detectRSIDivergence(priceKlines(bear...)); bullish || !bearish {
+		t.Errorf("expected a bearish divergence only, got bullish=%v bearish=%v", bullish, bearish)
+	}
+
+	// A range without swing lows / highs
+	if bullish, bearish := detectRSIDivergence(priceKlines(base...)); bullish || bearish {
+		t.Error("a range without swings should not flag divergences")
+	}
+}
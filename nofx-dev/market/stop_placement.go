@@ -0,0 +1,172 @@
+package market
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	stopDepthLimit     = 1000 // Book levels scanned for liquidity walls
+	stopProfileBars    = 168  // 1h bars in the volume profile (7 days)
+	stopBufferBps      = 10   // Distance kept beyond a liquidity level, in bps
+	stopWallMultiple   = 3.0  // Book level this many times the median size is a wall
+	stopNodeMultiple   = 2.0  // Profile bin this many times the mean volume is a high-volume node
+	stopProfileBinBps  = 20   // Volume profile bin width, in bps of the stop price
+	stopRoundNumberDiv = 10   // Round number step = 10^floor(log10(price)) / stopRoundNumberDiv
+)
+
+// Liquidity level kinds a stop is moved beyond
+const (
+	StopLevelRoundNumber = "round_number"
+	StopLevelBookWall    = "order_book_wall"
+	StopLevelVolumeNode  = "volume_node"
+)
+
+// StopAdjustment requested stop and the level it was snapped to (Adjusted == Requested when unchanged)
+type StopAdjustment struct {
+	Requested float64 `json:"requested"`
+	Adjusted  float64 `json:"adjusted"`
+	Level     float64 `json:"level,omitempty"`  // Liquidity level the stop was moved beyond
+	Reason    string  `json:"reason,omitempty"` // Kind of that level
+}
+
+// Changed whether the stop was moved
+func (a StopAdjustment) Changed() bool {
+	return a.Adjusted != a.Requested
+}
+
+// stopLevel price attracting stop hunts
+type stopLevel struct {
+	price float64
+	kind  string
+}
+
+// SuggestStop moves a stop loss beyond round numbers, order book walls and high-volume nodes near it
+// side is the position side ("long" stops sit below price, "short" above). The stop only moves further
+// from price, by at most tolerancePct % of the stop
+func SuggestStop(symbol, side string, stop, tolerancePct float64) (*StopAdjustment, error) {
+	symbol = Normalize(symbol)
+	client := NewAPIClient()
+	book, err := client.GetDepth(symbol, stopDepthLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order book for %s: %w", symbol, err)
+	}
+	klines, err := client.GetKlines(symbol, "1h", stopProfileBars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get klines for %s: %w", symbol, err)
+	}
+	adj := adjustStop(side, stop, tolerancePct, stopLevels(side, stop, book, klines))
+	return &adj, nil
+}
+
+// adjustStop moves stop beyond the furthest level near it that can be cleared within the tolerance
+func adjustStop(side string, stop, tolerancePct float64, levels []stopLevel) StopAdjustment {
+	adj := StopAdjustment{Requested: stop, Adjusted: stop}
+	if stop <= 0 || tolerancePct <= 0 {
+		return adj
+	}
+	tol := stop * tolerancePct / 100
+	buffer := stopBufferBps / 10000.0
+
+	for _, lvl := range levels {
+		if math.Abs(lvl.price-stop) > tol {
+			continue
+		}
+		var candidate float64
+		if side == "short" {
+			candidate = lvl.price * (1 + buffer)
+			if candidate <= adj.Adjusted || candidate > stop+tol {
+				continue
+			}
+		} else {
+			candidate = lvl.price * (1 - buffer)
+			if candidate >= adj.Adjusted || candidate < stop-tol {
+				continue
+			}
+		}
+		adj.Adjusted, adj.Level, adj.Reason = candidate, lvl.price, lvl.kind
+	}
+	return adj
+}
+
+// stopLevels collects liquidity levels around stop: round numbers, walls on the book side the stop
+// rests against (bids below longs, asks above shorts) and volume profile nodes
+func stopLevels(side string, stop float64, book *OrderBook, klines []Kline) []stopLevel {
+	var levels []stopLevel
+	if step := roundNumberStep(stop); step > 0 {
+		below := math.Floor(stop/step) * step
+		levels = append(levels,
+			stopLevel{price: below, kind: StopLevelRoundNumber},
+			stopLevel{price: below + step, kind: StopLevelRoundNumber})
+	}
+	if book != nil {
+		bookSide := book.Bids
+		if side == "short" {
+			bookSide = book.Asks
+		}
+		for _, p := range bookWalls(bookSide) {
+			levels = append(levels, stopLevel{price: p, kind: StopLevelBookWall})
+		}
+	}
+	for _, p := range volumeNodes(klines, stop*stopProfileBinBps/10000) {
+		levels = append(levels, stopLevel{price: p, kind: StopLevelVolumeNode})
+	}
+	return levels
+}
+
+// roundNumberStep psychological price step, e.g. 1000 for 64,250, 100 for 3,120, 0.01 for 0.52
+func roundNumberStep(price float64) float64 {
+	if price <= 0 {
+		return 0
+	}
+	return math.Pow(10, math.Floor(math.Log10(price))) / stopRoundNumberDiv
+}
+
+// bookWalls prices of levels at least stopWallMultiple times the median level size
+func bookWalls(levels []DepthLevel) []float64 {
+	if len(levels) == 0 {
+		return nil
+	}
+	sizes := make([]float64, len(levels))
+	for i, lvl := range levels {
+		sizes[i] = lvl.Quantity
+	}
+	sort.Float64s(sizes)
+	median := sizes[len(sizes)/2]
+
+	var walls []float64
+	for _, lvl := range levels {
+		if median > 0 && lvl.Quantity >= median*stopWallMultiple {
+			walls = append(walls, lvl.Price)
+		}
+	}
+	return walls
+}
+
+// volumeNodes centers of volume profile bins (volume by typical price) with at least stopNodeMultiple
+// times the mean volume of non-empty bins
+func volumeNodes(klines []Kline, binWidth float64) []float64 {
+	if len(klines) == 0 || binWidth <= 0 {
+		return nil
+	}
+	bins := make(map[int64]float64)
+	for _, k := range klines {
+		typical := (k.High + k.Low + k.Close) / 3
+		bins[int64(math.Floor(typical/binWidth))] += k.Volume
+	}
+	total := 0.0
+	for _, v := range bins {
+		total += v
+	}
+	mean := total / float64(len(bins))
+
+	var nodes []float64
+	for bin, v := range bins {
+		if mean > 0 && v >= mean*stopNodeMultiple {
+			nodes = append(nodes, (float64(bin)+0.5)*binWidth)
+		}
+	}
+	sort.Float64s(nodes)
+	return nodes
+}
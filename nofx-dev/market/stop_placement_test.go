@@ -0,0 +1,56 @@
+package market
+
+import (
+	"math"
+	"testing"
+)
+
+// TestAdjustStop tests snapping stops beyond nearby liquidity levels within the tolerance
+func TestAdjustStop(t *testing.T) {
+	// Long stop just above a round number moves below it, with buffer
+	adj := adjustStop("long", 64050, 0.5, stopLevels("long", 64050, nil, nil))
+	if !adj.Changed() || adj.Reason != StopLevelRoundNumber || math.Abs(adj.Adjusted-64000*0.999) > 1e-6 {
+		t.Errorf("expected stop below 64000, got %+v", adj)
+	}
+
+	// Short stop just below a round number moves above it
+	adj = adjustStop("short", 3095, 0.5, stopLevels("short", 3095, nil, nil))
+	if !adj.Changed() || math.Abs(adj.Adjusted-3100*1.001) > 1e-6 {
+		t.Errorf("expected stop above 3100, got %+v", adj)
+	}
+
+	// Level out of tolerance: unchanged
+	adj = adjustStop("long", 64500, 0.2, stopLevels("long", 64500, nil, nil))
+	if adj.Changed() {
+		t.Errorf("expected unchanged stop, got %+v", adj)
+	}
+
+	// Book wall below the stop is cleared, the stop never moves toward price
+	book := &OrderBook{Bids: []DepthLevel{{Price: 100.2, Quantity: 1}, {Price: 100.1, Quantity: 1}, {Price: 99.95, Quantity: 10}, {Price: 99.9, Quantity: 1}}}
+	adj = adjustStop("long", 100.05, 0.5, []stopLevel{{price: 100.2, kind: StopLevelBookWall}, {price: 99.95, kind: StopLevelBookWall}})
+	if adj.Level != 99.95 || adj.Adjusted >= 100.05 {
+		t.Errorf("expected stop below the 99.95 wall, got %+v", adj)
+	}
+	if walls := bookWalls(book.Bids); len(walls) != 1 || walls[0] != 99.95 {
+		t.Errorf("expected a single wall at 99.95, got %v", walls)
+	}
+
+	// Disabled
+	if adj := adjustStop("long", 64050, 0, stopLevels("long", 64050, nil, nil)); adj.Changed() {
+		t.Errorf("zero tolerance should disable snapping")
+	}
+}
+
+// TestVolumeNodes tests detection of high-volume price bins
+func TestVolumeNodes(t *testing.T) {
+	klines := []Kline{
+		{High: 101, Low: 99, Close: 100, Volume: 10},
+		{High: 111, Low: 109, Close: 110, Volume: 10},
+		{High: 121, Low: 119, Close: 120, Volume: 100},
+		{High: 131, Low: 129, Close: 130, Volume: 10},
+	}
+	nodes := volumeNodes(klines, 1)
+	if len(nodes) != 1 || nodes[0] != 120.5 {
+		t.Errorf("expected a node at 120.5, got %v", nodes)
+	}
+}
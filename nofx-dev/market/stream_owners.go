@@ -0,0 +1,103 @@
+package market
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// streamOwners kline and depth streams subscribed on demand and the traders using them
+// Streams of the startup subscription are never tracked, so they are never released
+type streamOwners struct {
+	mu      sync.Mutex
+	dynamic map[string]bool            // Streams subscribed on demand
+	owners  map[string]map[string]bool // Stream -> owner IDs
+}
+
+// klineStream stream name of a symbol's kline interval
+func klineStream(symbol, interval string) string {
+	return fmt.Sprintf("%s@kline_%s", strings.ToLower(symbol), interval)
+}
+
+// markDynamic records streams subscribed on demand
+func (o *streamOwners) markDynamic(streams []string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.dynamic == nil {
+		o.dynamic = make(map[string]bool)
+	}
+	for _, stream := range streams {
+		o.dynamic[stream] = true
+	}
+}
+
+// acquire records owner as a user of streams
+func (o *streamOwners) acquire(owner string, streams []string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.owners == nil {
+		o.owners = make(map[string]map[string]bool)
+	}
+	for _, stream := range streams {
+		if o.owners[stream] == nil {
+			o.owners[stream] = make(map[string]bool)
+		}
+		o.owners[stream][owner] = true
+	}
+}
+
+// release drops owner's claims, returns on-demand streams left without any owner (no longer tracked)
+func (o *streamOwners) release(owner string) []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var unused []string
+	for stream, owners := range o.owners {
+		if !owners[owner] {
+			continue
+		}
+		delete(owners, owner)
+		if len(owners) > 0 {
+			continue
+		}
+		delete(o.owners, stream)
+		if o.dynamic[stream] {
+			delete(o.dynamic, stream)
+			unused = append(unused, stream)
+		}
+	}
+	return unused
+}
+
+// Acquire records that owner (a trader ID) reads the kline streams of symbols × intervals and their depth streams
+func (m *WSMonitor) Acquire(owner string, symbols, intervals []string) {
+	streams := make([]string, 0, len(symbols)*(len(intervals)+1))
+	for _, symbol := range symbols {
+		for _, interval := range intervals {
+			streams = append(streams, klineStream(symbol, interval))
+		}
+		streams = append(streams, depthStream(symbol))
+	}
+	m.streams.acquire(owner, streams)
+}
+
+// Release drops owner's claims and unsubscribes on-demand streams no other owner uses, returns how many
+// Their caches are dropped, so the next GetCurrentKlines refetches and resubscribes
+func (m *WSMonitor) Release(owner string) int {
+	unused := m.streams.release(owner)
+	if len(unused) == 0 {
+		return 0
+	}
+	if err := m.combinedClient.unsubscribeStreams(unused); err != nil {
+		log.Printf("Warning: Failed to unsubscribe from %d stream(s): %v", len(unused), err)
+	}
+	for _, stream := range unused {
+		m.combinedClient.RemoveSubscriber(stream)
+		if symbol, interval, ok := strings.Cut(stream, "@kline_"); ok {
+			m.getKlineDataMap(interval).Delete(strings.ToUpper(symbol))
+		} else if symbol, _, ok := strings.Cut(stream, "@depth"); ok {
+			m.depthDataMap.Delete(strings.ToUpper(symbol))
+		}
+	}
+	return len(unused)
+}
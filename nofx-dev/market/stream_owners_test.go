@@ -0,0 +1,33 @@
+package market
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestStreamOwnersRelease(t *testing.T) {
+	var o streamOwners
+	o.markDynamic([]string{"btcusdt@kline_15m", "ethusdt@kline_15m"})
+	o.acquire("a", []string{"btcusdt@kline_15m", "ethusdt@kline_15m", "btcusdt@kline_3m"})
+	o.acquire("b", []string{"ethusdt@kline_15m"})
+
+	unused := o.release("a")
+	if len(unused) != 1 || unused[0] != "btcusdt@kline_15m" {
+		t.Fatalf("release(a) = %v, want only btcusdt@kline_15m (eth still owned, 3m not on demand)", unused)
+	}
+
+	unused = o.release("b")
+	sort.Strings(unused)
+	if len(unused) != 1 || unused[0] != "ethusdt@kline_15m" {
+		t.Fatalf("release(b) = %v, want ethusdt@kline_15m", unused)
+	}
+	if unused = o.release("b"); len(unused) != 0 {
+		t.Fatalf("second release(b) = %v, want none", unused)
+	}
+}
+
+func TestKlineStream(t *testing.T) {
+	if got := klineStream("BTCUSDT", "1h"); got != "btcusdt@kline_1h" {
+		t.Fatalf("klineStream = %q", got)
+	}
+}
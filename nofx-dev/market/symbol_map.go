@@ -0,0 +1,170 @@
+package market
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Venue identifiers (same values as the exchange_type of a stored exchange account)
+const (
+	VenueBinance     = "binance"
+	VenueBybit       = "bybit"
+	VenueOKX         = "okx"
+	VenueMEXC        = "mexc"
+	VenueCoinbase    = "coinbase"
+	VenueHyperliquid = "hyperliquid"
+	VenueAster       = "aster"
+	VenueLighter     = "lighter"
+)
+
+// Venues all venues with a symbol format, in display order
+var Venues = []string{VenueBinance, VenueBybit, VenueOKX, VenueMEXC, VenueCoinbase, VenueHyperliquid, VenueAster, VenueLighter}
+
+// Canonical symbols are Binance-style (BASEQUOTE, e.g. BTCUSDT, 1000PEPEUSDT), the format market data,
+// decisions and analytics are keyed by. Most venues derive their name from the canonical symbol by format
+// alone; symbolAliases lists the instruments whose base asset itself differs per venue
+var (
+	// symbolAliases canonical symbol -> venue -> venue-specific name
+	symbolAliases = map[string]map[string]string{
+		"1000PEPEUSDT":  {VenueHyperliquid: "kPEPE"},
+		"1000SHIBUSDT":  {VenueHyperliquid: "kSHIB"},
+		"1000BONKUSDT":  {VenueHyperliquid: "kBONK"},
+		"1000FLOKIUSDT": {VenueHyperliquid: "kFLOKI"},
+		"1000LUNCUSDT":  {VenueHyperliquid: "kLUNC"},
+	}
+	// symbolAliasesReverse venue -> venue-specific name -> canonical symbol
+	symbolAliasesReverse = buildReverseAliases(symbolAliases)
+	symbolAliasesMu      sync.RWMutex
+)
+
+// buildReverseAliases indexes the alias table by venue name
+func buildReverseAliases(aliases map[string]map[string]string) map[string]map[string]string {
+	reverse := make(map[string]map[string]string)
+	for canonical, venues := range aliases {
+		for venue, name := range venues {
+			if reverse[venue] == nil {
+				reverse[venue] = make(map[string]string)
+			}
+			reverse[venue][name] = canonical
+		}
+	}
+	return reverse
+}
+
+// RegisterSymbolAlias maps a canonical symbol to a venue-specific name, overriding the venue's default format
+func RegisterSymbolAlias(venue, canonical, venueSymbol string) {
+	venue = strings.ToLower(venue)
+	canonical = Normalize(canonical)
+
+	symbolAliasesMu.Lock()
+	defer symbolAliasesMu.Unlock()
+	if old, ok := symbolAliases[canonical][venue]; ok {
+		delete(symbolAliasesReverse[venue], old)
+	}
+	if symbolAliases[canonical] == nil {
+		symbolAliases[canonical] = make(map[string]string)
+	}
+	symbolAliases[canonical][venue] = venueSymbol
+	if symbolAliasesReverse[venue] == nil {
+		symbolAliasesReverse[venue] = make(map[string]string)
+	}
+	symbolAliasesReverse[venue][venueSymbol] = canonical
+}
+
+// VenueSymbol converts a canonical symbol to the instrument name used by venue
+// e.g. BTCUSDT -> BTC-USDT-SWAP (okx), BTC_USDT (mexc), BTC-PERP (coinbase), BTC (hyperliquid)
+// Unknown venues get the symbol unchanged
+func VenueSymbol(venue, symbol string) string {
+	venue = strings.ToLower(venue)
+
+	symbolAliasesMu.RLock()
+	alias, ok := symbolAliases[Normalize(symbol)][venue]
+	symbolAliasesMu.RUnlock()
+	if ok {
+		return alias
+	}
+
+	quote := QuoteAsset(symbol)
+	if quote == "" {
+		quote = DefaultQuoteAsset
+	}
+	switch venue {
+	case VenueOKX:
+		return BaseAsset(symbol) + "-" + quote + "-SWAP"
+	case VenueMEXC:
+		return BaseAsset(symbol) + "_" + quote
+	case VenueCoinbase:
+		// Single USDC-margined perp per base asset
+		return strings.ToUpper(BaseAsset(symbol)) + "-PERP"
+	case VenueHyperliquid:
+		// All perps are USDC-margined, case of the base is kept (kPEPE)
+		return BaseAsset(symbol)
+	default:
+		return symbol
+	}
+}
+
+// CanonicalSymbol converts a venue-specific instrument name back to its canonical symbol
+// e.g. BTC-USDT-SWAP (okx) -> BTCUSDT, kPEPE (hyperliquid) -> 1000PEPEUSDT
+// Venues without a quote in the name (coinbase, hyperliquid) map to DefaultQuoteAsset, the quote market data is keyed by
+func CanonicalSymbol(venue, venueSymbol string) string {
+	venue = strings.ToLower(venue)
+
+	symbolAliasesMu.RLock()
+	canonical, ok := symbolAliasesReverse[venue][venueSymbol]
+	symbolAliasesMu.RUnlock()
+	if ok {
+		return canonical
+	}
+
+	switch venue {
+	case VenueOKX:
+		parts := strings.Split(venueSymbol, "-")
+		if len(parts) >= 2 {
+			return parts[0] + parts[1]
+		}
+		return venueSymbol
+	case VenueMEXC:
+		return strings.ReplaceAll(venueSymbol, "_", "")
+	case VenueCoinbase:
+		return strings.TrimSuffix(venueSymbol, "-PERP") + DefaultQuoteAsset
+	case VenueHyperliquid:
+		return venueSymbol + DefaultQuoteAsset
+	default:
+		return venueSymbol
+	}
+}
+
+// SymbolMapping venue-specific names of one canonical symbol
+type SymbolMapping struct {
+	Symbol string            `json:"symbol"`
+	Venues map[string]string `json:"venues"`
+}
+
+// GetSymbolMapping returns the name of symbol on every known venue
+func GetSymbolMapping(symbol string) SymbolMapping {
+	canonical := Normalize(symbol)
+	mapping := SymbolMapping{Symbol: canonical, Venues: make(map[string]string, len(Venues))}
+	for _, venue := range Venues {
+		mapping.Venues[venue] = VenueSymbol(venue, canonical)
+	}
+	return mapping
+}
+
+// GetSymbolAliases returns all registered alias overrides, sorted by canonical symbol
+func GetSymbolAliases() []SymbolMapping {
+	symbolAliasesMu.RLock()
+	list := make([]SymbolMapping, 0, len(symbolAliases))
+	for canonical, venues := range symbolAliases {
+		copied := make(map[string]string, len(venues))
+		for venue, name := range venues {
+			copied[venue] = name
+		}
+		list = append(list, SymbolMapping{Symbol: canonical, Venues: copied})
+	}
+	symbolAliasesMu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Symbol < list[j].Symbol })
+	return list
+}
@@ -0,0 +1,51 @@
+package market
+
+import "testing"
+
+// TestVenueSymbolRoundTrip tests canonical <-> venue conversion including per-venue aliases
+func TestVenueSymbolRoundTrip(t *testing.T) {
+	tests := []struct {
+		venue     string
+		canonical string
+		venueSym  string
+	}{
+		{VenueBinance, "BTCUSDT", "BTCUSDT"},
+		{VenueBybit, "ETHUSDC", "ETHUSDC"},
+		{VenueOKX, "BTCUSDT", "BTC-USDT-SWAP"},
+		{VenueOKX, "ETHUSDC", "ETH-USDC-SWAP"},
+		{VenueMEXC, "1000PEPEUSDT", "1000PEPE_USDT"},
+		{VenueCoinbase, "SOLUSDT", "SOL-PERP"},
+		{VenueHyperliquid, "BTCUSDT", "BTC"},
+		{VenueHyperliquid, "1000PEPEUSDT", "kPEPE"},
+	}
+	for _, tt := range tests {
+		if got := VenueSymbol(tt.venue, tt.canonical); got != tt.venueSym {
+			t.Errorf("VenueSymbol(%s, %s) = %s, want %s", tt.venue, tt.canonical, got, tt.venueSym)
+		}
+		if got := CanonicalSymbol(tt.venue, tt.venueSym); got != tt.canonical {
+			t.Errorf("CanonicalSymbol(%s, %s) = %s, want %s", tt.venue, tt.venueSym, got, tt.canonical)
+		}
+	}
+}
+
+// TestRegisterSymbolAlias tests that a registered alias overrides the venue format in both directions
+func TestRegisterSymbolAlias(t *testing.T) {
+	RegisterSymbolAlias(VenueOKX, "1000SATSUSDT", "SATS-USDT-SWAP")
+	RegisterSymbolAlias(VenueOKX, "1000SATSUSDT", "1000SATS-USDT-SWAP")
+	defer func() {
+		symbolAliasesMu.Lock()
+		delete(symbolAliases, "1000SATSUSDT")
+		delete(symbolAliasesReverse[VenueOKX], "1000SATS-USDT-SWAP")
+		symbolAliasesMu.Unlock()
+	}()
+
+	if got := VenueSymbol(VenueOKX, "1000satsusdt"); got != "1000SATS-USDT-SWAP" {
+		t.Errorf("expected re-registered alias, got %s", got)
+	}
+	if got := CanonicalSymbol(VenueOKX, "SATS-USDT-SWAP"); got != "SATSUSDT" {
+		t.Errorf("replaced alias should fall back to the venue format, got %s", got)
+	}
+	if m := GetSymbolMapping("1000SATSUSDT"); m.Venues[VenueOKX] != "1000SATS-USDT-SWAP" || m.Venues[VenueBinance] != "1000SATSUSDT" {
+		t.Errorf("unexpected mapping: %+v", m)
+	}
+}
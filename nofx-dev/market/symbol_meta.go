@@ -0,0 +1,220 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"nofx/endpoint"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// symbolMetaCacheTTL how long the bulk metadata is reused (listings and filters rarely change)
+	symbolMetaCacheTTL = time.Hour
+	// bybitInstrumentsURL public linear instrument list, the source of max leverage
+	bybitInstrumentsURL = "https://api.bybit.com/v5/market/instruments-info?category=linear&limit=1000"
+)
+
+// Symbol categories (derived from Binance's underlying sub type)
+const (
+	CategoryMeme   = "meme"
+	CategoryL1     = "l1"
+	CategoryL2     = "l2"
+	CategoryDeFi   = "defi"
+	CategoryAI     = "ai"
+	CategoryGaming = "gaming"
+	CategoryOther  = "other"
+)
+
+// SymbolMeta static trading metadata of one perpetual contract
+type SymbolMeta struct {
+	Symbol      string    `json:"symbol"`
+	TickSize    float64   `json:"tick_size"`
+	StepSize    float64   `json:"step_size"`
+	MinNotional float64   `json:"min_notional,omitempty"`
+	MaxLeverage int       `json:"max_leverage,omitempty"` // 0 if unknown
+	LaunchDate  time.Time `json:"launch_date,omitempty"`
+	Category    string    `json:"category"`
+}
+
+// ListingAgeDays days since the contract launched (-1 if unknown)
+func (m *SymbolMeta) ListingAgeDays(now time.Time) int {
+	if m == nil || m.LaunchDate.IsZero() {
+		return -1
+	}
+	return int(now.Sub(m.LaunchDate).Hours() / 24)
+}
+
+var (
+	symbolMetaCache     map[string]*SymbolMeta
+	symbolMetaUpdatedAt time.Time
+	symbolMetaMu        sync.Mutex
+)
+
+// binanceSymbolMeta exchangeInfo fields used for metadata
+type binanceSymbolMeta struct {
+	Symbol            string                   `json:"symbol"`
+	Status            string                   `json:"status"`
+	ContractType      string                   `json:"contractType"`
+	OnboardDate       int64                    `json:"onboardDate"`
+	UnderlyingSubType []string                 `json:"underlyingSubType"`
+	Filters           []map[string]interface{} `json:"filters"`
+}
+
+// bybitInstrumentsResponse instruments-info fields used for metadata
+type bybitInstrumentsResponse struct {
+	RetCode int    `json:"retCode"`
+	RetMsg  string `json:"retMsg"`
+	Result  struct {
+		List []struct {
+			Symbol         string `json:"symbol"`
+			LeverageFilter struct {
+				MaxLeverage string `json:"maxLeverage"`
+			} `json:"leverageFilter"`
+		} `json:"list"`
+	} `json:"result"`
+}
+
+// symbolCategory maps Binance underlying sub types to a category
+func symbolCategory(subTypes []string) string {
+	for _, t := range subTypes {
+		switch strings.ToLower(strings.TrimSpace(t)) {
+		case "meme":
+			return CategoryMeme
+		case "layer-1", "layer1", "pow":
+			return CategoryL1
+		case "layer-2", "layer2":
+			return CategoryL2
+		case "defi":
+			return CategoryDeFi
+		case "ai":
+			return CategoryAI
+		case "gaming", "metaverse":
+			return CategoryGaming
+		}
+	}
+	return CategoryOther
+}
+
+// filterFloat reads a numeric field of the exchangeInfo filter with the given type
+func filterFloat(filters []map[string]interface{}, filterType, field string) float64 {
+	for _, f := range filters {
+		if f["filterType"] != filterType {
+			continue
+		}
+		v, _ := strconv.ParseFloat(fmt.Sprintf("%v", f[field]), 64)
+		return v
+	}
+	return 0
+}
+
+// parseBinanceSymbolMeta builds metadata of trading perpetual contracts from an exchangeInfo body
+func parseBinanceSymbolMeta(body []byte) (map[string]*SymbolMeta, error) {
+	var info struct {
+		Symbols []binanceSymbolMeta `json:"symbols"`
+	}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange info: %w", err)
+	}
+
+	metas := make(map[string]*SymbolMeta, len(info.Symbols))
+	for _, s := range info.Symbols {
+		if s.Status != "TRADING" || s.ContractType != "PERPETUAL" {
+			continue
+		}
+		meta := &SymbolMeta{
+			Symbol:      s.Symbol,
+			TickSize:    filterFloat(s.Filters, "PRICE_FILTER", "tickSize"),
+			StepSize:    filterFloat(s.Filters, "LOT_SIZE", "stepSize"),
+			MinNotional: filterFloat(s.Filters, "MIN_NOTIONAL", "notional"),
+			Category:    symbolCategory(s.UnderlyingSubType),
+		}
+		if s.OnboardDate > 0 {
+			meta.LaunchDate = time.UnixMilli(s.OnboardDate).UTC()
+		}
+		metas[s.Symbol] = meta
+	}
+	return metas, nil
+}
+
+// fetchMaxLeverage max leverage per symbol from Bybit's public instrument list
+// (Binance only exposes leverage brackets to signed requests)
+func fetchMaxLeverage() (map[string]int, error) {
+	client := endpoint.WrapClient(&http.Client{Timeout: 15 * time.Second})
+	resp, err := client.Get(bybitInstrumentsURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed bybitInstrumentsResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse instruments: %w", err)
+	}
+	if parsed.RetCode != 0 {
+		return nil, fmt.Errorf("instruments request failed: %s", parsed.RetMsg)
+	}
+	leverage := make(map[string]int, len(parsed.Result.List))
+	for _, inst := range parsed.Result.List {
+		if v, err := strconv.ParseFloat(inst.LeverageFilter.MaxLeverage, 64); err == nil && v > 0 {
+			leverage[inst.Symbol] = int(v)
+		}
+	}
+	return leverage, nil
+}
+
+// GetAllSymbolMeta returns metadata of every trading perpetual contract, refreshed hourly
+// Max leverage is best effort: it stays 0 when the leverage source is unreachable
+func GetAllSymbolMeta() (map[string]*SymbolMeta, error) {
+	symbolMetaMu.Lock()
+	defer symbolMetaMu.Unlock()
+	if symbolMetaCache != nil && time.Since(symbolMetaUpdatedAt) < symbolMetaCacheTTL {
+		return symbolMetaCache, nil
+	}
+
+	apiClient := NewAPIClient()
+	resp, err := apiClient.client.Get(fmt.Sprintf("%s/fapi/v1/exchangeInfo", baseURL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange info: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange info: %w", err)
+	}
+	metas, err := parseBinanceSymbolMeta(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if leverage, err := fetchMaxLeverage(); err == nil {
+		for symbol, meta := range metas {
+			meta.MaxLeverage = leverage[symbol]
+		}
+	}
+
+	symbolMetaCache = metas
+	symbolMetaUpdatedAt = time.Now()
+	return metas, nil
+}
+
+// GetSymbolMeta returns metadata of one symbol
+func GetSymbolMeta(symbol string) (*SymbolMeta, error) {
+	metas, err := GetAllSymbolMeta()
+	if err != nil {
+		return nil, err
+	}
+	meta, ok := metas[Normalize(symbol)]
+	if !ok {
+		return nil, fmt.Errorf("no metadata for %s", symbol)
+	}
+	return meta, nil
+}
@@ -0,0 +1,38 @@
+package market
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseBinanceSymbolMeta tests metadata extraction from exchangeInfo
+func TestParseBinanceSymbolMeta(t *testing.T) {
+	body := []byte(`{"symbols":[
+		{"symbol":"DOGEUSDT","status":"TRADING","contractType":"PERPETUAL","onboardDate":1569398400000,"underlyingSubType":["Meme"],
+		 "filters":[{"filterType":"PRICE_FILTER","tickSize":"0.000010"},{"filterType":"LOT_SIZE","stepSize":"1"},{"filterType":"MIN_NOTIONAL","notional":"5"}]},
+		{"symbol":"SOLUSDT","status":"TRADING","contractType":"PERPETUAL","underlyingSubType":["Layer-1"],"filters":[]},
+		{"symbol":"BTCUSDT_250627","status":"TRADING","contractType":"CURRENT_QUARTER","filters":[]},
+		{"symbol":"OLDUSDT","status":"SETTLING","contractType":"PERPETUAL","filters":[]}
+	]}`)
+
+	metas, err := parseBinanceSymbolMeta(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metas) != 2 {
+		t.Fatalf("expected only trading perpetuals, got %d", len(metas))
+	}
+	doge := metas["DOGEUSDT"]
+	if doge.TickSize != 0.00001 || doge.StepSize != 1 || doge.MinNotional != 5 || doge.Category != CategoryMeme {
+		t.Errorf("unexpected DOGE metadata: %+v", doge)
+	}
+	if !doge.LaunchDate.Equal(time.Date(2019, 9, 25, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected launch date: %v", doge.LaunchDate)
+	}
+	if metas["SOLUSDT"].Category != CategoryL1 || metas["SOLUSDT"].ListingAgeDays(time.Now()) != -1 {
+		t.Errorf("unexpected SOL metadata: %+v", metas["SOLUSDT"])
+	}
+	if symbolCategory(nil) != CategoryOther {
+		t.Error("symbols without sub type should be other")
+	}
+}
@@ -0,0 +1,70 @@
+package market
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RoundToTick rounds price to the nearest multiple of the symbol's tick size (unchanged when tick is unknown)
+func RoundToTick(price, tick float64) float64 {
+	if tick <= 0 || price == 0 {
+		return price
+	}
+	rounded := math.Round(price/tick) * tick
+	// Drop float noise (0.1 × 3 = 0.30000000000000004) so the price prints and serializes as the exchange expects
+	cleaned, err := strconv.ParseFloat(strconv.FormatFloat(rounded, 'f', TickDecimals(tick), 64), 64)
+	if err != nil {
+		return rounded
+	}
+	return cleaned
+}
+
+// TickDecimals decimal places of a tick size (0.01 → 2, 0.00001 → 5, 10 → 0)
+func TickDecimals(tick float64) int {
+	s := strconv.FormatFloat(tick, 'f', -1, 64)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
+
+// FormatPriceTick formats price rounded to the tick with the tick's decimals, dynamic precision when tick is unknown
+func FormatPriceTick(price, tick float64) string {
+	if tick <= 0 {
+		return formatPriceWithDynamicPrecision(price)
+	}
+	return strconv.FormatFloat(RoundToTick(price, tick), 'f', TickDecimals(tick), 64)
+}
+
+// FormatPrice formats a price of the symbol on its tick grid (dynamic precision without tick size)
+func (d *Data) FormatPrice(price float64) string {
+	return FormatPriceTick(price, d.TickSize)
+}
+
+// formatPriceSlice formats prices on the tick grid
+func formatPriceSlice(values []float64, tick float64) string {
+	strValues := make([]string, len(values))
+	for i, v := range values {
+		strValues[i] = FormatPriceTick(v, tick)
+	}
+	return "[" + strings.Join(strValues, ", ") + "]"
+}
+
+// cachedTickSize the symbol's tick size from already loaded exchangeInfo metadata (0 before the first load),
+// never fetches so building market data doesn't wait on exchangeInfo
+func cachedTickSize(symbol string) float64 {
+	symbolMetaMu.Lock()
+	defer symbolMetaMu.Unlock()
+	if meta, ok := symbolMetaCache[Normalize(symbol)]; ok {
+		return meta.TickSize
+	}
+	return 0
+}
+
+// setTickSize attaches the symbol's tick size for price formatting
+func setTickSize(data *Data) {
+	if data.TickSize == 0 {
+		data.TickSize = cachedTickSize(data.Symbol)
+	}
+}
@@ -0,0 +1,30 @@
+package market
+
+import "testing"
+
+// TestRoundToTick tests rounding and formatting prices on the tick grid
+func TestRoundToTick(t *testing.T) {
+	cases := []struct {
+		price, tick float64
+		rounded     float64
+		formatted   string
+	}{
+		{60123.456, 0.1, 60123.5, "60123.5"},
+		{0.30000000000000004, 0.1, 0.3, "0.3"},
+		{0.000012345678, 0.00000001, 0.00001235, "0.00001235"},
+		{2.5, 0.5, 2.5, "2.5"},
+		{1234.7, 10, 1230, "1230"},
+		{3.14159, 0, 3.14159, formatPriceWithDynamicPrecision(3.14159)}, // Tick unknown
+	}
+	for _, c := range cases {
+		if got := RoundToTick(c.price, c.tick); got != c.rounded {
+			t.Errorf("RoundToTick(%v, %v) = %v, want %v", c.price, c.tick, got, c.rounded)
+		}
+		if got := FormatPriceTick(c.price, c.tick); got != c.formatted {
+			t.Errorf("FormatPriceTick(%v, %v) = %q, want %q", c.price, c.tick, got, c.formatted)
+		}
+	}
+	if d := TickDecimals(0.00001); d != 5 {
+		t.Errorf("TickDecimals(0.00001) = %d, want 5", d)
+	}
+}
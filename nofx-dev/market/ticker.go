@@ -0,0 +1,126 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// tickerStream all-market rolling 24h ticker stream (symbols that changed, about once a second)
+	tickerStream = "!ticker@arr"
+	// tickerStaleAfter tickers not refreshed for this long are not served (stream down or symbol delisted)
+	tickerStaleAfter = 5 * time.Minute
+)
+
+// Ticker24h rolling 24h statistics of a symbol from the all-market ticker stream
+type Ticker24h struct {
+	Symbol           string    `json:"symbol"`
+	LastPrice        float64   `json:"last_price"`
+	PriceChangePct   float64   `json:"price_change_pct"`
+	High             float64   `json:"high"`
+	Low              float64   `json:"low"`
+	WeightedAvgPrice float64   `json:"weighted_avg_price"`
+	Volume           float64   `json:"volume"`       // Base asset
+	QuoteVolume      float64   `json:"quote_volume"` // Quote asset (USDT)
+	Trades           int       `json:"trades"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// RangePosition where the last price sits in the 24h range (0 = low, 1 = high)
+func (t *Ticker24h) RangePosition() float64 {
+	if t.High <= t.Low {
+		return 0.5
+	}
+	return (t.LastPrice - t.Low) / (t.High - t.Low)
+}
+
+// Summary one line for prompts, e.g. "change +2.35%, high 101.2, low 97.8 (price at 71% of range), volume 1.2M, quote volume $120.50M, 385210 trades"
+func (t *Ticker24h) Summary() string {
+	return fmt.Sprintf("change %+.2f%%, high %s, low %s (price at %.0f%% of range), volume %s, quote volume $%.2fM, %d trades",
+		t.PriceChangePct, formatPriceWithDynamicPrecision(t.High), formatPriceWithDynamicPrecision(t.Low),
+		t.RangePosition()*100, formatCompact(t.Volume), t.QuoteVolume/1e6, t.Trades)
+}
+
+// formatCompact formats a quantity with a K / M / B suffix
+func formatCompact(v float64) string {
+	switch {
+	case v >= 1e9:
+		return fmt.Sprintf("%.2fB", v/1e9)
+	case v >= 1e6:
+		return fmt.Sprintf("%.2fM", v/1e6)
+	case v >= 1e3:
+		return fmt.Sprintf("%.2fK", v/1e3)
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+// parseTicker converts a ticker stream entry
+func parseTicker(w TickerWSData) *Ticker24h {
+	t := &Ticker24h{Symbol: strings.ToUpper(w.Symbol), Trades: w.Count, UpdatedAt: time.Now()}
+	t.LastPrice, _ = strconv.ParseFloat(w.LastPrice, 64)
+	t.PriceChangePct, _ = strconv.ParseFloat(w.PriceChangePercent, 64)
+	t.High, _ = strconv.ParseFloat(w.HighPrice, 64)
+	t.Low, _ = strconv.ParseFloat(w.LowPrice, 64)
+	t.WeightedAvgPrice, _ = strconv.ParseFloat(w.WeightedAvgPrice, 64)
+	t.Volume, _ = strconv.ParseFloat(w.Volume, 64)
+	t.QuoteVolume, _ = strconv.ParseFloat(w.QuoteVolume, 64)
+	if w.EventTime > 0 {
+		t.UpdatedAt = time.UnixMilli(w.EventTime)
+	}
+	return t
+}
+
+// subscribeTickers subscribes to the all-market 24h ticker stream
+func (m *WSMonitor) subscribeTickers() error {
+	ch := m.combinedClient.AddSubscriber(tickerStream, 100)
+	go m.handleTickerData(ch)
+	return m.combinedClient.subscribeStreams([]string{tickerStream})
+}
+
+func (m *WSMonitor) handleTickerData(ch <-chan []byte) {
+	for data := range ch {
+		m.processTickers(data)
+	}
+}
+
+// processTickers stores the tickers of one stream message (an array of the symbols that changed)
+func (m *WSMonitor) processTickers(data []byte) {
+	var tickers []TickerWSData
+	if err := json.Unmarshal(data, &tickers); err != nil {
+		log.Printf("Failed to parse ticker data: %v", err)
+		return
+	}
+	for _, w := range tickers {
+		t := parseTicker(w)
+		m.tickerDataMap.Store(t.Symbol, t)
+	}
+}
+
+// Ticker24h returns the symbol's rolling 24h statistics, nil when the ticker stream hasn't delivered it recently
+func (m *WSMonitor) Ticker24h(symbol string) *Ticker24h {
+	value, ok := m.tickerDataMap.Load(strings.ToUpper(symbol))
+	if !ok {
+		return nil
+	}
+	t := value.(*Ticker24h)
+	if time.Since(t.UpdatedAt) > tickerStaleAfter {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
+
+// setTicker24h attaches the symbol's 24h statistics from the ticker stream
+func setTicker24h(data *Data) {
+	if WSMonitorCli == nil {
+		return
+	}
+	if t := WSMonitorCli.Ticker24h(data.Symbol); t != nil {
+		data.Ticker24h = t
+		data.PriceChange24h = t.PriceChangePct
+	}
+}
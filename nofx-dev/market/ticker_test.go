@@ -0,0 +1,36 @@
+package market
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestProcessTickers tests the all-market ticker stream payload and staleness of served tickers
+func TestProcessTickers(t *testing.T) {
+	m := &WSMonitor{}
+	now := time.Now().UnixMilli()
+	payload := `[
+		{"e":"24hrTicker","E":` + strconv.FormatInt(now, 10) + `,"s":"SOLUSDT","p":"2.30","P":"2.35","w":"99.5","c":"100.2","o":"97.9","h":"101.2","l":"97.8","v":"1200000","q":"120500000","n":385210},
+		{"e":"24hrTicker","E":` + strconv.FormatInt(now-(10*time.Minute).Milliseconds(), 10) + `,"s":"OLDUSDT","c":"1","h":"1","l":"1"}
+	]`
+	m.processTickers([]byte(payload))
+
+	sol := m.Ticker24h("solusdt")
+	if sol == nil {
+		t.Fatal("SOL ticker not stored")
+	}
+	if sol.PriceChangePct != 2.35 || sol.High != 101.2 || sol.Low != 97.8 || sol.QuoteVolume != 120500000 || sol.Trades != 385210 {
+		t.Errorf("unexpected ticker: %+v", sol)
+	}
+	if pos := sol.RangePosition(); pos < 0.70 || pos > 0.72 {
+		t.Errorf("RangePosition() = %.3f, want ~0.706", pos)
+	}
+	if s := sol.Summary(); !strings.Contains(s, "change +2.35%") || !strings.Contains(s, "volume 1.20M") || !strings.Contains(s, "quote volume $120.50M") {
+		t.Errorf("unexpected summary: %s", s)
+	}
+	if m.Ticker24h("OLDUSDT") != nil {
+		t.Error("ticker older than tickerStaleAfter should not be served")
+	}
+}
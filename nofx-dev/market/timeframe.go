@@ -20,6 +20,7 @@ var supportedTimeframes = map[string]time.Duration{
 	"6h":  6 * time.Hour,
 	"12h": 12 * time.Hour,
 	"1d":  24 * time.Hour,
+	"1w":  7 * 24 * time.Hour,
 }
 
 // NormalizeTimeframe normalizes the incoming timeframe string (case-insensitive, no spaces), and validates if it's supported.
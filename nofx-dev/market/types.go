@@ -12,11 +12,41 @@ type Data struct {
 	CurrentMACD       float64
 	CurrentRSI7       float64
 	OpenInterest      *OIData
-	FundingRate       float64
+	FundingRate       float64 // Predicted rate of the next settlement
 	IntradaySeries    *IntradayData
 	LongerTermContext *LongerTermData
 	// Multi-timeframe data (new)
 	TimeframeData map[string]*TimeframeSeriesData `json:"timeframe_data,omitempty"`
+	// OrderBook spread, top-N liquidity and imbalance (set by callers that need it, see GetOrderBookMetrics)
+	OrderBook *OrderBookMetrics `json:"order_book,omitempty"`
+	// Liquidations forced orders over the last minutes from the liquidation stream, nil when there were none
+	Liquidations *LiquidationStats `json:"liquidations,omitempty"`
+	// FundingHistory last settled funding rates and the predicted next one, nil when funding couldn't be fetched
+	FundingHistory *FundingRateHistory `json:"funding_history,omitempty"`
+	// WeeklyContext macro trend structure from 1w klines (set by callers that need it, see GetWeeklyContext)
+	WeeklyContext *WeeklyContext `json:"weekly_context,omitempty"`
+	// Bollinger Bands (20, 2σ) of the primary series, nil with fewer than 20 klines
+	Bollinger *BollingerBands `json:"bollinger,omitempty"`
+	// VWAP rolling 24h session VWAP and VWAP anchored at the daily open, nil without volume
+	VWAP *VWAP `json:"vwap,omitempty"`
+	// StochRSI (14, 14, 3, 3) of the primary series, nil without enough klines
+	StochRSI *StochRSI `json:"stoch_rsi,omitempty"`
+	// RSI divergence over the last 30 candles: price lower low with RSI higher low (bullish) / higher high with RSI lower high (bearish)
+	RSIBullishDivergence bool `json:"rsi_bullish_divergence"`
+	RSIBearishDivergence bool `json:"rsi_bearish_divergence"`
+	// VolumeProfile POC, value area and high-volume nodes of the primary series, nil without volume
+	VolumeProfile *VolumeProfile `json:"volume_profile,omitempty"`
+	// Ticker24h rolling 24h high / low / volume from the ticker stream, nil until the stream delivered the symbol
+	Ticker24h      *Ticker24h `json:"ticker_24h,omitempty"`
+	PriceChange24h float64    `json:"price_change_24h"` // 24h price change percentage (0 without Ticker24h)
+	// TickSize price tick from exchangeInfo (0 until metadata is loaded), prices are formatted on this grid
+	TickSize float64 `json:"tick_size,omitempty"`
+	// MarkPrice mark / index / last price and the basis between them, nil until the mark price stream delivered the symbol
+	MarkPrice *MarkPrice `json:"mark_price,omitempty"`
+	// ReceivedAt last update of the primary kline series, Stale when that is DataStaleAfter or longer ago
+	ReceivedAt time.Time     `json:"received_at,omitempty"`
+	DataAge    time.Duration `json:"data_age,omitempty"`
+	Stale      bool          `json:"stale,omitempty"`
 }
 
 // KlineBar single kline bar with OHLCV data
@@ -46,7 +76,20 @@ type TimeframeSeriesData struct {
 // OIData Open Interest data
 type OIData struct {
 	Latest  float64
-	Average float64
+	Average float64 // Mean of the last 24h of samples
+
+	// Change of OI over each window (%), Has* is false until the history covers the window
+	Change1h     float64
+	Change4h     float64
+	Change24h    float64
+	HasChange1h  bool
+	HasChange4h  bool
+	HasChange24h bool
+
+	// Divergence OIDivergencePriceUpOIDown / OIDivergencePriceDownOIUp when price and OI moved apart
+	// over the last DivergenceWindowHours (4h, or 1h with less history), empty otherwise
+	Divergence            string
+	DivergenceWindowHours int
 }
 
 // IntradayData intraday data (3-minute interval)
@@ -112,10 +155,32 @@ type Ticker24hr struct {
 	Symbol             string `json:"symbol"`
 	PriceChange        string `json:"priceChange"`
 	PriceChangePercent string `json:"priceChangePercent"`
+	LastPrice          string `json:"lastPrice"`
 	Volume             string `json:"volume"`
 	QuoteVolume        string `json:"quoteVolume"`
 }
 
+// DepthResponse Binance order book response
+type DepthResponse struct {
+	LastUpdateID int64      `json:"lastUpdateId"`
+	Bids         [][]string `json:"bids"`
+	Asks         [][]string `json:"asks"`
+}
+
+// DepthLevel single order book price level
+type DepthLevel struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// OrderBook order book snapshot (bids descending, asks ascending)
+type OrderBook struct {
+	Symbol    string       `json:"symbol"`
+	Bids      []DepthLevel `json:"bids"`
+	Asks      []DepthLevel `json:"asks"`
+	UpdatedAt time.Time    `json:"updated_at,omitempty"` // When the snapshot was taken (set for WebSocket monitor books)
+}
+
 // SymbolFeatures feature data structure
 type SymbolFeatures struct {
 	Symbol           string    `json:"symbol"`
@@ -0,0 +1,128 @@
+package market
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Volume profile parameters
+const (
+	volumeProfileBins      = 40   // Price bins between the lowest low and highest high of the window
+	volumeProfileValueArea = 0.70 // Share of volume inside the value area
+	hvnMinRatio            = 1.5  // A high-volume node holds at least this multiple of the mean bin volume
+	maxHighVolumeNodes     = 3
+)
+
+// VolumeProfile volume traded at each price over the recent klines, as support / resistance references
+type VolumeProfile struct {
+	POC             float64   `json:"poc"`               // Point of control: price with the most volume
+	ValueAreaHigh   float64   `json:"value_area_high"`   // Upper bound of the 70% value area
+	ValueAreaLow    float64   `json:"value_area_low"`    // Lower bound of the 70% value area
+	HighVolumeNodes []float64 `json:"high_volume_nodes"` // Other local volume peaks, highest volume first
+	Bars            int       `json:"bars"`              // Klines the profile was built from
+}
+
+// Summary one line for prompts, e.g. "POC 100.2, value area 98.7 - 101.5, high-volume nodes 103.1, 96.4 (last 100 candles)"
+func (v *VolumeProfile) Summary() string {
+	s := fmt.Sprintf("POC %s, value area %s - %s", formatPriceWithDynamicPrecision(v.POC),
+		formatPriceWithDynamicPrecision(v.ValueAreaLow), formatPriceWithDynamicPrecision(v.ValueAreaHigh))
+	if len(v.HighVolumeNodes) > 0 {
+		nodes := make([]string, len(v.HighVolumeNodes))
+		for i, p := range v.HighVolumeNodes {
+			nodes[i] = formatPriceWithDynamicPrecision(p)
+		}
+		s += ", high-volume nodes " + strings.Join(nodes, ", ")
+	}
+	return s + fmt.Sprintf(" (last %d candles)", v.Bars)
+}
+
+// calculateVolumeProfile spreads each kline's volume evenly over the bins its high-low range covers,
+// nil without volume or price range
+func calculateVolumeProfile(klines []Kline) *VolumeProfile {
+	if len(klines) == 0 {
+		return nil
+	}
+	lo, hi := klines[0].Low, klines[0].High
+	for _, k := range klines {
+		lo, hi = min(lo, k.Low), max(hi, k.High)
+	}
+	if hi <= lo {
+		return nil
+	}
+	step := (hi - lo) / volumeProfileBins
+	bin := func(price float64) int {
+		return min(int((price-lo)/step), volumeProfileBins-1)
+	}
+	center := func(i int) float64 {
+		return lo + (float64(i)+0.5)*step
+	}
+
+	volumes := make([]float64, volumeProfileBins)
+	total := 0.0
+	for _, k := range klines {
+		if k.Volume <= 0 {
+			continue
+		}
+		from, to := bin(k.Low), bin(k.High)
+		share := k.Volume / float64(to-from+1)
+		for i := from; i <= to; i++ {
+			volumes[i] += share
+		}
+		total += k.Volume
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	poc := 0
+	for i, v := range volumes {
+		if v > volumes[poc] {
+			poc = i
+		}
+	}
+
+	// Value area: grow from the POC toward the heavier neighbouring bin until it holds 70% of the volume
+	low, high := poc, poc
+	inArea := volumes[poc]
+	for inArea < total*volumeProfileValueArea && (low > 0 || high < volumeProfileBins-1) {
+		below, above := -1.0, -1.0
+		if low > 0 {
+			below = volumes[low-1]
+		}
+		if high < volumeProfileBins-1 {
+			above = volumes[high+1]
+		}
+		if above >= below {
+			high++
+			inArea += above
+		} else {
+			low--
+			inArea += below
+		}
+	}
+
+	profile := &VolumeProfile{
+		POC:           center(poc),
+		ValueAreaHigh: lo + float64(high+1)*step,
+		ValueAreaLow:  lo + float64(low)*step,
+		Bars:          len(klines),
+	}
+
+	// High-volume nodes: local peaks well above the mean bin volume, other than the POC
+	mean := total / volumeProfileBins
+	var nodes []int
+	for i, v := range volumes {
+		if i == poc || v < mean*hvnMinRatio {
+			continue
+		}
+		if (i == 0 || v > volumes[i-1]) && (i == volumeProfileBins-1 || v >= volumes[i+1]) {
+			nodes = append(nodes, i)
+		}
+	}
+	sort.Slice(nodes, func(a, b int) bool { return volumes[nodes[a]] > volumes[nodes[b]] })
+	for _, i := range nodes[:min(len(nodes), maxHighVolumeNodes)] {
+		profile.HighVolumeNodes = append(profile.HighVolumeNodes, center(i))
+	}
+	return profile
+}
@@ -0,0 +1,44 @@
+package market
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestCalculateVolumeProfile tests the POC, the value area around it and a secondary high-volume node
+func TestCalculateVolumeProfile(t *testing.T) {
+	// Range 100-140, heavy trading around 110, a smaller cluster around 130, thin elsewhere
+	var klines []Kline
+	klines = append(klines, Kline{Low: 100, High: 140, Close: 120, Volume: 40}) // 1 per bin
+	for i := 0; i < 10; i++ {
+		klines = append(klines, Kline{Low: 109.5, High: 110.5, Close: 110, Volume: 100})
+	}
+	for i := 0; i < 4; i++ {
+		klines = append(klines, Kline{Low: 129.5, High: 130.5, Close: 130, Volume: 100})
+	}
+
+	vp := calculateVolumeProfile(klines)
+	if vp == nil {
+		t.Fatal("expected a profile")
+	}
+	if math.Abs(vp.POC-110) > 1 {
+		t.Errorf("POC = %v, want about 110", vp.POC)
+	}
+	if vp.ValueAreaLow > 109.5 || vp.ValueAreaHigh < 110.5 || vp.ValueAreaHigh > 130 {
+		t.Errorf("value area %v - %v should hold the 110 cluster only", vp.ValueAreaLow, vp.ValueAreaHigh)
+	}
+	if len(vp.HighVolumeNodes) == 0 || math.Abs(vp.HighVolumeNodes[0]-130) > 1 {
+		t.Errorf("expected a high-volume node near 130, got %v", vp.HighVolumeNodes)
+	}
+	if vp.Bars != len(klines) || !strings.HasPrefix(vp.Summary(), "POC ") {
+		t.Errorf("unexpected profile: %+v / %s", vp, vp.Summary())
+	}
+
+	if calculateVolumeProfile([]Kline{{Low: 100, High: 100, Volume: 5}}) != nil {
+		t.Error("a profile needs a price range")
+	}
+	if calculateVolumeProfile([]Kline{{Low: 100, High: 110}}) != nil {
+		t.Error("a profile needs volume")
+	}
+}
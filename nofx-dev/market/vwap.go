@@ -0,0 +1,138 @@
+package market
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// vwapSessionWindow rolling session of the session VWAP (crypto trades around the clock, there is no session close)
+const vwapSessionWindow = 24 * time.Hour
+
+// VWAP volume-weighted average prices of the typical price (high + low + close) / 3
+type VWAP struct {
+	Session        float64   `json:"session"`          // Rolling 24h VWAP
+	SessionFrom    time.Time `json:"session_from"`     // Oldest bar in the session VWAP (later than 24h ago until enough bars were seen)
+	Anchored       float64   `json:"anchored"`         // VWAP anchored at the daily open (00:00 UTC)
+	AnchorTime     time.Time `json:"anchor_time"`      // Daily open the anchored VWAP starts from
+	SessionDevPct  float64   `json:"session_dev_pct"`  // (price - session VWAP) / session VWAP in %
+	AnchoredDevPct float64   `json:"anchored_dev_pct"` // (price - anchored VWAP) / anchored VWAP in %
+}
+
+// Summary one line for prompts, e.g. "session (24h) 100.5 (price +0.40%), anchored (daily open 00:00 UTC) 100.1 (price +0.80%)"
+func (v *VWAP) Summary() string {
+	return fmt.Sprintf("session (24h) %s (price %+.2f%%), anchored (daily open %s UTC) %s (price %+.2f%%)",
+		formatPriceWithDynamicPrecision(v.Session), v.SessionDevPct,
+		v.AnchorTime.UTC().Format("01-02 15:04"), formatPriceWithDynamicPrecision(v.Anchored), v.AnchoredDevPct)
+}
+
+// vwapBar volume-weighted contribution of one closed bar
+type vwapBar struct {
+	openTime int64
+	pv       float64 // typical price × volume
+	vol      float64
+}
+
+// vwapState incremental VWAP of one kline series: closed bars are added once, bars leaving the session window are
+// subtracted, so the VWAPs keep covering bars that already rolled out of the kline cache
+type vwapState struct {
+	mu       sync.Mutex
+	bars     []vwapBar // Closed bars in the session window, oldest first
+	sessPV   float64
+	sessVol  float64
+	anchor   int64 // Daily open (ms) of the anchored sums
+	anchPV   float64
+	anchVol  float64
+	lastOpen int64 // Newest closed bar added
+}
+
+var vwapStates sync.Map // map[string]*vwapState, keyed by symbol@timeframe
+
+// vwapStateFor returns the VWAP state of the symbol's timeframe series, creating it on first use
+func vwapStateFor(symbol, timeframe string) *vwapState {
+	state, _ := vwapStates.LoadOrStore(symbol+"@"+timeframe, &vwapState{})
+	return state.(*vwapState)
+}
+
+// dailyOpen 00:00 UTC of the day containing ms
+func dailyOpen(ms int64) int64 {
+	day := int64(24 * time.Hour / time.Millisecond)
+	return ms - ms%day
+}
+
+func vwapContribution(k Kline) vwapBar {
+	typical := (k.High + k.Low + k.Close) / 3
+	return vwapBar{openTime: k.OpenTime, pv: typical * k.Volume, vol: k.Volume}
+}
+
+// update adds the closed bars not seen yet (all but the last kline, which is still forming) and returns the VWAPs
+// including the forming bar, nil without volume
+func (s *vwapState) update(klines []Kline) *VWAP {
+	if len(klines) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, k := range klines[:len(klines)-1] {
+		if k.OpenTime <= s.lastOpen {
+			continue
+		}
+		bar := vwapContribution(k)
+		s.bars = append(s.bars, bar)
+		s.sessPV += bar.pv
+		s.sessVol += bar.vol
+		if day := dailyOpen(k.OpenTime); day != s.anchor {
+			s.anchor, s.anchPV, s.anchVol = day, 0, 0
+		}
+		s.anchPV += bar.pv
+		s.anchVol += bar.vol
+		s.lastOpen = k.OpenTime
+	}
+
+	forming := klines[len(klines)-1]
+	cutoff := forming.OpenTime - vwapSessionWindow.Milliseconds()
+	i := 0
+	for i < len(s.bars) && s.bars[i].openTime <= cutoff {
+		s.sessPV -= s.bars[i].pv
+		s.sessVol -= s.bars[i].vol
+		i++
+	}
+	s.bars = s.bars[i:]
+	if len(s.bars) == 0 {
+		s.sessPV, s.sessVol = 0, 0 // Drop accumulated rounding error
+	}
+
+	// The forming bar counts toward both, a new day starts the anchored VWAP over
+	cur := vwapContribution(forming)
+	anchor := dailyOpen(forming.OpenTime)
+	anchPV, anchVol := s.anchPV, s.anchVol
+	if anchor != s.anchor {
+		anchPV, anchVol = 0, 0
+	}
+	anchPV += cur.pv
+	anchVol += cur.vol
+	sessPV, sessVol := s.sessPV+cur.pv, s.sessVol+cur.vol
+	if sessVol <= 0 || anchVol <= 0 {
+		return nil
+	}
+
+	v := &VWAP{
+		Session:     sessPV / sessVol,
+		SessionFrom: time.UnixMilli(forming.OpenTime),
+		Anchored:    anchPV / anchVol,
+		AnchorTime:  time.UnixMilli(anchor),
+	}
+	if len(s.bars) > 0 {
+		v.SessionFrom = time.UnixMilli(s.bars[0].openTime)
+	}
+	v.SessionDevPct = pctChange(v.Session, forming.Close)
+	v.AnchoredDevPct = pctChange(v.Anchored, forming.Close)
+	return v
+}
+
+// CalculateVWAP computes the session and anchored VWAP of a kline series in one pass (no cached state), for
+// backtests and strategies working on their own series
+func CalculateVWAP(klines []Kline) *VWAP {
+	return (&vwapState{}).update(klines)
+}
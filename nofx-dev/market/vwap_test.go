@@ -0,0 +1,72 @@
+package market
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// vwapKlines hourly bars starting at start with the given typical prices and volumes
+func vwapKlines(start time.Time, prices, volumes []float64) []Kline {
+	klines := make([]Kline, len(prices))
+	for i := range prices {
+		open := start.Add(time.Duration(i) * time.Hour).UnixMilli()
+		klines[i] = Kline{OpenTime: open, High: prices[i], Low: prices[i], Close: prices[i], Volume: volumes[i],
+			CloseTime: open + time.Hour.Milliseconds() - 1}
+	}
+	return klines
+}
+
+// TestCalculateVWAP tests volume weighting and the daily anchor
+func TestCalculateVWAP(t *testing.T) {
+	// 22:00, 23:00 on day one, 00:00 and 01:00 (forming) on day two
+	start := time.Date(2025, 1, 1, 22, 0, 0, 0, time.UTC)
+	klines := vwapKlines(start, []float64{100, 110, 120, 130}, []float64{1, 1, 2, 2})
+
+	v := CalculateVWAP(klines)
+	if want := (100 + 110 + 240 + 260) / 6.0; math.Abs(v.Session-want) > 1e-9 {
+		t.Errorf("session VWAP = %v, want %v", v.Session, want)
+	}
+	if v.Anchored != 125 || !v.AnchorTime.Equal(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("anchored VWAP = %v from %v, want 125 from the daily open", v.Anchored, v.AnchorTime)
+	}
+	if math.Abs(v.AnchoredDevPct-4) > 1e-9 || !v.SessionFrom.Equal(start) {
+		t.Errorf("unexpected deviation %v / session start %v", v.AnchoredDevPct, v.SessionFrom)
+	}
+	if !strings.Contains(v.Summary(), "anchored (daily open 01-02 00:00 UTC) 125") {
+		t.Errorf("unexpected summary: %s", v.Summary())
+	}
+	if CalculateVWAP(vwapKlines(start, []float64{100}, []float64{0})) != nil {
+		t.Error("no volume should produce no VWAP")
+	}
+}
+
+// TestVWAPStateIncremental tests that bars which rolled out of the kline cache keep counting until they leave the
+// 24h session, and that a new day resets the anchored VWAP
+func TestVWAPStateIncremental(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	prices := make([]float64, 30)
+	volumes := make([]float64, 30)
+	for i := range prices {
+		prices[i], volumes[i] = 100+float64(i), 1
+	}
+	all := vwapKlines(start, prices, volumes)
+
+	var s vwapState
+	for end := 5; end <= len(all); end++ {
+		from := end - 5 // The cache only ever holds the latest 5 bars
+		if v := s.update(all[from:end]); v == nil {
+			t.Fatalf("expected a VWAP after %d bars", end)
+		}
+	}
+	v := s.update(all[len(all)-5:])
+
+	// Forming bar at 05:00 on day two: the session covers 05:00 day one .. 05:00 day two, the anchor 00:00 .. 05:00
+	if want := CalculateVWAP(all[5:]).Session; math.Abs(v.Session-want) > 1e-9 {
+		t.Errorf("session VWAP = %v, want %v over the last 24h", v.Session, want)
+	}
+	if want := (124.0 + 125 + 126 + 127 + 128 + 129) / 6; math.Abs(v.Anchored-want) > 1e-9 {
+		t.Errorf("anchored VWAP = %v, want %v since the daily open", v.Anchored, want)
+	}
+}
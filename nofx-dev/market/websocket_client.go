@@ -68,6 +68,17 @@ type TickerWSData struct {
 	Count              int    `json:"n"`
 }
 
+type MarkPriceWSData struct {
+	EventType       string `json:"e"`
+	EventTime       int64  `json:"E"`
+	Symbol          string `json:"s"`
+	MarkPrice       string `json:"p"`
+	IndexPrice      string `json:"i"`
+	EstSettlePrice  string `json:"P"`
+	FundingRate     string `json:"r"`
+	NextFundingTime int64  `json:"T"`
+}
+
 func NewWSClient() *WSClient {
 	return &WSClient{
 		subscribers: make(map[string]chan []byte),
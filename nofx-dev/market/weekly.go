@@ -0,0 +1,112 @@
+package market
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// weeklyKlineCount weekly bars fetched per symbol (enough for EMA50)
+	weeklyKlineCount = 60
+	// weeklyRecentCloses weekly closes included in the context
+	weeklyRecentCloses = 8
+	// weeklyCacheTTL weekly structure barely moves within an hour
+	weeklyCacheTTL = time.Hour
+)
+
+// WeeklyContext macro trend structure from 1w klines, for swing-style prompts
+type WeeklyContext struct {
+	EMA20        float64   `json:"ema20"`         // 0 with fewer than 20 weeks of history
+	EMA50        float64   `json:"ema50"`         // 0 with fewer than 50 weeks of history
+	ATR14        float64   `json:"atr14"`         // 0 with fewer than 15 weeks of history
+	RecentCloses []float64 `json:"recent_closes"` // Last weeklyRecentCloses closes, oldest first (the last week is still open)
+	Trend        string    `json:"trend"`         // "uptrend" / "downtrend" / "range"
+}
+
+// weeklyCache weekly context per symbol
+type weeklyCache struct {
+	context   *WeeklyContext
+	updatedAt time.Time
+}
+
+var weeklyCacheMap sync.Map // map[string]*weeklyCache
+
+// calculateWeeklyContext computes the weekly context, nil without at least two weekly bars
+func calculateWeeklyContext(klines []Kline) *WeeklyContext {
+	if len(klines) < 2 {
+		return nil
+	}
+	ctx := &WeeklyContext{
+		EMA20: calculateEMA(klines, 20),
+		EMA50: calculateEMA(klines, 50),
+		ATR14: calculateATR(klines, 14),
+	}
+	start := len(klines) - weeklyRecentCloses
+	if start < 0 {
+		start = 0
+	}
+	for _, k := range klines[start:] {
+		ctx.RecentCloses = append(ctx.RecentCloses, k.Close)
+	}
+	ctx.Trend = weeklyTrend(klines[len(klines)-1].Close, ctx.EMA20, ctx.EMA50)
+	return ctx
+}
+
+// weeklyTrend uptrend when price > EMA20 > EMA50, downtrend when price < EMA20 < EMA50 (EMA50 is skipped while it is
+// unavailable), range otherwise
+func weeklyTrend(price, ema20, ema50 float64) string {
+	if ema20 <= 0 {
+		return "range"
+	}
+	switch {
+	case price > ema20 && (ema50 <= 0 || ema20 > ema50):
+		return "uptrend"
+	case price < ema20 && (ema50 <= 0 || ema20 < ema50):
+		return "downtrend"
+	}
+	return "range"
+}
+
+// Summary one line for prompts, e.g. "trend uptrend, EMA20 61000, EMA50 52000, ATR14 4200, closes 58000, 60500, 63000 (oldest → latest)"
+func (w *WeeklyContext) Summary() string {
+	closes := make([]string, len(w.RecentCloses))
+	for i, c := range w.RecentCloses {
+		closes[i] = formatPriceWithDynamicPrecision(c)
+	}
+	parts := []string{"trend " + w.Trend}
+	if w.EMA20 > 0 {
+		parts = append(parts, "EMA20 "+formatPriceWithDynamicPrecision(w.EMA20))
+	}
+	if w.EMA50 > 0 {
+		parts = append(parts, "EMA50 "+formatPriceWithDynamicPrecision(w.EMA50))
+	}
+	if w.ATR14 > 0 {
+		parts = append(parts, "ATR14 "+formatPriceWithDynamicPrecision(w.ATR14))
+	}
+	parts = append(parts, fmt.Sprintf("closes %s (oldest → latest)", strings.Join(closes, ", ")))
+	return strings.Join(parts, ", ")
+}
+
+// GetWeeklyContext retrieves the weekly context of symbol (optimized: uses 1-hour cache)
+func GetWeeklyContext(symbol string) (*WeeklyContext, error) {
+	symbol = Normalize(symbol)
+	if cached, ok := weeklyCacheMap.Load(symbol); ok {
+		cache := cached.(*weeklyCache)
+		if time.Since(cache.updatedAt) < weeklyCacheTTL {
+			return cache.context, nil
+		}
+	}
+
+	klines, err := NewAPIClient().GetKlines(symbol, "1w", weeklyKlineCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weekly klines for %s: %w", symbol, err)
+	}
+	ctx := calculateWeeklyContext(klines)
+	if ctx == nil {
+		return nil, fmt.Errorf("%s has less than two weeks of history", symbol)
+	}
+	weeklyCacheMap.Store(symbol, &weeklyCache{context: ctx, updatedAt: time.Now()})
+	return ctx, nil
+}
@@ -0,0 +1,49 @@
+package market
+
+import (
+	"strings"
+	"testing"
+)
+
+// weeklyKlines n weekly bars with closes moving by step from start
+func weeklyKlines(n int, start, step float64) []Kline {
+	klines := make([]Kline, n)
+	for i := range klines {
+		c := start + step*float64(i)
+		klines[i] = Kline{Open: c - step, High: c + 2, Low: c - 2, Close: c}
+	}
+	return klines
+}
+
+// TestCalculateWeeklyContext tests EMA / ATR availability by history length, recent closes and the trend label
+func TestCalculateWeeklyContext(t *testing.T) {
+	ctx := calculateWeeklyContext(weeklyKlines(60, 100, 10))
+	if ctx.EMA20 <= ctx.EMA50 || ctx.EMA50 <= 0 || ctx.ATR14 <= 0 {
+		t.Fatalf("unexpected indicators for a steady rise: %+v", ctx)
+	}
+	if len(ctx.RecentCloses) != weeklyRecentCloses || ctx.RecentCloses[weeklyRecentCloses-1] != 690 {
+		t.Errorf("unexpected recent closes: %v", ctx.RecentCloses)
+	}
+	if ctx.Trend != "uptrend" {
+		t.Errorf("expected uptrend, got %s", ctx.Trend)
+	}
+	if s := ctx.Summary(); !strings.HasPrefix(s, "trend uptrend, EMA20 ") || !strings.Contains(s, "(oldest → latest)") {
+		t.Errorf("unexpected summary: %s", s)
+	}
+
+	// A young listing has no EMA50 yet, the trend follows EMA20
+	ctx = calculateWeeklyContext(weeklyKlines(25, 500, -5))
+	if ctx.EMA50 != 0 || ctx.Trend != "downtrend" {
+		t.Errorf("expected a downtrend without EMA50, got %+v", ctx)
+	}
+	if ctx = calculateWeeklyContext(weeklyKlines(5, 100, 1)); ctx.Trend != "range" || len(ctx.RecentCloses) != 5 {
+		t.Errorf("expected range with five weeks of history, got %+v", ctx)
+	}
+	if calculateWeeklyContext(weeklyKlines(1, 100, 1)) != nil {
+		t.Error("a single weekly bar should not produce a context")
+	}
+
+	if _, err := TFDuration("1w"); err != nil {
+		t.Errorf("1w should be a supported timeframe: %v", err)
+	}
+}
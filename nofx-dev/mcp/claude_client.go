@@ -4,12 +4,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 const (
 	ProviderClaude       = "claude"
 	DefaultClaudeBaseURL = "https://api.anthropic.com/v1"
 	DefaultClaudeModel   = "claude-opus-4-5-20251101"
+
+	// claudeMaxTemperature Messages API temperature range is 0-1
+	claudeMaxTemperature = 1.0
 )
 
 type ClaudeClient struct {
@@ -78,28 +82,189 @@ func (c *ClaudeClient) buildUrl() string {
 	return fmt.Sprintf("%s/messages", c.BaseURL)
 }
 
-// buildMCPRequestBody Claude has different request format
+// buildMCPRequestBody Claude has different request format (system prompt is a top-level field, not a message)
 func (c *ClaudeClient) buildMCPRequestBody(systemPrompt, userPrompt string) map[string]any {
 	requestBody := map[string]any{
-		"model":      c.Model,
-		"max_tokens": c.MaxTokens,
-		"system":     systemPrompt,
+		"model":       c.Model,
+		"max_tokens":  c.MaxTokens,
+		"temperature": min(c.config.Temperature, claudeMaxTemperature),
 		"messages": []map[string]string{
 			{"role": "user", "content": userPrompt},
 		},
 	}
+	if systemPrompt != "" {
+		requestBody["system"] = systemPrompt
+	}
 
 	return requestBody
 }
 
-// parseMCPResponse Claude has different response format
+// buildRequestBodyFromRequest maps a builder request to the Messages API: system messages move to the system
+// field, stop becomes stop_sequences, tools use input_schema and OpenAI-only penalties are dropped
+func (c *ClaudeClient) buildRequestBodyFromRequest(req *Request) map[string]any {
+	system, messages := claudeMessages(req.Messages)
+
+	maxTokens := c.MaxTokens
+	if req.MaxTokens != nil {
+		maxTokens = *req.MaxTokens
+	}
+	temperature := c.config.Temperature
+	if req.Temperature != nil {
+		temperature = *req.Temperature
+	}
+
+	requestBody := map[string]any{
+		"model":       req.Model,
+		"max_tokens":  maxTokens,
+		"temperature": min(temperature, claudeMaxTemperature),
+		"messages":    messages,
+	}
+	if hasToolMessages(req.Messages) {
+		requestBody["messages"] = claudeToolMessages(req.Messages)
+	}
+	if system != "" {
+		requestBody["system"] = system
+	}
+	if req.TopP != nil {
+		requestBody["top_p"] = *req.TopP
+	}
+	if len(req.Stop) > 0 {
+		requestBody["stop_sequences"] = req.Stop
+	}
+	if len(req.Tools) > 0 {
+		tools := make([]map[string]any, 0, len(req.Tools))
+		for _, tool := range req.Tools {
+			schema := tool.Function.Parameters
+			if schema == nil {
+				schema = map[string]any{"type": "object", "properties": map[string]any{}}
+			}
+			tools = append(tools, map[string]any{
+				"name":         tool.Function.Name,
+				"description":  tool.Function.Description,
+				"input_schema": schema,
+			})
+		}
+		requestBody["tools"] = tools
+	}
+	switch req.ToolChoice {
+	case "auto", "any", "none":
+		requestBody["tool_choice"] = map[string]string{"type": req.ToolChoice}
+	case "required":
+		requestBody["tool_choice"] = map[string]string{"type": "any"}
+	}
+	if req.Stream {
+		requestBody["stream"] = true
+	}
+
+	return requestBody
+}
+
+// claudeMessages splits system messages from the conversation and merges consecutive messages of the same role
+// (the Messages API requires alternating user / assistant turns)
+func claudeMessages(msgs []Message) (string, []map[string]string) {
+	var system []string
+	messages := make([]map[string]string, 0, len(msgs))
+	for _, msg := range msgs {
+		if msg.Role == "system" {
+			system = append(system, msg.Content)
+			continue
+		}
+		if n := len(messages); n > 0 && messages[n-1]["role"] == msg.Role {
+			messages[n-1]["content"] += "\n\n" + msg.Content
+			continue
+		}
+		messages = append(messages, map[string]string{"role": msg.Role, "content": msg.Content})
+	}
+	return strings.Join(system, "\n\n"), messages
+}
+
+// claudeToolMessages conversation with tool calls: assistant tool calls become tool_use blocks and tool results
+// tool_result blocks of a user turn (system messages are sent in the system field)
+func claudeToolMessages(msgs []Message) []map[string]any {
+	messages := make([]map[string]any, 0, len(msgs))
+	for _, msg := range msgs {
+		var role string
+		var blocks []map[string]any
+		switch {
+		case msg.Role == "system":
+			continue
+		case msg.Role == "tool":
+			role = "user"
+			blocks = []map[string]any{{"type": "tool_result", "tool_use_id": msg.ToolCallID, "content": msg.Content}}
+		default:
+			role = msg.Role
+			if msg.Content != "" {
+				blocks = append(blocks, map[string]any{"type": "text", "text": msg.Content})
+			}
+			for _, call := range msg.ToolCalls {
+				input := json.RawMessage(call.Function.Arguments)
+				if !json.Valid(input) {
+					input = json.RawMessage("{}")
+				}
+				blocks = append(blocks, map[string]any{"type": "tool_use", "id": call.ID, "name": call.Function.Name, "input": input})
+			}
+		}
+		// Consecutive turns of one role are merged (tool results of parallel calls share one user turn)
+		if n := len(messages); n > 0 && messages[n-1]["role"] == role {
+			messages[n-1]["content"] = append(messages[n-1]["content"].([]map[string]any), blocks...)
+			continue
+		}
+		messages = append(messages, map[string]any{"role": role, "content": blocks})
+	}
+	return messages
+}
+
+// parseToolResponse text blocks are the answer, tool_use blocks the tool calls
+func (c *ClaudeClient) parseToolResponse(body []byte) (*Response, error) {
+	var response struct {
+		Content []struct {
+			Type  string          `json:"type"`
+			Text  string          `json:"text"`
+			ID    string          `json:"id"`
+			Name  string          `json:"name"`
+			Input json.RawMessage `json:"input"`
+		} `json:"content"`
+		Error *struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse Claude response: %w, body: %s", err, string(body))
+	}
+	if response.Error != nil {
+		return nil, fmt.Errorf("Claude API error: %s - %s", response.Error.Type, response.Error.Message)
+	}
+
+	result := &Response{}
+	var texts []string
+	for _, content := range response.Content {
+		switch content.Type {
+		case "text":
+			texts = append(texts, content.Text)
+		case "tool_use":
+			result.ToolCalls = append(result.ToolCalls, ToolCall{
+				ID:       content.ID,
+				Type:     "function",
+				Function: ToolCallFunction{Name: content.Name, Arguments: string(content.Input)},
+			})
+		}
+	}
+	result.Content = strings.Join(texts, "")
+	return result, nil
+}
+
+// parseMCPResponse Claude has different response format: the answer is the text blocks of content
+// (thinking / tool_use blocks are skipped), split when the model interleaves them
 func (c *ClaudeClient) parseMCPResponse(body []byte) (string, error) {
 	var response struct {
 		Content []struct {
 			Type string `json:"type"`
 			Text string `json:"text"`
 		} `json:"content"`
-		Error *struct {
+		StopReason string `json:"stop_reason"`
+		Error      *struct {
 			Type    string `json:"type"`
 			Message string `json:"message"`
 		} `json:"error"`
@@ -117,12 +282,20 @@ func (c *ClaudeClient) parseMCPResponse(body []byte) (string, error) {
 		return "", fmt.Errorf("Claude returned empty content, body: %s", string(body))
 	}
 
-	// Find text content
+	var texts []string
 	for _, content := range response.Content {
 		if content.Type == "text" {
-			return content.Text, nil
+			texts = append(texts, content.Text)
 		}
 	}
+	if len(texts) == 0 {
+		return "", fmt.Errorf("no text content in Claude response")
+	}
+
+	// A response cut at max_tokens usually ends inside the JSON decisions
+	if response.StopReason == "max_tokens" {
+		c.logger.Warnf("⚠️ Claude response truncated at max_tokens (%d), JSON output may be incomplete, raise AI_MAX_TOKENS", c.MaxTokens)
+	}
 
-	return "", fmt.Errorf("no text content in Claude response")
+	return strings.Join(texts, ""), nil
 }
@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// ============================================================
+// Test ClaudeClient Messages API Mapping
+// ============================================================
+
+func TestClaudeClient_CallWithMessages_Success(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	var sent map[string]any
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		_ = json.Unmarshal(body, &sent)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body: io.NopCloser(bytes.NewBufferString(`{"content":[{"type":"thinking","thinking":"..."},` +
+				`{"type":"text","text":"<decision>[{\"symbol\":\"BTCUSDT\","},{"type":"text","text":"\"action\":\"wait\"}]</decision>"}],` +
+				`"stop_reason":"end_turn"}`)),
+		}, nil
+	}
+
+	client := NewClaudeClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewMockLogger()),
+		WithAPIKey("sk-ant-test"),
+		WithTemperature(1.5),
+	)
+
+	result, err := client.CallWithMessages("system prompt", "user prompt")
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if result != `<decision>[{"symbol":"BTCUSDT","action":"wait"}]</decision>` {
+		t.Errorf("text blocks should be joined, got '%s'", result)
+	}
+
+	req := mockHTTP.GetLastRequest()
+	if req.URL.String() != DefaultClaudeBaseURL+"/messages" {
+		t.Errorf("unexpected URL '%s'", req.URL.String())
+	}
+	if req.Header.Get("x-api-key") != "sk-ant-test" || req.Header.Get("anthropic-version") == "" {
+		t.Errorf("unexpected auth headers: %v", req.Header)
+	}
+	if sent["system"] != "system prompt" || sent["temperature"] != 1.0 {
+		t.Errorf("system prompt should be top-level and temperature capped at 1, got %v", sent)
+	}
+	if messages := sent["messages"].([]any); len(messages) != 1 {
+		t.Errorf("only the user prompt should be a message, got %v", messages)
+	}
+}
+
+func TestClaudeClient_BuildRequestBodyFromRequest(t *testing.T) {
+	client := NewClaudeClientWithOptions(WithLogger(NewMockLogger())).(*ClaudeClient)
+
+	req := NewRequestBuilder().
+		WithModel("claude-test").
+		AddSystemMessage("You are a trader").
+		AddSystemMessage("Answer in JSON").
+		AddUserMessage("Hello").
+		AddUserMessage("BTC?").
+		AddAssistantMessage("Sure").
+		WithFrequencyPenalty(0.5).
+		AddStopSequence("</decision>").
+		MustBuild()
+
+	body := client.buildRequestBodyFromRequest(req)
+
+	if body["system"] != "You are a trader\n\nAnswer in JSON" {
+		t.Errorf("system messages should be merged into system, got %v", body["system"])
+	}
+	messages := body["messages"].([]map[string]string)
+	if len(messages) != 2 || messages[0]["content"] != "Hello\n\nBTC?" || messages[1]["role"] != "assistant" {
+		t.Errorf("consecutive user messages should be merged, got %v", messages)
+	}
+	if _, ok := body["frequency_penalty"]; ok {
+		t.Error("frequency_penalty isn't supported by the Messages API")
+	}
+	if stops, ok := body["stop_sequences"].([]string); !ok || stops[0] != "</decision>" {
+		t.Errorf("stop should map to stop_sequences, got %v", body["stop_sequences"])
+	}
+}
+
+func TestClaudeClient_ParseResponseErrors(t *testing.T) {
+	client := NewClaudeClientWithOptions(WithLogger(NewMockLogger())).(*ClaudeClient)
+
+	if _, err := client.parseMCPResponse([]byte(`{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`)); err == nil {
+		t.Error("API errors should be returned")
+	}
+	if _, err := client.parseMCPResponse([]byte(`{"content":[{"type":"tool_use","id":"x"}]}`)); err == nil {
+		t.Error("responses without text should fail")
+	}
+
+	logger := NewMockLogger()
+	client.logger = logger
+	if _, err := client.parseMCPResponse([]byte(`{"content":[{"type":"text","text":"[{"}],"stop_reason":"max_tokens"}`)); err != nil {
+		t.Fatalf("truncated responses should still return their text: %v", err)
+	}
+	if len(logger.GetLogsByLevel("WARN")) == 0 {
+		t.Error("truncated responses should be warned about")
+	}
+}
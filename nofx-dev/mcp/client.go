@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"nofx/endpoint"
 	"strings"
 	"time"
 )
@@ -50,6 +51,8 @@ type Client struct {
 	// When DeepSeekClient embeds Client, hooks point to DeepSeekClient
 	// This way methods called in call() are automatically dispatched to the overridden version in subclass
 	hooks clientHooks
+
+	usage *usageCounter // Tokens spent since the last TakeUsage
 }
 
 // New creates default client (backward compatible)
@@ -97,6 +100,7 @@ func NewClient(opts ...ClientOption) AIClient {
 		httpClient: cfg.HTTPClient,
 		logger:     cfg.Logger,
 		config:     cfg,
+		usage:      &usageCounter{},
 	}
 
 	// 4. Set default Provider (if not set)
@@ -127,15 +131,36 @@ func (client *Client) SetAPIKey(apiKey, apiURL, customModel string) {
 	}
 
 	client.Model = customModel
+
+	// Local OpenAI-compatible servers (llama.cpp, LM Studio...) are much slower than hosted APIs
+	if IsLocalURL(apiURL) && client.httpClient.Timeout == DefaultTimeout {
+		client.httpClient.Timeout = DefaultLocalTimeout
+		client.logger.Infof("🔧 [MCP] Local AI server %s, timeout raised to %v", client.BaseURL, DefaultLocalTimeout)
+	}
 }
 
 func (client *Client) SetTimeout(timeout time.Duration) {
 	client.httpClient.Timeout = timeout
 }
 
+// PromptTokenBudget tokens the prompt may use within the model's context window after reserving the
+// response (AI_CONTEXT_WINDOW), 0 = unlimited
+func (client *Client) PromptTokenBudget() int {
+	window := client.config.ContextWindow
+	if window <= 0 {
+		return 0
+	}
+	return max(window-client.MaxTokens, window/2)
+}
+
+// do sends a request, rewritten to the preferred region when the provider's host is part of an endpoint pool
+func (client *Client) do(req *http.Request) (*http.Response, error) {
+	return endpoint.WrapClient(client.httpClient).Do(req)
+}
+
 // CallWithMessages template method - fixed retry flow (cannot be overridden)
 func (client *Client) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
-	if client.APIKey == "" {
+	if client.APIKey == "" && RequiresAPIKey(client.Provider, client.BaseURL) {
 		return "", fmt.Errorf("AI API key not set, please call SetAPIKey first")
 	}
 
@@ -234,6 +259,29 @@ func (client *Client) parseMCPResponse(body []byte) (string, error) {
 	return result.Choices[0].Message.Content, nil
 }
 
+// parseToolResponse OpenAI format: tool calls are listed next to the content of the first choice
+func (client *Client) parseToolResponse(body []byte) (*Response, error) {
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content   string     `json:"content"`
+				ToolCalls []ToolCall `json:"tool_calls"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("API returned empty response")
+	}
+
+	message := result.Choices[0].Message
+	return &Response{Content: message.Content, ToolCalls: message.ToolCalls}, nil
+}
+
 func (client *Client) buildUrl() string {
 	if client.UseFullURL {
 		return client.BaseURL
@@ -285,7 +333,7 @@ func (client *Client) call(systemPrompt, userPrompt string) (string, error) {
 	}
 
 	// Step 5: Send HTTP request (fixed logic)
-	resp, err := client.httpClient.Do(req)
+	resp, err := client.do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to send request: %w", err)
 	}
@@ -304,6 +352,7 @@ func (client *Client) call(systemPrompt, userPrompt string) (string, error) {
 
 	// Step 8: Parse response (via hooks for dynamic dispatch)
 	result, err := client.hooks.parseMCPResponse(body)
+	client.recordUsage(body, systemPrompt+userPrompt, result)
 	if err != nil {
 		return "", fmt.Errorf("fail to parse AI server response: %w", err)
 	}
@@ -348,8 +397,31 @@ func (client *Client) isRetryableError(err error) bool {
 //       Build()
 //   result, err := client.CallWithRequest(request)
 func (client *Client) CallWithRequest(req *Request) (string, error) {
-	if client.APIKey == "" {
-		return "", fmt.Errorf("AI API key not set, please call SetAPIKey first")
+	var result string
+	err := client.requestWithRetry(req, func(body []byte) (err error) {
+		result, err = client.hooks.parseMCPResponse(body)
+		client.recordUsage(body, requestText(req), result)
+		return err
+	})
+	return result, err
+}
+
+// CallWithTools sends a request offering req.Tools and returns the answer text together with the tool calls the
+// model made (empty when it answered directly). Tool results go back as NewToolResultMessage in the next request
+func (client *Client) CallWithTools(req *Request) (*Response, error) {
+	var resp *Response
+	err := client.requestWithRetry(req, func(body []byte) (err error) {
+		resp, err = client.hooks.parseToolResponse(body)
+		client.recordUsage(body, requestText(req), responseText(resp))
+		return err
+	})
+	return resp, err
+}
+
+// requestWithRetry sends the request with the fixed retry flow, parse reads the response body
+func (client *Client) requestWithRetry(req *Request, parse func(body []byte) error) error {
+	if client.APIKey == "" && RequiresAPIKey(client.Provider, client.BaseURL) {
+		return fmt.Errorf("AI API key not set, please call SetAPIKey first")
 	}
 
 	// If Model is not set in Request, use Client's Model
@@ -367,18 +439,18 @@ func (client *Client) CallWithRequest(req *Request) (string, error) {
 		}
 
 		// Call single request
-		result, err := client.callWithRequest(req)
+		err := client.callWithRequest(req, parse)
 		if err == nil {
 			if attempt > 1 {
 				client.logger.Infof("✓ AI API retry succeeded")
 			}
-			return result, nil
+			return nil
 		}
 
 		lastErr = err
 		// Check if error is retryable
 		if !client.hooks.isRetryableError(err) {
-			return "", err
+			return err
 		}
 
 		// Wait before retry
@@ -389,22 +461,22 @@ func (client *Client) CallWithRequest(req *Request) (string, error) {
 		}
 	}
 
-	return "", fmt.Errorf("still failed after %d retries: %w", maxRetries, lastErr)
+	return fmt.Errorf("still failed after %d retries: %w", maxRetries, lastErr)
 }
 
 // callWithRequest single AI API call (using Request object)
-func (client *Client) callWithRequest(req *Request) (string, error) {
+func (client *Client) callWithRequest(req *Request, parse func(body []byte) error) error {
 	// Print current AI configuration
 	client.logger.Infof("📡 [%s] Request AI Server with Builder: BaseURL: %s", client.String(), client.BaseURL)
 	client.logger.Debugf("[%s] Messages count: %d", client.String(), len(req.Messages))
 
-	// Build request body (from Request object)
-	requestBody := client.buildRequestBodyFromRequest(req)
+	// Build request body (from Request object, via hooks for dynamic dispatch)
+	requestBody := client.hooks.buildRequestBodyFromRequest(req)
 
 	// Serialize request body
 	jsonData, err := client.hooks.marshalRequestBody(requestBody)
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	// Build URL
@@ -414,34 +486,33 @@ func (client *Client) callWithRequest(req *Request) (string, error) {
 	// Create HTTP request
 	httpReq, err := client.hooks.buildRequest(url, jsonData)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Send HTTP request
-	resp, err := client.httpClient.Do(httpReq)
+	resp, err := client.do(httpReq)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Read response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// Check HTTP status code
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API returned error (status %d): %s", resp.StatusCode, string(body))
+		return fmt.Errorf("API returned error (status %d): %s", resp.StatusCode, string(body))
 	}
 
 	// Parse response
-	result, err := client.hooks.parseMCPResponse(body)
-	if err != nil {
-		return "", fmt.Errorf("fail to parse AI server response: %w", err)
+	if err := parse(body); err != nil {
+		return fmt.Errorf("fail to parse AI server response: %w", err)
 	}
 
-	return result, nil
+	return nil
 }
 
 // buildRequestBodyFromRequest builds request body from Request object
@@ -492,6 +563,11 @@ func (client *Client) buildRequestBodyFromRequest(req *Request) map[string]any {
 		requestBody["stop"] = req.Stop
 	}
 
+	// Tool-calling conversations carry tool_calls / tool_call_id, which Message already serializes in this format
+	if hasToolMessages(req.Messages) {
+		requestBody["messages"] = req.Messages
+	}
+
 	if len(req.Tools) > 0 {
 		requestBody["tools"] = req.Tools
 	}
@@ -21,6 +21,8 @@ type Config struct {
 	MaxTokens   int
 	Temperature float64
 	UseFullURL  bool
+	// ContextWindow model context window in tokens for small local models (0 = model default / unlimited)
+	ContextWindow int
 
 	// Retry configuration
 	MaxRetries     int
@@ -40,6 +42,7 @@ func DefaultConfig() *Config {
 	return &Config{
 		// Default values
 		MaxTokens:      getEnvInt("AI_MAX_TOKENS", 2000),
+		ContextWindow:  getEnvInt("AI_CONTEXT_WINDOW", 0),
 		Temperature:    MCPClientTemperature,
 		MaxRetries:     MaxRetryTimes,
 		RetryWaitBase:  2 * time.Second,
@@ -13,6 +13,11 @@ type AIClient interface {
 	CallWithRequest(req *Request) (string, error) // Builder pattern API (supports advanced features)
 }
 
+// ToolCaller AI clients supporting native tool / function calling (every client built on Client)
+type ToolCaller interface {
+	CallWithTools(req *Request) (*Response, error)
+}
+
 // clientHooks internal hook interface (for subclass to override specific steps)
 // These methods are only used inside the package to implement dynamic dispatch
 type clientHooks interface {
@@ -21,10 +26,12 @@ type clientHooks interface {
 	call(systemPrompt, userPrompt string) (string, error)
 
 	buildMCPRequestBody(systemPrompt, userPrompt string) map[string]any
+	buildRequestBodyFromRequest(req *Request) map[string]any
 	buildUrl() string
 	buildRequest(url string, jsonData []byte) (*http.Request, error)
 	setAuthHeader(reqHeaders http.Header)
 	marshalRequestBody(requestBody map[string]any) ([]byte, error)
 	parseMCPResponse(body []byte) (string, error)
+	parseToolResponse(body []byte) (*Response, error)
 	isRetryableError(err error) bool
 }
@@ -261,6 +261,11 @@ func (m *MockClientHooks) buildMCPRequestBody(systemPrompt, userPrompt string) m
 	}
 }
 
+func (m *MockClientHooks) buildRequestBodyFromRequest(req *Request) map[string]any {
+	m.BuildRequestBodyCalled++
+	return map[string]any{"model": req.Model, "messages": req.Messages}
+}
+
 func (m *MockClientHooks) buildUrl() string {
 	m.BuildUrlCalled++
 	if m.BuildUrlFunc != nil {
@@ -290,6 +295,11 @@ func (m *MockClientHooks) parseMCPResponse(body []byte) (string, error) {
 	return "mocked response", nil
 }
 
+func (m *MockClientHooks) parseToolResponse(body []byte) (*Response, error) {
+	m.ParseResponseCalled++
+	return &Response{Content: "mocked response"}, nil
+}
+
 func (m *MockClientHooks) isRetryableError(err error) bool {
 	m.IsRetryableErrorCalled++
 	if m.IsRetryableErrorFunc != nil {
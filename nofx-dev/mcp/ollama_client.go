@@ -0,0 +1,254 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	ProviderOllama       = "ollama"
+	DefaultOllamaBaseURL = "http://localhost:11434"
+	DefaultOllamaModel   = "qwen2.5:14b"
+
+	// DefaultLocalTimeout local models on consumer hardware can take minutes to answer a full decision prompt
+	DefaultLocalTimeout = 10 * time.Minute
+)
+
+// OllamaClient local Ollama server over its native chat API (/api/chat), which unlike the OpenAI-compatible
+// endpoint accepts the context window (num_ctx). No API key required
+type OllamaClient struct {
+	*Client
+}
+
+// NewOllamaClient creates Ollama client (backward compatible)
+func NewOllamaClient() AIClient {
+	return NewOllamaClientWithOptions()
+}
+
+// NewOllamaClientWithOptions creates Ollama client (supports options pattern)
+func NewOllamaClientWithOptions(opts ...ClientOption) AIClient {
+	// 1. Create Ollama preset options
+	ollamaOpts := []ClientOption{
+		WithProvider(ProviderOllama),
+		WithModel(DefaultOllamaModel),
+		WithBaseURL(DefaultOllamaBaseURL),
+		WithTimeout(DefaultLocalTimeout),
+	}
+
+	// 2. Merge user options (user options have higher priority)
+	allOpts := append(ollamaOpts, opts...)
+
+	// 3. Create base client
+	baseClient := NewClient(allOpts...).(*Client)
+
+	// 4. Create Ollama client
+	ollamaClient := &OllamaClient{
+		Client: baseClient,
+	}
+
+	// 5. Set hooks to point to OllamaClient (implement dynamic dispatch)
+	baseClient.hooks = ollamaClient
+
+	return ollamaClient
+}
+
+func (c *OllamaClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	c.APIKey = apiKey
+
+	if customURL != "" {
+		// Accept the OpenAI-compatible URL users copy from other tools
+		c.BaseURL = strings.TrimSuffix(strings.TrimSuffix(customURL, "/"), "/v1")
+		c.logger.Infof("🔧 [MCP] Ollama using custom BaseURL: %s", c.BaseURL)
+	} else {
+		c.logger.Infof("🔧 [MCP] Ollama using default BaseURL: %s", c.BaseURL)
+	}
+	if customModel != "" {
+		c.Model = customModel
+		c.logger.Infof("🔧 [MCP] Ollama using custom Model: %s", customModel)
+	} else {
+		c.logger.Infof("🔧 [MCP] Ollama using default Model: %s", c.Model)
+	}
+	if c.config.ContextWindow > 0 {
+		c.logger.Infof("🔧 [MCP] Ollama context window: %d tokens", c.config.ContextWindow)
+	}
+}
+
+// setAuthHeader only sent when a key is configured (e.g. Ollama behind an authenticating proxy)
+func (c *OllamaClient) setAuthHeader(reqHeaders http.Header) {
+	if c.APIKey != "" {
+		c.Client.setAuthHeader(reqHeaders)
+	}
+}
+
+// buildUrl Ollama native chat endpoint
+func (c *OllamaClient) buildUrl() string {
+	if c.UseFullURL {
+		return c.BaseURL
+	}
+	return fmt.Sprintf("%s/api/chat", c.BaseURL)
+}
+
+// buildMCPRequestBody Ollama takes sampling parameters and the context window under options
+func (c *OllamaClient) buildMCPRequestBody(systemPrompt, userPrompt string) map[string]any {
+	var messages []Message
+	if systemPrompt != "" {
+		messages = append(messages, NewSystemMessage(systemPrompt))
+	}
+	messages = append(messages, NewUserMessage(userPrompt))
+	return c.chatBody(c.Model, messages, c.config.Temperature, c.MaxTokens)
+}
+
+// buildRequestBodyFromRequest maps a builder request onto the native chat API
+func (c *OllamaClient) buildRequestBodyFromRequest(req *Request) map[string]any {
+	temperature := c.config.Temperature
+	if req.Temperature != nil {
+		temperature = *req.Temperature
+	}
+	maxTokens := c.MaxTokens
+	if req.MaxTokens != nil {
+		maxTokens = *req.MaxTokens
+	}
+	body := c.chatBody(req.Model, req.Messages, temperature, maxTokens)
+	options := body["options"].(map[string]any)
+	if req.TopP != nil {
+		options["top_p"] = *req.TopP
+	}
+	if len(req.Stop) > 0 {
+		options["stop"] = req.Stop
+	}
+	if len(req.Tools) > 0 {
+		body["tools"] = req.Tools
+	}
+	if hasToolMessages(req.Messages) {
+		body["messages"] = ollamaToolMessages(req.Messages)
+	}
+	return body
+}
+
+// ollamaToolMessages the native API takes tool call arguments as an object and matches results by order
+func ollamaToolMessages(msgs []Message) []map[string]any {
+	messages := make([]map[string]any, 0, len(msgs))
+	for _, msg := range msgs {
+		m := map[string]any{"role": msg.Role, "content": msg.Content}
+		if len(msg.ToolCalls) > 0 {
+			calls := make([]map[string]any, 0, len(msg.ToolCalls))
+			for _, call := range msg.ToolCalls {
+				args := map[string]any{}
+				_ = json.Unmarshal([]byte(call.Function.Arguments), &args)
+				calls = append(calls, map[string]any{"function": map[string]any{"name": call.Function.Name, "arguments": args}})
+			}
+			m["tool_calls"] = calls
+		}
+		messages = append(messages, m)
+	}
+	return messages
+}
+
+// chatBody non-streaming chat request, num_ctx only when a context window is configured (0 = model default)
+func (c *OllamaClient) chatBody(model string, messages []Message, temperature float64, maxTokens int) map[string]any {
+	options := map[string]any{
+		"temperature": temperature,
+		"num_predict": maxTokens,
+	}
+	if c.config.ContextWindow > 0 {
+		options["num_ctx"] = c.config.ContextWindow
+	}
+	return map[string]any{
+		"model":    model,
+		"messages": messages,
+		"stream":   false,
+		"options":  options,
+	}
+}
+
+// parseMCPResponse Ollama native response format
+func (c *OllamaClient) parseMCPResponse(body []byte) (string, error) {
+	var response struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		DoneReason string `json:"done_reason"`
+		Error      string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("failed to parse Ollama response: %w, body: %s", err, string(body))
+	}
+	if response.Error != "" {
+		return "", fmt.Errorf("Ollama error: %s", response.Error)
+	}
+	if response.Message.Content == "" {
+		return "", fmt.Errorf("Ollama returned empty content, body: %s", string(body))
+	}
+	if response.DoneReason == "length" {
+		c.logger.Warnf("⚠️ Ollama response hit the token limit (%d), JSON output may be incomplete", c.MaxTokens)
+	}
+	return response.Message.Content, nil
+}
+
+// parseToolResponse native format: arguments are objects and calls have no IDs, so they are numbered
+func (c *OllamaClient) parseToolResponse(body []byte) (*Response, error) {
+	var response struct {
+		Message struct {
+			Content   string `json:"content"`
+			ToolCalls []struct {
+				Function struct {
+					Name      string          `json:"name"`
+					Arguments json.RawMessage `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"message"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse Ollama response: %w, body: %s", err, string(body))
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("Ollama error: %s", response.Error)
+	}
+
+	result := &Response{Content: response.Message.Content}
+	for i, call := range response.Message.ToolCalls {
+		result.ToolCalls = append(result.ToolCalls, ToolCall{
+			ID:       fmt.Sprintf("call_%d", i),
+			Type:     "function",
+			Function: ToolCallFunction{Name: call.Function.Name, Arguments: string(call.Function.Arguments)},
+		})
+	}
+	return result, nil
+}
+
+// isRetryableError a local model that timed out will time out again, only connection errors are retried
+func (c *OllamaClient) isRetryableError(err error) bool {
+	if strings.Contains(err.Error(), "timeout") {
+		return false
+	}
+	return c.Client.isRetryableError(err)
+}
+
+// IsLocalURL whether the API URL points at this machine or a private network (local LLM servers such as
+// Ollama, llama.cpp or LM Studio, which need no API key)
+func IsLocalURL(apiURL string) bool {
+	u, err := url.Parse(strings.TrimSuffix(apiURL, "#"))
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" || strings.HasSuffix(host, ".local") || host == "host.docker.internal" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsPrivate())
+}
+
+// RequiresAPIKey whether a provider needs an API key: Ollama and OpenAI-compatible servers on a local URL don't
+func RequiresAPIKey(provider, apiURL string) bool {
+	if strings.EqualFold(provider, ProviderOllama) {
+		return false
+	}
+	return !IsLocalURL(apiURL)
+}
@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// ============================================================
+// Test OllamaClient (local models)
+// ============================================================
+
+func TestOllamaClient_CallWithMessages_NoKey(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	var sent map[string]any
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		_ = json.Unmarshal(body, &sent)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(bytes.NewBufferString(`{"message":{"role":"assistant","content":"local answer"},"done":true,"done_reason":"stop"}`)),
+		}, nil
+	}
+
+	client := NewOllamaClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewMockLogger()),
+		WithContextWindow(8192),
+	)
+	client.SetAPIKey("", "http://192.168.1.20:11434/v1", "llama3.1:8b")
+
+	result, err := client.CallWithMessages("system prompt", "user prompt")
+	if err != nil {
+		t.Fatalf("local models shouldn't need an API key: %v", err)
+	}
+	if result != "local answer" {
+		t.Errorf("expected 'local answer', got '%s'", result)
+	}
+
+	req := mockHTTP.GetLastRequest()
+	if req.URL.String() != "http://192.168.1.20:11434/api/chat" {
+		t.Errorf("the /v1 suffix should be dropped for the native API, got '%s'", req.URL.String())
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Error("no Authorization header without a key")
+	}
+	options := sent["options"].(map[string]any)
+	if sent["model"] != "llama3.1:8b" || sent["stream"] != false || options["num_ctx"] != 8192.0 {
+		t.Errorf("unexpected request body: %v", sent)
+	}
+	if budget := client.(*OllamaClient).PromptTokenBudget(); budget != 8192-client.(*OllamaClient).MaxTokens {
+		t.Errorf("prompt budget should reserve the response tokens, got %d", budget)
+	}
+}
+
+func TestOllamaClient_Defaults(t *testing.T) {
+	client := NewOllamaClient().(*OllamaClient)
+	if client.httpClient.Timeout != DefaultLocalTimeout {
+		t.Errorf("expected timeout %v, got %v", DefaultLocalTimeout, client.httpClient.Timeout)
+	}
+	if client.isRetryableError(errors.New("context deadline exceeded (Client.Timeout exceeded while awaiting headers) timeout")) {
+		t.Error("timeouts of a local model shouldn't be retried")
+	}
+	if !client.isRetryableError(errors.New("connection refused")) {
+		t.Error("connection errors should be retried")
+	}
+	if client.PromptTokenBudget() != 0 {
+		t.Error("no budget without a context window")
+	}
+}
+
+func TestRequiresAPIKey(t *testing.T) {
+	tests := []struct {
+		provider string
+		url      string
+		want     bool
+	}{
+		{"ollama", "", false},
+		{"custom", "http://localhost:8080/v1", false},
+		{"custom", "http://127.0.0.1:1234/v1/chat/completions#", false},
+		{"custom", "http://10.0.0.5:8000/v1", false},
+		{"custom", "http://host.docker.internal:8080/v1", false},
+		{"custom", "https://api.example.com/v1", true},
+		{"deepseek", "", true},
+	}
+	for _, tt := range tests {
+		if got := RequiresAPIKey(tt.provider, tt.url); got != tt.want {
+			t.Errorf("RequiresAPIKey(%q, %q) = %v, want %v", tt.provider, tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestClient_LocalCustomEndpoint(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("llama.cpp answer")
+
+	client := NewClient(WithHTTPClient(mockHTTP.ToHTTPClient()), WithLogger(NewMockLogger()))
+	client.SetAPIKey("", "http://localhost:8080/v1", "local-model")
+
+	if _, err := client.CallWithMessages("system", "user"); err != nil {
+		t.Fatalf("local OpenAI-compatible servers shouldn't need an API key: %v", err)
+	}
+	if mockHTTP.GetLastRequest().URL.String() != "http://localhost:8080/v1/chat/completions" {
+		t.Errorf("unexpected URL '%s'", mockHTTP.GetLastRequest().URL.String())
+	}
+}
@@ -82,6 +82,16 @@ func WithMaxTokens(maxTokens int) ClientOption {
 	}
 }
 
+// WithContextWindow sets the model context window in tokens (prompts are shrunk to fit, Ollama gets num_ctx)
+//
+// Usage example:
+//   client := mcp.NewOllamaClientWithOptions(mcp.WithContextWindow(8192))
+func WithContextWindow(tokens int) ClientOption {
+	return func(c *Config) {
+		c.ContextWindow = tokens
+	}
+}
+
 // WithTemperature sets temperature parameter
 //
 // Usage example:
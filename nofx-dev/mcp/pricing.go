@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Price list price per million tokens (USD)
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// providerPrices approximate list prices of each provider's default model
+// Custom endpoints and local models are unknown and reported as zero cost
+var providerPrices = map[string]Price{
+	"deepseek": {Input: 0.28, Output: 0.42},
+	"qwen":     {Input: 1.20, Output: 6.00},
+	"kimi":     {Input: 0.60, Output: 2.50},
+	"claude":   {Input: 3.00, Output: 15.00},
+	"openai":   {Input: 1.25, Output: 10.00},
+	"gemini":   {Input: 1.25, Output: 10.00},
+	"grok":     {Input: 3.00, Output: 15.00},
+}
+
+// EstimateTokens rough token count (~4 characters per token)
+func EstimateTokens(text string) int {
+	n := utf8.RuneCountInString(text)
+	if n == 0 {
+		return 0
+	}
+	return (n + 3) / 4
+}
+
+// EstimateCost estimated USD cost of the tokens at the provider's list price
+func EstimateCost(provider string, inputTokens, outputTokens int) float64 {
+	price, ok := providerPrices[strings.ToLower(provider)]
+	if !ok {
+		return 0
+	}
+	return (float64(inputTokens)*price.Input + float64(outputTokens)*price.Output) / 1_000_000
+}
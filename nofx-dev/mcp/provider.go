@@ -0,0 +1,28 @@
+package mcp
+
+import "strings"
+
+// NewProviderClient creates an AI client for a provider name ("deepseek", "qwen", "claude", ...)
+// Unknown or empty providers fall back to DeepSeek, same as trader initialization
+func NewProviderClient(provider string) AIClient {
+	switch strings.ToLower(strings.TrimSpace(provider)) {
+	case "claude":
+		return NewClaudeClient()
+	case "kimi":
+		return NewKimiClient()
+	case "gemini":
+		return NewGeminiClient()
+	case "grok":
+		return NewGrokClient()
+	case "openai":
+		return NewOpenAIClient()
+	case "qwen":
+		return NewQwenClient()
+	case "ollama":
+		return NewOllamaClient()
+	case "custom":
+		return New()
+	default:
+		return NewDeepSeekClient()
+	}
+}
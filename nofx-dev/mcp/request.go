@@ -2,8 +2,31 @@ package mcp
 
 // Message represents a conversation message
 type Message struct {
-	Role    string `json:"role"`    // "system", "user", "assistant"
+	Role    string `json:"role"`    // "system", "user", "assistant", "tool"
 	Content string `json:"content"` // Message content
+
+	// Tool calling
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`   // Tools the assistant called
+	ToolCallID string     `json:"tool_call_id,omitempty"` // Call answered by a "tool" message
+}
+
+// ToolCall a tool call made by the model
+type ToolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"` // "function"
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction the called function and its JSON-encoded arguments
+type ToolCallFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// Response answer of a tool-calling request
+type Response struct {
+	Content   string     // Answer text (may be empty when the model only called tools)
+	ToolCalls []ToolCall // Tool calls to answer before the model continues
 }
 
 // Tool represents a tool/function that AI can call
@@ -70,3 +93,31 @@ func NewAssistantMessage(content string) Message {
 		Content: content,
 	}
 }
+
+// NewAssistantToolCallMessage creates the assistant message carrying the model's tool calls
+func NewAssistantToolCallMessage(content string, calls []ToolCall) Message {
+	return Message{
+		Role:      "assistant",
+		Content:   content,
+		ToolCalls: calls,
+	}
+}
+
+// NewToolResultMessage creates a message answering a tool call
+func NewToolResultMessage(toolCallID, content string) Message {
+	return Message{
+		Role:       "tool",
+		Content:    content,
+		ToolCallID: toolCallID,
+	}
+}
+
+// hasToolMessages whether the conversation contains tool calls or tool results
+func hasToolMessages(msgs []Message) bool {
+	for _, msg := range msgs {
+		if len(msg.ToolCalls) > 0 || msg.ToolCallID != "" {
+			return true
+		}
+	}
+	return false
+}
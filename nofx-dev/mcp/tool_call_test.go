@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// ============================================================
+// Test Tool Calling (OpenAI format, Claude and Ollama mapping)
+// ============================================================
+
+// toolConversation a conversation where the assistant called a tool and got its result
+func toolConversation() *Request {
+	call := ToolCall{ID: "call_1", Type: "function", Function: ToolCallFunction{Name: "get_klines", Arguments: `{"symbol":"BTCUSDT","interval":"4h"}`}}
+	return &Request{
+		Messages: []Message{
+			NewSystemMessage("system"),
+			NewUserMessage("decide"),
+			NewAssistantToolCallMessage("", []ToolCall{call}),
+			NewToolResultMessage("call_1", `{"klines":[]}`),
+		},
+		Tools:      []Tool{{Type: "function", Function: FunctionDef{Name: "get_klines"}}},
+		ToolChoice: "auto",
+	}
+}
+
+func TestClient_CallWithTools(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	var sent map[string]any
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		_ = json.Unmarshal(body, &sent)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body: io.NopCloser(bytes.NewBufferString(`{"choices":[{"message":{"content":null,"tool_calls":[` +
+				`{"id":"call_2","type":"function","function":{"name":"get_orderbook","arguments":"{\"symbol\":\"BTCUSDT\"}"}}]}}]}`)),
+		}, nil
+	}
+
+	client := NewClient(WithHTTPClient(mockHTTP.ToHTTPClient()), WithLogger(NewMockLogger()), WithAPIKey("sk-test"))
+	resp, err := client.(ToolCaller).CallWithTools(toolConversation())
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ID != "call_2" || resp.ToolCalls[0].Function.Arguments != `{"symbol":"BTCUSDT"}` {
+		t.Errorf("unexpected tool calls: %+v", resp.ToolCalls)
+	}
+
+	messages := sent["messages"].([]any)
+	assistant := messages[2].(map[string]any)
+	tool := messages[3].(map[string]any)
+	if assistant["tool_calls"] == nil || tool["role"] != "tool" || tool["tool_call_id"] != "call_1" {
+		t.Errorf("tool calls and results should be sent in OpenAI format, got %v", messages)
+	}
+	if sent["tools"] == nil || sent["tool_choice"] != "auto" {
+		t.Errorf("tools should be offered, got %v", sent)
+	}
+}
+
+func TestClaudeClient_ToolCalls(t *testing.T) {
+	client := NewClaudeClientWithOptions(WithLogger(NewMockLogger())).(*ClaudeClient)
+
+	body := client.buildRequestBodyFromRequest(toolConversation())
+	messages := body["messages"].([]map[string]any)
+	if len(messages) != 3 || body["system"] != "system" {
+		t.Fatalf("expected user / assistant / user turns with the system prompt apart, got %v", messages)
+	}
+	toolUse := messages[1]["content"].([]map[string]any)[0]
+	toolResult := messages[2]["content"].([]map[string]any)[0]
+	if toolUse["type"] != "tool_use" || toolUse["id"] != "call_1" || toolResult["type"] != "tool_result" || toolResult["tool_use_id"] != "call_1" {
+		t.Errorf("expected tool_use / tool_result blocks, got %v / %v", toolUse, toolResult)
+	}
+
+	resp, err := client.parseToolResponse([]byte(`{"content":[{"type":"text","text":"Checking depth."},` +
+		`{"type":"tool_use","id":"toolu_1","name":"get_orderbook","input":{"symbol":"ETHUSDT"}}],"stop_reason":"tool_use"}`))
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if resp.Content != "Checking depth." || len(resp.ToolCalls) != 1 || resp.ToolCalls[0].Function.Arguments != `{"symbol":"ETHUSDT"}` {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestOllamaClient_ToolCalls(t *testing.T) {
+	client := NewOllamaClientWithOptions(WithLogger(NewMockLogger())).(*OllamaClient)
+
+	body := client.buildRequestBodyFromRequest(toolConversation())
+	messages := body["messages"].([]map[string]any)
+	calls := messages[2]["tool_calls"].([]map[string]any)
+	args := calls[0]["function"].(map[string]any)["arguments"].(map[string]any)
+	if args["interval"] != "4h" || body["tools"] == nil {
+		t.Errorf("arguments should be sent as an object, got %v", messages[2])
+	}
+
+	resp, err := client.parseToolResponse([]byte(`{"message":{"role":"assistant","content":"","tool_calls":[` +
+		`{"function":{"name":"get_klines","arguments":{"symbol":"SOLUSDT","interval":"1h"}}}]},"done":true}`))
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ID != "call_0" || resp.ToolCalls[0].Function.Name != "get_klines" {
+		t.Errorf("unexpected tool calls: %+v", resp.ToolCalls)
+	}
+}
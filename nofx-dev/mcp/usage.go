@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// Usage tokens spent by AI calls and their estimated USD cost
+type Usage struct {
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+	// Estimated some responses carried no token counts, those calls were counted at ~4 characters per token
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// Add adds the usage of another call
+func (u *Usage) Add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.CostUSD += other.CostUSD
+	u.Estimated = u.Estimated || other.Estimated
+}
+
+// UsageTracker AI clients counting the tokens of their calls (every client built on Client)
+type UsageTracker interface {
+	// TakeUsage returns the usage accumulated since the last TakeUsage and resets it
+	TakeUsage() Usage
+}
+
+// usageCounter usage accumulated by a client, behind a pointer so copies of the client share it
+type usageCounter struct {
+	mu    sync.Mutex
+	usage Usage
+}
+
+// TakeUsage returns the usage accumulated since the last TakeUsage and resets it
+func (client *Client) TakeUsage() Usage {
+	if client.usage == nil {
+		return Usage{}
+	}
+	client.usage.mu.Lock()
+	defer client.usage.mu.Unlock()
+	usage := client.usage.usage
+	client.usage.usage = Usage{}
+	return usage
+}
+
+// recordUsage adds the token counts of a response: OpenAI-compatible usage.prompt_tokens / completion_tokens,
+// Claude usage.input_tokens / output_tokens or Ollama prompt_eval_count / eval_count. Responses without counts
+// are estimated from the prompt and answer text
+func (client *Client) recordUsage(body []byte, prompt, completion string) {
+	var counts struct {
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+			InputTokens      int `json:"input_tokens"`
+			OutputTokens     int `json:"output_tokens"`
+		} `json:"usage"`
+		PromptEvalCount int `json:"prompt_eval_count"`
+		EvalCount       int `json:"eval_count"`
+	}
+	_ = json.Unmarshal(body, &counts)
+
+	usage := Usage{
+		PromptTokens:     counts.Usage.PromptTokens + counts.Usage.InputTokens + counts.PromptEvalCount,
+		CompletionTokens: counts.Usage.CompletionTokens + counts.Usage.OutputTokens + counts.EvalCount,
+	}
+	if usage.PromptTokens == 0 && usage.CompletionTokens == 0 {
+		usage = Usage{PromptTokens: EstimateTokens(prompt), CompletionTokens: EstimateTokens(completion), Estimated: true}
+	}
+	usage.CostUSD = EstimateCost(client.Provider, usage.PromptTokens, usage.CompletionTokens)
+
+	if client.usage == nil {
+		return
+	}
+	client.usage.mu.Lock()
+	client.usage.usage.Add(usage)
+	client.usage.mu.Unlock()
+}
+
+// requestText message text of a request, for estimating its prompt tokens
+func requestText(req *Request) string {
+	var sb strings.Builder
+	for _, msg := range req.Messages {
+		sb.WriteString(msg.Content)
+		for _, call := range msg.ToolCalls {
+			sb.WriteString(call.Function.Name)
+			sb.WriteString(call.Function.Arguments)
+		}
+	}
+	return sb.String()
+}
+
+// responseText answer text and tool calls of a response, for estimating its completion tokens
+func responseText(resp *Response) string {
+	if resp == nil {
+		return ""
+	}
+	text := resp.Content
+	for _, call := range resp.ToolCalls {
+		text += call.Function.Name + call.Function.Arguments
+	}
+	return text
+}
@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// ============================================================
+// Test token usage accounting
+// ============================================================
+
+func TestClient_TakeUsage(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body: io.NopCloser(bytes.NewBufferString(`{"choices":[{"message":{"content":"ok"}}],` +
+				`"usage":{"prompt_tokens":1000,"completion_tokens":200}}`)),
+		}, nil
+	}
+	client := NewDeepSeekClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewMockLogger()),
+		WithAPIKey("sk-test"),
+	)
+
+	if _, err := client.CallWithMessages("system", "user"); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if _, err := client.CallWithRequest(NewRequestBuilder().WithUserPrompt("fix it").MustBuild()); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	usage := client.(UsageTracker).TakeUsage()
+	if usage.PromptTokens != 2000 || usage.CompletionTokens != 400 || usage.Estimated {
+		t.Errorf("expected the reported counts of both calls, got %+v", usage)
+	}
+	if want := EstimateCost(ProviderDeepSeek, 2000, 400); usage.CostUSD != want || want == 0 {
+		t.Errorf("expected cost %v, got %v", want, usage.CostUSD)
+	}
+	if again := client.(UsageTracker).TakeUsage(); again != (Usage{}) {
+		t.Errorf("usage should reset after TakeUsage, got %+v", again)
+	}
+}
+
+func TestClient_RecordUsageFormats(t *testing.T) {
+	client := NewClient(WithLogger(NewMockLogger())).(*Client)
+	client.Provider = ProviderCustom
+
+	tests := []struct {
+		name       string
+		body       string
+		prompt     int
+		completion int
+		estimated  bool
+	}{
+		{"claude", `{"usage":{"input_tokens":30,"output_tokens":7}}`, 30, 7, false},
+		{"ollama", `{"prompt_eval_count":12,"eval_count":5}`, 12, 5, false},
+		{"missing", `{"choices":[]}`, 2, 1, true},
+	}
+	for _, tt := range tests {
+		client.recordUsage([]byte(tt.body), "12345678", "abcd")
+		usage := client.TakeUsage()
+		if usage.PromptTokens != tt.prompt || usage.CompletionTokens != tt.completion || usage.Estimated != tt.estimated {
+			t.Errorf("%s: unexpected usage %+v", tt.name, usage)
+		}
+		if usage.CostUSD != 0 {
+			t.Errorf("%s: custom endpoints have no known price, got %v", tt.name, usage.CostUSD)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"nofx/market"
 	"os"
 	"path/filepath"
 	"strings"
@@ -321,15 +322,8 @@ func normalizeSymbol(symbol string) string {
 	// Remove spaces
 	symbol = trimSpaces(symbol)
 
-	// Convert to uppercase
-	symbol = toUpper(symbol)
-
-	// Ensure ends with USDT
-	if !endsWith(symbol, "USDT") {
-		symbol = symbol + "USDT"
-	}
-
-	return symbol
+	// Uppercase and ensure ends with a quote asset (USDC/BUSD/FDUSD pairs are kept as-is)
+	return market.Normalize(symbol)
 }
 
 // Helper functions
@@ -343,25 +337,6 @@ func trimSpaces(s string) string {
 	return result
 }
 
-func toUpper(s string) string {
-	result := ""
-	for i := 0; i < len(s); i++ {
-		c := s[i]
-		if c >= 'a' && c <= 'z' {
-			c = c - 'a' + 'A'
-		}
-		result += string(c)
-	}
-	return result
-}
-
-func endsWith(s, suffix string) bool {
-	if len(s) < len(suffix) {
-		return false
-	}
-	return s[len(s)-len(suffix):] == suffix
-}
-
 // convertSymbolsToCoins converts symbol list to CoinInfo list
 func convertSymbolsToCoins(symbols []string) []CoinInfo {
 	coins := make([]CoinInfo, 0, len(symbols))
@@ -0,0 +1,216 @@
+// Package ratelimit provides a process-wide request weight budget shared by all
+// exchange clients (trader and market data), so multiple traders running under one
+// TraderManager don't collectively trip exchange rate limits (Binance 429/418 bans)
+package ratelimit
+
+import (
+	"nofx/logger"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WeightFunc returns the request weight of an endpoint (method, path, query)
+type WeightFunc func(method, path string, query map[string]string) int
+
+// Budget weight budget for a single exchange
+type Budget struct {
+	exchange string
+	limit    int           // Hard limit per window (as published by exchange)
+	reserve  float64       // Fraction of the limit kept in reserve (e.g. 0.1 = use at most 90%)
+	window   time.Duration // Window length (Binance: 1 minute, aligned to wall clock)
+	weigh    WeightFunc
+
+	mu           sync.Mutex
+	windowStart  time.Time
+	used         int
+	blockedUntil time.Time
+	throttled    int64 // Number of requests that had to wait for budget
+	rateLimited  int64 // Number of HTTP 429 responses
+	banned       int64 // Number of HTTP 418 responses (IP ban)
+	now          func() time.Time
+	sleep        func(time.Duration)
+}
+
+// Stats budget metrics (for API)
+type Stats struct {
+	Exchange     string    `json:"exchange"`
+	Limit        int       `json:"limit"`
+	SoftLimit    int       `json:"soft_limit"`
+	Used         int       `json:"used"`
+	Remaining    int       `json:"remaining"`
+	WindowResets time.Time `json:"window_resets"`
+	BlockedUntil time.Time `json:"blocked_until,omitempty"`
+	Throttled    int64     `json:"throttled"`
+	RateLimited  int64     `json:"rate_limited_429"`
+	Banned       int64     `json:"banned_418"`
+}
+
+var (
+	budgets   = make(map[string]*Budget)
+	budgetsMu sync.RWMutex
+)
+
+func init() {
+	// Binance USDⓈ-M futures: 2400 request weight per minute per IP
+	Register("binance", 2400, time.Minute, 0.1, BinanceFuturesWeight)
+	// Binance Portfolio Margin (PAPI): 6000 request weight per minute per IP
+	Register("binance_pm", 6000, time.Minute, 0.1, nil)
+}
+
+// Register registers (or replaces) the budget for an exchange
+func Register(exchange string, limit int, window time.Duration, reserve float64, weigh WeightFunc) *Budget {
+	if weigh == nil {
+		weigh = func(string, string, map[string]string) int { return 1 }
+	}
+	b := &Budget{
+		exchange: exchange,
+		limit:    limit,
+		reserve:  reserve,
+		window:   window,
+		weigh:    weigh,
+		now:      time.Now,
+		sleep:    time.Sleep,
+	}
+	budgetsMu.Lock()
+	budgets[exchange] = b
+	budgetsMu.Unlock()
+	return b
+}
+
+// Get returns the budget for an exchange (nil if none registered)
+func Get(exchange string) *Budget {
+	budgetsMu.RLock()
+	defer budgetsMu.RUnlock()
+	return budgets[exchange]
+}
+
+// AllStats returns metrics for all registered budgets (sorted by exchange)
+func AllStats() []Stats {
+	budgetsMu.RLock()
+	list := make([]*Budget, 0, len(budgets))
+	for _, b := range budgets {
+		list = append(list, b)
+	}
+	budgetsMu.RUnlock()
+
+	result := make([]Stats, 0, len(list))
+	for _, b := range list {
+		result = append(result, b.Stats())
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Exchange < result[j].Exchange })
+	return result
+}
+
+// softLimit usable weight per window after reserve
+func (b *Budget) softLimit() int {
+	soft := int(float64(b.limit) * (1 - b.reserve))
+	if soft < 1 {
+		soft = 1
+	}
+	return soft
+}
+
+// rollWindow resets usage when the window has elapsed (caller must hold lock)
+func (b *Budget) rollWindow(now time.Time) {
+	start := now.Truncate(b.window)
+	if !start.Equal(b.windowStart) {
+		b.windowStart = start
+		b.used = 0
+	}
+}
+
+// Weight returns the weight of a request according to the exchange's weight table
+func (b *Budget) Weight(method, path string, query map[string]string) int {
+	w := b.weigh(method, path, query)
+	if w < 1 {
+		w = 1
+	}
+	return w
+}
+
+// Acquire blocks until weight can be spent within the budget
+func (b *Budget) Acquire(weight int) {
+	for {
+		b.mu.Lock()
+		now := b.now()
+		b.rollWindow(now)
+
+		var wait time.Duration
+		if now.Before(b.blockedUntil) {
+			wait = b.blockedUntil.Sub(now)
+		} else if b.used+weight > b.softLimit() && b.used > 0 {
+			wait = b.windowStart.Add(b.window).Sub(now)
+		} else {
+			b.used += weight
+			b.mu.Unlock()
+			return
+		}
+		b.throttled++
+		b.mu.Unlock()
+
+		logger.Infof("⏳ [%s] Rate limit budget exhausted, waiting %v", b.exchange, wait.Round(time.Millisecond))
+		b.sleep(wait)
+	}
+}
+
+// ObserveUsedWeight syncs local usage with the exchange-reported used weight
+// (e.g. Binance X-MBX-USED-WEIGHT-1M header, which also counts other processes on the same IP)
+func (b *Budget) ObserveUsedWeight(used int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rollWindow(b.now())
+	if used > b.used {
+		b.used = used
+	}
+}
+
+// ObserveRateLimited records a 429/418 response and blocks the budget until retryAfter elapses
+func (b *Budget) ObserveRateLimited(statusCode int, retryAfter time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if retryAfter <= 0 {
+		retryAfter = b.window
+	}
+	until := b.now().Add(retryAfter)
+	if until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+	if statusCode == 418 {
+		b.banned++
+		logger.Warnf("🚫 [%s] IP banned (HTTP 418), pausing all requests for %v", b.exchange, retryAfter)
+	} else {
+		b.rateLimited++
+		logger.Warnf("⚠️ [%s] Rate limited (HTTP 429), pausing all requests for %v", b.exchange, retryAfter)
+	}
+}
+
+// Stats returns current budget metrics
+func (b *Budget) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	b.rollWindow(now)
+
+	soft := b.softLimit()
+	remaining := soft - b.used
+	if remaining < 0 {
+		remaining = 0
+	}
+	stats := Stats{
+		Exchange:     b.exchange,
+		Limit:        b.limit,
+		SoftLimit:    soft,
+		Used:         b.used,
+		Remaining:    remaining,
+		WindowResets: b.windowStart.Add(b.window),
+		Throttled:    b.throttled,
+		RateLimited:  b.rateLimited,
+		Banned:       b.banned,
+	}
+	if now.Before(b.blockedUntil) {
+		stats.BlockedUntil = b.blockedUntil
+	}
+	return stats
+}
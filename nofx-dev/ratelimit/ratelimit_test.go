@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestBudget creates a budget with a controllable clock
+func newTestBudget(limit int) (*Budget, *time.Time, *[]time.Duration) {
+	now := time.Date(2025, 1, 1, 0, 0, 10, 0, time.UTC)
+	var sleeps []time.Duration
+	b := &Budget{
+		exchange: "test",
+		limit:    limit,
+		window:   time.Minute,
+		weigh:    BinanceFuturesWeight,
+	}
+	b.now = func() time.Time { return now }
+	b.sleep = func(d time.Duration) {
+		sleeps = append(sleeps, d)
+		now = now.Add(d)
+	}
+	return b, &now, &sleeps
+}
+
+// TestBudgetAcquire tests budget spending and waiting for next window
+func TestBudgetAcquire(t *testing.T) {
+	b, _, sleeps := newTestBudget(10)
+
+	b.Acquire(5)
+	b.Acquire(5)
+	if len(*sleeps) != 0 {
+		t.Fatalf("should not wait within budget, waited %v", *sleeps)
+	}
+	if got := b.Stats().Remaining; got != 0 {
+		t.Errorf("expected 0 remaining, got %d", got)
+	}
+
+	// Exceeds budget → waits until the next minute boundary (50s left in window)
+	b.Acquire(1)
+	if len(*sleeps) != 1 || (*sleeps)[0] != 50*time.Second {
+		t.Fatalf("expected single 50s wait, got %v", *sleeps)
+	}
+	stats := b.Stats()
+	if stats.Used != 1 || stats.Throttled != 1 {
+		t.Errorf("unexpected stats after window roll: %+v", stats)
+	}
+}
+
+// TestBudgetObserve tests syncing with exchange-reported usage and 429 blocking
+func TestBudgetObserve(t *testing.T) {
+	b, _, sleeps := newTestBudget(100)
+
+	b.ObserveUsedWeight(95)
+	if got := b.Stats().Used; got != 95 {
+		t.Errorf("expected used 95 from header, got %d", got)
+	}
+	// Lower reported usage never decreases local count
+	b.ObserveUsedWeight(10)
+	if got := b.Stats().Used; got != 95 {
+		t.Errorf("expected used to stay 95, got %d", got)
+	}
+
+	b.ObserveRateLimited(429, 5*time.Second)
+	if b.Stats().BlockedUntil.IsZero() {
+		t.Fatal("expected budget to be blocked after 429")
+	}
+	b.Acquire(1)
+	if len(*sleeps) == 0 || (*sleeps)[0] != 5*time.Second {
+		t.Errorf("expected 5s wait for Retry-After, got %v", *sleeps)
+	}
+	if b.Stats().RateLimited != 1 {
+		t.Error("expected rate limited counter to be 1")
+	}
+}
+
+// TestBinanceFuturesWeight tests endpoint weight table
+func TestBinanceFuturesWeight(t *testing.T) {
+	tests := []struct {
+		path  string
+		query map[string]string
+		want  int
+	}{
+		{"/fapi/v1/klines", map[string]string{"limit": "50"}, 1},
+		{"/fapi/v1/klines", map[string]string{"limit": "100"}, 2},
+		{"/fapi/v1/klines", map[string]string{"limit": "1000"}, 5},
+		{"/fapi/v1/klines", map[string]string{"limit": "1500"}, 10},
+		{"/fapi/v1/klines", map[string]string{}, 5},
+		{"/fapi/v2/account", nil, 5},
+		{"/fapi/v2/positionRisk", nil, 5},
+		{"/fapi/v1/openOrders", map[string]string{"symbol": "BTCUSDT"}, 1},
+		{"/fapi/v1/openOrders", map[string]string{}, 40},
+		{"/fapi/v1/income", nil, 30},
+		{"/fapi/v1/order", map[string]string{"symbol": "BTCUSDT"}, 1},
+	}
+	for _, tt := range tests {
+		if got := BinanceFuturesWeight("GET", tt.path, tt.query); got != tt.want {
+			t.Errorf("%s %v: expected weight %d, got %d", tt.path, tt.query, tt.want, got)
+		}
+	}
+}
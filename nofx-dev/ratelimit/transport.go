@@ -0,0 +1,144 @@
+package ratelimit
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Transport http.RoundTripper that spends budget before each request and
+// feeds exchange usage headers / 429 / 418 responses back into the budget
+type Transport struct {
+	Exchange string
+	Base     http.RoundTripper
+}
+
+// NewTransport wraps base (nil = http.DefaultTransport) with the exchange's shared budget
+func NewTransport(exchange string, base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	// Avoid double wrapping (double spending)
+	if t, ok := base.(*Transport); ok && t.Exchange == exchange {
+		return t
+	}
+	return &Transport{Exchange: exchange, Base: base}
+}
+
+// WrapClient returns a shallow copy of client using the rate-limited transport
+// (the original client is not modified, so shared clients like http.DefaultClient stay untouched)
+func WrapClient(exchange string, client *http.Client) *http.Client {
+	wrapped := &http.Client{}
+	if client != nil {
+		*wrapped = *client
+	}
+	wrapped.Transport = NewTransport(exchange, wrapped.Transport)
+	return wrapped
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	budget := Get(t.Exchange)
+	if budget == nil {
+		return t.Base.RoundTrip(req)
+	}
+
+	query := make(map[string]string)
+	for k, v := range req.URL.Query() {
+		if len(v) > 0 {
+			query[k] = v[0]
+		}
+	}
+	budget.Acquire(budget.Weight(req.Method, req.URL.Path, query))
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	// Binance reports the IP's used weight in the current minute
+	if used := resp.Header.Get("X-Mbx-Used-Weight-1m"); used != "" {
+		if n, err := strconv.Atoi(used); err == nil {
+			budget.ObserveUsedWeight(n)
+		}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot {
+		budget.ObserveRateLimited(resp.StatusCode, parseRetryAfter(resp.Header.Get("Retry-After")))
+	}
+	return resp, nil
+}
+
+// parseRetryAfter parses Retry-After header (seconds)
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return 0
+}
+
+// BinanceFuturesWeight returns request weight for Binance USDⓈ-M futures endpoints
+func BinanceFuturesWeight(method, path string, query map[string]string) int {
+	_, hasSymbol := query["symbol"]
+	limit, _ := strconv.Atoi(query["limit"])
+
+	switch {
+	case strings.HasSuffix(path, "/klines"), strings.HasSuffix(path, "/continuousKlines"),
+		strings.HasSuffix(path, "/markPriceKlines"), strings.HasSuffix(path, "/indexPriceKlines"):
+		switch {
+		case limit == 0: // default limit is 500
+			return 5
+		case limit < 100:
+			return 1
+		case limit < 500:
+			return 2
+		case limit <= 1000:
+			return 5
+		default:
+			return 10
+		}
+	case strings.HasSuffix(path, "/depth"):
+		switch {
+		case limit == 0, limit <= 50:
+			return 2
+		case limit <= 100:
+			return 5
+		case limit <= 500:
+			return 10
+		default:
+			return 20
+		}
+	case strings.HasSuffix(path, "/account"), strings.HasSuffix(path, "/balance"),
+		strings.HasSuffix(path, "/positionRisk"), strings.HasSuffix(path, "/userTrades"):
+		return 5
+	case strings.HasSuffix(path, "/income"):
+		return 30
+	case strings.HasSuffix(path, "/openOrders"):
+		if hasSymbol {
+			return 1
+		}
+		return 40
+	case strings.HasSuffix(path, "/ticker/24hr"):
+		if hasSymbol {
+			return 1
+		}
+		return 40
+	case strings.HasSuffix(path, "/ticker/price"), strings.HasSuffix(path, "/ticker/bookTicker"):
+		if hasSymbol {
+			return 1
+		}
+		return 2
+	case strings.HasSuffix(path, "/premiumIndex"):
+		if hasSymbol {
+			return 1
+		}
+		return 10
+	case strings.HasSuffix(path, "/batchOrders"):
+		return 5
+	default:
+		return 1
+	}
+}
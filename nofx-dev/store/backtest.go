@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -417,6 +418,84 @@ func (s *BacktestStore) LoadTradeEvents(runID string) ([]TradeEvent, error) {
 	return events, rows.Err()
 }
 
+// LoadClosedTrades loads the most recent closed trades of a backtest run (newest first), in the same shape as
+// live recent trades: each reduce / close event pairs with the position's average entry
+func (s *BacktestStore) LoadClosedTrades(runID string, limit int) ([]RecentTrade, error) {
+	events, err := s.LoadTradeEvents(runID)
+	if err != nil {
+		return nil, err
+	}
+	trades := closedTradesFromEvents(events)
+	if limit > 0 && len(trades) > limit {
+		trades = trades[:limit]
+	}
+	return trades, nil
+}
+
+// closedTradesFromEvents replays trade events (oldest first) into closed trades (newest first)
+func closedTradesFromEvents(events []TradeEvent) []RecentTrade {
+	type openPosition struct {
+		qty, entry float64
+		leverage   int
+		opened     int64
+	}
+	open := make(map[string]*openPosition)
+	var trades []RecentTrade
+	for _, e := range events {
+		if e.Side == "" || e.Quantity <= 0 {
+			continue
+		}
+		key := e.Symbol + "_" + e.Side
+		pos := open[key]
+		if strings.HasPrefix(e.Action, "open_") || strings.HasPrefix(e.Action, "add_") {
+			if pos == nil {
+				pos = &openPosition{opened: e.Timestamp}
+				open[key] = pos
+			}
+			pos.entry = (pos.entry*pos.qty + e.Price*e.Quantity) / (pos.qty + e.Quantity)
+			pos.qty += e.Quantity
+			if e.Leverage > 0 {
+				pos.leverage = e.Leverage
+			}
+			continue
+		}
+		if pos == nil {
+			continue // Reduce without a known entry
+		}
+
+		t := RecentTrade{
+			Symbol:      e.Symbol,
+			Side:        e.Side,
+			EntryPrice:  pos.entry,
+			ExitPrice:   e.Price,
+			RealizedPnL: e.RealizedPnL,
+		}
+		leverage := float64(max(pos.leverage, 1))
+		if pos.entry > 0 {
+			if e.Side == "long" {
+				t.PnLPct = (e.Price - pos.entry) / pos.entry * 100 * leverage
+			} else {
+				t.PnLPct = (pos.entry - e.Price) / pos.entry * 100 * leverage
+			}
+		}
+		entryTime, exitTime := time.UnixMilli(pos.opened).UTC(), time.UnixMilli(e.Timestamp).UTC()
+		t.EntryTime = entryTime.Format("01-02 15:04 UTC")
+		t.ExitTime = exitTime.Format("01-02 15:04 UTC")
+		t.HoldDuration = formatDuration(exitTime.Sub(entryTime))
+		trades = append(trades, t)
+
+		pos.qty = e.PositionAfter
+		if pos.qty <= 0 {
+			delete(open, key)
+		}
+	}
+
+	for i, j := 0, len(trades)-1; i < j; i, j = i+1, j-1 {
+		trades[i], trades[j] = trades[j], trades[i]
+	}
+	return trades
+}
+
 // SaveMetrics saves metrics
 func (s *BacktestStore) SaveMetrics(runID string, payload []byte) error {
 	_, err := s.db.Exec(`
@@ -28,6 +28,17 @@ type DecisionRecord struct {
 	Success             bool               `json:"success"`
 	ErrorMessage        string             `json:"error_message"`
 	AIRequestDurationMs int64              `json:"ai_request_duration_ms"`
+	PromptTokens        int                `json:"prompt_tokens"` // AI tokens of the cycle (tool rounds, schema repair and ensemble models included)
+	CompletionTokens    int                `json:"completion_tokens"`
+	AICostUSD           float64            `json:"ai_cost_usd"` // Estimated at list prices, 0 for custom endpoints and local models
+	ModelUsage          []ModelUsage       `json:"model_usage,omitempty"`
+	ReflectionResponse  string             `json:"reflection_response,omitempty"` // Raw answer of the reflection pass (reflection_pass flag)
+	ReflectionJSON      string             `json:"reflection_json,omitempty"`     // Its verdicts on the entries of DecisionJSON
+	Confidences         []ActionConfidence `json:"confidences,omitempty"`         // Stated confidence of each action, for calibration
+	Fallback            string             `json:"fallback,omitempty"`            // Rule-based fallback mode that decided because the AI failed
+	PromptHash          string             `json:"prompt_hash,omitempty"`         // Hash of the strategy's prompt configuration the decision was made with
+	Clamps              []GuardrailClamp   `json:"clamps,omitempty"`              // AI values changed by the trader's risk guardrail
+	Provider            string             `json:"provider,omitempty"`            // Decision provider that decided instead of the AI model
 	AccountState        AccountSnapshot    `json:"account_state"`
 	Positions           []PositionSnapshot `json:"positions"`
 	Decisions           []DecisionAction   `json:"decisions"`
@@ -53,6 +64,7 @@ type PositionSnapshot struct {
 	UnrealizedProfit float64 `json:"unrealized_profit"`
 	Leverage         float64 `json:"leverage"`
 	LiquidationPrice float64 `json:"liquidation_price"`
+	FundingFee       float64 `json:"funding_fee"` // Funding received (+) / paid (-) since the position opened
 }
 
 // DecisionAction decision action
@@ -66,6 +78,48 @@ type DecisionAction struct{
 	Timestamp time.Time `json:"timestamp"`
 	Success   bool      `json:"success"`
 	Error     string    `json:"error"`
+
+	// Stop loss snapped beyond nearby liquidity: the AI's level and the one placed (0 when unchanged)
+	RequestedStopLoss float64 `json:"requested_stop_loss,omitempty"`
+	StopLoss          float64 `json:"stop_loss,omitempty"`
+	StopAdjustReason  string  `json:"stop_adjust_reason,omitempty"`
+}
+
+// ModelUsage AI tokens and estimated cost of one model
+type ModelUsage struct {
+	Model            string  `json:"model"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+	Estimated        bool    `json:"estimated,omitempty"` // Token counts estimated from the text, the API didn't report them
+}
+
+// ActionConfidence confidence the AI stated on an action and what the trader's confidence gate did with it
+type ActionConfidence struct {
+	Symbol     string  `json:"symbol"`
+	Action     string  `json:"action"`
+	Confidence int     `json:"confidence"`
+	Ignored    bool    `json:"ignored,omitempty"`     // Entry below the trader's min confidence, not executed
+	SizeFactor float64 `json:"size_factor,omitempty"` // Share of the AI's size kept by confidence scaling (0 = not scaled)
+}
+
+// GuardrailClamp an AI-proposed value the trader's risk guardrail clamped before execution
+type GuardrailClamp struct {
+	Symbol   string  `json:"symbol"`
+	Action   string  `json:"action"`
+	Field    string  `json:"field"`    // leverage / position_size_usd / stop_loss
+	Original float64 `json:"original"` // Value proposed by the AI (0 = not set)
+	Clamped  float64 `json:"clamped"`  // Value executed
+	Reason   string  `json:"reason"`   // Limit that applied
+}
+
+// AIUsageSummary AI tokens and estimated cost of a trader's decision cycles, in total and per model
+type AIUsageSummary struct {
+	Cycles           int          `json:"cycles"`
+	PromptTokens     int          `json:"prompt_tokens"`
+	CompletionTokens int          `json:"completion_tokens"`
+	CostUSD          float64      `json:"cost_usd"`
+	Models           []ModelUsage `json:"models"`
 }
 
 // Statistics statistics information
@@ -97,6 +151,17 @@ func (s *DecisionStore) initTables() error {
 			success BOOLEAN DEFAULT 0,
 			error_message TEXT DEFAULT '',
 			ai_request_duration_ms INTEGER DEFAULT 0,
+			prompt_tokens INTEGER DEFAULT 0,
+			completion_tokens INTEGER DEFAULT 0,
+			ai_cost_usd REAL DEFAULT 0,
+			model_usage TEXT DEFAULT '',
+			reflection_response TEXT DEFAULT '',
+			reflection_json TEXT DEFAULT '',
+			confidences TEXT DEFAULT '',
+			ai_fallback TEXT DEFAULT '',
+			prompt_hash TEXT DEFAULT '',
+			risk_clamps TEXT DEFAULT '',
+			decision_provider TEXT DEFAULT '',
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)`,
 		// Indexes
@@ -112,6 +177,18 @@ func (s *DecisionStore) initTables() error {
 
 	// Migration: add raw_response column if not exists
 	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN raw_response TEXT DEFAULT ''`)
+	// Migration: AI token usage and cost columns
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN prompt_tokens INTEGER DEFAULT 0`)
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN completion_tokens INTEGER DEFAULT 0`)
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN ai_cost_usd REAL DEFAULT 0`)
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN model_usage TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN reflection_response TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN reflection_json TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN confidences TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN ai_fallback TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN prompt_hash TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN risk_clamps TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN decision_provider TEXT DEFAULT ''`)
 
 	return nil
 }
@@ -127,19 +204,26 @@ func (s *DecisionStore) LogDecision(record *DecisionRecord) error {
 	// Serialize candidate coins and execution log to JSON
 	candidateCoinsJSON, _ := json.Marshal(record.CandidateCoins)
 	executionLogJSON, _ := json.Marshal(record.ExecutionLog)
+	modelUsageJSON, _ := json.Marshal(record.ModelUsage)
+	confidencesJSON, _ := json.Marshal(record.Confidences)
+	clampsJSON, _ := json.Marshal(record.Clamps)
 
 	// Insert decision record main table (only save AI decision related content)
 	result, err := s.db.Exec(`
 		INSERT INTO decision_records (
 			trader_id, cycle_number, timestamp, system_prompt, input_prompt,
 			cot_trace, decision_json, raw_response, candidate_coins, execution_log,
-			success, error_message, ai_request_duration_ms
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			success, error_message, ai_request_duration_ms,
+			prompt_tokens, completion_tokens, ai_cost_usd, model_usage,
+			reflection_response, reflection_json, confidences, ai_fallback, prompt_hash, risk_clamps, decision_provider
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		record.TraderID, record.CycleNumber, record.Timestamp.Format(time.RFC3339),
 		record.SystemPrompt, record.InputPrompt, record.CoTTrace, record.DecisionJSON,
 		record.RawResponse, string(candidateCoinsJSON), string(executionLogJSON),
 		record.Success, record.ErrorMessage, record.AIRequestDurationMs,
+		record.PromptTokens, record.CompletionTokens, record.AICostUSD, string(modelUsageJSON),
+		record.ReflectionResponse, record.ReflectionJSON, string(confidencesJSON), record.Fallback, record.PromptHash, string(clampsJSON), record.Provider,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert decision record: %w", err)
@@ -159,7 +243,11 @@ func (s *DecisionStore) GetLatestRecords(traderID string, n int) ([]*DecisionRec
 	rows, err := s.db.Query(`
 		SELECT id, trader_id, cycle_number, timestamp, system_prompt, input_prompt,
 			   cot_trace, decision_json, candidate_coins, execution_log,
-			   success, error_message, ai_request_duration_ms
+			   success, error_message, ai_request_duration_ms,
+			   prompt_tokens, completion_tokens, ai_cost_usd, model_usage,
+			   COALESCE(reflection_response, ''), COALESCE(reflection_json, ''), COALESCE(confidences, ''),
+			   COALESCE(ai_fallback, ''), COALESCE(prompt_hash, ''),
+			   COALESCE(risk_clamps, ''), COALESCE(decision_provider, '')
 		FROM decision_records
 		WHERE trader_id = ?
 		ORDER BY timestamp DESC
@@ -197,7 +285,11 @@ func (s *DecisionStore) GetAllLatestRecords(n int) ([]*DecisionRecord, error) {
 	rows, err := s.db.Query(`
 		SELECT id, trader_id, cycle_number, timestamp, system_prompt, input_prompt,
 			   cot_trace, decision_json, candidate_coins, execution_log,
-			   success, error_message, ai_request_duration_ms
+			   success, error_message, ai_request_duration_ms,
+			   prompt_tokens, completion_tokens, ai_cost_usd, model_usage,
+			   COALESCE(reflection_response, ''), COALESCE(reflection_json, ''), COALESCE(confidences, ''),
+			   COALESCE(ai_fallback, ''), COALESCE(prompt_hash, ''),
+			   COALESCE(risk_clamps, ''), COALESCE(decision_provider, '')
 		FROM decision_records
 		ORDER BY timestamp DESC
 		LIMIT ?
@@ -231,7 +323,11 @@ func (s *DecisionStore) GetRecordsByDate(traderID string, date time.Time) ([]*De
 	rows, err := s.db.Query(`
 		SELECT id, trader_id, cycle_number, timestamp, system_prompt, input_prompt,
 			   cot_trace, decision_json, candidate_coins, execution_log,
-			   success, error_message, ai_request_duration_ms
+			   success, error_message, ai_request_duration_ms,
+			   prompt_tokens, completion_tokens, ai_cost_usd, model_usage,
+			   COALESCE(reflection_response, ''), COALESCE(reflection_json, ''), COALESCE(confidences, ''),
+			   COALESCE(ai_fallback, ''), COALESCE(prompt_hash, ''),
+			   COALESCE(risk_clamps, ''), COALESCE(decision_provider, '')
 		FROM decision_records
 		WHERE trader_id = ? AND DATE(timestamp) = ?
 		ORDER BY timestamp ASC
@@ -253,6 +349,37 @@ func (s *DecisionStore) GetRecordsByDate(traderID string, date time.Time) ([]*De
 	return records, nil
 }
 
+// GetRecordsByTimeRange gets records of the trader between start and end (sorted old to new)
+func (s *DecisionStore) GetRecordsByTimeRange(traderID string, start, end time.Time) ([]*DecisionRecord, error) {
+	rows, err := s.db.Query(`
+		SELECT id, trader_id, cycle_number, timestamp, system_prompt, input_prompt,
+			   cot_trace, decision_json, candidate_coins, execution_log,
+			   success, error_message, ai_request_duration_ms,
+			   prompt_tokens, completion_tokens, ai_cost_usd, model_usage,
+			   COALESCE(reflection_response, ''), COALESCE(reflection_json, ''), COALESCE(confidences, ''),
+			   COALESCE(ai_fallback, ''), COALESCE(prompt_hash, ''),
+			   COALESCE(risk_clamps, ''), COALESCE(decision_provider, '')
+		FROM decision_records
+		WHERE trader_id = ? AND timestamp >= ? AND timestamp <= ?
+		ORDER BY timestamp ASC
+	`, traderID, start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query decision records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*DecisionRecord
+	for rows.Next() {
+		record, err := s.scanDecisionRecord(rows)
+		if err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
 // CleanOldRecords cleans old records from N days ago
 func (s *DecisionStore) CleanOldRecords(traderID string, days int) (int64, error) {
 	cutoffTime := time.Now().AddDate(0, 0, -days).Format(time.RFC3339)
@@ -301,6 +428,55 @@ func (s *DecisionStore) GetStatistics(traderID string) (*Statistics, error) {
 	return stats, nil
 }
 
+// GetAIUsage sums the AI tokens and estimated cost of a trader's decision cycles, per model from the recorded
+// breakdown (cycles logged before usage was recorded count as zero)
+func (s *DecisionStore) GetAIUsage(traderID string) (*AIUsageSummary, error) {
+	rows, err := s.db.Query(`
+		SELECT COALESCE(prompt_tokens, 0), COALESCE(completion_tokens, 0), COALESCE(ai_cost_usd, 0), COALESCE(model_usage, '')
+		FROM decision_records
+		WHERE trader_id = ?
+	`, traderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query AI usage: %w", err)
+	}
+	defer rows.Close()
+
+	summary := &AIUsageSummary{Models: []ModelUsage{}}
+	models := make(map[string]*ModelUsage)
+	var order []string
+	for rows.Next() {
+		var prompt, completion int
+		var cost float64
+		var modelUsageJSON string
+		if err := rows.Scan(&prompt, &completion, &cost, &modelUsageJSON); err != nil {
+			continue
+		}
+		summary.Cycles++
+		summary.PromptTokens += prompt
+		summary.CompletionTokens += completion
+		summary.CostUSD += cost
+
+		var usage []ModelUsage
+		json.Unmarshal([]byte(modelUsageJSON), &usage)
+		for _, u := range usage {
+			m, ok := models[u.Model]
+			if !ok {
+				m = &ModelUsage{Model: u.Model}
+				models[u.Model] = m
+				order = append(order, u.Model)
+			}
+			m.PromptTokens += u.PromptTokens
+			m.CompletionTokens += u.CompletionTokens
+			m.CostUSD += u.CostUSD
+			m.Estimated = m.Estimated || u.Estimated
+		}
+	}
+	for _, model := range order {
+		summary.Models = append(summary.Models, *models[model])
+	}
+	return summary, nil
+}
+
 // GetAllStatistics gets statistics information for all traders
 func (s *DecisionStore) GetAllStatistics() (*Statistics, error) {
 	stats := &Statistics{}
@@ -338,13 +514,16 @@ func (s *DecisionStore) GetLastCycleNumber(traderID string) (int, error) {
 func (s *DecisionStore) scanDecisionRecord(rows *sql.Rows) (*DecisionRecord, error) {
 	var record DecisionRecord
 	var timestampStr string
-	var candidateCoinsJSON, executionLogJSON string
+	var candidateCoinsJSON, executionLogJSON, modelUsageJSON, confidencesJSON, clampsJSON string
 
 	err := rows.Scan(
 		&record.ID, &record.TraderID, &record.CycleNumber, &timestampStr,
 		&record.SystemPrompt, &record.InputPrompt, &record.CoTTrace,
 		&record.DecisionJSON, &candidateCoinsJSON, &executionLogJSON,
 		&record.Success, &record.ErrorMessage, &record.AIRequestDurationMs,
+		&record.PromptTokens, &record.CompletionTokens, &record.AICostUSD, &modelUsageJSON,
+		&record.ReflectionResponse, &record.ReflectionJSON, &confidencesJSON,
+		&record.Fallback, &record.PromptHash, &clampsJSON, &record.Provider,
 	)
 	if err != nil {
 		return nil, err
@@ -353,6 +532,9 @@ func (s *DecisionStore) scanDecisionRecord(rows *sql.Rows) (*DecisionRecord, err
 	record.Timestamp, _ = time.Parse(time.RFC3339, timestampStr)
 	json.Unmarshal([]byte(candidateCoinsJSON), &record.CandidateCoins)
 	json.Unmarshal([]byte(executionLogJSON), &record.ExecutionLog)
+	json.Unmarshal([]byte(modelUsageJSON), &record.ModelUsage)
+	json.Unmarshal([]byte(confidencesJSON), &record.Confidences)
+	json.Unmarshal([]byte(clampsJSON), &record.Clamps)
 
 	return &record, nil
 }
@@ -20,7 +20,7 @@ type ExchangeStore struct {
 // Exchange exchange configuration
 type Exchange struct {
 	ID                      string    `json:"id"`            // UUID
-	ExchangeType            string    `json:"exchange_type"` // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter"
+	ExchangeType            string    `json:"exchange_type"` // "binance", "bybit", "okx", "mexc", "coinbase", "hyperliquid", "aster", "lighter"
 	AccountName             string    `json:"account_name"`  // User-defined account name
 	UserID                  string    `json:"user_id"`
 	Name                    string    `json:"name"` // Display name (auto-generated or user-defined)
@@ -28,7 +28,7 @@ type Exchange struct {
 	Enabled                 bool      `json:"enabled"`
 	APIKey                  string    `json:"apiKey"`
 	SecretKey               string    `json:"secretKey"`
-	Passphrase              string    `json:"passphrase"` // OKX-specific
+	Passphrase              string    `json:"passphrase"` // OKX/Coinbase-specific
 	Testnet                 bool      `json:"testnet"`
 	HyperliquidWalletAddr   string    `json:"hyperliquidWalletAddr"`
 	AsterUser               string    `json:"asterUser"`
@@ -37,6 +37,7 @@ type Exchange struct {
 	LighterWalletAddr       string    `json:"lighterWalletAddr"`
 	LighterPrivateKey       string    `json:"lighterPrivateKey"`
 	LighterAPIKeyPrivateKey string    `json:"lighterAPIKeyPrivateKey"`
+	QuoteAsset              string    `json:"quote_asset"` // Quote/margin asset traded on this account: USDT (default), USDC, BUSD, FDUSD
 	CreatedAt               time.Time `json:"created_at"`
 	UpdatedAt               time.Time `json:"updated_at"`
 }
@@ -63,6 +64,7 @@ func (s *ExchangeStore) initTables() error {
 			lighter_wallet_addr TEXT DEFAULT '',
 			lighter_private_key TEXT DEFAULT '',
 			lighter_api_key_private_key TEXT DEFAULT '',
+			quote_asset TEXT DEFAULT 'USDT',
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)
@@ -75,6 +77,7 @@ func (s *ExchangeStore) initTables() error {
 	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN passphrase TEXT DEFAULT ''`)
 	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN exchange_type TEXT NOT NULL DEFAULT ''`)
 	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN account_name TEXT NOT NULL DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN quote_asset TEXT DEFAULT 'USDT'`)
 
 	// Run migration to multi-account if needed
 	if err := s.migrateToMultiAccount(); err != nil {
@@ -230,6 +233,7 @@ func (s *ExchangeStore) List(userID string) ([]*Exchange, error) {
 		       COALESCE(lighter_wallet_addr, '') as lighter_wallet_addr,
 		       COALESCE(lighter_private_key, '') as lighter_private_key,
 		       COALESCE(lighter_api_key_private_key, '') as lighter_api_key_private_key,
+		       COALESCE(NULLIF(quote_asset, ''), 'USDT') as quote_asset,
 		       created_at, updated_at
 		FROM exchanges WHERE user_id = ? ORDER BY exchange_type, account_name
 	`, userID)
@@ -248,7 +252,7 @@ func (s *ExchangeStore) List(userID string) ([]*Exchange, error) {
 			&e.Enabled, &e.APIKey, &e.SecretKey, &e.Passphrase, &e.Testnet,
 			&e.HyperliquidWalletAddr, &e.AsterUser, &e.AsterSigner, &e.AsterPrivateKey,
 			&e.LighterWalletAddr, &e.LighterPrivateKey, &e.LighterAPIKeyPrivateKey,
-			&createdAt, &updatedAt,
+			&e.QuoteAsset, &createdAt, &updatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -281,6 +285,7 @@ func (s *ExchangeStore) GetByID(userID, id string) (*Exchange, error) {
 		       COALESCE(lighter_wallet_addr, '') as lighter_wallet_addr,
 		       COALESCE(lighter_private_key, '') as lighter_private_key,
 		       COALESCE(lighter_api_key_private_key, '') as lighter_api_key_private_key,
+		       COALESCE(NULLIF(quote_asset, ''), 'USDT') as quote_asset,
 		       created_at, updated_at
 		FROM exchanges WHERE id = ? AND user_id = ?
 	`, id, userID).Scan(
@@ -289,7 +294,7 @@ func (s *ExchangeStore) GetByID(userID, id string) (*Exchange, error) {
 		&e.Enabled, &e.APIKey, &e.SecretKey, &e.Passphrase, &e.Testnet,
 		&e.HyperliquidWalletAddr, &e.AsterUser, &e.AsterSigner, &e.AsterPrivateKey,
 		&e.LighterWalletAddr, &e.LighterPrivateKey, &e.LighterAPIKeyPrivateKey,
-		&createdAt, &updatedAt,
+		&e.QuoteAsset, &createdAt, &updatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -314,6 +319,10 @@ func getExchangeNameAndType(exchangeType string) (name string, typ string) {
 		return "Bybit Futures", "cex"
 	case "okx":
 		return "OKX Futures", "cex"
+	case "mexc":
+		return "MEXC Futures", "cex"
+	case "coinbase":
+		return "Coinbase International", "cex"
 	case "hyperliquid":
 		return "Hyperliquid", "dex"
 	case "aster":
@@ -431,6 +440,20 @@ func (s *ExchangeStore) UpdateAccountName(userID, id, accountName string) error
 	return nil
 }
 
+// UpdateQuoteAsset updates the quote asset traded on an exchange account
+func (s *ExchangeStore) UpdateQuoteAsset(userID, id, quoteAsset string) error {
+	result, err := s.db.Exec(`UPDATE exchanges SET quote_asset = ?, updated_at = datetime('now') WHERE id = ? AND user_id = ?`,
+		strings.ToUpper(quoteAsset), id, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("exchange not found: id=%s, userID=%s", id, userID)
+	}
+	return nil
+}
+
 // Delete deletes an exchange account
 func (s *ExchangeStore) Delete(userID, id string) error {
 	result, err := s.db.Exec(`DELETE FROM exchanges WHERE id = ? AND user_id = ?`, id, userID)
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// FeatureFlagStore deployment-wide feature flag overrides set at runtime
+// (per-trader overrides live on the traders table)
+type FeatureFlagStore struct {
+	db *sql.DB
+}
+
+func (s *FeatureFlagStore) initTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS feature_flags (
+			name TEXT PRIMARY KEY,
+			enabled BOOLEAN NOT NULL DEFAULT 0,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create feature_flags table: %w", err)
+	}
+	return nil
+}
+
+// List gets the deployment's flag overrides (flags without a row follow the environment default)
+func (s *FeatureFlagStore) List() (map[string]bool, error) {
+	rows, err := s.db.Query(`SELECT name, enabled FROM feature_flags`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	flags := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		var enabled bool
+		if err := rows.Scan(&name, &enabled); err != nil {
+			return nil, err
+		}
+		flags[name] = enabled
+	}
+	return flags, rows.Err()
+}
+
+// Replace replaces all of the deployment's flag overrides
+func (s *FeatureFlagStore) Replace(flags map[string]bool) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM feature_flags`); err != nil {
+		return err
+	}
+	for name, enabled := range flags {
+		if _, err := tx.Exec(`INSERT INTO feature_flags (name, enabled, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)`, name, enabled); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"nofx/market"
+	"time"
+)
+
+// KlineStore closed klines persisted per symbol / timeframe, so backtests and analytics can reuse exact history
+// without refetching it from the exchange
+type KlineStore struct {
+	db *sql.DB
+}
+
+func (s *KlineStore) initTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS klines (
+			symbol TEXT NOT NULL,
+			timeframe TEXT NOT NULL,
+			open_time INTEGER NOT NULL,
+			close_time INTEGER NOT NULL,
+			open REAL NOT NULL,
+			high REAL NOT NULL,
+			low REAL NOT NULL,
+			close REAL NOT NULL,
+			volume REAL NOT NULL DEFAULT 0,
+			quote_volume REAL NOT NULL DEFAULT 0,
+			trades INTEGER NOT NULL DEFAULT 0,
+			taker_buy_base_volume REAL NOT NULL DEFAULT 0,
+			taker_buy_quote_volume REAL NOT NULL DEFAULT 0,
+			PRIMARY KEY (symbol, timeframe, open_time)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create klines table: %w", err)
+	}
+	if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_klines_open_time ON klines(open_time)`); err != nil {
+		return fmt.Errorf("failed to create klines index: %w", err)
+	}
+	return nil
+}
+
+// SaveKlines upserts closed klines of a symbol's timeframe (a re-saved bar replaces the stored one)
+func (s *KlineStore) SaveKlines(symbol, timeframe string, klines []market.Kline) error {
+	if len(klines) == 0 {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT OR REPLACE INTO klines (symbol, timeframe, open_time, close_time, open, high, low, close,
+			volume, quote_volume, trades, taker_buy_base_volume, taker_buy_quote_volume)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, k := range klines {
+		if _, err := stmt.Exec(symbol, timeframe, k.OpenTime, k.CloseTime, k.Open, k.High, k.Low, k.Close,
+			k.Volume, k.QuoteVolume, k.Trades, k.TakerBuyBaseVolume, k.TakerBuyQuoteVolume); err != nil {
+			return fmt.Errorf("failed to save kline %s %s %d: %w", symbol, timeframe, k.OpenTime, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// LoadKlines gets the stored klines opening within [start, end], oldest first
+func (s *KlineStore) LoadKlines(symbol, timeframe string, start, end time.Time) ([]market.Kline, error) {
+	rows, err := s.db.Query(`
+		SELECT open_time, close_time, open, high, low, close, volume, quote_volume, trades,
+			taker_buy_base_volume, taker_buy_quote_volume
+		FROM klines
+		WHERE symbol = ? AND timeframe = ? AND open_time >= ? AND open_time <= ?
+		ORDER BY open_time ASC
+	`, symbol, timeframe, start.UnixMilli(), end.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var klines []market.Kline
+	for rows.Next() {
+		var k market.Kline
+		if err := rows.Scan(&k.OpenTime, &k.CloseTime, &k.Open, &k.High, &k.Low, &k.Close, &k.Volume,
+			&k.QuoteVolume, &k.Trades, &k.TakerBuyBaseVolume, &k.TakerBuyQuoteVolume); err != nil {
+			return nil, err
+		}
+		klines = append(klines, k)
+	}
+	return klines, rows.Err()
+}
+
+// DeleteBefore removes klines that opened before cutoff (retention), returns the number removed
+func (s *KlineStore) DeleteBefore(cutoff time.Time) (int64, error) {
+	result, err := s.db.Exec(`DELETE FROM klines WHERE open_time < ?`, cutoff.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
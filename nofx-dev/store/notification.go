@@ -0,0 +1,68 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// NotificationStore per-user notification preferences
+type NotificationStore struct {
+	db *sql.DB
+}
+
+// NotificationSettings quiet hours and digest batching of a user's trader notifications (webhooks)
+// Critical alerts (daily loss limit, margin ratio, exchange circuit open, ...) are always sent immediately
+type NotificationSettings struct {
+	QuietHoursEnabled bool   `json:"quiet_hours_enabled"`
+	QuietStart        string `json:"quiet_start"`    // "HH:MM" in Timezone
+	QuietEnd          string `json:"quiet_end"`      // "HH:MM" in Timezone
+	Timezone          string `json:"timezone"`       // IANA time zone, empty = UTC
+	DigestEnabled     bool   `json:"digest_enabled"` // Collect non-critical notifications into hourly summaries
+	Locale            string `json:"locale"`         // Number / date format of notification texts and reports, empty = en
+}
+
+func (s *NotificationStore) initTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS user_notification_settings (
+			user_id TEXT PRIMARY KEY,
+			settings TEXT NOT NULL DEFAULT '{}',
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create user_notification_settings table: %w", err)
+	}
+	return nil
+}
+
+// Get gets the user's notification settings (everything sent immediately if never set)
+func (s *NotificationStore) Get(userID string) (NotificationSettings, error) {
+	var raw string
+	err := s.db.QueryRow(`SELECT settings FROM user_notification_settings WHERE user_id = ?`, userID).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return NotificationSettings{}, nil
+	}
+	if err != nil {
+		return NotificationSettings{}, err
+	}
+	var settings NotificationSettings
+	if err := json.Unmarshal([]byte(raw), &settings); err != nil {
+		return NotificationSettings{}, err
+	}
+	return settings, nil
+}
+
+// Save creates or replaces the user's notification settings
+func (s *NotificationStore) Save(userID string, settings NotificationSettings) error {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO user_notification_settings (user_id, settings, updated_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(user_id) DO UPDATE SET settings = excluded.settings, updated_at = CURRENT_TIMESTAMP
+	`, userID, string(data))
+	return err
+}
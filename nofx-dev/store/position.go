@@ -28,7 +28,7 @@ type TraderPosition struct {
 	ID                 int64      `json:"id"`
 	TraderID           string     `json:"trader_id"`
 	ExchangeID         string     `json:"exchange_id"`          // Exchange account UUID (for multi-account support)
-	ExchangeType       string     `json:"exchange_type"`        // Exchange type: binance/bybit/okx/hyperliquid/aster/lighter
+	ExchangeType       string     `json:"exchange_type"`        // Exchange type: binance/bybit/okx/mexc/coinbase/hyperliquid/aster/lighter
 	ExchangePositionID string     `json:"exchange_position_id"` // Exchange-specific unique position ID for deduplication
 	Symbol             string     `json:"symbol"`
 	Side               string     `json:"side"`           // LONG/SHORT
@@ -41,10 +41,11 @@ type TraderPosition struct {
 	ExitTime           *time.Time `json:"exit_time"`      // Exit time
 	RealizedPnL        float64    `json:"realized_pnl"`   // Realized profit and loss
 	Fee                float64    `json:"fee"`            // Fee
+	FundingFee         float64    `json:"funding_fee"`    // Funding received (+) / paid (-) while open, included in RealizedPnL once closed
 	Leverage           int        `json:"leverage"`       // Leverage multiplier
 	Status             string     `json:"status"`         // OPEN/CLOSED
 	CloseReason        string     `json:"close_reason"`   // Close reason: ai_decision/manual/stop_loss/take_profit
-	Source             string     `json:"source"`         // Source: system/manual/sync
+	Source             string     `json:"source"`         // Source: system/manual/sync/import/backfill
 	CreatedAt          time.Time  `json:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at"`
 }
@@ -78,6 +79,7 @@ func (s *PositionStore) InitTables() error {
 			exit_time DATETIME,
 			realized_pnl REAL DEFAULT 0,
 			fee REAL DEFAULT 0,
+			funding_fee REAL DEFAULT 0,
 			leverage INTEGER DEFAULT 1,
 			status TEXT DEFAULT 'OPEN',
 			close_reason TEXT DEFAULT '',
@@ -97,8 +99,10 @@ func (s *PositionStore) InitTables() error {
 	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN exchange_type TEXT NOT NULL DEFAULT ''`)
 	// Migration: add exchange_position_id for deduplication
 	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN exchange_position_id TEXT NOT NULL DEFAULT ''`)
-	// Migration: add source field (system/manual/sync)
+	// Migration: add source field (system/manual/sync/import/backfill)
 	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN source TEXT DEFAULT 'system'`)
+	// Migration: add funding_fee (funding accrued while the position was open)
+	s.db.Exec(`ALTER TABLE trader_positions ADD COLUMN funding_fee REAL DEFAULT 0`)
 
 	// Create indexes (after migration)
 	indices := []string{
@@ -169,12 +173,102 @@ func (s *PositionStore) ClosePosition(id int64, exitPrice float64, exitOrderID s
 	return nil
 }
 
+// AddFunding accrues a funding payment (received +, paid -) on an open position
+func (s *PositionStore) AddFunding(id int64, amount float64) error {
+	result, err := s.db.Exec(`
+		UPDATE trader_positions SET funding_fee = COALESCE(funding_fee, 0) + ?, updated_at = ?
+		WHERE id = ? AND status = 'OPEN'
+	`, amount, time.Now().Format(time.RFC3339), id)
+	if err != nil {
+		return fmt.Errorf("failed to update position funding: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("open position %d not found", id)
+	}
+	return nil
+}
+
+// AddToPosition records a scale-in on an open position
+// Entry price becomes the quantity-weighted average of the existing and the added fill
+func (s *PositionStore) AddToPosition(id int64, quantity, price float64) error {
+	if quantity <= 0 {
+		return fmt.Errorf("added quantity must be positive: %f", quantity)
+	}
+	now := time.Now()
+	result, err := s.db.Exec(`
+		UPDATE trader_positions SET
+			entry_price = (entry_price * quantity + ? * ?) / (quantity + ?),
+			quantity = quantity + ?, updated_at = ?
+		WHERE id = ? AND status = 'OPEN'
+	`,
+		price, quantity, quantity,
+		quantity, now.Format(time.RFC3339), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update position record: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("open position %d not found", id)
+	}
+	return nil
+}
+
+// PartialClosePosition records closing part of an open position
+// The closed part becomes its own CLOSED record (same entry), the open record keeps the remaining quantity
+func (s *PositionStore) PartialClosePosition(id int64, quantity, exitPrice float64, exitOrderID string, realizedPnL float64, fee float64, closeReason string) error {
+	if quantity <= 0 {
+		return fmt.Errorf("closed quantity must be positive: %f", quantity)
+	}
+	now := time.Now().Format(time.RFC3339)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO trader_positions (
+			trader_id, exchange_id, exchange_type, symbol, side, quantity, entry_price, entry_order_id,
+			entry_time, exit_price, exit_order_id, exit_time, realized_pnl, fee, funding_fee, leverage, status,
+			close_reason, source, created_at, updated_at
+		)
+		SELECT trader_id, exchange_id, exchange_type, symbol, side, ?, entry_price, entry_order_id,
+			entry_time, ?, ?, ?, ?, ?, COALESCE(funding_fee, 0) * ? / quantity, leverage, 'CLOSED',
+			?, source, ?, ?
+		FROM trader_positions WHERE id = ? AND status = 'OPEN' AND quantity > ?
+	`,
+		quantity, exitPrice, exitOrderID, now, realizedPnL, fee, quantity,
+		closeReason, now, now, id, quantity,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create closed position record: %w", err)
+	}
+
+	// The open record keeps the remaining quantity and the rest of the accrued funding
+	result, err := tx.Exec(`
+		UPDATE trader_positions SET quantity = quantity - ?, funding_fee = COALESCE(funding_fee, 0) * (quantity - ?) / quantity, updated_at = ?
+		WHERE id = ? AND status = 'OPEN' AND quantity > ?
+	`, quantity, quantity, now, id, quantity)
+	if err != nil {
+		return fmt.Errorf("failed to update position record: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("open position %d not found or smaller than %f", id, quantity)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit partial close: %w", err)
+	}
+	return nil
+}
+
 // GetOpenPositions gets all open positions
 func (s *PositionStore) GetOpenPositions(traderID string) ([]*TraderPosition, error) {
 	rows, err := s.db.Query(`
 		SELECT id, trader_id, exchange_id, COALESCE(exchange_type, '') as exchange_type, symbol, side, quantity, entry_price, entry_order_id,
 			entry_time, exit_price, exit_order_id, exit_time, realized_pnl, fee,
-			leverage, status, close_reason, created_at, updated_at
+			leverage, status, close_reason, created_at, updated_at, COALESCE(funding_fee, 0)
 		FROM trader_positions
 		WHERE trader_id = ? AND status = 'OPEN'
 		ORDER BY entry_time DESC
@@ -195,7 +289,7 @@ func (s *PositionStore) GetOpenPositionBySymbol(traderID, symbol, side string) (
 	err := s.db.QueryRow(`
 		SELECT id, trader_id, exchange_id, COALESCE(exchange_type, '') as exchange_type, symbol, side, quantity, entry_price, entry_order_id,
 			entry_time, exit_price, exit_order_id, exit_time, realized_pnl, fee,
-			leverage, status, close_reason, created_at, updated_at
+			leverage, status, close_reason, created_at, updated_at, COALESCE(funding_fee, 0)
 		FROM trader_positions
 		WHERE trader_id = ? AND symbol = ? AND side = ? AND status = 'OPEN'
 		ORDER BY entry_time DESC LIMIT 1
@@ -203,7 +297,7 @@ func (s *PositionStore) GetOpenPositionBySymbol(traderID, symbol, side string) (
 		&pos.ID, &pos.TraderID, &pos.ExchangeID, &pos.ExchangeType, &pos.Symbol, &pos.Side, &pos.Quantity,
 		&pos.EntryPrice, &pos.EntryOrderID, &entryTime, &pos.ExitPrice,
 		&pos.ExitOrderID, &exitTime, &pos.RealizedPnL, &pos.Fee,
-		&pos.Leverage, &pos.Status, &pos.CloseReason, &createdAt, &updatedAt,
+		&pos.Leverage, &pos.Status, &pos.CloseReason, &createdAt, &updatedAt, &pos.FundingFee,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -216,12 +310,33 @@ func (s *PositionStore) GetOpenPositionBySymbol(traderID, symbol, side string) (
 	return &pos, nil
 }
 
+// GetByID gets a position of the trader by ID (nil if not found)
+func (s *PositionStore) GetByID(traderID string, id int64) (*TraderPosition, error) {
+	rows, err := s.db.Query(`
+		SELECT id, trader_id, exchange_id, COALESCE(exchange_type, '') as exchange_type, symbol, side, quantity, entry_price, entry_order_id,
+			entry_time, exit_price, exit_order_id, exit_time, realized_pnl, fee,
+			leverage, status, close_reason, created_at, updated_at, COALESCE(funding_fee, 0)
+		FROM trader_positions
+		WHERE trader_id = ? AND id = ?
+	`, traderID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query position: %w", err)
+	}
+	defer rows.Close()
+
+	positions, err := s.scanPositions(rows)
+	if err != nil || len(positions) == 0 {
+		return nil, err
+	}
+	return positions[0], nil
+}
+
 // GetClosedPositions gets closed positions (historical records)
 func (s *PositionStore) GetClosedPositions(traderID string, limit int) ([]*TraderPosition, error) {
 	rows, err := s.db.Query(`
 		SELECT id, trader_id, exchange_id, COALESCE(exchange_type, '') as exchange_type, symbol, side, quantity, entry_price, entry_order_id,
 			entry_time, exit_price, exit_order_id, exit_time, realized_pnl, fee,
-			leverage, status, close_reason, created_at, updated_at
+			leverage, status, close_reason, created_at, updated_at, COALESCE(funding_fee, 0)
 		FROM trader_positions
 		WHERE trader_id = ? AND status = 'CLOSED'
 		ORDER BY exit_time DESC
@@ -235,12 +350,31 @@ func (s *PositionStore) GetClosedPositions(traderID string, limit int) ([]*Trade
 	return s.scanPositions(rows)
 }
 
+// GetClosedPositionsUpdatedSince gets closed positions last updated (closed or synced) after since, oldest first
+func (s *PositionStore) GetClosedPositionsUpdatedSince(traderID string, since time.Time, limit int) ([]*TraderPosition, error) {
+	rows, err := s.db.Query(`
+		SELECT id, trader_id, exchange_id, COALESCE(exchange_type, '') as exchange_type, symbol, side, quantity, entry_price, entry_order_id,
+			entry_time, exit_price, exit_order_id, exit_time, realized_pnl, fee,
+			leverage, status, close_reason, created_at, updated_at, COALESCE(funding_fee, 0)
+		FROM trader_positions
+		WHERE trader_id = ? AND status = 'CLOSED' AND updated_at > ?
+		ORDER BY updated_at ASC
+		LIMIT ?
+	`, traderID, since.Format(time.RFC3339), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query closed positions: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanPositions(rows)
+}
+
 // GetAllOpenPositions gets all traders' open positions (for global sync)
 func (s *PositionStore) GetAllOpenPositions() ([]*TraderPosition, error) {
 	rows, err := s.db.Query(`
 		SELECT id, trader_id, exchange_id, COALESCE(exchange_type, '') as exchange_type, symbol, side, quantity, entry_price, entry_order_id,
 			entry_time, exit_price, exit_order_id, exit_time, realized_pnl, fee,
-			leverage, status, close_reason, created_at, updated_at
+			leverage, status, close_reason, created_at, updated_at, COALESCE(funding_fee, 0)
 		FROM trader_positions
 		WHERE status = 'OPEN'
 		ORDER BY trader_id, entry_time DESC
@@ -268,7 +402,7 @@ func (s *PositionStore) GetPositionStats(traderID string) (map[string]interface{
 			COALESCE(SUM(realized_pnl), 0) as total_pnl,
 			COALESCE(SUM(fee), 0) as total_fee
 		FROM trader_positions
-		WHERE trader_id = ? AND status = 'CLOSED'
+		WHERE trader_id = ? AND status = 'CLOSED' AND COALESCE(source, 'system') != 'backfill'
 	`, traderID).Scan(&totalTrades, &winTrades, &totalPnL, &totalFee)
 	if err != nil {
 		return nil, err
@@ -287,15 +421,28 @@ func (s *PositionStore) GetPositionStats(traderID string) (map[string]interface{
 	return stats, nil
 }
 
+// GetRealizedPnLSince sums the realized P&L, net of fees and funding, of positions closed since the given time
+func (s *PositionStore) GetRealizedPnLSince(traderID string, since time.Time) (float64, error) {
+	var pnl float64
+	err := s.db.QueryRow(`
+		SELECT COALESCE(SUM(realized_pnl - fee + COALESCE(funding_fee, 0)), 0)
+		FROM trader_positions
+		WHERE trader_id = ? AND status = 'CLOSED' AND exit_time IS NOT NULL AND julianday(exit_time) >= julianday(?)
+	`, traderID, since.UTC().Format(time.RFC3339)).Scan(&pnl)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum realized PnL: %w", err)
+	}
+	return pnl, nil
+}
+
 // GetFullStats gets complete trading statistics (compatible with TraderStats)
+// Backfilled pre-nofx history is left out, it is only reported by GetPerformanceComparison
 func (s *PositionStore) GetFullStats(traderID string) (*TraderStats, error) {
-	stats := &TraderStats{}
-
 	// Query all closed positions
 	rows, err := s.db.Query(`
 		SELECT realized_pnl, fee, exit_time
 		FROM trader_positions
-		WHERE trader_id = ? AND status = 'CLOSED'
+		WHERE trader_id = ? AND status = 'CLOSED' AND COALESCE(source, 'system') != 'backfill'
 		ORDER BY exit_time ASC
 	`, traderID)
 	if err != nil {
@@ -303,20 +450,29 @@ func (s *PositionStore) GetFullStats(traderID string) (*TraderStats, error) {
 	}
 	defer rows.Close()
 
-	var pnls []float64
-	var totalWin, totalLoss float64
-
+	var pnls, fees []float64
 	for rows.Next() {
 		var pnl, fee float64
 		var exitTime sql.NullString
 		if err := rows.Scan(&pnl, &fee, &exitTime); err != nil {
 			continue
 		}
+		pnls = append(pnls, pnl)
+		fees = append(fees, fee)
+	}
+
+	return buildTraderStats(pnls, fees), nil
+}
+
+// buildTraderStats calculates statistics from realized PnLs and fees ordered by exit time
+func buildTraderStats(pnls, fees []float64) *TraderStats {
+	stats := &TraderStats{}
+	var totalWin, totalLoss float64
 
+	for i, pnl := range pnls {
 		stats.TotalTrades++
 		stats.TotalPnL += pnl
-		stats.TotalFee += fee
-		pnls = append(pnls, pnl)
+		stats.TotalFee += fees[i]
 
 		if pnl > 0 {
 			stats.WinTrades++
@@ -355,7 +511,7 @@ func (s *PositionStore) GetFullStats(traderID string) (*TraderStats, error) {
 		stats.MaxDrawdownPct = calculateMaxDrawdownFromPnls(pnls)
 	}
 
-	return stats, nil
+	return stats
 }
 
 // RecentTrade recent trade record (for AI input)
@@ -371,12 +527,12 @@ type RecentTrade struct {
 	HoldDuration string  `json:"hold_duration"` // Hold duration (持仓时长), e.g. "2h30m"
 }
 
-// GetRecentTrades gets recent closed trades
+// GetRecentTrades gets recent closed trades (backfilled history excluded)
 func (s *PositionStore) GetRecentTrades(traderID string, limit int) ([]RecentTrade, error) {
 	rows, err := s.db.Query(`
 		SELECT symbol, side, entry_price, exit_price, realized_pnl, leverage, entry_time, exit_time
 		FROM trader_positions
-		WHERE trader_id = ? AND status = 'CLOSED'
+		WHERE trader_id = ? AND status = 'CLOSED' AND COALESCE(source, 'system') != 'backfill'
 		ORDER BY exit_time DESC
 		LIMIT ?
 	`, traderID, limit)
@@ -523,7 +679,7 @@ func (s *PositionStore) scanPositions(rows *sql.Rows) ([]*TraderPosition, error)
 			&pos.ID, &pos.TraderID, &pos.ExchangeID, &pos.ExchangeType, &pos.Symbol, &pos.Side, &pos.Quantity,
 			&pos.EntryPrice, &pos.EntryOrderID, &entryTime, &pos.ExitPrice,
 			&pos.ExitOrderID, &exitTime, &pos.RealizedPnL, &pos.Fee,
-			&pos.Leverage, &pos.Status, &pos.CloseReason, &createdAt, &updatedAt,
+			&pos.Leverage, &pos.Status, &pos.CloseReason, &createdAt, &updatedAt, &pos.FundingFee,
 		)
 		if err != nil {
 			continue
@@ -575,7 +731,7 @@ func (s *PositionStore) GetSymbolStats(traderID string, limit int) ([]SymbolStat
 			COALESCE(AVG(realized_pnl), 0) as avg_pnl,
 			COALESCE(AVG((julianday(exit_time) - julianday(entry_time)) * 24 * 60), 0) as avg_hold_mins
 		FROM trader_positions
-		WHERE trader_id = ? AND status = 'CLOSED'
+		WHERE trader_id = ? AND status = 'CLOSED' AND COALESCE(source, 'system') != 'backfill'
 		GROUP BY symbol
 		ORDER BY total_pnl DESC
 		LIMIT ?
@@ -616,7 +772,7 @@ func (s *PositionStore) GetHoldingTimeStats(traderID string) ([]HoldingTimeStats
 				realized_pnl,
 				(julianday(exit_time) - julianday(entry_time)) * 24 as hold_hours
 			FROM trader_positions
-			WHERE trader_id = ? AND status = 'CLOSED' AND exit_time IS NOT NULL
+			WHERE trader_id = ? AND status = 'CLOSED' AND COALESCE(source, 'system') != 'backfill' AND exit_time IS NOT NULL
 		)
 		SELECT
 			CASE
@@ -674,7 +830,7 @@ func (s *PositionStore) GetDirectionStats(traderID string) ([]DirectionStats, er
 			COALESCE(SUM(realized_pnl), 0) as total_pnl,
 			COALESCE(AVG(realized_pnl), 0) as avg_pnl
 		FROM trader_positions
-		WHERE trader_id = ? AND status = 'CLOSED'
+		WHERE trader_id = ? AND status = 'CLOSED' AND COALESCE(source, 'system') != 'backfill'
 		GROUP BY side
 	`, traderID)
 	if err != nil {
@@ -786,7 +942,7 @@ func (s *PositionStore) GetHistorySummary(traderID string) (*HistorySummary, err
 	s.db.QueryRow(`
 		SELECT AVG((julianday(exit_time) - julianday(entry_time)) * 24 * 60)
 		FROM trader_positions
-		WHERE trader_id = ? AND status = 'CLOSED' AND exit_time IS NOT NULL
+		WHERE trader_id = ? AND status = 'CLOSED' AND COALESCE(source, 'system') != 'backfill' AND exit_time IS NOT NULL
 	`, traderID).Scan(&avgHold)
 	if avgHold.Valid {
 		summary.AvgHoldingMins = avgHold.Float64
@@ -798,7 +954,7 @@ func (s *PositionStore) GetHistorySummary(traderID string) (*HistorySummary, err
 	var recentPnL float64
 	rows, err := s.db.Query(`
 		SELECT realized_pnl FROM trader_positions
-		WHERE trader_id = ? AND status = 'CLOSED'
+		WHERE trader_id = ? AND status = 'CLOSED' AND COALESCE(source, 'system') != 'backfill'
 		ORDER BY exit_time DESC LIMIT 20
 	`, traderID)
 	if err == nil {
@@ -828,7 +984,7 @@ func (s *PositionStore) GetHistorySummary(traderID string) (*HistorySummary, err
 func (s *PositionStore) calculateStreaks(traderID string, summary *HistorySummary) {
 	rows, err := s.db.Query(`
 		SELECT realized_pnl FROM trader_positions
-		WHERE trader_id = ? AND status = 'CLOSED'
+		WHERE trader_id = ? AND status = 'CLOSED' AND COALESCE(source, 'system') != 'backfill'
 		ORDER BY exit_time DESC
 	`, traderID)
 	if err != nil {
@@ -983,6 +1139,11 @@ func (s *PositionStore) CreateFromClosedPnL(traderID, exchangeID, exchangeType s
 		entryTime = exitTime
 	}
 
+	source := record.Source
+	if source == "" {
+		source = "sync"
+	}
+
 	// ==========================================================================
 	// Step 5: Insert into database
 	// ==========================================================================
@@ -993,12 +1154,12 @@ func (s *PositionStore) CreateFromClosedPnL(traderID, exchangeID, exchangeType s
 			exit_price, exit_order_id, exit_time,
 			realized_pnl, fee, leverage, status, close_reason, source,
 			created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 'CLOSED', ?, 'sync', ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 'CLOSED', ?, ?, ?, ?)
 	`,
 		traderID, exchangeID, exchangeType, exchangePositionID, record.Symbol, side, record.Quantity,
 		record.EntryPrice, "", entryTime.Format(time.RFC3339),
 		record.ExitPrice, record.OrderID, exitTime.Format(time.RFC3339),
-		record.RealizedPnL, record.Fee, record.Leverage, record.CloseType,
+		record.RealizedPnL, record.Fee, record.Leverage, record.CloseType, source,
 		now.Format(time.RFC3339), now.Format(time.RFC3339),
 	)
 	if err != nil {
@@ -1027,6 +1188,7 @@ type ClosedPnLRecord struct {
 	OrderID     string
 	CloseType   string
 	ExchangeID  string
+	Source      string // Record source, defaults to "sync"
 }
 
 // GetLastClosedPositionTime gets the most recent exit time from closed positions
@@ -1132,3 +1294,87 @@ func (s *PositionStore) SyncClosedPositions(traderID, exchangeID, exchangeType s
 	}
 	return created, skipped, nil
 }
+
+// PerformanceComparison baseline (pre-nofx) vs AI-driven performance of a trader
+type PerformanceComparison struct {
+	AIStartTime *time.Time   `json:"ai_start_time"` // Entry time of the first position opened by the AI (nil if none yet)
+	Baseline    *TraderStats `json:"baseline"`      // Imported positions closed before the AI started trading
+	AIDriven    *TraderStats `json:"ai_driven"`     // Positions opened by the AI
+}
+
+// GetPerformanceComparison compares imported exchange history before the AI started trading with AI-driven trades
+func (s *PositionStore) GetPerformanceComparison(traderID string) (*PerformanceComparison, error) {
+	rows, err := s.db.Query(`
+		SELECT realized_pnl, fee, entry_time, exit_time, status, COALESCE(source, 'system')
+		FROM trader_positions
+		WHERE trader_id = ?
+		ORDER BY exit_time ASC
+	`, traderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query positions for comparison: %w", err)
+	}
+	defer rows.Close()
+
+	type closedRow struct {
+		pnl, fee float64
+		exitTime time.Time
+		source   string
+	}
+	var closed []closedRow
+	var aiStart time.Time
+
+	for rows.Next() {
+		var pnl, fee float64
+		var entryTime, exitTime sql.NullString
+		var status, source string
+		if err := rows.Scan(&pnl, &fee, &entryTime, &exitTime, &status, &source); err != nil {
+			continue
+		}
+		if source == "system" && entryTime.Valid {
+			if t, err := time.Parse(time.RFC3339, entryTime.String); err == nil && (aiStart.IsZero() || t.Before(aiStart)) {
+				aiStart = t
+			}
+		}
+		if status != "CLOSED" || !exitTime.Valid {
+			continue
+		}
+		t, _ := time.Parse(time.RFC3339, exitTime.String)
+		closed = append(closed, closedRow{pnl: pnl, fee: fee, exitTime: t, source: source})
+	}
+
+	var basePnls, baseFees, aiPnls, aiFees []float64
+	for _, r := range closed {
+		if r.source == "system" {
+			aiPnls = append(aiPnls, r.pnl)
+			aiFees = append(aiFees, r.fee)
+			continue
+		}
+		// Imported records closed after the AI started may be the AI's own trades seen through history sync
+		if aiStart.IsZero() || r.exitTime.Before(aiStart) {
+			basePnls = append(basePnls, r.pnl)
+			baseFees = append(baseFees, r.fee)
+		}
+	}
+
+	comparison := &PerformanceComparison{
+		Baseline: buildTraderStats(basePnls, baseFees),
+		AIDriven: buildTraderStats(aiPnls, aiFees),
+	}
+	if !aiStart.IsZero() {
+		comparison.AIStartTime = &aiStart
+	}
+	return comparison, nil
+}
+
+// NeedsHistoryBackfill checks whether a trader is on its first run: no backfilled history and no AI-driven closed positions
+func (s *PositionStore) NeedsHistoryBackfill(traderID string) (bool, error) {
+	var count int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM trader_positions
+		WHERE trader_id = ? AND (source = 'backfill' OR (COALESCE(source, 'system') = 'system' AND status = 'CLOSED'))
+	`, traderID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check backfill state: %w", err)
+	}
+	return count == 0, nil
+}
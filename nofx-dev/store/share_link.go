@@ -0,0 +1,171 @@
+package store
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ShareLinkStore public read-only performance sharing links
+type ShareLinkStore struct {
+	db *sql.DB
+}
+
+// ShareFields what a share link exposes (privacy controls)
+// Position sizes, order IDs and keys are never exposed
+type ShareFields struct {
+	EquityCurve    bool `json:"equity_curve"`    // Return curve over time
+	AbsoluteEquity bool `json:"absolute_equity"` // USDT equity values on the curve (otherwise % return only)
+	Stats          bool `json:"stats"`           // Win rate, profit factor, Sharpe, drawdown
+	AbsolutePnL    bool `json:"absolute_pnl"`    // USDT P&L in stats and trades (otherwise % only)
+	Trades         bool `json:"trades"`          // Recent closed trades
+}
+
+// DefaultShareFields curve, stats and trades without USDT amounts
+func DefaultShareFields() ShareFields {
+	return ShareFields{EquityCurve: true, Stats: true, Trades: true}
+}
+
+// ShareLink tokenized, expiring read-only URL for one trader
+type ShareLink struct {
+	Token     string      `json:"token"`
+	UserID    string      `json:"-"`
+	TraderID  string      `json:"trader_id"`
+	Fields    ShareFields `json:"fields"`
+	ExpiresAt time.Time   `json:"expires_at"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// Expired reports whether the link is past its expiry
+func (l *ShareLink) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+func (s *ShareLinkStore) initTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS trader_share_links (
+			token TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			trader_id TEXT NOT NULL,
+			fields TEXT NOT NULL DEFAULT '{}',
+			expires_at TEXT NOT NULL,
+			created_at TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_share_links_trader ON trader_share_links(user_id, trader_id)`,
+	}
+	for _, query := range queries {
+		if _, err := s.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to execute SQL: %w", err)
+		}
+	}
+	return nil
+}
+
+// newShareToken returns a random URL-safe token (128 bits)
+func newShareToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Create creates a share link for a trader, expiring after ttl
+func (s *ShareLinkStore) Create(userID, traderID string, fields ShareFields, ttl time.Duration) (*ShareLink, error) {
+	token, err := newShareToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	fieldsJSON, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	link := &ShareLink{
+		Token:     token,
+		UserID:    userID,
+		TraderID:  traderID,
+		Fields:    fields,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO trader_share_links (token, user_id, trader_id, fields, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, link.Token, userID, traderID, string(fieldsJSON), link.ExpiresAt.Format(time.RFC3339), link.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create share link: %w", err)
+	}
+	return link, nil
+}
+
+// List lists a trader's share links (newest first, expired links included)
+func (s *ShareLinkStore) List(userID, traderID string) ([]*ShareLink, error) {
+	rows, err := s.db.Query(`
+		SELECT token, user_id, trader_id, fields, expires_at, created_at
+		FROM trader_share_links
+		WHERE user_id = ? AND trader_id = ?
+		ORDER BY created_at DESC
+	`, userID, traderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query share links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []*ShareLink{}
+	for rows.Next() {
+		link, err := scanShareLink(rows)
+		if err != nil {
+			continue
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+// Get gets a share link by token (expired links are returned too, callers check Expired)
+func (s *ShareLinkStore) Get(token string) (*ShareLink, error) {
+	row := s.db.QueryRow(`
+		SELECT token, user_id, trader_id, fields, expires_at, created_at
+		FROM trader_share_links WHERE token = ?
+	`, token)
+	return scanShareLink(row)
+}
+
+// Revoke deletes a share link
+func (s *ShareLinkStore) Revoke(userID, traderID, token string) error {
+	result, err := s.db.Exec(`DELETE FROM trader_share_links WHERE token = ? AND user_id = ? AND trader_id = ?`,
+		token, userID, traderID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteByTrader deletes all share links of a trader
+func (s *ShareLinkStore) DeleteByTrader(userID, traderID string) error {
+	_, err := s.db.Exec(`DELETE FROM trader_share_links WHERE user_id = ? AND trader_id = ?`, userID, traderID)
+	return err
+}
+
+// scanShareLink scans one trader_share_links row
+func scanShareLink(row interface{ Scan(...interface{}) error }) (*ShareLink, error) {
+	var link ShareLink
+	var fields, expiresAt, createdAt string
+	if err := row.Scan(&link.Token, &link.UserID, &link.TraderID, &fields, &expiresAt, &createdAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(fields), &link.Fields); err != nil {
+		return nil, fmt.Errorf("invalid share fields: %w", err)
+	}
+	link.ExpiresAt, _ = time.Parse(time.RFC3339, expiresAt)
+	link.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return &link, nil
+}
@@ -25,6 +25,11 @@ type Store struct {
 	position *PositionStore
 	strategy *StrategyStore
 	equity   *EquityStore
+	share    *ShareLinkStore
+	notify   *NotificationStore
+	flags    *FeatureFlagStore
+	klines   *KlineStore
+	memory   *TradeMemoryStore
 
 	// Encryption functions
 	encryptFunc func(string) string
@@ -109,6 +114,7 @@ func (s *Store) SetCryptoFuncs(encrypt, decrypt func(string) string) {
 		s.exchange.decryptFunc = decrypt
 	}
 	if s.trader != nil {
+		s.trader.encryptFunc = encrypt
 		s.trader.decryptFunc = decrypt
 	}
 }
@@ -143,6 +149,21 @@ func (s *Store) initTables() error {
 	if err := s.Equity().initTables(); err != nil {
 		return fmt.Errorf("failed to initialize equity tables: %w", err)
 	}
+	if err := s.ShareLink().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize share link tables: %w", err)
+	}
+	if err := s.Notification().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize notification tables: %w", err)
+	}
+	if err := s.FeatureFlag().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize feature flag tables: %w", err)
+	}
+	if err := s.Kline().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize kline tables: %w", err)
+	}
+	if err := s.TradeMemory().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize trade memory tables: %w", err)
+	}
 	return nil
 }
 
@@ -211,6 +232,7 @@ func (s *Store) Trader() *TraderStore {
 	if s.trader == nil {
 		s.trader = &TraderStore{
 			db:          s.db,
+			encryptFunc: s.encryptFunc,
 			decryptFunc: s.decryptFunc,
 		}
 	}
@@ -267,6 +289,56 @@ func (s *Store) Equity() *EquityStore {
 	return s.equity
 }
 
+// ShareLink gets public share link storage
+func (s *Store) ShareLink() *ShareLinkStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.share == nil {
+		s.share = &ShareLinkStore{db: s.db}
+	}
+	return s.share
+}
+
+// Notification gets per-user notification settings storage
+func (s *Store) Notification() *NotificationStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.notify == nil {
+		s.notify = &NotificationStore{db: s.db}
+	}
+	return s.notify
+}
+
+// FeatureFlag gets deployment feature flag override storage
+func (s *Store) FeatureFlag() *FeatureFlagStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.flags == nil {
+		s.flags = &FeatureFlagStore{db: s.db}
+	}
+	return s.flags
+}
+
+// Kline gets persisted historical kline storage
+func (s *Store) Kline() *KlineStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.klines == nil {
+		s.klines = &KlineStore{db: s.db}
+	}
+	return s.klines
+}
+
+// TradeMemory gets trade memory storage
+func (s *Store) TradeMemory() *TradeMemoryStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.memory == nil {
+		s.memory = &TradeMemoryStore{db: s.db}
+	}
+	return s.memory
+}
+
 // Close closes database connection
 func (s *Store) Close() error {
 	return s.db.Close()
@@ -37,9 +37,16 @@ type StrategyConfig struct {
 	RiskControl RiskControlConfig `json:"risk_control"`
 	// editable sections of System Prompt
 	PromptSections PromptSectionsConfig `json:"prompt_sections,omitempty"`
+	// reusable prompt snippets by name, included in the sections and custom prompt with {{template "name" .}}
+	PromptPartials map[string]string `json:"prompt_partials,omitempty"`
+	// instructions for single symbols (e.g. "BTCUSDT": "never short BTC"), added to that symbol's section of the prompt
+	SymbolPrompts map[string]string `json:"symbol_prompts,omitempty"`
+	// decision provider deciding instead of the AI model ("" = AI, "ema_cross" or another registered provider)
+	DecisionProvider string `json:"decision_provider,omitempty"`
 }
 
 // PromptSectionsConfig editable sections of System Prompt
+// Sections are Go text/template with the cycle's variables (decision.PromptVars), e.g. {{.Risk.MaxPositions}}
 type PromptSectionsConfig struct {
 	// role definition (title + description)
 	RoleDefinition string `json:"role_definition,omitempty"`
@@ -53,7 +60,7 @@ type PromptSectionsConfig struct {
 
 // CoinSourceConfig coin source configuration
 type CoinSourceConfig struct {
-	// source type: "static" | "coinpool" | "oi_top" | "mixed"
+	// source type: "static" | "coinpool" | "oi_top" | "top_movers" | "mixed"
 	SourceType string `json:"source_type"`
 	// static coin list (used when source_type = "static")
 	StaticCoins []string `json:"static_coins,omitempty"`
@@ -69,6 +76,21 @@ type CoinSourceConfig struct {
 	OITopLimit int `json:"oi_top_limit,omitempty"`
 	// OI Top API URL (strategy-level configuration)
 	OITopAPIURL string `json:"oi_top_api_url,omitempty"`
+	// whether to use the top-movers scanner (1h/4h change and volume spike across all futures)
+	UseTopMovers bool `json:"use_top_movers,omitempty"`
+	// top movers maximum count
+	TopMoversLimit int `json:"top_movers_limit,omitempty"`
+	// cycles after which an idle candidate's weight halves; candidates the AI never acts on
+	// decay out of the prompt after two half-lives (0 = disabled)
+	CandidateHalfLife int `json:"candidate_half_life,omitempty"`
+	// only scan candidates in these categories: meme, l1, l2, defi, ai, gaming, other (empty = all)
+	IncludeCategories []string `json:"include_categories,omitempty"`
+	// never scan candidates in these categories
+	ExcludeCategories []string `json:"exclude_categories,omitempty"`
+	// skip contracts listed fewer than this many days ago (0 = disabled)
+	MinListingDays int `json:"min_listing_days,omitempty"`
+	// watch-only coins: analyzed every cycle and commented on through alert notifications, never traded
+	WatchOnlyCoins []string `json:"watch_only_coins,omitempty"`
 }
 
 // IndicatorConfig indicator configuration
@@ -84,9 +106,24 @@ type IndicatorConfig struct {
 	EnableMACD        bool `json:"enable_macd"`
 	EnableRSI         bool `json:"enable_rsi"`
 	EnableATR         bool `json:"enable_atr"`
+	EnableBollinger   bool `json:"enable_bollinger"` // Bollinger Bands (20, 2σ) with %B and band width
+	EnableVWAP        bool `json:"enable_vwap"`      // rolling 24h session VWAP and VWAP anchored at the daily open
 	EnableVolume      bool `json:"enable_volume"`
 	EnableOI          bool `json:"enable_oi"`           // open interest
 	EnableFundingRate bool `json:"enable_funding_rate"` // funding rate
+	EnableOrderBook   bool `json:"enable_order_book"`   // order book spread, top-N depth and imbalance
+	// weekly (1w) trend structure: EMA20/50, ATR14 and recent weekly closes, for swing-style prompts
+	EnableWeeklyContext bool `json:"enable_weekly_context"`
+	// high-impact events (FOMC, CPI, token unlocks) within EventWindowHours, flagged per symbol and market-wide
+	EnableEventCalendar bool `json:"enable_event_calendar"`
+	EventWindowHours    int  `json:"event_window_hours,omitempty"` // default 24
+	// tool calling: compact market data in the prompt, the AI fetches klines, order book and closed trades on demand
+	// (models without tool support get the full prompt)
+	EnableToolCalling bool `json:"enable_tool_calling,omitempty"`
+	// trade memory: the K closed trades whose entry situation (indicators, regime) is most similar to each symbol's
+	// current one are added to the prompt
+	EnableTradeMemory bool `json:"enable_trade_memory,omitempty"`
+	TradeMemoryK      int  `json:"trade_memory_k,omitempty"` // default 3, max 10
 	// EMA period configuration
 	EMAPeriods []int `json:"ema_periods,omitempty"` // default [20, 50]
 	// RSI period configuration
@@ -116,6 +153,8 @@ type KlineConfig struct {
 	EnableMultiTimeframe bool `json:"enable_multi_timeframe"`
 	// selected timeframe list (new: supports multi-timeframe selection)
 	SelectedTimeframes []string `json:"selected_timeframes,omitempty"`
+	// run decision cycles when a primary timeframe candle closes instead of on the scan interval
+	CycleOnCandleClose bool `json:"cycle_on_candle_close,omitempty"`
 }
 
 // ExternalDataSource external data source configuration
@@ -146,6 +185,7 @@ type ExternalDataSource struct {
 // Risk Controls:
 //   - MaxMarginUsage: max margin utilization percentage (CODE ENFORCED)
 //   - MinPositionSize: minimum position size in USDT (CODE ENFORCED)
+//   - ExpectedValueGate / MinExpectedValuePct: skip entries with low expected value after fees and funding (CODE ENFORCED)
 //   - MinRiskRewardRatio: min take_profit / stop_loss ratio (AI guided)
 //   - MinConfidence: min AI confidence to open position (AI guided)
 type RiskControlConfig struct {
@@ -167,6 +207,77 @@ type RiskControlConfig struct {
 	// Min position size in USDT (CODE ENFORCED)
 	MinPositionSize float64 `json:"min_position_size"`
 
+	// Max position notional as share of the symbol's Binance 1h quote volume (e.g. 0.01 = 1%, 0 = off) (CODE ENFORCED, Binance only)
+	MaxLiquidityVolumePct float64 `json:"max_liquidity_volume_pct"`
+	// Max position notional as share of Binance order book depth within ±1% of mid (e.g. 0.2 = 20%, 0 = off) (CODE ENFORCED, Binance only)
+	MaxLiquidityDepthPct float64 `json:"max_liquidity_depth_pct"`
+	// Max estimated slippage of a market entry walking the Binance order book, in bps from mid (e.g. 50 = 0.5%, 0 = off) (CODE ENFORCED, Binance only)
+	// Larger entries are sized down to what fills within the limit, rejected if that is below the min position size
+	MaxSlippageBps float64 `json:"max_slippage_bps"`
+
+	// Max notional of all open positions on the trader's exchange account in USDT (0 = no cap) (CODE ENFORCED)
+	MaxTotalExposureUSD float64 `json:"max_total_exposure_usd"`
+	// Max notional of one symbol (long + short) in USDT (0 = no cap) (CODE ENFORCED)
+	MaxSymbolExposureUSD float64 `json:"max_symbol_exposure_usd"`
+	// Max notional across all running traders of the user in USDT (0 = no cap) (CODE ENFORCED)
+	MaxUserExposureUSD float64 `json:"max_user_exposure_usd"`
+
+	// Default trailing stop distance in % from best price, used when the AI doesn't set one (0 = disabled) (CODE ENFORCED)
+	TrailingStopPct float64 `json:"trailing_stop_pct"`
+	// Move the stop loss to entry (+ round-trip fees + buffer) once leveraged unrealized P&L reaches this % (0 = disabled) (CODE ENFORCED)
+	BreakEvenTriggerPct float64 `json:"break_even_trigger_pct"`
+	// Extra distance beyond entry for the break-even stop, in % of entry price (CODE ENFORCED)
+	BreakEvenBufferPct float64 `json:"break_even_buffer_pct"`
+	// Move the AI's stop loss beyond round numbers, order book walls and volume nodes within this % of the stop (0 = disabled) (CODE ENFORCED)
+	// Stops only move further from price, to avoid stop-hunt fills just beyond obvious liquidity
+	StopSnapTolerancePct float64 `json:"stop_snap_tolerance_pct"`
+	// Place the stop loss of new positions this many ATR14 (primary timeframe) from entry instead of the AI's level (0 = disabled) (CODE ENFORCED)
+	ATRStopMultiplier float64 `json:"atr_stop_multiplier"`
+	// Size new positions so hitting the stop loss loses this % of equity, replacing the AI's position_size_usd (0 = disabled) (CODE ENFORCED)
+	RiskPerTradePct float64 `json:"risk_per_trade_pct"`
+	// Scale leverage of new positions by this target ATR14 % of price over the realized one (primary timeframe),
+	// between 1x and the BTC/ETH / altcoin max leverage (0 = disabled) (CODE ENFORCED)
+	VolTargetATRPct float64 `json:"vol_target_atr_pct"`
+
+	// Daily loss kill-switch: realized + unrealized loss since the UTC day start in % of that day's starting equity (0 = disabled) (CODE ENFORCED)
+	// When reached the trader pauses until the next UTC day
+	MaxDailyLossPct float64 `json:"max_daily_loss_pct"`
+	// Close all positions (and cancel their orders) when the daily loss limit is reached (CODE ENFORCED)
+	DailyLossFlatten bool `json:"daily_loss_flatten"`
+
+	// Margin ratio (maintenance margin / margin balance, %) at which the largest losing position is reduced (0 = disabled) (CODE ENFORCED)
+	// Exchange liquidation happens at 100%
+	MaxMarginRatioPct float64 `json:"max_margin_ratio_pct"`
+	// Share of the largest losing position closed each time the margin ratio threshold is reached, % (default 25) (CODE ENFORCED)
+	MarginReducePct float64 `json:"margin_reduce_pct"`
+
+	// Liquidation guard: distance from mark to liquidation price in % of mark below which adding to the position
+	// (and, under cross margin, any new entry) is blocked (0 = disabled) (CODE ENFORCED)
+	LiquidationGuardPct float64 `json:"liquidation_guard_pct"`
+	// Share of a position inside the liquidation guard distance closed on entry and every 2 minutes while still inside, % (0 = block only) (CODE ENFORCED)
+	LiquidationDeleveragePct float64 `json:"liquidation_deleverage_pct"`
+
+	// Minutes after a stop-loss exit during which the same symbol can't be re-entered (0 = disabled) (CODE ENFORCED)
+	StopLossCooldownMinutes int `json:"stop_loss_cooldown_minutes"`
+
+	// Block new entries from this many minutes before until this many minutes after a high-impact event affecting
+	// the symbol (FOMC, CPI, its token unlock) (0 = disabled) (CODE ENFORCED)
+	EventPauseMinutes int `json:"event_pause_minutes"`
+
+	// Deterministic fallback when the AI errors, times out or returns invalid decisions for AIFallbackAfterFailures
+	// consecutive cycles: "" (skip the cycle), "manage_stops" (hold positions under their stops, no entries) or
+	// "ema_cross" (EMA20 / EMA50 trend of the primary timeframe: exit on a cross against, enter on a fresh cross) (CODE ENFORCED)
+	AIFallbackMode string `json:"ai_fallback_mode"`
+	// Consecutive failed AI cycles before the fallback decides (0 = from the first failure) (CODE ENFORCED)
+	AIFallbackAfterFailures int `json:"ai_fallback_after_failures"`
+
+	// Skip new positions whose expected value is below MinExpectedValuePct: hit rate of the decision's confidence bucket
+	// (from closed trades, with the stated confidence as a prior) × take profit distance - miss rate × stop distance
+	// - round-trip fees - funding over the bucket's average holding time (CODE ENFORCED)
+	ExpectedValueGate bool `json:"expected_value_gate"`
+	// Min expected value of a new position in % of its notional, may be negative (CODE ENFORCED)
+	MinExpectedValuePct float64 `json:"min_expected_value_pct"`
+
 	// Min take_profit / stop_loss ratio (AI guided)
 	MinRiskRewardRatio float64 `json:"min_risk_reward_ratio"`
 	// Min AI confidence to open position (AI guided)
@@ -241,6 +352,7 @@ func GetDefaultStrategyConfig(lang string) StrategyConfig {
 			EnableVolume:       true,
 			EnableOI:           true,
 			EnableFundingRate:  true,
+			EnableOrderBook:    true,
 			EMAPeriods:         []int{20, 50},
 			RSIPeriods:         []int{7, 14},
 			ATRPeriods:         []int{14},
@@ -257,6 +369,7 @@ func GetDefaultStrategyConfig(lang string) StrategyConfig {
 			AltcoinMaxPositionValueRatio: 1.0, // Altcoin: max position = 1x equity (CODE ENFORCED)
 			MaxMarginUsage:               0.9, // Max 90% margin usage (CODE ENFORCED)
 			MinPositionSize:              12,  // Min 12 USDT per position (CODE ENFORCED)
+			StopSnapTolerancePct:         0.5, // Snap stops beyond liquidity within 0.5% (CODE ENFORCED)
 			MinRiskRewardRatio:           3.0, // Min 3:1 profit/loss ratio (AI guided)
 			MinConfidence:                75,  // Min 75% confidence (AI guided)
 		},
@@ -0,0 +1,127 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TradeMemoryStore market situation at the entry of each AI trade, linked to its position by the entry order ID,
+// so closed trades can be retrieved by similarity to the current situation
+type TradeMemoryStore struct {
+	db *sql.DB
+}
+
+// TradeMemory market situation at a trade's entry
+type TradeMemory struct {
+	ID           int64     `json:"id"`
+	TraderID     string    `json:"trader_id"`
+	EntryOrderID string    `json:"entry_order_id"`
+	Symbol       string    `json:"symbol"`
+	Side         string    `json:"side"`    // LONG/SHORT
+	Regime       string    `json:"regime"`  // Market regime at entry, e.g. uptrend/high_vol
+	Vector       []float64 `json:"vector"`  // Normalized indicator features at entry (situation embedding)
+	Summary      string    `json:"summary"` // Entry reasoning of the AI
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TradeMemoryOutcome a remembered trade whose position is fully closed
+type TradeMemoryOutcome struct {
+	TradeMemory
+	EntryTime   time.Time `json:"entry_time"`
+	ExitTime    time.Time `json:"exit_time"`
+	RealizedPnL float64   `json:"realized_pnl"`
+	ReturnPct   float64   `json:"return_pct"` // Realized PnL / entry notional (%), unleveraged price return
+	CloseReason string    `json:"close_reason"`
+}
+
+func (s *TradeMemoryStore) initTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS trade_memories (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			trader_id TEXT NOT NULL,
+			entry_order_id TEXT NOT NULL,
+			symbol TEXT NOT NULL,
+			side TEXT NOT NULL,
+			regime TEXT DEFAULT '',
+			vector TEXT DEFAULT '',
+			summary TEXT DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create trade_memories table: %w", err)
+	}
+	if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_trade_memories_trader ON trade_memories(trader_id, created_at DESC)`); err != nil {
+		return fmt.Errorf("failed to create trade_memories index: %w", err)
+	}
+	return nil
+}
+
+// Save records the situation at a trade's entry
+func (s *TradeMemoryStore) Save(m *TradeMemory) error {
+	vector, err := json.Marshal(m.Vector)
+	if err != nil {
+		return err
+	}
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now().UTC()
+	}
+	result, err := s.db.Exec(`
+		INSERT INTO trade_memories (trader_id, entry_order_id, symbol, side, regime, vector, summary, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, m.TraderID, m.EntryOrderID, m.Symbol, m.Side, m.Regime, string(vector), m.Summary, m.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to save trade memory: %w", err)
+	}
+	m.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// GetOutcomes remembered trades of a trader whose position is fully closed (partial closes summed), newest first
+func (s *TradeMemoryStore) GetOutcomes(traderID string, limit int) ([]*TradeMemoryOutcome, error) {
+	rows, err := s.db.Query(`
+		SELECT m.id, m.trader_id, m.entry_order_id, m.symbol, m.side, m.regime, m.vector, m.summary, m.created_at,
+			MIN(p.entry_time), MAX(p.exit_time), SUM(p.realized_pnl), SUM(p.quantity * p.entry_price),
+			COALESCE(MAX(p.close_reason), '')
+		FROM trade_memories m
+		JOIN trader_positions p ON p.trader_id = m.trader_id AND p.entry_order_id = m.entry_order_id AND p.status = 'CLOSED'
+		WHERE m.trader_id = ? AND NOT EXISTS (
+			SELECT 1 FROM trader_positions o
+			WHERE o.trader_id = m.trader_id AND o.entry_order_id = m.entry_order_id AND o.status = 'OPEN'
+		)
+		GROUP BY m.id
+		ORDER BY m.created_at DESC
+		LIMIT ?
+	`, traderID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trade memories: %w", err)
+	}
+	defer rows.Close()
+
+	var outcomes []*TradeMemoryOutcome
+	for rows.Next() {
+		var o TradeMemoryOutcome
+		var vector, createdAt string
+		var entryTime, exitTime sql.NullString
+		var notional float64
+		if err := rows.Scan(&o.ID, &o.TraderID, &o.EntryOrderID, &o.Symbol, &o.Side, &o.Regime, &vector, &o.Summary,
+			&createdAt, &entryTime, &exitTime, &o.RealizedPnL, &notional, &o.CloseReason); err != nil {
+			continue
+		}
+		json.Unmarshal([]byte(vector), &o.Vector)
+		o.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		if entryTime.Valid {
+			o.EntryTime, _ = time.Parse(time.RFC3339, entryTime.String)
+		}
+		if exitTime.Valid {
+			o.ExitTime, _ = time.Parse(time.RFC3339, exitTime.String)
+		}
+		if notional > 0 {
+			o.ReturnPct = o.RealizedPnL / notional * 100
+		}
+		outcomes = append(outcomes, &o)
+	}
+	return outcomes, nil
+}
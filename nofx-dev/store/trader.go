@@ -2,6 +2,7 @@ package store
 
 import (
 	"database/sql"
+	"encoding/json"
 	"strings"
 	"time"
 )
@@ -9,24 +10,28 @@ import (
 // TraderStore trader storage
 type TraderStore struct {
 	db          *sql.DB
+	encryptFunc func(string) string
 	decryptFunc func(string) string
 }
 
 // Trader trader configuration
 type Trader struct {
-	ID                  string    `json:"id"`
-	UserID              string    `json:"user_id"`
-	Name                string    `json:"name"`
-	AIModelID           string    `json:"ai_model_id"`
-	ExchangeID          string    `json:"exchange_id"`
-	StrategyID          string    `json:"strategy_id"`           // Associated strategy ID
-	InitialBalance      float64   `json:"initial_balance"`
-	ScanIntervalMinutes int       `json:"scan_interval_minutes"`
-	IsRunning           bool      `json:"is_running"`
-	IsCrossMargin       bool      `json:"is_cross_margin"`
-	ShowInCompetition   bool      `json:"show_in_competition"`   // Whether to show in competition page
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
+	ID                    string    `json:"id"`
+	UserID                string    `json:"user_id"`
+	Name                  string    `json:"name"`
+	AIModelID             string    `json:"ai_model_id"`
+	ExchangeID            string    `json:"exchange_id"`
+	StrategyID            string    `json:"strategy_id"`          // Associated strategy ID
+	FallbackExchangeID    string    `json:"fallback_exchange_id"` // Exchange account used for new entries while the primary exchange is failing (optional)
+	InitialBalance        float64   `json:"initial_balance"`
+	ScanIntervalMinutes   int       `json:"scan_interval_minutes"`
+	MaxPositionAgeMinutes int       `json:"max_position_age_minutes"` // Positions held longer are closed / sent to review (0 = no limit)
+	MaxPositionAgeAction  string    `json:"max_position_age_action"`  // PositionAgeActionClose / PositionAgeActionReview
+	IsRunning             bool      `json:"is_running"`
+	IsCrossMargin         bool      `json:"is_cross_margin"`
+	ShowInCompetition     bool      `json:"show_in_competition"` // Whether to show in competition page
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
 
 	// Following fields are deprecated, kept for backward compatibility, new traders should use StrategyID
 	BTCETHLeverage       int    `json:"btc_eth_leverage,omitempty"`
@@ -41,10 +46,11 @@ type Trader struct {
 
 // TraderFullConfig trader full configuration (includes AI model, exchange and strategy)
 type TraderFullConfig struct {
-	Trader   *Trader
-	AIModel  *AIModel
-	Exchange *Exchange
-	Strategy *Strategy // Associated strategy configuration
+	Trader           *Trader
+	AIModel          *AIModel
+	Exchange         *Exchange
+	FallbackExchange *Exchange // Fallback exchange account (nil if not configured)
+	Strategy         *Strategy // Associated strategy configuration
 }
 
 func (s *TraderStore) initTables() error {
@@ -100,6 +106,22 @@ func (s *TraderStore) initTables() error {
 		`ALTER TABLE traders ADD COLUMN system_prompt_template TEXT DEFAULT 'default'`,
 		`ALTER TABLE traders ADD COLUMN strategy_id TEXT DEFAULT ''`,
 		`ALTER TABLE traders ADD COLUMN show_in_competition BOOLEAN DEFAULT 1`,
+		`ALTER TABLE traders ADD COLUMN fallback_exchange_id TEXT DEFAULT ''`,
+		`ALTER TABLE traders ADD COLUMN webhook_url TEXT DEFAULT ''`,
+		`ALTER TABLE traders ADD COLUMN webhook_secret TEXT DEFAULT ''`,
+		`ALTER TABLE traders ADD COLUMN flat_schedule TEXT DEFAULT ''`,
+		`ALTER TABLE traders ADD COLUMN max_position_age_minutes INTEGER DEFAULT 0`,
+		`ALTER TABLE traders ADD COLUMN max_position_age_action TEXT DEFAULT 'close'`,
+		`ALTER TABLE traders ADD COLUMN performance_seed TEXT DEFAULT ''`,
+		`ALTER TABLE traders ADD COLUMN outcome_export_url TEXT DEFAULT ''`,
+		`ALTER TABLE traders ADD COLUMN outcome_export_secret TEXT DEFAULT ''`,
+		`ALTER TABLE traders ADD COLUMN outcome_export_cursor TEXT DEFAULT ''`,
+		`ALTER TABLE traders ADD COLUMN feature_flags TEXT DEFAULT ''`,
+		`ALTER TABLE traders ADD COLUMN ensemble TEXT DEFAULT ''`,
+		`ALTER TABLE traders ADD COLUMN confidence_gate TEXT DEFAULT ''`,
+		`ALTER TABLE traders ADD COLUMN liquidity_filter TEXT DEFAULT ''`,
+		`ALTER TABLE traders ADD COLUMN risk_guardrail TEXT DEFAULT ''`,
+		`ALTER TABLE traders ADD COLUMN daily_loss_paused_until TEXT DEFAULT ''`,
 	}
 	for _, q := range alterQueries {
 		s.db.Exec(q)
@@ -187,6 +209,13 @@ func (s *TraderStore) migrateTradersRemoveFK() error {
 	return err
 }
 
+func (s *TraderStore) encrypt(plaintext string) string {
+	if s.encryptFunc != nil {
+		return s.encryptFunc(plaintext)
+	}
+	return plaintext
+}
+
 func (s *TraderStore) decrypt(encrypted string) string {
 	if s.decryptFunc != nil {
 		return s.decryptFunc(encrypted)
@@ -197,13 +226,14 @@ func (s *TraderStore) decrypt(encrypted string) string {
 // Create creates trader
 func (s *TraderStore) Create(trader *Trader) error {
 	_, err := s.db.Exec(`
-		INSERT INTO traders (id, user_id, name, ai_model_id, exchange_id, strategy_id, initial_balance,
-		                     scan_interval_minutes, is_running, is_cross_margin, show_in_competition,
+		INSERT INTO traders (id, user_id, name, ai_model_id, exchange_id, fallback_exchange_id, strategy_id, initial_balance,
+		                     scan_interval_minutes, max_position_age_minutes, max_position_age_action,
+		                     is_running, is_cross_margin, show_in_competition,
 		                     btc_eth_leverage, altcoin_leverage, trading_symbols, use_coin_pool,
 		                     use_oi_top, custom_prompt, override_base_prompt, system_prompt_template)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, trader.ID, trader.UserID, trader.Name, trader.AIModelID, trader.ExchangeID, trader.StrategyID,
-		trader.InitialBalance, trader.ScanIntervalMinutes, trader.IsRunning, trader.IsCrossMargin, trader.ShowInCompetition,
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, trader.ID, trader.UserID, trader.Name, trader.AIModelID, trader.ExchangeID, trader.FallbackExchangeID, trader.StrategyID,
+		trader.InitialBalance, trader.ScanIntervalMinutes, trader.MaxPositionAgeMinutes, trader.MaxPositionAgeAction, trader.IsRunning, trader.IsCrossMargin, trader.ShowInCompetition,
 		trader.BTCETHLeverage, trader.AltcoinLeverage, trader.TradingSymbols, trader.UseCoinPool,
 		trader.UseOITop, trader.CustomPrompt, trader.OverrideBasePrompt, trader.SystemPromptTemplate)
 	return err
@@ -212,8 +242,9 @@ func (s *TraderStore) Create(trader *Trader) error {
 // List gets user's trader list
 func (s *TraderStore) List(userID string) ([]*Trader, error) {
 	rows, err := s.db.Query(`
-		SELECT id, user_id, name, ai_model_id, exchange_id, COALESCE(strategy_id, ''),
-		       initial_balance, scan_interval_minutes, is_running, COALESCE(is_cross_margin, 1),
+		SELECT id, user_id, name, ai_model_id, exchange_id, COALESCE(fallback_exchange_id, ''), COALESCE(strategy_id, ''),
+		       initial_balance, scan_interval_minutes,
+		       COALESCE(max_position_age_minutes, 0), COALESCE(max_position_age_action, 'close'), is_running, COALESCE(is_cross_margin, 1),
 		       COALESCE(show_in_competition, 1),
 		       COALESCE(btc_eth_leverage, 5), COALESCE(altcoin_leverage, 5), COALESCE(trading_symbols, ''),
 		       COALESCE(use_coin_pool, 0), COALESCE(use_oi_top, 0), COALESCE(custom_prompt, ''),
@@ -231,8 +262,8 @@ func (s *TraderStore) List(userID string) ([]*Trader, error) {
 		var t Trader
 		var createdAt, updatedAt string
 		err := rows.Scan(
-			&t.ID, &t.UserID, &t.Name, &t.AIModelID, &t.ExchangeID, &t.StrategyID,
-			&t.InitialBalance, &t.ScanIntervalMinutes, &t.IsRunning, &t.IsCrossMargin,
+			&t.ID, &t.UserID, &t.Name, &t.AIModelID, &t.ExchangeID, &t.FallbackExchangeID, &t.StrategyID,
+			&t.InitialBalance, &t.ScanIntervalMinutes, &t.MaxPositionAgeMinutes, &t.MaxPositionAgeAction, &t.IsRunning, &t.IsCrossMargin,
 			&t.ShowInCompetition,
 			&t.BTCETHLeverage, &t.AltcoinLeverage, &t.TradingSymbols,
 			&t.UseCoinPool, &t.UseOITop, &t.CustomPrompt, &t.OverrideBasePrompt,
@@ -264,16 +295,340 @@ func (s *TraderStore) UpdateShowInCompetition(userID, id string, showInCompetiti
 func (s *TraderStore) Update(trader *Trader) error {
 	_, err := s.db.Exec(`
 		UPDATE traders SET
-			name = ?, ai_model_id = ?, exchange_id = ?, strategy_id = ?,
-			scan_interval_minutes = ?, is_cross_margin = ?, show_in_competition = ?,
+			name = ?, ai_model_id = ?, exchange_id = ?, fallback_exchange_id = ?, strategy_id = ?,
+			scan_interval_minutes = ?, max_position_age_minutes = ?, max_position_age_action = ?,
+			is_cross_margin = ?, show_in_competition = ?,
 			updated_at = CURRENT_TIMESTAMP
 		WHERE id = ? AND user_id = ?
-	`, trader.Name, trader.AIModelID, trader.ExchangeID, trader.StrategyID,
-		trader.ScanIntervalMinutes, trader.IsCrossMargin, trader.ShowInCompetition, trader.ID, trader.UserID)
+	`, trader.Name, trader.AIModelID, trader.ExchangeID, trader.FallbackExchangeID, trader.StrategyID,
+		trader.ScanIntervalMinutes, trader.MaxPositionAgeMinutes, trader.MaxPositionAgeAction, trader.IsCrossMargin, trader.ShowInCompetition, trader.ID, trader.UserID)
+	return err
+}
+
+// UpdateWebhook updates the executed-decision webhook (empty URL disables it), the secret is stored encrypted
+func (s *TraderStore) UpdateWebhook(userID, id, url, secret string) error {
+	_, err := s.db.Exec(`UPDATE traders SET webhook_url = ?, webhook_secret = ? WHERE id = ? AND user_id = ?`,
+		url, s.encrypt(secret), id, userID)
+	return err
+}
+
+// GetWebhook gets the executed-decision webhook URL and signing secret of a trader
+func (s *TraderStore) GetWebhook(userID, id string) (url, secret string, err error) {
+	err = s.db.QueryRow(`
+		SELECT COALESCE(webhook_url, ''), COALESCE(webhook_secret, '')
+		FROM traders WHERE id = ? AND user_id = ?
+	`, id, userID).Scan(&url, &secret)
+	if err != nil {
+		return "", "", err
+	}
+	return url, s.decrypt(secret), nil
+}
+
+// UpdateOutcomeExport updates the decision outcome export sink (empty URL disables it), the secret is stored encrypted
+// A changed sink starts over from the first closed trade
+func (s *TraderStore) UpdateOutcomeExport(userID, id, url, secret string) error {
+	_, err := s.db.Exec(`
+		UPDATE traders SET
+			outcome_export_cursor = CASE WHEN COALESCE(outcome_export_url, '') = ? THEN outcome_export_cursor ELSE '' END,
+			outcome_export_url = ?, outcome_export_secret = ?
+		WHERE id = ? AND user_id = ?
+	`, url, url, s.encrypt(secret), id, userID)
+	return err
+}
+
+// GetOutcomeExport gets the decision outcome export sink and signing secret of a trader
+func (s *TraderStore) GetOutcomeExport(userID, id string) (url, secret string, err error) {
+	err = s.db.QueryRow(`
+		SELECT COALESCE(outcome_export_url, ''), COALESCE(outcome_export_secret, '')
+		FROM traders WHERE id = ? AND user_id = ?
+	`, id, userID).Scan(&url, &secret)
+	if err != nil {
+		return "", "", err
+	}
+	return url, s.decrypt(secret), nil
+}
+
+// GetOutcomeExportCursor gets the close time (updated_at) of the last exported trade, zero if none was exported yet
+func (s *TraderStore) GetOutcomeExportCursor(id string) (time.Time, error) {
+	var raw string
+	if err := s.db.QueryRow(`SELECT COALESCE(outcome_export_cursor, '') FROM traders WHERE id = ?`, id).Scan(&raw); err != nil {
+		return time.Time{}, err
+	}
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
+
+// SetOutcomeExportCursor records the close time (updated_at) of the last exported trade
+func (s *TraderStore) SetOutcomeExportCursor(id string, cursor time.Time) error {
+	_, err := s.db.Exec(`UPDATE traders SET outcome_export_cursor = ? WHERE id = ?`, cursor.Format(time.RFC3339), id)
+	return err
+}
+
+// GetDailyLossPause gets the end of the trader's daily loss kill-switch pause, zero if it never tripped
+func (s *TraderStore) GetDailyLossPause(id string) (time.Time, error) {
+	var raw string
+	if err := s.db.QueryRow(`SELECT COALESCE(daily_loss_paused_until, '') FROM traders WHERE id = ?`, id).Scan(&raw); err != nil {
+		return time.Time{}, err
+	}
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
+
+// SetDailyLossPause records the end of the trader's daily loss kill-switch pause, so it survives restarts
+func (s *TraderStore) SetDailyLossPause(id string, until time.Time) error {
+	_, err := s.db.Exec(`UPDATE traders SET daily_loss_paused_until = ? WHERE id = ?`, until.UTC().Format(time.RFC3339), id)
+	return err
+}
+
+// FlatSchedule end-of-day flat mode: positions are closed and orders cancelled at FlatTime,
+// new entries stay blocked until ResumeTime
+type FlatSchedule struct {
+	Enabled    bool   `json:"enabled"`
+	Mode       string `json:"mode"`        // "daily" (every day) or "weekend" (Friday's flat time until Monday's resume time)
+	FlatTime   string `json:"flat_time"`   // "HH:MM" in Timezone
+	ResumeTime string `json:"resume_time"` // "HH:MM" in Timezone, empty = midnight
+	Timezone   string `json:"timezone"`    // IANA time zone, empty = UTC
+}
+
+// UpdateFlatSchedule updates the trader's end-of-day flat schedule
+func (s *TraderStore) UpdateFlatSchedule(userID, id string, schedule FlatSchedule) error {
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`UPDATE traders SET flat_schedule = ? WHERE id = ? AND user_id = ?`, string(data), id, userID)
+	return err
+}
+
+// GetFlatSchedule gets the trader's end-of-day flat schedule (disabled if never set)
+func (s *TraderStore) GetFlatSchedule(userID, id string) (FlatSchedule, error) {
+	var raw string
+	err := s.db.QueryRow(`SELECT COALESCE(flat_schedule, '') FROM traders WHERE id = ? AND user_id = ?`, id, userID).Scan(&raw)
+	if err != nil {
+		return FlatSchedule{}, err
+	}
+	var schedule FlatSchedule
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &schedule); err != nil {
+			return FlatSchedule{}, err
+		}
+	}
+	return schedule, nil
+}
+
+// Performance seed sources
+const (
+	PerformanceSeedTrader   = "trader"   // Closed trades of a predecessor trader
+	PerformanceSeedBacktest = "backtest" // Closed trades of a backtest run
+)
+
+// PerformanceSeed cold-start performance context: until the trader has MinLiveTrades closed trades of its own,
+// the AI's recent trades are topped up with trades from a predecessor trader or a backtest run
+type PerformanceSeed struct {
+	Source        string `json:"source"`          // PerformanceSeedTrader / PerformanceSeedBacktest, empty = disabled
+	ID            string `json:"id"`              // Predecessor trader ID or backtest run ID
+	MinLiveTrades int    `json:"min_live_trades"` // Live closed trades after which seeding stops (0 = default)
+}
+
+// UpdatePerformanceSeed updates the trader's cold-start performance seed
+func (s *TraderStore) UpdatePerformanceSeed(userID, id string, seed PerformanceSeed) error {
+	data, err := json.Marshal(seed)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`UPDATE traders SET performance_seed = ? WHERE id = ? AND user_id = ?`, string(data), id, userID)
+	return err
+}
+
+// GetPerformanceSeed gets the trader's cold-start performance seed (disabled if never set)
+func (s *TraderStore) GetPerformanceSeed(userID, id string) (PerformanceSeed, error) {
+	var raw string
+	err := s.db.QueryRow(`SELECT COALESCE(performance_seed, '') FROM traders WHERE id = ? AND user_id = ?`, id, userID).Scan(&raw)
+	if err != nil {
+		return PerformanceSeed{}, err
+	}
+	var seed PerformanceSeed
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &seed); err != nil {
+			return PerformanceSeed{}, err
+		}
+	}
+	return seed, nil
+}
+
+// UpdateFeatureFlags updates the trader's feature flag overrides
+func (s *TraderStore) UpdateFeatureFlags(userID, id string, flags map[string]bool) error {
+	data, err := json.Marshal(flags)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`UPDATE traders SET feature_flags = ? WHERE id = ? AND user_id = ?`, string(data), id, userID)
+	return err
+}
+
+// GetFeatureFlags gets the trader's feature flag overrides (empty if never set, flags follow the deployment)
+func (s *TraderStore) GetFeatureFlags(userID, id string) (map[string]bool, error) {
+	var raw string
+	err := s.db.QueryRow(`SELECT COALESCE(feature_flags, '') FROM traders WHERE id = ? AND user_id = ?`, id, userID).Scan(&raw)
+	if err != nil {
+		return nil, err
+	}
+	flags := make(map[string]bool)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &flags); err != nil {
+			return nil, err
+		}
+	}
+	return flags, nil
+}
+
+// Ensemble vote methods
+const (
+	EnsembleMajority = "majority" // One vote per model
+	EnsembleWeighted = "weighted" // Votes weighted by each model's recent closed-trade win rate
+)
+
+// EnsembleConfig AI models asked alongside the trader's own model each cycle (ensemble_mode feature flag),
+// their decisions are combined by vote and an action only executes when enough of the ensemble agrees
+type EnsembleConfig struct {
+	ModelIDs      []string `json:"model_ids"`      // Additional AI model IDs, empty = the trader's model decides alone
+	Method        string   `json:"method"`         // EnsembleMajority (default) / EnsembleWeighted
+	MinAgreement  float64  `json:"min_agreement"`  // Share of the vote weight (0-1] an action needs, 0 = more than half
+	MinConfidence int      `json:"min_confidence"` // Minimum average confidence of the agreeing models for entries (0 = none)
+}
+
+// UpdateEnsemble updates the trader's ensemble models and vote rules
+func (s *TraderStore) UpdateEnsemble(userID, id string, ensemble EnsembleConfig) error {
+	data, err := json.Marshal(ensemble)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`UPDATE traders SET ensemble = ? WHERE id = ? AND user_id = ?`, string(data), id, userID)
+	return err
+}
+
+// GetEnsemble gets the trader's ensemble models and vote rules (no models if never set)
+func (s *TraderStore) GetEnsemble(userID, id string) (EnsembleConfig, error) {
+	var raw string
+	err := s.db.QueryRow(`SELECT COALESCE(ensemble, '') FROM traders WHERE id = ? AND user_id = ?`, id, userID).Scan(&raw)
+	if err != nil {
+		return EnsembleConfig{}, err
+	}
+	var ensemble EnsembleConfig
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &ensemble); err != nil {
+			return EnsembleConfig{}, err
+		}
+	}
+	return ensemble, nil
+}
+
+// ConfidenceGate per-trader use of the confidence the AI states on each action: entries below MinConfidence are
+// ignored and, with ScaleSize, entry sizes scale with confidence
+type ConfidenceGate struct {
+	MinConfidence      int  `json:"min_confidence"`       // Entries (open / add) below are ignored (0 = disabled)
+	ScaleSize          bool `json:"scale_size"`           // Scale position_size_usd by confidence / FullSizeConfidence (risk_per_trade_pct sizing replaces it)
+	FullSizeConfidence int  `json:"full_size_confidence"` // Confidence that gets the full size (0 = 100)
+}
+
+// UpdateConfidenceGate updates the trader's confidence gate
+func (s *TraderStore) UpdateConfidenceGate(userID, id string, gate ConfidenceGate) error {
+	data, err := json.Marshal(gate)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`UPDATE traders SET confidence_gate = ? WHERE id = ? AND user_id = ?`, string(data), id, userID)
+	return err
+}
+
+// GetConfidenceGate gets the trader's confidence gate (disabled if never set)
+func (s *TraderStore) GetConfidenceGate(userID, id string) (ConfidenceGate, error) {
+	var raw string
+	err := s.db.QueryRow(`SELECT COALESCE(confidence_gate, '') FROM traders WHERE id = ? AND user_id = ?`, id, userID).Scan(&raw)
+	if err != nil {
+		return ConfidenceGate{}, err
+	}
+	var gate ConfidenceGate
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &gate); err != nil {
+			return ConfidenceGate{}, err
+		}
+	}
+	return gate, nil
+}
+
+// LiquidityFilter per-trader liquidity filter of candidate coins (coins with an open position are never filtered)
+type LiquidityFilter struct {
+	MinOIValueUSD   float64 `json:"min_oi_value_usd"`   // Min open interest value in USD (0 = default 15M, negative disables)
+	ZeroOIPass      bool    `json:"zero_oi_pass"`       // Coins whose OI is reported as 0 pass instead of being skipped
+	MinVolume24hUSD float64 `json:"min_volume_24h_usd"` // Min 24h quote volume in USD (0 = disabled)
+}
+
+// UpdateLiquidityFilter updates the trader's liquidity filter
+func (s *TraderStore) UpdateLiquidityFilter(userID, id string, filter LiquidityFilter) error {
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`UPDATE traders SET liquidity_filter = ? WHERE id = ? AND user_id = ?`, string(data), id, userID)
 	return err
 }
 
-// UpdateInitialBalance updates initial balance
+// GetLiquidityFilter gets the trader's liquidity filter (defaults if never set)
+func (s *TraderStore) GetLiquidityFilter(userID, id string) (LiquidityFilter, error) {
+	var raw string
+	err := s.db.QueryRow(`SELECT COALESCE(liquidity_filter, '') FROM traders WHERE id = ? AND user_id = ?`, id, userID).Scan(&raw)
+	if err != nil {
+		return LiquidityFilter{}, err
+	}
+	var filter LiquidityFilter
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &filter); err != nil {
+			return LiquidityFilter{}, err
+		}
+	}
+	return filter, nil
+}
+
+// RiskGuardrail per-trader limits the AI's entries are clamped to before execution (0 = no limit)
+type RiskGuardrail struct {
+	MaxLeverage          int     `json:"max_leverage"`            // Max leverage of entries
+	MaxPositionSizeUSD   float64 `json:"max_position_size_usd"`   // Max position size of an entry in USD
+	MaxPositionEquityPct float64 `json:"max_position_equity_pct"` // Max position size of an entry in % of equity
+	MinStopDistancePct   float64 `json:"min_stop_distance_pct"`   // Min stop loss distance from the price in %
+	MaxStopDistancePct   float64 `json:"max_stop_distance_pct"`   // Max stop loss distance from the price in % (also sets missing stops of opens)
+}
+
+// UpdateRiskGuardrail updates the trader's risk guardrail
+func (s *TraderStore) UpdateRiskGuardrail(userID, id string, guardrail RiskGuardrail) error {
+	data, err := json.Marshal(guardrail)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`UPDATE traders SET risk_guardrail = ? WHERE id = ? AND user_id = ?`, string(data), id, userID)
+	return err
+}
+
+// GetRiskGuardrail gets the trader's risk guardrail (no limits if never set)
+func (s *TraderStore) GetRiskGuardrail(userID, id string) (RiskGuardrail, error) {
+	var raw string
+	err := s.db.QueryRow(`SELECT COALESCE(risk_guardrail, '') FROM traders WHERE id = ? AND user_id = ?`, id, userID).Scan(&raw)
+	if err != nil {
+		return RiskGuardrail{}, err
+	}
+	var guardrail RiskGuardrail
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &guardrail); err != nil {
+			return RiskGuardrail{}, err
+		}
+	}
+	return guardrail, nil
+}
+
 func (s *TraderStore) UpdateInitialBalance(userID, id string, newBalance float64) error {
 	_, err := s.db.Exec(`UPDATE traders SET initial_balance = ? WHERE id = ? AND user_id = ?`, newBalance, id, userID)
 	return err
@@ -307,8 +662,9 @@ func (s *TraderStore) GetFullConfig(userID, traderID string) (*TraderFullConfig,
 
 	err := s.db.QueryRow(`
 		SELECT
-			t.id, t.user_id, t.name, t.ai_model_id, t.exchange_id, COALESCE(t.strategy_id, ''),
-			t.initial_balance, t.scan_interval_minutes, t.is_running, COALESCE(t.is_cross_margin, 1),
+			t.id, t.user_id, t.name, t.ai_model_id, t.exchange_id, COALESCE(t.fallback_exchange_id, ''), COALESCE(t.strategy_id, ''),
+			t.initial_balance, t.scan_interval_minutes,
+			COALESCE(t.max_position_age_minutes, 0), COALESCE(t.max_position_age_action, 'close'), t.is_running, COALESCE(t.is_cross_margin, 1),
 			COALESCE(t.btc_eth_leverage, 5), COALESCE(t.altcoin_leverage, 5), COALESCE(t.trading_symbols, ''),
 			COALESCE(t.use_coin_pool, 0), COALESCE(t.use_oi_top, 0), COALESCE(t.custom_prompt, ''),
 			COALESCE(t.override_base_prompt, 0), COALESCE(t.system_prompt_template, 'default'),
@@ -325,8 +681,8 @@ func (s *TraderStore) GetFullConfig(userID, traderID string) (*TraderFullConfig,
 		JOIN exchanges e ON t.exchange_id = e.id AND t.user_id = e.user_id
 		WHERE t.id = ? AND t.user_id = ?
 	`, traderID, userID).Scan(
-		&trader.ID, &trader.UserID, &trader.Name, &trader.AIModelID, &trader.ExchangeID, &trader.StrategyID,
-		&trader.InitialBalance, &trader.ScanIntervalMinutes, &trader.IsRunning, &trader.IsCrossMargin,
+		&trader.ID, &trader.UserID, &trader.Name, &trader.AIModelID, &trader.ExchangeID, &trader.FallbackExchangeID, &trader.StrategyID,
+		&trader.InitialBalance, &trader.ScanIntervalMinutes, &trader.MaxPositionAgeMinutes, &trader.MaxPositionAgeAction, &trader.IsRunning, &trader.IsCrossMargin,
 		&trader.BTCETHLeverage, &trader.AltcoinLeverage, &trader.TradingSymbols,
 		&trader.UseCoinPool, &trader.UseOITop, &trader.CustomPrompt, &trader.OverrideBasePrompt,
 		&trader.SystemPromptTemplate, &traderCreatedAt, &traderUpdatedAt,
@@ -369,11 +725,19 @@ func (s *TraderStore) GetFullConfig(userID, traderID string) (*TraderFullConfig,
 		strategy, _ = s.getActiveOrDefaultStrategy(userID)
 	}
 
+	// Load fallback exchange (ignored if it was deleted or is the primary itself)
+	var fallbackExchange *Exchange
+	if trader.FallbackExchangeID != "" && trader.FallbackExchangeID != trader.ExchangeID {
+		exchanges := &ExchangeStore{db: s.db, decryptFunc: s.decryptFunc}
+		fallbackExchange, _ = exchanges.GetByID(userID, trader.FallbackExchangeID)
+	}
+
 	return &TraderFullConfig{
-		Trader:   &trader,
-		AIModel:  &aiModel,
-		Exchange: &exchange,
-		Strategy: strategy,
+		Trader:           &trader,
+		AIModel:          &aiModel,
+		Exchange:         &exchange,
+		FallbackExchange: fallbackExchange,
+		Strategy:         strategy,
 	}, nil
 }
 
@@ -437,16 +801,17 @@ func (s *TraderStore) GetByID(traderID string) (*Trader, error) {
 	var t Trader
 	var createdAt, updatedAt string
 	err := s.db.QueryRow(`
-		SELECT id, user_id, name, ai_model_id, exchange_id, COALESCE(strategy_id, ''),
-		       initial_balance, scan_interval_minutes, is_running, COALESCE(is_cross_margin, 1),
+		SELECT id, user_id, name, ai_model_id, exchange_id, COALESCE(fallback_exchange_id, ''), COALESCE(strategy_id, ''),
+		       initial_balance, scan_interval_minutes,
+		       COALESCE(max_position_age_minutes, 0), COALESCE(max_position_age_action, 'close'), is_running, COALESCE(is_cross_margin, 1),
 		       COALESCE(btc_eth_leverage, 5), COALESCE(altcoin_leverage, 5), COALESCE(trading_symbols, ''),
 		       COALESCE(use_coin_pool, 0), COALESCE(use_oi_top, 0), COALESCE(custom_prompt, ''),
 		       COALESCE(override_base_prompt, 0), COALESCE(system_prompt_template, 'default'),
 		       created_at, updated_at
 		FROM traders WHERE id = ?
 	`, traderID).Scan(
-		&t.ID, &t.UserID, &t.Name, &t.AIModelID, &t.ExchangeID, &t.StrategyID,
-		&t.InitialBalance, &t.ScanIntervalMinutes, &t.IsRunning, &t.IsCrossMargin,
+		&t.ID, &t.UserID, &t.Name, &t.AIModelID, &t.ExchangeID, &t.FallbackExchangeID, &t.StrategyID,
+		&t.InitialBalance, &t.ScanIntervalMinutes, &t.MaxPositionAgeMinutes, &t.MaxPositionAgeAction, &t.IsRunning, &t.IsCrossMargin,
 		&t.BTCETHLeverage, &t.AltcoinLeverage, &t.TradingSymbols,
 		&t.UseCoinPool, &t.UseOITop, &t.CustomPrompt, &t.OverrideBasePrompt,
 		&t.SystemPromptTemplate, &createdAt, &updatedAt,
@@ -461,8 +826,9 @@ func (s *TraderStore) GetByID(traderID string) (*Trader, error) {
 
 func (s *TraderStore) ListAll() ([]*Trader, error) {
 	rows, err := s.db.Query(`
-		SELECT id, user_id, name, ai_model_id, exchange_id, COALESCE(strategy_id, ''),
-		       initial_balance, scan_interval_minutes, is_running, COALESCE(is_cross_margin, 1),
+		SELECT id, user_id, name, ai_model_id, exchange_id, COALESCE(fallback_exchange_id, ''), COALESCE(strategy_id, ''),
+		       initial_balance, scan_interval_minutes,
+		       COALESCE(max_position_age_minutes, 0), COALESCE(max_position_age_action, 'close'), is_running, COALESCE(is_cross_margin, 1),
 		       COALESCE(show_in_competition, 1),
 		       COALESCE(btc_eth_leverage, 5), COALESCE(altcoin_leverage, 5), COALESCE(trading_symbols, ''),
 		       COALESCE(use_coin_pool, 0), COALESCE(use_oi_top, 0), COALESCE(custom_prompt, ''),
@@ -480,8 +846,8 @@ func (s *TraderStore) ListAll() ([]*Trader, error) {
 		var t Trader
 		var createdAt, updatedAt string
 		err := rows.Scan(
-			&t.ID, &t.UserID, &t.Name, &t.AIModelID, &t.ExchangeID, &t.StrategyID,
-			&t.InitialBalance, &t.ScanIntervalMinutes, &t.IsRunning, &t.IsCrossMargin,
+			&t.ID, &t.UserID, &t.Name, &t.AIModelID, &t.ExchangeID, &t.FallbackExchangeID, &t.StrategyID,
+			&t.InitialBalance, &t.ScanIntervalMinutes, &t.MaxPositionAgeMinutes, &t.MaxPositionAgeAction, &t.IsRunning, &t.IsCrossMargin,
 			&t.ShowInCompetition,
 			&t.BTCETHLeverage, &t.AltcoinLeverage, &t.TradingSymbols,
 			&t.UseCoinPool, &t.UseOITop, &t.CustomPrompt, &t.OverrideBasePrompt,
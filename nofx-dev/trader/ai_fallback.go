@@ -0,0 +1,31 @@
+package trader
+
+import (
+	"encoding/json"
+	"fmt"
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/store"
+	"strings"
+)
+
+// aiFallback counts a failed AI cycle and, once the strategy's ai_fallback_after_failures cycles failed in a row,
+// returns the deterministic decisions of its ai_fallback_mode flagged on the record (nil = skip the cycle)
+func (at *AutoTrader) aiFallback(ctx *decision.Context, reason string, record *store.DecisionRecord) *decision.FullDecision {
+	at.aiFailures++
+	rc := at.config.StrategyConfig.RiskControl
+	if !decision.IsFallbackMode(rc.AIFallbackMode) || at.aiFailures < max(1, rc.AIFallbackAfterFailures) {
+		return nil
+	}
+
+	fallback := decision.FallbackDecision(ctx, at.config.StrategyConfig, rc.AIFallbackMode, reason)
+	record.Fallback = rc.AIFallbackMode
+	record.CoTTrace = strings.TrimSpace(record.CoTTrace + "\n\n" + fallback.CoTTrace)
+	decisionJSON, _ := json.MarshalIndent(fallback.Decisions, "", "  ")
+	record.DecisionJSON = string(decisionJSON)
+
+	note := fmt.Sprintf("🛟 AI failed %d cycle(s) in a row, rule-based fallback %s decides: %s", at.aiFailures, rc.AIFallbackMode, reason)
+	logger.Warnf("  %s", note)
+	record.ExecutionLog = append(record.ExecutionLog, note)
+	return fallback
+}
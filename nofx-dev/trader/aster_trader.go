@@ -25,6 +25,11 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+func init() {
+	// Perpetuals base tier: 0.01% maker, 0.035% taker
+	logger.RegisterFeeSchedule("aster", logger.FeeSchedule{Maker: 0.0001, Taker: 0.00035})
+}
+
 // AsterTrader Aster trading platform implementation
 type AsterTrader struct {
 	ctx        context.Context
@@ -489,19 +494,13 @@ func (t *AsterTrader) GetBalance() (map[string]interface{}, error) {
 	totalMarginUsed := 0.0
 	realUnrealizedPnl := 0.0
 	for _, pos := range positions {
-		markPrice := pos["markPrice"].(float64)
-		quantity := pos["positionAmt"].(float64)
-		if quantity < 0 {
-			quantity = -quantity
-		}
-		unrealizedPnl := pos["unRealizedProfit"].(float64)
-		realUnrealizedPnl += unrealizedPnl
+		realUnrealizedPnl += pos.UnrealizedPnL
 
 		leverage := 10
-		if lev, ok := pos["leverage"].(float64); ok {
-			leverage = int(lev)
+		if pos.Leverage > 0 {
+			leverage = int(pos.Leverage)
 		}
-		marginUsed := (quantity * markPrice) / float64(leverage)
+		marginUsed := (pos.Quantity * pos.MarkPrice) / float64(leverage)
 		totalMarginUsed += marginUsed
 	}
 
@@ -520,7 +519,7 @@ func (t *AsterTrader) GetBalance() (map[string]interface{}, error) {
 }
 
 // GetPositions Get position information
-func (t *AsterTrader) GetPositions() ([]map[string]interface{}, error) {
+func (t *AsterTrader) GetPositions() ([]Position, error) {
 	params := make(map[string]interface{})
 	body, err := t.request("GET", "/fapi/v3/positionRisk", params)
 	if err != nil {
@@ -532,7 +531,7 @@ func (t *AsterTrader) GetPositions() ([]map[string]interface{}, error) {
 		return nil, err
 	}
 
-	result := []map[string]interface{}{}
+	result := []Position{}
 	for _, pos := range positions {
 		posAmtStr, ok := pos["positionAmt"].(string)
 		if !ok {
@@ -557,16 +556,16 @@ func (t *AsterTrader) GetPositions() ([]map[string]interface{}, error) {
 			posAmt = -posAmt
 		}
 
-		// Return same field names as Binance
-		result = append(result, map[string]interface{}{
-			"symbol":           pos["symbol"],
-			"side":             side,
-			"positionAmt":      posAmt,
-			"entryPrice":       entryPrice,
-			"markPrice":        markPrice,
-			"unRealizedProfit": unRealizedProfit,
-			"leverage":         leverageVal,
-			"liquidationPrice": liquidationPrice,
+		symbol, _ := pos["symbol"].(string)
+		result = append(result, Position{
+			Symbol:           symbol,
+			Side:             side,
+			Quantity:         posAmt,
+			EntryPrice:       entryPrice,
+			MarkPrice:        markPrice,
+			UnrealizedPnL:    unRealizedProfit,
+			Leverage:         leverageVal,
+			LiquidationPrice: liquidationPrice,
 		})
 	}
 
@@ -729,8 +728,8 @@ func (t *AsterTrader) CloseLong(symbol string, quantity float64) (map[string]int
 		}
 
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "long" {
-				quantity = pos["positionAmt"].(float64)
+			if pos.Symbol == symbol && pos.Side == "long" {
+				quantity = pos.Quantity
 				break
 			}
 		}
@@ -779,6 +778,7 @@ func (t *AsterTrader) CloseLong(symbol string, quantity float64) (map[string]int
 		"timeInForce":  "GTC",
 		"quantity":     qtyStr,
 		"price":        priceStr,
+		"reduceOnly":   "true", // Never flips the one-way (BOTH) position into a short
 	}
 
 	body, err := t.request("POST", "/fapi/v3/order", params)
@@ -811,9 +811,8 @@ func (t *AsterTrader) CloseShort(symbol string, quantity float64) (map[string]in
 		}
 
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "short" {
-				// Aster's GetPositions has already converted short position quantity to positive, use directly
-				quantity = pos["positionAmt"].(float64)
+			if pos.Symbol == symbol && pos.Side == "short" {
+				quantity = pos.Quantity
 				break
 			}
 		}
@@ -862,6 +861,7 @@ func (t *AsterTrader) CloseShort(symbol string, quantity float64) (map[string]in
 		"timeInForce":  "GTC",
 		"quantity":     qtyStr,
 		"price":        priceStr,
+		"reduceOnly":   "true", // Never flips the one-way (BOTH) position into a long
 	}
 
 	body, err := t.request("POST", "/fapi/v3/order", params)
@@ -1004,6 +1004,7 @@ func (t *AsterTrader) SetStopLoss(symbol string, positionSide string, quantity,
 		"stopPrice":    priceStr,
 		"quantity":     qtyStr,
 		"timeInForce":  "GTC",
+		"reduceOnly":   "true",
 	}
 
 	_, err = t.request("POST", "/fapi/v3/order", params)
@@ -1045,6 +1046,7 @@ func (t *AsterTrader) SetTakeProfit(symbol string, positionSide string, quantity
 		"stopPrice":    priceStr,
 		"quantity":     qtyStr,
 		"timeInForce":  "GTC",
+		"reduceOnly":   "true",
 	}
 
 	_, err = t.request("POST", "/fapi/v3/order", params)
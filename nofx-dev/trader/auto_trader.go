@@ -2,9 +2,11 @@ package trader
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"nofx/decision"
+	"nofx/events"
 	"nofx/logger"
 	"nofx/market"
 	"nofx/mcp"
@@ -23,8 +25,15 @@ type AutoTraderConfig struct {
 	AIModel string // AI model: "qwen" or "deepseek"
 
 	// Trading platform selection
-	Exchange   string // Exchange type: "binance", "bybit", "okx", "hyperliquid", "aster" or "lighter"
+	Exchange   string // Exchange type: "binance", "bybit", "okx", "mexc", "coinbase", "hyperliquid", "aster" or "lighter"
 	ExchangeID string // Exchange account UUID (for multi-account support)
+	QuoteAsset string // Quote/margin asset of the exchange account: USDT (default), USDC, BUSD, FDUSD
+
+	// Fallback exchange account, takes new entries while the primary exchange API is failing (optional)
+	FallbackExchange *store.Exchange
+
+	// Event callback (circuit breaker transitions etc.), set by the trader manager
+	OnEvent func(TraderEvent)
 
 	// Binance API configuration
 	BinanceAPIKey    string
@@ -39,6 +48,15 @@ type AutoTraderConfig struct {
 	OKXSecretKey  string
 	OKXPassphrase string
 
+	// MEXC API configuration
+	MEXCAPIKey    string
+	MEXCSecretKey string
+
+	// Coinbase International API configuration
+	CoinbaseAPIKey     string
+	CoinbaseSecretKey  string
+	CoinbasePassphrase string
+
 	// Hyperliquid configuration
 	HyperliquidPrivateKey string
 	HyperliquidWalletAddr string
@@ -82,6 +100,48 @@ type AutoTraderConfig struct {
 	// Competition visibility
 	ShowInCompetition bool // Whether to show in competition page
 
+	// Executed-decision webhook (optional), payloads are signed with WebhookSecret
+	WebhookURL    string
+	WebhookSecret string
+
+	// Decision outcome export sink (optional): http(s) URL or file:// path receiving JSONL training examples
+	OutcomeExportURL    string
+	OutcomeExportSecret string
+
+	// Cold-start performance context from a predecessor trader or backtest run (optional)
+	PerformanceSeed store.PerformanceSeed
+
+	// Quiet hours / digest batching of the owner's notifications (optional)
+	Notifications store.NotificationSettings
+
+	// Feature flag overrides of this trader (optional), flags left out follow the deployment
+	FeatureFlags map[string]bool
+
+	// Ensemble (optional, ensemble_mode flag): AIModelID is the trader's own model, EnsembleModels the
+	// additional models of Ensemble.ModelIDs that vote with it
+	AIModelID      string
+	Ensemble       store.EnsembleConfig
+	EnsembleModels []*store.AIModel
+
+	// Confidence gate (optional): ignore low-confidence entries, scale entry sizes by confidence
+	ConfidenceGate store.ConfidenceGate
+
+	// Liquidity filter (optional): min OI value / 24h volume of candidate coins, default 15M USD OI
+	LiquidityFilter store.LiquidityFilter
+
+	// Risk guardrail (optional): limits the leverage, size and stop distance of AI entries are clamped to
+	RiskGuardrail store.RiskGuardrail
+
+	// End-of-day flat mode (optional): close everything at a daily / pre-weekend time
+	FlatSchedule store.FlatSchedule
+
+	// Max holding time (optional): positions held longer are closed or flagged for AI review
+	MaxPositionAgeMinutes int
+	MaxPositionAgeAction  string // PositionAgeActionClose (default) / PositionAgeActionReview
+
+	// User-wide exposure lookup for the max_user_exposure_usd cap (set by the trader manager)
+	UserExposure UserExposureFunc
+
 	// Strategy configuration (use complete strategy config)
 	StrategyConfig *store.StrategyConfig // Strategy configuration (includes coin sources, indicators, risk control, prompts, etc.)
 }
@@ -95,27 +155,60 @@ type AutoTrader struct {
 	exchangeID            string // Exchange account UUID
 	showInCompetition     bool   // Whether to show in competition page
 	config                AutoTraderConfig
-	trader                Trader // Use Trader interface (supports multiple platforms)
+	trader                Trader          // Use Trader interface (supports multiple platforms)
+	failover              *FailoverTrader // Primary/fallback exchange router (wraps trader)
 	mcpClient             mcp.AIClient
-	store                 *store.Store             // Data storage (decision records, etc.)
-	strategyEngine        *decision.StrategyEngine // Strategy engine (uses strategy configuration)
-	cycleNumber           int                      // Current cycle number
+	store                 *store.Store              // Data storage (decision records, etc.)
+	strategyEngine        *decision.StrategyEngine  // Strategy engine (uses strategy configuration)
+	provider              decision.DecisionProvider // Decides instead of the AI model (nil = AI)
+	cycleNumber           int                       // Current cycle number
 	initialBalance        float64
 	dailyPnL              float64
 	customPrompt          string // Custom trading strategy prompt
 	overrideBasePrompt    bool   // Whether to override base prompt
 	lastResetTime         time.Time
+	lastDailyReport       *DailyReport // Summary of the previous day (built on daily P&L reset)
 	stopUntil             time.Time
 	isRunning             bool
-	startTime             time.Time          // System start time
-	callCount             int                // AI call count
-	positionFirstSeenTime map[string]int64   // Position first seen time (symbol_side -> timestamp in milliseconds)
-	stopMonitorCh         chan struct{}      // Used to stop monitoring goroutine
-	monitorWg             sync.WaitGroup     // Used to wait for monitoring goroutine to finish
-	peakPnLCache          map[string]float64 // Peak profit cache (symbol -> peak P&L percentage)
-	peakPnLCacheMutex     sync.RWMutex       // Cache read-write lock
-	lastBalanceSyncTime   time.Time          // Last balance sync time
-	userID                string             // User ID
+	startTime             time.Time             // System start time
+	callCount             int                   // AI call count
+	aiFailures            int                   // Consecutive cycles whose AI decision failed (rule-based fallback)
+	positionFirstSeenTime map[string]int64      // Position first seen time (symbol_side -> timestamp in milliseconds)
+	stopMonitorCh         chan struct{}         // Used to stop monitoring goroutine
+	monitorWg             sync.WaitGroup        // Used to wait for monitoring goroutine to finish
+	deliveries            sync.WaitGroup        // In-flight webhook deliveries, waited for on shutdown
+	peakPnLCache          map[string]float64    // Peak profit cache (symbol -> peak P&L percentage)
+	peakPnLCacheMutex     sync.RWMutex          // Cache read-write lock
+	trailing              trailingStops         // Client-side trailing stops for exchanges without native support
+	brackets              brackets              // Linked stop loss / take profit per position
+	breakEven             breakEvenStops        // Positions whose stop loss was moved to break-even
+	candidates            *candidateDecay       // Idle candidate tracking for prompt pruning
+	lastExposure          *ExposureSnapshot     // Account exposure of the last cycle
+	exposureAlerting      bool                  // Exposure alert raised and not yet cleared
+	exposureMu            sync.RWMutex          // Guards lastExposure and exposureAlerting
+	webhook               webhook               // Executed-decision webhook
+	flat                  flatState             // End-of-day flat schedule
+	dailyLoss             dailyLossState        // Daily loss kill-switch
+	drift                 driftState            // Leverage / margin mode drift alerts already raised
+	reconcile             reconcileState        // Reconciliation discrepancies already recorded
+	idle                  idleState             // Idle mode (paused / flat schedule) resource scaling
+	funding               fundingState          // Funding payments already accrued on position records
+	exits                 managedExits          // Exits the AI handed to a deterministic trailing stop
+	positionAge           positionAgeState      // Max holding time per position
+	margin                marginMonitorState    // Margin ratio monitor auto-reduce
+	liqGuard              liqGuardState         // Positions reported inside the liquidation guard distance
+	stopCooldown          decision.StopCooldown // Symbols recently stopped out, blocked from re-entry
+	hitRates              hitRateCache          // Win rate by confidence bucket for the expected value gate
+	notifications         notificationState     // Quiet hours / digest batching of webhook notifications
+	perfSeed              perfSeedState         // Cold-start seeding of recent trades
+	featureFlags          featureFlagState      // Per-trader feature flag overrides
+	ensemble              ensembleState         // Additional AI models voting on decisions
+	confidence            confidenceGateState   // Min confidence of entries and confidence-scaled sizing
+	liquidity             liquidityFilterState  // Min OI value / 24h volume of candidate coins
+	guardrail             riskGuardrailState    // Leverage / size / stop distance limits of AI entries
+	outcomes              outcomeExport         // Decision outcome export for fine-tuning
+	lastBalanceSyncTime   time.Time             // Last balance sync time
+	userID                string                // User ID
 }
 
 // NewAutoTrader creates an automatic trader
@@ -169,6 +262,11 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		mcpClient.SetAPIKey(config.CustomAPIKey, config.CustomAPIURL, config.CustomModelName)
 		logger.Infof("🤖 [%s] Using OpenAI", config.Name)
 
+	case "ollama":
+		mcpClient = mcp.NewOllamaClient()
+		mcpClient.SetAPIKey(config.CustomAPIKey, config.CustomAPIURL, config.CustomModelName)
+		logger.Infof("🤖 [%s] Using local Ollama AI", config.Name)
+
 	case "qwen":
 		mcpClient = mcp.NewQwenClient()
 		apiKey := config.QwenKey
@@ -223,6 +321,12 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 	case "okx":
 		logger.Infof("🏦 [%s] Using OKX Futures trading", config.Name)
 		trader = NewOKXTrader(config.OKXAPIKey, config.OKXSecretKey, config.OKXPassphrase)
+	case "mexc":
+		logger.Infof("🏦 [%s] Using MEXC Futures trading", config.Name)
+		trader = NewMEXCTrader(config.MEXCAPIKey, config.MEXCSecretKey)
+	case "coinbase":
+		logger.Infof("🏦 [%s] Using Coinbase International perpetuals trading", config.Name)
+		trader = NewCoinbaseTrader(config.CoinbaseAPIKey, config.CoinbaseSecretKey, config.CoinbasePassphrase)
 	case "hyperliquid":
 		logger.Infof("🏦 [%s] Using Hyperliquid trading", config.Name)
 		trader, err = NewHyperliquidTrader(config.HyperliquidPrivateKey, config.HyperliquidWalletAddr, config.HyperliquidTestnet)
@@ -262,6 +366,45 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		return nil, fmt.Errorf("unsupported trading platform: %s", config.Exchange)
 	}
 
+	// Wrap with fault injection layer for chaos testing (testnet only, never on live accounts)
+	if faultCfg := faultInjectionConfig(); faultCfg.Enabled {
+		if isTestnetTrader(config) {
+			logger.Warnf("⚡ [%s] Fault injection enabled (timeout=%.2f, reject=%.2f, partial=%.2f, ws_drop=%.2f)",
+				config.Name, faultCfg.TimeoutRate, faultCfg.RejectRate, faultCfg.PartialFillRate, faultCfg.WSDropRate)
+			trader = NewFaultInjectingTrader(trader, faultCfg, config.Name)
+		} else {
+			logger.Warnf("⚠️ [%s] Fault injection ignored: only available for testnet traders", config.Name)
+		}
+	}
+
+	// Classify all exchange errors into typed categories (rate limited, insufficient margin, etc.)
+	trader = NewClassifiedTrader(trader, config.Exchange)
+
+	// Retry orders on transient errors, checking by client order ID / position that a failed attempt didn't fill
+	trader = NewIdempotentTrader(trader, config.ID)
+
+	// Check close / stop-loss / take-profit quantities against the live position so a close can't flip it
+	trader = NewReduceOnlyTrader(trader, reduceOnlyAutoCorrect())
+
+	// Route through circuit breaker, new entries move to the fallback exchange while the primary is failing
+	failover := NewFailoverTrader(trader, config.Exchange, config.ExchangeID)
+	if fb := config.FallbackExchange; fb != nil && fb.ID != config.ExchangeID {
+		fallbackTrader, err := NewExchangeTrader(fb, userID)
+		if err != nil {
+			logger.Warnf("⚠️ [%s] Failed to initialize fallback exchange %s, failover disabled: %v", config.Name, fb.ExchangeType, err)
+		} else {
+			failover.SetFallback(NewReduceOnlyTrader(NewIdempotentTrader(NewClassifiedTrader(fallbackTrader, fb.ExchangeType), config.ID), reduceOnlyAutoCorrect()), fb.ExchangeType, fb.ID)
+			logger.Infof("🔀 [%s] Fallback exchange configured: %s", config.Name, fb.ExchangeType)
+		}
+	}
+	failover.OnEvent(func(event TraderEvent) {
+		event.TraderID = config.ID
+		if config.OnEvent != nil {
+			config.OnEvent(event)
+		}
+	})
+	trader = failover
+
 	// Validate initial balance configuration, auto-fetch from exchange if 0
 	if config.InitialBalance <= 0 {
 		logger.Infof("📊 [%s] Initial balance not set, attempting to fetch current balance from exchange...", config.Name)
@@ -306,8 +449,75 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		return nil, fmt.Errorf("[%s] strategy not configured", config.Name)
 	}
 	strategyEngine := decision.NewStrategyEngine(config.StrategyConfig)
+	strategyEngine.SetQuoteAsset(config.QuoteAsset)
 	logger.Infof("✓ [%s] Using strategy engine (strategy configuration loaded)", config.Name)
 
+	var provider decision.DecisionProvider
+	if name := config.StrategyConfig.DecisionProvider; name != "" {
+		if provider, err = decision.NewProvider(name, strategyEngine); err != nil {
+			logger.Warnf("⚠️ [%s] %v, the AI model decides", config.Name, err)
+		} else {
+			logger.Infof("✓ [%s] Decisions by provider %s instead of the AI model", config.Name, name)
+		}
+	}
+
+	flatSchedule, err := parseFlatSchedule(config.FlatSchedule)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Invalid end-of-day flat schedule, flat mode disabled: %v", config.Name, err)
+	}
+
+	notificationPolicy, err := parseNotificationSettings(config.Notifications)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Invalid notification settings, sending everything immediately: %v", config.Name, err)
+	}
+
+	perfSeed := config.PerformanceSeed
+	if err := ValidatePerformanceSeed(perfSeed); err != nil {
+		logger.Warnf("⚠️ [%s] Invalid performance seed, seeding disabled: %v", config.Name, err)
+		perfSeed = store.PerformanceSeed{}
+	}
+
+	featureFlags, err := copyFeatureFlags(config.FeatureFlags)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Invalid feature flag overrides, following the deployment flags: %v", config.Name, err)
+		featureFlags = map[string]bool{}
+	}
+
+	ensembleCfg, ensembleModels := config.Ensemble, config.EnsembleModels
+	if err := ValidateEnsemble(ensembleCfg); err != nil {
+		logger.Warnf("⚠️ [%s] Invalid ensemble, the trader's model decides alone: %v", config.Name, err)
+		ensembleCfg, ensembleModels = store.EnsembleConfig{}, nil
+	}
+
+	confidenceGate := config.ConfidenceGate
+	if err := ValidateConfidenceGate(confidenceGate); err != nil {
+		logger.Warnf("⚠️ [%s] Invalid confidence gate, confidence gating disabled: %v", config.Name, err)
+		confidenceGate = store.ConfidenceGate{}
+	}
+
+	liquidityFilter := config.LiquidityFilter
+	if err := ValidateLiquidityFilter(liquidityFilter); err != nil {
+		logger.Warnf("⚠️ [%s] Invalid liquidity filter, using the default OI threshold: %v", config.Name, err)
+		liquidityFilter = store.LiquidityFilter{}
+	}
+
+	riskGuardrail := config.RiskGuardrail
+	if err := ValidateRiskGuardrail(riskGuardrail); err != nil {
+		logger.Warnf("⚠️ [%s] Invalid risk guardrail, entries are not clamped: %v", config.Name, err)
+		riskGuardrail = store.RiskGuardrail{}
+	}
+
+	var maxPositionAge time.Duration
+	maxPositionAgeAction := PositionAgeActionClose
+	if err := ValidatePositionAge(config.MaxPositionAgeMinutes, config.MaxPositionAgeAction); err != nil {
+		logger.Warnf("⚠️ [%s] Invalid max position age, limit disabled: %v", config.Name, err)
+	} else {
+		maxPositionAge = time.Duration(config.MaxPositionAgeMinutes) * time.Minute
+		if config.MaxPositionAgeAction != "" {
+			maxPositionAgeAction = config.MaxPositionAgeAction
+		}
+	}
+
 	return &AutoTrader{
 		id:                    config.ID,
 		name:                  config.Name,
@@ -317,9 +527,11 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		showInCompetition:     config.ShowInCompetition,
 		config:                config,
 		trader:                trader,
+		failover:              failover,
 		mcpClient:             mcpClient,
 		store:                 st,
 		strategyEngine:        strategyEngine,
+		provider:              provider,
 		cycleNumber:           cycleNumber,
 		initialBalance:        config.InitialBalance,
 		lastResetTime:         time.Now(),
@@ -331,6 +543,22 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		monitorWg:             sync.WaitGroup{},
 		peakPnLCache:          make(map[string]float64),
 		peakPnLCacheMutex:     sync.RWMutex{},
+		trailing:              trailingStops{stops: make(map[string]*emulatedTrailingStop)},
+		brackets:              brackets{legs: make(map[string]*bracket)},
+		breakEven:             breakEvenStops{moved: make(map[string]bool)},
+		exits:                 managedExits{trails: make(map[string]*managedExit)},
+		positionAge:           positionAgeState{limit: maxPositionAge, action: maxPositionAgeAction},
+		candidates:            newCandidateDecay(),
+		webhook:               webhook{url: config.WebhookURL, secret: config.WebhookSecret},
+		outcomes:              outcomeExport{url: config.OutcomeExportURL, secret: config.OutcomeExportSecret},
+		flat:                  flatState{schedule: flatSchedule},
+		notifications:         notificationState{policy: notificationPolicy},
+		perfSeed:              perfSeedState{seed: perfSeed},
+		featureFlags:          featureFlagState{overrides: featureFlags},
+		ensemble:              ensembleState{config: ensembleCfg, members: newEnsembleMembers(ensembleModels)},
+		confidence:            confidenceGateState{gate: confidenceGate},
+		liquidity:             liquidityFilterState{filter: liquidityFilter},
+		guardrail:             riskGuardrailState{guardrail: riskGuardrail},
 		lastBalanceSyncTime:   time.Now(),
 		userID:                userID,
 	}, nil
@@ -348,6 +576,25 @@ func (at *AutoTrader) Run() error {
 	logger.Info("🤖 AI will make full decisions on leverage, position size, stop loss/take profit, etc.")
 	at.monitorWg.Add(1)
 	defer at.monitorWg.Done()
+	if !isTestnetTrader(at.config) {
+		runningLiveTraders.Add(1)
+		defer runningLiveTraders.Add(-1)
+	}
+	at.restoreDailyLossPause()
+
+	// Import positions that already exist on the account (opened manually or before this trader was attached)
+	if n, err := at.ImportPositions(); err != nil {
+		logger.Infof("⚠️ Failed to import existing positions: %v", err)
+	} else if n > 0 {
+		logger.Infof("📥 Imported %d existing position(s) from exchange", n)
+	}
+
+	// Backfill exchange history on first run (in background, may take several API calls)
+	go func() {
+		if _, err := at.BackfillHistory(historyBackfillDays()); err != nil {
+			logger.Infof("⚠️ Failed to backfill trade history: %v", err)
+		}
+	}()
 
 	// Start drawdown monitoring
 	at.startDrawdownMonitor()
@@ -355,6 +602,13 @@ func (at *AutoTrader) Run() error {
 	ticker := time.NewTicker(at.config.ScanInterval)
 	defer ticker.Stop()
 
+	// Candle-close cycles replace the scan interval ticks
+	candleClose, stopClock := at.candleCloseClock()
+	defer stopClock()
+	if candleClose != nil {
+		ticker.Stop()
+	}
+
 	// Execute immediately on first run
 	if err := at.runCycle(); err != nil {
 		logger.Infof("❌ Execution failed: %v", err)
@@ -366,6 +620,16 @@ func (at *AutoTrader) Run() error {
 			if err := at.runCycle(); err != nil {
 				logger.Infof("❌ Execution failed: %v", err)
 			}
+		case _, ok := <-candleClose:
+			if !ok {
+				// Clock unsubscribed: fall back to the scan interval
+				candleClose = nil
+				ticker.Reset(at.config.ScanInterval)
+				continue
+			}
+			if err := at.runCycle(); err != nil {
+				logger.Infof("❌ Execution failed: %v", err)
+			}
 		case <-at.stopMonitorCh:
 			logger.Infof("[%s] ⏹ Stop signal received, exiting automatic trading main loop", at.name)
 			return nil
@@ -382,7 +646,9 @@ func (at *AutoTrader) Stop() {
 	}
 	at.isRunning = false
 	close(at.stopMonitorCh) // Notify monitoring goroutine to stop
-	at.monitorWg.Wait()     // Wait for monitoring goroutine to finish
+	at.monitorWg.Wait()     // Wait for the running cycle and monitoring goroutines to finish
+	at.flushOnShutdown()
+	at.releaseStreams()
 	logger.Info("⏹ Automatic trading system stopped")
 }
 
@@ -394,6 +660,9 @@ func (at *AutoTrader) runCycle() error {
 	logger.Infof("⏰ %s - AI decision cycle #%d", time.Now().Format("2006-01-02 15:04:05"), at.callCount)
 	logger.Info(strings.Repeat("=", 70))
 
+	// Pick up runtime feature flag changes
+	at.applyFeatureFlags()
+
 	// Create decision record
 	record := &store.DecisionRecord{
 		ExecutionLog: []string{},
@@ -410,8 +679,27 @@ func (at *AutoTrader) runCycle() error {
 		return nil
 	}
 
+	// Daily loss kill-switch: paused until the next UTC day
+	if paused, until := at.dailyLossPaused(time.Now()); paused {
+		logger.Infof("🛑 Daily loss limit reached, trading paused until %s", until.Format(time.RFC3339))
+		record.Success = false
+		record.ErrorMessage = fmt.Sprintf("Daily loss limit reached, paused until %s", until.Format(time.RFC3339))
+		at.saveDecision(record)
+		return nil
+	}
+
+	// End-of-day flat period: the monitor keeps the account flat, no AI call needed
+	if at.flatActive(time.Now()) {
+		logger.Infof("🌙 End-of-day flat period active, skipping decision cycle")
+		at.checkFlatSchedule()
+		return nil
+	}
+
 	// 2. Reset daily P&L (reset every day)
 	if time.Since(at.lastResetTime) > 24*time.Hour {
+		at.lastDailyReport = at.buildDailyReport()
+		logger.Infof("📋 [%s] Daily report %s: P&L %.2f, equity %.2f, stress %s", at.name,
+			at.lastDailyReport.Date, at.lastDailyReport.DailyPnL, at.lastDailyReport.Equity, at.lastDailyReport.StressSummary)
 		at.dailyPnL = 0
 		at.lastResetTime = time.Now()
 		logger.Info("📅 Daily P&L reset")
@@ -426,8 +714,11 @@ func (at *AutoTrader) runCycle() error {
 		return fmt.Errorf("failed to build trading context: %w", err)
 	}
 
+	record.Positions = at.positionSnapshots(ctx.Positions)
+
 	// Save equity snapshot independently (decoupled from AI decision, used for drawing profit curve)
 	at.saveEquitySnapshot(ctx)
+	at.updateExposure(ctx)
 
 	logger.Info(strings.Repeat("=", 70))
 	for _, coin := range ctx.CandidateCoins {
@@ -437,9 +728,25 @@ func (at *AutoTrader) runCycle() error {
 	logger.Infof("📊 Account equity: %.2f USDT | Available: %.2f USDT | Positions: %d",
 		ctx.Account.TotalEquity, ctx.Account.AvailableBalance, ctx.Account.PositionCount)
 
-	// 5. Use strategy engine to call AI for decision
-	logger.Infof("🤖 Requesting AI analysis and decision... [Strategy Engine]")
-	aiDecision, err := decision.GetFullDecisionWithStrategy(ctx, at.mcpClient, at.strategyEngine, "balanced")
+	// 5. Use strategy engine to call AI for decision (or the strategy's decision provider)
+	if at.provider != nil {
+		record.Provider = at.provider.Name()
+		logger.Infof("⚙️ Requesting decisions from provider %s... [Strategy Engine]", record.Provider)
+	} else {
+		logger.Infof("🤖 Requesting AI analysis and decision... [Strategy Engine]")
+	}
+	var ensembleNotes []string
+	var modelUsage []store.ModelUsage
+	aiDecision, err := at.requestDecision(func() (*decision.FullDecision, error) {
+		if at.provider != nil {
+			return at.provider.Decide(ctx)
+		}
+		full, notes, usage, err := at.requestEnsembleDecision(ctx)
+		ensembleNotes = notes
+		modelUsage = usage
+		return full, err
+	})
+	record.ExecutionLog = append(record.ExecutionLog, liquidityNotes(ctx.LiquidityChecks)...)
 
 	if aiDecision != nil && aiDecision.AIRequestDurationMs > 0 {
 		record.AIRequestDurationMs = aiDecision.AIRequestDurationMs
@@ -454,6 +761,15 @@ func (at *AutoTrader) runCycle() error {
 		record.InputPrompt = aiDecision.UserPrompt
 		record.CoTTrace = aiDecision.CoTTrace
 		record.RawResponse = aiDecision.RawResponse // Save raw AI response for debugging
+		record.PromptTokens = aiDecision.Usage.PromptTokens
+		record.CompletionTokens = aiDecision.Usage.CompletionTokens
+		record.AICostUSD = aiDecision.Usage.CostUSD
+		record.ModelUsage = modelUsage
+		record.PromptHash = aiDecision.PromptHash
+		if record.Provider == "" {
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("AI usage: %d prompt + %d completion tokens, ~$%.4f",
+				record.PromptTokens, record.CompletionTokens, record.AICostUSD))
+		}
 		if len(aiDecision.Decisions) > 0 {
 			decisionJSON, _ := json.MarshalIndent(aiDecision.Decisions, "", "  ")
 			record.DecisionJSON = string(decisionJSON)
@@ -481,9 +797,38 @@ func (at *AutoTrader) runCycle() error {
 			}
 		}
 
-		at.saveDecision(record)
-		return fmt.Errorf("failed to get AI decision: %w", err)
+		// Providers are deterministic, the AI fallback only stands in for the AI model
+		var fallback *decision.FullDecision
+		if !errors.Is(err, errShuttingDown) && at.provider == nil {
+			fallback = at.aiFallback(ctx, err.Error(), record)
+		}
+		if fallback == nil {
+			at.saveDecision(record)
+			return fmt.Errorf("failed to get AI decision: %w", err)
+		}
+		aiDecision = fallback
+	} else if aiDecision.SchemaRepair == decision.SchemaFailed {
+		if fallback := at.aiFallback(ctx, "decision JSON invalid after repair", record); fallback != nil {
+			aiDecision.Decisions = fallback.Decisions
+		}
+	} else {
+		at.aiFailures = 0
+	}
+	for _, note := range ensembleNotes {
+		logger.Infof("  %s", note)
 	}
+	record.ExecutionLog = append(record.ExecutionLog, ensembleNotes...)
+	for _, call := range aiDecision.ToolCalls {
+		logger.Infof("  %s", call)
+	}
+	record.ExecutionLog = append(record.ExecutionLog, aiDecision.ToolCalls...)
+	if note := aiDecision.SchemaRepairNote(); note != "" {
+		logger.Warnf("  %s", note)
+		record.ExecutionLog = append(record.ExecutionLog, note)
+		record.RawResponse += "\n\n--- schema repair ---\n" + aiDecision.RepairResponse
+	}
+	at.reflectOnDecisions(ctx, aiDecision, record)
+	at.observeCandidateDecisions(ctx, aiDecision.Decisions)
 
 	// // 5. Print system prompt
 	// logger.Infof("\n" + strings.Repeat("=", 70))
@@ -515,6 +860,43 @@ func (at *AutoTrader) runCycle() error {
 
 	// 8. Sort decisions: ensure close positions first, then open positions (prevent position stacking overflow)
 	sortedDecisions := sortDecisionsByPriority(aiDecision.Decisions)
+	// [CODE ENFORCED] Watch-only coins are never traded
+	var watchNotes []string
+	sortedDecisions, watchNotes = decision.FilterWatchOnly(sortedDecisions, at.watchOnlySymbols())
+	for _, note := range watchNotes {
+		logger.Infof("  %s", note)
+	}
+	record.ExecutionLog = append(record.ExecutionLog, watchNotes...)
+	sortedDecisions = at.applyConfidenceGate(sortedDecisions, record)
+	if rc := at.config.StrategyConfig.RiskControl; rc.ATRStopMultiplier > 0 {
+		notes := decision.ApplyATRStops(sortedDecisions, ctx.MarketDataMap, at.config.StrategyConfig.Indicators.Klines.PrimaryTimeframe, rc.ATRStopMultiplier)
+		for _, note := range notes {
+			logger.Infof("  %s", note)
+		}
+		record.ExecutionLog = append(record.ExecutionLog, notes...)
+	}
+	// [CODE ENFORCED] Stop loss / take profit on the exchange's tick grid
+	tickNotes := decision.RoundPricesToTick(sortedDecisions, ctx.MarketDataMap)
+	for _, note := range tickNotes {
+		logger.Infof("  %s", note)
+	}
+	record.ExecutionLog = append(record.ExecutionLog, tickNotes...)
+	if rc := at.config.StrategyConfig.RiskControl; rc.VolTargetATRPct > 0 {
+		notes := decision.ApplyVolatilityLeverage(sortedDecisions, ctx.MarketDataMap, at.config.StrategyConfig.Indicators.Klines.PrimaryTimeframe,
+			rc.VolTargetATRPct, rc.BTCETHMaxLeverage, rc.AltcoinMaxLeverage)
+		for _, note := range notes {
+			logger.Infof("  %s", note)
+		}
+		record.ExecutionLog = append(record.ExecutionLog, notes...)
+	}
+	// [CODE ENFORCED] Per-trader guardrail on leverage, position size and stop distance
+	at.applyRiskGuardrail(sortedDecisions, ctx.MarketDataMap, ctx.Account.TotalEquity, record)
+	var evNotes []string
+	sortedDecisions, evNotes = at.applyExpectedValueGate(sortedDecisions, ctx.MarketDataMap)
+	for _, note := range evNotes {
+		logger.Infof("  %s", note)
+	}
+	record.ExecutionLog = append(record.ExecutionLog, evNotes...)
 
 	logger.Info("🔄 Execution order (optimized): Close positions first → Open positions later")
 	for i, d := range sortedDecisions {
@@ -534,13 +916,22 @@ func (at *AutoTrader) runCycle() error {
 			Success:   false,
 		}
 
-		if err := at.executeDecisionWithRecord(&d, &actionRecord); err != nil {
-			logger.Infof("❌ Failed to execute decision (%s %s): %v", d.Symbol, d.Action, err)
+		err := at.checkShutdownEntry(d.Action)
+		if err == nil {
+			err = at.executeDecisionWithRecord(&d, &actionRecord)
+		}
+		if err != nil {
+			logger.Infof("❌ Failed to execute decision (%s %s): %s", d.Symbol, d.Action, describeError(err))
 			actionRecord.Error = err.Error()
-			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("❌ %s %s failed: %v", d.Symbol, d.Action, err))
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("❌ %s %s failed: %s", d.Symbol, d.Action, describeError(err)))
 		} else {
 			actionRecord.Success = true
 			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("✓ %s %s succeeded", d.Symbol, d.Action))
+			if actionRecord.RequestedStopLoss > 0 {
+				record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("📐 %s stop loss %.6g → %.6g (beyond %s)",
+					d.Symbol, actionRecord.RequestedStopLoss, actionRecord.StopLoss, actionRecord.StopAdjustReason))
+			}
+			at.notifyWebhook(&d, &actionRecord)
 			// Brief delay after successful execution
 			time.Sleep(1 * time.Second)
 		}
@@ -595,27 +986,29 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 	currentPositionKeys := make(map[string]bool)
 
 	for _, pos := range positions {
-		symbol := pos["symbol"].(string)
-		side := pos["side"].(string)
-		entryPrice := pos["entryPrice"].(float64)
-		markPrice := pos["markPrice"].(float64)
-		quantity := pos["positionAmt"].(float64)
-		if quantity < 0 {
-			quantity = -quantity // Short position quantity is negative, convert to positive
+		// Exchange circuit open: snapshot prices are stale and orders can't be sent
+		if pos.ReadOnly {
+			continue
 		}
+		symbol := pos.Symbol
+		side := pos.Side
+		entryPrice := pos.EntryPrice
+		markPrice := pos.MarkPrice
+		quantity := pos.Quantity
 
 		// Skip closed positions (quantity = 0), prevent "ghost positions" from being passed to AI
 		if quantity == 0 {
 			continue
 		}
 
-		unrealizedPnl := pos["unRealizedProfit"].(float64)
-		liquidationPrice := pos["liquidationPrice"].(float64)
+		unrealizedPnl := pos.UnrealizedPnL
+		liquidationPrice := positionLiquidationPrice(pos, at.config.IsCrossMargin) // Estimated for isolated positions without one
+		liquidationDistance, _ := liquidationDistancePct(side, markPrice, liquidationPrice)
 
 		// Calculate margin used (estimated)
-		leverage := 10 // Default value, should actually be fetched from position info
-		if lev, ok := pos["leverage"].(float64); ok {
-			leverage = int(lev)
+		leverage := 10 // Default value when the exchange doesn't report leverage
+		if pos.Leverage > 0 {
+			leverage = int(pos.Leverage)
 		}
 		marginUsed := (quantity * markPrice) / float64(leverage)
 		totalMarginUsed += marginUsed
@@ -630,7 +1023,7 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 		var updateTime int64
 		// Priority 1: Get from database (trader_positions table) - most accurate
 		if at.store != nil {
-			if dbPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, symbol, side); err == nil && dbPos != nil {
+			if dbPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, symbol, strings.ToUpper(side)); err == nil && dbPos != nil {
 				if !dbPos.EntryTime.IsZero() {
 					updateTime = dbPos.EntryTime.UnixMilli()
 				}
@@ -638,9 +1031,7 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 		}
 		// Priority 2: Get from exchange API (Bybit: createdTime, OKX: createdTime)
 		if updateTime == 0 {
-			if createdTime, ok := pos["createdTime"].(int64); ok && createdTime > 0 {
-				updateTime = createdTime
-			}
+			updateTime = pos.CreatedTime
 		}
 		// Priority 3: Fallback to local tracking
 		if updateTime == 0 {
@@ -666,8 +1057,10 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 			UnrealizedPnLPct: pnlPct,
 			PeakPnLPct:       peakPnlPct,
 			LiquidationPrice: liquidationPrice,
+			LiquidationDist:  liquidationDistance,
 			MarginUsed:       marginUsed,
 			UpdateTime:       updateTime,
+			ManagedExit:      at.managedExitSummary(symbol, side),
 		})
 	}
 
@@ -688,6 +1081,25 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 	}
 	logger.Infof("📋 [%s] Strategy engine fetched candidate coins: %d", at.name, len(candidateCoins))
 
+	// Drop candidates the AI has ignored for too long (positions and watch-only coins are always kept)
+	held := at.watchOnlySymbols()
+	for _, pos := range positionInfos {
+		held[pos.Symbol] = true
+	}
+	var pruned []string
+	candidateCoins, pruned = at.candidates.filter(candidateCoins, held, at.strategyEngine.GetConfig().CoinSource.CandidateHalfLife)
+	if len(pruned) > 0 {
+		logger.Infof("📋 [%s] Pruned %d idle candidate(s) from prompt: %v", at.name, len(pruned), pruned)
+	}
+	streamSymbols := make([]string, 0, len(candidateCoins)+len(positionInfos))
+	for _, coin := range candidateCoins {
+		streamSymbols = append(streamSymbols, coin.Symbol)
+	}
+	for _, pos := range positionInfos {
+		streamSymbols = append(streamSymbols, pos.Symbol)
+	}
+	at.acquireStreams(streamSymbols)
+
 	// 4. Calculate total P&L
 	totalPnL := totalEquity - at.initialBalance
 	totalPnLPct := 0.0
@@ -726,28 +1138,29 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 		Positions:      positionInfos,
 		CandidateCoins: candidateCoins,
 	}
+	if limit, action := at.maxPositionAge(); action == PositionAgeActionReview {
+		ctx.ReviewAge = limit
+	}
+	ctx.LiqGuardPct = at.liquidationGuardPct()
+	ctx.LiquidityFilter = at.liquidityFilter()
+	ctx.ToolSource = decision.LiveToolSource{}
 
 	// 7. Add recent closed trades (if store is available)
 	if at.store != nil {
-		// Get recent 10 closed trades for AI context
-		recentTrades, err := at.store.Position().GetRecentTrades(at.id, 10)
+		// Get recent closed trades for AI context
+		recentTrades, err := at.store.Position().GetRecentTrades(at.id, recentTradesWindow)
 		if err != nil {
 			logger.Infof("⚠️ [%s] Failed to get recent trades: %v", at.name, err)
 		} else {
 			logger.Infof("📊 [%s] Found %d recent closed trades for AI context", at.name, len(recentTrades))
 			for _, trade := range recentTrades {
-				ctx.RecentOrders = append(ctx.RecentOrders, decision.RecentOrder{
-					Symbol:       trade.Symbol,
-					Side:         trade.Side,
-					EntryPrice:   trade.EntryPrice,
-					ExitPrice:    trade.ExitPrice,
-					RealizedPnL:  trade.RealizedPnL,
-					PnLPct:       trade.PnLPct,
-					EntryTime:    trade.EntryTime,
-					ExitTime:     trade.ExitTime,
-					HoldDuration: trade.HoldDuration,
-				})
+				ctx.RecentOrders = append(ctx.RecentOrders, recentOrderFromTrade(trade, false))
 			}
+			// New trader: top up with a predecessor's / backtest's trades until enough live trades exist
+			at.applyPerformanceSeed(ctx)
+		}
+		if strategyConfig.Indicators.EnableTradeMemory {
+			ctx.TradeMemory = at.loadTradeMemory()
 		}
 	} else {
 		logger.Infof("⚠️ [%s] Store is nil, cannot get recent trades", at.name)
@@ -784,10 +1197,24 @@ func (at *AutoTrader) executeDecisionWithRecord(decision *decision.Decision, act
 		return at.executeOpenLongWithRecord(decision, actionRecord)
 	case "open_short":
 		return at.executeOpenShortWithRecord(decision, actionRecord)
+	case "add_long":
+		return at.executeAddWithRecord(decision, actionRecord, "long")
+	case "add_short":
+		return at.executeAddWithRecord(decision, actionRecord, "short")
 	case "close_long":
 		return at.executeCloseLongWithRecord(decision, actionRecord)
 	case "close_short":
 		return at.executeCloseShortWithRecord(decision, actionRecord)
+	case "partial_close_long":
+		return at.executePartialCloseWithRecord(decision, actionRecord, "long")
+	case "partial_close_short":
+		return at.executePartialCloseWithRecord(decision, actionRecord, "short")
+	case "manage_exit_long":
+		return at.executeManageExitWithRecord(decision, actionRecord, "long")
+	case "manage_exit_short":
+		return at.executeManageExitWithRecord(decision, actionRecord, "short")
+	case "alert":
+		return at.executeAlertWithRecord(decision)
 	case "hold", "wait":
 		// No execution needed, just record
 		return nil
@@ -800,6 +1227,11 @@ func (at *AutoTrader) executeDecisionWithRecord(decision *decision.Decision, act
 func (at *AutoTrader) executeOpenLongWithRecord(decision *decision.Decision, actionRecord *store.DecisionAction) error {
 	logger.Infof("  📈 Open long: %s", decision.Symbol)
 
+	// Primary exchange failing without a usable fallback: no new entries
+	if err := at.entriesAllowed(); err != nil {
+		return err
+	}
+
 	// ⚠️ Get current positions for multiple checks
 	positions, err := at.trader.GetPositions()
 	if err != nil {
@@ -811,9 +1243,24 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *decision.Decision, act
 		return err
 	}
 
+	// [CODE ENFORCED] No new cross margin entries while positions are near liquidation
+	if err := at.enforceLiquidationGuard(positions, decision.Symbol, "long"); err != nil {
+		return err
+	}
+
+	// [CODE ENFORCED] No re-entry right after a stop-loss exit on the same symbol
+	if err := at.stopCooldown.Check(decision.Symbol, time.Now()); err != nil {
+		return err
+	}
+
+	// [CODE ENFORCED] No new entries around high-impact events (FOMC, CPI, token unlocks)
+	if err := at.checkEventPause(events.Default(), decision.Symbol, time.Now()); err != nil {
+		return err
+	}
+
 	// Check if there's already a position in the same symbol and direction
 	for _, pos := range positions {
-		if pos["symbol"] == decision.Symbol && pos["side"] == "long" {
+		if pos.Symbol == decision.Symbol && pos.Side == "long" {
 			return fmt.Errorf("❌ %s already has long position, close it first", decision.Symbol)
 		}
 	}
@@ -824,15 +1271,15 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *decision.Decision, act
 		return err
 	}
 
+	// [CODE ENFORCED] Leverage within the contract's max leverage
+	at.enforceSymbolMaxLeverage(decision, actionRecord)
+
 	// Get balance (needed for multiple checks)
 	balance, err := at.trader.GetBalance()
 	if err != nil {
 		return fmt.Errorf("failed to get account balance: %w", err)
 	}
-	availableBalance := 0.0
-	if avail, ok := balance["availableBalance"].(float64); ok {
-		availableBalance = avail
-	}
+	availableBalance := availableForSymbol(balance, decision.Symbol)
 
 	// Get equity for position value ratio check
 	equity := 0.0
@@ -844,12 +1291,24 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *decision.Decision, act
 		equity = availableBalance // Fallback to available balance
 	}
 
+	// [CODE ENFORCED] Risk-based sizing: position size from stop distance and risk_per_trade_pct of equity
+	at.applyRiskSizing(decision, marketData.CurrentPrice, equity)
+
 	// [CODE ENFORCED] Position Value Ratio Check: position_value <= equity × ratio
 	adjustedPositionSize, wasCapped := at.enforcePositionValueRatio(decision.PositionSizeUSD, equity, decision.Symbol)
 	if wasCapped {
 		decision.PositionSizeUSD = adjustedPositionSize
 	}
 
+	// [CODE ENFORCED] Liquidity cap: position_value <= min(1h volume × pct, book depth × pct)
+	adjusted, capped, err := at.enforceLiquidityCap(decision.PositionSizeUSD, decision.Symbol)
+	if err != nil {
+		return err
+	}
+	if capped {
+		decision.PositionSizeUSD = adjusted
+	}
+
 	// ⚠️ Auto-adjust position size if insufficient margin
 	// Formula: totalRequired = positionSize/leverage + positionSize*0.001 + positionSize/leverage*0.01
 	//        = positionSize * (1.01/leverage + 0.001)
@@ -866,6 +1325,21 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *decision.Decision, act
 		decision.PositionSizeUSD = actualPositionSize
 	}
 
+	// [CODE ENFORCED] Estimated slippage from order book depth: downsize to what fills within the bps limit
+	adjusted, capped, err = at.enforceSlippageLimit(decision.PositionSizeUSD, decision.Symbol, "buy")
+	if err != nil {
+		return err
+	}
+	if capped {
+		actualPositionSize = adjusted
+		decision.PositionSizeUSD = adjusted
+	}
+
+	// [CODE ENFORCED] Exposure caps: total / per-symbol / user-wide notional after this entry
+	if err := at.enforceExposureLimits(decision.Symbol, decision.PositionSizeUSD); err != nil {
+		return err
+	}
+
 	// [CODE ENFORCED] Minimum position size check
 	if err := at.enforceMinPositionSize(decision.PositionSizeUSD); err != nil {
 		return err
@@ -882,8 +1356,14 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *decision.Decision, act
 		// Continue execution, doesn't affect trading
 	}
 
-	// Open position
-	order, err := at.trader.OpenLong(decision.Symbol, quantity, decision.Leverage)
+	// Move the stop loss beyond nearby liquidity before placing it
+	at.snapStopLoss(decision, "long", actionRecord)
+
+	// Open position, submitted together with its stop loss and take profit where the exchange batches them
+	order, batched, err := at.openWithBracket(decision, "LONG", quantity)
+	if !batched {
+		order, err = at.trader.OpenLong(decision.Symbol, quantity, decision.Leverage)
+	}
 	if err != nil {
 		return err
 	}
@@ -897,17 +1377,27 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *decision.Decision, act
 
 	// Record order to database and poll for confirmation
 	at.recordAndConfirmOrder(order, decision.Symbol, "open_long", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	at.rememberEntry(decision, "LONG", marketData, order)
 
 	// Record position opening time
 	posKey := decision.Symbol + "_long"
 	at.positionFirstSeenTime[posKey] = time.Now().UnixMilli()
 
-	// Set stop loss and take profit
-	if err := at.trader.SetStopLoss(decision.Symbol, "LONG", quantity, decision.StopLoss); err != nil {
-		logger.Infof("  ⚠ Failed to set stop loss: %v", err)
+	// Set stop loss and take profit as a linked bracket (one filling cancels the other)
+	if !batched {
+		at.setBracket(decision.Symbol, "LONG", quantity, decision.StopLoss, decision.TakeProfit)
+	}
+
+	// Trailing stop (lets winners run, on top of the fixed stop loss)
+	if pct := at.trailingStopPct(decision); pct > 0 {
+		at.setTrailingStop(decision.Symbol, "LONG", quantity, pct)
 	}
-	if err := at.trader.SetTakeProfit(decision.Symbol, "LONG", quantity, decision.TakeProfit); err != nil {
-		logger.Infof("  ⚠ Failed to set take profit: %v", err)
+
+	// Exit handed to a managed trailing stop right away
+	if decision.ManagedExit != nil {
+		if _, err := at.handOffExit(decision.Symbol, "long", *decision.ManagedExit); err != nil {
+			logger.Infof("  ⚠ Failed to set managed exit: %v", err)
+		}
 	}
 
 	return nil
@@ -917,6 +1407,11 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *decision.Decision, act
 func (at *AutoTrader) executeOpenShortWithRecord(decision *decision.Decision, actionRecord *store.DecisionAction) error {
 	logger.Infof("  📉 Open short: %s", decision.Symbol)
 
+	// Primary exchange failing without a usable fallback: no new entries
+	if err := at.entriesAllowed(); err != nil {
+		return err
+	}
+
 	// ⚠️ Get current positions for multiple checks
 	positions, err := at.trader.GetPositions()
 	if err != nil {
@@ -928,9 +1423,24 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *decision.Decision, ac
 		return err
 	}
 
+	// [CODE ENFORCED] No new cross margin entries while positions are near liquidation
+	if err := at.enforceLiquidationGuard(positions, decision.Symbol, "short"); err != nil {
+		return err
+	}
+
+	// [CODE ENFORCED] No re-entry right after a stop-loss exit on the same symbol
+	if err := at.stopCooldown.Check(decision.Symbol, time.Now()); err != nil {
+		return err
+	}
+
+	// [CODE ENFORCED] No new entries around high-impact events (FOMC, CPI, token unlocks)
+	if err := at.checkEventPause(events.Default(), decision.Symbol, time.Now()); err != nil {
+		return err
+	}
+
 	// Check if there's already a position in the same symbol and direction
 	for _, pos := range positions {
-		if pos["symbol"] == decision.Symbol && pos["side"] == "short" {
+		if pos.Symbol == decision.Symbol && pos.Side == "short" {
 			return fmt.Errorf("❌ %s already has short position, close it first", decision.Symbol)
 		}
 	}
@@ -941,15 +1451,15 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *decision.Decision, ac
 		return err
 	}
 
+	// [CODE ENFORCED] Leverage within the contract's max leverage
+	at.enforceSymbolMaxLeverage(decision, actionRecord)
+
 	// Get balance (needed for multiple checks)
 	balance, err := at.trader.GetBalance()
 	if err != nil {
 		return fmt.Errorf("failed to get account balance: %w", err)
 	}
-	availableBalance := 0.0
-	if avail, ok := balance["availableBalance"].(float64); ok {
-		availableBalance = avail
-	}
+	availableBalance := availableForSymbol(balance, decision.Symbol)
 
 	// Get equity for position value ratio check
 	equity := 0.0
@@ -961,12 +1471,24 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *decision.Decision, ac
 		equity = availableBalance // Fallback to available balance
 	}
 
+	// [CODE ENFORCED] Risk-based sizing: position size from stop distance and risk_per_trade_pct of equity
+	at.applyRiskSizing(decision, marketData.CurrentPrice, equity)
+
 	// [CODE ENFORCED] Position Value Ratio Check: position_value <= equity × ratio
 	adjustedPositionSize, wasCapped := at.enforcePositionValueRatio(decision.PositionSizeUSD, equity, decision.Symbol)
 	if wasCapped {
 		decision.PositionSizeUSD = adjustedPositionSize
 	}
 
+	// [CODE ENFORCED] Liquidity cap: position_value <= min(1h volume × pct, book depth × pct)
+	adjusted, capped, err := at.enforceLiquidityCap(decision.PositionSizeUSD, decision.Symbol)
+	if err != nil {
+		return err
+	}
+	if capped {
+		decision.PositionSizeUSD = adjusted
+	}
+
 	// ⚠️ Auto-adjust position size if insufficient margin
 	// Formula: totalRequired = positionSize/leverage + positionSize*0.001 + positionSize/leverage*0.01
 	//        = positionSize * (1.01/leverage + 0.001)
@@ -983,6 +1505,21 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *decision.Decision, ac
 		decision.PositionSizeUSD = actualPositionSize
 	}
 
+	// [CODE ENFORCED] Estimated slippage from order book depth: downsize to what fills within the bps limit
+	adjusted, capped, err = at.enforceSlippageLimit(decision.PositionSizeUSD, decision.Symbol, "sell")
+	if err != nil {
+		return err
+	}
+	if capped {
+		actualPositionSize = adjusted
+		decision.PositionSizeUSD = adjusted
+	}
+
+	// [CODE ENFORCED] Exposure caps: total / per-symbol / user-wide notional after this entry
+	if err := at.enforceExposureLimits(decision.Symbol, decision.PositionSizeUSD); err != nil {
+		return err
+	}
+
 	// [CODE ENFORCED] Minimum position size check
 	if err := at.enforceMinPositionSize(decision.PositionSizeUSD); err != nil {
 		return err
@@ -999,8 +1536,14 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *decision.Decision, ac
 		// Continue execution, doesn't affect trading
 	}
 
-	// Open position
-	order, err := at.trader.OpenShort(decision.Symbol, quantity, decision.Leverage)
+	// Move the stop loss beyond nearby liquidity before placing it
+	at.snapStopLoss(decision, "short", actionRecord)
+
+	// Open position, submitted together with its stop loss and take profit where the exchange batches them
+	order, batched, err := at.openWithBracket(decision, "SHORT", quantity)
+	if !batched {
+		order, err = at.trader.OpenShort(decision.Symbol, quantity, decision.Leverage)
+	}
 	if err != nil {
 		return err
 	}
@@ -1014,17 +1557,27 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *decision.Decision, ac
 
 	// Record order to database and poll for confirmation
 	at.recordAndConfirmOrder(order, decision.Symbol, "open_short", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	at.rememberEntry(decision, "SHORT", marketData, order)
 
 	// Record position opening time
 	posKey := decision.Symbol + "_short"
 	at.positionFirstSeenTime[posKey] = time.Now().UnixMilli()
 
-	// Set stop loss and take profit
-	if err := at.trader.SetStopLoss(decision.Symbol, "SHORT", quantity, decision.StopLoss); err != nil {
-		logger.Infof("  ⚠ Failed to set stop loss: %v", err)
+	// Set stop loss and take profit as a linked bracket (one filling cancels the other)
+	if !batched {
+		at.setBracket(decision.Symbol, "SHORT", quantity, decision.StopLoss, decision.TakeProfit)
 	}
-	if err := at.trader.SetTakeProfit(decision.Symbol, "SHORT", quantity, decision.TakeProfit); err != nil {
-		logger.Infof("  ⚠ Failed to set take profit: %v", err)
+
+	// Trailing stop (lets winners run, on top of the fixed stop loss)
+	if pct := at.trailingStopPct(decision); pct > 0 {
+		at.setTrailingStop(decision.Symbol, "SHORT", quantity, pct)
+	}
+
+	// Exit handed to a managed trailing stop right away
+	if decision.ManagedExit != nil {
+		if _, err := at.handOffExit(decision.Symbol, "short", *decision.ManagedExit); err != nil {
+			logger.Infof("  ⚠ Failed to set managed exit: %v", err)
+		}
 	}
 
 	return nil
@@ -1047,13 +1600,9 @@ func (at *AutoTrader) executeCloseLongWithRecord(decision *decision.Decision, ac
 	positions, err := at.trader.GetPositions()
 	if err == nil {
 		for _, pos := range positions {
-			if pos["symbol"] == decision.Symbol && pos["side"] == "long" {
-				if ep, ok := pos["entryPrice"].(float64); ok {
-					entryPrice = ep
-				}
-				if amt, ok := pos["positionAmt"].(float64); ok && amt > 0 {
-					quantity = amt
-				}
+			if pos.Symbol == decision.Symbol && pos.Side == "long" {
+				entryPrice = pos.EntryPrice
+				quantity = pos.Quantity
 				break
 			}
 		}
@@ -1072,6 +1621,9 @@ func (at *AutoTrader) executeCloseLongWithRecord(decision *decision.Decision, ac
 
 	// Record order to database and poll for confirmation
 	at.recordAndConfirmOrder(order, decision.Symbol, "close_long", quantity, marketData.CurrentPrice, 0, entryPrice)
+	at.clearTrailingStop(decision.Symbol, "long")
+	at.clearManagedExit(decision.Symbol, "long")
+	at.clearBracket(decision.Symbol, "long")
 
 	logger.Infof("  ✓ Position closed successfully")
 	return nil
@@ -1094,13 +1646,9 @@ func (at *AutoTrader) executeCloseShortWithRecord(decision *decision.Decision, a
 	positions, err := at.trader.GetPositions()
 	if err == nil {
 		for _, pos := range positions {
-			if pos["symbol"] == decision.Symbol && pos["side"] == "short" {
-				if ep, ok := pos["entryPrice"].(float64); ok {
-					entryPrice = ep
-				}
-				if amt, ok := pos["positionAmt"].(float64); ok {
-					quantity = -amt // positionAmt is negative for short
-				}
+			if pos.Symbol == decision.Symbol && pos.Side == "short" {
+				entryPrice = pos.EntryPrice
+				quantity = pos.Quantity
 				break
 			}
 		}
@@ -1119,6 +1667,9 @@ func (at *AutoTrader) executeCloseShortWithRecord(decision *decision.Decision, a
 
 	// Record order to database and poll for confirmation
 	at.recordAndConfirmOrder(order, decision.Symbol, "close_short", quantity, marketData.CurrentPrice, 0, entryPrice)
+	at.clearTrailingStop(decision.Symbol, "short")
+	at.clearManagedExit(decision.Symbol, "short")
+	at.clearBracket(decision.Symbol, "short")
 
 	logger.Infof("  ✓ Position closed successfully")
 	return nil
@@ -1231,7 +1782,7 @@ func (at *AutoTrader) GetStatus() map[string]interface{} {
 		aiProvider = "Qwen"
 	}
 
-	return map[string]interface{}{
+	status := map[string]interface{}{
 		"trader_id":       at.id,
 		"trader_name":     at.name,
 		"ai_model":        at.aiModel,
@@ -1246,6 +1797,41 @@ func (at *AutoTrader) GetStatus() map[string]interface{} {
 		"last_reset_time": at.lastResetTime.Format(time.RFC3339),
 		"ai_provider":     aiProvider,
 	}
+	if at.failover != nil {
+		status["failover"] = at.failover.Status()
+	}
+	if at.lastDailyReport != nil {
+		status["daily_report"] = at.lastDailyReport
+	}
+	if paused, until := at.dailyLossPaused(time.Now()); paused {
+		status["daily_loss_paused_until"] = until.Format(time.RFC3339)
+	}
+	return status
+}
+
+// entriesAllowed returns an error while new positions cannot be opened on any exchange,
+// the end-of-day flat period is active or the daily loss kill-switch paused the trader
+func (at *AutoTrader) entriesAllowed() error {
+	if at.flatActive(time.Now()) {
+		return fmt.Errorf("❌ End-of-day flat period active, new entries are blocked until the resume time")
+	}
+	if paused, until := at.dailyLossPaused(time.Now()); paused {
+		return fmt.Errorf("❌ Daily loss limit reached, new entries are blocked until %s", until.Format(time.RFC3339))
+	}
+	if at.failover == nil {
+		return nil
+	}
+	return at.failover.EntriesAllowed()
+}
+
+// TransferFunds moves funds between wallets or sub-accounts (for capital allocation)
+// Returns an error if the underlying exchange does not support programmatic transfers
+func (at *AutoTrader) TransferFunds(req TransferRequest) (string, error) {
+	transferer, ok := capability[FundTransferer](at.trader, "", "")
+	if !ok {
+		return "", fmt.Errorf("exchange %s does not support fund transfers", at.exchange)
+	}
+	return transferer.TransferFunds(req)
 }
 
 // GetAccountInfo gets account information (for API)
@@ -1279,25 +1865,19 @@ func (at *AutoTrader) GetAccountInfo() (map[string]interface{}, error) {
 		positions = livePositions
 	} else {
 		logger.Infof("⚠️ GetPositions failed, using empty positions: %v", err)
-		positions = []map[string]interface{}{}
+		positions = []Position{}
 	}
 
 	totalMarginUsed := 0.0
 	totalUnrealizedPnLCalculated := 0.0
 	for _, pos := range positions {
-		markPrice := getFloat(pos, "markPrice")
-		quantity := math.Abs(getFloat(pos, "positionAmt"))
-		unrealizedPnl := getFloat(pos, "unRealizedProfit")
-		totalUnrealizedPnLCalculated += unrealizedPnl
+		totalUnrealizedPnLCalculated += pos.UnrealizedPnL
 
-		leverage := int(getFloat(pos, "leverage"))
+		leverage := int(pos.Leverage)
 		if leverage == 0 {
 			leverage = 10
 		}
-		marginUsed := 0.0
-		if leverage != 0 {
-			marginUsed = (quantity * markPrice) / float64(leverage)
-		}
+		marginUsed := (pos.Quantity * pos.MarkPrice) / float64(leverage)
 		totalMarginUsed += marginUsed
 	}
 
@@ -1351,38 +1931,27 @@ func (at *AutoTrader) GetPositions() ([]map[string]interface{}, error) {
 
 	var result []map[string]interface{}
 	for _, pos := range positions {
-		symbol, _ := pos["symbol"].(string)
-		side, _ := pos["side"].(string)
-		entryPrice := getFloat(pos, "entryPrice")
-		markPrice := getFloat(pos, "markPrice")
-		quantity := math.Abs(getFloat(pos, "positionAmt"))
-		unrealizedPnl := getFloat(pos, "unRealizedProfit")
-		liquidationPrice := getFloat(pos, "liquidationPrice")
-
-		leverage := int(getFloat(pos, "leverage"))
+		leverage := int(pos.Leverage)
 		if leverage == 0 {
 			leverage = 10
 		}
 
 		// Calculate margin used
-		marginUsed := 0.0
-		if leverage != 0 {
-			marginUsed = (quantity * markPrice) / float64(leverage)
-		}
+		marginUsed := (pos.Quantity * pos.MarkPrice) / float64(leverage)
 
 		// Calculate P&L percentage (based on margin)
-		pnlPct := calculatePnLPercentage(unrealizedPnl, marginUsed)
+		pnlPct := calculatePnLPercentage(pos.UnrealizedPnL, marginUsed)
 
 		result = append(result, map[string]interface{}{
-			"symbol":             symbol,
-			"side":               side,
-			"entry_price":        entryPrice,
-			"mark_price":         markPrice,
-			"quantity":           quantity,
+			"symbol":             pos.Symbol,
+			"side":               pos.Side,
+			"entry_price":        pos.EntryPrice,
+			"mark_price":         pos.MarkPrice,
+			"quantity":           pos.Quantity,
 			"leverage":           leverage,
-			"unrealized_pnl":     unrealizedPnl,
+			"unrealized_pnl":     pos.UnrealizedPnL,
 			"unrealized_pnl_pct": pnlPct,
-			"liquidation_price":  liquidationPrice,
+			"liquidation_price":  pos.LiquidationPrice,
 			"margin_used":        marginUsed,
 		})
 	}
@@ -1424,7 +1993,7 @@ func calculatePnLPercentage(unrealizedPnl, marginUsed float64) float64 {
 	return 0.0
 }
 
-// sortDecisionsByPriority sorts decisions: close positions first, then open positions, finally hold/wait
+// sortDecisionsByPriority sorts decisions: close positions first, then open/add positions, finally hold/wait
 // This avoids position stacking overflow when changing positions
 func sortDecisionsByPriority(decisions []decision.Decision) []decision.Decision {
 	if len(decisions) <= 1 {
@@ -1434,11 +2003,11 @@ func sortDecisionsByPriority(decisions []decision.Decision) []decision.Decision
 	// Define priority
 	getActionPriority := func(action string) int {
 		switch action {
-		case "close_long", "close_short":
+		case "close_long", "close_short", "partial_close_long", "partial_close_short":
 			return 1 // Highest priority: close positions first
-		case "open_long", "open_short":
+		case "open_long", "open_short", "add_long", "add_short", "manage_exit_long", "manage_exit_short":
 			return 2 // Second priority: open positions later
-		case "hold", "wait":
+		case "hold", "wait", decision.ActionAlert:
 			return 3 // Lowest priority: wait
 		default:
 			return 999 // Unknown actions at the end
@@ -1469,13 +2038,92 @@ func (at *AutoTrader) startDrawdownMonitor() {
 
 		ticker := time.NewTicker(1 * time.Minute) // Check every minute
 		defer ticker.Stop()
+		trailingTicker := time.NewTicker(trailingStopCheckInterval)
+		defer trailingTicker.Stop()
+		exitTicker := time.NewTicker(managedExitCheckInterval)
+		defer exitTicker.Stop()
+		bracketTicker := time.NewTicker(bracketCheckInterval)
+		defer bracketTicker.Stop()
+		breakEvenTicker := time.NewTicker(breakEvenCheckInterval)
+		defer breakEvenTicker.Stop()
+		flatTicker := time.NewTicker(flatCheckInterval)
+		defer flatTicker.Stop()
+		dailyLossTicker := time.NewTicker(dailyLossCheckInterval)
+		defer dailyLossTicker.Stop()
+		reconcileTicker := time.NewTicker(reconcileInterval)
+		defer reconcileTicker.Stop()
+		fundingTicker := time.NewTicker(fundingSyncInterval)
+		defer fundingTicker.Stop()
+		ageTicker := time.NewTicker(positionAgeCheckInterval)
+		defer ageTicker.Stop()
+		marginTicker := time.NewTicker(marginRatioCheckInterval)
+		defer marginTicker.Stop()
+		notifyTicker := time.NewTicker(notificationFlushInterval)
+		defer notifyTicker.Stop()
+		liqTicker := time.NewTicker(liquidationGuardCheckInterval)
+		defer liqTicker.Stop()
+		outcomeTicker := time.NewTicker(outcomeExportInterval)
+		defer outcomeTicker.Stop()
+		var driftC <-chan time.Time // nil (never fires) when drift checks are disabled
+		if interval := driftCheckInterval(); interval > 0 {
+			driftTicker := time.NewTicker(interval)
+			defer driftTicker.Stop()
+			driftC = driftTicker.C
+		}
 
 		logger.Info("📊 Started position drawdown monitoring (check every minute)")
 
 		for {
 			select {
 			case <-ticker.C:
-				at.checkPositionDrawdown()
+				at.updateIdle()
+				if !at.workersSuspended() {
+					at.checkPositionDrawdown()
+				}
+			case <-trailingTicker.C:
+				if !at.workersSuspended() {
+					at.checkTrailingStops()
+				}
+			case <-exitTicker.C:
+				if !at.workersSuspended() {
+					at.checkManagedExits()
+				}
+			case <-bracketTicker.C:
+				if !at.workersSuspended() {
+					at.checkBrackets()
+				}
+			case <-breakEvenTicker.C:
+				if !at.workersSuspended() {
+					at.checkBreakEven()
+				}
+			case <-flatTicker.C:
+				at.checkFlatSchedule()
+			case <-dailyLossTicker.C:
+				at.checkDailyLoss()
+			case <-driftC:
+				if !at.workersSuspended() {
+					at.checkConfigDrift()
+				}
+			case <-reconcileTicker.C:
+				if !at.workersSuspended() {
+					at.reconcilePositions()
+				}
+			case <-fundingTicker.C:
+				if !at.workersSuspended() {
+					at.syncFundingFees()
+				}
+			case <-ageTicker.C:
+				if !at.workersSuspended() {
+					at.checkPositionAge()
+				}
+			case <-marginTicker.C:
+				at.checkMarginRatio()
+			case <-notifyTicker.C:
+				at.flushNotificationDigest()
+			case <-liqTicker.C:
+				at.checkLiquidationProximity()
+			case <-outcomeTicker.C:
+				at.exportOutcomes()
 			case <-at.stopMonitorCh:
 				logger.Info("⏹ Stopped position drawdown monitoring")
 				return
@@ -1494,19 +2142,19 @@ func (at *AutoTrader) checkPositionDrawdown() {
 	}
 
 	for _, pos := range positions {
-		symbol := pos["symbol"].(string)
-		side := pos["side"].(string)
-		entryPrice := pos["entryPrice"].(float64)
-		markPrice := pos["markPrice"].(float64)
-		quantity := pos["positionAmt"].(float64)
-		if quantity < 0 {
-			quantity = -quantity // Short position quantity is negative, convert to positive
+		// Exchange circuit open: snapshot prices are stale and orders can't be sent
+		if pos.ReadOnly {
+			continue
 		}
+		symbol := pos.Symbol
+		side := pos.Side
+		entryPrice := pos.EntryPrice
+		markPrice := pos.MarkPrice
 
 		// Calculate current P&L percentage
 		leverage := 10 // Default value
-		if lev, ok := pos["leverage"].(float64); ok {
-			leverage = int(lev)
+		if pos.Leverage > 0 {
+			leverage = int(pos.Leverage)
 		}
 
 		var currentPnLPct float64
@@ -1560,6 +2208,17 @@ func (at *AutoTrader) checkPositionDrawdown() {
 	}
 }
 
+// trailingStopPct returns the decision's trailing stop distance, or the strategy default
+func (at *AutoTrader) trailingStopPct(d *decision.Decision) float64 {
+	if d.TrailingStopPct > 0 {
+		return d.TrailingStopPct
+	}
+	if at.config.StrategyConfig != nil {
+		return at.config.StrategyConfig.RiskControl.TrailingStopPct
+	}
+	return 0
+}
+
 // emergencyClosePosition emergency close position function
 func (at *AutoTrader) emergencyClosePosition(symbol, side string) error {
 	switch side {
@@ -1579,6 +2238,8 @@ func (at *AutoTrader) emergencyClosePosition(symbol, side string) error {
 		return fmt.Errorf("unknown position direction: %s", side)
 	}
 
+	at.clearBracket(symbol, side)
+	at.clearManagedExit(symbol, side)
 	return nil
 }
 
@@ -1622,7 +2283,7 @@ func (at *AutoTrader) ClearPeakPnLCache(symbol, side string) {
 }
 
 // recordAndConfirmOrder polls order status for actual fill data and records position
-// action: open_long, open_short, close_long, close_short
+// action: open_long, open_short, add_long, add_short, close_long, close_short, partial_close_long, partial_close_short
 // entryPrice: entry price when closing (0 when opening)
 func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{}, symbol, action string, quantity float64, price float64, leverage int, entryPrice float64) {
 	if at.store == nil {
@@ -1630,17 +2291,7 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 	}
 
 	// Get order ID (supports multiple types)
-	var orderID string
-	switch v := orderResult["orderId"].(type) {
-	case int64:
-		orderID = fmt.Sprintf("%d", v)
-	case float64:
-		orderID = fmt.Sprintf("%.0f", v)
-	case string:
-		orderID = v
-	default:
-		orderID = fmt.Sprintf("%v", v)
-	}
+	orderID := orderIDString(orderResult)
 
 	if orderID == "" || orderID == "0" {
 		logger.Infof("  ⚠️ Order ID is empty, skipping record")
@@ -1650,9 +2301,9 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 	// Determine positionSide
 	var positionSide string
 	switch action {
-	case "open_long", "close_long":
+	case "open_long", "add_long", "close_long", "partial_close_long":
 		positionSide = "LONG"
-	case "open_short", "close_short":
+	case "open_short", "add_short", "close_short", "partial_close_short":
 		positionSide = "SHORT"
 	}
 
@@ -1661,9 +2312,22 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 	var actualQty = quantity // fallback to requested quantity
 	var fee float64
 
+	// Fill data already known (limit chase entries span several orders, the last order alone is incomplete)
+	knownFill := false
+	if execQty, ok := orderResult["executedQty"].(float64); ok && execQty > 0 {
+		if avgPrice, ok := orderResult["avgPrice"].(float64); ok && avgPrice > 0 {
+			actualPrice, actualQty = avgPrice, execQty
+			fee, _ = orderResult["commission"].(float64)
+			knownFill = true
+			logger.Infof("  ✅ Order filled: avgPrice=%.6f, qty=%.6f, fee=%.6f", actualPrice, actualQty, fee)
+		}
+	}
+
 	// Wait for order to be filled and get actual fill data
-	time.Sleep(500 * time.Millisecond)
-	for i := 0; i < 5; i++ {
+	if !knownFill {
+		time.Sleep(500 * time.Millisecond)
+	}
+	for i := 0; i < 5 && !knownFill; i++ {
 		status, err := at.trader.GetOrderStatus(symbol, orderID)
 		if err == nil {
 			statusStr, _ := status["status"].(string)
@@ -1676,9 +2340,11 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 				if execQty, ok := status["executedQty"].(float64); ok && execQty > 0 {
 					actualQty = execQty
 				}
-				// Get commission/fee
+				// Get commission/fee, estimate from the registered fee schedule if not reported
 				if commission, ok := status["commission"].(float64); ok {
 					fee = commission
+				} else {
+					fee = logger.EstimateFee(at.exchange, actualPrice*actualQty, false)
 				}
 				logger.Infof("  ✅ Order filled: avgPrice=%.6f, qty=%.6f, fee=%.6f", actualPrice, actualQty, fee)
 				break
@@ -1697,7 +2363,21 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 	at.recordPositionChange(orderID, symbol, positionSide, action, actualQty, actualPrice, leverage, entryPrice, fee)
 }
 
-// recordPositionChange records position change (create record on open, update record on close)
+// orderIDString order ID of an order result as recorded on positions (exchanges return int64, float64 or string)
+func orderIDString(orderResult map[string]interface{}) string {
+	switch v := orderResult["orderId"].(type) {
+	case int64:
+		return fmt.Sprintf("%d", v)
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// recordPositionChange records position change (create record on open, average in on add, split on partial close, update record on close)
 func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string, quantity, price float64, leverage int, entryPrice float64, fee float64) {
 	if at.store == nil {
 		return
@@ -1705,11 +2385,15 @@ func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string,
 
 	switch action {
 	case "open_long", "open_short":
-		// Open position: create new position record
+		// Open position: create new position record (on the fallback exchange if entries were rerouted)
+		exchangeType, exchangeID := at.exchange, at.exchangeID
+		if at.failover != nil {
+			exchangeType, exchangeID = at.failover.PositionExchange(symbol, side)
+		}
 		pos := &store.TraderPosition{
 			TraderID:     at.id,
-			ExchangeID:   at.exchangeID, // Exchange account UUID
-			ExchangeType: at.exchange,   // Exchange type: binance/bybit/okx/etc
+			ExchangeID:   exchangeID,   // Exchange account UUID
+			ExchangeType: exchangeType, // Exchange type: binance/bybit/okx/etc
 			Symbol:       symbol,
 			Side:         side, // LONG or SHORT
 			Quantity:     quantity,
@@ -1725,6 +2409,52 @@ func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string,
 			logger.Infof("  📊 Position recorded [%s] %s %s @ %.4f", at.id[:8], symbol, side, price)
 		}
 
+	case "add_long", "add_short":
+		// Scale-in: fold the fill into the open record (weighted-average entry price)
+		openPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, symbol, side)
+		if err != nil || openPos == nil {
+			logger.Infof("  ⚠️ Cannot find open position record to add to (%s %s)", symbol, side)
+			return
+		}
+		if err := at.store.Position().AddToPosition(openPos.ID, quantity, price); err != nil {
+			logger.Infof("  ⚠️ Failed to record position add: %v", err)
+		} else {
+			logger.Infof("  📊 Position add recorded [%s] %s %s +%.4f @ %.4f (avg entry %.4f)", at.id[:8], symbol, side,
+				quantity, price, weightedEntryPrice(openPos.Quantity, openPos.EntryPrice, quantity, price))
+		}
+
+	case "partial_close_long", "partial_close_short":
+		// Partial close: split the closed quantity off the open record
+		openPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, symbol, side)
+		if err != nil || openPos == nil {
+			logger.Infof("  ⚠️ Cannot find corresponding open position record (%s %s)", symbol, side)
+			return
+		}
+		if quantity >= openPos.Quantity {
+			// Record drifted from the exchange, treat as full close
+			at.recordPositionChange(orderID, symbol, side, "close_"+strings.ToLower(side), quantity, price, leverage, entryPrice, fee)
+			return
+		}
+
+		var realizedPnL float64
+		if side == "LONG" {
+			realizedPnL = (price - openPos.EntryPrice) * quantity
+		} else {
+			realizedPnL = (openPos.EntryPrice - price) * quantity
+		}
+		quote := quoteAssetOf(symbol)
+		realizedPnL = logger.ToUSD(quote, realizedPnL)
+		fee = logger.ToUSD(quote, fee)
+		// The closed part's share of the funding accrued while open
+		realizedPnL += openPos.FundingFee * quantity / openPos.Quantity
+
+		if err := at.store.Position().PartialClosePosition(openPos.ID, quantity, price, orderID, realizedPnL, fee, "ai_decision"); err != nil {
+			logger.Infof("  ⚠️ Failed to record partial close: %v", err)
+		} else {
+			logger.Infof("  📊 Position partially closed [%s] %s %s %.4f @ %.4f → %.4f, P&L: %.2f, Fee: %.4f (remaining %.4f)",
+				at.id[:8], symbol, side, quantity, openPos.EntryPrice, price, realizedPnL, fee, openPos.Quantity-quantity)
+		}
+
 	case "close_long", "close_short":
 		// Close position: find corresponding open position record and update
 		openPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, symbol, side)
@@ -1733,13 +2463,18 @@ func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string,
 			return
 		}
 
-		// Calculate P&L
+		// Calculate P&L (converted from the symbol's quote asset so stats add up across USDT/USDC/BUSD pairs)
 		var realizedPnL float64
 		if side == "LONG" {
 			realizedPnL = (price - openPos.EntryPrice) * openPos.Quantity
 		} else {
 			realizedPnL = (openPos.EntryPrice - price) * openPos.Quantity
 		}
+		quote := quoteAssetOf(symbol)
+		realizedPnL = logger.ToUSD(quote, realizedPnL)
+		fee = logger.ToUSD(quote, fee)
+		// Funding accrued while open
+		realizedPnL += openPos.FundingFee
 
 		// Update position record
 		err = at.store.Position().ClosePosition(
@@ -1753,8 +2488,8 @@ func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string,
 		if err != nil {
 			logger.Infof("  ⚠️ Failed to update position: %v", err)
 		} else {
-			logger.Infof("  📊 Position closed [%s] %s %s @ %.4f → %.4f, P&L: %.2f, Fee: %.4f",
-				at.id[:8], symbol, side, openPos.EntryPrice, price, realizedPnL, fee)
+			logger.Infof("  📊 Position closed [%s] %s %s @ %.4f → %.4f, P&L: %.2f (funding %.4f), Fee: %.4f",
+				at.id[:8], symbol, side, openPos.EntryPrice, price, realizedPnL, openPos.FundingFee, fee)
 		}
 	}
 }
@@ -1763,10 +2498,32 @@ func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string,
 // Risk Control Helpers
 // ============================================================================
 
-// isBTCETH checks if a symbol is BTC or ETH
-func isBTCETH(symbol string) bool {
-	symbol = strings.ToUpper(symbol)
-	return strings.HasPrefix(symbol, "BTC") || strings.HasPrefix(symbol, "ETH")
+// availableForSymbol returns the margin available for opening a position in symbol
+// USDC-margined contracts draw from the USDC wallet unless the account is in multi-assets mode
+func availableForSymbol(balance map[string]interface{}, symbol string) float64 {
+	availableBalance := 0.0
+	if avail, ok := balance["availableBalance"].(float64); ok {
+		availableBalance = avail
+	}
+	if multi, _ := balance["multiAssetsMargin"].(bool); multi {
+		return availableBalance
+	}
+	quoteBalances, ok := balance["quoteBalances"].(map[string]float64)
+	if !ok {
+		return availableBalance
+	}
+	if avail, ok := quoteBalances[quoteAssetOf(symbol)]; ok {
+		return avail
+	}
+	return availableBalance
+}
+
+// quoteAssetOf returns the quote asset of a symbol, defaulting to USDT
+func quoteAssetOf(symbol string) string {
+	if q := market.QuoteAsset(symbol); q != "" {
+		return q
+	}
+	return market.DefaultQuoteAsset
 }
 
 // enforcePositionValueRatio checks and enforces position value ratio limits (CODE ENFORCED)
@@ -1783,7 +2540,7 @@ func (at *AutoTrader) enforcePositionValueRatio(positionSizeUSD float64, equity
 
 	// Get the appropriate position value ratio limit
 	var maxPositionValueRatio float64
-	if isBTCETH(symbol) {
+	if market.IsBTCETH(symbol) {
 		maxPositionValueRatio = riskControl.BTCETHMaxPositionValueRatio
 		if maxPositionValueRatio <= 0 {
 			maxPositionValueRatio = 5.0 // Default: 5x for BTC/ETH
@@ -1808,23 +2565,94 @@ func (at *AutoTrader) enforcePositionValueRatio(positionSizeUSD float64, equity
 	return positionSizeUSD, false
 }
 
+// enforceLiquidityCap caps position size by the symbol's recent volume and order book depth (CODE ENFORCED)
+// Prevents putting meaningful size into illiquid alts where the order itself would move the market. Opt-in per
+// strategy; the volume and book are Binance's, so the cap is skipped on other exchanges. When enabled and the
+// liquidity can't be fetched the entry is rejected
+func (at *AutoTrader) enforceLiquidityCap(positionSizeUSD float64, symbol string) (float64, bool, error) {
+	if at.config.StrategyConfig == nil {
+		return positionSizeUSD, false, nil
+	}
+
+	riskControl := at.config.StrategyConfig.RiskControl
+	volumePct := math.Max(riskControl.MaxLiquidityVolumePct, 0)
+	depthPct := math.Max(riskControl.MaxLiquidityDepthPct, 0)
+	if volumePct == 0 && depthPct == 0 {
+		return positionSizeUSD, false, nil
+	}
+	if at.exchange != market.VenueBinance {
+		logger.Infof("  ℹ️ [RISK CONTROL] Liquidity cap skipped for %s: volume and depth come from Binance, not %s", symbol, at.exchange)
+		return positionSizeUSD, false, nil
+	}
+
+	limit, err := market.GetLiquidityLimit(symbol, volumePct, depthPct)
+	if err != nil {
+		return positionSizeUSD, false, fmt.Errorf("❌ [RISK CONTROL] Liquidity check unavailable for %s: %w", symbol, err)
+	}
+	if limit.MaxNotional <= 0 || positionSizeUSD <= limit.MaxNotional {
+		return positionSizeUSD, false, nil
+	}
+
+	logger.Infof("  ⚠️ [RISK CONTROL] Position %.2f USDT exceeds liquidity limit for %s (1h vol %.0f, ±1%% depth %.0f → max %.2f USDT), capping",
+		positionSizeUSD, symbol, limit.QuoteVolume1h, limit.DepthNotional, limit.MaxNotional)
+	return limit.MaxNotional, true, nil
+}
+
+// enforceSlippageLimit sizes a market entry down to what the order book absorbs within max_slippage_bps (CODE ENFORCED)
+// side: "buy" for longs, "sell" for shorts. The book is fetched fresh, the liquidity cap's cached depth is too old for pricing.
+// Opt-in per strategy; the book is Binance's, so the limit is skipped on other exchanges. When enabled and the book
+// can't be fetched the entry is rejected
+func (at *AutoTrader) enforceSlippageLimit(positionSizeUSD float64, symbol, side string) (float64, bool, error) {
+	if at.config.StrategyConfig == nil || positionSizeUSD <= 0 {
+		return positionSizeUSD, false, nil
+	}
+
+	maxBps := at.config.StrategyConfig.RiskControl.MaxSlippageBps
+	if maxBps <= 0 {
+		return positionSizeUSD, false, nil
+	}
+	if at.exchange != market.VenueBinance {
+		logger.Infof("  ℹ️ [RISK CONTROL] Slippage limit skipped for %s: the order book comes from Binance, not %s", symbol, at.exchange)
+		return positionSizeUSD, false, nil
+	}
+
+	est, err := market.EstimateSlippage(symbol, side, positionSizeUSD, maxBps)
+	if err != nil {
+		return positionSizeUSD, false, fmt.Errorf("❌ [RISK CONTROL] Slippage estimate unavailable for %s: %w", symbol, err)
+	}
+	if !est.Exhausted && est.SlippageBps <= maxBps {
+		logger.Infof("  ✓ Estimated %s slippage for %.2f USDT %s: %.1f bps (limit %.0f)", symbol, positionSizeUSD, side, est.SlippageBps, maxBps)
+		return positionSizeUSD, false, nil
+	}
+
+	// Fetched book thinner than the order: the estimate is a lower bound, never size above what was seen
+	maxNotional := math.Min(est.MaxNotional, positionSizeUSD)
+	logger.Infof("  ⚠️ [RISK CONTROL] Estimated %s slippage for %.2f USDT %s is %.1f bps (limit %.0f, book exhausted: %v), downsizing to %.2f USDT",
+		symbol, positionSizeUSD, side, est.SlippageBps, maxBps, est.Exhausted, maxNotional)
+	return maxNotional, true, nil
+}
+
 // enforceMinPositionSize checks minimum position size (CODE ENFORCED)
 func (at *AutoTrader) enforceMinPositionSize(positionSizeUSD float64) error {
 	if at.config.StrategyConfig == nil {
 		return nil
 	}
 
-	minSize := at.config.StrategyConfig.RiskControl.MinPositionSize
-	if minSize <= 0 {
-		minSize = 12 // Default: 12 USDT
-	}
-
+	minSize := at.minPositionSize()
 	if positionSizeUSD < minSize {
 		return fmt.Errorf("❌ [RISK CONTROL] Position %.2f USDT below minimum (%.2f USDT)", positionSizeUSD, minSize)
 	}
 	return nil
 }
 
+// minPositionSize returns the strategy's minimum position value (USDT)
+func (at *AutoTrader) minPositionSize() float64 {
+	if at.config.StrategyConfig != nil && at.config.StrategyConfig.RiskControl.MinPositionSize > 0 {
+		return at.config.StrategyConfig.RiskControl.MinPositionSize
+	}
+	return 12 // Default: 12 USDT
+}
+
 // enforceMaxPositions checks maximum positions count (CODE ENFORCED)
 func (at *AutoTrader) enforceMaxPositions(currentPositionCount int) error {
 	if at.config.StrategyConfig == nil {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -61,25 +62,26 @@ func (s *AutoTraderTestSuite) SetupTest() {
 			"availableBalance":      8000.0,
 			"totalUnrealizedProfit": 100.0,
 		},
-		positions: []map[string]interface{}{},
+		positions: []Position{},
 	}
 
-
 	// Create temporary store (using nil means no actual store needed in test)
 	s.mockStore = nil
 
 	// Set default configuration
+	strategyConfig := store.GetDefaultStrategyConfig("en")
+	strategyConfig.CoinSource = store.CoinSourceConfig{SourceType: "static", StaticCoins: []string{"BTC", "ETH"}}
+	strategyConfig.RiskControl.BTCETHMaxLeverage = 10
+	strategyConfig.RiskControl.AltcoinMaxLeverage = 5
 	s.config = AutoTraderConfig{
-		ID:                   "test_trader",
-		Name:                 "Test Trader",
-		AIModel:              "deepseek",
-		Exchange:             "binance",
-		InitialBalance:       10000.0,
-		ScanInterval:         3 * time.Minute,
-		SystemPromptTemplate: "adaptive",
-		BTCETHLeverage:       10,
-		AltcoinLeverage:      5,
-		IsCrossMargin:        true,
+		ID:             "test_trader",
+		Name:           "Test Trader",
+		AIModel:        "deepseek",
+		Exchange:       "binance",
+		InitialBalance: 10000.0,
+		ScanInterval:   3 * time.Minute,
+		IsCrossMargin:  true,
+		StrategyConfig: &strategyConfig,
 	}
 
 	// Create AutoTrader instance (direct construction, don't call NewAutoTrader to avoid external dependencies)
@@ -92,10 +94,8 @@ func (s *AutoTraderTestSuite) SetupTest() {
 		trader:                s.mockTrader,
 		mcpClient:             nil, // No actual MCP Client needed in tests
 		store:                 s.mockStore,
+		strategyEngine:        decision.NewStrategyEngine(s.config.StrategyConfig),
 		initialBalance:        s.config.InitialBalance,
-		systemPromptTemplate:  s.config.SystemPromptTemplate,
-		defaultCoins:          []string{"BTC", "ETH"},
-		tradingCoins:          []string{},
 		lastResetTime:         time.Now(),
 		startTime:             time.Now(),
 		callCount:             0,
@@ -180,7 +180,7 @@ func (s *AutoTraderTestSuite) TestNormalizeSymbol() {
 
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			result := normalizeSymbol(tt.input)
+			result := market.Normalize(strings.TrimSpace(tt.input))
 			s.Equal(tt.expected, result)
 		})
 	}
@@ -199,9 +199,8 @@ func (s *AutoTraderTestSuite) TestGettersAndSetters() {
 		s.Equal("Test Trader", s.autoTrader.GetName())
 	})
 
-	s.Run("SetSystemPromptTemplate", func() {
-		s.autoTrader.SetSystemPromptTemplate("aggressive")
-		s.Equal("aggressive", s.autoTrader.GetSystemPromptTemplate())
+	s.Run("GetSystemPromptTemplate", func() {
+		s.Equal("strategy", s.autoTrader.GetSystemPromptTemplate())
 	})
 
 	s.Run("SetCustomPrompt", func() {
@@ -293,16 +292,16 @@ func (s *AutoTraderTestSuite) TestGetPositions() {
 
 	s.Run("Has positions", func() {
 		// Set mock positions
-		s.mockTrader.positions = []map[string]interface{}{
+		s.mockTrader.positions = []Position{
 			{
-				"symbol":           "BTCUSDT",
-				"side":             "long",
-				"entryPrice":       50000.0,
-				"markPrice":        51000.0,
-				"positionAmt":      0.1,
-				"unRealizedProfit": 100.0,
-				"liquidationPrice": 45000.0,
-				"leverage":         10.0,
+				Symbol:           "BTCUSDT",
+				Side:             "long",
+				EntryPrice:       50000.0,
+				MarkPrice:        51000.0,
+				Quantity:         0.1,
+				UnrealizedPnL:    100.0,
+				LiquidationPrice: 45000.0,
+				Leverage:         10.0,
 			},
 		}
 
@@ -324,51 +323,38 @@ func (s *AutoTraderTestSuite) TestGetPositions() {
 // ============================================================
 
 func (s *AutoTraderTestSuite) TestGetCandidateCoins() {
-	s.Run("Use database default coins", func() {
-		s.autoTrader.defaultCoins = []string{"BTC", "ETH", "BNB"}
-		s.autoTrader.tradingCoins = []string{} // Empty custom coins
+	useCoinSource := func(coinSource store.CoinSourceConfig) {
+		cfg := *s.config.StrategyConfig
+		cfg.CoinSource = coinSource
+		s.autoTrader.strategyEngine = decision.NewStrategyEngine(&cfg)
+	}
+
+	s.Run("Use static coins", func() {
+		useCoinSource(store.CoinSourceConfig{SourceType: "static", StaticCoins: []string{"BTC", "ETH", "BNB"}})
 
-		coins, err := s.autoTrader.getCandidateCoins()
+		coins, err := s.autoTrader.strategyEngine.GetCandidateCoins()
 
 		s.NoError(err)
 		s.Equal(3, len(coins))
 		s.Equal("BTCUSDT", coins[0].Symbol)
 		s.Equal("ETHUSDT", coins[1].Symbol)
 		s.Equal("BNBUSDT", coins[2].Symbol)
-		s.Contains(coins[0].Sources, "default")
+		s.Contains(coins[0].Sources, "static")
 	})
 
-	s.Run("Use custom coins", func() {
-		s.autoTrader.tradingCoins = []string{"SOL", "AVAX"}
+	s.Run("Use AI500 coin pool", func() {
+		useCoinSource(store.CoinSourceConfig{SourceType: "coinpool", UseCoinPool: true, CoinPoolLimit: 2})
 
-		coins, err := s.autoTrader.getCandidateCoins()
-
-		s.NoError(err)
-		s.Equal(2, len(coins))
-		s.Equal("SOLUSDT", coins[0].Symbol)
-		s.Equal("AVAXUSDT", coins[1].Symbol)
-		s.Contains(coins[0].Sources, "custom")
-	})
-
-	s.Run("Use AI500+OI as fallback", func() {
-		s.autoTrader.defaultCoins = []string{} // Empty default coins
-		s.autoTrader.tradingCoins = []string{} // Empty custom coins
-
-		// Mock pool.GetMergedCoinPool
-		s.patches.ApplyFunc(pool.GetMergedCoinPool, func(ai500Limit int) (*pool.MergedCoinPool, error) {
-			return &pool.MergedCoinPool{
-				AllSymbols: []string{"BTCUSDT", "ETHUSDT"},
-				SymbolSources: map[string][]string{
-					"BTCUSDT": {"ai500", "oi_top"},
-					"ETHUSDT": {"ai500"},
-				},
-			}, nil
+		// Mock pool.GetTopRatedCoins
+		s.patches.ApplyFunc(pool.GetTopRatedCoins, func(limit int) ([]string, error) {
+			return []string{"BTCUSDT", "ETHUSDT"}, nil
 		})
 
-		coins, err := s.autoTrader.getCandidateCoins()
+		coins, err := s.autoTrader.strategyEngine.GetCandidateCoins()
 
 		s.NoError(err)
 		s.Equal(2, len(coins))
+		s.Contains(coins[0].Sources, "ai500")
 	})
 }
 
@@ -390,8 +376,7 @@ func (s *AutoTraderTestSuite) TestBuildTradingContext() {
 	// Verify core fields
 	s.Equal(10100.0, ctx.Account.TotalEquity) // 10000 + 100
 	s.Equal(8000.0, ctx.Account.AvailableBalance)
-	s.Equal(10, ctx.BTCETHLeverage)
-	s.Equal(5, ctx.AltcoinLeverage)
+	s.Equal([]string{"BTCUSDT", "ETHUSDT"}, []string{ctx.CandidateCoins[0].Symbol, ctx.CandidateCoins[1].Symbol})
 }
 
 // ============================================================
@@ -428,19 +413,19 @@ func (s *AutoTraderTestSuite) TestExecuteOpenPosition() {
 			},
 		},
 		{
-			name:         "Long - insufficient margin",
+			name:         "Long - no available margin",
 			action:       "open_long",
 			availBalance: 0.0,
-			expectedErr:  "Insufficient margin",
+			expectedErr:  "below minimum",
 			executeFn: func(d *decision.Decision, a *store.DecisionAction) error {
 				return s.autoTrader.executeOpenLongWithRecord(d, a)
 			},
 		},
 		{
-			name:         "Short - insufficient margin",
+			name:         "Short - no available margin",
 			action:       "open_short",
 			availBalance: 0.0,
-			expectedErr:  "Insufficient margin",
+			expectedErr:  "below minimum",
 			executeFn: func(d *decision.Decision, a *store.DecisionAction) error {
 				return s.autoTrader.executeOpenShortWithRecord(d, a)
 			},
@@ -450,7 +435,7 @@ func (s *AutoTraderTestSuite) TestExecuteOpenPosition() {
 			action:       "open_long",
 			existingSide: "long",
 			availBalance: 8000.0,
-			expectedErr:  "Already has long position",
+			expectedErr:  "already has long position",
 			executeFn: func(d *decision.Decision, a *store.DecisionAction) error {
 				return s.autoTrader.executeOpenLongWithRecord(d, a)
 			},
@@ -460,7 +445,7 @@ func (s *AutoTraderTestSuite) TestExecuteOpenPosition() {
 			action:       "open_short",
 			existingSide: "short",
 			availBalance: 8000.0,
-			expectedErr:  "Already has short position",
+			expectedErr:  "already has short position",
 			executeFn: func(d *decision.Decision, a *store.DecisionAction) error {
 				return s.autoTrader.executeOpenShortWithRecord(d, a)
 			},
@@ -476,9 +461,9 @@ func (s *AutoTraderTestSuite) TestExecuteOpenPosition() {
 
 			s.mockTrader.balance["availableBalance"] = tt.availBalance
 			if tt.existingSide != "" {
-				s.mockTrader.positions = []map[string]interface{}{{"symbol": "BTCUSDT", "side": tt.existingSide}}
+				s.mockTrader.positions = []Position{{Symbol: "BTCUSDT", Side: tt.existingSide}}
 			} else {
-				s.mockTrader.positions = []map[string]interface{}{}
+				s.mockTrader.positions = []Position{}
 			}
 
 			decision := &decision.Decision{Action: tt.action, Symbol: "BTCUSDT", PositionSizeUSD: 1000.0, Leverage: 10}
@@ -498,7 +483,7 @@ func (s *AutoTraderTestSuite) TestExecuteOpenPosition() {
 
 			// Restore default state
 			s.mockTrader.balance["availableBalance"] = 8000.0
-			s.mockTrader.positions = []map[string]interface{}{}
+			s.mockTrader.positions = []Position{}
 		})
 	}
 }
@@ -608,7 +593,7 @@ func (s *AutoTraderTestSuite) TestExecuteDecisionWithRecord() {
 
 		err := s.autoTrader.executeDecisionWithRecord(decision, actionRecord)
 		s.Error(err)
-		s.Contains(err.Error(), "Unknown action")
+		s.Contains(err.Error(), "unknown action")
 	})
 }
 
@@ -631,14 +616,14 @@ func (s *AutoTraderTestSuite) TestCheckPositionDrawdown() {
 		},
 		{
 			name:           "No positions - no panic",
-			setupPositions: func() { s.mockTrader.positions = []map[string]interface{}{} },
+			setupPositions: func() { s.mockTrader.positions = []Position{} },
 			skipCacheCheck: true,
 		},
 		{
 			name: "Profit less than 5% - no close",
 			setupPositions: func() {
-				s.mockTrader.positions = []map[string]interface{}{
-					{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.1, "entryPrice": 50000.0, "markPrice": 50150.0, "leverage": 10.0},
+				s.mockTrader.positions = []Position{
+					{Symbol: "BTCUSDT", Side: "long", Quantity: 0.1, EntryPrice: 50000.0, MarkPrice: 50150.0, Leverage: 10.0},
 				}
 			},
 			setupPeakPnL:   func() { s.autoTrader.ClearPeakPnLCache("BTCUSDT", "long") },
@@ -647,8 +632,8 @@ func (s *AutoTraderTestSuite) TestCheckPositionDrawdown() {
 		{
 			name: "Drawdown less than 40% - no close",
 			setupPositions: func() {
-				s.mockTrader.positions = []map[string]interface{}{
-					{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.1, "entryPrice": 50000.0, "markPrice": 50400.0, "leverage": 10.0},
+				s.mockTrader.positions = []Position{
+					{Symbol: "BTCUSDT", Side: "long", Quantity: 0.1, EntryPrice: 50000.0, MarkPrice: 50400.0, Leverage: 10.0},
 				}
 			},
 			setupPeakPnL:   func() { s.autoTrader.UpdatePeakPnL("BTCUSDT", "long", 10.0) },
@@ -657,8 +642,8 @@ func (s *AutoTraderTestSuite) TestCheckPositionDrawdown() {
 		{
 			name: "Long - trigger drawdown close",
 			setupPositions: func() {
-				s.mockTrader.positions = []map[string]interface{}{
-					{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.1, "entryPrice": 50000.0, "markPrice": 50300.0, "leverage": 10.0},
+				s.mockTrader.positions = []Position{
+					{Symbol: "BTCUSDT", Side: "long", Quantity: 0.1, EntryPrice: 50000.0, MarkPrice: 50300.0, Leverage: 10.0},
 				}
 			},
 			setupPeakPnL:     func() { s.autoTrader.UpdatePeakPnL("BTCUSDT", "long", 10.0) },
@@ -668,8 +653,8 @@ func (s *AutoTraderTestSuite) TestCheckPositionDrawdown() {
 		{
 			name: "Short - trigger drawdown close",
 			setupPositions: func() {
-				s.mockTrader.positions = []map[string]interface{}{
-					{"symbol": "ETHUSDT", "side": "short", "positionAmt": -0.5, "entryPrice": 3000.0, "markPrice": 2982.0, "leverage": 10.0},
+				s.mockTrader.positions = []Position{
+					{Symbol: "ETHUSDT", Side: "short", Quantity: 0.5, EntryPrice: 3000.0, MarkPrice: 2982.0, Leverage: 10.0},
 				}
 			},
 			setupPeakPnL:     func() { s.autoTrader.UpdatePeakPnL("ETHUSDT", "short", 10.0) },
@@ -679,8 +664,8 @@ func (s *AutoTraderTestSuite) TestCheckPositionDrawdown() {
 		{
 			name: "Long - close failed - keep cache",
 			setupPositions: func() {
-				s.mockTrader.positions = []map[string]interface{}{
-					{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.1, "entryPrice": 50000.0, "markPrice": 50300.0, "leverage": 10.0},
+				s.mockTrader.positions = []Position{
+					{Symbol: "BTCUSDT", Side: "long", Quantity: 0.1, EntryPrice: 50000.0, MarkPrice: 50300.0, Leverage: 10.0},
 				}
 			},
 			setupPeakPnL:     func() { s.autoTrader.UpdatePeakPnL("BTCUSDT", "long", 10.0) },
@@ -692,8 +677,8 @@ func (s *AutoTraderTestSuite) TestCheckPositionDrawdown() {
 		{
 			name: "Short - close failed - keep cache",
 			setupPositions: func() {
-				s.mockTrader.positions = []map[string]interface{}{
-					{"symbol": "ETHUSDT", "side": "short", "positionAmt": -0.5, "entryPrice": 3000.0, "markPrice": 2982.0, "leverage": 10.0},
+				s.mockTrader.positions = []Position{
+					{Symbol: "ETHUSDT", Side: "short", Quantity: 0.5, EntryPrice: 3000.0, MarkPrice: 2982.0, Leverage: 10.0},
 				}
 			},
 			setupPeakPnL:     func() { s.autoTrader.UpdatePeakPnL("ETHUSDT", "short", 10.0) },
@@ -732,7 +717,7 @@ func (s *AutoTraderTestSuite) TestCheckPositionDrawdown() {
 			}
 
 			// Clean up state
-			s.mockTrader.positions = []map[string]interface{}{}
+			s.mockTrader.positions = []Position{}
 		})
 	}
 }
@@ -756,7 +741,7 @@ func (m *MockDatabase) UpdateTraderInitialBalance(userID, traderID string, newBa
 // MockTrader Enhanced version (with error control)
 type MockTrader struct {
 	balance              map[string]interface{}
-	positions            []map[string]interface{}
+	positions            []Position
 	shouldFailBalance    bool
 	shouldFailPositions  bool
 	shouldFailOpenLong   bool
@@ -778,12 +763,12 @@ func (m *MockTrader) GetBalance() (map[string]interface{}, error) {
 	return m.balance, nil
 }
 
-func (m *MockTrader) GetPositions() ([]map[string]interface{}, error) {
+func (m *MockTrader) GetPositions() ([]Position, error) {
 	if m.shouldFailPositions {
 		return nil, errors.New("failed to get positions")
 	}
 	if m.positions == nil {
-		return []map[string]interface{}{}, nil
+		return []Position{}, nil
 	}
 	return m.positions, nil
 }
@@ -865,6 +850,14 @@ func (m *MockTrader) FormatQuantity(symbol string, quantity float64) (string, er
 	return fmt.Sprintf("%.4f", quantity), nil
 }
 
+func (m *MockTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	return map[string]interface{}{"status": "FILLED"}, nil
+}
+
+func (m *MockTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	return nil, nil
+}
+
 // ============================================================
 // Test suite entry point
 // ============================================================
@@ -989,3 +982,66 @@ func TestCalculatePnLPercentage_RealWorldScenarios(t *testing.T) {
 		}
 	})
 }
+
+// TestEnforceLiquidityCap tests the liquidity cap is opt-in, Binance only and rejects entries it can't check
+func TestEnforceLiquidityCap(t *testing.T) {
+	strategy := &store.StrategyConfig{}
+	at := &AutoTrader{exchange: "binance", config: AutoTraderConfig{StrategyConfig: strategy}}
+
+	// Not configured: no market data fetched, size unchanged
+	if size, capped, err := at.enforceLiquidityCap(5000, "BTCUSDT"); err != nil || capped || size != 5000 {
+		t.Errorf("expected no cap when not configured, got %.2f capped=%v err=%v", size, capped, err)
+	}
+
+	strategy.RiskControl.MaxLiquidityVolumePct = 0.01
+	patches := gomonkey.ApplyFunc(market.GetLiquidityLimit, func(symbol string, volumePct, depthPct float64) (*market.LiquidityLimit, error) {
+		if symbol == "DOWNUSDT" {
+			return nil, errors.New("binance depth api returned status 503")
+		}
+		return &market.LiquidityLimit{Symbol: symbol, QuoteVolume1h: 100000, MaxNotional: 100000 * volumePct}, nil
+	})
+	defer patches.Reset()
+
+	if size, capped, err := at.enforceLiquidityCap(5000, "ALTUSDT"); err != nil || !capped || size != 1000 {
+		t.Errorf("expected size capped to 1%% of 1h volume, got %.2f capped=%v err=%v", size, capped, err)
+	}
+	if _, _, err := at.enforceLiquidityCap(5000, "DOWNUSDT"); err == nil {
+		t.Error("expected the entry rejected when liquidity can't be checked")
+	}
+
+	at.exchange = "bybit"
+	if size, capped, err := at.enforceLiquidityCap(5000, "ALTUSDT"); err != nil || capped || size != 5000 {
+		t.Errorf("expected the Binance liquidity cap skipped on bybit, got %.2f capped=%v err=%v", size, capped, err)
+	}
+}
+
+// TestEnforceSlippageLimit tests the slippage limit is opt-in, Binance only and rejects entries it can't check
+func TestEnforceSlippageLimit(t *testing.T) {
+	strategy := &store.StrategyConfig{}
+	at := &AutoTrader{exchange: "binance", config: AutoTraderConfig{StrategyConfig: strategy}}
+
+	if size, capped, err := at.enforceSlippageLimit(5000, "BTCUSDT", "buy"); err != nil || capped || size != 5000 {
+		t.Errorf("expected no limit when not configured, got %.2f capped=%v err=%v", size, capped, err)
+	}
+
+	strategy.RiskControl.MaxSlippageBps = 50
+	patches := gomonkey.ApplyFunc(market.EstimateSlippage, func(symbol, side string, notional, maxBps float64) (*market.SlippageEstimate, error) {
+		if symbol == "DOWNUSDT" {
+			return nil, errors.New("binance depth api returned status 503")
+		}
+		return &market.SlippageEstimate{Symbol: symbol, SlippageBps: 80, MaxNotional: 2000}, nil
+	})
+	defer patches.Reset()
+
+	if size, capped, err := at.enforceSlippageLimit(5000, "ALTUSDT", "buy"); err != nil || !capped || size != 2000 {
+		t.Errorf("expected entry downsized to the book's limit, got %.2f capped=%v err=%v", size, capped, err)
+	}
+	if _, _, err := at.enforceSlippageLimit(5000, "DOWNUSDT", "buy"); err == nil {
+		t.Error("expected the entry rejected when slippage can't be estimated")
+	}
+
+	at.exchange = "okx"
+	if size, capped, err := at.enforceSlippageLimit(5000, "ALTUSDT", "sell"); err != nil || capped || size != 5000 {
+		t.Errorf("expected the Binance slippage limit skipped on okx, got %.2f capped=%v err=%v", size, capped, err)
+	}
+}
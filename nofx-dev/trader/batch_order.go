@@ -0,0 +1,209 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nofx/decision"
+	"nofx/logger"
+	"strconv"
+	"strings"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+// ErrBatchUnsupported the entry can't be submitted as one batch, callers open and protect it separately
+var ErrBatchUnsupported = errors.New("batch entry not supported")
+
+// BatchEntryPlacer optional capability for exchanges that accept the entry and its stop loss / take profit
+// in one request, closing the window where a filled position sits unprotected
+type BatchEntryPlacer interface {
+	// OpenWithProtection opens a position (side "LONG"/"SHORT") together with its stop loss and take profit
+	// (0 = none). Returns ErrBatchUnsupported when the entry must go through OpenLong/OpenShort instead.
+	// protected reports whether every requested leg was placed
+	OpenWithProtection(symbol, positionSide string, quantity float64, leverage int, stopLoss, takeProfit float64) (order map[string]interface{}, protected bool, err error)
+}
+
+// batchEntryPlacer returns t as a BatchEntryPlacer when the exchange adapter it wraps can batch entries. Wrappers
+// pass batch entries through their own handling (fault injection, error classification, retries)
+func batchEntryPlacer(t Trader) (BatchEntryPlacer, bool) {
+	if _, ok := unwrapTrader(t).(BatchEntryPlacer); !ok {
+		return nil, false
+	}
+	placer, ok := t.(BatchEntryPlacer)
+	return placer, ok
+}
+
+// batchLeg result of one order in a batch request
+type batchLeg struct {
+	Order *futures.Order
+	Err   error
+}
+
+// batchLegs maps a batch response back to the submitted orders (Orders holds only the successful ones, in order)
+func batchLegs(resp *futures.CreateBatchOrdersResponse, n int) []batchLeg {
+	legs := make([]batchLeg, n)
+	next := 0
+	for i := range legs {
+		switch {
+		case resp == nil || i >= len(resp.Errors):
+			legs[i].Err = fmt.Errorf("missing from batch response")
+		case resp.Errors[i] != nil:
+			legs[i].Err = resp.Errors[i]
+		case next < len(resp.Orders):
+			legs[i].Order = resp.Orders[next]
+			next++
+		default:
+			legs[i].Err = fmt.Errorf("missing from batch response")
+		}
+	}
+	return legs
+}
+
+// OpenWithProtection submits a market entry with its stop loss and take profit through /fapi/v1/batchOrders
+// Limit entries and Portfolio Margin accounts aren't batched (ErrBatchUnsupported). Protective legs the
+// batch rejected are retried on their own, if the entry itself is rejected the placed legs are cancelled
+func (t *FuturesTrader) OpenWithProtection(symbol, positionSide string, quantity float64, leverage int, stopLoss, takeProfit float64) (map[string]interface{}, bool, error) {
+	if t.pm != nil || t.limitEntry.Enabled {
+		return nil, false, ErrBatchUnsupported
+	}
+
+	side, closeSide := futures.SideTypeBuy, futures.SideTypeSell
+	posSide := futures.PositionSideTypeLong
+	if strings.ToUpper(positionSide) == "SHORT" {
+		side, closeSide = futures.SideTypeSell, futures.SideTypeBuy
+		posSide = futures.PositionSideTypeShort
+	}
+
+	// Same preparation as OpenLong/OpenShort
+	if err := t.CancelAllOrders(symbol); err != nil {
+		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
+	}
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, false, err
+	}
+	quantityStr, err := t.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return nil, false, err
+	}
+	quantityFloat, parseErr := strconv.ParseFloat(quantityStr, 64)
+	if parseErr != nil || quantityFloat <= 0 {
+		return nil, false, fmt.Errorf("position size too small, rounded to 0 (original: %.8f → formatted: %s). Suggest increasing position amount or selecting a lower-priced coin", quantity, quantityStr)
+	}
+	if err := t.CheckMinNotional(symbol, quantityFloat); err != nil {
+		return nil, false, err
+	}
+
+	orders := []*futures.CreateOrderService{
+		t.client.NewCreateOrderService().
+			Symbol(symbol).
+			Side(side).
+			PositionSide(posSide).
+			Type(futures.OrderTypeMarket).
+			Quantity(quantityStr).
+			NewClientOrderID(t.marketClientOrderID(symbol)),
+	}
+	var legTypes []futures.OrderType
+	var superseded [][]int64
+	for _, leg := range []struct {
+		orderType futures.OrderType
+		price     float64
+	}{{futures.OrderTypeStopMarket, stopLoss}, {futures.OrderTypeTakeProfitMarket, takeProfit}} {
+		if leg.price <= 0 {
+			continue
+		}
+		cancel, err := t.reserveAlgoOrder(symbol, leg.orderType, posSide)
+		if err != nil {
+			return nil, false, err
+		}
+		orders = append(orders, t.client.NewCreateOrderService().
+			Symbol(symbol).
+			Side(closeSide).
+			PositionSide(posSide).
+			Type(leg.orderType).
+			StopPrice(fmt.Sprintf("%.8f", leg.price)).
+			Quantity(quantityStr).
+			WorkingType(futures.WorkingTypeContractPrice).
+			ClosePosition(true).
+			NewClientOrderID(getBrOrderID()))
+		legTypes = append(legTypes, leg.orderType)
+		superseded = append(superseded, cancel)
+	}
+
+	resp, err := t.client.NewCreateBatchOrdersService().OrderList(orders).Do(context.Background())
+	if err != nil {
+		t.algoOrders.invalidate()
+		return nil, false, fmt.Errorf("failed to open %s position: %w", strings.ToLower(positionSide), err)
+	}
+	legs := batchLegs(resp, len(orders))
+
+	entry := legs[0]
+	if entry.Err != nil {
+		// Don't leave protective orders behind for a position that was never opened
+		for _, leg := range legs[1:] {
+			if leg.Order == nil {
+				continue
+			}
+			if _, err := t.client.NewCancelOrderService().Symbol(symbol).OrderID(leg.Order.OrderID).Do(context.Background()); err != nil {
+				logger.Infof("  ⚠ Failed to cancel %s order %d of rejected entry: %v", leg.Order.Type, leg.Order.OrderID, err)
+				t.algoOrders.invalidate()
+			}
+		}
+		return nil, false, fmt.Errorf("failed to open %s position: %w", strings.ToLower(positionSide), entry.Err)
+	}
+
+	protected := true
+	for i, leg := range legs[1:] {
+		orderType := legTypes[i]
+		if leg.Order != nil {
+			t.algoOrders.add(conditionalOrder{OrderID: leg.Order.OrderID, Symbol: symbol, Type: string(orderType), PositionSide: string(posSide)})
+			t.cancelSuperseded(symbol, posSide, superseded[i])
+			continue
+		}
+		logger.Infof("  ⚠ Batch %s rejected, retrying on its own: %v", orderType, leg.Err)
+		t.algoOrders.invalidate()
+		if orderType == futures.OrderTypeStopMarket {
+			err = t.SetStopLoss(symbol, string(posSide), quantityFloat, stopLoss)
+		} else {
+			err = t.SetTakeProfit(symbol, string(posSide), quantityFloat, takeProfit)
+		}
+		if err != nil {
+			logger.Infof("  ⚠ %v", err)
+			protected = false
+		}
+	}
+
+	logger.Infof("✓ Opened %s position with %d protective orders in one batch: %s quantity: %s", strings.ToLower(positionSide), len(legTypes), symbol, quantityStr)
+	logger.Infof("  Order ID: %v", entry.Order.OrderID)
+	return map[string]interface{}{
+		"orderId": entry.Order.OrderID,
+		"symbol":  entry.Order.Symbol,
+		"status":  entry.Order.Status,
+	}, protected, nil
+}
+
+// openWithBracket opens a position (side "LONG"/"SHORT") with its stop loss and take profit in one batch when
+// the entry exchange supports it, and tracks the bracket. batched is false when nothing was submitted, the
+// caller then opens the position and sets the bracket separately
+func (at *AutoTrader) openWithBracket(d *decision.Decision, side string, quantity float64) (map[string]interface{}, bool, error) {
+	if d.StopLoss <= 0 && d.TakeProfit <= 0 {
+		return nil, false, nil
+	}
+	placer, ok := batchEntryPlacer(at.trader)
+	if !ok || (at.failover != nil && at.failover.FailedOver()) {
+		return nil, false, nil // Entries currently go to the fallback exchange
+	}
+
+	order, protected, err := placer.OpenWithProtection(d.Symbol, side, quantity, d.Leverage, d.StopLoss, d.TakeProfit)
+	if errors.Is(err, ErrBatchUnsupported) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, true, err
+	}
+	if !protected {
+		logger.Infof("  ⚠ %s %s opened without all of its protective orders", d.Symbol, side)
+	}
+	at.trackBracket(&bracket{Symbol: d.Symbol, Side: side, Quantity: quantity, StopLoss: d.StopLoss, TakeProfit: d.TakeProfit})
+	return order, true, nil
+}
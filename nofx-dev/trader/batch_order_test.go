@@ -0,0 +1,129 @@
+package trader
+
+import (
+	"errors"
+	"nofx/config"
+	"nofx/decision"
+	"testing"
+
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+// batchStub exchange that accepts or declines batched entries
+type batchStub struct {
+	Trader
+	err   error
+	calls int
+}
+
+func (s *batchStub) OpenWithProtection(symbol, positionSide string, quantity float64, leverage int, stopLoss, takeProfit float64) (map[string]interface{}, bool, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, false, s.err
+	}
+	return map[string]interface{}{"orderId": int64(1)}, true, nil
+}
+
+// TestBatchLegs tests that batch results map back to the submitted orders when some are rejected
+func TestBatchLegs(t *testing.T) {
+	rejected := &common.APIError{Code: -2021, Message: "Order would immediately trigger."}
+	resp := &futures.CreateBatchOrdersResponse{
+		N:      3,
+		Orders: []*futures.Order{{OrderID: 10}, {OrderID: 12}},
+		Errors: []error{nil, rejected, nil},
+	}
+	legs := batchLegs(resp, 3)
+	if legs[0].Order == nil || legs[0].Order.OrderID != 10 {
+		t.Errorf("expected entry order 10, got %+v", legs[0])
+	}
+	if legs[1].Order != nil || legs[1].Err != rejected {
+		t.Errorf("expected stop loss rejected, got %+v", legs[1])
+	}
+	if legs[2].Order == nil || legs[2].Order.OrderID != 12 {
+		t.Errorf("expected take profit order 12, got %+v", legs[2])
+	}
+
+	// Short response: missing orders count as failed
+	legs = batchLegs(&futures.CreateBatchOrdersResponse{N: 1, Orders: []*futures.Order{{OrderID: 10}}, Errors: []error{nil}}, 2)
+	if legs[1].Err == nil {
+		t.Errorf("expected missing leg to fail, got %+v", legs[1])
+	}
+}
+
+// TestOpenWithBracket tests the batch path tracks the bracket and unsupported batches fall back
+func TestOpenWithBracket(t *testing.T) {
+	d := &decision.Decision{Symbol: "BTCUSDT", Leverage: 5, StopLoss: 90, TakeProfit: 110}
+
+	stub := &batchStub{}
+	at := &AutoTrader{trader: stub, brackets: brackets{legs: make(map[string]*bracket)}}
+	order, batched, err := at.openWithBracket(d, "LONG", 1)
+	if err != nil || !batched || order["orderId"] != int64(1) {
+		t.Fatalf("expected batched entry, got %v %v %v", order, batched, err)
+	}
+	if b := at.brackets.legs["BTCUSDT_long"]; b == nil || b.StopLoss != 90 || b.TakeProfit != 110 {
+		t.Errorf("expected tracked bracket, got %+v", b)
+	}
+
+	// Unsupported: nothing submitted, caller opens the usual way
+	stub = &batchStub{err: ErrBatchUnsupported}
+	at = &AutoTrader{trader: stub, brackets: brackets{legs: make(map[string]*bracket)}}
+	if _, batched, err := at.openWithBracket(d, "LONG", 1); batched || err != nil {
+		t.Errorf("expected fallback, got %v %v", batched, err)
+	}
+
+	// Rejected entry is reported, not retried
+	stub = &batchStub{err: errors.New("insufficient margin")}
+	at = &AutoTrader{trader: stub, brackets: brackets{legs: make(map[string]*bracket)}}
+	if _, batched, err := at.openWithBracket(d, "LONG", 1); !batched || err == nil {
+		t.Errorf("expected batched error, got %v %v", batched, err)
+	}
+	if len(at.brackets.legs) != 0 {
+		t.Errorf("expected no bracket for a failed entry")
+	}
+
+	// Without stops there is nothing to batch
+	stub = &batchStub{}
+	at = &AutoTrader{trader: stub, brackets: brackets{legs: make(map[string]*bracket)}}
+	if _, batched, _ := at.openWithBracket(&decision.Decision{Symbol: "BTCUSDT"}, "LONG", 1); batched || stub.calls != 0 {
+		t.Errorf("expected no batch without stop loss / take profit")
+	}
+}
+
+// batchExchange exchange with positions that accepts batched entries, for the wrapper chain
+type batchExchange struct {
+	*stubExchange
+	calls int
+}
+
+func (b *batchExchange) OpenWithProtection(symbol, positionSide string, quantity float64, leverage int, stopLoss, takeProfit float64) (map[string]interface{}, bool, error) {
+	b.calls++
+	return map[string]interface{}{"orderId": int64(2)}, true, nil
+}
+
+// TestOpenWithBracketWrappers tests batched entries go through the trader wrappers, not around them
+func TestOpenWithBracketWrappers(t *testing.T) {
+	d := &decision.Decision{Symbol: "BTCUSDT", Leverage: 5, StopLoss: 90, TakeProfit: 110}
+	base := &batchExchange{stubExchange: &stubExchange{name: "binance"}}
+	faults := NewFaultInjectingTrader(base, config.FaultInjectionConfig{Enabled: true, RejectRate: 1}, "chaos")
+	chain := NewFailoverTrader(NewReduceOnlyTrader(NewIdempotentTrader(NewClassifiedTrader(faults, "binance"), "t1"), true), "binance", "ex1")
+	at := &AutoTrader{trader: chain, brackets: brackets{legs: make(map[string]*bracket)}}
+
+	// Injected rejection reaches the caller classified, the exchange isn't called
+	if _, batched, err := at.openWithBracket(d, "LONG", 1); !batched || KindOf(err) != ErrKindRejected || base.calls != 0 {
+		t.Fatalf("expected classified injected rejection, got batched=%v err=%v calls=%d", batched, err, base.calls)
+	}
+
+	faults.cfg.RejectRate = 0
+	order, batched, err := at.openWithBracket(d, "LONG", 1)
+	if err != nil || !batched || order["orderId"] != int64(2) || base.calls != 1 {
+		t.Errorf("expected batched entry through the wrappers, got %v %v %v (calls=%d)", order, batched, err, base.calls)
+	}
+
+	// Exchange without batch entries: the wrappers don't claim the capability
+	plain := NewFailoverTrader(NewClassifiedTrader(&stubExchange{name: "bybit"}, "bybit"), "bybit", "ex2")
+	at = &AutoTrader{trader: plain, brackets: brackets{legs: make(map[string]*bracket)}}
+	if _, batched, err := at.openWithBracket(d, "LONG", 1); batched || err != nil {
+		t.Errorf("expected fallback to separate orders, got %v %v", batched, err)
+	}
+}
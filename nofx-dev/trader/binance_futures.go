@@ -5,14 +5,20 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"math"
+	"nofx/config"
 	"nofx/hook"
 	"nofx/logger"
+	"nofx/market"
+	"nofx/ratelimit"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/adshao/go-binance/v2/common"
 	"github.com/adshao/go-binance/v2/futures"
+	"github.com/adshao/go-binance/v2/portfolio"
 )
 
 // getBrOrderID generates unique order ID (for futures contracts)
@@ -43,22 +49,42 @@ func getBrOrderID() string {
 	return orderID
 }
 
+func init() {
+	// USDⓈ-M futures base tier: 0.02% maker, 0.05% taker
+	logger.RegisterFeeSchedule("binance", logger.FeeSchedule{Maker: 0.0002, Taker: 0.0005})
+}
+
 // FuturesTrader Binance futures trader
 type FuturesTrader struct {
 	client *futures.Client
 
+	// Portfolio Margin client (non-nil when the account is on PM and must use /papi)
+	pm *portfolio.Client
+
 	// Balance cache
 	cachedBalance     map[string]interface{}
 	balanceCacheTime  time.Time
 	balanceCacheMutex sync.RWMutex
 
 	// Position cache
-	cachedPositions     []map[string]interface{}
+	cachedPositions     []Position
 	positionsCacheTime  time.Time
 	positionsCacheMutex sync.RWMutex
 
 	// Cache validity period (15 seconds)
 	cacheDuration time.Duration
+
+	// Limit order entry chase policy (market orders when disabled)
+	limitEntry config.LimitEntryConfig
+
+	// Open conditional order tracking (Binance caps stop/take-profit orders per symbol)
+	algoOrders *algoOrderTracker
+
+	// Caller-chosen client order keys for market orders (see ClientOrderIDTrader)
+	orderKeys clientOrderKeys
+
+	// Spot API base URL for wallet transfers (empty = Binance default)
+	spotBaseURL string
 }
 
 // NewFuturesTrader creates futures trader
@@ -70,11 +96,17 @@ func NewFuturesTrader(apiKey, secretKey string, userId string) *FuturesTrader {
 		client = hookRes.GetResult()
 	}
 
+	// Share Binance request weight budget across all traders and market data clients
+	client.HTTPClient = ratelimit.WrapClient("binance", client.HTTPClient)
+
 	// Sync time to avoid "Timestamp ahead" error
 	syncBinanceServerTime(client)
 	trader := &FuturesTrader{
 		client:        client,
+		pm:            detectPortfolioMargin(client.APIKey, client.SecretKey, client.TimeOffset),
 		cacheDuration: 15 * time.Second, // 15-second cache
+		limitEntry:    limitEntryConfig(),
+		algoOrders:    newAlgoOrderTracker(),
 	}
 
 	// Set dual-side position mode (Hedge Mode)
@@ -88,6 +120,10 @@ func NewFuturesTrader(apiKey, secretKey string, userId string) *FuturesTrader {
 
 // setDualSidePosition sets dual-side position mode (called during initialization)
 func (t *FuturesTrader) setDualSidePosition() error {
+	if t.pm != nil {
+		return t.pmSetDualSidePosition()
+	}
+
 	// Try to set dual-side position mode
 	err := t.client.NewChangePositionModeService().
 		DualSide(true). // true = dual-side position (Hedge Mode)
@@ -136,6 +172,18 @@ func (t *FuturesTrader) GetBalance() (map[string]interface{}, error) {
 
 	// Cache expired or doesn't exist, call API
 	logger.Infof("🔄 Cache expired, calling Binance API to get account balance...")
+	if t.pm != nil {
+		result, err := t.pmGetBalance()
+		if err != nil {
+			return nil, err
+		}
+		t.balanceCacheMutex.Lock()
+		t.cachedBalance = result
+		t.balanceCacheTime = time.Now()
+		t.balanceCacheMutex.Unlock()
+		return result, nil
+	}
+
 	account, err := t.client.NewGetAccountService().Do(context.Background())
 	if err != nil {
 		logger.Infof("❌ Binance API call failed: %v", err)
@@ -147,6 +195,21 @@ func (t *FuturesTrader) GetBalance() (map[string]interface{}, error) {
 	result["availableBalance"], _ = strconv.ParseFloat(account.AvailableBalance, 64)
 	result["totalUnrealizedProfit"], _ = strconv.ParseFloat(account.TotalUnrealizedProfit, 64)
 
+	// Per quote asset availability (USDT/USDC), needed for USDC-margined contracts in single-asset mode
+	quoteBalances := make(map[string]float64)
+	for _, asset := range account.Assets {
+		if market.IsSupportedQuote(asset.Asset) {
+			quoteBalances[asset.Asset], _ = strconv.ParseFloat(asset.AvailableBalance, 64)
+		}
+	}
+	result["quoteBalances"] = quoteBalances
+	result["multiAssetsMargin"] = account.MultiAssetsMargin
+	maintMargin, _ := strconv.ParseFloat(account.TotalMaintMargin, 64)
+	marginBalance, _ := strconv.ParseFloat(account.TotalMarginBalance, 64)
+	if marginBalance > 0 {
+		result["marginRatio"] = maintMargin / marginBalance * 100
+	}
+
 	logger.Infof("✓ Binance API returned: total balance=%s, available=%s, unrealized PnL=%s",
 		account.TotalWalletBalance,
 		account.AvailableBalance,
@@ -162,7 +225,7 @@ func (t *FuturesTrader) GetBalance() (map[string]interface{}, error) {
 }
 
 // GetPositions gets all positions (with cache)
-func (t *FuturesTrader) GetPositions() ([]map[string]interface{}, error) {
+func (t *FuturesTrader) GetPositions() ([]Position, error) {
 	// First check if cache is valid
 	t.positionsCacheMutex.RLock()
 	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
@@ -175,36 +238,47 @@ func (t *FuturesTrader) GetPositions() ([]map[string]interface{}, error) {
 
 	// Cache expired or doesn't exist, call API
 	logger.Infof("🔄 Cache expired, calling Binance API to get position information...")
+	if t.pm != nil {
+		result, err := t.pmGetPositions()
+		if err != nil {
+			return nil, err
+		}
+		t.positionsCacheMutex.Lock()
+		t.cachedPositions = result
+		t.positionsCacheTime = time.Now()
+		t.positionsCacheMutex.Unlock()
+		return result, nil
+	}
+
 	positions, err := t.client.NewGetPositionRiskService().Do(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get positions: %w", err)
 	}
 
-	var result []map[string]interface{}
+	var result []Position
 	for _, pos := range positions {
 		posAmt, _ := strconv.ParseFloat(pos.PositionAmt, 64)
 		if posAmt == 0 {
 			continue // Skip positions with zero amount
 		}
 
-		posMap := make(map[string]interface{})
-		posMap["symbol"] = pos.Symbol
-		posMap["positionAmt"], _ = strconv.ParseFloat(pos.PositionAmt, 64)
-		posMap["entryPrice"], _ = strconv.ParseFloat(pos.EntryPrice, 64)
-		posMap["markPrice"], _ = strconv.ParseFloat(pos.MarkPrice, 64)
-		posMap["unRealizedProfit"], _ = strconv.ParseFloat(pos.UnRealizedProfit, 64)
-		posMap["leverage"], _ = strconv.ParseFloat(pos.Leverage, 64)
-		posMap["liquidationPrice"], _ = strconv.ParseFloat(pos.LiquidationPrice, 64)
+		p := Position{Symbol: pos.Symbol, Quantity: math.Abs(posAmt)}
+		p.EntryPrice, _ = strconv.ParseFloat(pos.EntryPrice, 64)
+		p.MarkPrice, _ = strconv.ParseFloat(pos.MarkPrice, 64)
+		p.UnrealizedPnL, _ = strconv.ParseFloat(pos.UnRealizedProfit, 64)
+		p.Leverage, _ = strconv.ParseFloat(pos.Leverage, 64)
+		p.LiquidationPrice, _ = strconv.ParseFloat(pos.LiquidationPrice, 64)
+		p.MarginMode = strings.ToLower(pos.MarginType) // "cross" / "isolated"
 		// Note: Binance SDK doesn't expose updateTime field, will fallback to local tracking
 
 		// Determine direction
 		if posAmt > 0 {
-			posMap["side"] = "long"
+			p.Side = "long"
 		} else {
-			posMap["side"] = "short"
+			p.Side = "short"
 		}
 
-		result = append(result, posMap)
+		result = append(result, p)
 	}
 
 	// Update cache
@@ -218,6 +292,14 @@ func (t *FuturesTrader) GetPositions() ([]map[string]interface{}, error) {
 
 // SetMarginMode sets margin mode
 func (t *FuturesTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	// Portfolio Margin UM positions are always cross margin
+	if t.pm != nil {
+		if !isCrossMargin {
+			logger.Infof("  ⚠️ %s Portfolio Margin account only supports Cross Margin, ignoring isolated setting", symbol)
+		}
+		return nil
+	}
+
 	var marginType futures.MarginType
 	if isCrossMargin {
 		marginType = futures.MarginTypeCrossed
@@ -274,11 +356,9 @@ func (t *FuturesTrader) SetLeverage(symbol string, leverage int) error {
 	positions, err := t.GetPositions()
 	if err == nil {
 		for _, pos := range positions {
-			if pos["symbol"] == symbol {
-				if lev, ok := pos["leverage"].(float64); ok {
-					currentLeverage = int(lev)
-					break
-				}
+			if pos.Symbol == symbol {
+				currentLeverage = int(pos.Leverage)
+				break
 			}
 		}
 	}
@@ -290,10 +370,14 @@ func (t *FuturesTrader) SetLeverage(symbol string, leverage int) error {
 	}
 
 	// Change leverage
-	_, err = t.client.NewChangeLeverageService().
-		Symbol(symbol).
-		Leverage(leverage).
-		Do(context.Background())
+	if t.pm != nil {
+		err = t.pmChangeLeverage(symbol, leverage)
+	} else {
+		_, err = t.client.NewChangeLeverageService().
+			Symbol(symbol).
+			Leverage(leverage).
+			Do(context.Background())
+	}
 
 	if err != nil {
 		// If error message contains "No need to change", leverage is already the target value
@@ -344,27 +428,16 @@ func (t *FuturesTrader) OpenLong(symbol string, quantity float64, leverage int)
 		return nil, err
 	}
 
-	// Create market buy order (using br ID)
-	order, err := t.client.NewCreateOrderService().
-		Symbol(symbol).
-		Side(futures.SideTypeBuy).
-		PositionSide(futures.PositionSideTypeLong).
-		Type(futures.OrderTypeMarket).
-		Quantity(quantityStr).
-		NewClientOrderID(getBrOrderID()).
-		Do(context.Background())
+	// Create entry order: market, or limit orders chased toward mark price (using br ID)
+	result, err := t.createEntryOrder(symbol, futures.SideTypeBuy, futures.PositionSideTypeLong, quantityStr)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to open long position: %w", err)
 	}
 
 	logger.Infof("✓ Opened long position successfully: %s quantity: %s", symbol, quantityStr)
-	logger.Infof("  Order ID: %d", order.OrderID)
+	logger.Infof("  Order ID: %v", result["orderId"])
 
-	result := make(map[string]interface{})
-	result["orderId"] = order.OrderID
-	result["symbol"] = order.Symbol
-	result["status"] = order.Status
 	return result, nil
 }
 
@@ -399,27 +472,16 @@ func (t *FuturesTrader) OpenShort(symbol string, quantity float64, leverage int)
 		return nil, err
 	}
 
-	// Create market sell order (using br ID)
-	order, err := t.client.NewCreateOrderService().
-		Symbol(symbol).
-		Side(futures.SideTypeSell).
-		PositionSide(futures.PositionSideTypeShort).
-		Type(futures.OrderTypeMarket).
-		Quantity(quantityStr).
-		NewClientOrderID(getBrOrderID()).
-		Do(context.Background())
+	// Create entry order: market, or limit orders chased toward mark price (using br ID)
+	result, err := t.createEntryOrder(symbol, futures.SideTypeSell, futures.PositionSideTypeShort, quantityStr)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to open short position: %w", err)
 	}
 
 	logger.Infof("✓ Opened short position successfully: %s quantity: %s", symbol, quantityStr)
-	logger.Infof("  Order ID: %d", order.OrderID)
+	logger.Infof("  Order ID: %v", result["orderId"])
 
-	result := make(map[string]interface{})
-	result["orderId"] = order.OrderID
-	result["symbol"] = order.Symbol
-	result["status"] = order.Status
 	return result, nil
 }
 
@@ -433,8 +495,8 @@ func (t *FuturesTrader) CloseLong(symbol string, quantity float64) (map[string]i
 		}
 
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "long" {
-				quantity = pos["positionAmt"].(float64)
+			if pos.Symbol == symbol && pos.Side == "long" {
+				quantity = pos.Quantity
 				break
 			}
 		}
@@ -451,14 +513,7 @@ func (t *FuturesTrader) CloseLong(symbol string, quantity float64) (map[string]i
 	}
 
 	// Create market sell order (close long, using br ID)
-	order, err := t.client.NewCreateOrderService().
-		Symbol(symbol).
-		Side(futures.SideTypeSell).
-		PositionSide(futures.PositionSideTypeLong).
-		Type(futures.OrderTypeMarket).
-		Quantity(quantityStr).
-		NewClientOrderID(getBrOrderID()).
-		Do(context.Background())
+	order, err := t.createMarketOrder(symbol, futures.SideTypeSell, futures.PositionSideTypeLong, quantityStr)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to close long position: %w", err)
@@ -488,8 +543,8 @@ func (t *FuturesTrader) CloseShort(symbol string, quantity float64) (map[string]
 		}
 
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "short" {
-				quantity = -pos["positionAmt"].(float64) // Short position quantity is negative, take absolute value
+			if pos.Symbol == symbol && pos.Side == "short" {
+				quantity = pos.Quantity
 				break
 			}
 		}
@@ -506,14 +561,7 @@ func (t *FuturesTrader) CloseShort(symbol string, quantity float64) (map[string]
 	}
 
 	// Create market buy order (close short, using br ID)
-	order, err := t.client.NewCreateOrderService().
-		Symbol(symbol).
-		Side(futures.SideTypeBuy).
-		PositionSide(futures.PositionSideTypeShort).
-		Type(futures.OrderTypeMarket).
-		Quantity(quantityStr).
-		NewClientOrderID(getBrOrderID()).
-		Do(context.Background())
+	order, err := t.createMarketOrder(symbol, futures.SideTypeBuy, futures.PositionSideTypeShort, quantityStr)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to close short position: %w", err)
@@ -535,6 +583,12 @@ func (t *FuturesTrader) CloseShort(symbol string, quantity float64) (map[string]
 
 // CancelStopLossOrders cancels only stop-loss orders (doesn't affect take-profit orders)
 func (t *FuturesTrader) CancelStopLossOrders(symbol string) error {
+	if t.pm != nil {
+		return t.pmCancelConditionalOrders(symbol, "stop-loss", func(strategyType string) bool {
+			return strategyType == string(futures.OrderTypeStopMarket) || strategyType == string(futures.OrderTypeStop)
+		})
+	}
+
 	// Get all open orders for this symbol
 	orders, err := t.client.NewListOpenOrdersService().
 		Symbol(symbol).
@@ -575,6 +629,8 @@ func (t *FuturesTrader) CancelStopLossOrders(symbol string) error {
 		logger.Infof("  ✓ Canceled %d stop-loss order(s) for %s", canceledCount, symbol)
 	}
 
+	t.algoOrders.invalidate()
+
 	// If all cancellations failed, return error
 	if len(cancelErrors) > 0 && canceledCount == 0 {
 		return fmt.Errorf("failed to cancel stop-loss orders: %v", cancelErrors)
@@ -585,6 +641,12 @@ func (t *FuturesTrader) CancelStopLossOrders(symbol string) error {
 
 // CancelTakeProfitOrders cancels only take-profit orders (doesn't affect stop-loss orders)
 func (t *FuturesTrader) CancelTakeProfitOrders(symbol string) error {
+	if t.pm != nil {
+		return t.pmCancelConditionalOrders(symbol, "take-profit", func(strategyType string) bool {
+			return strategyType == string(futures.OrderTypeTakeProfitMarket) || strategyType == string(futures.OrderTypeTakeProfit)
+		})
+	}
+
 	// Get all open orders for this symbol
 	orders, err := t.client.NewListOpenOrdersService().
 		Symbol(symbol).
@@ -625,6 +687,8 @@ func (t *FuturesTrader) CancelTakeProfitOrders(symbol string) error {
 		logger.Infof("  ✓ Canceled %d take-profit order(s) for %s", canceledCount, symbol)
 	}
 
+	t.algoOrders.invalidate()
+
 	// If all cancellations failed, return error
 	if len(cancelErrors) > 0 && canceledCount == 0 {
 		return fmt.Errorf("failed to cancel take-profit orders: %v", cancelErrors)
@@ -635,10 +699,15 @@ func (t *FuturesTrader) CancelTakeProfitOrders(symbol string) error {
 
 // CancelAllOrders cancels all pending orders for this symbol
 func (t *FuturesTrader) CancelAllOrders(symbol string) error {
+	if t.pm != nil {
+		return t.pmCancelAllOrders(symbol)
+	}
+
 	err := t.client.NewCancelAllOpenOrdersService().
 		Symbol(symbol).
 		Do(context.Background())
 
+	t.algoOrders.invalidate()
 	if err != nil {
 		return fmt.Errorf("failed to cancel pending orders: %w", err)
 	}
@@ -649,6 +718,10 @@ func (t *FuturesTrader) CancelAllOrders(symbol string) error {
 
 // CancelStopOrders cancels take-profit/stop-loss orders for this symbol (used to adjust TP/SL positions)
 func (t *FuturesTrader) CancelStopOrders(symbol string) error {
+	if t.pm != nil {
+		return t.pmCancelConditionalOrders(symbol, "take-profit/stop-loss", func(string) bool { return true })
+	}
+
 	// Get all open orders for this symbol
 	orders, err := t.client.NewListOpenOrdersService().
 		Symbol(symbol).
@@ -685,6 +758,8 @@ func (t *FuturesTrader) CancelStopOrders(symbol string) error {
 		}
 	}
 
+	t.algoOrders.invalidate()
+
 	if canceledCount == 0 {
 		logger.Infof("  ℹ %s has no take-profit/stop-loss orders to cancel", symbol)
 	} else {
@@ -694,6 +769,111 @@ func (t *FuturesTrader) CancelStopOrders(symbol string) error {
 	return nil
 }
 
+// createMarketOrder places a market order through the classic or Portfolio Margin endpoint (using br ID)
+func (t *FuturesTrader) createMarketOrder(symbol string, side futures.SideType, posSide futures.PositionSideType, quantity string) (*futures.CreateOrderResponse, error) {
+	if t.pm != nil {
+		return t.pmCreateMarketOrder(symbol, side, posSide, quantity)
+	}
+	return t.client.NewCreateOrderService().
+		Symbol(symbol).
+		Side(side).
+		PositionSide(posSide).
+		Type(futures.OrderTypeMarket).
+		Quantity(quantity).
+		NewClientOrderID(t.marketClientOrderID(symbol)).
+		Do(context.Background())
+}
+
+// brClientOrderID builds a br-tagged client order ID from a client order key (31 characters like getBrOrderID)
+func brClientOrderID(key string) string {
+	id := "x-KzrpZaP9" + key
+	if len(id) > 31 {
+		id = id[:31]
+	}
+	return id
+}
+
+// marketClientOrderID returns the client order ID of the next market order on symbol
+func (t *FuturesTrader) marketClientOrderID(symbol string) string {
+	if key := t.orderKeys.get(symbol); key != "" {
+		return brClientOrderID(key)
+	}
+	return getBrOrderID()
+}
+
+// SetClientOrderKey makes market orders on symbol use an ID derived from key ("" restores generated IDs)
+func (t *FuturesTrader) SetClientOrderKey(symbol, key string) {
+	t.orderKeys.set(symbol, key)
+}
+
+// FindOrderByClientKey looks up the order placed with key's client order ID
+func (t *FuturesTrader) FindOrderByClientKey(symbol, key string) (map[string]interface{}, bool, error) {
+	clientOrderID := brClientOrderID(key)
+	var (
+		result map[string]interface{}
+		err    error
+	)
+	if t.pm != nil {
+		result, err = t.pmFindOrderByClientID(symbol, clientOrderID)
+	} else {
+		var order *futures.Order
+		order, err = t.client.NewGetOrderService().
+			Symbol(symbol).
+			OrigClientOrderID(clientOrderID).
+			Do(context.Background())
+		if err == nil {
+			result = futuresOrderStatus(order)
+		}
+	}
+	if err != nil {
+		// -2013: order does not exist
+		if apiErr, ok := err.(*common.APIError); ok && apiErr.Code == -2013 {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return result, true, nil
+}
+
+// invalidateCaches clears balance/position caches (forces the next query to hit the API)
+func (t *FuturesTrader) invalidateCaches() {
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = nil
+	t.balanceCacheMutex.Unlock()
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = nil
+	t.positionsCacheMutex.Unlock()
+}
+
+// createStopOrder places a stop-loss/take-profit order that closes the position when triggered
+func (t *FuturesTrader) createStopOrder(symbol string, side futures.SideType, posSide futures.PositionSideType, orderType futures.OrderType, stopPrice float64, quantity string) error {
+	if t.pm != nil {
+		return t.pmCreateStopOrder(symbol, side, posSide, orderType, stopPrice, quantity)
+	}
+	superseded, err := t.reserveAlgoOrder(symbol, orderType, posSide)
+	if err != nil {
+		return err
+	}
+	order, err := t.client.NewCreateOrderService().
+		Symbol(symbol).
+		Side(side).
+		PositionSide(posSide).
+		Type(orderType).
+		StopPrice(fmt.Sprintf("%.8f", stopPrice)).
+		Quantity(quantity).
+		WorkingType(futures.WorkingTypeContractPrice).
+		ClosePosition(true).
+		NewClientOrderID(getBrOrderID()).
+		Do(context.Background())
+	if err != nil {
+		t.algoOrders.invalidate()
+		return err
+	}
+	t.algoOrders.add(conditionalOrder{OrderID: order.OrderID, Symbol: symbol, Type: string(orderType), PositionSide: string(posSide)})
+	t.cancelSuperseded(symbol, posSide, superseded)
+	return nil
+}
+
 // GetMarketPrice gets market price
 func (t *FuturesTrader) GetMarketPrice(symbol string) (float64, error) {
 	prices, err := t.client.NewListPricesService().Symbol(symbol).Do(context.Background())
@@ -740,17 +920,7 @@ func (t *FuturesTrader) SetStopLoss(symbol string, positionSide string, quantity
 		return err
 	}
 
-	_, err = t.client.NewCreateOrderService().
-		Symbol(symbol).
-		Side(side).
-		PositionSide(posSide).
-		Type(futures.OrderTypeStopMarket).
-		StopPrice(fmt.Sprintf("%.8f", stopPrice)).
-		Quantity(quantityStr).
-		WorkingType(futures.WorkingTypeContractPrice).
-		ClosePosition(true).
-		NewClientOrderID(getBrOrderID()).
-		Do(context.Background())
+	err = t.createStopOrder(symbol, side, posSide, futures.OrderTypeStopMarket, stopPrice, quantityStr)
 
 	if err != nil {
 		return fmt.Errorf("failed to set stop-loss: %w", err)
@@ -779,17 +949,7 @@ func (t *FuturesTrader) SetTakeProfit(symbol string, positionSide string, quanti
 		return err
 	}
 
-	_, err = t.client.NewCreateOrderService().
-		Symbol(symbol).
-		Side(side).
-		PositionSide(posSide).
-		Type(futures.OrderTypeTakeProfitMarket).
-		StopPrice(fmt.Sprintf("%.8f", takeProfitPrice)).
-		Quantity(quantityStr).
-		WorkingType(futures.WorkingTypeContractPrice).
-		ClosePosition(true).
-		NewClientOrderID(getBrOrderID()).
-		Do(context.Background())
+	err = t.createStopOrder(symbol, side, posSide, futures.OrderTypeTakeProfitMarket, takeProfitPrice, quantityStr)
 
 	if err != nil {
 		return fmt.Errorf("failed to set take-profit: %w", err)
@@ -817,8 +977,8 @@ func (t *FuturesTrader) CheckMinNotional(symbol string, quantity float64) error
 
 	if notionalValue < minNotional {
 		return fmt.Errorf(
-			"order amount %.2f USDT is below minimum requirement %.2f USDT (quantity: %.4f, price: %.4f)",
-			notionalValue, minNotional, quantity, price,
+			"order amount %.2f %s is below minimum requirement %.2f %s (quantity: %.4f, price: %.4f)",
+			notionalValue, quoteAssetOf(symbol), minNotional, quoteAssetOf(symbol), quantity, price,
 		)
 	}
 
@@ -926,6 +1086,9 @@ func (t *FuturesTrader) GetOrderStatus(symbol string, orderID string) (map[strin
 	if err != nil {
 		return nil, fmt.Errorf("invalid order ID: %s", orderID)
 	}
+	if t.pm != nil {
+		return t.pmGetOrderStatus(symbol, orderIDInt)
+	}
 
 	order, err := t.client.NewGetOrderService().
 		Symbol(symbol).
@@ -934,7 +1097,11 @@ func (t *FuturesTrader) GetOrderStatus(symbol string, orderID string) (map[strin
 	if err != nil {
 		return nil, fmt.Errorf("failed to get order status: %w", err)
 	}
+	return futuresOrderStatus(order), nil
+}
 
+// futuresOrderStatus converts an order query response to the GetOrderStatus result
+func futuresOrderStatus(order *futures.Order) map[string]interface{} {
 	// Parse execution price
 	avgPrice, _ := strconv.ParseFloat(order.AvgPrice, 64)
 	executedQty, _ := strconv.ParseFloat(order.ExecutedQuantity, 64)
@@ -955,7 +1122,7 @@ func (t *FuturesTrader) GetOrderStatus(symbol string, orderID string) (map[strin
 	// Can be obtained later through WebSocket or separate query
 	result["commission"] = 0.0
 
-	return result, nil
+	return result
 }
 
 // GetClosedPnL retrieves recent closing trades from Binance Futures
@@ -1027,6 +1194,10 @@ func (t *FuturesTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord
 		limit = 1000
 	}
 
+	if t.pm != nil {
+		return t.pmGetTrades(startTime, limit)
+	}
+
 	// Use Income API to get REALIZED_PNL records (all symbols)
 	incomes, err := t.client.NewGetIncomeHistoryService().
 		IncomeType("REALIZED_PNL").
@@ -1070,6 +1241,10 @@ func (t *FuturesTrader) GetTradesForSymbol(symbol string, startTime time.Time, l
 		limit = 1000
 	}
 
+	if t.pm != nil {
+		return t.pmGetTradesForSymbol(symbol, startTime, limit)
+	}
+
 	accountTrades, err := t.client.NewListAccountTradeService().
 		Symbol(symbol).
 		StartTime(startTime.UnixMilli()).
@@ -273,6 +273,7 @@ func NewBinanceFuturesTestSuite(t *testing.T) *BinanceFuturesTestSuite {
 	trader := &FuturesTrader{
 		client:        client,
 		cacheDuration: 0, // disable cache for testing
+		algoOrders:    newAlgoOrderTracker(),
 	}
 
 	// Create base suite
@@ -0,0 +1,320 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nofx/config"
+	"nofx/logger"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+// errPostOnlyRejected limit order would have crossed the book and was rejected as post-only
+var errPostOnlyRejected = errors.New("post-only order would take liquidity")
+
+// chasePollInterval how often a resting limit order is checked for fills
+const chasePollInterval = time.Second
+
+// limitEntryConfig returns the global limit entry chase policy
+func limitEntryConfig() config.LimitEntryConfig {
+	return config.Get().LimitEntry
+}
+
+// chaseFill order fill state
+type chaseFill struct {
+	Status      string
+	ExecutedQty float64
+	AvgPrice    float64
+}
+
+// limitChaseVenue order operations used by the chase loop (formatting to tick/step size is up to the venue)
+type limitChaseVenue interface {
+	MarkPrice(symbol string) (float64, error)
+	PlaceLimit(symbol string, side futures.SideType, posSide futures.PositionSideType, price, quantity float64) (int64, error)
+	PlaceMarket(symbol string, side futures.SideType, posSide futures.PositionSideType, quantity float64) (int64, error)
+	OrderFill(symbol string, orderID int64) (chaseFill, error)
+	Cancel(symbol string, orderID int64) error
+}
+
+// chaseRequest entry to be filled by the chase loop
+type chaseRequest struct {
+	Symbol       string
+	Side         futures.SideType
+	PositionSide futures.PositionSideType
+	Quantity     float64
+	Step         float64 // Quantity step size, remaining quantity below it counts as filled
+}
+
+// chaseResult aggregated fills of all orders placed for one entry
+type chaseResult struct {
+	OrderID     int64 // Last order that filled (used for order status lookups)
+	ExecutedQty float64
+	AvgPrice    float64
+	MakerQty    float64 // Quantity filled by limit orders
+	TakerQty    float64 // Quantity filled by the market fallback
+	Reprices    int
+}
+
+func (r *chaseResult) add(orderID int64, fill chaseFill, maker bool) {
+	if fill.ExecutedQty <= 0 {
+		return
+	}
+	notional := r.AvgPrice*r.ExecutedQty + fill.AvgPrice*fill.ExecutedQty
+	r.ExecutedQty += fill.ExecutedQty
+	r.AvgPrice = notional / r.ExecutedQty
+	r.OrderID = orderID
+	if maker {
+		r.MakerQty += fill.ExecutedQty
+	} else {
+		r.TakerQty += fill.ExecutedQty
+	}
+}
+
+// limitChaser fills an entry with post-only limit orders, repricing toward mark price
+// Attempt i of N rests at mark ∓ offset*(N-i)/N, so the last attempt sits at mark price;
+// whatever is still unfilled afterwards goes out as a market order if the policy allows
+type limitChaser struct {
+	venue  limitChaseVenue
+	policy config.LimitEntryConfig
+	sleep  func(time.Duration)
+}
+
+func (c *limitChaser) run(req chaseRequest) (*chaseResult, error) {
+	result := &chaseResult{}
+	remaining := func() float64 { return req.Quantity - result.ExecutedQty }
+
+	for attempt := 0; attempt <= c.policy.MaxReprices && remaining() >= req.Step; attempt++ {
+		mark, err := c.venue.MarkPrice(req.Symbol)
+		if err != nil {
+			logger.Infof("  ⚠️ Limit chase %s: failed to get mark price: %v", req.Symbol, err)
+			break
+		}
+		offset := c.policy.OffsetBps / 10000
+		if c.policy.MaxReprices > 0 {
+			offset *= float64(c.policy.MaxReprices-attempt) / float64(c.policy.MaxReprices)
+		}
+		price := mark * (1 - offset)
+		if req.Side == futures.SideTypeSell {
+			price = mark * (1 + offset)
+		}
+
+		if attempt > 0 {
+			result.Reprices++
+		}
+		orderID, err := c.venue.PlaceLimit(req.Symbol, req.Side, req.PositionSide, price, remaining())
+		if errors.Is(err, errPostOnlyRejected) {
+			logger.Infof("  ↻ Limit chase %s: %.6f would cross the book, repricing", req.Symbol, price)
+			continue
+		}
+		if err != nil {
+			if result.ExecutedQty == 0 && !c.policy.FallbackToMarket {
+				return nil, err
+			}
+			logger.Infof("  ⚠️ Limit chase %s: failed to place limit order: %v", req.Symbol, err)
+			break
+		}
+
+		fill := c.await(req.Symbol, orderID)
+		if fill.Status != "FILLED" {
+			if err := c.venue.Cancel(req.Symbol, orderID); err != nil {
+				logger.Infof("  ⚠️ Limit chase %s: failed to cancel order %d: %v", req.Symbol, orderID, err)
+			}
+			// Re-read after cancel, the order may have filled further in the meantime
+			if final, err := c.venue.OrderFill(req.Symbol, orderID); err == nil {
+				fill = final
+			}
+		}
+		result.add(orderID, fill, true)
+		logger.Infof("  ↻ Limit chase %s: attempt %d @ %.6f filled %.6f, remaining %.6f",
+			req.Symbol, attempt+1, price, fill.ExecutedQty, remaining())
+	}
+
+	if remaining() >= req.Step && c.policy.FallbackToMarket {
+		orderID, err := c.venue.PlaceMarket(req.Symbol, req.Side, req.PositionSide, remaining())
+		if err != nil {
+			if result.ExecutedQty == 0 {
+				return nil, err
+			}
+			logger.Infof("  ⚠️ Limit chase %s: market fallback failed, keeping partial fill: %v", req.Symbol, err)
+		} else {
+			c.sleep(500 * time.Millisecond)
+			fill, err := c.venue.OrderFill(req.Symbol, orderID)
+			if err != nil || fill.ExecutedQty <= 0 {
+				// Market order accepted but fill not yet visible, count it as filled at mark
+				mark, _ := c.venue.MarkPrice(req.Symbol)
+				fill = chaseFill{Status: "FILLED", ExecutedQty: remaining(), AvgPrice: mark}
+			}
+			result.add(orderID, fill, false)
+			logger.Infof("  ↻ Limit chase %s: market fallback filled %.6f", req.Symbol, fill.ExecutedQty)
+		}
+	}
+
+	if result.ExecutedQty == 0 {
+		return nil, fmt.Errorf("limit entry not filled after %d reprice(s)", result.Reprices)
+	}
+	return result, nil
+}
+
+// await polls a resting order until it fills or the reprice interval elapses
+func (c *limitChaser) await(symbol string, orderID int64) chaseFill {
+	var fill chaseFill
+	for waited := time.Duration(0); waited < c.policy.RepriceInterval; waited += chasePollInterval {
+		c.sleep(chasePollInterval)
+		f, err := c.venue.OrderFill(symbol, orderID)
+		if err != nil {
+			continue
+		}
+		fill = f
+		switch f.Status {
+		case "FILLED":
+			return f
+		case "EXPIRED", "REJECTED", "CANCELED":
+			// GTX orders that would cross are expired by the matching engine
+			return f
+		}
+	}
+	return fill
+}
+
+// binanceChaseVenue limitChaseVenue backed by the classic futures API
+type binanceChaseVenue struct {
+	t    *FuturesTrader
+	tick float64
+	step float64
+}
+
+func (v *binanceChaseVenue) MarkPrice(symbol string) (float64, error) {
+	res, err := v.t.client.NewPremiumIndexService().Symbol(symbol).Do(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	if len(res) == 0 {
+		return 0, fmt.Errorf("mark price not found")
+	}
+	return strconv.ParseFloat(res[0].MarkPrice, 64)
+}
+
+func (v *binanceChaseVenue) PlaceLimit(symbol string, side futures.SideType, posSide futures.PositionSideType, price, quantity float64) (int64, error) {
+	order, err := v.t.client.NewCreateOrderService().
+		Symbol(symbol).
+		Side(side).
+		PositionSide(posSide).
+		Type(futures.OrderTypeLimit).
+		TimeInForce(futures.TimeInForceTypeGTX).
+		Price(formatIncrement(price, v.tick)).
+		Quantity(formatIncrement(quantity, v.step)).
+		NewClientOrderID(getBrOrderID()).
+		Do(context.Background())
+	if err != nil {
+		if strings.Contains(err.Error(), "-5022") {
+			return 0, errPostOnlyRejected
+		}
+		return 0, err
+	}
+	return order.OrderID, nil
+}
+
+func (v *binanceChaseVenue) PlaceMarket(symbol string, side futures.SideType, posSide futures.PositionSideType, quantity float64) (int64, error) {
+	order, err := v.t.createMarketOrder(symbol, side, posSide, formatIncrement(quantity, v.step))
+	if err != nil {
+		return 0, err
+	}
+	return order.OrderID, nil
+}
+
+func (v *binanceChaseVenue) OrderFill(symbol string, orderID int64) (chaseFill, error) {
+	order, err := v.t.client.NewGetOrderService().Symbol(symbol).OrderID(orderID).Do(context.Background())
+	if err != nil {
+		return chaseFill{}, err
+	}
+	executedQty, _ := strconv.ParseFloat(order.ExecutedQuantity, 64)
+	avgPrice, _ := strconv.ParseFloat(order.AvgPrice, 64)
+	return chaseFill{Status: string(order.Status), ExecutedQty: executedQty, AvgPrice: avgPrice}, nil
+}
+
+func (v *binanceChaseVenue) Cancel(symbol string, orderID int64) error {
+	_, err := v.t.client.NewCancelOrderService().Symbol(symbol).OrderID(orderID).Do(context.Background())
+	return err
+}
+
+// getSymbolFilters gets price tick size and quantity step size for a trading pair
+func (t *FuturesTrader) getSymbolFilters(symbol string) (tick, step float64, err error) {
+	exchangeInfo, err := t.client.NewExchangeInfoService().Do(context.Background())
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get trading rules: %w", err)
+	}
+	for _, s := range exchangeInfo.Symbols {
+		if s.Symbol != symbol {
+			continue
+		}
+		for _, filter := range s.Filters {
+			switch filter["filterType"] {
+			case "PRICE_FILTER":
+				tick, _ = strconv.ParseFloat(fmt.Sprintf("%v", filter["tickSize"]), 64)
+			case "LOT_SIZE":
+				step, _ = strconv.ParseFloat(fmt.Sprintf("%v", filter["stepSize"]), 64)
+			}
+		}
+		return tick, step, nil
+	}
+	return 0, 0, fmt.Errorf("symbol %s not found in exchange info", symbol)
+}
+
+// SetLimitEntryPolicy overrides the limit entry chase policy of this trader
+func (t *FuturesTrader) SetLimitEntryPolicy(policy config.LimitEntryConfig) {
+	t.limitEntry = policy
+}
+
+// SetMakerFirst switches post-only limit entries on or off (maker_first_execution feature flag),
+// the rest of the chase policy is kept
+func (t *FuturesTrader) SetMakerFirst(enabled bool) {
+	t.limitEntry.Enabled = enabled
+}
+
+// createEntryOrder opens a position with the limit chase policy, or a market order when disabled
+func (t *FuturesTrader) createEntryOrder(symbol string, side futures.SideType, posSide futures.PositionSideType, quantity string) (map[string]interface{}, error) {
+	if !t.limitEntry.Enabled || t.pm != nil {
+		order, err := t.createMarketOrder(symbol, side, posSide, quantity)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"orderId": order.OrderID,
+			"symbol":  order.Symbol,
+			"status":  order.Status,
+		}, nil
+	}
+
+	qty, _ := strconv.ParseFloat(quantity, 64)
+	tick, step, err := t.getSymbolFilters(symbol)
+	if err != nil {
+		return nil, err
+	}
+	chaser := &limitChaser{
+		venue:  &binanceChaseVenue{t: t, tick: tick, step: step},
+		policy: t.limitEntry,
+		sleep:  time.Sleep,
+	}
+	res, err := chaser.run(chaseRequest{Symbol: symbol, Side: side, PositionSide: posSide, Quantity: qty, Step: step})
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill data is already known, callers don't need to poll order status
+	fee := logger.EstimateFee("binance", res.AvgPrice*res.MakerQty, true) +
+		logger.EstimateFee("binance", res.AvgPrice*res.TakerQty, false)
+	logger.Infof("  ✓ Limit entry %s filled %.6f @ %.6f (maker %.6f, taker %.6f, %d reprice(s))",
+		symbol, res.ExecutedQty, res.AvgPrice, res.MakerQty, res.TakerQty, res.Reprices)
+	return map[string]interface{}{
+		"orderId":     res.OrderID,
+		"symbol":      symbol,
+		"status":      "FILLED",
+		"avgPrice":    res.AvgPrice,
+		"executedQty": res.ExecutedQty,
+		"commission":  fee,
+	}, nil
+}
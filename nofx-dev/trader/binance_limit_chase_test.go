@@ -0,0 +1,144 @@
+package trader
+
+import (
+	"nofx/config"
+	"testing"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+// scriptedVenue fills limit orders according to a per-attempt script
+type scriptedVenue struct {
+	mark         float64
+	limitFills   []float64 // Quantity filled by the n-th limit order
+	rejectFirst  bool      // First limit order is rejected as post-only
+	prices       []float64
+	marketQty    float64
+	cancels      int
+	orders       map[int64]chaseFill
+	nextOrderID  int64
+	limitsPlaced int
+}
+
+func (v *scriptedVenue) MarkPrice(symbol string) (float64, error) { return v.mark, nil }
+
+func (v *scriptedVenue) PlaceLimit(symbol string, side futures.SideType, posSide futures.PositionSideType, price, quantity float64) (int64, error) {
+	v.limitsPlaced++
+	if v.rejectFirst && v.limitsPlaced == 1 {
+		return 0, errPostOnlyRejected
+	}
+	v.prices = append(v.prices, price)
+	filled := 0.0
+	if i := len(v.prices) - 1; i < len(v.limitFills) {
+		filled = v.limitFills[i]
+	}
+	status := "NEW"
+	if filled >= quantity {
+		filled, status = quantity, "FILLED"
+	} else if filled > 0 {
+		status = "PARTIALLY_FILLED"
+	}
+	return v.store(chaseFill{Status: status, ExecutedQty: filled, AvgPrice: price}), nil
+}
+
+func (v *scriptedVenue) PlaceMarket(symbol string, side futures.SideType, posSide futures.PositionSideType, quantity float64) (int64, error) {
+	v.marketQty = quantity
+	return v.store(chaseFill{Status: "FILLED", ExecutedQty: quantity, AvgPrice: v.mark * 1.001}), nil
+}
+
+func (v *scriptedVenue) OrderFill(symbol string, orderID int64) (chaseFill, error) {
+	return v.orders[orderID], nil
+}
+
+func (v *scriptedVenue) Cancel(symbol string, orderID int64) error {
+	v.cancels++
+	fill := v.orders[orderID]
+	fill.Status = "CANCELED"
+	v.orders[orderID] = fill
+	return nil
+}
+
+func (v *scriptedVenue) store(fill chaseFill) int64 {
+	if v.orders == nil {
+		v.orders = make(map[int64]chaseFill)
+	}
+	v.nextOrderID++
+	v.orders[v.nextOrderID] = fill
+	return v.nextOrderID
+}
+
+func testChasePolicy(fallback bool) config.LimitEntryConfig {
+	return config.LimitEntryConfig{
+		Enabled:          true,
+		MaxReprices:      2,
+		RepriceInterval:  3 * time.Second,
+		OffsetBps:        10,
+		FallbackToMarket: fallback,
+	}
+}
+
+// TestLimitChaseReprices tests repricing toward mark and aggregation of partial fills
+func TestLimitChaseReprices(t *testing.T) {
+	venue := &scriptedVenue{mark: 100, limitFills: []float64{0.4, 0, 1}}
+	chaser := &limitChaser{venue: venue, policy: testChasePolicy(false), sleep: func(time.Duration) {}}
+
+	res, err := chaser.run(chaseRequest{Symbol: "BTCUSDT", Side: futures.SideTypeBuy, PositionSide: futures.PositionSideTypeLong, Quantity: 1, Step: 0.001})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Buy prices: mark - 10bps, mark - 5bps, mark
+	want := []float64{99.9, 99.95, 100}
+	if len(venue.prices) != len(want) {
+		t.Fatalf("expected %d limit orders, got %v", len(want), venue.prices)
+	}
+	for i := range want {
+		if diff := venue.prices[i] - want[i]; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("attempt %d: expected price %.4f, got %.4f", i, want[i], venue.prices[i])
+		}
+	}
+	if venue.cancels != 2 {
+		t.Errorf("expected 2 cancels, got %d", venue.cancels)
+	}
+	if res.ExecutedQty != 1 || res.MakerQty != 1 || res.TakerQty != 0 {
+		t.Errorf("unexpected fill totals: %+v", res)
+	}
+	wantAvg := (0.4*99.9 + 0.6*100) / 1
+	if diff := res.AvgPrice - wantAvg; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("expected avg price %.4f, got %.4f", wantAvg, res.AvgPrice)
+	}
+}
+
+// TestLimitChaseMarketFallback tests the remainder goes out as a market order after the last reprice
+func TestLimitChaseMarketFallback(t *testing.T) {
+	venue := &scriptedVenue{mark: 50, limitFills: []float64{0.25}, rejectFirst: true}
+	chaser := &limitChaser{venue: venue, policy: testChasePolicy(true), sleep: func(time.Duration) {}}
+
+	res, err := chaser.run(chaseRequest{Symbol: "ETHUSDT", Side: futures.SideTypeSell, PositionSide: futures.PositionSideTypeShort, Quantity: 1, Step: 0.01})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if venue.prices[0] <= 50 {
+		t.Errorf("sell limit should rest above mark, got %.4f", venue.prices[0])
+	}
+	if venue.marketQty != 0.75 {
+		t.Errorf("expected market fallback for 0.75, got %v", venue.marketQty)
+	}
+	if res.ExecutedQty != 1 || res.MakerQty != 0.25 || res.TakerQty != 0.75 {
+		t.Errorf("unexpected fill totals: %+v", res)
+	}
+}
+
+// TestLimitChaseNoFill tests an error is returned when nothing fills and market fallback is disabled
+func TestLimitChaseNoFill(t *testing.T) {
+	venue := &scriptedVenue{mark: 10}
+	chaser := &limitChaser{venue: venue, policy: testChasePolicy(false), sleep: func(time.Duration) {}}
+
+	if _, err := chaser.run(chaseRequest{Symbol: "SOLUSDT", Side: futures.SideTypeBuy, PositionSide: futures.PositionSideTypeLong, Quantity: 2, Step: 0.1}); err == nil {
+		t.Fatal("expected error when no order fills")
+	}
+	if venue.marketQty != 0 {
+		t.Errorf("market fallback must not run when disabled")
+	}
+}
@@ -0,0 +1,221 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nofx/config"
+	"nofx/logger"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+const (
+	defaultMaxAlgoOrdersPerSymbol = 10               // Binance MAX_NUM_ALGO_ORDERS default (used if the filter is missing)
+	algoOrderHeadroom             = 2                // Start consolidating when this close to a cap
+	algoOrderCacheTTL             = 30 * time.Second // Open conditional orders are refetched after this
+)
+
+// ErrOrderCapReached new conditional order would exceed the exchange's open order caps
+var ErrOrderCapReached = errors.New("conditional order cap reached")
+
+// conditionalOrder open stop/take-profit order
+type conditionalOrder struct {
+	OrderID      int64
+	Symbol       string
+	Type         string // STOP_MARKET / TAKE_PROFIT_MARKET / STOP / TAKE_PROFIT / TRAILING_STOP_MARKET
+	PositionSide string
+}
+
+// conditionalKind groups order types that protect a position the same way
+func conditionalKind(orderType string) string {
+	switch orderType {
+	case string(futures.OrderTypeStopMarket), string(futures.OrderTypeStop):
+		return "stop_loss"
+	case string(futures.OrderTypeTakeProfitMarket), string(futures.OrderTypeTakeProfit):
+		return "take_profit"
+	}
+	return orderType
+}
+
+// isConditionalOrder whether the order type counts toward Binance's algo order caps
+func isConditionalOrder(orderType futures.OrderType) bool {
+	switch orderType {
+	case futures.OrderTypeStopMarket, futures.OrderTypeStop,
+		futures.OrderTypeTakeProfitMarket, futures.OrderTypeTakeProfit,
+		futures.OrderTypeTrailingStopMarket:
+		return true
+	}
+	return false
+}
+
+// planConditionalOrder decides which orders a new conditional order supersedes
+// The new order is placed before anything is cancelled, so it must fit under the caps as they are, otherwise it
+// is throttled with ErrOrderCapReached. Below the caps nothing is touched; when approaching a cap, older orders
+// of the same kind for the same position side are superseded by the new one and returned for cancellation once
+// it is accepted. totalCap 0 = no account-wide cap
+func planConditionalOrder(open []conditionalOrder, symbol, orderType, positionSide string, perSymbolCap, totalCap int) ([]int64, error) {
+	symbolCount := 0
+	for _, o := range open {
+		if o.Symbol == symbol {
+			symbolCount++
+		}
+	}
+	total := len(open)
+	if symbolCount+1 > perSymbolCap {
+		return nil, fmt.Errorf("%w: %s has %d/%d open conditional orders", ErrOrderCapReached, symbol, symbolCount, perSymbolCap)
+	}
+	if totalCap > 0 && total+1 > totalCap {
+		return nil, fmt.Errorf("%w: %d/%d open conditional orders across all symbols", ErrOrderCapReached, total, totalCap)
+	}
+	if symbolCount+1 <= perSymbolCap-algoOrderHeadroom && (totalCap <= 0 || total+1 <= totalCap-algoOrderHeadroom) {
+		return nil, nil
+	}
+
+	var superseded []int64
+	kind := conditionalKind(orderType)
+	for _, o := range open {
+		if o.Symbol == symbol && o.PositionSide == positionSide && conditionalKind(o.Type) == kind {
+			superseded = append(superseded, o.OrderID)
+		}
+	}
+	return superseded, nil
+}
+
+// algoOrderTracker caches open conditional orders and per-symbol caps
+type algoOrderTracker struct {
+	mu        sync.Mutex
+	orders    []conditionalOrder
+	fetchedAt time.Time
+	stale     bool           // Refetch on next use, the snapshot is kept as a fallback
+	caps      map[string]int // symbol -> MAX_NUM_ALGO_ORDERS
+}
+
+func newAlgoOrderTracker() *algoOrderTracker {
+	return &algoOrderTracker{caps: make(map[string]int)}
+}
+
+// invalidate forces a refetch on next use (after cancellations)
+func (a *algoOrderTracker) invalidate() {
+	a.mu.Lock()
+	a.stale = true
+	a.mu.Unlock()
+}
+
+func (a *algoOrderTracker) add(o conditionalOrder) {
+	a.mu.Lock()
+	a.orders = append(a.orders, o)
+	a.mu.Unlock()
+}
+
+func (a *algoOrderTracker) remove(orderID int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, o := range a.orders {
+		if o.OrderID == orderID {
+			a.orders = append(a.orders[:i], a.orders[i+1:]...)
+			return
+		}
+	}
+}
+
+// openConditionalOrders returns cached open conditional orders across all symbols, refetching when stale
+// If the refetch fails the last snapshot is used; without one the error is returned
+func (t *FuturesTrader) openConditionalOrders() ([]conditionalOrder, error) {
+	a := t.algoOrders
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.stale && !a.fetchedAt.IsZero() && time.Since(a.fetchedAt) < algoOrderCacheTTL {
+		return append([]conditionalOrder(nil), a.orders...), nil
+	}
+
+	orders, err := t.client.NewListOpenOrdersService().Do(context.Background())
+	if err != nil {
+		if a.fetchedAt.IsZero() {
+			return nil, fmt.Errorf("failed to get open orders: %w", err)
+		}
+		logger.Infof("  ⚠ Failed to refresh open conditional orders, using the count from %s: %v", a.fetchedAt.Format("15:04:05"), err)
+		return append([]conditionalOrder(nil), a.orders...), nil
+	}
+	a.orders = a.orders[:0]
+	for _, o := range orders {
+		if isConditionalOrder(o.Type) {
+			a.orders = append(a.orders, conditionalOrder{
+				OrderID:      o.OrderID,
+				Symbol:       o.Symbol,
+				Type:         string(o.Type),
+				PositionSide: string(o.PositionSide),
+			})
+		}
+	}
+	a.fetchedAt = time.Now()
+	a.stale = false
+	return append([]conditionalOrder(nil), a.orders...), nil
+}
+
+// maxAlgoOrders returns the symbol's MAX_NUM_ALGO_ORDERS filter (cached)
+func (t *FuturesTrader) maxAlgoOrders(symbol string) int {
+	a := t.algoOrders
+	a.mu.Lock()
+	limit, ok := a.caps[symbol]
+	a.mu.Unlock()
+	if ok {
+		return limit
+	}
+
+	limit = defaultMaxAlgoOrdersPerSymbol
+	if exchangeInfo, err := t.client.NewExchangeInfoService().Do(context.Background()); err == nil {
+		for _, s := range exchangeInfo.Symbols {
+			if s.Symbol != symbol {
+				continue
+			}
+			for _, filter := range s.Filters {
+				if filter["filterType"] == "MAX_NUM_ALGO_ORDERS" {
+					if n, err := strconv.Atoi(fmt.Sprintf("%v", filter["limit"])); err == nil && n > 0 {
+						limit = n
+					}
+				}
+			}
+		}
+	}
+
+	a.mu.Lock()
+	a.caps[symbol] = limit
+	a.mu.Unlock()
+	return limit
+}
+
+// reserveAlgoOrder checks a new conditional order fits under the caps and returns the orders it supersedes, to be
+// cancelled with cancelSuperseded once the new order is accepted. The order is throttled when the open orders
+// can't be counted
+func (t *FuturesTrader) reserveAlgoOrder(symbol string, orderType futures.OrderType, posSide futures.PositionSideType) ([]int64, error) {
+	open, err := t.openConditionalOrders()
+	if err != nil {
+		logger.Infof("  ⚠ Throttled %s %s order: %v", symbol, orderType, err)
+		return nil, ClassifyError("binance", "createStopOrder", fmt.Errorf("cannot count open conditional orders: %w", err))
+	}
+
+	perSymbolCap := t.maxAlgoOrders(symbol)
+	superseded, err := planConditionalOrder(open, symbol, string(orderType), string(posSide), perSymbolCap, config.Get().MaxConditionalOrders)
+	if err != nil {
+		logger.Infof("  ⚠ Throttled %s %s order: %v", symbol, orderType, err)
+		return nil, &ExchangeError{Exchange: "binance", Op: "createStopOrder", Kind: ErrKindRejected, Err: err}
+	}
+	return superseded, nil
+}
+
+// cancelSuperseded cancels orders replaced by a newly accepted conditional order (near the caps)
+func (t *FuturesTrader) cancelSuperseded(symbol string, posSide futures.PositionSideType, superseded []int64) {
+	for _, orderID := range superseded {
+		if _, err := t.client.NewCancelOrderService().Symbol(symbol).OrderID(orderID).Do(context.Background()); err != nil {
+			logger.Infof("  ⚠ Failed to cancel superseded order %d: %v", orderID, err)
+			t.algoOrders.invalidate()
+			continue
+		}
+		t.algoOrders.remove(orderID)
+		logger.Infof("  ✓ Consolidated %s %s: canceled superseded order %d (near conditional order cap)", symbol, posSide, orderID)
+	}
+}
@@ -0,0 +1,54 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func openStops(symbol string, n int, orderType, side string) []conditionalOrder {
+	orders := make([]conditionalOrder, 0, n)
+	for i := 0; i < n; i++ {
+		orders = append(orders, conditionalOrder{OrderID: int64(len(symbol)*1000 + i), Symbol: symbol, Type: orderType, PositionSide: side})
+	}
+	return orders
+}
+
+// TestPlanConditionalOrder tests consolidation and throttling near the algo order caps
+func TestPlanConditionalOrder(t *testing.T) {
+	// Well below the caps: nothing superseded
+	open := openStops("BTCUSDT", 3, "STOP_MARKET", "LONG")
+	if superseded, err := planConditionalOrder(open, "BTCUSDT", "STOP_MARKET", "LONG", 10, 100); err != nil || len(superseded) != 0 {
+		t.Errorf("expected no action below caps, got superseded=%v err=%v", superseded, err)
+	}
+
+	// Near the per-symbol cap: older stop-losses of the same side are superseded, take-profits kept
+	open = append(openStops("BTCUSDT", 5, "STOP_MARKET", "LONG"), openStops("BTCUSDT", 3, "TAKE_PROFIT_MARKET", "LONG")...)
+	superseded, err := planConditionalOrder(open, "BTCUSDT", "STOP_MARKET", "LONG", 10, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(superseded) != 5 {
+		t.Errorf("expected 5 superseded stop-losses, got %d", len(superseded))
+	}
+
+	// At the cap: the new order is placed before anything is cancelled, so it is throttled even with superseded orders
+	open = openStops("ETHUSDT", 10, "STOP_MARKET", "LONG")
+	if _, err := planConditionalOrder(open, "ETHUSDT", "STOP_MARKET", "LONG", 10, 100); !errors.Is(err, ErrOrderCapReached) {
+		t.Errorf("expected per-symbol cap error, got %v", err)
+	}
+
+	// Account-wide cap
+	open = nil
+	for i := 0; i < 20; i++ {
+		open = append(open, openStops(fmt.Sprintf("SYM%02dUSDT", i), 1, "STOP_MARKET", "LONG")...)
+	}
+	if _, err := planConditionalOrder(open, "NEWUSDT", "TAKE_PROFIT_MARKET", "LONG", 10, 20); !errors.Is(err, ErrOrderCapReached) {
+		t.Errorf("expected total cap error, got %v", err)
+	}
+
+	// No account-wide cap configured: only the per-symbol cap applies
+	if superseded, err := planConditionalOrder(open, "NEWUSDT", "TAKE_PROFIT_MARKET", "LONG", 10, 0); err != nil || len(superseded) != 0 {
+		t.Errorf("expected no action without a total cap, got superseded=%v err=%v", superseded, err)
+	}
+}
@@ -0,0 +1,342 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"nofx/logger"
+	"nofx/ratelimit"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/adshao/go-binance/v2/portfolio"
+)
+
+// Binance Portfolio Margin (PM) accounts cannot trade through the classic /fapi endpoints.
+// UM (USDT-margined) futures are traded through /papi instead, with stop orders placed
+// as "conditional orders" (strategyId instead of orderId). Market data (prices, exchange info)
+// is still served by /fapi, so only account/order calls are routed here.
+
+// portfolioMarginAccounts whether an API key belongs to a Portfolio Margin account (apiKey -> bool), detected once
+var portfolioMarginAccounts sync.Map
+
+// newPortfolioClient creates the PAPI client of an API key
+var newPortfolioClient = func(apiKey, secretKey string) *portfolio.Client {
+	pm := portfolio.NewClient(apiKey, secretKey)
+	pm.HTTPClient = ratelimit.WrapClient("binance_pm", pm.HTTPClient)
+	return pm
+}
+
+// detectPortfolioMargin checks whether the API key belongs to a Portfolio Margin account
+// Returns a ready-to-use PAPI client if so, nil otherwise. The answer is cached per key; a failed check
+// (network, rate limit) isn't, the next trader created with the key checks again
+func detectPortfolioMargin(apiKey, secretKey string, timeOffset int64) *portfolio.Client {
+	pm := newPortfolioClient(apiKey, secretKey)
+	pm.TimeOffset = timeOffset
+	if isPM, ok := portfolioMarginAccounts.Load(apiKey); ok {
+		if isPM.(bool) {
+			return pm
+		}
+		return nil
+	}
+
+	account, err := pm.NewGetAccountService().Do(context.Background())
+	if err != nil {
+		if IsRetryable(ClassifyError("binance", "detectPortfolioMargin", err)) {
+			logger.Warnf("⚠️ Binance Portfolio Margin detection failed, using classic futures endpoints: %v", err)
+			return nil
+		}
+		// Classic futures accounts are rejected by /papi, this is the common case
+		logger.Infof("🏦 Binance classic futures account (PAPI: %v)", err)
+		portfolioMarginAccounts.Store(apiKey, false)
+		return nil
+	}
+	portfolioMarginAccounts.Store(apiKey, true)
+	logger.Infof("🏦 Binance Portfolio Margin account detected (status: %s, uniMMR: %s), using PAPI endpoints",
+		account.AccountStatus, account.UniMMR)
+	return pm
+}
+
+// IsPortfolioMargin returns whether this trader routes orders through Portfolio Margin endpoints
+func (t *FuturesTrader) IsPortfolioMargin() bool {
+	return t.pm != nil
+}
+
+// pmSetDualSidePosition sets hedge mode for UM positions on a PM account
+func (t *FuturesTrader) pmSetDualSidePosition() error {
+	_, err := t.pm.NewChangeUMPositionModeService().
+		DualSidePosition(true).
+		Do(context.Background())
+	if err != nil {
+		if strings.Contains(err.Error(), "No need to change position side") {
+			logger.Infof("  ✓ Account is already in dual-side position mode (Hedge Mode)")
+			return nil
+		}
+		return err
+	}
+	logger.Infof("  ✓ Account switched to dual-side position mode (Hedge Mode)")
+	return nil
+}
+
+// pmGetBalance gets unified account balance
+// PM reports equity in USD across all collateral; UM unrealized PnL is summed from per-asset balances
+func (t *FuturesTrader) pmGetBalance() (map[string]interface{}, error) {
+	account, err := t.pm.NewGetAccountService().Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio margin account info: %w", err)
+	}
+	balances, err := t.pm.NewGetBalanceService().Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio margin balances: %w", err)
+	}
+
+	unrealized := 0.0
+	for _, b := range balances {
+		pnl, _ := strconv.ParseFloat(b.UMUnrealizedPNL, 64)
+		unrealized += pnl
+	}
+	equity, _ := strconv.ParseFloat(account.AccountEquity, 64)
+	available, _ := strconv.ParseFloat(account.TotalAvailableBalance, 64)
+
+	result := make(map[string]interface{})
+	result["totalWalletBalance"] = equity - unrealized
+	result["availableBalance"] = available
+	result["totalUnrealizedProfit"] = unrealized
+	uniMMR, _ := strconv.ParseFloat(account.UniMMR, 64)
+	result["uniMMR"] = uniMMR
+	if uniMMR > 0 {
+		result["marginRatio"] = 100 / uniMMR // uniMMR is equity / maintenance margin
+	}
+
+	logger.Infof("✓ Binance PM API returned: equity=%s, available=%s, unrealized PnL=%.4f, uniMMR=%s",
+		account.AccountEquity, account.TotalAvailableBalance, unrealized, account.UniMMR)
+	return result, nil
+}
+
+// pmGetPositions gets UM positions from a PM account
+func (t *FuturesTrader) pmGetPositions() ([]Position, error) {
+	positions, err := t.pm.NewGetUMPositionRiskService().Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	var result []Position
+	for _, pos := range positions {
+		posAmt, _ := strconv.ParseFloat(pos.PositionAmt, 64)
+		if posAmt == 0 {
+			continue
+		}
+
+		p := Position{Symbol: pos.Symbol, Quantity: math.Abs(posAmt), UpdatedTime: pos.UpdateTime}
+		p.EntryPrice, _ = strconv.ParseFloat(pos.EntryPrice, 64)
+		p.MarkPrice, _ = strconv.ParseFloat(pos.MarkPrice, 64)
+		p.UnrealizedPnL, _ = strconv.ParseFloat(pos.UnrealizedProfit, 64)
+		p.Leverage, _ = strconv.ParseFloat(pos.Leverage, 64)
+		p.LiquidationPrice, _ = strconv.ParseFloat(pos.LiquidationPrice, 64)
+
+		if posAmt > 0 {
+			p.Side = "long"
+		} else {
+			p.Side = "short"
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// pmChangeLeverage changes UM initial leverage on a PM account
+func (t *FuturesTrader) pmChangeLeverage(symbol string, leverage int) error {
+	_, err := t.pm.NewChangeUMInitialLeverageService().
+		Symbol(symbol).
+		Leverage(leverage).
+		Do(context.Background())
+	return err
+}
+
+// pmCreateMarketOrder places a UM market order, response is converted to the classic futures type
+func (t *FuturesTrader) pmCreateMarketOrder(symbol string, side futures.SideType, posSide futures.PositionSideType, quantity string) (*futures.CreateOrderResponse, error) {
+	order, err := t.pm.NewUMOrderService().
+		Symbol(symbol).
+		Side(portfolio.SideType(side)).
+		PositionSide(portfolio.PositionSideType(posSide)).
+		Type(portfolio.OrderTypeMarket).
+		Quantity(quantity).
+		NewClientOrderID(t.marketClientOrderID(symbol)).
+		Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &futures.CreateOrderResponse{
+		OrderID: order.OrderID,
+		Symbol:  order.Symbol,
+		Status:  futures.OrderStatusType(order.Status),
+	}, nil
+}
+
+// pmCreateStopOrder places a UM conditional order (STOP_MARKET / TAKE_PROFIT_MARKET)
+// Conditional orders on PAPI do not support closePosition, so the position quantity is used
+func (t *FuturesTrader) pmCreateStopOrder(symbol string, side futures.SideType, posSide futures.PositionSideType, orderType futures.OrderType, stopPrice float64, quantity string) error {
+	_, err := t.pm.NewUMConditionalOrderService().
+		Symbol(symbol).
+		Side(portfolio.SideType(side)).
+		PositionSide(portfolio.PositionSideType(posSide)).
+		StrategyType(string(orderType)).
+		StopPrice(fmt.Sprintf("%.8f", stopPrice)).
+		Quantity(quantity).
+		WorkingType(string(futures.WorkingTypeContractPrice)).
+		Do(context.Background())
+	return err
+}
+
+// pmCancelAllOrders cancels both regular and conditional UM orders for a symbol
+func (t *FuturesTrader) pmCancelAllOrders(symbol string) error {
+	if _, err := t.pm.NewUMCancelAllOrdersService().Symbol(symbol).Do(context.Background()); err != nil {
+		return fmt.Errorf("failed to cancel pending orders: %w", err)
+	}
+	if _, err := t.pm.NewUMCancelAllConditionalOrdersService().Symbol(symbol).Do(context.Background()); err != nil {
+		return fmt.Errorf("failed to cancel conditional orders: %w", err)
+	}
+	logger.Infof("  ✓ Canceled all pending orders for %s", symbol)
+	return nil
+}
+
+// pmCancelConditionalOrders cancels open conditional orders whose strategy type matches
+func (t *FuturesTrader) pmCancelConditionalOrders(symbol, label string, match func(strategyType string) bool) error {
+	orders, err := t.pm.NewUMOpenConditionalOrdersService().
+		Symbol(symbol).
+		Do(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get open conditional orders: %w", err)
+	}
+
+	canceledCount := 0
+	var cancelErrors []error
+	for _, order := range orders {
+		if !match(order.StrategyType) {
+			continue
+		}
+		_, err := t.pm.NewUMCancelConditionalOrderService().
+			Symbol(symbol).
+			StrategyID(order.StrategyID).
+			Do(context.Background())
+		if err != nil {
+			cancelErrors = append(cancelErrors, fmt.Errorf("strategy ID %d: %v", order.StrategyID, err))
+			logger.Infof("  ⚠ Failed to cancel %s order %d: %v", label, order.StrategyID, err)
+			continue
+		}
+		canceledCount++
+		logger.Infof("  ✓ Canceled %s order (Strategy ID: %d, Type: %s, Side: %s)", label, order.StrategyID, order.StrategyType, order.PositionSide)
+	}
+
+	if canceledCount == 0 && len(cancelErrors) == 0 {
+		logger.Infof("  ℹ %s has no %s orders to cancel", symbol, label)
+	}
+	if len(cancelErrors) > 0 && canceledCount == 0 {
+		return fmt.Errorf("failed to cancel %s orders: %v", label, cancelErrors)
+	}
+	return nil
+}
+
+// pmGetOrderStatus queries a UM order on a PM account
+func (t *FuturesTrader) pmGetOrderStatus(symbol string, orderID int64) (map[string]interface{}, error) {
+	order, err := t.pm.NewUMQueryOrderService().
+		Symbol(symbol).
+		OrderID(orderID).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order status: %w", err)
+	}
+	return pmOrderStatus(order), nil
+}
+
+// pmFindOrderByClientID queries a UM order by client order ID (API error returned unwrapped)
+func (t *FuturesTrader) pmFindOrderByClientID(symbol, clientOrderID string) (map[string]interface{}, error) {
+	order, err := t.pm.NewUMQueryOrderService().
+		Symbol(symbol).
+		OrigClientOrderID(clientOrderID).
+		Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return pmOrderStatus(order), nil
+}
+
+// pmOrderStatus converts a UM order query response to the GetOrderStatus result
+func pmOrderStatus(order *portfolio.UMQueryOrderResponse) map[string]interface{} {
+	avgPrice, _ := strconv.ParseFloat(order.AvgPrice, 64)
+	executedQty, _ := strconv.ParseFloat(order.ExecutedQty, 64)
+	return map[string]interface{}{
+		"orderId":     order.OrderID,
+		"symbol":      order.Symbol,
+		"status":      order.Status,
+		"avgPrice":    avgPrice,
+		"executedQty": executedQty,
+		"side":        order.Side,
+		"type":        order.Type,
+		"time":        order.Time,
+		"updateTime":  order.UpdateTime,
+		"commission":  0.0,
+	}
+}
+
+// pmGetTrades gets REALIZED_PNL income records from a PM account
+func (t *FuturesTrader) pmGetTrades(startTime time.Time, limit int) ([]TradeRecord, error) {
+	incomes, err := t.pm.NewGetUMIncomeHistoryService().
+		IncomeType(portfolio.IncomeTypeRealizedPNL).
+		StartTime(startTime.UnixMilli()).
+		Limit(limit).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get income history: %w", err)
+	}
+
+	var trades []TradeRecord
+	for _, income := range incomes {
+		pnl, _ := strconv.ParseFloat(income.Income, 64)
+		if pnl == 0 {
+			continue
+		}
+		trades = append(trades, TradeRecord{
+			TradeID:     strconv.FormatInt(income.TranID, 10),
+			Symbol:      income.Symbol,
+			RealizedPnL: pnl,
+			Time:        time.UnixMilli(income.Time),
+		})
+	}
+	return trades, nil
+}
+
+// pmGetTradesForSymbol gets UM account trades for a symbol from a PM account
+func (t *FuturesTrader) pmGetTradesForSymbol(symbol string, startTime time.Time, limit int) ([]TradeRecord, error) {
+	accountTrades, err := t.pm.NewUMAccountTradesService().
+		Symbol(symbol).
+		StartTime(startTime.UnixMilli()).
+		Limit(limit).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trade history for %s: %w", symbol, err)
+	}
+
+	var trades []TradeRecord
+	for _, at := range accountTrades {
+		price, _ := strconv.ParseFloat(at.Price, 64)
+		qty, _ := strconv.ParseFloat(at.Qty, 64)
+		fee, _ := strconv.ParseFloat(at.Commission, 64)
+		pnl, _ := strconv.ParseFloat(at.RealizedPnl, 64)
+		trades = append(trades, TradeRecord{
+			TradeID:      strconv.FormatInt(at.ID, 10),
+			Symbol:       at.Symbol,
+			Side:         at.Side,
+			PositionSide: at.PositionSide,
+			Price:        price,
+			Quantity:     qty,
+			RealizedPnL:  pnl,
+			Fee:          fee,
+			Time:         time.UnixMilli(at.Time),
+		})
+	}
+	return trades, nil
+}
@@ -0,0 +1,64 @@
+package trader
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/adshao/go-binance/v2/portfolio"
+)
+
+// TestDetectPortfolioMargin tests PAPI detection per account type and that only definitive answers are cached
+func TestDetectPortfolioMargin(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.Header.Get("X-MBX-APIKEY") {
+		case "pm-key":
+			w.Write([]byte(`{"accountStatus":"NORMAL","uniMMR":"5.0"}`))
+		case "classic-key":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":-2015,"msg":"Invalid API-key, IP, or permissions for action."}`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`Service Unavailable`))
+		}
+	}))
+	defer server.Close()
+
+	original := newPortfolioClient
+	defer func() { newPortfolioClient = original }()
+	newPortfolioClient = func(apiKey, secretKey string) *portfolio.Client {
+		pm := portfolio.NewClient(apiKey, secretKey)
+		pm.BaseURL = server.URL
+		return pm
+	}
+
+	tests := []struct {
+		name      string
+		apiKey    string
+		wantPM    bool
+		wantCalls int32 // requests of two detections
+	}{
+		{"portfolio margin account", "pm-key", true, 1},
+		{"classic account", "classic-key", false, 1},
+		{"detection failure not cached", "flaky-key", false, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			portfolioMarginAccounts.Delete(tt.apiKey)
+			defer portfolioMarginAccounts.Delete(tt.apiKey)
+			requests.Store(0)
+
+			for i := 0; i < 2; i++ {
+				if pm := detectPortfolioMargin(tt.apiKey, "secret", 0); (pm != nil) != tt.wantPM {
+					t.Fatalf("detection %d: got portfolio margin %v, want %v", i+1, pm != nil, tt.wantPM)
+				}
+			}
+			if got := requests.Load(); got != tt.wantCalls {
+				t.Errorf("expected %d PAPI requests, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
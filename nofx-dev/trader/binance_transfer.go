@@ -0,0 +1,115 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"nofx/logger"
+	"strconv"
+	"strings"
+
+	"github.com/adshao/go-binance/v2"
+)
+
+// binanceWalletTypes maps wallet names to Binance sub-account universal transfer account types
+var binanceWalletTypes = map[string]string{
+	WalletSpot:    "SPOT",
+	WalletFutures: "USDT_FUTURE",
+}
+
+// spotClient creates a Binance spot client (SAPI wallet endpoints) with the same credentials
+// SAPI weight is tracked separately from futures, so it does not share the futures rate-limit budget
+func (t *FuturesTrader) spotClient() *binance.Client {
+	client := binance.NewClient(t.client.APIKey, t.client.SecretKey)
+	if t.spotBaseURL != "" {
+		client.BaseURL = t.spotBaseURL
+	}
+	return client
+}
+
+// TransferFunds moves funds between spot/futures wallets or between sub-accounts
+// Sub-account transfers require master account API keys with universal transfer permission
+func (t *FuturesTrader) TransferFunds(req TransferRequest) (string, error) {
+	if err := validateTransferRequest(req); err != nil {
+		return "", err
+	}
+	asset := strings.ToUpper(req.Asset)
+	amount := strconv.FormatFloat(req.Amount, 'f', -1, 64)
+
+	var tranID string
+	if req.IsSubAccount() {
+		fromType, ok := binanceWalletTypes[req.FromWallet]
+		if !ok {
+			return "", fmt.Errorf("unsupported source wallet: %s", req.FromWallet)
+		}
+		toType, ok := binanceWalletTypes[req.ToWallet]
+		if !ok {
+			return "", fmt.Errorf("unsupported destination wallet: %s", req.ToWallet)
+		}
+
+		svc := t.spotClient().NewSubAccountUniversalTransferService().
+			FromAccountType(fromType).
+			ToAccountType(toType).
+			Asset(asset).
+			Amount(amount)
+		if req.FromEmail != "" {
+			svc = svc.FromEmail(req.FromEmail)
+		}
+		if req.ToEmail != "" {
+			svc = svc.ToEmail(req.ToEmail)
+		}
+		if req.ClientTranID != "" {
+			svc = svc.ClientTranId(req.ClientTranID)
+		}
+		res, err := svc.Do(context.Background())
+		if err != nil {
+			return "", ClassifyError("binance", "transfer", err)
+		}
+		tranID = strconv.FormatInt(res.TranId, 10)
+	} else {
+		var transferType binance.UserUniversalTransferType
+		switch {
+		case req.FromWallet == WalletSpot && req.ToWallet == WalletFutures:
+			transferType = binance.UserUniversalTransferTypeMainToUmFutures
+		case req.FromWallet == WalletFutures && req.ToWallet == WalletSpot:
+			transferType = binance.UserUniversalTransferTypeUmFuturesToMain
+		default:
+			return "", fmt.Errorf("unsupported wallet transfer: %s -> %s", req.FromWallet, req.ToWallet)
+		}
+
+		res, err := t.spotClient().NewUserUniversalTransferService().
+			Type(transferType).
+			Asset(asset).
+			Amount(amount).
+			Do(context.Background())
+		if err != nil {
+			return "", ClassifyError("binance", "transfer", err)
+		}
+		tranID = strconv.FormatInt(res.ID, 10)
+	}
+
+	// Balance changed, invalidate cache
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = nil
+	t.balanceCacheMutex.Unlock()
+
+	logger.Infof("💸 Transferred %s %s: %s(%s) -> %s(%s), tranId=%s",
+		amount, asset, req.FromWallet, req.FromEmail, req.ToWallet, req.ToEmail, tranID)
+	return tranID, nil
+}
+
+// validateTransferRequest checks common transfer parameters
+func validateTransferRequest(req TransferRequest) error {
+	if req.Asset == "" {
+		return fmt.Errorf("asset is required")
+	}
+	if req.Amount <= 0 {
+		return fmt.Errorf("amount must be positive, got %v", req.Amount)
+	}
+	if !req.IsSubAccount() && req.FromWallet == req.ToWallet {
+		return fmt.Errorf("source and destination wallet are the same: %s", req.FromWallet)
+	}
+	if req.IsSubAccount() && req.FromEmail == req.ToEmail && req.FromWallet == req.ToWallet {
+		return fmt.Errorf("source and destination are the same")
+	}
+	return nil
+}
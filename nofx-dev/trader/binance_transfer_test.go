@@ -0,0 +1,108 @@
+package trader
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+// TestValidateTransferRequest tests transfer parameter validation
+func TestValidateTransferRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     TransferRequest
+		wantErr bool
+	}{
+		{"spot to futures", TransferRequest{Asset: "USDT", Amount: 10, FromWallet: WalletSpot, ToWallet: WalletFutures}, false},
+		{"missing asset", TransferRequest{Amount: 10, FromWallet: WalletSpot, ToWallet: WalletFutures}, true},
+		{"zero amount", TransferRequest{Asset: "USDT", FromWallet: WalletSpot, ToWallet: WalletFutures}, true},
+		{"negative amount", TransferRequest{Asset: "USDT", Amount: -1, FromWallet: WalletSpot, ToWallet: WalletFutures}, true},
+		{"same wallet", TransferRequest{Asset: "USDT", Amount: 10, FromWallet: WalletSpot, ToWallet: WalletSpot}, true},
+		{"sub-account same wallet", TransferRequest{Asset: "USDT", Amount: 10, FromWallet: WalletSpot, ToWallet: WalletSpot, ToEmail: "sub@x.com"}, false},
+		{"sub-account to itself", TransferRequest{Asset: "USDT", Amount: 10, FromWallet: WalletSpot, ToWallet: WalletSpot, FromEmail: "sub@x.com", ToEmail: "sub@x.com"}, true},
+	}
+	for _, tt := range tests {
+		if err := validateTransferRequest(tt.req); (err != nil) != tt.wantErr {
+			t.Errorf("%s: got error %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+// TestTransferFundsRequests tests the SAPI request built for wallet and sub-account transfers
+func TestTransferFundsRequests(t *testing.T) {
+	var path string
+	var params url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		path, params = r.URL.Path, r.Form
+		if r.Form.Get("asset") == "FAIL" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":-5013,"msg":"Asset transfer failed: insufficient balance"}`))
+			return
+		}
+		w.Write([]byte(`{"tranId":42}`))
+	}))
+	defer server.Close()
+
+	ft := &FuturesTrader{client: futures.NewClient("key", "secret"), spotBaseURL: server.URL}
+
+	tests := []struct {
+		name       string
+		req        TransferRequest
+		wantPath   string
+		wantParams map[string]string
+	}{
+		{
+			name:       "spot to futures",
+			req:        TransferRequest{Asset: "usdt", Amount: 10.5, FromWallet: WalletSpot, ToWallet: WalletFutures},
+			wantPath:   "/sapi/v1/asset/transfer",
+			wantParams: map[string]string{"type": "MAIN_UMFUTURE", "asset": "USDT", "amount": "10.5"},
+		},
+		{
+			name:       "futures to spot",
+			req:        TransferRequest{Asset: "USDC", Amount: 3, FromWallet: WalletFutures, ToWallet: WalletSpot},
+			wantPath:   "/sapi/v1/asset/transfer",
+			wantParams: map[string]string{"type": "UMFUTURE_MAIN", "asset": "USDC", "amount": "3"},
+		},
+		{
+			name: "sub-account",
+			req: TransferRequest{Asset: "USDT", Amount: 100, FromWallet: WalletSpot, ToWallet: WalletFutures,
+				ToEmail: "sub@x.com", ClientTranID: "rebalance-1"},
+			wantPath: "/sapi/v1/sub-account/universalTransfer",
+			wantParams: map[string]string{"fromAccountType": "SPOT", "toAccountType": "USDT_FUTURE", "asset": "USDT",
+				"amount": "100", "toEmail": "sub@x.com", "fromEmail": "", "clientTranId": "rebalance-1"},
+		},
+	}
+	for _, tt := range tests {
+		tranID, err := ft.TransferFunds(tt.req)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if tranID != "42" || path != tt.wantPath {
+			t.Errorf("%s: got tranId %s on %s, want 42 on %s", tt.name, tranID, path, tt.wantPath)
+		}
+		for key, want := range tt.wantParams {
+			if got := params.Get(key); got != want {
+				t.Errorf("%s: param %s = %q, want %q", tt.name, key, got, want)
+			}
+		}
+	}
+
+	// Unsupported wallets are rejected before any request
+	path = ""
+	if _, err := ft.TransferFunds(TransferRequest{Asset: "USDT", Amount: 1, FromWallet: "margin", ToWallet: WalletSpot}); err == nil || path != "" {
+		t.Errorf("expected unsupported wallet rejected locally, got err=%v path=%q", err, path)
+	}
+	if _, err := ft.TransferFunds(TransferRequest{Asset: "USDT", Amount: 1, FromWallet: "margin", ToWallet: WalletSpot, ToEmail: "sub@x.com"}); err == nil || path != "" {
+		t.Errorf("expected unsupported sub-account wallet rejected locally, got err=%v path=%q", err, path)
+	}
+
+	// Exchange rejections are classified
+	_, err := ft.TransferFunds(TransferRequest{Asset: "FAIL", Amount: 1, FromWallet: WalletSpot, ToWallet: WalletFutures})
+	if KindOf(err) != ErrKindInsufficientMargin {
+		t.Errorf("expected an insufficient margin error, got %v (kind %v)", err, KindOf(err))
+	}
+}
@@ -0,0 +1,208 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bracketCheckInterval how often client-side brackets are checked for a filled leg
+const bracketCheckInterval = 15 * time.Second
+
+// BracketSetter optional capability for exchanges with native OCO take-profit/stop-loss orders
+// Filling one leg cancels the other on the exchange side
+type BracketSetter interface {
+	SetBracket(symbol string, positionSide string, quantity, stopPrice, takeProfitPrice float64) error
+}
+
+// SetBracket places an OKX OCO algo order carrying both the stop loss and the take profit
+func (t *OKXTrader) SetBracket(symbol string, positionSide string, quantity, stopPrice, takeProfitPrice float64) error {
+	inst, err := t.getInstrument(symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get instrument info: %w", err)
+	}
+	szStr := t.formatSize(quantity/inst.CtVal, inst)
+
+	side := "sell"
+	posSide := "long"
+	if strings.ToUpper(positionSide) == "SHORT" {
+		side = "buy"
+		posSide = "short"
+	}
+
+	body := map[string]interface{}{
+		"instId":      t.convertSymbol(symbol),
+		"tdMode":      "cross",
+		"side":        side,
+		"posSide":     posSide,
+		"ordType":     "oco",
+		"sz":          szStr,
+		"slTriggerPx": fmt.Sprintf("%.8f", stopPrice),
+		"slOrdPx":     "-1", // Market price
+		"tpTriggerPx": fmt.Sprintf("%.8f", takeProfitPrice),
+		"tpOrdPx":     "-1", // Market price
+		"tag":         okxTag,
+	}
+	if _, err := t.doRequest("POST", okxAlgoOrderPath, body); err != nil {
+		return fmt.Errorf("failed to set bracket: %w", err)
+	}
+
+	logger.Infof("  Bracket set (OCO): stop loss %.4f / take profit %.4f", stopPrice, takeProfitPrice)
+	return nil
+}
+
+// bracket linked stop loss / take profit of one position
+type bracket struct {
+	Symbol     string
+	Side       string // "LONG" or "SHORT"
+	Quantity   float64
+	StopLoss   float64
+	TakeProfit float64
+	Native     bool // Exchange-side OCO, the exchange cancels the other leg itself
+}
+
+// brackets active brackets keyed by symbol_side (side lowercase, matching position maps)
+type brackets struct {
+	mu   sync.Mutex
+	legs map[string]*bracket
+}
+
+func bracketKey(symbol, side string) string {
+	return symbol + "_" + strings.ToLower(side)
+}
+
+// setBracket places linked stop loss and take profit for a position (side "LONG"/"SHORT")
+// Uses a native OCO where the position's exchange supports it. Otherwise both legs are placed as
+// independent orders and the bracket watcher cancels the remaining leg once the position is gone
+func (at *AutoTrader) setBracket(symbol, side string, quantity, stopLoss, takeProfit float64) {
+	b := &bracket{Symbol: symbol, Side: side, Quantity: quantity, StopLoss: stopLoss, TakeProfit: takeProfit}
+
+	if setter, ok := capability[BracketSetter](at.trader, symbol, side); ok && stopLoss > 0 && takeProfit > 0 {
+		err := setter.SetBracket(symbol, side, quantity, stopLoss, takeProfit)
+		if err == nil {
+			b.Native = true
+			at.trackBracket(b)
+			return
+		}
+		logger.Infof("  ⚠ Native bracket failed, placing separate orders: %v", err)
+	}
+
+	placed := false
+	if stopLoss > 0 {
+		if err := at.trader.SetStopLoss(symbol, side, quantity, stopLoss); err != nil {
+			logger.Infof("  ⚠ Failed to set stop loss: %v", err)
+		} else {
+			placed = true
+		}
+	}
+	if takeProfit > 0 {
+		if err := at.trader.SetTakeProfit(symbol, side, quantity, takeProfit); err != nil {
+			logger.Infof("  ⚠ Failed to set take profit: %v", err)
+		} else {
+			placed = true
+		}
+	}
+	if placed {
+		at.trackBracket(b)
+	}
+}
+
+func (at *AutoTrader) trackBracket(b *bracket) {
+	at.brackets.mu.Lock()
+	at.brackets.legs[bracketKey(b.Symbol, b.Side)] = b
+	at.brackets.mu.Unlock()
+}
+
+// clearBracket forgets a position's bracket after it was closed and cancels leftover legs (side "long"/"short")
+func (at *AutoTrader) clearBracket(symbol, side string) {
+	at.brackets.mu.Lock()
+	_, ok := at.brackets.legs[bracketKey(symbol, side)]
+	delete(at.brackets.legs, bracketKey(symbol, side))
+	at.brackets.mu.Unlock()
+	if ok {
+		at.cancelBracketOrders(symbol)
+	}
+}
+
+// cancelBracketOrders cancels the symbol's stop loss / take profit orders, then restores the
+// bracket of the opposite side if it is still open (exchange cancels are per symbol, not per side)
+func (at *AutoTrader) cancelBracketOrders(symbol string) {
+	if err := at.trader.CancelStopOrders(symbol); err != nil {
+		logger.Infof("  ⚠ Failed to cancel leftover bracket orders for %s: %v", symbol, err)
+		return
+	}
+
+	var remaining []*bracket
+	at.brackets.mu.Lock()
+	for _, b := range at.brackets.legs {
+		if b.Symbol == symbol {
+			remaining = append(remaining, b)
+		}
+	}
+	at.brackets.mu.Unlock()
+
+	for _, b := range remaining {
+		logger.Infof("  ↻ Restoring %s %s bracket after cancelling leftover orders", b.Symbol, b.Side)
+		at.setBracket(b.Symbol, b.Side, b.Quantity, b.StopLoss, b.TakeProfit)
+	}
+}
+
+// closedBrackets returns keys of client-side brackets whose position is no longer open
+// (one leg filled, or the position was closed elsewhere). Native brackets are left to the exchange
+func closedBrackets(legs map[string]*bracket, open map[string]bool) []string {
+	var closed []string
+	for key, b := range legs {
+		if !b.Native && !open[key] {
+			closed = append(closed, key)
+		}
+	}
+	return closed
+}
+
+// checkBrackets cancels the remaining leg of client-side brackets whose position has closed
+// and starts the stop-loss cooldown of positions that closed at their stop
+func (at *AutoTrader) checkBrackets() {
+	at.brackets.mu.Lock()
+	empty := len(at.brackets.legs) == 0
+	at.brackets.mu.Unlock()
+	if empty {
+		return
+	}
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Infof("❌ Bracket monitoring: failed to get positions: %v", err)
+		return
+	}
+	open := make(map[string]bool, len(positions))
+	for _, pos := range positions {
+		open[bracketKey(pos.Symbol, pos.Side)] = true
+	}
+
+	at.brackets.mu.Lock()
+	closed := closedBrackets(at.brackets.legs, open)
+	symbols := make(map[string]bool)
+	var gone []*bracket
+	for _, key := range closed {
+		symbols[at.brackets.legs[key].Symbol] = true
+		gone = append(gone, at.brackets.legs[key])
+		delete(at.brackets.legs, key)
+	}
+	// Native brackets of closed positions need no cancel, just forget them
+	for key, b := range at.brackets.legs {
+		if b.Native && !open[key] {
+			gone = append(gone, b)
+			delete(at.brackets.legs, key)
+		}
+	}
+	at.brackets.mu.Unlock()
+
+	at.recordStopOuts(gone)
+
+	for symbol := range symbols {
+		logger.Infof("🔗 Bracket leg filled for %s, cancelling the other leg", symbol)
+		at.cancelBracketOrders(symbol)
+	}
+}
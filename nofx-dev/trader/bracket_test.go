@@ -0,0 +1,85 @@
+package trader
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// bracketStub records stop order calls of the client-side bracket path
+type bracketStub struct {
+	Trader
+	positions []Position
+	calls     []string
+}
+
+func (s *bracketStub) GetPositions() ([]Position, error) {
+	return s.positions, nil
+}
+
+func (s *bracketStub) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	s.calls = append(s.calls, "sl "+symbol+" "+positionSide)
+	return nil
+}
+
+func (s *bracketStub) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	s.calls = append(s.calls, "tp "+symbol+" "+positionSide)
+	return nil
+}
+
+func (s *bracketStub) CancelStopOrders(symbol string) error {
+	s.calls = append(s.calls, "cancel "+symbol)
+	return nil
+}
+
+// TestClosedBrackets tests that only client-side brackets of closed positions are reported
+func TestClosedBrackets(t *testing.T) {
+	legs := map[string]*bracket{
+		"BTCUSDT_long":  {Symbol: "BTCUSDT", Side: "LONG"},
+		"ETHUSDT_short": {Symbol: "ETHUSDT", Side: "SHORT"},
+		"SOLUSDT_long":  {Symbol: "SOLUSDT", Side: "LONG", Native: true},
+	}
+	closed := closedBrackets(legs, map[string]bool{"BTCUSDT_long": true})
+	if !reflect.DeepEqual(closed, []string{"ETHUSDT_short"}) {
+		t.Errorf("expected [ETHUSDT_short], got %v", closed)
+	}
+}
+
+// TestCheckBracketsCancelsLeftoverLeg tests the other leg is cancelled when a position closes,
+// and the opposite side's bracket on the same symbol is restored after the per-symbol cancel
+func TestCheckBracketsCancelsLeftoverLeg(t *testing.T) {
+	stub := &bracketStub{}
+	at := &AutoTrader{trader: stub, brackets: brackets{legs: make(map[string]*bracket)}}
+
+	at.setBracket("BTCUSDT", "LONG", 1, 90, 110)
+	at.setBracket("BTCUSDT", "SHORT", 1, 110, 90)
+	at.setBracket("ETHUSDT", "LONG", 1, 1900, 2100)
+	stub.calls = nil
+
+	// Stop loss of BTC long filled, BTC short and ETH long still open
+	stub.positions = []Position{
+		{Symbol: "BTCUSDT", Side: "short"},
+		{Symbol: "ETHUSDT", Side: "long"},
+	}
+	at.checkBrackets()
+
+	want := []string{"cancel BTCUSDT", "sl BTCUSDT SHORT", "tp BTCUSDT SHORT"}
+	if !reflect.DeepEqual(stub.calls, want) {
+		t.Errorf("expected calls %v, got %v", want, stub.calls)
+	}
+	var keys []string
+	for key := range at.brackets.legs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"BTCUSDT_short", "ETHUSDT_long"}) {
+		t.Errorf("unexpected remaining brackets %v", keys)
+	}
+
+	// Explicit close cancels leftovers immediately
+	stub.calls = nil
+	at.clearBracket("ETHUSDT", "long")
+	if !reflect.DeepEqual(stub.calls, []string{"cancel ETHUSDT"}) {
+		t.Errorf("expected ETHUSDT cancel on close, got %v", stub.calls)
+	}
+}
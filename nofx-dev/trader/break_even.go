@@ -0,0 +1,189 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+// breakEvenCheckInterval how often positions are checked for the break-even trigger
+const breakEvenCheckInterval = 30 * time.Second
+
+// breakEvenStops positions whose stop loss was already moved to break-even, keyed by symbol_side
+type breakEvenStops struct {
+	mu    sync.Mutex
+	moved map[string]bool
+}
+
+// leveragedPnLPct unrealized P&L of a position in % of margin (same measure as the drawdown monitor)
+func leveragedPnLPct(pos Position) float64 {
+	if pos.EntryPrice <= 0 {
+		return 0
+	}
+	leverage := pos.Leverage
+	if leverage <= 0 {
+		leverage = 10 // Default value
+	}
+	move := (pos.MarkPrice - pos.EntryPrice) / pos.EntryPrice
+	if pos.Side == "short" {
+		move = -move
+	}
+	return move * leverage * 100
+}
+
+// breakEvenStopPrice stop price that exits at entry after paying round-trip fees plus bufferPct (% of entry)
+// feeRate is the taker fee rate per side
+func breakEvenStopPrice(side string, entryPrice, feeRate, bufferPct float64) float64 {
+	offset := 2*feeRate + bufferPct/100
+	if side == "short" {
+		return entryPrice * (1 - offset)
+	}
+	return entryPrice * (1 + offset)
+}
+
+// stopProtects reports whether stop is at or beyond target in the position's favor
+func stopProtects(side string, stop, target float64) bool {
+	if stop <= 0 {
+		return false
+	}
+	if side == "short" {
+		return stop <= target
+	}
+	return stop >= target
+}
+
+// checkBreakEven moves stop losses to break-even for positions whose profit reached the configured trigger
+func (at *AutoTrader) checkBreakEven() {
+	if at.config.StrategyConfig == nil {
+		return
+	}
+	riskControl := at.config.StrategyConfig.RiskControl
+	if riskControl.BreakEvenTriggerPct <= 0 {
+		return
+	}
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Infof("❌ Break-even monitoring: failed to get positions: %v", err)
+		return
+	}
+
+	open := make(map[string]bool, len(positions))
+	for _, pos := range positions {
+		key := bracketKey(pos.Symbol, pos.Side)
+		open[key] = true
+
+		// Exchange circuit open: snapshot prices are stale and orders can't be sent
+		if pos.ReadOnly || pos.Quantity <= 0 {
+			continue
+		}
+		at.breakEven.mu.Lock()
+		moved := at.breakEven.moved[key]
+		at.breakEven.mu.Unlock()
+		if moved || leveragedPnLPct(pos) < riskControl.BreakEvenTriggerPct {
+			continue
+		}
+
+		feeRate := logger.EstimateFee(at.exchange, 1, false)
+		stop := breakEvenStopPrice(pos.Side, pos.EntryPrice, feeRate, riskControl.BreakEvenBufferPct)
+		// Mark already beyond the stop (fees + buffer exceed the profit): placing it would trigger at once
+		if stopProtects(pos.Side, stop, pos.MarkPrice) {
+			continue
+		}
+
+		if err := at.moveStopToBreakEven(pos, stop); err != nil {
+			logger.Infof("❌ Break-even stop failed (%s %s): %v", pos.Symbol, pos.Side, err)
+			continue
+		}
+		at.breakEven.mu.Lock()
+		at.breakEven.moved[key] = true
+		at.breakEven.mu.Unlock()
+	}
+
+	// Forget closed positions so a new position on the same symbol starts over
+	at.breakEven.mu.Lock()
+	for key := range at.breakEven.moved {
+		if !open[key] {
+			delete(at.breakEven.moved, key)
+		}
+	}
+	at.breakEven.mu.Unlock()
+}
+
+// moveStopToBreakEven replaces the position's stop loss with stop, keeping its take profit
+// A stop loss that already protects break-even is left alone
+func (at *AutoTrader) moveStopToBreakEven(pos Position, stop float64) error {
+	upperSide := strings.ToUpper(pos.Side)
+	key := bracketKey(pos.Symbol, pos.Side)
+
+	at.brackets.mu.Lock()
+	b, tracked := at.brackets.legs[key]
+	var current bracket
+	if tracked {
+		current = *b
+	}
+	at.brackets.mu.Unlock()
+
+	if tracked && stopProtects(pos.Side, current.StopLoss, stop) {
+		logger.Infof("🛡 %s %s stop loss %.4f already protects break-even %.4f", pos.Symbol, pos.Side, current.StopLoss, stop)
+		return nil
+	}
+
+	// Amend the open stop in place where the exchange supports it (the stop leg of a native OCO included)
+	if at.amendProtection(pos.Symbol, upperSide, pos.Quantity, stop, 0) {
+		at.brackets.mu.Lock()
+		if tracked {
+			b.StopLoss = stop
+		} else {
+			at.brackets.legs[key] = &bracket{Symbol: pos.Symbol, Side: upperSide, Quantity: pos.Quantity, StopLoss: stop}
+		}
+		at.brackets.mu.Unlock()
+		logger.Infof("🛡 Break-even stop set for %s %s: %.4f (entry %.4f, amended)", pos.Symbol, pos.Side, stop, pos.EntryPrice)
+		return nil
+	}
+
+	// Native OCO carries both legs in one order, replace the whole bracket
+	if tracked && current.Native {
+		at.brackets.mu.Lock()
+		delete(at.brackets.legs, key)
+		at.brackets.mu.Unlock()
+		at.cancelBracketOrders(pos.Symbol)
+		at.setBracket(pos.Symbol, upperSide, pos.Quantity, stop, current.TakeProfit)
+		logger.Infof("🛡 Break-even stop set for %s %s: %.4f (entry %.4f)", pos.Symbol, pos.Side, stop, pos.EntryPrice)
+		return nil
+	}
+
+	// Replace only stop-loss orders, take profit and trailing stops stay in place
+	if err := at.trader.CancelStopLossOrders(pos.Symbol); err != nil {
+		return fmt.Errorf("failed to cancel stop loss: %w", err)
+	}
+	if err := at.trader.SetStopLoss(pos.Symbol, upperSide, pos.Quantity, stop); err != nil {
+		return fmt.Errorf("failed to set break-even stop loss (position has no stop loss now): %w", err)
+	}
+
+	at.brackets.mu.Lock()
+	if tracked {
+		b.StopLoss = stop
+	} else {
+		at.brackets.legs[key] = &bracket{Symbol: pos.Symbol, Side: upperSide, Quantity: pos.Quantity, StopLoss: stop}
+	}
+	// The cancel is per symbol, restore the opposite side's stop loss
+	var opposite *bracket
+	for k, other := range at.brackets.legs {
+		if k != key && other.Symbol == pos.Symbol && !other.Native && other.StopLoss > 0 {
+			opposite = other
+		}
+	}
+	at.brackets.mu.Unlock()
+
+	if opposite != nil {
+		if err := at.trader.SetStopLoss(opposite.Symbol, opposite.Side, opposite.Quantity, opposite.StopLoss); err != nil {
+			logger.Infof("  ⚠ Failed to restore %s %s stop loss: %v", opposite.Symbol, opposite.Side, err)
+		}
+	}
+
+	logger.Infof("🛡 Break-even stop set for %s %s: %.4f (entry %.4f)", pos.Symbol, pos.Side, stop, pos.EntryPrice)
+	return nil
+}
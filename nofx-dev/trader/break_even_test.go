@@ -0,0 +1,49 @@
+package trader
+
+import (
+	"math"
+	"testing"
+)
+
+// TestBreakEvenStopPrice tests the stop covers round-trip fees and buffer on the profitable side of entry
+func TestBreakEvenStopPrice(t *testing.T) {
+	// 0.05% taker per side + 0.1% buffer = 0.2% beyond entry
+	if got := breakEvenStopPrice("long", 100, 0.0005, 0.1); math.Abs(got-100.2) > 1e-9 {
+		t.Errorf("long: expected 100.2, got %.6f", got)
+	}
+	if got := breakEvenStopPrice("short", 100, 0.0005, 0.1); math.Abs(got-99.8) > 1e-9 {
+		t.Errorf("short: expected 99.8, got %.6f", got)
+	}
+}
+
+// TestLeveragedPnLPct tests P&L in % of margin for both sides
+func TestLeveragedPnLPct(t *testing.T) {
+	long := Position{Side: "long", EntryPrice: 100, MarkPrice: 102, Leverage: 5}
+	if got := leveragedPnLPct(long); math.Abs(got-10) > 1e-9 {
+		t.Errorf("long: expected 10%%, got %.4f", got)
+	}
+	short := Position{Side: "short", EntryPrice: 100, MarkPrice: 102, Leverage: 5}
+	if got := leveragedPnLPct(short); math.Abs(got+10) > 1e-9 {
+		t.Errorf("short: expected -10%%, got %.4f", got)
+	}
+}
+
+// TestStopProtects tests an existing stop is kept only when it is at or beyond break-even
+func TestStopProtects(t *testing.T) {
+	tests := []struct {
+		side       string
+		stop, want float64
+		protects   bool
+	}{
+		{"long", 101, 100.2, true},
+		{"long", 95, 100.2, false},
+		{"short", 99, 99.8, true},
+		{"short", 105, 99.8, false},
+		{"long", 0, 100.2, false},
+	}
+	for _, tt := range tests {
+		if got := stopProtects(tt.side, tt.stop, tt.want); got != tt.protects {
+			t.Errorf("stopProtects(%s, %.2f, %.2f) = %v, want %v", tt.side, tt.stop, tt.want, got, tt.protects)
+		}
+	}
+}
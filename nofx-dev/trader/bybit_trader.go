@@ -10,6 +10,7 @@ import (
 	"io"
 	"math"
 	"net/http"
+	"nofx/endpoint"
 	"nofx/logger"
 	"strconv"
 	"strings"
@@ -19,6 +20,11 @@ import (
 	bybit "github.com/bybit-exchange/bybit.go.api"
 )
 
+func init() {
+	// USDT perpetual base tier: 0.02% maker, 0.055% taker
+	logger.RegisterFeeSchedule("bybit", logger.FeeSchedule{Maker: 0.0002, Taker: 0.00055})
+}
+
 // BybitTrader Bybit USDT Perpetual Futures Trader
 type BybitTrader struct {
 	client    *bybit.Client
@@ -31,7 +37,7 @@ type BybitTrader struct {
 	balanceCacheMutex sync.RWMutex
 
 	// Position cache
-	cachedPositions     []map[string]interface{}
+	cachedPositions     []Position
 	positionsCacheTime  time.Time
 	positionsCacheMutex sync.RWMutex
 
@@ -57,7 +63,7 @@ func NewBybitTrader(apiKey, secretKey string) *BybitTrader {
 		}
 
 		client.HTTPClient.Transport = &headerRoundTripper{
-			base:      defaultTransport,
+			base:      endpoint.NewTransport(defaultTransport),
 			refererID: src,
 		}
 	}
@@ -119,7 +125,7 @@ func (t *BybitTrader) GetBalance() (map[string]interface{}, error) {
 
 	list, _ := resultData["list"].([]interface{})
 
-	var totalEquity, availableBalance, totalWalletBalance, totalPerpUPL float64 = 0, 0, 0, 0
+	var totalEquity, availableBalance, totalWalletBalance, totalPerpUPL, accountMMRate float64 = 0, 0, 0, 0, 0
 
 	if len(list) > 0 {
 		account, _ := list[0].(map[string]interface{})
@@ -137,6 +143,10 @@ func (t *BybitTrader) GetBalance() (map[string]interface{}, error) {
 		if uplStr, ok := account["totalPerpUPL"].(string); ok {
 			totalPerpUPL, _ = strconv.ParseFloat(uplStr, 64)
 		}
+		// Maintenance margin rate of the whole account (decimal, liquidation at 1)
+		if mmrStr, ok := account["accountMMRate"].(string); ok {
+			accountMMRate, _ = strconv.ParseFloat(mmrStr, 64)
+		}
 	}
 
 	// If no totalWalletBalance, use totalEquity
@@ -150,6 +160,7 @@ func (t *BybitTrader) GetBalance() (map[string]interface{}, error) {
 		"availableBalance":      availableBalance,
 		"totalUnrealizedProfit": totalPerpUPL,
 		"balance":               totalEquity, // Compatible with other exchange formats
+		"marginRatio":           accountMMRate * 100,
 	}
 
 	// Update cache
@@ -162,7 +173,7 @@ func (t *BybitTrader) GetBalance() (map[string]interface{}, error) {
 }
 
 // GetPositions retrieves all positions
-func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
+func (t *BybitTrader) GetPositions() ([]Position, error) {
 	// Check cache
 	t.positionsCacheMutex.RLock()
 	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
@@ -194,7 +205,7 @@ func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
 
 	list, _ := resultData["list"].([]interface{})
 
-	var positions []map[string]interface{}
+	var positions []Position
 
 	for _, item := range list {
 		pos, ok := item.(map[string]interface{})
@@ -233,31 +244,34 @@ func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
 		updatedTimeStr, _ := pos["updatedTime"].(string)
 		updatedTime, _ := strconv.ParseInt(updatedTimeStr, 10, 64)
 
-		positionSide, _ := pos["side"].(string) // Buy = LONG, Sell = SHORT
+		// Trade mode: 0 = cross, 1 = isolated
+		marginMode := "cross"
+		if tradeMode, _ := pos["tradeMode"].(float64); tradeMode == 1 {
+			marginMode = "isolated"
+		}
+
+		positionSide, _ := pos["side"].(string) // Buy = long, Sell = short
+		symbol, _ := pos["symbol"].(string)
 
 		// Convert to unified format
-		side := "LONG"
-		positionAmt := size
+		side := "long"
 		if positionSide == "Sell" {
-			side = "SHORT"
-			positionAmt = -size
+			side = "short"
 		}
 
-		position := map[string]interface{}{
-			"symbol":           pos["symbol"],
-			"side":             side,
-			"positionAmt":      positionAmt,
-			"entryPrice":       entryPrice,
-			"markPrice":        markPrice,
-			"unRealizedProfit": unrealisedPnl,
-			"unrealizedPnL":    unrealisedPnl,
-			"liquidationPrice": liqPrice,
-			"leverage":         leverage,
-			"createdTime":      createdTime, // Position open time (ms)
-			"updatedTime":      updatedTime, // Position last update time (ms)
-		}
-
-		positions = append(positions, position)
+		positions = append(positions, Position{
+			Symbol:           symbol,
+			Side:             side,
+			Quantity:         size,
+			EntryPrice:       entryPrice,
+			MarkPrice:        markPrice,
+			UnrealizedPnL:    unrealisedPnl,
+			Leverage:         leverage,
+			LiquidationPrice: liqPrice,
+			MarginMode:       marginMode,
+			CreatedTime:      createdTime,
+			UpdatedTime:      updatedTime,
+		})
 	}
 
 	// Update cache
@@ -338,8 +352,8 @@ func (t *BybitTrader) CloseLong(symbol string, quantity float64) (map[string]int
 			return nil, err
 		}
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "LONG" {
-				quantity = pos["positionAmt"].(float64)
+			if pos.Symbol == symbol && pos.Side == "long" {
+				quantity = pos.Quantity
 				break
 			}
 		}
@@ -382,8 +396,8 @@ func (t *BybitTrader) CloseShort(symbol string, quantity float64) (map[string]in
 			return nil, err
 		}
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "SHORT" {
-				quantity = -pos["positionAmt"].(float64) // Short position is negative
+			if pos.Symbol == symbol && pos.Side == "short" {
+				quantity = pos.Quantity
 				break
 			}
 		}
@@ -647,7 +661,7 @@ func (t *BybitTrader) getQtyStep(symbol string) float64 {
 
 	// Call public API directly to get contract information
 	url := fmt.Sprintf("https://api.bybit.com/v5/market/instruments-info?category=linear&symbol=%s", symbol)
-	resp, err := http.Get(url)
+	resp, err := endpoint.WrapClient(http.DefaultClient).Get(url)
 	if err != nil {
 		logger.Infof("⚠️ [Bybit] Failed to get precision info for %s: %v", symbol, err)
 		return 1 // Default to integer
@@ -719,6 +733,11 @@ func (t *BybitTrader) FormatQuantity(symbol string, quantity float64) (string, e
 
 // Helper methods
 
+// invalidateCaches clears balance/position caches (forces the next query to hit the API)
+func (t *BybitTrader) invalidateCaches() {
+	t.clearCache()
+}
+
 func (t *BybitTrader) clearCache() {
 	t.balanceCacheMutex.Lock()
 	t.cachedBalance = nil
@@ -902,8 +921,8 @@ func (t *BybitTrader) getClosedPnLViaHTTP(startTime time.Time, limit int) ([]Clo
 	req.Header.Set("X-BAPI-RECV-WINDOW", recvWindow)
 	req.Header.Set("Content-Type", "application/json")
 
-	// Use http.DefaultClient for the request
-	resp, err := http.DefaultClient.Do(req)
+	// Use http.DefaultClient for the request (via the preferred Bybit domain)
+	resp, err := endpoint.WrapClient(http.DefaultClient).Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call Bybit API: %w", err)
 	}
@@ -177,6 +177,10 @@ func TestBybitTrader_SymbolFormat(t *testing.T) {
 // TestBybitTrader_FormatQuantity Test quantity formatting
 func TestBybitTrader_FormatQuantity(t *testing.T) {
 	trader := NewBybitTrader("test", "test")
+	// Seed the qty step cache so the test doesn't depend on the instruments-info API
+	for _, symbol := range []string{"BTCUSDT", "ETHUSDT", "SOLUSDT"} {
+		trader.qtyStepCache[symbol] = 0.001
+	}
 
 	tests := []struct {
 		name     string
@@ -189,7 +193,7 @@ func TestBybitTrader_FormatQuantity(t *testing.T) {
 			name:     "BTC quantity formatting",
 			symbol:   "BTCUSDT",
 			quantity: 0.12345,
-			expected: "0.123", // Bybit defaults to 3 decimal places
+			expected: "0.123", // Rounded down to the 0.001 qty step
 			hasError: false,
 		},
 		{
@@ -0,0 +1,105 @@
+package trader
+
+import (
+	"math"
+	"nofx/decision"
+	"sync"
+)
+
+// candidatePruneWeight candidates at or below this weight leave the prompt (two half-lives without action)
+const candidatePruneWeight = 0.25
+
+// candidateState presentation history of one candidate symbol
+type candidateState struct {
+	Idle      int // Consecutive cycles presented without any action
+	PrunedFor int // Remaining cycles the candidate stays out of the prompt (0 = active)
+}
+
+// candidateDecay prunes candidates the AI keeps ignoring, so prompts stay focused
+// A pruned candidate returns after a cooldown as long as its source still lists it
+type candidateDecay struct {
+	mu     sync.Mutex
+	states map[string]*candidateState
+}
+
+func newCandidateDecay() *candidateDecay {
+	return &candidateDecay{states: make(map[string]*candidateState)}
+}
+
+// candidateWeight relevance of a candidate after idle cycles: 1 when fresh, halving every halfLife cycles
+func candidateWeight(idle, halfLife int) float64 {
+	if halfLife <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(idle)/float64(halfLife))
+}
+
+// filter removes decayed candidates (symbols with open positions are always kept)
+// Returns the remaining candidates and the symbols pruned this cycle
+func (d *candidateDecay) filter(candidates []decision.CandidateCoin, held map[string]bool, halfLife int) ([]decision.CandidateCoin, []string) {
+	if halfLife <= 0 {
+		return candidates, nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	kept := make([]decision.CandidateCoin, 0, len(candidates))
+	var pruned []string
+	for _, c := range candidates {
+		state, ok := d.states[c.Symbol]
+		if !ok || held[c.Symbol] {
+			kept = append(kept, c)
+			continue
+		}
+		if state.PrunedFor > 0 {
+			state.PrunedFor--
+			if state.PrunedFor > 0 {
+				continue
+			}
+			state.Idle = 0 // Cooldown over, give it a fresh start
+		}
+		if candidateWeight(state.Idle, halfLife) <= candidatePruneWeight {
+			state.PrunedFor = 2 * halfLife
+			pruned = append(pruned, c.Symbol)
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept, pruned
+}
+
+// observe updates idle counters after a decision: acted symbols reset, the other presented ones age
+func (d *candidateDecay) observe(presented []string, acted map[string]bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, symbol := range presented {
+		state, ok := d.states[symbol]
+		if !ok {
+			state = &candidateState{}
+			d.states[symbol] = state
+		}
+		if acted[symbol] {
+			state.Idle = 0
+		} else {
+			state.Idle++
+		}
+	}
+}
+
+// observeCandidateDecisions ages the candidates presented this cycle by whether the AI acted on them
+func (at *AutoTrader) observeCandidateDecisions(ctx *decision.Context, decisions []decision.Decision) {
+	if at.strategyEngine.GetConfig().CoinSource.CandidateHalfLife <= 0 {
+		return
+	}
+	acted := make(map[string]bool)
+	for _, d := range decisions {
+		if d.Action != "hold" && d.Action != "wait" {
+			acted[d.Symbol] = true
+		}
+	}
+	presented := make([]string, 0, len(ctx.CandidateCoins))
+	for _, c := range ctx.CandidateCoins {
+		presented = append(presented, c.Symbol)
+	}
+	at.candidates.observe(presented, acted)
+}
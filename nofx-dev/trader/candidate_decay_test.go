@@ -0,0 +1,55 @@
+package trader
+
+import (
+	"nofx/decision"
+	"reflect"
+	"testing"
+)
+
+func candidateSymbols(coins []decision.CandidateCoin) []string {
+	symbols := make([]string, 0, len(coins))
+	for _, c := range coins {
+		symbols = append(symbols, c.Symbol)
+	}
+	return symbols
+}
+
+// TestCandidateDecay tests idle candidates decay out after two half-lives, held and acted ones stay,
+// and pruned candidates return after the cooldown
+func TestCandidateDecay(t *testing.T) {
+	d := newCandidateDecay()
+	candidates := []decision.CandidateCoin{{Symbol: "BTCUSDT"}, {Symbol: "ETHUSDT"}, {Symbol: "DOGEUSDT"}, {Symbol: "SOLUSDT"}}
+	held := map[string]bool{"SOLUSDT": true}
+	halfLife := 2
+
+	var pruned []string
+	for cycle := 0; cycle < 5; cycle++ {
+		var kept []decision.CandidateCoin
+		kept, pruned = d.filter(candidates, held, halfLife)
+		if cycle < 4 && len(pruned) > 0 {
+			t.Fatalf("cycle %d: unexpected prune %v", cycle, pruned)
+		}
+		d.observe(candidateSymbols(kept), map[string]bool{"BTCUSDT": true})
+	}
+	// ETH/DOGE idle for 4 cycles = two half-lives, SOL is held
+	if !reflect.DeepEqual(pruned, []string{"ETHUSDT", "DOGEUSDT"}) {
+		t.Fatalf("expected ETH and DOGE pruned, got %v", pruned)
+	}
+
+	// Out for 2*halfLife cycles, then back with a fresh start
+	for cycle := 0; cycle < 2*halfLife-1; cycle++ {
+		kept, _ := d.filter(candidates, held, halfLife)
+		if got := candidateSymbols(kept); !reflect.DeepEqual(got, []string{"BTCUSDT", "SOLUSDT"}) {
+			t.Fatalf("cooldown cycle %d: expected only BTC and SOL, got %v", cycle, got)
+		}
+	}
+	kept, _ := d.filter(candidates, held, halfLife)
+	if len(kept) != 4 {
+		t.Errorf("expected all candidates back after cooldown, got %v", candidateSymbols(kept))
+	}
+
+	// Disabled: nothing is filtered
+	if kept, _ := newCandidateDecay().filter(candidates, nil, 0); len(kept) != 4 {
+		t.Errorf("expected no pruning when disabled")
+	}
+}
@@ -0,0 +1,36 @@
+package trader
+
+import (
+	"nofx/logger"
+	"nofx/market"
+)
+
+// candleClockSymbol symbol whose candle closes pace candle-close cycles (every symbol's candles close together)
+const candleClockSymbol = "BTCUSDT"
+
+// candleCloseClock subscribes to the primary timeframe's candle closes when the strategy cycles on them, nil (and a
+// no-op stop) when it polls on the scan interval or the market monitor isn't running. The clock stream is claimed
+// under its own owner, so idle mode releasing the trader's streams doesn't stop the clock that resumes it
+func (at *AutoTrader) candleCloseClock() (<-chan market.Kline, func()) {
+	if market.WSMonitorCli == nil || at.config.StrategyConfig == nil {
+		return nil, func() {}
+	}
+	klines := at.config.StrategyConfig.Indicators.Klines
+	if !klines.CycleOnCandleClose {
+		return nil, func() {}
+	}
+	timeframe := klines.PrimaryTimeframe
+	if timeframe == "" {
+		timeframe = "3m"
+	}
+
+	owner := at.id + ":candle-clock"
+	monitor := market.WSMonitorCli
+	monitor.Acquire(owner, []string{candleClockSymbol}, []string{timeframe})
+	ch := monitor.OnCandleClose(candleClockSymbol, timeframe)
+	logger.Infof("🕯️ [%s] Decision cycles run on %s candle close", at.name, timeframe)
+	return ch, func() {
+		monitor.OffCandleClose(ch)
+		monitor.Release(owner)
+	}
+}
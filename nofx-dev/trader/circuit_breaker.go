@@ -0,0 +1,135 @@
+package trader
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// CircuitState circuit breaker state
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // Exchange healthy, all calls go through
+	CircuitOpen     CircuitState = "open"      // Exchange failing, calls fail fast until cooldown elapses
+	CircuitHalfOpen CircuitState = "half_open" // Cooldown elapsed, next call probes the exchange
+)
+
+const (
+	defaultCircuitThreshold = 5               // Consecutive API failures before the circuit opens
+	defaultCircuitCooldown  = 2 * time.Minute // Time the circuit stays open before probing again
+)
+
+// ErrCircuitOpen returned without calling the exchange while its circuit is open
+var ErrCircuitOpen = errors.New("exchange circuit open")
+
+// CircuitBreaker trips after consecutive exchange API failures
+// Only infrastructure errors count (network, rate limit, unclassified); order rejections and
+// insufficient margin prove the API is reachable and reset the failure count
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	state     CircuitState
+	openedAt  time.Time
+	lastErr   error
+	now       func() time.Time
+}
+
+// NewCircuitBreaker creates a circuit breaker (threshold/cooldown <= 0 use defaults)
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = defaultCircuitThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = defaultCircuitCooldown
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     CircuitClosed,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a call may go through
+// An open circuit moves to half-open once the cooldown has elapsed, letting calls probe the exchange
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		b.state = CircuitHalfOpen
+	}
+	return b.state != CircuitOpen
+}
+
+// Record records the result of a call, returns the new state and whether it changed
+// (half-open is not reported as a change, only transitions between open and closed)
+func (b *CircuitBreaker) Record(err error) (CircuitState, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !countsAsCircuitFailure(err) {
+		b.failures = 0
+		if b.state != CircuitClosed {
+			wasOpen := b.state == CircuitOpen || b.state == CircuitHalfOpen
+			b.state = CircuitClosed
+			return b.state, wasOpen
+		}
+		return b.state, false
+	}
+
+	b.failures++
+	b.lastErr = err
+	switch b.state {
+	case CircuitHalfOpen:
+		// Probe failed, stay open for another cooldown (already reported as open)
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+		return b.state, false
+	case CircuitClosed:
+		if b.failures >= b.threshold {
+			b.state = CircuitOpen
+			b.openedAt = b.now()
+			return b.state, true
+		}
+	}
+	return b.state, false
+}
+
+// State returns the current state
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Status returns a snapshot for status reporting
+func (b *CircuitBreaker) Status() map[string]interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := map[string]interface{}{
+		"state":    string(b.state),
+		"failures": b.failures,
+	}
+	if b.state != CircuitClosed {
+		status["opened_at"] = b.openedAt.Format(time.RFC3339)
+	}
+	if b.lastErr != nil {
+		status["last_error"] = b.lastErr.Error()
+	}
+	return status
+}
+
+// countsAsCircuitFailure whether err indicates the exchange API itself is failing
+func countsAsCircuitFailure(err error) bool {
+	if err == nil || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	switch KindOf(err) {
+	case ErrKindNetwork, ErrKindRateLimited, ErrKindUnknown:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package trader
+
+import (
+	"errors"
+	"time"
+)
+
+// ClassifiedTrader wraps a Trader so every returned error is an *ExchangeError
+// (rate limited / insufficient margin / invalid symbol / rejected / network)
+type ClassifiedTrader struct {
+	Trader
+	exchange string
+}
+
+// NewClassifiedTrader creates an error-classifying wrapper around inner
+func NewClassifiedTrader(inner Trader, exchange string) *ClassifiedTrader {
+	return &ClassifiedTrader{Trader: inner, exchange: exchange}
+}
+
+// Unwrap returns the wrapped trader
+func (c *ClassifiedTrader) Unwrap() Trader {
+	return c.Trader
+}
+
+// unwrapTrader strips decorator layers (error classification, fault injection)
+// to reach the concrete exchange adapter, for optional capability checks
+func unwrapTrader(t Trader) Trader {
+	for {
+		w, ok := t.(interface{ Unwrap() Trader })
+		if !ok {
+			return t
+		}
+		t = w.Unwrap()
+	}
+}
+
+func (c *ClassifiedTrader) wrap(op string, err error) error {
+	return ClassifyError(c.exchange, op, err)
+}
+
+func (c *ClassifiedTrader) GetBalance() (map[string]interface{}, error) {
+	result, err := c.Trader.GetBalance()
+	return result, c.wrap("GetBalance", err)
+}
+
+func (c *ClassifiedTrader) GetPositions() ([]Position, error) {
+	result, err := c.Trader.GetPositions()
+	return result, c.wrap("GetPositions", err)
+}
+
+func (c *ClassifiedTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	result, err := c.Trader.OpenLong(symbol, quantity, leverage)
+	return result, c.wrap("OpenLong", err)
+}
+
+func (c *ClassifiedTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	result, err := c.Trader.OpenShort(symbol, quantity, leverage)
+	return result, c.wrap("OpenShort", err)
+}
+
+func (c *ClassifiedTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	result, err := c.Trader.CloseLong(symbol, quantity)
+	return result, c.wrap("CloseLong", err)
+}
+
+func (c *ClassifiedTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	result, err := c.Trader.CloseShort(symbol, quantity)
+	return result, c.wrap("CloseShort", err)
+}
+
+func (c *ClassifiedTrader) SetLeverage(symbol string, leverage int) error {
+	return c.wrap("SetLeverage", c.Trader.SetLeverage(symbol, leverage))
+}
+
+func (c *ClassifiedTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	return c.wrap("SetMarginMode", c.Trader.SetMarginMode(symbol, isCrossMargin))
+}
+
+func (c *ClassifiedTrader) OpenWithProtection(symbol, positionSide string, quantity float64, leverage int, stopLoss, takeProfit float64) (map[string]interface{}, bool, error) {
+	placer, ok := batchEntryPlacer(c.Trader)
+	if !ok {
+		return nil, false, ErrBatchUnsupported
+	}
+	result, protected, err := placer.OpenWithProtection(symbol, positionSide, quantity, leverage, stopLoss, takeProfit)
+	if errors.Is(err, ErrBatchUnsupported) {
+		return nil, false, err
+	}
+	return result, protected, c.wrap("OpenWithProtection", err)
+}
+
+func (c *ClassifiedTrader) GetMarketPrice(symbol string) (float64, error) {
+	price, err := c.Trader.GetMarketPrice(symbol)
+	return price, c.wrap("GetMarketPrice", err)
+}
+
+func (c *ClassifiedTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	return c.wrap("SetStopLoss", c.Trader.SetStopLoss(symbol, positionSide, quantity, stopPrice))
+}
+
+func (c *ClassifiedTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	return c.wrap("SetTakeProfit", c.Trader.SetTakeProfit(symbol, positionSide, quantity, takeProfitPrice))
+}
+
+func (c *ClassifiedTrader) CancelStopLossOrders(symbol string) error {
+	return c.wrap("CancelStopLossOrders", c.Trader.CancelStopLossOrders(symbol))
+}
+
+func (c *ClassifiedTrader) CancelTakeProfitOrders(symbol string) error {
+	return c.wrap("CancelTakeProfitOrders", c.Trader.CancelTakeProfitOrders(symbol))
+}
+
+func (c *ClassifiedTrader) CancelAllOrders(symbol string) error {
+	return c.wrap("CancelAllOrders", c.Trader.CancelAllOrders(symbol))
+}
+
+func (c *ClassifiedTrader) CancelStopOrders(symbol string) error {
+	return c.wrap("CancelStopOrders", c.Trader.CancelStopOrders(symbol))
+}
+
+func (c *ClassifiedTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	result, err := c.Trader.FormatQuantity(symbol, quantity)
+	return result, c.wrap("FormatQuantity", err)
+}
+
+func (c *ClassifiedTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	result, err := c.Trader.GetOrderStatus(symbol, orderID)
+	return result, c.wrap("GetOrderStatus", err)
+}
+
+func (c *ClassifiedTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	result, err := c.Trader.GetClosedPnL(startTime, limit)
+	return result, c.wrap("GetClosedPnL", err)
+}
@@ -0,0 +1,831 @@
+package trader
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"nofx/logger"
+	"nofx/market"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Coinbase International Exchange (INTX) API endpoints
+const (
+	coinbaseBaseURL        = "https://api.international.coinbase.com"
+	coinbasePortfoliosPath = "/api/v1/portfolios"
+	coinbaseOrdersPath     = "/api/v1/orders"
+	coinbaseInstrumentPath = "/api/v1/instruments/"
+)
+
+// Client order ID prefixes, used to tell stop-loss and take-profit orders apart
+const (
+	coinbaseOrderPrefix = "nofx-"
+	coinbaseSLPrefix    = "nofx-sl-"
+	coinbaseTPPrefix    = "nofx-tp-"
+)
+
+func init() {
+	// INTX perpetuals base tier: 0.02% maker, 0.05% taker
+	logger.RegisterFeeSchedule("coinbase", logger.FeeSchedule{Maker: 0.0002, Taker: 0.0005})
+}
+
+// CoinbaseTrader Coinbase International Exchange perpetuals trader
+// INTX perps are USDC-margined with one net position per instrument (no hedge mode)
+type CoinbaseTrader struct {
+	apiKey     string
+	secretKey  string
+	passphrase string
+
+	// Portfolio used for trading (default portfolio of the API key)
+	portfolioID string
+	portfolioMu sync.Mutex
+
+	// HTTP client
+	httpClient *http.Client
+
+	// Balance cache
+	cachedBalance     map[string]interface{}
+	balanceCacheTime  time.Time
+	balanceCacheMutex sync.RWMutex
+
+	// Positions cache
+	cachedPositions     []Position
+	positionsCacheTime  time.Time
+	positionsCacheMutex sync.RWMutex
+
+	// Instrument info cache
+	instrumentsCache      map[string]*CoinbaseInstrument
+	instrumentsCacheMutex sync.RWMutex
+
+	// Cache duration
+	cacheDuration time.Duration
+}
+
+// CoinbaseInstrument INTX instrument info
+type CoinbaseInstrument struct {
+	Symbol         string  // Instrument symbol (e.g., "BTC-PERP")
+	BaseIncrement  float64 // Size step
+	QuoteIncrement float64 // Price tick
+	MinNotional    float64 // Minimum order notional
+}
+
+// cbDecimal INTX returns decimals as strings, this accepts both strings and numbers
+type cbDecimal float64
+
+func (d *cbDecimal) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), "\"")
+	if s == "" || s == "null" {
+		*d = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*d = cbDecimal(v)
+	return nil
+}
+
+// NewCoinbaseTrader creates Coinbase International Exchange trader
+func NewCoinbaseTrader(apiKey, secretKey, passphrase string) *CoinbaseTrader {
+	return &CoinbaseTrader{
+		apiKey:     apiKey,
+		secretKey:  secretKey,
+		passphrase: passphrase,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: http.DefaultTransport,
+		},
+		cacheDuration:    15 * time.Second,
+		instrumentsCache: make(map[string]*CoinbaseInstrument),
+	}
+}
+
+// sign generates INTX API signature: base64(HMAC-SHA256(secret, timestamp + method + path + body))
+func (t *CoinbaseTrader) sign(timestamp, method, requestPath, body string) string {
+	key, err := base64.StdEncoding.DecodeString(t.secretKey)
+	if err != nil {
+		key = []byte(t.secretKey)
+	}
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(timestamp + method + requestPath + body))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// doRequest executes HTTP request, path includes the query string
+func (t *CoinbaseTrader) doRequest(method, path string, body interface{}) ([]byte, error) {
+	var bodyBytes []byte
+	var err error
+
+	if body != nil {
+		bodyBytes, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize request body: %w", err)
+		}
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := t.sign(timestamp, method, path, string(bodyBytes))
+
+	req, err := http.NewRequest(method, coinbaseBaseURL+path, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("CB-ACCESS-KEY", t.apiKey)
+	req.Header.Set("CB-ACCESS-SIGN", signature)
+	req.Header.Set("CB-ACCESS-TIMESTAMP", timestamp)
+	req.Header.Set("CB-ACCESS-PASSPHRASE", t.passphrase)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		var errResp struct {
+			Title  string `json:"title"`
+			Detail string `json:"detail"`
+			Code   string `json:"code"`
+		}
+		_ = json.Unmarshal(respBody, &errResp)
+		msg := errResp.Title
+		if errResp.Detail != "" {
+			msg += ": " + errResp.Detail
+		}
+		if msg == "" {
+			msg = string(respBody)
+		}
+		return nil, fmt.Errorf("Coinbase API error: status=%d, msg=%s", resp.StatusCode, msg)
+	}
+
+	return respBody, nil
+}
+
+// portfolio returns the trading portfolio ID (default portfolio, resolved on first success)
+// A failed lookup is not cached, the next call retries it
+func (t *CoinbaseTrader) portfolio() (string, error) {
+	t.portfolioMu.Lock()
+	defer t.portfolioMu.Unlock()
+	if t.portfolioID != "" {
+		return t.portfolioID, nil
+	}
+
+	data, err := t.doRequest("GET", coinbasePortfoliosPath, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get portfolios: %w", err)
+	}
+
+	var portfolios []struct {
+		PortfolioID string `json:"portfolio_id"`
+		Name        string `json:"name"`
+		IsDefault   bool   `json:"is_default"`
+	}
+	if err := json.Unmarshal(data, &portfolios); err != nil {
+		return "", fmt.Errorf("failed to parse portfolios: %w", err)
+	}
+	if len(portfolios) == 0 {
+		return "", fmt.Errorf("no portfolio found for this API key")
+	}
+
+	portfolioID := portfolios[0].PortfolioID
+	for _, p := range portfolios {
+		if p.IsDefault {
+			portfolioID = p.PortfolioID
+			break
+		}
+	}
+	t.portfolioID = portfolioID
+	logger.Infof("✓ Coinbase INTX using portfolio %s", t.portfolioID)
+	return t.portfolioID, nil
+}
+
+// convertSymbol converts generic symbol to INTX format
+// e.g. BTCUSDT -> BTC-PERP, ETHUSDC -> ETH-PERP (all INTX perps are USDC-margined)
+func (t *CoinbaseTrader) convertSymbol(symbol string) string {
+	return market.VenueSymbol(market.VenueCoinbase, symbol)
+}
+
+// convertSymbolBack converts INTX format back to generic symbol
+// e.g. BTC-PERP -> BTCUSDT (market data is keyed by USDT symbols)
+func (t *CoinbaseTrader) convertSymbolBack(instrument string) string {
+	return market.CanonicalSymbol(market.VenueCoinbase, instrument)
+}
+
+// genCoinbaseClientOrderID generates a client order ID with the given prefix
+func genCoinbaseClientOrderID(prefix string) string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return fmt.Sprintf("%s%d%s", prefix, time.Now().UnixMilli(), hex.EncodeToString(b))
+}
+
+// GetBalance gets account balance
+func (t *CoinbaseTrader) GetBalance() (map[string]interface{}, error) {
+	// Check cache
+	t.balanceCacheMutex.RLock()
+	if t.cachedBalance != nil && time.Since(t.balanceCacheTime) < t.cacheDuration {
+		t.balanceCacheMutex.RUnlock()
+		logger.Infof("✓ Using cached Coinbase account balance")
+		return t.cachedBalance, nil
+	}
+	t.balanceCacheMutex.RUnlock()
+
+	portfolioID, err := t.portfolio()
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("🔄 Calling Coinbase API to get account balance...")
+	data, err := t.doRequest("GET", coinbasePortfoliosPath+"/"+portfolioID+"/summary", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account balance: %w", err)
+	}
+
+	var summary struct {
+		Collateral             cbDecimal `json:"collateral"`
+		UnrealizedPnl          cbDecimal `json:"unrealized_pnl"`
+		TotalBalance           cbDecimal `json:"total_balance"`
+		BuyingPower            cbDecimal `json:"buying_power"`
+		PortfolioInitialMargin cbDecimal `json:"portfolio_im_notional"`
+	}
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, fmt.Errorf("failed to parse balance data: %w", err)
+	}
+
+	totalEquity := float64(summary.TotalBalance)
+	if totalEquity == 0 {
+		totalEquity = float64(summary.Collateral) + float64(summary.UnrealizedPnl)
+	}
+	available := float64(summary.BuyingPower)
+	if available == 0 {
+		available = math.Max(0, totalEquity-float64(summary.PortfolioInitialMargin))
+	}
+
+	result := map[string]interface{}{
+		"totalWalletBalance":    float64(summary.Collateral),
+		"availableBalance":      available,
+		"totalUnrealizedProfit": float64(summary.UnrealizedPnl),
+		"total_equity":          totalEquity,
+		// Single USDC collateral pool margins every perp, regardless of the symbol's quote
+		"multiAssetsMargin": true,
+	}
+
+	logger.Infof("✓ Coinbase balance: Total equity=%.2f, Available=%.2f, Unrealized PnL=%.2f",
+		totalEquity, available, float64(summary.UnrealizedPnl))
+
+	// Update cache
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = result
+	t.balanceCacheTime = time.Now()
+	t.balanceCacheMutex.Unlock()
+
+	return result, nil
+}
+
+// GetPositions gets all positions
+func (t *CoinbaseTrader) GetPositions() ([]Position, error) {
+	// Check cache
+	t.positionsCacheMutex.RLock()
+	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
+		t.positionsCacheMutex.RUnlock()
+		logger.Infof("✓ Using cached Coinbase positions")
+		return t.cachedPositions, nil
+	}
+	t.positionsCacheMutex.RUnlock()
+
+	portfolioID, err := t.portfolio()
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("🔄 Calling Coinbase API to get positions...")
+	data, err := t.doRequest("GET", coinbasePortfoliosPath+"/"+portfolioID+"/positions", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	var positions []struct {
+		Symbol        string    `json:"symbol"`
+		NetSize       cbDecimal `json:"net_size"` // Signed: positive=long, negative=short
+		EntryVwap     cbDecimal `json:"entry_vwap"`
+		Vwap          cbDecimal `json:"vwap"`
+		MarkPrice     cbDecimal `json:"mark_price"`
+		UnrealizedPnl cbDecimal `json:"unrealized_pnl"`
+		ImContrib     cbDecimal `json:"im_contribution"`
+	}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, fmt.Errorf("failed to parse position data: %w", err)
+	}
+
+	result := make([]Position, 0, len(positions))
+	for _, pos := range positions {
+		size := float64(pos.NetSize)
+		if size == 0 {
+			continue
+		}
+
+		side := "long"
+		if size < 0 {
+			side = "short"
+			size = -size
+		}
+
+		entryPrice := float64(pos.EntryVwap)
+		if entryPrice == 0 {
+			entryPrice = float64(pos.Vwap)
+		}
+
+		// INTX uses portfolio margin: effective leverage = notional / initial margin contribution
+		leverage := 1.0
+		if im := float64(pos.ImContrib); im > 0 {
+			leverage = math.Round(size * float64(pos.MarkPrice) / im)
+		}
+
+		result = append(result, Position{
+			Symbol:        t.convertSymbolBack(pos.Symbol),
+			Side:          side,
+			Quantity:      size,
+			EntryPrice:    entryPrice,
+			MarkPrice:     float64(pos.MarkPrice),
+			UnrealizedPnL: float64(pos.UnrealizedPnl),
+			Leverage:      leverage,
+			MarginUsed:    float64(pos.ImContrib),
+			// Portfolio-level liquidation, no per-position price
+		})
+	}
+
+	// Update cache
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = result
+	t.positionsCacheTime = time.Now()
+	t.positionsCacheMutex.Unlock()
+
+	return result, nil
+}
+
+// invalidateCaches clears balance/position caches after order placement
+func (t *CoinbaseTrader) invalidateCaches() {
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = nil
+	t.balanceCacheMutex.Unlock()
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = nil
+	t.positionsCacheMutex.Unlock()
+}
+
+// getInstrument gets instrument info
+func (t *CoinbaseTrader) getInstrument(symbol string) (*CoinbaseInstrument, error) {
+	instrument := t.convertSymbol(symbol)
+
+	// Check cache (instrument increments rarely change)
+	t.instrumentsCacheMutex.RLock()
+	if inst, ok := t.instrumentsCache[instrument]; ok {
+		t.instrumentsCacheMutex.RUnlock()
+		return inst, nil
+	}
+	t.instrumentsCacheMutex.RUnlock()
+
+	data, err := t.doRequest("GET", coinbaseInstrumentPath+instrument, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var detail struct {
+		Symbol           string    `json:"symbol"`
+		BaseIncrement    cbDecimal `json:"base_increment"`
+		QuoteIncrement   cbDecimal `json:"quote_increment"`
+		MinNotionalValue cbDecimal `json:"min_notional_value"`
+	}
+	if err := json.Unmarshal(data, &detail); err != nil {
+		return nil, err
+	}
+	if detail.Symbol == "" {
+		return nil, fmt.Errorf("instrument info not found: %s", instrument)
+	}
+
+	inst := &CoinbaseInstrument{
+		Symbol:         detail.Symbol,
+		BaseIncrement:  float64(detail.BaseIncrement),
+		QuoteIncrement: float64(detail.QuoteIncrement),
+		MinNotional:    float64(detail.MinNotionalValue),
+	}
+
+	t.instrumentsCacheMutex.Lock()
+	t.instrumentsCache[instrument] = inst
+	t.instrumentsCacheMutex.Unlock()
+
+	return inst, nil
+}
+
+// formatIncrement rounds value down to the given increment and formats it with matching precision
+func formatIncrement(value, increment float64) string {
+	if increment <= 0 {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	steps := math.Floor(value/increment + 1e-9)
+	decimals := 0
+	if s := strconv.FormatFloat(increment, 'f', -1, 64); strings.Contains(s, ".") {
+		decimals = len(s) - strings.Index(s, ".") - 1
+	}
+	return strconv.FormatFloat(steps*increment, 'f', decimals, 64)
+}
+
+// SetMarginMode sets margin mode (INTX uses portfolio cross margin only)
+func (t *CoinbaseTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	if !isCrossMargin {
+		logger.Infof("  ⚠️ Coinbase INTX only supports portfolio cross margin, %s stays on cross", symbol)
+	}
+	return nil
+}
+
+// SetLeverage sets leverage
+// INTX margins positions at the portfolio level, so leverage is bounded by collateral rather than set per symbol
+func (t *CoinbaseTrader) SetLeverage(symbol string, leverage int) error {
+	logger.Infof("  ✓ %s leverage %dx (Coinbase INTX portfolio margin, sized by collateral)", symbol, leverage)
+	return nil
+}
+
+// placeOrder submits an order, quantity is in base asset
+func (t *CoinbaseTrader) placeOrder(symbol, side, orderType string, quantity, price, stopPrice float64, closeOnly bool, clientPrefix string) (map[string]interface{}, error) {
+	portfolioID, err := t.portfolio()
+	if err != nil {
+		return nil, err
+	}
+	inst, err := t.getInstrument(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instrument info: %w", err)
+	}
+
+	size := formatIncrement(quantity, inst.BaseIncrement)
+	if v, _ := strconv.ParseFloat(size, 64); v <= 0 {
+		return nil, fmt.Errorf("order size %.8f is below size increment %g", quantity, inst.BaseIncrement)
+	}
+
+	body := map[string]interface{}{
+		"client_order_id": genCoinbaseClientOrderID(clientPrefix),
+		"side":            side,
+		"size":            size,
+		"type":            orderType,
+		"instrument":      inst.Symbol,
+		"portfolio":       portfolioID,
+	}
+	switch orderType {
+	case "MARKET":
+		body["tif"] = "IOC"
+	case "LIMIT":
+		body["tif"] = "GTC"
+		body["price"] = formatIncrement(price, inst.QuoteIncrement)
+	case "STOP":
+		body["tif"] = "GTC"
+		body["stop_price"] = formatIncrement(stopPrice, inst.QuoteIncrement)
+	}
+	if closeOnly {
+		body["close_only"] = true
+	}
+
+	data, err := t.doRequest("POST", coinbaseOrdersPath, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var order struct {
+		OrderID     string    `json:"order_id"`
+		OrderStatus string    `json:"order_status"`
+		ExecQty     cbDecimal `json:"exec_qty"`
+	}
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, fmt.Errorf("failed to parse order response: %w", err)
+	}
+	if order.OrderID == "" {
+		return nil, fmt.Errorf("empty order ID in response: %s", string(data))
+	}
+
+	t.invalidateCaches()
+
+	return map[string]interface{}{
+		"orderId": order.OrderID,
+		"symbol":  symbol,
+		"status":  coinbaseOrderStatus(order.OrderStatus, float64(order.ExecQty)),
+	}, nil
+}
+
+// coinbaseOrderStatus maps an INTX order status to the common NEW/PARTIALLY_FILLED/FILLED/CANCELED/EXPIRED/REJECTED
+// A DONE order with nothing executed was cancelled
+func coinbaseOrderStatus(orderStatus string, execQty float64) string {
+	switch strings.ToUpper(orderStatus) {
+	case "DONE", "FILLED":
+		if execQty == 0 {
+			return "CANCELED"
+		}
+		return "FILLED"
+	case "WORKING", "OPEN":
+		if execQty > 0 {
+			return "PARTIALLY_FILLED"
+		}
+	case "CANCELLED", "CANCELED":
+		return "CANCELED"
+	case "EXPIRED":
+		return "EXPIRED"
+	case "REJECTED":
+		return "REJECTED"
+	}
+	return "NEW"
+}
+
+// OpenLong opens long position
+func (t *CoinbaseTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// Cancel old orders
+	t.CancelAllOrders(symbol)
+
+	result, err := t.placeOrder(symbol, "BUY", "MARKET", quantity, 0, 0, false, coinbaseOrderPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open long position: %w", err)
+	}
+
+	logger.Infof("✓ Coinbase opened long position successfully: %s quantity: %.6f", symbol, quantity)
+	logger.Infof("  Order ID: %v", result["orderId"])
+	return result, nil
+}
+
+// OpenShort opens short position
+func (t *CoinbaseTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// Cancel old orders
+	t.CancelAllOrders(symbol)
+
+	result, err := t.placeOrder(symbol, "SELL", "MARKET", quantity, 0, 0, false, coinbaseOrderPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open short position: %w", err)
+	}
+
+	logger.Infof("✓ Coinbase opened short position successfully: %s quantity: %.6f", symbol, quantity)
+	logger.Infof("  Order ID: %v", result["orderId"])
+	return result, nil
+}
+
+// positionQuantity returns the current position size (base asset) for symbol/side
+func (t *CoinbaseTrader) positionQuantity(symbol, side string) (float64, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, err
+	}
+	for _, pos := range positions {
+		if pos.Symbol == symbol && pos.Side == side {
+			return pos.Quantity, nil
+		}
+	}
+	return 0, fmt.Errorf("%s position not found for %s", side, symbol)
+}
+
+// CloseLong closes long position
+func (t *CoinbaseTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	// If quantity is 0, close entire position
+	if quantity == 0 {
+		var err error
+		if quantity, err = t.positionQuantity(symbol, "long"); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := t.placeOrder(symbol, "SELL", "MARKET", quantity, 0, 0, true, coinbaseOrderPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close long position: %w", err)
+	}
+
+	logger.Infof("✓ Coinbase closed long position successfully: %s quantity: %.6f", symbol, quantity)
+
+	// Cancel pending orders after closing position
+	t.CancelAllOrders(symbol)
+	return result, nil
+}
+
+// CloseShort closes short position
+func (t *CoinbaseTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	// If quantity is 0, close entire position
+	if quantity == 0 {
+		var err error
+		if quantity, err = t.positionQuantity(symbol, "short"); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := t.placeOrder(symbol, "BUY", "MARKET", quantity, 0, 0, true, coinbaseOrderPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close short position: %w", err)
+	}
+
+	logger.Infof("✓ Coinbase closed short position successfully: %s quantity: %.6f", symbol, quantity)
+
+	// Cancel pending orders after closing position
+	t.CancelAllOrders(symbol)
+	return result, nil
+}
+
+// GetMarketPrice gets market price
+func (t *CoinbaseTrader) GetMarketPrice(symbol string) (float64, error) {
+	data, err := t.doRequest("GET", coinbaseInstrumentPath+t.convertSymbol(symbol)+"/quote", nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get price: %w", err)
+	}
+
+	var quote struct {
+		TradePrice cbDecimal `json:"trade_price"`
+		MarkPrice  cbDecimal `json:"mark_price"`
+	}
+	if err := json.Unmarshal(data, &quote); err != nil {
+		return 0, err
+	}
+
+	price := float64(quote.TradePrice)
+	if price <= 0 {
+		price = float64(quote.MarkPrice)
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("no price data received")
+	}
+	return price, nil
+}
+
+// SetStopLoss sets stop loss order (stop-market, close only)
+func (t *CoinbaseTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	side := "SELL"
+	if strings.ToUpper(positionSide) == "SHORT" {
+		side = "BUY"
+	}
+
+	if _, err := t.placeOrder(symbol, side, "STOP", quantity, 0, stopPrice, true, coinbaseSLPrefix); err != nil {
+		return fmt.Errorf("failed to set stop loss: %w", err)
+	}
+
+	logger.Infof("  Stop loss price set: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit sets take profit order (resting limit, close only)
+func (t *CoinbaseTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	side := "SELL"
+	if strings.ToUpper(positionSide) == "SHORT" {
+		side = "BUY"
+	}
+
+	if _, err := t.placeOrder(symbol, side, "LIMIT", quantity, takeProfitPrice, 0, true, coinbaseTPPrefix); err != nil {
+		return fmt.Errorf("failed to set take profit: %w", err)
+	}
+
+	logger.Infof("  Take profit price set: %.4f", takeProfitPrice)
+	return nil
+}
+
+// CancelStopLossOrders cancels stop loss orders
+func (t *CoinbaseTrader) CancelStopLossOrders(symbol string) error {
+	return t.cancelOrdersByPrefix(symbol, coinbaseSLPrefix)
+}
+
+// CancelTakeProfitOrders cancels take profit orders
+func (t *CoinbaseTrader) CancelTakeProfitOrders(symbol string) error {
+	return t.cancelOrdersByPrefix(symbol, coinbaseTPPrefix)
+}
+
+// CancelStopOrders cancels stop loss and take profit orders
+func (t *CoinbaseTrader) CancelStopOrders(symbol string) error {
+	if err := t.cancelOrdersByPrefix(symbol, coinbaseSLPrefix); err != nil {
+		return err
+	}
+	return t.cancelOrdersByPrefix(symbol, coinbaseTPPrefix)
+}
+
+// cancelOrdersByPrefix cancels open orders whose client order ID starts with prefix
+func (t *CoinbaseTrader) cancelOrdersByPrefix(symbol, prefix string) error {
+	portfolioID, err := t.portfolio()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{
+		"portfolio":  {portfolioID},
+		"instrument": {t.convertSymbol(symbol)},
+	}
+	data, err := t.doRequest("GET", coinbaseOrdersPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Results []struct {
+			OrderID       string `json:"order_id"`
+			ClientOrderID string `json:"client_order_id"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return err
+	}
+
+	canceledCount := 0
+	for _, order := range resp.Results {
+		if !strings.HasPrefix(order.ClientOrderID, prefix) {
+			continue
+		}
+		path := fmt.Sprintf("%s/%s?portfolio=%s", coinbaseOrdersPath, order.OrderID, url.QueryEscape(portfolioID))
+		if _, err := t.doRequest("DELETE", path, nil); err != nil {
+			logger.Infof("  ⚠️ Failed to cancel order %s: %v", order.OrderID, err)
+			continue
+		}
+		canceledCount++
+	}
+
+	if canceledCount > 0 {
+		logger.Infof("  ✓ Canceled %d orders for %s", canceledCount, symbol)
+	}
+	return nil
+}
+
+// CancelAllOrders cancels all pending orders
+func (t *CoinbaseTrader) CancelAllOrders(symbol string) error {
+	portfolioID, err := t.portfolio()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{
+		"portfolio":  {portfolioID},
+		"instrument": {t.convertSymbol(symbol)},
+	}
+	if _, err := t.doRequest("DELETE", coinbaseOrdersPath+"?"+query.Encode(), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FormatQuantity formats quantity to the instrument's size increment
+func (t *CoinbaseTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	inst, err := t.getInstrument(symbol)
+	if err != nil {
+		return fmt.Sprintf("%.4f", quantity), nil
+	}
+	return formatIncrement(quantity, inst.BaseIncrement), nil
+}
+
+// GetOrderStatus gets order status
+// INTX order details don't include fees, commission is left out so the registered fee schedule is used
+func (t *CoinbaseTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	portfolioID, err := t.portfolio()
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("%s/%s?portfolio=%s", coinbaseOrdersPath, orderID, url.QueryEscape(portfolioID))
+	data, err := t.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order status: %w", err)
+	}
+
+	var order struct {
+		OrderID     string    `json:"order_id"`
+		Side        string    `json:"side"`
+		Type        string    `json:"type"`
+		AvgPrice    cbDecimal `json:"avg_price"`
+		ExecQty     cbDecimal `json:"exec_qty"`
+		LeavesQty   cbDecimal `json:"leaves_qty"`
+		OrderStatus string    `json:"order_status"`
+	}
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, err
+	}
+
+	status := coinbaseOrderStatus(order.OrderStatus, float64(order.ExecQty))
+
+	return map[string]interface{}{
+		"orderId":     order.OrderID,
+		"symbol":      symbol,
+		"status":      status,
+		"avgPrice":    float64(order.AvgPrice),
+		"executedQty": float64(order.ExecQty),
+		"side":        order.Side,
+		"type":        order.Type,
+	}, nil
+}
+
+// GetClosedPnL retrieves closed position PnL records
+// INTX fills carry no realized PnL and there is no position history API, so no records are returned;
+// position sync falls back to market price for closures (same as other trade-level exchanges)
+func (t *CoinbaseTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	return []ClosedPnLRecord{}, nil
+}
@@ -0,0 +1,121 @@
+package trader
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestCoinbaseSymbolConversion tests generic <-> INTX instrument format
+func TestCoinbaseSymbolConversion(t *testing.T) {
+	tr := &CoinbaseTrader{}
+	tests := []struct {
+		symbol     string
+		instrument string
+		back       string
+	}{
+		{"BTCUSDT", "BTC-PERP", "BTCUSDT"},
+		{"ETHUSDC", "ETH-PERP", "ETHUSDT"}, // Single USDC-margined perp per base asset
+		{"SOLUSDT", "SOL-PERP", "SOLUSDT"},
+	}
+	for _, tt := range tests {
+		if got := tr.convertSymbol(tt.symbol); got != tt.instrument {
+			t.Errorf("convertSymbol(%s): expected %s, got %s", tt.symbol, tt.instrument, got)
+		}
+		if got := tr.convertSymbolBack(tt.instrument); got != tt.back {
+			t.Errorf("convertSymbolBack(%s): expected %s, got %s", tt.instrument, tt.back, got)
+		}
+	}
+}
+
+// TestFormatIncrement tests rounding down to size/price increments
+func TestFormatIncrement(t *testing.T) {
+	tests := []struct {
+		value     float64
+		increment float64
+		want      string
+	}{
+		{0.01239, 0.0001, "0.0123"},
+		{64123.47, 0.1, "64123.4"},
+		{15, 1, "15"},
+		{0.3, 0.1, "0.3"},
+		{1.5, 0, "1.5"},
+	}
+	for _, tt := range tests {
+		if got := formatIncrement(tt.value, tt.increment); got != tt.want {
+			t.Errorf("formatIncrement(%v, %v): expected %s, got %s", tt.value, tt.increment, tt.want, got)
+		}
+	}
+}
+
+// TestCbDecimal tests decoding of string and numeric decimals
+func TestCbDecimal(t *testing.T) {
+	var v struct {
+		A cbDecimal `json:"a"`
+		B cbDecimal `json:"b"`
+		C cbDecimal `json:"c"`
+	}
+	if err := json.Unmarshal([]byte(`{"a":"-0.25","b":12.5,"c":""}`), &v); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if v.A != -0.25 || v.B != 12.5 || v.C != 0 {
+		t.Errorf("unexpected values: %+v", v)
+	}
+}
+
+// coinbaseRoundTrip answers INTX requests from a function instead of the network
+type coinbaseRoundTrip func(req *http.Request) (int, string)
+
+func (f coinbaseRoundTrip) RoundTrip(req *http.Request) (*http.Response, error) {
+	code, body := f(req)
+	return &http.Response{StatusCode: code, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}, Request: req}, nil
+}
+
+// TestCoinbasePortfolioRetry tests that a failed portfolio lookup is retried and a resolved one is cached
+func TestCoinbasePortfolioRetry(t *testing.T) {
+	calls := 0
+	tr := NewCoinbaseTrader("key", "secret", "pass")
+	tr.httpClient.Transport = coinbaseRoundTrip(func(req *http.Request) (int, string) {
+		calls++
+		if calls == 1 {
+			return http.StatusServiceUnavailable, `{"title":"unavailable"}`
+		}
+		return http.StatusOK, `[{"portfolio_id":"p1"},{"portfolio_id":"p2","is_default":true}]`
+	})
+
+	if _, err := tr.portfolio(); err == nil {
+		t.Fatal("expected the first lookup to fail")
+	}
+	for i := 0; i < 2; i++ {
+		if id, err := tr.portfolio(); err != nil || id != "p2" {
+			t.Fatalf("expected the default portfolio after a retry, got %q (%v)", id, err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected the resolved portfolio cached, got %d requests", calls)
+	}
+}
+
+// TestCoinbaseOrderStatus tests mapping INTX order statuses
+func TestCoinbaseOrderStatus(t *testing.T) {
+	tests := []struct {
+		status  string
+		execQty float64
+		want    string
+	}{
+		{"DONE", 0.5, "FILLED"},
+		{"DONE", 0, "CANCELED"},
+		{"WORKING", 0, "NEW"},
+		{"WORKING", 0.2, "PARTIALLY_FILLED"},
+		{"REJECTED", 0, "REJECTED"},
+		{"EXPIRED", 0, "EXPIRED"},
+		{"", 0, "NEW"},
+	}
+	for _, tt := range tests {
+		if got := coinbaseOrderStatus(tt.status, tt.execQty); got != tt.want {
+			t.Errorf("coinbaseOrderStatus(%s, %v): expected %s, got %s", tt.status, tt.execQty, tt.want, got)
+		}
+	}
+}
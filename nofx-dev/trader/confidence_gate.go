@@ -0,0 +1,56 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/store"
+	"sync"
+)
+
+// confidenceGateState per-trader confidence gate
+type confidenceGateState struct {
+	mu   sync.RWMutex
+	gate store.ConfidenceGate
+}
+
+// ValidateConfidenceGate checks the confidence bounds of a confidence gate
+func ValidateConfidenceGate(gate store.ConfidenceGate) error {
+	if gate.MinConfidence < 0 || gate.MinConfidence > 100 {
+		return fmt.Errorf("min confidence must be between 0 and 100: %d", gate.MinConfidence)
+	}
+	if gate.FullSizeConfidence < 0 || gate.FullSizeConfidence > 100 {
+		return fmt.Errorf("full size confidence must be between 0 and 100: %d", gate.FullSizeConfidence)
+	}
+	if gate.ScaleSize && gate.FullSizeConfidence > 0 && gate.FullSizeConfidence < gate.MinConfidence {
+		return fmt.Errorf("full size confidence %d is below the min confidence %d", gate.FullSizeConfidence, gate.MinConfidence)
+	}
+	return nil
+}
+
+// SetConfidenceGate updates the trader's confidence gate
+func (at *AutoTrader) SetConfidenceGate(gate store.ConfidenceGate) error {
+	if err := ValidateConfidenceGate(gate); err != nil {
+		return err
+	}
+	at.confidence.mu.Lock()
+	at.confidence.gate = gate
+	at.confidence.mu.Unlock()
+	return nil
+}
+
+// applyConfidenceGate ignores low-confidence entries and scales entry sizes by confidence, recording the stated
+// confidence of every action on the decision record
+func (at *AutoTrader) applyConfidenceGate(decisions []decision.Decision, record *store.DecisionRecord) []decision.Decision {
+	at.confidence.mu.RLock()
+	gate := at.confidence.gate
+	at.confidence.mu.RUnlock()
+
+	kept, confidences, notes := decision.ApplyConfidenceGate(decisions, gate)
+	record.Confidences = confidences
+	for _, note := range notes {
+		logger.Infof("  %s", note)
+	}
+	record.ExecutionLog = append(record.ExecutionLog, notes...)
+	return kept
+}
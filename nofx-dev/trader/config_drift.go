@@ -0,0 +1,170 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"nofx/config"
+	"nofx/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config drift event types
+const (
+	EventConfigDrift          = "config_drift"
+	EventConfigDriftCorrected = "config_drift_corrected"
+)
+
+// driftState events already raised per "SYMBOL side" drift, so a persisting drift is reported once
+type driftState struct {
+	mu      sync.Mutex
+	alerted map[string]string
+}
+
+// driftCheckInterval time between drift checks (0 = disabled)
+func driftCheckInterval() time.Duration {
+	return config.Get().DriftCheck.Interval
+}
+
+// marginModeName configured margin mode as reported by exchanges
+func marginModeName(isCrossMargin bool) string {
+	if isCrossMargin {
+		return "cross"
+	}
+	return "isolated"
+}
+
+// positionDrift compares a position's exchange-side settings with the expected ones
+// expectedLeverage 0 or an unreported mode skips that comparison
+func positionDrift(pos Position, expectedLeverage int, expectedMode string) (leverageDrift, modeDrift bool) {
+	if expectedLeverage > 0 && pos.Leverage > 0 {
+		leverageDrift = int(math.Round(pos.Leverage)) != expectedLeverage
+	}
+	if pos.MarginMode != "" {
+		modeDrift = !strings.EqualFold(pos.MarginMode, expectedMode)
+	}
+	return leverageDrift, modeDrift
+}
+
+// checkConfigDrift verifies leverage and margin mode of every managed position against the trader's settings
+// Leverage is the one the position was opened with (local record); drift is set back when auto-correct is on,
+// margin mode can't be switched while a position is open so it's only alerted
+func (at *AutoTrader) checkConfigDrift() {
+	if at.store == nil {
+		return
+	}
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Infof("❌ Drift check: failed to get positions: %v", err)
+		return
+	}
+
+	autoCorrect := config.Get().DriftCheck.AutoCorrect
+	expectedMode := marginModeName(at.config.IsCrossMargin)
+	drifting := make(map[string]bool)
+	for _, pos := range positions {
+		// Fallback exchange positions are not managed by this trader
+		if pos.ReadOnly || pos.Exchange != "" {
+			continue
+		}
+		// Only positions this trader opened (other traders may share the account)
+		record, err := at.store.Position().GetOpenPositionBySymbol(at.id, pos.Symbol, strings.ToUpper(pos.Side))
+		if err != nil || record == nil {
+			continue
+		}
+
+		key := pos.Symbol + " " + pos.Side
+		leverageDrift, modeDrift := positionDrift(pos, record.Leverage, expectedMode)
+
+		if leverageDrift {
+			message := fmt.Sprintf("%s leverage on exchange is %.0fx, trader uses %dx", key, pos.Leverage, record.Leverage)
+			drifting[key+" leverage"] = true
+			if autoCorrect {
+				if err := at.trader.SetLeverage(pos.Symbol, record.Leverage); err != nil {
+					message += fmt.Sprintf(", correction failed: %v", err)
+				} else if leverage, err := at.positionLeverage(pos.Symbol, pos.Side); err != nil {
+					message += fmt.Sprintf(", correction not verified: %v", err)
+				} else if int(math.Round(leverage)) != record.Leverage {
+					message += fmt.Sprintf(", correction didn't take effect (%.0fx after setting %dx)", leverage, record.Leverage)
+				} else {
+					// Kept as drifting: an exchange reverting the same change every check reports it once
+					at.raiseDriftOnce(EventConfigDriftCorrected, key+" leverage", message+fmt.Sprintf(", set back to %dx", record.Leverage))
+					continue
+				}
+			}
+			at.alertDrift(key+" leverage", message)
+		}
+
+		if modeDrift {
+			drifting[key+" margin"] = true
+			at.alertDrift(key+" margin", fmt.Sprintf("%s margin mode on exchange is %s, trader is configured for %s (can't be changed while the position is open)",
+				key, strings.ToLower(pos.MarginMode), expectedMode))
+		}
+	}
+
+	// Forget resolved drifts so a recurrence alerts again
+	at.drift.mu.Lock()
+	for alertKey := range at.drift.alerted {
+		if !drifting[alertKey] {
+			delete(at.drift.alerted, alertKey)
+		}
+	}
+	at.drift.mu.Unlock()
+}
+
+// alertDrift logs and raises a drift event unless the same drift was already reported
+func (at *AutoTrader) alertDrift(key, message string) {
+	at.raiseDriftOnce(EventConfigDrift, key, message)
+}
+
+// raiseDriftOnce logs and raises a drift event unless the same event was already reported for the key
+func (at *AutoTrader) raiseDriftOnce(eventType, key, message string) {
+	at.drift.mu.Lock()
+	if at.drift.alerted[key] == eventType+" "+message {
+		at.drift.mu.Unlock()
+		return
+	}
+	if at.drift.alerted == nil {
+		at.drift.alerted = make(map[string]string)
+	}
+	at.drift.alerted[key] = eventType + " " + message
+	at.drift.mu.Unlock()
+
+	if eventType == EventConfigDriftCorrected {
+		logger.Infof("🔧 [%s] Drift corrected: %s", at.name, message)
+	} else {
+		logger.Warnf("⚠️ [%s] Config drift: %s", at.name, message)
+	}
+	at.raiseDriftEvent(eventType, message)
+}
+
+// positionLeverage re-reads a position's leverage from the exchange after a correction
+func (at *AutoTrader) positionLeverage(symbol, side string) (float64, error) {
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		return 0, err
+	}
+	for _, pos := range positions {
+		if pos.Symbol == symbol && pos.Side == side && !pos.ReadOnly && pos.Exchange == "" {
+			return pos.Leverage, nil
+		}
+	}
+	return 0, fmt.Errorf("position no longer open")
+}
+
+// raiseDriftEvent sends a drift event to the event callback and the webhook
+func (at *AutoTrader) raiseDriftEvent(eventType, message string) {
+	event := TraderEvent{
+		TraderID:   at.id,
+		Type:       eventType,
+		Exchange:   at.exchange,
+		ExchangeID: at.exchangeID,
+		Message:    message,
+		Time:       time.Now(),
+	}
+	if at.config.OnEvent != nil {
+		at.config.OnEvent(event)
+	}
+	at.notifyWebhookEvent(event)
+}
@@ -0,0 +1,98 @@
+package trader
+
+import (
+	"nofx/store"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// leverageTrader Trader whose leverage changes take effect only if sticks is set
+type leverageTrader struct {
+	Trader
+	positions []Position
+	sticks    bool
+}
+
+func (l *leverageTrader) GetPositions() ([]Position, error) {
+	return l.positions, nil
+}
+
+func (l *leverageTrader) SetLeverage(symbol string, leverage int) error {
+	if l.sticks {
+		for i := range l.positions {
+			if l.positions[i].Symbol == symbol {
+				l.positions[i].Leverage = float64(leverage)
+			}
+		}
+	}
+	return nil
+}
+
+// TestPositionDrift tests leverage / margin mode drift detection
+func TestPositionDrift(t *testing.T) {
+	tests := []struct {
+		name         string
+		pos          Position
+		leverage     int
+		mode         string
+		wantLeverage bool
+		wantMode     bool
+	}{
+		{"in sync", Position{Leverage: 10, MarginMode: "cross"}, 10, "cross", false, false},
+		{"leverage changed", Position{Leverage: 20, MarginMode: "cross"}, 10, "cross", true, false},
+		{"margin mode changed", Position{Leverage: 10, MarginMode: "isolated"}, 10, "cross", false, true},
+		{"mode case insensitive", Position{Leverage: 5, MarginMode: "CROSS"}, 5, "cross", false, false},
+		{"mode not reported", Position{Leverage: 5}, 5, "isolated", false, false},
+		{"no local leverage", Position{Leverage: 5}, 0, "cross", false, false},
+	}
+	for _, tt := range tests {
+		leverageDrift, modeDrift := positionDrift(tt.pos, tt.leverage, tt.mode)
+		if leverageDrift != tt.wantLeverage || modeDrift != tt.wantMode {
+			t.Errorf("%s: got leverage=%v mode=%v, want %v/%v", tt.name, leverageDrift, modeDrift, tt.wantLeverage, tt.wantMode)
+		}
+	}
+}
+
+// TestCheckConfigDriftCorrection tests a leverage correction is verified against the exchange and reported once
+func TestCheckConfigDriftCorrection(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer st.Close()
+	if err := st.Position().Create(&store.TraderPosition{
+		TraderID: "t1", Symbol: "BTCUSDT", Side: "LONG", Quantity: 1, EntryPrice: 100, EntryTime: time.Now(), Leverage: 10,
+	}); err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	var events []string
+	ex := &leverageTrader{positions: []Position{{Symbol: "BTCUSDT", Side: "long", Leverage: 20}}}
+	at := &AutoTrader{id: "t1", trader: ex, store: st, config: AutoTraderConfig{
+		OnEvent: func(e TraderEvent) { events = append(events, e.Type) },
+	}}
+
+	// The exchange ignores the change: alerted as drift, once
+	at.checkConfigDrift()
+	at.checkConfigDrift()
+	if len(events) != 1 || events[0] != EventConfigDrift {
+		t.Fatalf("expected a single drift alert for an ineffective correction, got %v", events)
+	}
+
+	// The change takes effect: reported as corrected
+	ex.sticks = true
+	at.checkConfigDrift()
+	if len(events) != 2 || events[1] != EventConfigDriftCorrected {
+		t.Fatalf("expected a corrected event, got %v", events)
+	}
+
+	// Something reverts the leverage to the same value every check: the correction is reported once
+	for i := 0; i < 2; i++ {
+		ex.positions[0].Leverage = 20
+		at.checkConfigDrift()
+	}
+	if len(events) != 2 {
+		t.Errorf("expected the repeated correction reported once, got %v", events)
+	}
+}
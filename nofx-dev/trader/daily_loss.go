@@ -0,0 +1,292 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dailyLossCheckInterval how often today's loss is checked against the limit
+const dailyLossCheckInterval = 30 * time.Second
+
+// EventDailyLossLimit the daily loss kill-switch paused the trader
+const EventDailyLossLimit = "daily_loss_limit"
+
+// dailyLossState today's starting equity and the kill-switch pause
+type dailyLossState struct {
+	mu              sync.Mutex
+	day             string  // UTC date startEquity belongs to
+	startEquity     float64 // Equity at the start of the UTC day
+	startUnrealized float64 // Unrealized P&L at the start of the UTC day
+	pausedUntil     time.Time
+}
+
+// nextUTCDay returns the start of the UTC day after now
+func nextUTCDay(now time.Time) time.Time {
+	y, m, d := now.UTC().Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+}
+
+// dailyLossPct loss since the day start in % of starting equity (negative when in profit)
+func dailyLossPct(startEquity, pnl float64) float64 {
+	if startEquity <= 0 {
+		return 0
+	}
+	return -pnl / startEquity * 100
+}
+
+// dailyLossPaused reports whether the kill-switch paused the trader, and until when
+func (at *AutoTrader) dailyLossPaused(now time.Time) (bool, time.Time) {
+	at.dailyLoss.mu.Lock()
+	defer at.dailyLoss.mu.Unlock()
+	return now.Before(at.dailyLoss.pausedUntil), at.dailyLoss.pausedUntil
+}
+
+// restoreDailyLossPause resumes a kill-switch pause recorded before a restart
+func (at *AutoTrader) restoreDailyLossPause() {
+	if at.store == nil {
+		return
+	}
+	until, err := at.store.Trader().GetDailyLossPause(at.id)
+	if err != nil {
+		logger.Infof("⚠️ Failed to load daily loss pause: %v", err)
+		return
+	}
+	if !time.Now().Before(until) {
+		return
+	}
+	at.dailyLoss.mu.Lock()
+	at.dailyLoss.pausedUntil = until
+	at.dailyLoss.mu.Unlock()
+	logger.Warnf("🛑 [%s] Kill-switch pause restored, entries blocked until %s", at.name, until.Format(time.RFC3339))
+}
+
+// utcDayStart returns the start of now's UTC day
+func utcDayStart(now time.Time) time.Time {
+	y, m, d := now.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// dayStartEquity returns equity and unrealized P&L of the first equity snapshot of the UTC day (survives restarts),
+// the current values if none
+func (at *AutoTrader) dayStartEquity(now time.Time, equity, unrealized float64) (float64, float64) {
+	if at.store == nil {
+		return equity, unrealized
+	}
+	snapshots, err := at.store.Equity().GetByTimeRange(at.id, utcDayStart(now), now.UTC())
+	if err != nil || len(snapshots) == 0 || snapshots[0].TotalEquity <= 0 {
+		return equity, unrealized
+	}
+	return snapshots[0].TotalEquity, snapshots[0].UnrealizedPnL
+}
+
+// dayPnL trading P&L since the UTC day start: realized P&L of positions closed today plus the change in unrealized
+// P&L, so transfers and withdrawals don't count as loss. Without a store only the equity change is known
+func (at *AutoTrader) dayPnL(now time.Time, equity, unrealized float64) (float64, error) {
+	if at.store == nil {
+		return equity - at.dailyLoss.startEquity, nil
+	}
+	realized, err := at.store.Position().GetRealizedPnLSince(at.id, utcDayStart(now))
+	if err != nil {
+		return 0, err
+	}
+	return realized + unrealized - at.dailyLoss.startUnrealized, nil
+}
+
+// checkDailyLoss trips the kill-switch once realized + unrealized loss since the UTC day start reaches
+// max_daily_loss_pct: optionally flattens positions (cancelling their orders) and pauses until the next UTC day
+func (at *AutoTrader) checkDailyLoss() {
+	if at.config.StrategyConfig == nil {
+		return
+	}
+	rc := at.config.StrategyConfig.RiskControl
+	if rc.MaxDailyLossPct <= 0 {
+		return
+	}
+
+	now := time.Now()
+	if paused, _ := at.dailyLossPaused(now); paused {
+		// Positions that failed to close when the switch tripped (or were opened manually since) are retried
+		if rc.DailyLossFlatten {
+			if closed := at.flattenAll("Kill-switch"); len(closed) > 0 {
+				logger.Warnf("🛑 [%s] Kill-switch: closed %s", at.name, strings.Join(closed, ", "))
+			}
+		}
+		return
+	}
+
+	balance, err := at.trader.GetBalance()
+	if err != nil {
+		logger.Infof("❌ Daily loss check: failed to get balance: %v", err)
+		return
+	}
+	wallet, _ := balance["totalWalletBalance"].(float64)
+	unrealized, _ := balance["totalUnrealizedProfit"].(float64)
+	equity := wallet + unrealized
+	if equity <= 0 {
+		return
+	}
+
+	day := now.UTC().Format("2006-01-02")
+	at.dailyLoss.mu.Lock()
+	if at.dailyLoss.day != day {
+		at.dailyLoss.mu.Unlock()
+		startEquity, startUnrealized := at.dayStartEquity(now, equity, unrealized)
+		at.dailyLoss.mu.Lock()
+		at.dailyLoss.day = day
+		at.dailyLoss.startEquity = startEquity
+		at.dailyLoss.startUnrealized = startUnrealized
+	}
+	startEquity := at.dailyLoss.startEquity
+	pnl, err := at.dayPnL(now, equity, unrealized)
+	if err != nil {
+		at.dailyLoss.mu.Unlock()
+		logger.Infof("❌ Daily loss check: %v", err)
+		return
+	}
+	lossPct := dailyLossPct(startEquity, pnl)
+	if lossPct < rc.MaxDailyLossPct {
+		at.dailyLoss.mu.Unlock()
+		return
+	}
+	at.dailyLoss.pausedUntil = nextUTCDay(now)
+	pausedUntil := at.dailyLoss.pausedUntil
+	at.dailyLoss.mu.Unlock()
+
+	if at.store != nil {
+		if err := at.store.Trader().SetDailyLossPause(at.id, pausedUntil); err != nil {
+			logger.Infof("⚠️ Failed to save daily loss pause: %v", err)
+		}
+	}
+
+	message := fmt.Sprintf("daily loss %.2f%% (PnL %+.2f on day start equity %.2f) reached the %.2f%% limit, paused until %s",
+		lossPct, pnl, startEquity, rc.MaxDailyLossPct, pausedUntil.Format(time.RFC3339))
+	logger.Warnf("🛑 [%s] Kill-switch: %s", at.name, message)
+
+	if rc.DailyLossFlatten {
+		if closed := at.flattenAll("Kill-switch"); len(closed) > 0 {
+			message += fmt.Sprintf(", closed %s", strings.Join(closed, ", "))
+		}
+	}
+	if cancelled := at.cancelOrdersWithoutPosition("Kill-switch"); len(cancelled) > 0 {
+		message += fmt.Sprintf(", cancelled orders of %s", strings.Join(cancelled, ", "))
+	}
+
+	event := TraderEvent{
+		TraderID:   at.id,
+		Type:       EventDailyLossLimit,
+		Exchange:   at.exchange,
+		ExchangeID: at.exchangeID,
+		Message:    message,
+		Time:       now,
+	}
+	if at.config.OnEvent != nil {
+		at.config.OnEvent(event)
+	}
+	at.notifyWebhookEvent(event)
+}
+
+// flattenAll closes every managed position and cancels its open orders, returns "SYMBOL side" of closed positions
+// Positions that fail to close are logged and left for the caller's next check
+func (at *AutoTrader) flattenAll(reason string) []string {
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Infof("❌ %s: failed to get positions: %v", reason, err)
+		return nil
+	}
+
+	var closed []string
+	for _, pos := range positions {
+		// Exchange circuit open: orders can't be sent
+		if pos.ReadOnly || pos.Quantity <= 0 {
+			continue
+		}
+		if err := at.emergencyClosePosition(pos.Symbol, pos.Side); err != nil {
+			logger.Infof("❌ %s: failed to close %s %s: %v", reason, pos.Symbol, pos.Side, err)
+			continue
+		}
+		if err := at.trader.CancelAllOrders(pos.Symbol); err != nil {
+			logger.Infof("  ⚠ %s: failed to cancel %s orders: %v", reason, pos.Symbol, err)
+		}
+		at.ClearPeakPnLCache(pos.Symbol, pos.Side)
+		closed = append(closed, pos.Symbol+" "+pos.Side)
+	}
+	return closed
+}
+
+// cancelOrdersWithoutPosition cancels the open orders of symbols the trader holds no position in (pending entries,
+// leftover stops), returns the symbols. Protective orders of open positions stay in place
+func (at *AutoTrader) cancelOrdersWithoutPosition(reason string) []string {
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Infof("❌ %s: failed to get positions: %v", reason, err)
+		return nil
+	}
+	held := make(map[string]bool, len(positions))
+	for _, pos := range positions {
+		if pos.Quantity > 0 {
+			held[pos.Symbol] = true
+		}
+	}
+
+	// Orders are cancelled on the account they were listed on (the fallback account too), bracket legs on the
+	// account the trader routes the symbol to
+	symbols := make(map[string][]Trader)
+	for _, t := range accountTraders(at.trader) {
+		lister, ok := capability[OpenOrderLister](t, "", "")
+		if !ok {
+			continue
+		}
+		orders, err := lister.GetOpenConditionalOrders()
+		if err != nil {
+			logger.Infof("  ⚠ %s: failed to get open orders: %v", reason, err)
+		}
+		for _, o := range orders {
+			if accounts := symbols[o.Symbol]; len(accounts) == 0 || accounts[len(accounts)-1] != t {
+				symbols[o.Symbol] = append(accounts, t)
+			}
+		}
+	}
+	at.brackets.mu.Lock()
+	for _, b := range at.brackets.legs {
+		if len(symbols[b.Symbol]) == 0 {
+			symbols[b.Symbol] = []Trader{at.trader}
+		}
+	}
+	at.brackets.mu.Unlock()
+
+	var cancelled []string
+	for symbol, accounts := range symbols {
+		if held[symbol] {
+			continue
+		}
+		ok := true
+		for _, t := range accounts {
+			if err := t.CancelAllOrders(symbol); err != nil {
+				logger.Infof("  ⚠ %s: failed to cancel %s orders: %v", reason, symbol, err)
+				ok = false
+			}
+		}
+		if ok {
+			cancelled = append(cancelled, symbol)
+		}
+	}
+	sort.Strings(cancelled)
+	return cancelled
+}
+
+// notifyWebhookEvent posts a trader event to the trader's webhook in the background (action = event type)
+// Critical events bypass quiet hours and digest batching
+func (at *AutoTrader) notifyWebhookEvent(event TraderEvent) {
+	at.sendWebhook(WebhookPayload{
+		TraderID:   at.id,
+		TraderName: at.name,
+		Exchange:   at.exchange,
+		Action:     event.Type,
+		Reasoning:  event.Message,
+		Time:       event.Time,
+	}, criticalEvents[event.Type])
+}
@@ -0,0 +1,203 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+	"nofx/store"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// balanceTrader Trader reporting a settable wallet balance and positions that can fail to close
+type balanceTrader struct {
+	Trader
+	wallet    float64
+	positions []Position
+	closeErr  error
+	cancelled []string
+}
+
+func (b *balanceTrader) GetBalance() (map[string]interface{}, error) {
+	return map[string]interface{}{"totalWalletBalance": b.wallet, "totalUnrealizedProfit": 0.0}, nil
+}
+
+func (b *balanceTrader) GetPositions() ([]Position, error) {
+	return b.positions, nil
+}
+
+func (b *balanceTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	if b.closeErr != nil {
+		return nil, b.closeErr
+	}
+	for i, pos := range b.positions {
+		if pos.Symbol == symbol && pos.Side == "long" {
+			b.positions = append(b.positions[:i], b.positions[i+1:]...)
+			break
+		}
+	}
+	return map[string]interface{}{"orderId": "1"}, nil
+}
+
+func (b *balanceTrader) CancelAllOrders(symbol string) error {
+	b.cancelled = append(b.cancelled, symbol)
+	return nil
+}
+
+// TestDailyLossKillSwitch tests that the trader pauses until the next UTC day once the loss limit is reached
+func TestDailyLossKillSwitch(t *testing.T) {
+	ex := &balanceTrader{wallet: 1000}
+	var events []TraderEvent
+	at := &AutoTrader{
+		id:     "t1",
+		trader: ex,
+		config: AutoTraderConfig{
+			StrategyConfig: &store.StrategyConfig{RiskControl: store.RiskControlConfig{MaxDailyLossPct: 5}},
+			OnEvent:        func(e TraderEvent) { events = append(events, e) },
+		},
+	}
+
+	at.checkDailyLoss() // Records the day's starting equity
+	ex.wallet = 960
+	at.checkDailyLoss()
+	if paused, _ := at.dailyLossPaused(time.Now()); paused || len(events) != 0 {
+		t.Fatal("4% loss should not trip a 5% limit")
+	}
+
+	ex.wallet = 950
+	at.checkDailyLoss()
+	paused, until := at.dailyLossPaused(time.Now())
+	if !paused || len(events) != 1 || events[0].Type != EventDailyLossLimit {
+		t.Fatalf("5%% loss should pause the trader, paused=%v events=%v", paused, events)
+	}
+	if !until.Equal(nextUTCDay(time.Now())) {
+		t.Errorf("expected pause until next UTC day, got %v", until)
+	}
+	if err := at.entriesAllowed(); err == nil {
+		t.Error("entries should be blocked while paused")
+	}
+
+	// Already paused: no repeated events
+	at.checkDailyLoss()
+	if len(events) != 1 {
+		t.Errorf("expected a single event, got %d", len(events))
+	}
+}
+
+// TestNextUTCDay tests day rollover across time zones
+func TestNextUTCDay(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	now := time.Date(2025, 1, 31, 7, 0, 0, 0, loc) // 2025-01-30 23:00 UTC
+	if got := nextUTCDay(now); !got.Equal(time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected next UTC day: %v", got)
+	}
+}
+
+// TestDailyLossFlattenRetry tests failed closes are retried while paused and orders of symbols without a position
+// are cancelled whether or not positions are flattened
+func TestDailyLossFlattenRetry(t *testing.T) {
+	ex := &balanceTrader{wallet: 1000, positions: []Position{{Symbol: "BTCUSDT", Side: "long", Quantity: 1}}, closeErr: errors.New("timeout")}
+	at := &AutoTrader{
+		id:       "t1",
+		trader:   ex,
+		brackets: brackets{legs: map[string]*bracket{"ETHUSDT_long": {Symbol: "ETHUSDT", Side: "LONG"}}},
+		config: AutoTraderConfig{
+			StrategyConfig: &store.StrategyConfig{RiskControl: store.RiskControlConfig{MaxDailyLossPct: 5, DailyLossFlatten: true}},
+		},
+	}
+
+	at.checkDailyLoss()
+	ex.wallet = 900
+	at.checkDailyLoss()
+	if paused, _ := at.dailyLossPaused(time.Now()); !paused || len(ex.positions) != 1 {
+		t.Fatalf("expected paused with the failed close left open, paused=%v positions=%d", paused, len(ex.positions))
+	}
+	if len(ex.cancelled) != 1 || ex.cancelled[0] != "ETHUSDT" {
+		t.Errorf("expected only orders of symbols without a position cancelled, got %v", ex.cancelled)
+	}
+
+	ex.closeErr = nil
+	at.checkDailyLoss()
+	if len(ex.positions) != 0 {
+		t.Error("expected the close retried while paused")
+	}
+
+	// Without flatten: positions and their protection are kept, other orders still cancelled
+	ex = &balanceTrader{wallet: 1000, positions: []Position{{Symbol: "BTCUSDT", Side: "long", Quantity: 1}}}
+	at.trader = ex
+	at.dailyLoss = dailyLossState{}
+	at.config.StrategyConfig.RiskControl.DailyLossFlatten = false
+	at.checkDailyLoss()
+	ex.wallet = 900
+	at.checkDailyLoss()
+	if len(ex.positions) != 1 || len(ex.cancelled) != 1 || ex.cancelled[0] != "ETHUSDT" {
+		t.Errorf("expected position kept and ETHUSDT orders cancelled, got positions=%d cancelled=%v", len(ex.positions), ex.cancelled)
+	}
+}
+
+// TestDailyLossPausePersisted tests the kill-switch pause survives a restart
+func TestDailyLossPausePersisted(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer st.Close()
+	if err := st.Trader().Create(&store.Trader{ID: "t1", UserID: "u1", Name: "t1"}); err != nil {
+		t.Fatalf("failed to create trader: %v", err)
+	}
+
+	ex := &balanceTrader{wallet: 1000}
+	strategy := &store.StrategyConfig{RiskControl: store.RiskControlConfig{MaxDailyLossPct: 5}}
+	at := &AutoTrader{id: "t1", trader: ex, store: st, config: AutoTraderConfig{StrategyConfig: strategy}}
+	at.dailyLoss.day = time.Now().UTC().Format("2006-01-02")
+	at.dailyLoss.startEquity = 1000
+	ex.wallet = 900
+	recordClosedLoss(t, st, "t1", 100)
+	at.checkDailyLoss()
+
+	restarted := &AutoTrader{id: "t1", trader: ex, store: st, config: AutoTraderConfig{StrategyConfig: strategy}}
+	restarted.restoreDailyLossPause()
+	if paused, until := restarted.dailyLossPaused(time.Now()); !paused || !until.Equal(nextUTCDay(time.Now())) {
+		t.Errorf("expected the pause restored until the next UTC day, got paused=%v until=%v", paused, until)
+	}
+}
+
+// recordClosedLoss stores a position closed just now with the given loss
+func recordClosedLoss(t *testing.T, st *store.Store, traderID string, loss float64) {
+	t.Helper()
+	now := time.Now()
+	_, err := st.Position().CreateFromClosedPnL(traderID, "ex1", "binance", &store.ClosedPnLRecord{
+		Symbol: "BTCUSDT", Side: "long", EntryPrice: 100, ExitPrice: 90, Quantity: 1, RealizedPnL: -loss, Leverage: 1,
+		EntryTime: now.Add(-time.Minute), ExitTime: now, OrderID: fmt.Sprintf("loss-%d", now.UnixNano()),
+	})
+	if err != nil {
+		t.Fatalf("failed to record closed position: %v", err)
+	}
+}
+
+// TestDailyLossIgnoresTransfers tests that moving funds out of the account doesn't count as a trading loss
+func TestDailyLossIgnoresTransfers(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer st.Close()
+
+	ex := &balanceTrader{wallet: 1000}
+	strategy := &store.StrategyConfig{RiskControl: store.RiskControlConfig{MaxDailyLossPct: 5}}
+	at := &AutoTrader{id: "t1", trader: ex, store: st, config: AutoTraderConfig{StrategyConfig: strategy}}
+
+	at.checkDailyLoss() // Records the day's starting equity
+	ex.wallet = 500     // Half the balance transferred out
+	at.checkDailyLoss()
+	if paused, _ := at.dailyLossPaused(time.Now()); paused {
+		t.Fatal("a withdrawal should not trip the kill-switch")
+	}
+
+	ex.wallet = 440
+	recordClosedLoss(t, st, "t1", 60)
+	at.checkDailyLoss()
+	if paused, _ := at.dailyLossPaused(time.Now()); !paused {
+		t.Error("a 6% realized loss should trip a 5% limit")
+	}
+}
@@ -0,0 +1,189 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/config"
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/mcp"
+	"nofx/store"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxEnsembleModels additional models asked per cycle
+	maxEnsembleModels = 4
+	// ensembleWeightTrades recent closed trades per trader running a model used for its weighted vote
+	ensembleWeightTrades = 20
+)
+
+// ensembleMember an additional AI model of the ensemble
+type ensembleMember struct {
+	id     string
+	name   string
+	client mcp.AIClient
+}
+
+// ensembleState the trader's ensemble models and vote rules, used while the ensemble_mode flag is on
+type ensembleState struct {
+	mu      sync.RWMutex
+	config  store.EnsembleConfig
+	members []ensembleMember
+}
+
+// ValidateEnsemble checks the ensemble vote rules and model count
+func ValidateEnsemble(cfg store.EnsembleConfig) error {
+	switch cfg.Method {
+	case "", store.EnsembleMajority, store.EnsembleWeighted:
+	default:
+		return fmt.Errorf("invalid ensemble method %q, expected %q or %q", cfg.Method, store.EnsembleMajority, store.EnsembleWeighted)
+	}
+	if len(cfg.ModelIDs) > maxEnsembleModels {
+		return fmt.Errorf("at most %d ensemble models, got %d", maxEnsembleModels, len(cfg.ModelIDs))
+	}
+	seen := make(map[string]bool, len(cfg.ModelIDs))
+	for _, id := range cfg.ModelIDs {
+		if id == "" || seen[id] {
+			return fmt.Errorf("ensemble model IDs must be unique and non-empty")
+		}
+		seen[id] = true
+	}
+	if cfg.MinAgreement < 0 || cfg.MinAgreement > 1 {
+		return fmt.Errorf("min agreement must be between 0 and 1: %v", cfg.MinAgreement)
+	}
+	if cfg.MinConfidence < 0 || cfg.MinConfidence > 100 {
+		return fmt.Errorf("min confidence must be between 0 and 100: %d", cfg.MinConfidence)
+	}
+	return nil
+}
+
+// newEnsembleMembers creates an AI client per ensemble model
+func newEnsembleMembers(models []*store.AIModel) []ensembleMember {
+	members := make([]ensembleMember, 0, len(models))
+	for _, model := range models {
+		client := mcp.NewProviderClient(model.Provider)
+		client.SetAPIKey(model.APIKey, model.CustomAPIURL, model.CustomModelName)
+		members = append(members, ensembleMember{id: model.ID, name: model.Name, client: client})
+	}
+	return members
+}
+
+// SetEnsemble replaces the ensemble models (already resolved from cfg.ModelIDs) and vote rules
+func (at *AutoTrader) SetEnsemble(cfg store.EnsembleConfig, models []*store.AIModel) error {
+	if err := ValidateEnsemble(cfg); err != nil {
+		return err
+	}
+	members := newEnsembleMembers(models)
+	at.ensemble.mu.Lock()
+	at.ensemble.config = cfg
+	at.ensemble.members = members
+	at.ensemble.mu.Unlock()
+	return nil
+}
+
+// requestEnsembleDecision asks the trader's model and, while the ensemble_mode flag is on, every ensemble model,
+// then keeps the actions the ensemble agrees on. Returns the trader model's decision carrying the combined
+// decisions and total AI usage, the vote notes for the execution log and the usage per model
+func (at *AutoTrader) requestEnsembleDecision(ctx *decision.Context) (*decision.FullDecision, []string, []store.ModelUsage, error) {
+	primary, err := decision.GetFullDecisionWithStrategy(ctx, at.mcpClient, at.strategyEngine, "balanced")
+	var usage []store.ModelUsage
+	if primary != nil {
+		usage = append(usage, modelUsage(at.aiModel, primary.Usage))
+	}
+
+	at.ensemble.mu.RLock()
+	cfg := at.ensemble.config
+	members := at.ensemble.members
+	at.ensemble.mu.RUnlock()
+	if err != nil || len(members) == 0 || !at.FeatureEnabled(config.FlagEnsembleMode) {
+		return primary, nil, usage, err
+	}
+
+	// The context is complete after the first call, the other models only read it
+	results := make([]*decision.FullDecision, len(members))
+	errs := make([]error, len(members))
+	var wg sync.WaitGroup
+	for i, m := range members {
+		wg.Add(1)
+		go func(i int, m ensembleMember) {
+			defer wg.Done()
+			results[i], errs[i] = decision.GetFullDecisionWithStrategy(ctx, m.client, at.strategyEngine, "balanced")
+		}(i, m)
+	}
+	wg.Wait()
+
+	weight := func(string) float64 { return 1 }
+	if cfg.Method == store.EnsembleWeighted {
+		weight = at.modelWinRate
+	}
+	primaryName := "primary (" + at.aiModel + ")"
+	votes := []decision.EnsembleVote{{Model: primaryName, Weight: weight(at.config.AIModelID), Decisions: primary.Decisions}}
+	var notes []string
+	traces := []string{primary.CoTTrace}
+	for i, m := range members {
+		if errs[i] != nil {
+			notes = append(notes, fmt.Sprintf("⚠️ Ensemble model %s failed, left out of the vote: %v", m.name, errs[i]))
+			continue
+		}
+		votes = append(votes, decision.EnsembleVote{Model: m.name, Weight: weight(m.id), Decisions: results[i].Decisions})
+		usage = append(usage, modelUsage(m.name, results[i].Usage))
+		primary.Usage.Add(results[i].Usage)
+		traces = append(traces, fmt.Sprintf("--- %s ---\n%s", m.name, results[i].CoTTrace))
+	}
+	for _, v := range votes {
+		notes = append(notes, fmt.Sprintf("🗳 %s: weight %.2f, %d decisions", v.Model, v.Weight, len(v.Decisions)))
+	}
+
+	combined, voteNotes := decision.CombineEnsemble(votes, cfg.MinAgreement, cfg.MinConfidence)
+	primary.Decisions = combined
+	primary.CoTTrace = strings.Join(traces, "\n\n")
+	return primary, append(notes, voteNotes...), usage, nil
+}
+
+// modelUsage usage of one model for the decision record
+func modelUsage(model string, u mcp.Usage) store.ModelUsage {
+	return store.ModelUsage{
+		Model:            model,
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		CostUSD:          u.CostUSD,
+		Estimated:        u.Estimated,
+	}
+}
+
+// modelWinRate smoothed win rate ((wins+1)/(n+2), 0.5 without history) of the owner's recent closed trades
+// decided by the AI model, i.e. of traders running it
+func (at *AutoTrader) modelWinRate(modelID string) float64 {
+	if at.store == nil || modelID == "" {
+		return 0.5
+	}
+	traders, err := at.store.Trader().List(at.userID)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to load traders for ensemble weights: %v", at.name, err)
+		return 0.5
+	}
+	var trades []store.RecentTrade
+	for _, t := range traders {
+		if t.AIModelID != modelID {
+			continue
+		}
+		recent, err := at.store.Position().GetRecentTrades(t.ID, ensembleWeightTrades)
+		if err != nil {
+			continue
+		}
+		trades = append(trades, recent...)
+	}
+	return winRate(trades)
+}
+
+// winRate smoothed win rate of closed trades
+func winRate(trades []store.RecentTrade) float64 {
+	wins := 0
+	for _, t := range trades {
+		if t.RealizedPnL > 0 {
+			wins++
+		}
+	}
+	return float64(wins+1) / float64(len(trades)+2)
+}
@@ -0,0 +1,40 @@
+package trader
+
+import (
+	"nofx/store"
+	"testing"
+)
+
+// TestValidateEnsemble tests vote method, model list and threshold validation
+func TestValidateEnsemble(t *testing.T) {
+	if err := ValidateEnsemble(store.EnsembleConfig{}); err != nil {
+		t.Errorf("expected disabled ensemble to be valid: %v", err)
+	}
+	valid := store.EnsembleConfig{ModelIDs: []string{"a", "b"}, Method: store.EnsembleWeighted, MinAgreement: 0.66, MinConfidence: 70}
+	if err := ValidateEnsemble(valid); err != nil {
+		t.Errorf("expected valid ensemble: %v", err)
+	}
+	invalid := []store.EnsembleConfig{
+		{Method: "average"},
+		{ModelIDs: []string{"a", "a"}},
+		{ModelIDs: []string{"a", "b", "c", "d", "e"}},
+		{MinAgreement: 1.5},
+		{MinConfidence: -1},
+	}
+	for _, cfg := range invalid {
+		if err := ValidateEnsemble(cfg); err == nil {
+			t.Errorf("expected error for %+v", cfg)
+		}
+	}
+}
+
+// TestWinRate tests the smoothed win rate used as vote weight
+func TestWinRate(t *testing.T) {
+	if got := winRate(nil); got != 0.5 {
+		t.Errorf("expected 0.5 without history, got %v", got)
+	}
+	trades := []store.RecentTrade{{RealizedPnL: 10}, {RealizedPnL: 5}, {RealizedPnL: -3}}
+	if got := winRate(trades); got != 0.6 {
+		t.Errorf("expected (2+1)/(3+2) = 0.6, got %v", got)
+	}
+}
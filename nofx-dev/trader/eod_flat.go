@@ -0,0 +1,164 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"nofx/store"
+	"strings"
+	"sync"
+	"time"
+)
+
+// flatCheckInterval how often the end-of-day flat schedule is evaluated
+const flatCheckInterval = 30 * time.Second
+
+// EventFlattened positions were closed by the end-of-day flat schedule
+const EventFlattened = "eod_flattened"
+
+// Flat schedule modes
+const (
+	FlatModeDaily   = "daily"
+	FlatModeWeekend = "weekend"
+)
+
+// flatSchedule parsed end-of-day flat schedule (times in minutes after midnight)
+type flatSchedule struct {
+	mode   string
+	flat   int
+	resume int
+	loc    *time.Location
+}
+
+// flatState per-trader flat mode, schedule nil when disabled
+type flatState struct {
+	mu       sync.RWMutex
+	schedule *flatSchedule
+}
+
+// parseClock parses "HH:MM" into minutes after midnight
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ValidateFlatSchedule checks flat mode, times and time zone of an enabled schedule
+func ValidateFlatSchedule(cfg store.FlatSchedule) error {
+	_, err := parseFlatSchedule(cfg)
+	return err
+}
+
+// parseFlatSchedule parses a flat schedule (nil, nil when disabled)
+func parseFlatSchedule(cfg store.FlatSchedule) (*flatSchedule, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	s := &flatSchedule{mode: cfg.Mode, loc: time.UTC}
+	if s.mode == "" {
+		s.mode = FlatModeDaily
+	}
+	if s.mode != FlatModeDaily && s.mode != FlatModeWeekend {
+		return nil, fmt.Errorf("invalid flat mode %q, expected %s or %s", cfg.Mode, FlatModeDaily, FlatModeWeekend)
+	}
+
+	var err error
+	if s.flat, err = parseClock(cfg.FlatTime); err != nil {
+		return nil, err
+	}
+	if cfg.ResumeTime != "" {
+		if s.resume, err = parseClock(cfg.ResumeTime); err != nil {
+			return nil, err
+		}
+	}
+	if s.mode == FlatModeDaily && s.flat == s.resume {
+		return nil, fmt.Errorf("resume time must differ from flat time")
+	}
+	if cfg.Timezone != "" {
+		if s.loc, err = time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", cfg.Timezone, err)
+		}
+	}
+	return s, nil
+}
+
+// active reports whether now falls in the flat period (no positions, no new entries)
+func (s *flatSchedule) active(now time.Time) bool {
+	t := now.In(s.loc)
+	minute := t.Hour()*60 + t.Minute()
+
+	if s.mode == FlatModeWeekend {
+		switch t.Weekday() {
+		case time.Friday:
+			return minute >= s.flat
+		case time.Saturday, time.Sunday:
+			return true
+		case time.Monday:
+			return minute < s.resume
+		}
+		return false
+	}
+
+	// Daily: [flat, resume), wrapping past midnight when resume is earlier in the day
+	if s.flat < s.resume {
+		return minute >= s.flat && minute < s.resume
+	}
+	return minute >= s.flat || minute < s.resume
+}
+
+// SetFlatSchedule updates the end-of-day flat schedule (disabled schedules clear it)
+func (at *AutoTrader) SetFlatSchedule(cfg store.FlatSchedule) error {
+	schedule, err := parseFlatSchedule(cfg)
+	if err != nil {
+		return err
+	}
+	at.flat.mu.Lock()
+	at.flat.schedule = schedule
+	at.flat.mu.Unlock()
+	return nil
+}
+
+// flatActive reports whether the trader is inside its flat period
+func (at *AutoTrader) flatActive(now time.Time) bool {
+	at.flat.mu.RLock()
+	schedule := at.flat.schedule
+	at.flat.mu.RUnlock()
+	return schedule != nil && schedule.active(now)
+}
+
+// checkFlatSchedule closes all positions and cancels their orders, and pending orders of symbols without
+// a position, while the flat period is active
+// Runs every check interval so positions opened manually (or closes that failed) are retried
+func (at *AutoTrader) checkFlatSchedule() {
+	if !at.flatActive(time.Now()) {
+		return
+	}
+
+	closed := at.flattenAll("Flat mode")
+	// Pending entries would reopen positions during the flat window
+	cancelled := at.cancelOrdersWithoutPosition("Flat mode")
+	if len(closed) == 0 && len(cancelled) == 0 {
+		return
+	}
+
+	var parts []string
+	if len(closed) > 0 {
+		parts = append(parts, "closed "+strings.Join(closed, ", "))
+	}
+	if len(cancelled) > 0 {
+		parts = append(parts, "cancelled orders of "+strings.Join(cancelled, ", "))
+	}
+	message := strings.Join(parts, "; ")
+	logger.Infof("🌙 Flat mode: %s", message)
+	if at.config.OnEvent != nil {
+		at.config.OnEvent(TraderEvent{
+			TraderID:   at.id,
+			Type:       EventFlattened,
+			Exchange:   at.exchange,
+			ExchangeID: at.exchangeID,
+			Message:    fmt.Sprintf("end-of-day flat: %s", message),
+			Time:       time.Now(),
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package trader
+
+import (
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+// TestFlatScheduleActive tests daily (with and without midnight wrap) and weekend flat periods
+func TestFlatScheduleActive(t *testing.T) {
+	at := func(s string) time.Time {
+		ts, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+
+	tests := []struct {
+		name string
+		cfg  store.FlatSchedule
+		now  string
+		want bool
+	}{
+		// 2025-01-03 is a Friday
+		{"daily before flat", store.FlatSchedule{Enabled: true, FlatTime: "22:00", ResumeTime: "01:00"}, "2025-01-01 21:59", false},
+		{"daily after flat", store.FlatSchedule{Enabled: true, FlatTime: "22:00", ResumeTime: "01:00"}, "2025-01-01 22:00", true},
+		{"daily past midnight", store.FlatSchedule{Enabled: true, FlatTime: "22:00", ResumeTime: "01:00"}, "2025-01-02 00:30", true},
+		{"daily resumed", store.FlatSchedule{Enabled: true, FlatTime: "22:00", ResumeTime: "01:00"}, "2025-01-02 01:00", false},
+		{"daily resume midnight", store.FlatSchedule{Enabled: true, FlatTime: "23:00"}, "2025-01-02 00:05", false},
+		{"daily same day window", store.FlatSchedule{Enabled: true, FlatTime: "12:00", ResumeTime: "14:00"}, "2025-01-02 13:00", true},
+		{"weekend thursday", store.FlatSchedule{Enabled: true, Mode: FlatModeWeekend, FlatTime: "20:00"}, "2025-01-02 21:00", false},
+		{"weekend friday before", store.FlatSchedule{Enabled: true, Mode: FlatModeWeekend, FlatTime: "20:00"}, "2025-01-03 19:00", false},
+		{"weekend friday after", store.FlatSchedule{Enabled: true, Mode: FlatModeWeekend, FlatTime: "20:00"}, "2025-01-03 20:30", true},
+		{"weekend sunday", store.FlatSchedule{Enabled: true, Mode: FlatModeWeekend, FlatTime: "20:00"}, "2025-01-05 12:00", true},
+		{"weekend monday before resume", store.FlatSchedule{Enabled: true, Mode: FlatModeWeekend, FlatTime: "20:00", ResumeTime: "08:00"}, "2025-01-06 07:00", true},
+		{"weekend monday resumed", store.FlatSchedule{Enabled: true, Mode: FlatModeWeekend, FlatTime: "20:00", ResumeTime: "08:00"}, "2025-01-06 08:00", false},
+		// 22:00 in New York is 03:00 UTC the next day
+		{"time zone", store.FlatSchedule{Enabled: true, FlatTime: "22:00", ResumeTime: "23:00", Timezone: "America/New_York"}, "2025-01-02 03:30", true},
+	}
+
+	for _, tt := range tests {
+		s, err := parseFlatSchedule(tt.cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := s.active(at(tt.now)); got != tt.want {
+			t.Errorf("%s: active=%v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestValidateFlatSchedule tests rejection of invalid schedules
+func TestValidateFlatSchedule(t *testing.T) {
+	invalid := []store.FlatSchedule{
+		{Enabled: true, FlatTime: "25:00"},
+		{Enabled: true, FlatTime: "22:00", ResumeTime: "22:00"},
+		{Enabled: true, FlatTime: "22:00", Mode: "hourly"},
+		{Enabled: true, FlatTime: "22:00", Timezone: "Mars/Olympus"},
+	}
+	for _, cfg := range invalid {
+		if err := ValidateFlatSchedule(cfg); err == nil {
+			t.Errorf("expected error for %+v", cfg)
+		}
+	}
+	if err := ValidateFlatSchedule(store.FlatSchedule{Enabled: false, FlatTime: "bogus"}); err != nil {
+		t.Errorf("disabled schedule should not be validated: %v", err)
+	}
+}
+
+// TestCheckFlatScheduleCancelsPendingOrders tests that flat mode also cancels orders of symbols without a position
+func TestCheckFlatScheduleCancelsPendingOrders(t *testing.T) {
+	now := time.Now().UTC()
+	schedule, err := parseFlatSchedule(store.FlatSchedule{
+		Enabled: true, FlatTime: now.Add(-time.Hour).Format("15:04"), ResumeTime: now.Add(time.Hour).Format("15:04"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ex := &balanceTrader{wallet: 1000, positions: []Position{{Symbol: "BTCUSDT", Side: "long", Quantity: 1}}}
+	var events []TraderEvent
+	at := &AutoTrader{
+		id:       "t1",
+		trader:   ex,
+		flat:     flatState{schedule: schedule},
+		brackets: brackets{legs: map[string]*bracket{"ETHUSDT_long": {Symbol: "ETHUSDT", Side: "LONG"}}},
+		config:   AutoTraderConfig{OnEvent: func(e TraderEvent) { events = append(events, e) }},
+	}
+
+	at.checkFlatSchedule()
+	if len(ex.positions) != 0 {
+		t.Errorf("expected the position closed, got %v", ex.positions)
+	}
+	if len(ex.cancelled) != 2 || ex.cancelled[0] != "BTCUSDT" || ex.cancelled[1] != "ETHUSDT" {
+		t.Errorf("expected orders of the closed position and the pending entry cancelled, got %v", ex.cancelled)
+	}
+	if len(events) != 1 || events[0].Type != EventFlattened {
+		t.Errorf("expected one flattened event, got %v", events)
+	}
+}
@@ -0,0 +1,214 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// ErrorKind exchange error category
+// Retry logic, guardrails and alerting should branch on kind instead of matching error strings
+type ErrorKind string
+
+const (
+	ErrKindRateLimited        ErrorKind = "rate_limited"        // Too many requests / IP ban, retry after backoff
+	ErrKindInsufficientMargin ErrorKind = "insufficient_margin" // Not enough balance/margin for the order
+	ErrKindInvalidSymbol      ErrorKind = "invalid_symbol"      // Symbol not listed / not tradable
+	ErrKindRejected           ErrorKind = "rejected"            // Order rejected by exchange rules (precision, reduce-only, trigger price, etc.)
+	ErrKindNetwork            ErrorKind = "network"             // Timeout, connection reset, DNS, 5xx
+	ErrKindUnknown            ErrorKind = "unknown"
+)
+
+// Sentinel errors, usable with errors.Is(err, trader.ErrRateLimited)
+var (
+	ErrRateLimited        = errors.New("exchange rate limited")
+	ErrInsufficientMargin = errors.New("insufficient margin")
+	ErrInvalidSymbol      = errors.New("invalid symbol")
+	ErrRejected           = errors.New("order rejected")
+	ErrNetwork            = errors.New("exchange network error")
+)
+
+var kindSentinels = map[ErrorKind]error{
+	ErrKindRateLimited:        ErrRateLimited,
+	ErrKindInsufficientMargin: ErrInsufficientMargin,
+	ErrKindInvalidSymbol:      ErrInvalidSymbol,
+	ErrKindRejected:           ErrRejected,
+	ErrKindNetwork:            ErrNetwork,
+}
+
+// ExchangeError typed exchange error wrapping the original adapter error
+type ExchangeError struct {
+	Exchange string    // Exchange type: binance/bybit/okx/hyperliquid/aster/lighter
+	Op       string    // Operation, e.g. "OpenLong"
+	Kind     ErrorKind // Error category
+	Code     string    // Exchange error code (if found)
+	Err      error     // Original error
+}
+
+func (e *ExchangeError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the original error
+func (e *ExchangeError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches the sentinel error of this error's kind
+func (e *ExchangeError) Is(target error) bool {
+	sentinel, ok := kindSentinels[e.Kind]
+	return ok && sentinel == target
+}
+
+// Retryable whether the operation may succeed if retried later
+func (e *ExchangeError) Retryable() bool {
+	return e.Kind == ErrKindRateLimited || e.Kind == ErrKindNetwork
+}
+
+// KindOf returns the error category (ErrKindUnknown if err is not classified)
+func KindOf(err error) ErrorKind {
+	var exErr *ExchangeError
+	if errors.As(err, &exErr) {
+		return exErr.Kind
+	}
+	return ErrKindUnknown
+}
+
+// IsRetryable whether err is a transient exchange error (rate limit / network)
+func IsRetryable(err error) bool {
+	var exErr *ExchangeError
+	return errors.As(err, &exErr) && exErr.Retryable()
+}
+
+// Exchange error codes by category
+// Binance/Aster: negative integer codes, Bybit: retCode, OKX: sCode/code, MEXC: code
+var exchangeErrorCodes = map[string]map[string]ErrorKind{
+	"binance": {
+		"-1003": ErrKindRateLimited, "-1015": ErrKindRateLimited,
+		"-2019": ErrKindInsufficientMargin, "-2018": ErrKindInsufficientMargin, "-4164": ErrKindRejected,
+		"-1121": ErrKindInvalidSymbol, "-4141": ErrKindInvalidSymbol,
+		"-2010": ErrKindRejected, "-2021": ErrKindRejected, "-2022": ErrKindRejected, "-1111": ErrKindRejected,
+		"-4003": ErrKindRejected, "-4061": ErrKindRejected, "-4045": ErrKindRejected, "-1001": ErrKindNetwork, "-1007": ErrKindNetwork,
+	},
+	"bybit": {
+		"10006": ErrKindRateLimited, "10018": ErrKindRateLimited,
+		"110007": ErrKindInsufficientMargin, "110004": ErrKindInsufficientMargin, "110012": ErrKindInsufficientMargin,
+		"10001": ErrKindRejected, "110017": ErrKindRejected, "110094": ErrKindRejected,
+		"110009": ErrKindRejected, "10016": ErrKindNetwork,
+	},
+	"okx": {
+		"50011": ErrKindRateLimited, "50061": ErrKindRateLimited,
+		"51008": ErrKindInsufficientMargin, "51004": ErrKindInsufficientMargin,
+		"51001": ErrKindInvalidSymbol, "51000": ErrKindRejected, "51121": ErrKindRejected,
+		"51169": ErrKindRejected, "50001": ErrKindNetwork, "50013": ErrKindNetwork,
+	},
+	"mexc": {
+		"510": ErrKindRateLimited, "2005": ErrKindInsufficientMargin,
+		"1001": ErrKindInvalidSymbol, "2003": ErrKindRejected, "2011": ErrKindRejected,
+		"2015": ErrKindRejected, "9999": ErrKindNetwork,
+	},
+}
+
+func init() {
+	// Aster uses Binance-compatible API codes
+	exchangeErrorCodes["aster"] = exchangeErrorCodes["binance"]
+}
+
+var reErrorCode = regexp.MustCompile(`(?i)(?:code[=: "]+|retCode[=: "]+|sCode[=: "]+)(-?\d+)`)
+
+// reHTTPStatus matches an explicit HTTP status mention such as "http 429", "status 502" or "status code: 503"
+var reHTTPStatus = regexp.MustCompile(`(?i)\b(?:http(?:/[\d.]+)?|status(?: ?code)?)[=: ]+(\d{3})\b`)
+
+// reEOF matches EOF as a word so it doesn't fire inside other words or identifiers
+var reEOF = regexp.MustCompile(`(?i)\b(?:unexpected )?eof\b`)
+
+// httpStatusKinds categories for HTTP statuses reported by the exchange gateway
+// (go-binance reports an unparsable HTTP body as code=<status>)
+var httpStatusKinds = map[string]ErrorKind{
+	"418": ErrKindRateLimited, "429": ErrKindRateLimited,
+	"502": ErrKindNetwork, "503": ErrKindNetwork, "504": ErrKindNetwork,
+}
+
+// Message patterns checked when no code mapping matches (order matters: more specific first)
+var errorMessagePatterns = []struct {
+	kind     ErrorKind
+	patterns []string
+}{
+	{ErrKindRateLimited, []string{"too many requests", "rate limit", "request weight", "too many visits", "ip banned"}},
+	{ErrKindInsufficientMargin, []string{"insufficient", "margin is insufficient", "not enough", "balance not enough", "exceeds available"}},
+	{ErrKindInvalidSymbol, []string{"invalid symbol", "symbol not found", "unknown symbol", "instrument id does not exist", "coin not found", "asset not found", "market not found"}},
+	{ErrKindNetwork, []string{"timeout", "timed out", "deadline exceeded", "connection reset", "connection refused", "broken pipe", "no such host", "bad gateway", "service unavailable", "gateway timeout"}},
+	{ErrKindRejected, []string{"reject", "would immediately trigger", "reduceonly", "reduce only", "precision", "min notional", "minimum", "invalid quantity", "invalid price", "not allowed"}},
+}
+
+// ClassifyError wraps err into *ExchangeError with a detected category
+// Returns nil for nil, and err unchanged if it is already classified
+func ClassifyError(exchange, op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var exErr *ExchangeError
+	if errors.As(err, &exErr) {
+		return err
+	}
+
+	kind, code := classify(exchange, err)
+	return &ExchangeError{
+		Exchange: exchange,
+		Op:       op,
+		Kind:     kind,
+		Code:     code,
+		Err:      err,
+	}
+}
+
+// classify detects error kind from network error types, exchange codes and message patterns
+func classify(exchange string, err error) (ErrorKind, string) {
+	msg := err.Error()
+
+	code := ""
+	if m := reErrorCode.FindStringSubmatch(msg); len(m) == 2 {
+		code = m[1]
+		if kind, ok := exchangeErrorCodes[exchange][code]; ok {
+			return kind, code
+		}
+		if kind, ok := httpStatusKinds[code]; ok {
+			return kind, code
+		}
+	}
+	if m := reHTTPStatus.FindStringSubmatch(msg); len(m) == 2 {
+		if kind, ok := httpStatusKinds[m[1]]; ok {
+			return kind, code
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrKindNetwork, code
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || reEOF.MatchString(msg) {
+		return ErrKindNetwork, code
+	}
+
+	lower := strings.ToLower(msg)
+	for _, group := range errorMessagePatterns {
+		for _, p := range group.patterns {
+			if strings.Contains(lower, p) {
+				return group.kind, code
+			}
+		}
+	}
+	return ErrKindUnknown, code
+}
+
+// describeError formats err with its kind for logs
+func describeError(err error) string {
+	if kind := KindOf(err); kind != ErrKindUnknown {
+		return fmt.Sprintf("[%s] %v", kind, err)
+	}
+	return err.Error()
+}
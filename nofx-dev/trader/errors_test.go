@@ -0,0 +1,78 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestClassifyError tests exchange error categorization
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		exchange string
+		err      error
+		wantKind ErrorKind
+		wantCode string
+	}{
+		{"binance rate limit code", "binance", errors.New("<APIError> code=-1003, msg=Too much request weight used"), ErrKindRateLimited, "-1003"},
+		{"binance insufficient margin", "binance", fmt.Errorf("failed to open long position: %w", errors.New("<APIError> code=-2019, msg=Margin is insufficient.")), ErrKindInsufficientMargin, "-2019"},
+		{"binance invalid symbol", "binance", errors.New("<APIError> code=-1121, msg=Invalid symbol."), ErrKindInvalidSymbol, "-1121"},
+		{"binance would trigger", "binance", errors.New("<APIError> code=-2021, msg=Order would immediately trigger."), ErrKindRejected, "-2021"},
+		{"aster shares binance codes", "aster", errors.New("code=-2019 margin"), ErrKindInsufficientMargin, "-2019"},
+		{"okx message only", "okx", errors.New("failed to open long position: Insufficient USDT margin in account"), ErrKindInsufficientMargin, ""},
+		{"mexc insufficient margin", "mexc", errors.New("failed to open long position: MEXC API error: code=2005, msg=balance insufficient"), ErrKindInsufficientMargin, "2005"},
+		{"hyperliquid rejected", "hyperliquid", errors.New("order rejected: reduce only order would increase position"), ErrKindRejected, ""},
+		{"deadline exceeded", "bybit", fmt.Errorf("request failed: %w", context.DeadlineExceeded), ErrKindNetwork, ""},
+		{"connection reset", "lighter", errors.New("read tcp: connection reset by peer"), ErrKindNetwork, ""},
+		{"http status rate limit", "okx", errors.New("request failed: HTTP 429 Too Many"), ErrKindRateLimited, ""},
+		{"binance http body code", "binance", errors.New("<APIError> code=503, msg=upstream"), ErrKindNetwork, "503"},
+		{"status code gateway", "hyperliquid", errors.New("unexpected status code: 502"), ErrKindNetwork, "502"},
+		{"eof text", "lighter", errors.New("Post \"https://api\": unexpected EOF"), ErrKindNetwork, ""},
+		{"price containing status digits", "binance", errors.New("trigger 65029.5 above mark 64180.418"), ErrKindUnknown, ""},
+		{"word containing eof", "okx", errors.New("field geofence missing"), ErrKindUnknown, ""},
+		{"unknown", "binance", errors.New("something odd"), ErrKindUnknown, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ClassifyError(tt.exchange, "OpenLong", tt.err)
+			var exErr *ExchangeError
+			if !errors.As(err, &exErr) {
+				t.Fatalf("expected *ExchangeError, got %T", err)
+			}
+			if exErr.Kind != tt.wantKind {
+				t.Errorf("kind: expected %s, got %s", tt.wantKind, exErr.Kind)
+			}
+			if exErr.Code != tt.wantCode {
+				t.Errorf("code: expected %q, got %q", tt.wantCode, exErr.Code)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Error("classified error should unwrap to the original error")
+			}
+		})
+	}
+}
+
+// TestExchangeErrorSentinels tests errors.Is matching and retryability
+func TestExchangeErrorSentinels(t *testing.T) {
+	err := ClassifyError("binance", "GetBalance", errors.New("code=-1003 too many requests"))
+	if !errors.Is(err, ErrRateLimited) {
+		t.Error("expected errors.Is(err, ErrRateLimited)")
+	}
+	if errors.Is(err, ErrInsufficientMargin) {
+		t.Error("rate limited error should not match ErrInsufficientMargin")
+	}
+	if !IsRetryable(fmt.Errorf("cycle failed: %w", err)) {
+		t.Error("rate limited error should be retryable through wrapping")
+	}
+
+	if ClassifyError("binance", "x", nil) != nil {
+		t.Error("nil error should stay nil")
+	}
+	// Already classified errors are returned unchanged
+	if again := ClassifyError("okx", "y", err); again != err {
+		t.Error("classified error should not be re-wrapped")
+	}
+}
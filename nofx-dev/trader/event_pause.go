@@ -0,0 +1,27 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/events"
+	"time"
+)
+
+// eventPause the strategy's entry pause around high-impact events (0 = disabled)
+func (at *AutoTrader) eventPause() time.Duration {
+	if at.config.StrategyConfig == nil {
+		return 0
+	}
+	return time.Duration(at.config.StrategyConfig.RiskControl.EventPauseMinutes) * time.Minute
+}
+
+// checkEventPause returns an error while a high-impact event affecting symbol is within the entry pause window
+func (at *AutoTrader) checkEventPause(calendar *events.Calendar, symbol string, now time.Time) error {
+	pause := at.eventPause()
+	if pause <= 0 || calendar == nil {
+		return nil
+	}
+	if e := calendar.Blocking(symbol, now, pause, pause); e != nil {
+		return fmt.Errorf("❌ %s: new entries are paused around %s", symbol, e.Describe(now))
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package trader
+
+import (
+	"nofx/events"
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+// fixedEvents events.Source returning fixed events
+type fixedEvents []events.Event
+
+func (f fixedEvents) Name() string                   { return "fixed" }
+func (f fixedEvents) Fetch() ([]events.Event, error) { return f, nil }
+
+// TestCheckEventPause tests blocking entries around high-impact events affecting the symbol
+func TestCheckEventPause(t *testing.T) {
+	now := time.Date(2024, 3, 20, 17, 45, 0, 0, time.UTC)
+	calendar := events.NewCalendar(0, fixedEvents{
+		{Title: "USD FOMC Statement", Category: events.CategoryMacro, Impact: "high", Time: now.Add(15 * time.Minute)},
+		{Title: "ARB unlock", Category: events.CategoryUnlock, Impact: "high", Time: now.Add(-2 * time.Hour), Symbols: []string{"ARB"}},
+	})
+	calendar.Refresh()
+
+	at := &AutoTrader{config: AutoTraderConfig{StrategyConfig: &store.StrategyConfig{}}}
+	if err := at.checkEventPause(calendar, "BTCUSDT", now); err != nil {
+		t.Errorf("disabled pause should allow entries: %v", err)
+	}
+
+	at.config.StrategyConfig.RiskControl.EventPauseMinutes = 30
+	if err := at.checkEventPause(calendar, "BTCUSDT", now); err == nil {
+		t.Error("entries 15 minutes before FOMC should be blocked")
+	}
+	if err := at.checkEventPause(calendar, "BTCUSDT", now.Add(50*time.Minute)); err != nil {
+		t.Errorf("entries 35 minutes after FOMC should be allowed: %v", err)
+	}
+	if err := at.checkEventPause(calendar, "ARBUSDT", now.Add(-100*time.Minute)); err == nil {
+		t.Error("ARB entries 20 minutes after its unlock should be blocked")
+	}
+	if err := at.checkEventPause(nil, "BTCUSDT", now); err != nil {
+		t.Errorf("no calendar should allow entries: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package trader
+
+import (
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/market"
+	"sync"
+	"time"
+)
+
+const (
+	// hitRateHistory latest closed trades the confidence bucket hit rates are computed from
+	hitRateHistory = 200
+	// hitRateRefreshInterval how long computed hit rates are reused across cycles
+	hitRateRefreshInterval = 10 * time.Minute
+)
+
+// hitRateCache hit rates by confidence bucket, recomputed at most every hitRateRefreshInterval
+type hitRateCache struct {
+	mu       sync.Mutex
+	rates    *decision.HitRates
+	computed time.Time
+}
+
+// confidenceHitRates win rate of the trader's latest closed trades by the confidence of the decision that opened them
+// Trades without an opening decision on record (manual, imported) are left out
+func (at *AutoTrader) confidenceHitRates() *decision.HitRates {
+	at.hitRates.mu.Lock()
+	defer at.hitRates.mu.Unlock()
+	if at.hitRates.rates != nil && time.Since(at.hitRates.computed) < hitRateRefreshInterval {
+		return at.hitRates.rates
+	}
+
+	rates := &decision.HitRates{}
+	if at.store != nil {
+		positions, err := at.store.Position().GetClosedPositions(at.id, hitRateHistory)
+		if err != nil {
+			logger.Infof("⚠️ [%s] Failed to get closed trades for hit rates: %v", at.name, err)
+			return rates
+		}
+		for _, pos := range positions {
+			records, err := at.store.Decision().GetRecordsByTimeRange(at.id, pos.EntryTime.Add(-outcomeDecisionLookback), pos.EntryTime)
+			if err != nil {
+				logger.Infof("⚠️ [%s] Failed to get decisions for hit rates: %v", at.name, err)
+				return rates
+			}
+			if _, d := findOpeningDecision(pos, records); d != nil {
+				outcome := labelOutcome(pos)
+				rates.Add(d.Confidence, outcome.Label == OutcomeWin, outcome.HoldingMinutes)
+			}
+		}
+	}
+	at.hitRates.rates, at.hitRates.computed = rates, time.Now()
+	return rates
+}
+
+// applyExpectedValueGate drops open decisions whose expected value after fees and funding is below the strategy's
+// min_expected_value_pct (expected_value_gate), returns the decisions to execute and an execution log note per skipped trade
+func (at *AutoTrader) applyExpectedValueGate(decisions []decision.Decision, marketData map[string]*market.Data) ([]decision.Decision, []string) {
+	if at.config.StrategyConfig == nil || !at.config.StrategyConfig.RiskControl.ExpectedValueGate {
+		return decisions, nil
+	}
+	// Without the exchange's fees every trade would look cheaper than it is
+	if _, ok := logger.GetFeeSchedule(at.exchange); !ok {
+		logger.Infof("⚠️ Expected value gate off: no fee schedule registered for %s", at.exchange)
+		return decisions, nil
+	}
+	feeRate := logger.EstimateFee(at.exchange, 1, false)
+	return decision.ApplyExpectedValueGate(decisions, marketData, at.confidenceHitRates(), feeRate,
+		at.config.StrategyConfig.RiskControl.MinExpectedValuePct)
+}
@@ -0,0 +1,36 @@
+package trader
+
+import (
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"testing"
+)
+
+// TestFeeSchedulesRegistered tests every supported exchange has a default fee schedule for fee estimates
+func TestFeeSchedulesRegistered(t *testing.T) {
+	for _, exchange := range []string{market.VenueBinance, market.VenueBybit, market.VenueOKX, market.VenueMEXC,
+		market.VenueCoinbase, market.VenueHyperliquid, market.VenueAster, market.VenueLighter} {
+		if _, ok := logger.GetFeeSchedule(exchange); !ok {
+			t.Errorf("no fee schedule registered for %s", exchange)
+		}
+	}
+	if fee := logger.EstimateFee(market.VenueBinance, 10000, false); fee != 5 {
+		t.Errorf("expected 5 USDT taker fee on 10000 USDT at binance, got %.4f", fee)
+	}
+}
+
+// TestExpectedValueGateWithoutFees tests the gate isn't applied with fees it doesn't know
+func TestExpectedValueGateWithoutFees(t *testing.T) {
+	strategy := &store.StrategyConfig{}
+	strategy.RiskControl.ExpectedValueGate = true
+	strategy.RiskControl.MinExpectedValuePct = 100
+	at := &AutoTrader{exchange: "unknown", config: AutoTraderConfig{StrategyConfig: strategy}}
+
+	decisions := []decision.Decision{{Symbol: "BTCUSDT", Action: "open_long", Confidence: 10}}
+	kept, notes := at.applyExpectedValueGate(decisions, nil)
+	if len(kept) != 1 || len(notes) != 0 {
+		t.Errorf("expected decisions unchanged without a fee schedule, got %+v %v", kept, notes)
+	}
+}
@@ -0,0 +1,170 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"nofx/config"
+	"nofx/decision"
+	"sort"
+	"time"
+)
+
+// Exposure event types
+const (
+	EventExposureAlert  = "exposure_alert"
+	EventExposureNormal = "exposure_normal"
+)
+
+// ExposurePosition notional exposure of one position
+type ExposurePosition struct {
+	Symbol   string  `json:"symbol"`
+	Side     string  `json:"side"`
+	Notional float64 `json:"notional"`
+	Leverage int     `json:"leverage"`
+}
+
+// ExposureSnapshot exchange account exposure as seen by one trader in one cycle
+// Positions are account-wide, every trader on the same exchange account sees the same book
+type ExposureSnapshot struct {
+	TraderID          string             `json:"trader_id"`
+	ExchangeID        string             `json:"exchange_id"`
+	Time              time.Time          `json:"time"`
+	Equity            float64            `json:"equity"`
+	LongNotional      float64            `json:"long_notional"`
+	ShortNotional     float64            `json:"short_notional"`
+	NetNotional       float64            `json:"net_notional"`       // Long - short
+	GrossNotional     float64            `json:"gross_notional"`     // Long + short
+	Leverage          float64            `json:"leverage"`           // Gross notional / equity
+	MarginUsed        float64            `json:"margin_used"`        // Initial margin of open positions
+	MarginUtilization float64            `json:"margin_utilization"` // Margin used / equity (%)
+	Positions         []ExposurePosition `json:"positions"`
+}
+
+// ComputeExposure aggregates positions into long/short/net/gross notional, leverage and margin utilization
+func ComputeExposure(positions []decision.PositionInfo, equity float64) ExposureSnapshot {
+	snapshot := ExposureSnapshot{Time: time.Now(), Equity: equity, Positions: []ExposurePosition{}}
+	for _, pos := range positions {
+		notional := math.Abs(pos.Quantity) * pos.MarkPrice
+		if notional == 0 {
+			continue
+		}
+		if pos.Side == "short" {
+			snapshot.ShortNotional += notional
+		} else {
+			snapshot.LongNotional += notional
+		}
+		margin := pos.MarginUsed
+		if margin == 0 && pos.Leverage > 0 {
+			margin = notional / float64(pos.Leverage)
+		}
+		snapshot.MarginUsed += margin
+		snapshot.Positions = append(snapshot.Positions, ExposurePosition{
+			Symbol:   pos.Symbol,
+			Side:     pos.Side,
+			Notional: notional,
+			Leverage: pos.Leverage,
+		})
+	}
+	sort.Slice(snapshot.Positions, func(i, j int) bool {
+		return snapshot.Positions[i].Notional > snapshot.Positions[j].Notional
+	})
+
+	snapshot.NetNotional = snapshot.LongNotional - snapshot.ShortNotional
+	snapshot.GrossNotional = snapshot.LongNotional + snapshot.ShortNotional
+	if equity > 0 {
+		snapshot.Leverage = snapshot.GrossNotional / equity
+		snapshot.MarginUtilization = snapshot.MarginUsed / equity * 100
+	}
+	return snapshot
+}
+
+// exposureBreach describes which alert threshold the snapshot exceeds ("" if none)
+func exposureBreach(s ExposureSnapshot, limits config.ExposureAlertConfig) string {
+	if limits.MaxLeverage > 0 && s.Leverage > limits.MaxLeverage {
+		return fmt.Sprintf("account leverage %.2fx exceeds %.2fx (gross %.2f on equity %.2f)",
+			s.Leverage, limits.MaxLeverage, s.GrossNotional, s.Equity)
+	}
+	if limits.MaxMarginPct > 0 && s.MarginUtilization > limits.MaxMarginPct {
+		return fmt.Sprintf("margin utilization %.1f%% exceeds %.1f%% (margin %.2f on equity %.2f)",
+			s.MarginUtilization, limits.MaxMarginPct, s.MarginUsed, s.Equity)
+	}
+	return ""
+}
+
+// updateExposure records this cycle's exposure snapshot and raises/clears the exposure alert
+func (at *AutoTrader) updateExposure(ctx *decision.Context) {
+	snapshot := ComputeExposure(ctx.Positions, ctx.Account.TotalEquity)
+	snapshot.TraderID = at.id
+	snapshot.ExchangeID = at.exchangeID
+
+	breach := exposureBreach(snapshot, config.Get().ExposureAlert)
+
+	at.exposureMu.Lock()
+	at.lastExposure = &snapshot
+	wasAlerting := at.exposureAlerting
+	at.exposureAlerting = breach != ""
+	at.exposureMu.Unlock()
+
+	if at.config.OnEvent == nil || (breach != "") == wasAlerting {
+		return
+	}
+	event := TraderEvent{
+		TraderID:   at.id,
+		Type:       EventExposureAlert,
+		Exchange:   at.exchange,
+		ExchangeID: at.exchangeID,
+		Message:    breach,
+		Time:       snapshot.Time,
+	}
+	if breach == "" {
+		event.Type = EventExposureNormal
+		event.Message = fmt.Sprintf("account exposure back within limits (leverage %.2fx, margin utilization %.1f%%)",
+			snapshot.Leverage, snapshot.MarginUtilization)
+	}
+	at.config.OnEvent(event)
+}
+
+// GetExposure returns the exposure snapshot of the last cycle (nil before the first cycle)
+func (at *AutoTrader) GetExposure() *ExposureSnapshot {
+	at.exposureMu.RLock()
+	defer at.exposureMu.RUnlock()
+	return at.lastExposure
+}
+
+// GetExchangeID returns the exchange account UUID
+func (at *AutoTrader) GetExchangeID() string {
+	return at.exchangeID
+}
+
+// AccountExposure exposure of one exchange account across the traders using it
+// TraderID/Time identify the snapshot the account figures were taken from
+type AccountExposure struct {
+	ExposureSnapshot
+	Traders []string `json:"traders"` // Trader IDs on this account that reported a snapshot
+}
+
+// AggregateExposure combines trader snapshots of one exchange account
+// The exchange reports positions account-wide, so the newest snapshot is the account's current book;
+// summing snapshots would count shared positions once per trader
+func AggregateExposure(exchangeID string, snapshots []*ExposureSnapshot) *AccountExposure {
+	account := &AccountExposure{Traders: []string{}}
+	var newest *ExposureSnapshot
+	for _, s := range snapshots {
+		if s == nil {
+			continue
+		}
+		account.Traders = append(account.Traders, s.TraderID)
+		if newest == nil || s.Time.After(newest.Time) {
+			newest = s
+		}
+	}
+	if newest != nil {
+		account.ExposureSnapshot = *newest
+		account.ExposureSnapshot.Positions = append([]ExposurePosition(nil), newest.Positions...)
+	} else {
+		account.Positions = []ExposurePosition{}
+	}
+	account.ExchangeID = exchangeID
+	sort.Strings(account.Traders)
+	return account
+}
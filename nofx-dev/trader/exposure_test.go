@@ -0,0 +1,100 @@
+package trader
+
+import (
+	"math"
+	"nofx/config"
+	"nofx/decision"
+	"testing"
+	"time"
+)
+
+// TestComputeExposure tests long/short/net/gross notional, leverage and margin utilization
+func TestComputeExposure(t *testing.T) {
+	positions := []decision.PositionInfo{
+		{Symbol: "BTCUSDT", Side: "long", Quantity: 0.1, MarkPrice: 50000, Leverage: 10}, // 5000 notional, 500 margin
+		{Symbol: "ETHUSDT", Side: "short", Quantity: -1, MarkPrice: 3000, Leverage: 5},   // 3000 notional, 600 margin
+		{Symbol: "SOLUSDT", Side: "long", Quantity: 10, MarkPrice: 100, MarginUsed: 250}, // 1000 notional, reported margin
+		{Symbol: "DOGEUSDT", Side: "long", Quantity: 0, MarkPrice: 0.1, Leverage: 3},     // Empty, ignored
+	}
+	s := ComputeExposure(positions, 2000)
+
+	check := func(name string, got, want float64) {
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: expected %.4f, got %.4f", name, want, got)
+		}
+	}
+	check("long", s.LongNotional, 6000)
+	check("short", s.ShortNotional, 3000)
+	check("net", s.NetNotional, 3000)
+	check("gross", s.GrossNotional, 9000)
+	check("leverage", s.Leverage, 4.5)
+	check("margin", s.MarginUsed, 1350)
+	check("utilization", s.MarginUtilization, 67.5)
+	if len(s.Positions) != 3 || s.Positions[0].Symbol != "BTCUSDT" {
+		t.Errorf("expected 3 positions sorted by notional, got %+v", s.Positions)
+	}
+
+	limits := config.ExposureAlertConfig{MaxLeverage: 5, MaxMarginPct: 80}
+	if breach := exposureBreach(s, limits); breach != "" {
+		t.Errorf("expected no breach, got %q", breach)
+	}
+	limits.MaxMarginPct = 60
+	if breach := exposureBreach(s, limits); breach == "" {
+		t.Errorf("expected margin utilization breach")
+	}
+}
+
+// TestAggregateExposure tests that the newest snapshot of a shared account wins
+func TestAggregateExposure(t *testing.T) {
+	now := time.Now()
+	older := &ExposureSnapshot{TraderID: "b", Time: now.Add(-time.Minute), GrossNotional: 9000}
+	newer := &ExposureSnapshot{TraderID: "a", Time: now, GrossNotional: 4000}
+
+	account := AggregateExposure("ex-1", []*ExposureSnapshot{older, nil, newer})
+	if account.GrossNotional != 4000 || account.TraderID != "a" {
+		t.Errorf("expected newest snapshot (trader a, gross 4000), got trader %s gross %.2f", account.TraderID, account.GrossNotional)
+	}
+	if len(account.Traders) != 2 || account.Traders[0] != "a" || account.ExchangeID != "ex-1" {
+		t.Errorf("unexpected traders/exchange: %v %s", account.Traders, account.ExchangeID)
+	}
+
+	empty := AggregateExposure("ex-2", nil)
+	if empty.Positions == nil || len(empty.Traders) != 0 {
+		t.Errorf("expected empty non-nil exposure, got %+v", empty)
+	}
+}
+
+// TestExposureLimitBreach tests total, per-symbol and user-wide exposure caps
+func TestExposureLimitBreach(t *testing.T) {
+	own := exposureBySymbol([]Position{
+		{Symbol: "BTCUSDT", Side: "long", Quantity: 0.01, MarkPrice: 50000}, // 500
+		{Symbol: "ETHUSDT", Side: "short", Quantity: 0.1, EntryPrice: 3000}, // 300, no mark price
+	})
+	if own["BTCUSDT"] != 500 || own["ETHUSDT"] != 300 {
+		t.Fatalf("unexpected exposure by symbol: %v", own)
+	}
+	user := map[string]float64{"SOLUSDT": 1000}
+
+	tests := []struct {
+		name                      string
+		symbol                    string
+		add                       float64
+		maxTotal, maxSym, maxUser float64
+		blocked                   bool
+	}{
+		{"no caps", "BTCUSDT", 1e6, 0, 0, 0, false},
+		{"within symbol cap", "BTCUSDT", 500, 0, 1000, 0, false},
+		{"symbol cap", "BTCUSDT", 501, 0, 1000, 0, true},
+		{"symbol cap other symbol", "SOLUSDT", 900, 0, 1000, 0, false},
+		{"total cap", "SOLUSDT", 300, 1000, 0, 0, true},
+		{"within total cap", "SOLUSDT", 200, 1000, 0, 0, false},
+		{"user cap", "BTCUSDT", 201, 0, 0, 2000, true},
+		{"within user cap", "BTCUSDT", 200, 0, 0, 2000, false},
+	}
+	for _, tt := range tests {
+		breach := exposureLimitBreach(tt.symbol, tt.add, own, user, tt.maxTotal, tt.maxSym, tt.maxUser)
+		if (breach != "") != tt.blocked {
+			t.Errorf("%s: blocked=%v, want %v (%q)", tt.name, breach != "", tt.blocked, breach)
+		}
+	}
+}
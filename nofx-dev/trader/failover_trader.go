@@ -0,0 +1,620 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+	"nofx/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sentinel errors returned while the primary exchange circuit is open
+var (
+	ErrEntriesSuspended = errors.New("new entries suspended: primary exchange circuit open and no fallback available")
+	ErrPositionReadOnly = errors.New("position is read-only until its exchange recovers")
+)
+
+// Trader event types emitted to the manager
+const (
+	EventExchangeCircuitOpen   = "exchange_circuit_open"
+	EventExchangeCircuitClosed = "exchange_circuit_closed"
+)
+
+// TraderEvent notable trader state change, delivered to the manager through AutoTraderConfig.OnEvent
+type TraderEvent struct {
+	TraderID   string    `json:"trader_id"`
+	Type       string    `json:"type"`
+	Exchange   string    `json:"exchange"`    // Exchange type the event is about
+	ExchangeID string    `json:"exchange_id"` // Exchange account UUID
+	Message    string    `json:"message"`
+	Time       time.Time `json:"time"`
+
+	Details map[string]interface{} `json:"details,omitempty"` // Structured data for events that carry it (e.g. reconciliation)
+}
+
+// failoverLeg one exchange account behind the failover router
+type failoverLeg struct {
+	Trader
+	role       string // "primary" or "fallback"
+	exchange   string
+	exchangeID string
+	breaker    *CircuitBreaker
+}
+
+// FailoverTrader routes calls between a primary and an optional fallback exchange
+// While the primary's circuit is open, new entries go to the fallback (or are suspended without one)
+// and positions held on the primary are read-only: they stay visible from the last snapshot but
+// no orders are sent for them until the primary recovers. Optional capabilities are reached through
+// capability / accountTraders, which pick the account holding the position
+type FailoverTrader struct {
+	primary  *failoverLeg
+	fallback *failoverLeg
+	onEvent  func(TraderEvent)
+
+	mu                   sync.RWMutex
+	lastPrimaryPositions []Position
+	lastPrimaryBalance   map[string]interface{}
+	fallbackPositions    map[string]bool       // symbol_SIDE -> held on fallback
+	orderLegs            map[string]orderRoute // orderID -> exchange the order was placed on, until it's done
+}
+
+// orderRoute exchange an order was placed on
+type orderRoute struct {
+	leg      *failoverLeg
+	placedAt time.Time
+}
+
+// orderRouteTTL orders whose status is never looked up are forgotten after this long
+const orderRouteTTL = 24 * time.Hour
+
+// NewFailoverTrader creates a failover router with a circuit breaker on the primary exchange
+func NewFailoverTrader(primary Trader, exchange, exchangeID string) *FailoverTrader {
+	return &FailoverTrader{
+		primary: &failoverLeg{
+			Trader:     primary,
+			role:       "primary",
+			exchange:   exchange,
+			exchangeID: exchangeID,
+			breaker:    NewCircuitBreaker(0, 0),
+		},
+		fallbackPositions: make(map[string]bool),
+		orderLegs:         make(map[string]orderRoute),
+	}
+}
+
+// SetFallback configures the exchange that takes new entries while the primary is failing
+func (f *FailoverTrader) SetFallback(fallback Trader, exchange, exchangeID string) {
+	f.fallback = &failoverLeg{
+		Trader:     fallback,
+		role:       "fallback",
+		exchange:   exchange,
+		exchangeID: exchangeID,
+		breaker:    NewCircuitBreaker(0, 0),
+	}
+}
+
+// OnEvent sets the callback receiving circuit open/close events
+func (f *FailoverTrader) OnEvent(fn func(TraderEvent)) {
+	f.onEvent = fn
+}
+
+// capability returns the optional capability T of the exchange account holding symbol's position (side "" matches
+// either side, symbol "" is the primary account). It is called through that account's wrappers (error
+// classification, reduce-only checks, fault injection); ok=false when its exchange lacks T or its circuit is open
+func capability[T any](t Trader, symbol, side string) (T, bool) {
+	var zero T
+	if f, ok := t.(*FailoverTrader); ok {
+		leg := f.primary
+		if symbol != "" {
+			leg = f.positionLeg(symbol, side)
+		}
+		if leg.breaker.State() == CircuitOpen {
+			return zero, false
+		}
+		t = leg.Trader
+	}
+	if _, ok := unwrapTrader(t).(T); !ok {
+		return zero, false
+	}
+	c, ok := any(t).(T)
+	return c, ok
+}
+
+// accountTraders the wrapped trader of each exchange account behind t (primary first), for account-wide capabilities
+func accountTraders(t Trader) []Trader {
+	f, ok := t.(*FailoverTrader)
+	if !ok {
+		return []Trader{t}
+	}
+	traders := []Trader{f.primary.Trader}
+	if f.fallback != nil {
+		traders = append(traders, f.fallback.Trader)
+	}
+	return traders
+}
+
+// call runs op against a leg, failing fast while its circuit is open
+func (f *FailoverTrader) call(leg *failoverLeg, op func() error) error {
+	if !leg.breaker.Allow() {
+		return fmt.Errorf("%w (%s %s)", ErrCircuitOpen, leg.role, leg.exchange)
+	}
+	err := op()
+	if state, changed := leg.breaker.Record(err); changed {
+		f.notify(leg, state)
+	}
+	return err
+}
+
+// notify logs a circuit transition and emits an event to the manager
+func (f *FailoverTrader) notify(leg *failoverLeg, state CircuitState) {
+	event := TraderEvent{Exchange: leg.exchange, ExchangeID: leg.exchangeID, Time: time.Now()}
+	if state == CircuitOpen {
+		event.Type = EventExchangeCircuitOpen
+		switch {
+		case leg.role == "fallback":
+			event.Message = fmt.Sprintf("Fallback exchange %s is failing, its positions are read-only", leg.exchange)
+		case f.fallback != nil:
+			event.Message = fmt.Sprintf("Primary exchange %s is failing, new entries go to fallback %s and existing positions are read-only",
+				leg.exchange, f.fallback.exchange)
+		default:
+			event.Message = fmt.Sprintf("Primary exchange %s is failing, new entries are suspended and existing positions are read-only", leg.exchange)
+		}
+		logger.Warnf("🔌 %s", event.Message)
+	} else {
+		event.Type = EventExchangeCircuitClosed
+		event.Message = fmt.Sprintf("%s exchange %s recovered", strings.ToUpper(leg.role[:1])+leg.role[1:], leg.exchange)
+		logger.Infof("🔌 %s", event.Message)
+	}
+	if f.onEvent != nil {
+		f.onEvent(event)
+	}
+}
+
+// entryLeg returns the exchange new positions are opened on
+func (f *FailoverTrader) entryLeg() (*failoverLeg, error) {
+	if f.primary.breaker.State() == CircuitClosed {
+		return f.primary, nil
+	}
+	if f.fallback != nil && f.fallback.breaker.State() == CircuitClosed {
+		return f.fallback, nil
+	}
+	return nil, ErrEntriesSuspended
+}
+
+// positionLeg returns the exchange holding the position of symbol (side "" matches either side)
+func (f *FailoverTrader) positionLeg(symbol, side string) *failoverLeg {
+	if f.fallback == nil {
+		return f.primary
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if side != "" {
+		if f.fallbackPositions[positionKey(symbol, side)] {
+			return f.fallback
+		}
+		return f.primary
+	}
+	if f.fallbackPositions[positionKey(symbol, "LONG")] || f.fallbackPositions[positionKey(symbol, "SHORT")] {
+		return f.fallback
+	}
+	return f.primary
+}
+
+// callPosition runs op on the exchange holding the position, mapping an open circuit to ErrPositionReadOnly
+func (f *FailoverTrader) callPosition(leg *failoverLeg, op func() error) error {
+	err := f.call(leg, op)
+	if errors.Is(err, ErrCircuitOpen) {
+		return fmt.Errorf("%w (%s %s)", ErrPositionReadOnly, leg.role, leg.exchange)
+	}
+	return err
+}
+
+func positionKey(symbol, side string) string {
+	return symbol + "_" + strings.ToUpper(side)
+}
+
+// EntriesAllowed returns nil if new positions can be opened on some exchange
+func (f *FailoverTrader) EntriesAllowed() error {
+	_, err := f.entryLeg()
+	return err
+}
+
+// FailedOver whether the primary circuit is not closed
+func (f *FailoverTrader) FailedOver() bool {
+	return f.primary.breaker.State() != CircuitClosed
+}
+
+// PositionExchange returns the exchange type and account UUID holding the position
+func (f *FailoverTrader) PositionExchange(symbol, side string) (string, string) {
+	leg := f.positionLeg(symbol, side)
+	return leg.exchange, leg.exchangeID
+}
+
+// Status returns circuit states for status reporting
+func (f *FailoverTrader) Status() map[string]interface{} {
+	status := map[string]interface{}{
+		"primary": f.primary.breaker.Status(),
+	}
+	if f.fallback != nil {
+		fallback := f.fallback.breaker.Status()
+		fallback["exchange"] = f.fallback.exchange
+		status["fallback"] = fallback
+	}
+	_, err := f.entryLeg()
+	status["entries_allowed"] = err == nil
+	return status
+}
+
+// Balance fields while failed over: equity is summed over both accounts so equity-based checks (daily loss,
+// sizing, snapshots) don't see the switch as a loss, available margin is the fallback's that takes the entries
+var (
+	failoverEquityKeys = []string{"totalWalletBalance", "totalUnrealizedProfit", "totalEquity"}
+	failoverEntryKeys  = []string{"availableBalance", "quoteBalances", "multiAssetsMargin"}
+)
+
+// GetBalance returns the primary's balance, or while it is failing its last snapshot plus the fallback's equity
+// (the snapshot alone when no fallback is available)
+func (f *FailoverTrader) GetBalance() (map[string]interface{}, error) {
+	var balance map[string]interface{}
+	err := f.call(f.primary, func() (err error) {
+		balance, err = f.primary.GetBalance()
+		return err
+	})
+	if err == nil {
+		f.mu.Lock()
+		f.lastPrimaryBalance = balance
+		f.mu.Unlock()
+		return balance, nil
+	}
+	if !f.FailedOver() {
+		return nil, err
+	}
+
+	f.mu.RLock()
+	snapshot := f.lastPrimaryBalance
+	f.mu.RUnlock()
+
+	if leg, legErr := f.entryLeg(); legErr == nil && leg == f.fallback {
+		var fbBalance map[string]interface{}
+		if fbErr := f.call(leg, func() (err error) {
+			fbBalance, err = leg.GetBalance()
+			return err
+		}); fbErr == nil {
+			if snapshot == nil {
+				return fbBalance, nil
+			}
+			return combinedBalance(snapshot, fbBalance), nil
+		}
+	}
+
+	if snapshot != nil {
+		return snapshot, nil
+	}
+	return nil, err
+}
+
+// combinedBalance the primary snapshot with the fallback's equity added and its available margin
+func combinedBalance(primary, fallback map[string]interface{}) map[string]interface{} {
+	combined := make(map[string]interface{}, len(primary))
+	for k, v := range primary {
+		combined[k] = v
+	}
+	for _, key := range failoverEquityKeys {
+		p, pOK := primary[key].(float64)
+		fb, fbOK := fallback[key].(float64)
+		if pOK || fbOK {
+			combined[key] = p + fb
+		}
+	}
+	for _, key := range failoverEntryKeys {
+		if v, ok := fallback[key]; ok {
+			combined[key] = v
+		} else {
+			delete(combined, key)
+		}
+	}
+	return combined
+}
+
+// GetPositions returns live primary positions (or the last snapshot marked readOnly while its circuit is open)
+// plus positions held on the fallback exchange
+func (f *FailoverTrader) GetPositions() ([]Position, error) {
+	var primaryPositions []Position
+	err := f.call(f.primary, func() (err error) {
+		primaryPositions, err = f.primary.GetPositions()
+		return err
+	})
+	if err == nil {
+		f.mu.Lock()
+		f.lastPrimaryPositions = primaryPositions
+		f.mu.Unlock()
+	} else {
+		if !f.FailedOver() {
+			return nil, err
+		}
+		f.mu.RLock()
+		snapshot := f.lastPrimaryPositions
+		f.mu.RUnlock()
+		primaryPositions = make([]Position, 0, len(snapshot))
+		for _, pos := range snapshot {
+			pos.ReadOnly = true
+			primaryPositions = append(primaryPositions, pos)
+		}
+	}
+
+	if f.fallback == nil {
+		return primaryPositions, nil
+	}
+
+	var fallbackPositions []Position
+	fbErr := f.call(f.fallback, func() (err error) {
+		fallbackPositions, err = f.fallback.GetPositions()
+		return err
+	})
+	if fbErr != nil {
+		// Keep routing closes to the fallback for positions we know about, just don't list them
+		return primaryPositions, nil
+	}
+
+	held := make(map[string]bool, len(fallbackPositions))
+	result := primaryPositions
+	for _, pos := range fallbackPositions {
+		held[positionKey(pos.Symbol, pos.Side)] = true
+		pos.Exchange = f.fallback.exchange
+		result = append(result, pos)
+	}
+	f.mu.Lock()
+	f.fallbackPositions = held
+	f.mu.Unlock()
+
+	return result, nil
+}
+
+// openOn opens a position on the entry exchange and remembers where it lives
+func (f *FailoverTrader) openOn(symbol, side string, open func(leg *failoverLeg) (map[string]interface{}, error)) (map[string]interface{}, error) {
+	leg, err := f.entryLeg()
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	err = f.call(leg, func() (err error) {
+		result, err = open(leg)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	if leg == f.fallback {
+		f.fallbackPositions[positionKey(symbol, side)] = true
+		logger.Infof("  🔀 %s %s opened on fallback exchange %s", symbol, side, leg.exchange)
+	}
+	f.mu.Unlock()
+	f.trackOrder(result, leg)
+	return result, nil
+}
+
+// trackOrder remembers the exchange an order was placed on, forgetting orders that were never looked up
+func (f *FailoverTrader) trackOrder(result map[string]interface{}, leg *failoverLeg) {
+	if result["orderId"] == nil {
+		return
+	}
+	now := time.Now()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for id, route := range f.orderLegs {
+		if now.Sub(route.placedAt) > orderRouteTTL {
+			delete(f.orderLegs, id)
+		}
+	}
+	f.orderLegs[fmt.Sprintf("%v", result["orderId"])] = orderRoute{leg: leg, placedAt: now}
+}
+
+// OpenLong opens a long position on the entry exchange
+func (f *FailoverTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return f.openOn(symbol, "LONG", func(leg *failoverLeg) (map[string]interface{}, error) {
+		return leg.OpenLong(symbol, quantity, leverage)
+	})
+}
+
+// OpenShort opens a short position on the entry exchange
+func (f *FailoverTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return f.openOn(symbol, "SHORT", func(leg *failoverLeg) (map[string]interface{}, error) {
+		return leg.OpenShort(symbol, quantity, leverage)
+	})
+}
+
+// OpenWithProtection opens a position with its stop loss / take profit in one batch on the entry exchange
+func (f *FailoverTrader) OpenWithProtection(symbol, positionSide string, quantity float64, leverage int, stopLoss, takeProfit float64) (map[string]interface{}, bool, error) {
+	leg, err := f.entryLeg()
+	if err != nil {
+		return nil, false, err
+	}
+	// Checked before the call, an unsupported batch says nothing about the exchange's health
+	if _, ok := batchEntryPlacer(leg.Trader); !ok {
+		return nil, false, ErrBatchUnsupported
+	}
+	protected := false
+	result, err := f.openOn(symbol, positionSide, func(leg *failoverLeg) (map[string]interface{}, error) {
+		placer, ok := batchEntryPlacer(leg.Trader)
+		if !ok {
+			return nil, ErrBatchUnsupported
+		}
+		order, placed, err := placer.OpenWithProtection(symbol, positionSide, quantity, leverage, stopLoss, takeProfit)
+		protected = placed
+		return order, err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return result, protected, nil
+}
+
+// closeOn closes a position on the exchange holding it
+func (f *FailoverTrader) closeOn(symbol, side string, close func(leg *failoverLeg) (map[string]interface{}, error)) (map[string]interface{}, error) {
+	leg := f.positionLeg(symbol, side)
+	var result map[string]interface{}
+	err := f.callPosition(leg, func() (err error) {
+		result, err = close(leg)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	f.trackOrder(result, leg)
+	return result, nil
+}
+
+// CloseLong closes a long position on the exchange holding it
+func (f *FailoverTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return f.closeOn(symbol, "LONG", func(leg *failoverLeg) (map[string]interface{}, error) {
+		return leg.CloseLong(symbol, quantity)
+	})
+}
+
+// CloseShort closes a short position on the exchange holding it
+func (f *FailoverTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return f.closeOn(symbol, "SHORT", func(leg *failoverLeg) (map[string]interface{}, error) {
+		return leg.CloseShort(symbol, quantity)
+	})
+}
+
+// SetLeverage sets leverage on the entry exchange (called before opening)
+func (f *FailoverTrader) SetLeverage(symbol string, leverage int) error {
+	leg, err := f.entryLeg()
+	if err != nil {
+		return err
+	}
+	return f.call(leg, func() error { return leg.SetLeverage(symbol, leverage) })
+}
+
+// SetMarginMode sets margin mode on the entry exchange (called before opening)
+func (f *FailoverTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	leg, err := f.entryLeg()
+	if err != nil {
+		return err
+	}
+	return f.call(leg, func() error { return leg.SetMarginMode(symbol, isCrossMargin) })
+}
+
+// GetMarketPrice gets price from the primary, or the fallback while the primary is failing
+func (f *FailoverTrader) GetMarketPrice(symbol string) (float64, error) {
+	var price float64
+	err := f.call(f.primary, func() (err error) {
+		price, err = f.primary.GetMarketPrice(symbol)
+		return err
+	})
+	if err != nil && f.fallback != nil && f.FailedOver() {
+		err = f.call(f.fallback, func() (err error) {
+			price, err = f.fallback.GetMarketPrice(symbol)
+			return err
+		})
+	}
+	return price, err
+}
+
+// SetStopLoss sets stop loss on the exchange holding the position
+func (f *FailoverTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	leg := f.positionLeg(symbol, positionSide)
+	return f.callPosition(leg, func() error { return leg.SetStopLoss(symbol, positionSide, quantity, stopPrice) })
+}
+
+// SetTakeProfit sets take profit on the exchange holding the position
+func (f *FailoverTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	leg := f.positionLeg(symbol, positionSide)
+	return f.callPosition(leg, func() error { return leg.SetTakeProfit(symbol, positionSide, quantity, takeProfitPrice) })
+}
+
+// CancelStopLossOrders cancels stop loss orders on the exchange holding the position
+func (f *FailoverTrader) CancelStopLossOrders(symbol string) error {
+	leg := f.positionLeg(symbol, "")
+	return f.callPosition(leg, func() error { return leg.CancelStopLossOrders(symbol) })
+}
+
+// CancelTakeProfitOrders cancels take profit orders on the exchange holding the position
+func (f *FailoverTrader) CancelTakeProfitOrders(symbol string) error {
+	leg := f.positionLeg(symbol, "")
+	return f.callPosition(leg, func() error { return leg.CancelTakeProfitOrders(symbol) })
+}
+
+// CancelAllOrders cancels pending orders on the exchange holding the position
+func (f *FailoverTrader) CancelAllOrders(symbol string) error {
+	leg := f.positionLeg(symbol, "")
+	return f.callPosition(leg, func() error { return leg.CancelAllOrders(symbol) })
+}
+
+// CancelStopOrders cancels stop loss/take profit orders on the exchange holding the position
+func (f *FailoverTrader) CancelStopOrders(symbol string) error {
+	leg := f.positionLeg(symbol, "")
+	return f.callPosition(leg, func() error { return leg.CancelStopOrders(symbol) })
+}
+
+// FormatQuantity formats quantity with the precision of the entry exchange
+func (f *FailoverTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	leg, err := f.entryLeg()
+	if err != nil {
+		leg = f.positionLeg(symbol, "")
+	}
+	var formatted string
+	err = f.call(leg, func() (err error) {
+		formatted, err = leg.FormatQuantity(symbol, quantity)
+		return err
+	})
+	return formatted, err
+}
+
+// GetOrderStatus gets order status from the exchange the order was placed on
+func (f *FailoverTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	f.mu.RLock()
+	route, ok := f.orderLegs[orderID]
+	f.mu.RUnlock()
+	leg := f.primary
+	if ok {
+		leg = route.leg
+	}
+	var status map[string]interface{}
+	err := f.call(leg, func() (err error) {
+		status, err = leg.GetOrderStatus(symbol, orderID)
+		return err
+	})
+	if err == nil && ok && orderDone(status) {
+		f.mu.Lock()
+		delete(f.orderLegs, orderID)
+		f.mu.Unlock()
+	}
+	return status, err
+}
+
+// orderDone reports whether a looked-up order reached a terminal status
+func orderDone(order map[string]interface{}) bool {
+	switch strings.ToUpper(fmt.Sprintf("%v", order["status"])) {
+	case "FILLED", "CANCELED", "CANCELLED", "REJECTED", "EXPIRED":
+		return true
+	}
+	return false
+}
+
+// GetClosedPnL gets closed PnL records from the primary and, if configured, the fallback
+func (f *FailoverTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	var records []ClosedPnLRecord
+	err := f.call(f.primary, func() (err error) {
+		records, err = f.primary.GetClosedPnL(startTime, limit)
+		return err
+	})
+	if f.fallback == nil {
+		return records, err
+	}
+
+	var fbRecords []ClosedPnLRecord
+	fbErr := f.call(f.fallback, func() (err error) {
+		fbRecords, err = f.fallback.GetClosedPnL(startTime, limit)
+		return err
+	})
+	if err != nil && fbErr != nil {
+		return nil, err
+	}
+	return append(records, fbRecords...), nil
+}
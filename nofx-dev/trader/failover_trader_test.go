@@ -0,0 +1,256 @@
+package trader
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// stubExchange minimal Trader for failover routing tests (unimplemented methods panic)
+type stubExchange struct {
+	Trader
+	name      string
+	err       error
+	positions []Position
+	opened    []string
+	closed    []string
+}
+
+func (s *stubExchange) GetPositions() ([]Position, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.positions, nil
+}
+
+func (s *stubExchange) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.opened = append(s.opened, symbol)
+	return map[string]interface{}{"orderId": s.name + "-1"}, nil
+}
+
+func (s *stubExchange) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.closed = append(s.closed, symbol)
+	return map[string]interface{}{"orderId": s.name + "-2"}, nil
+}
+
+// TestCircuitBreaker tests open after threshold, half-open probe and which errors count
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	b := NewCircuitBreaker(3, time.Minute)
+	b.now = func() time.Time { return now }
+
+	netErr := ClassifyError("binance", "GetBalance", io.ErrUnexpectedEOF)
+	rejected := &ExchangeError{Exchange: "binance", Kind: ErrKindInsufficientMargin, Err: errors.New("margin is insufficient")}
+
+	b.Record(netErr)
+	b.Record(netErr)
+	b.Record(rejected) // API reachable, resets the count
+	b.Record(netErr)
+	if state, _ := b.Record(netErr); state != CircuitClosed {
+		t.Fatalf("expected closed after non-consecutive failures, got %s", state)
+	}
+	if state, changed := b.Record(netErr); state != CircuitOpen || !changed {
+		t.Fatalf("expected open transition, got %s (changed=%v)", state, changed)
+	}
+	if b.Allow() {
+		t.Fatal("open circuit should reject calls")
+	}
+
+	now = now.Add(time.Minute)
+	if !b.Allow() || b.State() != CircuitHalfOpen {
+		t.Fatalf("expected half-open probe after cooldown, got %s", b.State())
+	}
+	if _, changed := b.Record(netErr); changed || b.Allow() {
+		t.Fatal("failed probe should reopen without reporting a transition")
+	}
+
+	now = now.Add(time.Minute)
+	b.Allow()
+	if state, changed := b.Record(nil); state != CircuitClosed || !changed {
+		t.Fatalf("expected closed transition after successful probe, got %s (changed=%v)", state, changed)
+	}
+}
+
+// TestFailoverTrader tests entries move to the fallback and primary positions become read-only
+func TestFailoverTrader(t *testing.T) {
+	primary := &stubExchange{name: "primary", positions: []Position{
+		{Symbol: "BTCUSDT", Side: "long", Quantity: 0.1},
+	}}
+	fallback := &stubExchange{name: "fallback"}
+
+	f := NewFailoverTrader(primary, "binance", "ex-1")
+	f.SetFallback(fallback, "bybit", "ex-2")
+	var events []TraderEvent
+	f.OnEvent(func(e TraderEvent) { events = append(events, e) })
+
+	if _, err := f.GetPositions(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Primary goes down until the circuit opens
+	primary.err = ClassifyError("binance", "GetPositions", io.ErrUnexpectedEOF)
+	for i := 0; i < defaultCircuitThreshold; i++ {
+		f.GetPositions()
+	}
+	if len(events) != 1 || events[0].Type != EventExchangeCircuitOpen {
+		t.Fatalf("expected one circuit open event, got %+v", events)
+	}
+
+	positions, err := f.GetPositions()
+	if err != nil {
+		t.Fatalf("expected snapshot while circuit open, got error: %v", err)
+	}
+	if len(positions) != 1 || !positions[0].ReadOnly {
+		t.Fatalf("expected read-only snapshot position, got %+v", positions)
+	}
+
+	// New entry goes to the fallback and is tracked there
+	if _, err := f.OpenLong("ETHUSDT", 1, 5); err != nil {
+		t.Fatalf("open on fallback failed: %v", err)
+	}
+	if len(fallback.opened) != 1 {
+		t.Fatalf("expected entry on fallback, got %v", fallback.opened)
+	}
+	if ex, id := f.PositionExchange("ETHUSDT", "LONG"); ex != "bybit" || id != "ex-2" {
+		t.Errorf("expected ETHUSDT on bybit/ex-2, got %s/%s", ex, id)
+	}
+	if _, err := f.CloseLong("ETHUSDT", 0); err != nil || len(fallback.closed) != 1 {
+		t.Errorf("expected close routed to fallback, err=%v closed=%v", err, fallback.closed)
+	}
+
+	// Primary position can't be closed while its circuit is open
+	if _, err := f.CloseLong("BTCUSDT", 0); !errors.Is(err, ErrPositionReadOnly) {
+		t.Errorf("expected read-only error, got %v", err)
+	}
+
+	// Without a usable fallback entries are suspended
+	fallback.err = ClassifyError("bybit", "OpenLong", io.ErrUnexpectedEOF)
+	for i := 0; i < defaultCircuitThreshold; i++ {
+		f.OpenLong("SOLUSDT", 1, 5)
+	}
+	if err := f.EntriesAllowed(); !errors.Is(err, ErrEntriesSuspended) {
+		t.Errorf("expected entries suspended, got %v", err)
+	}
+}
+
+// trailingExchange stubExchange with native trailing stops
+type trailingExchange struct {
+	*stubExchange
+	trailing []string
+}
+
+func (t *trailingExchange) SetTrailingStop(symbol string, positionSide string, quantity, callbackPct float64) error {
+	t.trailing = append(t.trailing, symbol)
+	return nil
+}
+
+// TestCapability tests optional capabilities are routed through the wrappers of the account holding the position,
+// also when the fallback is a second account on the same exchange
+func TestCapability(t *testing.T) {
+	primary := &trailingExchange{stubExchange: &stubExchange{name: "primary"}}
+	fallback := &trailingExchange{stubExchange: &stubExchange{name: "fallback", positions: []Position{{Symbol: "ETHUSDT", Side: "long", Quantity: 1}}}}
+	failover := NewFailoverTrader(NewReduceOnlyTrader(NewClassifiedTrader(primary, "binance"), false), "binance", "acc-1")
+	failover.SetFallback(NewClassifiedTrader(fallback, "binance"), "binance", "acc-2")
+	if _, err := failover.GetPositions(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for symbol, want := range map[string]*trailingExchange{"BTCUSDT": primary, "ETHUSDT": fallback} {
+		setter, ok := capability[TrailingStopSetter](failover, symbol, "LONG")
+		if !ok {
+			t.Fatalf("expected a trailing stop capability for %s", symbol)
+		}
+		if _, wrapped := setter.(*trailingExchange); wrapped {
+			t.Errorf("%s: expected the call to go through the wrappers", symbol)
+		}
+		setter.SetTrailingStop(symbol, "LONG", 0, 1)
+		if len(want.trailing) != 1 || want.trailing[0] != symbol {
+			t.Errorf("expected %s routed to %s, got %v", symbol, want.name, want.trailing)
+		}
+	}
+
+	// An exchange without the capability reports none, even through wrappers that implement it
+	plain := NewFailoverTrader(NewClassifiedTrader(&stubExchange{name: "plain"}, "okx"), "okx", "acc-3")
+	if _, ok := capability[TrailingStopSetter](plain, "BTCUSDT", "LONG"); ok {
+		t.Error("expected no capability when the exchange adapter lacks it")
+	}
+}
+
+// statusExchange stubExchange reporting an order status
+type statusExchange struct {
+	*stubExchange
+	status string
+}
+
+func (s *statusExchange) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	return map[string]interface{}{"status": s.status}, nil
+}
+
+// TestFailoverOrderRoutes tests order routes are dropped once the order is done
+func TestFailoverOrderRoutes(t *testing.T) {
+	primary := &statusExchange{stubExchange: &stubExchange{name: "primary"}, status: "NEW"}
+	f := NewFailoverTrader(primary, "binance", "acc-1")
+	if _, err := f.OpenLong("BTCUSDT", 1, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := f.GetOrderStatus("BTCUSDT", "primary-1"); err != nil || len(f.orderLegs) != 1 {
+		t.Fatalf("expected the open order's route kept, err=%v routes=%d", err, len(f.orderLegs))
+	}
+	primary.status = "FILLED"
+	if _, err := f.GetOrderStatus("BTCUSDT", "primary-1"); err != nil || len(f.orderLegs) != 0 {
+		t.Errorf("expected the filled order's route dropped, err=%v routes=%d", err, len(f.orderLegs))
+	}
+}
+
+// balanceExchange stubExchange reporting a balance
+type balanceExchange struct {
+	*stubExchange
+	balance map[string]interface{}
+}
+
+func (b *balanceExchange) GetBalance() (map[string]interface{}, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.balance, nil
+}
+
+// TestFailoverBalance tests equity stays the sum of both accounts while failed over
+func TestFailoverBalance(t *testing.T) {
+	primary := &balanceExchange{stubExchange: &stubExchange{name: "primary"}, balance: map[string]interface{}{
+		"totalWalletBalance": 1000.0, "totalUnrealizedProfit": 50.0, "availableBalance": 800.0,
+	}}
+	fallback := &balanceExchange{stubExchange: &stubExchange{name: "fallback"}, balance: map[string]interface{}{
+		"totalWalletBalance": 200.0, "totalUnrealizedProfit": -10.0, "availableBalance": 150.0,
+	}}
+	f := NewFailoverTrader(primary, "binance", "acc-1")
+	f.SetFallback(fallback, "binance", "acc-2")
+	if _, err := f.GetBalance(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	primary.err = ClassifyError("binance", "GetBalance", io.ErrUnexpectedEOF)
+	for i := 0; i < defaultCircuitThreshold; i++ {
+		f.GetBalance()
+	}
+	balance, err := f.GetBalance()
+	if err != nil {
+		t.Fatalf("unexpected error while failed over: %v", err)
+	}
+	if balance["totalWalletBalance"] != 1200.0 || balance["totalUnrealizedProfit"] != 40.0 {
+		t.Errorf("expected equity of both accounts, got %+v", balance)
+	}
+	if balance["availableBalance"] != 150.0 {
+		t.Errorf("expected the fallback's available margin, got %v", balance["availableBalance"])
+	}
+	if primary.balance["totalWalletBalance"] != 1000.0 {
+		t.Errorf("the primary snapshot must not be modified: %+v", primary.balance)
+	}
+}
@@ -0,0 +1,236 @@
+package trader
+
+import (
+	"fmt"
+	"math/rand"
+	"nofx/config"
+	"nofx/logger"
+	"nofx/market"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FaultInjectingTrader wraps a Trader and injects simulated exchange failures
+// (timeouts, rejected orders, partial fills, WebSocket drops) for chaos testing.
+// Only enabled for testnet traders so users can verify risk settings and recovery before going live.
+type FaultInjectingTrader struct {
+	Trader
+	cfg    config.FaultInjectionConfig
+	name   string
+	rng    *rand.Rand
+	rngMu  sync.Mutex
+	dropWS func()
+}
+
+// runningLiveTraders counts running non-testnet traders. They share the market WebSocket with testnet traders,
+// so simulated WebSocket drops are refused while any of them runs
+var runningLiveTraders atomic.Int32
+
+// isTestnetTrader whether the trader configuration points at an exchange testnet
+// Only Hyperliquid and LIGHTER adapters can connect to a testnet, the other exchanges always trade live
+func isTestnetTrader(config AutoTraderConfig) bool {
+	switch config.Exchange {
+	case "hyperliquid":
+		return config.HyperliquidTestnet
+	case "lighter":
+		return config.LighterTestnet
+	default:
+		return false
+	}
+}
+
+// faultInjectionConfig returns the global fault injection configuration
+func faultInjectionConfig() config.FaultInjectionConfig {
+	return config.Get().FaultInjection
+}
+
+// NewFaultInjectingTrader creates a fault-injecting wrapper around inner
+func NewFaultInjectingTrader(inner Trader, cfg config.FaultInjectionConfig, name string) *FaultInjectingTrader {
+	if cfg.PartialFillRatio <= 0 || cfg.PartialFillRatio >= 1 {
+		cfg.PartialFillRatio = 0.5
+	}
+	return &FaultInjectingTrader{
+		Trader: inner,
+		cfg:    cfg,
+		name:   name,
+		rng:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		dropWS: func() {
+			if market.WSMonitorCli != nil {
+				market.WSMonitorCli.SimulateDisconnect()
+			}
+		},
+	}
+}
+
+// Unwrap returns the wrapped trader
+func (f *FaultInjectingTrader) Unwrap() Trader {
+	return f.Trader
+}
+
+// roll returns true with the given probability
+func (f *FaultInjectingTrader) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	f.rngMu.Lock()
+	defer f.rngMu.Unlock()
+	return f.rng.Float64() < rate
+}
+
+// beforeCall applies latency and simulated timeout
+func (f *FaultInjectingTrader) beforeCall(op string) error {
+	if f.cfg.Latency > 0 {
+		time.Sleep(f.cfg.Latency)
+	}
+	if f.roll(f.cfg.TimeoutRate) {
+		logger.Warnf("⚡ [%s] Fault injection: simulated timeout on %s", f.name, op)
+		return fmt.Errorf("fault injection: %s request timed out (context deadline exceeded)", op)
+	}
+	return nil
+}
+
+// beforeOrder applies timeout and rejection faults to order placement
+func (f *FaultInjectingTrader) beforeOrder(op, symbol string) error {
+	if err := f.beforeCall(op); err != nil {
+		return err
+	}
+	if f.roll(f.cfg.RejectRate) {
+		logger.Warnf("⚡ [%s] Fault injection: simulated rejection on %s %s", f.name, op, symbol)
+		return fmt.Errorf("fault injection: %s %s order rejected by exchange", op, symbol)
+	}
+	return nil
+}
+
+// partialQuantity returns the quantity to actually send (reduced on simulated partial fill)
+func (f *FaultInjectingTrader) partialQuantity(op, symbol string, quantity float64) (float64, bool) {
+	if quantity <= 0 || !f.roll(f.cfg.PartialFillRate) {
+		return quantity, false
+	}
+	filled := quantity * f.cfg.PartialFillRatio
+	logger.Warnf("⚡ [%s] Fault injection: simulated partial fill on %s %s (%.6f of %.6f)", f.name, op, symbol, filled, quantity)
+	return filled, true
+}
+
+// markPartial annotates the order result with partial fill information
+func markPartial(result map[string]interface{}, requested, filled float64) map[string]interface{} {
+	if result == nil {
+		result = make(map[string]interface{})
+	}
+	result["partial_fill"] = true
+	result["requested_qty"] = requested
+	result["filled_qty"] = filled
+	return result
+}
+
+// GetBalance gets account balance (may time out)
+func (f *FaultInjectingTrader) GetBalance() (map[string]interface{}, error) {
+	if err := f.beforeCall("GetBalance"); err != nil {
+		return nil, err
+	}
+	return f.Trader.GetBalance()
+}
+
+// GetPositions gets positions (may time out or drop the market WebSocket)
+func (f *FaultInjectingTrader) GetPositions() ([]Position, error) {
+	if f.roll(f.cfg.WSDropRate) && f.dropWS != nil {
+		if n := runningLiveTraders.Load(); n > 0 {
+			logger.Warnf("⚡ [%s] Fault injection: WebSocket drop skipped, %d live trader(s) share the market WebSocket", f.name, n)
+		} else {
+			logger.Warnf("⚡ [%s] Fault injection: dropping market WebSocket", f.name)
+			f.dropWS()
+		}
+	}
+	if err := f.beforeCall("GetPositions"); err != nil {
+		return nil, err
+	}
+	return f.Trader.GetPositions()
+}
+
+// GetMarketPrice gets market price (may time out)
+func (f *FaultInjectingTrader) GetMarketPrice(symbol string) (float64, error) {
+	if err := f.beforeCall("GetMarketPrice"); err != nil {
+		return 0, err
+	}
+	return f.Trader.GetMarketPrice(symbol)
+}
+
+// OpenLong opens long position (may time out, be rejected or partially filled)
+func (f *FaultInjectingTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	if err := f.beforeOrder("OpenLong", symbol); err != nil {
+		return nil, err
+	}
+	qty, partial := f.partialQuantity("OpenLong", symbol, quantity)
+	result, err := f.Trader.OpenLong(symbol, qty, leverage)
+	if err != nil || !partial {
+		return result, err
+	}
+	return markPartial(result, quantity, qty), nil
+}
+
+// OpenShort opens short position (may time out, be rejected or partially filled)
+func (f *FaultInjectingTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	if err := f.beforeOrder("OpenShort", symbol); err != nil {
+		return nil, err
+	}
+	qty, partial := f.partialQuantity("OpenShort", symbol, quantity)
+	result, err := f.Trader.OpenShort(symbol, qty, leverage)
+	if err != nil || !partial {
+		return result, err
+	}
+	return markPartial(result, quantity, qty), nil
+}
+
+// CloseLong closes long position (may time out, be rejected or partially filled)
+func (f *FaultInjectingTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	if err := f.beforeOrder("CloseLong", symbol); err != nil {
+		return nil, err
+	}
+	qty, partial := f.partialQuantity("CloseLong", symbol, quantity)
+	result, err := f.Trader.CloseLong(symbol, qty)
+	if err != nil || !partial {
+		return result, err
+	}
+	return markPartial(result, quantity, qty), nil
+}
+
+// CloseShort closes short position (may time out, be rejected or partially filled)
+func (f *FaultInjectingTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	if err := f.beforeOrder("CloseShort", symbol); err != nil {
+		return nil, err
+	}
+	qty, partial := f.partialQuantity("CloseShort", symbol, quantity)
+	result, err := f.Trader.CloseShort(symbol, qty)
+	if err != nil || !partial {
+		return result, err
+	}
+	return markPartial(result, quantity, qty), nil
+}
+
+// OpenWithProtection opens a position with its stop loss / take profit in one batch (may time out or be rejected)
+func (f *FaultInjectingTrader) OpenWithProtection(symbol, positionSide string, quantity float64, leverage int, stopLoss, takeProfit float64) (map[string]interface{}, bool, error) {
+	placer, ok := batchEntryPlacer(f.Trader)
+	if !ok {
+		return nil, false, ErrBatchUnsupported
+	}
+	if err := f.beforeOrder("OpenWithProtection", symbol); err != nil {
+		return nil, false, err
+	}
+	return placer.OpenWithProtection(symbol, positionSide, quantity, leverage, stopLoss, takeProfit)
+}
+
+// SetStopLoss sets stop-loss order (may time out or be rejected)
+func (f *FaultInjectingTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if err := f.beforeOrder("SetStopLoss", symbol); err != nil {
+		return err
+	}
+	return f.Trader.SetStopLoss(symbol, positionSide, quantity, stopPrice)
+}
+
+// SetTakeProfit sets take-profit order (may time out or be rejected)
+func (f *FaultInjectingTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	if err := f.beforeOrder("SetTakeProfit", symbol); err != nil {
+		return err
+	}
+	return f.Trader.SetTakeProfit(symbol, positionSide, quantity, takeProfitPrice)
+}
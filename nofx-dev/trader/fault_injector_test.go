@@ -0,0 +1,45 @@
+package trader
+
+import (
+	"nofx/config"
+	"testing"
+)
+
+// TestFaultInjectorWSDrop tests the shared market WebSocket is only dropped while no live trader runs
+func TestFaultInjectorWSDrop(t *testing.T) {
+	drops := 0
+	f := NewFaultInjectingTrader(&stubExchange{name: "testnet"}, config.FaultInjectionConfig{Enabled: true, WSDropRate: 1}, "chaos")
+	f.dropWS = func() { drops++ }
+
+	if _, err := f.GetPositions(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if drops != 1 {
+		t.Fatalf("expected WebSocket dropped without live traders, got %d drops", drops)
+	}
+
+	runningLiveTraders.Add(1)
+	defer runningLiveTraders.Add(-1)
+	if _, err := f.GetPositions(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if drops != 1 {
+		t.Errorf("expected WebSocket drop refused while a live trader runs, got %d drops", drops)
+	}
+}
+
+// TestIsTestnetTrader tests which trader configurations allow fault injection
+func TestIsTestnetTrader(t *testing.T) {
+	if isTestnetTrader(AutoTraderConfig{Exchange: "binance"}) {
+		t.Error("binance trader should count as live")
+	}
+	if isTestnetTrader(AutoTraderConfig{Exchange: "bybit", HyperliquidTestnet: true, LighterTestnet: true}) {
+		t.Error("bybit trader should count as live whatever the testnet flags")
+	}
+	if !isTestnetTrader(AutoTraderConfig{Exchange: "hyperliquid", HyperliquidTestnet: true}) {
+		t.Error("hyperliquid testnet trader should count as testnet")
+	}
+	if !isTestnetTrader(AutoTraderConfig{Exchange: "lighter", LighterTestnet: true}) {
+		t.Error("lighter testnet trader should count as testnet")
+	}
+}
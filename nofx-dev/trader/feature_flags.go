@@ -0,0 +1,96 @@
+package trader
+
+import (
+	"nofx/config"
+	"nofx/logger"
+	"sync"
+)
+
+// featureFlagState the trader's feature flag overrides, flags without one follow the deployment
+type featureFlagState struct {
+	mu         sync.RWMutex
+	overrides  map[string]bool
+	makerFirst *bool // Maker-first execution last pushed to the exchange adapter
+}
+
+// MakerFirstSetter exchange adapters that can switch post-only limit entries on and off at runtime
+type MakerFirstSetter interface {
+	SetMakerFirst(enabled bool)
+}
+
+// ValidateFeatureFlags checks the flag names of per-trader overrides
+func ValidateFeatureFlags(flags map[string]bool) error {
+	return config.ValidateFeatureFlags(flags)
+}
+
+// copyFeatureFlags copies validated overrides (nil stays empty)
+func copyFeatureFlags(flags map[string]bool) (map[string]bool, error) {
+	if err := ValidateFeatureFlags(flags); err != nil {
+		return nil, err
+	}
+	copied := make(map[string]bool, len(flags))
+	for name, enabled := range flags {
+		copied[name] = enabled
+	}
+	return copied, nil
+}
+
+// FeatureEnabled reports whether the flag is on for this trader: trader override, else deployment override, else default
+func (at *AutoTrader) FeatureEnabled(name string) bool {
+	at.featureFlags.mu.RLock()
+	enabled, ok := at.featureFlags.overrides[name]
+	at.featureFlags.mu.RUnlock()
+	if ok {
+		return enabled
+	}
+	return config.FeatureEnabled(name)
+}
+
+// FeatureFlags returns the effective value of every known flag for this trader
+func (at *AutoTrader) FeatureFlags() map[string]bool {
+	flags := make(map[string]bool)
+	for _, f := range config.FeatureFlags() {
+		flags[f.Name] = at.FeatureEnabled(f.Name)
+	}
+	return flags
+}
+
+// SetFeatureFlags replaces the trader's flag overrides (flags left out follow the deployment)
+func (at *AutoTrader) SetFeatureFlags(flags map[string]bool) error {
+	copied, err := copyFeatureFlags(flags)
+	if err != nil {
+		return err
+	}
+	at.featureFlags.mu.Lock()
+	at.featureFlags.overrides = copied
+	at.featureFlags.mu.Unlock()
+	at.applyFeatureFlags()
+	return nil
+}
+
+// applyFeatureFlags pushes flags to the exchange adapters (fallback account included), called on every cycle so deployment-wide changes take
+// effect without a restart. Adapters without limit entries keep using market orders
+func (at *AutoTrader) applyFeatureFlags() {
+	var setters []MakerFirstSetter
+	for _, t := range accountTraders(at.trader) {
+		if setter, ok := capability[MakerFirstSetter](t, "", ""); ok {
+			setters = append(setters, setter)
+		}
+	}
+	if len(setters) == 0 {
+		return
+	}
+	enabled := at.FeatureEnabled(config.FlagMakerFirstExecution)
+
+	at.featureFlags.mu.Lock()
+	changed := at.featureFlags.makerFirst == nil || *at.featureFlags.makerFirst != enabled
+	at.featureFlags.makerFirst = &enabled
+	at.featureFlags.mu.Unlock()
+
+	if changed {
+		for _, setter := range setters {
+			setter.SetMakerFirst(enabled)
+		}
+		logger.Infof("🚩 [%s] Feature %s: %v", at.name, config.FlagMakerFirstExecution, enabled)
+	}
+}
@@ -0,0 +1,48 @@
+package trader
+
+import (
+	"nofx/config"
+	"testing"
+)
+
+// makerFirstTrader Trader recording maker-first switches
+type makerFirstTrader struct {
+	Trader
+	calls []bool
+}
+
+func (m *makerFirstTrader) SetMakerFirst(enabled bool) {
+	m.calls = append(m.calls, enabled)
+}
+
+// TestFeatureFlagResolution tests that trader overrides win over deployment overrides, which win over defaults,
+// and that maker-first execution is pushed to the (wrapped) exchange adapter only when it changes
+func TestFeatureFlagResolution(t *testing.T) {
+	defer config.SetFeatureFlagOverrides(nil)
+	if err := config.SetFeatureFlagOverrides(map[string]bool{config.FlagMakerFirstExecution: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	ex := &makerFirstTrader{}
+	at := &AutoTrader{name: "t1", trader: NewClassifiedTrader(ex, "binance")}
+	if !at.FeatureEnabled(config.FlagMakerFirstExecution) || at.FeatureEnabled(config.FlagEnsembleMode) {
+		t.Fatalf("expected the deployment flags without trader overrides, got %v", at.FeatureFlags())
+	}
+
+	at.applyFeatureFlags()
+	at.applyFeatureFlags()
+	if len(ex.calls) != 1 || !ex.calls[0] {
+		t.Fatalf("expected maker-first to be switched on once, got %v", ex.calls)
+	}
+
+	if err := at.SetFeatureFlags(map[string]bool{config.FlagMakerFirstExecution: false}); err != nil {
+		t.Fatal(err)
+	}
+	if at.FeatureEnabled(config.FlagMakerFirstExecution) || len(ex.calls) != 2 || ex.calls[1] {
+		t.Fatalf("trader override should switch maker-first off, got %v", ex.calls)
+	}
+
+	if err := at.SetFeatureFlags(map[string]bool{"turbo_mode": true}); err == nil {
+		t.Error("unknown flags should be rejected")
+	}
+}
@@ -0,0 +1,202 @@
+package trader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/store"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// fundingSyncInterval how often funding payments are fetched (exchanges settle every 1-8 hours)
+	fundingSyncInterval = 10 * time.Minute
+	// okxBillsPath account bills of the last 7 days
+	okxBillsPath = "/api/v5/account/bills"
+)
+
+// FundingPayment one funding settlement on the account
+type FundingPayment struct {
+	Symbol string    // Trading pair (e.g., "BTCUSDT")
+	Amount float64   // Received (+) / paid (-), in the quote asset
+	Time   time.Time // Settlement time
+}
+
+// FundingFeeLister optional capability for exchanges that report funding settlements
+type FundingFeeLister interface {
+	// GetFundingFees returns funding payments settled after startTime, oldest first
+	GetFundingFees(startTime time.Time) ([]FundingPayment, error)
+}
+
+// fundingState last funding settlement already attributed to positions
+type fundingState struct {
+	mu    sync.Mutex
+	since time.Time // Zero until the first sync, which starts at the trader's start
+}
+
+// GetFundingFees returns USDT-margined funding payments since startTime (first 1000)
+func (t *FuturesTrader) GetFundingFees(startTime time.Time) ([]FundingPayment, error) {
+	var payments []FundingPayment
+	if t.pm != nil {
+		incomes, err := t.pm.NewGetUMIncomeHistoryService().
+			IncomeType("FUNDING_FEE").
+			StartTime(startTime.UnixMilli()).
+			Limit(1000).
+			Do(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get funding history: %w", err)
+		}
+		for _, income := range incomes {
+			amount, _ := strconv.ParseFloat(income.Income, 64)
+			payments = append(payments, FundingPayment{Symbol: income.Symbol, Amount: amount, Time: time.UnixMilli(income.Time)})
+		}
+		return payments, nil
+	}
+
+	incomes, err := t.client.NewGetIncomeHistoryService().
+		IncomeType("FUNDING_FEE").
+		StartTime(startTime.UnixMilli()).
+		Limit(1000).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funding history: %w", err)
+	}
+	for _, income := range incomes {
+		amount, _ := strconv.ParseFloat(income.Income, 64)
+		payments = append(payments, FundingPayment{Symbol: income.Symbol, Amount: amount, Time: time.UnixMilli(income.Time)})
+	}
+	return payments, nil
+}
+
+// GetFundingFees returns swap funding payments since startTime from the account bills (last 100, 7 days at most)
+func (t *OKXTrader) GetFundingFees(startTime time.Time) ([]FundingPayment, error) {
+	path := fmt.Sprintf("%s?instType=SWAP&type=8&begin=%d", okxBillsPath, startTime.UnixMilli())
+	data, err := t.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funding bills: %w", err)
+	}
+	var bills []struct {
+		InstId string `json:"instId"`
+		BalChg string `json:"balChg"`
+		Ts     string `json:"ts"`
+	}
+	if err := json.Unmarshal(data, &bills); err != nil {
+		return nil, fmt.Errorf("failed to parse funding bills: %w", err)
+	}
+
+	// Bills are newest first
+	payments := make([]FundingPayment, 0, len(bills))
+	for i := len(bills) - 1; i >= 0; i-- {
+		amount, _ := strconv.ParseFloat(bills[i].BalChg, 64)
+		ts, _ := strconv.ParseInt(bills[i].Ts, 10, 64)
+		payments = append(payments, FundingPayment{Symbol: t.convertSymbolBack(bills[i].InstId), Amount: amount, Time: time.UnixMilli(ts)})
+	}
+	return payments, nil
+}
+
+// attributeFunding splits each payment over the positions of its symbol that were open at settlement,
+// by quantity (hedge mode holds a long and a short). Returns funding per position ID; payments of
+// symbols the trader didn't hold are dropped
+func attributeFunding(payments []FundingPayment, positions []*store.TraderPosition) map[int64]float64 {
+	accrued := make(map[int64]float64)
+	for _, p := range payments {
+		var holders []*store.TraderPosition
+		total := 0.0
+		for _, pos := range positions {
+			if pos.Symbol == p.Symbol && !pos.EntryTime.After(p.Time) && pos.Quantity > 0 {
+				holders = append(holders, pos)
+				total += pos.Quantity
+			}
+		}
+		for _, pos := range holders {
+			accrued[pos.ID] += p.Amount * pos.Quantity / total
+		}
+	}
+	return accrued
+}
+
+// syncFundingFees fetches funding settled since the last sync and accrues it on the open position records,
+// so the trade's realized P&L includes funding when it closes. Payments while the trader was stopped are not backfilled
+func (at *AutoTrader) syncFundingFees() {
+	var listers []FundingFeeLister
+	for _, t := range accountTraders(at.trader) {
+		if lister, ok := capability[FundingFeeLister](t, "", ""); ok {
+			listers = append(listers, lister)
+		}
+	}
+	if len(listers) == 0 || at.store == nil {
+		return
+	}
+
+	at.funding.mu.Lock()
+	defer at.funding.mu.Unlock()
+	if at.funding.since.IsZero() {
+		at.funding.since = at.startTime
+	}
+
+	// Positions on the fallback account accrue funding there, a failed account retries the whole window next time
+	var payments []FundingPayment
+	for _, lister := range listers {
+		fees, err := lister.GetFundingFees(at.funding.since.Add(time.Millisecond))
+		if err != nil {
+			logger.Infof("⚠️ [%s] Funding sync failed: %v", at.name, err)
+			return
+		}
+		payments = append(payments, fees...)
+	}
+	sort.SliceStable(payments, func(i, j int) bool { return payments[i].Time.Before(payments[j].Time) })
+	if len(payments) == 0 {
+		return
+	}
+	positions, err := at.store.Position().GetOpenPositions(at.id)
+	if err != nil {
+		logger.Infof("⚠️ [%s] Funding sync: failed to get open positions: %v", at.name, err)
+		return
+	}
+
+	for id, amount := range attributeFunding(payments, positions) {
+		if err := at.store.Position().AddFunding(id, amount); err != nil {
+			logger.Infof("⚠️ [%s] Failed to record funding on position %d: %v", at.name, id, err)
+			continue
+		}
+		logger.Infof("💸 [%s] Funding %+.4f accrued on position %d", at.name, amount, id)
+	}
+	for _, p := range payments {
+		if p.Time.After(at.funding.since) {
+			at.funding.since = p.Time
+		}
+	}
+}
+
+// positionSnapshots decision record snapshot of the open positions, with the funding accrued on their records
+func (at *AutoTrader) positionSnapshots(positions []decision.PositionInfo) []store.PositionSnapshot {
+	funding := make(map[string]float64)
+	if at.store != nil {
+		if records, err := at.store.Position().GetOpenPositions(at.id); err == nil {
+			for _, r := range records {
+				funding[bracketKey(r.Symbol, r.Side)] += r.FundingFee
+			}
+		}
+	}
+
+	snapshots := make([]store.PositionSnapshot, 0, len(positions))
+	for _, pos := range positions {
+		snapshots = append(snapshots, store.PositionSnapshot{
+			Symbol:           pos.Symbol,
+			Side:             pos.Side,
+			PositionAmt:      pos.Quantity,
+			EntryPrice:       pos.EntryPrice,
+			MarkPrice:        pos.MarkPrice,
+			UnrealizedProfit: pos.UnrealizedPnL,
+			Leverage:         float64(pos.Leverage),
+			LiquidationPrice: pos.LiquidationPrice,
+			FundingFee:       funding[bracketKey(pos.Symbol, pos.Side)],
+		})
+	}
+	return snapshots
+}
@@ -0,0 +1,35 @@
+package trader
+
+import (
+	"math"
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+// TestAttributeFunding tests splitting funding over the positions open at settlement by quantity
+func TestAttributeFunding(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	positions := []*store.TraderPosition{
+		{ID: 1, Symbol: "BTCUSDT", Side: "LONG", Quantity: 3, EntryTime: t0},
+		{ID: 2, Symbol: "BTCUSDT", Side: "SHORT", Quantity: 1, EntryTime: t0},
+		{ID: 3, Symbol: "ETHUSDT", Side: "LONG", Quantity: 2, EntryTime: t0.Add(10 * time.Hour)},
+	}
+	payments := []FundingPayment{
+		{Symbol: "BTCUSDT", Amount: -4, Time: t0.Add(8 * time.Hour)},
+		{Symbol: "ETHUSDT", Amount: -1, Time: t0.Add(8 * time.Hour)}, // Before ETH position opened
+		{Symbol: "ETHUSDT", Amount: 0.5, Time: t0.Add(16 * time.Hour)},
+		{Symbol: "SOLUSDT", Amount: -2, Time: t0.Add(16 * time.Hour)}, // Not held
+	}
+
+	got := attributeFunding(payments, positions)
+	want := map[int64]float64{1: -3, 2: -1, 3: 0.5}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for id, amount := range want {
+		if math.Abs(got[id]-amount) > 1e-9 {
+			t.Errorf("position %d: expected funding %v, got %v", id, amount, got[id])
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/config"
+	"nofx/logger"
+	"nofx/store"
+	"time"
+)
+
+const (
+	// backfillPageSize records requested per history call
+	backfillPageSize = 500
+	// backfillTradeWindow trade history APIs (e.g. Binance userTrades) return at most 7 days per query
+	backfillTradeWindow = 7 * 24 * time.Hour
+	// backfillMaxPages safety cap on paginated history calls per symbol/exchange
+	backfillMaxPages = 50
+)
+
+// tradeHistory optional capability for exchanges that can list fills across all symbols
+type tradeHistory interface {
+	GetTrades(startTime time.Time, limit int) ([]TradeRecord, error)
+}
+
+// historyBackfillDays returns the configured backfill window in days
+func historyBackfillDays() int {
+	return config.Get().HistoryBackfillDays
+}
+
+// hasPositionHistory whether the exchange exposes position-level closed history (accurate PnL per position)
+func hasPositionHistory(exchangeType string) bool {
+	switch exchangeType {
+	case "bybit", "okx", "mexc":
+		return true
+	}
+	return false
+}
+
+// BackfillHistory imports the last days of exchange trade history as closed positions on the trader's first run
+// so analytics are not empty and pre-existing (manual) performance can be compared with AI-driven trading
+func (at *AutoTrader) BackfillHistory(days int) (int, error) {
+	if at.store == nil {
+		return 0, fmt.Errorf("store not configured")
+	}
+	return backfillHistory(at.store, at.id, at.exchangeID, at.exchange, at.trader, days)
+}
+
+// backfillHistory imports closed positions from exchange history, skipped once the trader has history of its own
+func backfillHistory(st *store.Store, traderID, exchangeID, exchangeType string, trader Trader, days int) (int, error) {
+	if days <= 0 {
+		return 0, nil
+	}
+
+	needed, err := st.Position().NeedsHistoryBackfill(traderID)
+	if err != nil {
+		return 0, err
+	}
+	if !needed {
+		return 0, nil
+	}
+
+	since := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+	records, err := fetchClosedHistory(trader, exchangeType, since)
+	if err != nil {
+		return 0, err
+	}
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	created, skipped, err := st.Position().SyncClosedPositions(traderID, exchangeID, exchangeType, toStoreClosedPnLRecords(records, "backfill"))
+	if err != nil {
+		return created, fmt.Errorf("failed to store backfilled positions: %w", err)
+	}
+	logger.Infof("📚 Backfilled %d closed position(s) from the last %d days of %s history (skipped %d)",
+		created, days, exchangeType, skipped)
+	return created, nil
+}
+
+// fetchClosedHistory gets closed positions since the given time
+// Position-level history is used where available, otherwise positions are rebuilt from fills
+func fetchClosedHistory(trader Trader, exchangeType string, since time.Time) ([]ClosedPnLRecord, error) {
+	if hasPositionHistory(exchangeType) {
+		return fetchClosedPnLPages(trader, since)
+	}
+
+	var trades []TradeRecord
+	var err error
+	if bySymbol, ok := capability[symbolTradeHistory](trader, "", ""); ok {
+		// Account-wide history only has realized PnL entries (e.g. Binance income),
+		// use it to find traded symbols, then fetch full fills per symbol
+		all, ok := capability[tradeHistory](trader, "", "")
+		if !ok {
+			return nil, nil
+		}
+		trades, err = fetchSymbolTrades(all, bySymbol, since)
+	} else if all, ok := capability[tradeHistory](trader, "", ""); ok {
+		trades, err = fetchTradePages(all.GetTrades, since)
+	} else {
+		return nil, nil // No history API
+	}
+	if err != nil {
+		return nil, err
+	}
+	return RebuildPositionsFromTrades(trades), nil
+}
+
+// fetchClosedPnLPages pages through position-level closed history
+func fetchClosedPnLPages(trader Trader, since time.Time) ([]ClosedPnLRecord, error) {
+	var records []ClosedPnLRecord
+	start := since
+	for page := 0; page < backfillMaxPages; page++ {
+		batch, err := trader.GetClosedPnL(start, backfillPageSize)
+		if err != nil {
+			return records, fmt.Errorf("failed to get closed PnL history: %w", err)
+		}
+		records = append(records, batch...)
+		if len(batch) < backfillPageSize {
+			break
+		}
+		start = latestExitTimeOf(batch).Add(time.Millisecond)
+	}
+	return records, nil
+}
+
+// fetchSymbolTrades finds symbols with realized PnL since the given time and fetches their fills
+func fetchSymbolTrades(all tradeHistory, bySymbol symbolTradeHistory, since time.Time) ([]TradeRecord, error) {
+	pnlEntries, err := fetchTradePages(all.GetTrades, since)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := make(map[string]bool)
+	for _, tr := range pnlEntries {
+		if tr.Symbol != "" {
+			symbols[tr.Symbol] = true
+		}
+	}
+
+	var trades []TradeRecord
+	for symbol := range symbols {
+		fetch := func(start time.Time, limit int) ([]TradeRecord, error) {
+			return bySymbol.GetTradesForSymbol(symbol, start, limit)
+		}
+		symbolTrades, err := fetchTradePages(fetch, since)
+		if err != nil {
+			logger.Infof("⚠️  Failed to backfill %s fills: %v", symbol, err)
+			continue
+		}
+		trades = append(trades, symbolTrades...)
+	}
+	return trades, nil
+}
+
+// fetchTradePages pages through fills from since to now
+// An empty page skips ahead one query window, since some APIs only cover a limited range per call
+func fetchTradePages(fetch func(start time.Time, limit int) ([]TradeRecord, error), since time.Time) ([]TradeRecord, error) {
+	var trades []TradeRecord
+	seen := make(map[string]bool)
+	start := since
+	now := time.Now()
+
+	for page := 0; page < backfillMaxPages && start.Before(now); page++ {
+		batch, err := fetch(start, backfillPageSize)
+		if err != nil {
+			return trades, fmt.Errorf("failed to get trade history: %w", err)
+		}
+
+		var latest time.Time
+		added := 0
+		for _, tr := range batch {
+			if tr.TradeID != "" {
+				if seen[tr.TradeID] {
+					continue
+				}
+				seen[tr.TradeID] = true
+			}
+			if tr.Time.Before(since) {
+				continue
+			}
+			trades = append(trades, tr)
+			added++
+			if tr.Time.After(latest) {
+				latest = tr.Time
+			}
+		}
+
+		if added == 0 {
+			start = start.Add(backfillTradeWindow)
+			continue
+		}
+		if len(batch) < backfillPageSize && latest.Add(backfillTradeWindow).After(now) {
+			break
+		}
+		start = latest.Add(time.Millisecond)
+	}
+	return trades, nil
+}
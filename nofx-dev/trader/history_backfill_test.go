@@ -0,0 +1,106 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/store"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestFetchTradePages tests paging through windowed trade history
+func TestFetchTradePages(t *testing.T) {
+	since := time.Now().Add(-30 * 24 * time.Hour)
+
+	// Fills spread over 30 days, API returns at most one 7-day window per call (like Binance userTrades)
+	var fills []TradeRecord
+	for d := 1; d < 30; d += 3 {
+		fills = append(fills, TradeRecord{TradeID: fmt.Sprintf("t%d", d), Time: since.Add(time.Duration(d) * 24 * time.Hour)})
+	}
+	// Fill before the window must be dropped
+	fills = append([]TradeRecord{{TradeID: "old", Time: since.Add(-time.Hour)}}, fills...)
+
+	calls := 0
+	fetch := func(start time.Time, limit int) ([]TradeRecord, error) {
+		calls++
+		end := start.Add(backfillTradeWindow)
+		var batch []TradeRecord
+		for _, f := range fills {
+			if !f.Time.Before(start.Add(-2*time.Hour)) && f.Time.Before(end) && len(batch) < limit {
+				batch = append(batch, f)
+			}
+		}
+		return batch, nil
+	}
+
+	trades, err := fetchTradePages(fetch, since)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(trades) != len(fills)-1 {
+		t.Errorf("expected %d trades, got %d", len(fills)-1, len(trades))
+	}
+	for _, tr := range trades {
+		if tr.TradeID == "old" {
+			t.Errorf("fill before backfill window was included")
+		}
+	}
+	if calls > backfillMaxPages {
+		t.Errorf("too many calls: %d", calls)
+	}
+}
+
+// TestFetchTradePagesIgnoresStartTime tests termination when the API ignores startTime and repeats the same page
+func TestFetchTradePagesIgnoresStartTime(t *testing.T) {
+	since := time.Now().Add(-14 * 24 * time.Hour)
+	page := []TradeRecord{
+		{TradeID: "a", Time: since.Add(time.Hour)},
+		{TradeID: "b", Time: since.Add(2 * time.Hour)},
+	}
+	fetch := func(start time.Time, limit int) ([]TradeRecord, error) {
+		return page, nil
+	}
+
+	trades, err := fetchTradePages(fetch, since)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(trades) != 2 {
+		t.Errorf("expected 2 trades, got %d", len(trades))
+	}
+}
+
+// TestBackfillExcludedFromStats tests that backfilled history only shows up in the performance comparison
+func TestBackfillExcludedFromStats(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer st.Close()
+
+	exit := time.Now().Add(-48 * time.Hour)
+	records := []*store.ClosedPnLRecord{
+		{Symbol: "BTCUSDT", Side: "long", EntryPrice: 100, ExitPrice: 90, Quantity: 1, RealizedPnL: -10, Leverage: 1,
+			EntryTime: exit.Add(-time.Hour), ExitTime: exit, OrderID: "b1", Source: "backfill"},
+		{Symbol: "ETHUSDT", Side: "long", EntryPrice: 100, ExitPrice: 110, Quantity: 1, RealizedPnL: 10, Leverage: 1,
+			EntryTime: exit, ExitTime: exit.Add(time.Hour), OrderID: "s1"},
+	}
+	for _, r := range records {
+		if _, err := st.Position().CreateFromClosedPnL("t1", "ex1", "binance", r); err != nil {
+			t.Fatalf("failed to insert %s: %v", r.OrderID, err)
+		}
+	}
+
+	stats, err := st.Position().GetFullStats("t1")
+	if err != nil || stats.TotalTrades != 1 || stats.TotalPnL != 10 {
+		t.Errorf("expected only the synced trade in stats, got %+v (%v)", stats, err)
+	}
+	trades, err := st.Position().GetRecentTrades("t1", 10)
+	if err != nil || len(trades) != 1 || trades[0].Symbol != "ETHUSDT" {
+		t.Errorf("expected only the synced trade in recent trades, got %+v (%v)", trades, err)
+	}
+	comparison, err := st.Position().GetPerformanceComparison("t1")
+	if err != nil || comparison.Baseline.TotalTrades != 2 {
+		t.Errorf("expected backfilled history in the comparison baseline, got %+v (%v)", comparison, err)
+	}
+}
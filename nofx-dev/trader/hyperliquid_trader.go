@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"nofx/logger"
+	"nofx/market"
 	"strconv"
 	"strings"
 	"sync"
@@ -15,6 +16,11 @@ import (
 	"github.com/sonirico/go-hyperliquid"
 )
 
+func init() {
+	// Perpetuals base tier: 0.015% maker, 0.045% taker
+	logger.RegisterFeeSchedule("hyperliquid", logger.FeeSchedule{Maker: 0.00015, Taker: 0.00045})
+}
+
 // HyperliquidTrader Hyperliquid trader
 type HyperliquidTrader struct {
 	exchange      *hyperliquid.Exchange
@@ -244,14 +250,14 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 }
 
 // GetPositions gets all positions
-func (t *HyperliquidTrader) GetPositions() ([]map[string]interface{}, error) {
+func (t *HyperliquidTrader) GetPositions() ([]Position, error) {
 	// Get account status
 	accountState, err := t.exchange.Info().UserState(t.ctx, t.walletAddr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get positions: %w", err)
 	}
 
-	var result []map[string]interface{}
+	var result []Position
 
 	// Iterate through all positions
 	for _, assetPos := range accountState.AssetPositions {
@@ -264,19 +270,16 @@ func (t *HyperliquidTrader) GetPositions() ([]map[string]interface{}, error) {
 			continue // Skip positions with zero amount
 		}
 
-		posMap := make(map[string]interface{})
-
 		// Normalize symbol format (Hyperliquid uses "BTC", we convert to "BTCUSDT")
-		symbol := position.Coin + "USDT"
-		posMap["symbol"] = symbol
+		p := Position{Symbol: market.CanonicalSymbol(market.VenueHyperliquid, position.Coin)}
 
 		// Position amount and direction
 		if posAmt > 0 {
-			posMap["side"] = "long"
-			posMap["positionAmt"] = posAmt
+			p.Side = "long"
+			p.Quantity = posAmt
 		} else {
-			posMap["side"] = "short"
-			posMap["positionAmt"] = -posAmt // Convert to positive number
+			p.Side = "short"
+			p.Quantity = -posAmt // Convert to positive number
 		}
 
 		// Price information (EntryPx and LiquidationPx are pointer types)
@@ -297,13 +300,13 @@ func (t *HyperliquidTrader) GetPositions() ([]map[string]interface{}, error) {
 			markPrice = positionValue / absFloat(posAmt)
 		}
 
-		posMap["entryPrice"] = entryPrice
-		posMap["markPrice"] = markPrice
-		posMap["unRealizedProfit"] = unrealizedPnl
-		posMap["leverage"] = float64(position.Leverage.Value)
-		posMap["liquidationPrice"] = liquidationPx
+		p.EntryPrice = entryPrice
+		p.MarkPrice = markPrice
+		p.UnrealizedPnL = unrealizedPnl
+		p.Leverage = float64(position.Leverage.Value)
+		p.LiquidationPrice = liquidationPx
 
-		result = append(result, posMap)
+		result = append(result, p)
 	}
 
 	return result, nil
@@ -498,8 +501,8 @@ func (t *HyperliquidTrader) CloseLong(symbol string, quantity float64) (map[stri
 		}
 
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "long" {
-				quantity = pos["positionAmt"].(float64)
+			if pos.Symbol == symbol && pos.Side == "long" {
+				quantity = pos.Quantity
 				break
 			}
 		}
@@ -570,8 +573,8 @@ func (t *HyperliquidTrader) CloseShort(symbol string, quantity float64) (map[str
 		}
 
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "short" {
-				quantity = pos["positionAmt"].(float64)
+			if pos.Symbol == symbol && pos.Side == "short" {
+				quantity = pos.Quantity
 				break
 			}
 		}
@@ -887,13 +890,9 @@ func (t *HyperliquidTrader) roundPriceToSigfigs(price float64) float64 {
 }
 
 // convertSymbolToHyperliquid converts standard symbol to Hyperliquid format
-// Example: "BTCUSDT" -> "BTC"
+// Example: "BTCUSDT" -> "BTC", "BTCUSDC" -> "BTC", "1000PEPEUSDT" -> "kPEPE"
 func convertSymbolToHyperliquid(symbol string) string {
-	// Remove USDT suffix
-	if len(symbol) > 4 && symbol[len(symbol)-4:] == "USDT" {
-		return symbol[:len(symbol)-4]
-	}
-	return symbol
+	return market.VenueSymbol(market.VenueHyperliquid, symbol)
 }
 
 // GetOrderStatus gets order status
@@ -281,7 +281,7 @@ func TestNewHyperliquidTrader(t *testing.T) {
 			walletAddr:    "0x1234567890123456789012345678901234567890",
 			testnet:       true,
 			wantError:     true,
-			errorContains: "Failed to parse private key",
+			errorContains: "failed to parse private key",
 		},
 		{
 			name:          "Empty wallet address",
@@ -403,6 +403,11 @@ func TestConvertSymbolToHyperliquid(t *testing.T) {
 			symbol:   "BTC",
 			expected: "BTC",
 		},
+		{
+			name:     "1000x memecoin alias",
+			symbol:   "1000PEPEUSDT",
+			expected: "kPEPE",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,232 @@
+package trader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"nofx/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	orderMaxAttempts  = 3
+	orderRetryBackoff = time.Second // Doubles after every attempt
+)
+
+// IdempotentTrader wraps a Trader so market orders are retried on transient errors (rate limit / network)
+// without risking a double fill: every logical order gets a deterministic client order key, and after a
+// failed attempt the order is looked up by that key (or the position is re-read) before sending it again.
+// Must wrap a ClassifiedTrader, retry decisions are based on the error category
+type IdempotentTrader struct {
+	Trader
+	traderID string
+
+	locks sync.Map // symbol -> *sync.Mutex, one order in flight per symbol so keys aren't mixed up
+	sleep func(time.Duration)
+	now   func() time.Time
+}
+
+// NewIdempotentTrader creates an order-retrying wrapper around inner
+func NewIdempotentTrader(inner Trader, traderID string) *IdempotentTrader {
+	return &IdempotentTrader{
+		Trader:   inner,
+		traderID: traderID,
+		sleep:    time.Sleep,
+		now:      time.Now,
+	}
+}
+
+// Unwrap returns the wrapped trader
+func (t *IdempotentTrader) Unwrap() Trader {
+	return t.Trader
+}
+
+// clientOrderKey derives the client order key of a logical order (hex, 32 characters)
+// Retries of the same order reuse the key, the first attempt's time keeps separate orders apart
+func clientOrderKey(traderID, op, symbol string, quantity float64, firstAttempt time.Time) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%.8f|%d", traderID, op, symbol, quantity, firstAttempt.UnixMilli())))
+	return hex.EncodeToString(sum[:16])
+}
+
+// orderFilled reports whether a looked-up order executed (fully or partially)
+func orderFilled(order map[string]interface{}) bool {
+	status := strings.ToUpper(fmt.Sprintf("%v", order["status"]))
+	switch status {
+	case "CANCELED", "CANCELLED", "REJECTED", "EXPIRED":
+		return getFloat(order, "executedQty") > 0
+	}
+	return true
+}
+
+// positionChanged reports whether the position moved as the order would have moved it
+// opening: size grew; closing: size shrank (or the position is gone for close-all)
+func positionChanged(opening bool, before, after, quantity float64) (bool, float64) {
+	const epsilon = 1e-9
+	if opening {
+		delta := after - before
+		return delta > epsilon*math.Max(1, quantity), delta
+	}
+	delta := before - after
+	if quantity <= 0 {
+		return before > 0 && after <= epsilon, delta
+	}
+	return delta > epsilon*math.Max(1, quantity), delta
+}
+
+// cacheInvalidator adapters caching balance/positions, a stale position would hide a fill
+type cacheInvalidator interface {
+	invalidateCaches()
+}
+
+// positionSize returns the current size of symbol/side ("long"/"short"), 0 if none
+func (t *IdempotentTrader) positionSize(symbol, side string) (float64, error) {
+	return livePositionSize(t.Trader, symbol, side)
+}
+
+// lock serializes orders on one symbol
+func (t *IdempotentTrader) lock(symbol string) func() {
+	mu, _ := t.locks.LoadOrStore(symbol, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+// placeOrder runs place with retries, checking whether a failed attempt actually filled before re-sending
+func (t *IdempotentTrader) placeOrder(op, symbol, side string, opening bool, quantity float64, place func() (map[string]interface{}, error)) (map[string]interface{}, error) {
+	defer t.lock(symbol)()
+
+	key := clientOrderKey(t.traderID, op, symbol, quantity, t.now())
+	keyed, _ := unwrapTrader(t.Trader).(ClientOrderIDTrader)
+	if keyed != nil {
+		keyed.SetClientOrderKey(symbol, key)
+		defer keyed.SetClientOrderKey(symbol, "")
+	}
+
+	// Position size before the order, the fallback check when the order can't be looked up by key
+	// (unsupported exchange, or limit entry orders placed under their own IDs)
+	before, beforeErr := t.positionSize(symbol, side)
+
+	var lastErr error
+	for attempt := 1; attempt <= orderMaxAttempts; attempt++ {
+		if attempt > 1 {
+			backoff := orderRetryBackoff << (attempt - 2)
+			logger.Warnf("🔁 %s %s attempt %d/%d in %v after: %v", op, symbol, attempt, orderMaxAttempts, backoff, lastErr)
+			t.sleep(backoff)
+		}
+
+		result, err := place()
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+		if !IsRetryable(err) {
+			return nil, err
+		}
+
+		// The request failed, but the exchange may have executed it (e.g. timeout after the fill)
+		if keyed != nil {
+			order, found, lookupErr := keyed.FindOrderByClientKey(symbol, key)
+			if lookupErr == nil && found && orderFilled(order) {
+				logger.Warnf("✓ %s %s failed with %v but order %v was executed, not re-sending", op, symbol, err, order["orderId"])
+				order["recovered"] = true
+				return order, nil
+			}
+		}
+
+		if beforeErr != nil {
+			return nil, fmt.Errorf("%w (position state unknown, not retried)", err)
+		}
+		after, posErr := t.positionSize(symbol, side)
+		if posErr != nil {
+			// Unknown outcome, re-sending could double the order
+			return nil, fmt.Errorf("%w (position state unknown, not retried: %v)", err, posErr)
+		}
+		if changed, delta := positionChanged(opening, before, after, quantity); changed {
+			logger.Warnf("✓ %s %s failed with %v but the %s position changed by %.6f, not re-sending", op, symbol, err, side, delta)
+			return map[string]interface{}{
+				"orderId":     "",
+				"symbol":      symbol,
+				"status":      "FILLED",
+				"executedQty": math.Abs(delta),
+				"recovered":   true,
+			}, nil
+		}
+	}
+	return nil, lastErr
+}
+
+// OpenLong opens a long position, retrying transient failures without double-opening
+func (t *IdempotentTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.placeOrder("OpenLong", symbol, "long", true, quantity, func() (map[string]interface{}, error) {
+		return t.Trader.OpenLong(symbol, quantity, leverage)
+	})
+}
+
+// OpenShort opens a short position, retrying transient failures without double-opening
+func (t *IdempotentTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.placeOrder("OpenShort", symbol, "short", true, quantity, func() (map[string]interface{}, error) {
+		return t.Trader.OpenShort(symbol, quantity, leverage)
+	})
+}
+
+// CloseLong closes a long position, retrying transient failures without over-closing
+func (t *IdempotentTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.placeOrder("CloseLong", symbol, "long", false, quantity, func() (map[string]interface{}, error) {
+		return t.Trader.CloseLong(symbol, quantity)
+	})
+}
+
+// CloseShort closes a short position, retrying transient failures without over-closing
+func (t *IdempotentTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.placeOrder("CloseShort", symbol, "short", false, quantity, func() (map[string]interface{}, error) {
+		return t.Trader.CloseShort(symbol, quantity)
+	})
+}
+
+// OpenWithProtection opens a position with its stop loss / take profit in one batch, retrying transient failures
+// without double-opening. An entry recovered after a failed attempt reports its protection as incomplete
+func (t *IdempotentTrader) OpenWithProtection(symbol, positionSide string, quantity float64, leverage int, stopLoss, takeProfit float64) (map[string]interface{}, bool, error) {
+	placer, ok := batchEntryPlacer(t.Trader)
+	if !ok {
+		return nil, false, ErrBatchUnsupported
+	}
+	protected := false
+	result, err := t.placeOrder("OpenWithProtection", symbol, strings.ToLower(positionSide), true, quantity, func() (map[string]interface{}, error) {
+		order, placed, err := placer.OpenWithProtection(symbol, positionSide, quantity, leverage, stopLoss, takeProfit)
+		protected = placed
+		return order, err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return result, protected, nil
+}
+
+// clientOrderKeys pending client order keys by symbol, embedded by ClientOrderIDTrader adapters
+type clientOrderKeys struct {
+	mu   sync.Mutex
+	keys map[string]string
+}
+
+// set sets (or clears with "") the key of symbol
+func (k *clientOrderKeys) set(symbol, key string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.keys == nil {
+		k.keys = make(map[string]string)
+	}
+	if key == "" {
+		delete(k.keys, symbol)
+		return
+	}
+	k.keys[symbol] = key
+}
+
+// get returns the key of symbol ("" if none)
+func (k *clientOrderKeys) get(symbol string) string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.keys[symbol]
+}
@@ -0,0 +1,168 @@
+package trader
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyExchange Trader whose order requests time out, optionally after the order was executed
+type flakyExchange struct {
+	Trader
+	failures    int  // Order attempts that fail with a timeout
+	fillsOnFail bool // A failed attempt still opens the position
+	attempts    int
+	size        float64
+	keys        []string
+	placed      map[string]bool // Client order keys the exchange executed
+	current     string
+}
+
+func (f *flakyExchange) GetPositions() ([]Position, error) {
+	if f.size <= 0 {
+		return nil, nil
+	}
+	return []Position{{Symbol: "BTCUSDT", Side: "long", Quantity: f.size}}, nil
+}
+
+func (f *flakyExchange) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	f.attempts++
+	f.keys = append(f.keys, f.current)
+	if f.attempts <= f.failures {
+		if f.fillsOnFail {
+			f.size += quantity
+			f.placed[f.current] = true
+		}
+		return nil, ClassifyError("binance", "OpenLong", errors.New("Post \"https://fapi.binance.com\": context deadline exceeded"))
+	}
+	f.size += quantity
+	f.placed[f.current] = true
+	return map[string]interface{}{"orderId": int64(f.attempts)}, nil
+}
+
+// keyedExchange flakyExchange with ClientOrderIDTrader support
+type keyedExchange struct {
+	*flakyExchange
+}
+
+func (k keyedExchange) SetClientOrderKey(symbol, key string) {
+	if key != "" {
+		k.current = key
+	}
+}
+
+func (k keyedExchange) FindOrderByClientKey(symbol, key string) (map[string]interface{}, bool, error) {
+	if !k.placed[key] {
+		return nil, false, nil
+	}
+	return map[string]interface{}{"orderId": int64(42), "status": "FILLED", "executedQty": 1.0}, true, nil
+}
+
+func newIdempotentTestTrader(inner Trader) *IdempotentTrader {
+	it := NewIdempotentTrader(inner, "trader-1")
+	it.sleep = func(time.Duration) {}
+	return it
+}
+
+// TestIdempotentRetryTransient tests retries after a timeout that did not fill
+func TestIdempotentRetryTransient(t *testing.T) {
+	ex := &flakyExchange{failures: 1, placed: map[string]bool{}}
+	result, err := newIdempotentTestTrader(ex).OpenLong("BTCUSDT", 1, 5)
+	if err != nil {
+		t.Fatalf("expected success after retry, got %v", err)
+	}
+	if ex.attempts != 2 || ex.size != 1 {
+		t.Errorf("expected 2 attempts and size 1, got %d attempts, size %v", ex.attempts, ex.size)
+	}
+	if result["recovered"] != nil {
+		t.Error("a normal retry should not be marked recovered")
+	}
+}
+
+// TestIdempotentNoDoubleOpen tests that a timeout after the fill is detected instead of re-sent
+func TestIdempotentNoDoubleOpen(t *testing.T) {
+	// Position check (no client order ID support)
+	ex := &flakyExchange{failures: 1, fillsOnFail: true, placed: map[string]bool{}}
+	result, err := newIdempotentTestTrader(ex).OpenLong("BTCUSDT", 1, 5)
+	if err != nil {
+		t.Fatalf("expected recovered fill, got %v", err)
+	}
+	if ex.attempts != 1 || ex.size != 1 {
+		t.Errorf("order must not be re-sent: %d attempts, size %v", ex.attempts, ex.size)
+	}
+	if result["recovered"] != true {
+		t.Error("result should be marked recovered")
+	}
+
+	// Client order ID lookup, retries reuse the key
+	keyed := keyedExchange{&flakyExchange{failures: 1, fillsOnFail: true, placed: map[string]bool{}}}
+	result, err = newIdempotentTestTrader(keyed).OpenLong("BTCUSDT", 1, 5)
+	if err != nil {
+		t.Fatalf("expected recovered fill, got %v", err)
+	}
+	if keyed.attempts != 1 || result["orderId"] != int64(42) {
+		t.Errorf("expected the looked-up order, got %d attempts, result %v", keyed.attempts, result)
+	}
+}
+
+// TestIdempotentKeyReusedAcrossRetries tests deterministic keys per logical order
+func TestIdempotentKeyReusedAcrossRetries(t *testing.T) {
+	keyed := keyedExchange{&flakyExchange{failures: 2, placed: map[string]bool{}}}
+	if _, err := newIdempotentTestTrader(keyed).OpenLong("BTCUSDT", 1, 5); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if len(keyed.keys) != 3 || keyed.keys[0] == "" || keyed.keys[0] != keyed.keys[2] {
+		t.Errorf("all attempts should use the same key, got %v", keyed.keys)
+	}
+
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if clientOrderKey("t", "OpenLong", "BTCUSDT", 1, at) != clientOrderKey("t", "OpenLong", "BTCUSDT", 1, at) {
+		t.Error("key should be deterministic")
+	}
+	if clientOrderKey("t", "OpenLong", "BTCUSDT", 1, at) == clientOrderKey("t", "OpenLong", "BTCUSDT", 1, at.Add(time.Second)) {
+		t.Error("separate orders should get separate keys")
+	}
+}
+
+// TestIdempotentNonRetryable tests that rejections are returned without retrying
+func TestIdempotentNonRetryable(t *testing.T) {
+	ex := &flakyExchange{placed: map[string]bool{}}
+	rejecting := &rejectingExchange{flakyExchange: ex}
+	if _, err := newIdempotentTestTrader(rejecting).OpenLong("BTCUSDT", 1, 5); !errors.Is(err, ErrInsufficientMargin) {
+		t.Fatalf("expected insufficient margin, got %v", err)
+	}
+	if rejecting.calls != 1 {
+		t.Errorf("non-retryable error should not be retried, got %d calls", rejecting.calls)
+	}
+}
+
+type rejectingExchange struct {
+	*flakyExchange
+	calls int
+}
+
+func (r *rejectingExchange) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	r.calls++
+	return nil, ClassifyError("binance", "OpenLong", errors.New("<APIError> code=-2019, msg=Margin is insufficient."))
+}
+
+// TestPositionChanged tests fill detection from position sizes
+func TestPositionChanged(t *testing.T) {
+	tests := []struct {
+		name                    string
+		opening                 bool
+		before, after, quantity float64
+		want                    bool
+	}{
+		{"open grew", true, 0, 1, 1, true},
+		{"open unchanged", true, 1, 1, 1, false},
+		{"close shrank", false, 2, 1, 1, true},
+		{"close all gone", false, 2, 0, 0, true},
+		{"close all unchanged", false, 2, 2, 0, false},
+	}
+	for _, tt := range tests {
+		if got, _ := positionChanged(tt.opening, tt.before, tt.after, tt.quantity); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
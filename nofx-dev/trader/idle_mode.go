@@ -0,0 +1,88 @@
+package trader
+
+import (
+	"nofx/logger"
+	"nofx/market"
+	"sync"
+	"time"
+)
+
+// idleState whether the trader is paused / outside its trading schedule and what was scaled down
+type idleState struct {
+	mu        sync.Mutex
+	idle      bool
+	suspended bool // Position workers suspended (idle with nothing open)
+}
+
+// idleReason why the trader is idle ("" when active)
+func (at *AutoTrader) idleReason(now time.Time) string {
+	if paused, _ := at.dailyLossPaused(now); paused {
+		return "daily loss limit"
+	}
+	if at.flatActive(now) {
+		return "flat schedule"
+	}
+	return ""
+}
+
+// updateIdle enters or leaves idle mode: entering releases the trader's on-demand market streams,
+// and while idle with no open positions the position workers (drawdown, trailing, brackets, break-even,
+// drift, reconcile) are suspended. Streams are reacquired by the next decision cycle on resume
+func (at *AutoTrader) updateIdle() {
+	reason := at.idleReason(time.Now())
+
+	at.idle.mu.Lock()
+	wasIdle := at.idle.idle
+	at.idle.idle = reason != ""
+	if reason == "" {
+		at.idle.suspended = false
+	}
+	at.idle.mu.Unlock()
+
+	if reason == "" {
+		if wasIdle {
+			logger.Infof("▶️ [%s] Leaving idle mode, resuming workers", at.name)
+		}
+		return
+	}
+	if !wasIdle {
+		released := at.releaseStreams()
+		logger.Infof("💤 [%s] Idle (%s): released %d market stream(s)", at.name, reason, released)
+	}
+
+	positions, err := at.trader.GetPositions()
+	suspend := err == nil && len(positions) == 0
+	at.idle.mu.Lock()
+	at.idle.suspended = suspend
+	at.idle.mu.Unlock()
+}
+
+// workersSuspended whether position workers are suspended by idle mode
+func (at *AutoTrader) workersSuspended() bool {
+	at.idle.mu.Lock()
+	defer at.idle.mu.Unlock()
+	return at.idle.suspended
+}
+
+// acquireStreams claims the kline streams of symbols in the strategy's timeframes
+func (at *AutoTrader) acquireStreams(symbols []string) {
+	if market.WSMonitorCli == nil || at.config.StrategyConfig == nil {
+		return
+	}
+	klines := at.config.StrategyConfig.Indicators.Klines
+	timeframes := append([]string{}, klines.SelectedTimeframes...)
+	for _, tf := range []string{klines.PrimaryTimeframe, klines.LongerTimeframe} {
+		if tf != "" {
+			timeframes = append(timeframes, tf)
+		}
+	}
+	market.WSMonitorCli.Acquire(at.id, symbols, timeframes)
+}
+
+// releaseStreams releases the on-demand streams only this trader used, returns how many
+func (at *AutoTrader) releaseStreams() int {
+	if market.WSMonitorCli == nil {
+		return 0
+	}
+	return market.WSMonitorCli.Release(at.id)
+}
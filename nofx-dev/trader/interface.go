@@ -33,6 +33,25 @@ type TradeRecord struct {
 	Time         time.Time // Trade execution time
 }
 
+// Position represents an open position as reported by the exchange
+// Adapters convert their exchange payloads into this type
+type Position struct {
+	Symbol           string  // Trading pair (e.g., "BTCUSDT")
+	Side             string  // "long" or "short"
+	Quantity         float64 // Position size (always positive)
+	EntryPrice       float64 // Average entry price
+	MarkPrice        float64 // Current mark price
+	UnrealizedPnL    float64 // Unrealized profit/loss
+	Leverage         float64 // Leverage multiplier
+	LiquidationPrice float64 // Liquidation price (0 if unknown)
+	MarginUsed       float64 // Initial margin (0 if not reported)
+	MarginMode       string  // "cross" or "isolated" ("" if not reported)
+	CreatedTime      int64   // Position open time in ms (0 if not reported)
+	UpdatedTime      int64   // Last update time in ms (0 if not reported)
+	ReadOnly         bool    // Held on a failover fallback exchange, not managed by the primary
+	Exchange         string  // Exchange holding the position (set by FailoverTrader)
+}
+
 // Trader Unified trader interface
 // Supports multiple trading platforms (Binance, Hyperliquid, etc.)
 type Trader interface {
@@ -40,7 +59,7 @@ type Trader interface {
 	GetBalance() (map[string]interface{}, error)
 
 	// GetPositions Get all positions
-	GetPositions() ([]map[string]interface{}, error)
+	GetPositions() ([]Position, error)
 
 	// OpenLong Open long position
 	OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error)
@@ -94,3 +113,42 @@ type Trader interface {
 	// Returns accurate exit price, fees, and close reason for positions closed externally
 	GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error)
 }
+
+// Wallet types used by FundTransferer
+const (
+	WalletSpot    = "spot"    // Spot / funding wallet
+	WalletFutures = "futures" // USDT-margined futures wallet
+)
+
+// TransferRequest describes a fund movement between wallets or sub-accounts
+type TransferRequest struct {
+	Asset        string  // Asset to move (e.g., "USDT")
+	Amount       float64 // Amount to move, must be positive
+	FromWallet   string  // WalletSpot or WalletFutures
+	ToWallet     string  // WalletSpot or WalletFutures
+	FromEmail    string  // Source sub-account email (empty = master account)
+	ToEmail      string  // Destination sub-account email (empty = master account)
+	ClientTranID string  // Optional client-side idempotency ID
+}
+
+// IsSubAccount returns true if the transfer involves a sub-account
+func (r TransferRequest) IsSubAccount() bool {
+	return r.FromEmail != "" || r.ToEmail != ""
+}
+
+// FundTransferer Optional capability for exchanges that support programmatic fund movement
+// Used by capital allocation / rebalancing to fund traders without manual transfers
+type FundTransferer interface {
+	// TransferFunds moves funds between wallets or sub-accounts, returns exchange transaction ID
+	TransferFunds(req TransferRequest) (string, error)
+}
+
+// ClientOrderIDTrader Optional capability for exchanges that place market orders under a caller-chosen
+// client order ID and can look orders up by it, so an order whose request failed can be found instead of re-sent
+type ClientOrderIDTrader interface {
+	// SetClientOrderKey makes market orders on symbol use an ID derived from key ("" restores generated IDs)
+	SetClientOrderKey(symbol, key string)
+
+	// FindOrderByClientKey returns the order placed with key's ID (found=false if the exchange has none)
+	FindOrderByClientKey(symbol, key string) (order map[string]interface{}, found bool, err error)
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"strings"
 )
 
 // AccountBalance Account balance information
@@ -16,8 +18,8 @@ type AccountBalance struct {
 	MaintenanceMargin float64 `json:"maintenance_margin"`  // Maintenance margin
 }
 
-// Position Position information
-type Position struct {
+// LighterPosition Position information as returned by the Lighter API
+type LighterPosition struct {
 	Symbol           string  `json:"symbol"`             // Trading pair
 	Side             string  `json:"side"`               // "long" or "short"
 	Size             float64 `json:"size"`               // Position size
@@ -29,6 +31,21 @@ type Position struct {
 	MarginUsed       float64 `json:"margin_used"`        // Used margin
 }
 
+// toPosition converts the Lighter payload into the unified Position
+func (p LighterPosition) toPosition() Position {
+	return Position{
+		Symbol:           p.Symbol,
+		Side:             strings.ToLower(p.Side),
+		Quantity:         math.Abs(p.Size),
+		EntryPrice:       p.EntryPrice,
+		MarkPrice:        p.MarkPrice,
+		UnrealizedPnL:    p.UnrealizedPnL,
+		Leverage:         p.Leverage,
+		LiquidationPrice: p.LiquidationPrice,
+		MarginUsed:       p.MarginUsed,
+	}
+}
+
 // GetBalance Get account balance (implements Trader interface)
 func (t *LighterTrader) GetBalance() (map[string]interface{}, error) {
 	balance, err := t.GetAccountBalance()
@@ -91,7 +108,7 @@ func (t *LighterTrader) GetAccountBalance() (*AccountBalance, error) {
 }
 
 // GetPositionsRaw Get all positions (returns raw type)
-func (t *LighterTrader) GetPositionsRaw(symbol string) ([]Position, error) {
+func (t *LighterTrader) GetPositionsRaw(symbol string) ([]LighterPosition, error) {
 	if err := t.ensureAuthToken(); err != nil {
 		return nil, fmt.Errorf("invalid auth token: %w", err)
 	}
@@ -130,7 +147,7 @@ func (t *LighterTrader) GetPositionsRaw(symbol string) ([]Position, error) {
 		return nil, fmt.Errorf("failed to get positions (status %d): %s", resp.StatusCode, string(body))
 	}
 
-	var positions []Position
+	var positions []LighterPosition
 	if err := json.Unmarshal(body, &positions); err != nil {
 		return nil, fmt.Errorf("failed to parse positions response: %w", err)
 	}
@@ -139,32 +156,22 @@ func (t *LighterTrader) GetPositionsRaw(symbol string) ([]Position, error) {
 }
 
 // GetPositions Get all positions (implements Trader interface)
-func (t *LighterTrader) GetPositions() ([]map[string]interface{}, error) {
+func (t *LighterTrader) GetPositions() ([]Position, error) {
 	positions, err := t.GetPositionsRaw("")
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]map[string]interface{}, 0, len(positions))
+	result := make([]Position, 0, len(positions))
 	for _, pos := range positions {
-		result = append(result, map[string]interface{}{
-			"symbol":             pos.Symbol,
-			"side":               pos.Side,
-			"size":               pos.Size,
-			"entry_price":        pos.EntryPrice,
-			"mark_price":         pos.MarkPrice,
-			"liquidation_price":  pos.LiquidationPrice,
-			"unrealized_pnl":     pos.UnrealizedPnL,
-			"leverage":           pos.Leverage,
-			"margin_used":        pos.MarginUsed,
-		})
+		result = append(result, pos.toPosition())
 	}
 
 	return result, nil
 }
 
 // GetPosition Get position for specified symbol
-func (t *LighterTrader) GetPosition(symbol string) (*Position, error) {
+func (t *LighterTrader) GetPosition(symbol string) (*LighterPosition, error) {
 	positions, err := t.GetPositionsRaw(symbol)
 	if err != nil {
 		return nil, err
@@ -37,6 +37,11 @@ type OrderResponse struct {
 
 // CreateOrder Create order (market or limit)
 func (t *LighterTrader) CreateOrder(symbol, side string, quantity, price float64, orderType string) (string, error) {
+	return t.createOrder(symbol, side, quantity, price, orderType, false)
+}
+
+// createOrder creates an order, reduce-only orders can never open or flip a position (closes, stop-loss, take-profit)
+func (t *LighterTrader) createOrder(symbol, side string, quantity, price float64, orderType string, reduceOnly bool) (string, error) {
 	if err := t.ensureAuthToken(); err != nil {
 		return "", fmt.Errorf("invalid auth token: %w", err)
 	}
@@ -47,7 +52,7 @@ func (t *LighterTrader) CreateOrder(symbol, side string, quantity, price float64
 		Side:        side,
 		OrderType:   orderType,
 		Quantity:    quantity,
-		ReduceOnly:  false,
+		ReduceOnly:  reduceOnly,
 		TimeInForce: "GTC",
 		PostOnly:    false,
 	}
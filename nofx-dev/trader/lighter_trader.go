@@ -16,6 +16,11 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+func init() {
+	// Standard accounts trade without maker / taker fees
+	logger.RegisterFeeSchedule("lighter", logger.FeeSchedule{Maker: 0, Taker: 0})
+}
+
 // LighterTrader LIGHTER DEX trader
 // LIGHTER is an Ethereum L2-based perpetual contract DEX using zk-rollup technology
 type LighterTrader struct {
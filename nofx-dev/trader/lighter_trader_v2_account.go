@@ -68,32 +68,22 @@ func (t *LighterTraderV2) GetAccountBalance() (*AccountBalance, error) {
 }
 
 // GetPositions Get all positions (implements Trader interface)
-func (t *LighterTraderV2) GetPositions() ([]map[string]interface{}, error) {
+func (t *LighterTraderV2) GetPositions() ([]Position, error) {
 	positions, err := t.GetPositionsRaw("")
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]map[string]interface{}, 0, len(positions))
+	result := make([]Position, 0, len(positions))
 	for _, pos := range positions {
-		result = append(result, map[string]interface{}{
-			"symbol":             pos.Symbol,
-			"side":               pos.Side,
-			"size":               pos.Size,
-			"entry_price":        pos.EntryPrice,
-			"mark_price":         pos.MarkPrice,
-			"liquidation_price":  pos.LiquidationPrice,
-			"unrealized_pnl":     pos.UnrealizedPnL,
-			"leverage":           pos.Leverage,
-			"margin_used":        pos.MarginUsed,
-		})
+		result = append(result, pos.toPosition())
 	}
 
 	return result, nil
 }
 
 // GetPositionsRaw Get all positions (returns raw type)
-func (t *LighterTraderV2) GetPositionsRaw(symbol string) ([]Position, error) {
+func (t *LighterTraderV2) GetPositionsRaw(symbol string) ([]LighterPosition, error) {
 	if err := t.ensureAuthToken(); err != nil {
 		return nil, fmt.Errorf("invalid auth token: %w", err)
 	}
@@ -130,7 +120,7 @@ func (t *LighterTraderV2) GetPositionsRaw(symbol string) ([]Position, error) {
 		return nil, fmt.Errorf("failed to get positions (status %d): %s", resp.StatusCode, string(body))
 	}
 
-	var positions []Position
+	var positions []LighterPosition
 	if err := json.Unmarshal(body, &positions); err != nil {
 		return nil, fmt.Errorf("failed to parse positions response: %w", err)
 	}
@@ -139,7 +129,7 @@ func (t *LighterTraderV2) GetPositionsRaw(symbol string) ([]Position, error) {
 }
 
 // GetPosition Get position for specified symbol
-func (t *LighterTraderV2) GetPosition(symbol string) (*Position, error) {
+func (t *LighterTraderV2) GetPosition(symbol string) (*LighterPosition, error) {
 	positions, err := t.GetPositionsRaw(symbol)
 	if err != nil {
 		return nil, err
@@ -24,7 +24,7 @@ func (t *LighterTraderV2) SetStopLoss(symbol string, positionSide string, quanti
 	isAsk := (positionSide == "LONG" || positionSide == "long")
 
 	// Create limit stop-loss order
-	_, err := t.CreateOrder(symbol, isAsk, quantity, stopPrice, "limit")
+	_, err := t.createOrder(symbol, isAsk, quantity, stopPrice, "limit", true)
 	if err != nil {
 		return fmt.Errorf("failed to set stop-loss: %w", err)
 	}
@@ -45,7 +45,7 @@ func (t *LighterTraderV2) SetTakeProfit(symbol string, positionSide string, quan
 	isAsk := (positionSide == "LONG" || positionSide == "long")
 
 	// Create limit take-profit order
-	_, err := t.CreateOrder(symbol, isAsk, quantity, takeProfitPrice, "limit")
+	_, err := t.createOrder(symbol, isAsk, quantity, takeProfitPrice, "limit", true)
 	if err != nil {
 		return fmt.Errorf("failed to set take-profit: %w", err)
 	}
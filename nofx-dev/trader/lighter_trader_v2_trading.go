@@ -108,7 +108,7 @@ func (t *LighterTraderV2) CloseLong(symbol string, quantity float64) (map[string
 	logger.Infof("🔻 LIGHTER closing long: %s, qty=%.4f", symbol, quantity)
 
 	// Create market sell order to close (reduceOnly=true)
-	orderResult, err := t.CreateOrder(symbol, true, quantity, 0, "market")
+	orderResult, err := t.createOrder(symbol, true, quantity, 0, "market", true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to close long: %w", err)
 	}
@@ -151,7 +151,7 @@ func (t *LighterTraderV2) CloseShort(symbol string, quantity float64) (map[strin
 	logger.Infof("🔺 LIGHTER closing short: %s, qty=%.4f", symbol, quantity)
 
 	// Create market buy order to close (reduceOnly=true)
-	orderResult, err := t.CreateOrder(symbol, false, quantity, 0, "market")
+	orderResult, err := t.createOrder(symbol, false, quantity, 0, "market", true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to close short: %w", err)
 	}
@@ -172,6 +172,11 @@ func (t *LighterTraderV2) CloseShort(symbol string, quantity float64) (map[strin
 
 // CreateOrder Create order (market or limit) - uses official SDK for signing
 func (t *LighterTraderV2) CreateOrder(symbol string, isAsk bool, quantity float64, price float64, orderType string) (map[string]interface{}, error) {
+	return t.createOrder(symbol, isAsk, quantity, price, orderType, false)
+}
+
+// createOrder creates an order, reduce-only orders can never open or flip a position (closes, stop-loss, take-profit)
+func (t *LighterTraderV2) createOrder(symbol string, isAsk bool, quantity float64, price float64, orderType string, reduceOnly bool) (map[string]interface{}, error) {
 	if t.txClient == nil {
 		return nil, fmt.Errorf("TxClient not initialized")
 	}
@@ -205,7 +210,7 @@ func (t *LighterTraderV2) CreateOrder(symbol string, isAsk bool, quantity float6
 		IsAsk:            boolToUint8(isAsk),
 		Type:             orderTypeValue,
 		TimeInForce:      0, // GTC
-		ReduceOnly:       0, // Not reduce-only
+		ReduceOnly:       boolToUint8(reduceOnly),
 		TriggerPrice:     0,
 		OrderExpiry:      time.Now().Add(24 * 28 * time.Hour).UnixMilli(), // Expires in 28 days
 	}
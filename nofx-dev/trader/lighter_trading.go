@@ -59,7 +59,7 @@ func (t *LighterTrader) CloseLong(symbol string, quantity float64) (map[string]i
 	}
 
 	// Use market sell order to close
-	orderID, err := t.CreateOrder(symbol, "sell", quantity, 0, "market")
+	orderID, err := t.createOrder(symbol, "sell", quantity, 0, "market", true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to close long: %w", err)
 	}
@@ -94,7 +94,7 @@ func (t *LighterTrader) CloseShort(symbol string, quantity float64) (map[string]
 	}
 
 	// Use market buy order to close
-	orderID, err := t.CreateOrder(symbol, "buy", quantity, 0, "market")
+	orderID, err := t.createOrder(symbol, "buy", quantity, 0, "market", true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to close short: %w", err)
 	}
@@ -123,7 +123,7 @@ func (t *LighterTrader) SetStopLoss(symbol string, positionSide string, quantity
 	}
 
 	// Create limit stop-loss order
-	_, err := t.CreateOrder(symbol, side, quantity, stopPrice, "limit")
+	_, err := t.createOrder(symbol, side, quantity, stopPrice, "limit", true)
 	if err != nil {
 		return fmt.Errorf("failed to set stop-loss: %w", err)
 	}
@@ -144,7 +144,7 @@ func (t *LighterTrader) SetTakeProfit(symbol string, positionSide string, quanti
 	}
 
 	// Create limit take-profit order
-	_, err := t.CreateOrder(symbol, side, quantity, takeProfitPrice, "limit")
+	_, err := t.createOrder(symbol, side, quantity, takeProfitPrice, "limit", true)
 	if err != nil {
 		return fmt.Errorf("failed to set take-profit: %w", err)
 	}
@@ -0,0 +1,177 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// liquidationGuardCheckInterval how often positions are checked against the liquidation guard distance
+	liquidationGuardCheckInterval = 30 * time.Second
+	// estimatedMaintenanceMarginRate maintenance margin rate used when the exchange doesn't report a liquidation price
+	estimatedMaintenanceMarginRate = 0.005
+	// liquidationDeleverageCooldown minimum time between two reductions of the same position, so the exchange
+	// reflects the last one first (reducing an isolated position doesn't move its liquidation price)
+	liquidationDeleverageCooldown = 2 * time.Minute
+)
+
+// EventLiquidationProximity a position's mark price came within the liquidation guard distance
+const EventLiquidationProximity = "liquidation_proximity"
+
+// liqGuardState last alert / deleverage of positions inside the guard distance (symbol_side)
+type liqGuardState struct {
+	mu    sync.Mutex
+	acted map[string]time.Time
+}
+
+// estimateLiquidationPrice isolated-margin liquidation price from entry and leverage (0 if leverage unknown)
+func estimateLiquidationPrice(side string, entry, leverage float64) float64 {
+	if entry <= 0 || leverage <= 0 {
+		return 0
+	}
+	if side == "short" {
+		return entry * (1 + 1/leverage - estimatedMaintenanceMarginRate)
+	}
+	return entry * (1 - 1/leverage + estimatedMaintenanceMarginRate)
+}
+
+// positionLiquidationPrice the exchange's liquidation price, estimated for isolated positions that don't report one
+// Cross margin liquidation depends on the whole account and is not estimated (0 = unknown)
+func positionLiquidationPrice(pos Position, crossMargin bool) float64 {
+	if pos.LiquidationPrice > 0 {
+		return pos.LiquidationPrice
+	}
+	if pos.MarginMode == "cross" || (pos.MarginMode == "" && crossMargin) {
+		return 0
+	}
+	return estimateLiquidationPrice(pos.Side, pos.EntryPrice, pos.Leverage)
+}
+
+// liquidationDistancePct distance from mark to liquidation price in % of mark (ok false if unknown)
+func liquidationDistancePct(side string, mark, liquidation float64) (float64, bool) {
+	if mark <= 0 || liquidation <= 0 {
+		return 0, false
+	}
+	if side == "short" {
+		return (liquidation - mark) / mark * 100, true
+	}
+	return (mark - liquidation) / mark * 100, true
+}
+
+// liquidationGuardPct the strategy's liquidation guard distance in % (0 = disabled)
+func (at *AutoTrader) liquidationGuardPct() float64 {
+	if at.config.StrategyConfig == nil {
+		return 0
+	}
+	return at.config.StrategyConfig.RiskControl.LiquidationGuardPct
+}
+
+// nearLiquidation returns the managed positions whose mark is within guardPct of liquidation, with their distance
+func (at *AutoTrader) nearLiquidation(positions []Position, guardPct float64) map[string]float64 {
+	near := make(map[string]float64)
+	if guardPct <= 0 {
+		return near
+	}
+	for _, pos := range positions {
+		if pos.ReadOnly || pos.Quantity <= 0 {
+			continue
+		}
+		dist, ok := liquidationDistancePct(pos.Side, pos.MarkPrice, positionLiquidationPrice(pos, at.config.IsCrossMargin))
+		if ok && dist < guardPct {
+			near[bracketKey(pos.Symbol, pos.Side)] = dist
+		}
+	}
+	return near
+}
+
+// enforceLiquidationGuard blocks entries while positions are near liquidation (CODE ENFORCED): adding to such a
+// position always, any new entry under cross margin (shared margin brings every position closer to liquidation)
+func (at *AutoTrader) enforceLiquidationGuard(positions []Position, symbol, side string) error {
+	guardPct := at.liquidationGuardPct()
+	near := at.nearLiquidation(positions, guardPct)
+	if dist, ok := near[bracketKey(symbol, side)]; ok {
+		return fmt.Errorf("❌ %s %s mark price is %.2f%% from liquidation (guard %.2f%%), adding is blocked", symbol, side, dist, guardPct)
+	}
+	if at.config.IsCrossMargin && len(near) > 0 {
+		keys := make([]string, 0, len(near))
+		for key := range near {
+			keys = append(keys, key)
+		}
+		return fmt.Errorf("❌ Positions within %.2f%% of liquidation (%s), new cross margin entries are blocked", guardPct, strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+// checkLiquidationProximity alerts once per position entering the guard distance and, when
+// liquidation_deleverage_pct is set, reduces it by that share (again after each cooldown while still inside)
+func (at *AutoTrader) checkLiquidationProximity() {
+	guardPct := at.liquidationGuardPct()
+	if guardPct <= 0 {
+		return
+	}
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Infof("❌ Liquidation guard: failed to get positions: %v", err)
+		return
+	}
+	near := at.nearLiquidation(positions, guardPct)
+	reducePct := at.config.StrategyConfig.RiskControl.LiquidationDeleveragePct
+	now := time.Now()
+
+	// Positions due for action: just entered the guard distance, or still inside it after the deleverage cooldown
+	var due []string
+	at.liqGuard.mu.Lock()
+	if at.liqGuard.acted == nil {
+		at.liqGuard.acted = make(map[string]time.Time)
+	}
+	for key := range near {
+		last, seen := at.liqGuard.acted[key]
+		if !seen || (reducePct > 0 && now.Sub(last) >= liquidationDeleverageCooldown) {
+			at.liqGuard.acted[key] = now
+			due = append(due, key)
+		}
+	}
+	for key := range at.liqGuard.acted {
+		if _, ok := near[key]; !ok {
+			delete(at.liqGuard.acted, key) // Back outside the guard distance, act again next time
+		}
+	}
+	at.liqGuard.mu.Unlock()
+
+	var actions []string
+	for _, key := range due {
+		idx := strings.LastIndex(key, "_")
+		symbol, side, dist := key[:idx], key[idx+1:], near[key]
+		if reducePct <= 0 {
+			actions = append(actions, fmt.Sprintf("%s %s %.2f%% from liquidation", symbol, side, dist))
+			continue
+		}
+		closed, err := at.PartialClose(symbol, side, reducePct)
+		if err != nil {
+			logger.Infof("❌ Liquidation guard: failed to reduce %s %s: %v", symbol, side, err)
+			actions = append(actions, fmt.Sprintf("%s %s %.2f%% from liquidation, reduce failed: %v", symbol, side, dist, err))
+			continue
+		}
+		logger.Warnf("⚠️ [%s] Liquidation guard: %s %s %.2f%% from liquidation, reduced by %.0f%% (%.6g)", at.name, symbol, side, dist, reducePct, closed)
+		actions = append(actions, fmt.Sprintf("reduced %s %s by %.0f%% (%.2f%% from liquidation)", symbol, side, reducePct, dist))
+	}
+	if len(actions) == 0 {
+		return
+	}
+
+	event := TraderEvent{
+		TraderID:   at.id,
+		Type:       EventLiquidationProximity,
+		Exchange:   at.exchange,
+		ExchangeID: at.exchangeID,
+		Message:    fmt.Sprintf("liquidation guard %.2f%%: %s", guardPct, strings.Join(actions, ", ")),
+		Time:       time.Now(),
+	}
+	if at.config.OnEvent != nil {
+		at.config.OnEvent(event)
+	}
+	at.notifyWebhookEvent(event)
+}
@@ -0,0 +1,50 @@
+package trader
+
+import (
+	"math"
+	"nofx/store"
+	"testing"
+)
+
+// TestPositionLiquidationPrice tests reported, estimated (isolated) and unknown (cross) liquidation prices
+func TestPositionLiquidationPrice(t *testing.T) {
+	reported := Position{Side: "long", EntryPrice: 100, Leverage: 10, LiquidationPrice: 91}
+	if got := positionLiquidationPrice(reported, true); got != 91 {
+		t.Errorf("expected reported 91, got %v", got)
+	}
+
+	long := Position{Side: "long", EntryPrice: 100, Leverage: 10, MarginMode: "isolated"}
+	if got := positionLiquidationPrice(long, true); math.Abs(got-90.5) > 1e-9 {
+		t.Errorf("expected estimated long liquidation 90.5, got %v", got)
+	}
+	short := Position{Side: "short", EntryPrice: 100, Leverage: 10}
+	if got := positionLiquidationPrice(short, false); math.Abs(got-109.5) > 1e-9 {
+		t.Errorf("expected estimated short liquidation 109.5, got %v", got)
+	}
+	if got := positionLiquidationPrice(short, true); got != 0 {
+		t.Errorf("expected unknown cross margin liquidation, got %v", got)
+	}
+}
+
+// TestEnforceLiquidationGuard tests adding near liquidation is blocked, and new entries too under cross margin
+func TestEnforceLiquidationGuard(t *testing.T) {
+	strategy := &store.StrategyConfig{}
+	strategy.RiskControl.LiquidationGuardPct = 5
+	positions := []Position{
+		{Symbol: "BTCUSDT", Side: "long", Quantity: 1, MarkPrice: 100, LiquidationPrice: 97}, // 3% away
+		{Symbol: "ETHUSDT", Side: "long", Quantity: 1, MarkPrice: 100, LiquidationPrice: 80}, // 20% away
+	}
+
+	isolated := &AutoTrader{config: AutoTraderConfig{StrategyConfig: strategy}}
+	if err := isolated.enforceLiquidationGuard(positions, "BTCUSDT", "long"); err == nil {
+		t.Error("expected adding to BTCUSDT long to be blocked")
+	}
+	if err := isolated.enforceLiquidationGuard(positions, "SOLUSDT", "long"); err != nil {
+		t.Errorf("expected isolated entry elsewhere to be allowed: %v", err)
+	}
+
+	cross := &AutoTrader{config: AutoTraderConfig{StrategyConfig: strategy, IsCrossMargin: true}}
+	if err := cross.enforceLiquidationGuard(positions, "SOLUSDT", "long"); err == nil {
+		t.Error("expected cross margin entries to be blocked")
+	}
+}
@@ -0,0 +1,49 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/decision"
+	"nofx/store"
+	"sync"
+)
+
+// liquidityFilterState per-trader liquidity filter of candidate coins
+type liquidityFilterState struct {
+	mu     sync.RWMutex
+	filter store.LiquidityFilter
+}
+
+// ValidateLiquidityFilter checks the thresholds of a liquidity filter
+func ValidateLiquidityFilter(filter store.LiquidityFilter) error {
+	if filter.MinVolume24hUSD < 0 {
+		return fmt.Errorf("min 24h volume cannot be negative: %.2f", filter.MinVolume24hUSD)
+	}
+	return nil
+}
+
+// SetLiquidityFilter updates the trader's liquidity filter, applied from the next cycle
+func (at *AutoTrader) SetLiquidityFilter(filter store.LiquidityFilter) error {
+	if err := ValidateLiquidityFilter(filter); err != nil {
+		return err
+	}
+	at.liquidity.mu.Lock()
+	at.liquidity.filter = filter
+	at.liquidity.mu.Unlock()
+	return nil
+}
+
+// liquidityFilter the trader's current liquidity filter
+func (at *AutoTrader) liquidityFilter() store.LiquidityFilter {
+	at.liquidity.mu.RLock()
+	defer at.liquidity.mu.RUnlock()
+	return at.liquidity.filter
+}
+
+// liquidityNotes execution log line per candidate coin checked by the liquidity filter
+func liquidityNotes(checks []decision.LiquidityCheck) []string {
+	notes := make([]string, 0, len(checks))
+	for _, check := range checks {
+		notes = append(notes, check.String())
+	}
+	return notes
+}
@@ -0,0 +1,199 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	managedExitCheckInterval = 15 * time.Second // Managed exits are checked against the mark price at this interval
+	managedExitATRRefresh    = 5 * time.Minute  // ATR of a managed exit is refreshed at this interval
+	managedExitKlineCount    = 50               // Klines fetched for the managed exit's ATR14
+)
+
+// managedExits positions whose exit the AI handed to a deterministic trailing stop (symbol_side -> exit)
+type managedExits struct {
+	mu     sync.Mutex
+	trails map[string]*managedExit
+}
+
+// managedExit trailing state of one position plus where its ATR comes from
+type managedExit struct {
+	trail      *decision.ExitTrail
+	timeframe  string
+	atrUpdated time.Time
+}
+
+// managedExitATR ATR14 of timeframe and the current price of symbol
+func managedExitATR(symbol, timeframe string) (atr, price float64, err error) {
+	data, err := market.GetWithTimeframes(symbol, []string{timeframe}, timeframe, managedExitKlineCount)
+	if err != nil {
+		return 0, 0, err
+	}
+	return data.ATR14For(timeframe), data.CurrentPrice, nil
+}
+
+// handOffExit starts (or replaces) the managed exit of a position (side "long"/"short")
+func (at *AutoTrader) handOffExit(symbol, side string, exit decision.ManagedExit) (*decision.ExitTrail, error) {
+	timeframe := exit.Timeframe
+	if timeframe == "" {
+		timeframe = at.config.StrategyConfig.Indicators.Klines.PrimaryTimeframe
+	}
+	if timeframe == "" {
+		timeframe = "5m"
+	}
+	atr, price, err := managedExitATR(symbol, timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s ATR: %w", timeframe, err)
+	}
+	trail, err := decision.NewExitTrail(side, exit, atr, price)
+	if err != nil {
+		return nil, err
+	}
+
+	at.exits.mu.Lock()
+	if at.exits.trails == nil {
+		at.exits.trails = make(map[string]*managedExit)
+	}
+	at.exits.trails[bracketKey(symbol, side)] = &managedExit{trail: trail, timeframe: timeframe, atrUpdated: time.Now()}
+	at.exits.mu.Unlock()
+	logger.Infof("  🪢 Managed exit set: %s %s | %s ATR %.6g", symbol, side, trail, atr)
+	return trail, nil
+}
+
+// clearManagedExit removes the managed exit of a position (side "long"/"short")
+func (at *AutoTrader) clearManagedExit(symbol, side string) {
+	at.exits.mu.Lock()
+	delete(at.exits.trails, bracketKey(symbol, side))
+	at.exits.mu.Unlock()
+}
+
+// managedExitSummary describes the active managed exit of a position for the prompt ("" if none)
+func (at *AutoTrader) managedExitSummary(symbol, side string) string {
+	at.exits.mu.Lock()
+	defer at.exits.mu.Unlock()
+	if m, ok := at.exits.trails[bracketKey(symbol, side)]; ok {
+		return m.trail.String()
+	}
+	return ""
+}
+
+// executeManageExitWithRecord hands the exit of an existing position to a managed trailing stop
+func (at *AutoTrader) executeManageExitWithRecord(d *decision.Decision, actionRecord *store.DecisionAction, side string) error {
+	logger.Infof("  🪢 Managed exit %s: %s", side, d.Symbol)
+	if d.ManagedExit == nil {
+		return fmt.Errorf("managed_exit is required")
+	}
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		return fmt.Errorf("failed to get positions: %w", err)
+	}
+	found := false
+	for _, pos := range positions {
+		if pos.Symbol == d.Symbol && pos.Side == side {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("no %s %s position to manage", d.Symbol, side)
+	}
+
+	trail, err := at.handOffExit(d.Symbol, side, *d.ManagedExit)
+	if err != nil {
+		return err
+	}
+	actionRecord.Price = trail.Extreme
+	actionRecord.StopLoss = trail.Stop
+	return nil
+}
+
+// checkManagedExits trails managed exits with mark prices and closes positions whose stop was crossed
+func (at *AutoTrader) checkManagedExits() {
+	at.exits.mu.Lock()
+	empty := len(at.exits.trails) == 0
+	at.exits.mu.Unlock()
+	if empty {
+		return
+	}
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Infof("❌ Managed exit monitoring: failed to get positions: %v", err)
+		return
+	}
+	open := make(map[string]float64, len(positions))
+	for _, pos := range positions {
+		if pos.ReadOnly {
+			continue
+		}
+		open[bracketKey(pos.Symbol, pos.Side)] = pos.MarkPrice
+	}
+
+	// ATR refresh needs market data calls, done outside the lock
+	var stale []string
+	at.exits.mu.Lock()
+	for key, m := range at.exits.trails {
+		if time.Since(m.atrUpdated) >= managedExitATRRefresh {
+			stale = append(stale, key)
+		}
+	}
+	at.exits.mu.Unlock()
+	for _, key := range stale {
+		symbol := key[:strings.LastIndex(key, "_")]
+		at.exits.mu.Lock()
+		m, ok := at.exits.trails[key]
+		timeframe := ""
+		if ok {
+			timeframe = m.timeframe
+		}
+		at.exits.mu.Unlock()
+		if !ok {
+			continue
+		}
+		atr, _, err := managedExitATR(symbol, timeframe)
+		at.exits.mu.Lock()
+		if m, ok := at.exits.trails[key]; ok {
+			m.atrUpdated = time.Now()
+			if err == nil {
+				m.trail.SetATR(atr)
+			}
+		}
+		at.exits.mu.Unlock()
+	}
+
+	var triggered []string
+	at.exits.mu.Lock()
+	for key, m := range at.exits.trails {
+		price, ok := open[key]
+		if !ok {
+			delete(at.exits.trails, key) // Position closed elsewhere
+			continue
+		}
+		if m.trail.Hit(price) {
+			triggered = append(triggered, key)
+			logger.Infof("🪢 Managed exit triggered: %s | %s | mark %.6f", key, m.trail, price)
+			continue
+		}
+		m.trail.Advance(price, price, price)
+	}
+	at.exits.mu.Unlock()
+
+	for _, key := range triggered {
+		idx := strings.LastIndex(key, "_")
+		symbol, side := key[:idx], key[idx+1:]
+		if err := at.emergencyClosePosition(symbol, side); err != nil {
+			logger.Infof("❌ Managed exit close failed (%s %s): %v", symbol, side, err)
+			continue
+		}
+		at.clearTrailingStop(symbol, side)
+		at.ClearPeakPnLCache(symbol, side)
+	}
+}
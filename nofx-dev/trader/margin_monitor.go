@@ -0,0 +1,126 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"sync"
+	"time"
+)
+
+const (
+	// marginRatioCheckInterval how often the account margin ratio is checked against the threshold
+	marginRatioCheckInterval = 30 * time.Second
+	// marginReduceCooldown minimum time between two reductions, so the exchange reflects the last one first
+	marginReduceCooldown = 2 * time.Minute
+	// defaultMarginReducePct share of the position closed when margin_reduce_pct is not set
+	defaultMarginReducePct = 25.0
+)
+
+// EventMarginRatioHigh the margin ratio threshold was reached and a position was reduced
+const EventMarginRatioHigh = "margin_ratio_high"
+
+// marginMonitorState last automatic reduction of the margin ratio monitor
+type marginMonitorState struct {
+	mu          sync.Mutex
+	lastReduce  time.Time
+	unsupported bool // Exchange doesn't report a margin ratio (logged once)
+}
+
+// marginRatioPct returns the account margin ratio (maintenance margin / margin balance, %) reported in a
+// GetBalance result, ok is false when the exchange doesn't report one
+func marginRatioPct(balance map[string]interface{}) (float64, bool) {
+	ratio, ok := balance["marginRatio"].(float64)
+	return ratio, ok
+}
+
+// largestLoser returns the managed position with the largest unrealized loss (ok false if none is losing)
+func largestLoser(positions []Position) (Position, bool) {
+	var worst Position
+	found := false
+	for _, pos := range positions {
+		if pos.ReadOnly || pos.Quantity <= 0 || pos.UnrealizedPnL >= 0 {
+			continue
+		}
+		if !found || pos.UnrealizedPnL < worst.UnrealizedPnL {
+			worst, found = pos, true
+		}
+	}
+	return worst, found
+}
+
+// checkMarginRatio reduces the largest losing position by margin_reduce_pct once the account margin ratio
+// reaches max_margin_ratio_pct, and alerts, instead of waiting for the exchange to liquidate
+func (at *AutoTrader) checkMarginRatio() {
+	if at.config.StrategyConfig == nil {
+		return
+	}
+	rc := at.config.StrategyConfig.RiskControl
+	if rc.MaxMarginRatioPct <= 0 {
+		return
+	}
+
+	at.margin.mu.Lock()
+	coolingDown := time.Since(at.margin.lastReduce) < marginReduceCooldown
+	at.margin.mu.Unlock()
+	if coolingDown {
+		return
+	}
+
+	balance, err := at.trader.GetBalance()
+	if err != nil {
+		logger.Infof("❌ Margin ratio check: failed to get balance: %v", err)
+		return
+	}
+	ratio, ok := marginRatioPct(balance)
+	if !ok {
+		at.margin.mu.Lock()
+		if !at.margin.unsupported {
+			at.margin.unsupported = true
+			logger.Infof("⚠️ [%s] %s doesn't report a margin ratio, margin ratio monitor inactive", at.name, at.exchange)
+		}
+		at.margin.mu.Unlock()
+		return
+	}
+	if ratio < rc.MaxMarginRatioPct {
+		return
+	}
+
+	message := fmt.Sprintf("margin ratio %.2f%% reached the %.2f%% threshold", ratio, rc.MaxMarginRatioPct)
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Infof("❌ Margin ratio check: failed to get positions: %v", err)
+		return
+	}
+	if pos, ok := largestLoser(positions); ok {
+		pct := rc.MarginReducePct
+		if pct <= 0 {
+			pct = defaultMarginReducePct
+		}
+		closed, err := at.PartialClose(pos.Symbol, pos.Side, pct)
+		if err != nil {
+			message += fmt.Sprintf(", failed to reduce %s %s: %v", pos.Symbol, pos.Side, err)
+		} else {
+			message += fmt.Sprintf(", reduced %s %s by %.0f%% (%.6g, unrealized P&L %.2f)", pos.Symbol, pos.Side, pct, closed, pos.UnrealizedPnL)
+		}
+	} else {
+		message += ", no losing position to reduce"
+	}
+	at.margin.mu.Lock()
+	at.margin.lastReduce = time.Now()
+	at.margin.mu.Unlock()
+
+	logger.Warnf("⚠️ [%s] Margin monitor: %s", at.name, message)
+	event := TraderEvent{
+		TraderID:   at.id,
+		Type:       EventMarginRatioHigh,
+		Exchange:   at.exchange,
+		ExchangeID: at.exchangeID,
+		Message:    message,
+		Time:       time.Now(),
+		Details:    map[string]interface{}{"margin_ratio_pct": ratio, "threshold_pct": rc.MaxMarginRatioPct},
+	}
+	if at.config.OnEvent != nil {
+		at.config.OnEvent(event)
+	}
+	at.notifyWebhookEvent(event)
+}
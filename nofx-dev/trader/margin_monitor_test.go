@@ -0,0 +1,31 @@
+package trader
+
+import "testing"
+
+// TestLargestLoser tests picking the managed position with the largest unrealized loss
+func TestLargestLoser(t *testing.T) {
+	positions := []Position{
+		{Symbol: "BTCUSDT", Side: "long", Quantity: 1, UnrealizedPnL: -50},
+		{Symbol: "ETHUSDT", Side: "short", Quantity: 2, UnrealizedPnL: -120},
+		{Symbol: "SOLUSDT", Side: "long", Quantity: 5, UnrealizedPnL: 300},
+		{Symbol: "XRPUSDT", Side: "long", Quantity: 9, UnrealizedPnL: -500, ReadOnly: true}, // Held on the fallback exchange
+	}
+	pos, ok := largestLoser(positions)
+	if !ok || pos.Symbol != "ETHUSDT" {
+		t.Errorf("expected ETHUSDT, got %+v (ok=%v)", pos, ok)
+	}
+
+	if _, ok := largestLoser(positions[2:3]); ok {
+		t.Error("expected no loser when every position is in profit")
+	}
+}
+
+// TestMarginRatioPct tests reading the margin ratio reported by GetBalance
+func TestMarginRatioPct(t *testing.T) {
+	if ratio, ok := marginRatioPct(map[string]interface{}{"marginRatio": 42.5}); !ok || ratio != 42.5 {
+		t.Errorf("expected 42.5, got %v (ok=%v)", ratio, ok)
+	}
+	if _, ok := marginRatioPct(map[string]interface{}{"totalWalletBalance": 1000.0}); ok {
+		t.Error("expected not ok without a reported margin ratio")
+	}
+}
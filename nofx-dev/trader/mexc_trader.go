@@ -0,0 +1,1042 @@
+package trader
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"nofx/logger"
+	"nofx/market"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MEXC contract API endpoints
+const (
+	mexcBaseURL             = "https://contract.mexc.com"
+	mexcAssetsPath          = "/api/v1/private/account/assets"
+	mexcOpenPositionsPath   = "/api/v1/private/position/open_positions"
+	mexcHistoryPositionPath = "/api/v1/private/position/list/history_positions"
+	mexcLeveragePath        = "/api/v1/private/position/change_leverage"
+	mexcGetLeveragePath     = "/api/v1/private/position/leverage"
+	mexcPositionModePath    = "/api/v1/private/position/change_position_mode"
+	mexcOrderPath           = "/api/v1/private/order/submit"
+	mexcOrderDetailPath     = "/api/v1/private/order/get/"
+	mexcCancelAllPath       = "/api/v1/private/order/cancel_all"
+	mexcPlanOrderPath       = "/api/v1/private/planorder/place"
+	mexcPlanOrderListPath   = "/api/v1/private/planorder/list/orders"
+	mexcPlanCancelPath      = "/api/v1/private/planorder/cancel"
+	mexcPlanCancelAllPath   = "/api/v1/private/planorder/cancel_all"
+	mexcTickerPath          = "/api/v1/contract/ticker"
+	mexcContractDetailPath  = "/api/v1/contract/detail"
+)
+
+// MEXC order sides
+const (
+	mexcSideOpenLong   = 1
+	mexcSideCloseShort = 2
+	mexcSideOpenShort  = 3
+	mexcSideCloseLong  = 4
+)
+
+// MEXC plan order trigger types
+const (
+	mexcTriggerGTE = 1 // Trigger when price >= triggerPrice
+	mexcTriggerLTE = 2 // Trigger when price <= triggerPrice
+)
+
+func init() {
+	// MEXC perpetual default tier: 0% maker, 0.02% taker
+	logger.RegisterFeeSchedule("mexc", logger.FeeSchedule{Maker: 0, Taker: 0.0002})
+}
+
+// MEXCTrader MEXC perpetual futures trader
+type MEXCTrader struct {
+	apiKey    string
+	secretKey string
+
+	// Default margin mode, for symbols without one set (MEXC passes openType per order)
+	isCrossMargin bool
+
+	// Leverage and margin mode per symbol, sent along with each order
+	leverages      map[string]int
+	marginModes    map[string]bool // symbol -> cross margin, set once the exchange accepted it
+	leveragesMutex sync.RWMutex
+
+	baseURL string
+
+	// HTTP client
+	httpClient *http.Client
+
+	// Balance cache
+	cachedBalance     map[string]interface{}
+	balanceCacheTime  time.Time
+	balanceCacheMutex sync.RWMutex
+
+	// Positions cache
+	cachedPositions     []Position
+	positionsCacheTime  time.Time
+	positionsCacheMutex sync.RWMutex
+
+	// Contract info cache
+	contractsCache      map[string]*MEXCContract
+	contractsCacheTime  time.Time
+	contractsCacheMutex sync.RWMutex
+
+	// Cache duration
+	cacheDuration time.Duration
+}
+
+// MEXCContract MEXC contract info
+type MEXCContract struct {
+	Symbol       string  // Contract symbol (e.g., "BTC_USDT")
+	ContractSize float64 // Base asset per contract
+	MinVol       float64 // Minimum order volume (contracts)
+	MaxVol       float64 // Maximum order volume (contracts)
+	VolScale     int     // Volume decimal places
+	PriceScale   int     // Price decimal places
+}
+
+// MEXCResponse MEXC API response
+type MEXCResponse struct {
+	Success bool            `json:"success"`
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// NewMEXCTrader creates MEXC trader
+func NewMEXCTrader(apiKey, secretKey string) *MEXCTrader {
+	trader := &MEXCTrader{
+		apiKey:        apiKey,
+		secretKey:     secretKey,
+		isCrossMargin: true,
+		leverages:     make(map[string]int),
+		marginModes:   make(map[string]bool),
+		baseURL:       mexcBaseURL,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: http.DefaultTransport,
+		},
+		cacheDuration:  15 * time.Second,
+		contractsCache: make(map[string]*MEXCContract),
+	}
+
+	// Set hedge position mode
+	if err := trader.setPositionMode(); err != nil {
+		logger.Infof("⚠️ Failed to set MEXC position mode: %v (ignore if already in hedge mode)", err)
+	}
+
+	return trader
+}
+
+// setPositionMode sets hedge (dual) position mode
+func (t *MEXCTrader) setPositionMode() error {
+	_, err := t.doRequest("POST", mexcPositionModePath, nil, map[string]interface{}{
+		"positionMode": 1, // 1=hedge, 2=one-way
+	})
+	if err != nil {
+		return err
+	}
+	logger.Infof("  ✓ MEXC account is in hedge position mode")
+	return nil
+}
+
+// sign generates MEXC API signature: hex(HMAC-SHA256(secret, apiKey + timestamp + params))
+func (t *MEXCTrader) sign(timestamp, params string) string {
+	h := hmac.New(sha256.New, []byte(t.secretKey))
+	h.Write([]byte(t.apiKey + timestamp + params))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// doRequest executes HTTP request
+// GET requests sign the sorted query string, POST requests sign the JSON body
+func (t *MEXCTrader) doRequest(method, path string, query url.Values, body interface{}) ([]byte, error) {
+	var bodyBytes []byte
+	var err error
+
+	if body != nil {
+		bodyBytes, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize request body: %w", err)
+		}
+	}
+
+	// url.Values.Encode sorts by key, as required by MEXC
+	queryStr := query.Encode()
+	signParams := queryStr
+	if method != "GET" {
+		signParams = string(bodyBytes)
+	}
+
+	fullURL := t.baseURL + path
+	if queryStr != "" {
+		fullURL += "?" + queryStr
+	}
+
+	req, err := http.NewRequest(method, fullURL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	req.Header.Set("ApiKey", t.apiKey)
+	req.Header.Set("Request-Time", timestamp)
+	req.Header.Set("Signature", t.sign(timestamp, signParams))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var mexcResp MEXCResponse
+	if err := json.Unmarshal(respBody, &mexcResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response (HTTP %d): %w", resp.StatusCode, err)
+	}
+
+	if !mexcResp.Success || mexcResp.Code != 0 {
+		return nil, fmt.Errorf("MEXC API error: code=%d, msg=%s", mexcResp.Code, mexcResp.Message)
+	}
+
+	return mexcResp.Data, nil
+}
+
+// convertSymbol converts generic symbol to MEXC format
+// e.g. BTCUSDT -> BTC_USDT, BTCUSDC -> BTC_USDC
+func (t *MEXCTrader) convertSymbol(symbol string) string {
+	return market.VenueSymbol(market.VenueMEXC, symbol)
+}
+
+// convertSymbolBack converts MEXC format back to generic symbol
+// e.g. BTC_USDT -> BTCUSDT
+func (t *MEXCTrader) convertSymbolBack(symbol string) string {
+	return market.CanonicalSymbol(market.VenueMEXC, symbol)
+}
+
+// openType returns the symbol's MEXC margin type (1=isolated, 2=cross)
+func (t *MEXCTrader) openType(symbol string) int {
+	t.leveragesMutex.RLock()
+	isCross, ok := t.marginModes[symbol]
+	t.leveragesMutex.RUnlock()
+	if !ok {
+		isCross = t.isCrossMargin
+	}
+	return mexcOpenType(isCross)
+}
+
+// mexcOpenType MEXC margin type of a margin mode (1=isolated, 2=cross)
+func mexcOpenType(isCrossMargin bool) int {
+	if isCrossMargin {
+		return 2
+	}
+	return 1
+}
+
+// leverageFor returns the last leverage set for symbol
+func (t *MEXCTrader) leverageFor(symbol string) int {
+	t.leveragesMutex.RLock()
+	defer t.leveragesMutex.RUnlock()
+	if lev, ok := t.leverages[symbol]; ok {
+		return lev
+	}
+	return 1
+}
+
+// GetBalance gets account balance
+func (t *MEXCTrader) GetBalance() (map[string]interface{}, error) {
+	// Check cache
+	t.balanceCacheMutex.RLock()
+	if t.cachedBalance != nil && time.Since(t.balanceCacheTime) < t.cacheDuration {
+		t.balanceCacheMutex.RUnlock()
+		logger.Infof("✓ Using cached MEXC account balance")
+		return t.cachedBalance, nil
+	}
+	t.balanceCacheMutex.RUnlock()
+
+	logger.Infof("🔄 Calling MEXC API to get account balance...")
+	data, err := t.doRequest("GET", mexcAssetsPath, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account balance: %w", err)
+	}
+
+	var assets []struct {
+		Currency         string  `json:"currency"`
+		Equity           float64 `json:"equity"`
+		CashBalance      float64 `json:"cashBalance"`
+		AvailableBalance float64 `json:"availableBalance"`
+		Unrealized       float64 `json:"unrealized"`
+	}
+	if err := json.Unmarshal(data, &assets); err != nil {
+		return nil, fmt.Errorf("failed to parse balance data: %w", err)
+	}
+
+	var totalEquity, available, unrealized float64
+	quoteBalances := make(map[string]float64)
+	for _, asset := range assets {
+		if !market.IsSupportedQuote(asset.Currency) {
+			continue
+		}
+		quoteBalances[asset.Currency] = asset.AvailableBalance
+		totalEquity += asset.Equity
+		unrealized += asset.Unrealized
+		if asset.Currency == market.DefaultQuoteAsset {
+			available = asset.AvailableBalance
+		}
+	}
+
+	result := map[string]interface{}{
+		"totalWalletBalance":    totalEquity - unrealized,
+		"availableBalance":      available,
+		"totalUnrealizedProfit": unrealized,
+		"total_equity":          totalEquity,
+		"quoteBalances":         quoteBalances,
+	}
+
+	logger.Infof("✓ MEXC balance: Total equity=%.2f, Available=%.2f, Unrealized PnL=%.2f", totalEquity, available, unrealized)
+
+	// Update cache
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = result
+	t.balanceCacheTime = time.Now()
+	t.balanceCacheMutex.Unlock()
+
+	return result, nil
+}
+
+// GetPositions gets all positions
+func (t *MEXCTrader) GetPositions() ([]Position, error) {
+	// Check cache
+	t.positionsCacheMutex.RLock()
+	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
+		t.positionsCacheMutex.RUnlock()
+		logger.Infof("✓ Using cached MEXC positions")
+		return t.cachedPositions, nil
+	}
+	t.positionsCacheMutex.RUnlock()
+
+	logger.Infof("🔄 Calling MEXC API to get positions...")
+	data, err := t.doRequest("GET", mexcOpenPositionsPath, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	var positions []struct {
+		PositionID     int64   `json:"positionId"`
+		Symbol         string  `json:"symbol"`
+		PositionType   int     `json:"positionType"` // 1=long, 2=short
+		HoldVol        float64 `json:"holdVol"`      // Contracts
+		HoldAvgPrice   float64 `json:"holdAvgPrice"`
+		LiquidatePrice float64 `json:"liquidatePrice"`
+		Leverage       float64 `json:"leverage"`
+		CreateTime     int64   `json:"createTime"`
+		UpdateTime     int64   `json:"updateTime"`
+	}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, fmt.Errorf("failed to parse position data: %w", err)
+	}
+
+	result := make([]Position, 0, len(positions))
+	for _, pos := range positions {
+		if pos.HoldVol == 0 {
+			continue
+		}
+
+		symbol := t.convertSymbolBack(pos.Symbol)
+		side := "long"
+		if pos.PositionType == 2 {
+			side = "short"
+		}
+
+		// Convert contracts to base asset quantity
+		posAmt := pos.HoldVol
+		if contract, err := t.getContract(symbol); err == nil && contract.ContractSize > 0 {
+			posAmt = pos.HoldVol * contract.ContractSize
+		}
+
+		// MEXC positions don't carry mark price or unrealized PnL, derive from fair price
+		markPrice, err := t.getFairPrice(symbol)
+		if err != nil {
+			markPrice = pos.HoldAvgPrice
+		}
+		upl := (markPrice - pos.HoldAvgPrice) * posAmt
+		if side == "short" {
+			upl = -upl
+		}
+
+		result = append(result, Position{
+			Symbol:           symbol,
+			Side:             side,
+			Quantity:         posAmt,
+			EntryPrice:       pos.HoldAvgPrice,
+			MarkPrice:        markPrice,
+			UnrealizedPnL:    upl,
+			Leverage:         pos.Leverage,
+			LiquidationPrice: pos.LiquidatePrice,
+			CreatedTime:      pos.CreateTime,
+			UpdatedTime:      pos.UpdateTime,
+		})
+	}
+
+	// Update cache
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = result
+	t.positionsCacheTime = time.Now()
+	t.positionsCacheMutex.Unlock()
+
+	return result, nil
+}
+
+// invalidateCaches clears balance/position caches after order placement
+func (t *MEXCTrader) invalidateCaches() {
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = nil
+	t.balanceCacheMutex.Unlock()
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = nil
+	t.positionsCacheMutex.Unlock()
+}
+
+// getContract gets contract info
+func (t *MEXCTrader) getContract(symbol string) (*MEXCContract, error) {
+	mexcSymbol := t.convertSymbol(symbol)
+
+	// Check cache
+	t.contractsCacheMutex.RLock()
+	if contract, ok := t.contractsCache[mexcSymbol]; ok && time.Since(t.contractsCacheTime) < 5*time.Minute {
+		t.contractsCacheMutex.RUnlock()
+		return contract, nil
+	}
+	t.contractsCacheMutex.RUnlock()
+
+	data, err := t.doRequest("GET", mexcContractDetailPath, url.Values{"symbol": {mexcSymbol}}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var detail struct {
+		Symbol       string  `json:"symbol"`
+		ContractSize float64 `json:"contractSize"`
+		MinVol       float64 `json:"minVol"`
+		MaxVol       float64 `json:"maxVol"`
+		VolScale     int     `json:"volScale"`
+		PriceScale   int     `json:"priceScale"`
+	}
+	if err := json.Unmarshal(data, &detail); err != nil {
+		return nil, err
+	}
+	if detail.Symbol == "" || detail.ContractSize <= 0 {
+		return nil, fmt.Errorf("contract info not found: %s", mexcSymbol)
+	}
+
+	contract := &MEXCContract{
+		Symbol:       detail.Symbol,
+		ContractSize: detail.ContractSize,
+		MinVol:       detail.MinVol,
+		MaxVol:       detail.MaxVol,
+		VolScale:     detail.VolScale,
+		PriceScale:   detail.PriceScale,
+	}
+
+	// Update cache
+	t.contractsCacheMutex.Lock()
+	t.contractsCache[mexcSymbol] = contract
+	t.contractsCacheTime = time.Now()
+	t.contractsCacheMutex.Unlock()
+
+	return contract, nil
+}
+
+// toVolume converts base asset quantity to contract volume (rounded down to volScale)
+func (t *MEXCTrader) toVolume(quantity float64, contract *MEXCContract) float64 {
+	vol := quantity / contract.ContractSize
+	scale := math.Pow(10, float64(contract.VolScale))
+	vol = math.Floor(vol*scale+1e-9) / scale
+	if contract.MaxVol > 0 && vol > contract.MaxVol {
+		logger.Infof("  ⚠️ MEXC order volume %.4f exceeds max %.4f, reducing to max", vol, contract.MaxVol)
+		vol = contract.MaxVol
+	}
+	return vol
+}
+
+// SetMarginMode sets the symbol's margin mode on the exchange (MEXC changes it along with the leverage)
+func (t *MEXCTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	mode := "isolated"
+	if isCrossMargin {
+		mode = "cross"
+	}
+	t.leveragesMutex.RLock()
+	current, known := t.marginModes[symbol]
+	leverage, hasLeverage := t.leverages[symbol]
+	t.leveragesMutex.RUnlock()
+	if known && current == isCrossMargin {
+		return nil
+	}
+
+	if !hasLeverage {
+		var err error
+		if leverage, err = t.currentLeverage(symbol); err != nil {
+			return fmt.Errorf("failed to set %s margin mode to %s: %w", symbol, mode, err)
+		}
+	}
+	if err := t.changeLeverage(symbol, leverage, mexcOpenType(isCrossMargin)); err != nil {
+		return fmt.Errorf("failed to set %s margin mode to %s: %w", symbol, mode, err)
+	}
+
+	t.leveragesMutex.Lock()
+	t.marginModes[symbol] = isCrossMargin
+	t.leverages[symbol] = leverage
+	t.leveragesMutex.Unlock()
+
+	logger.Infof("  ✓ %s margin mode set to %s", symbol, mode)
+	return nil
+}
+
+// SetLeverage sets leverage
+func (t *MEXCTrader) SetLeverage(symbol string, leverage int) error {
+	if err := t.changeLeverage(symbol, leverage, t.openType(symbol)); err != nil {
+		return fmt.Errorf("failed to set %s leverage: %w", symbol, err)
+	}
+
+	t.leveragesMutex.Lock()
+	t.leverages[symbol] = leverage
+	t.leveragesMutex.Unlock()
+
+	logger.Infof("  ✓ %s leverage set to %dx", symbol, leverage)
+	return nil
+}
+
+// changeLeverage sets leverage and margin type of both position sides
+func (t *MEXCTrader) changeLeverage(symbol string, leverage, openType int) error {
+	mexcSymbol := t.convertSymbol(symbol)
+	for _, positionType := range []int{1, 2} {
+		body := map[string]interface{}{
+			"symbol":       mexcSymbol,
+			"leverage":     leverage,
+			"openType":     openType,
+			"positionType": positionType,
+		}
+		if _, err := t.doRequest("POST", mexcLeveragePath, nil, body); err != nil {
+			return fmt.Errorf("positionType=%d: %w", positionType, err)
+		}
+	}
+	return nil
+}
+
+// currentLeverage returns the symbol's leverage on the exchange (the higher of both position sides)
+func (t *MEXCTrader) currentLeverage(symbol string) (int, error) {
+	data, err := t.doRequest("GET", mexcGetLeveragePath, url.Values{"symbol": {t.convertSymbol(symbol)}}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get leverage: %w", err)
+	}
+	var sides []struct {
+		PositionType int `json:"positionType"`
+		Leverage     int `json:"leverage"`
+	}
+	if err := json.Unmarshal(data, &sides); err != nil {
+		return 0, fmt.Errorf("failed to parse leverage: %w", err)
+	}
+	leverage := 0
+	for _, side := range sides {
+		if side.Leverage > leverage {
+			leverage = side.Leverage
+		}
+	}
+	if leverage <= 0 {
+		return 0, fmt.Errorf("no leverage returned for %s", symbol)
+	}
+	return leverage, nil
+}
+
+// placeMarketOrder submits a market order, quantity is in base asset
+func (t *MEXCTrader) placeMarketOrder(symbol string, side int, quantity float64, leverage int) (map[string]interface{}, error) {
+	contract, err := t.getContract(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract info: %w", err)
+	}
+
+	vol := t.toVolume(quantity, contract)
+	if vol <= 0 || (contract.MinVol > 0 && vol < contract.MinVol) {
+		return nil, fmt.Errorf("order volume %.6f contracts is below minimum %.6f (quantity=%.6f, contractSize=%.6f)",
+			vol, contract.MinVol, quantity, contract.ContractSize)
+	}
+
+	body := map[string]interface{}{
+		"symbol":   contract.Symbol,
+		"vol":      vol,
+		"leverage": leverage,
+		"side":     side,
+		"type":     5, // Market order
+		"openType": t.openType(symbol),
+	}
+	if side == mexcSideCloseLong || side == mexcSideCloseShort {
+		// Close sides only reduce in hedge mode, reduceOnly keeps that true on one-way accounts
+		body["reduceOnly"] = true
+	}
+
+	data, err := t.doRequest("POST", mexcOrderPath, nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	orderID := parseMEXCOrderID(data)
+	if orderID == "" {
+		return nil, fmt.Errorf("empty order ID in response: %s", string(data))
+	}
+
+	t.invalidateCaches()
+
+	logger.Infof("  📊 MEXC order: %s side=%d, quantity=%.6f, contractSize=%.6f, vol=%.4f",
+		symbol, side, quantity, contract.ContractSize, vol)
+
+	return map[string]interface{}{
+		"orderId": orderID,
+		"symbol":  symbol,
+		"status":  "FILLED",
+	}, nil
+}
+
+// parseMEXCOrderID extracts the order ID, which MEXC returns either as a bare value or as {"orderId": ...}
+func parseMEXCOrderID(data json.RawMessage) string {
+	var wrapped struct {
+		OrderID json.Number `json:"orderId"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err == nil && wrapped.OrderID != "" {
+		return wrapped.OrderID.String()
+	}
+	return strings.Trim(string(data), "\" ")
+}
+
+// OpenLong opens long position
+func (t *MEXCTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// Cancel old orders
+	t.CancelAllOrders(symbol)
+
+	// Set leverage
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		logger.Infof("  ⚠️ Failed to set leverage: %v", err)
+	}
+
+	result, err := t.placeMarketOrder(symbol, mexcSideOpenLong, quantity, leverage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open long position: %w", err)
+	}
+
+	logger.Infof("✓ MEXC opened long position successfully: %s quantity: %.6f", symbol, quantity)
+	logger.Infof("  Order ID: %v", result["orderId"])
+	return result, nil
+}
+
+// OpenShort opens short position
+func (t *MEXCTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// Cancel old orders
+	t.CancelAllOrders(symbol)
+
+	// Set leverage
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		logger.Infof("  ⚠️ Failed to set leverage: %v", err)
+	}
+
+	result, err := t.placeMarketOrder(symbol, mexcSideOpenShort, quantity, leverage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open short position: %w", err)
+	}
+
+	logger.Infof("✓ MEXC opened short position successfully: %s quantity: %.6f", symbol, quantity)
+	logger.Infof("  Order ID: %v", result["orderId"])
+	return result, nil
+}
+
+// positionQuantity returns the current position size (base asset) for symbol/side
+func (t *MEXCTrader) positionQuantity(symbol, side string) (float64, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, err
+	}
+	for _, pos := range positions {
+		if pos.Symbol == symbol && pos.Side == side {
+			return pos.Quantity, nil
+		}
+	}
+	return 0, fmt.Errorf("%s position not found for %s", side, symbol)
+}
+
+// CloseLong closes long position
+func (t *MEXCTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	// If quantity is 0, close entire position
+	if quantity == 0 {
+		var err error
+		if quantity, err = t.positionQuantity(symbol, "long"); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := t.placeMarketOrder(symbol, mexcSideCloseLong, quantity, t.leverageFor(symbol))
+	if err != nil {
+		return nil, fmt.Errorf("failed to close long position: %w", err)
+	}
+
+	logger.Infof("✓ MEXC closed long position successfully: %s quantity: %.6f", symbol, quantity)
+
+	// Cancel pending orders after closing position
+	t.CancelAllOrders(symbol)
+	return result, nil
+}
+
+// CloseShort closes short position
+func (t *MEXCTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	// If quantity is 0, close entire position
+	if quantity == 0 {
+		var err error
+		if quantity, err = t.positionQuantity(symbol, "short"); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := t.placeMarketOrder(symbol, mexcSideCloseShort, quantity, t.leverageFor(symbol))
+	if err != nil {
+		return nil, fmt.Errorf("failed to close short position: %w", err)
+	}
+
+	logger.Infof("✓ MEXC closed short position successfully: %s quantity: %.6f", symbol, quantity)
+
+	// Cancel pending orders after closing position
+	t.CancelAllOrders(symbol)
+	return result, nil
+}
+
+// getTicker gets ticker data
+func (t *MEXCTrader) getTicker(symbol string) (lastPrice, fairPrice float64, err error) {
+	data, err := t.doRequest("GET", mexcTickerPath, url.Values{"symbol": {t.convertSymbol(symbol)}}, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var ticker struct {
+		LastPrice float64 `json:"lastPrice"`
+		FairPrice float64 `json:"fairPrice"`
+	}
+	if err := json.Unmarshal(data, &ticker); err != nil {
+		return 0, 0, err
+	}
+	if ticker.LastPrice <= 0 {
+		return 0, 0, fmt.Errorf("no price data received")
+	}
+	return ticker.LastPrice, ticker.FairPrice, nil
+}
+
+// getFairPrice gets mark (fair) price
+func (t *MEXCTrader) getFairPrice(symbol string) (float64, error) {
+	last, fair, err := t.getTicker(symbol)
+	if err != nil {
+		return 0, err
+	}
+	if fair > 0 {
+		return fair, nil
+	}
+	return last, nil
+}
+
+// GetMarketPrice gets market price
+func (t *MEXCTrader) GetMarketPrice(symbol string) (float64, error) {
+	price, _, err := t.getTicker(symbol)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get price: %w", err)
+	}
+	return price, nil
+}
+
+// placeTriggerOrder places a reduce-only plan order that closes the position at market when triggered
+func (t *MEXCTrader) placeTriggerOrder(symbol, positionSide string, quantity, triggerPrice float64, isStopLoss bool) error {
+	contract, err := t.getContract(symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get contract info: %w", err)
+	}
+
+	// Long: SL triggers on price <= stop, TP on price >= target; short is reversed
+	side := mexcSideCloseLong
+	triggerType := mexcTriggerLTE
+	if !isStopLoss {
+		triggerType = mexcTriggerGTE
+	}
+	if strings.ToUpper(positionSide) == "SHORT" {
+		side = mexcSideCloseShort
+		if isStopLoss {
+			triggerType = mexcTriggerGTE
+		} else {
+			triggerType = mexcTriggerLTE
+		}
+	}
+
+	body := map[string]interface{}{
+		"symbol":       contract.Symbol,
+		"vol":          t.toVolume(quantity, contract),
+		"leverage":     t.leverageFor(symbol),
+		"side":         side,
+		"openType":     t.openType(symbol),
+		"triggerPrice": triggerPrice,
+		"triggerType":  triggerType,
+		"executeCycle": 2, // Valid for 7 days
+		"orderType":    5, // Market order when triggered
+		"trend":        1, // Trigger on latest price
+		"reduceOnly":   true,
+	}
+
+	_, err = t.doRequest("POST", mexcPlanOrderPath, nil, body)
+	return err
+}
+
+// SetStopLoss sets stop loss order
+func (t *MEXCTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if err := t.placeTriggerOrder(symbol, positionSide, quantity, stopPrice, true); err != nil {
+		return fmt.Errorf("failed to set stop loss: %w", err)
+	}
+	logger.Infof("  Stop loss price set: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit sets take profit order
+func (t *MEXCTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	if err := t.placeTriggerOrder(symbol, positionSide, quantity, takeProfitPrice, false); err != nil {
+		return fmt.Errorf("failed to set take profit: %w", err)
+	}
+	logger.Infof("  Take profit price set: %.4f", takeProfitPrice)
+	return nil
+}
+
+// CancelStopLossOrders cancels stop loss orders
+func (t *MEXCTrader) CancelStopLossOrders(symbol string) error {
+	return t.cancelPlanOrders(symbol, "sl")
+}
+
+// CancelTakeProfitOrders cancels take profit orders
+func (t *MEXCTrader) CancelTakeProfitOrders(symbol string) error {
+	return t.cancelPlanOrders(symbol, "tp")
+}
+
+// cancelPlanOrders cancels pending plan orders ("sl", "tp", or "" for both)
+func (t *MEXCTrader) cancelPlanOrders(symbol string, orderType string) error {
+	mexcSymbol := t.convertSymbol(symbol)
+
+	if orderType == "" {
+		_, err := t.doRequest("POST", mexcPlanCancelAllPath, nil, map[string]interface{}{"symbol": mexcSymbol})
+		return err
+	}
+
+	query := url.Values{
+		"symbol":    {mexcSymbol},
+		"states":    {"1"}, // Untriggered
+		"page_num":  {"1"},
+		"page_size": {"100"},
+	}
+	data, err := t.doRequest("GET", mexcPlanOrderListPath, query, nil)
+	if err != nil {
+		return err
+	}
+
+	var orders []struct {
+		ID          json.Number `json:"id"`
+		Symbol      string      `json:"symbol"`
+		Side        int         `json:"side"`
+		TriggerType int         `json:"triggerType"`
+	}
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return err
+	}
+
+	var toCancel []map[string]interface{}
+	for _, order := range orders {
+		// Close long: LTE=stop loss, GTE=take profit; close short is reversed
+		isStopLoss := (order.Side == mexcSideCloseLong && order.TriggerType == mexcTriggerLTE) ||
+			(order.Side == mexcSideCloseShort && order.TriggerType == mexcTriggerGTE)
+		if (orderType == "sl") != isStopLoss {
+			continue
+		}
+		toCancel = append(toCancel, map[string]interface{}{
+			"symbol":  order.Symbol,
+			"orderId": order.ID.String(),
+		})
+	}
+
+	if len(toCancel) == 0 {
+		return nil
+	}
+	if _, err := t.doRequest("POST", mexcPlanCancelPath, nil, toCancel); err != nil {
+		return err
+	}
+
+	logger.Infof("  ✓ Canceled %d plan orders for %s", len(toCancel), symbol)
+	return nil
+}
+
+// CancelAllOrders cancels all pending orders
+func (t *MEXCTrader) CancelAllOrders(symbol string) error {
+	mexcSymbol := t.convertSymbol(symbol)
+
+	if _, err := t.doRequest("POST", mexcCancelAllPath, nil, map[string]interface{}{"symbol": mexcSymbol}); err != nil {
+		logger.Infof("  ⚠️ Failed to cancel MEXC orders for %s: %v", symbol, err)
+	}
+
+	// Also cancel plan orders
+	return t.cancelPlanOrders(symbol, "")
+}
+
+// CancelStopOrders cancels stop loss and take profit orders
+func (t *MEXCTrader) CancelStopOrders(symbol string) error {
+	return t.cancelPlanOrders(symbol, "")
+}
+
+// FormatQuantity formats quantity (converts base asset quantity to contract volume)
+func (t *MEXCTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	contract, err := t.getContract(symbol)
+	if err != nil {
+		return fmt.Sprintf("%.3f", quantity), nil
+	}
+	vol := t.toVolume(quantity, contract)
+	return strconv.FormatFloat(vol, 'f', contract.VolScale, 64), nil
+}
+
+// GetOrderStatus gets order status
+func (t *MEXCTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	data, err := t.doRequest("GET", mexcOrderDetailPath+url.PathEscape(orderID), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order status: %w", err)
+	}
+
+	var order struct {
+		OrderID      json.Number `json:"orderId"`
+		State        int         `json:"state"` // 1=uninformed, 2=uncompleted, 3=completed, 4=cancelled, 5=invalid
+		Side         int         `json:"side"`
+		OrderType    int         `json:"orderType"`
+		DealAvgPrice float64     `json:"dealAvgPrice"`
+		DealVol      float64     `json:"dealVol"` // Contracts
+		TakerFee     float64     `json:"takerFee"`
+		MakerFee     float64     `json:"makerFee"`
+		CreateTime   int64       `json:"createTime"`
+		UpdateTime   int64       `json:"updateTime"`
+	}
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, err
+	}
+
+	// Convert contracts to base asset quantity
+	executedQty := order.DealVol
+	if contract, err := t.getContract(symbol); err == nil && contract.ContractSize > 0 {
+		executedQty = order.DealVol * contract.ContractSize
+	}
+
+	status := "NEW"
+	switch order.State {
+	case 2:
+		if order.DealVol > 0 {
+			status = "PARTIALLY_FILLED"
+		}
+	case 3:
+		status = "FILLED"
+	case 4:
+		status = "CANCELED"
+	case 5:
+		status = "REJECTED"
+	}
+
+	side := "BUY"
+	if order.Side == mexcSideOpenShort || order.Side == mexcSideCloseLong {
+		side = "SELL"
+	}
+
+	return map[string]interface{}{
+		"orderId":     order.OrderID.String(),
+		"symbol":      symbol,
+		"status":      status,
+		"avgPrice":    order.DealAvgPrice,
+		"executedQty": executedQty,
+		"side":        side,
+		"type":        "MARKET",
+		"time":        order.CreateTime,
+		"updateTime":  order.UpdateTime,
+		"commission":  order.TakerFee + order.MakerFee,
+	}, nil
+}
+
+// GetClosedPnL retrieves closed position PnL records from MEXC
+// MEXC API: /api/v1/private/position/list/history_positions
+func (t *MEXCTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	query := url.Values{
+		"page_num":  {"1"},
+		"page_size": {strconv.Itoa(limit)},
+	}
+	data, err := t.doRequest("GET", mexcHistoryPositionPath, query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions history: %w", err)
+	}
+
+	var positions []struct {
+		PositionID    int64   `json:"positionId"`
+		Symbol        string  `json:"symbol"`
+		PositionType  int     `json:"positionType"` // 1=long, 2=short
+		HoldAvgPrice  float64 `json:"holdAvgPrice"`
+		CloseAvgPrice float64 `json:"closeAvgPrice"`
+		CloseVol      float64 `json:"closeVol"` // Contracts
+		Realised      float64 `json:"realised"` // Net of fees
+		Leverage      float64 `json:"leverage"`
+		State         int     `json:"state"` // 3=closed
+		CreateTime    int64   `json:"createTime"`
+		UpdateTime    int64   `json:"updateTime"`
+	}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	records := make([]ClosedPnLRecord, 0, len(positions))
+	for _, pos := range positions {
+		exitTime := time.UnixMilli(pos.UpdateTime)
+		if pos.State != 3 || exitTime.Before(startTime) {
+			continue
+		}
+
+		symbol := t.convertSymbolBack(pos.Symbol)
+		quantity := pos.CloseVol
+		if contract, err := t.getContract(symbol); err == nil && contract.ContractSize > 0 {
+			quantity = pos.CloseVol * contract.ContractSize
+		}
+
+		side := "long"
+		if pos.PositionType == 2 {
+			side = "short"
+		}
+
+		records = append(records, ClosedPnLRecord{
+			Symbol:      symbol,
+			Side:        side,
+			EntryPrice:  pos.HoldAvgPrice,
+			ExitPrice:   pos.CloseAvgPrice,
+			Quantity:    quantity,
+			RealizedPnL: pos.Realised,
+			Leverage:    int(pos.Leverage),
+			EntryTime:   time.UnixMilli(pos.CreateTime),
+			ExitTime:    exitTime,
+			CloseType:   "unknown",
+			ExchangeID:  strconv.FormatInt(pos.PositionID, 10),
+		})
+	}
+
+	return records, nil
+}
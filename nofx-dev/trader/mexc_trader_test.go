@@ -0,0 +1,141 @@
+package trader
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestMEXCSymbolConversion tests generic <-> MEXC symbol format
+func TestMEXCSymbolConversion(t *testing.T) {
+	tr := &MEXCTrader{}
+	tests := []struct {
+		symbol string
+		mexc   string
+	}{
+		{"BTCUSDT", "BTC_USDT"},
+		{"ETHUSDC", "ETH_USDC"},
+		{"1000PEPEUSDT", "1000PEPE_USDT"},
+	}
+	for _, tt := range tests {
+		if got := tr.convertSymbol(tt.symbol); got != tt.mexc {
+			t.Errorf("convertSymbol(%s): expected %s, got %s", tt.symbol, tt.mexc, got)
+		}
+		if got := tr.convertSymbolBack(tt.mexc); got != tt.symbol {
+			t.Errorf("convertSymbolBack(%s): expected %s, got %s", tt.mexc, tt.symbol, got)
+		}
+	}
+}
+
+// TestMEXCToVolume tests base quantity to contract volume conversion
+func TestMEXCToVolume(t *testing.T) {
+	tr := &MEXCTrader{}
+	tests := []struct {
+		name     string
+		quantity float64
+		contract MEXCContract
+		want     float64
+	}{
+		{"whole contracts round down", 0.0159, MEXCContract{ContractSize: 0.0001, VolScale: 0}, 159},
+		{"fractional volume", 12.345, MEXCContract{ContractSize: 1, VolScale: 1}, 12.3},
+		{"capped at max volume", 5, MEXCContract{ContractSize: 0.001, MaxVol: 1000}, 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tr.toVolume(tt.quantity, &tt.contract); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestParseMEXCOrderID tests both order ID response shapes
+func TestParseMEXCOrderID(t *testing.T) {
+	tests := map[string]string{
+		`102057569836905984`:               "102057569836905984",
+		`"102057569836905984"`:             "102057569836905984",
+		`{"orderId":"739113577038255616"}`: "739113577038255616",
+		`{"orderId":739113577038255616}`:   "739113577038255616",
+	}
+	for raw, want := range tests {
+		if got := parseMEXCOrderID(json.RawMessage(raw)); got != want {
+			t.Errorf("parseMEXCOrderID(%s): expected %s, got %s", raw, want, got)
+		}
+	}
+}
+
+// newTestMEXCTrader MEXC trader against a test server recording the change_leverage requests
+func newTestMEXCTrader(t *testing.T, leverageCode int) (*MEXCTrader, *[]map[string]interface{}) {
+	var changes []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case mexcLeveragePath:
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			changes = append(changes, body)
+			if leverageCode != 0 {
+				fmt.Fprintf(w, `{"success":false,"code":%d,"message":"position exists"}`, leverageCode)
+				return
+			}
+			w.Write([]byte(`{"success":true,"code":0}`))
+		case mexcGetLeveragePath:
+			w.Write([]byte(`{"success":true,"code":0,"data":[{"positionType":1,"leverage":5},{"positionType":2,"leverage":5}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return &MEXCTrader{
+		isCrossMargin: true,
+		leverages:     make(map[string]int),
+		marginModes:   make(map[string]bool),
+		baseURL:       server.URL,
+		httpClient:    server.Client(),
+	}, &changes
+}
+
+// TestMEXCSetMarginMode tests the margin mode is changed on the exchange and used for later orders
+func TestMEXCSetMarginMode(t *testing.T) {
+	tr, changes := newTestMEXCTrader(t, 0)
+	if err := tr.SetMarginMode("BTCUSDT", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*changes) != 2 {
+		t.Fatalf("expected both position sides changed, got %d requests", len(*changes))
+	}
+	for _, body := range *changes {
+		if body["openType"] != float64(1) || body["leverage"] != float64(5) || body["symbol"] != "BTC_USDT" {
+			t.Errorf("expected isolated margin at the current 5x leverage, got %v", body)
+		}
+	}
+	if got := tr.openType("BTCUSDT"); got != 1 {
+		t.Errorf("expected isolated openType for later orders, got %d", got)
+	}
+	if got := tr.openType("ETHUSDT"); got != 2 {
+		t.Errorf("expected other symbols to keep cross margin, got %d", got)
+	}
+
+	// Unchanged mode: no request
+	if err := tr.SetMarginMode("BTCUSDT", false); err != nil || len(*changes) != 2 {
+		t.Errorf("expected no request for an unchanged mode, got %d requests (err=%v)", len(*changes), err)
+	}
+}
+
+// TestMEXCSetLeverageError tests leverage failures are returned and not recorded
+func TestMEXCSetLeverageError(t *testing.T) {
+	tr, _ := newTestMEXCTrader(t, 2019)
+	if err := tr.SetLeverage("BTCUSDT", 10); err == nil {
+		t.Fatal("expected leverage error")
+	}
+	if got := tr.leverageFor("BTCUSDT"); got != 1 {
+		t.Errorf("expected failed leverage not recorded, got %dx", got)
+	}
+	if err := tr.SetMarginMode("BTCUSDT", false); err == nil {
+		t.Fatal("expected margin mode error")
+	}
+	if got := tr.openType("BTCUSDT"); got != 2 {
+		t.Errorf("expected rejected margin mode not recorded, got openType %d", got)
+	}
+}
@@ -0,0 +1,261 @@
+package trader
+
+import (
+	"encoding/json"
+	"fmt"
+	"nofx/locale"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// notificationFlushInterval how often held notifications are checked for a digest
+	notificationFlushInterval = 1 * time.Minute
+	// maxDigestItems notifications kept per digest, older ones are counted but dropped
+	maxDigestItems = 200
+)
+
+// WebhookActionDigest action of a digest payload summarizing held notifications
+const WebhookActionDigest = "digest"
+
+// criticalEvents event types that always break through quiet hours and digest batching
+var criticalEvents = map[string]bool{
+	EventExchangeCircuitOpen:  true,
+	EventDailyLossLimit:       true,
+	EventMarginRatioHigh:      true,
+	EventLiquidationProximity: true,
+	EventReconcileDiscrepancy: true,
+	EventExposureAlert:        true,
+	EventConfigDrift:          true,
+}
+
+// notificationPolicy parsed notification settings, quiet nil when quiet hours are disabled
+type notificationPolicy struct {
+	quiet  *quietHours
+	digest bool
+	format locale.Formatter // User's locale and time zone for notification texts and reports
+}
+
+// quietHours [start, end) in minutes after midnight, wrapping past midnight when end is earlier
+type quietHours struct {
+	start int
+	end   int
+	loc   *time.Location
+}
+
+// notificationState per-trader notification policy and the notifications held for the next digest
+type notificationState struct {
+	mu      sync.Mutex
+	policy  notificationPolicy
+	pending []WebhookPayload
+	dropped int
+}
+
+// ValidateNotificationSettings checks the quiet hours and time zone of notification settings
+func ValidateNotificationSettings(cfg store.NotificationSettings) error {
+	_, err := parseNotificationSettings(cfg)
+	return err
+}
+
+// parseNotificationSettings parses notification settings
+func parseNotificationSettings(cfg store.NotificationSettings) (notificationPolicy, error) {
+	format, err := locale.New(cfg.Locale, cfg.Timezone)
+	if err != nil {
+		return notificationPolicy{}, err
+	}
+	policy := notificationPolicy{digest: cfg.DigestEnabled, format: format}
+	if !cfg.QuietHoursEnabled {
+		return policy, nil
+	}
+
+	q := &quietHours{loc: format.Location()}
+	if q.start, err = parseClock(cfg.QuietStart); err != nil {
+		return notificationPolicy{}, err
+	}
+	if q.end, err = parseClock(cfg.QuietEnd); err != nil {
+		return notificationPolicy{}, err
+	}
+	if q.start == q.end {
+		return notificationPolicy{}, fmt.Errorf("quiet hours end must differ from start")
+	}
+	policy.quiet = q
+	return policy, nil
+}
+
+// active reports whether now falls in the quiet hours
+func (q *quietHours) active(now time.Time) bool {
+	t := now.In(q.loc)
+	minute := t.Hour()*60 + t.Minute()
+	if q.start < q.end {
+		return minute >= q.start && minute < q.end
+	}
+	return minute >= q.start || minute < q.end
+}
+
+// quietAt reports whether notifications are held for quiet hours at now
+func (p notificationPolicy) quietAt(now time.Time) bool {
+	return p.quiet != nil && p.quiet.active(now)
+}
+
+// SetNotificationSettings updates the notification policy, held notifications are sent by the next flush
+func (at *AutoTrader) SetNotificationSettings(cfg store.NotificationSettings) error {
+	policy, err := parseNotificationSettings(cfg)
+	if err != nil {
+		return err
+	}
+	at.notifications.mu.Lock()
+	at.notifications.policy = policy
+	at.notifications.mu.Unlock()
+	return nil
+}
+
+// holdNotification queues a non-critical notification for the digest while quiet hours or batching apply
+// Returns false when it should be sent right away
+func (at *AutoTrader) holdNotification(payload WebhookPayload, now time.Time) bool {
+	at.notifications.mu.Lock()
+	defer at.notifications.mu.Unlock()
+	policy := at.notifications.policy
+	if !policy.digest && !policy.quietAt(now) {
+		return false
+	}
+	if len(at.notifications.pending) >= maxDigestItems {
+		at.notifications.pending = at.notifications.pending[1:]
+		at.notifications.dropped++
+	}
+	at.notifications.pending = append(at.notifications.pending, payload)
+	return true
+}
+
+// takeDigest returns the held notifications once they are due (nil otherwise) and clears them
+// Held notifications are due after quiet hours end, batched ones at the top of the hour after the first was held
+func (at *AutoTrader) takeDigest(now time.Time) ([]WebhookPayload, int) {
+	at.notifications.mu.Lock()
+	defer at.notifications.mu.Unlock()
+	pending := at.notifications.pending
+	if len(pending) == 0 || at.notifications.policy.quietAt(now) {
+		return nil, 0
+	}
+	if at.notifications.policy.digest && !now.Truncate(time.Hour).After(pending[0].Time) {
+		return nil, 0
+	}
+	dropped := at.notifications.dropped
+	at.notifications.pending = nil
+	at.notifications.dropped = 0
+	return pending, dropped
+}
+
+// notificationFormat the owner's locale and time zone for notification texts and reports
+func (at *AutoTrader) notificationFormat() locale.Formatter {
+	at.notifications.mu.Lock()
+	defer at.notifications.mu.Unlock()
+	return at.notifications.policy.format
+}
+
+// describeNotification renders a payload as one line of text in the user's locale, without its time
+func describeNotification(f locale.Formatter, p WebhookPayload) string {
+	text := p.Action
+	if p.Symbol != "" {
+		text += " " + p.Symbol
+	}
+	if p.Quantity > 0 && p.Price > 0 {
+		text += fmt.Sprintf(" %s @ %s (%s)", f.Decimal(p.Quantity, 8), f.Price(p.Price), f.Amount(p.SizeUSD, market.DefaultQuoteAsset))
+	}
+	if p.Leverage > 0 {
+		text += fmt.Sprintf(", %dx", p.Leverage)
+	}
+	if p.StopLoss > 0 {
+		text += ", SL " + f.Price(p.StopLoss)
+	}
+	if p.TakeProfit > 0 {
+		text += ", TP " + f.Price(p.TakeProfit)
+	}
+	if p.Reasoning != "" {
+		text += ": " + p.Reasoning
+	}
+	return text
+}
+
+// notificationMessage renders a payload as text with its time in the user's time zone (Message)
+func notificationMessage(f locale.Formatter, p WebhookPayload) string {
+	return describeNotification(f, p) + " · " + f.DateTime(p.Time)
+}
+
+// buildDigest summarizes held notifications into one payload, one line per notification
+// Times are shown in the owner's time zone
+func (at *AutoTrader) buildDigest(items []WebhookPayload, dropped int, now time.Time) WebhookPayload {
+	f := at.notificationFormat()
+	lines := make([]string, 0, len(items)+1)
+	for _, item := range items {
+		line := f.Clock(item.Time) + " " + item.Action
+		if item.Symbol != "" {
+			line += " " + item.Symbol
+		}
+		if item.Reasoning != "" {
+			line += ": " + item.Reasoning
+		}
+		lines = append(lines, line)
+	}
+	if dropped > 0 {
+		lines = append(lines, fmt.Sprintf("(%d older notifications omitted)", dropped))
+	}
+
+	details := make([]string, 0, len(items))
+	for _, item := range items {
+		details = append(details, f.Clock(item.Time)+" "+describeNotification(f, item))
+	}
+	total := len(items) + dropped
+	return WebhookPayload{
+		TraderID:   at.id,
+		TraderName: at.name,
+		Exchange:   at.exchange,
+		Action:     WebhookActionDigest,
+		Reasoning:  fmt.Sprintf("%d notifications\n%s", total, strings.Join(lines, "\n")),
+		Message:    fmt.Sprintf("%d notifications · %s\n%s", total, f.DateTime(now), strings.Join(details, "\n")),
+		Time:       now,
+		Digest:     items,
+	}
+}
+
+// flushNotificationDigest sends the held notifications as one digest once they are due
+func (at *AutoTrader) flushNotificationDigest() {
+	now := time.Now()
+	items, dropped := at.takeDigest(now)
+	if len(items) == 0 {
+		return
+	}
+	at.sendWebhook(at.buildDigest(items, dropped, now), true)
+}
+
+// sendWebhook posts a payload to the trader's webhook in the background
+// Non-critical payloads are held for the digest during quiet hours or when batching is enabled
+func (at *AutoTrader) sendWebhook(payload WebhookPayload, critical bool) {
+	at.webhook.mu.RLock()
+	url, secret := at.webhook.url, at.webhook.secret
+	at.webhook.mu.RUnlock()
+	if url == "" {
+		return
+	}
+	if !critical && at.holdNotification(payload, time.Now()) {
+		return
+	}
+	if payload.Message == "" {
+		payload.Message = notificationMessage(at.notificationFormat(), payload)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		logger.Infof("⚠️ Failed to encode webhook payload: %v", err)
+		return
+	}
+	at.deliveries.Add(1)
+	go func() {
+		defer at.deliveries.Done()
+		if err := at.postWebhook(url, secret, body); err != nil {
+			logger.Infof("⚠️ Webhook delivery failed (%s %s): %v", payload.Symbol, payload.Action, err)
+		}
+	}()
+}
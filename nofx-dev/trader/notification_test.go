@@ -0,0 +1,106 @@
+package trader
+
+import (
+	"nofx/store"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestQuietHoursWrap tests quiet hours spanning midnight in the configured time zone
+func TestQuietHoursWrap(t *testing.T) {
+	policy, err := parseNotificationSettings(store.NotificationSettings{
+		QuietHoursEnabled: true, QuietStart: "22:00", QuietEnd: "07:00", Timezone: "Asia/Shanghai",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cases := map[string]bool{
+		"2025-01-01T13:59:00Z": false, // 21:59 local
+		"2025-01-01T14:00:00Z": true,  // 22:00 local
+		"2025-01-01T22:30:00Z": true,  // 06:30 local
+		"2025-01-01T23:00:00Z": false, // 07:00 local
+	}
+	for ts, want := range cases {
+		now, _ := time.Parse(time.RFC3339, ts)
+		if got := policy.quietAt(now); got != want {
+			t.Errorf("%s: expected quiet=%v, got %v", ts, want, got)
+		}
+	}
+
+	if _, err := parseNotificationSettings(store.NotificationSettings{QuietHoursEnabled: true, QuietStart: "22:00", QuietEnd: "22:00"}); err == nil {
+		t.Error("expected error for equal start and end")
+	}
+}
+
+// TestDigestBatching tests held notifications are released at the top of the next hour
+func TestDigestBatching(t *testing.T) {
+	at := &AutoTrader{notifications: notificationState{policy: notificationPolicy{digest: true}}}
+	t0 := time.Date(2025, 1, 1, 10, 15, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		payload := WebhookPayload{Action: "open_long", Symbol: "BTCUSDT", Time: t0.Add(time.Duration(i) * 10 * time.Minute)}
+		if !at.holdNotification(payload, payload.Time) {
+			t.Fatal("expected notification to be held for the digest")
+		}
+	}
+
+	if items, _ := at.takeDigest(t0.Add(30 * time.Minute)); items != nil {
+		t.Errorf("expected no digest before the top of the hour, got %d items", len(items))
+	}
+	items, dropped := at.takeDigest(t0.Add(45 * time.Minute))
+	if len(items) != 2 || dropped != 0 {
+		t.Fatalf("expected 2 items at 11:00, got %d (dropped %d)", len(items), dropped)
+	}
+	if digest := at.buildDigest(items, dropped, t0); digest.Action != WebhookActionDigest || len(digest.Digest) != 2 {
+		t.Errorf("unexpected digest: %+v", digest)
+	}
+	if items, _ := at.takeDigest(t0.Add(2 * time.Hour)); items != nil {
+		t.Error("expected the digest to be cleared after it was taken")
+	}
+}
+
+// TestQuietHoursHold tests notifications are held until quiet hours end
+func TestQuietHoursHold(t *testing.T) {
+	policy, _ := parseNotificationSettings(store.NotificationSettings{QuietHoursEnabled: true, QuietStart: "00:00", QuietEnd: "06:00"})
+	at := &AutoTrader{notifications: notificationState{policy: policy}}
+	night := time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC)
+
+	if at.holdNotification(WebhookPayload{Action: "close_long", Time: night.Add(6 * time.Hour)}, night.Add(6*time.Hour)) {
+		t.Error("expected immediate delivery outside quiet hours without batching")
+	}
+	if !at.holdNotification(WebhookPayload{Action: "close_long", Time: night}, night) {
+		t.Fatal("expected notification to be held during quiet hours")
+	}
+	if items, _ := at.takeDigest(night.Add(time.Hour)); items != nil {
+		t.Error("expected no digest while quiet hours are active")
+	}
+	if items, _ := at.takeDigest(night.Add(4 * time.Hour)); len(items) != 1 {
+		t.Errorf("expected 1 held item after quiet hours, got %d", len(items))
+	}
+}
+
+// TestLocalizedNotificationMessage tests notification texts use the owner's locale and time zone
+func TestLocalizedNotificationMessage(t *testing.T) {
+	policy, err := parseNotificationSettings(store.NotificationSettings{Locale: "de", Timezone: "Europe/Berlin"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	at := &AutoTrader{notifications: notificationState{policy: policy}}
+	payload := WebhookPayload{
+		Action: "open_long", Symbol: "BTCUSDT", Quantity: 0.015, Price: 65432.1, SizeUSD: 981.48, Leverage: 5,
+		Time: time.Date(2025, 1, 1, 13, 5, 0, 0, time.UTC),
+	}
+
+	want := "open_long BTCUSDT 0,015 @ 65.432,10 (981,48 USDT), 5x · 01.01.2025 14:05 CET"
+	if got := notificationMessage(at.notificationFormat(), payload); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	digest := at.buildDigest([]WebhookPayload{payload}, 0, payload.Time)
+	if !strings.HasPrefix(digest.Reasoning, "1 notifications\n14:05 open_long") {
+		t.Errorf("expected local digest times, got %q", digest.Reasoning)
+	}
+
+	if _, err := parseNotificationSettings(store.NotificationSettings{Locale: "xx"}); err == nil {
+		t.Error("expected error for unsupported locale")
+	}
+}
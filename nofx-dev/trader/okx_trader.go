@@ -11,7 +11,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"nofx/endpoint"
 	"nofx/logger"
+	"nofx/market"
 	"strconv"
 	"strings"
 	"sync"
@@ -35,6 +37,11 @@ const (
 	okxPositionModePath  = "/api/v5/account/set-position-mode"
 )
 
+func init() {
+	// Perpetual swaps base tier: 0.02% maker, 0.05% taker
+	logger.RegisterFeeSchedule("okx", logger.FeeSchedule{Maker: 0.0002, Taker: 0.0005})
+}
+
 // OKXTrader OKX futures trader
 type OKXTrader struct {
 	apiKey     string
@@ -53,10 +60,13 @@ type OKXTrader struct {
 	balanceCacheMutex sync.RWMutex
 
 	// Positions cache
-	cachedPositions     []map[string]interface{}
+	cachedPositions     []Position
 	positionsCacheTime  time.Time
 	positionsCacheMutex sync.RWMutex
 
+	// Caller-chosen client order keys for market orders (see ClientOrderIDTrader)
+	orderKeys clientOrderKeys
+
 	// Instrument info cache
 	instrumentsCache      map[string]*OKXInstrument
 	instrumentsCacheTime  time.Time
@@ -103,10 +113,11 @@ func genOkxClOrdID() string {
 func NewOKXTrader(apiKey, secretKey, passphrase string) *OKXTrader {
 	// Use default transport which respects system proxy settings
 	// OKX requires proxy in China due to DNS pollution
-	httpClient := &http.Client{
+	// Requests go to the fastest healthy OKX domain when endpoint selection is enabled
+	httpClient := endpoint.WrapClient(&http.Client{
 		Timeout:   30 * time.Second,
 		Transport: http.DefaultTransport,
-	}
+	})
 
 	trader := &OKXTrader{
 		apiKey:           apiKey,
@@ -207,21 +218,15 @@ func (t *OKXTrader) doRequest(method, path string, body interface{}) ([]byte, er
 }
 
 // convertSymbol converts generic symbol to OKX format
-// e.g. BTCUSDT -> BTC-USDT-SWAP
+// e.g. BTCUSDT -> BTC-USDT-SWAP, BTCUSDC -> BTC-USDC-SWAP
 func (t *OKXTrader) convertSymbol(symbol string) string {
-	// Remove USDT suffix and build OKX format
-	base := strings.TrimSuffix(symbol, "USDT")
-	return fmt.Sprintf("%s-USDT-SWAP", base)
+	return market.VenueSymbol(market.VenueOKX, symbol)
 }
 
 // convertSymbolBack converts OKX format back to generic symbol
 // e.g. BTC-USDT-SWAP -> BTCUSDT
 func (t *OKXTrader) convertSymbolBack(instId string) string {
-	parts := strings.Split(instId, "-")
-	if len(parts) >= 2 {
-		return parts[0] + parts[1]
-	}
-	return instId
+	return market.CanonicalSymbol(market.VenueOKX, instId)
 }
 
 // GetBalance gets account balance
@@ -244,6 +249,7 @@ func (t *OKXTrader) GetBalance() (map[string]interface{}, error) {
 	var balances []struct {
 		TotalEq string `json:"totalEq"`
 		AdjEq   string `json:"adjEq"`
+		MMR     string `json:"mmr"` // Maintenance margin requirement (USD), multi-currency / portfolio margin only
 		IsoEq   string `json:"isoEq"`
 		OrdFroz string `json:"ordFroz"`
 		Details []struct {
@@ -282,6 +288,11 @@ func (t *OKXTrader) GetBalance() (map[string]interface{}, error) {
 		"availableBalance":      usdtAvail,
 		"totalUnrealizedProfit": usdtUPL,
 	}
+	adjEq, _ := strconv.ParseFloat(balance.AdjEq, 64)
+	mmr, _ := strconv.ParseFloat(balance.MMR, 64)
+	if adjEq > 0 && mmr > 0 {
+		result["marginRatio"] = mmr / adjEq * 100
+	}
 
 	logger.Infof("✓ OKX balance: Total equity=%.2f, Available=%.2f, Unrealized PnL=%.2f", totalEq, usdtAvail, usdtUPL)
 
@@ -295,7 +306,7 @@ func (t *OKXTrader) GetBalance() (map[string]interface{}, error) {
 }
 
 // GetPositions gets all positions
-func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
+func (t *OKXTrader) GetPositions() ([]Position, error) {
 	// Check cache
 	t.positionsCacheMutex.RLock()
 	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
@@ -321,6 +332,7 @@ func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
 		Lever   string `json:"lever"`
 		LiqPx   string `json:"liqPx"`
 		Margin  string `json:"margin"`
+		MgnMode string `json:"mgnMode"` // cross / isolated
 		CTime   string `json:"cTime"` // Position created time (ms)
 		UTime   string `json:"uTime"` // Position last update time (ms)
 	}
@@ -329,7 +341,7 @@ func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
 		return nil, fmt.Errorf("failed to parse position data: %w", err)
 	}
 
-	var result []map[string]interface{}
+	var result []Position
 	for _, pos := range positions {
 		contractCount, _ := strconv.ParseFloat(pos.Pos, 64)
 		if contractCount == 0 {
@@ -341,6 +353,7 @@ func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
 		upl, _ := strconv.ParseFloat(pos.Upl, 64)
 		leverage, _ := strconv.ParseFloat(pos.Lever, 64)
 		liqPrice, _ := strconv.ParseFloat(pos.LiqPx, 64)
+		margin, _ := strconv.ParseFloat(pos.Margin, 64)
 
 		// Convert symbol format
 		symbol := t.convertSymbolBack(pos.InstId)
@@ -368,19 +381,20 @@ func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
 		cTime, _ := strconv.ParseInt(pos.CTime, 10, 64)
 		uTime, _ := strconv.ParseInt(pos.UTime, 10, 64)
 
-		posMap := map[string]interface{}{
-			"symbol":           symbol,
-			"positionAmt":      posAmt,
-			"entryPrice":       entryPrice,
-			"markPrice":        markPrice,
-			"unRealizedProfit": upl,
-			"leverage":         leverage,
-			"liquidationPrice": liqPrice,
-			"side":             side,
-			"createdTime":      cTime, // Position open time (ms)
-			"updatedTime":      uTime, // Position last update time (ms)
-		}
-		result = append(result, posMap)
+		result = append(result, Position{
+			Symbol:           symbol,
+			Side:             side,
+			Quantity:         posAmt,
+			EntryPrice:       entryPrice,
+			MarkPrice:        markPrice,
+			UnrealizedPnL:    upl,
+			Leverage:         leverage,
+			LiquidationPrice: liqPrice,
+			MarginUsed:       margin,
+			MarginMode:       pos.MgnMode,
+			CreatedTime:      cTime,
+			UpdatedTime:      uTime,
+		})
 	}
 
 	// Update cache
@@ -557,7 +571,7 @@ func (t *OKXTrader) OpenLong(symbol string, quantity float64, leverage int) (map
 		"posSide": "long",
 		"ordType": "market",
 		"sz":      szStr,
-		"clOrdId": genOkxClOrdID(),
+		"clOrdId": t.marketClOrdID(symbol),
 		"tag":     okxTag,
 	}
 
@@ -634,7 +648,7 @@ func (t *OKXTrader) OpenShort(symbol string, quantity float64, leverage int) (ma
 		"posSide": "short",
 		"ordType": "market",
 		"sz":      szStr,
-		"clOrdId": genOkxClOrdID(),
+		"clOrdId": t.marketClOrdID(symbol),
 		"tag":     okxTag,
 	}
 
@@ -689,8 +703,8 @@ func (t *OKXTrader) CloseLong(symbol string, quantity float64) (map[string]inter
 			return nil, err
 		}
 		for _, pos := range positions {
-			if pos["symbol"] == symbol && pos["side"] == "long" {
-				quantity = pos["positionAmt"].(float64) // This is in base asset (BTC)
+			if pos.Symbol == symbol && pos.Side == "long" {
+				quantity = pos.Quantity // This is in base asset (BTC)
 				break
 			}
 		}
@@ -708,14 +722,15 @@ func (t *OKXTrader) CloseLong(symbol string, quantity float64) (map[string]inter
 		symbol, quantity, inst.CtVal, contracts, szStr)
 
 	body := map[string]interface{}{
-		"instId":  instId,
-		"tdMode":  "cross",
-		"side":    "sell",
-		"posSide": "long",
-		"ordType": "market",
-		"sz":      szStr,
-		"clOrdId": genOkxClOrdID(),
-		"tag":     okxTag,
+		"instId":     instId,
+		"tdMode":     "cross",
+		"side":       "sell",
+		"posSide":    "long",
+		"ordType":    "market",
+		"sz":         szStr,
+		"reduceOnly": true, // posSide alone only reduces in long_short_mode, this also holds in net mode
+		"clOrdId":    t.marketClOrdID(symbol),
+		"tag":        okxTag,
 	}
 
 	data, err := t.doRequest("POST", okxOrderPath, body)
@@ -771,10 +786,10 @@ func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]inte
 		}
 		logger.Infof("🔍 OKX CloseShort searching positions: symbol=%s, current position count=%d", symbol, len(positions))
 		for _, pos := range positions {
-			logger.Infof("🔍 OKX position: symbol=%v, side=%v, positionAmt=%v",
-				pos["symbol"], pos["side"], pos["positionAmt"])
-			if pos["symbol"] == symbol && pos["side"] == "short" {
-				quantity = pos["positionAmt"].(float64) // This is in base asset (BTC)
+			logger.Infof("🔍 OKX position: symbol=%s, side=%s, positionAmt=%v",
+				pos.Symbol, pos.Side, pos.Quantity)
+			if pos.Symbol == symbol && pos.Side == "short" {
+				quantity = pos.Quantity // This is in base asset (BTC)
 				logger.Infof("🔍 OKX found short position: quantity=%f (base asset)", quantity)
 				break
 			}
@@ -798,14 +813,15 @@ func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]inte
 		symbol, quantity, inst.CtVal, contracts, szStr)
 
 	body := map[string]interface{}{
-		"instId":  instId,
-		"tdMode":  "cross",
-		"side":    "buy",
-		"posSide": "short",
-		"ordType": "market",
-		"sz":      szStr,
-		"clOrdId": genOkxClOrdID(),
-		"tag":     okxTag,
+		"instId":     instId,
+		"tdMode":     "cross",
+		"side":       "buy",
+		"posSide":    "short",
+		"ordType":    "market",
+		"sz":         szStr,
+		"reduceOnly": true, // posSide alone only reduces in long_short_mode, this also holds in net mode
+		"clOrdId":    t.marketClOrdID(symbol),
+		"tag":        okxTag,
 	}
 
 	logger.Infof("🔻 OKX close short request body: %+v", body)
@@ -905,6 +921,7 @@ func (t *OKXTrader) SetStopLoss(symbol string, positionSide string, quantity, st
 		"posSide":     posSide,
 		"ordType":     "conditional",
 		"sz":          szStr,
+		"reduceOnly":  true,
 		"slTriggerPx": fmt.Sprintf("%.8f", stopPrice),
 		"slOrdPx":     "-1", // Market price
 		"tag":         okxTag,
@@ -948,6 +965,7 @@ func (t *OKXTrader) SetTakeProfit(symbol string, positionSide string, quantity,
 		"posSide":     posSide,
 		"ordType":     "conditional",
 		"sz":          szStr,
+		"reduceOnly":  true,
 		"tpTriggerPx": fmt.Sprintf("%.8f", takeProfitPrice),
 		"tpOrdPx":     "-1", // Market price
 		"tag":         okxTag,
@@ -976,20 +994,26 @@ func (t *OKXTrader) CancelTakeProfitOrders(symbol string) error {
 func (t *OKXTrader) cancelAlgoOrders(symbol string, orderType string) error {
 	instId := t.convertSymbol(symbol)
 
-	// Get pending algo orders
-	path := fmt.Sprintf("%s?instType=SWAP&instId=%s&ordType=conditional", okxAlgoPendingPath, instId)
-	data, err := t.doRequest("GET", path, nil)
-	if err != nil {
-		return err
-	}
-
+	// Get pending algo orders (single-leg conditional and OCO brackets, which carry both legs)
 	var orders []struct {
 		AlgoId string `json:"algoId"`
 		InstId string `json:"instId"`
 	}
+	for _, ordType := range []string{"conditional", "oco"} {
+		path := fmt.Sprintf("%s?instType=SWAP&instId=%s&ordType=%s", okxAlgoPendingPath, instId, ordType)
+		data, err := t.doRequest("GET", path, nil)
+		if err != nil {
+			return err
+		}
 
-	if err := json.Unmarshal(data, &orders); err != nil {
-		return err
+		var pending []struct {
+			AlgoId string `json:"algoId"`
+			InstId string `json:"instId"`
+		}
+		if err := json.Unmarshal(data, &pending); err != nil {
+			return err
+		}
+		orders = append(orders, pending...)
 	}
 
 	canceledCount := 0
@@ -1096,8 +1120,58 @@ func (t *OKXTrader) formatSize(sz float64, inst *OKXInstrument) string {
 
 // GetOrderStatus gets order status
 func (t *OKXTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	return t.queryOrder(symbol, "ordId", orderID)
+}
+
+// marketClOrdID returns the clOrdId of the next market order on symbol
+func (t *OKXTrader) marketClOrdID(symbol string) string {
+	if key := t.orderKeys.get(symbol); key != "" {
+		return okxClOrdIDFromKey(key)
+	}
+	return genOkxClOrdID()
+}
+
+// okxClOrdIDFromKey builds a tagged clOrdId from a client order key (max 32 alphanumeric characters)
+func okxClOrdIDFromKey(key string) string {
+	id := okxTag + key
+	if len(id) > 32 {
+		id = id[:32]
+	}
+	return id
+}
+
+// SetClientOrderKey makes market orders on symbol use a clOrdId derived from key ("" restores generated IDs)
+func (t *OKXTrader) SetClientOrderKey(symbol, key string) {
+	t.orderKeys.set(symbol, key)
+}
+
+// FindOrderByClientKey looks up the order placed with key's clOrdId
+func (t *OKXTrader) FindOrderByClientKey(symbol, key string) (map[string]interface{}, bool, error) {
+	order, err := t.queryOrder(symbol, "clOrdId", okxClOrdIDFromKey(key))
+	if err != nil {
+		// 51603: order does not exist
+		if strings.Contains(err.Error(), "order not found") || strings.Contains(err.Error(), "51603") {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return order, true, nil
+}
+
+// invalidateCaches clears balance/position caches (forces the next query to hit the API)
+func (t *OKXTrader) invalidateCaches() {
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = nil
+	t.balanceCacheMutex.Unlock()
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = nil
+	t.positionsCacheMutex.Unlock()
+}
+
+// queryOrder gets an order by ordId or clOrdId (idParam)
+func (t *OKXTrader) queryOrder(symbol, idParam, id string) (map[string]interface{}, error) {
 	instId := t.convertSymbol(symbol)
-	path := fmt.Sprintf("/api/v5/trade/order?instId=%s&ordId=%s", instId, orderID)
+	path := fmt.Sprintf("/api/v5/trade/order?instId=%s&%s=%s", instId, idParam, id)
 
 	data, err := t.doRequest("GET", path, nil)
 	if err != nil {
@@ -1137,7 +1211,7 @@ func (t *OKXTrader) GetOrderStatus(symbol string, orderID string) (map[string]in
 	inst, err := t.getInstrument(symbol)
 	if err == nil && inst.CtVal > 0 {
 		executedQty = fillSz * inst.CtVal
-		logger.Debugf("  📊 OKX order %s: fillSz(contracts)=%.4f, ctVal=%.6f, executedQty=%.6f", orderID, fillSz, inst.CtVal, executedQty)
+		logger.Debugf("  📊 OKX order %s: fillSz(contracts)=%.4f, ctVal=%.6f, executedQty=%.6f", order.OrdId, fillSz, inst.CtVal, executedQty)
 	}
 
 	// Status mapping
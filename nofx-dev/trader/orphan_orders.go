@@ -0,0 +1,146 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"nofx/logger"
+	"time"
+)
+
+// orphanCleanupInterval how often exchange accounts are swept for stop / take profit orders without a position
+const orphanCleanupInterval = time.Minute
+
+// GetOpenConditionalOrders lists open conditional (stop) orders of all USDT linear symbols
+// Only the first page (50 orders) is returned, more than that is left for the next sweep
+func (t *BybitTrader) GetOpenConditionalOrders() ([]OpenOrder, error) {
+	params := map[string]interface{}{
+		"category":    "linear",
+		"settleCoin":  "USDT",
+		"orderFilter": "StopOrder",
+		"limit":       50,
+	}
+	result, err := t.client.NewUtaBybitServiceWithParams(params).GetOpenOrders(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conditional orders: %w", err)
+	}
+	if result.RetCode != 0 {
+		return nil, fmt.Errorf("failed to get conditional orders: %s", result.RetMsg)
+	}
+	resultData, _ := result.Result.(map[string]interface{})
+	list, _ := resultData["list"].([]interface{})
+
+	orders := make([]OpenOrder, 0, len(list))
+	for _, item := range list {
+		order, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		orderID, _ := order["orderId"].(string)
+		symbol, _ := order["symbol"].(string)
+		stopOrderType, _ := order["stopOrderType"].(string)
+
+		kind := stopOrderType
+		switch stopOrderType {
+		case "StopLoss", "Stop":
+			kind = "stop_loss"
+		case "TakeProfit", "PartialTakeProfit":
+			kind = "take_profit"
+		}
+		positionSide := "BOTH" // positionIdx 0 = one-way mode
+		switch idx, _ := order["positionIdx"].(float64); idx {
+		case 1:
+			positionSide = "LONG"
+		case 2:
+			positionSide = "SHORT"
+		}
+		orders = append(orders, OpenOrder{OrderID: orderID, Symbol: symbol, Kind: kind, PositionSide: positionSide})
+	}
+	return orders, nil
+}
+
+// orphanedSymbols symbols with open conditional orders but no position on the account (order count per symbol)
+func orphanedSymbols(orders []OpenOrder, positions []Position) map[string]int {
+	held := make(map[string]bool, len(positions))
+	for _, pos := range positions {
+		if pos.Quantity > 0 {
+			held[pos.Symbol] = true
+		}
+	}
+	orphaned := make(map[string]int)
+	for _, o := range orders {
+		if !held[o.Symbol] {
+			orphaned[o.Symbol]++
+		}
+	}
+	return orphaned
+}
+
+// cleanupOrphanedOrders cancels stop / take profit orders left on symbols without a position (closed by
+// liquidation, manually or by the other leg), once per exchange account. Orders are listed before positions
+// and a symbol must be orphaned in two consecutive sweeps, so a just-opened position whose stop is already
+// placed is never mistaken for a closed one. Exchanges that can't list orders are cleaned up on close instead
+func (m *PositionSyncManager) cleanupOrphanedOrders() {
+	traders, err := m.store.Trader().ListAll()
+	if err != nil {
+		logger.Infof("⚠️  Orphaned order cleanup: failed to list traders: %v", err)
+		return
+	}
+
+	seen := make(map[string]bool)
+	current := make(map[string]bool)
+	for _, info := range traders {
+		config, err := m.getTraderConfig(info.ID)
+		if err != nil || config.Exchange == nil || seen[config.Exchange.ID] {
+			continue
+		}
+		seen[config.Exchange.ID] = true
+
+		trader, err := m.getOrCreateTrader(info.ID)
+		if err != nil {
+			continue
+		}
+		lister, ok := trader.(OpenOrderLister)
+		if !ok {
+			continue
+		}
+		orders, err := lister.GetOpenConditionalOrders()
+		if err != nil || len(orders) == 0 {
+			continue
+		}
+		positions, err := trader.GetPositions()
+		if err != nil {
+			continue
+		}
+
+		for symbol, n := range orphanedSymbols(orders, positions) {
+			key := config.Exchange.ID + "|" + symbol
+			current[key] = true
+			if !m.orphanedOrders[key] {
+				continue // First sighting, confirm on the next sweep
+			}
+			if err := trader.CancelStopOrders(symbol); err != nil {
+				logger.Infof("⚠️  Failed to cancel %d orphaned order(s) on %s (%s): %v", n, symbol, config.Exchange.ExchangeType, err)
+				continue
+			}
+			delete(current, key)
+			logger.Infof("🧹 Cancelled %d orphaned stop / take profit order(s) on %s (%s, no open position)",
+				n, symbol, config.Exchange.ExchangeType)
+		}
+	}
+	m.orphanedOrders = current
+}
+
+// cancelClosedSymbolOrders cancels the symbol's stop / take profit orders after an external close
+// when the account holds no other position on it (exchangePositions keyed SYMBOL_SIDE)
+func (m *PositionSyncManager) cancelClosedSymbolOrders(trader Trader, symbol string, exchangePositions map[string]Position) {
+	for _, side := range []string{"LONG", "SHORT"} {
+		if pos, ok := exchangePositions[symbol+"_"+side]; ok && pos.Quantity > 0 {
+			return
+		}
+	}
+	if err := trader.CancelStopOrders(symbol); err != nil {
+		logger.Infof("⚠️  Failed to cancel leftover orders of closed %s: %v", symbol, err)
+		return
+	}
+	logger.Infof("🧹 Cancelled leftover stop / take profit orders of closed %s", symbol)
+}
@@ -0,0 +1,23 @@
+package trader
+
+import "testing"
+
+// TestOrphanedSymbols tests that only symbols without any position on the account are orphaned
+func TestOrphanedSymbols(t *testing.T) {
+	orders := []OpenOrder{
+		{Symbol: "BTCUSDT", Kind: "stop_loss", PositionSide: "LONG"},
+		{Symbol: "ETHUSDT", Kind: "stop_loss", PositionSide: "SHORT"}, // Short closed, long still open
+		{Symbol: "DOGEUSDT", Kind: "stop_loss", PositionSide: "LONG"},
+		{Symbol: "DOGEUSDT", Kind: "take_profit", PositionSide: "LONG"},
+	}
+	positions := []Position{
+		{Symbol: "BTCUSDT", Side: "long", Quantity: 0.1},
+		{Symbol: "ETHUSDT", Side: "long", Quantity: 1},
+		{Symbol: "SOLUSDT", Side: "long", Quantity: 0}, // Zero quantity doesn't count as held
+	}
+
+	orphaned := orphanedSymbols(orders, positions)
+	if len(orphaned) != 1 || orphaned["DOGEUSDT"] != 2 {
+		t.Errorf("expected DOGEUSDT with 2 orphaned orders, got %v", orphaned)
+	}
+}
@@ -0,0 +1,81 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// outboundResolveTimeout bound on resolving a user-supplied host when it is saved
+const outboundResolveTimeout = 5 * time.Second
+
+// lookupIPAddr resolves user-supplied hosts (replaced in tests)
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// isPublicIP whether ip may be reached by user-configured outbound requests
+// Loopback, private, link-local, multicast and unspecified addresses would let a webhook probe the host's network
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+// ValidatePublicURL accepts absolute http(s) URLs whose host resolves to public addresses only
+func ValidatePublicURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("URL must be an absolute http(s) URL")
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !isPublicIP(ip) {
+			return fmt.Errorf("URL host %s is not a public address", host)
+		}
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), outboundResolveTimeout)
+	defer cancel()
+	addrs, err := lookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve URL host %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if !isPublicIP(addr.IP) {
+			return fmt.Errorf("URL host %s resolves to non-public address %s", host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// guardPublicDial rejects connections to non-public addresses, checked on the resolved address
+// so a host re-pointed after it was validated (DNS rebinding) is still refused
+func guardPublicDial(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("refusing to connect to non-public address %s", host)
+	}
+	return nil
+}
+
+// newOutboundClient HTTP client for user-configured URLs (webhooks, export sinks)
+// Only public addresses are dialed, no proxy is used and redirects are not followed
+func newOutboundClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: guardPublicDial}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestValidatePublicURL tests rejecting URLs of loopback, private and link-local hosts
+func TestValidatePublicURL(t *testing.T) {
+	hosts := map[string][]string{
+		"hooks.example.com": {"93.184.216.34"},
+		"internal.example":  {"93.184.216.34", "10.0.0.5"},
+		"rebind.example":    {"169.254.169.254"},
+	}
+	orig := lookupIPAddr
+	lookupIPAddr = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		ips, ok := hosts[host]
+		if !ok {
+			return nil, errors.New("no such host")
+		}
+		addrs := make([]net.IPAddr, 0, len(ips))
+		for _, ip := range ips {
+			addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+		}
+		return addrs, nil
+	}
+	defer func() { lookupIPAddr = orig }()
+
+	tests := map[string]bool{
+		"https://hooks.example.com/nofx":     true,
+		"http://93.184.216.34:8080/x":        true,
+		"https://internal.example/x":         false,
+		"http://rebind.example/latest":       false,
+		"http://127.0.0.1:9000":              false,
+		"http://[::1]/x":                     false,
+		"http://192.168.1.10/x":              false,
+		"http://169.254.169.254/latest/meta": false,
+		"http://0.0.0.0/x":                   false,
+		"https://unknown.example/x":          false,
+		"ftp://hooks.example.com":            false,
+		"https://":                           false,
+	}
+	for raw, ok := range tests {
+		if err := ValidatePublicURL(raw); (err == nil) != ok {
+			t.Errorf("ValidatePublicURL(%s): ok=%v, err=%v", raw, ok, err)
+		}
+	}
+}
+
+// TestOutboundClient tests that the webhook client refuses loopback at dial time and doesn't follow redirects
+func TestOutboundClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	at := &AutoTrader{}
+	if err := at.postWebhook(server.URL, "", []byte(`{}`)); err == nil {
+		t.Error("expected the default client to refuse a loopback receiver")
+	}
+
+	client := newOutboundClient(webhookTimeout)
+	if err := client.CheckRedirect(nil, nil); !errors.Is(err, http.ErrUseLastResponse) {
+		t.Errorf("expected redirects not to be followed, got %v", err)
+	}
+}
@@ -0,0 +1,313 @@
+package trader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"nofx/config"
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/store"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// outcomeExportInterval how often newly closed trades are exported
+	outcomeExportInterval = 5 * time.Minute
+	// outcomeExportBatch max trades exported per run, a backlog drains over the following runs
+	outcomeExportBatch = 100
+	// outcomeDecisionLookback how long before entry the opening decision may have been made
+	outcomeDecisionLookback = 30 * time.Minute
+)
+
+// Outcome labels
+const (
+	OutcomeWin       = "win"
+	OutcomeLoss      = "loss"
+	OutcomeBreakEven = "breakeven"
+)
+
+// OutcomeLabel how the trade opened by a decision ended. Absolute amounts are left out so the
+// export doesn't reveal account size, returns are relative to margin / entry price
+type OutcomeLabel struct {
+	Label          string  `json:"label"`          // win / loss / breakeven (net of fees)
+	ReturnPct      float64 `json:"return_pct"`     // Net P&L in % of margin
+	PriceMovePct   float64 `json:"price_move_pct"` // Exit vs entry in % of entry, positive when in the trade's favour
+	HoldingMinutes float64 `json:"holding_minutes"`
+	CloseReason    string  `json:"close_reason,omitempty"`
+	EntryPrice     float64 `json:"entry_price"`
+	ExitPrice      float64 `json:"exit_price"`
+}
+
+// OutcomeExample one (prompt, decision, labeled outcome) training example, one JSONL line
+type OutcomeExample struct {
+	PositionID   int64             `json:"position_id"`
+	Exchange     string            `json:"exchange"`
+	DecisionTime time.Time         `json:"decision_time"`
+	SystemPrompt string            `json:"system_prompt"`
+	UserPrompt   string            `json:"user_prompt"`
+	Reasoning    string            `json:"reasoning"` // Chain of thought
+	Decision     decision.Decision `json:"decision"`
+	Outcome      OutcomeLabel      `json:"outcome"`
+}
+
+// outcomeExport per-trader decision outcome export sink, URL empty when disabled
+type outcomeExport struct {
+	mu      sync.RWMutex
+	url     string
+	secret  string
+	running sync.Mutex // One export run at a time
+}
+
+// SetOutcomeExport updates the decision outcome export sink (empty URL disables it)
+func (at *AutoTrader) SetOutcomeExport(url, secret string) {
+	at.outcomes.mu.Lock()
+	at.outcomes.url = url
+	at.outcomes.secret = secret
+	at.outcomes.mu.Unlock()
+}
+
+// ValidateOutcomeSink accepts absolute http(s) URLs of public hosts, and file:// URLs of files inside OUTCOME_EXPORT_DIR
+func ValidateOutcomeSink(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid export sink: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		if err := ValidatePublicURL(raw); err != nil {
+			return fmt.Errorf("invalid export sink: %w", err)
+		}
+		return nil
+	case "file":
+		dir := config.Get().OutcomeExportDir
+		if dir == "" {
+			return fmt.Errorf("file export sinks are disabled (OUTCOME_EXPORT_DIR not set)")
+		}
+		rel, err := filepath.Rel(dir, filepath.Clean(u.Path))
+		if u.Host != "" || !filepath.IsAbs(u.Path) || err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("file export sink must be a file:///path URL inside %s", dir)
+		}
+		return nil
+	}
+	return fmt.Errorf("export sink must be an absolute http(s) URL or a file:/// URL")
+}
+
+var (
+	redactAddressRe = regexp.MustCompile(`0x[0-9a-fA-F]{40,}`)
+	redactEmailRe   = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// Account amounts in the prompts ("Equity 1234.56", "Balance 987.65", "Margin 120", "equity: 1234"),
+	// percentages ("Margin 12.5%") are kept
+	redactAmountRe = regexp.MustCompile(`(?i)\b(equity|balance|wallet|margin balance|margin)(:?\s+)[0-9][0-9,]*(?:\.[0-9]+)?(%?)`)
+	// Any quote currency amount ("Position Value 1500.00 USDT", "PnL Amount+12.34 USDT", "max 1500 USDT")
+	redactQuoteAmountRe = regexp.MustCompile(`[+-]?[0-9][0-9,]*(?:\.[0-9]+)?(\s*)(USDT|USDC|FDUSD|BUSD|USD)\b`)
+	// Absolute sizes in decision JSON quoted by prompts and reasoning ("position_size_usd": 5000)
+	redactSizeFieldRe = regexp.MustCompile(`("(?:position_size_usd|risk_usd)"\s*:\s*)[0-9][0-9.]*`)
+)
+
+// redactText removes wallet addresses, e-mail addresses, absolute account amounts and the given identifiers
+// (trader name / ID) from prompt and reasoning text
+func redactText(text string, identifiers ...string) string {
+	text = redactAddressRe.ReplaceAllString(text, "0x[redacted]")
+	text = redactEmailRe.ReplaceAllString(text, "[redacted]")
+	text = redactAmountRe.ReplaceAllStringFunc(text, func(m string) string {
+		if strings.HasSuffix(m, "%") {
+			return m
+		}
+		return redactAmountRe.ReplaceAllString(m, "$1$2[redacted]")
+	})
+	text = redactQuoteAmountRe.ReplaceAllString(text, "[redacted]$1$2")
+	text = redactSizeFieldRe.ReplaceAllString(text, `$1"[redacted]"`)
+	for _, id := range identifiers {
+		if len(id) >= 3 {
+			text = strings.ReplaceAll(text, id, "[trader]")
+		}
+	}
+	return text
+}
+
+// labelOutcome labels a closed position from its net P&L (realized minus fees)
+func labelOutcome(pos *store.TraderPosition) OutcomeLabel {
+	label := OutcomeLabel{CloseReason: pos.CloseReason, EntryPrice: pos.EntryPrice, ExitPrice: pos.ExitPrice}
+	net := pos.RealizedPnL - pos.Fee
+	switch {
+	case net > 0:
+		label.Label = OutcomeWin
+	case net < 0:
+		label.Label = OutcomeLoss
+	default:
+		label.Label = OutcomeBreakEven
+	}
+	if margin := positionMargin(pos); margin > 0 {
+		label.ReturnPct = net / margin * 100
+	}
+	if pos.EntryPrice > 0 {
+		label.PriceMovePct = (pos.ExitPrice - pos.EntryPrice) / pos.EntryPrice * 100
+		if strings.EqualFold(pos.Side, "short") {
+			label.PriceMovePct = -label.PriceMovePct
+		}
+	}
+	if pos.ExitTime != nil {
+		label.HoldingMinutes = pos.ExitTime.Sub(pos.EntryTime).Minutes()
+	}
+	return label
+}
+
+// positionMargin initial margin of a position record
+func positionMargin(pos *store.TraderPosition) float64 {
+	margin := pos.EntryPrice * pos.Quantity
+	if pos.Leverage > 0 {
+		margin /= float64(pos.Leverage)
+	}
+	return margin
+}
+
+// findOpeningDecision returns the latest open_<side> decision for the position's symbol made before entry
+func findOpeningDecision(pos *store.TraderPosition, records []*store.DecisionRecord) (*store.DecisionRecord, *decision.Decision) {
+	action := "open_" + strings.ToLower(pos.Side)
+	var (
+		bestRecord   *store.DecisionRecord
+		bestDecision *decision.Decision
+	)
+	for _, record := range records {
+		if record.DecisionJSON == "" || record.Timestamp.After(pos.EntryTime) {
+			continue
+		}
+		if bestRecord != nil && !record.Timestamp.After(bestRecord.Timestamp) {
+			continue
+		}
+		var decisions []decision.Decision
+		if err := json.Unmarshal([]byte(record.DecisionJSON), &decisions); err != nil {
+			continue
+		}
+		for i := range decisions {
+			if decisions[i].Symbol == pos.Symbol && decisions[i].Action == action {
+				bestRecord, bestDecision = record, &decisions[i]
+				break
+			}
+		}
+	}
+	return bestRecord, bestDecision
+}
+
+// buildOutcomeExample pairs a closed position with the decision that opened it (ok false if none is on record,
+// e.g. positions opened manually or imported from exchange history)
+func (at *AutoTrader) buildOutcomeExample(pos *store.TraderPosition, records []*store.DecisionRecord) (OutcomeExample, bool) {
+	record, d := findOpeningDecision(pos, records)
+	if record == nil {
+		return OutcomeExample{}, false
+	}
+	// Position size and risk are absolute amounts, leverage and prices stay
+	redacted := *d
+	redacted.PositionSizeUSD = 0
+	redacted.RiskUSD = 0
+	redacted.Reasoning = redactText(d.Reasoning, at.name, at.id)
+	return OutcomeExample{
+		PositionID:   pos.ID,
+		Exchange:     at.exchange,
+		DecisionTime: record.Timestamp,
+		SystemPrompt: redactText(record.SystemPrompt, at.name, at.id),
+		UserPrompt:   redactText(record.InputPrompt, at.name, at.id),
+		Reasoning:    redactText(record.CoTTrace, at.name, at.id),
+		Decision:     redacted,
+		Outcome:      labelOutcome(pos),
+	}, true
+}
+
+// encodeOutcomes encodes examples as JSONL
+func encodeOutcomes(examples []OutcomeExample) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, ex := range examples {
+		if err := enc.Encode(ex); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// writeOutcomes delivers a JSONL batch: appended to a file:// sink, POSTed (signed like the executed-decision
+// webhook) to an http(s) sink
+func (at *AutoTrader) writeOutcomes(sink, secret string, body []byte) error {
+	u, err := url.Parse(sink)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "file" {
+		if err := ValidateOutcomeSink(sink); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(u.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(body); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	return at.postSigned(sink, secret, "application/x-ndjson", body)
+}
+
+// exportOutcomes exports trades closed since the last run as (prompt, decision, outcome) examples
+// The cursor only advances once a batch is delivered, a failed delivery is retried on the next run
+func (at *AutoTrader) exportOutcomes() {
+	at.outcomes.mu.RLock()
+	sink, secret := at.outcomes.url, at.outcomes.secret
+	at.outcomes.mu.RUnlock()
+	if sink == "" || at.store == nil {
+		return
+	}
+	if !at.outcomes.running.TryLock() {
+		return
+	}
+	defer at.outcomes.running.Unlock()
+
+	cursor, err := at.store.Trader().GetOutcomeExportCursor(at.id)
+	if err != nil {
+		logger.Infof("❌ Outcome export: failed to get cursor: %v", err)
+		return
+	}
+	positions, err := at.store.Position().GetClosedPositionsUpdatedSince(at.id, cursor, outcomeExportBatch)
+	if err != nil {
+		logger.Infof("❌ Outcome export: %v", err)
+		return
+	}
+	if len(positions) == 0 {
+		return
+	}
+
+	examples := make([]OutcomeExample, 0, len(positions))
+	for _, pos := range positions {
+		records, err := at.store.Decision().GetRecordsByTimeRange(at.id, pos.EntryTime.Add(-outcomeDecisionLookback), pos.EntryTime)
+		if err != nil {
+			logger.Infof("❌ Outcome export: failed to get decisions: %v", err)
+			return
+		}
+		if ex, ok := at.buildOutcomeExample(pos, records); ok {
+			examples = append(examples, ex)
+		}
+	}
+	if len(examples) > 0 {
+		body, err := encodeOutcomes(examples)
+		if err != nil {
+			logger.Infof("❌ Outcome export: %v", err)
+			return
+		}
+		if err := at.writeOutcomes(sink, secret, body); err != nil {
+			logger.Infof("⚠️ [%s] Outcome export failed, retrying next run: %v", at.name, err)
+			return
+		}
+		logger.Infof("📤 [%s] Exported %d decision outcomes", at.name, len(examples))
+	}
+	if err := at.store.Trader().SetOutcomeExportCursor(at.id, positions[len(positions)-1].UpdatedAt); err != nil {
+		logger.Infof("❌ Outcome export: failed to save cursor: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package trader
+
+import (
+	"context"
+	"net"
+	"nofx/config"
+	"nofx/decision"
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRedactText tests removing addresses, e-mails, account amounts and trader identifiers from prompts
+func TestRedactText(t *testing.T) {
+	in := "Account: Equity 1234.56 | Balance 987.65 (80.0%) | wallet 0x" + strings.Repeat("ab", 20) +
+		" | owner bob@example.com | trader alpha-bot"
+	got := redactText(in, "alpha-bot")
+
+	for _, leaked := range []string{"1234.56", "987.65", "abab", "bob@example.com", "alpha-bot"} {
+		if strings.Contains(got, leaked) {
+			t.Errorf("%q not redacted: %s", leaked, got)
+		}
+	}
+	if !strings.Contains(got, "Equity [redacted]") || !strings.Contains(got, "(80.0%)") {
+		t.Errorf("unexpected redaction: %s", got)
+	}
+}
+
+// TestRedactPrompts tests that no account amount of real engine prompts survives the export
+func TestRedactPrompts(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	engine := decision.NewStrategyEngine(&cfg)
+	ctx := &decision.Context{
+		Account: decision.AccountInfo{TotalEquity: 12345.67, AvailableBalance: 8765.43, MarginUsedPct: 25.9, PositionCount: 1},
+		Positions: []decision.PositionInfo{{
+			Symbol: "BTCUSDT", Side: "long", EntryPrice: 63000, MarkPrice: 64000, Quantity: 0.5,
+			Leverage: 5, UnrealizedPnL: 432.1, UnrealizedPnLPct: 6.8, MarginUsed: 3200.55,
+		}},
+		RecentOrders:  []decision.RecentOrder{{Symbol: "ETHUSDT", Side: "long", EntryPrice: 3000, ExitPrice: 3100, RealizedPnL: 98.76, PnLPct: 3.3}},
+		MarketDataMap: map[string]*market.Data{"BTCUSDT": {Symbol: "BTCUSDT", CurrentPrice: 64000}},
+	}
+	system := redactText(engine.BuildSystemPrompt(ctx.Account.TotalEquity, ""))
+	user := redactText(engine.BuildUserPrompt(ctx))
+
+	for _, leaked := range []string{"12345.67", "12346", "61728", "8765.43", "32000.00", "432.10", "3201", "98.76"} {
+		if strings.Contains(system, leaked) || strings.Contains(user, leaked) {
+			t.Errorf("%q not redacted", leaked)
+		}
+	}
+	if !strings.Contains(user, "Margin 25.9%") || !strings.Contains(user, "Position Value [redacted] USDT") {
+		t.Errorf("unexpected user prompt redaction: %s", user)
+	}
+
+	at := &AutoTrader{name: "alpha-bot", id: "trader-1", exchange: "binance"}
+	entry := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	pos := &store.TraderPosition{Symbol: "BTCUSDT", Side: "LONG", EntryTime: entry}
+	records := []*store.DecisionRecord{{Timestamp: entry.Add(-time.Minute),
+		DecisionJSON: `[{"symbol":"BTCUSDT","action":"open_long","leverage":5,"position_size_usd":32000,"risk_usd":640,"stop_loss":62000}]`}}
+	ex, ok := at.buildOutcomeExample(pos, records)
+	if !ok {
+		t.Fatal("expected an outcome example")
+	}
+	if ex.Decision.PositionSizeUSD != 0 || ex.Decision.RiskUSD != 0 || ex.Decision.Leverage != 5 || ex.Decision.StopLoss != 62000 {
+		t.Errorf("expected absolute amounts zeroed, got %+v", ex.Decision)
+	}
+}
+
+// TestLabelOutcome tests labels and relative returns of closed positions
+func TestLabelOutcome(t *testing.T) {
+	entry := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	exit := entry.Add(90 * time.Minute)
+	pos := &store.TraderPosition{
+		Side: "SHORT", EntryPrice: 100, ExitPrice: 95, Quantity: 10, Leverage: 5,
+		RealizedPnL: 50, Fee: 2, EntryTime: entry, ExitTime: &exit, CloseReason: "take_profit",
+	}
+	label := labelOutcome(pos)
+	if label.Label != OutcomeWin || label.ReturnPct != 24 || label.PriceMovePct != 5 || label.HoldingMinutes != 90 {
+		t.Errorf("unexpected label: %+v", label)
+	}
+
+	pos.RealizedPnL = 1
+	if label := labelOutcome(pos); label.Label != OutcomeLoss {
+		t.Errorf("fees should turn a small gain into a loss, got %s", label.Label)
+	}
+}
+
+// TestFindOpeningDecision tests matching the latest opening decision made before entry
+func TestFindOpeningDecision(t *testing.T) {
+	entry := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	pos := &store.TraderPosition{Symbol: "BTCUSDT", Side: "LONG", EntryTime: entry}
+	records := []*store.DecisionRecord{
+		{CycleNumber: 1, Timestamp: entry.Add(-20 * time.Minute), DecisionJSON: `[{"symbol":"BTCUSDT","action":"open_long","leverage":3}]`},
+		{CycleNumber: 2, Timestamp: entry.Add(-5 * time.Minute), DecisionJSON: `[{"symbol":"ETHUSDT","action":"open_long"},{"symbol":"BTCUSDT","action":"open_long","leverage":5}]`},
+		{CycleNumber: 3, Timestamp: entry.Add(-time.Minute), DecisionJSON: `[{"symbol":"BTCUSDT","action":"open_short"}]`},
+		{CycleNumber: 4, Timestamp: entry.Add(time.Minute), DecisionJSON: `[{"symbol":"BTCUSDT","action":"open_long"}]`},
+	}
+	record, d := findOpeningDecision(pos, records)
+	if record == nil || record.CycleNumber != 2 || d.Leverage != 5 {
+		t.Fatalf("expected cycle 2 decision, got %+v %+v", record, d)
+	}
+	if record, _ := findOpeningDecision(&store.TraderPosition{Symbol: "SOLUSDT", Side: "LONG", EntryTime: entry}, records); record != nil {
+		t.Errorf("expected no opening decision, got cycle %d", record.CycleNumber)
+	}
+}
+
+// TestValidateOutcomeSink tests accepted sink URLs
+func TestValidateOutcomeSink(t *testing.T) {
+	config.Get().OutcomeExportDir = "/data/exports"
+	defer func() { config.Get().OutcomeExportDir = "" }()
+	orig := lookupIPAddr
+	lookupIPAddr = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		if host == "localhost" {
+			return []net.IPAddr{{IP: net.IPv6loopback}, {IP: net.IPv4(127, 0, 0, 1)}}, nil
+		}
+		return []net.IPAddr{{IP: net.IPv4(93, 184, 216, 34)}}, nil
+	}
+	defer func() { lookupIPAddr = orig }()
+
+	tests := map[string]bool{
+		"https://example.com/ingest":        true,
+		"http://localhost:9000":             false,
+		"http://10.0.0.8/ingest":            false,
+		"file:///data/exports/trader.jsonl": true,
+		"file:///data/exports/../x.jsonl":   false,
+		"file:///etc/passwd":                false,
+		"file:///data/exports":              false,
+		"ftp://example.com":                 false,
+		"https://":                          false,
+	}
+	for sink, ok := range tests {
+		if err := ValidateOutcomeSink(sink); (err == nil) != ok {
+			t.Errorf("ValidateOutcomeSink(%s): ok=%v, err=%v", sink, ok, err)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"strconv"
+)
+
+// partialCloseQuantity returns the quantity to close for pct percent of a position, rounded to the exchange precision
+// closeAll is set when the whole position should be closed instead: pct ≥ 100, or the remainder would fall
+// below minRemainder (base asset units) and could not be closed by a later order
+func partialCloseQuantity(total, pct, minRemainder float64, format func(float64) (string, error)) (qty float64, closeAll bool, err error) {
+	if total <= 0 {
+		return 0, false, fmt.Errorf("no position quantity to close")
+	}
+	if pct >= 100 {
+		return total, true, nil
+	}
+
+	formatted, err := format(total * pct / 100)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to format quantity: %w", err)
+	}
+	qty, err = strconv.ParseFloat(formatted, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid formatted quantity %q: %w", formatted, err)
+	}
+	if qty <= 0 {
+		return 0, false, fmt.Errorf("%.2f%% of %.8f rounds to zero at exchange precision", pct, total)
+	}
+	if qty >= total || total-qty < minRemainder {
+		return total, true, nil
+	}
+	return qty, false, nil
+}
+
+// PartialClose closes pct percent (0-100] of the symbol's long/short position and re-places its
+// stop loss / take profit for the remaining quantity. Returns the closed quantity
+func (at *AutoTrader) PartialClose(symbol, side string, pct float64) (float64, error) {
+	d := &decision.Decision{Symbol: symbol, Action: "partial_close_" + side, ClosePct: pct}
+	actionRecord := &store.DecisionAction{Action: d.Action, Symbol: symbol}
+	if err := at.executePartialCloseWithRecord(d, actionRecord, side); err != nil {
+		return 0, err
+	}
+	return actionRecord.Quantity, nil
+}
+
+// executePartialCloseWithRecord closes part of a position (partial_close_long/partial_close_short) and records detailed information
+// side is "long" or "short"
+func (at *AutoTrader) executePartialCloseWithRecord(d *decision.Decision, actionRecord *store.DecisionAction, side string) error {
+	logger.Infof("  ✂️ Partial close %s %.1f%%: %s", side, d.ClosePct, d.Symbol)
+
+	if d.ClosePct <= 0 || d.ClosePct > 100 {
+		return fmt.Errorf("close percentage must be within (0, 100]: %.2f", d.ClosePct)
+	}
+
+	marketData, err := market.Get(d.Symbol)
+	if err != nil {
+		return err
+	}
+	actionRecord.Price = marketData.CurrentPrice
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		return fmt.Errorf("failed to get positions: %w", err)
+	}
+	existing, ok := findPosition(positions, d.Symbol, side)
+	if !ok {
+		return fmt.Errorf("❌ %s has no %s position to close", d.Symbol, side)
+	}
+	if existing.ReadOnly {
+		return fmt.Errorf("❌ %s %s position is read-only while its exchange is unavailable", d.Symbol, side)
+	}
+
+	minRemainder := 0.0
+	if marketData.CurrentPrice > 0 {
+		minRemainder = at.minPositionSize() / marketData.CurrentPrice
+	}
+	quantity, closeAll, err := partialCloseQuantity(existing.Quantity, d.ClosePct, minRemainder, func(q float64) (string, error) {
+		return at.trader.FormatQuantity(d.Symbol, q)
+	})
+	if err != nil {
+		return err
+	}
+	if closeAll {
+		if d.ClosePct < 100 {
+			logger.Infof("  ⚠️ Remainder of %s %s would be below the minimum position size, closing the whole position", d.Symbol, side)
+		}
+		if side == "long" {
+			return at.executeCloseLongWithRecord(d, actionRecord)
+		}
+		return at.executeCloseShortWithRecord(d, actionRecord)
+	}
+	actionRecord.Quantity = quantity
+
+	var order map[string]interface{}
+	if side == "long" {
+		order, err = at.trader.CloseLong(d.Symbol, quantity)
+	} else {
+		order, err = at.trader.CloseShort(d.Symbol, quantity)
+	}
+	if err != nil {
+		return err
+	}
+
+	if orderID, ok := order["orderId"].(int64); ok {
+		actionRecord.OrderID = orderID
+	}
+	remaining := existing.Quantity - quantity
+	logger.Infof("  ✓ Partially closed, order ID: %v, quantity: %.4f (remaining %.4f)", order["orderId"], quantity, remaining)
+
+	// Record order to database (closed part split off the open record) and poll for confirmation
+	at.recordAndConfirmOrder(order, d.Symbol, "partial_close_"+side, quantity, marketData.CurrentPrice, 0, existing.EntryPrice)
+
+	// Protection orders carry a fixed quantity, re-place them for the remainder
+	at.resizeProtection(d, side, remaining)
+	return nil
+}
@@ -0,0 +1,43 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// TestPartialCloseQuantity tests rounding of the closed quantity and full close of dust remainders
+func TestPartialCloseQuantity(t *testing.T) {
+	format := func(q float64) (string, error) { return fmt.Sprintf("%.2f", q), nil }
+
+	tests := []struct {
+		name         string
+		total, pct   float64
+		minRemainder float64
+		wantQty      float64
+		wantAll      bool
+		wantErr      bool
+	}{
+		{name: "half", total: 1, pct: 50, wantQty: 0.5},
+		{name: "rounded to precision", total: 1, pct: 33.333, wantQty: 0.33},
+		{name: "full percentage", total: 1, pct: 100, wantQty: 1, wantAll: true},
+		{name: "remainder below minimum", total: 1, pct: 95, minRemainder: 0.1, wantQty: 1, wantAll: true},
+		{name: "rounds up to whole position", total: 0.1, pct: 99, wantQty: 0.1, wantAll: true},
+		{name: "rounds to zero", total: 0.01, pct: 10, wantErr: true},
+		{name: "no position", total: 0, pct: 50, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qty, all, err := partialCloseQuantity(tt.total, tt.pct, tt.minRemainder, format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if math.Abs(qty-tt.wantQty) > 1e-9 || all != tt.wantAll {
+				t.Errorf("expected %.4f (closeAll=%v), got %.4f (closeAll=%v)", tt.wantQty, tt.wantAll, qty, all)
+			}
+		})
+	}
+}
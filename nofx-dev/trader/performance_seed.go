@@ -0,0 +1,135 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/store"
+	"sync"
+)
+
+const (
+	// recentTradesWindow closed trades shown to the AI as recent performance
+	recentTradesWindow = 10
+	// defaultSeedMinLiveTrades live closed trades after which seeding stops when min_live_trades is not set
+	defaultSeedMinLiveTrades = recentTradesWindow
+)
+
+// perfSeedState cold-start performance seed, backtest trades are cached (a run's trades don't change)
+type perfSeedState struct {
+	mu         sync.Mutex
+	seed       store.PerformanceSeed
+	backtest   []store.RecentTrade
+	backtestID string
+}
+
+// ValidatePerformanceSeed checks the seed source of a performance seed (empty source disables it)
+func ValidatePerformanceSeed(seed store.PerformanceSeed) error {
+	switch seed.Source {
+	case "":
+		return nil
+	case store.PerformanceSeedTrader, store.PerformanceSeedBacktest:
+	default:
+		return fmt.Errorf("invalid performance seed source %q, expected %q or %q", seed.Source, store.PerformanceSeedTrader, store.PerformanceSeedBacktest)
+	}
+	if seed.ID == "" {
+		return fmt.Errorf("performance seed %s ID is required", seed.Source)
+	}
+	if seed.MinLiveTrades < 0 {
+		return fmt.Errorf("min live trades must not be negative: %d", seed.MinLiveTrades)
+	}
+	return nil
+}
+
+// SetPerformanceSeed updates the cold-start performance seed (empty source disables it)
+func (at *AutoTrader) SetPerformanceSeed(seed store.PerformanceSeed) error {
+	if err := ValidatePerformanceSeed(seed); err != nil {
+		return err
+	}
+	at.perfSeed.mu.Lock()
+	at.perfSeed.seed = seed
+	at.perfSeed.mu.Unlock()
+	return nil
+}
+
+// recentOrderFromTrade converts a closed trade into the AI's recent order format
+func recentOrderFromTrade(trade store.RecentTrade, seeded bool) decision.RecentOrder {
+	return decision.RecentOrder{
+		Symbol:       trade.Symbol,
+		Side:         trade.Side,
+		EntryPrice:   trade.EntryPrice,
+		ExitPrice:    trade.ExitPrice,
+		RealizedPnL:  trade.RealizedPnL,
+		PnLPct:       trade.PnLPct,
+		EntryTime:    trade.EntryTime,
+		ExitTime:     trade.ExitTime,
+		HoldDuration: trade.HoldDuration,
+		Seeded:       seeded,
+	}
+}
+
+// mergeSeededTrades tops up live recent trades with seeded ones up to window, until minLive live trades exist
+func mergeSeededTrades(live []decision.RecentOrder, seeded []store.RecentTrade, window, minLive int) []decision.RecentOrder {
+	if len(live) >= minLive {
+		return live
+	}
+	merged := live
+	for _, trade := range seeded {
+		if len(merged) >= window {
+			break
+		}
+		merged = append(merged, recentOrderFromTrade(trade, true))
+	}
+	return merged
+}
+
+// seedTrades loads the seed's closed trades (newest first) and a description of where they come from
+func (at *AutoTrader) seedTrades(seed store.PerformanceSeed) ([]store.RecentTrade, string, error) {
+	switch seed.Source {
+	case store.PerformanceSeedTrader:
+		trades, err := at.store.Position().GetRecentTrades(seed.ID, recentTradesWindow)
+		return trades, "predecessor trader " + seed.ID, err
+	case store.PerformanceSeedBacktest:
+		at.perfSeed.mu.Lock()
+		defer at.perfSeed.mu.Unlock()
+		if at.perfSeed.backtestID != seed.ID {
+			trades, err := at.store.Backtest().LoadClosedTrades(seed.ID, recentTradesWindow)
+			if err != nil {
+				return nil, "", err
+			}
+			at.perfSeed.backtest, at.perfSeed.backtestID = trades, seed.ID
+		}
+		return at.perfSeed.backtest, "backtest run " + seed.ID, nil
+	}
+	return nil, "", nil
+}
+
+// applyPerformanceSeed tops up the context's recent trades from the performance seed while the trader
+// has fewer live closed trades than the seed's minimum
+func (at *AutoTrader) applyPerformanceSeed(ctx *decision.Context) {
+	at.perfSeed.mu.Lock()
+	seed := at.perfSeed.seed
+	at.perfSeed.mu.Unlock()
+	if seed.Source == "" || at.store == nil {
+		return
+	}
+	minLive := seed.MinLiveTrades
+	if minLive <= 0 {
+		minLive = defaultSeedMinLiveTrades
+	}
+	if len(ctx.RecentOrders) >= minLive {
+		return
+	}
+
+	seeded, source, err := at.seedTrades(seed)
+	if err != nil {
+		logger.Infof("⚠️ [%s] Failed to load performance seed (%s %s): %v", at.name, seed.Source, seed.ID, err)
+		return
+	}
+	live := len(ctx.RecentOrders)
+	ctx.RecentOrders = mergeSeededTrades(ctx.RecentOrders, seeded, recentTradesWindow, minLive)
+	if n := len(ctx.RecentOrders) - live; n > 0 {
+		ctx.PerformanceSeed = source
+		logger.Infof("🌱 [%s] Seeded %d recent trades from %s (%d/%d live trades)", at.name, n, source, live, minLive)
+	}
+}
@@ -0,0 +1,38 @@
+package trader
+
+import (
+	"nofx/decision"
+	"nofx/store"
+	"testing"
+)
+
+// TestMergeSeededTrades tests seeded trades top up live ones until enough live trades exist
+func TestMergeSeededTrades(t *testing.T) {
+	live := []decision.RecentOrder{{Symbol: "BTCUSDT", Side: "long"}, {Symbol: "ETHUSDT", Side: "short"}}
+	seeded := []store.RecentTrade{{Symbol: "SOLUSDT"}, {Symbol: "XRPUSDT"}, {Symbol: "DOGEUSDT"}}
+
+	merged := mergeSeededTrades(live, seeded, 4, 3)
+	if len(merged) != 4 {
+		t.Fatalf("expected 4 trades (window), got %d", len(merged))
+	}
+	if merged[0].Seeded || merged[1].Seeded || !merged[2].Seeded || merged[2].Symbol != "SOLUSDT" {
+		t.Errorf("expected live trades first, then seeded ones: %+v", merged)
+	}
+
+	if merged := mergeSeededTrades(live, seeded, 10, 2); len(merged) != 2 {
+		t.Errorf("expected no seeding once min live trades is reached, got %d trades", len(merged))
+	}
+}
+
+// TestValidatePerformanceSeed tests seed source and ID validation
+func TestValidatePerformanceSeed(t *testing.T) {
+	if err := ValidatePerformanceSeed(store.PerformanceSeed{}); err != nil {
+		t.Errorf("expected disabled seed to be valid: %v", err)
+	}
+	if err := ValidatePerformanceSeed(store.PerformanceSeed{Source: store.PerformanceSeedBacktest}); err == nil {
+		t.Error("expected error for missing ID")
+	}
+	if err := ValidatePerformanceSeed(store.PerformanceSeed{Source: "paper", ID: "x"}); err == nil {
+		t.Error("expected error for unknown source")
+	}
+}
@@ -0,0 +1,235 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"nofx/market"
+	"strings"
+	"time"
+)
+
+// stressMaintenanceRate estimated maintenance margin rate (first tier of major perpetuals)
+// Exchanges use tiered rates by notional, this keeps the margin ratio a conservative estimate
+const stressMaintenanceRate = 0.005
+
+// StressScenario price shocks applied to all open positions
+// Percentages are price changes, e.g. -10 means price falls 10%
+type StressScenario struct {
+	Name      string             `json:"name"`
+	BTCPct    float64            `json:"btc_pct"`             // Shock for BTC
+	AltPct    float64            `json:"alt_pct"`             // Shock for every other symbol
+	Overrides map[string]float64 `json:"overrides,omitempty"` // Per-symbol shock (e.g. "ETHUSDT": -15), takes precedence
+}
+
+// DefaultStressScenarios used when no scenarios are supplied
+func DefaultStressScenarios() []StressScenario {
+	return []StressScenario{
+		{Name: "Mild sell-off", BTCPct: -5, AltPct: -10},
+		{Name: "Crash", BTCPct: -10, AltPct: -20},
+		{Name: "Capitulation", BTCPct: -20, AltPct: -35},
+		{Name: "Short squeeze", BTCPct: 10, AltPct: 20},
+	}
+}
+
+// shockFor returns the price change percentage for symbol
+func (s StressScenario) shockFor(symbol string) float64 {
+	if pct, ok := s.Overrides[symbol]; ok {
+		return pct
+	}
+	if strings.HasPrefix(symbol, "BTC") {
+		return s.BTCPct
+	}
+	return s.AltPct
+}
+
+// StressPosition shocked state of one position
+type StressPosition struct {
+	Symbol           string  `json:"symbol"`
+	Side             string  `json:"side"`
+	ShockPct         float64 `json:"shock_pct"`
+	MarkPrice        float64 `json:"mark_price"`
+	ShockedPrice     float64 `json:"shocked_price"`
+	PnLChange        float64 `json:"pnl_change"`
+	LiquidationPrice float64 `json:"liquidation_price"`
+	LiquidationDist  float64 `json:"liquidation_distance_pct"` // Distance from shocked price to liquidation (negative = beyond)
+	Liquidated       bool    `json:"liquidated"`
+}
+
+// StressResult portfolio state after one scenario
+type StressResult struct {
+	Scenario           StressScenario   `json:"scenario"`
+	Equity             float64          `json:"equity"`
+	EquityChange       float64          `json:"equity_change"`
+	EquityChangePct    float64          `json:"equity_change_pct"`
+	MarginUsedPct      float64          `json:"margin_used_pct"` // Initial margin / equity
+	MarginRatio        float64          `json:"margin_ratio"`    // Estimated maintenance margin / equity (100% = account liquidation)
+	LiquidatedCount    int              `json:"liquidated_count"`
+	MinLiquidationDist float64          `json:"min_liquidation_distance_pct"`
+	Positions          []StressPosition `json:"positions"`
+}
+
+// StressReport results of all scenarios for a portfolio
+type StressReport struct {
+	Time      time.Time      `json:"time"`
+	Equity    float64        `json:"equity"`
+	Results   []StressResult `json:"results"`
+	WorstCase *StressResult  `json:"worst_case,omitempty"`
+}
+
+// Summary one-line worst-case description for logs and reports
+func (r *StressReport) Summary() string {
+	if r.WorstCase == nil {
+		return "no open positions"
+	}
+	w := r.WorstCase
+	return fmt.Sprintf("worst case %q: equity %.2f (%+.2f%%), margin ratio %.1f%%, %d position(s) liquidated, min liquidation distance %.2f%%",
+		w.Scenario.Name, w.Equity, w.EquityChangePct, w.MarginRatio, w.LiquidatedCount, w.MinLiquidationDist)
+}
+
+// StressTestPositions applies each scenario to exchange positions and recomputes equity,
+// margin ratio and liquidation proximity
+func StressTestPositions(positions []Position, equity float64, scenarios []StressScenario) *StressReport {
+	report := &StressReport{Time: time.Now(), Equity: equity}
+	if len(positions) == 0 {
+		return report
+	}
+
+	for _, scenario := range scenarios {
+		result := StressResult{Scenario: scenario, MinLiquidationDist: math.Inf(1)}
+		var pnlChange, initialMargin, maintMargin float64
+
+		for _, pos := range positions {
+			symbol, side := pos.Symbol, pos.Side
+			mark := pos.MarkPrice
+			qty := pos.Quantity
+			if mark <= 0 || qty == 0 {
+				continue
+			}
+			leverage := pos.Leverage
+			if leverage <= 0 {
+				leverage = 1
+			}
+			liqPrice := pos.LiquidationPrice
+
+			shock := scenario.shockFor(symbol)
+			shocked := mark * (1 + shock/100)
+			change := (shocked - mark) * qty
+			if side == "short" {
+				change = -change
+			}
+
+			sp := StressPosition{
+				Symbol:           symbol,
+				Side:             side,
+				ShockPct:         shock,
+				MarkPrice:        mark,
+				ShockedPrice:     shocked,
+				PnLChange:        change,
+				LiquidationPrice: liqPrice,
+			}
+			if liqPrice > 0 && shocked > 0 {
+				if side == "short" {
+					sp.LiquidationDist = (liqPrice - shocked) / shocked * 100
+				} else {
+					sp.LiquidationDist = (shocked - liqPrice) / shocked * 100
+				}
+				sp.Liquidated = sp.LiquidationDist <= 0
+				if sp.Liquidated {
+					result.LiquidatedCount++
+				}
+				result.MinLiquidationDist = math.Min(result.MinLiquidationDist, sp.LiquidationDist)
+			}
+
+			pnlChange += change
+			initialMargin += qty * shocked / leverage
+			maintMargin += qty * shocked * stressMaintenanceRate
+			result.Positions = append(result.Positions, sp)
+		}
+
+		if math.IsInf(result.MinLiquidationDist, 1) {
+			result.MinLiquidationDist = 0
+		}
+		result.Equity = equity + pnlChange
+		result.EquityChange = pnlChange
+		if equity > 0 {
+			result.EquityChangePct = pnlChange / equity * 100
+		}
+		if result.Equity > 0 {
+			result.MarginUsedPct = initialMargin / result.Equity * 100
+			result.MarginRatio = maintMargin / result.Equity * 100
+		} else {
+			result.MarginUsedPct = 100
+			result.MarginRatio = 100
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	for i := range report.Results {
+		if report.WorstCase == nil || report.Results[i].Equity < report.WorstCase.Equity {
+			report.WorstCase = &report.Results[i]
+		}
+	}
+	return report
+}
+
+// StressTest shocks the trader's current open positions by each scenario (defaults if none)
+func (at *AutoTrader) StressTest(scenarios []StressScenario) (*StressReport, error) {
+	if len(scenarios) == 0 {
+		scenarios = DefaultStressScenarios()
+	}
+	balance, err := at.trader.GetBalance()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	equity := getFloat(balance, "totalWalletBalance") + getFloat(balance, "totalUnrealizedProfit")
+	if equity <= 0 {
+		equity = getFloat(balance, "total_equity")
+	}
+	return StressTestPositions(positions, equity, scenarios), nil
+}
+
+// DailyReport daily trader summary, built when the daily P&L resets
+type DailyReport struct {
+	Date          string        `json:"date"`
+	DailyPnL      float64       `json:"daily_pnl"`
+	Equity        float64       `json:"equity"`
+	PositionCount int           `json:"position_count"`
+	StressSummary string        `json:"stress_summary"`
+	StressWorst   *StressResult `json:"stress_worst_case,omitempty"`
+	Summary       string        `json:"summary"` // One line in the owner's locale, e.g. "2 Jan 2006: +12.30 USDT, equity 1,012.30 USDT, 2 positions"
+}
+
+// buildDailyReport summarizes the day including the worst-case stress scenario of open positions
+func (at *AutoTrader) buildDailyReport() *DailyReport {
+	report := &DailyReport{
+		Date:     at.lastResetTime.Format("2006-01-02"),
+		DailyPnL: at.dailyPnL,
+	}
+	stress, err := at.StressTest(nil)
+	if err != nil {
+		report.StressSummary = fmt.Sprintf("stress test unavailable: %v", err)
+		report.Summary = at.dailyReportSummary(report)
+		return report
+	}
+	report.Equity = stress.Equity
+	report.StressSummary = stress.Summary()
+	report.StressWorst = stress.WorstCase
+	if stress.WorstCase != nil {
+		report.PositionCount = len(stress.WorstCase.Positions)
+	}
+	report.Summary = at.dailyReportSummary(report)
+	return report
+}
+
+// dailyReportSummary renders the report's figures in the owner's locale and time zone
+func (at *AutoTrader) dailyReportSummary(report *DailyReport) string {
+	f := at.notificationFormat()
+	return fmt.Sprintf("%s: %s, equity %s, %d positions",
+		f.Date(at.lastResetTime), f.SignedAmount(report.DailyPnL, market.DefaultQuoteAsset),
+		f.Amount(report.Equity, market.DefaultQuoteAsset), report.PositionCount)
+}
@@ -0,0 +1,53 @@
+package trader
+
+import (
+	"math"
+	"testing"
+)
+
+// TestStressTestPositions tests shocked equity, liquidation detection and worst-case selection
+func TestStressTestPositions(t *testing.T) {
+	positions := []Position{
+		{Symbol: "BTCUSDT", Side: "long", Quantity: 0.1, MarkPrice: 60000, Leverage: 10, LiquidationPrice: 55000},
+		{Symbol: "SOLUSDT", Side: "short", Quantity: 10, MarkPrice: 100, Leverage: 5, LiquidationPrice: 115},
+	}
+	scenarios := []StressScenario{
+		{Name: "Crash", BTCPct: -10, AltPct: -20},
+		{Name: "Squeeze", BTCPct: 5, AltPct: 20},
+	}
+
+	report := StressTestPositions(positions, 1000, scenarios)
+	if len(report.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(report.Results))
+	}
+
+	crash := report.Results[0]
+	// BTC long: (54000-60000)*0.1 = -600, SOL short: -(80-100)*10 = +200
+	if math.Abs(crash.EquityChange-(-400)) > 1e-6 {
+		t.Errorf("crash: expected equity change -400, got %.4f", crash.EquityChange)
+	}
+	if crash.LiquidatedCount != 1 || !crash.Positions[0].Liquidated {
+		t.Errorf("crash: expected BTC long liquidated, got %+v", crash.Positions)
+	}
+
+	squeeze := report.Results[1]
+	// BTC long: +300, SOL short: -(120-100)*10 = -200, SOL beyond liquidation at 115
+	if math.Abs(squeeze.EquityChange-100) > 1e-6 {
+		t.Errorf("squeeze: expected equity change 100, got %.4f", squeeze.EquityChange)
+	}
+	if squeeze.LiquidatedCount != 1 || !squeeze.Positions[1].Liquidated {
+		t.Errorf("squeeze: expected SOL short liquidated, got %+v", squeeze.Positions)
+	}
+
+	if report.WorstCase == nil || report.WorstCase.Scenario.Name != "Crash" {
+		t.Errorf("expected Crash as worst case, got %+v", report.WorstCase)
+	}
+}
+
+// TestStressScenarioOverrides tests per-symbol overrides take precedence over group shocks
+func TestStressScenarioOverrides(t *testing.T) {
+	s := StressScenario{BTCPct: -10, AltPct: -20, Overrides: map[string]float64{"ETHUSDT": -15}}
+	if s.shockFor("BTCUSDT") != -10 || s.shockFor("ETHUSDT") != -15 || s.shockFor("DOGEUSDT") != -20 {
+		t.Errorf("unexpected shocks: %v %v %v", s.shockFor("BTCUSDT"), s.shockFor("ETHUSDT"), s.shockFor("DOGEUSDT"))
+	}
+}
@@ -0,0 +1,140 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+// positionAgeCheckInterval how often position ages are checked against the trader's limit
+const positionAgeCheckInterval = 1 * time.Minute
+
+// EventPositionAged a position was closed for exceeding the trader's max holding time
+const EventPositionAged = "position_aged"
+
+// Max position age actions
+const (
+	PositionAgeActionClose  = "close"  // Close the position once it exceeds the limit
+	PositionAgeActionReview = "review" // Flag it in the prompt, the AI must close it or justify holding it
+)
+
+// positionAgeState per-trader max holding time, limit 0 = disabled
+type positionAgeState struct {
+	mu     sync.RWMutex
+	limit  time.Duration
+	action string
+}
+
+// ValidatePositionAge checks the max position age limit and action
+func ValidatePositionAge(minutes int, action string) error {
+	if minutes < 0 {
+		return fmt.Errorf("max position age must not be negative: %d", minutes)
+	}
+	if action != "" && action != PositionAgeActionClose && action != PositionAgeActionReview {
+		return fmt.Errorf("invalid max position age action %q, expected %q or %q", action, PositionAgeActionClose, PositionAgeActionReview)
+	}
+	return nil
+}
+
+// SetMaxPositionAge updates the max holding time (0 disables it), action defaults to close
+func (at *AutoTrader) SetMaxPositionAge(minutes int, action string) error {
+	if err := ValidatePositionAge(minutes, action); err != nil {
+		return err
+	}
+	if action == "" {
+		action = PositionAgeActionClose
+	}
+	at.positionAge.mu.Lock()
+	at.positionAge.limit = time.Duration(minutes) * time.Minute
+	at.positionAge.action = action
+	at.positionAge.mu.Unlock()
+	return nil
+}
+
+// maxPositionAge returns the limit and action (limit 0 when disabled)
+func (at *AutoTrader) maxPositionAge() (time.Duration, string) {
+	at.positionAge.mu.RLock()
+	defer at.positionAge.mu.RUnlock()
+	return at.positionAge.limit, at.positionAge.action
+}
+
+// agedPositions returns the keys (symbol_side) of positions opened more than limit before now
+// Positions without a known open time are skipped
+func agedPositions(openTimes map[string]time.Time, limit time.Duration, now time.Time) []string {
+	var aged []string
+	for key, opened := range openTimes {
+		if !opened.IsZero() && now.Sub(opened) > limit {
+			aged = append(aged, key)
+		}
+	}
+	return aged
+}
+
+// checkPositionAge closes positions held longer than the trader's max position age (close action only,
+// review is handled in the decision prompt)
+func (at *AutoTrader) checkPositionAge() {
+	limit, action := at.maxPositionAge()
+	if limit <= 0 || action != PositionAgeActionClose {
+		return
+	}
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Infof("❌ Position age check: failed to get positions: %v", err)
+		return
+	}
+
+	// Open time: the position record (most accurate), otherwise the exchange's creation time
+	entryTimes := make(map[string]time.Time)
+	if at.store != nil {
+		if records, err := at.store.Position().GetOpenPositions(at.id); err == nil {
+			for _, r := range records {
+				entryTimes[bracketKey(r.Symbol, r.Side)] = r.EntryTime
+			}
+		}
+	}
+	openTimes := make(map[string]time.Time)
+	for _, pos := range positions {
+		if pos.ReadOnly || pos.Quantity <= 0 {
+			continue
+		}
+		key := bracketKey(pos.Symbol, pos.Side)
+		openTimes[key] = entryTimes[key]
+		if openTimes[key].IsZero() && pos.CreatedTime > 0 {
+			openTimes[key] = time.UnixMilli(pos.CreatedTime)
+		}
+	}
+
+	var closed []string
+	for _, key := range agedPositions(openTimes, limit, time.Now()) {
+		idx := strings.LastIndex(key, "_")
+		symbol, side := key[:idx], key[idx+1:]
+		logger.Infof("⏰ %s %s held for %s, beyond the max position age %s, closing", symbol, side,
+			time.Since(openTimes[key]).Round(time.Minute), limit)
+		if err := at.emergencyClosePosition(symbol, side); err != nil {
+			logger.Infof("❌ Max position age close failed (%s %s): %v", symbol, side, err)
+			continue
+		}
+		at.clearTrailingStop(symbol, side)
+		at.ClearPeakPnLCache(symbol, side)
+		closed = append(closed, symbol+" "+side)
+	}
+	if len(closed) == 0 {
+		return
+	}
+
+	event := TraderEvent{
+		TraderID:   at.id,
+		Type:       EventPositionAged,
+		Exchange:   at.exchange,
+		ExchangeID: at.exchangeID,
+		Message:    fmt.Sprintf("max position age %s: closed %s", limit, strings.Join(closed, ", ")),
+		Time:       time.Now(),
+	}
+	if at.config.OnEvent != nil {
+		at.config.OnEvent(event)
+	}
+	at.notifyWebhookEvent(event)
+}
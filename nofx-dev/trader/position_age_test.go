@@ -0,0 +1,40 @@
+package trader
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+// TestAgedPositions tests selecting positions held longer than the limit
+func TestAgedPositions(t *testing.T) {
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	openTimes := map[string]time.Time{
+		"BTCUSDT_long":  now.Add(-25 * time.Hour),
+		"ETHUSDT_short": now.Add(-23 * time.Hour),
+		"SOLUSDT_long":  now.Add(-48 * time.Hour),
+		"XRPUSDT_long":  {}, // Unknown open time
+	}
+
+	got := agedPositions(openTimes, 24*time.Hour, now)
+	sort.Strings(got)
+	want := []string{"BTCUSDT_long", "SOLUSDT_long"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestValidatePositionAge tests limit and action validation
+func TestValidatePositionAge(t *testing.T) {
+	if err := ValidatePositionAge(-1, PositionAgeActionClose); err == nil {
+		t.Error("expected error for negative limit")
+	}
+	if err := ValidatePositionAge(60, "ignore"); err == nil {
+		t.Error("expected error for unknown action")
+	}
+	for _, action := range []string{"", PositionAgeActionClose, PositionAgeActionReview} {
+		if err := ValidatePositionAge(60, action); err != nil {
+			t.Errorf("action %q: unexpected error %v", action, err)
+		}
+	}
+}
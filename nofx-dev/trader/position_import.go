@@ -0,0 +1,163 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"nofx/store"
+	"sort"
+	"strings"
+	"time"
+)
+
+// entryTimeLookback how far back trade history is scanned to estimate entry time of imported positions
+const entryTimeLookback = 7 * 24 * time.Hour
+
+// symbolTradeHistory optional capability for exchanges that can list fills for a single symbol
+type symbolTradeHistory interface {
+	GetTradesForSymbol(symbol string, startTime time.Time, limit int) ([]TradeRecord, error)
+}
+
+// ImportPositions records positions that already exist on the exchange but have no local record
+// Used when a trader is attached to an account that already holds positions, so logging,
+// risk control and AI context see them as regular open positions
+func (at *AutoTrader) ImportPositions() (int, error) {
+	if at.store == nil {
+		return 0, fmt.Errorf("store not configured")
+	}
+	// Entry time comes from the stored record, so the AI context picks it up on the next cycle
+	imported, err := importExchangePositions(at.store, at.id, at.exchangeID, at.exchange, at.trader, "import")
+	if err != nil {
+		return 0, err
+	}
+	return len(imported), nil
+}
+
+// importExchangePositions creates OPEN records for exchange positions without a local counterpart
+// source marks where the record came from ("sync" for startup sync, "import" for trader attach)
+func importExchangePositions(st *store.Store, traderID, exchangeID, exchangeType string, trader Trader, source string) ([]*store.TraderPosition, error) {
+	exchangePositions, err := trader.GetPositions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange positions: %w", err)
+	}
+
+	localPositions, err := st.Position().GetOpenPositions(traderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get local positions: %w", err)
+	}
+
+	// Build local position map: symbol_side -> position
+	localMap := make(map[string]*store.TraderPosition)
+	for _, pos := range localPositions {
+		key := fmt.Sprintf("%s_%s", pos.Symbol, pos.Side)
+		localMap[key] = pos
+	}
+
+	var imported []*store.TraderPosition
+	for _, pos := range exchangePositions {
+		symbol := pos.Symbol
+		if symbol == "" || pos.Side == "" {
+			continue
+		}
+		normalizedSide := strings.ToUpper(pos.Side)
+
+		key := fmt.Sprintf("%s_%s", symbol, normalizedSide)
+		if _, exists := localMap[key]; exists {
+			continue // Already tracking this position
+		}
+
+		qty := pos.Quantity
+		if qty < 0.0000001 {
+			continue // No actual position
+		}
+
+		entryPrice := pos.EntryPrice
+		leverage := int(pos.Leverage)
+		if leverage == 0 {
+			leverage = 1
+		}
+		entryTime := resolveEntryTime(trader, pos, symbol, normalizedSide, qty)
+
+		newPos := &store.TraderPosition{
+			TraderID:           traderID,
+			ExchangeID:         exchangeID,
+			ExchangeType:       exchangeType,
+			ExchangePositionID: fmt.Sprintf("%s_%s_%d", symbol, normalizedSide, entryTime.UnixMilli()),
+			Symbol:             symbol,
+			Side:               normalizedSide,
+			Quantity:           qty,
+			EntryPrice:         entryPrice,
+			EntryTime:          entryTime,
+			Leverage:           leverage,
+			Source:             source,
+		}
+
+		if err := st.Position().CreateOpenPosition(newPos); err != nil {
+			logger.Infof("⚠️  Failed to create %s position record: %v", source, err)
+			continue
+		}
+		if newPos.ID == 0 {
+			continue // Already recorded under this exchange account
+		}
+		imported = append(imported, newPos)
+		logger.Infof("📥 Imported existing position (%s): [%s] %s %s @ %.4f (qty: %.4f, since %s)",
+			source, traderID[:8], symbol, normalizedSide, entryPrice, qty, entryTime.Format("2006-01-02 15:04"))
+	}
+
+	return imported, nil
+}
+
+// resolveEntryTime determines when a pre-existing position was opened
+// Priority: exchange-reported creation time -> estimate from recent fills -> now
+func resolveEntryTime(trader Trader, pos Position, symbol, side string, qty float64) time.Time {
+	if pos.CreatedTime > 0 {
+		return time.UnixMilli(pos.CreatedTime)
+	}
+	// UpdatedTime moves with every fill, funding and margin change, it isn't when the position opened
+	if history, ok := capability[symbolTradeHistory](trader, symbol, side); ok {
+		trades, err := history.GetTradesForSymbol(symbol, time.Now().Add(-entryTimeLookback), 1000)
+		if err == nil {
+			if t, ok := estimateEntryTime(trades, side, qty); ok {
+				return t
+			}
+		}
+	}
+
+	return time.Now()
+}
+
+// estimateEntryTime walks fills backwards from the newest until the current position size is explained,
+// the fill where that happens is the start of the current position
+func estimateEntryTime(trades []TradeRecord, side string, qty float64) (time.Time, bool) {
+	sorted := make([]TradeRecord, len(trades))
+	copy(sorted, trades)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Time.After(sorted[j].Time) })
+
+	openSide := "BUY"
+	if side == "SHORT" {
+		openSide = "SELL"
+	}
+
+	remaining := qty
+	var oldest time.Time
+	for _, tr := range sorted {
+		// In hedge mode only fills of this position side count; one-way mode reports BOTH
+		if tr.PositionSide != "" && tr.PositionSide != "BOTH" && tr.PositionSide != side {
+			continue
+		}
+		if tr.Side == openSide {
+			remaining -= tr.Quantity
+		} else {
+			remaining += tr.Quantity
+		}
+		oldest = tr.Time
+		if remaining <= qty*1e-6 {
+			return tr.Time, true
+		}
+	}
+
+	// Position is older than the lookback window, oldest fill seen is the closest approximation
+	if !oldest.IsZero() {
+		return oldest, true
+	}
+	return time.Time{}, false
+}
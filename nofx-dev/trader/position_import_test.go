@@ -0,0 +1,93 @@
+package trader
+
+import (
+	"testing"
+	"time"
+)
+
+// TestEstimateEntryTime tests entry time estimation from fill history
+func TestEstimateEntryTime(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return base.Add(time.Duration(h) * time.Hour) }
+
+	tests := []struct {
+		name   string
+		trades []TradeRecord
+		side   string
+		qty    float64
+		want   time.Time
+		wantOK bool
+	}{
+		{
+			name: "single open",
+			trades: []TradeRecord{
+				{Side: "BUY", PositionSide: "LONG", Quantity: 1, Time: at(5)},
+			},
+			side: "LONG", qty: 1, want: at(5), wantOK: true,
+		},
+		{
+			name: "previous round trip ignored",
+			trades: []TradeRecord{
+				{Side: "BUY", PositionSide: "LONG", Quantity: 2, Time: at(1)},
+				{Side: "SELL", PositionSide: "LONG", Quantity: 2, Time: at(2)},
+				{Side: "BUY", PositionSide: "LONG", Quantity: 1, Time: at(3)},
+				{Side: "BUY", PositionSide: "LONG", Quantity: 1, Time: at(4)},
+			},
+			side: "LONG", qty: 2, want: at(3), wantOK: true,
+		},
+		{
+			name: "partial close then add",
+			trades: []TradeRecord{
+				{Side: "SELL", PositionSide: "SHORT", Quantity: 3, Time: at(1)},
+				{Side: "BUY", PositionSide: "SHORT", Quantity: 1, Time: at(2)},
+				{Side: "SELL", PositionSide: "SHORT", Quantity: 1, Time: at(3)},
+			},
+			side: "SHORT", qty: 3, want: at(1), wantOK: true,
+		},
+		{
+			name: "other position side ignored",
+			trades: []TradeRecord{
+				{Side: "BUY", PositionSide: "LONG", Quantity: 1, Time: at(1)},
+				{Side: "SELL", PositionSide: "SHORT", Quantity: 5, Time: at(2)},
+			},
+			side: "LONG", qty: 1, want: at(1), wantOK: true,
+		},
+		{
+			name: "older than lookback falls back to oldest fill",
+			trades: []TradeRecord{
+				{Side: "BUY", PositionSide: "LONG", Quantity: 1, Time: at(6)},
+			},
+			side: "LONG", qty: 3, want: at(6), wantOK: true,
+		},
+		{
+			name: "no fills", side: "LONG", qty: 1, wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := estimateEntryTime(tt.trades, tt.side, tt.qty)
+			if ok != tt.wantOK {
+				t.Fatalf("ok: expected %v, got %v", tt.wantOK, ok)
+			}
+			if ok && !got.Equal(tt.want) {
+				t.Errorf("entry time: expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestResolveEntryTime tests the exchange's creation time is used and the last update time is not
+func TestResolveEntryTime(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	pos := Position{CreatedTime: created.UnixMilli(), UpdatedTime: created.Add(time.Hour).UnixMilli()}
+	if got := resolveEntryTime(&stubExchange{}, pos, "BTCUSDT", "long", 1); !got.Equal(created) {
+		t.Errorf("expected creation time %v, got %v", created, got)
+	}
+
+	// Only an update time: falls through to now
+	pos = Position{UpdatedTime: created.UnixMilli()}
+	if got := resolveEntryTime(&stubExchange{}, pos, "BTCUSDT", "long", 1); time.Since(got) > time.Minute {
+		t.Errorf("expected the update time ignored, got %v", got)
+	}
+}
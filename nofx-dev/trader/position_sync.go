@@ -22,6 +22,7 @@ type PositionSyncManager struct {
 	cacheMutex           sync.RWMutex
 	lastHistorySync      map[string]time.Time // trader_id -> last history sync time
 	lastHistorySyncMutex sync.RWMutex
+	orphanedOrders       map[string]bool // exchange_id|symbol orphaned in the last sweep (run loop only)
 }
 
 // NewPositionSyncManager Create position synchronization manager
@@ -73,6 +74,8 @@ func (m *PositionSyncManager) run() {
 
 	ticker := time.NewTicker(m.interval)
 	defer ticker.Stop()
+	orphanTicker := time.NewTicker(orphanCleanupInterval)
+	defer orphanTicker.Stop()
 
 	for {
 		select {
@@ -80,6 +83,8 @@ func (m *PositionSyncManager) run() {
 			return
 		case <-ticker.C:
 			m.syncPositions()
+		case <-orphanTicker.C:
+			m.cleanupOrphanedOrders()
 		}
 	}
 }
@@ -141,41 +146,46 @@ func (m *PositionSyncManager) syncTraderPositions(traderID string, localPosition
 
 	// Build exchange position map: symbol_side -> position
 	// Note: Exchange returns side as "long"/"short" (lowercase), database stores "LONG"/"SHORT" (uppercase)
-	exchangeMap := make(map[string]map[string]interface{})
+	exchangeMap := make(map[string]Position)
 	for _, pos := range exchangePositions {
-		symbol, _ := pos["symbol"].(string)
-		side, _ := pos["side"].(string) // Note: use "side" not "positionSide"
-		if symbol == "" || side == "" {
+		if pos.Symbol == "" || pos.Side == "" {
 			continue
 		}
 		// Normalize side to uppercase for matching with database
-		normalizedSide := strings.ToUpper(side)
-		key := fmt.Sprintf("%s_%s", symbol, normalizedSide)
+		key := fmt.Sprintf("%s_%s", pos.Symbol, strings.ToUpper(pos.Side))
 		exchangeMap[key] = pos
 	}
 
 	// Compare local and exchange positions
+	closedSymbols := make(map[string]bool)
 	for _, localPos := range localPositions {
+		// Position opened on the fallback exchange, not visible through the primary account
+		if exchangeID != "" && localPos.ExchangeID != "" && localPos.ExchangeID != exchangeID {
+			continue
+		}
+
 		key := fmt.Sprintf("%s_%s", localPos.Symbol, localPos.Side)
 		exchangePos, exists := exchangeMap[key]
 
 		if !exists {
 			// Exchange doesn't have this position → it has been closed
 			m.closeLocalPosition(localPos, trader, "manual")
+			closedSymbols[localPos.Symbol] = true
 			continue
 		}
 
 		// Check if quantity is 0 or very small
-		qty := getFloatFromMap(exchangePos, "positionAmt")
-		if qty < 0 {
-			qty = -qty // Short position quantity is negative
-		}
-
-		if qty < 0.0000001 {
+		if exchangePos.Quantity < 0.0000001 {
 			// Quantity is 0, position closed
 			m.closeLocalPosition(localPos, trader, "manual")
+			closedSymbols[localPos.Symbol] = true
 		}
 	}
+
+	// Closed externally (liquidation, manual close, stop hit): the remaining TP/SL legs stay on the book
+	for symbol := range closedSymbols {
+		m.cancelClosedSymbolOrders(trader, symbol, exchangeMap)
+	}
 }
 
 // closeLocalPosition Mark local position as closed
@@ -210,6 +220,8 @@ func (m *PositionSyncManager) closeLocalPosition(pos *store.TraderPosition, trad
 		closeReason = reason
 		fee = 0
 		exitOrderID = ""
+		// Funding accrued while open (exchange closed P&L records already account for it where reported)
+		realizedPnL += pos.FundingFee
 		logger.Infof("⚠️  Using market price for closure (no exchange data): %s %s", pos.Symbol, pos.Side)
 	}
 
@@ -487,12 +499,15 @@ func (m *PositionSyncManager) getTraderConfig(traderID string) (*store.TraderFul
 
 // createTrader Create trader instance based on configuration
 func (m *PositionSyncManager) createTrader(config *store.TraderFullConfig) (Trader, error) {
-	exchange := config.Exchange
+	return NewExchangeTrader(config.Exchange, config.Trader.UserID)
+}
 
+// NewExchangeTrader Create exchange trader instance from a stored exchange account
+func NewExchangeTrader(exchange *store.Exchange, userID string) (Trader, error) {
 	// Use exchange.ExchangeType to determine specific exchange, not exchange.ID (UUID) or exchange.Type (cex/dex)
 	switch exchange.ExchangeType {
 	case "binance":
-		return NewFuturesTrader(exchange.APIKey, exchange.SecretKey, config.Trader.UserID), nil
+		return NewFuturesTrader(exchange.APIKey, exchange.SecretKey, userID), nil
 
 	case "bybit":
 		return NewBybitTrader(exchange.APIKey, exchange.SecretKey), nil
@@ -500,6 +515,12 @@ func (m *PositionSyncManager) createTrader(config *store.TraderFullConfig) (Trad
 	case "okx":
 		return NewOKXTrader(exchange.APIKey, exchange.SecretKey, exchange.Passphrase), nil
 
+	case "mexc":
+		return NewMEXCTrader(exchange.APIKey, exchange.SecretKey), nil
+
+	case "coinbase":
+		return NewCoinbaseTrader(exchange.APIKey, exchange.SecretKey, exchange.Passphrase), nil
+
 	case "hyperliquid":
 		return NewHyperliquidTrader(exchange.SecretKey, exchange.HyperliquidWalletAddr, exchange.Testnet)
 
@@ -531,25 +552,6 @@ func (m *PositionSyncManager) InvalidateCache(traderID string) {
 	delete(m.configCache, traderID)
 }
 
-// getFloatFromMap Get float64 value from map
-func getFloatFromMap(m map[string]interface{}, key string) float64 {
-	if v, ok := m[key]; ok {
-		switch val := v.(type) {
-		case float64:
-			return val
-		case int64:
-			return float64(val)
-		case int:
-			return float64(val)
-		case string:
-			var f float64
-			fmt.Sscanf(val, "%f", &f)
-			return f
-		}
-	}
-	return 0
-}
-
 // =============================================================================
 // Startup and History Sync Methods
 // =============================================================================
@@ -599,97 +601,8 @@ func (m *PositionSyncManager) startupSync() {
 // syncExternalPositions syncs positions that exist on exchange but not locally
 // These could be positions opened manually or from other systems
 func (m *PositionSyncManager) syncExternalPositions(traderID, exchangeID, exchangeType string, trader Trader) {
-	// Get current positions from exchange
-	exchangePositions, err := trader.GetPositions()
-	if err != nil {
-		logger.Infof("⚠️  Failed to get exchange positions for external sync (ID: %s): %v", traderID, err)
-		return
-	}
-
-	// Get local open positions
-	localPositions, err := m.store.Position().GetOpenPositions(traderID)
-	if err != nil {
-		logger.Infof("⚠️  Failed to get local positions for external sync (ID: %s): %v", traderID, err)
-		return
-	}
-
-	// Build local position map: symbol_side -> position
-	localMap := make(map[string]*store.TraderPosition)
-	for _, pos := range localPositions {
-		key := fmt.Sprintf("%s_%s", pos.Symbol, pos.Side)
-		localMap[key] = pos
-	}
-
-	// Find positions that exist on exchange but not locally
-	for _, pos := range exchangePositions {
-		symbol, _ := pos["symbol"].(string)
-		side, _ := pos["side"].(string)
-		if symbol == "" || side == "" {
-			continue
-		}
-
-		// Normalize side
-		normalizedSide := side
-		if side == "Buy" || side == "LONG" || side == "long" {
-			normalizedSide = "LONG"
-		} else if side == "Sell" || side == "SHORT" || side == "short" {
-			normalizedSide = "SHORT"
-		}
-
-		key := fmt.Sprintf("%s_%s", symbol, normalizedSide)
-
-		// Check if we already have this position locally
-		if _, exists := localMap[key]; exists {
-			continue // Already tracking this position
-		}
-
-		// This is an external position - create local record
-		qty := getFloatFromMap(pos, "positionAmt")
-		if qty < 0 {
-			qty = -qty
-		}
-		if qty < 0.0000001 {
-			continue // No actual position
-		}
-
-		entryPrice := getFloatFromMap(pos, "entryPrice")
-		leverage := int(getFloatFromMap(pos, "leverage"))
-		if leverage == 0 {
-			leverage = 1
-		}
-
-		// Get entry time if available
-		createdTime := getFloatFromMap(pos, "createdTime")
-		var entryTime time.Time
-		if createdTime > 0 {
-			entryTime = time.UnixMilli(int64(createdTime))
-		} else {
-			entryTime = time.Now() // Use current time as fallback
-		}
-
-		// Generate unique exchange position ID
-		exchangePositionID := fmt.Sprintf("%s_%s_%d", symbol, normalizedSide, entryTime.UnixMilli())
-
-		newPos := &store.TraderPosition{
-			TraderID:           traderID,
-			ExchangeID:         exchangeID,
-			ExchangeType:       exchangeType,
-			ExchangePositionID: exchangePositionID,
-			Symbol:             symbol,
-			Side:               normalizedSide,
-			Quantity:           qty,
-			EntryPrice:         entryPrice,
-			EntryTime:          entryTime,
-			Leverage:           leverage,
-			Source:             "sync", // Mark as synced from exchange
-		}
-
-		if err := m.store.Position().CreateOpenPosition(newPos); err != nil {
-			logger.Infof("⚠️  Failed to create external position record: %v", err)
-		} else {
-			logger.Infof("📊 Synced external position: [%s] %s %s @ %.4f (qty: %.4f)",
-				traderID[:8], symbol, normalizedSide, entryPrice, qty)
-		}
+	if _, err := importExchangePositions(m.store, traderID, exchangeID, exchangeType, trader, "sync"); err != nil {
+		logger.Infof("⚠️  External position sync failed (ID: %s): %v", traderID, err)
 	}
 }
 
@@ -697,17 +610,14 @@ func (m *PositionSyncManager) syncExternalPositions(traderID, exchangeID, exchan
 // IMPORTANT: Only exchanges with position-level history API should sync history:
 // - Bybit: /v5/position/closed-pnl (accurate position records)
 // - OKX: /api/v5/account/positions-history (accurate position records)
+// - MEXC: /api/v1/private/position/list/history_positions (accurate position records)
 // Other exchanges (Binance, Hyperliquid, Lighter, Aster) only have trade-level data,
 // which cannot accurately reconstruct positions. They should NOT sync historical positions.
 func (m *PositionSyncManager) syncClosedPositionsHistory(traderID, exchangeID, exchangeType string, trader Trader) {
 	// Only sync history for exchanges with position-level API
 	// Binance/Hyperliquid/Lighter/Aster only have trade-level data, skip history sync
-	switch exchangeType {
-	case "bybit", "okx":
-		// These exchanges have position-level history API, proceed with sync
-	default:
-		// Other exchanges don't have accurate position history API
-		// Their GetClosedPnL only returns recent trades for closure detection, not for history sync
+	// Their GetClosedPnL only returns recent trades for closure detection, not for history sync
+	if !hasPositionHistory(exchangeType) {
 		return
 	}
 
@@ -740,29 +650,8 @@ func (m *PositionSyncManager) syncClosedPositionsHistory(traderID, exchangeID, e
 		}
 
 		// Convert to store.ClosedPnLRecord and sync
-		storeRecords := make([]store.ClosedPnLRecord, len(closedRecords))
-		var latestExitTime time.Time
-		for i, rec := range closedRecords {
-			storeRecords[i] = store.ClosedPnLRecord{
-				Symbol:      rec.Symbol,
-				Side:        rec.Side,
-				EntryPrice:  rec.EntryPrice,
-				ExitPrice:   rec.ExitPrice,
-				Quantity:    rec.Quantity,
-				RealizedPnL: rec.RealizedPnL,
-				Fee:         rec.Fee,
-				Leverage:    rec.Leverage,
-				EntryTime:   rec.EntryTime,
-				ExitTime:    rec.ExitTime,
-				OrderID:     rec.OrderID,
-				CloseType:   rec.CloseType,
-				ExchangeID:  rec.ExchangeID,
-			}
-			// Track latest exit time for pagination
-			if rec.ExitTime.After(latestExitTime) {
-				latestExitTime = rec.ExitTime
-			}
-		}
+		storeRecords := toStoreClosedPnLRecords(closedRecords, "sync")
+		latestExitTime := latestExitTimeOf(closedRecords)
 
 		created, skipped, err := m.store.Position().SyncClosedPositions(traderID, exchangeID, exchangeType, storeRecords)
 		if err != nil {
@@ -803,3 +692,38 @@ func (m *PositionSyncManager) maybeRunHistorySync(traderID, exchangeID, exchange
 		m.syncClosedPositionsHistory(traderID, exchangeID, exchangeType, trader)
 	}
 }
+
+// toStoreClosedPnLRecords converts exchange closed PnL records for the position store
+func toStoreClosedPnLRecords(records []ClosedPnLRecord, source string) []store.ClosedPnLRecord {
+	storeRecords := make([]store.ClosedPnLRecord, len(records))
+	for i, rec := range records {
+		storeRecords[i] = store.ClosedPnLRecord{
+			Symbol:      rec.Symbol,
+			Side:        rec.Side,
+			EntryPrice:  rec.EntryPrice,
+			ExitPrice:   rec.ExitPrice,
+			Quantity:    rec.Quantity,
+			RealizedPnL: rec.RealizedPnL,
+			Fee:         rec.Fee,
+			Leverage:    rec.Leverage,
+			EntryTime:   rec.EntryTime,
+			ExitTime:    rec.ExitTime,
+			OrderID:     rec.OrderID,
+			CloseType:   rec.CloseType,
+			ExchangeID:  rec.ExchangeID,
+			Source:      source,
+		}
+	}
+	return storeRecords
+}
+
+// latestExitTimeOf returns the latest exit time of records (used for pagination)
+func latestExitTimeOf(records []ClosedPnLRecord) time.Time {
+	var latest time.Time
+	for _, rec := range records {
+		if rec.ExitTime.After(latest) {
+			latest = rec.ExitTime
+		}
+	}
+	return latest
+}
@@ -0,0 +1,206 @@
+package trader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"nofx/logger"
+	"strings"
+)
+
+// okxAmendAlgoPath amends the trigger price / size of a pending algo order
+const okxAmendAlgoPath = "/api/v5/trade/amend-algos"
+
+// errNoProtectionOrder the position has no open order of the requested kind to amend
+var errNoProtectionOrder = errors.New("no open protection order to amend")
+
+// ProtectionAmender optional capability for exchanges that can amend an open stop loss / take profit order in place
+// Unlike cancel + create the position stays protected throughout, and it costs one request instead of two
+type ProtectionAmender interface {
+	// ModifyStopLoss moves the position's stop loss order to stopPrice for quantity
+	ModifyStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error
+
+	// ModifyTakeProfit moves the position's take profit order to takeProfitPrice for quantity
+	ModifyTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error
+}
+
+// ModifyStopLoss amends the position's open stop loss order
+func (t *BybitTrader) ModifyStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	return t.amendConditionalOrder(symbol, positionSide, "StopLoss", quantity, stopPrice)
+}
+
+// ModifyTakeProfit amends the position's open take profit order
+func (t *BybitTrader) ModifyTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	return t.amendConditionalOrder(symbol, positionSide, "TakeProfit", quantity, takeProfitPrice)
+}
+
+// amendConditionalOrder amends the first open conditional order of orderType ("StopLoss"/"TakeProfit") closing positionSide
+func (t *BybitTrader) amendConditionalOrder(symbol, positionSide, orderType string, quantity, triggerPrice float64) error {
+	params := map[string]interface{}{
+		"category":    "linear",
+		"symbol":      symbol,
+		"orderFilter": "StopOrder",
+	}
+	result, err := t.client.NewUtaBybitServiceWithParams(params).GetOpenOrders(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get conditional orders: %w", err)
+	}
+	if result.RetCode != 0 {
+		return fmt.Errorf("failed to get conditional orders: %s", result.RetMsg)
+	}
+	resultData, _ := result.Result.(map[string]interface{})
+	list, _ := resultData["list"].([]interface{})
+
+	// Orders closing a long sell, orders closing a short buy
+	closeSide := "Sell"
+	if strings.ToUpper(positionSide) == "SHORT" {
+		closeSide = "Buy"
+	}
+	orderID := ""
+	for _, item := range list {
+		order, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		stopOrderType, _ := order["stopOrderType"].(string)
+		side, _ := order["side"].(string)
+		kind := stopOrderType
+		switch stopOrderType {
+		case "Stop":
+			kind = "StopLoss"
+		case "PartialTakeProfit":
+			kind = "TakeProfit"
+		}
+		if kind == orderType && side == closeSide {
+			orderID, _ = order["orderId"].(string)
+			break
+		}
+	}
+	if orderID == "" {
+		return errNoProtectionOrder
+	}
+
+	qtyStr, _ := t.FormatQuantity(symbol, quantity)
+	amendParams := map[string]interface{}{
+		"category":     "linear",
+		"symbol":       symbol,
+		"orderId":      orderID,
+		"qty":          qtyStr,
+		"triggerPrice": fmt.Sprintf("%v", triggerPrice),
+	}
+	amended, err := t.client.NewUtaBybitServiceWithParams(amendParams).AmendOrder(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to amend order: %w", err)
+	}
+	if amended.RetCode != 0 {
+		return fmt.Errorf("failed to amend order: %s", amended.RetMsg)
+	}
+
+	logger.Infof("  ✓ [Bybit] %s order amended: %s @ %v", orderType, symbol, triggerPrice)
+	return nil
+}
+
+// ModifyStopLoss amends the stop loss of the position's conditional or OCO algo order
+func (t *OKXTrader) ModifyStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	return t.amendAlgoOrder(symbol, positionSide, "sl", quantity, stopPrice)
+}
+
+// ModifyTakeProfit amends the take profit of the position's conditional or OCO algo order
+func (t *OKXTrader) ModifyTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	return t.amendAlgoOrder(symbol, positionSide, "tp", quantity, takeProfitPrice)
+}
+
+// amendAlgoOrder amends the first pending algo order of positionSide carrying a leg of kind ("sl"/"tp")
+func (t *OKXTrader) amendAlgoOrder(symbol, positionSide, kind string, quantity, triggerPrice float64) error {
+	instId := t.convertSymbol(symbol)
+	posSide := "long"
+	if strings.ToUpper(positionSide) == "SHORT" {
+		posSide = "short"
+	}
+
+	algoID := ""
+	for _, ordType := range []string{"conditional", "oco"} {
+		path := fmt.Sprintf("%s?instType=SWAP&instId=%s&ordType=%s", okxAlgoPendingPath, instId, ordType)
+		data, err := t.doRequest("GET", path, nil)
+		if err != nil {
+			return err
+		}
+		var pending []struct {
+			AlgoId      string `json:"algoId"`
+			PosSide     string `json:"posSide"`
+			SlTriggerPx string `json:"slTriggerPx"`
+			TpTriggerPx string `json:"tpTriggerPx"`
+		}
+		if err := json.Unmarshal(data, &pending); err != nil {
+			return err
+		}
+		for _, order := range pending {
+			trigger := order.SlTriggerPx
+			if kind == "tp" {
+				trigger = order.TpTriggerPx
+			}
+			if order.PosSide == posSide && trigger != "" {
+				algoID = order.AlgoId
+				break
+			}
+		}
+		if algoID != "" {
+			break
+		}
+	}
+	if algoID == "" {
+		return errNoProtectionOrder
+	}
+
+	inst, err := t.getInstrument(symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get instrument info: %w", err)
+	}
+	body := map[string]interface{}{
+		"instId": instId,
+		"algoId": algoID,
+		"newSz":  t.formatSize(quantity/inst.CtVal, inst),
+	}
+	if kind == "tp" {
+		body["newTpTriggerPx"] = fmt.Sprintf("%.8f", triggerPrice)
+		body["newTpOrdPx"] = "-1" // Market price
+	} else {
+		body["newSlTriggerPx"] = fmt.Sprintf("%.8f", triggerPrice)
+		body["newSlOrdPx"] = "-1" // Market price
+	}
+	if _, err := t.doRequest("POST", okxAmendAlgoPath, body); err != nil {
+		return fmt.Errorf("failed to amend algo order: %w", err)
+	}
+
+	logger.Infof("  Algo order %s amended: %s %.4f", algoID, kind, triggerPrice)
+	return nil
+}
+
+// amendProtection amends the position's open stop loss / take profit in place (prices ≤ 0 are left untouched)
+// Returns false when the exchange holding the position can't amend or an amend failed,
+// the caller then falls back to cancel + create
+func (at *AutoTrader) amendProtection(symbol, side string, quantity, stopLoss, takeProfit float64) bool {
+	amender, ok := capability[ProtectionAmender](at.trader, symbol, side)
+	if !ok || (stopLoss <= 0 && takeProfit <= 0) {
+		return false
+	}
+
+	if stopLoss > 0 {
+		if err := amender.ModifyStopLoss(symbol, side, quantity, stopLoss); err != nil {
+			if !errors.Is(err, errNoProtectionOrder) {
+				logger.Infof("  ⚠ Failed to amend %s %s stop loss, replacing it: %v", symbol, side, err)
+			}
+			return false
+		}
+	}
+	if takeProfit > 0 {
+		if err := amender.ModifyTakeProfit(symbol, side, quantity, takeProfit); err != nil {
+			if !errors.Is(err, errNoProtectionOrder) {
+				logger.Infof("  ⚠ Failed to amend %s %s take profit, replacing it: %v", symbol, side, err)
+			}
+			return false
+		}
+	}
+	return true
+}
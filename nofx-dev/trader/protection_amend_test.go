@@ -0,0 +1,51 @@
+package trader
+
+import (
+	"nofx/decision"
+	"reflect"
+	"testing"
+)
+
+// amendStub bracketStub that amends open stop orders in place (noTakeProfit: no take profit order is open)
+type amendStub struct {
+	bracketStub
+	noTakeProfit bool
+}
+
+func (s *amendStub) ModifyStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	s.calls = append(s.calls, "amend sl "+symbol+" "+positionSide)
+	return nil
+}
+
+func (s *amendStub) ModifyTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	if s.noTakeProfit {
+		return errNoProtectionOrder
+	}
+	s.calls = append(s.calls, "amend tp "+symbol+" "+positionSide)
+	return nil
+}
+
+// TestResizeProtectionAmends tests that resized protection is amended in place where possible,
+// and falls back to cancel + create when a leg has no order to amend
+func TestResizeProtectionAmends(t *testing.T) {
+	stub := &amendStub{}
+	at := &AutoTrader{trader: stub, brackets: brackets{legs: make(map[string]*bracket)}}
+	at.brackets.legs["BTCUSDT_long"] = &bracket{Symbol: "BTCUSDT", Side: "LONG", Quantity: 1, StopLoss: 90, TakeProfit: 110}
+
+	at.resizeProtection(&decision.Decision{Symbol: "BTCUSDT", StopLoss: 95}, "long", 2)
+	want := []string{"amend sl BTCUSDT LONG", "amend tp BTCUSDT LONG"}
+	if !reflect.DeepEqual(stub.calls, want) {
+		t.Errorf("expected calls %v, got %v", want, stub.calls)
+	}
+	if b := at.brackets.legs["BTCUSDT_long"]; b == nil || b.Quantity != 2 || b.StopLoss != 95 || b.TakeProfit != 110 {
+		t.Errorf("bracket not updated after amend: %+v", b)
+	}
+
+	stub.calls = nil
+	stub.noTakeProfit = true
+	at.resizeProtection(&decision.Decision{Symbol: "BTCUSDT"}, "long", 3)
+	want = []string{"amend sl BTCUSDT LONG", "cancel BTCUSDT", "sl BTCUSDT LONG", "tp BTCUSDT LONG"}
+	if !reflect.DeepEqual(stub.calls, want) {
+		t.Errorf("expected fallback calls %v, got %v", want, stub.calls)
+	}
+}
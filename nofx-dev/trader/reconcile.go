@@ -0,0 +1,298 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"nofx/logger"
+	"nofx/store"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// reconcileInterval how often believed positions are reconciled with the exchange
+const reconcileInterval = 2 * time.Minute
+
+// reconcileQtyTolerance relative quantity difference tolerated between local records and the exchange
+const reconcileQtyTolerance = 0.01
+
+// EventReconcileDiscrepancy reconciliation found local state out of sync with the exchange
+const EventReconcileDiscrepancy = "reconcile_discrepancy"
+
+// Discrepancy kinds
+const (
+	DiscrepancyPositionMissing   = "position_missing"   // Recorded open, not on the exchange
+	DiscrepancyPositionUntracked = "position_untracked" // On the exchange, no trader on the account records it
+	DiscrepancyQuantityMismatch  = "quantity_mismatch"  // Recorded and exchange quantity differ
+	DiscrepancyMissingStop       = "missing_stop"       // Position has a stop loss but no stop order on the exchange
+)
+
+// OpenOrder open conditional (stop loss / take profit) order
+type OpenOrder struct {
+	OrderID      string
+	Symbol       string
+	Kind         string // "stop_loss", "take_profit" or the exchange order type
+	PositionSide string // "LONG" / "SHORT", "BOTH" in one-way mode
+}
+
+// OpenOrderLister optional capability for exchanges that can list open conditional orders account-wide
+type OpenOrderLister interface {
+	GetOpenConditionalOrders() ([]OpenOrder, error)
+}
+
+// GetOpenConditionalOrders lists open stop / take profit orders of all symbols
+func (t *FuturesTrader) GetOpenConditionalOrders() ([]OpenOrder, error) {
+	if t.pm != nil {
+		orders, err := t.pm.NewUMOpenConditionalOrdersService().Do(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get open conditional orders: %w", err)
+		}
+		result := make([]OpenOrder, 0, len(orders))
+		for _, o := range orders {
+			result = append(result, OpenOrder{
+				OrderID:      strconv.FormatInt(o.StrategyID, 10),
+				Symbol:       o.Symbol,
+				Kind:         conditionalKind(o.StrategyType),
+				PositionSide: o.PositionSide,
+			})
+		}
+		return result, nil
+	}
+
+	// Fresh list, the cache may predate orders placed or filled since
+	t.algoOrders.invalidate()
+	orders, err := t.openConditionalOrders()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]OpenOrder, 0, len(orders))
+	for _, o := range orders {
+		result = append(result, OpenOrder{
+			OrderID:      strconv.FormatInt(o.OrderID, 10),
+			Symbol:       o.Symbol,
+			Kind:         conditionalKind(o.Type),
+			PositionSide: o.PositionSide,
+		})
+	}
+	return result, nil
+}
+
+// Discrepancy one difference between local state and the exchange
+type Discrepancy struct {
+	Kind        string
+	Symbol      string
+	Side        string // "long" / "short"
+	LocalQty    float64
+	ExchangeQty float64
+	StopLoss    float64 // Stop price to restore (missing_stop)
+	Repaired    bool
+	Detail      string
+}
+
+func (d Discrepancy) key() string {
+	return d.Kind + " " + d.Symbol + " " + d.Side
+}
+
+// reconcileState discrepancies already reported, so a persisting one is recorded once
+type reconcileState struct {
+	mu       sync.Mutex
+	reported map[string]string
+}
+
+// reconcileInput snapshot of believed and live state for findDiscrepancies
+type reconcileInput struct {
+	Own       []*store.TraderPosition // This trader's open records
+	Account   []*store.TraderPosition // Open records of every trader on the exchange account
+	Exchange  []Position              // Live positions of the account (managed legs only)
+	Orders    []OpenOrder             // Open conditional orders
+	HasOrders bool                    // Orders were listed (the exchange supports it and the call succeeded)
+	StopLoss  map[string]float64      // Tracked stop price by bracketKey
+}
+
+// findDiscrepancies compares believed positions and protective orders with the exchange
+func findDiscrepancies(in reconcileInput) []Discrepancy {
+	var result []Discrepancy
+
+	live := make(map[string]Position, len(in.Exchange))
+	for _, pos := range in.Exchange {
+		live[bracketKey(pos.Symbol, pos.Side)] = pos
+	}
+	recorded := make(map[string]float64)
+	for _, rec := range in.Account {
+		recorded[bracketKey(rec.Symbol, rec.Side)] += rec.Quantity
+	}
+
+	for _, rec := range in.Own {
+		key := bracketKey(rec.Symbol, rec.Side)
+		side := strings.ToLower(rec.Side)
+		pos, ok := live[key]
+		if !ok {
+			result = append(result, Discrepancy{
+				Kind: DiscrepancyPositionMissing, Symbol: rec.Symbol, Side: side, LocalQty: rec.Quantity,
+				Detail: fmt.Sprintf("%s %s recorded open (qty %.6f) but not held on the exchange", rec.Symbol, side, rec.Quantity),
+			})
+			continue
+		}
+		if stop := in.StopLoss[key]; stop > 0 && in.HasOrders && !hasStopOrder(in.Orders, rec.Symbol, side) {
+			result = append(result, Discrepancy{
+				Kind: DiscrepancyMissingStop, Symbol: rec.Symbol, Side: side, ExchangeQty: pos.Quantity, StopLoss: stop,
+				Detail: fmt.Sprintf("%s %s has no stop loss order on the exchange (expected %.6f)", rec.Symbol, side, stop),
+			})
+		}
+	}
+
+	for key, pos := range live {
+		localQty, ok := recorded[key]
+		if !ok {
+			result = append(result, Discrepancy{
+				Kind: DiscrepancyPositionUntracked, Symbol: pos.Symbol, Side: pos.Side, ExchangeQty: pos.Quantity,
+				Detail: fmt.Sprintf("%s %s held on the exchange (qty %.6f) but not recorded by any trader", pos.Symbol, pos.Side, pos.Quantity),
+			})
+			continue
+		}
+		if pos.Quantity > 0 && math.Abs(localQty-pos.Quantity)/pos.Quantity > reconcileQtyTolerance {
+			result = append(result, Discrepancy{
+				Kind: DiscrepancyQuantityMismatch, Symbol: pos.Symbol, Side: pos.Side, LocalQty: localQty, ExchangeQty: pos.Quantity,
+				Detail: fmt.Sprintf("%s %s recorded qty %.6f, exchange qty %.6f", pos.Symbol, pos.Side, localQty, pos.Quantity),
+			})
+		}
+	}
+
+	return result
+}
+
+// hasStopOrder whether a stop loss order protects the symbol's side (side "long"/"short")
+func hasStopOrder(orders []OpenOrder, symbol, side string) bool {
+	for _, o := range orders {
+		if o.Symbol != symbol || o.Kind != "stop_loss" {
+			continue
+		}
+		if o.PositionSide == "" || o.PositionSide == "BOTH" || strings.EqualFold(o.PositionSide, side) {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcilePositions compares this trader's believed positions with live exchange positions and open orders,
+// re-places missing stops and records every discrepancy. Missing positions and orphaned stop / take profit
+// orders are left to the position sync manager, which closes records with exchange fill data and cleans up orders
+func (at *AutoTrader) reconcilePositions() {
+	if at.store == nil {
+		return
+	}
+	own, err := at.store.Position().GetOpenPositions(at.id)
+	if err != nil {
+		logger.Infof("❌ Reconcile: failed to get local positions: %v", err)
+		return
+	}
+	all, err := at.store.Position().GetAllOpenPositions()
+	if err != nil {
+		logger.Infof("❌ Reconcile: failed to get local positions: %v", err)
+		return
+	}
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Infof("❌ Reconcile: failed to get positions: %v", err)
+		return
+	}
+
+	in := reconcileInput{StopLoss: make(map[string]float64)}
+	for _, rec := range own {
+		// Opened on the failover fallback exchange, not visible through this account
+		if rec.ExchangeID == "" || rec.ExchangeID == at.exchangeID {
+			in.Own = append(in.Own, rec)
+		}
+	}
+	for _, rec := range all {
+		if rec.ExchangeID == at.exchangeID {
+			in.Account = append(in.Account, rec)
+		}
+	}
+	for _, pos := range positions {
+		if !pos.ReadOnly && pos.Exchange == "" {
+			in.Exchange = append(in.Exchange, pos)
+		}
+	}
+	if lister, ok := capability[OpenOrderLister](at.trader, "", ""); ok {
+		if in.Orders, err = lister.GetOpenConditionalOrders(); err != nil {
+			logger.Infof("  ⚠ Reconcile: failed to get open orders: %v", err)
+		} else {
+			in.HasOrders = true
+		}
+	}
+	at.brackets.mu.Lock()
+	for key, b := range at.brackets.legs {
+		if !b.Native {
+			in.StopLoss[key] = b.StopLoss
+		}
+	}
+	at.brackets.mu.Unlock()
+
+	found := findDiscrepancies(in)
+	current := make(map[string]bool, len(found))
+	for _, d := range found {
+		if d.Kind == DiscrepancyMissingStop {
+			if err := at.trader.SetStopLoss(d.Symbol, strings.ToUpper(d.Side), d.ExchangeQty, d.StopLoss); err != nil {
+				d.Detail += fmt.Sprintf(", repair failed: %v", err)
+			} else {
+				d.Repaired = true
+				d.Detail += ", stop loss re-placed"
+			}
+		}
+		if !d.Repaired {
+			current[d.key()] = true
+		}
+		at.recordDiscrepancy(d)
+	}
+
+	// Forget resolved discrepancies so a recurrence is recorded again
+	at.reconcile.mu.Lock()
+	for key := range at.reconcile.reported {
+		if !current[key] {
+			delete(at.reconcile.reported, key)
+		}
+	}
+	at.reconcile.mu.Unlock()
+}
+
+// recordDiscrepancy logs a discrepancy and raises it as a structured event, once while it persists
+// Repairs are always recorded
+func (at *AutoTrader) recordDiscrepancy(d Discrepancy) {
+	at.reconcile.mu.Lock()
+	if !d.Repaired && at.reconcile.reported[d.key()] == d.Detail {
+		at.reconcile.mu.Unlock()
+		return
+	}
+	if !d.Repaired {
+		if at.reconcile.reported == nil {
+			at.reconcile.reported = make(map[string]string)
+		}
+		at.reconcile.reported[d.key()] = d.Detail
+	}
+	at.reconcile.mu.Unlock()
+
+	logger.Warnf("🔍 [%s] Reconcile %s: %s", at.name, d.Kind, d.Detail)
+	event := TraderEvent{
+		TraderID:   at.id,
+		Type:       EventReconcileDiscrepancy,
+		Exchange:   at.exchange,
+		ExchangeID: at.exchangeID,
+		Message:    d.Detail,
+		Time:       time.Now(),
+		Details: map[string]interface{}{
+			"kind":         d.Kind,
+			"symbol":       d.Symbol,
+			"side":         d.Side,
+			"local_qty":    d.LocalQty,
+			"exchange_qty": d.ExchangeQty,
+			"stop_loss":    d.StopLoss,
+			"repaired":     d.Repaired,
+		},
+	}
+	if at.config.OnEvent != nil {
+		at.config.OnEvent(event)
+	}
+}
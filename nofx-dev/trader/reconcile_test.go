@@ -0,0 +1,57 @@
+package trader
+
+import (
+	"nofx/store"
+	"testing"
+)
+
+// TestFindDiscrepancies tests detection of drift between local records, exchange positions and open orders
+func TestFindDiscrepancies(t *testing.T) {
+	own := []*store.TraderPosition{
+		{Symbol: "BTCUSDT", Side: "LONG", Quantity: 0.1},
+		{Symbol: "ETHUSDT", Side: "SHORT", Quantity: 2},
+		{Symbol: "SOLUSDT", Side: "LONG", Quantity: 10},
+	}
+	in := reconcileInput{
+		Own:     own,
+		Account: append(own, &store.TraderPosition{Symbol: "BNBUSDT", Side: "LONG", Quantity: 1}), // Another trader on the account
+		Exchange: []Position{
+			{Symbol: "BTCUSDT", Side: "long", Quantity: 0.1},
+			{Symbol: "ETHUSDT", Side: "short", Quantity: 1.5},
+			{Symbol: "BNBUSDT", Side: "long", Quantity: 1},
+			{Symbol: "XRPUSDT", Side: "short", Quantity: 100},
+		},
+		Orders: []OpenOrder{
+			{Symbol: "ETHUSDT", Kind: "stop_loss", PositionSide: "SHORT"},
+		},
+		HasOrders: true,
+		StopLoss:  map[string]float64{"BTCUSDT_long": 90000, "ETHUSDT_short": 4000},
+	}
+
+	got := make(map[string]Discrepancy)
+	for _, d := range findDiscrepancies(in) {
+		got[d.key()] = d
+	}
+	want := []string{
+		"missing_stop BTCUSDT long",
+		"quantity_mismatch ETHUSDT short",
+		"position_missing SOLUSDT long",
+		"position_untracked XRPUSDT short",
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected %d discrepancies, got %v", len(want), got)
+	}
+	for _, key := range want {
+		if _, ok := got[key]; !ok {
+			t.Errorf("missing discrepancy %q", key)
+		}
+	}
+
+	// Without an order listing, stop checks are skipped
+	in.Orders, in.HasOrders = nil, false
+	for _, d := range findDiscrepancies(in) {
+		if d.Kind == DiscrepancyMissingStop {
+			t.Errorf("unexpected %s without open orders", d.Kind)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+	"nofx/config"
+	"nofx/logger"
+	"strings"
+)
+
+// reduceOnlyEpsilon relative excess over the live size still treated as equal (float formatting noise)
+const reduceOnlyEpsilon = 1e-9
+
+// ErrReduceOnlyViolation a close / stop-loss / take-profit quantity exceeds the live position
+var ErrReduceOnlyViolation = errors.New("reduce-only violation")
+
+// reduceOnlyAutoCorrect whether oversized close quantities are corrected to the live size instead of rejected
+func reduceOnlyAutoCorrect() bool {
+	return config.Get().ReduceOnlyAutoCorrect
+}
+
+// ReduceOnlyTrader wraps a Trader so closes, stop-losses and take-profits never exceed the live position.
+// Adapters already send them reduce-only where the exchange supports it; this catches quantities that
+// drifted from the position (partial fills, funding-sized adjustments, stale local records) before they reach
+// exchanges where an oversized close would flip the position instead of being rejected
+type ReduceOnlyTrader struct {
+	Trader
+	autoCorrect bool // Clamp to the live size (true) or reject (false)
+}
+
+// NewReduceOnlyTrader creates a close-quantity validating wrapper around inner
+func NewReduceOnlyTrader(inner Trader, autoCorrect bool) *ReduceOnlyTrader {
+	return &ReduceOnlyTrader{Trader: inner, autoCorrect: autoCorrect}
+}
+
+// Unwrap returns the wrapped trader
+func (r *ReduceOnlyTrader) Unwrap() Trader {
+	return r.Trader
+}
+
+// livePositionSize returns the current size of symbol/side ("long"/"short") read past adapter caches, 0 if none
+func livePositionSize(t Trader, symbol, side string) (float64, error) {
+	if c, ok := unwrapTrader(t).(cacheInvalidator); ok {
+		c.invalidateCaches()
+	}
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, err
+	}
+	pos, _ := findPosition(positions, symbol, side)
+	return pos.Quantity, nil
+}
+
+// checkQuantity validates quantity against the live position, returning the quantity to send
+// Protective orders (stop-loss / take-profit) are never rejected: they may be placed before a fresh position
+// shows up, and refusing them would leave the position unprotected, so they're left to the exchange's reduce-only flag
+func (r *ReduceOnlyTrader) checkQuantity(op, symbol, side string, quantity float64, protective bool) (float64, error) {
+	live, err := livePositionSize(r.Trader, symbol, side)
+	if err != nil {
+		if protective {
+			return quantity, nil
+		}
+		return 0, fmt.Errorf("%s %s: failed to read live position: %w", op, symbol, err)
+	}
+	if live <= 0 {
+		if protective {
+			return quantity, nil
+		}
+		return 0, fmt.Errorf("%w: %s %s with no live %s position", ErrReduceOnlyViolation, op, symbol, side)
+	}
+	if quantity <= live*(1+reduceOnlyEpsilon) {
+		return quantity, nil
+	}
+	switch {
+	case r.autoCorrect:
+		logger.Warnf("⚠️ %s %s quantity %.8g exceeds live %s position, corrected to %.8g", op, symbol, quantity, side, live)
+		return live, nil
+	case protective:
+		logger.Warnf("⚠️ %s %s quantity %.8g exceeds live %s position %.8g", op, symbol, quantity, side, live)
+		return quantity, nil
+	default:
+		return 0, fmt.Errorf("%w: %s %s quantity %.8g exceeds live %s position %.8g", ErrReduceOnlyViolation, op, symbol, quantity, side, live)
+	}
+}
+
+// CloseLong closes a long position, quantity checked against the live position (0 = close all, left to the adapter)
+func (r *ReduceOnlyTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity > 0 {
+		var err error
+		if quantity, err = r.checkQuantity("CloseLong", symbol, "long", quantity, false); err != nil {
+			return nil, err
+		}
+	}
+	return r.Trader.CloseLong(symbol, quantity)
+}
+
+// CloseShort closes a short position, quantity checked against the live position (0 = close all, left to the adapter)
+func (r *ReduceOnlyTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity > 0 {
+		var err error
+		if quantity, err = r.checkQuantity("CloseShort", symbol, "short", quantity, false); err != nil {
+			return nil, err
+		}
+	}
+	return r.Trader.CloseShort(symbol, quantity)
+}
+
+// SetStopLoss places a stop-loss no larger than the live position
+func (r *ReduceOnlyTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	quantity, err := r.checkQuantity("SetStopLoss", symbol, strings.ToLower(positionSide), quantity, true)
+	if err != nil {
+		return err
+	}
+	return r.Trader.SetStopLoss(symbol, positionSide, quantity, stopPrice)
+}
+
+// SetTakeProfit places a take-profit no larger than the live position
+func (r *ReduceOnlyTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	quantity, err := r.checkQuantity("SetTakeProfit", symbol, strings.ToLower(positionSide), quantity, true)
+	if err != nil {
+		return err
+	}
+	return r.Trader.SetTakeProfit(symbol, positionSide, quantity, takeProfitPrice)
+}
+
+// OpenWithProtection opens a position with its stop loss / take profit in one batch (entries aren't checked)
+func (r *ReduceOnlyTrader) OpenWithProtection(symbol, positionSide string, quantity float64, leverage int, stopLoss, takeProfit float64) (map[string]interface{}, bool, error) {
+	placer, ok := batchEntryPlacer(r.Trader)
+	if !ok {
+		return nil, false, ErrBatchUnsupported
+	}
+	return placer.OpenWithProtection(symbol, positionSide, quantity, leverage, stopLoss, takeProfit)
+}
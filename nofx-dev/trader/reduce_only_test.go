@@ -0,0 +1,94 @@
+package trader
+
+import (
+	"errors"
+	"testing"
+)
+
+// sizedExchange Trader holding one long BTCUSDT position, recording the quantities orders were sent with
+type sizedExchange struct {
+	Trader
+	size  float64
+	sent  []float64
+	stops []float64
+}
+
+func (s *sizedExchange) GetPositions() ([]Position, error) {
+	if s.size <= 0 {
+		return nil, nil
+	}
+	return []Position{{Symbol: "BTCUSDT", Side: "long", Quantity: s.size}}, nil
+}
+
+func (s *sizedExchange) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	s.sent = append(s.sent, quantity)
+	return map[string]interface{}{"orderId": int64(len(s.sent))}, nil
+}
+
+func (s *sizedExchange) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	s.stops = append(s.stops, quantity)
+	return nil
+}
+
+// TestReduceOnlyClose tests close quantities are checked against the live position
+func TestReduceOnlyClose(t *testing.T) {
+	tests := []struct {
+		name        string
+		size        float64
+		quantity    float64
+		autoCorrect bool
+		wantSent    float64
+		wantErr     bool
+	}{
+		{"within position", 2, 1, false, 1, false},
+		{"exact size", 2, 2, false, 2, false},
+		{"close all", 2, 0, false, 0, false},
+		{"drifted, corrected", 2, 2.5, true, 2, false},
+		{"drifted, rejected", 2, 2.5, false, 0, true},
+		{"no position", 0, 1, true, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exchange := &sizedExchange{size: tt.size}
+			_, err := NewReduceOnlyTrader(exchange, tt.autoCorrect).CloseLong("BTCUSDT", tt.quantity)
+			if tt.wantErr {
+				if !errors.Is(err, ErrReduceOnlyViolation) {
+					t.Fatalf("expected reduce-only violation, got %v", err)
+				}
+				if len(exchange.sent) != 0 {
+					t.Errorf("rejected close reached the exchange: %v", exchange.sent)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(exchange.sent) != 1 || exchange.sent[0] != tt.wantSent {
+				t.Errorf("expected close of %v, sent %v", tt.wantSent, exchange.sent)
+			}
+		})
+	}
+}
+
+// TestReduceOnlyStopLoss tests protective orders are corrected but never rejected
+func TestReduceOnlyStopLoss(t *testing.T) {
+	exchange := &sizedExchange{size: 2}
+	if err := NewReduceOnlyTrader(exchange, true).SetStopLoss("BTCUSDT", "LONG", 3, 90); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := NewReduceOnlyTrader(exchange, false).SetStopLoss("BTCUSDT", "LONG", 3, 90); err != nil {
+		t.Fatalf("stop-loss should not be rejected, got %v", err)
+	}
+	// Placed right after the entry, before the position shows up
+	exchange.size = 0
+	if err := NewReduceOnlyTrader(exchange, false).SetStopLoss("BTCUSDT", "LONG", 1, 90); err != nil {
+		t.Fatalf("stop-loss should not be rejected, got %v", err)
+	}
+
+	want := []float64{2, 3, 1}
+	for i, q := range want {
+		if exchange.stops[i] != q {
+			t.Errorf("stop %d: expected quantity %v, got %v", i, q, exchange.stops[i])
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package trader
+
+import (
+	"encoding/json"
+	"fmt"
+	"nofx/config"
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/store"
+)
+
+// reflectOnDecisions runs the reflection pass (reflection_pass flag): the trader's AI model reviews the proposed
+// entries and its vetoes / downsizes replace aiDecision.Decisions. The proposed decisions stay in
+// record.DecisionJSON, the review is saved next to them. When the pass fails the proposed decisions are executed,
+// rule-based fallback and decision provider decisions aren't reviewed
+func (at *AutoTrader) reflectOnDecisions(ctx *decision.Context, aiDecision *decision.FullDecision, record *store.DecisionRecord) {
+	if !at.FeatureEnabled(config.FlagReflectionPass) || record.Fallback != "" || record.Provider != "" {
+		return
+	}
+	reflection, err := decision.Reflect(at.mcpClient, at.strategyEngine, ctx, aiDecision.Decisions)
+	if reflection != nil {
+		record.ReflectionResponse = reflection.Response
+		record.PromptTokens += reflection.Usage.PromptTokens
+		record.CompletionTokens += reflection.Usage.CompletionTokens
+		record.AICostUSD += reflection.Usage.CostUSD
+		record.ModelUsage = append(record.ModelUsage, modelUsage(at.aiModel, reflection.Usage))
+	}
+	if err != nil {
+		note := fmt.Sprintf("⚠️ Reflection pass failed, executing the proposed decisions: %v", err)
+		logger.Warnf("  %s", note)
+		record.ExecutionLog = append(record.ExecutionLog, note)
+		return
+	}
+	if reflection == nil {
+		return
+	}
+
+	verdicts, _ := json.MarshalIndent(reflection.Verdicts, "", "  ")
+	record.ReflectionJSON = string(verdicts)
+	notes := reflection.Notes()
+	if len(notes) == 0 {
+		notes = []string{fmt.Sprintf("🪞 Reflection approved all %d proposed entries", len(reflection.Verdicts))}
+	}
+	for _, note := range notes {
+		logger.Infof("  %s", note)
+	}
+	record.ExecutionLog = append(record.ExecutionLog, notes...)
+	aiDecision.Decisions = reflection.Decisions
+}
@@ -0,0 +1,72 @@
+package trader
+
+import (
+	"nofx/vcr"
+	"testing"
+)
+
+// Recorded exchange traffic, replayed offline (VCR_RECORD=1 re-records against the live exchange)
+// Incident payloads can be added by editing the cassettes' response bodies
+
+// TestBinanceFuturesReplay tests account and position parsing against recorded Binance futures responses
+func TestBinanceFuturesReplay(t *testing.T) {
+	vcr.Use(t, "testdata/cassettes/binance_futures_account.json")
+	trader := NewFuturesTrader("test-api-key", "test-secret-key", "replay")
+	if trader.IsPortfolioMargin() {
+		t.Fatal("expected a classic futures account")
+	}
+
+	balance, err := trader.GetBalance()
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if balance["totalWalletBalance"] != 1250.5 || balance["availableBalance"] != 980.25 {
+		t.Errorf("unexpected balance: %v", balance)
+	}
+	if quotes, _ := balance["quoteBalances"].(map[string]float64); quotes["USDC"] != 100 {
+		t.Errorf("expected USDC availability from the assets list, got %v", balance["quoteBalances"])
+	}
+
+	positions, err := trader.GetPositions()
+	if err != nil {
+		t.Fatalf("GetPositions: %v", err)
+	}
+	if len(positions) != 2 {
+		t.Fatalf("expected 2 open positions (flat entries skipped), got %+v", positions)
+	}
+	short := positions[1]
+	if short.Symbol != "ETHUSDT" || short.Side != "short" || short.Quantity != 0.5 || short.Leverage != 5 || short.MarginMode != "isolated" {
+		t.Errorf("unexpected short position: %+v", short)
+	}
+}
+
+// TestHyperliquidReplay tests account and position parsing against recorded Hyperliquid responses
+func TestHyperliquidReplay(t *testing.T) {
+	vcr.Use(t, "testdata/cassettes/hyperliquid_account.json")
+	// Well-known development key, the recorded agent wallet is empty
+	trader, err := NewHyperliquidTrader("0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+		"0x70997970C51812dc3A010C7d01b50e0d17dc79C8", false)
+	if err != nil {
+		t.Fatalf("NewHyperliquidTrader: %v", err)
+	}
+
+	balance, err := trader.GetBalance()
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	// Perp equity 2000 minus 35.5 unrealized, plus 150 USDC in spot
+	if balance["totalWalletBalance"] != 2114.5 || balance["totalUnrealizedProfit"] != 35.5 || balance["spotBalance"] != 150.0 {
+		t.Errorf("unexpected balance: %v", balance)
+	}
+
+	positions, err := trader.GetPositions()
+	if err != nil {
+		t.Fatalf("GetPositions: %v", err)
+	}
+	if len(positions) != 1 {
+		t.Fatalf("expected 1 position, got %+v", positions)
+	}
+	if p := positions[0]; p.Symbol != "BTCUSDT" || p.Side != "long" || p.Quantity != 0.01 || p.Leverage != 10 {
+		t.Errorf("unexpected position: %+v", p)
+	}
+}
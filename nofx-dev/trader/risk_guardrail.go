@@ -0,0 +1,59 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"sync"
+)
+
+// riskGuardrailState per-trader limits AI entries are clamped to
+type riskGuardrailState struct {
+	mu        sync.RWMutex
+	guardrail store.RiskGuardrail
+}
+
+// ValidateRiskGuardrail checks the limits of a risk guardrail
+func ValidateRiskGuardrail(g store.RiskGuardrail) error {
+	if g.MaxLeverage < 0 || g.MaxPositionSizeUSD < 0 || g.MaxPositionEquityPct < 0 || g.MinStopDistancePct < 0 || g.MaxStopDistancePct < 0 {
+		return fmt.Errorf("risk guardrail limits cannot be negative")
+	}
+	if g.MaxStopDistancePct >= 100 {
+		return fmt.Errorf("max stop distance must be below 100%%: %.2f", g.MaxStopDistancePct)
+	}
+	if g.MaxStopDistancePct > 0 && g.MinStopDistancePct > g.MaxStopDistancePct {
+		return fmt.Errorf("min stop distance %.2f%% is above the max stop distance %.2f%%", g.MinStopDistancePct, g.MaxStopDistancePct)
+	}
+	return nil
+}
+
+// SetRiskGuardrail updates the trader's risk guardrail, applied from the next cycle
+func (at *AutoTrader) SetRiskGuardrail(g store.RiskGuardrail) error {
+	if err := ValidateRiskGuardrail(g); err != nil {
+		return err
+	}
+	at.guardrail.mu.Lock()
+	at.guardrail.guardrail = g
+	at.guardrail.mu.Unlock()
+	return nil
+}
+
+// riskGuardrail the trader's current risk guardrail
+func (at *AutoTrader) riskGuardrail() store.RiskGuardrail {
+	at.guardrail.mu.RLock()
+	defer at.guardrail.mu.RUnlock()
+	return at.guardrail.guardrail
+}
+
+// applyRiskGuardrail clamps the leverage, size and stop distance of entries to the trader's guardrail, recording
+// the original and clamped values on the decision record
+func (at *AutoTrader) applyRiskGuardrail(decisions []decision.Decision, marketData map[string]*market.Data, equity float64, record *store.DecisionRecord) {
+	clamps, notes := decision.ClampToGuardrail(decisions, marketData, equity, at.riskGuardrail())
+	record.Clamps = append(record.Clamps, clamps...)
+	for _, note := range notes {
+		logger.Infof("  %s", note)
+	}
+	record.ExecutionLog = append(record.ExecutionLog, notes...)
+}
@@ -0,0 +1,33 @@
+package trader
+
+import (
+	"nofx/decision"
+	"nofx/logger"
+)
+
+// applyRiskSizing replaces the AI's position size with the size that loses risk_per_trade_pct of equity at the stop
+// Missing or invalid stops keep the AI's size; the usual caps still apply afterwards and the size never exceeds the
+// trader's risk guardrail
+func (at *AutoTrader) applyRiskSizing(d *decision.Decision, entry, equity float64) {
+	if at.config.StrategyConfig == nil {
+		return
+	}
+	riskPct := at.config.StrategyConfig.RiskControl.RiskPerTradePct
+	if riskPct <= 0 {
+		return
+	}
+	qty, err := decision.RiskQuantity(entry, d.StopLoss, equity, riskPct)
+	if err != nil {
+		logger.Infof("  ⚠️ Risk sizing skipped for %s: %v, keeping %.2f USDT", d.Symbol, err, d.PositionSizeUSD)
+		return
+	}
+	size := qty * entry
+	if limit := decision.GuardrailMaxPositionUSD(at.riskGuardrail(), equity); limit > 0 && size > limit {
+		logger.Infof("  🛡️ Risk sizing %.2f USDT capped by the risk guardrail at %.2f USDT", size, limit)
+		size = limit
+	}
+	logger.Infof("  📏 Risk sizing: %.2f%% of %.2f equity over stop %.6g → position %.2f USDT (AI: %.2f)",
+		riskPct, equity, d.StopLoss, size, d.PositionSizeUSD)
+	d.PositionSizeUSD = size
+	d.RiskUSD = equity * riskPct / 100
+}
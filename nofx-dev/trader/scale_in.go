@@ -0,0 +1,231 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/decision"
+	"nofx/events"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"time"
+)
+
+// findPosition returns the open position of symbol/side ("long"/"short")
+func findPosition(positions []Position, symbol, side string) (Position, bool) {
+	for _, pos := range positions {
+		if pos.Symbol == symbol && pos.Side == side && pos.Quantity > 0 {
+			return pos, true
+		}
+	}
+	return Position{}, false
+}
+
+// weightedEntryPrice average entry of a position after adding addQty at addPrice
+func weightedEntryPrice(qty, entryPrice, addQty, addPrice float64) float64 {
+	if qty+addQty <= 0 {
+		return 0
+	}
+	return (qty*entryPrice + addQty*addPrice) / (qty + addQty)
+}
+
+// scaleInSize caps the added notional so the whole position stays within the position value ratio
+// Returns 0 if the existing position already uses the full allowance
+func (at *AutoTrader) scaleInSize(addUSD, existingUSD, equity float64, symbol string) float64 {
+	total, capped := at.enforcePositionValueRatio(existingUSD+addUSD, equity, symbol)
+	if !capped {
+		return addUSD
+	}
+	if total <= existingUSD {
+		return 0
+	}
+	return total - existingUSD
+}
+
+// executeAddWithRecord scales into an existing position (add_long/add_short) and records detailed information
+// side is "long" or "short"
+func (at *AutoTrader) executeAddWithRecord(d *decision.Decision, actionRecord *store.DecisionAction, side string) error {
+	logger.Infof("  ➕ Add %s: %s", side, d.Symbol)
+
+	// Primary exchange failing without a usable fallback: no new entries
+	if err := at.entriesAllowed(); err != nil {
+		return err
+	}
+
+	// [CODE ENFORCED] No adding around high-impact events (FOMC, CPI, token unlocks)
+	if err := at.checkEventPause(events.Default(), d.Symbol, time.Now()); err != nil {
+		return err
+	}
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		return fmt.Errorf("failed to get positions: %w", err)
+	}
+	existing, ok := findPosition(positions, d.Symbol, side)
+	if !ok {
+		return fmt.Errorf("❌ %s has no %s position to add to, open it first", d.Symbol, side)
+	}
+	if existing.ReadOnly {
+		return fmt.Errorf("❌ %s %s position is read-only while its exchange is unavailable", d.Symbol, side)
+	}
+	if existing.Exchange != "" {
+		// New entries are routed by exchange health, the add could land on a different exchange
+		return fmt.Errorf("❌ %s %s position is held on fallback exchange %s, adding is not supported", d.Symbol, side, existing.Exchange)
+	}
+	// [CODE ENFORCED] No adding to a position near liquidation
+	if err := at.enforceLiquidationGuard(positions, d.Symbol, side); err != nil {
+		return err
+	}
+
+	// Keep the position's leverage, the exchange applies one leverage per symbol
+	leverage := int(existing.Leverage)
+	if leverage <= 0 {
+		leverage = d.Leverage
+	}
+	if leverage <= 0 {
+		leverage = 10
+	}
+	d.Leverage = leverage
+
+	marketData, err := market.Get(d.Symbol)
+	if err != nil {
+		return err
+	}
+
+	balance, err := at.trader.GetBalance()
+	if err != nil {
+		return fmt.Errorf("failed to get account balance: %w", err)
+	}
+	availableBalance := availableForSymbol(balance, d.Symbol)
+
+	equity := 0.0
+	if eq, ok := balance["totalEquity"].(float64); ok && eq > 0 {
+		equity = eq
+	} else if eq, ok := balance["totalWalletBalance"].(float64); ok && eq > 0 {
+		equity = eq
+	} else {
+		equity = availableBalance // Fallback to available balance
+	}
+
+	// [CODE ENFORCED] Position value ratio applies to the whole position after the add
+	existingValue := existing.Quantity * marketData.CurrentPrice
+	d.PositionSizeUSD = at.scaleInSize(d.PositionSizeUSD, existingValue, equity, d.Symbol)
+	if d.PositionSizeUSD <= 0 {
+		return fmt.Errorf("❌ [RISK CONTROL] %s %s position already at max position value", d.Symbol, side)
+	}
+
+	// [CODE ENFORCED] Liquidity cap on the added order
+	adjusted, capped, err := at.enforceLiquidityCap(d.PositionSizeUSD, d.Symbol)
+	if err != nil {
+		return err
+	}
+	if capped {
+		d.PositionSizeUSD = adjusted
+	}
+
+	// Auto-adjust add size if insufficient margin (same formula as opening)
+	marginFactor := 1.01/float64(leverage) + 0.001
+	maxAffordablePositionSize := availableBalance / marginFactor
+	if d.PositionSizeUSD > maxAffordablePositionSize {
+		adjustedSize := maxAffordablePositionSize * 0.98
+		logger.Infof("  ⚠️ Add size %.2f exceeds max affordable %.2f, auto-reducing to %.2f",
+			d.PositionSizeUSD, maxAffordablePositionSize, adjustedSize)
+		d.PositionSizeUSD = adjustedSize
+	}
+
+	// [CODE ENFORCED] Exposure caps: total / per-symbol / user-wide notional after the add
+	if err := at.enforceExposureLimits(d.Symbol, d.PositionSizeUSD); err != nil {
+		return err
+	}
+
+	// [CODE ENFORCED] Minimum position size check
+	if err := at.enforceMinPositionSize(d.PositionSizeUSD); err != nil {
+		return err
+	}
+
+	quantity := d.PositionSizeUSD / marketData.CurrentPrice
+	actionRecord.Quantity = quantity
+	actionRecord.Price = marketData.CurrentPrice
+	actionRecord.Leverage = leverage
+
+	var order map[string]interface{}
+	if side == "long" {
+		order, err = at.trader.OpenLong(d.Symbol, quantity, leverage)
+	} else {
+		order, err = at.trader.OpenShort(d.Symbol, quantity, leverage)
+	}
+	if err != nil {
+		return err
+	}
+
+	if orderID, ok := order["orderId"].(int64); ok {
+		actionRecord.OrderID = orderID
+	}
+	logger.Infof("  ✓ Added to position, order ID: %v, quantity: %.4f (total %.4f)",
+		order["orderId"], quantity, existing.Quantity+quantity)
+
+	// Record order to database (weighted-average entry) and poll for confirmation
+	at.recordAndConfirmOrder(order, d.Symbol, "add_"+side, quantity, marketData.CurrentPrice, leverage, 0)
+
+	// Protection orders carry a fixed quantity, re-place them for the whole position
+	at.resizeProtection(d, side, existing.Quantity+quantity)
+
+	// Entry moved, let the break-even trigger re-evaluate against the averaged entry
+	at.breakEven.mu.Lock()
+	delete(at.breakEven.moved, bracketKey(d.Symbol, side))
+	at.breakEven.mu.Unlock()
+	return nil
+}
+
+// resizeProtection re-places stop loss / take profit (and a native trailing stop) after the position size changed
+// Prices from the decision replace the current bracket's, missing ones are kept
+func (at *AutoTrader) resizeProtection(d *decision.Decision, side string, quantity float64) {
+	upperSide := strings.ToUpper(side)
+	key := bracketKey(d.Symbol, side)
+
+	stopLoss, takeProfit := d.StopLoss, d.TakeProfit
+	native := false
+	at.brackets.mu.Lock()
+	if b, ok := at.brackets.legs[key]; ok {
+		if stopLoss <= 0 {
+			stopLoss = b.StopLoss
+		}
+		if takeProfit <= 0 {
+			takeProfit = b.TakeProfit
+		}
+		native = b.Native
+		delete(at.brackets.legs, key)
+	}
+	at.brackets.mu.Unlock()
+
+	if stopLoss <= 0 && takeProfit <= 0 {
+		logger.Infof("  ⚠ %s %s has no stop loss / take profit to resize", d.Symbol, side)
+		return
+	}
+
+	// Emulated trailing stops close the whole position already; a native one needs re-placing at the new size
+	at.trailing.mu.Lock()
+	_, emulated := at.trailing.stops[d.Symbol+"_"+side]
+	at.trailing.mu.Unlock()
+	trailingPct := at.trailingStopPct(d)
+
+	// Amend both legs in place where the exchange supports it, nothing else on the symbol is touched
+	if (trailingPct <= 0 || emulated) && at.amendProtection(d.Symbol, upperSide, quantity, stopLoss, takeProfit) {
+		at.trackBracket(&bracket{Symbol: d.Symbol, Side: upperSide, Quantity: quantity, StopLoss: stopLoss, TakeProfit: takeProfit, Native: native})
+		logger.Infof("  🔁 Protection amended for %s %s: quantity %.4f, stop loss %.4f, take profit %.4f",
+			d.Symbol, side, quantity, stopLoss, takeProfit)
+		return
+	}
+
+	// Cancels the symbol's stop orders and restores the opposite side's bracket
+	at.cancelBracketOrders(d.Symbol)
+	at.setBracket(d.Symbol, upperSide, quantity, stopLoss, takeProfit)
+
+	// A native trailing stop was cancelled above
+	if trailingPct > 0 && !emulated {
+		at.setTrailingStop(d.Symbol, upperSide, quantity, trailingPct)
+	}
+
+	logger.Infof("  🔁 Protection resized for %s %s: quantity %.4f, stop loss %.4f, take profit %.4f",
+		d.Symbol, side, quantity, stopLoss, takeProfit)
+}
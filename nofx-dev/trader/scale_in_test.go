@@ -0,0 +1,40 @@
+package trader
+
+import (
+	"math"
+	"nofx/store"
+	"testing"
+)
+
+// TestWeightedEntryPrice tests the averaged entry after a scale-in
+func TestWeightedEntryPrice(t *testing.T) {
+	// 1 @ 100 + 3 @ 120 = 460 / 4
+	if got := weightedEntryPrice(1, 100, 3, 120); math.Abs(got-115) > 1e-9 {
+		t.Errorf("expected 115, got %.6f", got)
+	}
+	if got := weightedEntryPrice(0, 0, 0, 120); got != 0 {
+		t.Errorf("expected 0 for empty position, got %.6f", got)
+	}
+}
+
+// TestScaleInSize tests the add is capped so the whole position stays within the position value ratio
+func TestScaleInSize(t *testing.T) {
+	at := &AutoTrader{config: AutoTraderConfig{StrategyConfig: &store.StrategyConfig{}}}
+	at.config.StrategyConfig.RiskControl.AltcoinMaxPositionValueRatio = 1
+
+	tests := []struct {
+		name                string
+		add, existing, want float64
+	}{
+		{"within limit", 200, 500, 200},
+		{"capped to remaining allowance", 800, 500, 500},
+		{"already at limit", 100, 1000, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := at.scaleInSize(tt.add, tt.existing, 1000, "SOLUSDT"); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("expected %.2f, got %.2f", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/store"
+	"time"
+)
+
+// Shutdown order of a trader (SIGTERM, or the manager stopping it):
+//  1. Stop accepting cycles: the main loop exits, no new decision cycle starts
+//  2. Cancel LLM calls: an in-flight AI request is abandoned, the cycle's decision record is saved as failed
+//  3. Settle orders: the running cycle finishes its close / hold decisions but skips new entries,
+//     the monitors finish their current check
+//  4. Flush: held notifications go out as a digest, pending webhook deliveries complete and a final
+//     equity point is saved
+// The store is closed by the caller once every trader has been shut down
+
+// errShuttingDown returned for AI requests and entries cut short by a shutdown
+var errShuttingDown = errors.New("trader is shutting down")
+
+// shuttingDown reports whether the trader has been told to stop
+func (at *AutoTrader) shuttingDown() bool {
+	select {
+	case <-at.stopMonitorCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// requestDecision runs the AI decision request, abandoning it when the trader is stopped meanwhile
+// The AI clients can't be cancelled, the request finishes in the background and its result is dropped
+func (at *AutoTrader) requestDecision(call func() (*decision.FullDecision, error)) (*decision.FullDecision, error) {
+	type result struct {
+		decision *decision.FullDecision
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		d, err := call()
+		done <- result{d, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.decision, r.err
+	case <-at.stopMonitorCh:
+		logger.Infof("⏹ [%s] AI request abandoned, trader is shutting down", at.name)
+		return nil, errShuttingDown
+	}
+}
+
+// checkShutdownEntry blocks decisions that would open or add to a position once shutdown has started,
+// closes still run so the cycle leaves the account settled
+func (at *AutoTrader) checkShutdownEntry(action string) error {
+	switch action {
+	case "open_long", "open_short", "add_long", "add_short":
+		if at.shuttingDown() {
+			return errShuttingDown
+		}
+	}
+	return nil
+}
+
+// Shutdown stops the trader like Stop, giving up waiting once ctx is done (the stop keeps running in the background)
+func (at *AutoTrader) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		at.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("trader %s did not stop in time: %w", at.name, ctx.Err())
+	}
+}
+
+// flushOnShutdown writes out what the trader still holds in memory, called once the cycle and monitors have stopped
+func (at *AutoTrader) flushOnShutdown() {
+	if items, dropped := at.drainDigest(); len(items) > 0 {
+		at.sendWebhook(at.buildDigest(items, dropped, time.Now()), true)
+	}
+	at.deliveries.Wait()
+	at.saveFinalEquity()
+}
+
+// drainDigest returns all held notifications whether or not they are due, and clears them
+func (at *AutoTrader) drainDigest() ([]WebhookPayload, int) {
+	at.notifications.mu.Lock()
+	defer at.notifications.mu.Unlock()
+	pending, dropped := at.notifications.pending, at.notifications.dropped
+	at.notifications.pending = nil
+	at.notifications.dropped = 0
+	return pending, dropped
+}
+
+// saveFinalEquity saves an equity point at shutdown so the curve ends where the trader stopped
+// Skipped when the exchange can't be reached, a fallback balance would distort the curve
+func (at *AutoTrader) saveFinalEquity() {
+	if at.store == nil || at.trader == nil {
+		return
+	}
+	balance, err := at.trader.GetBalance()
+	if err != nil {
+		logger.Infof("⚠️ [%s] Final equity point skipped: %v", at.name, err)
+		return
+	}
+	wallet, _ := balance["totalWalletBalance"].(float64)
+	unrealized, _ := balance["totalUnrealizedProfit"].(float64)
+
+	snapshot := &store.EquitySnapshot{
+		TraderID:      at.id,
+		Timestamp:     time.Now().UTC(),
+		TotalEquity:   wallet + unrealized,
+		Balance:       wallet,
+		UnrealizedPnL: unrealized,
+	}
+	if positions, err := at.trader.GetPositions(); err == nil {
+		snapshot.PositionCount = len(positions)
+		marginUsed := 0.0
+		for _, pos := range positions {
+			if pos.Leverage > 0 {
+				marginUsed += pos.Quantity * pos.MarkPrice / pos.Leverage
+			}
+		}
+		if snapshot.TotalEquity > 0 {
+			snapshot.MarginUsedPct = marginUsed / snapshot.TotalEquity * 100
+		}
+	}
+	if err := at.store.Equity().Save(snapshot); err != nil {
+		logger.Infof("⚠️ [%s] Failed to save final equity point: %v", at.name, err)
+	}
+}
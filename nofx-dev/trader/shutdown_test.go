@@ -0,0 +1,120 @@
+package trader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"nofx/decision"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestRequestDecisionAbandonedOnStop tests an in-flight AI request stops blocking the cycle once the trader is stopped
+func TestRequestDecisionAbandonedOnStop(t *testing.T) {
+	at := &AutoTrader{stopMonitorCh: make(chan struct{})}
+	release := make(chan struct{})
+	defer close(release)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(at.stopMonitorCh)
+	}()
+	start := time.Now()
+	_, err := at.requestDecision(func() (*decision.FullDecision, error) {
+		<-release
+		return &decision.FullDecision{}, nil
+	})
+	if !errors.Is(err, errShuttingDown) {
+		t.Fatalf("expected errShuttingDown, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("request was not abandoned promptly")
+	}
+
+	at.stopMonitorCh = make(chan struct{})
+	d, err := at.requestDecision(func() (*decision.FullDecision, error) {
+		return &decision.FullDecision{CoTTrace: "ok"}, nil
+	})
+	if err != nil || d.CoTTrace != "ok" {
+		t.Errorf("expected the decision to be returned, got %+v, %v", d, err)
+	}
+}
+
+// TestCheckShutdownEntry tests new entries are skipped during shutdown while closes still run
+func TestCheckShutdownEntry(t *testing.T) {
+	at := &AutoTrader{stopMonitorCh: make(chan struct{})}
+	if err := at.checkShutdownEntry("open_long"); err != nil {
+		t.Fatalf("expected entries while running, got %v", err)
+	}
+
+	close(at.stopMonitorCh)
+	tests := map[string]bool{
+		"open_long":          false,
+		"open_short":         false,
+		"add_long":           false,
+		"add_short":          false,
+		"close_long":         true,
+		"partial_close_long": true,
+		"hold":               true,
+	}
+	for action, allowed := range tests {
+		if err := at.checkShutdownEntry(action); (err == nil) != allowed {
+			t.Errorf("%s: allowed=%v, err=%v", action, allowed, err)
+		}
+	}
+}
+
+// TestStopFlushesHeldNotifications tests held notifications are delivered as a digest before Stop returns
+func TestStopFlushesHeldNotifications(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		got []WebhookPayload
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload WebhookPayload
+		json.Unmarshal(body, &payload)
+		time.Sleep(20 * time.Millisecond) // Slow receiver, Stop must still wait for it
+		mu.Lock()
+		got = append(got, payload)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	at := &AutoTrader{
+		isRunning:     true,
+		stopMonitorCh: make(chan struct{}),
+		webhook:       webhook{url: server.URL, client: server.Client()},
+		notifications: notificationState{policy: notificationPolicy{digest: true}},
+	}
+	// Held for the next top of the hour, not due yet
+	at.holdNotification(WebhookPayload{Action: "close_long", Symbol: "BTCUSDT", Time: time.Now()}, time.Now())
+
+	at.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 || got[0].Action != WebhookActionDigest || len(got[0].Digest) != 1 {
+		t.Fatalf("expected the held notification to be flushed as a digest, got %+v", got)
+	}
+}
+
+// TestShutdownDeadline tests Shutdown gives up waiting for a trader that doesn't settle in time
+func TestShutdownDeadline(t *testing.T) {
+	at := &AutoTrader{isRunning: true, stopMonitorCh: make(chan struct{})}
+	at.monitorWg.Add(1) // A cycle that is still settling
+	defer at.monitorWg.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := at.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+	if !at.shuttingDown() {
+		t.Error("expected the trader to be told to stop")
+	}
+}
@@ -0,0 +1,50 @@
+package trader
+
+import (
+	"nofx/logger"
+	"strings"
+	"time"
+)
+
+// stopOutTolerancePct how far past the stop (in % of the stop price) the mark may already have moved back
+// when a bracketed position is found closed, for the exit to still count as a stop-loss hit
+const stopOutTolerancePct = 0.5
+
+// stopLossCooldown the strategy's re-entry cooldown after a stop-loss exit (0 = disabled)
+func (at *AutoTrader) stopLossCooldown() time.Duration {
+	if at.config.StrategyConfig == nil {
+		return 0
+	}
+	return time.Duration(at.config.StrategyConfig.RiskControl.StopLossCooldownMinutes) * time.Minute
+}
+
+// stoppedOut reports whether a closed bracket's position most likely exited at its stop loss:
+// the mark is at, through or within stopOutTolerancePct of the stop
+func stoppedOut(b *bracket, mark float64) bool {
+	if b.StopLoss <= 0 || mark <= 0 {
+		return false
+	}
+	tolerance := b.StopLoss * stopOutTolerancePct / 100
+	if strings.EqualFold(b.Side, "SHORT") {
+		return mark >= b.StopLoss-tolerance
+	}
+	return mark <= b.StopLoss+tolerance
+}
+
+// recordStopOuts starts the re-entry cooldown of symbols whose bracketed position closed at its stop loss
+func (at *AutoTrader) recordStopOuts(closed []*bracket) {
+	cooldown := at.stopLossCooldown()
+	if cooldown <= 0 {
+		return
+	}
+	now := time.Now()
+	for _, b := range closed {
+		mark, err := at.trader.GetMarketPrice(b.Symbol)
+		if err != nil || !stoppedOut(b, mark) {
+			continue
+		}
+		at.stopCooldown.Record(b.Symbol, now, cooldown)
+		logger.Infof("🧊 %s %s stopped out at ~%.6f (stop %.6f), re-entry blocked for %s",
+			b.Symbol, b.Side, mark, b.StopLoss, cooldown)
+	}
+}
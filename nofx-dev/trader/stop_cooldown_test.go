@@ -0,0 +1,29 @@
+package trader
+
+import "testing"
+
+// TestStoppedOut tests classifying a closed bracket as a stop-loss exit from the mark price
+func TestStoppedOut(t *testing.T) {
+	long := &bracket{Symbol: "BTCUSDT", Side: "LONG", StopLoss: 100, TakeProfit: 120}
+	short := &bracket{Symbol: "BTCUSDT", Side: "SHORT", StopLoss: 100, TakeProfit: 80}
+
+	tests := []struct {
+		name string
+		b    *bracket
+		mark float64
+		want bool
+	}{
+		{"long through stop", long, 99, true},
+		{"long bounced within tolerance", long, 100.4, true},
+		{"long at take profit", long, 120, false},
+		{"short through stop", short, 101, true},
+		{"short bounced within tolerance", short, 99.6, true},
+		{"short at take profit", short, 80, false},
+		{"no stop", &bracket{Side: "LONG", TakeProfit: 120}, 50, false},
+	}
+	for _, tt := range tests {
+		if got := stoppedOut(tt.b, tt.mark); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package trader
+
+import (
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+)
+
+// snapStopLoss moves the AI's stop loss beyond round numbers, order book walls and volume nodes near it
+// (stop_snap_tolerance_pct), recording both the requested and the placed level. side: "long"/"short"
+// Failing to fetch market data keeps the AI's stop
+func (at *AutoTrader) snapStopLoss(d *decision.Decision, side string, action *store.DecisionAction) {
+	if d.StopLoss <= 0 || at.config.StrategyConfig == nil {
+		return
+	}
+	tolerance := at.config.StrategyConfig.RiskControl.StopSnapTolerancePct
+	if tolerance <= 0 {
+		return
+	}
+
+	adj, err := market.SuggestStop(d.Symbol, side, d.StopLoss, tolerance)
+	if err != nil {
+		logger.Infof("  ⚠ Stop placement: %v, keeping stop loss %.6g", err, d.StopLoss)
+		return
+	}
+	if !adj.Changed() {
+		return
+	}
+
+	logger.Infof("  📐 Stop loss %.6g → %.6g (beyond %s at %.6g)", adj.Requested, adj.Adjusted, adj.Reason, adj.Level)
+	action.RequestedStopLoss = adj.Requested
+	action.StopLoss = adj.Adjusted
+	action.StopAdjustReason = adj.Reason
+	d.StopLoss = adj.Adjusted
+}
@@ -0,0 +1,20 @@
+package trader
+
+import (
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+)
+
+// enforceSymbolMaxLeverage lowers the decision's leverage to the contract's max leverage
+// Unknown metadata leaves the leverage to the strategy limits
+func (at *AutoTrader) enforceSymbolMaxLeverage(d *decision.Decision, action *store.DecisionAction) {
+	meta, err := market.GetSymbolMeta(d.Symbol)
+	if err != nil || meta.MaxLeverage <= 0 || d.Leverage <= meta.MaxLeverage {
+		return
+	}
+	logger.Infof("  ⚠️ %s leverage %dx exceeds the contract max %dx, using %dx", d.Symbol, d.Leverage, meta.MaxLeverage, meta.MaxLeverage)
+	d.Leverage = meta.MaxLeverage
+	action.Leverage = meta.MaxLeverage
+}
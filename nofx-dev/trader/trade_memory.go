@@ -0,0 +1,60 @@
+package trader
+
+import (
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+)
+
+// tradeMemoryWindow closed trades with a remembered entry situation searched for similar ones
+const tradeMemoryWindow = 500
+
+// rememberEntry records the market situation of an opened position, so the trade can be recalled once closed
+func (at *AutoTrader) rememberEntry(d *decision.Decision, side string, data *market.Data, order map[string]interface{}) {
+	if at.store == nil {
+		return
+	}
+	orderID := orderIDString(order)
+	vector := decision.SituationVector(data)
+	if orderID == "" || orderID == "0" || len(vector) == 0 {
+		return
+	}
+	memory := &store.TradeMemory{
+		TraderID:     at.id,
+		EntryOrderID: orderID,
+		Symbol:       d.Symbol,
+		Side:         side,
+		Regime:       decision.MarketRegime(data),
+		Vector:       vector,
+		Summary:      d.Reasoning,
+	}
+	if err := at.store.TradeMemory().Save(memory); err != nil {
+		logger.Warnf("⚠️ [%s] Failed to save trade memory: %v", at.name, err)
+	}
+}
+
+// loadTradeMemory closed trades of the trader with their entry situation
+func (at *AutoTrader) loadTradeMemory() []decision.TradeOutcome {
+	outcomes, err := at.store.TradeMemory().GetOutcomes(at.id, tradeMemoryWindow)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to load trade memory: %v", at.name, err)
+		return nil
+	}
+	memory := make([]decision.TradeOutcome, 0, len(outcomes))
+	for _, o := range outcomes {
+		memory = append(memory, decision.TradeOutcome{
+			Symbol:      o.Symbol,
+			Side:        o.Side,
+			Regime:      o.Regime,
+			Vector:      o.Vector,
+			Summary:     o.Summary,
+			EntryTime:   o.EntryTime,
+			ExitTime:    o.ExitTime,
+			RealizedPnL: o.RealizedPnL,
+			ReturnPct:   o.ReturnPct,
+			CloseReason: o.CloseReason,
+		})
+	}
+	return memory
+}
@@ -104,18 +104,18 @@ func (s *TraderTestSuite) TestGetPositions() {
 	tests := []struct {
 		name      string
 		wantError bool
-		validate  func(*testing.T, []map[string]interface{})
+		validate  func(*testing.T, []Position)
 	}{
 		{
 			name:      "Successfully get position list",
 			wantError: false,
-			validate: func(t *testing.T, positions []map[string]interface{}) {
+			validate: func(t *testing.T, positions []Position) {
 				assert.NotNil(t, positions)
 				// Positions can be empty array
 				for _, pos := range positions {
-					assert.Contains(t, pos, "symbol")
-					assert.Contains(t, pos, "side")
-					assert.Contains(t, pos, "positionAmt")
+					assert.NotEmpty(t, pos.Symbol)
+					assert.Contains(t, []string{"long", "short"}, pos.Side)
+					assert.Greater(t, pos.Quantity, 0.0)
 				}
 			},
 		},
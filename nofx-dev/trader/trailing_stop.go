@@ -0,0 +1,205 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"nofx/logger"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+const (
+	minTrailingStopPct        = 0.1              // Binance callbackRate range is 0.1% - 10%
+	maxTrailingStopPct        = 10.0             // Same bounds are used for the client-side emulation
+	trailingStopCheckInterval = 15 * time.Second // Emulated trailing stops are checked at this interval
+)
+
+// ErrTrailingStopUnsupported exchange (or account mode) has no native trailing stop, use client-side emulation
+var ErrTrailingStopUnsupported = errors.New("native trailing stop not supported")
+
+// TrailingStopSetter optional capability for exchanges with native trailing stop orders
+// callbackPct is the distance from the best price since activation, in percent (e.g. 1.5 = 1.5%)
+type TrailingStopSetter interface {
+	SetTrailingStop(symbol string, positionSide string, quantity, callbackPct float64) error
+}
+
+// clampTrailingStopPct limits the callback to the supported range (0 stays 0 = disabled)
+func clampTrailingStopPct(pct float64) float64 {
+	if pct <= 0 {
+		return 0
+	}
+	return math.Min(math.Max(pct, minTrailingStopPct), maxTrailingStopPct)
+}
+
+// SetTrailingStop places a TRAILING_STOP_MARKET order on Binance
+func (t *FuturesTrader) SetTrailingStop(symbol string, positionSide string, quantity, callbackPct float64) error {
+	if t.pm != nil {
+		return ErrTrailingStopUnsupported
+	}
+
+	var side futures.SideType
+	var posSide futures.PositionSideType
+	if positionSide == "LONG" {
+		side = futures.SideTypeSell
+		posSide = futures.PositionSideTypeLong
+	} else {
+		side = futures.SideTypeBuy
+		posSide = futures.PositionSideTypeShort
+	}
+
+	quantityStr, err := t.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return err
+	}
+	superseded, err := t.reserveAlgoOrder(symbol, futures.OrderTypeTrailingStopMarket, posSide)
+	if err != nil {
+		return err
+	}
+
+	callbackPct = clampTrailingStopPct(callbackPct)
+	order, err := t.client.NewCreateOrderService().
+		Symbol(symbol).
+		Side(side).
+		PositionSide(posSide).
+		Type(futures.OrderTypeTrailingStopMarket).
+		CallbackRate(strconv.FormatFloat(callbackPct, 'f', 1, 64)).
+		Quantity(quantityStr).
+		WorkingType(futures.WorkingTypeContractPrice).
+		NewClientOrderID(getBrOrderID()).
+		Do(context.Background())
+	if err != nil {
+		t.algoOrders.invalidate()
+		return fmt.Errorf("failed to set trailing stop: %w", err)
+	}
+	t.algoOrders.add(conditionalOrder{OrderID: order.OrderID, Symbol: symbol, Type: string(futures.OrderTypeTrailingStopMarket), PositionSide: string(posSide)})
+	t.cancelSuperseded(symbol, posSide, superseded)
+
+	logger.Infof("  Trailing stop set: %.1f%% callback", callbackPct)
+	return nil
+}
+
+// emulatedTrailingStop client-side trailing stop state for one position
+type emulatedTrailingStop struct {
+	Side        string  // "long" or "short"
+	CallbackPct float64 // Retrace from best price that triggers the close
+	BestPrice   float64 // Highest price since set (long) / lowest (short)
+}
+
+// update records a new price and reports whether the stop is hit
+func (s *emulatedTrailingStop) update(price float64) bool {
+	if price <= 0 {
+		return false
+	}
+	if s.BestPrice == 0 ||
+		(s.Side == "long" && price > s.BestPrice) ||
+		(s.Side == "short" && price < s.BestPrice) {
+		s.BestPrice = price
+		return false
+	}
+	if s.Side == "long" {
+		return (s.BestPrice-price)/s.BestPrice*100 >= s.CallbackPct
+	}
+	return (price-s.BestPrice)/s.BestPrice*100 >= s.CallbackPct
+}
+
+// trailingStops emulated trailing stops keyed by symbol_side
+type trailingStops struct {
+	mu    sync.Mutex
+	stops map[string]*emulatedTrailingStop
+}
+
+// setTrailingStop sets a native trailing stop when the position's exchange supports it, emulates it otherwise
+// side is "LONG" or "SHORT"
+func (at *AutoTrader) setTrailingStop(symbol, side string, quantity, callbackPct float64) {
+	callbackPct = clampTrailingStopPct(callbackPct)
+	if callbackPct == 0 {
+		return
+	}
+
+	if setter, ok := capability[TrailingStopSetter](at.trader, symbol, side); ok {
+		err := setter.SetTrailingStop(symbol, side, quantity, callbackPct)
+		if err == nil {
+			return
+		}
+		if !errors.Is(err, ErrTrailingStopUnsupported) {
+			logger.Infof("  ⚠ Native trailing stop failed, emulating client-side: %v", err)
+		}
+	}
+
+	price := 0.0
+	if p, err := at.trader.GetMarketPrice(symbol); err == nil {
+		price = p
+	}
+	at.trailing.mu.Lock()
+	at.trailing.stops[symbol+"_"+strings.ToLower(side)] = &emulatedTrailingStop{
+		Side:        strings.ToLower(side),
+		CallbackPct: callbackPct,
+		BestPrice:   price,
+	}
+	at.trailing.mu.Unlock()
+	logger.Infof("  Trailing stop set (emulated): %.2f%% callback from %.6f", callbackPct, price)
+}
+
+// clearTrailingStop removes an emulated trailing stop (side "long"/"short")
+func (at *AutoTrader) clearTrailingStop(symbol, side string) {
+	at.trailing.mu.Lock()
+	delete(at.trailing.stops, symbol+"_"+strings.ToLower(side))
+	at.trailing.mu.Unlock()
+}
+
+// checkTrailingStops updates emulated trailing stops with mark prices and closes positions that retraced
+func (at *AutoTrader) checkTrailingStops() {
+	at.trailing.mu.Lock()
+	empty := len(at.trailing.stops) == 0
+	at.trailing.mu.Unlock()
+	if empty {
+		return
+	}
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Infof("❌ Trailing stop monitoring: failed to get positions: %v", err)
+		return
+	}
+
+	open := make(map[string]float64, len(positions))
+	for _, pos := range positions {
+		if pos.ReadOnly {
+			continue
+		}
+		open[pos.Symbol+"_"+pos.Side] = pos.MarkPrice
+	}
+
+	var triggered []string
+	at.trailing.mu.Lock()
+	for key, stop := range at.trailing.stops {
+		price, ok := open[key]
+		if !ok {
+			delete(at.trailing.stops, key) // Position closed elsewhere
+			continue
+		}
+		if stop.update(price) {
+			triggered = append(triggered, key)
+			logger.Infof("🎯 Trailing stop triggered: %s | best %.6f | mark %.6f | callback %.2f%%",
+				key, stop.BestPrice, price, stop.CallbackPct)
+		}
+	}
+	at.trailing.mu.Unlock()
+
+	for _, key := range triggered {
+		idx := strings.LastIndex(key, "_")
+		symbol, side := key[:idx], key[idx+1:]
+		if err := at.emergencyClosePosition(symbol, side); err != nil {
+			logger.Infof("❌ Trailing stop close failed (%s %s): %v", symbol, side, err)
+			continue
+		}
+		at.clearTrailingStop(symbol, side)
+		at.ClearPeakPnLCache(symbol, side)
+	}
+}
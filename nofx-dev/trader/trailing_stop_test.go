@@ -0,0 +1,39 @@
+package trader
+
+import "testing"
+
+// TestEmulatedTrailingStop tests best-price tracking and trigger on retrace
+func TestEmulatedTrailingStop(t *testing.T) {
+	long := &emulatedTrailingStop{Side: "long", CallbackPct: 2, BestPrice: 100}
+	for _, price := range []float64{101, 105, 104, 103} {
+		if long.update(price) {
+			t.Fatalf("long: unexpected trigger at %.2f (best %.2f)", price, long.BestPrice)
+		}
+	}
+	if long.BestPrice != 105 {
+		t.Errorf("long: expected best price 105, got %.2f", long.BestPrice)
+	}
+	if !long.update(102.8) {
+		t.Errorf("long: expected trigger at 2%% below 105")
+	}
+
+	short := &emulatedTrailingStop{Side: "short", CallbackPct: 1}
+	for _, price := range []float64{50, 48, 48.4} {
+		if short.update(price) {
+			t.Fatalf("short: unexpected trigger at %.2f (best %.2f)", price, short.BestPrice)
+		}
+	}
+	if !short.update(48.5) {
+		t.Errorf("short: expected trigger at 1%% above 48")
+	}
+}
+
+// TestClampTrailingStopPct tests the supported callback range
+func TestClampTrailingStopPct(t *testing.T) {
+	tests := map[float64]float64{0: 0, -1: 0, 0.05: 0.1, 2.5: 2.5, 25: 10}
+	for in, want := range tests {
+		if got := clampTrailingStopPct(in); got != want {
+			t.Errorf("clampTrailingStopPct(%v): expected %v, got %v", in, want, got)
+		}
+	}
+}
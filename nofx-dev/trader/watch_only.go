@@ -0,0 +1,47 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/decision"
+	"nofx/logger"
+	"time"
+)
+
+// EventWatchAlert the AI flagged a symbol (breakout, OI surge...) with an alert decision, nothing was traded
+const EventWatchAlert = "watch_alert"
+
+// watchOnlySymbols the strategy's watch-only coins (nil without a strategy engine)
+func (at *AutoTrader) watchOnlySymbols() map[string]bool {
+	if at.strategyEngine == nil {
+		return nil
+	}
+	return at.strategyEngine.WatchOnlySymbols()
+}
+
+// executeAlertWithRecord delivers an alert decision as a trader event and notification, no order is placed
+func (at *AutoTrader) executeAlertWithRecord(d *decision.Decision) error {
+	message := fmt.Sprintf("%s %s: %s", d.Symbol, d.AlertType, d.Reasoning)
+	if at.watchOnlySymbols()[d.Symbol] {
+		message = "[watch-only] " + message
+	}
+	logger.Infof("  🔔 Alert: %s", message)
+
+	event := TraderEvent{
+		TraderID:   at.id,
+		Type:       EventWatchAlert,
+		Exchange:   at.exchange,
+		ExchangeID: at.exchangeID,
+		Message:    message,
+		Time:       time.Now(),
+		Details: map[string]interface{}{
+			"symbol":     d.Symbol,
+			"alert_type": d.AlertType,
+			"confidence": d.Confidence,
+		},
+	}
+	if at.config.OnEvent != nil {
+		at.config.OnEvent(event)
+	}
+	at.notifyWebhookEvent(event)
+	return nil
+}
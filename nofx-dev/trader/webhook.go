@@ -0,0 +1,168 @@
+package trader
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"nofx/decision"
+	"nofx/store"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Webhook request headers, receivers verify HMAC-SHA256(secret, timestamp + "." + body)
+const (
+	WebhookSignatureHeader = "X-NOFX-Signature"
+	WebhookTimestampHeader = "X-NOFX-Timestamp"
+)
+
+const (
+	webhookTimeout      = 10 * time.Second
+	webhookMaxAttempts  = 3
+	webhookReasoningLen = 280 // Characters of reasoning sent as summary
+)
+
+// WebhookPayload executed decision posted to the trader's webhook
+type WebhookPayload struct {
+	TraderID   string    `json:"trader_id"`
+	TraderName string    `json:"trader_name"`
+	Exchange   string    `json:"exchange"`
+	Action     string    `json:"action"`
+	Symbol     string    `json:"symbol"`
+	Price      float64   `json:"price"`
+	Quantity   float64   `json:"quantity"`
+	SizeUSD    float64   `json:"size_usd"` // Quantity * price
+	Leverage   int       `json:"leverage,omitempty"`
+	OrderID    int64     `json:"order_id,omitempty"`
+	StopLoss   float64   `json:"stop_loss,omitempty"`
+	TakeProfit float64   `json:"take_profit,omitempty"`
+	Reasoning  string    `json:"reasoning"` // Summary, truncated
+	Time       time.Time `json:"time"`
+
+	// Message human-readable text in the owner's locale and time zone, for chat / e-mail relays
+	Message string `json:"message"`
+
+	// RequestedStopLoss AI's stop loss when it was snapped beyond nearby liquidity (StopLoss is the placed one)
+	RequestedStopLoss float64 `json:"requested_stop_loss,omitempty"`
+
+	// Digest notifications held during quiet hours / digest batching (action "digest" only)
+	Digest []WebhookPayload `json:"digest,omitempty"`
+}
+
+// webhook per-trader executed-decision webhook, URL empty when disabled
+type webhook struct {
+	mu     sync.RWMutex
+	url    string
+	secret string
+	client *http.Client
+}
+
+// SetWebhook updates the executed-decision webhook (empty URL disables it)
+func (at *AutoTrader) SetWebhook(url, secret string) {
+	at.webhook.mu.Lock()
+	at.webhook.url = url
+	at.webhook.secret = secret
+	at.webhook.mu.Unlock()
+}
+
+// signWebhook returns the hex HMAC-SHA256 signature of a webhook body
+func signWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// summarizeReasoning collapses whitespace and truncates the reasoning for the webhook payload
+func summarizeReasoning(reasoning string) string {
+	summary := strings.Join(strings.Fields(reasoning), " ")
+	runes := []rune(summary)
+	if len(runes) <= webhookReasoningLen {
+		return summary
+	}
+	return string(runes[:webhookReasoningLen-1]) + "…"
+}
+
+// buildWebhookPayload describes an executed decision
+func (at *AutoTrader) buildWebhookPayload(d *decision.Decision, action *store.DecisionAction) WebhookPayload {
+	return WebhookPayload{
+		TraderID:   at.id,
+		TraderName: at.name,
+		Exchange:   at.exchange,
+		Action:     action.Action,
+		Symbol:     action.Symbol,
+		Price:      action.Price,
+		Quantity:   action.Quantity,
+		SizeUSD:    action.Quantity * action.Price,
+		Leverage:   action.Leverage,
+		OrderID:    action.OrderID,
+		StopLoss:   d.StopLoss,
+		TakeProfit: d.TakeProfit,
+		Reasoning:  summarizeReasoning(d.Reasoning),
+		Time:       action.Timestamp,
+
+		RequestedStopLoss: action.RequestedStopLoss,
+	}
+}
+
+// notifyWebhook posts an executed decision to the trader's webhook in the background
+// hold/wait are not executions and are not sent
+func (at *AutoTrader) notifyWebhook(d *decision.Decision, action *store.DecisionAction) {
+	if d.Action == "hold" || d.Action == "wait" {
+		return
+	}
+	at.sendWebhook(at.buildWebhookPayload(d, action), false)
+}
+
+// postWebhook sends a signed webhook body, retrying network errors and 5xx responses
+func (at *AutoTrader) postWebhook(url, secret string, body []byte) error {
+	return at.postSigned(url, secret, "application/json", body)
+}
+
+// postSigned posts a body of contentType with the webhook timestamp / signature headers,
+// retrying network errors and 5xx responses
+func (at *AutoTrader) postSigned(url, secret, contentType string, body []byte) error {
+	client := at.webhook.client
+	if client == nil {
+		client = newOutboundClient(webhookTimeout)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(time.Duration(attempt-1) * time.Second)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("invalid webhook request: %w", err)
+		}
+		timestamp := time.Now().Unix()
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+		if secret != "" {
+			req.Header.Set(WebhookSignatureHeader, "sha256="+signWebhook(secret, timestamp, body))
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+		if resp.StatusCode < 500 {
+			return lastErr // Client errors will not succeed on retry
+		}
+	}
+	return lastErr
+}
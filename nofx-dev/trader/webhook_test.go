@@ -0,0 +1,66 @@
+package trader
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// TestPostWebhookSigned tests the body is delivered with a verifiable signature
+func TestPostWebhookSigned(t *testing.T) {
+	var got WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(WebhookTimestampHeader), 10, 64)
+		if want := "sha256=" + signWebhook("s3cret", timestamp, body); r.Header.Get(WebhookSignatureHeader) != want {
+			t.Errorf("signature mismatch: %s", r.Header.Get(WebhookSignatureHeader))
+		}
+		json.Unmarshal(body, &got)
+	}))
+	defer server.Close()
+
+	at := &AutoTrader{}
+	at.webhook.client = server.Client() // The test server listens on loopback
+	body, _ := json.Marshal(WebhookPayload{Action: "open_long", Symbol: "BTCUSDT", Price: 100})
+	if err := at.postWebhook(server.URL, "s3cret", body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Action != "open_long" || got.Symbol != "BTCUSDT" {
+		t.Errorf("unexpected payload: %+v", got)
+	}
+}
+
+// TestPostWebhookClientError tests 4xx responses are not retried
+func TestPostWebhookClientError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	at := &AutoTrader{}
+	at.webhook.client = server.Client()
+	if err := at.postWebhook(server.URL, "", []byte(`{}`)); err == nil {
+		t.Fatal("expected error for HTTP 400")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 attempt, got %d", calls)
+	}
+}
+
+// TestSummarizeReasoning tests whitespace collapsing and truncation
+func TestSummarizeReasoning(t *testing.T) {
+	if got := summarizeReasoning("  trend\n\nup  "); got != "trend up" {
+		t.Errorf("expected collapsed whitespace, got %q", got)
+	}
+	long := summarizeReasoning(strings.Repeat("a", webhookReasoningLen+50))
+	if n := len([]rune(long)); n != webhookReasoningLen {
+		t.Errorf("expected %d characters, got %d", webhookReasoningLen, n)
+	}
+}
@@ -0,0 +1,396 @@
+package trader
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Optional capabilities passed through the wrapper layers, so calls reached through capability() get the same
+// error classification, fault injection and reduce-only checks as the core Trader methods. capability() only
+// returns a wrapper when the exchange adapter underneath implements the capability
+
+// innerCapability returns the wrapped trader's capability T, an error naming op when it has none
+func innerCapability[T any](t Trader, op string) (T, error) {
+	c, ok := any(t).(T)
+	if !ok {
+		return c, fmt.Errorf("%s not supported by the exchange", op)
+	}
+	return c, nil
+}
+
+// ============================================================================
+// ClassifiedTrader
+// ============================================================================
+
+func (c *ClassifiedTrader) SetTrailingStop(symbol string, positionSide string, quantity, callbackPct float64) error {
+	setter, err := innerCapability[TrailingStopSetter](c.Trader, "SetTrailingStop")
+	if err != nil {
+		return ErrTrailingStopUnsupported
+	}
+	return c.wrap("SetTrailingStop", setter.SetTrailingStop(symbol, positionSide, quantity, callbackPct))
+}
+
+func (c *ClassifiedTrader) SetBracket(symbol string, positionSide string, quantity, stopPrice, takeProfitPrice float64) error {
+	setter, err := innerCapability[BracketSetter](c.Trader, "SetBracket")
+	if err != nil {
+		return err
+	}
+	return c.wrap("SetBracket", setter.SetBracket(symbol, positionSide, quantity, stopPrice, takeProfitPrice))
+}
+
+func (c *ClassifiedTrader) ModifyStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	amender, err := innerCapability[ProtectionAmender](c.Trader, "ModifyStopLoss")
+	if err != nil {
+		return err
+	}
+	return c.wrap("ModifyStopLoss", amender.ModifyStopLoss(symbol, positionSide, quantity, stopPrice))
+}
+
+func (c *ClassifiedTrader) ModifyTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	amender, err := innerCapability[ProtectionAmender](c.Trader, "ModifyTakeProfit")
+	if err != nil {
+		return err
+	}
+	return c.wrap("ModifyTakeProfit", amender.ModifyTakeProfit(symbol, positionSide, quantity, takeProfitPrice))
+}
+
+func (c *ClassifiedTrader) TransferFunds(req TransferRequest) (string, error) {
+	transferer, err := innerCapability[FundTransferer](c.Trader, "TransferFunds")
+	if err != nil {
+		return "", err
+	}
+	tranID, err := transferer.TransferFunds(req)
+	return tranID, c.wrap("TransferFunds", err)
+}
+
+func (c *ClassifiedTrader) GetOpenConditionalOrders() ([]OpenOrder, error) {
+	lister, err := innerCapability[OpenOrderLister](c.Trader, "GetOpenConditionalOrders")
+	if err != nil {
+		return nil, err
+	}
+	orders, err := lister.GetOpenConditionalOrders()
+	return orders, c.wrap("GetOpenConditionalOrders", err)
+}
+
+func (c *ClassifiedTrader) GetFundingFees(startTime time.Time) ([]FundingPayment, error) {
+	lister, err := innerCapability[FundingFeeLister](c.Trader, "GetFundingFees")
+	if err != nil {
+		return nil, err
+	}
+	payments, err := lister.GetFundingFees(startTime)
+	return payments, c.wrap("GetFundingFees", err)
+}
+
+func (c *ClassifiedTrader) SetMakerFirst(enabled bool) {
+	if setter, err := innerCapability[MakerFirstSetter](c.Trader, "SetMakerFirst"); err == nil {
+		setter.SetMakerFirst(enabled)
+	}
+}
+
+func (c *ClassifiedTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord, error) {
+	history, err := innerCapability[tradeHistory](c.Trader, "GetTrades")
+	if err != nil {
+		return nil, err
+	}
+	trades, err := history.GetTrades(startTime, limit)
+	return trades, c.wrap("GetTrades", err)
+}
+
+func (c *ClassifiedTrader) GetTradesForSymbol(symbol string, startTime time.Time, limit int) ([]TradeRecord, error) {
+	history, err := innerCapability[symbolTradeHistory](c.Trader, "GetTradesForSymbol")
+	if err != nil {
+		return nil, err
+	}
+	trades, err := history.GetTradesForSymbol(symbol, startTime, limit)
+	return trades, c.wrap("GetTradesForSymbol", err)
+}
+
+// ============================================================================
+// FaultInjectingTrader: orders may time out or be rejected, reads may time out
+// ============================================================================
+
+func (f *FaultInjectingTrader) SetTrailingStop(symbol string, positionSide string, quantity, callbackPct float64) error {
+	setter, err := innerCapability[TrailingStopSetter](f.Trader, "SetTrailingStop")
+	if err != nil {
+		return ErrTrailingStopUnsupported
+	}
+	if err := f.beforeOrder("SetTrailingStop", symbol); err != nil {
+		return err
+	}
+	return setter.SetTrailingStop(symbol, positionSide, quantity, callbackPct)
+}
+
+func (f *FaultInjectingTrader) SetBracket(symbol string, positionSide string, quantity, stopPrice, takeProfitPrice float64) error {
+	setter, err := innerCapability[BracketSetter](f.Trader, "SetBracket")
+	if err != nil {
+		return err
+	}
+	if err := f.beforeOrder("SetBracket", symbol); err != nil {
+		return err
+	}
+	return setter.SetBracket(symbol, positionSide, quantity, stopPrice, takeProfitPrice)
+}
+
+func (f *FaultInjectingTrader) ModifyStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	amender, err := innerCapability[ProtectionAmender](f.Trader, "ModifyStopLoss")
+	if err != nil {
+		return err
+	}
+	if err := f.beforeOrder("ModifyStopLoss", symbol); err != nil {
+		return err
+	}
+	return amender.ModifyStopLoss(symbol, positionSide, quantity, stopPrice)
+}
+
+func (f *FaultInjectingTrader) ModifyTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	amender, err := innerCapability[ProtectionAmender](f.Trader, "ModifyTakeProfit")
+	if err != nil {
+		return err
+	}
+	if err := f.beforeOrder("ModifyTakeProfit", symbol); err != nil {
+		return err
+	}
+	return amender.ModifyTakeProfit(symbol, positionSide, quantity, takeProfitPrice)
+}
+
+func (f *FaultInjectingTrader) TransferFunds(req TransferRequest) (string, error) {
+	transferer, err := innerCapability[FundTransferer](f.Trader, "TransferFunds")
+	if err != nil {
+		return "", err
+	}
+	if err := f.beforeCall("TransferFunds"); err != nil {
+		return "", err
+	}
+	return transferer.TransferFunds(req)
+}
+
+func (f *FaultInjectingTrader) GetOpenConditionalOrders() ([]OpenOrder, error) {
+	lister, err := innerCapability[OpenOrderLister](f.Trader, "GetOpenConditionalOrders")
+	if err != nil {
+		return nil, err
+	}
+	if err := f.beforeCall("GetOpenConditionalOrders"); err != nil {
+		return nil, err
+	}
+	return lister.GetOpenConditionalOrders()
+}
+
+func (f *FaultInjectingTrader) GetFundingFees(startTime time.Time) ([]FundingPayment, error) {
+	lister, err := innerCapability[FundingFeeLister](f.Trader, "GetFundingFees")
+	if err != nil {
+		return nil, err
+	}
+	if err := f.beforeCall("GetFundingFees"); err != nil {
+		return nil, err
+	}
+	return lister.GetFundingFees(startTime)
+}
+
+func (f *FaultInjectingTrader) SetMakerFirst(enabled bool) {
+	if setter, err := innerCapability[MakerFirstSetter](f.Trader, "SetMakerFirst"); err == nil {
+		setter.SetMakerFirst(enabled)
+	}
+}
+
+func (f *FaultInjectingTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord, error) {
+	history, err := innerCapability[tradeHistory](f.Trader, "GetTrades")
+	if err != nil {
+		return nil, err
+	}
+	if err := f.beforeCall("GetTrades"); err != nil {
+		return nil, err
+	}
+	return history.GetTrades(startTime, limit)
+}
+
+func (f *FaultInjectingTrader) GetTradesForSymbol(symbol string, startTime time.Time, limit int) ([]TradeRecord, error) {
+	history, err := innerCapability[symbolTradeHistory](f.Trader, "GetTradesForSymbol")
+	if err != nil {
+		return nil, err
+	}
+	if err := f.beforeCall("GetTradesForSymbol"); err != nil {
+		return nil, err
+	}
+	return history.GetTradesForSymbol(symbol, startTime, limit)
+}
+
+// ============================================================================
+// IdempotentTrader: protective orders and reads aren't retried, passed through unchanged
+// ============================================================================
+
+func (t *IdempotentTrader) SetTrailingStop(symbol string, positionSide string, quantity, callbackPct float64) error {
+	setter, err := innerCapability[TrailingStopSetter](t.Trader, "SetTrailingStop")
+	if err != nil {
+		return ErrTrailingStopUnsupported
+	}
+	return setter.SetTrailingStop(symbol, positionSide, quantity, callbackPct)
+}
+
+func (t *IdempotentTrader) SetBracket(symbol string, positionSide string, quantity, stopPrice, takeProfitPrice float64) error {
+	setter, err := innerCapability[BracketSetter](t.Trader, "SetBracket")
+	if err != nil {
+		return err
+	}
+	return setter.SetBracket(symbol, positionSide, quantity, stopPrice, takeProfitPrice)
+}
+
+func (t *IdempotentTrader) ModifyStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	amender, err := innerCapability[ProtectionAmender](t.Trader, "ModifyStopLoss")
+	if err != nil {
+		return err
+	}
+	return amender.ModifyStopLoss(symbol, positionSide, quantity, stopPrice)
+}
+
+func (t *IdempotentTrader) ModifyTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	amender, err := innerCapability[ProtectionAmender](t.Trader, "ModifyTakeProfit")
+	if err != nil {
+		return err
+	}
+	return amender.ModifyTakeProfit(symbol, positionSide, quantity, takeProfitPrice)
+}
+
+func (t *IdempotentTrader) TransferFunds(req TransferRequest) (string, error) {
+	transferer, err := innerCapability[FundTransferer](t.Trader, "TransferFunds")
+	if err != nil {
+		return "", err
+	}
+	return transferer.TransferFunds(req)
+}
+
+func (t *IdempotentTrader) GetOpenConditionalOrders() ([]OpenOrder, error) {
+	lister, err := innerCapability[OpenOrderLister](t.Trader, "GetOpenConditionalOrders")
+	if err != nil {
+		return nil, err
+	}
+	return lister.GetOpenConditionalOrders()
+}
+
+func (t *IdempotentTrader) GetFundingFees(startTime time.Time) ([]FundingPayment, error) {
+	lister, err := innerCapability[FundingFeeLister](t.Trader, "GetFundingFees")
+	if err != nil {
+		return nil, err
+	}
+	return lister.GetFundingFees(startTime)
+}
+
+func (t *IdempotentTrader) SetMakerFirst(enabled bool) {
+	if setter, err := innerCapability[MakerFirstSetter](t.Trader, "SetMakerFirst"); err == nil {
+		setter.SetMakerFirst(enabled)
+	}
+}
+
+func (t *IdempotentTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord, error) {
+	history, err := innerCapability[tradeHistory](t.Trader, "GetTrades")
+	if err != nil {
+		return nil, err
+	}
+	return history.GetTrades(startTime, limit)
+}
+
+func (t *IdempotentTrader) GetTradesForSymbol(symbol string, startTime time.Time, limit int) ([]TradeRecord, error) {
+	history, err := innerCapability[symbolTradeHistory](t.Trader, "GetTradesForSymbol")
+	if err != nil {
+		return nil, err
+	}
+	return history.GetTradesForSymbol(symbol, startTime, limit)
+}
+
+// ============================================================================
+// ReduceOnlyTrader: protective orders are checked against the live position like SetStopLoss / SetTakeProfit
+// ============================================================================
+
+func (r *ReduceOnlyTrader) SetTrailingStop(symbol string, positionSide string, quantity, callbackPct float64) error {
+	setter, err := innerCapability[TrailingStopSetter](r.Trader, "SetTrailingStop")
+	if err != nil {
+		return ErrTrailingStopUnsupported
+	}
+	quantity, err = r.checkQuantity("SetTrailingStop", symbol, strings.ToLower(positionSide), quantity, true)
+	if err != nil {
+		return err
+	}
+	return setter.SetTrailingStop(symbol, positionSide, quantity, callbackPct)
+}
+
+func (r *ReduceOnlyTrader) SetBracket(symbol string, positionSide string, quantity, stopPrice, takeProfitPrice float64) error {
+	setter, err := innerCapability[BracketSetter](r.Trader, "SetBracket")
+	if err != nil {
+		return err
+	}
+	quantity, err = r.checkQuantity("SetBracket", symbol, strings.ToLower(positionSide), quantity, true)
+	if err != nil {
+		return err
+	}
+	return setter.SetBracket(symbol, positionSide, quantity, stopPrice, takeProfitPrice)
+}
+
+func (r *ReduceOnlyTrader) ModifyStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	amender, err := innerCapability[ProtectionAmender](r.Trader, "ModifyStopLoss")
+	if err != nil {
+		return err
+	}
+	quantity, err = r.checkQuantity("ModifyStopLoss", symbol, strings.ToLower(positionSide), quantity, true)
+	if err != nil {
+		return err
+	}
+	return amender.ModifyStopLoss(symbol, positionSide, quantity, stopPrice)
+}
+
+func (r *ReduceOnlyTrader) ModifyTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	amender, err := innerCapability[ProtectionAmender](r.Trader, "ModifyTakeProfit")
+	if err != nil {
+		return err
+	}
+	quantity, err = r.checkQuantity("ModifyTakeProfit", symbol, strings.ToLower(positionSide), quantity, true)
+	if err != nil {
+		return err
+	}
+	return amender.ModifyTakeProfit(symbol, positionSide, quantity, takeProfitPrice)
+}
+
+func (r *ReduceOnlyTrader) TransferFunds(req TransferRequest) (string, error) {
+	transferer, err := innerCapability[FundTransferer](r.Trader, "TransferFunds")
+	if err != nil {
+		return "", err
+	}
+	return transferer.TransferFunds(req)
+}
+
+func (r *ReduceOnlyTrader) GetOpenConditionalOrders() ([]OpenOrder, error) {
+	lister, err := innerCapability[OpenOrderLister](r.Trader, "GetOpenConditionalOrders")
+	if err != nil {
+		return nil, err
+	}
+	return lister.GetOpenConditionalOrders()
+}
+
+func (r *ReduceOnlyTrader) GetFundingFees(startTime time.Time) ([]FundingPayment, error) {
+	lister, err := innerCapability[FundingFeeLister](r.Trader, "GetFundingFees")
+	if err != nil {
+		return nil, err
+	}
+	return lister.GetFundingFees(startTime)
+}
+
+func (r *ReduceOnlyTrader) SetMakerFirst(enabled bool) {
+	if setter, err := innerCapability[MakerFirstSetter](r.Trader, "SetMakerFirst"); err == nil {
+		setter.SetMakerFirst(enabled)
+	}
+}
+
+func (r *ReduceOnlyTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord, error) {
+	history, err := innerCapability[tradeHistory](r.Trader, "GetTrades")
+	if err != nil {
+		return nil, err
+	}
+	return history.GetTrades(startTime, limit)
+}
+
+func (r *ReduceOnlyTrader) GetTradesForSymbol(symbol string, startTime time.Time, limit int) ([]TradeRecord, error) {
+	history, err := innerCapability[symbolTradeHistory](r.Trader, "GetTradesForSymbol")
+	if err != nil {
+		return nil, err
+	}
+	return history.GetTradesForSymbol(symbol, startTime, limit)
+}
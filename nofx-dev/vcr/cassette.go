@@ -0,0 +1,193 @@
+// Package vcr records exchange HTTP and WebSocket traffic into cassettes and plays it back,
+// so trader and market tests run deterministically offline against real payloads
+package vcr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// volatileParams query / body fields that change on every request (clocks, nonces, signatures)
+// They are left out of recorded requests and ignored when matching
+var volatileParams = map[string]bool{
+	"timestamp":    true,
+	"recvWindow":   true,
+	"signature":    true,
+	"nonce":        true,
+	"expiresAfter": true,
+}
+
+// keptResponseHeaders response headers worth recording (rate limit feedback), everything else is dropped
+var keptResponseHeaders = []string{"Content-Type", "Retry-After", "X-Mbx-Used-Weight-1m"}
+
+// Cassette recorded exchange traffic of one test
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+	Frames       []Frame       `json:"frames,omitempty"` // WebSocket messages, in the order they were received
+}
+
+// Interaction one HTTP request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request recorded request, without headers (API keys) and volatile fields
+type Request struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	JSON   json.RawMessage `json:"json,omitempty"` // JSON body (Hyperliquid)
+	Body   string          `json:"body,omitempty"` // Other bodies (Binance form posts)
+}
+
+// Response recorded response, the body kept as JSON when it is JSON so fixtures stay editable
+type Response struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	JSON    json.RawMessage   `json:"json,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// Frame one WebSocket message received from the exchange
+type Frame struct {
+	Data json.RawMessage `json:"data"`
+}
+
+// Load reads a cassette file
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to path, creating its directory
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// body returns the recorded response body
+func (r Response) body() []byte {
+	if len(r.JSON) > 0 {
+		return r.JSON
+	}
+	return []byte(r.Body)
+}
+
+// newRequest builds the recorded form of a request, volatile fields removed
+func newRequest(method string, u *url.URL, body []byte) Request {
+	clean := *u
+	clean.RawQuery = stripValues(u.Query()).Encode()
+	req := Request{Method: method, URL: clean.String()}
+	if len(body) == 0 {
+		return req
+	}
+	if obj, ok := stripJSON(body); ok {
+		req.JSON = obj
+	} else if form, err := url.ParseQuery(string(body)); err == nil {
+		req.Body = stripValues(form).Encode()
+	} else {
+		req.Body = string(body)
+	}
+	return req
+}
+
+// key identifies requests that are served by the same recorded interaction
+func (r Request) key() string {
+	body := r.Body
+	if len(r.JSON) > 0 {
+		// Re-encoded so whitespace and key order of hand-edited fixtures don't matter
+		if obj, ok := stripJSON(r.JSON); ok {
+			body = string(obj)
+		}
+	}
+	return r.Method + " " + r.URL + " " + body
+}
+
+// stripValues removes volatile fields from query or form values
+func stripValues(values url.Values) url.Values {
+	for k := range values {
+		if volatileParams[k] {
+			values.Del(k)
+		}
+	}
+	return values
+}
+
+// stripJSON removes volatile top-level fields from a JSON object body, re-encoded with sorted keys
+func stripJSON(body []byte) (json.RawMessage, bool) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, false
+	}
+	for k := range obj {
+		if volatileParams[k] {
+			delete(obj, k)
+		}
+	}
+	encoded, err := json.Marshal(obj)
+	if err != nil {
+		return nil, false
+	}
+	return encoded, true
+}
+
+// newResponse builds the recorded form of a response
+func newResponse(status int, header map[string][]string, body []byte) Response {
+	resp := Response{Status: status}
+	for _, name := range keptResponseHeaders {
+		if v := headerValue(header, name); v != "" {
+			if resp.Headers == nil {
+				resp.Headers = make(map[string]string)
+			}
+			resp.Headers[name] = v
+		}
+	}
+	if json.Valid(body) && len(body) > 0 {
+		resp.JSON = body
+	} else {
+		resp.Body = string(body)
+	}
+	return resp
+}
+
+// headerValue case-insensitive header lookup
+func headerValue(header map[string][]string, name string) string {
+	for k, v := range header {
+		if strings.EqualFold(k, name) && len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// describe lists the recorded requests (for mismatch errors)
+func (c *Cassette) describe() string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, it := range c.Interactions {
+		if k := it.Request.key(); !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "\n  ")
+}
@@ -0,0 +1,71 @@
+package vcr
+
+import (
+	"net/http"
+	"nofx/endpoint"
+	"os"
+	"testing"
+)
+
+// RecordEnv set to 1 to record cassettes against the live exchanges instead of replaying them
+// (real API keys come from the test's own environment variables, they are never written to cassettes)
+const RecordEnv = "VCR_RECORD"
+
+// streamPool endpoint pool routing exchange stream hosts to the local stream server
+const streamPool = "vcr-streams"
+
+// Session a cassette in use by one test
+type Session struct {
+	Cassette  *Cassette
+	Mode      Mode
+	Transport *Transport
+}
+
+// Use plays back the cassette at path for every HTTP client created during the test, or records it when
+// VCR_RECORD=1. Exchange clients must be created after Use: they capture http.DefaultTransport when built
+// Tests using it must not run in parallel, the default transport is process-wide
+func Use(t testing.TB, path string) *Session {
+	t.Helper()
+	s := &Session{Cassette: &Cassette{}, Mode: Replay}
+	if os.Getenv(RecordEnv) == "1" {
+		s.Mode = Record
+	} else {
+		c, err := Load(path)
+		if err != nil {
+			t.Fatalf("vcr: %v (set %s=1 to record it)", err, RecordEnv)
+		}
+		s.Cassette = c
+	}
+
+	// SDKs use http.DefaultClient, whose transport may have been replaced by another client's setup
+	original, originalClient := http.DefaultTransport, http.DefaultClient.Transport
+	s.Transport = NewTransport(s.Cassette, s.Mode, original)
+	http.DefaultTransport = s.Transport
+	http.DefaultClient.Transport = s.Transport
+	t.Cleanup(func() {
+		http.DefaultTransport = original
+		http.DefaultClient.Transport = originalClient
+		for _, req := range s.Transport.Unmatched() {
+			t.Logf("vcr: unmatched request %s", req)
+		}
+		if s.Mode == Record {
+			if err := s.Cassette.Save(path); err != nil {
+				t.Errorf("vcr: failed to save cassette: %v", err)
+			}
+		}
+	})
+	return s
+}
+
+// Streams routes WebSocket connections to upstream (e.g. "wss://fstream.binance.com") to a local server
+// replaying or recording the session's frames, through an endpoint pool (clients resolve stream URLs via endpoint.Resolve)
+func (s *Session) Streams(t testing.TB, upstream string) *StreamServer {
+	t.Helper()
+	server := NewStreamServer(s.Cassette, s.Mode, upstream)
+	endpoint.Register(streamPool, "", server.URL, upstream)
+	t.Cleanup(func() {
+		endpoint.Unregister(streamPool)
+		server.Close()
+	})
+	return server
+}
@@ -0,0 +1,114 @@
+package vcr
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+
+// StreamServer local WebSocket server standing in for an exchange stream endpoint
+// Replay: every connection receives the cassette's frames in order once it subscribes, what it subscribes to is ignored
+// Record: connections are relayed to the upstream endpoint and the messages it sends are recorded as frames
+type StreamServer struct {
+	URL string // ws://127.0.0.1:port
+
+	mode     Mode
+	upstream string // e.g. wss://fstream.binance.com
+	server   *httptest.Server
+
+	mu       sync.Mutex
+	cassette *Cassette
+}
+
+// NewStreamServer starts a stream server over cassette
+func NewStreamServer(cassette *Cassette, mode Mode, upstream string) *StreamServer {
+	s := &StreamServer{mode: mode, upstream: strings.TrimSuffix(upstream, "/"), cassette: cassette}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.URL = "ws" + strings.TrimPrefix(s.server.URL, "http")
+	return s
+}
+
+// Close stops the server and closes open connections
+func (s *StreamServer) Close() {
+	s.server.CloseClientConnections()
+	s.server.Close()
+}
+
+func (s *StreamServer) handle(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	if s.mode == Record {
+		s.relay(conn, s.upstream+r.URL.RequestURI())
+		return
+	}
+
+	// Exchanges only send data once subscribed, unless the streams are part of the URL
+	if r.URL.Query().Get("streams") == "" {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+	s.mu.Lock()
+	frames := append([]Frame(nil), s.cassette.Frames...)
+	s.mu.Unlock()
+	for _, f := range frames {
+		if err := conn.WriteMessage(websocket.TextMessage, f.Data); err != nil {
+			return
+		}
+	}
+	// Stay connected like an idle stream until the client leaves, so it doesn't reconnect in a loop
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// relay forwards client messages upstream and records what upstream sends back
+func (s *StreamServer) relay(client *websocket.Conn, target string) {
+	upstream, _, err := websocket.DefaultDialer.Dial(target, nil)
+	if err != nil {
+		client.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, err.Error()), time.Now().Add(time.Second))
+		return
+	}
+	defer upstream.Close()
+
+	go func() {
+		for {
+			kind, data, err := client.ReadMessage()
+			if err != nil {
+				upstream.Close()
+				return
+			}
+			if err := upstream.WriteMessage(kind, data); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		kind, data, err := upstream.ReadMessage()
+		if err != nil {
+			return
+		}
+		if json.Valid(data) {
+			s.mu.Lock()
+			s.cassette.Frames = append(s.cassette.Frames, Frame{Data: append([]byte(nil), data...)})
+			s.mu.Unlock()
+		}
+		if err := client.WriteMessage(kind, data); err != nil {
+			return
+		}
+	}
+}
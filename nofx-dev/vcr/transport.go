@@ -0,0 +1,141 @@
+package vcr
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Mode whether traffic is played back from a cassette or recorded into it
+type Mode int
+
+const (
+	// Replay serves recorded responses, requests without one fail (never reaches the network)
+	Replay Mode = iota
+	// Record forwards requests to the exchange and records them
+	Record
+)
+
+// Transport http.RoundTripper that plays back or records a cassette's HTTP interactions
+// Requests are matched on method, URL and body without volatile fields; interactions recorded
+// for the same request are served in order, the last one repeating (polling loops)
+type Transport struct {
+	Mode Mode
+	Base http.RoundTripper // Used when recording
+
+	mu        sync.Mutex
+	cassette  *Cassette
+	served    map[string]int // Request key -> times served
+	unmatched []string       // Replayed requests without a recorded interaction
+}
+
+// NewTransport creates a transport over cassette (base nil = http.DefaultTransport)
+func NewTransport(cassette *Cassette, mode Mode, base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Mode: mode, Base: base, cassette: cassette, served: make(map[string]int)}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	recorded := newRequest(req.Method, req.URL, body)
+
+	if t.Mode == Record {
+		return t.record(req, recorded)
+	}
+
+	resp, ok := t.next(recorded.key())
+	if !ok {
+		t.mu.Lock()
+		t.unmatched = append(t.unmatched, recorded.key())
+		t.mu.Unlock()
+		return nil, fmt.Errorf("vcr: no recorded interaction for %s %s", req.Method, recorded.URL)
+	}
+	return resp.httpResponse(req), nil
+}
+
+// record forwards req and appends the exchange's answer to the cassette
+func (t *Transport) record(req *http.Request, recorded Request) (*http.Response, error) {
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.mu.Lock()
+	t.cassette.Interactions = append(t.cassette.Interactions, Interaction{
+		Request:  recorded,
+		Response: newResponse(resp.StatusCode, resp.Header, body),
+	})
+	t.mu.Unlock()
+	return resp, nil
+}
+
+// next returns the response to serve for a request key
+func (t *Transport) next(key string) (Response, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var matches []Response
+	for _, it := range t.cassette.Interactions {
+		if it.Request.key() == key {
+			matches = append(matches, it.Response)
+		}
+	}
+	if len(matches) == 0 {
+		return Response{}, false
+	}
+	n := t.served[key]
+	t.served[key] = n + 1
+	return matches[min(n, len(matches)-1)], true
+}
+
+// Unmatched returns the replayed requests that had no recorded interaction
+func (t *Transport) Unmatched() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.unmatched...)
+}
+
+// httpResponse builds the response served for req
+func (r Response) httpResponse(req *http.Request) *http.Response {
+	status := r.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	header := make(http.Header)
+	for k, v := range r.Headers {
+		header.Set(k, v)
+	}
+	if header.Get("Content-Type") == "" && len(r.JSON) > 0 {
+		header.Set("Content-Type", "application/json")
+	}
+	body := r.body()
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,90 @@
+package vcr
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func get(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	calls := 0
+	exchange := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Mbx-Used-Weight-1m", "7")
+		io.WriteString(w, `{"serverTime":`+strings.Repeat("1", calls)+`}`)
+	}))
+
+	cassette := &Cassette{}
+	recorder := &http.Client{Transport: NewTransport(cassette, Record, nil)}
+	get(t, recorder, exchange.URL+"/fapi/v1/time?timestamp=1&signature=abc")
+	get(t, recorder, exchange.URL+"/fapi/v1/time?timestamp=2&signature=def")
+	exchange.Close()
+
+	if len(cassette.Interactions) != 2 || strings.Contains(cassette.Interactions[0].Request.URL, "signature") {
+		t.Fatalf("expected 2 interactions without volatile params, got %+v", cassette.Interactions)
+	}
+	if cassette.Interactions[0].Response.Headers["X-Mbx-Used-Weight-1m"] != "7" {
+		t.Errorf("expected rate limit header to be recorded, got %v", cassette.Interactions[0].Response.Headers)
+	}
+
+	// Offline: same requests with fresh timestamps are served in recorded order, the last one repeating
+	replayer := &http.Client{Transport: NewTransport(cassette, Replay, nil)}
+	for i, want := range []string{`{"serverTime":1}`, `{"serverTime":11}`, `{"serverTime":11}`} {
+		if got := get(t, replayer, exchange.URL+"/fapi/v1/time?timestamp=99&signature=zzz"); got != want {
+			t.Errorf("replay %d: expected %s, got %s", i, want, got)
+		}
+	}
+	if _, err := replayer.Get(exchange.URL + "/fapi/v2/account"); err == nil {
+		t.Error("expected an error for a request that was never recorded")
+	}
+}
+
+func TestJSONBodyMatching(t *testing.T) {
+	cassette := &Cassette{Interactions: []Interaction{{
+		Request:  Request{Method: "POST", URL: "https://api.hyperliquid.xyz/exchange", JSON: []byte(`{"action": {"type": "cancel"}, "vaultAddress": null}`)},
+		Response: Response{Status: 200, JSON: []byte(`{"status":"ok"}`)},
+	}}}
+	client := &http.Client{Transport: NewTransport(cassette, Replay, nil)}
+
+	resp, err := client.Post("https://api.hyperliquid.xyz/exchange", "application/json",
+		strings.NewReader(`{"vaultAddress":null,"nonce":1700000000000,"signature":{"r":"0x1"},"action":{"type":"cancel"}}`))
+	if err != nil {
+		t.Fatalf("expected the recorded interaction to match regardless of key order and nonce, got %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestStreamReplay(t *testing.T) {
+	cassette := &Cassette{Frames: []Frame{{Data: []byte(`{"stream":"a","data":1}`)}, {Data: []byte(`{"stream":"a","data":2}`)}}}
+	server := NewStreamServer(cassette, Replay, "wss://fstream.binance.com")
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial(server.URL+"/stream", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	conn.WriteJSON(map[string]any{"method": "SUBSCRIBE", "params": []string{"a"}, "id": 1})
+	for _, want := range []string{`{"stream":"a","data":1}`, `{"stream":"a","data":2}`} {
+		_, data, err := conn.ReadMessage()
+		if err != nil || string(data) != want {
+			t.Fatalf("expected %s, got %s (%v)", want, data, err)
+		}
+	}
+}
@@ -93,8 +93,16 @@ const AI_PROVIDER_CONFIG: Record<string, {
     apiUrl: 'https://platform.moonshot.ai/console/api-keys',
     apiName: 'Moonshot',
   },
+  ollama: {
+    defaultModel: 'qwen2.5:14b',
+    apiUrl: 'https://ollama.com/download',
+    apiName: 'Ollama',
+  },
 }
 
+// Providers running locally, API Key optional
+const KEYLESS_PROVIDERS = ['ollama']
+
 interface AITradersPageProps {
   onTraderSelect?: (traderId: string) => void
 }
@@ -312,6 +320,8 @@ export function AITradersPage({ onTraderSelect }: AITradersPageProps) {
         exchange_id: data.exchange_id,
         initial_balance: data.initial_balance,
         scan_interval_minutes: data.scan_interval_minutes,
+        max_position_age_minutes: data.max_position_age_minutes,
+        max_position_age_action: data.max_position_age_action,
         btc_eth_leverage: data.btc_eth_leverage,
         altcoin_leverage: data.altcoin_leverage,
         trading_symbols: data.trading_symbols,
@@ -1304,9 +1314,11 @@ function ModelConfigModal({
     }
   }, [editingModelId, selectedModel])
 
+  const keyOptional = !!selectedModel && KEYLESS_PROVIDERS.includes(selectedModel.provider)
+
   const handleSubmit = (e: React.FormEvent) => {
     e.preventDefault()
-    if (!selectedModelId || !apiKey.trim()) return
+    if (!selectedModelId || (!apiKey.trim() && !keyOptional)) return
 
     onSave(
       selectedModelId,
@@ -1439,6 +1451,11 @@ function ModelConfigModal({
                         ⚠️ {t('kimiApiNote', language)}
                       </div>
                     )}
+                    {selectedModel.provider === 'ollama' && (
+                      <div className="mt-2 text-xs p-2 rounded" style={{ background: 'rgba(240, 185, 11, 0.1)', color: '#F0B90B' }}>
+                        💻 {t('ollamaNote', language)}
+                      </div>
+                    )}
                   </div>
                 )}
               </div>
@@ -1451,7 +1468,7 @@ function ModelConfigModal({
                     className="block text-sm font-semibold mb-2"
                     style={{ color: '#EAECEF' }}
                   >
-                    API Key
+                    {keyOptional ? t('apiKeyOptional', language) : 'API Key'}
                   </label>
                   <input
                     type="password"
@@ -1464,7 +1481,7 @@ function ModelConfigModal({
                       border: '1px solid #2B3139',
                       color: '#EAECEF',
                     }}
-                    required
+                    required={!keyOptional}
                   />
                 </div>
 
@@ -1556,7 +1573,7 @@ function ModelConfigModal({
             </button>
             <button
               type="submit"
-              disabled={!selectedModel || !apiKey.trim()}
+              disabled={!selectedModel || (!apiKey.trim() && !keyOptional)}
               className="flex-1 px-4 py-2 rounded text-sm font-semibold disabled:opacity-50"
               style={{ background: '#F0B90B', color: '#000' }}
             >
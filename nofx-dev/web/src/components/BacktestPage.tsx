@@ -51,6 +51,10 @@ export function BacktestPage() {
     slippage: 2,
     btcEthLeverage: 5,
     altcoinLeverage: 5,
+    atrStopMultiplier: 0,
+    riskPerTradePct: 0,
+    volTargetAtrPct: 0,
+    stopLossCooldownBars: 0,
     fill: 'next_open',
     prompt: 'baseline',
     promptTemplate: 'default',
@@ -254,6 +258,10 @@ export function BacktestPage() {
           btc_eth_leverage: Number(formState.btcEthLeverage),
           altcoin_leverage: Number(formState.altcoinLeverage),
         },
+        atr_stop_multiplier: Number(formState.atrStopMultiplier) || undefined,
+        risk_per_trade_pct: Number(formState.riskPerTradePct) || undefined,
+        vol_target_atr_pct: Number(formState.volTargetAtrPct) || undefined,
+        stop_loss_cooldown_bars: Number(formState.stopLossCooldownBars) || undefined,
       })
       setToast({ text: tr('toasts.startSuccess', { id: payload.run_id }), tone: 'success' })
       setSelectedRunId(payload.run_id)
@@ -719,7 +727,7 @@ export function BacktestPage() {
             </label>
           </div>
 
-          <div className="grid grid-cols-1 gap-2 text-xs md:grid-cols-2">
+          <div className="grid grid-cols-1 gap-2 text-xs md:grid-cols-4">
             <label className="flex flex-col gap-1">
               <span>{tr('form.btcEthLeverageLabel')}</span>
               <input
@@ -746,6 +754,59 @@ export function BacktestPage() {
                 }
               />
             </label>
+            <label className="flex flex-col gap-1">
+              <span>{tr('form.atrStopLabel')}</span>
+              <input
+                type="number"
+                className="input"
+                min={0}
+                step={0.1}
+                value={formState.atrStopMultiplier}
+                onChange={(e) =>
+                  handleFormChange('atrStopMultiplier', Number(e.target.value))
+                }
+              />
+            </label>
+            <label className="flex flex-col gap-1">
+              <span>{tr('form.riskPerTradeLabel')}</span>
+              <input
+                type="number"
+                className="input"
+                min={0}
+                max={100}
+                step={0.1}
+                value={formState.riskPerTradePct}
+                onChange={(e) =>
+                  handleFormChange('riskPerTradePct', Number(e.target.value))
+                }
+              />
+            </label>
+            <label className="flex flex-col gap-1">
+              <span>{tr('form.volTargetLabel')}</span>
+              <input
+                type="number"
+                className="input"
+                min={0}
+                step={0.1}
+                value={formState.volTargetAtrPct}
+                onChange={(e) =>
+                  handleFormChange('volTargetAtrPct', Number(e.target.value))
+                }
+              />
+            </label>
+            <label className="flex flex-col gap-1">
+              <span>{tr('form.stopCooldownLabel')}</span>
+              <input
+                type="number"
+                className="input"
+                min={0}
+                step={1}
+                value={formState.stopLossCooldownBars}
+                onChange={(e) =>
+                  handleFormChange('stopLossCooldownBars', Number(e.target.value))
+                }
+              />
+            </label>
           </div>
 
           <label className="flex flex-col gap-1 text-xs">
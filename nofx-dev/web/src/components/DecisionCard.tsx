@@ -116,7 +116,8 @@ export function DecisionCard({ decision, language }: DecisionCardProps) {
               <span
                 className="px-2 py-0.5 rounded text-xs font-bold"
                 style={
-                  action.action.includes('open')
+                  action.action.includes('open') ||
+                  action.action.startsWith('add_')
                     ? {
                         background: 'rgba(96, 165, 250, 0.1)',
                         color: '#60a5fa',
@@ -34,6 +34,8 @@ interface FormState {
   is_cross_margin: boolean
   show_in_competition: boolean
   scan_interval_minutes: number
+  max_position_age_minutes: number
+  max_position_age_action: 'close' | 'review'
   initial_balance?: number
 }
 
@@ -65,6 +67,8 @@ export function TraderConfigModal({
     is_cross_margin: true,
     show_in_competition: true,
     scan_interval_minutes: 3,
+    max_position_age_minutes: 0,
+    max_position_age_action: 'close',
   })
   const [isSaving, setIsSaving] = useState(false)
   const [strategies, setStrategies] = useState<Strategy[]>([])
@@ -103,6 +107,8 @@ export function TraderConfigModal({
       setFormData({
         ...traderData,
         strategy_id: traderData.strategy_id || '',
+        max_position_age_minutes: traderData.max_position_age_minutes || 0,
+        max_position_age_action: traderData.max_position_age_action || 'close',
       })
     } else if (!isEditMode) {
       setFormData({
@@ -113,6 +119,8 @@ export function TraderConfigModal({
         is_cross_margin: true,
         show_in_competition: true,
         scan_interval_minutes: 3,
+        max_position_age_minutes: 0,
+        max_position_age_action: 'close',
       })
     }
   }, [traderData, isEditMode, availableModels, availableExchanges])
@@ -167,6 +175,8 @@ export function TraderConfigModal({
         is_cross_margin: formData.is_cross_margin,
         show_in_competition: formData.show_in_competition,
         scan_interval_minutes: formData.scan_interval_minutes,
+        max_position_age_minutes: formData.max_position_age_minutes,
+        max_position_age_action: formData.max_position_age_action,
       }
 
       // 只在编辑模式时包含initial_balance
@@ -369,7 +379,8 @@ export function TraderConfigModal({
                     <div>
                       币种来源: {selectedStrategy.config.coin_source.source_type === 'static' ? '固定币种' :
                         selectedStrategy.config.coin_source.source_type === 'coinpool' ? 'Coin Pool' :
-                        selectedStrategy.config.coin_source.source_type === 'oi_top' ? 'OI Top' : '混合'}
+                        selectedStrategy.config.coin_source.source_type === 'oi_top' ? 'OI Top' :
+                        selectedStrategy.config.coin_source.source_type === 'top_movers' ? 'Top Movers' : '混合'}
                     </div>
                     <div>
                       保证金上限: {((selectedStrategy.config.risk_control?.max_margin_usage || 0.9) * 100).toFixed(0)}%
@@ -443,6 +454,60 @@ export function TraderConfigModal({
                 </div>
               </div>
 
+              {/* Max position age */}
+              <div className="grid grid-cols-2 gap-4">
+                <div>
+                  <label className="text-sm text-[#EAECEF] block mb-2">
+                    {t('maxPositionAge', language)}
+                  </label>
+                  <input
+                    type="number"
+                    value={formData.max_position_age_minutes}
+                    onChange={(e) => {
+                      const parsedValue = Number(e.target.value)
+                      const safeValue = Number.isFinite(parsedValue)
+                        ? Math.max(0, Math.floor(parsedValue))
+                        : 0
+                      handleInputChange('max_position_age_minutes', safeValue)
+                    }}
+                    className="w-full px-3 py-2 bg-[#0B0E11] border border-[#2B3139] rounded text-[#EAECEF] focus:border-[#F0B90B] focus:outline-none"
+                    min="0"
+                    step="30"
+                  />
+                  <p className="text-xs text-gray-500 mt-1">
+                    {t('maxPositionAgeHint', language)}
+                  </p>
+                </div>
+                <div>
+                  <label className="text-sm text-[#EAECEF] block mb-2">
+                    {t('maxPositionAgeAction', language)}
+                  </label>
+                  <div className="flex gap-2">
+                    {(['close', 'review'] as const).map((action) => (
+                      <button
+                        key={action}
+                        type="button"
+                        onClick={() =>
+                          handleInputChange('max_position_age_action', action)
+                        }
+                        className={`flex-1 px-3 py-2 rounded text-sm ${
+                          formData.max_position_age_action === action
+                            ? 'bg-[#F0B90B] text-black'
+                            : 'bg-[#0B0E11] text-[#848E9C] border border-[#2B3139]'
+                        }`}
+                      >
+                        {t(
+                          action === 'close'
+                            ? 'maxPositionAgeClose'
+                            : 'maxPositionAgeReview',
+                          language
+                        )}
+                      </button>
+                    ))}
+                  </div>
+                </div>
+              </div>
+
               {/* Competition visibility */}
               <div>
                 <label className="text-sm text-[#EAECEF] block mb-2">
@@ -1,5 +1,5 @@
 import { useState } from 'react'
-import { Plus, X, Database, TrendingUp, List, Link, AlertCircle } from 'lucide-react'
+import { Plus, X, Database, TrendingUp, List, Link, AlertCircle, Zap } from 'lucide-react'
 import type { CoinSourceConfig } from '../../types'
 
 // Default API URLs for data sources
@@ -20,6 +20,7 @@ export function CoinSourceEditor({
   language,
 }: CoinSourceEditorProps) {
   const [newCoin, setNewCoin] = useState('')
+  const [newWatchCoin, setNewWatchCoin] = useState('')
 
   const t = (key: string) => {
     const translations: Record<string, Record<string, string>> = {
@@ -27,6 +28,10 @@ export function CoinSourceEditor({
       static: { zh: '静态列表', en: 'Static List' },
       coinpool: { zh: 'AI500 币种池', en: 'AI500 Coin Pool' },
       oi_top: { zh: 'OI Top 持仓增长', en: 'OI Top' },
+      top_movers: { zh: '异动扫描', en: 'Top Movers' },
+      top_moversDesc: { zh: '全市场按 1h/4h 涨跌幅与放量排序', en: 'All futures ranked by 1h/4h change and volume spike' },
+      useTopMovers: { zh: '启用异动扫描', en: 'Enable Top Movers' },
+      topMoversLimit: { zh: '异动币种数量上限', en: 'Top Movers Limit' },
       mixed: { zh: '混合模式', en: 'Mixed Mode' },
       staticCoins: { zh: '自定义币种', en: 'Custom Coins' },
       addCoin: { zh: '添加币种', en: 'Add Coin' },
@@ -54,6 +59,24 @@ export function CoinSourceEditor({
       apiUrlRequired: { zh: '需要填写 API URL 才能获取数据', en: 'API URL required to fetch data' },
       dataSourceConfig: { zh: '数据源配置', en: 'Data Source Configuration' },
       fillDefault: { zh: '填入默认', en: 'Fill Default' },
+      candidateHalfLife: { zh: '闲置候选币半衰期（周期）', en: 'Idle Candidate Half-Life (cycles)' },
+      candidateHalfLifeDesc: {
+        zh: 'AI 连续多个周期未操作的候选币会逐渐从提示词中移除，0 表示关闭',
+        en: 'Candidates the AI keeps ignoring decay out of the prompt, 0 disables',
+      },
+      includeCategories: { zh: '仅扫描类别', en: 'Only Categories' },
+      excludeCategories: { zh: '排除类别', en: 'Exclude Categories' },
+      categoryFilterDesc: {
+        zh: '按合约类别筛选候选币（不选表示全部），无元数据的币种保留',
+        en: 'Filter candidates by contract category (none selected = all), symbols without metadata are kept',
+      },
+      minListingDays: { zh: '最短上线天数', en: 'Min Listing Age (days)' },
+      minListingDaysDesc: { zh: '跳过上线不足该天数的新合约，0 表示关闭', en: 'Skip contracts listed fewer days ago, 0 disables' },
+      watchOnlyCoins: { zh: '仅观察币种', en: 'Watch-Only Coins' },
+      watchOnlyCoinsDesc: {
+        zh: 'AI 每个周期分析这些币种并通过通知发送提醒（突破、OI 激增等），但从不下单，适合在开放交易前评估模型',
+        en: 'Analyzed every cycle with alerts (breakout, OI surge...) sent as notifications, never traded. Useful to evaluate the model on new coins before enabling trading',
+      },
     }
     return translations[key]?.[language] || key
   }
@@ -62,9 +85,21 @@ export function CoinSourceEditor({
     { value: 'static', icon: List, color: '#848E9C' },
     { value: 'coinpool', icon: Database, color: '#F0B90B' },
     { value: 'oi_top', icon: TrendingUp, color: '#0ECB81' },
+    { value: 'top_movers', icon: Zap, color: '#f472b6' },
     { value: 'mixed', icon: Database, color: '#60a5fa' },
   ] as const
 
+  const categories = ['meme', 'l1', 'l2', 'defi', 'ai', 'gaming', 'other']
+
+  const toggleCategory = (field: 'include_categories' | 'exclude_categories', category: string) => {
+    if (disabled) return
+    const current = config[field] || []
+    const next = current.includes(category)
+      ? current.filter((c) => c !== category)
+      : [...current, category]
+    onChange({ ...config, [field]: next })
+  }
+
   const handleAddCoin = () => {
     if (!newCoin.trim()) return
     const symbol = newCoin.toUpperCase().trim()
@@ -86,6 +121,27 @@ export function CoinSourceEditor({
     })
   }
 
+  const handleAddWatchCoin = () => {
+    if (!newWatchCoin.trim()) return
+    const symbol = newWatchCoin.toUpperCase().trim()
+    const formattedSymbol = symbol.endsWith('USDT') ? symbol : `${symbol}USDT`
+    const currentCoins = config.watch_only_coins || []
+    if (!currentCoins.includes(formattedSymbol)) {
+      onChange({
+        ...config,
+        watch_only_coins: [...currentCoins, formattedSymbol],
+      })
+    }
+    setNewWatchCoin('')
+  }
+
+  const handleRemoveWatchCoin = (coin: string) => {
+    onChange({
+      ...config,
+      watch_only_coins: (config.watch_only_coins || []).filter((c) => c !== coin),
+    })
+  }
+
   return (
     <div className="space-y-6">
       {/* Source Type Selector */}
@@ -93,7 +149,7 @@ export function CoinSourceEditor({
         <label className="block text-sm font-medium mb-3" style={{ color: '#EAECEF' }}>
           {t('sourceType')}
         </label>
-        <div className="grid grid-cols-4 gap-3">
+        <div className="grid grid-cols-5 gap-3">
           {sourceTypes.map(({ value, icon: Icon, color }) => (
             <button
               key={value}
@@ -371,6 +427,191 @@ export function CoinSourceEditor({
           )}
         </div>
       )}
+
+      {/* Top Movers Options */}
+      {(config.source_type === 'top_movers' || config.source_type === 'mixed') && (
+        <div className="flex items-center gap-6">
+          {config.source_type === 'mixed' && (
+            <label className="flex items-center gap-3 cursor-pointer">
+              <input
+                type="checkbox"
+                checked={config.use_top_movers || false}
+                onChange={(e) =>
+                  !disabled && onChange({ ...config, use_top_movers: e.target.checked })
+                }
+                disabled={disabled}
+                className="w-5 h-5 rounded accent-yellow-500"
+              />
+              <span style={{ color: '#EAECEF' }}>{t('useTopMovers')}</span>
+            </label>
+          )}
+          {(config.source_type === 'top_movers' || config.use_top_movers) && (
+            <div className="flex items-center gap-3">
+              <span className="text-sm" style={{ color: '#848E9C' }}>
+                {t('topMoversLimit')}:
+              </span>
+              <input
+                type="number"
+                value={config.top_movers_limit || 10}
+                onChange={(e) =>
+                  !disabled &&
+                  onChange({ ...config, top_movers_limit: parseInt(e.target.value) || 10 })
+                }
+                disabled={disabled}
+                min={1}
+                max={50}
+                className="w-20 px-3 py-1.5 rounded"
+                style={{
+                  background: '#0B0E11',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+            </div>
+          )}
+        </div>
+      )}
+
+      {/* Idle candidate decay */}
+      <div>
+        <div className="flex items-center gap-3">
+          <span className="text-sm" style={{ color: '#848E9C' }}>
+            {t('candidateHalfLife')}:
+          </span>
+          <input
+            type="number"
+            value={config.candidate_half_life || 0}
+            onChange={(e) =>
+              !disabled &&
+              onChange({ ...config, candidate_half_life: Math.max(0, parseInt(e.target.value) || 0) })
+            }
+            disabled={disabled}
+            min={0}
+            max={100}
+            className="w-20 px-3 py-1.5 rounded"
+            style={{
+              background: '#0B0E11',
+              border: '1px solid #2B3139',
+              color: '#EAECEF',
+            }}
+          />
+        </div>
+        <p className="text-xs mt-1" style={{ color: '#848E9C' }}>
+          {t('candidateHalfLifeDesc')}
+        </p>
+      </div>
+
+      {/* Category / listing age filters */}
+      <div className="space-y-2">
+        {(['include_categories', 'exclude_categories'] as const).map((field) => (
+          <div key={field} className="flex flex-wrap items-center gap-2">
+            <span className="text-sm" style={{ color: '#848E9C' }}>
+              {t(field === 'include_categories' ? 'includeCategories' : 'excludeCategories')}:
+            </span>
+            {categories.map((category) => {
+              const active = (config[field] || []).includes(category)
+              return (
+                <button
+                  key={category}
+                  type="button"
+                  onClick={() => toggleCategory(field, category)}
+                  disabled={disabled}
+                  className="px-2 py-1 rounded text-xs"
+                  style={{
+                    background: active ? 'rgba(240, 185, 11, 0.15)' : '#0B0E11',
+                    border: `1px solid ${active ? '#F0B90B' : '#2B3139'}`,
+                    color: active ? '#F0B90B' : '#848E9C',
+                  }}
+                >
+                  {category}
+                </button>
+              )
+            })}
+          </div>
+        ))}
+        <p className="text-xs" style={{ color: '#848E9C' }}>
+          {t('categoryFilterDesc')}
+        </p>
+        <div className="flex items-center gap-3">
+          <span className="text-sm" style={{ color: '#848E9C' }}>
+            {t('minListingDays')}:
+          </span>
+          <input
+            type="number"
+            value={config.min_listing_days || 0}
+            onChange={(e) =>
+              !disabled &&
+              onChange({ ...config, min_listing_days: Math.max(0, parseInt(e.target.value) || 0) })
+            }
+            disabled={disabled}
+            min={0}
+            max={365}
+            className="w-20 px-3 py-1.5 rounded"
+            style={{
+              background: '#0B0E11',
+              border: '1px solid #2B3139',
+              color: '#EAECEF',
+            }}
+          />
+        </div>
+        <p className="text-xs" style={{ color: '#848E9C' }}>
+          {t('minListingDaysDesc')}
+        </p>
+      </div>
+
+      {/* Watch-Only Coins */}
+      <div>
+        <label className="block text-sm font-medium mb-1" style={{ color: '#EAECEF' }}>
+          {t('watchOnlyCoins')}
+        </label>
+        <p className="text-xs mb-3" style={{ color: '#848E9C' }}>
+          {t('watchOnlyCoinsDesc')}
+        </p>
+        <div className="flex flex-wrap gap-2 mb-3">
+          {(config.watch_only_coins || []).map((coin) => (
+            <span
+              key={coin}
+              className="flex items-center gap-1 px-3 py-1.5 rounded-full text-sm"
+              style={{ background: '#2B3139', color: '#EAECEF' }}
+            >
+              {coin}
+              {!disabled && (
+                <button
+                  onClick={() => handleRemoveWatchCoin(coin)}
+                  className="ml-1 hover:text-red-400 transition-colors"
+                >
+                  <X className="w-3 h-3" />
+                </button>
+              )}
+            </span>
+          ))}
+        </div>
+        {!disabled && (
+          <div className="flex gap-2">
+            <input
+              type="text"
+              value={newWatchCoin}
+              onChange={(e) => setNewWatchCoin(e.target.value)}
+              onKeyDown={(e) => e.key === 'Enter' && handleAddWatchCoin()}
+              placeholder="PEPE, WIF..."
+              className="flex-1 px-4 py-2 rounded-lg"
+              style={{
+                background: '#0B0E11',
+                border: '1px solid #2B3139',
+                color: '#EAECEF',
+              }}
+            />
+            <button
+              onClick={handleAddWatchCoin}
+              className="px-4 py-2 rounded-lg flex items-center gap-2 transition-colors"
+              style={{ background: '#F0B90B', color: '#0B0E11' }}
+            >
+              <Plus className="w-4 h-4" />
+              {t('addCoin')}
+            </button>
+          </div>
+        )}
+      </div>
     </div>
   )
 }
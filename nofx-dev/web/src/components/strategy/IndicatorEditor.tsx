@@ -51,6 +51,8 @@ export function IndicatorEditor({
       timeframes: { zh: '时间周期', en: 'Timeframes' },
       timeframesDesc: { zh: '选择 K 线分析周期，★ 为主周期（双击设置）', en: 'Select K-line timeframes, ★ = primary (double-click)' },
       klineCount: { zh: 'K 线数量', en: 'K-line Count' },
+      cycleOnCandleClose: { zh: '收线触发决策', en: 'Cycle on Candle Close' },
+      cycleOnCandleCloseDesc: { zh: '主周期 K 线收盘时运行决策，而非按扫描间隔', en: 'Run decisions when a primary timeframe candle closes instead of on the scan interval' },
       scalp: { zh: '超短', en: 'Scalp' },
       intraday: { zh: '日内', en: 'Intraday' },
       swing: { zh: '波段', en: 'Swing' },
@@ -70,12 +72,26 @@ export function IndicatorEditor({
       rsiDesc: { zh: '相对强弱指标', en: 'Relative Strength Index' },
       atr: { zh: 'ATR', en: 'ATR' },
       atrDesc: { zh: '真实波幅均值', en: 'Average True Range' },
+      bollinger: { zh: '布林带', en: 'Bollinger Bands' },
+      bollingerDesc: { zh: '20 周期 2σ，含 %B 与带宽', en: '20-period 2σ with %B and band width' },
+      vwap: { zh: 'VWAP', en: 'VWAP' },
+      vwapDesc: { zh: '24 小时滚动与日开盘锚定 VWAP', en: 'Rolling 24h and daily-open anchored VWAP' },
+      weeklyContext: { zh: '周线结构', en: 'Weekly Context' },
+      weeklyContextDesc: { zh: '周线趋势、EMA20/50、ATR 与近期收盘', en: 'Weekly trend, EMA20/50, ATR and recent closes' },
+      eventCalendar: { zh: '事件日历', en: 'Event Calendar' },
+      eventCalendarDesc: { zh: '未来 24 小时内的 FOMC、CPI 等宏观数据与代币解锁', en: 'FOMC, CPI and other macro releases and token unlocks in the next 24h' },
+      toolCalling: { zh: '工具调用', en: 'Tool Calling' },
+      toolCallingDesc: { zh: '精简提示词，AI 按需获取K线、盘口与历史成交', en: 'Compact prompt, the AI fetches klines, order book and trade history on demand' },
+      tradeMemory: { zh: '交易记忆', en: 'Trade Memory' },
+      tradeMemoryDesc: { zh: '检索入场行情最相似的历史交易及其结果', en: 'Recall past trades with the most similar entry conditions and their outcome' },
       volume: { zh: '成交量', en: 'Volume' },
       volumeDesc: { zh: '交易量分析', en: 'Trading volume analysis' },
       oi: { zh: '持仓量', en: 'Open Interest' },
       oiDesc: { zh: '合约未平仓量', en: 'Futures open interest' },
       fundingRate: { zh: '资金费率', en: 'Funding Rate' },
       fundingRateDesc: { zh: '永续合约资金费率', en: 'Perpetual funding rate' },
+      orderBook: { zh: '盘口深度', en: 'Order Book' },
+      orderBookDesc: { zh: '价差、前10档深度与买卖失衡', en: 'Spread, top-10 depth and imbalance' },
 
       // Quant data
       quantDataUrl: { zh: '数据接口 URL', en: 'Data API URL' },
@@ -260,6 +276,27 @@ export function IndicatorEditor({
                 )
               })}
             </div>
+
+            {/* Decision cycle trigger */}
+            <label className="flex items-center justify-between mt-3 cursor-pointer">
+              <div>
+                <div className="text-xs font-medium" style={{ color: '#EAECEF' }}>{t('cycleOnCandleClose')}</div>
+                <div className="text-[10px]" style={{ color: '#5E6673' }}>{t('cycleOnCandleCloseDesc')}</div>
+              </div>
+              <input
+                type="checkbox"
+                checked={config.klines.cycle_on_candle_close || false}
+                onChange={(e) =>
+                  !disabled &&
+                  onChange({
+                    ...config,
+                    klines: { ...config.klines, cycle_on_candle_close: e.target.checked },
+                  })
+                }
+                disabled={disabled}
+                className="w-4 h-4 rounded accent-yellow-500"
+              />
+            </label>
           </div>
         </div>
       </div>
@@ -286,6 +323,12 @@ export function IndicatorEditor({
               { key: 'enable_macd', label: 'macd', desc: 'macdDesc', color: '#a855f7' },
               { key: 'enable_rsi', label: 'rsi', desc: 'rsiDesc', color: '#F6465D', periodKey: 'rsi_periods', defaultPeriods: '7,14' },
               { key: 'enable_atr', label: 'atr', desc: 'atrDesc', color: '#60a5fa', periodKey: 'atr_periods', defaultPeriods: '14' },
+              { key: 'enable_bollinger', label: 'bollinger', desc: 'bollingerDesc', color: '#f472b6' },
+              { key: 'enable_vwap', label: 'vwap', desc: 'vwapDesc', color: '#fb923c' },
+              { key: 'enable_weekly_context', label: 'weeklyContext', desc: 'weeklyContextDesc', color: '#0ECB81' },
+              { key: 'enable_event_calendar', label: 'eventCalendar', desc: 'eventCalendarDesc', color: '#60a5fa' },
+              { key: 'enable_tool_calling', label: 'toolCalling', desc: 'toolCallingDesc', color: '#a855f7' },
+              { key: 'enable_trade_memory', label: 'tradeMemory', desc: 'tradeMemoryDesc', color: '#0ECB81' },
             ].map(({ key, label, desc, color, periodKey, defaultPeriods }) => (
               <div
                 key={key}
@@ -347,6 +390,7 @@ export function IndicatorEditor({
               { key: 'enable_volume', label: 'volume', desc: 'volumeDesc', color: '#c084fc' },
               { key: 'enable_oi', label: 'oi', desc: 'oiDesc', color: '#34d399' },
               { key: 'enable_funding_rate', label: 'fundingRate', desc: 'fundingRateDesc', color: '#fbbf24' },
+              { key: 'enable_order_book', label: 'orderBook', desc: 'orderBookDesc', color: '#60a5fa' },
             ].map(({ key, label, desc, color }) => (
               <div
                 key={key}
@@ -66,6 +66,10 @@ export function PromptSectionsEditor({
       entryStandardsDesc: { zh: '定义开仓信号条件和避免事项', en: 'Define entry signal conditions and avoidances' },
       decisionProcess: { zh: '决策流程', en: 'Decision Process' },
       decisionProcessDesc: { zh: '设定决策步骤和思考流程', en: 'Set decision steps and thinking process' },
+      templateHint: {
+        zh: '支持 Go 模板变量：{{.Equity}}、{{.Risk.MaxPositions}}、{{.Risk.AltcoinMaxLeverage}}、{{join .Symbols ", "}}，以及 {{template "片段名" .}} 引用策略中的可复用片段',
+        en: 'Supports Go template variables: {{.Equity}}, {{.Risk.MaxPositions}}, {{.Risk.AltcoinMaxLeverage}}, {{join .Symbols ", "}}, and {{template "partial" .}} to include the strategy\'s reusable partials',
+      },
       resetToDefault: { zh: '重置为默认', en: 'Reset to Default' },
       chars: { zh: '字符', en: 'chars' },
     }
@@ -112,6 +116,9 @@ export function PromptSectionsEditor({
           <p className="text-xs mt-1" style={{ color: '#848E9C' }}>
             {t('promptSectionsDesc')}
           </p>
+          <p className="text-[10px] mt-1 font-mono" style={{ color: '#848E9C' }}>
+            {t('templateHint')}
+          </p>
         </div>
       </div>
 
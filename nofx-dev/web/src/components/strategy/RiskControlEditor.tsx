@@ -35,6 +35,45 @@ export function RiskControlEditor({
       riskParameters: { zh: '风险参数', en: 'Risk Parameters' },
       minRiskReward: { zh: '最小风险回报比', en: 'Min Risk/Reward Ratio' },
       minRiskRewardDesc: { zh: '开仓要求的最低盈亏比', en: 'Minimum profit ratio for opening' },
+      trailingStop: { zh: '默认移动止损', en: 'Default Trailing Stop' },
+      trailingStopDesc: { zh: '距最优价格的回撤百分比，AI 未指定时使用（0 = 关闭）', en: 'Retrace % from best price, used when AI sets none (0 = off)' },
+      breakEven: { zh: '保本止损', en: 'Break-even Stop' },
+      breakEvenDesc: { zh: '杠杆后浮盈达到此比例时将止损移至开仓价（含双边手续费 + 缓冲，0 = 关闭）', en: 'Move stop to entry (+ round-trip fees + buffer) once leveraged profit reaches this % (0 = off)' },
+      breakEvenBuffer: { zh: '缓冲', en: 'Buffer' },
+      stopSnap: { zh: '止损避开流动性位', en: 'Stop Placement' },
+      stopSnapDesc: { zh: '将 AI 止损移至附近整数关口、订单簿大单墙和成交密集区之外，只会远离价格（止损价的 %，0 = 关闭）', en: 'Move AI stops beyond nearby round numbers, order book walls and volume nodes, only ever away from price (% of stop, 0 = off)' },
+      atrStop: { zh: 'ATR 动态止损', en: 'ATR Stop Distance' },
+      atrStopDesc: { zh: '新开仓止损设在入场价 ± 倍数 × 主周期 ATR14，替代 AI 给出的止损（0 = 关闭）', en: 'Place new position stops this many ATR14 (primary timeframe) from entry instead of the AI stop (0 = off)' },
+      riskPerTrade: { zh: '单笔风险', en: 'Risk Per Trade' },
+      volTarget: { zh: '波动率自适应杠杆', en: 'Volatility-Adaptive Leverage' },
+      volTargetDesc: { zh: '新开仓杠杆 = 最大杠杆 × 目标波动 / 主周期 ATR14 占价格比例，波动放大时降杠杆、平静时回升，不超过最大杠杆（%，0 = 关闭）', en: 'New position leverage = max leverage × target / ATR14 % of price (primary timeframe): lower in volatility spikes, back up to the max when calm (%, 0 = off)' },
+      riskPerTradeDesc: { zh: '按止损距离计算仓位，使触发止损时亏损该比例的净值，替代 AI 给出的仓位金额（净值 %，0 = 关闭）', en: 'Size positions from the stop distance so a stop-out loses this share of equity, replacing the AI size (% of equity, 0 = off)' },
+      maxSlippage: { zh: '最大预估滑点', en: 'Max Estimated Slippage' },
+      maxSlippageDesc: { zh: '开仓前根据 Binance 订单簿深度预估市价单滑点，超出时缩减仓位（bps，0 = 关闭，仅 Binance）', en: 'Market entries are sized down when Binance order book depth implies more slippage than this (bps, 0 = off, Binance only)' },
+      exposureCaps: { zh: '持仓敞口上限', en: 'Exposure Caps' },
+      exposureCapsDesc: { zh: '新开仓后名义价值超过上限时拒绝开仓（USDT，0 = 不限制）', en: 'Entries are blocked when notional after the entry would exceed a cap (USDT, 0 = no cap)' },
+      exposureTotal: { zh: '总计', en: 'Total' },
+      exposureSymbol: { zh: '单币种', en: 'Per symbol' },
+      exposureUser: { zh: '所有交易员', en: 'All traders' },
+      dailyLoss: { zh: '每日亏损熔断', en: 'Daily Loss Kill-switch' },
+      dailyLossDesc: { zh: '当日（UTC）已实现 + 未实现亏损达到当日初始净值的此比例时暂停至次日（0 = 关闭）', en: 'Pause until the next UTC day once realized + unrealized loss reaches this % of the day-start equity (0 = off)' },
+      dailyLossFlatten: { zh: '触发时平掉所有仓位', en: 'Close all positions when triggered' },
+      marginRatio: { zh: '维持保证金率监控', en: 'Margin Ratio Monitor' },
+      marginRatioDesc: { zh: '账户维持保证金 / 保证金余额达到此比例时自动减仓亏损最大的仓位并告警（100% 即强平，0 = 关闭）', en: 'Reduce the largest losing position and alert once maintenance margin / margin balance reaches this % (liquidation at 100%, 0 = off)' },
+      marginReduce: { zh: '每次减仓', en: 'Reduce by' },
+      liquidationGuard: { zh: '强平价距离保护', en: 'Liquidation Guard' },
+      liquidationGuardDesc: { zh: '标记价格距强平价小于此比例时禁止加仓（全仓模式下禁止任何新开仓），可选自动减仓（0 = 关闭）', en: 'Block adding to a position (any new entry under cross margin) once mark price is within this % of liquidation, optionally deleveraging it (0 = off)' },
+      liquidationDeleverage: { zh: '自动减仓', en: 'Deleverage by' },
+      stopLossCooldown: { zh: '止损冷却', en: 'Stop-Loss Cooldown' },
+      stopLossCooldownDesc: { zh: '某币种触发止损后，在此时间内禁止再次开仓，避免来回打脸（0 = 关闭）', en: 'Block re-entering a symbol for this long after its stop loss is hit, to avoid whipsaw re-entries (0 = off)' },
+      eventPause: { zh: '事件暂停', en: 'Event Pause' },
+      eventPauseDesc: { zh: 'FOMC、CPI 或该币种代币解锁等高影响事件前后此时间内禁止开仓（0 = 关闭）', en: 'Block new entries this long before and after a high-impact event affecting the symbol, e.g. FOMC, CPI or its token unlock (0 = off)' },
+      aiFallback: { zh: 'AI 故障兜底策略', en: 'AI Failure Fallback' },
+      aiFallbackDesc: { zh: 'AI 报错、超时或连续返回无效决策时，改用确定性规则而不是跳过本周期，记录中会标记兜底', en: 'When the AI errors, times out or keeps returning invalid decisions, a deterministic rule decides instead of skipping the cycle, flagged in the record' },
+      aiFallbackNone: { zh: '跳过本周期', en: 'Skip the cycle' },
+      aiFallbackManageStops: { zh: '仅维护现有止损', en: 'Manage existing stops only' },
+      aiFallbackEMACross: { zh: 'EMA20/EMA50 交叉', en: 'EMA20 / EMA50 cross' },
+      aiFallbackAfter: { zh: '连续失败', en: 'After failures' },
       maxMarginUsage: { zh: '最大保证金使用率（代码强制）', en: 'Max Margin Usage (CODE ENFORCED)' },
       maxMarginUsageDesc: { zh: '保证金使用率上限，由代码强制执行', en: 'Maximum margin utilization, enforced by code' },
       entryRequirements: { zh: '开仓要求', en: 'Entry Requirements' },
@@ -42,6 +81,9 @@ export function RiskControlEditor({
       minPositionSizeDesc: { zh: 'USDT 最小名义价值', en: 'Minimum notional value in USDT' },
       minConfidence: { zh: '最小信心度', en: 'Min Confidence' },
       minConfidenceDesc: { zh: 'AI 开仓信心度阈值', en: 'AI confidence threshold for entry' },
+      expectedValueGate: { zh: '期望值门槛', en: 'Expected Value Gate' },
+      expectedValueGateDesc: { zh: '按该信心度区间的历史胜率、止盈/止损距离扣除手续费和资金费率估算期望值，低于此值（占名义价值的 %）则跳过开仓', en: 'Skip entries whose expected value — historical win rate at that confidence × target vs stop distance, minus fees and funding — is below this % of notional' },
+      enabled: { zh: '启用', en: 'Enabled' },
     }
     return translations[key]?.[language] || key
   }
@@ -284,6 +326,585 @@ export function RiskControlEditor({
             </div>
           </div>
 
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('trailingStop')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('trailingStopDesc')}
+            </p>
+            <div className="flex items-center">
+              <input
+                type="number"
+                value={config.trailing_stop_pct ?? 0}
+                onChange={(e) =>
+                  updateField('trailing_stop_pct', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                max={10}
+                step={0.1}
+                className="w-20 px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-2" style={{ color: '#848E9C' }}>%</span>
+            </div>
+          </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('breakEven')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('breakEvenDesc')}
+            </p>
+            <div className="flex items-center">
+              <input
+                type="number"
+                value={config.break_even_trigger_pct ?? 0}
+                onChange={(e) =>
+                  updateField('break_even_trigger_pct', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                max={500}
+                step={1}
+                className="w-20 px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-2" style={{ color: '#848E9C' }}>%</span>
+              <span className="ml-4 text-xs" style={{ color: '#848E9C' }}>
+                {t('breakEvenBuffer')}
+              </span>
+              <input
+                type="number"
+                value={config.break_even_buffer_pct ?? 0}
+                onChange={(e) =>
+                  updateField('break_even_buffer_pct', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                max={5}
+                step={0.05}
+                className="w-20 px-3 py-2 rounded ml-2"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-2" style={{ color: '#848E9C' }}>%</span>
+            </div>
+          </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('stopSnap')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('stopSnapDesc')}
+            </p>
+            <div className="flex items-center">
+              <input
+                type="number"
+                value={config.stop_snap_tolerance_pct ?? 0}
+                onChange={(e) =>
+                  updateField('stop_snap_tolerance_pct', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                max={5}
+                step={0.1}
+                className="w-20 px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-2" style={{ color: '#848E9C' }}>%</span>
+            </div>
+          </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('atrStop')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('atrStopDesc')}
+            </p>
+            <div className="flex items-center">
+              <input
+                type="number"
+                value={config.atr_stop_multiplier ?? 0}
+                onChange={(e) =>
+                  updateField('atr_stop_multiplier', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                max={10}
+                step={0.1}
+                className="w-20 px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-2" style={{ color: '#848E9C' }}>× ATR</span>
+            </div>
+          </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('riskPerTrade')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('riskPerTradeDesc')}
+            </p>
+            <div className="flex items-center">
+              <input
+                type="number"
+                value={config.risk_per_trade_pct ?? 0}
+                onChange={(e) =>
+                  updateField('risk_per_trade_pct', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                max={10}
+                step={0.1}
+                className="w-20 px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-2" style={{ color: '#848E9C' }}>%</span>
+            </div>
+          </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('volTarget')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('volTargetDesc')}
+            </p>
+            <div className="flex items-center">
+              <input
+                type="number"
+                value={config.vol_target_atr_pct ?? 0}
+                onChange={(e) =>
+                  updateField('vol_target_atr_pct', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                max={10}
+                step={0.1}
+                className="w-20 px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-2" style={{ color: '#848E9C' }}>%</span>
+            </div>
+          </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('maxSlippage')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('maxSlippageDesc')}
+            </p>
+            <div className="flex items-center">
+              <input
+                type="number"
+                value={config.max_slippage_bps ?? 0}
+                onChange={(e) =>
+                  updateField('max_slippage_bps', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                max={500}
+                step={5}
+                className="w-20 px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-2" style={{ color: '#848E9C' }}>bps</span>
+            </div>
+          </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('exposureCaps')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('exposureCapsDesc')}
+            </p>
+            <div className="flex items-center flex-wrap">
+              <span className="text-xs" style={{ color: '#848E9C' }}>
+                {t('exposureTotal')}
+              </span>
+              <input
+                type="number"
+                value={config.max_total_exposure_usd ?? 0}
+                onChange={(e) =>
+                  updateField('max_total_exposure_usd', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                step={100}
+                className="w-24 px-3 py-2 rounded ml-2"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-4 text-xs" style={{ color: '#848E9C' }}>
+                {t('exposureSymbol')}
+              </span>
+              <input
+                type="number"
+                value={config.max_symbol_exposure_usd ?? 0}
+                onChange={(e) =>
+                  updateField('max_symbol_exposure_usd', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                step={100}
+                className="w-24 px-3 py-2 rounded ml-2"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-4 text-xs" style={{ color: '#848E9C' }}>
+                {t('exposureUser')}
+              </span>
+              <input
+                type="number"
+                value={config.max_user_exposure_usd ?? 0}
+                onChange={(e) =>
+                  updateField('max_user_exposure_usd', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                step={100}
+                className="w-24 px-3 py-2 rounded ml-2"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+            </div>
+          </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('dailyLoss')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('dailyLossDesc')}
+            </p>
+            <div className="flex items-center">
+              <input
+                type="number"
+                value={config.max_daily_loss_pct ?? 0}
+                onChange={(e) =>
+                  updateField('max_daily_loss_pct', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                max={100}
+                step={0.5}
+                className="w-20 px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-2" style={{ color: '#848E9C' }}>%</span>
+              <label className="ml-4 flex items-center text-xs" style={{ color: '#848E9C' }}>
+                <input
+                  type="checkbox"
+                  checked={config.daily_loss_flatten ?? false}
+                  onChange={(e) => updateField('daily_loss_flatten', e.target.checked)}
+                  disabled={disabled}
+                  className="mr-2"
+                />
+                {t('dailyLossFlatten')}
+              </label>
+            </div>
+          </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('marginRatio')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('marginRatioDesc')}
+            </p>
+            <div className="flex items-center">
+              <input
+                type="number"
+                value={config.max_margin_ratio_pct ?? 0}
+                onChange={(e) =>
+                  updateField('max_margin_ratio_pct', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                max={100}
+                step={5}
+                className="w-20 px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-2" style={{ color: '#848E9C' }}>%</span>
+              <span className="ml-4 text-xs" style={{ color: '#848E9C' }}>
+                {t('marginReduce')}
+              </span>
+              <input
+                type="number"
+                value={config.margin_reduce_pct ?? 25}
+                onChange={(e) =>
+                  updateField('margin_reduce_pct', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={1}
+                max={100}
+                step={5}
+                className="w-20 px-3 py-2 rounded ml-2"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-2" style={{ color: '#848E9C' }}>%</span>
+            </div>
+          </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('liquidationGuard')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('liquidationGuardDesc')}
+            </p>
+            <div className="flex items-center">
+              <input
+                type="number"
+                value={config.liquidation_guard_pct ?? 0}
+                onChange={(e) =>
+                  updateField('liquidation_guard_pct', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                max={50}
+                step={1}
+                className="w-20 px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-2" style={{ color: '#848E9C' }}>%</span>
+              <span className="ml-4 text-xs" style={{ color: '#848E9C' }}>
+                {t('liquidationDeleverage')}
+              </span>
+              <input
+                type="number"
+                value={config.liquidation_deleverage_pct ?? 0}
+                onChange={(e) =>
+                  updateField('liquidation_deleverage_pct', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                max={100}
+                step={5}
+                className="w-20 px-3 py-2 rounded ml-2"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-2" style={{ color: '#848E9C' }}>%</span>
+            </div>
+          </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('stopLossCooldown')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('stopLossCooldownDesc')}
+            </p>
+            <div className="flex items-center">
+              <input
+                type="number"
+                value={config.stop_loss_cooldown_minutes ?? 0}
+                onChange={(e) =>
+                  updateField('stop_loss_cooldown_minutes', parseInt(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                max={1440}
+                step={5}
+                className="w-20 px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-2" style={{ color: '#848E9C' }}>min</span>
+            </div>
+          </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('eventPause')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('eventPauseDesc')}
+            </p>
+            <div className="flex items-center">
+              <input
+                type="number"
+                value={config.event_pause_minutes ?? 0}
+                onChange={(e) =>
+                  updateField('event_pause_minutes', parseInt(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                max={240}
+                step={5}
+                className="w-20 px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-2" style={{ color: '#848E9C' }}>min</span>
+            </div>
+          </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('aiFallback')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('aiFallbackDesc')}
+            </p>
+            <div className="flex items-center">
+              <select
+                value={config.ai_fallback_mode ?? ''}
+                onChange={(e) =>
+                  updateField('ai_fallback_mode', e.target.value as RiskControlConfig['ai_fallback_mode'])
+                }
+                disabled={disabled}
+                className="px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              >
+                <option value="">{t('aiFallbackNone')}</option>
+                <option value="manage_stops">{t('aiFallbackManageStops')}</option>
+                <option value="ema_cross">{t('aiFallbackEMACross')}</option>
+              </select>
+              <span className="ml-4 text-xs" style={{ color: '#848E9C' }}>
+                {t('aiFallbackAfter')}
+              </span>
+              <input
+                type="number"
+                value={config.ai_fallback_after_failures ?? 0}
+                onChange={(e) =>
+                  updateField('ai_fallback_after_failures', parseInt(e.target.value) || 0)
+                }
+                disabled={disabled || !config.ai_fallback_mode}
+                min={0}
+                max={20}
+                step={1}
+                className="w-20 px-3 py-2 rounded ml-2"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+            </div>
+          </div>
+
           <div
             className="p-4 rounded-lg"
             style={{ background: '#0B0E11', border: '1px solid #0ECB81' }}
@@ -384,6 +1005,48 @@ export function RiskControlEditor({
               </span>
             </div>
           </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('expectedValueGate')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('expectedValueGateDesc')}
+            </p>
+            <div className="flex items-center">
+              <input
+                type="number"
+                value={config.min_expected_value_pct ?? 0}
+                onChange={(e) =>
+                  updateField('min_expected_value_pct', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled || !config.expected_value_gate}
+                min={-5}
+                max={10}
+                step={0.05}
+                className="w-20 px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span className="ml-2" style={{ color: '#848E9C' }}>%</span>
+              <label className="ml-4 flex items-center text-xs" style={{ color: '#848E9C' }}>
+                <input
+                  type="checkbox"
+                  checked={config.expected_value_gate ?? false}
+                  onChange={(e) => updateField('expected_value_gate', e.target.checked)}
+                  disabled={disabled}
+                  className="mr-2"
+                />
+                {t('enabled')}
+              </label>
+            </div>
+          </div>
         </div>
       </div>
     </div>
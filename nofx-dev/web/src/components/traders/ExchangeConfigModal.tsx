@@ -21,6 +21,8 @@ const SUPPORTED_EXCHANGE_TEMPLATES = [
   { exchange_type: 'binance', name: 'Binance Futures', type: 'cex' as const },
   { exchange_type: 'bybit', name: 'Bybit Futures', type: 'cex' as const },
   { exchange_type: 'okx', name: 'OKX Futures', type: 'cex' as const },
+  { exchange_type: 'mexc', name: 'MEXC Futures', type: 'cex' as const },
+  { exchange_type: 'coinbase', name: 'Coinbase International', type: 'cex' as const },
   { exchange_type: 'hyperliquid', name: 'Hyperliquid', type: 'dex' as const },
   { exchange_type: 'aster', name: 'Aster DEX', type: 'dex' as const },
   { exchange_type: 'lighter', name: 'Lighter', type: 'dex' as const },
@@ -279,7 +281,7 @@ export function ExchangeConfigModal({
       if (currentExchangeType === 'binance') {
         if (!apiKey.trim() || !secretKey.trim()) return
         await onSave(exchangeId, exchangeType, trimmedAccountName, apiKey.trim(), secretKey.trim(), '', testnet)
-      } else if (currentExchangeType === 'okx') {
+      } else if (currentExchangeType === 'okx' || currentExchangeType === 'coinbase') {
         if (!apiKey.trim() || !secretKey.trim() || !passphrase.trim()) return
         await onSave(exchangeId, exchangeType, trimmedAccountName, apiKey.trim(), secretKey.trim(), passphrase.trim(), testnet)
       } else if (currentExchangeType === 'hyperliquid') {
@@ -533,10 +535,12 @@ export function ExchangeConfigModal({
 
             {selectedTemplate && (
               <>
-                {/* Binance/Bybit/OKX 的输入字段 */}
+                {/* Binance/Bybit/OKX/MEXC/Coinbase 的输入字段 */}
                 {(currentExchangeType === 'binance' ||
                   currentExchangeType === 'bybit' ||
-                  currentExchangeType === 'okx') && (
+                  currentExchangeType === 'okx' ||
+                  currentExchangeType === 'mexc' ||
+                  currentExchangeType === 'coinbase') && (
                     <>
                       {/* 币安用户配置提示 (D1 方案) */}
                       {currentExchangeType === 'binance' && (
@@ -681,7 +685,8 @@ export function ExchangeConfigModal({
                         />
                       </div>
 
-                      {currentExchangeType === 'okx' && (
+                      {(currentExchangeType === 'okx' ||
+                        currentExchangeType === 'coinbase') && (
                         <div>
                           <label
                             className="block text-sm font-semibold mb-2"
@@ -1180,7 +1185,8 @@ export function ExchangeConfigModal({
                 !accountName.trim() ||
                 (currentExchangeType === 'binance' &&
                   (!apiKey.trim() || !secretKey.trim())) ||
-                (currentExchangeType === 'okx' &&
+                ((currentExchangeType === 'okx' ||
+                  currentExchangeType === 'coinbase') &&
                   (!apiKey.trim() ||
                     !secretKey.trim() ||
                     !passphrase.trim())) ||
@@ -171,6 +171,8 @@ export function useTraderActions({
         exchange_id: data.exchange_id,
         initial_balance: data.initial_balance,
         scan_interval_minutes: data.scan_interval_minutes,
+        max_position_age_minutes: data.max_position_age_minutes,
+        max_position_age_action: data.max_position_age_action,
         btc_eth_leverage: data.btc_eth_leverage,
         altcoin_leverage: data.altcoin_leverage,
         trading_symbols: data.trading_symbols,
@@ -136,6 +136,10 @@ export const translations = {
       slippageLabel: 'Slippage (bps)',
       btcEthLeverageLabel: 'BTC/ETH leverage (x)',
       altcoinLeverageLabel: 'Altcoin leverage (x)',
+      atrStopLabel: 'ATR stop (× ATR14, 0 = AI stop)',
+      riskPerTradeLabel: 'Risk per trade (% equity, 0 = AI size)',
+      volTargetLabel: 'Volatility target (ATR14 %, 0 = AI leverage)',
+      stopCooldownLabel: 'Stop-loss cooldown (bars, 0 = off)',
       fillPolicies: {
         nextOpen: 'Next open',
         barVwap: 'Bar VWAP',
@@ -309,6 +313,11 @@ export const translations = {
     configureExchanges: 'Configure Exchanges',
     aiScanInterval: 'AI Scan Decision Interval (minutes)',
     scanIntervalRecommend: 'Recommended: 3-10 minutes',
+    maxPositionAge: 'Max Position Age (minutes)',
+    maxPositionAgeHint: '0 = no limit. Positions held longer are closed or sent to AI review',
+    maxPositionAgeAction: 'When Exceeded',
+    maxPositionAgeClose: 'Force close',
+    maxPositionAgeReview: 'AI review',
     useTestnet: 'Use Testnet',
     enabled: 'Enabled',
     save: 'Save',
@@ -525,6 +534,8 @@ export const translations = {
     defaultModel: 'Default model',
     applyApiKey: 'Apply API Key',
     kimiApiNote: 'Kimi requires API Key from international site (moonshot.ai), China region keys are not compatible',
+    ollamaNote: 'Runs on your own Ollama server, no API Key needed. Base URL defaults to http://localhost:11434 (use http://host.docker.internal:11434 from Docker). For llama.cpp or LM Studio use a custom OpenAI-compatible model with a local URL. Set AI_CONTEXT_WINDOW for models with small context windows',
+    apiKeyOptional: 'API Key (optional)',
     leaveBlankForDefaultModel: 'Leave blank to use default model',
     customModelName: 'Model Name (Optional)',
     customModelNamePlaceholder: 'e.g.: deepseek-chat, qwen3-max, gpt-4o',
@@ -1152,6 +1163,10 @@ export const translations = {
       slippageLabel: '滑点 (bps)',
       btcEthLeverageLabel: 'BTC/ETH 杠杆 (倍)',
       altcoinLeverageLabel: '山寨币杠杆 (倍)',
+      atrStopLabel: 'ATR 止损 (× ATR14，0 = AI 止损)',
+      riskPerTradeLabel: '单笔风险 (净值 %，0 = AI 仓位)',
+      volTargetLabel: '目标波动 (ATR14 %，0 = AI 杠杆)',
+      stopCooldownLabel: '止损冷却 (K线数，0 = 关闭)',
       fillPolicies: {
         nextOpen: '下一根开盘价',
         barVwap: 'K线 VWAP',
@@ -1323,6 +1338,11 @@ export const translations = {
     configureExchanges: '配置交易所',
     aiScanInterval: 'AI 扫描决策间隔 (分钟)',
     scanIntervalRecommend: '建议: 3-10分钟',
+    maxPositionAge: '最长持仓时间 (分钟)',
+    maxPositionAgeHint: '0 = 不限制。超时的持仓将被强制平仓或交给AI复核',
+    maxPositionAgeAction: '超时处理',
+    maxPositionAgeClose: '强制平仓',
+    maxPositionAgeReview: 'AI复核',
     useTestnet: '使用测试网',
     enabled: '启用',
     save: '保存',
@@ -1518,6 +1538,8 @@ export const translations = {
     defaultModel: '默认模型',
     applyApiKey: '申请 API Key',
     kimiApiNote: 'Kimi 需要从国际站申请 API Key (moonshot.ai)，中国区 Key 不通用',
+    ollamaNote: '运行在您自己的 Ollama 服务上，无需 API Key。Base URL 默认为 http://localhost:11434（Docker 中请使用 http://host.docker.internal:11434）。llama.cpp 或 LM Studio 请使用自定义 OpenAI 兼容模型并填写本地地址。小上下文窗口的模型请设置 AI_CONTEXT_WINDOW',
+    apiKeyOptional: 'API 密钥（可选）',
     leaveBlankForDefaultModel: '留空使用默认模型名称',
     customModelName: 'Model Name (可选)',
     customModelNamePlaceholder: '例如: deepseek-chat, qwen3-max, gpt-4o',
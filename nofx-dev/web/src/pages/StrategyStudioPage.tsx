@@ -543,6 +543,19 @@ export function StrategyStudioPage() {
       title: t('customPrompt'),
       content: editingConfig && (
         <div>
+          <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+            {language === 'zh' ? '决策者：由 AI 决策，或由算法策略代替（风控与执行流程相同，不使用提示词）' : 'Decision maker: the AI, or an algorithmic strategy instead (same risk checks and execution, prompts unused)'}
+          </p>
+          <select
+            value={editingConfig.decision_provider ?? ''}
+            onChange={(e) => updateConfig('decision_provider', e.target.value as StrategyConfig['decision_provider'])}
+            disabled={selectedStrategy?.is_default}
+            className="px-3 py-2 rounded mb-4 text-xs"
+            style={{ background: '#1E2329', border: '1px solid #2B3139', color: '#EAECEF' }}
+          >
+            <option value="">{language === 'zh' ? 'AI 模型' : 'AI model'}</option>
+            <option value="ema_cross">{language === 'zh' ? 'EMA20/EMA50 交叉' : 'EMA20 / EMA50 cross'}</option>
+          </select>
           <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
             {language === 'zh' ? '附加在 System Prompt 末尾的额外提示，用于补充个性化交易风格' : 'Extra prompt appended to System Prompt for personalized trading style'}
           </p>
@@ -11,6 +11,7 @@ export interface SystemStatus {
   stop_until: string
   last_reset_time: string
   ai_provider: string
+  daily_loss_paused_until?: string // 触发每日亏损熔断后暂停至（下一个 UTC 日）
 }
 
 export interface AccountInfo {
@@ -74,6 +75,47 @@ export interface DecisionRecord {
   execution_log: string[]
   success: boolean
   error_message?: string
+  prompt_tokens?: number // 本周期 AI 调用的 token（含工具调用轮次、JSON 修复与集成模型）
+  completion_tokens?: number
+  ai_cost_usd?: number // 按标价估算的费用，自定义端点与本地模型为 0
+  model_usage?: ModelUsage[]
+  reflection_response?: string // 复核轮（reflection_pass）的原始回复，decision_json 为复核前的提议
+  reflection_json?: string // 复核轮对各开仓/加仓提议的裁决：approve / veto / downsize
+  confidences?: ActionConfidence[] // 各操作的信心及信心阈值的处理，用于校准分析
+  fallback?: '' | 'manage_stops' | 'ema_cross' // AI 失败时由规则兜底策略决策（error_message 为 AI 错误）
+  prompt_hash?: string // 决策所用提示词配置（段落、自定义提示词、片段、币种指令）的哈希
+  clamps?: GuardrailClamp[] // 风控护栏修改的 AI 值
+  provider?: string // 代替 AI 决策的算法策略（为空表示 AI）
+}
+
+export interface ActionConfidence {
+  symbol: string
+  action: string
+  confidence: number
+  ignored?: boolean // 低于交易员的最低信心，未执行
+  size_factor?: number // 按信心缩放后保留的仓位比例，缺省表示未缩放
+}
+
+export interface ModelUsage {
+  model: string
+  prompt_tokens: number
+  completion_tokens: number
+  cost_usd: number
+  estimated?: boolean // 接口未返回 token 数，按文本长度估算
+}
+
+// GET /api/traders/:id/ai-cost — AI 调用费用与交易盈亏对比
+export interface TraderAICost {
+  usage: {
+    cycles: number
+    prompt_tokens: number
+    completion_tokens: number
+    cost_usd: number
+    models: ModelUsage[]
+  }
+  trading_pnl: number // 已平仓盈亏
+  trading_fee: number
+  net_pnl_after_ai: number // 已平仓盈亏减去 AI 费用
 }
 
 export interface Statistics {
@@ -112,7 +154,7 @@ export interface AIModel {
 
 export interface Exchange {
   id: string                     // UUID (empty for supported exchange templates)
-  exchange_type: string          // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter"
+  exchange_type: string          // "binance", "bybit", "okx", "mexc", "coinbase", "hyperliquid", "aster", "lighter"
   account_name: string           // User-defined account name
   name: string                   // Display name
   type: 'cex' | 'dex'
@@ -131,10 +173,170 @@ export interface Exchange {
   lighterWalletAddr?: string
   lighterPrivateKey?: string
   lighterApiKeyPrivateKey?: string
+  quote_asset?: string           // Quote/margin asset: USDT (default), USDC, BUSD, FDUSD
+}
+
+// GET /api/exchanges/:id/exposure — 交易所账户敞口（按最近一次周期更新）
+export interface AccountExposure {
+  exchange_id: string
+  trader_id: string // 提供该快照的交易员
+  time: string
+  equity: number
+  long_notional: number
+  short_notional: number
+  net_notional: number
+  gross_notional: number
+  leverage: number // 总名义价值 / 净值
+  margin_used: number
+  margin_utilization: number // 保证金占用率（%）
+  positions: { symbol: string; side: string; notional: number; leverage: number }[]
+  traders: string[]
+}
+
+// GET/PUT /api/traders/:id/webhook — 交易执行后推送（HMAC-SHA256 签名，密钥不回传）
+export interface TraderWebhook {
+  url: string // 为空表示关闭
+  has_secret: boolean
+}
+
+export interface UpdateTraderWebhookRequest {
+  url: string
+  secret?: string // 省略则保留当前密钥
+}
+
+// Webhook 推送内容，签名头 X-NOFX-Signature: sha256=HMAC(secret, timestamp + "." + body)
+export interface TraderWebhookPayload {
+  trader_id: string
+  trader_name: string
+  exchange: string
+  action: string
+  symbol: string
+  price: number
+  quantity: number
+  size_usd: number
+  leverage?: number
+  order_id?: number
+  stop_loss?: number
+  take_profit?: number
+  reasoning: string // 决策理由摘要（digest 时为逐条汇总）
+  time: string
+  message: string // 按用户语言区域与时区格式化的可读文本（数字、金额、时间）
+  digest?: TraderWebhookPayload[] // action 为 "digest" 时：免打扰 / 汇总期间暂存的推送
+}
+
+// GET/PUT /api/notification-settings — 用户级免打扰与每小时汇总（作用于该用户所有交易员的 Webhook）
+// 关键告警（日亏损上限、保证金率、交易所熔断等）始终立即推送
+export interface NotificationSettings {
+  quiet_hours_enabled: boolean
+  quiet_start: string // "HH:MM"
+  quiet_end: string // "HH:MM"，早于开始时间表示跨午夜
+  timezone?: string // IANA 时区，为空表示 UTC
+  digest_enabled: boolean // 非关键推送按小时汇总发送
+  locale?: string // 推送文本与日报的数字/日期格式，如 "zh"、"en-GB"、"de"，为空表示 en
+}
+
+// GET/PUT /api/traders/:id/flat-schedule — 收盘清仓：到点平掉所有仓位并撤单，恢复时间前禁止开仓
+export interface TraderFlatSchedule {
+  enabled: boolean
+  mode: 'daily' | 'weekend' // weekend: 周五清仓时间至周一恢复时间
+  flat_time: string // "HH:MM"
+  resume_time?: string // "HH:MM"，为空表示午夜
+  timezone?: string // IANA 时区，为空表示 UTC
+}
+
+// GET/PUT /api/traders/:id/performance-seed — 冷启动：实盘平仓交易不足时，用前任交易员或回测的交易补足 AI 的近期成交
+export interface TraderPerformanceSeed {
+  source: '' | 'trader' | 'backtest' // 为空表示关闭
+  id: string // 前任交易员 ID 或回测 run ID（须属于同一用户）
+  min_live_trades?: number // 实盘平仓交易达到此数后停止补足，0 表示默认 10
+}
+
+// GET/PUT /api/traders/:id/ensemble — 多模型集成：ensemble_mode 开启时，附加模型与交易员自身模型共同投票，达成共识才执行
+export interface TraderEnsemble {
+  model_ids: string[] // 附加 AI 模型 ID（最多 4 个，不含交易员自身模型），为空表示关闭
+  method: '' | 'majority' | 'weighted' // 多数投票，或按模型近期胜率加权；为空表示 majority
+  min_agreement?: number // 执行所需的最低赞成比例（0-1），0 表示过半
+  min_confidence?: number // 开仓所需的最低平均信心（0-100），0 表示不限
+}
+
+// GET/PUT /api/traders/:id/confidence-gate — 信心阈值：低于阈值的开仓/加仓被忽略，可按信心缩放仓位
+export interface TraderConfidenceGate {
+  min_confidence: number // 开仓/加仓所需的最低信心（0-100），0 表示不限；平仓不受影响
+  scale_size: boolean // 仓位按 信心 / full_size_confidence 缩放（启用 risk_per_trade_pct 时以其为准）
+  full_size_confidence?: number // 使用 AI 完整仓位的信心，0 表示 100
+}
+
+// GET/PUT /api/traders/:id/liquidity-filter — 候选币流动性过滤：持仓币种不受影响，每个候选币的结果写入执行日志
+export interface TraderLiquidityFilter {
+  min_oi_value_usd: number // 最低持仓量价值（USD），0 表示默认 15M，负数表示关闭
+  zero_oi_pass: boolean // 持仓量报告为 0 时放行（无持仓量数据时总是放行）
+  min_volume_24h_usd: number // 最低 24h 成交额（USD），0 表示不检查；无行情数据时放行
+}
+
+// GET/PUT /api/traders/:id/risk-guardrail — 风控护栏：执行前将 AI 开仓/加仓的杠杆、仓位和止损距离限制在上限内（0 表示不限）
+export interface TraderRiskGuardrail {
+  max_leverage: number // 最大杠杆
+  max_position_size_usd: number // 单笔最大仓位（USD）
+  max_position_equity_pct: number // 单笔最大仓位占净值百分比
+  min_stop_distance_pct: number // 止损距现价的最小百分比
+  max_stop_distance_pct: number // 止损距现价的最大百分比（开仓未设止损时按此设置）
+}
+
+// 护栏修改的 AI 原始值与执行值，用于审计
+export interface GuardrailClamp {
+  symbol: string
+  action: string
+  field: 'leverage' | 'position_size_usd' | 'stop_loss'
+  original: number // AI 给出的值（0 表示未设置）
+  clamped: number // 实际执行的值
+  reason: string
+}
+
+// GET/PUT /api/feature-flags（部署级）与 /api/traders/:id/feature-flags（交易员级）— 实验功能灰度开关
+export type FeatureFlagName =
+  | 'ensemble_mode'
+  | 'event_triggers'
+  | 'maker_first_execution'
+  | 'reflection_pass'
+
+export interface FeatureFlagStatus {
+  name: FeatureFlagName
+  description: string
+  fallback: string // 关闭时的降级行为
+  default: boolean // 环境变量 FEATURE_FLAGS 的默认值
+  override?: boolean // 本级覆盖值，缺省表示继承
+  enabled: boolean // 生效值
+}
+
+export interface FeatureFlagsResponse {
+  flags: FeatureFlagStatus[]
+  editable?: boolean // 部署级开关仅管理员可修改
+}
+
+export interface FeatureFlagsUpdate {
+  flags: Partial<Record<FeatureFlagName, boolean>> // 未列出的开关继承上一级
+}
+
+// 公开分享链接可见字段（仓位数量、订单与密钥永不公开）
+export interface ShareFields {
+  equity_curve: boolean
+  absolute_equity: boolean // 收益曲线显示 USDT 净值，否则只显示收益率
+  stats: boolean
+  absolute_pnl: boolean // 统计与交易显示 USDT 盈亏，否则只显示百分比
+  trades: boolean
+}
+
+// GET/POST /api/traders/:id/share-links，公开访问 GET /api/share/:token
+export interface ShareLink {
+  token: string
+  trader_id: string
+  fields: ShareFields
+  expires_at: string
+  created_at: string
 }
 
 export interface CreateExchangeRequest {
-  exchange_type: string          // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter"
+  exchange_type: string          // "binance", "bybit", "okx", "mexc", "coinbase", "hyperliquid", "aster", "lighter"
   account_name: string           // User-defined account name
   enabled: boolean
   api_key?: string
@@ -148,15 +350,19 @@ export interface CreateExchangeRequest {
   lighter_wallet_addr?: string
   lighter_private_key?: string
   lighter_api_key_private_key?: string
+  quote_asset?: string // 计价/保证金币种，默认 USDT
 }
 
 export interface CreateTraderRequest {
   name: string
   ai_model_id: string
   exchange_id: string
+  fallback_exchange_id?: string // 备用交易所ID（主交易所API持续故障时接管新开仓）
   strategy_id?: string // 策略ID（新版，使用保存的策略配置）
   initial_balance?: number // 可选：创建时由后端自动获取，编辑时可手动更新
   scan_interval_minutes?: number
+  max_position_age_minutes?: number // 最长持仓时间（分钟），0 = 不限制
+  max_position_age_action?: 'close' | 'review' // 超时处理：强制平仓 / 交给AI复核
   is_cross_margin?: boolean
   show_in_competition?: boolean // 是否在竞技场显示
   // 以下字段为向后兼容保留，新版使用策略配置
@@ -199,10 +405,70 @@ export interface UpdateExchangeConfigRequest {
       lighter_wallet_addr?: string
       lighter_private_key?: string
       lighter_api_key_private_key?: string
+      quote_asset?: string // 计价/保证金币种（留空则不修改）
     }
   }
 }
 
+// GET /api/leaderboard?window=24h|7d|30d|90d&sort=sharpe|return|drawdown（需 LEADERBOARD_ENABLED）
+export interface LeaderboardEntry {
+  rank: number
+  trader_id: string
+  trader_name: string
+  ai_model: string
+  exchange: string
+  return_pct: number
+  sharpe_ratio: number // 年化，基于分桶净值收益
+  volatility_pct: number // 年化波动率（%）
+  max_drawdown_pct: number
+  samples: number
+}
+
+export interface Leaderboard {
+  window: string
+  sort_by: string
+  updated_at: string
+  entries: LeaderboardEntry[]
+}
+
+// GET /api/traders/:id/trades/:position_id/replay — 已平仓交易回放（K 线、开平仓决策、止损止盈、盈亏曲线）
+export interface TradeReplayDecision {
+  time: string
+  cycle_number: number
+  decision: {
+    symbol: string
+    action: string
+    stop_loss?: number
+    take_profit?: number
+    confidence?: number
+    reasoning: string
+  }
+  cot_trace: string
+}
+
+export interface TradeReplay {
+  trade: {
+    id: number
+    symbol: string
+    side: string
+    quantity: number
+    entry_price: number
+    entry_time: string
+    exit_price: number
+    exit_time: string
+    realized_pnl: number // 含持仓期间资金费
+    funding_fee: number // 资金费（收入为正，支出为负）
+    leverage: number
+    close_reason: string
+  }
+  interval: string
+  candles: { time: number; open: number; high: number; low: number; close: number; volume: number }[]
+  open_decision?: TradeReplayDecision
+  close_decision?: TradeReplayDecision // 止损 / 止盈 / 手动平仓时为空
+  levels: { time: string; stop_loss: number; take_profit: number; action: string }[]
+  pnl: { time: number; price: number; pnl: number; pnl_pct: number }[] // pnl_pct 为保证金收益率
+}
+
 // Competition related types
 export interface CompetitionTraderData {
   trader_id: string
@@ -233,6 +499,8 @@ export interface TraderConfigData {
   is_cross_margin: boolean
   show_in_competition: boolean  // 是否在竞技场显示
   scan_interval_minutes: number
+  max_position_age_minutes?: number // 最长持仓时间（分钟），0 = 不限制
+  max_position_age_action?: 'close' | 'review'
   initial_balance: number
   is_running: boolean
   // 以下为旧版字段（向后兼容）
@@ -366,6 +634,10 @@ export interface BacktestStartConfig {
   checkpoint_interval_seconds?: number;
   replay_decision_dir?: string;
   shared_ai_cache_path?: string;
+  atr_stop_multiplier?: number;
+  risk_per_trade_pct?: number;
+  vol_target_atr_pct?: number;
+  stop_loss_cooldown_bars?: number;
   ai?: {
     provider?: string;
     model?: string;
@@ -391,6 +663,7 @@ export interface Strategy {
   updated_at: string;
 }
 
+// 各段落与 custom_prompt 均为 Go text/template，可使用 {{.Equity}}、{{.Risk.MaxPositions}}、{{join .Symbols ", "}} 等变量
 export interface PromptSectionsConfig {
   role_definition?: string;
   trading_frequency?: string;
@@ -404,10 +677,13 @@ export interface StrategyConfig {
   custom_prompt?: string;
   risk_control: RiskControlConfig;
   prompt_sections?: PromptSectionsConfig;
+  prompt_partials?: Record<string, string>; // 可复用的提示词片段，在各段落中以 {{template "名称" .}} 引用
+  symbol_prompts?: Record<string, string>; // 币种专属指令（如 "BTCUSDT": "永不做空"），加入该币种在提示词中的段落
+  decision_provider?: '' | 'ema_cross'; // 代替 AI 决策的算法策略，为空表示由 AI 决策；风控与执行流程相同
 }
 
 export interface CoinSourceConfig {
-  source_type: 'static' | 'coinpool' | 'oi_top' | 'mixed';
+  source_type: 'static' | 'coinpool' | 'oi_top' | 'top_movers' | 'mixed';
   static_coins?: string[];
   use_coin_pool: boolean;
   coin_pool_limit?: number;
@@ -415,6 +691,13 @@ export interface CoinSourceConfig {
   use_oi_top: boolean;
   oi_top_limit?: number;
   oi_top_api_url?: string;     // OI Top API URL
+  use_top_movers?: boolean;    // 全市场涨跌幅 / 放量扫描
+  top_movers_limit?: number;
+  candidate_half_life?: number; // 候选币无操作衰减半衰期（周期数，0 = 关闭）
+  include_categories?: string[]; // 仅扫描这些类别：meme / l1 / l2 / defi / ai / gaming / other（空 = 全部）
+  exclude_categories?: string[]; // 排除这些类别
+  min_listing_days?: number;     // 跳过上线不足该天数的合约（0 = 关闭）
+  watch_only_coins?: string[];   // 仅观察币种：AI 只发提醒/点评，从不下单
 }
 
 export interface IndicatorConfig {
@@ -426,9 +709,18 @@ export interface IndicatorConfig {
   enable_macd: boolean;
   enable_rsi: boolean;
   enable_atr: boolean;
+  enable_bollinger?: boolean; // 布林带 (20, 2σ)：%B 与带宽
+  enable_vwap?: boolean; // VWAP：24 小时滚动与日开盘锚定
   enable_volume: boolean;
   enable_oi: boolean;
   enable_funding_rate: boolean;
+  enable_order_book?: boolean; // 盘口：买卖价差、前 10 档深度、买卖盘失衡
+  enable_weekly_context?: boolean; // 周线结构：趋势、EMA20/50、ATR14、近期周收盘价
+  enable_event_calendar?: boolean; // 事件日历：FOMC、CPI 等宏观数据与代币解锁
+  event_window_hours?: number; // 事件提示窗口（小时，默认 24）
+  enable_tool_calling?: boolean; // 工具调用：提示词只含精简行情，AI 按需调用 get_klines / get_orderbook / get_position_history
+  enable_trade_memory?: boolean; // 交易记忆：按入场时的指标与行情状态检索最相似的已平仓交易，加入提示词
+  trade_memory_k?: number; // 每个币种检索的历史交易数（默认 3，最多 10）
   ema_periods?: number[];
   rsi_periods?: number[];
   atr_periods?: number[];
@@ -448,6 +740,8 @@ export interface KlineConfig {
   enable_multi_timeframe: boolean;
   // 新增：支持选择多个时间周期
   selected_timeframes?: string[];
+  // Run decision cycles on primary timeframe candle close instead of the scan interval
+  cycle_on_candle_close?: boolean;
 }
 
 export interface ExternalDataSource {
@@ -476,6 +770,31 @@ export interface RiskControlConfig {
   // Risk Parameters
   max_margin_usage: number;        // Max margin utilization, e.g. 0.9 = 90% (CODE ENFORCED)
   min_position_size: number;       // Min position size in USDT (CODE ENFORCED)
+  max_liquidity_volume_pct?: number; // Max position as share of Binance 1h volume, 0 = off (CODE ENFORCED, Binance only)
+  max_liquidity_depth_pct?: number;  // Max position as share of Binance ±1% book depth, 0 = off (CODE ENFORCED, Binance only)
+  max_slippage_bps?: number;       // Max estimated market entry slippage from Binance book depth, 0 = off (CODE ENFORCED, Binance only)
+  max_total_exposure_usd?: number;  // Max notional of all positions on the trader's account, USDT (0 = no cap) (CODE ENFORCED)
+  max_symbol_exposure_usd?: number; // Max notional per symbol, USDT (0 = no cap) (CODE ENFORCED)
+  max_user_exposure_usd?: number;   // Max notional across all of the user's running traders, USDT (0 = no cap) (CODE ENFORCED)
+  trailing_stop_pct?: number;      // Default trailing stop distance %, used when AI sets none (0 = disabled)
+  break_even_trigger_pct?: number; // Move stop to entry once leveraged P&L reaches this % (0 = disabled)
+  break_even_buffer_pct?: number;  // Extra % beyond entry (on top of round-trip fees) for the break-even stop
+  stop_snap_tolerance_pct?: number; // Move AI stops beyond round numbers / book walls / volume nodes within this % (0 = disabled)
+  atr_stop_multiplier?: number; // Stop loss of new positions in ATR14 (primary timeframe) from entry (0 = use AI stop)
+  risk_per_trade_pct?: number; // Size new positions to lose this % of equity at the stop loss (0 = use AI size)
+  vol_target_atr_pct?: number; // Leverage of new positions = max leverage × target / ATR14% of price, at least 1x (0 = use AI leverage)
+  max_daily_loss_pct?: number;     // Loss since UTC day start (% of day-start equity) that pauses the trader until next UTC day (0 = disabled)
+  daily_loss_flatten?: boolean;    // Close all positions when the daily loss limit is reached
+  max_margin_ratio_pct?: number;   // Maintenance margin / margin balance % at which the largest losing position is reduced (0 = disabled)
+  margin_reduce_pct?: number;      // Share of that position closed each time, % (default 25)
+  liquidation_guard_pct?: number;  // Mark-to-liquidation distance % below which adding to the position is blocked (0 = disabled)
+  liquidation_deleverage_pct?: number; // Share of such a position closed automatically, % (0 = block only)
+  stop_loss_cooldown_minutes?: number; // Minutes a symbol can't be re-entered after a stop-loss exit (0 = disabled)
+  event_pause_minutes?: number; // No new entries this many minutes before / after a high-impact event affecting the symbol (0 = disabled)
+  ai_fallback_mode?: '' | 'manage_stops' | 'ema_cross'; // Deterministic strategy deciding when the AI fails ('' = skip the cycle)
+  ai_fallback_after_failures?: number; // Consecutive failed AI cycles before the fallback decides (0 = first failure)
+  expected_value_gate?: boolean;   // Skip entries whose expected value after fees and funding is below min_expected_value_pct
+  min_expected_value_pct?: number; // Min expected value of a new position, % of notional (may be negative)
   min_risk_reward_ratio: number;   // Min take_profit / stop_loss ratio (AI guided)
   min_confidence: number;          // Min AI confidence to open position (AI guided)
 }